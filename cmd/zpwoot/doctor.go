@@ -0,0 +1,266 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"zpwoot/internal/infra/db"
+	"zpwoot/platform/config"
+	platformDB "zpwoot/platform/db"
+	"zpwoot/platform/logger"
+)
+
+const (
+	doctorCommand       = "doctor"
+	defaultGlobalAPIKey = "a0b1125a0eb3364d98e2c49ec6f7d6ba"
+	minAPIKeyLength     = 32
+	webhookProbeTimeout = 5 * time.Second
+	mediaCacheDir       = "/tmp/media_cache"
+	doctorStatusOK      = "OK"
+	doctorStatusWarning = "WARN"
+	doctorStatusFailure = "FAIL"
+)
+
+// doctorResult is the outcome of a single environment check
+type doctorResult struct {
+	name   string
+	status string
+	detail string
+	hint   string
+}
+
+// runDoctor validates the environment and prints actionable results.
+// It returns false when at least one check failed.
+func runDoctor(cfg *config.Config, appLogger *logger.Logger) bool {
+	var results []doctorResult
+
+	database, dbResult := checkDatabaseConnectivity(cfg)
+	results = append(results, dbResult)
+	if database != nil {
+		results = append(results, checkSchemaVersion(database, appLogger))
+		if err := database.Close(); err != nil {
+			appLogger.Error("Failed to close database connection: " + err.Error())
+		}
+	}
+
+	results = append(results, checkAPIKeys(cfg)...)
+	results = append(results, checkWebhookReachability(cfg))
+	results = append(results, checkStorageWritable(os.TempDir()))
+	results = append(results, checkStorageWritable(mediaCacheDir))
+	results = append(results, checkFFmpeg())
+
+	return printDoctorResults(results)
+}
+
+func checkDatabaseConnectivity(cfg *config.Config) (*platformDB.DB, doctorResult) {
+	result := doctorResult{name: "database connectivity"}
+
+	database, err := platformDB.New(cfg.DatabaseURL)
+	if err != nil {
+		result.status = doctorStatusFailure
+		result.detail = err.Error()
+		result.hint = "check DATABASE_URL and that PostgreSQL is reachable from this host"
+		return nil, result
+	}
+
+	if err := database.Health(); err != nil {
+		result.status = doctorStatusFailure
+		result.detail = err.Error()
+		result.hint = "the database accepted the connection but failed the health check"
+		_ = database.Close()
+		return nil, result
+	}
+
+	result.status = doctorStatusOK
+	result.detail = "connected"
+	return database, result
+}
+
+func checkSchemaVersion(database *platformDB.DB, appLogger *logger.Logger) doctorResult {
+	result := doctorResult{name: "schema version"}
+
+	migrations, err := db.NewMigrator(database.GetDB().DB, appLogger).GetMigrationStatus()
+	if err != nil {
+		result.status = doctorStatusFailure
+		result.detail = err.Error()
+		result.hint = "run `zpwoot -migrate-up` to initialize the schema"
+		return result
+	}
+
+	current, pending := 0, 0
+	for _, migration := range migrations {
+		if migration.AppliedAt != nil {
+			if migration.Version > current {
+				current = migration.Version
+			}
+			continue
+		}
+		pending++
+	}
+
+	if pending > 0 {
+		result.status = doctorStatusWarning
+		result.detail = fmt.Sprintf("version %03d, %d pending migration(s)", current, pending)
+		result.hint = "pending migrations are applied on startup, or run `zpwoot -migrate-up`"
+		return result
+	}
+
+	result.status = doctorStatusOK
+	result.detail = fmt.Sprintf("version %03d, up to date", current)
+	return result
+}
+
+func checkAPIKeys(cfg *config.Config) []doctorResult {
+	results := []doctorResult{checkAPIKeyStrength("api key (ZP_API_KEY)", cfg.GlobalAPIKey)}
+	for i, key := range cfg.CompatAPIKeys {
+		results = append(results, checkAPIKeyStrength(fmt.Sprintf("compat api key #%d", i+1), key))
+	}
+	return results
+}
+
+func checkAPIKeyStrength(name, key string) doctorResult {
+	result := doctorResult{name: name}
+
+	switch {
+	case key == "":
+		result.status = doctorStatusFailure
+		result.detail = "not set"
+		result.hint = "set a random key, e.g. `openssl rand -hex 32`"
+	case key == defaultGlobalAPIKey:
+		result.status = doctorStatusFailure
+		result.detail = "using the documented default key"
+		result.hint = "replace it with a random key, e.g. `openssl rand -hex 32`"
+	case len(key) < minAPIKeyLength:
+		result.status = doctorStatusWarning
+		result.detail = fmt.Sprintf("only %d characters long", len(key))
+		result.hint = fmt.Sprintf("use at least %d random characters", minAPIKeyLength)
+	default:
+		result.status = doctorStatusOK
+		result.detail = fmt.Sprintf("%d characters", len(key))
+	}
+
+	return result
+}
+
+func checkWebhookReachability(cfg *config.Config) doctorResult {
+	result := doctorResult{name: "global webhook"}
+
+	if cfg.GlobalWebhookURL == "" {
+		result.status = doctorStatusOK
+		result.detail = "not configured"
+		return result
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), webhookProbeTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, cfg.GlobalWebhookURL, nil)
+	if err != nil {
+		result.status = doctorStatusFailure
+		result.detail = err.Error()
+		result.hint = "GLOBAL_WEBHOOK_URL is not a valid URL"
+		return result
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		result.status = doctorStatusFailure
+		result.detail = err.Error()
+		result.hint = "check GLOBAL_WEBHOOK_URL, DNS and outbound firewall rules"
+		return result
+	}
+	_ = resp.Body.Close()
+
+	// Any HTTP answer proves reachability; receivers commonly reject HEAD
+	if resp.StatusCode >= http.StatusInternalServerError {
+		result.status = doctorStatusWarning
+		result.detail = fmt.Sprintf("reachable, responded %d", resp.StatusCode)
+		result.hint = "the receiver is up but returning server errors"
+		return result
+	}
+
+	result.status = doctorStatusOK
+	result.detail = fmt.Sprintf("reachable, responded %d", resp.StatusCode)
+	return result
+}
+
+func checkStorageWritable(dir string) doctorResult {
+	result := doctorResult{name: "storage " + dir}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		result.status = doctorStatusFailure
+		result.detail = err.Error()
+		result.hint = "create the directory or fix its permissions"
+		return result
+	}
+
+	file, err := os.CreateTemp(dir, ".zpwoot-doctor-*")
+	if err != nil {
+		result.status = doctorStatusFailure
+		result.detail = err.Error()
+		result.hint = "make the directory writable by the zpwoot user"
+		return result
+	}
+	path := file.Name()
+
+	_, writeErr := file.WriteString("zpwoot doctor write test")
+	closeErr := file.Close()
+	removeErr := os.Remove(path)
+
+	for _, err := range []error{writeErr, closeErr, removeErr} {
+		if err != nil {
+			result.status = doctorStatusFailure
+			result.detail = err.Error()
+			result.hint = "check free disk space and permissions on " + filepath.Clean(dir)
+			return result
+		}
+	}
+
+	result.status = doctorStatusOK
+	result.detail = "writable"
+	return result
+}
+
+func checkFFmpeg() doctorResult {
+	result := doctorResult{name: "ffmpeg"}
+
+	path, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		result.status = doctorStatusWarning
+		result.detail = "not found in PATH"
+		result.hint = "install ffmpeg to enable audio and video conversion for media messages"
+		return result
+	}
+
+	result.status = doctorStatusOK
+	result.detail = path
+	return result
+}
+
+func printDoctorResults(results []doctorResult) bool {
+	fmt.Printf("zpwoot doctor\n")
+	fmt.Printf("=============\n\n")
+
+	failures, warnings := 0, 0
+	for _, result := range results {
+		fmt.Printf("[%-4s] %-28s %s\n", result.status, result.name, result.detail)
+		if result.hint != "" {
+			fmt.Printf("       -> %s\n", result.hint)
+		}
+
+		switch result.status {
+		case doctorStatusFailure:
+			failures++
+		case doctorStatusWarning:
+			warnings++
+		}
+	}
+
+	fmt.Printf("\n%d check(s), %d failure(s), %d warning(s)\n", len(results), failures, warnings)
+	return failures == 0
+}
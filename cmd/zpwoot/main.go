@@ -16,6 +16,10 @@
 // @in header
 // @name Authorization
 // @description Enter your API key directly (no Bearer prefix required). Example: a0b1125a0eb3364d98e2c49ec6f7d6ba
+// @securityDefinitions.apikey AdminKeyAuth
+// @in header
+// @name X-Admin-Key
+// @description Admin-only key (ZP_ADMIN_API_KEY) for the /debug routes
 package main
 
 import (
@@ -34,19 +38,43 @@ import (
 
 	_ "zpwoot/docs/swagger" // Import generated swagger docs
 	"zpwoot/internal/app"
+	"zpwoot/internal/app/common"
 	sessionApp "zpwoot/internal/app/session"
-	"zpwoot/internal/domain/session"
+	domainActivity "zpwoot/internal/domain/activity"
+	domainAPIKey "zpwoot/internal/domain/apikey"
+	domainChat "zpwoot/internal/domain/chat"
 	domainChatwoot "zpwoot/internal/domain/chatwoot"
 	domainCommunity "zpwoot/internal/domain/community"
 	domainContact "zpwoot/internal/domain/contact"
+	domainConversation "zpwoot/internal/domain/conversation"
+	domainDraft "zpwoot/internal/domain/draft"
 	domainGroup "zpwoot/internal/domain/group"
+	domainHistory "zpwoot/internal/domain/history"
+	domainJob "zpwoot/internal/domain/job"
+	domainMaintenance "zpwoot/internal/domain/maintenance"
 	domainMedia "zpwoot/internal/domain/media"
+	domainMessage "zpwoot/internal/domain/message"
 	domainNewsletter "zpwoot/internal/domain/newsletter"
+	domainOutbox "zpwoot/internal/domain/outbox"
+	domainPairing "zpwoot/internal/domain/pairing"
+	domainPoll "zpwoot/internal/domain/poll"
+	domainPrivacy "zpwoot/internal/domain/privacy"
+	domainQueue "zpwoot/internal/domain/queue"
+	domainRouting "zpwoot/internal/domain/routing"
+	domainScheduled "zpwoot/internal/domain/scheduled"
+	domainSendGuard "zpwoot/internal/domain/sendguard"
+	"zpwoot/internal/domain/session"
+	domainSettings "zpwoot/internal/domain/settings"
+	domainStats "zpwoot/internal/domain/stats"
+	domainTranslation "zpwoot/internal/domain/translation"
+	domainUsage "zpwoot/internal/domain/usage"
+	domainWarmup "zpwoot/internal/domain/warmup"
 	domainWebhook "zpwoot/internal/domain/webhook"
 	"zpwoot/internal/infra/db"
 	"zpwoot/internal/infra/http/middleware"
 	"zpwoot/internal/infra/http/routers"
 	chatwootIntegration "zpwoot/internal/infra/integrations/chatwoot"
+	translationIntegration "zpwoot/internal/infra/integrations/translation"
 	"zpwoot/internal/infra/integrations/webhook"
 	"zpwoot/internal/infra/repository"
 	"zpwoot/internal/infra/wameow"
@@ -54,8 +82,51 @@ import (
 	"zpwoot/platform/config"
 	platformDB "zpwoot/platform/db"
 	"zpwoot/platform/logger"
+	"zpwoot/platform/tracing"
 )
 
+// draftSchedulerInterval is how often scheduled drafts are checked
+const draftSchedulerInterval = 30 * time.Second
+
+// contactChangePruneInterval is how often expired contact changes are dropped from the feed
+const contactChangePruneInterval = time.Hour
+
+// conversationStatePruneInterval is how often expired conversation states are dropped
+const conversationStatePruneInterval = 10 * time.Minute
+
+// eventStorePruneInterval is how often stored webhook events past their retention are dropped
+const eventStorePruneInterval = time.Hour
+
+// offlineQueueExpiryInterval is how often queued messages of sessions that did not reconnect in time expire
+const offlineQueueExpiryInterval = time.Minute
+
+// outboxDispatchInterval is how often due outbox messages are picked up; the delay between messages
+// to a chat comes from OUTBOX_MIN_DELAY and OUTBOX_MAX_DELAY
+const outboxDispatchInterval = time.Second
+
+// scheduledDispatchInterval is how often scheduled messages are checked; it also paces the retries of
+// due messages whose session is disconnected
+const scheduledDispatchInterval = 10 * time.Second
+
+// sessionJanitorInterval is how often state of sessions deleted from the database is released
+const sessionJanitorInterval = 10 * time.Minute
+
+// staleSessionSweepInterval is how often the stale session policy is applied
+const staleSessionSweepInterval = time.Hour
+
+// settingsSyncInterval is how often runtime settings changed by other replicas are picked up
+const settingsSyncInterval = 10 * time.Second
+
+// maintenanceSyncInterval is how often maintenance changed through other replicas is picked up; sends
+// reaching another replica may still go out for this long after maintenance is enabled
+const maintenanceSyncInterval = 5 * time.Second
+
+// autoConnectDelay is how long after startup the stored sessions are connected
+const autoConnectDelay = 3 * time.Second
+
+// defaultShutdownGracePeriod bounds the shutdown when SHUTDOWN_GRACE_PERIOD is not positive
+const defaultShutdownGracePeriod = 30 * time.Second
+
 var (
 	Version   = "dev"
 	BuildTime = "unknown"
@@ -67,6 +138,7 @@ type commandFlags struct {
 	migrateUp     bool
 	migrateDown   bool
 	migrateStatus bool
+	migrateOnly   bool
 	seed          bool
 	version       bool
 }
@@ -77,6 +149,27 @@ type managers struct {
 	webhook         *webhook.WebhookManager
 	chatwoot        *chatwootIntegration.IntegrationManager
 	chatwootManager *chatwootIntegration.Manager
+	chatwootQueue   *chatwootIntegration.WebhookQueue
+	maintenance     *domainMaintenance.Service
+	activity        *domainActivity.Service
+	routing         *domainRouting.Service
+	conversation    *domainConversation.Service
+	poll            *domainPoll.Service
+	translation     *domainTranslation.Service
+	contactFeed     *domainContact.ChangeFeed
+	warmup          *domainWarmup.Service
+	offlineQueue    *domainQueue.Service
+	outbox          *domainOutbox.Service
+	scheduled       *domainScheduled.Service
+	sendGuard       *domainSendGuard.Service
+	privacy         *domainPrivacy.Service
+	eventStore      *domainWebhook.EventStore
+	eventStream     *domainWebhook.EventStream
+	history         *domainHistory.Service
+	stats           *domainStats.Service
+	jobs            *domainJob.Service
+	apiKey          *domainAPIKey.Service
+	tracer          *tracing.Tracer
 }
 
 func main() {
@@ -90,35 +183,53 @@ func main() {
 	}
 
 	// Initialize configuration and logger
-	cfg := config.Load()
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to load configuration: "+err.Error())
+		os.Exit(1)
+	}
 	appLogger := initializeLogger(cfg)
 
+	// Validate the environment without touching the schema
+	if flag.Arg(0) == doctorCommand {
+		if !runDoctor(cfg, appLogger) {
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Initialize database with migrations
 	database := initializeDatabase(cfg, appLogger)
 	defer closeDatabase(database, appLogger)
 
 	// Handle database operations (migrations, seed)
 	migrator := db.NewMigrator(database.GetDB().DB, appLogger)
+	migrator.SetLockTimeout(cfg.MigrationLockTimeout)
 	if handled := handleDatabaseOperations(flags, migrator, database, appLogger); handled {
 		return
 	}
 
 	// Initialize core components
-	repositories := repository.NewRepositories(database.GetDB(), appLogger)
-	managers := initializeManagers(database, repositories, appLogger)
-	container := createContainer(repositories, managers, database, appLogger)
+	repositories := repository.NewRepositories(database.GetDB(), appLogger, repository.Options{
+		CaseSensitiveSessionNames: cfg.SessionNamesCaseSensitive,
+	})
+	managers := initializeManagers(cfg, database, repositories, appLogger)
+	container := createContainer(cfg, repositories, managers, database, appLogger)
 
 	// Setup and start HTTP server
-	fiberApp := setupHTTPServer(cfg, container, database, managers.whatsapp, appLogger)
+	fiberApp := setupHTTPServer(cfg, container, database, managers, appLogger)
 
 	// Start background services
-	startBackgroundServices(container, appLogger)
+	startBackgroundServices(container, managers, appLogger)
 
 	// Setup graceful shutdown
-	setupGracefulShutdown(fiberApp, appLogger)
+	shutdownDone := setupGracefulShutdown(fiberApp, cfg, managers, appLogger)
 
 	// Start server
 	startServer(fiberApp, cfg, appLogger)
+
+	// Listen returns once HTTP is stopped; the database closes only after the rest of the shutdown
+	<-shutdownDone
 }
 
 // parseFlags parses and returns command line flags
@@ -127,6 +238,7 @@ func parseFlags() commandFlags {
 	flag.BoolVar(&flags.migrateUp, "migrate-up", false, "Run database migrations up")
 	flag.BoolVar(&flags.migrateDown, "migrate-down", false, "Rollback last migration")
 	flag.BoolVar(&flags.migrateStatus, "migrate-status", false, "Show migration status")
+	flag.BoolVar(&flags.migrateOnly, "migrate-only", false, "Apply pending migrations and exit (for init containers)")
 	flag.BoolVar(&flags.seed, "seed", false, "Seed database with sample data")
 	flag.BoolVar(&flags.version, "version", false, "Show version information")
 	flag.Parse()
@@ -152,10 +264,11 @@ func initializeLogger(cfg *config.Config) *logger.Logger {
 
 // initializeDatabase connects to database and runs initial migrations
 func initializeDatabase(cfg *config.Config, appLogger *logger.Logger) *platformDB.DB {
-	database, err := platformDB.NewWithMigrations(cfg.DatabaseURL, appLogger)
+	database, err := platformDB.NewWithMigrations(cfg.DatabaseURL, cfg.MigrationLockTimeout, appLogger)
 	if err != nil {
 		appLogger.Fatal("Failed to connect to database and run migrations: " + err.Error())
 	}
+	database.SetSlowQueryLog(cfg.SlowQueryThreshold, appLogger)
 	return database
 }
 
@@ -173,6 +286,12 @@ func handleDatabaseOperations(
 	database *platformDB.DB,
 	appLogger *logger.Logger,
 ) bool {
+	// Migrations already ran while connecting
+	if flags.migrateOnly {
+		appLogger.Info("Migrations completed, exiting (--migrate-only)")
+		return true
+	}
+
 	if flags.migrateUp {
 		if err := migrator.RunMigrations(); err != nil {
 			appLogger.Fatal("Failed to run migrations: " + err.Error())
@@ -211,13 +330,151 @@ func handleDatabaseOperations(
 
 // initializeManagers creates and configures all application managers
 func initializeManagers(
+	cfg *config.Config,
 	database *platformDB.DB,
 	repositories *repository.Repositories,
 	appLogger *logger.Logger,
 ) managers {
-	whatsappManager := createWhatsAppManager(database, repositories.GetSessionRepository(), appLogger)
-	webhookManager := createWebhookManager(repositories.GetWebhookRepository(), appLogger)
+	tracer := tracing.Init(tracing.Config{
+		ServiceName: cfg.OTelServiceName,
+		Endpoint:    cfg.OTelTracesEndpoint,
+		Headers:     cfg.OTelHeaders,
+		SampleRatio: cfg.OTelSampleRatio,
+	}, appLogger)
+	jobs := domainJob.NewService(appLogger, platformDB.NewLeader(database.GetDB().DB, appLogger))
+	maintenanceService := createMaintenanceService(repositories, jobs, appLogger)
+	activityService := domainActivity.NewService(appLogger)
+	whatsappManager := createWhatsAppManager(database, repositories.GetSessionRepository(), jobs, appLogger)
+	whatsappManager.SetSlowSendThreshold(cfg.SlowSendThreshold)
+	routingService := domainRouting.NewService(appLogger, repositories.GetRoutingRuleRepository(), whatsappManager)
+	conversationService := domainConversation.NewService(appLogger, repositories.GetConversationStateRepository())
+	routingService.SetConversationStates(conversationService)
+	registerJob(jobs, domainJob.Definition{
+		Name:        "conversation_state_pruner",
+		Description: "Drops expired conversation states",
+		Interval:    conversationStatePruneInterval,
+		Exclusive:   true,
+		Run:         conversationService.PruneExpired,
+	}, appLogger)
+	translationService := createTranslationService(cfg, repositories, appLogger)
+	privacyService := domainPrivacy.NewService(appLogger, repositories.GetPrivacyRepository())
+	whatsappManager.SetPrivacy(privacyService)
+	eventStore := domainWebhook.NewEventStore(appLogger, repositories.GetWebhookEventRepository(), domainWebhook.EventStoreConfig{
+		Enabled:   cfg.EventStoreEnabled,
+		Retention: cfg.EventStoreRetention,
+	})
+	webhookManager := createWebhookManager(cfg, repositories.GetWebhookRepository(), maintenanceService, activityService, routingService, translationService, privacyService, eventStore, appLogger)
+	eventStore.SetDispatcher(webhookManager.GetDeliveryService())
+	eventStream := domainWebhook.NewEventStream()
+	webhookManager.GetDeliveryService().SetEventStreamer(eventStream)
+	if eventStore.Enabled() {
+		registerJob(jobs, domainJob.Definition{
+			Name:        "webhook_event_pruner",
+			Description: "Drops stored webhook events past their retention",
+			Interval:    eventStorePruneInterval,
+			Exclusive:   true,
+			Run:         eventStore.PruneExpired,
+		}, appLogger)
+	}
 	chatwootIntegrationManager, chatwootManager := createChatwootIntegration(repositories, appLogger)
+	chatwootQueue := createChatwootWebhookQueue(appLogger)
+	chatwootIntegrationManager.SetErrorRecorder(activityService)
+	chatwootQueue.SetErrorRecorder(activityService)
+	activityService.AddQueue("webhook_delivery", webhookManager.GetDeliveryService())
+	activityService.AddQueue("chatwoot_webhooks", chatwootQueue)
+	pollService := domainPoll.NewService(appLogger, repositories.GetPollRepository())
+	whatsappManager.SetPollService(pollService)
+	contactFeed := domainContact.NewChangeFeed(appLogger, repositories.GetContactChangeRepository())
+	whatsappManager.SetContactChangeFeed(contactFeed)
+	registerJob(jobs, domainJob.Definition{
+		Name:        "contact_change_pruner",
+		Description: "Drops contact changes past their retention from the feed",
+		Interval:    contactChangePruneInterval,
+		Exclusive:   true,
+		Run:         contactFeed.PruneExpired,
+	}, appLogger)
+	whatsappManager.SetFloodProtection(wameow.FloodConfig{
+		MaxMessages: cfg.FloodMaxMessages,
+		Window:      cfg.FloodWindow,
+		Cooldown:    cfg.FloodCooldown,
+		Suppress:    cfg.FloodSuppress,
+	})
+	if cfg.ReconnectEnabled {
+		whatsappManager.SetReconnectSupervisor(wameow.ReconnectConfig{
+			InitialDelay:   cfg.ReconnectInitialDelay,
+			MaxDelay:       cfg.ReconnectMaxDelay,
+			MaxAttempts:    cfg.ReconnectMaxAttempts,
+			MaxConcurrent:  cfg.ReconnectMaxConcurrent,
+			AttemptTimeout: cfg.ReconnectAttemptTimeout,
+		})
+		if cfg.ReconnectProbeInterval > 0 {
+			registerJob(jobs, domainJob.Definition{
+				Name:        "session_reconnect_probe",
+				Description: "Hands paired sessions whose connection dropped silently to the reconnect supervisor",
+				Interval:    cfg.ReconnectProbeInterval,
+				Run:         whatsappManager.ProbeConnections,
+			}, appLogger)
+		}
+	}
+	warmupService := domainWarmup.NewService(appLogger, repositories.GetWarmupRepository(), cfg.WarmupNewSessions)
+	whatsappManager.SetSendThrottle(warmupService)
+	sendGuard := domainSendGuard.NewService(appLogger, repositories.GetSendGuardRepository(), domainSendGuard.Config{
+		BlockGroups:       cfg.SendGuardBlockGroups,
+		AllowedGroups:     cfg.SendGuardAllowedGroups,
+		Sandbox:           cfg.SandboxMode,
+		SandboxRecipients: cfg.SandboxRecipients,
+		SandboxRedirectTo: cfg.SandboxRedirectTo,
+	})
+	whatsappManager.SetSendGuard(sendGuard)
+	whatsappManager.SetSendGate(maintenanceService)
+	historyService := domainHistory.NewService(appLogger, repositories.GetHistoryRepository())
+	if cfg.MessageHistoryEnabled {
+		whatsappManager.SetMessageHistory(historyService)
+	}
+	statsService := domainStats.NewService(appLogger, repositories.GetMessageStatsRepository())
+	whatsappManager.SetMessageStats(statsService)
+	apiKeyService := domainAPIKey.NewService(appLogger, repositories.GetAPIKeyRepository(), repositories.GetSessionRepository())
+	offlineQueue := domainQueue.NewService(appLogger, repositories.GetQueueRepository(), domainQueue.Config{
+		Enabled:       cfg.OfflineQueueEnabled,
+		TTL:           cfg.OfflineQueueTTL,
+		MaxPerSession: cfg.OfflineQueueMaxPerSession,
+	})
+	offlineQueue.SetSendGate(maintenanceService)
+	whatsappManager.SetOfflineQueue(offlineQueue)
+	if offlineQueue.Enabled() {
+		registerJob(jobs, domainJob.Definition{
+			Name:        "offline_queue_expiry",
+			Description: "Expires queued messages of sessions that did not reconnect in time",
+			Interval:    offlineQueueExpiryInterval,
+			Exclusive:   true,
+			Run:         offlineQueue.ExpireOverdue,
+		}, appLogger)
+	}
+	outbox := domainOutbox.NewService(appLogger, repositories.GetOutboxRepository(), domainOutbox.Config{
+		Enabled:        cfg.OutboxEnabled,
+		MinDelay:       cfg.OutboxMinDelay,
+		MaxDelay:       cfg.OutboxMaxDelay,
+		SimulateTyping: cfg.OutboxSimulateTyping,
+	}, whatsappManager)
+	outbox.SetSendGate(maintenanceService)
+	if outbox.Enabled() {
+		registerJob(jobs, domainJob.Definition{
+			Name:        "outbox_dispatcher",
+			Description: "Sends due outbox messages, one at a time per chat",
+			Interval:    outboxDispatchInterval,
+			Exclusive:   true,
+			Run:         outbox.DispatchDue,
+		}, appLogger)
+	}
+	scheduled := domainScheduled.NewService(appLogger, repositories.GetScheduledMessageRepository())
+	scheduled.SetSendGate(maintenanceService)
+	registerJob(jobs, domainJob.Definition{
+		Name:        "message_scheduler",
+		Description: "Sends scheduled messages whose time has come",
+		Interval:    scheduledDispatchInterval,
+		Exclusive:   true,
+		Run:         scheduled.DispatchDue,
+	}, appLogger)
 
 	// Configure integrations
 	configureWebhookIntegration(whatsappManager, webhookManager, appLogger)
@@ -228,11 +485,32 @@ func initializeManagers(
 		webhook:         webhookManager,
 		chatwoot:        chatwootIntegrationManager,
 		chatwootManager: chatwootManager,
+		chatwootQueue:   chatwootQueue,
+		maintenance:     maintenanceService,
+		activity:        activityService,
+		routing:         routingService,
+		conversation:    conversationService,
+		poll:            pollService,
+		translation:     translationService,
+		contactFeed:     contactFeed,
+		warmup:          warmupService,
+		offlineQueue:    offlineQueue,
+		outbox:          outbox,
+		scheduled:       scheduled,
+		sendGuard:       sendGuard,
+		privacy:         privacyService,
+		eventStore:      eventStore,
+		eventStream:     eventStream,
+		history:         historyService,
+		stats:           statsService,
+		jobs:            jobs,
+		apiKey:          apiKeyService,
+		tracer:          tracer,
 	}
 }
 
 // createWhatsAppManager initializes the WhatsApp manager
-func createWhatsAppManager(database *platformDB.DB, sessionRepo ports.SessionRepository, appLogger *logger.Logger) *wameow.Manager {
+func createWhatsAppManager(database *platformDB.DB, sessionRepo ports.SessionRepository, jobs *domainJob.Service, appLogger *logger.Logger) *wameow.Manager {
 	factory, err := wameow.NewFactory(appLogger, sessionRepo)
 	if err != nil {
 		appLogger.Fatal("Failed to create wameow factory: " + err.Error())
@@ -243,14 +521,40 @@ func createWhatsAppManager(database *platformDB.DB, sessionRepo ports.SessionRep
 		appLogger.Fatal("Failed to create WhatsApp manager: " + err.Error())
 	}
 
+	// The manager's state is per replica, so every replica releases its own
+	registerJob(jobs, domainJob.Definition{
+		Name:        "session_janitor",
+		Description: "Releases the in-memory state of sessions deleted from the database",
+		Interval:    sessionJanitorInterval,
+		Run:         manager.ReleaseDeletedSessions,
+	}, appLogger)
+
 	appLogger.Info("WhatsApp manager initialized")
 	return manager
 }
 
 // createWebhookManager initializes the webhook manager
-func createWebhookManager(webhookRepo ports.WebhookRepository, appLogger *logger.Logger) *webhook.WebhookManager {
+func createWebhookManager(cfg *config.Config, webhookRepo ports.WebhookRepository, gate webhook.DeliveryGate, activityService *domainActivity.Service, router webhook.Router, translationService *domainTranslation.Service, privacyService *domainPrivacy.Service, eventStore webhook.EventRecorder, appLogger *logger.Logger) *webhook.WebhookManager {
 	const defaultWebhookWorkers = 5
 	webhookManager := webhook.NewWebhookManager(appLogger, webhookRepo, defaultWebhookWorkers)
+	webhookManager.GetDeliveryService().SetHTTPClientConfig(webhook.HTTPClientConfig{
+		ConnectTimeout:      cfg.WebhookConnectTimeout,
+		Timeout:             cfg.WebhookTimeout,
+		TLSHandshakeTimeout: cfg.WebhookTLSHandshakeTimeout,
+		MaxIdleConnsPerHost: cfg.WebhookMaxIdleConnsPerHost,
+		EnableHTTP2:         cfg.WebhookHTTP2,
+	})
+	webhookManager.GetDeliveryService().SetGate(gate)
+	webhookManager.GetDeliveryService().SetRouter(router)
+	// Translate incoming text before the other processors and receivers see it
+	webhookManager.GetDeliveryService().AddProcessor(translationService)
+	// Strip the content of sessions with metadata-only webhooks, translations included, before anything is kept
+	webhookManager.GetDeliveryService().AddProcessor(privacyService)
+	// Recent messages and permanent delivery failures feed the dashboard
+	webhookManager.GetDeliveryService().AddProcessor(activityService)
+	webhookManager.GetDeliveryService().SetFailureRecorder(activityService)
+	webhookManager.GetDeliveryService().SetDeliveryRecorder(activityService)
+	webhookManager.GetDeliveryService().SetEventRecorder(eventStore)
 
 	if err := webhookManager.Start(); err != nil {
 		appLogger.Fatal("Failed to start webhook manager: " + err.Error())
@@ -260,6 +564,25 @@ func createWebhookManager(webhookRepo ports.WebhookRepository, appLogger *logger
 	return webhookManager
 }
 
+// createTranslationService creates the translation service; it translates nothing when no provider is configured
+func createTranslationService(cfg *config.Config, repositories *repository.Repositories, appLogger *logger.Logger) *domainTranslation.Service {
+	var translator domainTranslation.Translator
+	if cfg.TranslationURL != "" {
+		translator = translationIntegration.NewClient(cfg.TranslationURL, cfg.TranslationAPIKey, cfg.TranslationTimeout)
+		appLogger.Info("Message translation provider configured")
+	}
+	return domainTranslation.NewService(appLogger, repositories.GetTranslationRepository(), translator)
+}
+
+// createChatwootWebhookQueue initializes the queue that processes Chatwoot webhooks in the background
+func createChatwootWebhookQueue(appLogger *logger.Logger) *chatwootIntegration.WebhookQueue {
+	const defaultChatwootWebhookWorkers = 5
+	queue := chatwootIntegration.NewWebhookQueue(appLogger, defaultChatwootWebhookWorkers)
+	queue.Start()
+
+	return queue
+}
+
 // createChatwootIntegration initializes the Chatwoot integration
 func createChatwootIntegration(repositories *repository.Repositories, appLogger *logger.Logger) (*chatwootIntegration.IntegrationManager, *chatwootIntegration.Manager) {
 	chatwootRepo := repositories.GetChatwootRepository()
@@ -285,15 +608,15 @@ func createChatwootIntegration(repositories *repository.Repositories, appLogger
 }
 
 // createContainer creates the application container with all dependencies
-func createContainer(repositories *repository.Repositories, managers managers, database *platformDB.DB, appLogger *logger.Logger) *app.Container {
+func createContainer(cfg *config.Config, repositories *repository.Repositories, managers managers, database *platformDB.DB, appLogger *logger.Logger) *app.Container {
 	// Create adapters and mappers
 	adapters := createAdapters(repositories, managers, appLogger)
 
 	// Create domain services
-	services := createDomainServices(repositories, managers, appLogger, adapters)
+	services := createDomainServices(cfg, repositories, managers, appLogger, adapters)
 
 	// Create container config
-	config := createContainerConfig(repositories, managers, database, appLogger, adapters, services)
+	config := createContainerConfig(cfg, repositories, managers, database, appLogger, adapters, services)
 
 	return app.NewContainer(config)
 }
@@ -321,12 +644,14 @@ type containerAdapters struct {
 	qrGenerator           *wameow.QRCodeGenerator
 }
 
-func createDomainServices(repositories *repository.Repositories, managers managers, appLogger *logger.Logger, adapters *containerAdapters) *containerServices {
+func createDomainServices(cfg *config.Config, repositories *repository.Repositories, managers managers, appLogger *logger.Logger, adapters *containerAdapters) *containerServices {
 	sessionService := session.NewService(
 		repositories.GetSessionRepository(),
 		managers.whatsapp,
 		adapters.qrGenerator,
+		appLogger,
 	)
+	configureStalePolicy(cfg, sessionService, managers.webhook.GetDeliveryService(), managers.jobs, appLogger)
 
 	webhookService := domainWebhook.NewService(
 		appLogger,
@@ -343,60 +668,208 @@ func createDomainServices(repositories *repository.Repositories, managers manage
 	if adapters.chatwootMessageMapper != nil {
 		chatwootService.SetMessageMapper(adapters.chatwootMessageMapper)
 	}
+	chatwootService.SetDeduplicator(chatwootIntegration.NewWebhookDeduplicator(chatwootIntegration.DefaultWebhookDedupeTTL))
+
+	chatService := domainChat.NewService(appLogger, managers.whatsapp, managers.history, managers.whatsapp)
+	chatwootService.SetTypingIndicator(chatService)
 
 	return &containerServices{
-		sessionService:    sessionService,
-		webhookService:    webhookService,
-		chatwootService:   chatwootService,
-		groupService:      domainGroup.NewService(nil, managers.whatsapp, adapters.jidValidator),
-		contactService:    domainContact.NewService(managers.whatsapp, appLogger),
-		mediaService:      domainMedia.NewService(nil, nil, appLogger, "/tmp/media_cache"),
-		newsletterService: domainNewsletter.NewService(nil),
-		communityService:  domainCommunity.NewService(),
+		sessionService:      sessionService,
+		webhookService:      webhookService,
+		chatwootService:     chatwootService,
+		groupService:        domainGroup.NewService(nil, managers.whatsapp, adapters.jidValidator),
+		contactService:      domainContact.NewService(managers.whatsapp, appLogger),
+		chatService:         chatService,
+		mediaService:        domainMedia.NewService(nil, nil, appLogger, "/tmp/media_cache"),
+		newsletterService:   domainNewsletter.NewService(nil),
+		communityService:    domainCommunity.NewService(),
+		draftService:        createDraftService(repositories, managers, appLogger),
+		maintenanceService:  managers.maintenance,
+		settingsService:     createSettingsService(repositories, managers.jobs, appLogger),
+		activityService:     managers.activity,
+		pairingService:      createPairingService(appLogger),
+		routingService:      managers.routing,
+		conversationService: managers.conversation,
+		translationService:  managers.translation,
+		usageService: domainUsage.NewService(appLogger, repositories.GetUsageRepository(), domainUsage.Limits{
+			MessagesPerDay:  int64(cfg.QuotaMessagesPerDay),
+			MediaMBPerMonth: int64(cfg.QuotaMediaMBPerMonth),
+			MaxSessions:     cfg.QuotaMaxSessions,
+		}),
 	}
 }
 
+// configureStalePolicy applies the stale session policy and starts its sweep when enabled
+func configureStalePolicy(cfg *config.Config, sessionService *session.Service, dispatcher session.EventDispatcher, jobs *domainJob.Service, appLogger *logger.Logger) {
+	policy := session.StalePolicy{
+		After:  time.Duration(cfg.StaleSessionDays) * 24 * time.Hour,
+		Action: cfg.StaleSessionAction,
+	}
+	if err := policy.Validate(); err != nil {
+		appLogger.Warn("Unknown STALE_SESSION_ACTION " + cfg.StaleSessionAction + ", stale sessions are only flagged")
+		policy.Action = session.StaleActionFlag
+	}
+
+	sessionService.SetStalePolicy(policy, dispatcher)
+	if policy.Enabled() && policy.Action != session.StaleActionFlag {
+		registerJob(jobs, domainJob.Definition{
+			Name:        "stale_session_sweep",
+			Description: "Applies the stale session policy",
+			Interval:    staleSessionSweepInterval,
+			Exclusive:   true,
+			Run:         sessionService.SweepStaleSessions,
+		}, appLogger)
+	}
+}
+
+// createDraftService creates the draft service and starts its scheduler
+func createDraftService(repositories *repository.Repositories, managers managers, appLogger *logger.Logger) *domainDraft.Service {
+	draftService := domainDraft.NewService(appLogger, repositories.GetDraftRepository())
+	draftService.SetSendGate(managers.maintenance)
+	registerJob(managers.jobs, domainJob.Definition{
+		Name:        "draft_scheduler",
+		Description: "Sends scheduled drafts whose time has come",
+		Interval:    draftSchedulerInterval,
+		Exclusive:   true,
+		Run:         draftService.DispatchDue,
+	}, appLogger)
+	return draftService
+}
+
+// registerJob adds a background job to the scheduler. Exclusive jobs run on a single replica at a time;
+// jobs that only touch the replica's own memory, like the session janitor and the settings sync, run
+// everywhere instead.
+func registerJob(jobs *domainJob.Service, def domainJob.Definition, appLogger *logger.Logger) {
+	if err := jobs.Register(def); err != nil {
+		appLogger.ErrorWithFields("Failed to register background job", map[string]interface{}{
+			"job":   def.Name,
+			"error": err.Error(),
+		})
+	}
+}
+
+// createMaintenanceService loads the persisted maintenance state and keeps it in sync with other replicas
+func createMaintenanceService(repositories *repository.Repositories, jobs *domainJob.Service, appLogger *logger.Logger) *domainMaintenance.Service {
+	maintenanceService := domainMaintenance.NewService(appLogger, repositories.GetMaintenanceRepository())
+	if err := maintenanceService.Load(context.Background()); err != nil {
+		appLogger.Warn("Failed to load maintenance state, starting out of maintenance: " + err.Error())
+	}
+	registerJob(jobs, domainJob.Definition{
+		Name:        "maintenance_sync",
+		Description: "Picks up maintenance changes made through other replicas",
+		Interval:    maintenanceSyncInterval,
+		Run:         maintenanceService.Sync,
+	}, appLogger)
+	return maintenanceService
+}
+
+// createSettingsService loads the persisted runtime settings and keeps them in sync with other replicas
+func createSettingsService(repositories *repository.Repositories, jobs *domainJob.Service, appLogger *logger.Logger) *domainSettings.Service {
+	// The level the logger started with applies until a log level is persisted
+	settingsService := domainSettings.NewService(appLogger, repositories.GetSettingsRepository(), logger.Level())
+	if err := settingsService.Load(context.Background()); err != nil {
+		appLogger.Warn("Failed to load runtime settings, using defaults: " + err.Error())
+	}
+	registerJob(jobs, domainJob.Definition{
+		Name:        "settings_sync",
+		Description: "Picks up runtime settings changed by other replicas",
+		Interval:    settingsSyncInterval,
+		Run:         settingsService.Sync,
+	}, appLogger)
+	return settingsService
+}
+
+// createPairingService creates the service that signs public pairing links
+func createPairingService(appLogger *logger.Logger) *domainPairing.Service {
+	pairingService, err := domainPairing.NewService(appLogger)
+	if err != nil {
+		appLogger.Fatal("Failed to create pairing service: " + err.Error())
+	}
+	return pairingService
+}
+
 type containerServices struct {
-	sessionService    *session.Service
-	webhookService    *domainWebhook.Service
-	chatwootService   *domainChatwoot.Service
-	groupService      *domainGroup.Service
-	contactService    domainContact.Service
-	mediaService      domainMedia.Service
-	newsletterService *domainNewsletter.Service
-	communityService  domainCommunity.Service
+	sessionService      *session.Service
+	webhookService      *domainWebhook.Service
+	chatwootService     *domainChatwoot.Service
+	groupService        *domainGroup.Service
+	contactService      domainContact.Service
+	chatService         *domainChat.Service
+	mediaService        domainMedia.Service
+	newsletterService   *domainNewsletter.Service
+	communityService    domainCommunity.Service
+	draftService        *domainDraft.Service
+	usageService        *domainUsage.Service
+	maintenanceService  *domainMaintenance.Service
+	settingsService     *domainSettings.Service
+	activityService     *domainActivity.Service
+	pairingService      *domainPairing.Service
+	routingService      *domainRouting.Service
+	conversationService *domainConversation.Service
+	translationService  *domainTranslation.Service
 }
 
-func createContainerConfig(repositories *repository.Repositories, managers managers, database *platformDB.DB, appLogger *logger.Logger, adapters *containerAdapters, services *containerServices) *app.ContainerConfig {
+func createContainerConfig(cfg *config.Config, repositories *repository.Repositories, managers managers, database *platformDB.DB, appLogger *logger.Logger, adapters *containerAdapters, services *containerServices) *app.ContainerConfig {
 	return &app.ContainerConfig{
 		// Repositories
-		SessionRepo:         repositories.GetSessionRepository(),
-		WebhookRepo:         repositories.GetWebhookRepository(),
-		ChatwootRepo:        repositories.GetChatwootRepository(),
-		ChatwootMessageRepo: repositories.GetChatwootMessageRepository(),
+		SessionRepo:  repositories.GetSessionRepository(),
+		WebhookRepo:  repositories.GetWebhookRepository(),
+		ChatwootRepo: repositories.GetChatwootRepository(),
+		DraftRepo:    repositories.GetDraftRepository(),
 
 		// Managers and Integrations
 		WameowManager:         managers.whatsapp,
 		ChatwootIntegration:   nil, // IntegrationManager doesn't implement this interface
 		ChatwootManager:       managers.chatwootManager,
 		ChatwootMessageMapper: adapters.chatwootMessageMapper,
+		ChatwootWebhookQueue:  managers.chatwootQueue,
+		WebhookDispatch:       managers.webhook.GetDeliveryService(),
 		JIDValidator:          adapters.jidValidator,
 		NewsletterManager:     adapters.newsletterManager,
 		CommunityManager:      adapters.communityManager,
 
 		// Domain Services
-		SessionService:    services.sessionService,
-		WebhookService:    services.webhookService,
-		ChatwootService:   services.chatwootService,
-		GroupService:      services.groupService,
-		ContactService:    services.contactService,
-		MediaService:      services.mediaService,
-		NewsletterService: services.newsletterService,
-		CommunityService:  services.communityService,
+		SessionService:      services.sessionService,
+		WebhookService:      services.webhookService,
+		ChatwootService:     services.chatwootService,
+		GroupService:        services.groupService,
+		ContactService:      services.contactService,
+		ContactChangeFeed:   managers.contactFeed,
+		ChatService:         services.chatService,
+		WarmupService:       managers.warmup,
+		QueueService:        managers.offlineQueue,
+		OutboxService:       managers.outbox,
+		ScheduledService:    managers.scheduled,
+		SendGuardService:    managers.sendGuard,
+		PrivacyService:      managers.privacy,
+		EventStore:          managers.eventStore,
+		EventStream:         managers.eventStream,
+		HistoryService:      managers.history,
+		PollService:         managers.poll,
+		StatsService:        managers.stats,
+		APIKeyService:       managers.apiKey,
+		JobService:          managers.jobs,
+		MediaService:        services.mediaService,
+		NewsletterService:   services.newsletterService,
+		CommunityService:    services.communityService,
+		DraftService:        services.draftService,
+		UsageService:        services.usageService,
+		MaintenanceService:  services.maintenanceService,
+		SettingsService:     services.settingsService,
+		ActivityService:     services.activityService,
+		PairingService:      services.pairingService,
+		RoutingService:      services.routingService,
+		ConversationService: services.conversationService,
+		TranslationService:  services.translationService,
 
 		// Infrastructure
-		Logger: appLogger,
-		DB:     database.GetDB().DB,
+		Logger:    appLogger,
+		DB:        database.GetDB().DB,
+		ServerURL: cfg.GetServerURL(),
+		MediaLimits: domainMessage.MediaLimits{
+			MaxSize:         int64(cfg.MediaMaxSizeMB) * 1024 * 1024,
+			DownloadTimeout: cfg.MediaDownloadTimeout,
+		},
 
 		// Build Info
 		Version:   Version,
@@ -406,56 +879,147 @@ func createContainerConfig(repositories *repository.Repositories, managers manag
 }
 
 // setupHTTPServer creates and configures the Fiber HTTP server
-func setupHTTPServer(cfg *config.Config, container *app.Container, database *platformDB.DB, whatsappManager *wameow.Manager, appLogger *logger.Logger) *fiber.App {
-	fiberApp := fiber.New(fiber.Config{
+func setupHTTPServer(cfg *config.Config, container *app.Container, database *platformDB.DB, mgrs managers, appLogger *logger.Logger) *fiber.App {
+	whatsappManager := mgrs.whatsapp
+
+	fiberConfig := fiber.Config{
 		DisableStartupMessage: true,
+		// Bodies are streamed so media uploads reach the handlers without being buffered; BodyLimit
+		// enforces the limit on the other requests
+		StreamRequestBody:            true,
+		DisablePreParseMultipartForm: true,
 		ErrorHandler: func(c *fiber.Ctx, err error) error {
 			code := fiber.StatusInternalServerError
 			if e, ok := err.(*fiber.Error); ok {
 				code = e.Code
 			}
-			return c.Status(code).JSON(fiber.Map{
-				"error": err.Error(),
-			})
+			return c.Status(code).JSON(common.NewErrorResponse(err.Error()))
 		},
-	})
+	}
+	// Behind trusted proxies the client IP, used in logs and auth lockouts, comes from the proxy header
+	if len(cfg.TrustedProxies) > 0 {
+		fiberConfig.EnableTrustedProxyCheck = true
+		fiberConfig.TrustedProxies = cfg.TrustedProxies
+		fiberConfig.ProxyHeader = cfg.ProxyHeader
+		fiberConfig.EnableIPValidation = true
+	}
+	fiberApp := fiber.New(fiberConfig)
 
 	// Configure middlewares
-	setupMiddlewares(fiberApp, cfg, container, appLogger)
+	setupMiddlewares(fiberApp, cfg, container, mgrs.webhook.GetDeliveryService(), appLogger)
 
 	// Setup routes
 	routers.SetupRoutes(fiberApp, database, appLogger, whatsappManager, container)
 
+	if cfg.DashboardEnabled {
+		routers.SetupDashboardRoutes(fiberApp, container, appLogger)
+	}
+
+	if cfg.HasAdminAPIKey() {
+		routers.SetupDebugRoutes(fiberApp, cfg, appLogger, whatsappManager, mgrs.webhook, mgrs.chatwootQueue, container)
+	}
+
+	if cfg.EvolutionCompat {
+		routers.SetupEvolutionRoutes(fiberApp, appLogger, whatsappManager, container)
+	}
+
 	return fiberApp
 }
 
 // setupMiddlewares configures all HTTP middlewares
-func setupMiddlewares(app *fiber.App, cfg *config.Config, container *app.Container, appLogger *logger.Logger) {
+func setupMiddlewares(app *fiber.App, cfg *config.Config, container *app.Container, authEvents middleware.AuthEventDispatcher, appLogger *logger.Logger) {
 	app.Use(recover.New())
 	app.Use(middleware.RequestID(appLogger))
+	app.Use(middleware.Tracing())
 	app.Use(middleware.HTTPLogger(appLogger))
+	app.Use(middleware.BodyLimit(fiber.DefaultBodyLimit, appLogger))
+	app.Use(middleware.BodyLogger(container, appLogger))
 	app.Use(middleware.Metrics(container, appLogger))
 	app.Use(cors.New())
-	app.Use(middleware.APIKeyAuth(cfg, appLogger))
+	app.Use(middleware.APIKeyAuth(cfg, container, appLogger, authEvents))
+	app.Use(middleware.ResponseEnvelope(cfg, appLogger))
+	app.Use(middleware.ResponseShaping(appLogger))
+	app.Use(middleware.Maintenance(container, appLogger))
+	app.Use(middleware.SessionConcurrency(cfg, container, appLogger))
 }
 
-// startBackgroundServices starts all background services
-func startBackgroundServices(container *app.Container, appLogger *logger.Logger) {
-	go connectOnStartup(container, appLogger)
+// startBackgroundServices registers the startup jobs and starts the background job scheduler
+func startBackgroundServices(container *app.Container, mgrs managers, appLogger *logger.Logger) {
+	registerJob(mgrs.jobs, domainJob.Definition{
+		Name:        "session_autoconnect",
+		Description: "Connects the stored paired sessions that are not connected",
+		RunOnStart:  true,
+		StartDelay:  autoConnectDelay,
+		Run: func(ctx context.Context) error {
+			return connectOnStartup(ctx, container, mgrs.whatsapp, appLogger)
+		},
+	}, appLogger)
+
+	mgrs.jobs.Start(context.Background())
 }
 
-// setupGracefulShutdown configures graceful shutdown handling
-func setupGracefulShutdown(fiberApp *fiber.App, appLogger *logger.Logger) {
+// setupGracefulShutdown runs the shutdown on SIGINT/SIGTERM; the returned channel is closed when it is done
+func setupGracefulShutdown(fiberApp *fiber.App, cfg *config.Config, mgrs managers, appLogger *logger.Logger) <-chan struct{} {
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 
+	done := make(chan struct{})
 	go func() {
+		defer close(done)
 		<-c
-		appLogger.Info("Shutting down server...")
-		if err := fiberApp.Shutdown(); err != nil {
-			appLogger.Error("Failed to shutdown server gracefully: " + err.Error())
+
+		gracePeriod := cfg.ShutdownGracePeriod
+		if gracePeriod <= 0 {
+			gracePeriod = defaultShutdownGracePeriod
 		}
+
+		appLogger.InfoWithFields("Shutting down server...", map[string]interface{}{
+			"grace_period": gracePeriod.String(),
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), gracePeriod)
+		defer cancel()
+
+		shutdown(ctx, fiberApp, mgrs, appLogger)
 	}()
+
+	return done
+}
+
+// shutdown stops the process in dependency order within ctx: HTTP first so no new work comes in,
+// then the background jobs and the WhatsApp clients that produce events, then the webhook queues
+// that deliver them, and last the trace exporter so the spans of the shutdown are sent. A step that runs out of time is abandoned and the next one still runs.
+func shutdown(ctx context.Context, fiberApp *fiber.App, mgrs managers, appLogger *logger.Logger) {
+	startedAt := time.Now()
+
+	// Event streams run on hijacked connections the HTTP server no longer tracks
+	mgrs.eventStream.Close()
+	if err := fiberApp.ShutdownWithContext(ctx); err != nil {
+		appLogger.Error("Failed to shutdown server gracefully: " + err.Error())
+	}
+
+	steps := []struct {
+		name string
+		stop func(ctx context.Context) error
+	}{
+		{"background jobs", mgrs.jobs.Stop},
+		{"WhatsApp clients", mgrs.whatsapp.Shutdown},
+		{"webhook delivery", mgrs.webhook.Shutdown},
+		{"Chatwoot webhook queue", mgrs.chatwootQueue.Shutdown},
+		{"trace exporter", mgrs.tracer.Shutdown},
+	}
+
+	for _, step := range steps {
+		if err := step.stop(ctx); err != nil {
+			appLogger.WarnWithFields("Shutdown step did not finish cleanly", map[string]interface{}{
+				"step":  step.name,
+				"error": err.Error(),
+			})
+		}
+	}
+
+	appLogger.InfoWithFields("Shutdown complete", map[string]interface{}{
+		"duration": time.Since(startedAt).String(),
+	})
 }
 
 // startServer starts the HTTP server
@@ -581,54 +1145,80 @@ func configureChatwootIntegration(whatsappManager *wameow.Manager, integrationMa
 	appLogger.Info("Chatwoot integration configured successfully")
 }
 
-// connectOnStartup automatically reconnects existing sessions on startup
-func connectOnStartup(container *app.Container, logger *logger.Logger) {
+// connectOnStartup automatically reconnects existing sessions on startup. With the reconnect supervisor
+// the paired sessions are handed to it, which connects them as fast as its concurrency cap allows;
+// otherwise they connect one by one.
+func connectOnStartup(ctx context.Context, container *app.Container, wameowManager *wameow.Manager, logger *logger.Logger) error {
 	const (
-		startupDelay     = 3 * time.Second
 		operationTimeout = 60 * time.Second
 		sessionLimit     = 100
 		reconnectDelay   = 1 * time.Second
 	)
 
-	time.Sleep(startupDelay)
-
 	sessionUC := container.GetSessionUseCase()
 	sessionRepo := container.GetSessionRepository()
 
 	if sessionUC == nil || sessionRepo == nil {
-		logger.Error("Required components not available, skipping auto-connect")
-		return
+		return fmt.Errorf("required components not available, skipping auto-connect")
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), operationTimeout)
+	ctx, cancel := context.WithTimeout(ctx, operationTimeout)
 	defer cancel()
 
 	sessions := getExistingSessions(ctx, sessionRepo, sessionLimit, logger)
 	if len(sessions) == 0 {
 		logger.Info("No existing sessions found, skipping auto-connect")
-		return
+		return nil
 	}
 
 	logger.InfoWithFields("Starting auto-reconnect", map[string]interface{}{
 		"total_sessions": len(sessions),
 	})
 
-	stats := reconnectSessions(ctx, sessions, sessionUC, logger, reconnectDelay)
+	if wameowManager != nil && wameowManager.ReconnectSupervised() {
+		stats := scheduleReconnects(sessions, wameowManager)
+		logger.InfoWithFields("Auto-reconnect handed to the reconnect supervisor", map[string]interface{}{
+			"scheduled": stats.scheduled,
+			"skipped":   stats.skipped,
+		})
+		return nil
+	}
+
+	stats := reconnectSessions(ctx, sessions, sessionUC, wameowManager, logger, reconnectDelay)
 
 	logger.InfoWithFields("Auto-reconnect completed", map[string]interface{}{
 		"connected": stats.connected,
 		"skipped":   stats.skipped,
 		"failed":    stats.failed,
 	})
+	return nil
 }
 
 // reconnectStats holds statistics for reconnection process
 type reconnectStats struct {
 	connected int
+	scheduled int
 	skipped   int
 	failed    int
 }
 
+// scheduleReconnects hands the paired sessions to the reconnect supervisor
+func scheduleReconnects(sessions []*session.Session, wameowManager *wameow.Manager) reconnectStats {
+	stats := reconnectStats{}
+
+	for _, sess := range sessions {
+		if sess.DeviceJid == "" || wameowManager.IsConnected(sess.ID.String()) {
+			stats.skipped++
+			continue
+		}
+
+		wameowManager.ScheduleReconnect(sess.ID.String(), wameow.ReconnectReasonStartup)
+		stats.scheduled++
+	}
+
+	return stats
+}
+
 // getExistingSessions retrieves existing sessions from repository
 func getExistingSessions(ctx context.Context, sessionRepo ports.SessionRepository, limit int, logger *logger.Logger) []*session.Session {
 	sessions, _, err := sessionRepo.List(ctx, &session.ListSessionsRequest{
@@ -645,13 +1235,13 @@ func getExistingSessions(ctx context.Context, sessionRepo ports.SessionRepositor
 }
 
 // reconnectSessions attempts to reconnect all valid sessions
-func reconnectSessions(ctx context.Context, sessions []*session.Session, sessionUC sessionApp.UseCase, logger *logger.Logger, delay time.Duration) reconnectStats {
+func reconnectSessions(ctx context.Context, sessions []*session.Session, sessionUC sessionApp.UseCase, wameowManager *wameow.Manager, logger *logger.Logger, delay time.Duration) reconnectStats {
 	stats := reconnectStats{}
 
 	for _, sess := range sessions {
 		sessionID := sess.ID.String()
 
-		if sess.DeviceJid == "" {
+		if sess.DeviceJid == "" || (wameowManager != nil && wameowManager.IsConnected(sessionID)) {
 			stats.skipped++
 			continue
 		}
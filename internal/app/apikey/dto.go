@@ -0,0 +1,89 @@
+package apikey
+
+import (
+	"time"
+
+	"zpwoot/internal/domain/apikey"
+)
+
+type CreateAPIKeyRequest struct {
+	Name string `json:"name" validate:"required" example:"billing-service"`
+	// Scopes are send, manage-sessions and/or read-only; send and manage-sessions can also read
+	Scopes []string `json:"scopes" validate:"required,min=1" example:"send"`
+	// Sessions restricts the key to those sessions, given by ID or name; empty allows every session
+	Sessions  []string   `json:"sessions,omitempty" example:"mySession"`
+	Workspace string     `json:"workspace,omitempty" example:"acme"` // Workspace the usage of the key is accounted to
+	ExpiresAt *time.Time `json:"expiresAt,omitempty" example:"2025-01-01T00:00:00Z"`
+} //@name CreateAPIKeyRequest
+
+// UpdateAPIKeyRequest changes the fields that are set; an empty sessions list lifts the session restriction
+type UpdateAPIKeyRequest struct {
+	Name      *string   `json:"name,omitempty" example:"billing-service"`
+	Scopes    *[]string `json:"scopes,omitempty" example:"send,read-only"`
+	Sessions  *[]string `json:"sessions,omitempty" example:"mySession"`
+	Workspace *string   `json:"workspace,omitempty" example:"acme"`
+} //@name UpdateAPIKeyRequest
+
+type APIKeyResponse struct {
+	ID   string `json:"id" example:"4f1c2a8e-5b7d-4e3f-9a6c-1d2e3f4a5b6c"`
+	Name string `json:"name" example:"billing-service"`
+	// Prefix is the start of the key, to tell keys apart
+	Prefix     string     `json:"prefix" example:"zpw_live_3f9a"`
+	Scopes     []string   `json:"scopes" example:"send"`
+	SessionIDs []string   `json:"sessionIds" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	Workspace  string     `json:"workspace,omitempty" example:"acme"`
+	Status     string     `json:"status" example:"active"` // active, expired or revoked
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty" example:"2025-01-01T00:00:00Z"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty" example:"2024-01-01T12:00:00Z"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty" example:"2024-02-01T12:00:00Z"`
+	CreatedAt  time.Time  `json:"createdAt" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt  time.Time  `json:"updatedAt" example:"2024-01-01T00:00:00Z"`
+} //@name APIKeyResponse
+
+type CreateAPIKeyResponse struct {
+	APIKeyResponse
+	// Key is the API key itself; it is only returned here and cannot be read back
+	Key string `json:"key" example:"zpw_live_3f9a1c0b7e2d4f6a8b9c0d1e2f3a4b5c6d7e8f9a0b1c2d3e"`
+} //@name CreateAPIKeyResponse
+
+type ListAPIKeysResponse struct {
+	Keys  []APIKeyResponse `json:"keys"`
+	Total int              `json:"total" example:"2"`
+} //@name ListAPIKeysResponse
+
+// API key statuses
+const (
+	StatusActive  = "active"
+	StatusExpired = "expired"
+	StatusRevoked = "revoked"
+)
+
+func FromAPIKey(key *apikey.APIKey) *APIKeyResponse {
+	status := StatusActive
+	switch key.Status(time.Now()) {
+	case apikey.ErrAPIKeyRevoked:
+		status = StatusRevoked
+	case apikey.ErrAPIKeyExpired:
+		status = StatusExpired
+	}
+
+	sessionIDs := key.SessionIDs
+	if sessionIDs == nil {
+		sessionIDs = []string{}
+	}
+
+	return &APIKeyResponse{
+		ID:         key.ID.String(),
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		SessionIDs: sessionIDs,
+		Workspace:  key.Workspace,
+		Status:     status,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+		CreatedAt:  key.CreatedAt,
+		UpdatedAt:  key.UpdatedAt,
+	}
+}
@@ -0,0 +1,106 @@
+package apikey
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"zpwoot/internal/domain/apikey"
+)
+
+type UseCase interface {
+	CreateKey(ctx context.Context, req *CreateAPIKeyRequest) (*CreateAPIKeyResponse, error)
+	ListKeys(ctx context.Context) (*ListAPIKeysResponse, error)
+	GetKey(ctx context.Context, id uuid.UUID) (*APIKeyResponse, error)
+	UpdateKey(ctx context.Context, id uuid.UUID, req *UpdateAPIKeyRequest) (*APIKeyResponse, error)
+	RevokeKey(ctx context.Context, id uuid.UUID) (*APIKeyResponse, error)
+	// Authenticate returns the stored key matching the key sent with a request
+	Authenticate(ctx context.Context, key string) (*apikey.APIKey, error)
+	// Authorize checks that key may make a request needing scope on the session given by ID or name
+	Authorize(ctx context.Context, key *apikey.APIKey, scope, sessionIdentifier string) error
+}
+
+type useCaseImpl struct {
+	apiKeyService *apikey.Service
+}
+
+func NewUseCase(apiKeyService *apikey.Service) UseCase {
+	return &useCaseImpl{
+		apiKeyService: apiKeyService,
+	}
+}
+
+func (uc *useCaseImpl) CreateKey(ctx context.Context, req *CreateAPIKeyRequest) (*CreateAPIKeyResponse, error) {
+	key, plaintext, err := uc.apiKeyService.Create(ctx, &apikey.CreateRequest{
+		Name:      req.Name,
+		Scopes:    req.Scopes,
+		Sessions:  req.Sessions,
+		Workspace: req.Workspace,
+		ExpiresAt: req.ExpiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &CreateAPIKeyResponse{
+		APIKeyResponse: *FromAPIKey(key),
+		Key:            plaintext,
+	}, nil
+}
+
+func (uc *useCaseImpl) ListKeys(ctx context.Context) (*ListAPIKeysResponse, error) {
+	keys, err := uc.apiKeyService.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]APIKeyResponse, len(keys))
+	for i, key := range keys {
+		responses[i] = *FromAPIKey(key)
+	}
+
+	return &ListAPIKeysResponse{
+		Keys:  responses,
+		Total: len(responses),
+	}, nil
+}
+
+func (uc *useCaseImpl) GetKey(ctx context.Context, id uuid.UUID) (*APIKeyResponse, error) {
+	key, err := uc.apiKeyService.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return FromAPIKey(key), nil
+}
+
+func (uc *useCaseImpl) UpdateKey(ctx context.Context, id uuid.UUID, req *UpdateAPIKeyRequest) (*APIKeyResponse, error) {
+	key, err := uc.apiKeyService.Update(ctx, id, &apikey.UpdateRequest{
+		Name:      req.Name,
+		Scopes:    req.Scopes,
+		Sessions:  req.Sessions,
+		Workspace: req.Workspace,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return FromAPIKey(key), nil
+}
+
+func (uc *useCaseImpl) RevokeKey(ctx context.Context, id uuid.UUID) (*APIKeyResponse, error) {
+	key, err := uc.apiKeyService.Revoke(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return FromAPIKey(key), nil
+}
+
+func (uc *useCaseImpl) Authenticate(ctx context.Context, key string) (*apikey.APIKey, error) {
+	return uc.apiKeyService.Authenticate(ctx, key)
+}
+
+func (uc *useCaseImpl) Authorize(ctx context.Context, key *apikey.APIKey, scope, sessionIdentifier string) error {
+	return uc.apiKeyService.Authorize(ctx, key, scope, sessionIdentifier)
+}
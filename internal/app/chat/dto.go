@@ -0,0 +1,51 @@
+package chat
+
+import (
+	"time"
+
+	"zpwoot/internal/domain/chat"
+)
+
+type MuteChatRequest struct {
+	DurationSeconds int64 `json:"durationSeconds,omitempty" validate:"omitempty,min=0" example:"28800"` // 0 mutes until unmuted
+} //@name MuteChatRequest
+
+type ClearChatRequest struct {
+	KeepStarred bool `json:"keepStarred,omitempty" example:"false"` // Keep the starred messages when clearing
+	DeleteMedia bool `json:"deleteMedia,omitempty" example:"false"` // Also delete the media of the messages from the devices
+} //@name ClearChatRequest
+
+type TypingRequest struct {
+	Action         string `json:"action" validate:"required,oneof=start stop" example:"start"`              // start or stop
+	TimeoutSeconds int64  `json:"timeoutSeconds,omitempty" validate:"omitempty,min=1,max=300" example:"25"` // How long a started indicator lasts; 25 when omitted
+} //@name TypingRequest
+
+// Validate checks the action and that the timeout is within 1 to 300 seconds when given
+func (r *TypingRequest) Validate() error {
+	if r.Action != "start" && r.Action != "stop" {
+		return chat.ErrInvalidTypingAction
+	}
+	if r.TimeoutSeconds != 0 && (r.TimeoutSeconds < 1 || r.TimeoutSeconds > int64(chat.MaxTypingTimeout/time.Second)) {
+		return chat.ErrInvalidTypingTimeout
+	}
+	return nil
+}
+
+type ChatActionResponse struct {
+	ChatJID     string     `json:"chatJid" example:"5511999999999@s.whatsapp.net"`
+	Action      string     `json:"action" example:"archive"` // archive, unarchive, pin, unpin, mute, unmute, read, unread, clear, delete, typing_start or typing_stop
+	MutedUntil  *time.Time `json:"mutedUntil,omitempty" example:"2024-01-01T20:00:00Z"`
+	TypingUntil *time.Time `json:"typingUntil,omitempty" example:"2024-01-01T12:00:25Z"`
+	AppliedAt   time.Time  `json:"appliedAt" example:"2024-01-01T12:00:00Z"`
+} //@name ChatActionResponse
+
+// FromResult converts the outcome of a chat action to its response
+func FromResult(r *chat.Result) *ChatActionResponse {
+	return &ChatActionResponse{
+		ChatJID:     r.ChatJID,
+		Action:      r.Action,
+		MutedUntil:  r.MutedUntil,
+		TypingUntil: r.TypingUntil,
+		AppliedAt:   r.AppliedAt,
+	}
+}
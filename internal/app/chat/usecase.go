@@ -0,0 +1,77 @@
+package chat
+
+import (
+	"context"
+	"time"
+
+	"zpwoot/internal/domain/chat"
+)
+
+type UseCase interface {
+	Archive(ctx context.Context, sessionID, chatJID string, archive bool) (*ChatActionResponse, error)
+	Pin(ctx context.Context, sessionID, chatJID string, pin bool) (*ChatActionResponse, error)
+	Mute(ctx context.Context, sessionID, chatJID string, req *MuteChatRequest) (*ChatActionResponse, error)
+	Unmute(ctx context.Context, sessionID, chatJID string) (*ChatActionResponse, error)
+	MarkRead(ctx context.Context, sessionID, chatJID string, read bool) (*ChatActionResponse, error)
+	Clear(ctx context.Context, sessionID, chatJID string, req *ClearChatRequest) (*ChatActionResponse, error)
+	Delete(ctx context.Context, sessionID, chatJID string, deleteMedia bool) (*ChatActionResponse, error)
+	Typing(ctx context.Context, sessionID, chatJID string, req *TypingRequest) (*ChatActionResponse, error)
+}
+
+type useCaseImpl struct {
+	chatService *chat.Service
+}
+
+func NewUseCase(chatService *chat.Service) UseCase {
+	return &useCaseImpl{
+		chatService: chatService,
+	}
+}
+
+func (uc *useCaseImpl) Archive(ctx context.Context, sessionID, chatJID string, archive bool) (*ChatActionResponse, error) {
+	return toResponse(uc.chatService.Archive(ctx, sessionID, chatJID, archive))
+}
+
+func (uc *useCaseImpl) Pin(ctx context.Context, sessionID, chatJID string, pin bool) (*ChatActionResponse, error) {
+	return toResponse(uc.chatService.Pin(ctx, sessionID, chatJID, pin))
+}
+
+func (uc *useCaseImpl) Mute(ctx context.Context, sessionID, chatJID string, req *MuteChatRequest) (*ChatActionResponse, error) {
+	return toResponse(uc.chatService.Mute(ctx, sessionID, chatJID, time.Duration(req.DurationSeconds)*time.Second))
+}
+
+func (uc *useCaseImpl) Unmute(ctx context.Context, sessionID, chatJID string) (*ChatActionResponse, error) {
+	return toResponse(uc.chatService.Unmute(ctx, sessionID, chatJID))
+}
+
+func (uc *useCaseImpl) MarkRead(ctx context.Context, sessionID, chatJID string, read bool) (*ChatActionResponse, error) {
+	return toResponse(uc.chatService.MarkRead(ctx, sessionID, chatJID, read))
+}
+
+func (uc *useCaseImpl) Clear(ctx context.Context, sessionID, chatJID string, req *ClearChatRequest) (*ChatActionResponse, error) {
+	return toResponse(uc.chatService.Clear(ctx, sessionID, chatJID, chat.ClearOptions{
+		KeepStarred: req.KeepStarred,
+		DeleteMedia: req.DeleteMedia,
+	}))
+}
+
+func (uc *useCaseImpl) Delete(ctx context.Context, sessionID, chatJID string, deleteMedia bool) (*ChatActionResponse, error) {
+	return toResponse(uc.chatService.Delete(ctx, sessionID, chatJID, chat.ClearOptions{DeleteMedia: deleteMedia}))
+}
+
+func (uc *useCaseImpl) Typing(ctx context.Context, sessionID, chatJID string, req *TypingRequest) (*ChatActionResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if req.Action == "stop" {
+		return toResponse(uc.chatService.StopTyping(ctx, sessionID, chatJID))
+	}
+	return toResponse(uc.chatService.StartTyping(ctx, sessionID, chatJID, time.Duration(req.TimeoutSeconds)*time.Second))
+}
+
+func toResponse(result *chat.Result, err error) (*ChatActionResponse, error) {
+	if err != nil {
+		return nil, err
+	}
+	return FromResult(result), nil
+}
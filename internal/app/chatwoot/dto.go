@@ -28,10 +28,13 @@ type CreateChatwootConfigRequest struct {
 	ImportMessages *bool    `json:"importMessages,omitempty" example:"false"`
 	ImportDays     *int     `json:"importDays,omitempty" example:"60"`
 	MergeBrazil    *bool    `json:"mergeBrazil,omitempty" example:"true"`
+	SyncReads      *bool    `json:"syncReads,omitempty" example:"true"`
+	SendReads      *bool    `json:"sendReads,omitempty" example:"false"`
 	Organization   *string  `json:"organization,omitempty" example:"zpwoot Bot"`
 	Logo           *string  `json:"logo,omitempty" example:"https://zpwoot.com/logo.png"`
 	Number         *string  `json:"number,omitempty" example:"5511999999999"`
 	IgnoreJids     []string `json:"ignoreJids,omitempty" example:"[\"5511888888888@s.whatsapp.net\"]"`
+	WebhookSecret  *string  `json:"webhookSecret,omitempty" example:"s3cr3t-webhook-token"`
 } //@name CreateChatwootConfigRequest
 
 type CreateChatwootConfigResponse struct {
@@ -61,20 +64,24 @@ type UpdateChatwootConfigRequest struct {
 	ImportMessages *bool    `json:"importMessages,omitempty" example:"true"`
 	ImportDays     *int     `json:"importDays,omitempty" example:"30"`
 	MergeBrazil    *bool    `json:"mergeBrazil,omitempty" example:"false"`
+	SyncReads      *bool    `json:"syncReads,omitempty" example:"true"`
+	SendReads      *bool    `json:"sendReads,omitempty" example:"true"`
 	Organization   *string  `json:"organization,omitempty" example:"Updated Bot"`
 	Logo           *string  `json:"logo,omitempty" example:"https://new-logo.com/logo.png"`
 	Number         *string  `json:"number,omitempty" example:"5511888888888"`
 	IgnoreJids     []string `json:"ignoreJids,omitempty" example:"[\"5511777777777@s.whatsapp.net\"]"`
+	WebhookSecret  *string  `json:"webhookSecret,omitempty" example:"new-webhook-token"`
 }
 
 type ChatwootConfigResponse struct {
-	ID        string    `json:"id" example:"chatwoot-config-123"`
-	URL       string    `json:"url" example:"https://chatwoot.example.com"`
-	AccountID string    `json:"accountId" example:"1"`
-	InboxID   *string   `json:"inboxId,omitempty" example:"1"`
-	Active    bool      `json:"active" example:"true"`
-	CreatedAt time.Time `json:"createdAt" example:"2024-01-01T00:00:00Z"`
-	UpdatedAt time.Time `json:"updatedAt" example:"2024-01-01T00:00:00Z"`
+	ID          string    `json:"id" example:"chatwoot-config-123"`
+	URL         string    `json:"url" example:"https://chatwoot.example.com"`
+	AccountID   string    `json:"accountId" example:"1"`
+	InboxID     *string   `json:"inboxId,omitempty" example:"1"`
+	Active      bool      `json:"active" example:"true"`
+	WebhookAuth bool      `json:"webhookAuth" example:"true"` // Inbound webhooks must present the webhook secret
+	CreatedAt   time.Time `json:"createdAt" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt   time.Time `json:"updatedAt" example:"2024-01-01T00:00:00Z"`
 } //@name ChatwootConfigResponse
 
 type SyncContactRequest struct {
@@ -187,6 +194,28 @@ type ChatwootWebhookPayload struct {
 	AdditionalAttributes map[string]interface{} `json:"additional_attributes,omitempty"`
 	CreatedAt            interface{}            `json:"created_at,omitempty"`
 	Data                 map[string]interface{} `json:"data,omitempty"`
+
+	// Typing events: who types, and whether in a private note
+	User      *Sender `json:"user,omitempty"`
+	IsPrivate bool    `json:"is_private,omitempty"`
+
+	// Conversation updates: the attributes that changed, keyed by attribute name
+	ChangedAttributes []map[string]ChangedAttribute `json:"changed_attributes,omitempty"`
+}
+
+// ChangedAttribute is the previous and current value of an attribute a conversation update changed
+type ChangedAttribute struct {
+	PreviousValue interface{} `json:"previous_value"`
+	CurrentValue  interface{} `json:"current_value"`
+}
+
+// WebhookAuth is what an inbound webhook request presents to authenticate itself: the token query
+// parameter, the X-Chatwoot-Signature and X-Chatwoot-Timestamp headers and the raw body they sign
+type WebhookAuth struct {
+	Token     string
+	Signature string
+	Timestamp string
+	Body      []byte
 }
 
 type ChatwootAccount struct {
@@ -196,6 +225,7 @@ type ChatwootAccount struct {
 
 type ChatwootConversation struct {
 	ID                   int                    `json:"id" example:"456"`
+	AccountID            int                    `json:"account_id,omitempty" example:"1"`
 	Status               string                 `json:"status" example:"open"`
 	ContactID            int                    `json:"contact_id" example:"123"`
 	InboxID              int                    `json:"inbox_id" example:"1"`
@@ -247,6 +277,49 @@ type ChatwootStatsResponse struct {
 	MessagesReceived    int `json:"messagesReceived" example:"890"`
 } // @name ChatwootStatsResponse
 
+type WebhookQueueStatsResponse struct {
+	Started        bool                     `json:"started" example:"true"`
+	Workers        int                      `json:"workers" example:"5"`
+	QueueSize      int                      `json:"queueSize" example:"0"`
+	QueueCapacity  int                      `json:"queueCapacity" example:"1000"`
+	Processed      int64                    `json:"processed" example:"1250"`
+	Failed         int64                    `json:"failed" example:"2"`
+	Retried        int64                    `json:"retried" example:"7"`
+	RecentFailures []WebhookFailureResponse `json:"recentFailures"`
+} // @name WebhookQueueStatsResponse
+
+type WebhookFailureResponse struct {
+	SessionID string    `json:"sessionId" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Event     string    `json:"event" example:"message_created"`
+	Error     string    `json:"error" example:"failed to send message to WhatsApp: session is not connected"`
+	Attempts  int       `json:"attempts" example:"3"`
+	FailedAt  time.Time `json:"failedAt" example:"2024-01-01T00:00:00Z"`
+} // @name WebhookFailureResponse
+
+func FromWebhookQueueStats(stats *ports.ChatwootWebhookQueueStats) *WebhookQueueStatsResponse {
+	failures := make([]WebhookFailureResponse, 0, len(stats.RecentFailures))
+	for _, failure := range stats.RecentFailures {
+		failures = append(failures, WebhookFailureResponse{
+			SessionID: failure.SessionID,
+			Event:     failure.Event,
+			Error:     failure.Error,
+			Attempts:  failure.Attempts,
+			FailedAt:  failure.FailedAt,
+		})
+	}
+
+	return &WebhookQueueStatsResponse{
+		Started:        stats.Started,
+		Workers:        stats.Workers,
+		QueueSize:      stats.QueueSize,
+		QueueCapacity:  stats.QueueCapacity,
+		Processed:      stats.Processed,
+		Failed:         stats.Failed,
+		Retried:        stats.Retried,
+		RecentFailures: failures,
+	}
+}
+
 // Evolution API specific DTOs
 type ChatwootConfigEvolutionRequest struct {
 	Enabled                 *bool    `json:"enabled,omitempty" example:"true"`
@@ -328,10 +401,13 @@ func (r *CreateChatwootConfigRequest) ToCreateChatwootConfigRequest(sessionID st
 		ImportMessages: r.ImportMessages,
 		ImportDays:     r.ImportDays,
 		MergeBrazil:    r.MergeBrazil,
+		SyncReads:      r.SyncReads,
+		SendReads:      r.SendReads,
 		Organization:   r.Organization,
 		Logo:           r.Logo,
 		Number:         r.Number,
 		IgnoreJids:     r.IgnoreJids,
+		WebhookSecret:  r.WebhookSecret,
 	}, nil
 }
 
@@ -352,21 +428,25 @@ func (r *UpdateChatwootConfigRequest) ToUpdateChatwootConfigRequest() *chatwoot.
 		ImportMessages: r.ImportMessages,
 		ImportDays:     r.ImportDays,
 		MergeBrazil:    r.MergeBrazil,
+		SyncReads:      r.SyncReads,
+		SendReads:      r.SendReads,
 		Organization:   r.Organization,
 		Logo:           r.Logo,
 		Number:         r.Number,
 		IgnoreJids:     r.IgnoreJids,
+		WebhookSecret:  r.WebhookSecret,
 	}
 }
 
 func FromChatwootConfig(c *ports.ChatwootConfig) *ChatwootConfigResponse {
 	return &ChatwootConfigResponse{
-		ID:        c.ID.String(),
-		URL:       c.URL,
-		AccountID: c.AccountID,
-		InboxID:   c.InboxID,
-		Active:    c.Enabled,
-		CreatedAt: c.CreatedAt,
-		UpdatedAt: c.UpdatedAt,
+		ID:          c.ID.String(),
+		URL:         c.URL,
+		AccountID:   c.AccountID,
+		InboxID:     c.InboxID,
+		Active:      c.Enabled,
+		WebhookAuth: c.WebhookSecret != nil,
+		CreatedAt:   c.CreatedAt,
+		UpdatedAt:   c.UpdatedAt,
 	}
 }
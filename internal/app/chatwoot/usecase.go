@@ -3,9 +3,11 @@ package chatwoot
 import (
 	"context"
 	"fmt"
+	"net/http"
 
 	"zpwoot/internal/domain/chatwoot"
 	"zpwoot/internal/ports"
+	"zpwoot/pkg/errors"
 	"zpwoot/platform/logger"
 )
 
@@ -17,7 +19,8 @@ type UseCase interface {
 	SyncContact(ctx context.Context, req *SyncContactRequest) (*SyncContactResponse, error)
 	SyncConversation(ctx context.Context, req *SyncConversationRequest) (*SyncConversationResponse, error)
 	SendMessageToChatwoot(ctx context.Context, req *SendMessageToChatwootRequest) (*SendMessageToChatwootResponse, error)
-	ProcessWebhook(ctx context.Context, sessionID string, payload *ChatwootWebhookPayload) error
+	ProcessWebhook(ctx context.Context, sessionID string, payload *ChatwootWebhookPayload, auth *WebhookAuth) error
+	GetWebhookQueueStats(ctx context.Context, sessionID string) (*WebhookQueueStatsResponse, error)
 	TestConnection(ctx context.Context) (*TestChatwootConnectionResponse, error)
 	GetStats(ctx context.Context) (*ChatwootStatsResponse, error)
 	AutoCreateInbox(ctx context.Context, sessionID, inboxName, webhookURL string) error
@@ -28,6 +31,7 @@ type useCaseImpl struct {
 	chatwootIntegration ports.ChatwootIntegration
 	chatwootManager     ports.ChatwootManager
 	chatwootService     *chatwoot.Service
+	webhookQueue        ports.ChatwootWebhookQueue
 	logger              *logger.Logger
 }
 
@@ -36,6 +40,7 @@ func NewUseCase(
 	chatwootIntegration ports.ChatwootIntegration,
 	chatwootManager ports.ChatwootManager,
 	chatwootService *chatwoot.Service,
+	webhookQueue ports.ChatwootWebhookQueue,
 	logger *logger.Logger,
 ) UseCase {
 	return &useCaseImpl{
@@ -43,6 +48,7 @@ func NewUseCase(
 		chatwootIntegration: chatwootIntegration,
 		chatwootManager:     chatwootManager,
 		chatwootService:     chatwootService,
+		webhookQueue:        webhookQueue,
 		logger:              logger,
 	}
 }
@@ -174,10 +180,86 @@ func (uc *useCaseImpl) SendMessageToChatwoot(ctx context.Context, req *SendMessa
 	return response, nil
 }
 
-func (uc *useCaseImpl) ProcessWebhook(ctx context.Context, sessionID string, payload *ChatwootWebhookPayload) error {
+// ProcessWebhook validates the webhook against the session's config, then queues it for background
+// processing when a queue is configured, otherwise it is processed synchronously
+func (uc *useCaseImpl) ProcessWebhook(ctx context.Context, sessionID string, payload *ChatwootWebhookPayload, auth *WebhookAuth) error {
 	// Convert app-layer payload to domain-layer payload
 	domainPayload := uc.convertToDomainPayload(payload)
 
+	if err := uc.validateWebhook(ctx, sessionID, domainPayload, auth); err != nil {
+		return err
+	}
+
+	if uc.webhookQueue == nil {
+		return uc.processDomainWebhook(ctx, sessionID, domainPayload)
+	}
+
+	err := uc.webhookQueue.Enqueue(sessionID, payload.Event, func(ctx context.Context) error {
+		return uc.processDomainWebhook(ctx, sessionID, domainPayload)
+	})
+	if err != nil {
+		return errors.NewWithDetails(http.StatusServiceUnavailable, "Webhook queue unavailable", err.Error())
+	}
+
+	return nil
+}
+
+// validateWebhook rejects webhooks that do not belong to the session's Chatwoot account and inbox or
+// lack the configured webhook secret
+func (uc *useCaseImpl) validateWebhook(ctx context.Context, sessionID string, domainPayload *chatwoot.ChatwootWebhookPayload, auth *WebhookAuth) error {
+	var domainAuth *chatwoot.WebhookAuth
+	if auth != nil {
+		domainAuth = &chatwoot.WebhookAuth{
+			Token:     auth.Token,
+			Signature: auth.Signature,
+			Timestamp: auth.Timestamp,
+			Body:      auth.Body,
+		}
+	}
+
+	err := uc.chatwootService.ValidateWebhook(ctx, sessionID, domainPayload, domainAuth)
+	if err == nil {
+		return nil
+	}
+
+	uc.logger.WarnWithFields("Rejected Chatwoot webhook", map[string]interface{}{
+		"session_id": sessionID,
+		"event":      domainPayload.Event,
+		"account_id": domainPayload.Account.ID,
+		"error":      err.Error(),
+	})
+
+	switch err {
+	case chatwoot.ErrConfigNotFound:
+		return errors.New(http.StatusNotFound, "Chatwoot integration not configured for this session")
+	case chatwoot.ErrIntegrationDisabled:
+		return errors.New(http.StatusForbidden, "Chatwoot integration is disabled for this session")
+	case chatwoot.ErrWebhookUnauthorized:
+		return errors.New(http.StatusUnauthorized, "Invalid or missing webhook token or signature")
+	case chatwoot.ErrInvalidAccountID:
+		return errors.NewWithDetails(http.StatusForbidden, "Webhook account does not match the Chatwoot configuration", fmt.Sprintf("account %d", domainPayload.Account.ID))
+	case chatwoot.ErrUnknownInbox:
+		return errors.New(http.StatusForbidden, "Webhook inbox does not match the Chatwoot configuration")
+	}
+	return fmt.Errorf("failed to validate webhook: %w", err)
+}
+
+// GetWebhookQueueStats returns statistics about asynchronous webhook processing for a session
+func (uc *useCaseImpl) GetWebhookQueueStats(ctx context.Context, sessionID string) (*WebhookQueueStatsResponse, error) {
+	if uc.webhookQueue == nil {
+		return nil, errors.New(http.StatusNotFound, "Webhook queue not configured")
+	}
+
+	return FromWebhookQueueStats(uc.webhookQueue.GetStats(sessionID)), nil
+}
+
+// processDomainWebhook resolves missing sender data and hands the payload to the domain service
+func (uc *useCaseImpl) processDomainWebhook(ctx context.Context, sessionID string, domainPayload *chatwoot.ChatwootWebhookPayload) error {
+	// Typing events are matched to their chat through the message mappings and need no phone number
+	if chatwoot.IsTypingEvent(domainPayload.Event) {
+		return uc.chatwootService.ProcessWebhook(ctx, sessionID, domainPayload)
+	}
+
 	// Resolve sender phone number if missing
 	if err := uc.resolveSenderPhoneNumber(ctx, domainPayload); err != nil {
 		uc.logger.WarnWithFields("Failed to resolve sender phone number", map[string]interface{}{
@@ -201,6 +283,26 @@ func (uc *useCaseImpl) convertToDomainPayload(payload *ChatwootWebhookPayload) *
 			InboxID:   payload.Conversation.InboxID,
 			Status:    payload.Conversation.Status,
 		},
+		Inbox:     payload.Inbox,
+		IsPrivate: payload.IsPrivate,
+	}
+
+	for _, changed := range payload.ChangedAttributes {
+		for attribute := range changed {
+			domainPayload.ChangedAttributes = append(domainPayload.ChangedAttributes, attribute)
+		}
+	}
+
+	// Typing events carry the account only in their conversation
+	if domainPayload.Account.ID == 0 {
+		domainPayload.Account.ID = payload.Conversation.AccountID
+	}
+	if payload.User != nil {
+		domainPayload.User = &chatwoot.ChatwootSender{
+			ID:   payload.User.ID,
+			Name: payload.User.Name,
+			Type: payload.User.Type,
+		}
 	}
 
 	// Map message data from nested or top-level fields
@@ -224,6 +326,8 @@ func (uc *useCaseImpl) mapMessageData(payload *ChatwootWebhookPayload, domainPay
 			ContentType: m.ContentType,
 			Private:     m.Private,
 			SourceID:    m.SourceID,
+
+			ContentAttributes: m.ContentAttributes,
 		}
 		// Set shortcuts
 		domainPayload.ID = m.ID
@@ -231,6 +335,7 @@ func (uc *useCaseImpl) mapMessageData(payload *ChatwootWebhookPayload, domainPay
 		domainPayload.MessageType = m.MessageType
 		domainPayload.ContentType = m.ContentType
 		domainPayload.Private = m.Private
+		domainPayload.ContentAttributes = m.ContentAttributes
 		return
 	}
 
@@ -241,6 +346,7 @@ func (uc *useCaseImpl) mapMessageData(payload *ChatwootWebhookPayload, domainPay
 		domainPayload.MessageType = payload.MessageType
 		domainPayload.ContentType = payload.ContentType
 		domainPayload.Private = payload.Private
+		domainPayload.ContentAttributes = payload.ContentAttributes
 		if payload.SourceID != nil {
 			domainPayload.SourceID = payload.SourceID
 		}
@@ -83,6 +83,15 @@ func NewErrorResponse(error string, details ...interface{}) *ErrorResponse {
 	return response
 }
 
+// NewErrorResponseWithCode creates an error response carrying a machine-readable error code
+func NewErrorResponseWithCode(error, code string) *ErrorResponse {
+	return &ErrorResponse{
+		Success: false,
+		Error:   error,
+		Code:    code,
+	}
+}
+
 func NewValidationErrorResponse(errors []ValidationError) *ValidationErrorResponse {
 	return &ValidationErrorResponse{
 		Success: false,
@@ -15,6 +15,10 @@ type WhatsAppStatus struct {
 	JID          string `json:"jid,omitempty" example:"5511999999999@s.whatsapp.net"`
 	IsBusiness   bool   `json:"isBusiness" example:"false"`
 	VerifiedName string `json:"verifiedName,omitempty" example:"Company Name"`
+	// E164 is the number in E.164 form, set when it is a possible number
+	E164 string `json:"e164,omitempty" example:"+5511999999999"`
+	// InvalidReason tells why the number is impossible for its country; such numbers are not checked with WhatsApp
+	InvalidReason string `json:"invalidReason,omitempty" example:"impossible phone number: BR numbers have 10 to 11 digits after +55, got 12"`
 }
 
 // CheckWhatsAppResponse represents the response for checking WhatsApp numbers
@@ -163,3 +167,57 @@ type GetContactStatsResponse struct {
 	Stats     ContactStats `json:"stats"`
 	UpdatedAt time.Time    `json:"updatedAt" example:"2024-01-01T12:00:00Z"`
 }
+
+// ListContactChangesRequest represents a request to poll the contact change feed
+type ListContactChangesRequest struct {
+	SessionID string    `json:"sessionId,omitempty"`
+	Since     time.Time `json:"since" example:"2024-01-01T12:00:00Z"`
+	Limit     int       `json:"limit" validate:"min=1,max=500" example:"100"`
+}
+
+// ContactChange represents an entry of the contact change feed
+type ContactChange struct {
+	ID             int64     `json:"id" example:"42"`
+	ContactJID     string    `json:"contactJid" example:"5511999999999@s.whatsapp.net"`
+	Type           string    `json:"type" example:"push_name"`
+	PushName       string    `json:"pushName,omitempty" example:"John"`
+	BusinessName   string    `json:"businessName,omitempty" example:"Company Name"`
+	FullName       string    `json:"fullName,omitempty" example:"John Doe"`
+	FirstName      string    `json:"firstName,omitempty" example:"John"`
+	PictureID      string    `json:"pictureId,omitempty" example:"1234567890"`
+	PictureRemoved bool      `json:"pictureRemoved,omitempty" example:"false"`
+	ChangedAt      time.Time `json:"changedAt" example:"2024-01-01T12:00:00Z"`
+}
+
+// ListContactChangesResponse represents a page of the contact change feed. NextSince is the value
+// to pass as since to fetch the following changes
+type ListContactChangesResponse struct {
+	Changes   []ContactChange `json:"changes"`
+	Count     int             `json:"count" example:"1"`
+	HasMore   bool            `json:"hasMore" example:"false"`
+	NextSince time.Time       `json:"nextSince" example:"2024-01-01T12:00:00Z"`
+}
+
+// SubscribePresenceRequest represents a request to follow the presence of contacts
+type SubscribePresenceRequest struct {
+	SessionID string   `json:"sessionId,omitempty"`
+	JIDs      []string `json:"jids" validate:"required,min=1,max=100" example:"[\"5511999999999@s.whatsapp.net\"]"`
+}
+
+// SubscribePresenceResponse lists the contacts subscribed and why the others failed. Presence updates
+// arrive as Presence webhook events
+type SubscribePresenceResponse struct {
+	Subscribed []string          `json:"subscribed" example:"[\"5511999999999@s.whatsapp.net\"]"`
+	Failed     map[string]string `json:"failed,omitempty"`
+}
+
+// ListPresenceSubscriptionsRequest represents a request to list the contacts whose presence is followed
+type ListPresenceSubscriptionsRequest struct {
+	SessionID string `json:"sessionId,omitempty"`
+}
+
+// ListPresenceSubscriptionsResponse lists the contacts whose presence is followed
+type ListPresenceSubscriptionsResponse struct {
+	JIDs  []string `json:"jids" example:"[\"5511999999999@s.whatsapp.net\"]"`
+	Total int      `json:"total" example:"1"`
+}
@@ -17,17 +17,22 @@ type UseCase interface {
 	SyncContacts(ctx context.Context, req *SyncContactsRequest) (*SyncContactsResponse, error)
 	GetBusinessProfile(ctx context.Context, req *GetBusinessProfileRequest) (*BusinessProfileResponse, error)
 	GetContactStats(ctx context.Context, req *GetContactStatsRequest) (*GetContactStatsResponse, error)
+	ListContactChanges(ctx context.Context, req *ListContactChangesRequest) (*ListContactChangesResponse, error)
+	SubscribePresence(ctx context.Context, req *SubscribePresenceRequest) (*SubscribePresenceResponse, error)
+	ListPresenceSubscriptions(ctx context.Context, req *ListPresenceSubscriptionsRequest) (*ListPresenceSubscriptionsResponse, error)
 }
 
 type useCaseImpl struct {
 	contactService contact.Service
+	changeFeed     *contact.ChangeFeed
 	logger         *logger.Logger
 }
 
 // NewUseCase creates a new contact use case
-func NewUseCase(contactService contact.Service, logger *logger.Logger) UseCase {
+func NewUseCase(contactService contact.Service, changeFeed *contact.ChangeFeed, logger *logger.Logger) UseCase {
 	return &useCaseImpl{
 		contactService: contactService,
+		changeFeed:     changeFeed,
 		logger:         logger,
 	}
 }
@@ -57,11 +62,13 @@ func (uc *useCaseImpl) CheckWhatsApp(ctx context.Context, req *CheckWhatsAppRequ
 	dtoResults := make([]WhatsAppStatus, len(result.Results))
 	for i, domainResult := range result.Results {
 		dtoResults[i] = WhatsAppStatus{
-			PhoneNumber:  domainResult.PhoneNumber,
-			IsOnWhatsApp: domainResult.IsOnWhatsApp,
-			JID:          domainResult.JID,
-			IsBusiness:   domainResult.IsBusiness,
-			VerifiedName: domainResult.VerifiedName,
+			PhoneNumber:   domainResult.PhoneNumber,
+			IsOnWhatsApp:  domainResult.IsOnWhatsApp,
+			JID:           domainResult.JID,
+			IsBusiness:    domainResult.IsBusiness,
+			VerifiedName:  domainResult.VerifiedName,
+			E164:          domainResult.E164,
+			InvalidReason: domainResult.InvalidReason,
 		}
 	}
 
@@ -254,3 +261,70 @@ func (uc *useCaseImpl) GetContactStats(ctx context.Context, req *GetContactStats
 		UpdatedAt: result.UpdatedAt,
 	}, nil
 }
+
+// ListContactChanges returns the contact changes recorded after req.Since, oldest first
+func (uc *useCaseImpl) ListContactChanges(ctx context.Context, req *ListContactChangesRequest) (*ListContactChangesResponse, error) {
+	changes, hasMore, err := uc.changeFeed.ListChanges(ctx, &contact.ListChangesRequest{
+		SessionID: req.SessionID,
+		Since:     req.Since,
+		Limit:     req.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ListContactChangesResponse{
+		Changes:   make([]ContactChange, len(changes)),
+		Count:     len(changes),
+		HasMore:   hasMore,
+		NextSince: req.Since,
+	}
+	for i, change := range changes {
+		response.Changes[i] = ContactChange{
+			ID:             change.ID,
+			ContactJID:     change.ContactJID,
+			Type:           string(change.Type),
+			PushName:       change.PushName,
+			BusinessName:   change.BusinessName,
+			FullName:       change.FullName,
+			FirstName:      change.FirstName,
+			PictureID:      change.PictureID,
+			PictureRemoved: change.PictureRemoved,
+			ChangedAt:      change.ChangedAt,
+		}
+		response.NextSince = change.ChangedAt
+	}
+
+	return response, nil
+}
+
+// SubscribePresence follows the presence of contacts
+func (uc *useCaseImpl) SubscribePresence(ctx context.Context, req *SubscribePresenceRequest) (*SubscribePresenceResponse, error) {
+	result, err := uc.contactService.SubscribePresence(ctx, &contact.SubscribePresenceRequest{
+		SessionID: req.SessionID,
+		JIDs:      req.JIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &SubscribePresenceResponse{
+		Subscribed: result.Subscribed,
+		Failed:     result.Failed,
+	}, nil
+}
+
+// ListPresenceSubscriptions lists the contacts whose presence the session follows
+func (uc *useCaseImpl) ListPresenceSubscriptions(ctx context.Context, req *ListPresenceSubscriptionsRequest) (*ListPresenceSubscriptionsResponse, error) {
+	result, err := uc.contactService.ListPresenceSubscriptions(ctx, &contact.ListPresenceSubscriptionsRequest{
+		SessionID: req.SessionID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ListPresenceSubscriptionsResponse{
+		JIDs:  result.JIDs,
+		Total: result.Total,
+	}, nil
+}
@@ -4,39 +4,97 @@ import (
 	"database/sql"
 	"fmt"
 
+	"zpwoot/internal/app/apikey"
+	"zpwoot/internal/app/chat"
 	"zpwoot/internal/app/chatwoot"
 	"zpwoot/internal/app/common"
 	"zpwoot/internal/app/community"
 	"zpwoot/internal/app/contact"
+	"zpwoot/internal/app/conversation"
+	"zpwoot/internal/app/dashboard"
+	"zpwoot/internal/app/draft"
 	"zpwoot/internal/app/group"
+	"zpwoot/internal/app/history"
+	"zpwoot/internal/app/job"
+	"zpwoot/internal/app/maintenance"
 	"zpwoot/internal/app/media"
 	"zpwoot/internal/app/message"
 	"zpwoot/internal/app/newsletter"
+	"zpwoot/internal/app/outbox"
+	"zpwoot/internal/app/pairing"
+	"zpwoot/internal/app/privacy"
+	"zpwoot/internal/app/routing"
+	"zpwoot/internal/app/scheduled"
+	"zpwoot/internal/app/sendguard"
 	"zpwoot/internal/app/session"
+	"zpwoot/internal/app/settings"
+	"zpwoot/internal/app/translation"
+	"zpwoot/internal/app/usage"
+	"zpwoot/internal/app/warmup"
 	"zpwoot/internal/app/webhook"
+	domainActivity "zpwoot/internal/domain/activity"
+	domainAPIKey "zpwoot/internal/domain/apikey"
+	domainChat "zpwoot/internal/domain/chat"
 	domainChatwoot "zpwoot/internal/domain/chatwoot"
 	domainCommunity "zpwoot/internal/domain/community"
 	domainContact "zpwoot/internal/domain/contact"
+	domainConversation "zpwoot/internal/domain/conversation"
+	domainDraft "zpwoot/internal/domain/draft"
 	domainGroup "zpwoot/internal/domain/group"
+	domainHistory "zpwoot/internal/domain/history"
+	domainJob "zpwoot/internal/domain/job"
+	domainMaintenance "zpwoot/internal/domain/maintenance"
 	domainMedia "zpwoot/internal/domain/media"
+	domainMessage "zpwoot/internal/domain/message"
 	domainNewsletter "zpwoot/internal/domain/newsletter"
+	domainOutbox "zpwoot/internal/domain/outbox"
+	domainPairing "zpwoot/internal/domain/pairing"
+	domainPoll "zpwoot/internal/domain/poll"
+	domainPrivacy "zpwoot/internal/domain/privacy"
+	domainQueue "zpwoot/internal/domain/queue"
+	domainRouting "zpwoot/internal/domain/routing"
+	domainScheduled "zpwoot/internal/domain/scheduled"
+	domainSendGuard "zpwoot/internal/domain/sendguard"
 	domainSession "zpwoot/internal/domain/session"
+	domainSettings "zpwoot/internal/domain/settings"
+	domainStats "zpwoot/internal/domain/stats"
+	domainTranslation "zpwoot/internal/domain/translation"
+	domainUsage "zpwoot/internal/domain/usage"
+	domainWarmup "zpwoot/internal/domain/warmup"
 	domainWebhook "zpwoot/internal/domain/webhook"
 	"zpwoot/internal/ports"
 	"zpwoot/platform/logger"
 )
 
 type Container struct {
-	CommonUseCase     common.UseCase
-	SessionUseCase    session.UseCase
-	WebhookUseCase    webhook.UseCase
-	ChatwootUseCase   chatwoot.UseCase
-	MessageUseCase    message.UseCase
-	MediaUseCase      media.UseCase
-	GroupUseCase      group.UseCase
-	ContactUseCase    contact.UseCase
-	NewsletterUseCase newsletter.UseCase
-	CommunityUseCase  community.UseCase
+	CommonUseCase       common.UseCase
+	SessionUseCase      session.UseCase
+	WebhookUseCase      webhook.UseCase
+	ChatwootUseCase     chatwoot.UseCase
+	MessageUseCase      message.UseCase
+	MediaUseCase        media.UseCase
+	GroupUseCase        group.UseCase
+	ContactUseCase      contact.UseCase
+	ChatUseCase         chat.UseCase
+	NewsletterUseCase   newsletter.UseCase
+	CommunityUseCase    community.UseCase
+	DraftUseCase        draft.UseCase
+	UsageUseCase        usage.UseCase
+	MaintenanceUseCase  maintenance.UseCase
+	SettingsUseCase     settings.UseCase
+	JobUseCase          job.UseCase
+	DashboardUseCase    dashboard.UseCase
+	PairingUseCase      pairing.UseCase
+	RoutingUseCase      routing.UseCase
+	ConversationUseCase conversation.UseCase
+	TranslationUseCase  translation.UseCase
+	PrivacyUseCase      privacy.UseCase
+	OutboxUseCase       outbox.UseCase
+	ScheduledUseCase    scheduled.UseCase
+	APIKeyUseCase       apikey.UseCase
+	WarmupUseCase       warmup.UseCase
+	SendGuardUseCase    sendguard.UseCase
+	HistoryUseCase      history.UseCase
 
 	logger      *logger.Logger
 	sessionRepo ports.SessionRepository
@@ -44,34 +102,64 @@ type Container struct {
 
 type ContainerConfig struct {
 	// Repositories
-	SessionRepo         ports.SessionRepository
-	WebhookRepo         ports.WebhookRepository
-	ChatwootRepo        ports.ChatwootRepository
-	ChatwootMessageRepo ports.ChatwootMessageRepository
-	MediaRepo           ports.MediaRepository
+	SessionRepo  ports.SessionRepository
+	WebhookRepo  ports.WebhookRepository
+	ChatwootRepo ports.ChatwootRepository
+	MediaRepo    ports.MediaRepository
+	DraftRepo    ports.DraftRepository
 
 	// Managers and Integrations
 	WameowManager         ports.WameowManager
 	ChatwootIntegration   ports.ChatwootIntegration
 	ChatwootManager       ports.ChatwootManager
 	ChatwootMessageMapper ports.ChatwootMessageMapper
+	ChatwootWebhookQueue  ports.ChatwootWebhookQueue
+	WebhookDispatch       ports.WebhookDispatchControl
 	JIDValidator          ports.JIDValidator
 	NewsletterManager     ports.NewsletterManager
 	CommunityManager      ports.CommunityManager
 
 	// Domain Services (pre-created)
-	SessionService    *domainSession.Service
-	WebhookService    *domainWebhook.Service
-	ChatwootService   *domainChatwoot.Service
-	GroupService      *domainGroup.Service
-	ContactService    domainContact.Service
-	MediaService      domainMedia.Service
-	NewsletterService *domainNewsletter.Service
-	CommunityService  domainCommunity.Service
+	SessionService      *domainSession.Service
+	WebhookService      *domainWebhook.Service
+	ChatwootService     *domainChatwoot.Service
+	GroupService        *domainGroup.Service
+	ContactService      domainContact.Service
+	ContactChangeFeed   *domainContact.ChangeFeed
+	ChatService         *domainChat.Service
+	MediaService        domainMedia.Service
+	NewsletterService   *domainNewsletter.Service
+	CommunityService    domainCommunity.Service
+	DraftService        *domainDraft.Service
+	UsageService        *domainUsage.Service
+	MaintenanceService  *domainMaintenance.Service
+	SettingsService     *domainSettings.Service
+	JobService          *domainJob.Service
+	ActivityService     *domainActivity.Service
+	PairingService      *domainPairing.Service
+	RoutingService      *domainRouting.Service
+	ConversationService *domainConversation.Service
+	TranslationService  *domainTranslation.Service
+	PrivacyService      *domainPrivacy.Service
+	WarmupService       *domainWarmup.Service
+	QueueService        *domainQueue.Service
+	OutboxService       *domainOutbox.Service
+	ScheduledService    *domainScheduled.Service
+	APIKeyService       *domainAPIKey.Service
+	SendGuardService    *domainSendGuard.Service
+	EventStore          *domainWebhook.EventStore
+	EventStream         *domainWebhook.EventStream
+	HistoryService      *domainHistory.Service
+	PollService         *domainPoll.Service
+	StatsService        *domainStats.Service
 
 	// Infrastructure
 	Logger *logger.Logger
 	DB     *sql.DB
+	// ServerURL is the public base URL used in links handed out by the API
+	ServerURL string
+	// MediaLimits bound the media sent from URLs and base64 data URIs
+	MediaLimits domainMessage.MediaLimits
 
 	// Build Info
 	Version   string
@@ -82,62 +170,144 @@ type ContainerConfig struct {
 func NewContainer(config *ContainerConfig) *Container {
 	// Domain services are now injected, so we create the services struct directly
 	services := &domainServices{
-		session:    config.SessionService,
-		webhook:    config.WebhookService,
-		chatwoot:   config.ChatwootService,
-		group:      config.GroupService,
-		contact:    config.ContactService,
-		media:      config.MediaService,
-		newsletter: config.NewsletterService,
-		community:  config.CommunityService,
+		session:      config.SessionService,
+		webhook:      config.WebhookService,
+		chatwoot:     config.ChatwootService,
+		group:        config.GroupService,
+		contact:      config.ContactService,
+		contactFeed:  config.ContactChangeFeed,
+		chat:         config.ChatService,
+		media:        config.MediaService,
+		newsletter:   config.NewsletterService,
+		community:    config.CommunityService,
+		draft:        config.DraftService,
+		usage:        config.UsageService,
+		maintenance:  config.MaintenanceService,
+		settings:     config.SettingsService,
+		job:          config.JobService,
+		activity:     config.ActivityService,
+		pairing:      config.PairingService,
+		routing:      config.RoutingService,
+		conversation: config.ConversationService,
+		translation:  config.TranslationService,
+		privacy:      config.PrivacyService,
+		warmup:       config.WarmupService,
+		queue:        config.QueueService,
+		outbox:       config.OutboxService,
+		scheduled:    config.ScheduledService,
+		apiKey:       config.APIKeyService,
+		sendGuard:    config.SendGuardService,
+		eventStore:   config.EventStore,
+		eventStream:  config.EventStream,
+		history:      config.HistoryService,
+		poll:         config.PollService,
+		stats:        config.StatsService,
 	}
 
 	useCases := createUseCases(config, services)
 
 	return &Container{
-		CommonUseCase:     useCases.common,
-		SessionUseCase:    useCases.session,
-		WebhookUseCase:    useCases.webhook,
-		ChatwootUseCase:   useCases.chatwoot,
-		MessageUseCase:    useCases.message,
-		MediaUseCase:      useCases.media,
-		GroupUseCase:      useCases.group,
-		ContactUseCase:    useCases.contact,
-		NewsletterUseCase: useCases.newsletter,
-		CommunityUseCase:  useCases.community,
-		logger:            config.Logger,
-		sessionRepo:       config.SessionRepo,
+		CommonUseCase:       useCases.common,
+		SessionUseCase:      useCases.session,
+		WebhookUseCase:      useCases.webhook,
+		ChatwootUseCase:     useCases.chatwoot,
+		MessageUseCase:      useCases.message,
+		MediaUseCase:        useCases.media,
+		GroupUseCase:        useCases.group,
+		ContactUseCase:      useCases.contact,
+		ChatUseCase:         useCases.chat,
+		NewsletterUseCase:   useCases.newsletter,
+		CommunityUseCase:    useCases.community,
+		DraftUseCase:        useCases.draft,
+		UsageUseCase:        useCases.usage,
+		MaintenanceUseCase:  useCases.maintenance,
+		SettingsUseCase:     useCases.settings,
+		JobUseCase:          useCases.job,
+		DashboardUseCase:    useCases.dashboard,
+		PairingUseCase:      useCases.pairing,
+		RoutingUseCase:      useCases.routing,
+		ConversationUseCase: useCases.conversation,
+		TranslationUseCase:  useCases.translation,
+		PrivacyUseCase:      useCases.privacy,
+		OutboxUseCase:       useCases.outbox,
+		ScheduledUseCase:    useCases.scheduled,
+		APIKeyUseCase:       useCases.apiKey,
+		WarmupUseCase:       useCases.warmup,
+		SendGuardUseCase:    useCases.sendGuard,
+		HistoryUseCase:      useCases.history,
+		logger:              config.Logger,
+		sessionRepo:         config.SessionRepo,
 	}
 }
 
 // domainServices holds all domain services
 type domainServices struct {
-	session    *domainSession.Service
-	webhook    *domainWebhook.Service
-	chatwoot   *domainChatwoot.Service
-	group      *domainGroup.Service
-	contact    domainContact.Service
-	media      domainMedia.Service
-	newsletter *domainNewsletter.Service
-	community  domainCommunity.Service
+	session      *domainSession.Service
+	webhook      *domainWebhook.Service
+	chatwoot     *domainChatwoot.Service
+	group        *domainGroup.Service
+	contact      domainContact.Service
+	contactFeed  *domainContact.ChangeFeed
+	chat         *domainChat.Service
+	media        domainMedia.Service
+	newsletter   *domainNewsletter.Service
+	community    domainCommunity.Service
+	draft        *domainDraft.Service
+	usage        *domainUsage.Service
+	maintenance  *domainMaintenance.Service
+	settings     *domainSettings.Service
+	job          *domainJob.Service
+	activity     *domainActivity.Service
+	pairing      *domainPairing.Service
+	routing      *domainRouting.Service
+	conversation *domainConversation.Service
+	translation  *domainTranslation.Service
+	privacy      *domainPrivacy.Service
+	warmup       *domainWarmup.Service
+	queue        *domainQueue.Service
+	outbox       *domainOutbox.Service
+	scheduled    *domainScheduled.Service
+	apiKey       *domainAPIKey.Service
+	sendGuard    *domainSendGuard.Service
+	eventStore   *domainWebhook.EventStore
+	eventStream  *domainWebhook.EventStream
+	history      *domainHistory.Service
+	poll         *domainPoll.Service
+	stats        *domainStats.Service
 }
 
 // useCases holds all use cases
 type useCases struct {
-	common     common.UseCase
-	session    session.UseCase
-	webhook    webhook.UseCase
-	chatwoot   chatwoot.UseCase
-	message    message.UseCase
-	media      media.UseCase
-	group      group.UseCase
-	contact    contact.UseCase
-	newsletter newsletter.UseCase
-	community  community.UseCase
+	common       common.UseCase
+	session      session.UseCase
+	webhook      webhook.UseCase
+	chatwoot     chatwoot.UseCase
+	message      message.UseCase
+	media        media.UseCase
+	group        group.UseCase
+	contact      contact.UseCase
+	chat         chat.UseCase
+	newsletter   newsletter.UseCase
+	community    community.UseCase
+	draft        draft.UseCase
+	usage        usage.UseCase
+	maintenance  maintenance.UseCase
+	settings     settings.UseCase
+	job          job.UseCase
+	dashboard    dashboard.UseCase
+	pairing      pairing.UseCase
+	routing      routing.UseCase
+	conversation conversation.UseCase
+	translation  translation.UseCase
+	privacy      privacy.UseCase
+	outbox       outbox.UseCase
+	scheduled    scheduled.UseCase
+	apiKey       apikey.UseCase
+	warmup       warmup.UseCase
+	sendGuard    sendguard.UseCase
+	history      history.UseCase
 }
 
-
-
 // createUseCases creates all use cases
 func createUseCases(config *ContainerConfig, services *domainServices) *useCases {
 	// Create core use cases
@@ -147,35 +317,71 @@ func createUseCases(config *ContainerConfig, services *domainServices) *useCases
 	businessUseCases := createBusinessUseCases(config, services)
 
 	return &useCases{
-		common:     coreUseCases.common,
-		session:    coreUseCases.session,
-		webhook:    coreUseCases.webhook,
-		chatwoot:   coreUseCases.chatwoot,
-		message:    businessUseCases.message,
-		media:      businessUseCases.media,
-		group:      businessUseCases.group,
-		contact:    businessUseCases.contact,
-		newsletter: businessUseCases.newsletter,
-		community:  businessUseCases.community,
+		common:       coreUseCases.common,
+		session:      coreUseCases.session,
+		webhook:      coreUseCases.webhook,
+		chatwoot:     coreUseCases.chatwoot,
+		message:      businessUseCases.message,
+		media:        businessUseCases.media,
+		group:        businessUseCases.group,
+		contact:      businessUseCases.contact,
+		chat:         businessUseCases.chat,
+		newsletter:   businessUseCases.newsletter,
+		community:    businessUseCases.community,
+		draft:        businessUseCases.draft,
+		usage:        businessUseCases.usage,
+		maintenance:  coreUseCases.maintenance,
+		settings:     coreUseCases.settings,
+		job:          coreUseCases.job,
+		dashboard:    coreUseCases.dashboard,
+		pairing:      coreUseCases.pairing,
+		routing:      businessUseCases.routing,
+		conversation: businessUseCases.conversation,
+		translation:  businessUseCases.translation,
+		privacy:      businessUseCases.privacy,
+		outbox:       businessUseCases.outbox,
+		scheduled:    businessUseCases.scheduled,
+		apiKey:       businessUseCases.apiKey,
+		warmup:       businessUseCases.warmup,
+		sendGuard:    businessUseCases.sendGuard,
+		history:      businessUseCases.history,
 	}
 }
 
 // coreUseCases holds core system use cases
 type coreUseCases struct {
-	common   common.UseCase
-	session  session.UseCase
-	webhook  webhook.UseCase
-	chatwoot chatwoot.UseCase
+	common      common.UseCase
+	session     session.UseCase
+	webhook     webhook.UseCase
+	chatwoot    chatwoot.UseCase
+	maintenance maintenance.UseCase
+	settings    settings.UseCase
+	job         job.UseCase
+	dashboard   dashboard.UseCase
+	pairing     pairing.UseCase
 }
 
 // businessUseCases holds business logic use cases
 type businessUseCases struct {
-	message    message.UseCase
-	media      media.UseCase
-	group      group.UseCase
-	contact    contact.UseCase
-	newsletter newsletter.UseCase
-	community  community.UseCase
+	message      message.UseCase
+	media        media.UseCase
+	group        group.UseCase
+	contact      contact.UseCase
+	chat         chat.UseCase
+	newsletter   newsletter.UseCase
+	community    community.UseCase
+	draft        draft.UseCase
+	usage        usage.UseCase
+	routing      routing.UseCase
+	conversation conversation.UseCase
+	translation  translation.UseCase
+	privacy      privacy.UseCase
+	outbox       outbox.UseCase
+	scheduled    scheduled.UseCase
+	apiKey       apikey.UseCase
+	warmup       warmup.UseCase
+	sendGuard    sendguard.UseCase
+	history      history.UseCase
 }
 
 // createCoreUseCases creates core system use cases
@@ -193,30 +399,70 @@ func createCoreUseCases(config *ContainerConfig, services *domainServices) *core
 			config.SessionRepo,
 			config.WameowManager,
 			services.session,
+			services.usage,
+			services.warmup,
+			services.webhook,
+			services.stats,
+			services.activity,
 			config.Logger,
 		),
 		webhook: webhook.NewUseCase(
 			config.WebhookRepo,
 			services.webhook,
+			services.eventStore,
+			services.eventStream,
+			config.WebhookDispatch,
 		),
 		chatwoot: chatwoot.NewUseCase(
 			config.ChatwootRepo,
 			config.ChatwootIntegration,
 			config.ChatwootManager,
 			services.chatwoot,
+			config.ChatwootWebhookQueue,
 			config.Logger,
 		),
+		maintenance: maintenance.NewUseCase(
+			config.SessionRepo,
+			services.maintenance,
+		),
+		settings: settings.NewUseCase(
+			services.settings,
+		),
+		job: job.NewUseCase(
+			services.job,
+		),
+		dashboard: dashboard.NewUseCase(
+			config.SessionRepo,
+			services.activity,
+		),
+		pairing: pairing.NewUseCase(
+			config.SessionRepo,
+			services.session,
+			services.pairing,
+			config.ServerURL,
+		),
 	}
 }
 
 // createBusinessUseCases creates business logic use cases
 func createBusinessUseCases(config *ContainerConfig, services *domainServices) *businessUseCases {
+	messageUseCase := message.NewUseCase(
+		config.SessionRepo,
+		config.WameowManager,
+		services.usage,
+		services.translation,
+		services.queue,
+		services.outbox,
+		services.scheduled,
+		services.draft,
+		services.poll,
+		services.history,
+		config.MediaLimits,
+		config.Logger,
+	)
+
 	return &businessUseCases{
-		message: message.NewUseCase(
-			config.SessionRepo,
-			config.WameowManager,
-			config.Logger,
-		),
+		message: messageUseCase,
 		media: media.NewUseCase(
 			services.media,
 			config.MediaRepo,
@@ -229,8 +475,12 @@ func createBusinessUseCases(config *ContainerConfig, services *domainServices) *
 		),
 		contact: contact.NewUseCase(
 			services.contact,
+			services.contactFeed,
 			config.Logger,
 		),
+		chat: chat.NewUseCase(
+			services.chat,
+		),
 		newsletter: newsletter.NewUseCase(
 			config.NewsletterManager,
 			services.newsletter,
@@ -243,6 +493,45 @@ func createBusinessUseCases(config *ContainerConfig, services *domainServices) *
 			config.SessionRepo,
 			*config.Logger,
 		),
+		draft: draft.NewUseCase(
+			services.draft,
+			messageUseCase,
+		),
+		usage: usage.NewUseCase(
+			services.usage,
+		),
+		routing: routing.NewUseCase(
+			services.routing,
+		),
+		conversation: conversation.NewUseCase(
+			services.conversation,
+		),
+		translation: translation.NewUseCase(
+			services.translation,
+		),
+		privacy: privacy.NewUseCase(
+			config.SessionRepo,
+			services.privacy,
+		),
+		outbox: outbox.NewUseCase(
+			services.outbox,
+			messageUseCase,
+		),
+		scheduled: scheduled.NewUseCase(
+			services.scheduled,
+		),
+		apiKey: apikey.NewUseCase(
+			services.apiKey,
+		),
+		warmup: warmup.NewUseCase(
+			services.warmup,
+		),
+		sendGuard: sendguard.NewUseCase(
+			services.sendGuard,
+		),
+		history: history.NewUseCase(
+			services.history,
+		),
 	}
 }
 
@@ -286,6 +575,10 @@ func (c *Container) GetContactUseCase() contact.UseCase {
 	return c.ContactUseCase
 }
 
+func (c *Container) GetChatUseCase() chat.UseCase {
+	return c.ChatUseCase
+}
+
 func (c *Container) GetNewsletterUseCase() newsletter.UseCase {
 	return c.NewsletterUseCase
 }
@@ -294,10 +587,76 @@ func (c *Container) GetCommunityUseCase() community.UseCase {
 	return c.CommunityUseCase
 }
 
+func (c *Container) GetDraftUseCase() draft.UseCase {
+	return c.DraftUseCase
+}
+
+func (c *Container) GetRoutingUseCase() routing.UseCase {
+	return c.RoutingUseCase
+}
+
+func (c *Container) GetConversationUseCase() conversation.UseCase {
+	return c.ConversationUseCase
+}
+
+func (c *Container) GetTranslationUseCase() translation.UseCase {
+	return c.TranslationUseCase
+}
+
+func (c *Container) GetPrivacyUseCase() privacy.UseCase {
+	return c.PrivacyUseCase
+}
+
+func (c *Container) GetOutboxUseCase() outbox.UseCase {
+	return c.OutboxUseCase
+}
+
+func (c *Container) GetScheduledUseCase() scheduled.UseCase {
+	return c.ScheduledUseCase
+}
+
+func (c *Container) GetAPIKeyUseCase() apikey.UseCase {
+	return c.APIKeyUseCase
+}
+
+func (c *Container) GetWarmupUseCase() warmup.UseCase {
+	return c.WarmupUseCase
+}
+
+func (c *Container) GetSendGuardUseCase() sendguard.UseCase {
+	return c.SendGuardUseCase
+}
+
+func (c *Container) GetHistoryUseCase() history.UseCase {
+	return c.HistoryUseCase
+}
+
+func (c *Container) GetUsageUseCase() usage.UseCase {
+	return c.UsageUseCase
+}
+
+func (c *Container) GetMaintenanceUseCase() maintenance.UseCase {
+	return c.MaintenanceUseCase
+}
+
+func (c *Container) GetSettingsUseCase() settings.UseCase {
+	return c.SettingsUseCase
+}
+
+func (c *Container) GetJobUseCase() job.UseCase {
+	return c.JobUseCase
+}
+
+func (c *Container) GetDashboardUseCase() dashboard.UseCase {
+	return c.DashboardUseCase
+}
+
+func (c *Container) GetPairingUseCase() pairing.UseCase {
+	return c.PairingUseCase
+}
+
 func (c *Container) GetSessionResolver() func(sessionID string) (ports.WameowManager, error) {
 	return func(sessionID string) (ports.WameowManager, error) {
 		return nil, fmt.Errorf("session resolver not properly implemented")
 	}
 }
-
-
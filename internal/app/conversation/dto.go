@@ -0,0 +1,51 @@
+package conversation
+
+import (
+	"time"
+
+	"zpwoot/internal/domain/conversation"
+)
+
+type SetConversationStateRequest struct {
+	State string            `json:"state" validate:"required" example:"main_menu"`
+	Data  map[string]string `json:"data,omitempty"`
+	// MergeData keeps the stored data and overwrites only the given keys
+	MergeData  bool `json:"mergeData,omitempty" example:"false"`
+	TTLSeconds int  `json:"ttlSeconds,omitempty" validate:"omitempty,min=0" example:"3600"` // Return the chat to "start" after this long; 0 keeps it
+} //@name SetConversationStateRequest
+
+type ListConversationStatesRequest struct {
+	State  string `json:"state,omitempty" query:"state" example:"main_menu"`
+	Limit  int    `json:"limit,omitempty" query:"limit" validate:"omitempty,min=1,max=100" example:"20"`
+	Offset int    `json:"offset,omitempty" query:"offset" validate:"omitempty,min=0" example:"0"`
+} //@name ListConversationStatesRequest
+
+type ConversationStateResponse struct {
+	ChatJID   string            `json:"chatJid" example:"5511999999999@s.whatsapp.net"`
+	State     string            `json:"state" example:"main_menu"`
+	Data      map[string]string `json:"data"`
+	ExpiresAt *time.Time        `json:"expiresAt,omitempty" example:"2024-01-01T13:00:00Z"`
+	UpdatedAt time.Time         `json:"updatedAt" example:"2024-01-01T12:00:00Z"`
+} //@name ConversationStateResponse
+
+type ListConversationStatesResponse struct {
+	States []ConversationStateResponse `json:"states"`
+	Total  int                         `json:"total" example:"3"`
+	Limit  int                         `json:"limit" example:"20"`
+	Offset int                         `json:"offset" example:"0"`
+} //@name ListConversationStatesResponse
+
+func FromState(s *conversation.State) *ConversationStateResponse {
+	data := s.Data
+	if data == nil {
+		data = map[string]string{}
+	}
+
+	return &ConversationStateResponse{
+		ChatJID:   s.ChatJID,
+		State:     s.State,
+		Data:      data,
+		ExpiresAt: s.ExpiresAt,
+		UpdatedAt: s.UpdatedAt,
+	}
+}
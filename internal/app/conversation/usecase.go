@@ -0,0 +1,89 @@
+package conversation
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"zpwoot/internal/domain/conversation"
+)
+
+type UseCase interface {
+	GetState(ctx context.Context, sessionID, chatJID string) (*ConversationStateResponse, error)
+	SetState(ctx context.Context, sessionID, chatJID string, req *SetConversationStateRequest) (*ConversationStateResponse, error)
+	ListStates(ctx context.Context, sessionID string, req *ListConversationStatesRequest) (*ListConversationStatesResponse, error)
+	ResetState(ctx context.Context, sessionID, chatJID string) error
+}
+
+type useCaseImpl struct {
+	conversationService *conversation.Service
+}
+
+func NewUseCase(conversationService *conversation.Service) UseCase {
+	return &useCaseImpl{
+		conversationService: conversationService,
+	}
+}
+
+// GetState returns the state of a chat; a chat without one is reported in the initial state
+func (uc *useCaseImpl) GetState(ctx context.Context, sessionID, chatJID string) (*ConversationStateResponse, error) {
+	state, err := uc.conversationService.GetState(ctx, sessionID, chatJID)
+	if errors.Is(err, conversation.ErrStateNotFound) {
+		return &ConversationStateResponse{
+			ChatJID: chatJID,
+			State:   conversation.InitialState,
+			Data:    map[string]string{},
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return FromState(state), nil
+}
+
+func (uc *useCaseImpl) SetState(ctx context.Context, sessionID, chatJID string, req *SetConversationStateRequest) (*ConversationStateResponse, error) {
+	state, err := uc.conversationService.SetState(ctx, &conversation.SetStateRequest{
+		SessionID: sessionID,
+		ChatJID:   chatJID,
+		State:     req.State,
+		Data:      req.Data,
+		MergeData: req.MergeData,
+		TTL:       time.Duration(req.TTLSeconds) * time.Second,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return FromState(state), nil
+}
+
+func (uc *useCaseImpl) ListStates(ctx context.Context, sessionID string, req *ListConversationStatesRequest) (*ListConversationStatesResponse, error) {
+	domainReq := &conversation.ListStatesRequest{
+		SessionID: sessionID,
+		State:     req.State,
+		Limit:     req.Limit,
+		Offset:    req.Offset,
+	}
+
+	states, total, err := uc.conversationService.ListStates(ctx, domainReq)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]ConversationStateResponse, len(states))
+	for i, state := range states {
+		responses[i] = *FromState(state)
+	}
+
+	return &ListConversationStatesResponse{
+		States: responses,
+		Total:  total,
+		Limit:  domainReq.Limit,
+		Offset: domainReq.Offset,
+	}, nil
+}
+
+func (uc *useCaseImpl) ResetState(ctx context.Context, sessionID, chatJID string) error {
+	return uc.conversationService.ResetState(ctx, sessionID, chatJID)
+}
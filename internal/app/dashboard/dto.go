@@ -0,0 +1,74 @@
+package dashboard
+
+import (
+	"time"
+
+	"zpwoot/internal/domain/activity"
+)
+
+type RecentMessagesRequest struct {
+	SessionID string `json:"sessionId" query:"sessionId" validate:"required" example:"mySession"`
+	Limit     int    `json:"limit" query:"limit" validate:"omitempty,min=1,max=100" example:"20"`
+} //@name RecentMessagesRequest
+
+type WebhookFailuresRequest struct {
+	SessionID string `json:"sessionId,omitempty" query:"sessionId" example:"mySession"`
+	Limit     int    `json:"limit" query:"limit" validate:"omitempty,min=1,max=200" example:"50"`
+} //@name WebhookFailuresRequest
+
+type RecentMessageResponse struct {
+	MessageID string    `json:"messageId" example:"3EB0C767D26A1D3D5A5B"`
+	Chat      string    `json:"chat" example:"5511999999999@s.whatsapp.net"`
+	Sender    string    `json:"sender" example:"5511999999999@s.whatsapp.net"`
+	FromMe    bool      `json:"fromMe" example:"false"`
+	Type      string    `json:"type" example:"text"`
+	Text      string    `json:"text,omitempty" example:"Hello"`
+	Timestamp time.Time `json:"timestamp" example:"2024-01-01T12:00:00Z"`
+} //@name RecentMessageResponse
+
+type RecentMessagesResponse struct {
+	SessionID string                  `json:"sessionId" example:"0f7c6a1e-4a52-4e6b-9d2b-3c1f2e4d5a6b"`
+	Messages  []RecentMessageResponse `json:"messages"`
+} //@name RecentMessagesResponse
+
+type WebhookFailureResponse struct {
+	WebhookID  string    `json:"webhookId" example:"0f7c6a1e-4a52-4e6b-9d2b-3c1f2e4d5a6b"`
+	URL        string    `json:"url" example:"https://example.com/webhook"`
+	SessionID  string    `json:"sessionId,omitempty" example:"0f7c6a1e-4a52-4e6b-9d2b-3c1f2e4d5a6b"`
+	EventID    string    `json:"eventId" example:"1b7c2d9e-0a3f-4f7e-8c1d-2e3f4a5b6c7d"`
+	EventType  string    `json:"eventType" example:"Message"`
+	StatusCode int       `json:"statusCode,omitempty" example:"502"`
+	Error      string    `json:"error,omitempty" example:"request failed: context deadline exceeded"`
+	Attempts   int       `json:"attempts" example:"3"`
+	FailedAt   time.Time `json:"failedAt" example:"2024-01-01T12:00:00Z"`
+} //@name WebhookFailureResponse
+
+type WebhookFailuresResponse struct {
+	Failures []WebhookFailureResponse `json:"failures"`
+} //@name WebhookFailuresResponse
+
+func FromRecentMessage(m *activity.RecentMessage) *RecentMessageResponse {
+	return &RecentMessageResponse{
+		MessageID: m.MessageID,
+		Chat:      m.Chat,
+		Sender:    m.Sender,
+		FromMe:    m.FromMe,
+		Type:      m.Type,
+		Text:      m.Text,
+		Timestamp: m.Timestamp,
+	}
+}
+
+func FromWebhookFailure(f *activity.WebhookFailure) *WebhookFailureResponse {
+	return &WebhookFailureResponse{
+		WebhookID:  f.WebhookID,
+		URL:        f.URL,
+		SessionID:  f.SessionID,
+		EventID:    f.EventID,
+		EventType:  f.EventType,
+		StatusCode: f.StatusCode,
+		Error:      f.Error,
+		Attempts:   f.Attempts,
+		FailedAt:   f.FailedAt,
+	}
+}
@@ -0,0 +1,89 @@
+package dashboard
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"zpwoot/internal/domain/activity"
+	"zpwoot/internal/domain/session"
+	"zpwoot/internal/ports"
+)
+
+const (
+	defaultMessagesLimit = 20
+	defaultFailuresLimit = 50
+)
+
+type UseCase interface {
+	// RecentMessages returns the latest messages of a session, given by ID or name, newest first
+	RecentMessages(ctx context.Context, req *RecentMessagesRequest) (*RecentMessagesResponse, error)
+	// WebhookFailures returns the latest permanently failed webhook deliveries, newest first
+	WebhookFailures(ctx context.Context, req *WebhookFailuresRequest) (*WebhookFailuresResponse, error)
+}
+
+type useCaseImpl struct {
+	sessionRepo     ports.SessionRepository
+	activityService *activity.Service
+}
+
+func NewUseCase(sessionRepo ports.SessionRepository, activityService *activity.Service) UseCase {
+	return &useCaseImpl{
+		sessionRepo:     sessionRepo,
+		activityService: activityService,
+	}
+}
+
+func (uc *useCaseImpl) RecentMessages(ctx context.Context, req *RecentMessagesRequest) (*RecentMessagesResponse, error) {
+	sess, err := uc.resolveSession(ctx, req.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultMessagesLimit
+	}
+
+	recent := uc.activityService.RecentMessages(sess.ID.String(), limit)
+	messages := make([]RecentMessageResponse, len(recent))
+	for i, m := range recent {
+		messages[i] = *FromRecentMessage(m)
+	}
+
+	return &RecentMessagesResponse{
+		SessionID: sess.ID.String(),
+		Messages:  messages,
+	}, nil
+}
+
+func (uc *useCaseImpl) WebhookFailures(ctx context.Context, req *WebhookFailuresRequest) (*WebhookFailuresResponse, error) {
+	sessionID := ""
+	if req.SessionID != "" {
+		sess, err := uc.resolveSession(ctx, req.SessionID)
+		if err != nil {
+			return nil, err
+		}
+		sessionID = sess.ID.String()
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultFailuresLimit
+	}
+
+	recent := uc.activityService.RecentWebhookFailures(sessionID, limit)
+	failures := make([]WebhookFailureResponse, len(recent))
+	for i, f := range recent {
+		failures[i] = *FromWebhookFailure(f)
+	}
+
+	return &WebhookFailuresResponse{Failures: failures}, nil
+}
+
+func (uc *useCaseImpl) resolveSession(ctx context.Context, identifier string) (*session.Session, error) {
+	if _, err := uuid.Parse(identifier); err == nil {
+		return uc.sessionRepo.GetByID(ctx, identifier)
+	}
+	return uc.sessionRepo.GetByName(ctx, identifier)
+}
@@ -0,0 +1,74 @@
+package draft
+
+import (
+	"time"
+
+	"zpwoot/internal/domain/draft"
+)
+
+type SaveDraftRequest struct {
+	Text string `json:"text" validate:"required" example:"Hi! Following up on your order"`
+} //@name SaveDraftRequest
+
+type SendDraftRequest struct {
+	SendAt *time.Time `json:"sendAt,omitempty" example:"2024-01-01T15:00:00Z"` // Schedule the draft; omit to send now
+} //@name SendDraftRequest
+
+type ListDraftsRequest struct {
+	Status string `json:"status,omitempty" query:"status" example:"scheduled"` // draft, scheduled, sending or failed
+	Limit  int    `json:"limit,omitempty" query:"limit" validate:"omitempty,min=1,max=100" example:"20"`
+	Offset int    `json:"offset,omitempty" query:"offset" validate:"omitempty,min=0" example:"0"`
+	// Cursor continues from the nextCursor of the previous page; it replaces offset
+	Cursor string `json:"cursor,omitempty" query:"cursor" example:"MjAyNC0wMS0wMVQxMjowMDowMFp8MWIyZTQyNGM"`
+} //@name ListDraftsRequest
+
+type DraftResponse struct {
+	ID          string     `json:"id" example:"0f7c6a1e-4a52-4e6b-9d2b-3c1f2e4d5a6b"`
+	ChatJID     string     `json:"chatJid" example:"5511999999999@s.whatsapp.net"`
+	Text        string     `json:"text" example:"Hi! Following up on your order"`
+	Status      string     `json:"status" example:"draft"`
+	ScheduledAt *time.Time `json:"scheduledAt,omitempty" example:"2024-01-01T15:00:00Z"`
+	LastError   string     `json:"lastError,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt" example:"2024-01-01T12:00:00Z"`
+	UpdatedAt   time.Time  `json:"updatedAt" example:"2024-01-01T12:00:00Z"`
+} //@name DraftResponse
+
+type ListDraftsResponse struct {
+	Drafts []DraftResponse `json:"drafts"`
+	Total  int             `json:"total" example:"3"`
+	Limit  int             `json:"limit" example:"20"`
+	Offset int             `json:"offset" example:"0"`
+	// NextCursor fetches the next page; it is empty on the last page
+	NextCursor string `json:"nextCursor,omitempty"`
+} //@name ListDraftsResponse
+
+type SendDraftResponse struct {
+	Status      string     `json:"status" example:"sent"` // sent or scheduled
+	MessageID   string     `json:"messageId,omitempty" example:"3EB0C767D71D"`
+	ChatJID     string     `json:"chatJid" example:"5511999999999@s.whatsapp.net"`
+	ScheduledAt *time.Time `json:"scheduledAt,omitempty" example:"2024-01-01T15:00:00Z"`
+	Timestamp   time.Time  `json:"timestamp" example:"2024-01-01T12:00:00Z"`
+} //@name SendDraftResponse
+
+func FromDraft(d *draft.Draft) *DraftResponse {
+	return &DraftResponse{
+		ID:          d.ID.String(),
+		ChatJID:     d.ChatJID,
+		Text:        d.Text,
+		Status:      d.Status,
+		ScheduledAt: d.ScheduledAt,
+		LastError:   d.LastError,
+		CreatedAt:   d.CreatedAt,
+		UpdatedAt:   d.UpdatedAt,
+	}
+}
+
+func FromSendDraftResult(result *draft.SendDraftResult) *SendDraftResponse {
+	return &SendDraftResponse{
+		Status:      result.Status,
+		MessageID:   result.MessageID,
+		ChatJID:     result.Draft.ChatJID,
+		ScheduledAt: result.Draft.ScheduledAt,
+		Timestamp:   result.Timestamp,
+	}
+}
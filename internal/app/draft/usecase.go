@@ -0,0 +1,120 @@
+package draft
+
+import (
+	"context"
+
+	appMessage "zpwoot/internal/app/message"
+	"zpwoot/internal/domain/draft"
+	"zpwoot/platform/pagination"
+)
+
+type UseCase interface {
+	SaveDraft(ctx context.Context, sessionID, chatJID string, req *SaveDraftRequest) (*DraftResponse, error)
+	GetDraft(ctx context.Context, sessionID, chatJID string) (*DraftResponse, error)
+	ListDrafts(ctx context.Context, sessionID string, req *ListDraftsRequest) (*ListDraftsResponse, error)
+	DeleteDraft(ctx context.Context, sessionID, chatJID string) error
+	// SendDraft sends the draft now or schedules it, provided the workspace quota allows another
+	// message; the draft is counted once it is sent
+	SendDraft(ctx context.Context, sessionID, chatJID string, req *SendDraftRequest) (*SendDraftResponse, error)
+}
+
+type useCaseImpl struct {
+	draftService *draft.Service
+	messageUC    appMessage.UseCase
+}
+
+func NewUseCase(draftService *draft.Service, messageUC appMessage.UseCase) UseCase {
+	// Drafts go through the same translation, usage and history handling as direct sends
+	draftService.SetSender(messageUC)
+
+	return &useCaseImpl{
+		draftService: draftService,
+		messageUC:    messageUC,
+	}
+}
+
+func (uc *useCaseImpl) SaveDraft(ctx context.Context, sessionID, chatJID string, req *SaveDraftRequest) (*DraftResponse, error) {
+	d, err := uc.draftService.SaveDraft(ctx, &draft.SaveDraftRequest{
+		SessionID: sessionID,
+		ChatJID:   chatJID,
+		Text:      req.Text,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return FromDraft(d), nil
+}
+
+func (uc *useCaseImpl) GetDraft(ctx context.Context, sessionID, chatJID string) (*DraftResponse, error) {
+	d, err := uc.draftService.GetDraft(ctx, sessionID, chatJID)
+	if err != nil {
+		return nil, err
+	}
+
+	return FromDraft(d), nil
+}
+
+func (uc *useCaseImpl) ListDrafts(ctx context.Context, sessionID string, req *ListDraftsRequest) (*ListDraftsResponse, error) {
+	domainReq := &draft.ListDraftsRequest{
+		SessionID: sessionID,
+		Status:    req.Status,
+		Limit:     req.Limit,
+		Offset:    req.Offset,
+	}
+
+	after, err := pagination.Decode(req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	if after != nil {
+		domainReq.After = after
+		domainReq.Offset = 0
+	}
+
+	drafts, total, err := uc.draftService.ListDrafts(ctx, domainReq)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]DraftResponse, len(drafts))
+	for i, d := range drafts {
+		responses[i] = *FromDraft(d)
+	}
+
+	response := &ListDraftsResponse{
+		Drafts: responses,
+		Total:  total,
+		Limit:  domainReq.Limit,
+		Offset: domainReq.Offset,
+	}
+	if len(drafts) > 0 {
+		last := drafts[len(drafts)-1]
+		response.NextCursor = pagination.Next(len(drafts), domainReq.Limit, last.CreatedAt, last.ID.String())
+	}
+
+	return response, nil
+}
+
+func (uc *useCaseImpl) DeleteDraft(ctx context.Context, sessionID, chatJID string) error {
+	return uc.draftService.DeleteDraft(ctx, sessionID, chatJID)
+}
+
+func (uc *useCaseImpl) SendDraft(ctx context.Context, sessionID, chatJID string, req *SendDraftRequest) (*SendDraftResponse, error) {
+	domainReq := &draft.SendDraftRequest{
+		SessionID: sessionID,
+		ChatJID:   chatJID,
+		SendAt:    req.SendAt,
+	}
+
+	if err := uc.messageUC.CheckSendQuota(ctx); err != nil {
+		return nil, err
+	}
+
+	result, err := uc.draftService.SendDraft(ctx, domainReq)
+	if err != nil {
+		return nil, err
+	}
+
+	return FromSendDraftResult(result), nil
+}
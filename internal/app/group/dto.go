@@ -159,6 +159,37 @@ type GroupActionResponse struct {
 	Timestamp time.Time `json:"timestamp" example:"2024-01-01T00:00:00Z"`
 } //@name GroupActionResponse
 
+// GroupJoinRequest is a pending request to join a group that requires admin approval
+type GroupJoinRequest struct {
+	JID         string    `json:"jid" example:"5511999999999@s.whatsapp.net"`
+	RequestedAt time.Time `json:"requestedAt" example:"2024-01-01T00:00:00Z"`
+} //@name GroupJoinRequest
+
+// ListGroupJoinRequestsResponse represents the pending join requests of a group
+type ListGroupJoinRequestsResponse struct {
+	GroupJID     string             `json:"groupJid" example:"120363123456789012@g.us"`
+	Participants []GroupJoinRequest `json:"participants"`
+	Total        int                `json:"total" example:"2"`
+} //@name ListGroupJoinRequestsResponse
+
+// UpdateGroupJoinRequestsRequest represents the request to approve or reject join requests; All handles
+// every pending request instead of the listed participants
+type UpdateGroupJoinRequestsRequest struct {
+	GroupJID     string   `json:"groupJid" validate:"required" example:"120363123456789012@g.us"`
+	Action       string   `json:"action" validate:"required,oneof=approve reject" example:"approve"`
+	Participants []string `json:"participants,omitempty" example:"5511999999999@s.whatsapp.net"`
+	All          bool     `json:"all,omitempty" example:"false"`
+} //@name UpdateGroupJoinRequestsRequest
+
+// UpdateGroupJoinRequestsResponse represents the result of approving or rejecting join requests
+type UpdateGroupJoinRequestsResponse struct {
+	GroupJID     string   `json:"groupJid" example:"120363123456789012@g.us"`
+	Action       string   `json:"action" example:"approve"`
+	Participants []string `json:"participants" example:"5511999999999@s.whatsapp.net"`
+	Success      []string `json:"success" example:"5511999999999@s.whatsapp.net"`
+	Failed       []string `json:"failed"`
+} //@name UpdateGroupJoinRequestsResponse
+
 // Conversion functions to/from domain models
 func (r *CreateGroupRequest) ToDomain() *group.CreateGroupRequest {
 	return &group.CreateGroupRequest{
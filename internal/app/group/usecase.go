@@ -20,8 +20,8 @@ type UseCase interface {
 	JoinGroup(ctx context.Context, sessionID string, req *JoinGroupRequest) (*JoinGroupResponse, error)
 	LeaveGroup(ctx context.Context, sessionID string, req *LeaveGroupRequest) (*LeaveGroupResponse, error)
 	UpdateGroupSettings(ctx context.Context, sessionID string, req *UpdateGroupSettingsRequest) (*GroupActionResponse, error)
-	GetGroupRequestParticipants(ctx context.Context, sessionID string, groupJID string) ([]interface{}, error)
-	UpdateGroupRequestParticipants(ctx context.Context, sessionID string, groupJID string, participants []string, action string) ([]string, []string, error)
+	GetGroupRequestParticipants(ctx context.Context, sessionID string, groupJID string) (*ListGroupJoinRequestsResponse, error)
+	UpdateGroupRequestParticipants(ctx context.Context, sessionID string, req *UpdateGroupJoinRequestsRequest) (*UpdateGroupJoinRequestsResponse, error)
 	SetGroupJoinApprovalMode(ctx context.Context, sessionID string, groupJID string, requireApproval bool) error
 	SetGroupMemberAddMode(ctx context.Context, sessionID string, groupJID string, mode string) error
 
@@ -302,26 +302,65 @@ func (uc *useCaseImpl) isUserAdmin(group *ports.GroupInfo, sessionID string) boo
 	return false
 }
 
-func (uc *useCaseImpl) GetGroupRequestParticipants(ctx context.Context, sessionID string, groupJID string) ([]interface{}, error) {
+func (uc *useCaseImpl) GetGroupRequestParticipants(ctx context.Context, sessionID string, groupJID string) (*ListGroupJoinRequestsResponse, error) {
 	participants, err := uc.wameowMgr.GetGroupRequestParticipants(sessionID, groupJID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Convert to interface{} slice for JSON response
-	result := make([]interface{}, len(participants))
+	requests := make([]GroupJoinRequest, len(participants))
 	for i, p := range participants {
-		result[i] = map[string]interface{}{
-			"jid":          p.JID.String(),
-			"requested_at": p.RequestedAt.Format(time.RFC3339),
+		requests[i] = GroupJoinRequest{
+			JID:         p.JID.String(),
+			RequestedAt: p.RequestedAt,
 		}
 	}
 
-	return result, nil
+	return &ListGroupJoinRequestsResponse{
+		GroupJID:     groupJID,
+		Participants: requests,
+		Total:        len(requests),
+	}, nil
 }
 
-func (uc *useCaseImpl) UpdateGroupRequestParticipants(ctx context.Context, sessionID string, groupJID string, participants []string, action string) ([]string, []string, error) {
-	return uc.wameowMgr.UpdateGroupRequestParticipants(sessionID, groupJID, participants, action)
+// UpdateGroupRequestParticipants approves or rejects join requests, resolving every pending request
+// first when the request asks for all of them
+func (uc *useCaseImpl) UpdateGroupRequestParticipants(ctx context.Context, sessionID string, req *UpdateGroupJoinRequestsRequest) (*UpdateGroupJoinRequestsResponse, error) {
+	participants := req.Participants
+	if req.All {
+		pending, err := uc.wameowMgr.GetGroupRequestParticipants(sessionID, req.GroupJID)
+		if err != nil {
+			return nil, err
+		}
+		participants = make([]string, len(pending))
+		for i, p := range pending {
+			participants[i] = p.JID.String()
+		}
+	}
+
+	response := &UpdateGroupJoinRequestsResponse{
+		GroupJID:     req.GroupJID,
+		Action:       req.Action,
+		Participants: participants,
+		Success:      []string{},
+		Failed:       []string{},
+	}
+	if len(participants) == 0 {
+		return response, nil
+	}
+
+	success, failed, err := uc.wameowMgr.UpdateGroupRequestParticipants(sessionID, req.GroupJID, participants, req.Action)
+	if err != nil {
+		return nil, err
+	}
+	if success != nil {
+		response.Success = success
+	}
+	if failed != nil {
+		response.Failed = failed
+	}
+
+	return response, nil
 }
 
 func (uc *useCaseImpl) SetGroupJoinApprovalMode(ctx context.Context, sessionID string, groupJID string, requireApproval bool) error {
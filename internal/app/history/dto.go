@@ -0,0 +1,132 @@
+package history
+
+import (
+	"time"
+
+	"zpwoot/internal/domain/history"
+)
+
+type ListChatMessagesRequest struct {
+	// From and To limit the messages to those sent in the range; the handler parses them as RFC 3339
+	From  *time.Time `json:"from,omitempty" query:"-" example:"2024-01-01T00:00:00Z"`
+	To    *time.Time `json:"to,omitempty" query:"-" example:"2024-01-31T23:59:59Z"`
+	Query string     `json:"q,omitempty" query:"q" example:"invoice"` // Full-text search on the message text
+	// Actor keeps the outgoing messages sent by that kind of actor
+	Actor string `json:"actor,omitempty" query:"actor" validate:"omitempty,oneof=api chatwoot autoresponder campaign scheduler" example:"api"`
+	// Pinned keeps the messages currently pinned in the chat
+	Pinned bool `json:"pinned,omitempty" query:"pinned" example:"false"`
+	Limit  int  `json:"limit,omitempty" query:"limit" validate:"omitempty,min=1,max=200" example:"50"`
+	// Cursor continues from the nextCursor of the previous page
+	Cursor string `json:"cursor,omitempty" query:"cursor" example:"MjAyNC0wMS0wMVQxMjowMDowMFp8MWIyZTQyNGM"`
+} //@name ListChatMessagesRequest
+
+type MediaResponse struct {
+	MimeType   string `json:"mimeType,omitempty" example:"image/jpeg"`
+	FileName   string `json:"fileName,omitempty" example:"invoice.pdf"`
+	FileLength uint64 `json:"fileLength,omitempty" example:"48213"`
+	Width      uint32 `json:"width,omitempty" example:"1280"`
+	Height     uint32 `json:"height,omitempty" example:"720"`
+	Seconds    uint32 `json:"seconds,omitempty" example:"12"`
+	DirectPath string `json:"directPath,omitempty"`
+	URL        string `json:"url,omitempty"`
+} //@name MessageMediaResponse
+
+type ContextInfoResponse struct {
+	QuotedMessageID   string   `json:"quotedMessageId,omitempty" example:"3EB0C767D71D"`
+	QuotedParticipant string   `json:"quotedParticipant,omitempty" example:"5511999999999@s.whatsapp.net"`
+	Mentions          []string `json:"mentions,omitempty"`
+	Forwarded         bool     `json:"forwarded,omitempty"`
+} //@name MessageContextInfoResponse
+
+type ActorResponse struct {
+	Type string `json:"type" example:"api"` // api, chatwoot, autoresponder, campaign, scheduler
+	ID   string `json:"id,omitempty" example:"zpw_live****a1b2"`
+} //@name MessageActorResponse
+
+type PinResponse struct {
+	PinnedAt  time.Time `json:"pinnedAt" example:"2024-01-01T12:00:00Z"`
+	ExpiresAt time.Time `json:"expiresAt" example:"2024-01-08T12:00:00Z"`
+	PinnedBy  string    `json:"pinnedBy,omitempty" example:"5511999999999@s.whatsapp.net"`
+} //@name MessagePinResponse
+
+type EphemeralResponse struct {
+	Duration  uint32    `json:"duration" example:"604800"` // Disappearing messages timer of the chat in seconds
+	ExpiresAt time.Time `json:"expiresAt" example:"2024-01-08T12:00:00Z"`
+} //@name MessageEphemeralResponse
+
+type MessageResponse struct {
+	ID        string               `json:"id" example:"0f7c6a1e-4a52-4e6b-9d2b-3c1f2e4d5a6b"`
+	MessageID string               `json:"messageId" example:"3EB0C767D71D"`
+	ChatJID   string               `json:"chatJid" example:"5511999999999@s.whatsapp.net"`
+	SenderJID string               `json:"senderJid" example:"5511999999999@s.whatsapp.net"`
+	FromMe    bool                 `json:"fromMe" example:"false"`
+	Type      string               `json:"type" example:"text"`
+	Text      string               `json:"text,omitempty" example:"Hi! Is my order on its way?"`
+	Media     *MediaResponse       `json:"media,omitempty"`
+	Context   *ContextInfoResponse `json:"context,omitempty"`
+	Timestamp time.Time            `json:"timestamp" example:"2024-01-01T12:00:00Z"`
+	EditedAt  *time.Time           `json:"editedAt,omitempty" example:"2024-01-01T12:05:00Z"`
+	RevokedAt *time.Time           `json:"revokedAt,omitempty" example:"2024-01-01T12:10:00Z"`
+	// Pin is set while the message is pinned in the chat
+	Pin *PinResponse `json:"pin,omitempty"`
+	// Ephemeral is set for messages sent in a chat with disappearing messages
+	Ephemeral *EphemeralResponse `json:"ephemeral,omitempty"`
+	// Actor is what made the session send the message, for messages sent through the API or an integration
+	Actor *ActorResponse `json:"actor,omitempty"`
+} //@name ChatMessageResponse
+
+type ListChatMessagesResponse struct {
+	Messages []MessageResponse `json:"messages"`
+	Total    int               `json:"total" example:"120"`
+	Limit    int               `json:"limit" example:"50"`
+	// NextCursor fetches the next (older) page; it is empty on the last page
+	NextCursor string `json:"nextCursor,omitempty"`
+} //@name ListChatMessagesResponse
+
+func FromMessage(m *history.Message) *MessageResponse {
+	response := &MessageResponse{
+		ID:        m.ID.String(),
+		MessageID: m.MessageID,
+		ChatJID:   m.ChatJID,
+		SenderJID: m.SenderJID,
+		FromMe:    m.FromMe,
+		Type:      m.Type,
+		Text:      m.Text,
+		Timestamp: m.Timestamp,
+		EditedAt:  m.EditedAt,
+		RevokedAt: m.RevokedAt,
+	}
+
+	if m.Pin.IsActive(time.Now()) {
+		response.Pin = &PinResponse{PinnedAt: m.Pin.PinnedAt, ExpiresAt: m.Pin.ExpiresAt, PinnedBy: m.Pin.PinnedBy}
+	}
+	if m.Ephemeral != nil {
+		response.Ephemeral = &EphemeralResponse{Duration: m.Ephemeral.Duration, ExpiresAt: m.Ephemeral.ExpiresAt}
+	}
+	if m.Actor != nil {
+		response.Actor = &ActorResponse{Type: m.Actor.Type, ID: m.Actor.ID}
+	}
+
+	if m.Media != nil {
+		response.Media = &MediaResponse{
+			MimeType:   m.Media.MimeType,
+			FileName:   m.Media.FileName,
+			FileLength: m.Media.FileLength,
+			Width:      m.Media.Width,
+			Height:     m.Media.Height,
+			Seconds:    m.Media.Seconds,
+			DirectPath: m.Media.DirectPath,
+			URL:        m.Media.URL,
+		}
+	}
+	if m.Context != nil {
+		response.Context = &ContextInfoResponse{
+			QuotedMessageID:   m.Context.QuotedMessageID,
+			QuotedParticipant: m.Context.QuotedParticipant,
+			Mentions:          m.Context.Mentions,
+			Forwarded:         m.Context.Forwarded,
+		}
+	}
+
+	return response
+}
@@ -0,0 +1,63 @@
+package history
+
+import (
+	"context"
+
+	"zpwoot/internal/domain/history"
+	"zpwoot/platform/pagination"
+)
+
+type UseCase interface {
+	ListChatMessages(ctx context.Context, sessionID, chatJID string, req *ListChatMessagesRequest) (*ListChatMessagesResponse, error)
+}
+
+type useCaseImpl struct {
+	historyService *history.Service
+}
+
+func NewUseCase(historyService *history.Service) UseCase {
+	return &useCaseImpl{
+		historyService: historyService,
+	}
+}
+
+func (uc *useCaseImpl) ListChatMessages(ctx context.Context, sessionID, chatJID string, req *ListChatMessagesRequest) (*ListChatMessagesResponse, error) {
+	after, err := pagination.Decode(req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	domainReq := &history.ListRequest{
+		SessionID: sessionID,
+		ChatJID:   chatJID,
+		From:      req.From,
+		To:        req.To,
+		Query:     req.Query,
+		ActorType: req.Actor,
+		Pinned:    req.Pinned,
+		Limit:     req.Limit,
+		After:     after,
+	}
+
+	messages, total, err := uc.historyService.ListChatMessages(ctx, domainReq)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]MessageResponse, len(messages))
+	for i, m := range messages {
+		responses[i] = *FromMessage(m)
+	}
+
+	response := &ListChatMessagesResponse{
+		Messages: responses,
+		Total:    total,
+		Limit:    domainReq.Limit,
+	}
+	if len(messages) > 0 {
+		last := messages[len(messages)-1]
+		response.NextCursor = pagination.Next(len(messages), domainReq.Limit, last.Timestamp, last.ID.String())
+	}
+
+	return response, nil
+}
@@ -0,0 +1,47 @@
+package job
+
+import (
+	"time"
+
+	"zpwoot/internal/domain/job"
+)
+
+type JobResponse struct {
+	Name          string     `json:"name" example:"draft_scheduler"`
+	Description   string     `json:"description,omitempty" example:"Sends scheduled drafts whose time has come"`
+	State         string     `json:"state" example:"idle"`     // idle, running, standby, stopped
+	Interval      int        `json:"interval" example:"30"`    // Seconds between runs; 0 runs only at startup or when triggered
+	Exclusive     bool       `json:"exclusive" example:"true"` // Runs on a single replica; standby on the others
+	Runs          int64      `json:"runs" example:"42"`
+	Failures      int64      `json:"failures" example:"1"`
+	LastTrigger   string     `json:"lastTrigger,omitempty" example:"schedule"` // schedule, manual
+	LastRunAt     *time.Time `json:"lastRunAt,omitempty" example:"2024-01-01T12:00:00Z"`
+	LastDuration  int64      `json:"lastDuration" example:"120"` // Milliseconds
+	LastError     string     `json:"lastError,omitempty" example:"failed to claim due drafts: connection refused"`
+	LastSuccessAt *time.Time `json:"lastSuccessAt,omitempty" example:"2024-01-01T12:00:00Z"`
+	NextRunAt     *time.Time `json:"nextRunAt,omitempty" example:"2024-01-01T12:00:30Z"`
+	RunQueued     bool       `json:"runQueued" example:"false"`
+} //@name JobResponse
+
+type ListJobsResponse struct {
+	Jobs []JobResponse `json:"jobs"`
+} //@name ListJobsResponse
+
+func FromStatus(status *job.Status) *JobResponse {
+	return &JobResponse{
+		Name:          status.Name,
+		Description:   status.Description,
+		State:         status.State,
+		Interval:      int(status.Interval.Seconds()),
+		Exclusive:     status.Exclusive,
+		Runs:          status.Runs,
+		Failures:      status.Failures,
+		LastTrigger:   status.LastTrigger,
+		LastRunAt:     status.LastRunAt,
+		LastDuration:  status.LastDuration.Milliseconds(),
+		LastError:     status.LastError,
+		LastSuccessAt: status.LastSuccessAt,
+		NextRunAt:     status.NextRunAt,
+		RunQueued:     status.RunQueued,
+	}
+}
@@ -0,0 +1,51 @@
+package job
+
+import (
+	"context"
+
+	"zpwoot/internal/domain/job"
+)
+
+type UseCase interface {
+	ListJobs(ctx context.Context) *ListJobsResponse
+	GetJob(ctx context.Context, name string) (*JobResponse, error)
+	// TriggerJob queues a run of the job outside its schedule
+	TriggerJob(ctx context.Context, name string) (*JobResponse, error)
+}
+
+type useCaseImpl struct {
+	jobService *job.Service
+}
+
+func NewUseCase(jobService *job.Service) UseCase {
+	return &useCaseImpl{
+		jobService: jobService,
+	}
+}
+
+func (uc *useCaseImpl) ListJobs(ctx context.Context) *ListJobsResponse {
+	statuses := uc.jobService.List()
+
+	jobs := make([]JobResponse, len(statuses))
+	for i, status := range statuses {
+		jobs[i] = *FromStatus(status)
+	}
+
+	return &ListJobsResponse{Jobs: jobs}
+}
+
+func (uc *useCaseImpl) GetJob(ctx context.Context, name string) (*JobResponse, error) {
+	status, err := uc.jobService.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return FromStatus(status), nil
+}
+
+func (uc *useCaseImpl) TriggerJob(ctx context.Context, name string) (*JobResponse, error) {
+	status, err := uc.jobService.Trigger(name)
+	if err != nil {
+		return nil, err
+	}
+	return FromStatus(status), nil
+}
@@ -0,0 +1,56 @@
+package maintenance
+
+import (
+	"time"
+
+	"zpwoot/internal/domain/maintenance"
+)
+
+type SetMaintenanceRequest struct {
+	Enabled    bool   `json:"enabled" example:"true"`
+	Reason     string `json:"reason,omitempty" example:"Upgrading to v1.4"`
+	RetryAfter int    `json:"retryAfter,omitempty" validate:"omitempty,min=1" example:"120"` // Seconds clients should wait before retrying sends
+} //@name SetMaintenanceRequest
+
+type FreezeSessionRequest struct {
+	Reason string `json:"reason,omitempty" example:"Re-pairing device"`
+} //@name FreezeSessionRequest
+
+type FrozenSessionResponse struct {
+	SessionID   string    `json:"sessionId" example:"0f7c6a1e-4a52-4e6b-9d2b-3c1f2e4d5a6b"`
+	SessionName string    `json:"sessionName" example:"mySession"`
+	Reason      string    `json:"reason,omitempty" example:"Re-pairing device"`
+	Since       time.Time `json:"since" example:"2024-01-01T12:00:00Z"`
+} //@name FrozenSessionResponse
+
+type MaintenanceStatusResponse struct {
+	Enabled    bool                    `json:"enabled" example:"true"`
+	Reason     string                  `json:"reason,omitempty" example:"Upgrading to v1.4"`
+	Since      *time.Time              `json:"since,omitempty" example:"2024-01-01T12:00:00Z"`
+	RetryAfter int                     `json:"retryAfter" example:"120"`
+	Sessions   []FrozenSessionResponse `json:"sessions"`
+} //@name MaintenanceStatusResponse
+
+func FromFreeze(f *maintenance.SessionFreeze) *FrozenSessionResponse {
+	return &FrozenSessionResponse{
+		SessionID:   f.SessionID,
+		SessionName: f.SessionName,
+		Reason:      f.Reason,
+		Since:       f.Since,
+	}
+}
+
+func FromStatus(status *maintenance.Status) *MaintenanceStatusResponse {
+	sessions := make([]FrozenSessionResponse, len(status.Sessions))
+	for i, f := range status.Sessions {
+		sessions[i] = *FromFreeze(f)
+	}
+
+	return &MaintenanceStatusResponse{
+		Enabled:    status.Enabled,
+		Reason:     status.Reason,
+		Since:      status.Since,
+		RetryAfter: int(status.RetryAfter / time.Second),
+		Sessions:   sessions,
+	}
+}
@@ -0,0 +1,92 @@
+package maintenance
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"zpwoot/internal/domain/maintenance"
+	"zpwoot/internal/domain/session"
+	"zpwoot/internal/ports"
+)
+
+type UseCase interface {
+	GetStatus(ctx context.Context) *MaintenanceStatusResponse
+	SetMaintenance(ctx context.Context, req *SetMaintenanceRequest) (*MaintenanceStatusResponse, error)
+	FreezeSession(ctx context.Context, sessionIdentifier string, req *FreezeSessionRequest) (*FrozenSessionResponse, error)
+	UnfreezeSession(ctx context.Context, sessionIdentifier string) error
+	// SendFrozen reports whether sends are refused for the session, given by ID or name, and when to retry
+	SendFrozen(sessionIdentifier string) (bool, time.Duration)
+}
+
+type useCaseImpl struct {
+	sessionRepo        ports.SessionRepository
+	maintenanceService *maintenance.Service
+}
+
+func NewUseCase(sessionRepo ports.SessionRepository, maintenanceService *maintenance.Service) UseCase {
+	return &useCaseImpl{
+		sessionRepo:        sessionRepo,
+		maintenanceService: maintenanceService,
+	}
+}
+
+func (uc *useCaseImpl) GetStatus(ctx context.Context) *MaintenanceStatusResponse {
+	return FromStatus(uc.maintenanceService.Status())
+}
+
+func (uc *useCaseImpl) SetMaintenance(ctx context.Context, req *SetMaintenanceRequest) (*MaintenanceStatusResponse, error) {
+	var status *maintenance.Status
+	var err error
+	if req.Enabled {
+		status, err = uc.maintenanceService.Enable(ctx, &maintenance.EnableRequest{
+			Reason:     req.Reason,
+			RetryAfter: time.Duration(req.RetryAfter) * time.Second,
+		})
+	} else {
+		status, err = uc.maintenanceService.Disable(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return FromStatus(status), nil
+}
+
+func (uc *useCaseImpl) FreezeSession(ctx context.Context, sessionIdentifier string, req *FreezeSessionRequest) (*FrozenSessionResponse, error) {
+	sess, err := uc.resolveSession(ctx, sessionIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	freeze, err := uc.maintenanceService.FreezeSession(ctx, sess.ID.String(), sess.Name, req.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	return FromFreeze(freeze), nil
+}
+
+func (uc *useCaseImpl) UnfreezeSession(ctx context.Context, sessionIdentifier string) error {
+	sess, err := uc.resolveSession(ctx, sessionIdentifier)
+	if err != nil {
+		return err
+	}
+
+	return uc.maintenanceService.UnfreezeSession(ctx, sess.ID.String())
+}
+
+func (uc *useCaseImpl) SendFrozen(sessionIdentifier string) (bool, time.Duration) {
+	if !uc.maintenanceService.SendFrozen(sessionIdentifier) {
+		return false, 0
+	}
+	return true, uc.maintenanceService.RetryAfter()
+}
+
+func (uc *useCaseImpl) resolveSession(ctx context.Context, identifier string) (*session.Session, error) {
+	if _, err := uuid.Parse(identifier); err == nil {
+		return uc.sessionRepo.GetByID(ctx, identifier)
+	}
+	return uc.sessionRepo.GetByName(ctx, identifier)
+}
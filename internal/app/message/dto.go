@@ -1,9 +1,11 @@
 package message
 
 import (
+	"fmt"
 	"time"
 
 	"zpwoot/internal/domain/message"
+	"zpwoot/internal/domain/poll"
 )
 
 type SendMessageRequest struct {
@@ -11,9 +13,12 @@ type SendMessageRequest struct {
 	Type      string `json:"type" validate:"required,oneof=text image audio video document sticker location contact" example:"text"`
 	Body      string `json:"body,omitempty" example:"Hello World!"`
 	Caption   string `json:"caption,omitempty" example:"Image caption"`
-	File      string `json:"file,omitempty" example:"https://example.com/image.jpg"`
-	Filename  string `json:"filename,omitempty" example:"document.pdf"` // Only used for document type, not for audio
-	MimeType  string `json:"mimeType,omitempty" example:"image/jpeg"`
+	// File is an http(s) URL or a base64 data URI (data:image/png;base64,...), up to MEDIA_MAX_SIZE_MB.
+	// The media endpoints also take the file as a multipart/form-data upload.
+	File     string `json:"file,omitempty" example:"https://example.com/image.jpg"`
+	Filename string `json:"filename,omitempty" example:"document.pdf"` // Only used for document type, not for audio
+	// MimeType overrides the type detected from the media content
+	MimeType string `json:"mimeType,omitempty" example:"image/jpeg"`
 
 	Latitude  float64 `json:"latitude,omitempty" example:"-23.5505"`
 	Longitude float64 `json:"longitude,omitempty" example:"-46.6333"`
@@ -22,14 +27,56 @@ type SendMessageRequest struct {
 	ContactName  string       `json:"contactName,omitempty" example:"John Doe"`
 	ContactPhone string       `json:"contactPhone,omitempty" example:"+5511999999999"`
 	ContextInfo  *ContextInfo `json:"contextInfo,omitempty"`
+
+	// ScheduleAt sends the message at that time instead of now; track it with the scheduled message endpoints
+	ScheduleAt *time.Time `json:"scheduleAt,omitempty" example:"2024-01-01T18:00:00Z"`
+
+	// Upload is set instead of File for media uploaded with multipart/form-data
+	Upload *message.ProcessedMedia `json:"-" swaggerignore:"true"`
 } //@name SendMessageRequest
 
 type SendMessageResponse struct {
 	ID        string    `json:"id" example:"3EB0C767D71D"`
 	Status    string    `json:"status" example:"sent"`
 	Timestamp time.Time `json:"timestamp" example:"2024-01-01T12:00:00Z"`
+	// Translation is set when the session translated the message before sending it
+	Translation *MessageTranslation `json:"translation,omitempty"`
+	// QueueID and ExpiresAt are set when the session was disconnected and the message was queued
+	// (status "queued"); it is sent once the session reconnects before ExpiresAt
+	QueueID   string     `json:"queueId,omitempty" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty" example:"2024-01-01T13:00:00Z"`
+	// ScheduledID and ScheduledAt are set when the message was scheduled with scheduleAt (status "scheduled")
+	ScheduledID string     `json:"scheduledId,omitempty" example:"1b9d6bcd-bbfd-4b2d-9b5d-ab8dfbbd4bed"`
+	ScheduledAt *time.Time `json:"scheduledAt,omitempty" example:"2024-01-01T18:00:00Z"`
 } //@name SendMessageResponse
 
+// Sources of a cancelled message
+const (
+	CancelSourceOfflineQueue = "offline_queue"
+	CancelSourceOutbox       = "outbox"
+	CancelSourceScheduler    = "scheduler"
+)
+
+type CancelQueuedMessageResponse struct {
+	QueueID string `json:"queueId" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	// Source is offline_queue for sends queued while the session was disconnected, outbox for messages
+	// added to the outbox and scheduler for scheduled drafts and messages
+	Source string `json:"source" example:"offline_queue"`
+	// Cancelled is false when the message was no longer waiting, e.g. already picked up for sending
+	Cancelled bool `json:"cancelled" example:"true"`
+	// Status is the state of the message after the call, e.g. cancelled, sending or sent
+	Status    string `json:"status" example:"cancelled"`
+	MessageID string `json:"messageId,omitempty" example:"3EB0C767D71D"`
+} //@name CancelQueuedMessageResponse
+
+// MessageTranslation keeps the text a message was written in before it was translated and sent
+type MessageTranslation struct {
+	SourceLanguage  string `json:"sourceLanguage" example:"en"`
+	TargetLanguage  string `json:"targetLanguage" example:"es"`
+	OriginalText    string `json:"originalText,omitempty" example:"Hello World!"`
+	OriginalCaption string `json:"originalCaption,omitempty" example:"Image caption"`
+} //@name MessageTranslation
+
 func FromDomainRequest(req *message.SendMessageRequest) *SendMessageRequest {
 	return &SendMessageRequest{
 		RemoteJID:    req.To,
@@ -70,6 +117,7 @@ func (r *SendMessageRequest) ToDomainRequest() *message.SendMessageRequest {
 		ContactName:  r.ContactName,
 		ContactPhone: r.ContactPhone,
 		ContextInfo:  contextInfo,
+		Upload:       r.Upload,
 	}
 }
 
@@ -105,8 +153,88 @@ type ListMessageRequest struct {
 	Body       string    `json:"body" validate:"required" example:"Please select one of the available options:"`
 	ButtonText string    `json:"buttonText" validate:"required" example:"Select Option"`
 	Sections   []Section `json:"sections" validate:"required,min=1"`
+	// FallbackToText sends the list as a numbered text message for clients that do not render native lists
+	FallbackToText bool `json:"fallbackToText,omitempty" example:"false"`
 } //@name ListMessageRequest
 
+// FlowMessageRequest sends an interactive message with native flow buttons. Each button has a type
+// WhatsApp renders (quick_reply, cta_url, cta_call, cta_copy, single_select, ...) and its parameters;
+// replies arrive as flow.response webhook events. Only WhatsApp Business sessions can send them.
+type FlowMessageRequest struct {
+	RemoteJID string       `json:"remoteJid" validate:"required" example:"5511999999999@s.whatsapp.net"`
+	Header    string       `json:"header,omitempty" example:"Order #1234"`
+	Body      string       `json:"body" validate:"required" example:"Your order is ready for pickup"`
+	Footer    string       `json:"footer,omitempty" example:"zpwoot store"`
+	Buttons   []FlowButton `json:"buttons" validate:"required,min=1,max=10"`
+	// MessageParams are message-level native flow parameters, sent as JSON
+	MessageParams map[string]interface{} `json:"messageParams,omitempty"`
+} //@name FlowMessageRequest
+
+type FlowButton struct {
+	Name   string                 `json:"name" validate:"required" example:"quick_reply"`
+	Params map[string]interface{} `json:"params,omitempty"`
+} //@name FlowButton
+
+// CarouselMessageRequest sends cards the recipient swipes through, each with an image or video, a body
+// and native flow buttons. Sessions of non-business accounts, which WhatsApp does not render carousels
+// for, send the cards as text; FallbackToText forces it.
+type CarouselMessageRequest struct {
+	RemoteJID      string         `json:"remoteJid" validate:"required" example:"5511999999999@s.whatsapp.net"`
+	Body           string         `json:"body" validate:"required" example:"Check out our new arrivals"`
+	Footer         string         `json:"footer,omitempty" example:"zpwoot store"`
+	Cards          []CarouselCard `json:"cards" validate:"required,min=1,max=10"`
+	FallbackToText bool           `json:"fallbackToText,omitempty" example:"false"`
+} //@name CarouselMessageRequest
+
+type CarouselCard struct {
+	Title   string        `json:"title,omitempty" example:"Sneakers"`
+	Body    string        `json:"body" validate:"required" example:"Now $49.90"`
+	Footer  string        `json:"footer,omitempty" example:"Free shipping"`
+	Media   CarouselMedia `json:"media" validate:"required"`
+	Buttons []FlowButton  `json:"buttons" validate:"required,min=1,max=2"`
+} //@name CarouselCard
+
+type CarouselMedia struct {
+	Type string `json:"type" validate:"required,oneof=image video" example:"image"` // The same for every card
+	// File is an http(s) URL or a base64 data URI (data:image/png;base64,...), up to MEDIA_MAX_SIZE_MB.
+	File     string `json:"file" validate:"required" example:"https://example.com/sneakers.jpg"`
+	MimeType string `json:"mimeType,omitempty" example:"image/jpeg"`
+} //@name CarouselMedia
+
+type CarouselMessageResponse struct {
+	ID         string    `json:"id" example:"3EB0C767D71D"`
+	Status     string    `json:"status" example:"sent"`
+	SentAsText bool      `json:"sentAsText" example:"false"` // The cards were sent as a text message
+	Timestamp  time.Time `json:"timestamp" example:"2024-01-01T12:00:00Z"`
+} //@name CarouselMessageResponse
+
+// TemplateMessageRequest sends a hydrated template: a header with a title or media, a body, a footer and
+// up to three quick reply, url or call buttons.
+type TemplateMessageRequest struct {
+	RemoteJID   string               `json:"remoteJid" validate:"required" example:"5511999999999@s.whatsapp.net"`
+	Title       string               `json:"title,omitempty" example:"Order #1234"` // Ignored when headerMedia is set
+	HeaderMedia *TemplateHeaderMedia `json:"headerMedia,omitempty"`
+	Body        string               `json:"body" validate:"required" example:"Your order has shipped"`
+	Footer      string               `json:"footer,omitempty" example:"zpwoot store"`
+	Buttons     []TemplateButton     `json:"buttons" validate:"required,min=1,max=3"`
+} //@name TemplateMessageRequest
+
+type TemplateHeaderMedia struct {
+	Type string `json:"type" validate:"required,oneof=image video document" example:"image"`
+	// File is an http(s) URL or a base64 data URI (data:image/png;base64,...), up to MEDIA_MAX_SIZE_MB.
+	File     string `json:"file" validate:"required" example:"https://example.com/image.jpg"`
+	MimeType string `json:"mimeType,omitempty" example:"image/jpeg"`
+	Filename string `json:"filename,omitempty" example:"invoice.pdf"` // Only used for document type
+} //@name TemplateHeaderMedia
+
+type TemplateButton struct {
+	Type        string `json:"type" validate:"required,oneof=quick_reply url call" example:"quick_reply"`
+	Text        string `json:"text" validate:"required" example:"Track order"`
+	ID          string `json:"id,omitempty" example:"track_order"`                      // quick_reply
+	URL         string `json:"url,omitempty" example:"https://example.com/orders/1234"` // url
+	PhoneNumber string `json:"phoneNumber,omitempty" example:"+5511999999999"`          // call
+} //@name TemplateButton
+
 type Section struct {
 	Title string `json:"title" example:"Available Services"`
 	Rows  []Row  `json:"rows" validate:"required,min=1,max=10"`
@@ -118,6 +246,8 @@ type Row struct {
 	Description string `json:"description" example:"Get help from our support team"`
 } //@name Row
 
+// MediaMessageRequest and the typed media requests take the media as an http(s) URL or a base64 data URI
+// in File; the mime type is detected from the content unless MimeType is given
 type MediaMessageRequest struct {
 	RemoteJID string `json:"remoteJid" validate:"required" example:"5511999999999@s.whatsapp.net"`
 	File      string `json:"file" validate:"required" example:"https://example.com/media.file"`
@@ -210,6 +340,11 @@ type ReactionMessageRequest struct {
 	RemoteJID string `json:"remoteJid" validate:"required" example:"5511999999999@s.whatsapp.net"`
 	MessageID string `json:"messageId" validate:"required" example:"3EB0C767D71D"`
 	Reaction  string `json:"reaction" validate:"required" example:"👍"`
+
+	// Participant is the sender of the original message, required for messages from other group members
+	Participant string `json:"participant,omitempty" example:"5511888888888@s.whatsapp.net"`
+	// FromMe indicates the original message was sent by this session
+	FromMe bool `json:"fromMe,omitempty" example:"false"`
 } //@name ReactionMessageRequest
 
 type PresenceMessageRequest struct {
@@ -235,18 +370,54 @@ type RevokeMessageRequest struct {
 	SessionID string `json:"sessionId" validate:"required" example:"mySession"`
 	RemoteJID string `json:"remoteJid" validate:"required" example:"5511999999999@s.whatsapp.net"`
 	MessageID string `json:"messageId" validate:"required" example:"3EB0C767D71D"`
+
+	// ForAll revokes the message for everyone (default); false deletes it only for this account
+	ForAll *bool `json:"forAll,omitempty" example:"true"`
+	// Participant is the sender of the original message, used for messages from other group members
+	Participant string `json:"participant,omitempty" example:"5511888888888@s.whatsapp.net"`
+	// FromMe indicates the original message was sent by this session (delete for me only)
+	FromMe bool `json:"fromMe,omitempty" example:"true"`
+	// MessageTimestamp is the unix timestamp of the original message (delete for me only)
+	MessageTimestamp int64 `json:"messageTimestamp,omitempty" example:"1704110400"`
+	// DeleteMedia also removes downloaded media from the device (delete for me only)
+	DeleteMedia bool `json:"deleteMedia,omitempty" example:"false"`
 } //@name RevokeMessageRequest
 
+// IsForAll reports whether the message should be revoked for everyone
+func (r *RevokeMessageRequest) IsForAll() bool {
+	return r.ForAll == nil || *r.ForAll
+}
+
 type RevokeMessageResponse struct {
 	ID        string    `json:"id" example:"3EB0C767D71D"`
 	Status    string    `json:"status" example:"revoked"`
 	Timestamp time.Time `json:"timestamp" example:"2024-01-01T12:00:00Z"`
 } //@name RevokeMessageResponse
 
+type PinMessageRequest struct {
+	SessionID string `json:"sessionId,omitempty" example:"mySession"`
+	RemoteJID string `json:"remoteJid" validate:"required" example:"120363025246125486@g.us"`
+	MessageID string `json:"messageId" validate:"required" example:"3EB0C767D71D"`
+	// Participant is the sender of the message in groups, unless it was sent by this session
+	Participant string `json:"participant,omitempty" example:"5511888888888@s.whatsapp.net"`
+	// FromMe indicates the message was sent by this session
+	FromMe bool `json:"fromMe,omitempty" example:"false"`
+	// Duration is how long the message stays pinned: 24h, 7d (default) or 30d; ignored when unpinning
+	Duration string `json:"duration,omitempty" validate:"omitempty,oneof=24h 7d 30d" example:"7d"`
+} //@name PinMessageRequest
+
+type PinMessageResponse struct {
+	ID        string     `json:"id" example:"3EB0C767D71D"`
+	Status    string     `json:"status" example:"pinned"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty" example:"2024-01-08T12:00:00Z"`
+	Timestamp time.Time  `json:"timestamp" example:"2024-01-01T12:00:00Z"`
+} //@name PinMessageResponse
+
 type MarkAsReadRequest struct {
 	SessionID  string   `json:"sessionId" validate:"required" example:"mySession"`
 	RemoteJID  string   `json:"remoteJid" validate:"required" example:"5511999999999@s.whatsapp.net"`
 	MessageIDs []string `json:"messageIds" validate:"required,min=1" example:"3EB0C767D71D,3EB0C767D71E"`
+	Sender     string   `json:"sender,omitempty" example:"5511888888888@s.whatsapp.net"`
 } //@name MarkAsReadRequest
 
 type MarkAsReadResponse struct {
@@ -255,6 +426,41 @@ type MarkAsReadResponse struct {
 	Timestamp  time.Time `json:"timestamp" example:"2024-01-01T12:00:00Z"`
 } //@name MarkAsReadResponse
 
+// MarkChatAsReadRequest marks every received message in a chat up to a message ID or timestamp as read
+type MarkChatAsReadRequest struct {
+	SessionID     string `json:"-"`
+	ChatJID       string `json:"-"`
+	UpToMessageID string `json:"upToMessageId,omitempty" example:"3EB0C767D71D"`
+	UpToTimestamp int64  `json:"upToTimestamp,omitempty" example:"1704110400"`
+} //@name MarkChatAsReadRequest
+
+type MarkChatAsReadResponse struct {
+	ChatJID    string    `json:"chatJid" example:"5511999999999@s.whatsapp.net"`
+	MessageIDs []string  `json:"messageIds" example:"3EB0C767D71D,3EB0C767D71E"`
+	Count      int       `json:"count" example:"2"`
+	Status     string    `json:"status" example:"read"`
+	Timestamp  time.Time `json:"timestamp" example:"2024-01-01T12:00:00Z"`
+} //@name MarkChatAsReadResponse
+
+// BackfillChatRequest requests messages older than the oldest stored message of a chat from the phone.
+// The before* fields give the anchor message when nothing is stored for the chat yet
+type BackfillChatRequest struct {
+	SessionID       string `json:"-"`
+	ChatJID         string `json:"-"`
+	Count           int    `json:"count,omitempty" example:"50"`
+	BeforeMessageID string `json:"beforeMessageId,omitempty" example:"3EB0C767D71D"`
+	BeforeFromMe    bool   `json:"beforeFromMe,omitempty" example:"false"`
+	BeforeTimestamp int64  `json:"beforeTimestamp,omitempty" example:"1704110400"`
+} //@name BackfillChatRequest
+
+type BackfillChatResponse struct {
+	ChatJID   string    `json:"chatJid" example:"5511999999999@s.whatsapp.net"`
+	Requested int       `json:"requested" example:"50"`
+	Received  int       `json:"received" example:"48"`
+	Added     int       `json:"added" example:"45"`
+	Timestamp time.Time `json:"timestamp" example:"2024-01-01T12:00:00Z"`
+} //@name BackfillChatResponse
+
 type MessageResponse struct {
 	ID        string    `json:"id" example:"3EB0C767D71D"`
 	Status    string    `json:"status" example:"sent"`
@@ -296,6 +502,10 @@ type TextMessageRequest struct {
 	RemoteJID   string       `json:"remoteJid" validate:"required" example:"5511987654321@s.whatsapp.net"`
 	Body        string       `json:"body" validate:"required" example:"Hello, this is a text message"`
 	ContextInfo *ContextInfo `json:"contextInfo,omitempty"`
+	// MentionAll mentions every participant of the group, the account must be a group admin
+	MentionAll bool `json:"mentionAll,omitempty" example:"false"`
+	// ScheduleAt sends the message at that time instead of now; it cannot be combined with mentionAll
+	ScheduleAt *time.Time `json:"scheduleAt,omitempty" example:"2024-01-01T18:00:00Z"`
 } //@name TextMessageRequest
 
 type ContextInfo struct {
@@ -307,42 +517,65 @@ type ContextInfo struct {
 
 // CreatePollRequest represents a request to create a poll
 type CreatePollRequest struct {
-	RemoteJID             string   `json:"remoteJid" validate:"required" example:"5511999999999@s.whatsapp.net"`
-	Name                  string   `json:"name" validate:"required,min=1,max=100" example:"What's your favorite color?"`
-	Options               []string `json:"options" validate:"required,min=2,max=12,dive,required,min=1,max=100" example:"Red,Blue,Green"`
-	SelectableOptionCount int      `json:"selectableOptionCount" validate:"min=1" example:"1"`
-	AllowMultipleAnswers  bool     `json:"allowMultipleAnswers" example:"false"`
+	RemoteJID             string     `json:"remoteJid" validate:"required" example:"5511999999999@s.whatsapp.net"`
+	Name                  string     `json:"name" validate:"required,min=1,max=100" example:"What's your favorite color?"`
+	Options               []string   `json:"options" validate:"required,min=2,max=12,dive,required,min=1,max=100" example:"Red,Blue,Green"`
+	SelectableOptionCount int        `json:"selectableOptionCount" validate:"min=0" example:"1"` // 0 uses the default: 1, or any number with allowMultipleAnswers
+	AllowMultipleAnswers  bool       `json:"allowMultipleAnswers" example:"false"`
+	ExpiresAt             *time.Time `json:"expiresAt,omitempty" example:"2024-01-01T18:00:00Z"` // Votes after this time are flagged as late
 } //@name CreatePollRequest
 
+// ResolveSelectableCount returns the selectable option count sent to WhatsApp, where 0 means any number
+func (r *CreatePollRequest) ResolveSelectableCount() (int, error) {
+	switch {
+	case r.SelectableOptionCount < 0:
+		return 0, fmt.Errorf("selectable count cannot be negative")
+	case r.SelectableOptionCount > len(r.Options):
+		return 0, fmt.Errorf("selectable count cannot exceed number of options")
+	case r.AllowMultipleAnswers && r.SelectableOptionCount == 1:
+		return 0, fmt.Errorf("allowMultipleAnswers requires a selectable count other than 1")
+	case r.SelectableOptionCount > 0:
+		return r.SelectableOptionCount, nil
+	case r.AllowMultipleAnswers:
+		return 0, nil
+	default:
+		return 1, nil
+	}
+}
+
 // CreatePollResponse represents the response after creating a poll
 type CreatePollResponse struct {
-	MessageID string    `json:"messageId" example:"3EB0C767D71D"`
-	PollName  string    `json:"pollName" example:"What's your favorite color?"`
-	Options   []string  `json:"options" example:"Red,Blue,Green"`
-	RemoteJID string    `json:"remoteJid" example:"5511999999999@s.whatsapp.net"`
-	Status    string    `json:"status" example:"sent"`
-	Timestamp time.Time `json:"timestamp" example:"2024-01-01T12:00:00Z"`
+	MessageID string   `json:"messageId" example:"3EB0C767D71D"`
+	PollName  string   `json:"pollName" example:"What's your favorite color?"`
+	Options   []string `json:"options" example:"Red,Blue,Green"`
+	// SelectableOptionCount is how many options a voter may pick; 0 allows any number
+	SelectableOptionCount int        `json:"selectableOptionCount" example:"1"`
+	AllowMultipleAnswers  bool       `json:"allowMultipleAnswers" example:"false"`
+	ExpiresAt             *time.Time `json:"expiresAt,omitempty" example:"2024-01-01T18:00:00Z"`
+	RemoteJID             string     `json:"remoteJid" example:"5511999999999@s.whatsapp.net"`
+	Status                string     `json:"status" example:"sent"`
+	Timestamp             time.Time  `json:"timestamp" example:"2024-01-01T12:00:00Z"`
 } //@name CreatePollResponse
 
-// VotePollRequest represents a request to vote in a poll
+// VotePollRequest represents a request to vote in a poll; the poll message ID comes from the path.
+// Options are the option text, or for polls sent by the session also the option hash; an empty
+// selection is not allowed, WhatsApp clients retract votes instead.
 type VotePollRequest struct {
 	RemoteJID       string   `json:"remoteJid" validate:"required" example:"5511999999999@s.whatsapp.net"`
-	PollMessageID   string   `json:"pollMessageId" validate:"required" example:"3EB0C767D71D"`
+	PollMessageID   string   `json:"-"`
 	SelectedOptions []string `json:"selectedOptions" validate:"required,min=1,dive,required" example:"Red"`
-} //@name VotePollRequest
 
-// VotePollResponse represents the response after voting in a poll
-type VotePollResponse struct {
-	PollMessageID   string    `json:"pollMessageId" example:"3EB0C767D71D"`
-	SelectedOptions []string  `json:"selectedOptions" example:"Red"`
-	RemoteJID       string    `json:"remoteJid" example:"5511999999999@s.whatsapp.net"`
-	Status          string    `json:"status" example:"sent"`
-	Timestamp       time.Time `json:"timestamp" example:"2024-01-01T12:00:00Z"`
-} //@name VotePollResponse
+	// Participant is the creator of the poll, required for polls from other group members
+	Participant string `json:"participant,omitempty" example:"5511888888888@s.whatsapp.net"`
+	// FromMe indicates the poll was sent by this session
+	FromMe bool `json:"fromMe,omitempty" example:"false"`
+} //@name VotePollRequest
 
 // GetPollResultsRequest represents a request to get poll results
 type GetPollResultsRequest struct {
-	RemoteJID     string `json:"remoteJid" validate:"required" example:"5511999999999@s.whatsapp.net"`
+	SessionID string `json:"-"`
+	// RemoteJID optionally checks the chat the poll was sent in
+	RemoteJID     string `json:"remoteJid,omitempty" example:"5511999999999@s.whatsapp.net"`
 	PollMessageID string `json:"pollMessageId" validate:"required" example:"3EB0C767D71D"`
 } //@name GetPollResultsRequest
 
@@ -363,14 +596,59 @@ type GetPollResultsResponse struct {
 	AllowMultipleAnswers  bool         `json:"allowMultipleAnswers" example:"false"`
 	CreatedAt             time.Time    `json:"createdAt" example:"2024-01-01T12:00:00Z"`
 	RemoteJID             string       `json:"remoteJid" example:"5511999999999@s.whatsapp.net"`
+	// LateVotes counts votes cast after ExpiresAt; they are not part of the option counts
+	LateVotes int        `json:"lateVotes" example:"0"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty" example:"2024-01-01T18:00:00Z"`
 } //@name GetPollResultsResponse
 
-// MarkReadRequest represents a request to mark a message as read
+// FromPollResults converts the aggregated votes of a poll; TotalVotes counts voters, not selected options
+func FromPollResults(results *poll.Results) *GetPollResultsResponse {
+	options := make([]PollOption, len(results.Tallies))
+	for i, tally := range results.Tallies {
+		options[i] = PollOption{
+			Name:      tally.Option,
+			VoteCount: tally.Votes,
+			Voters:    results.Voters[tally.Option],
+		}
+	}
+
+	return &GetPollResultsResponse{
+		PollMessageID:         results.Poll.MessageID,
+		PollName:              results.Poll.Name,
+		Options:               options,
+		TotalVotes:            results.TotalVoters,
+		SelectableOptionCount: results.Poll.SelectableCount,
+		AllowMultipleAnswers:  results.Poll.AllowsMultipleAnswers(),
+		CreatedAt:             results.Poll.CreatedAt,
+		RemoteJID:             results.Poll.ChatJID,
+		LateVotes:             results.LateVotes,
+		ExpiresAt:             results.Poll.ExpiresAt,
+	}
+}
+
+// MarkReadRequest represents a request to mark one or more messages as read
 type MarkReadRequest struct {
-	RemoteJID string `json:"remoteJid" validate:"required" example:"5511999999999@s.whatsapp.net"`
-	MessageID string `json:"messageId" validate:"required" example:"3EB0C431C26A1916E07E"`
+	RemoteJID  string   `json:"remoteJid" validate:"required" example:"5511999999999@s.whatsapp.net"`
+	MessageID  string   `json:"messageId,omitempty" example:"3EB0C431C26A1916E07E"`
+	MessageIDs []string `json:"messageIds,omitempty" example:"3EB0C431C26A1916E07E,3EB0C431C26A1916E07F"`
+	// Sender is the original sender for group messages; looked up from stored messages when omitted
+	Sender string `json:"sender,omitempty" example:"5511888888888@s.whatsapp.net"`
 } //@name MarkReadRequest
 
+// GetMessageIDs returns the single and batch message IDs combined
+func (r *MarkReadRequest) GetMessageIDs() []string {
+	ids := make([]string, 0, len(r.MessageIDs)+1)
+	if r.MessageID != "" {
+		ids = append(ids, r.MessageID)
+	}
+	for _, id := range r.MessageIDs {
+		if id != "" && id != r.MessageID {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
 // MarkReadResponse represents the response for marking a message as read
 type MarkReadResponse struct {
 	MessageID string    `json:"messageId" example:"3EB0C431C26A1916E07E"`
@@ -2,12 +2,28 @@ package message
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+
+	"zpwoot/internal/constants"
+	"zpwoot/internal/domain/draft"
+	"zpwoot/internal/domain/history"
 	"zpwoot/internal/domain/message"
+	"zpwoot/internal/domain/outbox"
+	"zpwoot/internal/domain/poll"
+	"zpwoot/internal/domain/queue"
+	"zpwoot/internal/domain/scheduled"
+	"zpwoot/internal/domain/translation"
+	"zpwoot/internal/domain/usage"
 	"zpwoot/internal/ports"
 	"zpwoot/platform/logger"
+	"zpwoot/platform/tracing"
 )
 
 type UseCase interface {
@@ -15,30 +31,113 @@ type UseCase interface {
 	GetPollResults(ctx context.Context, req *GetPollResultsRequest) (*GetPollResultsResponse, error)
 	RevokeMessage(ctx context.Context, req *RevokeMessageRequest) (*RevokeMessageResponse, error)
 	EditMessage(ctx context.Context, req *EditMessageRequest) (*EditMessageResponse, error)
+	// PinMessage pins a message in its chat, or unpins it when pin is false
+	PinMessage(ctx context.Context, req *PinMessageRequest, pin bool) (*PinMessageResponse, error)
 	MarkAsRead(ctx context.Context, req *MarkAsReadRequest) (*MarkAsReadResponse, error)
+	MarkChatAsRead(ctx context.Context, req *MarkChatAsReadRequest) (*MarkChatAsReadResponse, error)
+	BackfillChat(ctx context.Context, req *BackfillChatRequest) (*BackfillChatResponse, error)
+	// CheckSendQuota returns the quota error when the workspace may not send another message
+	CheckSendQuota(ctx context.Context) error
+	// AccountSend runs send, a send made outside SendMessage, under the workspace message quota: it is
+	// rejected when the quota is used up and counted once send succeeds
+	AccountSend(ctx context.Context, send func() error) error
+	// TranslateText applies the session's outbound translation to text sent outside SendMessage
+	TranslateText(ctx context.Context, sessionID, text string) (string, *MessageTranslation)
+	// ScheduleMessage stores a send given a scheduleAt until it is due
+	ScheduleMessage(ctx context.Context, sessionID string, req *SendMessageRequest) (*SendMessageResponse, error)
+	// QueueMessage stores a send of a disconnected session in the offline queue
+	QueueMessage(ctx context.Context, sessionID string, req *SendMessageRequest) (*SendMessageResponse, error)
+	// SendQueued sends a message of the offline queue once its session is connected again
+	SendQueued(ctx context.Context, sessionID string, req *message.SendMessageRequest) (*message.SendResult, error)
+	// CancelQueuedMessage cancels a message waiting in the offline queue or a scheduled draft
+	CancelQueuedMessage(ctx context.Context, sessionID string, queueID uuid.UUID) (*CancelQueuedMessageResponse, error)
+	// SaveUpload streams media uploaded with multipart/form-data to a temporary file so it can be sent
+	// as SendMessageRequest.Upload; the caller cleans it up
+	SaveUpload(r io.Reader, filename, mimeType string) (*message.ProcessedMedia, error)
+	// ProcessMedia downloads or decodes media given as a URL or data URI to a temporary file, validated
+	// for the message type; the caller cleans it up
+	ProcessMedia(ctx context.Context, file string, messageType message.MessageType) (*message.ProcessedMedia, error)
 }
 
+// maxChatReadMessages bounds how many stored messages are acknowledged by a single chat mark-read
+const maxChatReadMessages = 500
+
+// Backfill batch sizes; the phone answers on-demand history requests with at most a few hundred messages
+const (
+	defaultBackfillMessages = 50
+	maxBackfillMessages     = 200
+)
+
 type useCaseImpl struct {
 	sessionRepo    ports.SessionRepository
 	wameowManager  ports.WameowManager
 	mediaProcessor *message.MediaProcessor
+	usageService   *usage.Service
+	translator     *translation.Service
+	offlineQueue   *queue.Service
+	outbox         *outbox.Service
+	scheduler      *scheduled.Service
+	drafts         *draft.Service
+	polls          *poll.Service
+	history        *history.Service
 	logger         *logger.Logger
 }
 
 func NewUseCase(
 	sessionRepo ports.SessionRepository,
 	wameowManager ports.WameowManager,
+	usageService *usage.Service,
+	translator *translation.Service,
+	offlineQueue *queue.Service,
+	outboxService *outbox.Service,
+	scheduler *scheduled.Service,
+	drafts *draft.Service,
+	polls *poll.Service,
+	historyService *history.Service,
+	mediaLimits message.MediaLimits,
 	logger *logger.Logger,
 ) UseCase {
-	return &useCaseImpl{
+	uc := &useCaseImpl{
 		sessionRepo:    sessionRepo,
 		wameowManager:  wameowManager,
-		mediaProcessor: message.NewMediaProcessor(logger),
+		mediaProcessor: message.NewMediaProcessor(logger, mediaLimits),
+		usageService:   usageService,
+		translator:     translator,
+		offlineQueue:   offlineQueue,
+		outbox:         outboxService,
+		scheduler:      scheduler,
+		drafts:         drafts,
+		polls:          polls,
+		history:        historyService,
 		logger:         logger,
 	}
+
+	// Queued and scheduled messages go through the same media, translation and usage handling as direct sends
+	if offlineQueue != nil {
+		offlineQueue.SetSender(uc)
+	}
+	if scheduler != nil {
+		scheduler.SetSender(uc)
+	}
+
+	return uc
 }
 
-func (uc *useCaseImpl) SendMessage(ctx context.Context, sessionID string, req *SendMessageRequest) (*SendMessageResponse, error) {
+func (uc *useCaseImpl) SendMessage(ctx context.Context, sessionID string, req *SendMessageRequest) (response *SendMessageResponse, err error) {
+	ctx, span := tracing.Start(ctx, "message.SendMessage", tracing.KindInternal)
+	span.SetAttributes(
+		attribute.String("zpwoot.session", sessionID),
+		attribute.String("zpwoot.message.type", req.Type),
+	)
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	if req.ScheduleAt != nil {
+		return uc.ScheduleMessage(ctx, sessionID, req)
+	}
+
 	uc.logger.InfoWithFields("Sending message", map[string]interface{}{
 		"session_id": sessionID,
 		"to":         req.RemoteJID,
@@ -47,6 +146,10 @@ func (uc *useCaseImpl) SendMessage(ctx context.Context, sessionID string, req *S
 
 	// Validate session
 	if err := uc.validateSession(ctx, sessionID); err != nil {
+		// Uploads only live as long as the request, so they cannot wait in the queue
+		if errors.Is(err, queue.ErrSessionDisconnected) && uc.offlineQueue.Enabled() && req.Upload == nil {
+			return uc.QueueMessage(ctx, sessionID, req)
+		}
 		return nil, err
 	}
 
@@ -56,23 +159,13 @@ func (uc *useCaseImpl) SendMessage(ctx context.Context, sessionID string, req *S
 		return nil, fmt.Errorf("invalid request: %w", err)
 	}
 
-	// Process media if needed
-	filePath, cleanup, err := uc.processMediaIfNeeded(ctx, domainReq)
-	if err != nil {
+	if err := uc.usageService.CheckMessage(ctx, usage.WorkspaceFromContext(ctx)); err != nil {
 		return nil, err
 	}
-	defer uc.cleanupMedia(cleanup, filePath)
 
-	// Send message
-	result, err := uc.sendMessageToWameow(sessionID, domainReq, filePath)
+	result, translated, err := uc.deliver(ctx, sessionID, domainReq)
 	if err != nil {
-		uc.logger.ErrorWithFields("Failed to send message", map[string]interface{}{
-			"session_id": sessionID,
-			"to":         req.RemoteJID,
-			"type":       req.Type,
-			"error":      err.Error(),
-		})
-		return nil, fmt.Errorf("failed to send message: %w", err)
+		return nil, err
 	}
 
 	uc.logger.InfoWithFields("Message sent successfully", map[string]interface{}{
@@ -83,9 +176,240 @@ func (uc *useCaseImpl) SendMessage(ctx context.Context, sessionID string, req *S
 	})
 
 	return &SendMessageResponse{
-		ID:        result.MessageID,
-		Status:    result.Status,
-		Timestamp: result.Timestamp,
+		ID:          result.MessageID,
+		Status:      result.Status,
+		Timestamp:   result.Timestamp,
+		Translation: translated,
+	}, nil
+}
+
+// deliver processes the media, translates and sends a validated message, and records its usage
+func (uc *useCaseImpl) deliver(ctx context.Context, sessionID string, domainReq *message.SendMessageRequest) (*message.SendResult, *MessageTranslation, error) {
+	workspace := usage.WorkspaceFromContext(ctx)
+
+	// Process media if needed
+	filePath, fileSize, cleanup, err := uc.processMediaIfNeeded(ctx, domainReq)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer uc.cleanupMedia(cleanup, filePath)
+
+	if err := uc.usageService.CheckMedia(ctx, workspace, fileSize); err != nil {
+		return nil, nil, err
+	}
+
+	translated := uc.translateOutgoing(ctx, sessionID, domainReq)
+
+	result, err := uc.sendMessageToWameow(ctx, sessionID, domainReq, filePath)
+	if err != nil {
+		uc.logger.ErrorWithFields("Failed to send message", map[string]interface{}{
+			"session_id": sessionID,
+			"to":         domainReq.To,
+			"type":       domainReq.Type,
+			"error":      err.Error(),
+		})
+		return nil, nil, fmt.Errorf("failed to send message: %w", err)
+	}
+
+	uc.usageService.RecordMessage(ctx, workspace)
+	uc.usageService.RecordMedia(ctx, workspace, fileSize)
+	uc.wameowManager.TraceRequest(ctx, sessionID, result.MessageID)
+
+	return result, translated, nil
+}
+
+// QueueMessage validates the message and stores it until the session reconnects; the quota is
+// checked now and the message counted once it is sent
+func (uc *useCaseImpl) QueueMessage(ctx context.Context, sessionID string, req *SendMessageRequest) (*SendMessageResponse, error) {
+	if req.Upload != nil {
+		return nil, fmt.Errorf("%w: uploaded media cannot be queued", message.ErrInvalidMedia)
+	}
+
+	domainReq := req.ToDomainRequest()
+	if err := message.ValidateMessageRequest(domainReq); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	if err := uc.usageService.CheckMessage(ctx, usage.WorkspaceFromContext(ctx)); err != nil {
+		return nil, err
+	}
+
+	queued, err := uc.offlineQueue.Enqueue(ctx, sessionID, domainReq)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := queued.ExpiresAt
+	return &SendMessageResponse{
+		Status:    queue.StatusQueued,
+		Timestamp: queued.CreatedAt,
+		QueueID:   queued.ID.String(),
+		ExpiresAt: &expiresAt,
+	}, nil
+}
+
+// ScheduleMessage validates the message and stores it until req.ScheduleAt; like queued messages, the
+// quota is checked now and the message counted once it is sent
+func (uc *useCaseImpl) ScheduleMessage(ctx context.Context, sessionID string, req *SendMessageRequest) (*SendMessageResponse, error) {
+	if req.ScheduleAt == nil {
+		return nil, fmt.Errorf("invalid request: scheduleAt is required")
+	}
+	if req.Upload != nil {
+		return nil, fmt.Errorf("%w: uploaded media cannot be scheduled", message.ErrInvalidMedia)
+	}
+
+	domainReq := req.ToDomainRequest()
+	if err := message.ValidateMessageRequest(domainReq); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	if err := uc.usageService.CheckMessage(ctx, usage.WorkspaceFromContext(ctx)); err != nil {
+		return nil, err
+	}
+
+	msg, err := uc.scheduler.Schedule(ctx, sessionID, domainReq, *req.ScheduleAt)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduledAt := msg.ScheduledAt
+	return &SendMessageResponse{
+		Status:      scheduled.StatusScheduled,
+		Timestamp:   msg.CreatedAt,
+		ScheduledID: msg.ID.String(),
+		ScheduledAt: &scheduledAt,
+	}, nil
+}
+
+func (uc *useCaseImpl) SendQueued(ctx context.Context, sessionID string, req *message.SendMessageRequest) (*message.SendResult, error) {
+	if !uc.wameowManager.IsConnected(sessionID) {
+		return nil, queue.ErrSessionDisconnected
+	}
+
+	result, _, err := uc.deliver(ctx, sessionID, req)
+	if err != nil {
+		if strings.Contains(err.Error(), "not connected") {
+			return nil, queue.ErrSessionDisconnected
+		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// translateOutgoing replaces the body and caption with their translation when the session translates
+// outgoing messages; the message is sent as written when the provider fails
+func (uc *useCaseImpl) translateOutgoing(ctx context.Context, sessionID string, domainReq *message.SendMessageRequest) *MessageTranslation {
+	var translated *MessageTranslation
+
+	body, bodyInfo := uc.TranslateText(ctx, sessionID, domainReq.Body)
+	if bodyInfo != nil {
+		domainReq.Body = body
+		translated = bodyInfo
+	}
+
+	caption, captionInfo := uc.TranslateText(ctx, sessionID, domainReq.Caption)
+	if captionInfo != nil {
+		domainReq.Caption = caption
+		if translated == nil {
+			translated = &MessageTranslation{
+				SourceLanguage: captionInfo.SourceLanguage,
+				TargetLanguage: captionInfo.TargetLanguage,
+			}
+		}
+		translated.OriginalCaption = captionInfo.OriginalText
+	}
+
+	return translated
+}
+
+func (uc *useCaseImpl) TranslateText(ctx context.Context, sessionID, text string) (string, *MessageTranslation) {
+	if uc.translator == nil || text == "" {
+		return text, nil
+	}
+
+	result, err := uc.translator.TranslateOutbound(ctx, sessionID, text)
+	if err != nil {
+		uc.logger.WarnWithFields("Failed to translate outgoing message, sending original text", map[string]interface{}{
+			"session_id": sessionID,
+			"error":      err.Error(),
+		})
+		return text, nil
+	}
+	if result == nil {
+		return text, nil
+	}
+
+	return result.Text, &MessageTranslation{
+		SourceLanguage: result.SourceLanguage,
+		TargetLanguage: result.TargetLanguage,
+		OriginalText:   result.OriginalText,
+	}
+}
+
+// CancelQueuedMessage looks the ID up in the offline queue, then in the outbox and among the scheduled
+// messages and drafts;
+// a message already picked up for sending is reported with cancelled false
+func (uc *useCaseImpl) CancelQueuedMessage(ctx context.Context, sessionID string, queueID uuid.UUID) (*CancelQueuedMessageResponse, error) {
+	queued, cancelled, err := uc.offlineQueue.Cancel(ctx, sessionID, queueID)
+	if err == nil {
+		return &CancelQueuedMessageResponse{
+			QueueID:   queueID.String(),
+			Source:    CancelSourceOfflineQueue,
+			Cancelled: cancelled,
+			Status:    queued.Status,
+			MessageID: queued.MessageID,
+		}, nil
+	}
+	if !errors.Is(err, queue.ErrQueuedMessageNotFound) {
+		return nil, err
+	}
+
+	outboxMsg, cancelled, err := uc.outbox.Cancel(ctx, sessionID, queueID)
+	if err == nil {
+		return &CancelQueuedMessageResponse{
+			QueueID:   queueID.String(),
+			Source:    CancelSourceOutbox,
+			Cancelled: cancelled,
+			Status:    outboxMsg.Status,
+			MessageID: outboxMsg.MessageID,
+		}, nil
+	}
+	if !errors.Is(err, outbox.ErrOutboxMessageNotFound) {
+		return nil, err
+	}
+
+	scheduledMsg, cancelled, err := uc.scheduler.Cancel(ctx, sessionID, queueID)
+	if err == nil {
+		return &CancelQueuedMessageResponse{
+			QueueID:   queueID.String(),
+			Source:    CancelSourceScheduler,
+			Cancelled: cancelled,
+			Status:    scheduledMsg.Status,
+			MessageID: scheduledMsg.MessageID,
+		}, nil
+	}
+	if !errors.Is(err, scheduled.ErrScheduledMessageNotFound) {
+		return nil, err
+	}
+
+	scheduledDraft, cancelled, err := uc.drafts.CancelScheduled(ctx, sessionID, queueID)
+	if err != nil {
+		if errors.Is(err, draft.ErrDraftNotFound) {
+			return nil, queue.ErrQueuedMessageNotFound
+		}
+		return nil, err
+	}
+
+	status := scheduledDraft.Status
+	if cancelled {
+		status = queue.StatusCancelled
+	}
+	return &CancelQueuedMessageResponse{
+		QueueID:   queueID.String(),
+		Source:    CancelSourceScheduler,
+		Cancelled: cancelled,
+		Status:    status,
 	}, nil
 }
 
@@ -101,21 +425,25 @@ func (uc *useCaseImpl) validateSession(ctx context.Context, sessionID string) er
 	}
 
 	if !sess.IsConnected {
-		return fmt.Errorf("session is not connected")
+		return queue.ErrSessionDisconnected
 	}
 
 	return nil
 }
 
-// processMediaIfNeeded processes media files if the message contains media
-func (uc *useCaseImpl) processMediaIfNeeded(ctx context.Context, domainReq *message.SendMessageRequest) (string, func() error, error) {
+// processMediaIfNeeded processes media files if the message contains media and returns the file path and size
+func (uc *useCaseImpl) processMediaIfNeeded(ctx context.Context, domainReq *message.SendMessageRequest) (string, int64, func() error, error) {
+	if domainReq.IsMediaMessage() && domainReq.Upload != nil {
+		return uc.processUpload(domainReq)
+	}
+
 	if !domainReq.IsMediaMessage() || domainReq.File == "" {
-		return "", nil, nil
+		return "", 0, nil, nil
 	}
 
 	processedMedia, err := uc.mediaProcessor.ProcessMediaForType(ctx, domainReq.File, domainReq.Type)
 	if err != nil {
-		return "", nil, fmt.Errorf("failed to process media: %w", err)
+		return "", 0, nil, fmt.Errorf("failed to process media: %w", err)
 	}
 
 	// Set default values if not provided
@@ -127,7 +455,51 @@ func (uc *useCaseImpl) processMediaIfNeeded(ctx context.Context, domainReq *mess
 		domainReq.Filename = "document"
 	}
 
-	return processedMedia.FilePath, processedMedia.Cleanup, nil
+	return processedMedia.FilePath, processedMedia.FileSize, processedMedia.Cleanup, nil
+}
+
+// processUpload validates media saved by SaveUpload; the upload is cleaned up by its owner, not after the send
+func (uc *useCaseImpl) processUpload(domainReq *message.SendMessageRequest) (string, int64, func() error, error) {
+	upload := domainReq.Upload
+	if err := uc.mediaProcessor.ValidateUpload(upload, domainReq.Type); err != nil {
+		return "", 0, nil, fmt.Errorf("failed to process media: %w", err)
+	}
+
+	if domainReq.MimeType == "" {
+		domainReq.MimeType = upload.MimeType
+	}
+
+	if domainReq.Type == message.MessageTypeDocument && domainReq.Filename == "" {
+		domainReq.Filename = "document"
+	}
+
+	return upload.FilePath, upload.FileSize, nil, nil
+}
+
+func (uc *useCaseImpl) SaveUpload(r io.Reader, filename, mimeType string) (*message.ProcessedMedia, error) {
+	return uc.mediaProcessor.SaveUpload(r, mimeType, filename)
+}
+
+func (uc *useCaseImpl) ProcessMedia(ctx context.Context, file string, messageType message.MessageType) (*message.ProcessedMedia, error) {
+	return uc.mediaProcessor.ProcessMediaForType(ctx, file, messageType)
+}
+
+func (uc *useCaseImpl) CheckSendQuota(ctx context.Context) error {
+	return uc.usageService.CheckMessage(ctx, usage.WorkspaceFromContext(ctx))
+}
+
+func (uc *useCaseImpl) AccountSend(ctx context.Context, send func() error) error {
+	workspace := usage.WorkspaceFromContext(ctx)
+	if err := uc.usageService.CheckMessage(ctx, workspace); err != nil {
+		return err
+	}
+
+	if err := send(); err != nil {
+		return err
+	}
+
+	uc.usageService.RecordMessage(ctx, workspace)
+	return nil
 }
 
 // cleanupMedia cleans up temporary media files
@@ -143,7 +515,14 @@ func (uc *useCaseImpl) cleanupMedia(cleanup func() error, filePath string) {
 }
 
 // sendMessageToWameow sends the message via WameowManager
-func (uc *useCaseImpl) sendMessageToWameow(sessionID string, domainReq *message.SendMessageRequest, filePath string) (*message.SendResult, error) {
+func (uc *useCaseImpl) sendMessageToWameow(ctx context.Context, sessionID string, domainReq *message.SendMessageRequest, filePath string) (*message.SendResult, error) {
+	_, span := tracing.Start(ctx, "whatsmeow.SendMessage", tracing.KindClient)
+	span.SetAttributes(
+		attribute.String("zpwoot.session", sessionID),
+		attribute.String("zpwoot.message.type", string(domainReq.Type)),
+	)
+	defer span.End()
+
 	// Convert domain ContextInfo to message ContextInfo
 	var msgContextInfo *message.ContextInfo
 	if domainReq.ContextInfo != nil {
@@ -153,7 +532,7 @@ func (uc *useCaseImpl) sendMessageToWameow(sessionID string, domainReq *message.
 		}
 	}
 
-	return uc.wameowManager.SendMessage(
+	result, err := uc.wameowManager.SendMessage(
 		sessionID,
 		domainReq.To,
 		string(domainReq.Type),
@@ -161,12 +540,19 @@ func (uc *useCaseImpl) sendMessageToWameow(sessionID string, domainReq *message.
 		domainReq.Caption,
 		filePath,
 		domainReq.Filename,
+		domainReq.MimeType,
 		domainReq.Latitude,
 		domainReq.Longitude,
 		domainReq.ContactName,
 		domainReq.ContactPhone,
 		msgContextInfo,
 	)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+	span.SetAttributes(attribute.String("zpwoot.message.id", result.MessageID))
+	return result, nil
 }
 
 // GetPollResults retrieves poll results for a specific poll message
@@ -176,31 +562,56 @@ func (uc *useCaseImpl) GetPollResults(ctx context.Context, req *GetPollResultsRe
 		"poll_message_id": req.PollMessageID,
 	})
 
-	// Note: whatsmeow doesn't have a direct GetPollResults method
-	// Poll results are typically collected via events (DecryptPollVote)
-	// This is a placeholder implementation that would need to be enhanced
-	// with actual poll vote collection from events
-
-	return &GetPollResultsResponse{
-		PollMessageID:         req.PollMessageID,
-		PollName:              "Poll results not yet implemented",
-		Options:               []PollOption{},
-		TotalVotes:            0,
-		SelectableOptionCount: 1,
-		AllowMultipleAnswers:  false,
-		RemoteJID:             req.RemoteJID,
-	}, fmt.Errorf("poll results collection not yet implemented - requires event handling")
+	// Votes are decrypted from the poll update events and stored per poll as they arrive
+	results, err := uc.polls.GetResults(ctx, req.SessionID, req.PollMessageID)
+	if err != nil {
+		return nil, err
+	}
+	if req.RemoteJID != "" && !sameChat(req.RemoteJID, results.Poll.ChatJID) {
+		return nil, poll.ErrPollNotFound
+	}
+
+	return FromPollResults(results), nil
+}
+
+// sameChat reports whether a chat given by the caller, with or without its server, is the chat a poll was sent in
+func sameChat(remoteJID, chatJID string) bool {
+	if remoteJID == chatJID {
+		return true
+	}
+	user, _, _ := strings.Cut(chatJID, "@")
+	return !strings.Contains(remoteJID, "@") && strings.TrimPrefix(remoteJID, "+") == user
 }
 
 // RevokeMessage revokes a message using whatsmeow's RevokeMessage method
 func (uc *useCaseImpl) RevokeMessage(ctx context.Context, req *RevokeMessageRequest) (*RevokeMessageResponse, error) {
 	uc.logger.InfoWithFields("Revoking message", map[string]interface{}{
-		"to":         req.RemoteJID,
-		"message_id": req.MessageID,
+		"to":          req.RemoteJID,
+		"message_id":  req.MessageID,
+		"participant": req.Participant,
+		"for_all":     req.IsForAll(),
 	})
 
+	if !req.IsForAll() {
+		var timestamp time.Time
+		if req.MessageTimestamp > 0 {
+			timestamp = time.Unix(req.MessageTimestamp, 0)
+		}
+
+		result, err := uc.wameowManager.DeleteMessageForMe(req.SessionID, req.RemoteJID, req.MessageID, req.Participant, req.FromMe, timestamp, req.DeleteMedia)
+		if err != nil {
+			return nil, fmt.Errorf("failed to delete message for me: %w", err)
+		}
+
+		return &RevokeMessageResponse{
+			ID:        result.MessageID,
+			Status:    result.Status,
+			Timestamp: result.Timestamp,
+		}, nil
+	}
+
 	// Use whatsmeow's RevokeMessage method
-	result, err := uc.wameowManager.RevokeMessage(req.SessionID, req.RemoteJID, req.MessageID)
+	result, err := uc.wameowManager.RevokeMessage(req.SessionID, req.RemoteJID, req.MessageID, req.Participant)
 	if err != nil {
 		return nil, fmt.Errorf("failed to revoke message: %w", err)
 	}
@@ -221,38 +632,79 @@ func (uc *useCaseImpl) EditMessage(ctx context.Context, req *EditMessageRequest)
 	})
 
 	// Use whatsmeow's BuildEdit method
-	err := uc.wameowManager.EditMessage(req.SessionID, req.RemoteJID, req.MessageID, req.NewBody)
+	result, err := uc.wameowManager.EditMessage(req.SessionID, req.RemoteJID, req.MessageID, req.NewBody)
 	if err != nil {
 		return nil, fmt.Errorf("failed to edit message: %w", err)
 	}
 
 	return &EditMessageResponse{
-		ID:        req.MessageID,
-		Status:    "edited",
+		ID:        result.MessageID,
+		Status:    result.Status,
 		NewBody:   req.NewBody,
-		Timestamp: time.Now(),
+		Timestamp: result.Timestamp,
 	}, nil
 }
 
+// PinMessage pins a message for one of the durations WhatsApp offers, or unpins it
+func (uc *useCaseImpl) PinMessage(ctx context.Context, req *PinMessageRequest, pin bool) (*PinMessageResponse, error) {
+	var duration time.Duration
+	if pin {
+		var err error
+		if duration, err = message.ParsePinDuration(req.Duration); err != nil {
+			return nil, err
+		}
+	}
+
+	uc.logger.InfoWithFields("Pinning message", map[string]interface{}{
+		"to":          req.RemoteJID,
+		"message_id":  req.MessageID,
+		"participant": req.Participant,
+		"pin":         pin,
+		"duration":    duration.String(),
+	})
+
+	result, err := uc.wameowManager.PinMessage(req.SessionID, req.RemoteJID, req.MessageID, req.Participant, req.FromMe, pin, duration)
+	if err != nil {
+		if pin {
+			return nil, fmt.Errorf("failed to pin message: %w", err)
+		}
+		return nil, fmt.Errorf("failed to unpin message: %w", err)
+	}
+
+	response := &PinMessageResponse{
+		ID:        result.MessageID,
+		Status:    result.Status,
+		Timestamp: result.Timestamp,
+	}
+	if pin {
+		expiresAt := result.Timestamp.Add(duration)
+		response.ExpiresAt = &expiresAt
+	}
+
+	return response, nil
+}
+
 // MarkAsRead marks messages as read using whatsmeow's MarkRead method
 func (uc *useCaseImpl) MarkAsRead(ctx context.Context, req *MarkAsReadRequest) (*MarkAsReadResponse, error) {
 	uc.logger.InfoWithFields("Marking messages as read", map[string]interface{}{
 		"to":          req.RemoteJID,
 		"message_ids": req.MessageIDs,
+		"sender":      req.Sender,
 	})
 
-	// Use whatsmeow's MarkRead method (currently supports single message)
-	// For multiple messages, we'll mark each one individually
-	for _, messageID := range req.MessageIDs {
-		err := uc.wameowManager.MarkRead(req.SessionID, req.RemoteJID, messageID)
-		if err != nil {
-			uc.logger.WarnWithFields("Failed to mark message as read", map[string]interface{}{
-				"session_id": req.SessionID,
-				"to":         req.RemoteJID,
-				"message_id": messageID,
-				"error":      err.Error(),
-			})
-			// Continue with other messages even if one fails
+	if len(req.MessageIDs) == 0 {
+		return nil, fmt.Errorf("at least one message ID is required")
+	}
+
+	// A receipt carries a single participant, so group messages are batched per sender
+	batches, senders, err := uc.groupMessagesBySender(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sender := range senders {
+		if err := uc.wameowManager.MarkRead(req.SessionID, req.RemoteJID, batches[sender], sender); err != nil {
+			return nil, fmt.Errorf("failed to mark messages as read: %w", err)
 		}
 	}
 
@@ -262,3 +714,180 @@ func (uc *useCaseImpl) MarkAsRead(ctx context.Context, req *MarkAsReadRequest) (
 		Timestamp:  time.Now(),
 	}, nil
 }
+
+// MarkChatAsRead marks every message of the message history received in a chat up to a message ID or
+// timestamp as read, sending one receipt per sender instead of one per message
+func (uc *useCaseImpl) MarkChatAsRead(ctx context.Context, req *MarkChatAsReadRequest) (*MarkChatAsReadResponse, error) {
+	uc.logger.InfoWithFields("Marking chat as read", map[string]interface{}{
+		"session_id":       req.SessionID,
+		"chat_jid":         req.ChatJID,
+		"up_to_message_id": req.UpToMessageID,
+		"up_to_timestamp":  req.UpToTimestamp,
+	})
+
+	if uc.history == nil {
+		return nil, fmt.Errorf("message history not available")
+	}
+
+	until := time.Now()
+	switch {
+	case req.UpToMessageID != "":
+		stored, err := uc.history.GetMessage(ctx, req.SessionID, req.UpToMessageID)
+		if errors.Is(err, history.ErrMessageNotFound) {
+			return nil, fmt.Errorf("message %s not found", req.UpToMessageID)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get message %s: %w", req.UpToMessageID, err)
+		}
+		until = stored.Timestamp
+	case req.UpToTimestamp > 0:
+		until = time.Unix(req.UpToTimestamp, 0)
+	}
+
+	messages, err := uc.history.ReceivedChatMessages(ctx, req.SessionID, req.ChatJID, until, maxChatReadMessages)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chat messages: %w", err)
+	}
+
+	batches := make(map[string][]string)
+	senders := make([]string, 0, 1)
+	messageIDs := make([]string, 0, len(messages))
+	for _, msg := range messages {
+		if _, exists := batches[msg.SenderJID]; !exists {
+			senders = append(senders, msg.SenderJID)
+		}
+		batches[msg.SenderJID] = append(batches[msg.SenderJID], msg.MessageID)
+		messageIDs = append(messageIDs, msg.MessageID)
+	}
+
+	for _, sender := range senders {
+		if err := uc.wameowManager.MarkRead(req.SessionID, req.ChatJID, batches[sender], sender); err != nil {
+			return nil, fmt.Errorf("failed to mark messages as read: %w", err)
+		}
+	}
+
+	return &MarkChatAsReadResponse{
+		ChatJID:    req.ChatJID,
+		MessageIDs: messageIDs,
+		Count:      len(messageIDs),
+		Status:     "read",
+		Timestamp:  time.Now(),
+	}, nil
+}
+
+// BackfillChat fetches messages older than the oldest message of a chat in the message history from the
+// phone and stores the ones not seen before
+func (uc *useCaseImpl) BackfillChat(ctx context.Context, req *BackfillChatRequest) (*BackfillChatResponse, error) {
+	uc.logger.InfoWithFields("Backfilling chat history", map[string]interface{}{
+		"session_id": req.SessionID,
+		"chat_jid":   req.ChatJID,
+		"count":      req.Count,
+	})
+
+	if uc.history == nil {
+		return nil, fmt.Errorf("message history not available")
+	}
+
+	count := req.Count
+	if count <= 0 {
+		count = defaultBackfillMessages
+	}
+	if count > maxBackfillMessages {
+		count = maxBackfillMessages
+	}
+
+	oldestID, oldestFromMe, oldestAt := req.BeforeMessageID, req.BeforeFromMe, time.Unix(req.BeforeTimestamp, 0)
+	if oldestID == "" {
+		oldest, err := uc.history.OldestChatMessage(ctx, req.SessionID, req.ChatJID)
+		if errors.Is(err, history.ErrMessageNotFound) {
+			return nil, fmt.Errorf("no stored messages in chat %s: beforeMessageId and beforeTimestamp are required", req.ChatJID)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the oldest message of chat %s: %w", req.ChatJID, err)
+		}
+		oldestID, oldestFromMe, oldestAt = oldest.MessageID, oldest.FromMe, oldest.Timestamp
+	} else if req.BeforeTimestamp <= 0 {
+		return nil, fmt.Errorf("beforeTimestamp is required with beforeMessageId")
+	}
+
+	fetched, err := uc.wameowManager.RequestHistoryBackfill(req.SessionID, req.ChatJID, oldestID, oldestFromMe, oldestAt, count)
+	if err != nil {
+		return nil, fmt.Errorf("failed to backfill chat: %w", err)
+	}
+
+	added := 0
+	for _, msg := range fetched {
+		// Live events may have stored the message under its sender rather than the chat
+		_, err := uc.history.GetMessage(ctx, req.SessionID, msg.MessageID)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, history.ErrMessageNotFound) {
+			return nil, fmt.Errorf("failed to look up backfilled message %s: %w", msg.MessageID, err)
+		}
+
+		if err := uc.history.RecordMessage(ctx, msg); err != nil {
+			return nil, fmt.Errorf("failed to store backfilled message %s: %w", msg.MessageID, err)
+		}
+		added++
+	}
+
+	uc.logger.InfoWithFields("Chat history backfilled", map[string]interface{}{
+		"session_id": req.SessionID,
+		"chat_jid":   req.ChatJID,
+		"received":   len(fetched),
+		"added":      added,
+	})
+
+	return &BackfillChatResponse{
+		ChatJID:   req.ChatJID,
+		Requested: count,
+		Received:  len(fetched),
+		Added:     added,
+		Timestamp: time.Now(),
+	}, nil
+}
+
+// groupMessagesBySender groups message IDs by their sender, preserving the order senders were first seen.
+// Group messages without an explicit sender are looked up in the stored messages.
+func (uc *useCaseImpl) groupMessagesBySender(ctx context.Context, req *MarkAsReadRequest) (map[string][]string, []string, error) {
+	isGroup := strings.HasSuffix(req.RemoteJID, constants.GroupJIDSuffix)
+
+	batches := make(map[string][]string)
+	senders := make([]string, 0, 1)
+
+	for _, messageID := range req.MessageIDs {
+		sender := req.Sender
+		if sender == "" && isGroup {
+			stored, err := uc.lookupStoredMessage(ctx, req.SessionID, messageID)
+			if err != nil {
+				return nil, nil, fmt.Errorf("sender is required for group message %s", messageID)
+			}
+			sender = stored.SenderJID
+		}
+
+		if _, exists := batches[sender]; !exists {
+			senders = append(senders, sender)
+		}
+		batches[sender] = append(batches[sender], messageID)
+	}
+
+	return batches, senders, nil
+}
+
+// lookupStoredMessage returns a message of the message history by its WhatsApp ID
+func (uc *useCaseImpl) lookupStoredMessage(ctx context.Context, sessionID, messageID string) (*history.Message, error) {
+	if uc.history == nil {
+		return nil, fmt.Errorf("message history not available")
+	}
+
+	stored, err := uc.history.GetMessage(ctx, sessionID, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if stored.SenderJID == "" {
+		return nil, fmt.Errorf("message %s has no sender", messageID)
+	}
+
+	return stored, nil
+}
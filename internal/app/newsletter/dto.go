@@ -33,6 +33,20 @@ type GetNewsletterInfoWithInviteRequest struct {
 	InviteKey string `json:"inviteKey" validate:"required"`
 }
 
+// FollowNewsletterWithInviteRequest - Request para seguir newsletter via convite
+type FollowNewsletterWithInviteRequest struct {
+	InviteKey string `json:"inviteKey" validate:"required" example:"https://whatsapp.com/channel/0029Va4K0PZ5a245NkngBA2M"`
+}
+
+// FollowNewsletterWithInviteResponse - Response do follow via convite com o estado da inscrição
+type FollowNewsletterWithInviteResponse struct {
+	Newsletter       NewsletterInfoResponse `json:"newsletter"`
+	AlreadyFollowing bool                   `json:"alreadyFollowing"`
+	Status           string                 `json:"status"`
+	Message          string                 `json:"message"`
+	Timestamp        time.Time              `json:"timestamp"`
+}
+
 // NewsletterInfoResponse - Response com informações do newsletter
 type NewsletterInfoResponse struct {
 	ID                string              `json:"id"`
@@ -80,10 +94,24 @@ func (r *UnfollowNewsletterRequest) GetNewsletterJID() string {
 	return r.NewsletterJID
 }
 
+// GetSubscribedNewslettersRequest - Filtros e paginação da listagem de newsletters seguidos
+type GetSubscribedNewslettersRequest struct {
+	Muted   *bool  `json:"muted,omitempty" example:"false"`
+	Role    string `json:"role,omitempty" example:"owner"`
+	Limit   int    `json:"limit" validate:"min=1,max=100" example:"50"`
+	Offset  int    `json:"offset" validate:"min=0" example:"0"`
+	Refresh bool   `json:"refresh" example:"false"` // Ignore the cache and fetch from WhatsApp
+}
+
 // SubscribedNewslettersResponse - Response com newsletters seguidos
 type SubscribedNewslettersResponse struct {
 	Newsletters []NewsletterInfoResponse `json:"newsletters"`
 	Total       int                      `json:"total"`
+	Limit       int                      `json:"limit"`
+	Offset      int                      `json:"offset"`
+	HasMore     bool                     `json:"hasMore"`
+	Cached      bool                     `json:"cached"`
+	FetchedAt   time.Time                `json:"fetchedAt"`
 }
 
 // NewsletterActionResponse - Response genérica para ações
@@ -208,6 +236,37 @@ func (req *UnfollowNewsletterRequest) Validate() error {
 	return domainReq.Validate()
 }
 
+// Validate validates the FollowNewsletterWithInviteRequest
+func (req *FollowNewsletterWithInviteRequest) Validate() error {
+	domainReq := &newsletter.GetNewsletterInfoWithInviteRequest{InviteKey: req.InviteKey}
+	return domainReq.Validate()
+}
+
+// Validate validates the GetSubscribedNewslettersRequest
+func (req *GetSubscribedNewslettersRequest) Validate() error {
+	if req.Role != "" && !newsletter.IsValidNewsletterRole(req.Role) {
+		return fmt.Errorf("%w: %s", newsletter.ErrInvalidNewsletterRole, req.Role)
+	}
+	if req.Limit < 1 || req.Limit > 100 {
+		return fmt.Errorf("limit must be between 1 and 100")
+	}
+	if req.Offset < 0 {
+		return fmt.Errorf("offset cannot be negative")
+	}
+	return nil
+}
+
+// Matches reports whether a newsletter passes the request filters
+func (req *GetSubscribedNewslettersRequest) Matches(info *newsletter.NewsletterInfo) bool {
+	if req.Muted != nil && info.Muted != *req.Muted {
+		return false
+	}
+	if req.Role != "" && string(info.Role) != req.Role {
+		return false
+	}
+	return true
+}
+
 // Helper functions
 
 // NewCreateNewsletterResponse creates a new CreateNewsletterResponse from domain data
@@ -230,12 +289,32 @@ func NewNewsletterInfoResponse(info *newsletter.NewsletterInfo) *NewsletterInfoR
 	return resp
 }
 
-// NewSubscribedNewslettersResponse creates a new SubscribedNewslettersResponse
-func NewSubscribedNewslettersResponse(infos []*newsletter.NewsletterInfo) *SubscribedNewslettersResponse {
-	newsletters := FromDomainList(infos)
+// NewSubscribedNewslettersResponse filters and paginates subscribed newsletters into a response
+func NewSubscribedNewslettersResponse(infos []*newsletter.NewsletterInfo, req *GetSubscribedNewslettersRequest, cached bool, fetchedAt time.Time) *SubscribedNewslettersResponse {
+	matching := make([]*newsletter.NewsletterInfo, 0, len(infos))
+	for _, info := range infos {
+		if req.Matches(info) {
+			matching = append(matching, info)
+		}
+	}
+
+	start := req.Offset
+	if start > len(matching) {
+		start = len(matching)
+	}
+	end := start + req.Limit
+	if end > len(matching) {
+		end = len(matching)
+	}
+
 	return &SubscribedNewslettersResponse{
-		Newsletters: newsletters,
-		Total:       len(newsletters),
+		Newsletters: FromDomainList(matching[start:end]),
+		Total:       len(matching),
+		Limit:       req.Limit,
+		Offset:      req.Offset,
+		HasMore:     end < len(matching),
+		Cached:      cached,
+		FetchedAt:   fetchedAt,
 	}
 }
 
@@ -256,6 +335,22 @@ func NewSuccessFollowResponse(jid string) *NewsletterActionResponse {
 	return NewNewsletterActionResponse(jid, "success", "Newsletter followed successfully")
 }
 
+// NewFollowNewsletterWithInviteResponse creates the response of a follow via invite from the resulting newsletter state
+func NewFollowNewsletterWithInviteResponse(info *newsletter.NewsletterInfo, alreadyFollowing bool) *FollowNewsletterWithInviteResponse {
+	message := "Newsletter followed successfully"
+	if alreadyFollowing {
+		message = "Newsletter was already followed"
+	}
+
+	return &FollowNewsletterWithInviteResponse{
+		Newsletter:       *NewNewsletterInfoResponse(info),
+		AlreadyFollowing: alreadyFollowing,
+		Status:           "success",
+		Message:          message,
+		Timestamp:        time.Now(),
+	}
+}
+
 // NewSuccessUnfollowResponse creates a success response for unfollow action
 func NewSuccessUnfollowResponse(jid string) *NewsletterActionResponse {
 	return NewNewsletterActionResponse(jid, "success", "Newsletter unfollowed successfully")
@@ -414,6 +509,93 @@ func (req *NewsletterSendReactionRequest) Validate() error {
 	return nil
 }
 
+// MaxBulkNewsletterItems bounds how many messages one bulk newsletter request may touch
+const MaxBulkNewsletterItems = 500
+
+// BulkNewsletterMarkViewedRequest represents the request for marking many newsletter messages as viewed
+type BulkNewsletterMarkViewedRequest struct {
+	NewsletterJID string   `json:"newsletterJid" validate:"required"`
+	ServerIDs     []string `json:"serverIds" validate:"required,min=1,max=500"`
+}
+
+// Validate validates the BulkNewsletterMarkViewedRequest
+func (req *BulkNewsletterMarkViewedRequest) Validate() error {
+	if req.NewsletterJID == "" {
+		return fmt.Errorf("newsletterJid is required")
+	}
+	if len(req.ServerIDs) == 0 {
+		return fmt.Errorf("serverIds is required and cannot be empty")
+	}
+	if len(req.ServerIDs) > MaxBulkNewsletterItems {
+		return fmt.Errorf("serverIds cannot have more than %d items", MaxBulkNewsletterItems)
+	}
+	return nil
+}
+
+// BulkNewsletterReactionItem is one message to react to in a bulk reaction request
+type BulkNewsletterReactionItem struct {
+	ServerID  string  `json:"serverId,omitempty"`  // Optional - will be looked up from MessageID if not provided
+	MessageID string  `json:"messageId,omitempty"` // Required when ServerID is not provided
+	Reaction  *string `json:"reaction,omitempty"`  // Overrides the request reaction for this message
+}
+
+// BulkNewsletterSendReactionRequest represents the request for reacting to many newsletter messages
+type BulkNewsletterSendReactionRequest struct {
+	NewsletterJID string                       `json:"newsletterJid" validate:"required"`
+	Reaction      string                       `json:"reaction"` // Empty string to remove reactions
+	Items         []BulkNewsletterReactionItem `json:"items" validate:"required,min=1,max=500"`
+}
+
+// Validate validates the BulkNewsletterSendReactionRequest
+func (req *BulkNewsletterSendReactionRequest) Validate() error {
+	if req.NewsletterJID == "" {
+		return fmt.Errorf("newsletterJid is required")
+	}
+	if len(req.Items) == 0 {
+		return fmt.Errorf("items is required and cannot be empty")
+	}
+	if len(req.Items) > MaxBulkNewsletterItems {
+		return fmt.Errorf("items cannot have more than %d entries", MaxBulkNewsletterItems)
+	}
+	return nil
+}
+
+// BulkNewsletterItemResult is the outcome of a bulk operation for one message
+type BulkNewsletterItemResult struct {
+	ServerID  string `json:"serverId,omitempty"`
+	MessageID string `json:"messageId,omitempty"`
+	Success   bool   `json:"success"`
+	Error     string `json:"error,omitempty"`
+}
+
+// BulkNewsletterOperationResponse represents the outcome of a bulk newsletter operation
+type BulkNewsletterOperationResponse struct {
+	NewsletterJID string                     `json:"newsletterJid"`
+	Total         int                        `json:"total"`
+	Succeeded     int                        `json:"succeeded"`
+	Failed        int                        `json:"failed"`
+	Results       []BulkNewsletterItemResult `json:"results"`
+	Timestamp     time.Time                  `json:"timestamp"`
+}
+
+// NewBulkNewsletterOperationResponse summarizes per-message results into a response
+func NewBulkNewsletterOperationResponse(jid string, results []BulkNewsletterItemResult) *BulkNewsletterOperationResponse {
+	resp := &BulkNewsletterOperationResponse{
+		NewsletterJID: jid,
+		Total:         len(results),
+		Results:       results,
+		Timestamp:     time.Now(),
+	}
+	for _, result := range results {
+		if result.Success {
+			resp.Succeeded++
+		} else {
+			resp.Failed++
+		}
+	}
+	return resp
+}
+
 // NewsletterToggleMuteRequest represents the request for toggling newsletter mute status
 type NewsletterToggleMuteRequest struct {
 	NewsletterJID string `json:"newsletterJid" validate:"required"`
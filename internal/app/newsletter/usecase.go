@@ -3,6 +3,9 @@ package newsletter
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"sync"
+	"time"
 
 	"zpwoot/internal/domain/newsletter"
 	"zpwoot/internal/domain/session"
@@ -24,11 +27,14 @@ type UseCase interface {
 	// FollowNewsletter follows a newsletter
 	FollowNewsletter(ctx context.Context, sessionID string, req *FollowNewsletterRequest) (*NewsletterActionResponse, error)
 
+	// FollowNewsletterWithInvite resolves an invite link or key and follows the newsletter in one call
+	FollowNewsletterWithInvite(ctx context.Context, sessionID string, req *FollowNewsletterWithInviteRequest) (*FollowNewsletterWithInviteResponse, error)
+
 	// UnfollowNewsletter unfollows a newsletter
 	UnfollowNewsletter(ctx context.Context, sessionID string, req *UnfollowNewsletterRequest) (*NewsletterActionResponse, error)
 
-	// GetSubscribedNewsletters gets all subscribed newsletters
-	GetSubscribedNewsletters(ctx context.Context, sessionID string) (*SubscribedNewslettersResponse, error)
+	// GetSubscribedNewsletters lists subscribed newsletters from the session cache, filtered and paginated
+	GetSubscribedNewsletters(ctx context.Context, sessionID string, req *GetSubscribedNewslettersRequest) (*SubscribedNewslettersResponse, error)
 
 	// GetNewsletterMessages gets messages from a newsletter
 	GetNewsletterMessages(ctx context.Context, sessionID string, req *GetNewsletterMessagesRequest) (*GetNewsletterMessagesResponse, error)
@@ -42,6 +48,12 @@ type UseCase interface {
 	// NewsletterSendReaction sends a reaction to a newsletter message
 	NewsletterSendReaction(ctx context.Context, sessionID string, req *NewsletterSendReactionRequest) (*NewsletterActionResponse, error)
 
+	// BulkNewsletterMarkViewed marks many newsletter messages as viewed, reporting the outcome per message
+	BulkNewsletterMarkViewed(ctx context.Context, sessionID string, req *BulkNewsletterMarkViewedRequest) (*BulkNewsletterOperationResponse, error)
+
+	// BulkNewsletterSendReaction reacts to many newsletter messages, reporting the outcome per message
+	BulkNewsletterSendReaction(ctx context.Context, sessionID string, req *BulkNewsletterSendReactionRequest) (*BulkNewsletterOperationResponse, error)
+
 	// NewsletterSubscribeLiveUpdates subscribes to live updates from a newsletter
 	NewsletterSubscribeLiveUpdates(ctx context.Context, sessionID string, req *NewsletterSubscribeLiveUpdatesRequest) (*NewsletterSubscribeLiveUpdatesResponse, error)
 
@@ -58,12 +70,30 @@ type UseCase interface {
 	UploadNewsletterReader(ctx context.Context, sessionID string, req *UploadNewsletterRequest) (*UploadNewsletterResponse, error)
 }
 
+// bulkMarkViewedBatchSize is how many server IDs are marked as viewed per WhatsApp request
+const bulkMarkViewedBatchSize = 100
+
+// bulkReactionLookupCount is how many recent messages are scanned to find server IDs of bulk reactions
+const bulkReactionLookupCount = 100
+
+// subscribedCacheTTL is how long subscribed newsletters are served from memory before WhatsApp is asked again
+const subscribedCacheTTL = 10 * time.Minute
+
+// subscribedNewsletters is the cached subscription list of one session
+type subscribedNewsletters struct {
+	infos     []*newsletter.NewsletterInfo
+	fetchedAt time.Time
+}
+
 // useCaseImpl implements the UseCase interface
 type useCaseImpl struct {
 	newsletterManager ports.NewsletterManager
 	newsletterService ports.NewsletterService
 	sessionRepo       ports.SessionRepository
 	logger            logger.Logger
+
+	subscribedMu    sync.Mutex
+	subscribedCache map[string]*subscribedNewsletters
 }
 
 // NewUseCase creates a new newsletter use case
@@ -78,6 +108,7 @@ func NewUseCase(
 		newsletterService: newsletterService,
 		sessionRepo:       sessionRepo,
 		logger:            logger,
+		subscribedCache:   make(map[string]*subscribedNewsletters),
 	}
 }
 
@@ -137,6 +168,8 @@ func (uc *useCaseImpl) CreateNewsletter(ctx context.Context, sessionID string, r
 		return nil, fmt.Errorf("failed to process newsletter info: %w", err)
 	}
 
+	uc.invalidateSubscribed(sessionID)
+
 	uc.logger.InfoWithFields("Newsletter created successfully", map[string]interface{}{
 		"session_id":    sessionID,
 		"newsletter_id": newsletterInfo.ID,
@@ -288,6 +321,8 @@ func (uc *useCaseImpl) newsletterActionGeneric(
 		return nil, fmt.Errorf("failed to %s newsletter: %w", actionName, err)
 	}
 
+	uc.invalidateSubscribed(sessionID)
+
 	uc.logger.InfoWithFields(fmt.Sprintf("Newsletter %s successfully", actionName), map[string]interface{}{
 		"session_id": sessionID,
 		"jid":        formattedJID,
@@ -413,6 +448,80 @@ func (uc *useCaseImpl) FollowNewsletter(ctx context.Context, sessionID string, r
 	return uc.newsletterActionGeneric(ctx, sessionID, req.NewsletterJID, "follow", uc.newsletterManager.FollowNewsletter, NewSuccessFollowResponse)
 }
 
+// FollowNewsletterWithInvite resolves an invite link or key and follows the newsletter in one call
+func (uc *useCaseImpl) FollowNewsletterWithInvite(ctx context.Context, sessionID string, req *FollowNewsletterWithInviteRequest) (*FollowNewsletterWithInviteResponse, error) {
+	// Validate request
+	if err := req.Validate(); err != nil {
+		uc.logger.ErrorWithFields("Invalid follow newsletter with invite request", map[string]interface{}{
+			"session_id": sessionID,
+			"invite_key": req.InviteKey,
+			"error":      err.Error(),
+		})
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	// Validate session and connection
+	if _, err := uc.validateSessionAndConnection(ctx, sessionID); err != nil {
+		return nil, err
+	}
+
+	inviteKey := uc.newsletterService.CleanInviteKey(req.InviteKey)
+
+	// Resolve the invite into the newsletter
+	info, err := uc.newsletterManager.GetNewsletterInfoWithInvite(ctx, sessionID, inviteKey)
+	if err != nil {
+		uc.logger.ErrorWithFields("Failed to resolve newsletter invite", map[string]interface{}{
+			"session_id": sessionID,
+			"invite_key": inviteKey,
+			"error":      err.Error(),
+		})
+		return nil, fmt.Errorf("failed to get newsletter info via invite_key: %w", err)
+	}
+
+	if err := uc.processNewsletterInfoCommon(ctx, sessionID, info); err != nil {
+		return nil, err
+	}
+
+	if info.IsFollowing() {
+		return NewFollowNewsletterWithInviteResponse(info, true), nil
+	}
+
+	uc.logger.InfoWithFields("Following newsletter via invite", map[string]interface{}{
+		"session_id": sessionID,
+		"jid":        info.ID,
+		"invite_key": inviteKey,
+	})
+
+	if err := uc.newsletterManager.FollowNewsletter(ctx, sessionID, info.ID); err != nil {
+		uc.logger.ErrorWithFields("Failed to follow newsletter via invite", map[string]interface{}{
+			"session_id": sessionID,
+			"jid":        info.ID,
+			"error":      err.Error(),
+		})
+		return nil, fmt.Errorf("failed to follow newsletter: %w", err)
+	}
+
+	uc.invalidateSubscribed(sessionID)
+
+	// Reload the newsletter so the response carries the subscription as WhatsApp sees it
+	followed, err := uc.newsletterManager.GetNewsletterInfo(ctx, sessionID, info.ID)
+	if err != nil || uc.newsletterService.ProcessNewsletterInfo(followed) != nil {
+		uc.logger.WarnWithFields("Failed to reload newsletter after follow", map[string]interface{}{
+			"session_id": sessionID,
+			"jid":        info.ID,
+		})
+		info.Role = newsletter.NewsletterRoleSubscriber
+		followed = info
+	}
+
+	uc.logger.InfoWithFields("Newsletter followed via invite successfully", map[string]interface{}{
+		"session_id": sessionID,
+		"jid":        followed.ID,
+	})
+
+	return NewFollowNewsletterWithInviteResponse(followed, false), nil
+}
+
 // UnfollowNewsletter unfollows a newsletter
 func (uc *useCaseImpl) UnfollowNewsletter(ctx context.Context, sessionID string, req *UnfollowNewsletterRequest) (*NewsletterActionResponse, error) {
 	// Validate request
@@ -429,8 +538,13 @@ func (uc *useCaseImpl) UnfollowNewsletter(ctx context.Context, sessionID string,
 	return uc.newsletterActionGeneric(ctx, sessionID, req.NewsletterJID, "unfollow", uc.newsletterManager.UnfollowNewsletter, NewSuccessUnfollowResponse)
 }
 
-// GetSubscribedNewsletters gets all subscribed newsletters
-func (uc *useCaseImpl) GetSubscribedNewsletters(ctx context.Context, sessionID string) (*SubscribedNewslettersResponse, error) {
+// GetSubscribedNewsletters lists subscribed newsletters from the session cache, filtered and paginated
+func (uc *useCaseImpl) GetSubscribedNewsletters(ctx context.Context, sessionID string, req *GetSubscribedNewslettersRequest) (*SubscribedNewslettersResponse, error) {
+	// Validate request
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
 	// Validate session
 	session, err := uc.sessionRepo.GetByID(ctx, sessionID)
 	if err != nil {
@@ -445,8 +559,15 @@ func (uc *useCaseImpl) GetSubscribedNewsletters(ctx context.Context, sessionID s
 		return nil, fmt.Errorf("session is not connected")
 	}
 
+	if !req.Refresh {
+		if cached := uc.cachedSubscribed(sessionID); cached != nil {
+			return NewSubscribedNewslettersResponse(cached.infos, req, true, cached.fetchedAt), nil
+		}
+	}
+
 	uc.logger.InfoWithFields("Getting subscribed newsletters", map[string]interface{}{
 		"session_id": sessionID,
+		"refresh":    req.Refresh,
 	})
 
 	// Get subscribed newsletters via WhatsApp
@@ -471,12 +592,56 @@ func (uc *useCaseImpl) GetSubscribedNewsletters(ctx context.Context, sessionID s
 		}
 	}
 
+	fetched := uc.storeSubscribed(sessionID, newsletters)
+
 	uc.logger.InfoWithFields("Subscribed newsletters retrieved successfully", map[string]interface{}{
 		"session_id": sessionID,
 		"count":      len(newsletters),
 	})
 
-	return NewSubscribedNewslettersResponse(newsletters), nil
+	return NewSubscribedNewslettersResponse(fetched.infos, req, false, fetched.fetchedAt), nil
+}
+
+// cachedSubscribed returns the session's cached subscription list, or nil when missing or expired
+func (uc *useCaseImpl) cachedSubscribed(sessionID string) *subscribedNewsletters {
+	uc.subscribedMu.Lock()
+	defer uc.subscribedMu.Unlock()
+
+	cached, exists := uc.subscribedCache[sessionID]
+	if !exists {
+		return nil
+	}
+	if time.Since(cached.fetchedAt) >= subscribedCacheTTL {
+		delete(uc.subscribedCache, sessionID)
+		return nil
+	}
+	return cached
+}
+
+// storeSubscribed caches a freshly fetched subscription list, dropping expired entries of other sessions
+func (uc *useCaseImpl) storeSubscribed(sessionID string, infos []*newsletter.NewsletterInfo) *subscribedNewsletters {
+	uc.subscribedMu.Lock()
+	defer uc.subscribedMu.Unlock()
+
+	for id, cached := range uc.subscribedCache {
+		if time.Since(cached.fetchedAt) >= subscribedCacheTTL {
+			delete(uc.subscribedCache, id)
+		}
+	}
+
+	fetched := &subscribedNewsletters{
+		infos:     infos,
+		fetchedAt: time.Now(),
+	}
+	uc.subscribedCache[sessionID] = fetched
+	return fetched
+}
+
+// invalidateSubscribed drops the session's cached subscription list after a change to its subscriptions
+func (uc *useCaseImpl) invalidateSubscribed(sessionID string) {
+	uc.subscribedMu.Lock()
+	delete(uc.subscribedCache, sessionID)
+	uc.subscribedMu.Unlock()
 }
 
 // GetNewsletterMessages gets messages from a newsletter
@@ -773,6 +938,186 @@ func (uc *useCaseImpl) sendReactionAndRespond(ctx context.Context, sessionID str
 	return NewNewsletterActionResponse(req.NewsletterJID, "success", message), nil
 }
 
+// BulkNewsletterMarkViewed marks many newsletter messages as viewed in batches, reporting the outcome per message
+func (uc *useCaseImpl) BulkNewsletterMarkViewed(ctx context.Context, sessionID string, req *BulkNewsletterMarkViewedRequest) (*BulkNewsletterOperationResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if _, err := uc.validateSessionAndConnection(ctx, sessionID); err != nil {
+		return nil, err
+	}
+
+	uc.logger.InfoWithFields("Bulk marking newsletter messages as viewed", map[string]interface{}{
+		"session_id": sessionID,
+		"jid":        req.NewsletterJID,
+		"count":      len(req.ServerIDs),
+	})
+
+	results := make([]BulkNewsletterItemResult, len(req.ServerIDs))
+	seen := make(map[string]bool, len(req.ServerIDs))
+	valid := make([]int, 0, len(req.ServerIDs))
+	for i, serverID := range req.ServerIDs {
+		results[i].ServerID = serverID
+		if _, err := strconv.ParseUint(serverID, 10, 64); err != nil {
+			results[i].Error = "server ID must be numeric"
+			continue
+		}
+		if seen[serverID] {
+			results[i].Error = "duplicate server ID"
+			continue
+		}
+		seen[serverID] = true
+		valid = append(valid, i)
+	}
+
+	for start := 0; start < len(valid); start += bulkMarkViewedBatchSize {
+		end := start + bulkMarkViewedBatchSize
+		if end > len(valid) {
+			end = len(valid)
+		}
+		batch := valid[start:end]
+
+		err := ctx.Err()
+		if err == nil {
+			serverIDs := make([]string, len(batch))
+			for j, i := range batch {
+				serverIDs[j] = req.ServerIDs[i]
+			}
+			err = uc.newsletterManager.NewsletterMarkViewed(ctx, sessionID, req.NewsletterJID, serverIDs)
+		}
+
+		for _, i := range batch {
+			if err != nil {
+				results[i].Error = err.Error()
+			} else {
+				results[i].Success = true
+			}
+		}
+		if err != nil {
+			uc.logger.WarnWithFields("Failed to mark newsletter message batch as viewed", map[string]interface{}{
+				"session_id": sessionID,
+				"jid":        req.NewsletterJID,
+				"batch_size": len(batch),
+				"error":      err.Error(),
+			})
+		}
+	}
+
+	response := NewBulkNewsletterOperationResponse(req.NewsletterJID, results)
+
+	uc.logger.InfoWithFields("Bulk newsletter mark viewed finished", map[string]interface{}{
+		"session_id": sessionID,
+		"jid":        req.NewsletterJID,
+		"succeeded":  response.Succeeded,
+		"failed":     response.Failed,
+	})
+
+	return response, nil
+}
+
+// BulkNewsletterSendReaction reacts to many newsletter messages one by one, reporting the outcome per message
+func (uc *useCaseImpl) BulkNewsletterSendReaction(ctx context.Context, sessionID string, req *BulkNewsletterSendReactionRequest) (*BulkNewsletterOperationResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	if _, err := uc.validateSessionAndConnection(ctx, sessionID); err != nil {
+		return nil, err
+	}
+
+	uc.logger.InfoWithFields("Bulk sending newsletter reactions", map[string]interface{}{
+		"session_id": sessionID,
+		"jid":        req.NewsletterJID,
+		"count":      len(req.Items),
+	})
+
+	serverIDsByMessage, lookupErr := uc.lookupBulkReactionServerIDs(ctx, sessionID, req)
+
+	results := make([]BulkNewsletterItemResult, len(req.Items))
+	for i, item := range req.Items {
+		results[i].ServerID = item.ServerID
+		results[i].MessageID = item.MessageID
+
+		if err := ctx.Err(); err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+
+		serverID := item.ServerID
+		if serverID == "" {
+			switch {
+			case item.MessageID == "":
+				results[i].Error = "serverId or messageId is required"
+				continue
+			case lookupErr != nil:
+				results[i].Error = fmt.Sprintf("failed to lookup ServerID: %v", lookupErr)
+				continue
+			}
+			serverID = serverIDsByMessage[item.MessageID]
+			if serverID == "" {
+				results[i].Error = fmt.Sprintf("could not find ServerID for MessageID %s", item.MessageID)
+				continue
+			}
+			results[i].ServerID = serverID
+		}
+
+		reaction := req.Reaction
+		if item.Reaction != nil {
+			reaction = *item.Reaction
+		}
+
+		if err := uc.newsletterManager.NewsletterSendReaction(ctx, sessionID, req.NewsletterJID, serverID, reaction, item.MessageID); err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		results[i].Success = true
+	}
+
+	response := NewBulkNewsletterOperationResponse(req.NewsletterJID, results)
+
+	uc.logger.InfoWithFields("Bulk newsletter reactions finished", map[string]interface{}{
+		"session_id": sessionID,
+		"jid":        req.NewsletterJID,
+		"succeeded":  response.Succeeded,
+		"failed":     response.Failed,
+	})
+
+	return response, nil
+}
+
+// lookupBulkReactionServerIDs maps message IDs to server IDs for items that only carry a message ID,
+// fetching the newsletter's recent messages once for the whole batch
+func (uc *useCaseImpl) lookupBulkReactionServerIDs(ctx context.Context, sessionID string, req *BulkNewsletterSendReactionRequest) (map[string]string, error) {
+	serverIDs := make(map[string]string)
+
+	needsLookup := false
+	for _, item := range req.Items {
+		if item.ServerID == "" && item.MessageID != "" {
+			needsLookup = true
+			break
+		}
+	}
+	if !needsLookup {
+		return serverIDs, nil
+	}
+
+	messages, err := uc.newsletterManager.GetNewsletterMessages(ctx, sessionID, req.NewsletterJID, bulkReactionLookupCount, "")
+	if err != nil {
+		uc.logger.ErrorWithFields("Failed to get newsletter messages for ServerID lookup", map[string]interface{}{
+			"session_id": sessionID,
+			"jid":        req.NewsletterJID,
+			"error":      err.Error(),
+		})
+		return nil, err
+	}
+
+	for _, msg := range messages {
+		serverIDs[msg.ID] = msg.ServerID
+	}
+	return serverIDs, nil
+}
+
 // NewsletterSubscribeLiveUpdates subscribes to live updates from a newsletter
 func (uc *useCaseImpl) NewsletterSubscribeLiveUpdates(ctx context.Context, sessionID string, req *NewsletterSubscribeLiveUpdatesRequest) (*NewsletterSubscribeLiveUpdatesResponse, error) {
 	uc.logger.InfoWithFields("Subscribing to newsletter live updates", map[string]interface{}{
@@ -866,6 +1211,8 @@ func (uc *useCaseImpl) NewsletterToggleMute(ctx context.Context, sessionID strin
 		return nil, fmt.Errorf("failed to toggle newsletter mute status: %w", err)
 	}
 
+	uc.invalidateSubscribed(sessionID)
+
 	uc.logger.InfoWithFields("Newsletter mute status toggled successfully", map[string]interface{}{
 		"session_id": sessionID,
 		"jid":        req.NewsletterJID,
@@ -0,0 +1,56 @@
+package outbox
+
+import (
+	"time"
+
+	"zpwoot/internal/domain/outbox"
+)
+
+type ListOutboxRequest struct {
+	Status string `json:"status,omitempty" query:"status" example:"queued"` // queued, sending, sent, failed or cancelled
+	Limit  int    `json:"limit,omitempty" query:"limit" validate:"omitempty,min=1,max=100" example:"20"`
+	Offset int    `json:"offset,omitempty" query:"offset" validate:"omitempty,min=0" example:"0"`
+	// Cursor continues from the nextCursor of the previous page; it replaces offset
+	Cursor string `json:"cursor,omitempty" query:"cursor" example:"MjAyNC0wMS0wMVQxMjowMDowMFp8MWIyZTQyNGM"`
+} //@name ListOutboxRequest
+
+type OutboxMessageResponse struct {
+	ID      string `json:"id" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	ChatJID string `json:"chatJid" example:"5511999999999@s.whatsapp.net"`
+	Type    string `json:"type" example:"text"`
+	// Status goes from queued to sending, then sent or failed; a queued message can also be cancelled
+	Status    string `json:"status" example:"queued"`
+	Attempts  int    `json:"attempts" example:"0"`
+	MessageID string `json:"messageId,omitempty" example:"3EB0C767D71D"`
+	LastError string `json:"lastError,omitempty"`
+	// SendAfter is the earliest time the message is sent
+	SendAfter time.Time  `json:"sendAfter" example:"2024-01-01T12:00:05Z"`
+	SentAt    *time.Time `json:"sentAt,omitempty" example:"2024-01-01T12:00:07Z"`
+	CreatedAt time.Time  `json:"createdAt" example:"2024-01-01T12:00:00Z"`
+	UpdatedAt time.Time  `json:"updatedAt" example:"2024-01-01T12:00:07Z"`
+} //@name OutboxMessageResponse
+
+type ListOutboxResponse struct {
+	Messages []OutboxMessageResponse `json:"messages"`
+	Total    int                     `json:"total" example:"3"`
+	Limit    int                     `json:"limit" example:"20"`
+	Offset   int                     `json:"offset" example:"0"`
+	// NextCursor fetches the next page; it is empty on the last page
+	NextCursor string `json:"nextCursor,omitempty"`
+} //@name ListOutboxResponse
+
+func FromMessage(msg *outbox.Message) *OutboxMessageResponse {
+	return &OutboxMessageResponse{
+		ID:        msg.ID.String(),
+		ChatJID:   msg.ChatJID,
+		Type:      string(msg.Request.Type),
+		Status:    msg.Status,
+		Attempts:  msg.Attempts,
+		MessageID: msg.MessageID,
+		LastError: msg.LastError,
+		SendAfter: msg.SendAfter,
+		SentAt:    msg.SentAt,
+		CreatedAt: msg.CreatedAt,
+		UpdatedAt: msg.UpdatedAt,
+	}
+}
@@ -0,0 +1,111 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	appMessage "zpwoot/internal/app/message"
+	domainMessage "zpwoot/internal/domain/message"
+	"zpwoot/internal/domain/outbox"
+	"zpwoot/platform/pagination"
+)
+
+type UseCase interface {
+	// Enqueue validates the message and adds it to the outbox; the quota is checked now and the
+	// message counted once it is sent
+	Enqueue(ctx context.Context, sessionID string, req *appMessage.SendMessageRequest) (*OutboxMessageResponse, error)
+	GetMessage(ctx context.Context, sessionID string, id uuid.UUID) (*OutboxMessageResponse, error)
+	ListMessages(ctx context.Context, sessionID string, req *ListOutboxRequest) (*ListOutboxResponse, error)
+}
+
+type useCaseImpl struct {
+	outboxService *outbox.Service
+	messageUC     appMessage.UseCase
+}
+
+func NewUseCase(outboxService *outbox.Service, messageUC appMessage.UseCase) UseCase {
+	// Outbox messages go through the same media, translation and usage handling as direct sends
+	outboxService.SetSender(messageUC)
+
+	return &useCaseImpl{
+		outboxService: outboxService,
+		messageUC:     messageUC,
+	}
+}
+
+func (uc *useCaseImpl) Enqueue(ctx context.Context, sessionID string, req *appMessage.SendMessageRequest) (*OutboxMessageResponse, error) {
+	if !uc.outboxService.Enabled() {
+		return nil, outbox.ErrOutboxDisabled
+	}
+	if req.ScheduleAt != nil {
+		return nil, fmt.Errorf("invalid request: scheduleAt is not supported by the outbox")
+	}
+
+	domainReq := req.ToDomainRequest()
+	if err := domainMessage.ValidateMessageRequest(domainReq); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	if err := uc.messageUC.CheckSendQuota(ctx); err != nil {
+		return nil, err
+	}
+
+	msg, err := uc.outboxService.Enqueue(ctx, sessionID, domainReq)
+	if err != nil {
+		return nil, err
+	}
+
+	return FromMessage(msg), nil
+}
+
+func (uc *useCaseImpl) GetMessage(ctx context.Context, sessionID string, id uuid.UUID) (*OutboxMessageResponse, error) {
+	msg, err := uc.outboxService.Get(ctx, sessionID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return FromMessage(msg), nil
+}
+
+func (uc *useCaseImpl) ListMessages(ctx context.Context, sessionID string, req *ListOutboxRequest) (*ListOutboxResponse, error) {
+	domainReq := &outbox.ListRequest{
+		SessionID: sessionID,
+		Status:    req.Status,
+		Limit:     req.Limit,
+		Offset:    req.Offset,
+	}
+
+	after, err := pagination.Decode(req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	if after != nil {
+		domainReq.After = after
+		domainReq.Offset = 0
+	}
+
+	msgs, total, err := uc.outboxService.List(ctx, domainReq)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]OutboxMessageResponse, len(msgs))
+	for i, msg := range msgs {
+		responses[i] = *FromMessage(msg)
+	}
+
+	response := &ListOutboxResponse{
+		Messages: responses,
+		Total:    total,
+		Limit:    domainReq.Limit,
+		Offset:   domainReq.Offset,
+	}
+	if len(msgs) > 0 {
+		last := msgs[len(msgs)-1]
+		response.NextCursor = pagination.Next(len(msgs), domainReq.Limit, last.CreatedAt, last.ID.String())
+	}
+
+	return response, nil
+}
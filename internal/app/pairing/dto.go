@@ -0,0 +1,38 @@
+package pairing
+
+import (
+	"time"
+
+	"zpwoot/internal/domain/pairing"
+)
+
+type CreateLinkRequest struct {
+	TTL int `json:"ttlSeconds,omitempty" validate:"omitempty,min=60,max=3600" example:"600"` // Seconds the link stays valid; defaults to 600
+} //@name CreatePairingLinkRequest
+
+type PairingLinkResponse struct {
+	SessionID string    `json:"sessionId" example:"0f7c6a1e-4a52-4e6b-9d2b-3c1f2e4d5a6b"`
+	URL       string    `json:"url" example:"https://api.example.com/pair/9f86d081884c7d65.1704110400.Qm9vYmFy"`
+	ExpiresAt time.Time `json:"expiresAt" example:"2024-01-01T12:10:00Z"`
+} //@name PairingLinkResponse
+
+// PairingPage is what the public pairing page shows about its session
+type PairingPage struct {
+	SessionName string
+	ExpiresAt   time.Time
+}
+
+// PairingState is one update of the pairing page's event stream
+type PairingState struct {
+	Connected   bool       `json:"connected"`
+	QRCodeImage string     `json:"qrCodeImage,omitempty"`
+	QRExpiresAt *time.Time `json:"qrExpiresAt,omitempty"`
+}
+
+func FromLink(link *pairing.Link, url string) *PairingLinkResponse {
+	return &PairingLinkResponse{
+		SessionID: link.SessionID,
+		URL:       url,
+		ExpiresAt: link.ExpiresAt,
+	}
+}
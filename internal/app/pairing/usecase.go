@@ -0,0 +1,130 @@
+package pairing
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"zpwoot/internal/domain/pairing"
+	"zpwoot/internal/domain/session"
+	"zpwoot/internal/ports"
+)
+
+type UseCase interface {
+	// CreateLink issues a one-time pairing URL for a session, given by ID or name
+	CreateLink(ctx context.Context, sessionIdentifier string, req *CreateLinkRequest) (*PairingLinkResponse, error)
+	// OpenLink verifies a pairing token and returns what the pairing page shows
+	OpenLink(ctx context.Context, token string) (*PairingPage, error)
+	// StartPairing connects the session of a pairing token so that it produces QR codes
+	StartPairing(ctx context.Context, token string) error
+	// PairingState returns the current QR code of a pairing token, or that the session paired
+	PairingState(ctx context.Context, token string) (*PairingState, error)
+}
+
+type useCaseImpl struct {
+	sessionRepo    ports.SessionRepository
+	sessionService *session.Service
+	pairingService *pairing.Service
+	serverURL      string
+}
+
+func NewUseCase(
+	sessionRepo ports.SessionRepository,
+	sessionService *session.Service,
+	pairingService *pairing.Service,
+	serverURL string,
+) UseCase {
+	return &useCaseImpl{
+		sessionRepo:    sessionRepo,
+		sessionService: sessionService,
+		pairingService: pairingService,
+		serverURL:      strings.TrimRight(serverURL, "/"),
+	}
+}
+
+func (uc *useCaseImpl) CreateLink(ctx context.Context, sessionIdentifier string, req *CreateLinkRequest) (*PairingLinkResponse, error) {
+	sess, err := uc.resolveSession(ctx, sessionIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	if sess.IsConnected {
+		return nil, pairing.ErrAlreadyPaired
+	}
+
+	link, token, err := uc.pairingService.Issue(sess.ID.String(), sess.Name, time.Duration(req.TTL)*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	return FromLink(link, uc.serverURL+"/pair/"+token), nil
+}
+
+func (uc *useCaseImpl) OpenLink(ctx context.Context, token string) (*PairingPage, error) {
+	link, err := uc.pairingService.Verify(token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PairingPage{
+		SessionName: link.SessionName,
+		ExpiresAt:   link.ExpiresAt,
+	}, nil
+}
+
+func (uc *useCaseImpl) StartPairing(ctx context.Context, token string) error {
+	link, err := uc.pairingService.Verify(token)
+	if err != nil {
+		return err
+	}
+
+	sess, err := uc.sessionRepo.GetByID(ctx, link.SessionID)
+	if err != nil {
+		return err
+	}
+	if sess.IsConnected {
+		return nil
+	}
+
+	// A pending QR code means the session is already waiting to be scanned
+	if _, err := uc.sessionService.GetQRCode(ctx, link.SessionID); err == nil {
+		return nil
+	}
+
+	return uc.sessionService.ConnectSession(ctx, link.SessionID)
+}
+
+func (uc *useCaseImpl) PairingState(ctx context.Context, token string) (*PairingState, error) {
+	link, err := uc.pairingService.Verify(token)
+	if err != nil {
+		return nil, err
+	}
+
+	sess, err := uc.sessionRepo.GetByID(ctx, link.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if sess.IsConnected {
+		uc.pairingService.MarkUsed(link.ID)
+		return &PairingState{Connected: true}, nil
+	}
+
+	qr, err := uc.sessionService.GetQRCode(ctx, link.SessionID)
+	if err != nil {
+		// No QR code yet, or the previous one expired while the next is generated
+		return &PairingState{}, nil
+	}
+
+	return &PairingState{
+		QRCodeImage: qr.QRCodeImage,
+		QRExpiresAt: &qr.ExpiresAt,
+	}, nil
+}
+
+func (uc *useCaseImpl) resolveSession(ctx context.Context, identifier string) (*session.Session, error) {
+	if _, err := uuid.Parse(identifier); err == nil {
+		return uc.sessionRepo.GetByID(ctx, identifier)
+	}
+	return uc.sessionRepo.GetByName(ctx, identifier)
+}
@@ -0,0 +1,30 @@
+package privacy
+
+import (
+	"time"
+
+	"zpwoot/internal/domain/privacy"
+)
+
+type UpdatePrivacyConfigRequest struct {
+	RedactLogs           *bool `json:"redactLogs,omitempty" example:"true"`           // Keep message text, captions and contact cards out of the logs
+	MetadataOnlyWebhooks *bool `json:"metadataOnlyWebhooks,omitempty" example:"true"` // Deliver message events without their content
+} //@name UpdatePrivacyConfigRequest
+
+type PrivacyConfigResponse struct {
+	RedactLogs           bool       `json:"redactLogs" example:"true"`
+	MetadataOnlyWebhooks bool       `json:"metadataOnlyWebhooks" example:"true"`
+	UpdatedAt            *time.Time `json:"updatedAt,omitempty" example:"2024-01-01T12:00:00Z"`
+} //@name PrivacyConfigResponse
+
+func FromConfig(c *privacy.Config) *PrivacyConfigResponse {
+	response := &PrivacyConfigResponse{
+		RedactLogs:           c.RedactLogs,
+		MetadataOnlyWebhooks: c.MetadataOnlyWebhooks,
+	}
+	if !c.UpdatedAt.IsZero() {
+		updatedAt := c.UpdatedAt
+		response.UpdatedAt = &updatedAt
+	}
+	return response
+}
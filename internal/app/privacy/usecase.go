@@ -0,0 +1,68 @@
+package privacy
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"zpwoot/internal/domain/privacy"
+	"zpwoot/internal/domain/session"
+	"zpwoot/internal/ports"
+)
+
+type UseCase interface {
+	GetConfig(ctx context.Context, sessionID string) (*PrivacyConfigResponse, error)
+	UpdateConfig(ctx context.Context, sessionID string, req *UpdatePrivacyConfigRequest) (*PrivacyConfigResponse, error)
+	// RedactsLogs reports whether content of the session, given by ID or name, must be kept out of the logs
+	RedactsLogs(ctx context.Context, sessionIdentifier string) bool
+}
+
+type useCaseImpl struct {
+	sessionRepo    ports.SessionRepository
+	privacyService *privacy.Service
+}
+
+func NewUseCase(sessionRepo ports.SessionRepository, privacyService *privacy.Service) UseCase {
+	return &useCaseImpl{
+		sessionRepo:    sessionRepo,
+		privacyService: privacyService,
+	}
+}
+
+func (uc *useCaseImpl) GetConfig(ctx context.Context, sessionID string) (*PrivacyConfigResponse, error) {
+	config, err := uc.privacyService.GetConfig(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return FromConfig(config), nil
+}
+
+func (uc *useCaseImpl) UpdateConfig(ctx context.Context, sessionID string, req *UpdatePrivacyConfigRequest) (*PrivacyConfigResponse, error) {
+	config, err := uc.privacyService.UpdateConfig(ctx, sessionID, &privacy.UpdateConfigRequest{
+		RedactLogs:           req.RedactLogs,
+		MetadataOnlyWebhooks: req.MetadataOnlyWebhooks,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return FromConfig(config), nil
+}
+
+func (uc *useCaseImpl) RedactsLogs(ctx context.Context, sessionIdentifier string) bool {
+	if _, err := uuid.Parse(sessionIdentifier); err == nil {
+		return uc.privacyService.RedactsLogs(sessionIdentifier)
+	}
+
+	sess, err := uc.sessionRepo.GetByName(ctx, sessionIdentifier)
+	if errors.Is(err, session.ErrSessionNotFound) {
+		// Unknown sessions have no content to leak
+		return false
+	}
+	if err != nil || sess == nil {
+		return true
+	}
+	return uc.privacyService.RedactsLogs(sess.ID.String())
+}
@@ -0,0 +1,95 @@
+package routing
+
+import (
+	"time"
+
+	"zpwoot/internal/domain/routing"
+)
+
+type CreateRoutingRuleRequest struct {
+	Name           string   `json:"name" validate:"required" example:"Support"`
+	Priority       int      `json:"priority" example:"10"` // Higher priority rules are evaluated first
+	Enabled        *bool    `json:"enabled,omitempty" example:"true"`
+	Keywords       []string `json:"keywords,omitempty" example:"suporte,help"`
+	Patterns       []string `json:"patterns,omitempty" example:"pedido\\s*#?\\d+"` // Case-insensitive regular expressions
+	Senders        []string `json:"senders,omitempty" example:"5511999999999"`
+	States         []string `json:"states,omitempty" example:"main_menu"` // Conversation states the chat must be in; "start" when it has none
+	ReplyIDs       []string `json:"replyIds,omitempty" example:"support"` // Button or list row IDs the message must reply with
+	Tags           []string `json:"tags,omitempty" example:"support"`
+	WebhookIDs     []string `json:"webhookIds,omitempty" example:"0f7c6a1e-4a52-4e6b-9d2b-3c1f2e4d5a6b"`
+	Labels         []string `json:"labels,omitempty" example:"1"`   // WhatsApp label IDs applied to the chat
+	Exclusive      bool     `json:"exclusive" example:"false"`      // Deliver matching messages only to the rule's webhooks
+	StopProcessing bool     `json:"stopProcessing" example:"false"` // Skip lower priority rules when this one matches
+	// NextState is the conversation state the chat moves to; "start" resets it
+	NextState string `json:"nextState,omitempty" example:"support_menu"`
+} //@name CreateRoutingRuleRequest
+
+type UpdateRoutingRuleRequest struct {
+	Name           *string   `json:"name,omitempty" example:"Support"`
+	Priority       *int      `json:"priority,omitempty" example:"10"`
+	Enabled        *bool     `json:"enabled,omitempty" example:"true"`
+	Keywords       *[]string `json:"keywords,omitempty" example:"suporte,help"`
+	Patterns       *[]string `json:"patterns,omitempty" example:"pedido\\s*#?\\d+"`
+	Senders        *[]string `json:"senders,omitempty" example:"5511999999999"`
+	States         *[]string `json:"states,omitempty" example:"main_menu"`
+	ReplyIDs       *[]string `json:"replyIds,omitempty" example:"support"`
+	Tags           *[]string `json:"tags,omitempty" example:"support"`
+	WebhookIDs     *[]string `json:"webhookIds,omitempty" example:"0f7c6a1e-4a52-4e6b-9d2b-3c1f2e4d5a6b"`
+	Labels         *[]string `json:"labels,omitempty" example:"1"`
+	Exclusive      *bool     `json:"exclusive,omitempty" example:"false"`
+	StopProcessing *bool     `json:"stopProcessing,omitempty" example:"false"`
+	NextState      *string   `json:"nextState,omitempty" example:"support_menu"`
+} //@name UpdateRoutingRuleRequest
+
+type RoutingRuleResponse struct {
+	ID             string    `json:"id" example:"0f7c6a1e-4a52-4e6b-9d2b-3c1f2e4d5a6b"`
+	Name           string    `json:"name" example:"Support"`
+	Priority       int       `json:"priority" example:"10"`
+	Enabled        bool      `json:"enabled" example:"true"`
+	Keywords       []string  `json:"keywords"`
+	Patterns       []string  `json:"patterns"`
+	Senders        []string  `json:"senders"`
+	States         []string  `json:"states"`
+	ReplyIDs       []string  `json:"replyIds"`
+	Tags           []string  `json:"tags"`
+	WebhookIDs     []string  `json:"webhookIds"`
+	Labels         []string  `json:"labels"`
+	Exclusive      bool      `json:"exclusive" example:"false"`
+	StopProcessing bool      `json:"stopProcessing" example:"false"`
+	NextState      string    `json:"nextState,omitempty" example:"support_menu"`
+	CreatedAt      time.Time `json:"createdAt" example:"2024-01-01T12:00:00Z"`
+	UpdatedAt      time.Time `json:"updatedAt" example:"2024-01-01T12:00:00Z"`
+} //@name RoutingRuleResponse
+
+type ListRoutingRulesResponse struct {
+	Rules []RoutingRuleResponse `json:"rules"`
+	Total int                   `json:"total" example:"2"`
+} //@name ListRoutingRulesResponse
+
+type ChatLabelResponse struct {
+	ChatJID string `json:"chatJid" example:"5511999999999@s.whatsapp.net"`
+	LabelID string `json:"labelId" example:"1"`
+	Labeled bool   `json:"labeled" example:"true"`
+} //@name ChatLabelResponse
+
+func FromRule(r *routing.Rule) *RoutingRuleResponse {
+	return &RoutingRuleResponse{
+		ID:             r.ID.String(),
+		Name:           r.Name,
+		Priority:       r.Priority,
+		Enabled:        r.Enabled,
+		Keywords:       r.Keywords,
+		Patterns:       r.Patterns,
+		Senders:        r.Senders,
+		States:         r.States,
+		ReplyIDs:       r.ReplyIDs,
+		Tags:           r.Tags,
+		WebhookIDs:     r.WebhookIDs,
+		Labels:         r.Labels,
+		Exclusive:      r.Exclusive,
+		StopProcessing: r.StopProcessing,
+		NextState:      r.NextState,
+		CreatedAt:      r.CreatedAt,
+		UpdatedAt:      r.UpdatedAt,
+	}
+}
@@ -0,0 +1,117 @@
+package routing
+
+import (
+	"context"
+
+	"zpwoot/internal/domain/routing"
+)
+
+type UseCase interface {
+	CreateRule(ctx context.Context, sessionID string, req *CreateRoutingRuleRequest) (*RoutingRuleResponse, error)
+	GetRule(ctx context.Context, sessionID, ruleID string) (*RoutingRuleResponse, error)
+	ListRules(ctx context.Context, sessionID string) (*ListRoutingRulesResponse, error)
+	UpdateRule(ctx context.Context, sessionID, ruleID string, req *UpdateRoutingRuleRequest) (*RoutingRuleResponse, error)
+	DeleteRule(ctx context.Context, sessionID, ruleID string) error
+	LabelChat(ctx context.Context, sessionID, chatJID, labelID string, labeled bool) (*ChatLabelResponse, error)
+}
+
+type useCaseImpl struct {
+	routingService *routing.Service
+}
+
+func NewUseCase(routingService *routing.Service) UseCase {
+	return &useCaseImpl{
+		routingService: routingService,
+	}
+}
+
+func (uc *useCaseImpl) CreateRule(ctx context.Context, sessionID string, req *CreateRoutingRuleRequest) (*RoutingRuleResponse, error) {
+	rule, err := uc.routingService.CreateRule(ctx, &routing.CreateRuleRequest{
+		SessionID:      sessionID,
+		Name:           req.Name,
+		Priority:       req.Priority,
+		Enabled:        req.Enabled,
+		Keywords:       req.Keywords,
+		Patterns:       req.Patterns,
+		Senders:        req.Senders,
+		States:         req.States,
+		ReplyIDs:       req.ReplyIDs,
+		Tags:           req.Tags,
+		WebhookIDs:     req.WebhookIDs,
+		Labels:         req.Labels,
+		Exclusive:      req.Exclusive,
+		StopProcessing: req.StopProcessing,
+		NextState:      req.NextState,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return FromRule(rule), nil
+}
+
+func (uc *useCaseImpl) GetRule(ctx context.Context, sessionID, ruleID string) (*RoutingRuleResponse, error) {
+	rule, err := uc.routingService.GetRule(ctx, sessionID, ruleID)
+	if err != nil {
+		return nil, err
+	}
+
+	return FromRule(rule), nil
+}
+
+func (uc *useCaseImpl) ListRules(ctx context.Context, sessionID string) (*ListRoutingRulesResponse, error) {
+	rules, err := uc.routingService.ListRules(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]RoutingRuleResponse, len(rules))
+	for i, rule := range rules {
+		responses[i] = *FromRule(rule)
+	}
+
+	return &ListRoutingRulesResponse{
+		Rules: responses,
+		Total: len(responses),
+	}, nil
+}
+
+func (uc *useCaseImpl) UpdateRule(ctx context.Context, sessionID, ruleID string, req *UpdateRoutingRuleRequest) (*RoutingRuleResponse, error) {
+	rule, err := uc.routingService.UpdateRule(ctx, sessionID, ruleID, &routing.UpdateRuleRequest{
+		Name:           req.Name,
+		Priority:       req.Priority,
+		Enabled:        req.Enabled,
+		Keywords:       req.Keywords,
+		Patterns:       req.Patterns,
+		Senders:        req.Senders,
+		States:         req.States,
+		ReplyIDs:       req.ReplyIDs,
+		Tags:           req.Tags,
+		WebhookIDs:     req.WebhookIDs,
+		Labels:         req.Labels,
+		Exclusive:      req.Exclusive,
+		StopProcessing: req.StopProcessing,
+		NextState:      req.NextState,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return FromRule(rule), nil
+}
+
+func (uc *useCaseImpl) DeleteRule(ctx context.Context, sessionID, ruleID string) error {
+	return uc.routingService.DeleteRule(ctx, sessionID, ruleID)
+}
+
+func (uc *useCaseImpl) LabelChat(ctx context.Context, sessionID, chatJID, labelID string, labeled bool) (*ChatLabelResponse, error) {
+	if err := uc.routingService.LabelChat(sessionID, chatJID, labelID, labeled); err != nil {
+		return nil, err
+	}
+
+	return &ChatLabelResponse{
+		ChatJID: chatJID,
+		LabelID: labelID,
+		Labeled: labeled,
+	}, nil
+}
@@ -0,0 +1,65 @@
+package scheduled
+
+import (
+	"time"
+
+	"zpwoot/internal/domain/scheduled"
+)
+
+type ListScheduledRequest struct {
+	Status string `json:"status,omitempty" query:"status" example:"scheduled"` // scheduled, sending, sent, failed or cancelled
+	Limit  int    `json:"limit,omitempty" query:"limit" validate:"omitempty,min=1,max=100" example:"20"`
+	Offset int    `json:"offset,omitempty" query:"offset" validate:"omitempty,min=0" example:"0"`
+	// Cursor continues from the nextCursor of the previous page; it replaces offset
+	Cursor string `json:"cursor,omitempty" query:"cursor" example:"MjAyNC0wMS0wMVQxMjowMDowMFp8MWIyZTQyNGM"`
+} //@name ListScheduledRequest
+
+type RescheduleRequest struct {
+	ScheduleAt *time.Time `json:"scheduleAt" validate:"required" example:"2024-01-02T09:00:00Z"`
+} //@name RescheduleRequest
+
+type ScheduledMessageResponse struct {
+	ID      string `json:"id" example:"1b9d6bcd-bbfd-4b2d-9b5d-ab8dfbbd4bed"`
+	ChatJID string `json:"chatJid" example:"5511999999999@s.whatsapp.net"`
+	Type    string `json:"type" example:"text"`
+	// Status goes from scheduled to sending, then sent or failed; cancelled messages are never sent
+	Status      string     `json:"status" example:"scheduled"`
+	Attempts    int        `json:"attempts" example:"0"`
+	MessageID   string     `json:"messageId,omitempty" example:"3EB0C767D71D"`
+	LastError   string     `json:"lastError,omitempty"`
+	ScheduledAt time.Time  `json:"scheduledAt" example:"2024-01-01T18:00:00Z"`
+	SentAt      *time.Time `json:"sentAt,omitempty" example:"2024-01-01T18:00:04Z"`
+	CreatedAt   time.Time  `json:"createdAt" example:"2024-01-01T12:00:00Z"`
+	UpdatedAt   time.Time  `json:"updatedAt" example:"2024-01-01T18:00:04Z"`
+} //@name ScheduledMessageResponse
+
+type ListScheduledResponse struct {
+	Messages []ScheduledMessageResponse `json:"messages"`
+	Total    int                        `json:"total" example:"3"`
+	Limit    int                        `json:"limit" example:"20"`
+	Offset   int                        `json:"offset" example:"0"`
+	// NextCursor fetches the next page; it is empty on the last page
+	NextCursor string `json:"nextCursor,omitempty"`
+} //@name ListScheduledResponse
+
+type CancelScheduledResponse struct {
+	// Cancelled is false when the message was no longer scheduled, e.g. already picked up for sending
+	Cancelled bool                      `json:"cancelled" example:"true"`
+	Message   *ScheduledMessageResponse `json:"message"`
+} //@name CancelScheduledResponse
+
+func FromMessage(msg *scheduled.Message) *ScheduledMessageResponse {
+	return &ScheduledMessageResponse{
+		ID:          msg.ID.String(),
+		ChatJID:     msg.ChatJID,
+		Type:        string(msg.Request.Type),
+		Status:      msg.Status,
+		Attempts:    msg.Attempts,
+		MessageID:   msg.MessageID,
+		LastError:   msg.LastError,
+		ScheduledAt: msg.ScheduledAt,
+		SentAt:      msg.SentAt,
+		CreatedAt:   msg.CreatedAt,
+		UpdatedAt:   msg.UpdatedAt,
+	}
+}
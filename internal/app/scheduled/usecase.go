@@ -0,0 +1,105 @@
+package scheduled
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"zpwoot/internal/domain/scheduled"
+	"zpwoot/platform/pagination"
+)
+
+type UseCase interface {
+	GetMessage(ctx context.Context, sessionID string, id uuid.UUID) (*ScheduledMessageResponse, error)
+	ListMessages(ctx context.Context, sessionID string, req *ListScheduledRequest) (*ListScheduledResponse, error)
+	// Reschedule moves a message that is still scheduled to a new time
+	Reschedule(ctx context.Context, sessionID string, id uuid.UUID, req *RescheduleRequest) (*ScheduledMessageResponse, error)
+	// Cancel keeps a scheduled message from being sent
+	Cancel(ctx context.Context, sessionID string, id uuid.UUID) (*CancelScheduledResponse, error)
+}
+
+type useCaseImpl struct {
+	scheduler *scheduled.Service
+}
+
+func NewUseCase(scheduler *scheduled.Service) UseCase {
+	return &useCaseImpl{
+		scheduler: scheduler,
+	}
+}
+
+func (uc *useCaseImpl) GetMessage(ctx context.Context, sessionID string, id uuid.UUID) (*ScheduledMessageResponse, error) {
+	msg, err := uc.scheduler.Get(ctx, sessionID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return FromMessage(msg), nil
+}
+
+func (uc *useCaseImpl) ListMessages(ctx context.Context, sessionID string, req *ListScheduledRequest) (*ListScheduledResponse, error) {
+	domainReq := &scheduled.ListRequest{
+		SessionID: sessionID,
+		Status:    req.Status,
+		Limit:     req.Limit,
+		Offset:    req.Offset,
+	}
+
+	after, err := pagination.Decode(req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	if after != nil {
+		domainReq.After = after
+		domainReq.Offset = 0
+	}
+
+	msgs, total, err := uc.scheduler.List(ctx, domainReq)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]ScheduledMessageResponse, len(msgs))
+	for i, msg := range msgs {
+		responses[i] = *FromMessage(msg)
+	}
+
+	response := &ListScheduledResponse{
+		Messages: responses,
+		Total:    total,
+		Limit:    domainReq.Limit,
+		Offset:   domainReq.Offset,
+	}
+	if len(msgs) > 0 {
+		last := msgs[len(msgs)-1]
+		response.NextCursor = pagination.Next(len(msgs), domainReq.Limit, last.CreatedAt, last.ID.String())
+	}
+
+	return response, nil
+}
+
+func (uc *useCaseImpl) Reschedule(ctx context.Context, sessionID string, id uuid.UUID, req *RescheduleRequest) (*ScheduledMessageResponse, error) {
+	if req.ScheduleAt == nil {
+		return nil, fmt.Errorf("invalid request: scheduleAt is required")
+	}
+
+	msg, err := uc.scheduler.Reschedule(ctx, sessionID, id, *req.ScheduleAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return FromMessage(msg), nil
+}
+
+func (uc *useCaseImpl) Cancel(ctx context.Context, sessionID string, id uuid.UUID) (*CancelScheduledResponse, error) {
+	msg, cancelled, err := uc.scheduler.Cancel(ctx, sessionID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CancelScheduledResponse{
+		Cancelled: cancelled,
+		Message:   FromMessage(msg),
+	}, nil
+}
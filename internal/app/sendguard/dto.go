@@ -0,0 +1,69 @@
+package sendguard
+
+import (
+	"time"
+
+	"zpwoot/internal/domain/sendguard"
+)
+
+type UpdateSendPolicyRequest struct {
+	AllowedGroups *[]string `json:"allowedGroups,omitempty" example:"120363025246125244@g.us"` // Group JIDs the session may send to, * allows all groups
+	// Sandbox restricts the sends of the session to the sandbox recipients
+	Sandbox           *bool     `json:"sandbox,omitempty" example:"true"`
+	SandboxRecipients *[]string `json:"sandboxRecipients,omitempty" example:"5511999999999"` // Phone numbers or JIDs the sandboxed session may send to
+	// SandboxRedirectTo receives the sends to other recipients instead of rejecting them, "" rejects them
+	SandboxRedirectTo *string `json:"sandboxRedirectTo,omitempty" example:"5511988888888"`
+} //@name UpdateSendPolicyRequest
+
+type SendPolicyResponse struct {
+	// BlockGroups is true when sends to groups that are not allowed are blocked
+	BlockGroups bool `json:"blockGroups" example:"true"`
+	// GlobalAllowedGroups are the groups every session may send to
+	GlobalAllowedGroups []string `json:"globalAllowedGroups"`
+	AllowedGroups       []string `json:"allowedGroups"`
+	// Sandbox is true when the session only sends to sandbox recipients, globally or by its own policy
+	Sandbox           bool     `json:"sandbox" example:"true"`
+	SandboxRecipients []string `json:"sandboxRecipients"`
+	// SandboxRedirectTo receives the sends to other recipients; empty when they are rejected
+	SandboxRedirectTo string       `json:"sandboxRedirectTo,omitempty" example:"5511988888888@s.whatsapp.net"`
+	GlobalSandbox     *SandboxInfo `json:"globalSandbox"`
+	UpdatedAt         *time.Time   `json:"updatedAt,omitempty" example:"2024-01-01T12:00:00Z"`
+} //@name SendPolicyResponse
+
+// SandboxInfo is the sandbox every session is in
+type SandboxInfo struct {
+	Enabled    bool     `json:"enabled" example:"false"`
+	Recipients []string `json:"recipients"`
+	RedirectTo string   `json:"redirectTo,omitempty" example:"5511988888888@s.whatsapp.net"`
+} //@name SandboxInfo
+
+type SendViolationResponse struct {
+	ID        string `json:"id" example:"b3f1c2d4-5e6f-7a8b-9c0d-1e2f3a4b5c6d"`
+	Recipient string `json:"recipient" example:"120363025246125244@g.us"`
+	Reason    string `json:"reason" example:"group_not_allowed"`
+	// RedirectedTo is the recipient a sandboxed send went to instead
+	RedirectedTo string    `json:"redirectedTo,omitempty" example:"5511988888888@s.whatsapp.net"`
+	CreatedAt    time.Time `json:"createdAt" example:"2024-01-01T12:00:00Z"`
+} //@name SendViolationResponse
+
+type ListSendViolationsResponse struct {
+	Violations []*SendViolationResponse `json:"violations"`
+	Total      int                      `json:"total" example:"1"`
+} //@name ListSendViolationsResponse
+
+func FromViolations(violations []*sendguard.Violation) *ListSendViolationsResponse {
+	response := &ListSendViolationsResponse{
+		Violations: make([]*SendViolationResponse, 0, len(violations)),
+		Total:      len(violations),
+	}
+	for _, violation := range violations {
+		response.Violations = append(response.Violations, &SendViolationResponse{
+			ID:           violation.ID.String(),
+			Recipient:    violation.Recipient,
+			Reason:       violation.Reason,
+			RedirectedTo: violation.RedirectedTo,
+			CreatedAt:    violation.CreatedAt,
+		})
+	}
+	return response
+}
@@ -0,0 +1,100 @@
+package sendguard
+
+import (
+	"context"
+
+	"zpwoot/internal/domain/sendguard"
+)
+
+// defaultViolationLimit and maxViolationLimit bound the violations listed at once
+const (
+	defaultViolationLimit = 50
+	maxViolationLimit     = 500
+)
+
+type UseCase interface {
+	GetPolicy(ctx context.Context, sessionID string) (*SendPolicyResponse, error)
+	UpdatePolicy(ctx context.Context, sessionID string, req *UpdateSendPolicyRequest) (*SendPolicyResponse, error)
+	ListViolations(ctx context.Context, sessionID string, limit int) (*ListSendViolationsResponse, error)
+}
+
+type useCaseImpl struct {
+	sendGuardService *sendguard.Service
+}
+
+func NewUseCase(sendGuardService *sendguard.Service) UseCase {
+	return &useCaseImpl{
+		sendGuardService: sendGuardService,
+	}
+}
+
+func (uc *useCaseImpl) GetPolicy(ctx context.Context, sessionID string) (*SendPolicyResponse, error) {
+	policy, err := uc.sendGuardService.GetPolicy(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.toResponse(policy), nil
+}
+
+func (uc *useCaseImpl) UpdatePolicy(ctx context.Context, sessionID string, req *UpdateSendPolicyRequest) (*SendPolicyResponse, error) {
+	policy, err := uc.sendGuardService.UpdatePolicy(ctx, sessionID, &sendguard.UpdatePolicyRequest{
+		AllowedGroups:     req.AllowedGroups,
+		Sandbox:           req.Sandbox,
+		SandboxRecipients: req.SandboxRecipients,
+		SandboxRedirectTo: req.SandboxRedirectTo,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.toResponse(policy), nil
+}
+
+func (uc *useCaseImpl) ListViolations(ctx context.Context, sessionID string, limit int) (*ListSendViolationsResponse, error) {
+	if limit <= 0 {
+		limit = defaultViolationLimit
+	}
+	if limit > maxViolationLimit {
+		limit = maxViolationLimit
+	}
+
+	violations, err := uc.sendGuardService.ListViolations(ctx, sessionID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return FromViolations(violations), nil
+}
+
+func (uc *useCaseImpl) toResponse(policy *sendguard.Policy) *SendPolicyResponse {
+	sandbox, sandboxRecipients, sandboxRedirectTo := uc.sendGuardService.GlobalSandbox()
+	if sandboxRecipients == nil {
+		sandboxRecipients = []string{}
+	}
+
+	response := &SendPolicyResponse{
+		BlockGroups:         uc.sendGuardService.BlocksGroups(),
+		GlobalAllowedGroups: uc.sendGuardService.GlobalAllowedGroups(),
+		AllowedGroups:       policy.AllowedGroups,
+		Sandbox:             sandbox || policy.Sandbox,
+		SandboxRecipients:   policy.SandboxRecipients,
+		SandboxRedirectTo:   policy.SandboxRedirectTo,
+		GlobalSandbox: &SandboxInfo{
+			Enabled:    sandbox,
+			Recipients: sandboxRecipients,
+			RedirectTo: sandboxRedirectTo,
+		},
+	}
+	if response.GlobalAllowedGroups == nil {
+		response.GlobalAllowedGroups = []string{}
+	}
+	if response.SandboxRecipients == nil {
+		response.SandboxRecipients = []string{}
+	}
+	if !policy.UpdatedAt.IsZero() {
+		updatedAt := policy.UpdatedAt
+		response.UpdatedAt = &updatedAt
+	}
+	return response
+}
@@ -3,7 +3,12 @@ package session
 import (
 	"time"
 
+	"zpwoot/internal/app/warmup"
+	appWebhook "zpwoot/internal/app/webhook"
+	"zpwoot/internal/domain/activity"
 	domainSession "zpwoot/internal/domain/session"
+	"zpwoot/internal/domain/stats"
+	"zpwoot/internal/ports"
 )
 
 type ProxyConfig struct {
@@ -12,8 +17,20 @@ type ProxyConfig struct {
 	Port     int    `json:"port" example:"8080"`
 	Username string `json:"username,omitempty" example:"proxyuser"`
 	Password string `json:"password,omitempty" example:"proxypass123"`
+	// LocalAddress binds the session's WhatsApp and media connections to this local IP; Interface binds
+	// them to the first address of a network interface. Both work on direct connections (leave type
+	// empty) and through socks5 proxies, and apply from the next connection.
+	LocalAddress string `json:"localAddress,omitempty" example:"203.0.113.10"`
+	Interface    string `json:"interface,omitempty" example:"eth1"`
 } //@name ProxyConfig
 
+// Redacted returns a copy of the configuration without the proxy password, for echoing it back to
+// the caller
+func (p ProxyConfig) Redacted() *ProxyConfig {
+	p.Password = ""
+	return &p
+}
+
 type CreateSessionRequest struct {
 	Name        string       `json:"name" validate:"required,min=3,max=50" example:"my-session"`
 	QrCode      bool         `json:"qrCode" example:"false"`
@@ -40,6 +57,8 @@ type ListSessionsRequest struct {
 	DeviceJid   *string `json:"deviceJid,omitempty" query:"deviceJid" example:"5511999999999@s.Wameow.net"`
 	Limit       int     `json:"limit,omitempty" query:"limit" validate:"omitempty,min=1,max=100" example:"20"`
 	Offset      int     `json:"offset,omitempty" query:"offset" validate:"omitempty,min=0" example:"0"`
+	// Cursor continues from the nextCursor of the previous page; it replaces offset
+	Cursor string `json:"cursor,omitempty" query:"cursor" example:"MjAyNC0wMS0wMVQxMjowMDowMFp8MWIyZTQyNGM"`
 } //@name ListSessionsRequest
 
 type ListSessionsResponse struct {
@@ -47,13 +66,112 @@ type ListSessionsResponse struct {
 	Total    int                   `json:"total" example:"10"`
 	Limit    int                   `json:"limit" example:"20"`
 	Offset   int                   `json:"offset" example:"0"`
+	// NextCursor fetches the next page; it is empty on the last page
+	NextCursor string `json:"nextCursor,omitempty"`
 } //@name ListSessionsResponse
 
 type SessionInfoResponse struct {
 	Session    *SessionResponse    `json:"session"`
 	DeviceInfo *DeviceInfoResponse `json:"deviceInfo,omitempty"`
+	// Warmup is set while the session is warming up a newly paired number
+	Warmup *warmup.WarmupStatusResponse `json:"warmup,omitempty"`
+	// SyncState tells whether the connected session is waiting for its phone
+	SyncState *SyncStateResponse `json:"syncState,omitempty"`
+	// Reconnect tells how a dropped session is being reconnected; not set when supervision is disabled
+	Reconnect *ReconnectStateResponse `json:"reconnect,omitempty"`
+	// Health tells whether the webhooks and Chatwoot relay of the session are degraded
+	Health *IntegrationHealthResponse `json:"health,omitempty"`
+	// Stats holds the message counters of the connected session; only set with expand=stats
+	Stats *SessionStatsResponse `json:"stats,omitempty"`
+	// Webhooks lists the webhooks of the session; only set with expand=webhooks
+	Webhooks []appWebhook.WebhookResponse `json:"webhooks,omitempty"`
 } //@name SessionInfoResponse
 
+type SessionStatsResponse struct {
+	MessagesSent     int64 `json:"messagesSent" example:"120"`
+	MessagesReceived int64 `json:"messagesReceived" example:"340"`
+	LastActivity     int64 `json:"lastActivity" example:"1704067200"` // Unix time of the last sent or received message
+	Uptime           int64 `json:"uptime" example:"3600"`             // Seconds since the session connected
+} //@name SessionStatsResponse
+
+// SessionStatsRequest selects the UTC days of a stats report, both included; it defaults to the last 30 days
+type SessionStatsRequest struct {
+	From string `json:"from,omitempty" query:"from" example:"2024-01-01"`
+	To   string `json:"to,omitempty" query:"to" example:"2024-01-31"`
+} //@name SessionStatsRequest
+
+type MessageTypeStatsResponse struct {
+	Type     string `json:"type" example:"image"` // text, image, audio, video, document, sticker, location, contact, poll, reaction, template, ...
+	Inbound  int64  `json:"inbound" example:"42"`
+	Outbound int64  `json:"outbound" example:"17"`
+} //@name MessageTypeStatsResponse
+
+type DailyMessageStatsResponse struct {
+	Day      string                     `json:"day" example:"2024-01-15"`
+	Inbound  int64                      `json:"inbound" example:"12"`
+	Outbound int64                      `json:"outbound" example:"5"`
+	ByType   []MessageTypeStatsResponse `json:"byType"`
+} //@name DailyMessageStatsResponse
+
+// SessionStatsReportResponse is the message breakdown of a session by type and direction.
+// Messages sent from the phone count as outbound; edits, revokes and receipts are not counted.
+type SessionStatsReportResponse struct {
+	From     string                      `json:"from" example:"2024-01-01"`
+	To       string                      `json:"to" example:"2024-01-31"`
+	Inbound  int64                       `json:"inbound" example:"340"`
+	Outbound int64                       `json:"outbound" example:"120"`
+	ByType   []MessageTypeStatsResponse  `json:"byType"`
+	Days     []DailyMessageStatsResponse `json:"days"` // Only days with messages, oldest first
+	// Live holds the in-memory counters of the connected client since it started
+	Live *SessionStatsResponse `json:"live,omitempty"`
+} //@name SessionStatsReportResponse
+
+type SyncStateResponse struct {
+	State           string     `json:"state" example:"waiting_for_phone"` // synced, waiting_for_phone
+	Reasons         []string   `json:"reasons,omitempty" example:"app_state_keys,message_resend"`
+	PendingMessages int        `json:"pendingMessages,omitempty" example:"2"` // Undecryptable messages waiting for the phone to resend them
+	WaitingSince    *time.Time `json:"waitingSince,omitempty" example:"2024-01-01T00:00:00Z"`
+} //@name SyncStateResponse
+
+type ReconnectStateResponse struct {
+	State          string     `json:"state" example:"waiting"`                 // idle, waiting, reconnecting, connected, gave_up
+	Reason         string     `json:"reason,omitempty" example:"disconnected"` // startup, disconnected, stream_replaced, probe
+	Attempts       int        `json:"attempts" example:"3"`
+	LastError      string     `json:"lastError,omitempty" example:"session did not connect in time"`
+	DisconnectedAt *time.Time `json:"disconnectedAt,omitempty" example:"2024-01-01T00:00:00Z"`
+	NextAttemptAt  *time.Time `json:"nextAttemptAt,omitempty" example:"2024-01-01T00:00:08Z"`
+	ConnectedAt    *time.Time `json:"connectedAt,omitempty" example:"2024-01-01T00:00:00Z"`
+} //@name ReconnectStateResponse
+
+type IntegrationHealthResponse struct {
+	Status        string                 `json:"status" example:"degraded"` // healthy, degraded
+	Reasons       []string               `json:"reasons,omitempty" example:"webhook success rate 62%"`
+	WindowSeconds int                    `json:"windowSeconds" example:"900"` // Period the webhook and Chatwoot figures cover
+	Webhooks      WebhookHealthResponse  `json:"webhooks"`
+	Chatwoot      ChatwootHealthResponse `json:"chatwoot"`
+	Queues        []QueueHealthResponse  `json:"queues"`
+} //@name IntegrationHealthResponse
+
+type WebhookHealthResponse struct {
+	Delivered     int        `json:"delivered" example:"5"`
+	Failed        int        `json:"failed" example:"3"`
+	SuccessRate   *float64   `json:"successRate,omitempty" example:"0.625"` // Unset without deliveries in the window
+	LastFailureAt *time.Time `json:"lastFailureAt,omitempty" example:"2024-01-01T12:00:00Z"`
+	LastError     string     `json:"lastError,omitempty" example:"HTTP 502"`
+} //@name WebhookHealthResponse
+
+type ChatwootHealthResponse struct {
+	Errors      int        `json:"errors" example:"0"`
+	LastErrorAt *time.Time `json:"lastErrorAt,omitempty" example:"2024-01-01T12:00:00Z"`
+	LastError   string     `json:"lastError,omitempty" example:"failed to get Chatwoot client"`
+} //@name ChatwootHealthResponse
+
+type QueueHealthResponse struct {
+	Name     string `json:"name" example:"webhook_delivery"`
+	Depth    int    `json:"depth" example:"12"`
+	Capacity int    `json:"capacity" example:"1000"`
+} //@name QueueHealthResponse
+
 type SessionResponse struct {
 	ID              string       `json:"id" example:"session-123"`
 	Name            string       `json:"name" example:"my-Wameow-session"`
@@ -64,8 +182,21 @@ type SessionResponse struct {
 	CreatedAt       time.Time    `json:"createdAt" example:"2024-01-01T00:00:00Z"`
 	UpdatedAt       time.Time    `json:"updatedAt" example:"2024-01-01T00:00:00Z"`
 	ConnectedAt     *time.Time   `json:"connectedAt,omitempty" example:"2024-01-01T00:00:30Z"`
+	LastSeen        *time.Time   `json:"lastSeen,omitempty" example:"2024-01-01T12:00:00Z"`
+	// Stale is set when the session has had no activity for longer than the stale session policy allows
+	Stale bool `json:"stale" example:"false"`
+	// Warning is the last account violation or ban warning WhatsApp reported, until it is cleared
+	Warning *SessionWarningResponse `json:"warning,omitempty"`
 } //@name SessionResponse
 
+type SessionWarningResponse struct {
+	Kind       string     `json:"kind" example:"temporary_ban"`
+	Code       int        `json:"code" example:"101"`
+	Reason     string     `json:"reason,omitempty" example:"you sent too many messages to people who don't have you in their address books"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty" example:"2024-01-02T00:00:00Z"`
+	ReceivedAt time.Time  `json:"receivedAt" example:"2024-01-01T00:00:00Z"`
+} //@name SessionWarningResponse
+
 type DeviceInfoResponse struct {
 	Platform    string `json:"platform" example:"android"`
 	DeviceModel string `json:"deviceModel" example:"Samsung Galaxy S21"`
@@ -92,6 +223,11 @@ type ProxyResponse struct {
 	ProxyConfig *ProxyConfig `json:"proxyConfig,omitempty"`
 } //@name ProxyResponse
 
+type UpdateProxyResponse struct {
+	ProxyConfig *ProxyConfig `json:"proxyConfig,omitempty"`
+	Reconnected bool         `json:"reconnected" example:"true"`
+} //@name UpdateProxyResponse
+
 type ConnectSessionResponse struct {
 	Success bool   `json:"success" example:"true"`
 	Message string `json:"message" example:"Session connection initiated successfully"`
@@ -99,15 +235,28 @@ type ConnectSessionResponse struct {
 	Code    string `json:"code,omitempty" example:"2@abc123..."`
 } //@name ConnectSessionResponse
 
+type EventHandlerResponse struct {
+	ID           string    `json:"id" example:"handler_1700000000000000000"`
+	Attached     bool      `json:"attached" example:"true"`
+	RegisteredAt time.Time `json:"registeredAt" example:"2024-01-01T00:00:00Z"`
+} //@name EventHandlerResponse
+
+type ListEventHandlersResponse struct {
+	Handlers []*EventHandlerResponse `json:"handlers"`
+	Total    int                     `json:"total" example:"1"`
+} //@name ListEventHandlersResponse
+
 func (r *CreateSessionRequest) ToCreateSessionRequest() *domainSession.CreateSessionRequest {
 	var proxyConfig *domainSession.ProxyConfig
 	if r.ProxyConfig != nil {
 		proxyConfig = &domainSession.ProxyConfig{
-			Type:     r.ProxyConfig.Type,
-			Host:     r.ProxyConfig.Host,
-			Port:     r.ProxyConfig.Port,
-			Username: r.ProxyConfig.Username,
-			Password: r.ProxyConfig.Password,
+			Type:         r.ProxyConfig.Type,
+			Host:         r.ProxyConfig.Host,
+			Port:         r.ProxyConfig.Port,
+			Username:     r.ProxyConfig.Username,
+			Password:     r.ProxyConfig.Password,
+			LocalAddress: r.ProxyConfig.LocalAddress,
+			Interface:    r.ProxyConfig.Interface,
 		}
 	}
 	return &domainSession.CreateSessionRequest{
@@ -121,11 +270,13 @@ func FromSession(s *domainSession.Session) *SessionResponse {
 	var proxyConfig *ProxyConfig
 	if s.ProxyConfig != nil {
 		proxyConfig = &ProxyConfig{
-			Type:     s.ProxyConfig.Type,
-			Host:     s.ProxyConfig.Host,
-			Port:     s.ProxyConfig.Port,
-			Username: s.ProxyConfig.Username,
-			Password: s.ProxyConfig.Password,
+			Type:         s.ProxyConfig.Type,
+			Host:         s.ProxyConfig.Host,
+			Port:         s.ProxyConfig.Port,
+			Username:     s.ProxyConfig.Username,
+			Password:     s.ProxyConfig.Password,
+			LocalAddress: s.ProxyConfig.LocalAddress,
+			Interface:    s.ProxyConfig.Interface,
 		}
 	}
 
@@ -138,12 +289,23 @@ func FromSession(s *domainSession.Session) *SessionResponse {
 		CreatedAt:       s.CreatedAt,
 		UpdatedAt:       s.UpdatedAt,
 		ConnectedAt:     s.ConnectedAt,
+		LastSeen:        s.LastSeen,
 	}
 
 	if s.DeviceJid != "" {
 		response.DeviceJid = s.DeviceJid
 	}
 
+	if s.Warning != nil {
+		response.Warning = &SessionWarningResponse{
+			Kind:       s.Warning.Kind,
+			Code:       s.Warning.Code,
+			Reason:     s.Warning.Reason,
+			ExpiresAt:  s.Warning.ExpiresAt,
+			ReceivedAt: s.Warning.ReceivedAt,
+		}
+	}
+
 	return response
 }
 
@@ -166,6 +328,93 @@ func FromSessionInfo(si *domainSession.SessionInfo) *SessionInfoResponse {
 	return response
 }
 
+func FromSyncState(s *domainSession.SyncState) *SyncStateResponse {
+	return &SyncStateResponse{
+		State:           s.State,
+		Reasons:         s.Reasons,
+		PendingMessages: s.PendingMessages,
+		WaitingSince:    s.WaitingSince,
+	}
+}
+
+func FromReconnectState(s *domainSession.ReconnectState) *ReconnectStateResponse {
+	return &ReconnectStateResponse{
+		State:          s.State,
+		Reason:         s.Reason,
+		Attempts:       s.Attempts,
+		LastError:      s.LastError,
+		DisconnectedAt: s.DisconnectedAt,
+		NextAttemptAt:  s.NextAttemptAt,
+		ConnectedAt:    s.ConnectedAt,
+	}
+}
+
+func FromIntegrationHealth(h *activity.IntegrationHealth) *IntegrationHealthResponse {
+	response := &IntegrationHealthResponse{
+		Status:        h.Status,
+		Reasons:       h.Reasons,
+		WindowSeconds: int(h.Window.Seconds()),
+		Webhooks: WebhookHealthResponse{
+			Delivered:     h.Webhooks.Delivered,
+			Failed:        h.Webhooks.Failed,
+			SuccessRate:   h.Webhooks.SuccessRate,
+			LastFailureAt: h.Webhooks.LastFailureAt,
+			LastError:     h.Webhooks.LastError,
+		},
+		Chatwoot: ChatwootHealthResponse{
+			Errors:      h.Chatwoot.Errors,
+			LastErrorAt: h.Chatwoot.LastErrorAt,
+			LastError:   h.Chatwoot.LastError,
+		},
+		Queues: make([]QueueHealthResponse, len(h.Queues)),
+	}
+	for i, queue := range h.Queues {
+		response.Queues[i] = QueueHealthResponse{Name: queue.Name, Depth: queue.Depth, Capacity: queue.Capacity}
+	}
+	return response
+}
+
+func FromSessionStats(s *ports.SessionStats) *SessionStatsResponse {
+	return &SessionStatsResponse{
+		MessagesSent:     s.MessagesSent,
+		MessagesReceived: s.MessagesReceived,
+		LastActivity:     s.LastActivity,
+		Uptime:           s.Uptime,
+	}
+}
+
+func FromStatsReport(r *stats.Report) *SessionStatsReportResponse {
+	response := &SessionStatsReportResponse{
+		From:     r.From,
+		To:       r.To,
+		Inbound:  r.Inbound,
+		Outbound: r.Outbound,
+		ByType:   fromTypeTotals(r.ByType),
+		Days:     make([]DailyMessageStatsResponse, len(r.Days)),
+	}
+	for i, day := range r.Days {
+		response.Days[i] = DailyMessageStatsResponse{
+			Day:      day.Day,
+			Inbound:  day.Inbound,
+			Outbound: day.Outbound,
+			ByType:   fromTypeTotals(day.ByType),
+		}
+	}
+	return response
+}
+
+func fromTypeTotals(totals []*stats.TypeTotal) []MessageTypeStatsResponse {
+	responses := make([]MessageTypeStatsResponse, len(totals))
+	for i, total := range totals {
+		responses[i] = MessageTypeStatsResponse{
+			Type:     total.Type,
+			Inbound:  total.Inbound,
+			Outbound: total.Outbound,
+		}
+	}
+	return responses
+}
+
 func FromQRCodeResponse(qr *domainSession.QRCodeResponse) *QRCodeResponse {
 	return &QRCodeResponse{
 		QRCode:      qr.QRCode,
@@ -174,3 +423,19 @@ func FromQRCodeResponse(qr *domainSession.QRCodeResponse) *QRCodeResponse {
 		Timeout:     qr.Timeout,
 	}
 }
+
+func FromEventHandlerRegistrations(registrations []*ports.EventHandlerRegistration) *ListEventHandlersResponse {
+	handlers := make([]*EventHandlerResponse, 0, len(registrations))
+	for _, registration := range registrations {
+		handlers = append(handlers, &EventHandlerResponse{
+			ID:           registration.ID,
+			Attached:     registration.Attached,
+			RegisteredAt: registration.RegisteredAt,
+		})
+	}
+
+	return &ListEventHandlersResponse{
+		Handlers: handlers,
+		Total:    len(handlers),
+	}
+}
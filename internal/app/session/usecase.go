@@ -4,11 +4,26 @@ import (
 	"context"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+
+	appWarmup "zpwoot/internal/app/warmup"
+	appWebhook "zpwoot/internal/app/webhook"
+	"zpwoot/internal/domain/activity"
 	"zpwoot/internal/domain/session"
+	"zpwoot/internal/domain/stats"
+	"zpwoot/internal/domain/usage"
+	"zpwoot/internal/domain/warmup"
+	"zpwoot/internal/domain/webhook"
 	"zpwoot/internal/ports"
 	"zpwoot/platform/logger"
+	"zpwoot/platform/pagination"
+	"zpwoot/platform/shaping"
+	"zpwoot/platform/tracing"
 )
 
+// maxExpandedWebhooks bounds the webhooks listed by expand=webhooks
+const maxExpandedWebhooks = 100
+
 type UseCase interface {
 	CreateSession(ctx context.Context, req *CreateSessionRequest) (*CreateSessionResponse, error)
 	ListSessions(ctx context.Context, req *ListSessionsRequest) (*ListSessionsResponse, error)
@@ -16,16 +31,26 @@ type UseCase interface {
 	DeleteSession(ctx context.Context, sessionID string) error
 	ConnectSession(ctx context.Context, sessionID string) (*ConnectSessionResponse, error)
 	LogoutSession(ctx context.Context, sessionID string) error
+	RestartSession(ctx context.Context, sessionID string) error
 	GetQRCode(ctx context.Context, sessionID string) (*QRCodeResponse, error)
 	PairPhone(ctx context.Context, sessionID string, req *PairPhoneRequest) error
 	SetProxy(ctx context.Context, sessionID string, req *SetProxyRequest) error
 	GetProxy(ctx context.Context, sessionID string) (*ProxyResponse, error)
+	UpdateProxy(ctx context.Context, sessionID string, req *SetProxyRequest) (*UpdateProxyResponse, error)
+	ListEventHandlers(ctx context.Context, sessionID string) (*ListEventHandlersResponse, error)
+	ClearWarning(ctx context.Context, sessionID string) error
+	GetSessionStats(ctx context.Context, sessionID string, req *SessionStatsRequest) (*SessionStatsReportResponse, error)
 }
 
 type useCaseImpl struct {
 	sessionRepo    ports.SessionRepository
 	WameowMgr      ports.WameowManager
 	sessionService *session.Service
+	usageService   *usage.Service
+	warmupService  *warmup.Service
+	webhookService *webhook.Service
+	statsService   *stats.Service
+	activity       *activity.Service
 	logger         *logger.Logger
 }
 
@@ -33,17 +58,31 @@ func NewUseCase(
 	sessionRepo ports.SessionRepository,
 	WameowMgr ports.WameowManager,
 	sessionService *session.Service,
+	usageService *usage.Service,
+	warmupService *warmup.Service,
+	webhookService *webhook.Service,
+	statsService *stats.Service,
+	activityService *activity.Service,
 	logger *logger.Logger,
 ) UseCase {
 	return &useCaseImpl{
 		sessionRepo:    sessionRepo,
 		WameowMgr:      WameowMgr,
 		sessionService: sessionService,
+		usageService:   usageService,
+		warmupService:  warmupService,
+		webhookService: webhookService,
+		statsService:   statsService,
+		activity:       activityService,
 		logger:         logger,
 	}
 }
 
 func (uc *useCaseImpl) CreateSession(ctx context.Context, req *CreateSessionRequest) (*CreateSessionResponse, error) {
+	if err := uc.usageService.CheckSessions(ctx, usage.WorkspaceFromContext(ctx)); err != nil {
+		return nil, err
+	}
+
 	domainReq := req.ToCreateSessionRequest()
 
 	sess, err := uc.sessionService.CreateSession(ctx, domainReq)
@@ -54,11 +93,13 @@ func (uc *useCaseImpl) CreateSession(ctx context.Context, req *CreateSessionRequ
 	var proxyConfig *ProxyConfig
 	if sess.ProxyConfig != nil {
 		proxyConfig = &ProxyConfig{
-			Type:     sess.ProxyConfig.Type,
-			Host:     sess.ProxyConfig.Host,
-			Port:     sess.ProxyConfig.Port,
-			Username: sess.ProxyConfig.Username,
-			Password: sess.ProxyConfig.Password,
+			Type:         sess.ProxyConfig.Type,
+			Host:         sess.ProxyConfig.Host,
+			Port:         sess.ProxyConfig.Port,
+			Username:     sess.ProxyConfig.Username,
+			Password:     sess.ProxyConfig.Password,
+			LocalAddress: sess.ProxyConfig.LocalAddress,
+			Interface:    sess.ProxyConfig.Interface,
 		}
 	}
 
@@ -100,6 +141,15 @@ func (uc *useCaseImpl) ListSessions(ctx context.Context, req *ListSessionsReques
 		domainReq.Limit = 20
 	}
 
+	after, err := pagination.Decode(req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	if after != nil {
+		domainReq.After = after
+		domainReq.Offset = 0
+	}
+
 	sessions, total, err := uc.sessionService.ListSessions(ctx, domainReq)
 	if err != nil {
 		return nil, err
@@ -111,6 +161,8 @@ func (uc *useCaseImpl) ListSessions(ctx context.Context, req *ListSessionsReques
 			Session: sess,
 		}
 		sessionResponses[i] = *FromSessionInfo(sessionInfo)
+		sessionResponses[i].Session.Stale = uc.sessionService.IsStale(sess)
+		uc.expandSessionInfo(ctx, &sessionResponses[i], sess.ID.String())
 	}
 
 	response := &ListSessionsResponse{
@@ -119,6 +171,10 @@ func (uc *useCaseImpl) ListSessions(ctx context.Context, req *ListSessionsReques
 		Limit:    domainReq.Limit,
 		Offset:   domainReq.Offset,
 	}
+	if len(sessions) > 0 {
+		last := sessions[len(sessions)-1]
+		response.NextCursor = pagination.Next(len(sessions), domainReq.Limit, last.CreatedAt, last.ID.String())
+	}
 
 	return response, nil
 }
@@ -134,16 +190,83 @@ func (uc *useCaseImpl) GetSessionInfo(ctx context.Context, sessionID string) (*S
 	}
 
 	response := FromSessionInfo(sessionInfo)
+	response.Session.Stale = uc.sessionService.IsStale(sess)
+
+	if uc.warmupService != nil {
+		status, err := uc.warmupService.GetStatus(ctx, sess.ID.String())
+		if err != nil {
+			uc.logger.WarnWithFields("Failed to get warm-up status", map[string]interface{}{
+				"session_id": sessionID,
+				"error":      err.Error(),
+			})
+		} else if status.Enabled {
+			response.Warmup = appWarmup.FromStatus(status)
+		}
+	}
+
+	if uc.WameowMgr != nil {
+		if syncState := uc.WameowMgr.GetSyncState(sess.ID.String()); syncState != nil {
+			response.SyncState = FromSyncState(syncState)
+		}
+		if reconnectState := uc.WameowMgr.GetReconnectState(sess.ID.String()); reconnectState != nil {
+			response.Reconnect = FromReconnectState(reconnectState)
+		}
+	}
+
+	if uc.activity != nil {
+		response.Health = FromIntegrationHealth(uc.activity.Health(sess.ID.String()))
+	}
+
+	uc.expandSessionInfo(ctx, response, sess.ID.String())
+
 	return response, nil
 }
 
+// expandSessionInfo adds the sub-resources the request asked for with expand; a sub-resource that
+// fails to load is logged and left out
+func (uc *useCaseImpl) expandSessionInfo(ctx context.Context, response *SessionInfoResponse, sessionID string) {
+	if shaping.Expands(ctx, "stats") && uc.WameowMgr != nil {
+		// Sessions without a client have no counters yet
+		if stats, err := uc.WameowMgr.GetSessionStats(sessionID); err == nil {
+			response.Stats = FromSessionStats(stats)
+		}
+	}
+
+	if shaping.Expands(ctx, "webhooks") && uc.webhookService != nil {
+		webhooks, _, err := uc.webhookService.ListWebhooks(ctx, &webhook.ListWebhooksRequest{
+			SessionID: &sessionID,
+			Limit:     maxExpandedWebhooks,
+		})
+		if err != nil {
+			uc.logger.WarnWithFields("Failed to expand session webhooks", map[string]interface{}{
+				"session_id": sessionID,
+				"error":      err.Error(),
+			})
+			return
+		}
+
+		response.Webhooks = make([]appWebhook.WebhookResponse, len(webhooks))
+		for i, wh := range webhooks {
+			response.Webhooks[i] = *appWebhook.FromWebhook(wh)
+		}
+	}
+}
+
 func (uc *useCaseImpl) DeleteSession(ctx context.Context, sessionID string) error {
 	return uc.sessionService.DeleteSession(ctx, sessionID)
 }
 
 func (uc *useCaseImpl) ConnectSession(ctx context.Context, sessionID string) (*ConnectSessionResponse, error) {
+	ctx, span := tracing.Start(ctx, "session.ConnectSession", tracing.KindInternal)
+	span.SetAttributes(attribute.String("zpwoot.session", sessionID))
+	defer span.End()
+
+	// The QR codes and connection events that follow are traced back to this request
+	uc.WameowMgr.TraceSessionRequest(ctx, sessionID)
+
 	err := uc.sessionService.ConnectSession(ctx, sessionID)
 	if err != nil {
+		tracing.RecordError(span, err)
 		return nil, err
 	}
 
@@ -163,7 +286,25 @@ func (uc *useCaseImpl) ConnectSession(ctx context.Context, sessionID string) (*C
 }
 
 func (uc *useCaseImpl) LogoutSession(ctx context.Context, sessionID string) error {
-	return uc.sessionService.LogoutSession(ctx, sessionID)
+	ctx, span := tracing.Start(ctx, "session.LogoutSession", tracing.KindInternal)
+	span.SetAttributes(attribute.String("zpwoot.session", sessionID))
+	defer span.End()
+
+	uc.WameowMgr.TraceSessionRequest(ctx, sessionID)
+	err := uc.sessionService.LogoutSession(ctx, sessionID)
+	tracing.RecordError(span, err)
+	return err
+}
+
+func (uc *useCaseImpl) RestartSession(ctx context.Context, sessionID string) error {
+	ctx, span := tracing.Start(ctx, "session.RestartSession", tracing.KindInternal)
+	span.SetAttributes(attribute.String("zpwoot.session", sessionID))
+	defer span.End()
+
+	uc.WameowMgr.TraceSessionRequest(ctx, sessionID)
+	err := uc.sessionService.RestartSession(ctx, sessionID)
+	tracing.RecordError(span, err)
+	return err
 }
 
 func (uc *useCaseImpl) GetQRCode(ctx context.Context, sessionID string) (*QRCodeResponse, error) {
@@ -182,15 +323,40 @@ func (uc *useCaseImpl) PairPhone(ctx context.Context, sessionID string, req *Pai
 
 func (uc *useCaseImpl) SetProxy(ctx context.Context, sessionID string, req *SetProxyRequest) error {
 	domainProxyConfig := &session.ProxyConfig{
-		Type:     req.ProxyConfig.Type,
-		Host:     req.ProxyConfig.Host,
-		Port:     req.ProxyConfig.Port,
-		Username: req.ProxyConfig.Username,
-		Password: req.ProxyConfig.Password,
+		Type:         req.ProxyConfig.Type,
+		Host:         req.ProxyConfig.Host,
+		Port:         req.ProxyConfig.Port,
+		Username:     req.ProxyConfig.Username,
+		Password:     req.ProxyConfig.Password,
+		LocalAddress: req.ProxyConfig.LocalAddress,
+		Interface:    req.ProxyConfig.Interface,
 	}
 	return uc.sessionService.SetProxy(ctx, sessionID, domainProxyConfig)
 }
 
+// UpdateProxy changes the session's proxy and reconnects the session with it
+func (uc *useCaseImpl) UpdateProxy(ctx context.Context, sessionID string, req *SetProxyRequest) (*UpdateProxyResponse, error) {
+	domainProxyConfig := &session.ProxyConfig{
+		Type:         req.ProxyConfig.Type,
+		Host:         req.ProxyConfig.Host,
+		Port:         req.ProxyConfig.Port,
+		Username:     req.ProxyConfig.Username,
+		Password:     req.ProxyConfig.Password,
+		LocalAddress: req.ProxyConfig.LocalAddress,
+		Interface:    req.ProxyConfig.Interface,
+	}
+
+	reconnected, err := uc.sessionService.UpdateProxy(ctx, sessionID, domainProxyConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UpdateProxyResponse{
+		ProxyConfig: req.ProxyConfig.Redacted(),
+		Reconnected: reconnected,
+	}, nil
+}
+
 func (uc *useCaseImpl) GetProxy(ctx context.Context, sessionID string) (*ProxyResponse, error) {
 	proxyConfig, err := uc.sessionService.GetProxy(ctx, sessionID)
 	if err != nil {
@@ -200,11 +366,13 @@ func (uc *useCaseImpl) GetProxy(ctx context.Context, sessionID string) (*ProxyRe
 	var appProxyConfig *ProxyConfig
 	if proxyConfig != nil {
 		appProxyConfig = &ProxyConfig{
-			Type:     proxyConfig.Type,
-			Host:     proxyConfig.Host,
-			Port:     proxyConfig.Port,
-			Username: proxyConfig.Username,
-			Password: proxyConfig.Password,
+			Type:         proxyConfig.Type,
+			Host:         proxyConfig.Host,
+			Port:         proxyConfig.Port,
+			Username:     proxyConfig.Username,
+			Password:     proxyConfig.Password,
+			LocalAddress: proxyConfig.LocalAddress,
+			Interface:    proxyConfig.Interface,
 		}
 	}
 
@@ -214,3 +382,32 @@ func (uc *useCaseImpl) GetProxy(ctx context.Context, sessionID string) (*ProxyRe
 
 	return response, nil
 }
+
+func (uc *useCaseImpl) ListEventHandlers(ctx context.Context, sessionID string) (*ListEventHandlersResponse, error) {
+	return FromEventHandlerRegistrations(uc.WameowMgr.ListEventHandlers(sessionID)), nil
+}
+
+// ClearWarning removes the account warning kept on the session once an operator has dealt with it
+func (uc *useCaseImpl) ClearWarning(ctx context.Context, sessionID string) error {
+	return uc.sessionRepo.SetWarning(ctx, sessionID, nil)
+}
+
+// GetSessionStats returns the stored per-type message breakdown of a session for a day range, with the
+// live counters of the running client when it is connected
+func (uc *useCaseImpl) GetSessionStats(ctx context.Context, sessionID string, req *SessionStatsRequest) (*SessionStatsReportResponse, error) {
+	report, err := uc.statsService.GetReport(ctx, &stats.ReportRequest{
+		SessionID: sessionID,
+		From:      req.From,
+		To:        req.To,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := FromStatsReport(report)
+	if live, err := uc.WameowMgr.GetSessionStats(sessionID); err == nil {
+		response.Live = FromSessionStats(live)
+	}
+
+	return response, nil
+}
@@ -0,0 +1,29 @@
+package settings
+
+import (
+	"time"
+
+	"zpwoot/internal/domain/settings"
+)
+
+type UpdateSettingsRequest struct {
+	LogLevel         *string         `json:"logLevel,omitempty" example:"debug"` // trace, debug, info, warn or error
+	DebugBodyLogging *bool           `json:"debugBodyLogging,omitempty" example:"true"`
+	Flags            map[string]bool `json:"flags,omitempty"` // Feature flags to turn on or off
+} //@name UpdateSettingsRequest
+
+type SettingsResponse struct {
+	LogLevel         string          `json:"logLevel" example:"info"`
+	DebugBodyLogging bool            `json:"debugBodyLogging" example:"false"`
+	Flags            map[string]bool `json:"flags"`
+	UpdatedAt        *time.Time      `json:"updatedAt,omitempty" example:"2024-01-01T12:00:00Z"`
+} //@name SettingsResponse
+
+func FromSettings(s *settings.Settings) *SettingsResponse {
+	return &SettingsResponse{
+		LogLevel:         s.LogLevel,
+		DebugBodyLogging: s.DebugBodyLogging,
+		Flags:            s.Flags,
+		UpdatedAt:        s.UpdatedAt,
+	}
+}
@@ -0,0 +1,49 @@
+package settings
+
+import (
+	"context"
+
+	"zpwoot/internal/domain/settings"
+)
+
+type UseCase interface {
+	GetSettings(ctx context.Context) *SettingsResponse
+	UpdateSettings(ctx context.Context, req *UpdateSettingsRequest) (*SettingsResponse, error)
+	DebugBodyLogging() bool
+	FlagEnabled(name string) bool
+}
+
+type useCaseImpl struct {
+	settingsService *settings.Service
+}
+
+func NewUseCase(settingsService *settings.Service) UseCase {
+	return &useCaseImpl{
+		settingsService: settingsService,
+	}
+}
+
+func (uc *useCaseImpl) GetSettings(ctx context.Context) *SettingsResponse {
+	return FromSettings(uc.settingsService.Current())
+}
+
+func (uc *useCaseImpl) UpdateSettings(ctx context.Context, req *UpdateSettingsRequest) (*SettingsResponse, error) {
+	updated, err := uc.settingsService.Update(ctx, &settings.UpdateRequest{
+		LogLevel:         req.LogLevel,
+		DebugBodyLogging: req.DebugBodyLogging,
+		Flags:            req.Flags,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return FromSettings(updated), nil
+}
+
+func (uc *useCaseImpl) DebugBodyLogging() bool {
+	return uc.settingsService.DebugBodyLogging()
+}
+
+func (uc *useCaseImpl) FlagEnabled(name string) bool {
+	return uc.settingsService.FlagEnabled(name)
+}
@@ -0,0 +1,42 @@
+package translation
+
+import (
+	"time"
+
+	"zpwoot/internal/domain/translation"
+)
+
+type UpdateTranslationConfigRequest struct {
+	Enabled        *bool   `json:"enabled,omitempty" example:"true"`
+	Inbound        *bool   `json:"inbound,omitempty" example:"true"`      // Translate incoming messages before webhook delivery
+	Outbound       *bool   `json:"outbound,omitempty" example:"false"`    // Translate outgoing messages before they are sent
+	SourceLanguage *string `json:"sourceLanguage,omitempty" example:"es"` // Language contacts write in; "auto" detects it for incoming messages
+	TargetLanguage *string `json:"targetLanguage,omitempty" example:"en"` // Language operators read and write in
+} //@name UpdateTranslationConfigRequest
+
+type TranslationConfigResponse struct {
+	Enabled        bool   `json:"enabled" example:"true"`
+	Inbound        bool   `json:"inbound" example:"true"`
+	Outbound       bool   `json:"outbound" example:"false"`
+	SourceLanguage string `json:"sourceLanguage" example:"es"`
+	TargetLanguage string `json:"targetLanguage" example:"en"`
+	// ProviderConfigured is false when TRANSLATION_URL is not set; nothing is translated then
+	ProviderConfigured bool       `json:"providerConfigured" example:"true"`
+	UpdatedAt          *time.Time `json:"updatedAt,omitempty" example:"2024-01-01T12:00:00Z"`
+} //@name TranslationConfigResponse
+
+func FromConfig(c *translation.Config, providerConfigured bool) *TranslationConfigResponse {
+	response := &TranslationConfigResponse{
+		Enabled:            c.Enabled,
+		Inbound:            c.Inbound,
+		Outbound:           c.Outbound,
+		SourceLanguage:     c.SourceLanguage,
+		TargetLanguage:     c.TargetLanguage,
+		ProviderConfigured: providerConfigured,
+	}
+	if !c.UpdatedAt.IsZero() {
+		updatedAt := c.UpdatedAt
+		response.UpdatedAt = &updatedAt
+	}
+	return response
+}
@@ -0,0 +1,46 @@
+package translation
+
+import (
+	"context"
+
+	"zpwoot/internal/domain/translation"
+)
+
+type UseCase interface {
+	GetConfig(ctx context.Context, sessionID string) (*TranslationConfigResponse, error)
+	UpdateConfig(ctx context.Context, sessionID string, req *UpdateTranslationConfigRequest) (*TranslationConfigResponse, error)
+}
+
+type useCaseImpl struct {
+	translationService *translation.Service
+}
+
+func NewUseCase(translationService *translation.Service) UseCase {
+	return &useCaseImpl{
+		translationService: translationService,
+	}
+}
+
+func (uc *useCaseImpl) GetConfig(ctx context.Context, sessionID string) (*TranslationConfigResponse, error) {
+	config, err := uc.translationService.GetConfig(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return FromConfig(config, uc.translationService.Available()), nil
+}
+
+func (uc *useCaseImpl) UpdateConfig(ctx context.Context, sessionID string, req *UpdateTranslationConfigRequest) (*TranslationConfigResponse, error) {
+	config, err := uc.translationService.UpdateConfig(ctx, sessionID, &translation.UpdateConfigRequest{
+		Enabled:        req.Enabled,
+		Inbound:        req.Inbound,
+		Outbound:       req.Outbound,
+		SourceLanguage: req.SourceLanguage,
+		TargetLanguage: req.TargetLanguage,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return FromConfig(config, uc.translationService.Available()), nil
+}
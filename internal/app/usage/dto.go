@@ -0,0 +1,77 @@
+package usage
+
+import (
+	"time"
+
+	"zpwoot/internal/domain/usage"
+)
+
+type ListUsageRequest struct {
+	Workspace string `json:"workspace,omitempty" query:"workspace" example:"default"`
+	Metric    string `json:"metric,omitempty" query:"metric" example:"messages"` // messages or media_bytes
+	From      string `json:"from,omitempty" query:"from" example:"2024-01-01"`   // First period, day or month
+	To        string `json:"to,omitempty" query:"to" example:"2024-01-31"`       // Last period, day or month
+} //@name ListUsageRequest
+
+type QuotaLimits struct {
+	MessagesPerDay  int64 `json:"messagesPerDay" example:"1000"`
+	MediaMBPerMonth int64 `json:"mediaMbPerMonth" example:"500"`
+	MaxSessions     int   `json:"maxSessions" example:"5"`
+} //@name QuotaLimits
+
+type UsageResponse struct {
+	Workspace       string      `json:"workspace" example:"default"`
+	Day             string      `json:"day" example:"2024-01-01"`
+	Month           string      `json:"month" example:"2024-01"`
+	MessagesToday   int64       `json:"messagesToday" example:"120"`
+	MediaBytesMonth int64       `json:"mediaBytesMonth" example:"10485760"`
+	Sessions        int         `json:"sessions" example:"2"`
+	Limits          QuotaLimits `json:"limits"`
+} //@name UsageResponse
+
+type UsageCounter struct {
+	Workspace string    `json:"workspace" example:"default"`
+	Metric    string    `json:"metric" example:"messages"`
+	Period    string    `json:"period" example:"2024-01-01"`
+	Value     int64     `json:"value" example:"120"`
+	UpdatedAt time.Time `json:"updatedAt" example:"2024-01-01T12:00:00Z"`
+} //@name UsageCounter
+
+type ListUsageResponse struct {
+	Counters []UsageCounter `json:"counters"`
+	Total    int            `json:"total" example:"31"`
+} //@name ListUsageResponse
+
+func FromUsage(u *usage.Usage) *UsageResponse {
+	return &UsageResponse{
+		Workspace:       u.Workspace,
+		Day:             u.Day,
+		Month:           u.Month,
+		MessagesToday:   u.MessagesToday,
+		MediaBytesMonth: u.MediaBytesMonth,
+		Sessions:        u.Sessions,
+		Limits: QuotaLimits{
+			MessagesPerDay:  u.Limits.MessagesPerDay,
+			MediaMBPerMonth: u.Limits.MediaMBPerMonth,
+			MaxSessions:     u.Limits.MaxSessions,
+		},
+	}
+}
+
+func FromCounters(counters []*usage.Counter) *ListUsageResponse {
+	responses := make([]UsageCounter, len(counters))
+	for i, c := range counters {
+		responses[i] = UsageCounter{
+			Workspace: c.Workspace,
+			Metric:    c.Metric,
+			Period:    c.Period,
+			Value:     c.Value,
+			UpdatedAt: c.UpdatedAt,
+		}
+	}
+
+	return &ListUsageResponse{
+		Counters: responses,
+		Total:    len(responses),
+	}
+}
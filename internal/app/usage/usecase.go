@@ -0,0 +1,49 @@
+package usage
+
+import (
+	"context"
+
+	"zpwoot/internal/domain/usage"
+)
+
+type UseCase interface {
+	GetUsage(ctx context.Context, workspace string) (*UsageResponse, error)
+	ListUsage(ctx context.Context, req *ListUsageRequest) (*ListUsageResponse, error)
+}
+
+type useCaseImpl struct {
+	usageService *usage.Service
+}
+
+func NewUseCase(usageService *usage.Service) UseCase {
+	return &useCaseImpl{
+		usageService: usageService,
+	}
+}
+
+func (uc *useCaseImpl) GetUsage(ctx context.Context, workspace string) (*UsageResponse, error) {
+	if workspace == "" {
+		workspace = usage.DefaultWorkspace
+	}
+
+	u, err := uc.usageService.GetUsage(ctx, workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	return FromUsage(u), nil
+}
+
+func (uc *useCaseImpl) ListUsage(ctx context.Context, req *ListUsageRequest) (*ListUsageResponse, error) {
+	counters, err := uc.usageService.ListCounters(ctx, &usage.ListCountersRequest{
+		Workspace: req.Workspace,
+		Metric:    req.Metric,
+		From:      req.From,
+		To:        req.To,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return FromCounters(counters), nil
+}
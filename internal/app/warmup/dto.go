@@ -0,0 +1,46 @@
+package warmup
+
+import (
+	"time"
+
+	"zpwoot/internal/domain/warmup"
+)
+
+type UpdateWarmupRequest struct {
+	Enabled         *bool  `json:"enabled,omitempty" example:"true"`                      // Enabling a disabled warm-up starts it over from day 1
+	Restart         bool   `json:"restart,omitempty" example:"false"`                     // Start the schedule over from day 1
+	Schedule        *[]int `json:"schedule,omitempty" example:"20,40,80,150,250,400,600"` // Daily send cap of each warm-up day
+	MinDelaySeconds *int   `json:"minDelaySeconds,omitempty" example:"3"`                 // Minimum random delay between two sends
+	MaxDelaySeconds *int   `json:"maxDelaySeconds,omitempty" example:"10"`                // Maximum random delay between two sends
+} //@name UpdateWarmupRequest
+
+type WarmupStatusResponse struct {
+	Enabled bool `json:"enabled" example:"true"`
+	// Completed is true once every day of the schedule has passed; sends are no longer capped then
+	Completed       bool       `json:"completed" example:"false"`
+	StartedAt       *time.Time `json:"startedAt,omitempty" example:"2024-01-01T12:00:00Z"`
+	Day             int        `json:"day" example:"2"`
+	TotalDays       int        `json:"totalDays" example:"7"`
+	DailyCap        int        `json:"dailyCap" example:"40"`
+	SentToday       int        `json:"sentToday" example:"12"`
+	Remaining       int        `json:"remaining" example:"28"`
+	MinDelaySeconds int        `json:"minDelaySeconds" example:"3"`
+	MaxDelaySeconds int        `json:"maxDelaySeconds" example:"10"`
+	Schedule        []int      `json:"schedule"`
+} //@name WarmupStatusResponse
+
+func FromStatus(s *warmup.Status) *WarmupStatusResponse {
+	return &WarmupStatusResponse{
+		Enabled:         s.Enabled,
+		Completed:       s.Completed,
+		StartedAt:       s.StartedAt,
+		Day:             s.Day,
+		TotalDays:       s.TotalDays,
+		DailyCap:        s.DailyCap,
+		SentToday:       s.SentToday,
+		Remaining:       s.Remaining,
+		MinDelaySeconds: int(s.MinDelay / time.Second),
+		MaxDelaySeconds: int(s.MaxDelay / time.Second),
+		Schedule:        s.Schedule,
+	}
+}
@@ -0,0 +1,55 @@
+package warmup
+
+import (
+	"context"
+	"time"
+
+	"zpwoot/internal/domain/warmup"
+)
+
+type UseCase interface {
+	GetStatus(ctx context.Context, sessionID string) (*WarmupStatusResponse, error)
+	UpdateWarmup(ctx context.Context, sessionID string, req *UpdateWarmupRequest) (*WarmupStatusResponse, error)
+}
+
+type useCaseImpl struct {
+	warmupService *warmup.Service
+}
+
+func NewUseCase(warmupService *warmup.Service) UseCase {
+	return &useCaseImpl{
+		warmupService: warmupService,
+	}
+}
+
+func (uc *useCaseImpl) GetStatus(ctx context.Context, sessionID string) (*WarmupStatusResponse, error) {
+	status, err := uc.warmupService.GetStatus(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return FromStatus(status), nil
+}
+
+func (uc *useCaseImpl) UpdateWarmup(ctx context.Context, sessionID string, req *UpdateWarmupRequest) (*WarmupStatusResponse, error) {
+	update := &warmup.UpdateProfileRequest{
+		Enabled:  req.Enabled,
+		Restart:  req.Restart,
+		Schedule: req.Schedule,
+	}
+	if req.MinDelaySeconds != nil {
+		minDelay := time.Duration(*req.MinDelaySeconds) * time.Second
+		update.MinDelay = &minDelay
+	}
+	if req.MaxDelaySeconds != nil {
+		maxDelay := time.Duration(*req.MaxDelaySeconds) * time.Second
+		update.MaxDelay = &maxDelay
+	}
+
+	status, err := uc.warmupService.UpdateProfile(ctx, sessionID, update)
+	if err != nil {
+		return nil, err
+	}
+
+	return FromStatus(status), nil
+}
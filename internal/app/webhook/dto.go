@@ -1,9 +1,12 @@
 package webhook
 
 import (
+	"encoding/json"
+	"sort"
 	"time"
 
 	"zpwoot/internal/domain/webhook"
+	"zpwoot/internal/ports"
 )
 
 type SetConfigRequest struct {
@@ -12,22 +15,38 @@ type SetConfigRequest struct {
 	Secret    string   `json:"secret,omitempty" example:"my-webhook-secret-key-123"`
 	Events    []string `json:"events" validate:"required,min=1" example:"message,status,connection"`
 	Enabled   *bool    `json:"enabled,omitempty" example:"true"` // Whether webhook is enabled (default: true)
+	// TimeoutSeconds overrides the delivery timeout for this webhook (max 120); 0 uses WEBHOOK_TIMEOUT
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty" example:"10"`
+	// PayloadTemplate is a Go text/template rendering the JSON body from the default payload fields (.event, .sessionId, .timestamp, .data, ...); empty delivers the default payload
+	PayloadTemplate string `json:"payloadTemplate,omitempty" example:"{\"type\": {{json .event}}, \"from\": {{json .data.from}}}"`
+	// Shadow sets a webhook that receives a copy of the events next to the regular one; its deliveries are never retried nor reported as failures
+	Shadow bool `json:"shadow,omitempty" example:"false"`
+	// Chats limits the events about a chat to these chat JIDs or patterns (e.g. *@g.us); events not about a chat always pass
+	Chats []string `json:"chats,omitempty" example:"5511999999999@s.whatsapp.net,*@g.us"`
 } //@name SetConfigRequest
 
 type SetConfigResponse struct {
-	ID        string    `json:"id" example:"webhook-456def"`
-	SessionID *string   `json:"sessionId,omitempty" example:"1b2e424c-a2a0-41a4-b992-15b7ec06b9bc"`
-	URL       string    `json:"url" example:"https://myapp.com/webhook/whatsapp"`
-	Events    []string  `json:"events" example:"message,status,connection"`
-	Enabled   bool      `json:"enabled" example:"true"` // Whether webhook is enabled
-	CreatedAt time.Time `json:"createdAt" example:"2024-01-01T00:00:00Z"`
+	ID              string    `json:"id" example:"webhook-456def"`
+	SessionID       *string   `json:"sessionId,omitempty" example:"1b2e424c-a2a0-41a4-b992-15b7ec06b9bc"`
+	URL             string    `json:"url" example:"https://myapp.com/webhook/whatsapp"`
+	Events          []string  `json:"events" example:"message,status,connection"`
+	Enabled         bool      `json:"enabled" example:"true"` // Whether webhook is enabled
+	PayloadTemplate string    `json:"payloadTemplate,omitempty"`
+	TimeoutSeconds  int       `json:"timeoutSeconds,omitempty" example:"10"`
+	Shadow          bool      `json:"shadow,omitempty" example:"false"`
+	Chats           []string  `json:"chats,omitempty" example:"*@g.us"`
+	CreatedAt       time.Time `json:"createdAt" example:"2024-01-01T00:00:00Z"`
 } //@name SetConfigResponse
 
 type UpdateWebhookRequest struct {
-	URL     *string  `json:"url,omitempty" validate:"omitempty,url" example:"https://myapp.com/webhook/whatsapp/v2"`
-	Secret  *string  `json:"secret,omitempty" example:"updated-webhook-secret-456"`
-	Events  []string `json:"events,omitempty" validate:"omitempty,min=1" example:"message,status,connection,qr"`
-	Enabled *bool    `json:"enabled,omitempty" example:"false"` // Whether webhook is enabled
+	URL             *string  `json:"url,omitempty" validate:"omitempty,url" example:"https://myapp.com/webhook/whatsapp/v2"`
+	Secret          *string  `json:"secret,omitempty" example:"updated-webhook-secret-456"`
+	Events          []string `json:"events,omitempty" validate:"omitempty,min=1" example:"message,status,connection,qr"`
+	Enabled         *bool    `json:"enabled,omitempty" example:"false"`     // Whether webhook is enabled
+	PayloadTemplate *string  `json:"payloadTemplate,omitempty"`             // An empty string restores the default payload
+	TimeoutSeconds  *int     `json:"timeoutSeconds,omitempty" example:"10"` // 0 restores the WEBHOOK_TIMEOUT default
+	Shadow          *bool    `json:"shadow,omitempty" example:"false"`      // Whether webhook only receives a copy of the events
+	Chats           []string `json:"chats,omitempty" example:"*@g.us"`      // An empty list removes the chat filters
 } //@name UpdateWebhookRequest
 
 type ListWebhooksRequest struct {
@@ -35,6 +54,8 @@ type ListWebhooksRequest struct {
 	Enabled   *bool   `json:"enabled,omitempty" query:"enabled" example:"true"` // Filter by enabled status
 	Limit     int     `json:"limit,omitempty" query:"limit" validate:"omitempty,min=1,max=100" example:"20"`
 	Offset    int     `json:"offset,omitempty" query:"offset" validate:"omitempty,min=0" example:"0"`
+	// Cursor continues from the nextCursor of the previous page; it replaces offset
+	Cursor string `json:"cursor,omitempty" query:"cursor" example:"MjAyNC0wMS0wMVQxMjowMDowMFp8MWIyZTQyNGM"`
 } //@name ListWebhooksRequest
 
 type ListWebhooksResponse struct {
@@ -42,16 +63,22 @@ type ListWebhooksResponse struct {
 	Total    int               `json:"total" example:"5"`
 	Limit    int               `json:"limit" example:"20"`
 	Offset   int               `json:"offset" example:"0"`
+	// NextCursor fetches the next page; it is empty on the last page
+	NextCursor string `json:"nextCursor,omitempty"`
 } //@name ListWebhooksResponse
 
 type WebhookResponse struct {
-	ID        string    `json:"id" example:"webhook-123"`
-	SessionID *string   `json:"sessionId,omitempty" example:"session-123"`
-	URL       string    `json:"url" example:"https://example.com/webhook"`
-	Events    []string  `json:"events" example:"message,status"`
-	Enabled   bool      `json:"enabled" example:"true"` // Whether webhook is enabled
-	CreatedAt time.Time `json:"createdAt" example:"2024-01-01T00:00:00Z"`
-	UpdatedAt time.Time `json:"updatedAt" example:"2024-01-01T00:00:00Z"`
+	ID              string    `json:"id" example:"webhook-123"`
+	SessionID       *string   `json:"sessionId,omitempty" example:"session-123"`
+	URL             string    `json:"url" example:"https://example.com/webhook"`
+	Events          []string  `json:"events" example:"message,status"`
+	Enabled         bool      `json:"enabled" example:"true"` // Whether webhook is enabled
+	PayloadTemplate string    `json:"payloadTemplate,omitempty"`
+	TimeoutSeconds  int       `json:"timeoutSeconds,omitempty" example:"10"`
+	Shadow          bool      `json:"shadow,omitempty" example:"false"` // Whether webhook only receives a copy of the events
+	Chats           []string  `json:"chats,omitempty" example:"*@g.us"` // Chat filters of the events about a chat
+	CreatedAt       time.Time `json:"createdAt" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt       time.Time `json:"updatedAt" example:"2024-01-01T00:00:00Z"`
 } //@name WebhookResponse
 
 type WebhookEventResponse struct {
@@ -74,6 +101,65 @@ type TestWebhookResponse struct {
 	Error        string `json:"error,omitempty"`
 }
 
+type PreviewPayloadTemplateRequest struct {
+	// Template to render; empty previews the template of the session's webhook
+	Template  string                 `json:"template,omitempty" example:"{\"type\": {{json .event}}, \"text\": {{json .data.text}}}"`
+	EventType string                 `json:"eventType" validate:"required" example:"Message"`
+	Data      map[string]interface{} `json:"data,omitempty"` // Event data to render the template against
+} //@name PreviewPayloadTemplateRequest
+
+type PreviewPayloadTemplateResponse struct {
+	// Payload is the JSON body the webhook would receive
+	Payload json.RawMessage `json:"payload" swaggertype:"object"`
+} //@name PreviewPayloadTemplateResponse
+
+type ReplayEventsRequest struct {
+	From       time.Time `json:"from" validate:"required" example:"2024-01-01T00:00:00Z"` // Replays events timestamped from here (inclusive)
+	To         time.Time `json:"to" validate:"required" example:"2024-01-01T06:00:00Z"`   // up to here (exclusive)
+	EventTypes []string  `json:"eventTypes,omitempty" example:"Message,Receipt"`          // Event types to replay; empty replays all
+	// Limit caps the events replayed by this call (default 500, max 1000); continue from nextFrom when more are left
+	Limit int `json:"limit,omitempty" example:"500"`
+} //@name ReplayEventsRequest
+
+type ReplayEventsResponse struct {
+	Replayed int  `json:"replayed" example:"120"`
+	HasMore  bool `json:"hasMore" example:"false"`
+	// NextFrom is the from of the call that replays the events left
+	NextFrom *time.Time `json:"nextFrom,omitempty" example:"2024-01-01T03:12:45Z"`
+} //@name ReplayEventsResponse
+
+type UpdateDispatchRequest struct {
+	Workers *int  `json:"workers,omitempty" example:"10"`   // Delivery concurrency, 1 to 100
+	Paused  *bool `json:"paused,omitempty" example:"false"` // Pauses or resumes dispatch for every session
+} //@name UpdateDispatchRequest
+
+type SetSessionDispatchRequest struct {
+	Paused bool `json:"paused" example:"true"`
+} //@name SetSessionDispatchRequest
+
+type DispatchStatusResponse struct {
+	Workers       int `json:"workers" example:"5"`
+	QueueSize     int `json:"queueSize" example:"12"`
+	QueueCapacity int `json:"queueCapacity" example:"1000"`
+	// Parked counts the deliveries held back while dispatch is paused, here or by maintenance
+	Parked         int                     `json:"parked" example:"0"`
+	PausedAll      bool                    `json:"pausedAll" example:"false"`
+	PausedSessions []PausedSessionResponse `json:"pausedSessions"`
+} //@name DispatchStatusResponse
+
+type PausedSessionResponse struct {
+	SessionID string    `json:"sessionId" example:"1b2e424c-a2a0-41a4-b992-15b7ec06b9bc"`
+	PausedAt  time.Time `json:"pausedAt" example:"2024-01-01T00:00:00Z"`
+} //@name PausedSessionResponse
+
+type DrainDispatchResponse struct {
+	// Drained counts the events whose deliveries were removed from the queue
+	Drained int `json:"drained" example:"42"`
+	// ReplayFrom and ReplayTo are the from and to of the replay call that delivers the drained events again
+	ReplayFrom *time.Time `json:"replayFrom,omitempty" example:"2024-01-01T00:00:00Z"`
+	ReplayTo   *time.Time `json:"replayTo,omitempty" example:"2024-01-01T00:05:00Z"`
+} //@name DrainDispatchResponse
+
 type WebhookEventsResponse struct {
 	Events []WebhookEventInfo `json:"events"`
 }
@@ -86,20 +172,37 @@ type WebhookEventInfo struct {
 
 func (r *SetConfigRequest) ToSetConfigRequest() *webhook.SetConfigRequest {
 	return &webhook.SetConfigRequest{
-		SessionID: r.SessionID,
-		URL:       r.URL,
-		Secret:    r.Secret,
-		Events:    r.Events,
-		Enabled:   r.Enabled,
+		SessionID:       r.SessionID,
+		URL:             r.URL,
+		Secret:          r.Secret,
+		Events:          r.Events,
+		Enabled:         r.Enabled,
+		PayloadTemplate: r.PayloadTemplate,
+		TimeoutSeconds:  r.TimeoutSeconds,
+		Shadow:          r.Shadow,
+		Chats:           r.Chats,
 	}
 }
 
 func (r *UpdateWebhookRequest) ToUpdateWebhookRequest() *webhook.UpdateWebhookRequest {
 	return &webhook.UpdateWebhookRequest{
-		URL:     r.URL,
-		Secret:  r.Secret,
-		Events:  r.Events,
-		Enabled: r.Enabled,
+		URL:             r.URL,
+		Secret:          r.Secret,
+		Events:          r.Events,
+		Enabled:         r.Enabled,
+		PayloadTemplate: r.PayloadTemplate,
+		TimeoutSeconds:  r.TimeoutSeconds,
+		Shadow:          r.Shadow,
+		Chats:           r.Chats,
+	}
+}
+
+func (r *ReplayEventsRequest) ToReplayRequest() *webhook.ReplayRequest {
+	return &webhook.ReplayRequest{
+		From:       r.From,
+		To:         r.To,
+		EventTypes: r.EventTypes,
+		Limit:      r.Limit,
 	}
 }
 
@@ -114,13 +217,17 @@ func (r *ListWebhooksRequest) ToListWebhooksRequest() *webhook.ListWebhooksReque
 
 func FromWebhook(w *webhook.WebhookConfig) *WebhookResponse {
 	return &WebhookResponse{
-		ID:        w.ID.String(),
-		SessionID: w.SessionID,
-		URL:       w.URL,
-		Events:    w.Events,
-		Enabled:   w.Enabled,
-		CreatedAt: w.CreatedAt,
-		UpdatedAt: w.UpdatedAt,
+		ID:              w.ID.String(),
+		SessionID:       w.SessionID,
+		URL:             w.URL,
+		Events:          w.Events,
+		Enabled:         w.Enabled,
+		PayloadTemplate: w.PayloadTemplate,
+		TimeoutSeconds:  w.TimeoutSeconds,
+		Shadow:          w.Shadow,
+		Chats:           w.Chats,
+		CreatedAt:       w.CreatedAt,
+		UpdatedAt:       w.UpdatedAt,
 	}
 }
 
@@ -162,6 +269,82 @@ func GetSupportedEvents() *WebhookEventsResponse {
 				Description: "Triggered when phone pairing is successful",
 				DataSchema:  "PairEventData",
 			},
+			{
+				Type:        "newsletter.message",
+				Description: "Triggered when views or reactions change on a message of a newsletter with live updates subscribed",
+				DataSchema:  "NewsletterMessageUpdate",
+			},
+			{
+				Type:        "poll.vote",
+				Description: "Triggered when a participant votes on a poll sent by the session, with the selected options and current tallies",
+				DataSchema:  "PollVote",
+			},
+			{
+				Type:        "list.response",
+				Description: "Triggered when a recipient selects a row on a list message, with the row ID given when the list was sent",
+				DataSchema:  "ListResponse",
+			},
+			{
+				Type:        "message.pin",
+				Description: "Triggered when a message is pinned or unpinned in a chat from the phone, another device or by another participant, with who pinned it and when the pin lapses",
+				DataSchema:  "MessagePin",
+			},
+			{
+				Type:        "chat.ephemeral",
+				Description: "Triggered when the disappearing messages timer of a chat or group is changed or turned off, with the new duration in seconds. Message events of chats with a timer carry an ephemeral field with the duration and when the message disappears",
+				DataSchema:  "ChatEphemeral",
+			},
+			{
+				Type:        "flow.response",
+				Description: "Triggered when a recipient replies to a native flow message, with the button or flow name and the decoded response parameters",
+				DataSchema:  "FlowResponse",
+			},
+			{
+				Type:        "flood.detected",
+				Description: "Triggered once when a contact sends more messages than the flood protection allows; its messages are suppressed during the cooldown when configured",
+				DataSchema:  "FloodDetected",
+			},
+			{
+				Type:        "contact.updated",
+				Description: "Triggered when a contact's pushname, business name or avatar changes or the contact is added or renamed in the address book; also available through GET /sessions/{sessionId}/contacts/changes",
+				DataSchema:  "ContactUpdated",
+			},
+			{
+				Type:        "session.sync_state",
+				Description: "Triggered when a connected session has been waiting for its phone (app state keys not shared yet, or undecryptable messages only the phone can resend) for 30 seconds, and again when it no longer waits",
+				DataSchema:  "SessionSyncStateChanged",
+			},
+			{
+				Type:        "session.stale",
+				Description: "Triggered once when a session has had no received messages or connections for longer than STALE_SESSION_DAYS and STALE_SESSION_ACTION is notify or disconnect",
+				DataSchema:  "SessionStale",
+			},
+			{
+				Type:        "session.warning",
+				Description: "Triggered when WhatsApp reports a temporary ban, a ban or an account violation for the session; the warning is also kept on the session until cleared with DELETE /sessions/{sessionId}/warning",
+				DataSchema:  "SessionWarning",
+			},
 		},
 	}
 }
+
+func FromDispatchStatus(status *ports.WebhookDispatchStatus) *DispatchStatusResponse {
+	response := &DispatchStatusResponse{
+		Workers:        status.Workers,
+		QueueSize:      status.QueueSize,
+		QueueCapacity:  status.QueueCapacity,
+		Parked:         status.Parked,
+		PausedAll:      status.PausedAll,
+		PausedSessions: make([]PausedSessionResponse, 0, len(status.PausedSessions)),
+	}
+	for sessionID, pausedAt := range status.PausedSessions {
+		response.PausedSessions = append(response.PausedSessions, PausedSessionResponse{
+			SessionID: sessionID,
+			PausedAt:  pausedAt,
+		})
+	}
+	sort.Slice(response.PausedSessions, func(i, j int) bool {
+		return response.PausedSessions[i].PausedAt.Before(response.PausedSessions[j].PausedAt)
+	})
+	return response
+}
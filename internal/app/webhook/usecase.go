@@ -2,9 +2,12 @@ package webhook
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"zpwoot/internal/domain/webhook"
 	"zpwoot/internal/ports"
+	"zpwoot/platform/pagination"
 )
 
 type UseCase interface {
@@ -15,21 +18,42 @@ type UseCase interface {
 	ListWebhooks(ctx context.Context, req *ListWebhooksRequest) (*ListWebhooksResponse, error)
 	TestWebhook(ctx context.Context, webhookID string, req *TestWebhookRequest) (*TestWebhookResponse, error)
 	GetSupportedWebhookEvents(ctx context.Context) (*WebhookEventsResponse, error)
+	PreviewPayloadTemplate(ctx context.Context, sessionID string, req *PreviewPayloadTemplateRequest) (*PreviewPayloadTemplateResponse, error)
+	ReplayEvents(ctx context.Context, sessionID string, req *ReplayEventsRequest) (*ReplayEventsResponse, error)
+	GetDispatchStatus(ctx context.Context) *DispatchStatusResponse
+	UpdateDispatch(ctx context.Context, req *UpdateDispatchRequest) (*DispatchStatusResponse, error)
+	SetSessionDispatch(ctx context.Context, sessionID string, req *SetSessionDispatchRequest) *DispatchStatusResponse
+	// DrainSessionDispatch drops the pending deliveries of a paused session; the events stay in the event
+	// store, so a replay delivers them once the consumer is fixed
+	DrainSessionDispatch(ctx context.Context, sessionID string) (*DrainDispatchResponse, error)
 	ProcessWebhookEvent(ctx context.Context, event *webhook.WebhookEvent) error
+	// OpenEventStream subscribes to the events of a session matching the event types or patterns; close
+	// it with CloseEventStream
+	OpenEventStream(sessionID string, events []string) (*webhook.Subscription, error)
+	CloseEventStream(sub *webhook.Subscription)
 }
 
 type useCaseImpl struct {
 	webhookRepo    ports.WebhookRepository
 	webhookService *webhook.Service
+	eventStore     *webhook.EventStore
+	eventStream    *webhook.EventStream
+	dispatch       ports.WebhookDispatchControl
 }
 
 func NewUseCase(
 	webhookRepo ports.WebhookRepository,
 	webhookService *webhook.Service,
+	eventStore *webhook.EventStore,
+	eventStream *webhook.EventStream,
+	dispatch ports.WebhookDispatchControl,
 ) UseCase {
 	return &useCaseImpl{
 		webhookRepo:    webhookRepo,
 		webhookService: webhookService,
+		eventStore:     eventStore,
+		eventStream:    eventStream,
+		dispatch:       dispatch,
 	}
 }
 
@@ -42,12 +66,16 @@ func (uc *useCaseImpl) SetConfig(ctx context.Context, req *SetConfigRequest) (*S
 	}
 
 	response := &SetConfigResponse{
-		ID:        webhookConfig.ID.String(),
-		SessionID: webhookConfig.SessionID,
-		URL:       webhookConfig.URL,
-		Events:    webhookConfig.Events,
-		Enabled:   webhookConfig.Enabled,
-		CreatedAt: webhookConfig.CreatedAt,
+		ID:              webhookConfig.ID.String(),
+		SessionID:       webhookConfig.SessionID,
+		URL:             webhookConfig.URL,
+		Events:          webhookConfig.Events,
+		Enabled:         webhookConfig.Enabled,
+		PayloadTemplate: webhookConfig.PayloadTemplate,
+		TimeoutSeconds:  webhookConfig.TimeoutSeconds,
+		Shadow:          webhookConfig.Shadow,
+		Chats:           webhookConfig.Chats,
+		CreatedAt:       webhookConfig.CreatedAt,
 	}
 
 	return response, nil
@@ -86,6 +114,15 @@ func (uc *useCaseImpl) ListWebhooks(ctx context.Context, req *ListWebhooksReques
 		domainReq.Limit = 20
 	}
 
+	after, err := pagination.Decode(req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	if after != nil {
+		domainReq.After = after
+		domainReq.Offset = 0
+	}
+
 	webhooks, total, err := uc.webhookService.ListWebhooks(ctx, domainReq)
 	if err != nil {
 		return nil, err
@@ -102,6 +139,10 @@ func (uc *useCaseImpl) ListWebhooks(ctx context.Context, req *ListWebhooksReques
 		Limit:    domainReq.Limit,
 		Offset:   domainReq.Offset,
 	}
+	if len(webhooks) > 0 {
+		last := webhooks[len(webhooks)-1]
+		response.NextCursor = pagination.Next(len(webhooks), domainReq.Limit, last.CreatedAt, last.ID.String())
+	}
 
 	return response, nil
 }
@@ -139,6 +180,121 @@ func (uc *useCaseImpl) GetSupportedWebhookEvents(ctx context.Context) (*WebhookE
 	return GetSupportedEvents(), nil
 }
 
+func (uc *useCaseImpl) PreviewPayloadTemplate(ctx context.Context, sessionID string, req *PreviewPayloadTemplateRequest) (*PreviewPayloadTemplateResponse, error) {
+	template := req.Template
+	if template == "" {
+		webhookConfig, err := uc.webhookService.GetWebhookBySession(ctx, sessionID)
+		if err != nil {
+			return nil, err
+		}
+		if webhookConfig.PayloadTemplate == "" {
+			return nil, fmt.Errorf("%w: the session's webhook has no payload template", webhook.ErrInvalidPayloadTemplate)
+		}
+		template = webhookConfig.PayloadTemplate
+	}
+
+	data := req.Data
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	event := webhook.NewWebhookEvent(sessionID, req.EventType, data)
+
+	payload, err := uc.webhookService.PreviewPayloadTemplate(template, event)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PreviewPayloadTemplateResponse{Payload: payload}, nil
+}
+
 func (uc *useCaseImpl) ProcessWebhookEvent(ctx context.Context, event *webhook.WebhookEvent) error {
 	return uc.webhookService.ProcessEvent(ctx, event)
 }
+
+func (uc *useCaseImpl) ReplayEvents(ctx context.Context, sessionID string, req *ReplayEventsRequest) (*ReplayEventsResponse, error) {
+	if uc.eventStore == nil {
+		return nil, webhook.ErrEventStoreDisabled
+	}
+
+	result, err := uc.eventStore.Replay(ctx, sessionID, req.ToReplayRequest())
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReplayEventsResponse{
+		Replayed: result.Replayed,
+		HasMore:  result.HasMore,
+		NextFrom: result.NextFrom,
+	}, nil
+}
+
+func (uc *useCaseImpl) OpenEventStream(sessionID string, events []string) (*webhook.Subscription, error) {
+	if uc.eventStream == nil {
+		return nil, webhook.ErrStreamClosed
+	}
+	return uc.eventStream.Subscribe(sessionID, events)
+}
+
+func (uc *useCaseImpl) CloseEventStream(sub *webhook.Subscription) {
+	if uc.eventStream != nil {
+		uc.eventStream.Unsubscribe(sub)
+	}
+}
+
+func (uc *useCaseImpl) GetDispatchStatus(ctx context.Context) *DispatchStatusResponse {
+	return FromDispatchStatus(uc.dispatch.DispatchStatus())
+}
+
+func (uc *useCaseImpl) UpdateDispatch(ctx context.Context, req *UpdateDispatchRequest) (*DispatchStatusResponse, error) {
+	if req.Workers != nil {
+		if err := uc.dispatch.SetWorkers(*req.Workers); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Paused != nil {
+		if *req.Paused {
+			uc.dispatch.PauseDispatch("")
+		} else {
+			uc.dispatch.ResumeDispatch("")
+		}
+	}
+
+	return uc.GetDispatchStatus(ctx), nil
+}
+
+func (uc *useCaseImpl) SetSessionDispatch(ctx context.Context, sessionID string, req *SetSessionDispatchRequest) *DispatchStatusResponse {
+	if req.Paused {
+		uc.dispatch.PauseDispatch(sessionID)
+	} else {
+		uc.dispatch.ResumeDispatch(sessionID)
+	}
+
+	return uc.GetDispatchStatus(ctx)
+}
+
+func (uc *useCaseImpl) DrainSessionDispatch(ctx context.Context, sessionID string) (*DrainDispatchResponse, error) {
+	// Without the event store the drained events could not be delivered anymore
+	if uc.eventStore == nil || !uc.eventStore.Enabled() {
+		return nil, webhook.ErrEventStoreDisabled
+	}
+
+	events, err := uc.dispatch.DrainSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &DrainDispatchResponse{Drained: len(events)}
+	for _, event := range events {
+		if response.ReplayFrom == nil || event.Timestamp.Before(*response.ReplayFrom) {
+			from := event.Timestamp
+			response.ReplayFrom = &from
+		}
+		// The replay range excludes its to
+		if to := event.Timestamp.Add(time.Millisecond); response.ReplayTo == nil || to.After(*response.ReplayTo) {
+			response.ReplayTo = &to
+		}
+	}
+
+	return response, nil
+}
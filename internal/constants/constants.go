@@ -0,0 +1,6 @@
+package constants
+
+const (
+	GroupJIDSuffix      = "@g.us"
+	NewsletterJIDSuffix = "@newsletter"
+)
@@ -0,0 +1,116 @@
+package activity
+
+import "time"
+
+const (
+	// MaxRecentMessages bounds how many messages are kept per session
+	MaxRecentMessages = 100
+	// MaxWebhookFailures bounds how many permanent webhook failures are kept
+	MaxWebhookFailures = 200
+
+	messageEventType = "Message"
+)
+
+// RecentMessage is a message seen on a session's event stream
+type RecentMessage struct {
+	SessionID string
+	MessageID string
+	Chat      string
+	Sender    string
+	FromMe    bool
+	Type      string
+	Text      string
+	Timestamp time.Time
+}
+
+// WebhookFailure is a webhook delivery that failed after all retries
+type WebhookFailure struct {
+	WebhookID  string
+	URL        string
+	SessionID  string
+	EventID    string
+	EventType  string
+	StatusCode int
+	Error      string
+	Attempts   int
+	FailedAt   time.Time
+}
+
+const (
+	// HealthWindow is how far back webhook deliveries and Chatwoot errors count towards integration health
+	HealthWindow = 15 * time.Minute
+	// MaxDeliveryOutcomes bounds how many delivery outcomes are kept per session
+	MaxDeliveryOutcomes = 500
+	// MaxChatwootErrors bounds how many Chatwoot relay errors are kept
+	MaxChatwootErrors = 200
+
+	// An integration is degraded when fewer than degradedSuccessRate of at least minRatedDeliveries
+	// deliveries succeeded, when Chatwoot failed degradedChatwootErrors times, or when a queue is fuller
+	// than degradedQueueUsage
+	minRatedDeliveries     = 5
+	degradedSuccessRate    = 0.9
+	degradedChatwootErrors = 3
+	degradedQueueUsage     = 0.8
+)
+
+// Integration health statuses
+const (
+	HealthHealthy  = "healthy"
+	HealthDegraded = "degraded"
+)
+
+// Chatwoot relay directions
+const (
+	RelayToChatwoot   = "to_chatwoot"
+	RelayFromChatwoot = "from_chatwoot"
+)
+
+// ChatwootError is a message or webhook that could not be relayed between WhatsApp and Chatwoot
+type ChatwootError struct {
+	SessionID string
+	Direction string
+	Event     string
+	Error     string
+	At        time.Time
+}
+
+// QueueGauge reports the depth of a queue that feeds a session's integrations. Queues shared by all
+// sessions report their total depth.
+type QueueGauge interface {
+	QueueDepth(sessionID string) (depth, capacity int)
+}
+
+// IntegrationHealth summarizes the recent state of the webhooks, Chatwoot relay and queues of a session
+type IntegrationHealth struct {
+	Status   string
+	Reasons  []string
+	Window   time.Duration
+	Webhooks WebhookHealth
+	Chatwoot ChatwootHealth
+	Queues   []QueueHealth
+}
+
+type WebhookHealth struct {
+	Delivered     int
+	Failed        int
+	SuccessRate   *float64 // Unset without deliveries in the window
+	LastFailureAt *time.Time
+	LastError     string
+}
+
+type ChatwootHealth struct {
+	Errors      int
+	LastErrorAt *time.Time
+	LastError   string
+}
+
+type QueueHealth struct {
+	Name     string
+	Depth    int
+	Capacity int
+}
+
+type deliveryOutcome struct {
+	success bool
+	at      time.Time
+}
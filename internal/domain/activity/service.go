@@ -0,0 +1,263 @@
+package activity
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"zpwoot/internal/domain/webhook"
+	"zpwoot/platform/logger"
+)
+
+// Service keeps a short in-memory history of recent messages, webhook deliveries and Chatwoot relay
+// errors for operators. Nothing is persisted, so the history starts empty after a restart.
+type Service struct {
+	logger *logger.Logger
+
+	mu             sync.RWMutex
+	messages       map[string][]*RecentMessage
+	failures       []*WebhookFailure
+	deliveries     map[string][]deliveryOutcome
+	chatwootErrors []*ChatwootError
+
+	queueNames []string
+	queues     map[string]QueueGauge
+}
+
+func NewService(logger *logger.Logger) *Service {
+	return &Service{
+		logger:     logger,
+		messages:   make(map[string][]*RecentMessage),
+		deliveries: make(map[string][]deliveryOutcome),
+		queues:     make(map[string]QueueGauge),
+	}
+}
+
+// AddQueue registers a queue whose depth is reported in integration health; it must be called at startup
+func (s *Service) AddQueue(name string, gauge QueueGauge) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.queues[name]; !exists {
+		s.queueNames = append(s.queueNames, name)
+	}
+	s.queues[name] = gauge
+}
+
+// ProcessWebhookEvent records message events; it lets the service be added as a webhook event processor
+func (s *Service) ProcessWebhookEvent(ctx context.Context, event *webhook.WebhookEvent) error {
+	if event.Type != messageEventType || event.SessionID == "" {
+		return nil
+	}
+
+	s.RecordMessage(messageFromEvent(event))
+	return nil
+}
+
+// RecordMessage appends a message to its session history, dropping the oldest one when full
+func (s *Service) RecordMessage(msg *RecentMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	history := append(s.messages[msg.SessionID], msg)
+	if len(history) > MaxRecentMessages {
+		history = history[len(history)-MaxRecentMessages:]
+	}
+	s.messages[msg.SessionID] = history
+}
+
+// RecordWebhookFailure appends a permanent delivery failure, dropping the oldest one when full
+func (s *Service) RecordWebhookFailure(failure *WebhookFailure) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures = append(s.failures, failure)
+	if len(s.failures) > MaxWebhookFailures {
+		s.failures = s.failures[len(s.failures)-MaxWebhookFailures:]
+	}
+}
+
+// RecordWebhookDelivery counts the final outcome of a webhook delivery, after its retries
+func (s *Service) RecordWebhookDelivery(sessionID string, success bool) {
+	if sessionID == "" {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	outcomes := append(s.deliveries[sessionID], deliveryOutcome{success: success, at: time.Now()})
+	if len(outcomes) > MaxDeliveryOutcomes {
+		outcomes = outcomes[len(outcomes)-MaxDeliveryOutcomes:]
+	}
+	s.deliveries[sessionID] = outcomes
+}
+
+// RecordChatwootError appends a Chatwoot relay error, dropping the oldest one when full
+func (s *Service) RecordChatwootError(chatwootErr *ChatwootError) {
+	if chatwootErr.At.IsZero() {
+		chatwootErr.At = time.Now()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.chatwootErrors = append(s.chatwootErrors, chatwootErr)
+	if len(s.chatwootErrors) > MaxChatwootErrors {
+		s.chatwootErrors = s.chatwootErrors[len(s.chatwootErrors)-MaxChatwootErrors:]
+	}
+}
+
+// Health summarizes the webhook deliveries and Chatwoot errors of a session in the last HealthWindow
+// and the current depth of the queues feeding its integrations
+func (s *Service) Health(sessionID string) *IntegrationHealth {
+	since := time.Now().Add(-HealthWindow)
+	health := &IntegrationHealth{
+		Status: HealthHealthy,
+		Window: HealthWindow,
+	}
+
+	s.mu.RLock()
+	for _, outcome := range s.deliveries[sessionID] {
+		if outcome.at.Before(since) {
+			continue
+		}
+		if outcome.success {
+			health.Webhooks.Delivered++
+		} else {
+			health.Webhooks.Failed++
+		}
+	}
+	for i := len(s.failures) - 1; i >= 0; i-- {
+		if failure := s.failures[i]; failure.SessionID == sessionID {
+			failedAt := failure.FailedAt
+			health.Webhooks.LastFailureAt = &failedAt
+			health.Webhooks.LastError = failure.Error
+			break
+		}
+	}
+	for i := len(s.chatwootErrors) - 1; i >= 0; i-- {
+		chatwootErr := s.chatwootErrors[i]
+		if chatwootErr.SessionID != sessionID || chatwootErr.At.Before(since) {
+			continue
+		}
+		if health.Chatwoot.Errors == 0 {
+			at := chatwootErr.At
+			health.Chatwoot.LastErrorAt = &at
+			health.Chatwoot.LastError = chatwootErr.Error
+		}
+		health.Chatwoot.Errors++
+	}
+	gauges := make([]QueueGauge, len(s.queueNames))
+	names := append([]string(nil), s.queueNames...)
+	for i, name := range names {
+		gauges[i] = s.queues[name]
+	}
+	s.mu.RUnlock()
+
+	// Gauges are read outside the lock, they may take their own locks
+	for i, gauge := range gauges {
+		depth, capacity := gauge.QueueDepth(sessionID)
+		health.Queues = append(health.Queues, QueueHealth{Name: names[i], Depth: depth, Capacity: capacity})
+	}
+
+	health.evaluate()
+	return health
+}
+
+// evaluate sets the status of the health from its figures
+func (h *IntegrationHealth) evaluate() {
+	if total := h.Webhooks.Delivered + h.Webhooks.Failed; total > 0 {
+		rate := float64(h.Webhooks.Delivered) / float64(total)
+		h.Webhooks.SuccessRate = &rate
+		if total >= minRatedDeliveries && rate < degradedSuccessRate {
+			h.Reasons = append(h.Reasons, fmt.Sprintf("webhook success rate %.0f%%", rate*100))
+		}
+	}
+
+	if h.Chatwoot.Errors >= degradedChatwootErrors {
+		h.Reasons = append(h.Reasons, fmt.Sprintf("%d Chatwoot relay errors", h.Chatwoot.Errors))
+	}
+
+	for _, queue := range h.Queues {
+		if queue.Capacity > 0 && float64(queue.Depth) >= float64(queue.Capacity)*degradedQueueUsage {
+			h.Reasons = append(h.Reasons, fmt.Sprintf("%s queue at %d/%d", queue.Name, queue.Depth, queue.Capacity))
+		}
+	}
+
+	if len(h.Reasons) > 0 {
+		h.Status = HealthDegraded
+	}
+}
+
+// RecentMessages returns up to limit messages of a session, newest first
+func (s *Service) RecentMessages(sessionID string, limit int) []*RecentMessage {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	history := s.messages[sessionID]
+	limit = clampLimit(limit, len(history))
+
+	result := make([]*RecentMessage, 0, limit)
+	for i := len(history) - 1; i >= 0 && len(result) < limit; i-- {
+		result = append(result, history[i])
+	}
+	return result
+}
+
+// RecentWebhookFailures returns up to limit failures, newest first; an empty sessionID returns all sessions
+func (s *Service) RecentWebhookFailures(sessionID string, limit int) []*WebhookFailure {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	limit = clampLimit(limit, len(s.failures))
+
+	result := make([]*WebhookFailure, 0, limit)
+	for i := len(s.failures) - 1; i >= 0 && len(result) < limit; i-- {
+		if sessionID == "" || s.failures[i].SessionID == sessionID {
+			result = append(result, s.failures[i])
+		}
+	}
+	return result
+}
+
+func clampLimit(limit, size int) int {
+	if limit <= 0 || limit > size {
+		return size
+	}
+	return limit
+}
+
+func messageFromEvent(event *webhook.WebhookEvent) *RecentMessage {
+	msg := &RecentMessage{
+		SessionID: event.SessionID,
+		MessageID: stringField(event.Data, "message_id"),
+		Chat:      stringField(event.Data, "chat"),
+		Sender:    stringField(event.Data, "sender"),
+		Type:      stringField(event.Data, "message_type"),
+		Timestamp: event.Timestamp,
+	}
+
+	if fromMe, ok := event.Data["from_me"].(bool); ok {
+		msg.FromMe = fromMe
+	}
+	if ts, ok := event.Data["timestamp"].(int64); ok && ts > 0 {
+		msg.Timestamp = time.Unix(ts, 0)
+	}
+
+	msg.Text = stringField(event.Data, "text")
+	if msg.Text == "" {
+		msg.Text = stringField(event.Data, "caption")
+	}
+	if msg.Text == "" {
+		msg.Text = stringField(event.Data, "filename")
+	}
+
+	return msg
+}
+
+func stringField(data map[string]interface{}, key string) string {
+	value, _ := data[key].(string)
+	return value
+}
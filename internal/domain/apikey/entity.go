@@ -0,0 +1,145 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// keyPrefix starts every generated key so leaked keys are easy to recognize
+const keyPrefix = "zpw_live_"
+
+// displayPrefixLength is how much of a key is kept in clear to tell keys apart
+const displayPrefixLength = len(keyPrefix) + 4
+
+// Scopes a key can be given. Keys with send or manage-sessions can also read.
+const (
+	// ScopeReadOnly allows GET requests
+	ScopeReadOnly = "read-only"
+	// ScopeSend allows sending messages and acting on chats
+	ScopeSend = "send"
+	// ScopeManageSessions allows every other change: creating, connecting and configuring sessions
+	ScopeManageSessions = "manage-sessions"
+)
+
+// APIKey is a stored API key. Only the hash of the key is kept; the key itself is shown once when it
+// is created.
+type APIKey struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	// Prefix is the start of the key, kept in clear to tell keys apart
+	Prefix  string   `json:"prefix"`
+	KeyHash string   `json:"-"`
+	Scopes  []string `json:"scopes"`
+	// SessionIDs restricts the key to those sessions; an empty list allows every session
+	SessionIDs []string `json:"session_ids"`
+	// Workspace is the workspace the usage of the key is accounted to
+	Workspace  string     `json:"workspace,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+type CreateRequest struct {
+	Name   string
+	Scopes []string
+	// Sessions are the IDs or names of the sessions the key is restricted to
+	Sessions  []string
+	Workspace string
+	ExpiresAt *time.Time
+}
+
+// UpdateRequest changes the fields that are set; an empty Sessions list lifts the session restriction
+type UpdateRequest struct {
+	Name      *string
+	Scopes    *[]string
+	Sessions  *[]string
+	Workspace *string
+}
+
+var (
+	ErrAPIKeyNotFound  = errors.New("API key not found")
+	ErrAPIKeyRevoked   = errors.New("API key has been revoked")
+	ErrAPIKeyExpired   = errors.New("API key has expired")
+	ErrNameRequired    = errors.New("name is required")
+	ErrScopesRequired  = errors.New("at least one scope is required")
+	ErrInvalidScope    = errors.New("scopes must be send, manage-sessions or read-only")
+	ErrUnknownSession  = errors.New("session not found")
+	ErrExpiresInPast   = errors.New("expiresAt must be in the future")
+	ErrScopeDenied     = errors.New("API key scope does not allow this request")
+	ErrSessionDenied   = errors.New("API key is not allowed to access this session")
+	ErrAlreadyRevoked  = errors.New("API key is already revoked")
+	ErrGlobalKeyNeeded = errors.New("this route requires the global API key")
+)
+
+// IsScope reports whether scope is one of the scopes
+func IsScope(scope string) bool {
+	switch scope {
+	case ScopeReadOnly, ScopeSend, ScopeManageSessions:
+		return true
+	}
+	return false
+}
+
+// HasScope reports whether the key allows requests needing scope; every scope allows reading
+func (k *APIKey) HasScope(scope string) bool {
+	for _, granted := range k.Scopes {
+		if granted == scope || scope == ScopeReadOnly {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsSession reports whether the key may access the session with ID sessionID
+func (k *APIKey) AllowsSession(sessionID string) bool {
+	if len(k.SessionIDs) == 0 {
+		return true
+	}
+	for _, id := range k.SessionIDs {
+		if id == sessionID {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSessionScoped reports whether the key is restricted to some sessions
+func (k *APIKey) IsSessionScoped() bool {
+	return len(k.SessionIDs) > 0
+}
+
+// Status returns why the key cannot be used at now, or nil when it can
+func (k *APIKey) Status(now time.Time) error {
+	if k.RevokedAt != nil {
+		return ErrAPIKeyRevoked
+	}
+	if k.ExpiresAt != nil && !now.Before(*k.ExpiresAt) {
+		return ErrAPIKeyExpired
+	}
+	return nil
+}
+
+// GenerateKey returns a new random key with its display prefix and hash
+func GenerateKey() (key, prefix, hash string, err error) {
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", "", err
+	}
+
+	key = keyPrefix + hex.EncodeToString(secret)
+	return key, key[:displayPrefixLength], HashKey(key), nil
+}
+
+// HashKey returns the stored form of a key. Keys are random, so a plain SHA-256 is enough to keep them
+// from being read back.
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,335 @@
+package apikey
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"zpwoot/internal/domain/session"
+	"zpwoot/platform/logger"
+)
+
+// keyCacheTTL bounds how long a revoked or changed key keeps working on another replica
+const keyCacheTTL = 30 * time.Second
+
+// lastUsedInterval limits how often the last use of a key is written
+const lastUsedInterval = time.Minute
+
+// Repository defines the interface for API key data operations
+type Repository interface {
+	Create(ctx context.Context, key *APIKey) error
+	GetByID(ctx context.Context, id uuid.UUID) (*APIKey, error)
+	// GetByHash returns ErrAPIKeyNotFound when no key has that hash
+	GetByHash(ctx context.Context, keyHash string) (*APIKey, error)
+	List(ctx context.Context) ([]*APIKey, error)
+	Update(ctx context.Context, key *APIKey) error
+	TouchLastUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) error
+}
+
+// SessionRepository looks up the sessions a key is restricted to
+type SessionRepository interface {
+	GetByID(ctx context.Context, id string) (*session.Session, error)
+	GetByName(ctx context.Context, name string) (*session.Session, error)
+}
+
+// Service manages the stored API keys and checks what they allow
+type Service struct {
+	logger      *logger.Logger
+	repo        Repository
+	sessionRepo SessionRepository
+
+	mu    sync.RWMutex
+	cache map[string]*cachedKey
+	// touched is when the last use of each key was written
+	touched map[uuid.UUID]time.Time
+}
+
+type cachedKey struct {
+	key      *APIKey
+	loadedAt time.Time
+}
+
+func NewService(logger *logger.Logger, repo Repository, sessionRepo SessionRepository) *Service {
+	return &Service{
+		logger:      logger,
+		repo:        repo,
+		sessionRepo: sessionRepo,
+		cache:       make(map[string]*cachedKey),
+		touched:     make(map[uuid.UUID]time.Time),
+	}
+}
+
+// Create stores a new key and returns it with the key itself, which cannot be read back afterwards
+func (s *Service) Create(ctx context.Context, req *CreateRequest) (*APIKey, string, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, "", ErrNameRequired
+	}
+	scopes, err := normalizeScopes(req.Scopes)
+	if err != nil {
+		return nil, "", err
+	}
+	sessionIDs, err := s.resolveSessions(ctx, req.Sessions)
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now()
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(now) {
+		return nil, "", ErrExpiresInPast
+	}
+
+	plaintext, prefix, hash, err := GenerateKey()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate API key: %w", err)
+	}
+
+	key := &APIKey{
+		ID:         uuid.New(),
+		Name:       name,
+		Prefix:     prefix,
+		KeyHash:    hash,
+		Scopes:     scopes,
+		SessionIDs: sessionIDs,
+		Workspace:  strings.TrimSpace(req.Workspace),
+		ExpiresAt:  req.ExpiresAt,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	if err := s.repo.Create(ctx, key); err != nil {
+		return nil, "", err
+	}
+
+	s.logger.InfoWithFields("API key created", map[string]interface{}{
+		"api_key_id": key.ID.String(),
+		"name":       key.Name,
+		"prefix":     key.Prefix,
+		"scopes":     key.Scopes,
+		"sessions":   len(key.SessionIDs),
+	})
+
+	return key, plaintext, nil
+}
+
+func (s *Service) Get(ctx context.Context, id uuid.UUID) (*APIKey, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *Service) List(ctx context.Context) ([]*APIKey, error) {
+	return s.repo.List(ctx)
+}
+
+// Update changes the name, scopes, sessions or workspace of a key that is not revoked
+func (s *Service) Update(ctx context.Context, id uuid.UUID, req *UpdateRequest) (*APIKey, error) {
+	key, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if key.RevokedAt != nil {
+		return nil, ErrAPIKeyRevoked
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, ErrNameRequired
+		}
+		key.Name = name
+	}
+	if req.Scopes != nil {
+		scopes, err := normalizeScopes(*req.Scopes)
+		if err != nil {
+			return nil, err
+		}
+		key.Scopes = scopes
+	}
+	if req.Sessions != nil {
+		sessionIDs, err := s.resolveSessions(ctx, *req.Sessions)
+		if err != nil {
+			return nil, err
+		}
+		key.SessionIDs = sessionIDs
+	}
+	if req.Workspace != nil {
+		key.Workspace = strings.TrimSpace(*req.Workspace)
+	}
+
+	key.UpdatedAt = time.Now()
+	if err := s.repo.Update(ctx, key); err != nil {
+		return nil, err
+	}
+
+	s.invalidate(key.KeyHash)
+	return key, nil
+}
+
+// Revoke disables a key for good
+func (s *Service) Revoke(ctx context.Context, id uuid.UUID) (*APIKey, error) {
+	key, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if key.RevokedAt != nil {
+		return nil, ErrAlreadyRevoked
+	}
+
+	now := time.Now()
+	key.RevokedAt = &now
+	key.UpdatedAt = now
+	if err := s.repo.Update(ctx, key); err != nil {
+		return nil, err
+	}
+
+	s.invalidate(key.KeyHash)
+
+	s.logger.InfoWithFields("API key revoked", map[string]interface{}{
+		"api_key_id": key.ID.String(),
+		"name":       key.Name,
+		"prefix":     key.Prefix,
+	})
+
+	return key, nil
+}
+
+// Authenticate returns the stored key matching plaintext, or ErrAPIKeyNotFound, ErrAPIKeyRevoked or
+// ErrAPIKeyExpired
+func (s *Service) Authenticate(ctx context.Context, plaintext string) (*APIKey, error) {
+	hash := HashKey(plaintext)
+
+	key, err := s.lookup(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := key.Status(now); err != nil {
+		return nil, err
+	}
+
+	if s.shouldTouch(key.ID, now) {
+		if err := s.repo.TouchLastUsed(ctx, key.ID, now); err != nil {
+			s.logger.WarnWithFields("Failed to record API key use", map[string]interface{}{
+				"api_key_id": key.ID.String(),
+				"error":      err.Error(),
+			})
+		}
+	}
+
+	return key, nil
+}
+
+// Authorize checks that key may make a request needing scope on the session given by ID or name;
+// sessionIdentifier is empty for requests that are not about one session, which session-scoped keys
+// cannot make
+func (s *Service) Authorize(ctx context.Context, key *APIKey, scope, sessionIdentifier string) error {
+	if !key.HasScope(scope) {
+		return ErrScopeDenied
+	}
+	if !key.IsSessionScoped() {
+		return nil
+	}
+	if sessionIdentifier == "" {
+		return ErrSessionDenied
+	}
+
+	sess, err := s.findSession(ctx, sessionIdentifier)
+	if err != nil || !key.AllowsSession(sess.ID.String()) {
+		return ErrSessionDenied
+	}
+	return nil
+}
+
+func (s *Service) lookup(ctx context.Context, hash string) (*APIKey, error) {
+	s.mu.RLock()
+	cached, ok := s.cache[hash]
+	s.mu.RUnlock()
+	if ok && time.Since(cached.loadedAt) < keyCacheTTL {
+		return cached.key, nil
+	}
+
+	key, err := s.repo.GetByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.cache[hash] = &cachedKey{key: key, loadedAt: time.Now()}
+	s.mu.Unlock()
+
+	return key, nil
+}
+
+// shouldTouch reports whether the last use of the key is due to be written, and records that it is
+func (s *Service) shouldTouch(id uuid.UUID, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if last, ok := s.touched[id]; ok && now.Sub(last) < lastUsedInterval {
+		return false
+	}
+	s.touched[id] = now
+	return true
+}
+
+func (s *Service) invalidate(hash string) {
+	s.mu.Lock()
+	delete(s.cache, hash)
+	s.mu.Unlock()
+}
+
+// resolveSessions returns the IDs of the sessions given by ID or name, without duplicates
+func (s *Service) resolveSessions(ctx context.Context, identifiers []string) ([]string, error) {
+	ids := make([]string, 0, len(identifiers))
+	seen := make(map[string]bool, len(identifiers))
+	for _, identifier := range identifiers {
+		identifier = strings.TrimSpace(identifier)
+		if identifier == "" {
+			continue
+		}
+
+		sess, err := s.findSession(ctx, identifier)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownSession, identifier)
+		}
+
+		id := sess.ID.String()
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (s *Service) findSession(ctx context.Context, identifier string) (*session.Session, error) {
+	if _, err := uuid.Parse(identifier); err == nil {
+		return s.sessionRepo.GetByID(ctx, identifier)
+	}
+	return s.sessionRepo.GetByName(ctx, identifier)
+}
+
+// normalizeScopes validates scopes and drops duplicates
+func normalizeScopes(scopes []string) ([]string, error) {
+	normalized := make([]string, 0, len(scopes))
+	seen := make(map[string]bool, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if !IsScope(scope) {
+			return nil, ErrInvalidScope
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			normalized = append(normalized, scope)
+		}
+	}
+
+	if len(normalized) == 0 {
+		return nil, ErrScopesRequired
+	}
+	return normalized, nil
+}
@@ -0,0 +1,59 @@
+package chat
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidChatJID       = errors.New("chat JID is required")
+	ErrInvalidMuteDuration  = errors.New("mute duration cannot be negative")
+	ErrInvalidTypingAction  = errors.New("typing action must be start or stop")
+	ErrInvalidTypingTimeout = errors.New("typing timeout must be between 1 and 300 seconds")
+)
+
+// Actions applied to a chat; each is synced to the phone and the other devices as an app state patch
+const (
+	ActionArchive   = "archive"
+	ActionUnarchive = "unarchive"
+	ActionPin       = "pin"
+	ActionUnpin     = "unpin"
+	ActionMute      = "mute"
+	ActionUnmute    = "unmute"
+	ActionRead      = "read"
+	ActionUnread    = "unread"
+	ActionClear     = "clear"
+	ActionDelete    = "delete"
+)
+
+// Typing actions; unlike the actions above they only reach the other party of the chat, as presence
+const (
+	ActionTypingStart = "typing_start"
+	ActionTypingStop  = "typing_stop"
+)
+
+// LastMessage is the newest message of a chat. WhatsApp checks archive, read, clear and delete patches
+// against the message range they cover, which ends at this message.
+type LastMessage struct {
+	ID        string
+	SenderJID string
+	FromMe    bool
+	Timestamp time.Time
+}
+
+// Result is the outcome of an action applied to a chat
+type Result struct {
+	ChatJID string `json:"chat_jid"`
+	Action  string `json:"action"`
+	// MutedUntil is when a timed mute ends; nil for other actions and mutes without an end
+	MutedUntil *time.Time `json:"muted_until,omitempty"`
+	// TypingUntil is when a started typing indicator stops by itself; nil for other actions
+	TypingUntil *time.Time `json:"typing_until,omitempty"`
+	AppliedAt   time.Time  `json:"applied_at"`
+}
+
+// ClearOptions choose what clearing or deleting a chat keeps
+type ClearOptions struct {
+	KeepStarred bool
+	DeleteMedia bool
+}
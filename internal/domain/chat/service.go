@@ -0,0 +1,188 @@
+package chat
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"zpwoot/internal/domain/history"
+	"zpwoot/platform/logger"
+)
+
+// StateManager applies chat changes to WhatsApp as app state patches
+type StateManager interface {
+	ArchiveChat(sessionID, chatJID string, archive bool, last *LastMessage) error
+	PinChat(sessionID, chatJID string, pin bool) error
+	MuteChat(sessionID, chatJID string, mute bool, duration time.Duration) error
+	MarkChatRead(sessionID, chatJID string, read bool, last *LastMessage) error
+	ClearChat(sessionID, chatJID string, options ClearOptions, last *LastMessage) error
+	DeleteChat(sessionID, chatJID string, options ClearOptions, last *LastMessage) error
+}
+
+// MessageHistory looks up the stored messages of a chat
+type MessageHistory interface {
+	ListChatMessages(ctx context.Context, req *history.ListRequest) ([]*history.Message, int, error)
+}
+
+// PresenceSender shows the session typing in a chat, or stops showing it, giving up when ctx ends
+type PresenceSender interface {
+	SendChatPresence(ctx context.Context, sessionID, to, presence string) error
+}
+
+// Service archives, pins, mutes, reads, clears and deletes chats, and shows the session typing in them
+type Service struct {
+	logger   *logger.Logger
+	manager  StateManager
+	history  MessageHistory
+	presence PresenceSender
+
+	typingMu sync.Mutex
+	typing   map[typingKey]*typingState
+}
+
+func NewService(logger *logger.Logger, manager StateManager, messageHistory MessageHistory, presence PresenceSender) *Service {
+	return &Service{
+		logger:   logger,
+		manager:  manager,
+		history:  messageHistory,
+		presence: presence,
+		typing:   make(map[typingKey]*typingState),
+	}
+}
+
+// Archive archives or unarchives a chat; archiving also unpins it
+func (s *Service) Archive(ctx context.Context, sessionID, chatJID string, archive bool) (*Result, error) {
+	action := ActionUnarchive
+	if archive {
+		action = ActionArchive
+	}
+	return s.apply(ctx, sessionID, chatJID, action, func(chatJID string, last *LastMessage) error {
+		return s.manager.ArchiveChat(sessionID, chatJID, archive, last)
+	})
+}
+
+// Pin pins or unpins a chat
+func (s *Service) Pin(ctx context.Context, sessionID, chatJID string, pin bool) (*Result, error) {
+	action := ActionUnpin
+	if pin {
+		action = ActionPin
+	}
+	return s.apply(ctx, sessionID, chatJID, action, func(chatJID string, _ *LastMessage) error {
+		return s.manager.PinChat(sessionID, chatJID, pin)
+	})
+}
+
+// Mute mutes a chat for a duration, or until unmuted when the duration is zero
+func (s *Service) Mute(ctx context.Context, sessionID, chatJID string, duration time.Duration) (*Result, error) {
+	if duration < 0 {
+		return nil, ErrInvalidMuteDuration
+	}
+
+	result, err := s.apply(ctx, sessionID, chatJID, ActionMute, func(chatJID string, _ *LastMessage) error {
+		return s.manager.MuteChat(sessionID, chatJID, true, duration)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if duration > 0 {
+		mutedUntil := result.AppliedAt.Add(duration)
+		result.MutedUntil = &mutedUntil
+	}
+	return result, nil
+}
+
+// Unmute unmutes a chat
+func (s *Service) Unmute(ctx context.Context, sessionID, chatJID string) (*Result, error) {
+	return s.apply(ctx, sessionID, chatJID, ActionUnmute, func(chatJID string, _ *LastMessage) error {
+		return s.manager.MuteChat(sessionID, chatJID, false, 0)
+	})
+}
+
+// MarkRead marks a whole chat as read, or as unread
+func (s *Service) MarkRead(ctx context.Context, sessionID, chatJID string, read bool) (*Result, error) {
+	action := ActionUnread
+	if read {
+		action = ActionRead
+	}
+	return s.apply(ctx, sessionID, chatJID, action, func(chatJID string, last *LastMessage) error {
+		return s.manager.MarkChatRead(sessionID, chatJID, read, last)
+	})
+}
+
+// Clear deletes the messages of a chat but keeps the chat
+func (s *Service) Clear(ctx context.Context, sessionID, chatJID string, options ClearOptions) (*Result, error) {
+	return s.apply(ctx, sessionID, chatJID, ActionClear, func(chatJID string, last *LastMessage) error {
+		return s.manager.ClearChat(sessionID, chatJID, options, last)
+	})
+}
+
+// Delete deletes a chat with its messages
+func (s *Service) Delete(ctx context.Context, sessionID, chatJID string, options ClearOptions) (*Result, error) {
+	return s.apply(ctx, sessionID, chatJID, ActionDelete, func(chatJID string, last *LastMessage) error {
+		return s.manager.DeleteChat(sessionID, chatJID, options, last)
+	})
+}
+
+// apply validates the chat, looks up its last message and runs the action
+func (s *Service) apply(ctx context.Context, sessionID, chatJID, action string, run func(chatJID string, last *LastMessage) error) (*Result, error) {
+	chatJID = strings.TrimSpace(chatJID)
+	if chatJID == "" {
+		return nil, ErrInvalidChatJID
+	}
+
+	if err := run(chatJID, s.lastMessage(ctx, sessionID, chatJID)); err != nil {
+		s.logger.ErrorWithFields("Failed to update chat", map[string]interface{}{
+			"session_id": sessionID,
+			"chat_jid":   chatJID,
+			"action":     action,
+			"error":      err.Error(),
+		})
+		return nil, err
+	}
+
+	s.logger.InfoWithFields("Chat updated", map[string]interface{}{
+		"session_id": sessionID,
+		"chat_jid":   chatJID,
+		"action":     action,
+	})
+
+	return &Result{
+		ChatJID:   chatJID,
+		Action:    action,
+		AppliedAt: time.Now(),
+	}, nil
+}
+
+// lastMessage returns the newest stored message of a chat, or nil when none is stored; the patches then
+// cover the chat up to now
+func (s *Service) lastMessage(ctx context.Context, sessionID, chatJID string) *LastMessage {
+	if s.history == nil {
+		return nil
+	}
+
+	messages, _, err := s.history.ListChatMessages(ctx, &history.ListRequest{
+		SessionID: sessionID,
+		ChatJID:   chatJID,
+		Limit:     1,
+	})
+	if err != nil {
+		s.logger.WarnWithFields("Failed to look up the last message of a chat", map[string]interface{}{
+			"session_id": sessionID,
+			"chat_jid":   chatJID,
+			"error":      err.Error(),
+		})
+		return nil
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+
+	return &LastMessage{
+		ID:        messages[0].MessageID,
+		SenderJID: messages[0].SenderJID,
+		FromMe:    messages[0].FromMe,
+		Timestamp: messages[0].Timestamp,
+	}
+}
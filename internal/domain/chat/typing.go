@@ -0,0 +1,175 @@
+package chat
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultTypingTimeout is how long a typing indicator lasts when no timeout is given
+	DefaultTypingTimeout = 25 * time.Second
+	// MaxTypingTimeout bounds the timeout of a typing indicator, so a client that never stops it
+	// does not leave the session typing for good
+	MaxTypingTimeout = 5 * time.Minute
+
+	// typingRefreshInterval is how often a long typing indicator is sent again; WhatsApp clients hide
+	// one that is not refreshed within about 25 seconds
+	typingRefreshInterval = 20 * time.Second
+	// typingSendTimeout bounds how long sending a typing presence may take
+	typingSendTimeout = 10 * time.Second
+)
+
+// typingKey identifies the typing indicator of a chat of a session
+type typingKey struct {
+	sessionID string
+	chatJID   string
+}
+
+// typingState is a running typing indicator; its timer refreshes it until it expires. sendMu orders
+// the refreshes of the indicator with the stop that ends it, so a refresh never lands after the stop.
+type typingState struct {
+	until time.Time
+	timer *time.Timer
+
+	sendMu  sync.Mutex
+	stopped bool
+}
+
+// end stops the indicator's refreshes, waiting for one being sent
+func (t *typingState) end() {
+	t.sendMu.Lock()
+	t.stopped = true
+	t.sendMu.Unlock()
+}
+
+// StartTyping shows the session typing in a chat until StopTyping or the timeout, DefaultTypingTimeout
+// when zero. Starting again extends a running indicator.
+func (s *Service) StartTyping(ctx context.Context, sessionID, chatJID string, timeout time.Duration) (*Result, error) {
+	chatJID = strings.TrimSpace(chatJID)
+	if chatJID == "" {
+		return nil, ErrInvalidChatJID
+	}
+	if timeout == 0 {
+		timeout = DefaultTypingTimeout
+	}
+	if timeout < time.Second || timeout > MaxTypingTimeout {
+		return nil, ErrInvalidTypingTimeout
+	}
+
+	if err := s.sendTypingPresence(ctx, sessionID, chatJID, "typing"); err != nil {
+		s.logger.WarnWithFields("Failed to start typing", map[string]interface{}{
+			"session_id": sessionID,
+			"chat_jid":   chatJID,
+			"error":      err.Error(),
+		})
+		return nil, err
+	}
+
+	key := typingKey{sessionID: sessionID, chatJID: chatJID}
+	state := &typingState{until: time.Now().Add(timeout)}
+
+	s.typingMu.Lock()
+	previous := s.typing[key]
+	if previous != nil {
+		previous.timer.Stop()
+	}
+	state.timer = time.AfterFunc(min(typingRefreshInterval, timeout), func() {
+		s.refreshTyping(key, state)
+	})
+	s.typing[key] = state
+	s.typingMu.Unlock()
+
+	if previous != nil {
+		previous.end()
+	}
+
+	until := state.until
+	return &Result{
+		ChatJID:     chatJID,
+		Action:      ActionTypingStart,
+		TypingUntil: &until,
+		AppliedAt:   time.Now(),
+	}, nil
+}
+
+// StopTyping stops showing the session typing in a chat, whether or not an indicator is running
+func (s *Service) StopTyping(ctx context.Context, sessionID, chatJID string) (*Result, error) {
+	chatJID = strings.TrimSpace(chatJID)
+	if chatJID == "" {
+		return nil, ErrInvalidChatJID
+	}
+
+	key := typingKey{sessionID: sessionID, chatJID: chatJID}
+
+	s.typingMu.Lock()
+	state := s.typing[key]
+	if state != nil {
+		state.timer.Stop()
+		delete(s.typing, key)
+	}
+	s.typingMu.Unlock()
+
+	if state != nil {
+		state.end()
+	}
+
+	if err := s.sendTypingPresence(ctx, sessionID, chatJID, "paused"); err != nil {
+		s.logger.WarnWithFields("Failed to stop typing", map[string]interface{}{
+			"session_id": sessionID,
+			"chat_jid":   chatJID,
+			"error":      err.Error(),
+		})
+		return nil, err
+	}
+
+	return &Result{
+		ChatJID:   chatJID,
+		Action:    ActionTypingStop,
+		AppliedAt: time.Now(),
+	}, nil
+}
+
+// refreshTyping sends a running typing indicator again, or stops it once it expired
+func (s *Service) refreshTyping(key typingKey, state *typingState) {
+	state.sendMu.Lock()
+	defer state.sendMu.Unlock()
+	if state.stopped {
+		return
+	}
+
+	s.typingMu.Lock()
+	// Stopped or restarted since the timer fired
+	if s.typing[key] != state {
+		s.typingMu.Unlock()
+		return
+	}
+	presence := "typing"
+	remaining := time.Until(state.until)
+	if remaining <= 0 {
+		presence = "paused"
+		state.stopped = true
+		delete(s.typing, key)
+	} else {
+		state.timer = time.AfterFunc(min(typingRefreshInterval, remaining), func() {
+			s.refreshTyping(key, state)
+		})
+	}
+	s.typingMu.Unlock()
+
+	if err := s.sendTypingPresence(context.Background(), key.sessionID, key.chatJID, presence); err != nil {
+		s.logger.DebugWithFields("Failed to refresh typing", map[string]interface{}{
+			"session_id": key.sessionID,
+			"chat_jid":   key.chatJID,
+			"presence":   presence,
+			"error":      err.Error(),
+		})
+	}
+}
+
+func (s *Service) sendTypingPresence(ctx context.Context, sessionID, chatJID, presence string) error {
+	ctx, cancel := context.WithTimeout(ctx, typingSendTimeout)
+	defer cancel()
+	return s.presence.SendChatPresence(ctx, sessionID, chatJID, presence)
+}
@@ -0,0 +1,60 @@
+package chat
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"zpwoot/platform/logger"
+)
+
+// blockingPresence blocks the presence sends to one chat until released
+type blockingPresence struct {
+	blockedChat string
+	release     chan struct{}
+}
+
+func (p *blockingPresence) SendChatPresence(ctx context.Context, sessionID, to, presence string) error {
+	if to != p.blockedChat {
+		return nil
+	}
+	select {
+	case <-p.release:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestTypingSendsPresenceOutsideTheLock(t *testing.T) {
+	presence := &blockingPresence{blockedChat: "slow@s.whatsapp.net", release: make(chan struct{})}
+	service := NewService(logger.New(), nil, nil, presence)
+
+	slowDone := make(chan struct{})
+	go func() {
+		defer close(slowDone)
+		_, _ = service.StartTyping(context.Background(), "session", "slow@s.whatsapp.net", 0)
+	}()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := service.StartTyping(context.Background(), "session", "fast@s.whatsapp.net", 0)
+		if err == nil {
+			_, err = service.StopTyping(context.Background(), "session", "fast@s.whatsapp.net")
+		}
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("typing in another chat failed: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("typing in another chat waited for a slow presence send")
+	}
+
+	close(presence.release)
+	<-slowDone
+	_, _ = service.StopTyping(context.Background(), "session", "slow@s.whatsapp.net")
+}
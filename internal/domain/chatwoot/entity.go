@@ -27,10 +27,13 @@ type ChatwootConfig struct {
 	ImportMessages bool     `json:"importMessages" db:"importMessages"`
 	ImportDays     int      `json:"importDays" db:"importDays"`
 	MergeBrazil    bool     `json:"mergeBrazil" db:"mergeBrazil"`
+	SyncReads      bool     `json:"syncReads" db:"syncReads"`
+	SendReads      bool     `json:"sendReads" db:"sendReads"`
 	Organization   *string  `json:"organization,omitempty" db:"organization"`
 	Logo           *string  `json:"logo,omitempty" db:"logo"`
 	Number         *string  `json:"number,omitempty" db:"number"`
 	IgnoreJids     []string `json:"ignoreJids,omitempty" db:"ignoreJids"`
+	WebhookSecret  *string  `json:"-" db:"webhookSecret"`
 
 	CreatedAt time.Time `json:"createdAt" db:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt" db:"updatedAt"`
@@ -44,8 +47,25 @@ var (
 	ErrInvalidAPIKey        = errors.New("invalid chatwoot API key")
 	ErrInvalidAccountID     = errors.New("invalid chatwoot account ID")
 	ErrChatwootAPIError     = errors.New("chatwoot API error")
+	ErrIntegrationDisabled  = errors.New("chatwoot integration is disabled")
+	ErrWebhookUnauthorized  = errors.New("chatwoot webhook token or signature is missing or invalid")
+	ErrUnknownInbox         = errors.New("chatwoot webhook is for an unknown inbox")
 )
 
+// WebhookSignatureTolerance bounds how old a signed webhook's timestamp may be before it is taken as a replay
+const WebhookSignatureTolerance = 5 * time.Minute
+
+// WebhookAuth is what an inbound webhook presents to prove it comes from the configured Chatwoot. Token is
+// the token query parameter of the webhook URL; Signature is the X-Chatwoot-Signature header, the hex
+// HMAC-SHA256 of the X-Chatwoot-Timestamp value, a dot and the body. A signature is only accepted with
+// its timestamp, which must be within WebhookSignatureTolerance.
+type WebhookAuth struct {
+	Token     string
+	Signature string
+	Timestamp string
+	Body      []byte
+}
+
 // Domain DTOs - used by domain service
 type CreateChatwootConfigRequest struct {
 	SessionID uuid.UUID `json:"sessionId" validate:"required"`
@@ -66,10 +86,13 @@ type CreateChatwootConfigRequest struct {
 	ImportMessages *bool    `json:"importMessages,omitempty"`
 	ImportDays     *int     `json:"importDays,omitempty"`
 	MergeBrazil    *bool    `json:"mergeBrazil,omitempty"`
+	SyncReads      *bool    `json:"syncReads,omitempty"`
+	SendReads      *bool    `json:"sendReads,omitempty"`
 	Organization   *string  `json:"organization,omitempty"`
 	Logo           *string  `json:"logo,omitempty"`
 	Number         *string  `json:"number,omitempty"`
 	IgnoreJids     []string `json:"ignoreJids,omitempty"`
+	WebhookSecret  *string  `json:"webhookSecret,omitempty"`
 }
 
 type GetChatwootConfigBySessionRequest struct {
@@ -94,10 +117,13 @@ type UpdateChatwootConfigRequest struct {
 	ImportMessages *bool    `json:"importMessages,omitempty"`
 	ImportDays     *int     `json:"importDays,omitempty"`
 	MergeBrazil    *bool    `json:"mergeBrazil,omitempty"`
+	SyncReads      *bool    `json:"syncReads,omitempty"`
+	SendReads      *bool    `json:"sendReads,omitempty"`
 	Organization   *string  `json:"organization,omitempty"`
 	Logo           *string  `json:"logo,omitempty"`
 	Number         *string  `json:"number,omitempty"`
 	IgnoreJids     []string `json:"ignoreJids,omitempty"`
+	WebhookSecret  *string  `json:"webhookSecret,omitempty"`
 }
 
 type ChatwootContact struct {
@@ -197,6 +223,13 @@ type ChatwootWebhookPayload struct {
 	Contact  ChatwootContact        `json:"contact,omitempty"`
 	Message  *ChatwootMessage       `json:"message,omitempty"`
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
+
+	// Eventos de digitação
+	User      *ChatwootSender `json:"user,omitempty"`
+	IsPrivate bool            `json:"is_private,omitempty"`
+
+	// ChangedAttributes names the conversation attributes a conversation_updated event changed
+	ChangedAttributes []string `json:"changed_attributes,omitempty"`
 }
 
 type ChatwootAccount struct {
@@ -2,9 +2,19 @@ package chatwoot
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
+	"zpwoot/internal/domain/chat"
+	"zpwoot/internal/domain/history"
+	"zpwoot/internal/domain/message"
 	"zpwoot/internal/ports"
 	"zpwoot/platform/logger"
 
@@ -16,6 +26,14 @@ type Service struct {
 	repository    ports.ChatwootRepository
 	wameowManager ports.WameowManager
 	messageMapper ports.ChatwootMessageMapper // Optional - for storing outgoing messages
+	deduplicator  ports.ChatwootWebhookDeduplicator
+	typing        TypingIndicator // Optional - for showing agents typing on WhatsApp
+}
+
+// TypingIndicator shows the session typing in a WhatsApp chat until stopped or timed out
+type TypingIndicator interface {
+	StartTyping(ctx context.Context, sessionID, chatJID string, timeout time.Duration) (*chat.Result, error)
+	StopTyping(ctx context.Context, sessionID, chatJID string) (*chat.Result, error)
 }
 
 func NewService(logger *logger.Logger, repository ports.ChatwootRepository, wameowManager ports.WameowManager) *Service {
@@ -31,6 +49,16 @@ func (s *Service) SetMessageMapper(messageMapper ports.ChatwootMessageMapper) {
 	s.messageMapper = messageMapper
 }
 
+// SetTypingIndicator sets where agent typing is forwarded to
+func (s *Service) SetTypingIndicator(typing TypingIndicator) {
+	s.typing = typing
+}
+
+// SetDeduplicator sets the store used to ignore redelivered Chatwoot webhooks
+func (s *Service) SetDeduplicator(deduplicator ports.ChatwootWebhookDeduplicator) {
+	s.deduplicator = deduplicator
+}
+
 // ============================================================================
 // CONFIGURATION MANAGEMENT
 // ============================================================================
@@ -62,6 +90,8 @@ type configDefaults struct {
 	importMessages bool
 	importDays     int
 	mergeBrazil    bool
+	syncReads      bool
+	sendReads      bool
 	ignoreJids     []string
 }
 
@@ -78,6 +108,8 @@ func (s *Service) applyConfigDefaults(req *CreateChatwootConfigRequest) *configD
 		importMessages: false,
 		importDays:     60,
 		mergeBrazil:    true,
+		syncReads:      true,
+		sendReads:      false,
 		ignoreJids:     []string{},
 	}
 
@@ -112,6 +144,12 @@ func (s *Service) applyConfigDefaults(req *CreateChatwootConfigRequest) *configD
 	if req.MergeBrazil != nil {
 		defaults.mergeBrazil = *req.MergeBrazil
 	}
+	if req.SyncReads != nil {
+		defaults.syncReads = *req.SyncReads
+	}
+	if req.SendReads != nil {
+		defaults.sendReads = *req.SendReads
+	}
 	if req.IgnoreJids != nil {
 		defaults.ignoreJids = req.IgnoreJids
 	}
@@ -141,10 +179,13 @@ func (s *Service) buildChatwootConfig(req *CreateChatwootConfigRequest, defaults
 		ImportMessages: defaults.importMessages,
 		ImportDays:     defaults.importDays,
 		MergeBrazil:    defaults.mergeBrazil,
+		SyncReads:      defaults.syncReads,
+		SendReads:      defaults.sendReads,
 		Organization:   req.Organization,
 		Logo:           req.Logo,
 		Number:         req.Number,
 		IgnoreJids:     defaults.ignoreJids,
+		WebhookSecret:  webhookSecret(req.WebhookSecret),
 
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
@@ -256,6 +297,12 @@ func (s *Service) updateAdvancedConfigFields(config *ports.ChatwootConfig, req *
 	if req.MergeBrazil != nil {
 		config.MergeBrazil = *req.MergeBrazil
 	}
+	if req.SyncReads != nil {
+		config.SyncReads = *req.SyncReads
+	}
+	if req.SendReads != nil {
+		config.SendReads = *req.SendReads
+	}
 }
 
 // updateOptionalConfigFields updates optional configuration fields
@@ -272,6 +319,18 @@ func (s *Service) updateOptionalConfigFields(config *ports.ChatwootConfig, req *
 	if req.IgnoreJids != nil {
 		config.IgnoreJids = req.IgnoreJids
 	}
+	if req.WebhookSecret != nil {
+		config.WebhookSecret = webhookSecret(req.WebhookSecret)
+	}
+}
+
+// webhookSecret normalizes a requested webhook secret; an empty secret turns webhook authentication off
+func webhookSecret(secret *string) *string {
+	if secret == nil || strings.TrimSpace(*secret) == "" {
+		return nil
+	}
+	value := strings.TrimSpace(*secret)
+	return &value
 }
 
 func (s *Service) DeleteConfig(ctx context.Context) error {
@@ -330,18 +389,104 @@ func (s *Service) SendMessage(ctx context.Context, req *SendMessageToChatwootReq
 // WEBHOOK PROCESSING
 // ============================================================================
 
-func (s *Service) ProcessWebhook(ctx context.Context, sessionID string, payload *ChatwootWebhookPayload) error {
-	// Delay 500ms to avoid race conditions (based on Evolution API)
-	time.Sleep(500 * time.Millisecond)
+// ValidateWebhook checks an inbound webhook against the session's config: the integration must be enabled,
+// the request must carry the webhook secret when one is set, and the payload must belong to the configured
+// account and inbox
+func (s *Service) ValidateWebhook(ctx context.Context, sessionID string, payload *ChatwootWebhookPayload, auth *WebhookAuth) error {
+	config, err := s.repository.GetConfigBySessionID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, ports.ErrConfigNotFound) {
+			return ErrConfigNotFound
+		}
+		return err
+	}
+
+	if !config.Enabled {
+		return ErrIntegrationDisabled
+	}
+
+	// Authenticate first so unauthenticated callers learn nothing about the account or inbox
+	if config.WebhookSecret != nil && !verifyWebhookAuth(*config.WebhookSecret, auth, time.Now()) {
+		return ErrWebhookUnauthorized
+	}
+
+	if strconv.Itoa(payload.Account.ID) != strings.TrimSpace(config.AccountID) {
+		return ErrInvalidAccountID
+	}
+
+	// Without a configured inbox every inbox of the account is accepted; contact events carry no inbox
+	if config.InboxID == nil || strings.TrimSpace(*config.InboxID) == "" {
+		return nil
+	}
+	inboxID := webhookInboxID(payload)
+	if inboxID == 0 && strings.HasPrefix(payload.Event, "contact_") {
+		return nil
+	}
+	if strconv.Itoa(inboxID) != strings.TrimSpace(*config.InboxID) {
+		return ErrUnknownInbox
+	}
+
+	return nil
+}
+
+// verifyWebhookAuth reports whether the webhook carries the secret as its token or signed its body with it
+func verifyWebhookAuth(secret string, auth *WebhookAuth, now time.Time) bool {
+	if auth == nil {
+		return false
+	}
+
+	if auth.Token != "" && subtle.ConstantTimeCompare([]byte(auth.Token), []byte(secret)) == 1 {
+		return true
+	}
+
+	// A signature without a timestamp could be replayed forever, so both are required
+	if auth.Signature == "" || auth.Timestamp == "" {
+		return false
+	}
+	signature, err := hex.DecodeString(strings.TrimPrefix(auth.Signature, "sha256="))
+	if err != nil {
+		return false
+	}
+
+	timestamp, err := strconv.ParseInt(auth.Timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	age := now.Sub(time.Unix(timestamp, 0))
+	if age > WebhookSignatureTolerance || age < -WebhookSignatureTolerance {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(auth.Timestamp + "."))
+	mac.Write(auth.Body)
+
+	return hmac.Equal(mac.Sum(nil), signature)
+}
 
+// webhookInboxID returns the inbox a webhook belongs to, from its conversation or its inbox object
+func webhookInboxID(payload *ChatwootWebhookPayload) int {
+	if payload.Conversation.InboxID != 0 {
+		return payload.Conversation.InboxID
+	}
+	if id, ok := payload.Inbox["id"].(float64); ok {
+		return int(id)
+	}
+	return 0
+}
+
+// ProcessWebhook handles a Chatwoot webhook; redelivered messages are ignored so it is safe to retry
+func (s *Service) ProcessWebhook(ctx context.Context, sessionID string, payload *ChatwootWebhookPayload) error {
 	// Skip private messages
 	if payload.Message != nil && payload.Message.Private {
 		return nil
 	}
 
-	// Skip message updates without deletion
+	// Deleting a message in Chatwoot deletes it on WhatsApp; other updates are skipped
 	if payload.Event == "message_updated" {
-		// TODO: Handle message deletion if needed
+		if isDeletedMessage(payload) {
+			return s.handleMessageDeleted(ctx, sessionID, payload)
+		}
 		s.logger.DebugWithFields("Skipping message update", map[string]interface{}{
 			"session_id": sessionID,
 			"event":      payload.Event,
@@ -359,9 +504,17 @@ func (s *Service) ProcessWebhook(ctx context.Context, sessionID string, payload
 		return nil
 	}
 
-	// Handle typing events (ignore them as they don't require action)
-	if payload.Event == "conversation_typing_on" || payload.Event == "conversation_typing_off" {
-		return nil
+	// An agent reading the conversation is forwarded as WhatsApp read receipts; other updates are not
+	if payload.Event == "conversation_updated" {
+		if !isAgentRead(payload) {
+			return nil
+		}
+		return s.handleConversationRead(ctx, sessionID, payload)
+	}
+
+	// Agents typing a reply show the session typing in the WhatsApp chat
+	if IsTypingEvent(payload.Event) {
+		return s.handleTyping(ctx, sessionID, payload)
 	}
 
 	// Process new messages (main functionality)
@@ -372,6 +525,207 @@ func (s *Service) ProcessWebhook(ctx context.Context, sessionID string, payload
 	return nil
 }
 
+// IsTypingEvent reports whether a webhook event tells an agent started or stopped typing
+func IsTypingEvent(event string) bool {
+	return event == string(ChatwootEventConversationTypingOn) || event == string(ChatwootEventConversationTypingOff)
+}
+
+// handleTyping starts or stops the typing indicator in the WhatsApp chat of a conversation, found
+// through its newest mapped message. Typing in private notes and typing of the contact are not forwarded.
+func (s *Service) handleTyping(ctx context.Context, sessionID string, payload *ChatwootWebhookPayload) error {
+	if s.typing == nil || s.messageMapper == nil || payload.Conversation.ID == 0 {
+		return nil
+	}
+	if payload.IsPrivate || (payload.User != nil && payload.User.Type == "contact") {
+		return nil
+	}
+
+	mappings, err := s.messageMapper.GetMappingsByCwConversation(ctx, sessionID, payload.Conversation.ID, 1)
+	if err != nil {
+		return fmt.Errorf("failed to get conversation messages: %w", err)
+	}
+	if len(mappings) == 0 || mappings[0].ZpChat == "" {
+		s.logger.DebugWithFields("Typing in a Chatwoot conversation without WhatsApp messages", map[string]interface{}{
+			"session_id":         sessionID,
+			"cw_conversation_id": payload.Conversation.ID,
+		})
+		return nil
+	}
+
+	chatJID := mappings[0].ZpChat
+	if payload.Event == string(ChatwootEventConversationTypingOn) {
+		_, err = s.typing.StartTyping(ctx, sessionID, chatJID, 0)
+	} else {
+		_, err = s.typing.StopTyping(ctx, sessionID, chatJID)
+	}
+	if err != nil {
+		// A missed indicator is not worth a webhook retry
+		s.logger.WarnWithFields("Failed to forward Chatwoot typing to WhatsApp", map[string]interface{}{
+			"session_id":         sessionID,
+			"cw_conversation_id": payload.Conversation.ID,
+			"event":              payload.Event,
+			"error":              err.Error(),
+		})
+	}
+
+	return nil
+}
+
+// maxReadReceiptMessages limits how many recent messages are acknowledged per conversation update
+const maxReadReceiptMessages = 20
+
+// agentReadAttributes are the conversation attributes Chatwoot moves forward when an agent opens it
+var agentReadAttributes = map[string]bool{
+	"agent_last_seen_at":    true,
+	"assignee_last_seen_at": true,
+}
+
+// isAgentRead reports whether a conversation_updated event is an agent reading the conversation
+func isAgentRead(payload *ChatwootWebhookPayload) bool {
+	for _, attribute := range payload.ChangedAttributes {
+		if agentReadAttributes[attribute] {
+			return true
+		}
+	}
+	return false
+}
+
+// handleConversationRead sends WhatsApp read receipts for the contact's messages in a conversation the
+// agent has seen, skipping messages a receipt was already sent for
+func (s *Service) handleConversationRead(ctx context.Context, sessionID string, payload *ChatwootWebhookPayload) error {
+	conversationID := payload.Conversation.ID
+	if conversationID == 0 {
+		// Conversation events carry the conversation itself at the top level
+		conversationID = payload.ID
+	}
+	if s.messageMapper == nil || conversationID == 0 {
+		return nil
+	}
+
+	config, err := s.repository.GetConfigBySessionID(ctx, sessionID)
+	if err != nil || !config.SendReads {
+		return nil
+	}
+
+	mappings, err := s.messageMapper.GetMappingsByCwConversation(ctx, sessionID, conversationID, maxReadReceiptMessages)
+	if err != nil {
+		return fmt.Errorf("failed to get conversation messages: %w", err)
+	}
+
+	// A receipt carries a single participant, so messages are batched per chat and sender
+	type receiptKey struct{ chat, sender string }
+	batches := make(map[receiptKey][]string)
+	keys := make([]receiptKey, 0, 1)
+	for _, mapping := range mappings {
+		if mapping.ZpFromMe || mapping.ReadSentAt != nil || mapping.SyncStatus == "deleted" {
+			continue
+		}
+
+		key := receiptKey{chat: mapping.ZpChat, sender: mapping.ZpSender}
+		if _, exists := batches[key]; !exists {
+			keys = append(keys, key)
+		}
+		batches[key] = append(batches[key], mapping.ZpMessageID)
+	}
+
+	for _, key := range keys {
+		if err := s.wameowManager.MarkRead(sessionID, key.chat, batches[key], key.sender); err != nil {
+			s.logger.WarnWithFields("Failed to send WhatsApp read receipt", map[string]interface{}{
+				"session_id":         sessionID,
+				"message_ids":        batches[key],
+				"cw_conversation_id": conversationID,
+				"error":              err.Error(),
+			})
+			continue
+		}
+
+		if err := s.messageMapper.MarkAsRead(ctx, sessionID, batches[key]); err != nil {
+			s.logger.WarnWithFields("Failed to record WhatsApp read receipt", map[string]interface{}{
+				"session_id":  sessionID,
+				"message_ids": batches[key],
+				"error":       err.Error(),
+			})
+		}
+	}
+
+	return nil
+}
+
+// handleMessageDeleted revokes the WhatsApp message of a message an agent deleted in Chatwoot.
+// Messages of the contact cannot be revoked, so they are only deleted for this account.
+func (s *Service) handleMessageDeleted(ctx context.Context, sessionID string, payload *ChatwootWebhookPayload) error {
+	if s.messageMapper == nil {
+		return nil
+	}
+
+	messageID := s.extractMessageID(payload)
+	if messageID == 0 {
+		return nil
+	}
+
+	mapping, err := s.messageMapper.GetMappingByCwID(ctx, sessionID, messageID)
+	if err != nil || mapping == nil {
+		s.logger.DebugWithFields("Deleted Chatwoot message not mapped to WhatsApp", map[string]interface{}{
+			"session_id":    sessionID,
+			"cw_message_id": messageID,
+		})
+		return nil
+	}
+
+	// Already deleted, either on WhatsApp, whose revoke deleted this message, or by an earlier webhook
+	if mapping.SyncStatus == "deleted" {
+		return nil
+	}
+
+	// Marked first so the revoke WhatsApp echoes is not synced back to Chatwoot
+	if err := s.messageMapper.MarkAsDeleted(ctx, sessionID, mapping.ZpMessageID); err != nil {
+		return err
+	}
+
+	if mapping.ZpFromMe {
+		_, err = s.wameowManager.RevokeMessage(sessionID, mapping.ZpChat, mapping.ZpMessageID, "")
+	} else {
+		participant := ""
+		if strings.HasSuffix(mapping.ZpChat, "@g.us") && mapping.ZpSender != "" {
+			participant = normalizeParticipantJID(mapping.ZpSender)
+		}
+		_, err = s.wameowManager.DeleteMessageForMe(sessionID, mapping.ZpChat, mapping.ZpMessageID, participant, false, mapping.ZpTimestamp, true)
+	}
+	if err != nil {
+		// Restore the mapping so a retried webhook deletes the message again
+		if mapping.CwConversationID != nil {
+			_ = s.messageMapper.UpdateMapping(ctx, sessionID, mapping.ZpMessageID, messageID, *mapping.CwConversationID)
+		}
+		return fmt.Errorf("failed to delete WhatsApp message: %w", err)
+	}
+
+	s.logger.InfoWithFields("Chatwoot message deletion synced to WhatsApp", map[string]interface{}{
+		"session_id":    sessionID,
+		"cw_message_id": messageID,
+		"message_id":    mapping.ZpMessageID,
+		"revoked":       mapping.ZpFromMe,
+	})
+
+	return nil
+}
+
+// isDeletedMessage reports whether a message_updated webhook is the deletion of the message
+func isDeletedMessage(payload *ChatwootWebhookPayload) bool {
+	attributes := payload.ContentAttributes
+	if payload.Message != nil && len(payload.Message.ContentAttributes) > 0 {
+		attributes = payload.Message.ContentAttributes
+	}
+
+	switch deleted := attributes["deleted"].(type) {
+	case bool:
+		return deleted
+	case string:
+		return deleted == "true"
+	default:
+		return false
+	}
+}
+
 // handleMessageCreated processes new messages from Chatwoot
 func (s *Service) handleMessageCreated(ctx context.Context, sessionID string, payload *ChatwootWebhookPayload) error {
 	// Extract and validate message details
@@ -382,7 +736,51 @@ func (s *Service) handleMessageCreated(ctx context.Context, sessionID string, pa
 		return nil
 	}
 
-	return s.sendToWhatsApp(ctx, sessionID, payload, content)
+	// Ignore redeliveries of a message that was already sent or is being sent
+	messageID := s.extractMessageID(payload)
+	dedupeKey, claimed := s.claimMessage(ctx, sessionID, messageID)
+	if !claimed {
+		s.logger.InfoWithFields("Skipping duplicate Chatwoot webhook", map[string]interface{}{
+			"session_id":    sessionID,
+			"cw_message_id": messageID,
+		})
+		return nil
+	}
+
+	if err := s.sendToWhatsApp(ctx, sessionID, payload, content); err != nil {
+		// Allow Chatwoot to retry a delivery that failed
+		if dedupeKey != "" {
+			s.deduplicator.Release(dedupeKey)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// claimMessage marks a Chatwoot message as being processed
+// Returns false when the message was already processed, either recently or persisted in the message mapping
+func (s *Service) claimMessage(ctx context.Context, sessionID string, messageID int) (string, bool) {
+	if messageID == 0 {
+		return "", true
+	}
+
+	if s.messageMapper != nil {
+		if mapping, err := s.messageMapper.GetMappingByCwID(ctx, sessionID, messageID); err == nil && mapping != nil {
+			return "", false
+		}
+	}
+
+	if s.deduplicator == nil {
+		return "", true
+	}
+
+	key := fmt.Sprintf("%s:%d", sessionID, messageID)
+	if !s.deduplicator.Claim(key) {
+		return "", false
+	}
+
+	return key, true
 }
 
 // extractMessageDetails extracts message information from webhook payload
@@ -479,18 +877,35 @@ func (s *Service) sendToWhatsApp(ctx context.Context, sessionID string, payload
 	formattedContent := s.formatContentForWhatsApp(content)
 	messageID := s.extractMessageID(payload)
 
+	// Quote the original WhatsApp message when the agent replied to a specific message
+	contextInfo := s.extractReplyContext(ctx, sessionID, payload)
+
 	// Send message to WhatsApp
-	result, err := s.wameowManager.SendMessage(sessionID, phoneNumber, "text", formattedContent, "", "", "", 0, 0, "", "", nil)
+	result, err := s.wameowManager.SendMessage(sessionID, phoneNumber, "text", formattedContent, "", "", "", "", 0, 0, "", "", contextInfo)
 	if err != nil {
 		return fmt.Errorf("failed to send message to WhatsApp: %w", err)
 	}
 
+	s.wameowManager.TraceRequest(history.WithActor(ctx, chatwootActor(payload)), sessionID, result.MessageID)
+
 	// Store message for tracking (non-blocking)
 	_ = s.storeOutgoingMessage(ctx, sessionID, result.MessageID, phoneNumber, formattedContent, result.Timestamp, messageID, payload.Conversation.ID)
 
 	return nil
 }
 
+// chatwootActor attributes a message sent from Chatwoot to the agent that wrote it, or to its
+// conversation when the sender is unknown
+func chatwootActor(payload *ChatwootWebhookPayload) *history.Actor {
+	actor := &history.Actor{Type: history.ActorChatwoot}
+	if payload.Sender.ID != 0 {
+		actor.ID = fmt.Sprintf("agent:%d", payload.Sender.ID)
+	} else if payload.Conversation.ID != 0 {
+		actor.ID = fmt.Sprintf("conversation:%d", payload.Conversation.ID)
+	}
+	return actor
+}
+
 // extractRecipientPhone determines the recipient phone number from payload
 func (s *Service) extractRecipientPhone(payload *ChatwootWebhookPayload) (string, error) {
 	messageType := "outgoing" // Default assumption
@@ -522,6 +937,70 @@ func (s *Service) extractMessageID(payload *ChatwootWebhookPayload) int {
 	return payload.ID
 }
 
+// extractReplyContext builds WhatsApp reply context from the in_reply_to content attribute
+// Returns nil when the message is not a reply or the replied message has no WhatsApp mapping
+func (s *Service) extractReplyContext(ctx context.Context, sessionID string, payload *ChatwootWebhookPayload) *message.ContextInfo {
+	if s.messageMapper == nil {
+		return nil
+	}
+
+	attributes := payload.ContentAttributes
+	if payload.Message != nil && len(payload.Message.ContentAttributes) > 0 {
+		attributes = payload.Message.ContentAttributes
+	}
+
+	inReplyTo := parseChatwootMessageID(attributes["in_reply_to"])
+	if inReplyTo == 0 {
+		return nil
+	}
+
+	mapping, err := s.messageMapper.GetMappingByCwID(ctx, sessionID, inReplyTo)
+	if err != nil || mapping == nil {
+		s.logger.DebugWithFields("Replied message not mapped to WhatsApp, sending without quote", map[string]interface{}{
+			"session_id":  sessionID,
+			"in_reply_to": inReplyTo,
+		})
+		return nil
+	}
+
+	contextInfo := &message.ContextInfo{
+		StanzaID: mapping.ZpMessageID,
+	}
+
+	// Messages we sent are quoted without participant; customer messages need the sender JID
+	if !mapping.ZpFromMe && mapping.ZpSender != "" {
+		contextInfo.Participant = normalizeParticipantJID(mapping.ZpSender)
+	}
+
+	return contextInfo
+}
+
+// parseChatwootMessageID converts a content attribute value into a Chatwoot message ID
+func parseChatwootMessageID(value interface{}) int {
+	switch v := value.(type) {
+	case float64:
+		return int(v)
+	case int:
+		return v
+	case string:
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return 0
+		}
+		return id
+	default:
+		return 0
+	}
+}
+
+// normalizeParticipantJID converts a stored sender (JID or phone number) into a user JID
+func normalizeParticipantJID(sender string) string {
+	if strings.Contains(sender, "@") {
+		return sender
+	}
+	return strings.TrimPrefix(sender, "+") + "@s.whatsapp.net"
+}
+
 // storeOutgoingMessage stores an outgoing message in the zpMessage table
 func (s *Service) storeOutgoingMessage(ctx context.Context, sessionID, whatsappMessageID, phoneNumber, content string, timestamp time.Time, chatwootMessageID, chatwootConversationID int) error {
 	// Check if we have a message mapper available
@@ -533,8 +1012,9 @@ func (s *Service) storeOutgoingMessage(ctx context.Context, sessionID, whatsappM
 		return nil
 	}
 
-	// Create mapping for outgoing message
-	mapping, err := s.messageMapper.CreateMapping(ctx, sessionID, whatsappMessageID, phoneNumber, phoneNumber, "text", content, timestamp, true)
+	// Create mapping for outgoing message, keyed by the chat JID the WhatsApp echo of this message carries
+	chatJID := normalizeParticipantJID(phoneNumber)
+	mapping, _, err := s.messageMapper.CreateMapping(ctx, sessionID, whatsappMessageID, chatJID, chatJID, "text", content, timestamp, true)
 	if err != nil {
 		return fmt.Errorf("failed to create mapping for outgoing message: %w", err)
 	}
@@ -0,0 +1,46 @@
+package chatwoot
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifyWebhookAuth(t *testing.T) {
+	const secret = "webhook-secret"
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"event":"message_created"}`)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	stale := strconv.FormatInt(now.Add(-2*WebhookSignatureTolerance).Unix(), 10)
+
+	unsigned := hmac.New(sha256.New, []byte(secret))
+	unsigned.Write(body)
+
+	cases := map[string]struct {
+		auth *WebhookAuth
+		want bool
+	}{
+		"token":              {&WebhookAuth{Token: secret}, true},
+		"wrong token":        {&WebhookAuth{Token: "other"}, false},
+		"signed":             {&WebhookAuth{Signature: sign(secret, timestamp, body), Timestamp: timestamp, Body: body}, true},
+		"stale timestamp":    {&WebhookAuth{Signature: sign(secret, stale, body), Timestamp: stale, Body: body}, false},
+		"missing timestamp":  {&WebhookAuth{Signature: "sha256=" + hex.EncodeToString(unsigned.Sum(nil)), Body: body}, false},
+		"tampered body":      {&WebhookAuth{Signature: sign(secret, timestamp, body), Timestamp: timestamp, Body: []byte("{}")}, false},
+		"nothing to present": {&WebhookAuth{Body: body}, false},
+	}
+	for name, tc := range cases {
+		if got := verifyWebhookAuth(secret, tc.auth, now); got != tc.want {
+			t.Errorf("%s: verifyWebhookAuth = %v, want %v", name, got, tc.want)
+		}
+	}
+}
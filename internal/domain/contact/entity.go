@@ -40,6 +40,10 @@ type WhatsAppStatus struct {
 	JID          string `json:"jid,omitempty"`
 	IsBusiness   bool   `json:"is_business,omitempty"`
 	VerifiedName string `json:"verified_name,omitempty"`
+	// E164 is the number in E.164 form, set when it is a possible number
+	E164 string `json:"e164,omitempty"`
+	// InvalidReason tells why the number is impossible; such numbers are not checked with WhatsApp
+	InvalidReason string `json:"invalid_reason,omitempty"`
 }
 
 // CheckWhatsAppResponse represents the response for checking WhatsApp numbers
@@ -187,3 +191,59 @@ type GetContactStatsResponse struct {
 	Stats     ContactStats `json:"stats"`
 	UpdatedAt time.Time    `json:"updated_at"`
 }
+
+// SubscribePresenceRequest represents a request to follow the presence of contacts
+type SubscribePresenceRequest struct {
+	SessionID string   `json:"session_id"`
+	JIDs      []string `json:"jids"`
+}
+
+// SubscribePresenceResponse represents the contacts subscribed and why the others failed
+type SubscribePresenceResponse struct {
+	Subscribed []string          `json:"subscribed"`
+	Failed     map[string]string `json:"failed,omitempty"`
+}
+
+// ListPresenceSubscriptionsRequest represents a request to list the contacts whose presence is followed
+type ListPresenceSubscriptionsRequest struct {
+	SessionID string `json:"session_id"`
+}
+
+// ListPresenceSubscriptionsResponse represents the contacts whose presence is followed
+type ListPresenceSubscriptionsResponse struct {
+	JIDs  []string `json:"jids"`
+	Total int      `json:"total"`
+}
+
+// ChangeType is what changed about a contact in the device store
+type ChangeType string
+
+const (
+	ChangeTypePushName     ChangeType = "push_name"
+	ChangeTypeBusinessName ChangeType = "business_name"
+	ChangeTypePicture      ChangeType = "picture"
+	// ChangeTypeContact is a contact added or renamed in the phone's address book
+	ChangeTypeContact ChangeType = "contact"
+)
+
+// Change is an entry of a session's contact change feed; only the fields of its type are set
+type Change struct {
+	ID             int64      `json:"id"`
+	SessionID      string     `json:"session_id"`
+	ContactJID     string     `json:"contact_jid"`
+	Type           ChangeType `json:"type"`
+	PushName       string     `json:"push_name,omitempty"`
+	BusinessName   string     `json:"business_name,omitempty"`
+	FullName       string     `json:"full_name,omitempty"`
+	FirstName      string     `json:"first_name,omitempty"`
+	PictureID      string     `json:"picture_id,omitempty"`
+	PictureRemoved bool       `json:"picture_removed,omitempty"`
+	ChangedAt      time.Time  `json:"changed_at"`
+}
+
+// ListChangesRequest lists the changes recorded after Since, oldest first
+type ListChangesRequest struct {
+	SessionID string    `json:"session_id"`
+	Since     time.Time `json:"since"`
+	Limit     int       `json:"limit"`
+}
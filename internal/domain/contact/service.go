@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"zpwoot/platform/logger"
+	"zpwoot/platform/phone"
 )
 
 // Service defines the interface for contact domain service
@@ -18,6 +19,8 @@ type Service interface {
 	SyncContacts(ctx context.Context, req *SyncContactsRequest) (*SyncContactsResponse, error)
 	GetBusinessProfile(ctx context.Context, req *GetBusinessProfileRequest) (*GetBusinessProfileResponse, error)
 	GetContactStats(ctx context.Context, req *GetContactStatsRequest) (*GetContactStatsResponse, error)
+	SubscribePresence(ctx context.Context, req *SubscribePresenceRequest) (*SubscribePresenceResponse, error)
+	ListPresenceSubscriptions(ctx context.Context, req *ListPresenceSubscriptionsRequest) (*ListPresenceSubscriptionsResponse, error)
 }
 
 // WameowManager defines the interface for multi-session WhatsApp operations
@@ -27,6 +30,8 @@ type WameowManager interface {
 	GetUserInfo(ctx context.Context, sessionID string, jids []string) ([]map[string]interface{}, error)
 	GetBusinessProfile(ctx context.Context, sessionID, jid string) (map[string]interface{}, error)
 	GetAllContacts(ctx context.Context, sessionID string) (map[string]interface{}, error)
+	SubscribePresence(ctx context.Context, sessionID string, jids []string) ([]string, map[string]string, error)
+	ListPresenceSubscriptions(sessionID string) ([]string, error)
 }
 
 type service struct {
@@ -53,20 +58,46 @@ func (s *service) CheckWhatsApp(ctx context.Context, req *CheckWhatsAppRequest)
 		"phone_count": len(req.PhoneNumbers),
 	})
 
-	// Check with WhatsApp using real whatsmeow method
-	statusMap, err := s.wameowManager.IsOnWhatsApp(ctx, req.SessionID, req.PhoneNumbers)
-	if err != nil {
-		s.logger.ErrorWithFields("Failed to check WhatsApp numbers", map[string]interface{}{
-			"session_id": req.SessionID,
-			"error":      err.Error(),
-		})
-		return nil, fmt.Errorf("failed to check WhatsApp numbers: %w", err)
+	// Impossible numbers are reported without asking WhatsApp about them
+	formatted := make(map[string]string, len(req.PhoneNumbers))
+	invalid := make(map[string]string)
+	possible := make([]string, 0, len(req.PhoneNumbers))
+	for _, phoneNumber := range req.PhoneNumbers {
+		number, err := phone.Parse(phoneNumber)
+		if err != nil {
+			invalid[phoneNumber] = err.Error()
+			continue
+		}
+		formatted[phoneNumber] = number.E164()
+		possible = append(possible, phoneNumber)
+	}
+
+	statusMap := map[string]interface{}{}
+	if len(possible) > 0 {
+		// Check with WhatsApp using real whatsmeow method
+		var err error
+		statusMap, err = s.wameowManager.IsOnWhatsApp(ctx, req.SessionID, possible)
+		if err != nil {
+			s.logger.ErrorWithFields("Failed to check WhatsApp numbers", map[string]interface{}{
+				"session_id": req.SessionID,
+				"error":      err.Error(),
+			})
+			return nil, fmt.Errorf("failed to check WhatsApp numbers: %w", err)
+		}
 	}
 
 	// Convert map to slice
 	results := make([]WhatsAppStatus, 0, len(req.PhoneNumbers))
 	checked := 0
 	for _, phoneNumber := range req.PhoneNumbers {
+		if reason, isInvalid := invalid[phoneNumber]; isInvalid {
+			results = append(results, WhatsAppStatus{
+				PhoneNumber:   phoneNumber,
+				InvalidReason: reason,
+			})
+			continue
+		}
+
 		if statusData, exists := statusMap[phoneNumber]; exists {
 			if statusMap, ok := statusData.(map[string]interface{}); ok {
 				status := WhatsAppStatus{
@@ -75,6 +106,7 @@ func (s *service) CheckWhatsApp(ctx context.Context, req *CheckWhatsAppRequest)
 					JID:          getStringFromMap(statusMap, "jid"),
 					IsBusiness:   getBoolFromMap(statusMap, "is_business"),
 					VerifiedName: getStringFromMap(statusMap, "verified_name"),
+					E164:         formatted[phoneNumber],
 				}
 				results = append(results, status)
 				checked++
@@ -84,6 +116,7 @@ func (s *service) CheckWhatsApp(ctx context.Context, req *CheckWhatsAppRequest)
 			results = append(results, WhatsAppStatus{
 				PhoneNumber:  phoneNumber,
 				IsOnWhatsApp: false,
+				E164:         formatted[phoneNumber],
 			})
 		}
 	}
@@ -404,6 +437,49 @@ func (s *service) GetContactStats(ctx context.Context, req *GetContactStatsReque
 	}, fmt.Errorf("GetContactStats not supported by whatsmeow - contact stats not available")
 }
 
+// SubscribePresence follows the presence of contacts; updates are delivered as Presence webhook events
+func (s *service) SubscribePresence(ctx context.Context, req *SubscribePresenceRequest) (*SubscribePresenceResponse, error) {
+	if err := s.validateSubscribePresenceRequest(req); err != nil {
+		return nil, err
+	}
+
+	s.logger.InfoWithFields("Subscribing to presence", map[string]interface{}{
+		"session_id": req.SessionID,
+		"jid_count":  len(req.JIDs),
+	})
+
+	subscribed, failed, err := s.wameowManager.SubscribePresence(ctx, req.SessionID, req.JIDs)
+	if err != nil {
+		s.logger.ErrorWithFields("Failed to subscribe to presence", map[string]interface{}{
+			"session_id": req.SessionID,
+			"error":      err.Error(),
+		})
+		return nil, fmt.Errorf("failed to subscribe to presence: %w", err)
+	}
+
+	return &SubscribePresenceResponse{
+		Subscribed: subscribed,
+		Failed:     failed,
+	}, nil
+}
+
+// ListPresenceSubscriptions lists the contacts whose presence the session follows
+func (s *service) ListPresenceSubscriptions(ctx context.Context, req *ListPresenceSubscriptionsRequest) (*ListPresenceSubscriptionsResponse, error) {
+	if req.SessionID == "" {
+		return nil, ErrInvalidSessionID
+	}
+
+	jids, err := s.wameowManager.ListPresenceSubscriptions(req.SessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list presence subscriptions: %w", err)
+	}
+
+	return &ListPresenceSubscriptionsResponse{
+		JIDs:  jids,
+		Total: len(jids),
+	}, nil
+}
+
 // Validation methods
 func (s *service) validateCheckWhatsAppRequest(req *CheckWhatsAppRequest) error {
 	if req.SessionID == "" {
@@ -478,6 +554,19 @@ func (s *service) validateGetContactStatsRequest(req *GetContactStatsRequest) er
 	return nil
 }
 
+func (s *service) validateSubscribePresenceRequest(req *SubscribePresenceRequest) error {
+	if req.SessionID == "" {
+		return ErrInvalidSessionID
+	}
+	if len(req.JIDs) == 0 {
+		return fmt.Errorf("at least one JID is required")
+	}
+	if len(req.JIDs) > 100 {
+		return fmt.Errorf("maximum 100 JIDs allowed")
+	}
+	return nil
+}
+
 // Helper functions to extract values from map[string]interface{}
 func getStringFromMap(m map[string]interface{}, key string) string {
 	if val, ok := m[key]; ok {
@@ -508,3 +597,89 @@ func getTimeFromMap(m map[string]interface{}, key string) *time.Time {
 	}
 	return nil
 }
+
+// changeRetention is how long contact changes stay in the feed
+const changeRetention = 30 * 24 * time.Hour
+
+// Contact change feed page sizes
+const (
+	defaultChangesLimit = 100
+	maxChangesLimit     = 500
+)
+
+// ChangeRepository stores the contact change feed
+type ChangeRepository interface {
+	Create(ctx context.Context, change *Change) error
+	// ListSince returns up to limit changes recorded after since, oldest first
+	ListSince(ctx context.Context, sessionID string, since time.Time, limit int) ([]*Change, error)
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// ChangeFeed records pushname, avatar and address book changes so CRMs can poll them
+type ChangeFeed struct {
+	logger *logger.Logger
+	repo   ChangeRepository
+}
+
+// NewChangeFeed creates the contact change feed
+func NewChangeFeed(logger *logger.Logger, repo ChangeRepository) *ChangeFeed {
+	return &ChangeFeed{
+		logger: logger,
+		repo:   repo,
+	}
+}
+
+// Record adds a change to the feed, stamped with the time it was recorded
+func (f *ChangeFeed) Record(ctx context.Context, change *Change) error {
+	if change.SessionID == "" {
+		return ErrInvalidSessionID
+	}
+	if change.ContactJID == "" {
+		return ErrInvalidJID
+	}
+	// The feed is ordered by arrival so pollers never miss a change carrying an old timestamp
+	change.ChangedAt = time.Now()
+
+	return f.repo.Create(ctx, change)
+}
+
+// ListChanges returns the changes recorded after req.Since and whether more are available
+func (f *ChangeFeed) ListChanges(ctx context.Context, req *ListChangesRequest) ([]*Change, bool, error) {
+	if req.SessionID == "" {
+		return nil, false, ErrInvalidSessionID
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultChangesLimit
+	}
+	if limit > maxChangesLimit {
+		limit = maxChangesLimit
+	}
+
+	changes, err := f.repo.ListSince(ctx, req.SessionID, req.Since, limit+1)
+	if err != nil {
+		return nil, false, err
+	}
+
+	hasMore := len(changes) > limit
+	if hasMore {
+		changes = changes[:limit]
+	}
+
+	return changes, hasMore, nil
+}
+
+// PruneExpired drops changes older than the retention period
+func (f *ChangeFeed) PruneExpired(ctx context.Context) error {
+	deleted, err := f.repo.DeleteBefore(ctx, time.Now().Add(-changeRetention))
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		f.logger.InfoWithFields("Old contact changes pruned", map[string]interface{}{
+			"count": deleted,
+		})
+	}
+	return nil
+}
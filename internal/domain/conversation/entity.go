@@ -0,0 +1,59 @@
+package conversation
+
+import (
+	"errors"
+	"time"
+)
+
+// InitialState is the state of a chat that has no stored state, or whose state expired
+const InitialState = "start"
+
+// Limits that keep a conversation state small enough to load on every incoming message
+const (
+	maxStateLength = 100
+	maxDataEntries = 50
+)
+
+// State is where a chat stands in a multi-step conversation, e.g. the menu level of a bot, with the
+// values collected along the way. A chat has at most one state.
+type State struct {
+	SessionID string            `json:"session_id"`
+	ChatJID   string            `json:"chat_jid"`
+	State     string            `json:"state"`
+	Data      map[string]string `json:"data"`
+	// ExpiresAt resets the chat to the initial state when reached; nil keeps the state until changed
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+var (
+	ErrStateNotFound  = errors.New("conversation state not found")
+	ErrInvalidChatJID = errors.New("chat JID is required")
+	ErrInvalidState   = errors.New("conversation state must be between 1 and 100 characters")
+	ErrInvalidTTL     = errors.New("conversation state TTL must not be negative")
+	ErrTooMuchData    = errors.New("conversation state data holds at most 50 entries")
+)
+
+// SetStateRequest moves a chat to a state. Data replaces the stored values unless MergeData is set, and
+// TTL, when positive, expires the state after that long.
+type SetStateRequest struct {
+	SessionID string            `json:"session_id" validate:"required"`
+	ChatJID   string            `json:"chat_jid" validate:"required"`
+	State     string            `json:"state" validate:"required"`
+	Data      map[string]string `json:"data,omitempty"`
+	MergeData bool              `json:"merge_data,omitempty"`
+	TTL       time.Duration     `json:"ttl,omitempty"`
+}
+
+type ListStatesRequest struct {
+	SessionID string `json:"session_id" validate:"required"`
+	State     string `json:"state,omitempty" query:"state"`
+	Limit     int    `json:"limit,omitempty" query:"limit" validate:"omitempty,min=1,max=100"`
+	Offset    int    `json:"offset,omitempty" query:"offset" validate:"omitempty,min=0"`
+}
+
+// Expired reports whether the state reached its expiry
+func (s *State) Expired(now time.Time) bool {
+	return s.ExpiresAt != nil && !now.Before(*s.ExpiresAt)
+}
@@ -0,0 +1,174 @@
+package conversation
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"zpwoot/platform/logger"
+)
+
+// Repository defines the interface for conversation state data operations
+type Repository interface {
+	Upsert(ctx context.Context, state *State) error
+	Get(ctx context.Context, sessionID, chatJID string) (*State, error)
+	List(ctx context.Context, req *ListStatesRequest) ([]*State, int, error)
+	Delete(ctx context.Context, sessionID, chatJID string) error
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// Service keeps the per chat state of multi-step conversations, read and moved by routing rules and by
+// API clients answering button and list replies
+type Service struct {
+	logger *logger.Logger
+	repo   Repository
+}
+
+func NewService(logger *logger.Logger, repo Repository) *Service {
+	return &Service{
+		logger: logger,
+		repo:   repo,
+	}
+}
+
+// GetState returns the state of a chat; expired states are reported as not found
+func (s *Service) GetState(ctx context.Context, sessionID, chatJID string) (*State, error) {
+	chatJID = strings.TrimSpace(chatJID)
+	if chatJID == "" {
+		return nil, ErrInvalidChatJID
+	}
+
+	state, err := s.repo.Get(ctx, sessionID, chatJID)
+	if err != nil {
+		return nil, err
+	}
+	if state.Expired(time.Now()) {
+		return nil, ErrStateNotFound
+	}
+
+	return state, nil
+}
+
+// SetState moves a chat to a state, replacing or merging its data
+func (s *Service) SetState(ctx context.Context, req *SetStateRequest) (*State, error) {
+	chatJID := strings.TrimSpace(req.ChatJID)
+	if chatJID == "" {
+		return nil, ErrInvalidChatJID
+	}
+	name := strings.TrimSpace(req.State)
+	if name == "" || len(name) > maxStateLength {
+		return nil, ErrInvalidState
+	}
+	if req.TTL < 0 {
+		return nil, ErrInvalidTTL
+	}
+
+	now := time.Now()
+	state := &State{
+		SessionID: req.SessionID,
+		ChatJID:   chatJID,
+		State:     name,
+		Data:      map[string]string{},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if req.MergeData {
+		current, err := s.GetState(ctx, req.SessionID, chatJID)
+		if err != nil && !errors.Is(err, ErrStateNotFound) {
+			return nil, err
+		}
+		if current != nil {
+			state.CreatedAt = current.CreatedAt
+			for key, value := range current.Data {
+				state.Data[key] = value
+			}
+		}
+	}
+	for key, value := range req.Data {
+		state.Data[key] = value
+	}
+	if len(state.Data) > maxDataEntries {
+		return nil, ErrTooMuchData
+	}
+
+	if req.TTL > 0 {
+		expiresAt := now.Add(req.TTL)
+		state.ExpiresAt = &expiresAt
+	}
+
+	if err := s.repo.Upsert(ctx, state); err != nil {
+		return nil, err
+	}
+
+	s.logger.DebugWithFields("Conversation state set", map[string]interface{}{
+		"session_id": req.SessionID,
+		"chat_jid":   chatJID,
+		"state":      name,
+	})
+
+	return state, nil
+}
+
+func (s *Service) ListStates(ctx context.Context, req *ListStatesRequest) ([]*State, int, error) {
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+	return s.repo.List(ctx, req)
+}
+
+// ResetState returns a chat to the initial state by dropping its stored state
+func (s *Service) ResetState(ctx context.Context, sessionID, chatJID string) error {
+	chatJID = strings.TrimSpace(chatJID)
+	if chatJID == "" {
+		return ErrInvalidChatJID
+	}
+	return s.repo.Delete(ctx, sessionID, chatJID)
+}
+
+// CurrentState returns the state name of a chat, or InitialState when it has none; lookup failures are
+// logged and treated as the initial state so message delivery never waits on them
+func (s *Service) CurrentState(ctx context.Context, sessionID, chatJID string) string {
+	state, err := s.GetState(ctx, sessionID, chatJID)
+	if err != nil {
+		if !errors.Is(err, ErrStateNotFound) {
+			s.logger.WarnWithFields("Failed to load conversation state", map[string]interface{}{
+				"session_id": sessionID,
+				"chat_jid":   chatJID,
+				"error":      err.Error(),
+			})
+		}
+		return InitialState
+	}
+	return state.State
+}
+
+// Transition moves a chat to a state keeping the data collected so far; moving to InitialState resets it
+func (s *Service) Transition(ctx context.Context, sessionID, chatJID, state string) error {
+	if state == InitialState {
+		return s.ResetState(ctx, sessionID, chatJID)
+	}
+
+	_, err := s.SetState(ctx, &SetStateRequest{
+		SessionID: sessionID,
+		ChatJID:   chatJID,
+		State:     state,
+		MergeData: true,
+	})
+	return err
+}
+
+// PruneExpired drops expired states
+func (s *Service) PruneExpired(ctx context.Context) error {
+	deleted, err := s.repo.DeleteExpired(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		s.logger.InfoWithFields("Expired conversation states pruned", map[string]interface{}{
+			"count": deleted,
+		})
+	}
+	return nil
+}
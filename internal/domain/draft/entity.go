@@ -0,0 +1,75 @@
+package draft
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"zpwoot/platform/pagination"
+)
+
+// Draft is an unsent message kept server-side for a chat; a chat has at most one draft
+type Draft struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	SessionID   string     `json:"session_id" db:"session_id"`
+	ChatJID     string     `json:"chat_jid" db:"chat_jid"`
+	Text        string     `json:"text" db:"text"`
+	Status      string     `json:"status" db:"status"`
+	ScheduledAt *time.Time `json:"scheduled_at,omitempty" db:"scheduled_at"`
+	LastError   string     `json:"last_error,omitempty" db:"last_error"`
+	// Workspace is the workspace a scheduled draft is accounted to once the scheduler sends it
+	Workspace string    `json:"workspace,omitempty" db:"workspace"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Draft statuses; drafts are deleted once they are sent
+const (
+	StatusDraft     = "draft"
+	StatusScheduled = "scheduled"
+	StatusSending   = "sending"
+	StatusFailed    = "failed"
+)
+
+var (
+	ErrDraftNotFound  = errors.New("draft not found")
+	ErrEmptyDraft     = errors.New("draft text is required")
+	ErrInvalidChatJID = errors.New("chat JID is required")
+	ErrDraftSending   = errors.New("draft is already being sent")
+)
+
+type SaveDraftRequest struct {
+	SessionID string `json:"session_id" validate:"required"`
+	ChatJID   string `json:"chat_jid" validate:"required"`
+	Text      string `json:"text" validate:"required"`
+}
+
+type ListDraftsRequest struct {
+	SessionID string `json:"session_id" validate:"required"`
+	Status    string `json:"status,omitempty" query:"status"`
+	Limit     int    `json:"limit,omitempty" query:"limit" validate:"omitempty,min=1,max=100"`
+	Offset    int    `json:"offset,omitempty" query:"offset" validate:"omitempty,min=0"`
+	// After continues the list past a cursor instead of skipping Offset rows
+	After *pagination.Cursor `json:"-"`
+}
+
+// SendDraftRequest sends a draft now, or schedules it when SendAt is in the future
+type SendDraftRequest struct {
+	SessionID string     `json:"session_id" validate:"required"`
+	ChatJID   string     `json:"chat_jid" validate:"required"`
+	SendAt    *time.Time `json:"send_at,omitempty"`
+}
+
+// SendDraftResult describes what happened to a draft that was sent or scheduled
+type SendDraftResult struct {
+	Draft     *Draft    `json:"draft"`
+	Status    string    `json:"status"` // sent or scheduled
+	MessageID string    `json:"message_id,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// IsScheduled reports whether the draft is waiting to be sent by the scheduler
+func (d *Draft) IsScheduled() bool {
+	return d.Status == StatusScheduled && d.ScheduledAt != nil
+}
@@ -0,0 +1,305 @@
+package draft
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"zpwoot/internal/domain/history"
+	"zpwoot/internal/domain/maintenance"
+	"zpwoot/internal/domain/message"
+	"zpwoot/internal/domain/usage"
+	"zpwoot/platform/logger"
+)
+
+// dueDraftsBatchSize limits how many scheduled drafts are claimed per scheduler tick
+const dueDraftsBatchSize = 50
+
+// staleClaimTimeout is how long a draft may stay in sending before the scheduler takes it back
+const staleClaimTimeout = 10 * time.Minute
+
+// Repository defines the interface for draft data operations
+type Repository interface {
+	Upsert(ctx context.Context, draft *Draft) error
+	GetByChat(ctx context.Context, sessionID, chatJID string) (*Draft, error)
+	List(ctx context.Context, req *ListDraftsRequest) ([]*Draft, int, error)
+	Update(ctx context.Context, draft *Draft) error
+	Delete(ctx context.Context, sessionID, chatJID string) error
+	ClaimDue(ctx context.Context, until time.Time, limit int) ([]*Draft, error)
+	// ReleaseStale reschedules drafts claimed before claimedBefore and returns how many
+	ReleaseStale(ctx context.Context, claimedBefore time.Time) (int, error)
+	GetByID(ctx context.Context, sessionID string, id uuid.UUID) (*Draft, error)
+	// CancelScheduled turns a scheduled draft back into a plain draft unless the scheduler already
+	// claimed it, and reports whether it did
+	CancelScheduled(ctx context.Context, sessionID string, id uuid.UUID) (bool, error)
+}
+
+// Sender sends the text of a draft through the regular send path
+type Sender interface {
+	SendQueued(ctx context.Context, sessionID string, req *message.SendMessageRequest) (*message.SendResult, error)
+}
+
+// SendGate reports whether sends are frozen for a session, e.g. during maintenance
+type SendGate interface {
+	SendFrozen(sessionID string) bool
+}
+
+type Service struct {
+	logger    *logger.Logger
+	draftRepo Repository
+	sender    Sender
+	sendGate  SendGate
+}
+
+func NewService(logger *logger.Logger, draftRepo Repository) *Service {
+	return &Service{
+		logger:    logger,
+		draftRepo: draftRepo,
+	}
+}
+
+// SetSender sets what sends the drafts; it is set after creation because the sender is built on top
+// of the domain services
+func (s *Service) SetSender(sender Sender) {
+	s.sender = sender
+}
+
+// SetSendGate makes the scheduler hold back drafts of sessions whose sends are frozen
+func (s *Service) SetSendGate(gate SendGate) {
+	s.sendGate = gate
+}
+
+// SaveDraft creates or replaces the draft of a chat; saving a scheduled draft turns it back into a plain draft
+func (s *Service) SaveDraft(ctx context.Context, req *SaveDraftRequest) (*Draft, error) {
+	chatJID := strings.TrimSpace(req.ChatJID)
+	if chatJID == "" {
+		return nil, ErrInvalidChatJID
+	}
+	if strings.TrimSpace(req.Text) == "" {
+		return nil, ErrEmptyDraft
+	}
+
+	now := time.Now()
+	draft := &Draft{
+		ID:        uuid.New(),
+		SessionID: req.SessionID,
+		ChatJID:   chatJID,
+		Text:      req.Text,
+		Status:    StatusDraft,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := s.draftRepo.Upsert(ctx, draft); err != nil {
+		return nil, fmt.Errorf("failed to save draft: %w", err)
+	}
+
+	s.logger.DebugWithFields("Draft saved", map[string]interface{}{
+		"session_id": req.SessionID,
+		"chat_jid":   chatJID,
+	})
+
+	return s.draftRepo.GetByChat(ctx, req.SessionID, chatJID)
+}
+
+func (s *Service) GetDraft(ctx context.Context, sessionID, chatJID string) (*Draft, error) {
+	return s.draftRepo.GetByChat(ctx, sessionID, chatJID)
+}
+
+func (s *Service) ListDrafts(ctx context.Context, req *ListDraftsRequest) ([]*Draft, int, error) {
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+
+	return s.draftRepo.List(ctx, req)
+}
+
+func (s *Service) DeleteDraft(ctx context.Context, sessionID, chatJID string) error {
+	if _, err := s.draftRepo.GetByChat(ctx, sessionID, chatJID); err != nil {
+		return err
+	}
+
+	return s.draftRepo.Delete(ctx, sessionID, chatJID)
+}
+
+// SendDraft sends a draft immediately, or schedules it when SendAt is in the future
+func (s *Service) SendDraft(ctx context.Context, req *SendDraftRequest) (*SendDraftResult, error) {
+	draft, err := s.draftRepo.GetByChat(ctx, req.SessionID, req.ChatJID)
+	if err != nil {
+		return nil, err
+	}
+
+	if draft.Status == StatusSending {
+		return nil, ErrDraftSending
+	}
+
+	if req.SendAt != nil && req.SendAt.After(time.Now()) {
+		sendAt := req.SendAt.UTC()
+		draft.Status = StatusScheduled
+		draft.ScheduledAt = &sendAt
+		draft.LastError = ""
+		draft.Workspace = usage.WorkspaceFromContext(ctx)
+
+		if err := s.draftRepo.Update(ctx, draft); err != nil {
+			return nil, fmt.Errorf("failed to schedule draft: %w", err)
+		}
+
+		s.logger.InfoWithFields("Draft scheduled", map[string]interface{}{
+			"session_id":   draft.SessionID,
+			"chat_jid":     draft.ChatJID,
+			"scheduled_at": sendAt,
+		})
+
+		return &SendDraftResult{
+			Draft:     draft,
+			Status:    StatusScheduled,
+			Timestamp: time.Now(),
+		}, nil
+	}
+
+	return s.deliver(ctx, draft, false)
+}
+
+// CancelScheduled keeps a scheduled draft from being sent, leaving it as a plain draft of its chat; it
+// reports false with the draft's current state when it is not scheduled anymore
+func (s *Service) CancelScheduled(ctx context.Context, sessionID string, id uuid.UUID) (*Draft, bool, error) {
+	cancelled, err := s.draftRepo.CancelScheduled(ctx, sessionID, id)
+	if err != nil {
+		return nil, false, err
+	}
+
+	draft, err := s.draftRepo.GetByID(ctx, sessionID, id)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if cancelled {
+		s.logger.InfoWithFields("Scheduled draft cancelled", map[string]interface{}{
+			"session_id": sessionID,
+			"chat_jid":   draft.ChatJID,
+		})
+	}
+
+	return draft, cancelled, nil
+}
+
+// DispatchDue sends every scheduled draft whose time has come
+func (s *Service) DispatchDue(ctx context.Context) error {
+	if s.sender == nil {
+		return nil
+	}
+	// Nothing can be sent while the whole instance is frozen
+	if s.sendGate != nil && s.sendGate.SendFrozen("") {
+		return nil
+	}
+
+	if released, err := s.draftRepo.ReleaseStale(ctx, time.Now().Add(-staleClaimTimeout)); err != nil {
+		s.logger.ErrorWithFields("Failed to release stale draft claims", map[string]interface{}{
+			"error": err.Error(),
+		})
+	} else if released > 0 {
+		s.logger.WarnWithFields("Draft claims abandoned in sending were released", map[string]interface{}{
+			"count": released,
+		})
+	}
+
+	drafts, err := s.draftRepo.ClaimDue(ctx, time.Now(), dueDraftsBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to claim due drafts: %w", err)
+	}
+
+	sent := 0
+	for _, draft := range drafts {
+		if s.sendGate != nil && s.sendGate.SendFrozen(draft.SessionID) {
+			s.reschedule(ctx, draft)
+			continue
+		}
+		if _, err := s.deliver(history.WithActor(ctx, schedulerActor(draft)), draft, true); err == nil {
+			sent++
+		}
+	}
+
+	if sent > 0 {
+		s.logger.InfoWithFields("Scheduled drafts sent", map[string]interface{}{
+			"count": sent,
+		})
+	}
+	return nil
+}
+
+// reschedule returns a claimed draft to the scheduler so it is retried on a later tick
+func (s *Service) reschedule(ctx context.Context, draft *Draft) {
+	draft.Status = StatusScheduled
+	if err := s.draftRepo.Update(ctx, draft); err != nil {
+		s.logger.ErrorWithFields("Failed to reschedule draft", map[string]interface{}{
+			"session_id": draft.SessionID,
+			"chat_jid":   draft.ChatJID,
+			"error":      err.Error(),
+		})
+	}
+}
+
+// schedulerActor attributes a scheduled send to the draft it came from
+func schedulerActor(draft *Draft) *history.Actor {
+	return &history.Actor{Type: history.ActorScheduler, ID: "draft:" + draft.ID.String()}
+}
+
+// deliver sends the draft text through the regular send path, accounted to the workspace that
+// scheduled it, and removes the draft; failures are kept on the draft for inspection. A scheduled
+// draft caught by a send freeze goes back to the scheduler instead of failing
+func (s *Service) deliver(ctx context.Context, draft *Draft, scheduled bool) (*SendDraftResult, error) {
+	sendCtx := ctx
+	if draft.Workspace != "" {
+		sendCtx = context.WithValue(ctx, usage.WorkspaceContextKey, draft.Workspace)
+	}
+
+	result, err := s.sender.SendQueued(sendCtx, draft.SessionID, &message.SendMessageRequest{
+		To:   draft.ChatJID,
+		Type: message.MessageTypeText,
+		Body: draft.Text,
+	})
+	if err != nil {
+		s.logger.WarnWithFields("Failed to send draft", map[string]interface{}{
+			"session_id": draft.SessionID,
+			"chat_jid":   draft.ChatJID,
+			"error":      err.Error(),
+		})
+
+		if scheduled && errors.Is(err, maintenance.ErrSendFrozen) {
+			draft.LastError = err.Error()
+			s.reschedule(ctx, draft)
+			return nil, fmt.Errorf("failed to send draft: %w", err)
+		}
+
+		draft.Status = StatusFailed
+		draft.LastError = err.Error()
+		if updateErr := s.draftRepo.Update(ctx, draft); updateErr != nil {
+			s.logger.ErrorWithFields("Failed to record draft failure", map[string]interface{}{
+				"session_id": draft.SessionID,
+				"chat_jid":   draft.ChatJID,
+				"error":      updateErr.Error(),
+			})
+		}
+
+		return nil, fmt.Errorf("failed to send draft: %w", err)
+	}
+
+	if err := s.draftRepo.Delete(ctx, draft.SessionID, draft.ChatJID); err != nil {
+		s.logger.WarnWithFields("Draft sent but could not be removed", map[string]interface{}{
+			"session_id": draft.SessionID,
+			"chat_jid":   draft.ChatJID,
+			"error":      err.Error(),
+		})
+	}
+
+	return &SendDraftResult{
+		Draft:     draft,
+		Status:    "sent",
+		MessageID: result.MessageID,
+		Timestamp: result.Timestamp,
+	}, nil
+}
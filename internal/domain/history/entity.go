@@ -0,0 +1,154 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"zpwoot/platform/pagination"
+)
+
+// Message is a WhatsApp message sent or received by a session, kept for the chat history. Edits
+// replace the text and set EditedAt; revokes keep the row and set RevokedAt. Pin is set while the
+// message is pinned in its chat, and Ephemeral when it was sent in a chat with disappearing messages.
+type Message struct {
+	ID        uuid.UUID    `json:"id"`
+	SessionID string       `json:"session_id"`
+	ChatJID   string       `json:"chat_jid"`
+	MessageID string       `json:"message_id"`
+	SenderJID string       `json:"sender_jid"`
+	FromMe    bool         `json:"from_me"`
+	Type      string       `json:"type"`
+	Text      string       `json:"text,omitempty"`
+	Media     *Media       `json:"media,omitempty"`
+	Context   *ContextInfo `json:"context,omitempty"`
+	Timestamp time.Time    `json:"timestamp"`
+	EditedAt  *time.Time   `json:"edited_at,omitempty"`
+	RevokedAt *time.Time   `json:"revoked_at,omitempty"`
+	Pin       *Pin         `json:"pin,omitempty"`
+	Ephemeral *Ephemeral   `json:"ephemeral,omitempty"`
+	// Actor is what made the session send the message; unset for received messages and messages sent
+	// from the phone or other devices
+	Actor     *Actor    `json:"actor,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Media is the metadata of the attachment of a media message; the media itself is not stored
+type Media struct {
+	MimeType   string `json:"mime_type,omitempty"`
+	FileName   string `json:"file_name,omitempty"`
+	FileLength uint64 `json:"file_length,omitempty"`
+	Width      uint32 `json:"width,omitempty"`
+	Height     uint32 `json:"height,omitempty"`
+	Seconds    uint32 `json:"seconds,omitempty"`
+	DirectPath string `json:"direct_path,omitempty"`
+	URL        string `json:"url,omitempty"`
+}
+
+// ContextInfo is what a message says about other messages: the one it replies to, who it mentions and
+// whether it was forwarded
+type ContextInfo struct {
+	QuotedMessageID   string   `json:"quoted_message_id,omitempty"`
+	QuotedParticipant string   `json:"quoted_participant,omitempty"`
+	Mentions          []string `json:"mentions,omitempty"`
+	Forwarded         bool     `json:"forwarded,omitempty"`
+}
+
+// Pin is the pinned state of a message; WhatsApp unpins it by itself once ExpiresAt has passed
+type Pin struct {
+	PinnedAt  time.Time `json:"pinned_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	// PinnedBy is the JID of the participant who pinned the message
+	PinnedBy string `json:"pinned_by,omitempty"`
+}
+
+// IsActive reports whether the pin has not lapsed at now
+func (p *Pin) IsActive(now time.Time) bool {
+	return p != nil && now.Before(p.ExpiresAt)
+}
+
+// Ephemeral is the disappearing messages timer a message was sent with; WhatsApp removes the message
+// from the chat once ExpiresAt has passed
+type Ephemeral struct {
+	// Duration is the timer of the chat in seconds
+	Duration  uint32    `json:"duration"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// IsExpired reports whether the message has disappeared at now
+func (e *Ephemeral) IsExpired(now time.Time) bool {
+	return e != nil && !now.Before(e.ExpiresAt)
+}
+
+// Actor types of outgoing messages
+const (
+	ActorAPI           = "api"
+	ActorChatwoot      = "chatwoot"
+	ActorAutoresponder = "autoresponder"
+	ActorCampaign      = "campaign"
+	ActorScheduler     = "scheduler"
+)
+
+// IsActorType reports whether actorType is one of the actor types
+func IsActorType(actorType string) bool {
+	switch actorType {
+	case ActorAPI, ActorChatwoot, ActorAutoresponder, ActorCampaign, ActorScheduler:
+		return true
+	}
+	return false
+}
+
+// ActorContextKey is the request value holding the actor of the caller
+const ActorContextKey = "actor"
+
+// Actor identifies who triggered an outgoing message: the kind of caller and, for API calls, the key it
+// authenticated with (masked) or, for integrations, the item that caused the send
+type Actor struct {
+	Type string `json:"type"`
+	ID   string `json:"id,omitempty"`
+}
+
+// WithActor carries the actor of the sends made with ctx
+func WithActor(ctx context.Context, actor *Actor) context.Context {
+	return context.WithValue(ctx, ActorContextKey, actor)
+}
+
+// ActorFromContext returns the actor of the sends made with ctx, or nil when unknown
+func ActorFromContext(ctx context.Context) *Actor {
+	if ctx == nil {
+		return nil
+	}
+	actor, _ := ctx.Value(ActorContextKey).(*Actor)
+	return actor
+}
+
+var (
+	ErrInvalidMessage   = errors.New("message needs a session, chat and message ID")
+	ErrMessageNotFound  = errors.New("message not found")
+	ErrInvalidChatJID   = errors.New("chat JID is required")
+	ErrInvalidTimeRange = errors.New("from must be before to")
+	ErrInvalidActorType = errors.New("actor must be api, chatwoot, autoresponder, campaign or scheduler")
+)
+
+// ListRequest lists the messages of a chat, newest first; ephemeral messages that have disappeared are
+// never listed
+type ListRequest struct {
+	SessionID string
+	ChatJID   string
+	// From and To limit the messages to those sent in the range; either may be nil
+	From *time.Time
+	To   *time.Time
+	// Query keeps the messages whose text matches the words of the full-text search query
+	Query string
+	// ActorType keeps the outgoing messages sent by that kind of actor
+	ActorType string
+	// Pinned keeps the messages currently pinned in the chat
+	Pinned bool
+	Limit  int
+	Offset int
+	// After continues the list past a cursor on the message timestamp and id instead of skipping Offset rows
+	After *pagination.Cursor
+}
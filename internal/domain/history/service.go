@@ -0,0 +1,156 @@
+package history
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"zpwoot/platform/logger"
+)
+
+// defaultListLimit and maxListLimit bound the messages listed at once
+const (
+	defaultListLimit = 50
+	maxListLimit     = 200
+)
+
+// Repository defines the interface for message history data operations
+type Repository interface {
+	// Upsert stores a message keyed by session, chat and message ID; storing it again refreshes its
+	// content but keeps its edit, revoke and pin state
+	Upsert(ctx context.Context, message *Message) error
+	MarkEdited(ctx context.Context, sessionID, chatJID, messageID, text string, editedAt time.Time) error
+	MarkRevoked(ctx context.Context, sessionID, chatJID, messageID string, revokedAt time.Time) error
+	// SetPin stores the pinned state of a message; a nil pin unpins it
+	SetPin(ctx context.Context, sessionID, chatJID, messageID string, pin *Pin) error
+	// SetActor attributes a stored message to the actor that sent it
+	SetActor(ctx context.Context, sessionID, messageID string, actor *Actor) error
+	// GetByMessageID gets a stored message of the session by its WhatsApp ID, whichever chat it is in
+	GetByMessageID(ctx context.Context, sessionID, messageID string) (*Message, error)
+	// GetOldestInChat gets the earliest stored message of a chat
+	GetOldestInChat(ctx context.Context, sessionID, chatJID string) (*Message, error)
+	// ListReceivedUntil gets the most recent messages received in a chat up to until, newest first
+	ListReceivedUntil(ctx context.Context, sessionID, chatJID string, until time.Time, limit int) ([]*Message, error)
+	ListByChat(ctx context.Context, req *ListRequest) ([]*Message, int, error)
+}
+
+type Service struct {
+	logger      *logger.Logger
+	historyRepo Repository
+}
+
+func NewService(logger *logger.Logger, historyRepo Repository) *Service {
+	return &Service{
+		logger:      logger,
+		historyRepo: historyRepo,
+	}
+}
+
+// RecordMessage stores a message sent or received by a session
+func (s *Service) RecordMessage(ctx context.Context, m *Message) error {
+	if m.SessionID == "" || m.ChatJID == "" || m.MessageID == "" {
+		return ErrInvalidMessage
+	}
+
+	now := time.Now()
+	if m.ID == uuid.Nil {
+		m.ID = uuid.New()
+	}
+	if m.Timestamp.IsZero() {
+		m.Timestamp = now
+	}
+	m.CreatedAt = now
+	m.UpdatedAt = now
+
+	return s.historyRepo.Upsert(ctx, m)
+}
+
+// RecordEdit replaces the text of a stored message; edits of messages that were never stored are ignored
+func (s *Service) RecordEdit(ctx context.Context, sessionID, chatJID, messageID, text string, editedAt time.Time) error {
+	if sessionID == "" || chatJID == "" || messageID == "" {
+		return ErrInvalidMessage
+	}
+	return s.historyRepo.MarkEdited(ctx, sessionID, chatJID, messageID, text, editedAt)
+}
+
+// RecordRevoke marks a stored message as revoked for everyone
+func (s *Service) RecordRevoke(ctx context.Context, sessionID, chatJID, messageID string, revokedAt time.Time) error {
+	if sessionID == "" || chatJID == "" || messageID == "" {
+		return ErrInvalidMessage
+	}
+	return s.historyRepo.MarkRevoked(ctx, sessionID, chatJID, messageID, revokedAt)
+}
+
+// RecordPin stores that a message was pinned, or unpinned when pin is nil; pins of messages that were
+// never stored are ignored
+func (s *Service) RecordPin(ctx context.Context, sessionID, chatJID, messageID string, pin *Pin) error {
+	if sessionID == "" || chatJID == "" || messageID == "" {
+		return ErrInvalidMessage
+	}
+	return s.historyRepo.SetPin(ctx, sessionID, chatJID, messageID, pin)
+}
+
+// RecordActor attributes a message the session sent to the actor that triggered it
+func (s *Service) RecordActor(ctx context.Context, sessionID, messageID string, actor *Actor) error {
+	if sessionID == "" || messageID == "" || actor == nil {
+		return ErrInvalidMessage
+	}
+	if !IsActorType(actor.Type) {
+		return ErrInvalidActorType
+	}
+	return s.historyRepo.SetActor(ctx, sessionID, messageID, actor)
+}
+
+// GetMessage returns a stored message of the session by its WhatsApp ID, or ErrMessageNotFound
+func (s *Service) GetMessage(ctx context.Context, sessionID, messageID string) (*Message, error) {
+	if sessionID == "" || messageID == "" {
+		return nil, ErrInvalidMessage
+	}
+	return s.historyRepo.GetByMessageID(ctx, sessionID, messageID)
+}
+
+// OldestChatMessage returns the earliest stored message of a chat, or ErrMessageNotFound when none is stored
+func (s *Service) OldestChatMessage(ctx context.Context, sessionID, chatJID string) (*Message, error) {
+	if sessionID == "" || chatJID == "" {
+		return nil, ErrInvalidChatJID
+	}
+	return s.historyRepo.GetOldestInChat(ctx, sessionID, chatJID)
+}
+
+// ReceivedChatMessages returns up to limit messages received in a chat up to until, newest first
+func (s *Service) ReceivedChatMessages(ctx context.Context, sessionID, chatJID string, until time.Time, limit int) ([]*Message, error) {
+	if sessionID == "" || chatJID == "" {
+		return nil, ErrInvalidChatJID
+	}
+	return s.historyRepo.ListReceivedUntil(ctx, sessionID, chatJID, until, limit)
+}
+
+// ListChatMessages lists the stored messages of a chat, newest first, with the total matching the filters
+func (s *Service) ListChatMessages(ctx context.Context, req *ListRequest) ([]*Message, int, error) {
+	req.ChatJID = strings.TrimSpace(req.ChatJID)
+	if req.ChatJID == "" {
+		return nil, 0, ErrInvalidChatJID
+	}
+	if req.From != nil && req.To != nil && req.From.After(*req.To) {
+		return nil, 0, ErrInvalidTimeRange
+	}
+	req.Query = strings.TrimSpace(req.Query)
+	req.ActorType = strings.TrimSpace(req.ActorType)
+	if req.ActorType != "" && !IsActorType(req.ActorType) {
+		return nil, 0, ErrInvalidActorType
+	}
+
+	if req.Limit <= 0 {
+		req.Limit = defaultListLimit
+	}
+	if req.Limit > maxListLimit {
+		req.Limit = maxListLimit
+	}
+	if req.Offset < 0 {
+		req.Offset = 0
+	}
+
+	return s.historyRepo.ListByChat(ctx, req)
+}
@@ -0,0 +1,67 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// States of a background job
+const (
+	// StateIdle: the job waits for its next run or a manual trigger
+	StateIdle = "idle"
+	// StateRunning: a run is in progress
+	StateRunning = "running"
+	// StateStandby: the job is exclusive and another replica holds its lock
+	StateStandby = "standby"
+	// StateStopped: the scheduler is not running the job, before startup or after shutdown
+	StateStopped = "stopped"
+)
+
+// Ways a run was started
+const (
+	TriggerSchedule = "schedule"
+	TriggerManual   = "manual"
+)
+
+var (
+	ErrJobNotFound      = errors.New("job not found")
+	ErrDuplicateJob     = errors.New("a job with this name is already registered")
+	ErrInvalidJob       = errors.New("job name and run function are required")
+	ErrJobNotLeader     = errors.New("job runs on another replica")
+	ErrJobAlreadyQueued = errors.New("job already has a run queued")
+	ErrJobNotStarted    = errors.New("job scheduler is not running")
+)
+
+// Definition describes a background job. A job with an Interval runs every Interval; without one it
+// only runs at startup (RunOnStart) and when triggered.
+type Definition struct {
+	Name        string
+	Description string
+	Interval    time.Duration
+	// RunOnStart runs the job StartDelay after the scheduler starts instead of one Interval later
+	RunOnStart bool
+	StartDelay time.Duration
+	// Exclusive jobs run on a single replica, the one holding the job's lock
+	Exclusive bool
+	Run       func(ctx context.Context) error
+}
+
+// Status is what the scheduler knows about a job and its runs in this process
+type Status struct {
+	Name          string        `json:"name"`
+	Description   string        `json:"description,omitempty"`
+	State         string        `json:"state"`
+	Interval      time.Duration `json:"interval,omitempty"`
+	Exclusive     bool          `json:"exclusive"`
+	Runs          int64         `json:"runs"`
+	Failures      int64         `json:"failures"`
+	LastTrigger   string        `json:"last_trigger,omitempty"`
+	LastRunAt     *time.Time    `json:"last_run_at,omitempty"`
+	LastDuration  time.Duration `json:"last_duration,omitempty"`
+	LastError     string        `json:"last_error,omitempty"`
+	LastSuccessAt *time.Time    `json:"last_success_at,omitempty"`
+	NextRunAt     *time.Time    `json:"next_run_at,omitempty"`
+	// RunQueued is set while a manual trigger waits for the run in progress to finish
+	RunQueued bool `json:"run_queued"`
+}
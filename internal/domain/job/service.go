@@ -0,0 +1,325 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"zpwoot/platform/logger"
+)
+
+// Locker runs fn while this replica holds the lock of name, cancelling fn's context when the lock is lost
+type Locker interface {
+	Run(ctx context.Context, name string, fn func(ctx context.Context))
+}
+
+// Service schedules the named background jobs of the process and keeps their run history for the
+// admin API, replacing a goroutine and ticker per subsystem
+type Service struct {
+	logger *logger.Logger
+	locker Locker
+
+	mu     sync.RWMutex
+	jobs   map[string]*job
+	order  []string
+	ctx    context.Context
+	cancel context.CancelFunc
+	// running tracks the scheduled jobs so Stop can wait for runs in progress
+	running sync.WaitGroup
+}
+
+// job is a registered definition with its run state; fields besides def are guarded by Service.mu
+type job struct {
+	def     Definition
+	trigger chan struct{}
+	status  Status
+	leading bool
+}
+
+// NewService creates the job scheduler; exclusive jobs run everywhere when locker is nil
+func NewService(logger *logger.Logger, locker Locker) *Service {
+	return &Service{
+		logger: logger,
+		locker: locker,
+		jobs:   make(map[string]*job),
+	}
+}
+
+// Register adds a job; jobs registered after Start are scheduled right away
+func (s *Service) Register(def Definition) error {
+	if def.Name == "" || def.Run == nil {
+		return ErrInvalidJob
+	}
+
+	s.mu.Lock()
+	if _, exists := s.jobs[def.Name]; exists {
+		s.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrDuplicateJob, def.Name)
+	}
+
+	j := &job{
+		def:     def,
+		trigger: make(chan struct{}, 1),
+		status: Status{
+			Name:        def.Name,
+			Description: def.Description,
+			State:       StateStopped,
+			Interval:    def.Interval,
+			Exclusive:   def.Exclusive,
+		},
+	}
+	s.jobs[def.Name] = j
+	s.order = append(s.order, def.Name)
+	if s.ctx != nil && s.ctx.Err() == nil {
+		s.running.Add(1)
+		go s.schedule(s.ctx, j)
+	}
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Start schedules every registered job until ctx is done
+func (s *Service) Start(ctx context.Context) {
+	s.mu.Lock()
+	if s.ctx != nil {
+		s.mu.Unlock()
+		return
+	}
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.ctx = ctx
+	jobs := make([]*job, 0, len(s.order))
+	for _, name := range s.order {
+		jobs = append(jobs, s.jobs[name])
+	}
+	s.running.Add(len(jobs))
+	s.mu.Unlock()
+
+	for _, j := range jobs {
+		go s.schedule(ctx, j)
+	}
+
+	s.logger.InfoWithFields("Background jobs started", map[string]interface{}{
+		"jobs": len(jobs),
+	})
+}
+
+// Stop cancels every job and waits for the runs in progress to return until ctx is done. Exclusive
+// jobs release their locks on the way out, so another replica takes them over.
+func (s *Service) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	cancel := s.cancel
+	s.mu.Unlock()
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		s.logger.Info("Background jobs stopped")
+		return nil
+	case <-ctx.Done():
+		s.logger.Warn("Background jobs did not stop in time")
+		return ctx.Err()
+	}
+}
+
+// List returns the status of every job in registration order
+func (s *Service) List() []*Status {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	statuses := make([]*Status, 0, len(s.order))
+	for _, name := range s.order {
+		status := s.jobs[name].status
+		statuses = append(statuses, &status)
+	}
+	return statuses
+}
+
+func (s *Service) Get(name string) (*Status, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	j, ok := s.jobs[name]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	status := j.status
+	return &status, nil
+}
+
+// Trigger queues a run of the job outside its schedule; a run in progress finishes first
+func (s *Service) Trigger(name string) (*Status, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, ok := s.jobs[name]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	if s.ctx == nil || s.ctx.Err() != nil {
+		return nil, ErrJobNotStarted
+	}
+	if s.exclusive(j) && !j.leading {
+		return nil, ErrJobNotLeader
+	}
+
+	select {
+	case j.trigger <- struct{}{}:
+	default:
+		return nil, ErrJobAlreadyQueued
+	}
+
+	j.status.RunQueued = true
+	status := j.status
+	return &status, nil
+}
+
+// exclusive reports whether the job waits for its lock before running
+func (s *Service) exclusive(j *job) bool {
+	return j.def.Exclusive && s.locker != nil
+}
+
+// schedule runs a job until ctx is done, holding its lock first when it is exclusive
+func (s *Service) schedule(ctx context.Context, j *job) {
+	defer s.running.Done()
+
+	if !s.exclusive(j) {
+		s.setState(j, StateIdle, false)
+		s.loop(ctx, j)
+		s.setState(j, StateStopped, false)
+		return
+	}
+
+	s.setState(j, StateStandby, false)
+	s.locker.Run(ctx, j.def.Name, func(ctx context.Context) {
+		s.setState(j, StateIdle, true)
+		s.loop(ctx, j)
+		s.setState(j, StateStandby, false)
+	})
+	s.setState(j, StateStopped, false)
+}
+
+// loop runs the job on its schedule and on manual triggers until ctx is done
+func (s *Service) loop(ctx context.Context, j *job) {
+	var timer *time.Timer
+	var timerC <-chan time.Time
+	arm := func(delay time.Duration) {
+		if timer != nil {
+			timer.Stop()
+		}
+		next := time.Now().Add(delay)
+		timer = time.NewTimer(delay)
+		timerC = timer.C
+		s.setNextRun(j, &next)
+	}
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+		s.setNextRun(j, nil)
+	}()
+
+	switch {
+	case j.def.RunOnStart:
+		arm(j.def.StartDelay)
+	case j.def.Interval > 0:
+		arm(j.def.Interval)
+	}
+
+	for {
+		trigger := TriggerSchedule
+		select {
+		case <-ctx.Done():
+			return
+		case <-timerC:
+			timerC = nil
+		case <-j.trigger:
+			trigger = TriggerManual
+		}
+
+		s.setNextRun(j, nil)
+		s.execute(ctx, j, trigger)
+
+		if j.def.Interval > 0 {
+			arm(j.def.Interval)
+		}
+	}
+}
+
+// execute runs the job once and records the outcome
+func (s *Service) execute(ctx context.Context, j *job, trigger string) {
+	startedAt := time.Now()
+
+	s.mu.Lock()
+	j.status.State = StateRunning
+	j.status.RunQueued = len(j.trigger) > 0
+	j.status.LastTrigger = trigger
+	j.status.LastRunAt = &startedAt
+	s.mu.Unlock()
+
+	err := s.run(ctx, j)
+	duration := time.Since(startedAt)
+
+	s.mu.Lock()
+	j.status.State = StateIdle
+	j.status.Runs++
+	j.status.LastDuration = duration
+	if err != nil {
+		j.status.Failures++
+		j.status.LastError = err.Error()
+	} else {
+		finishedAt := time.Now()
+		j.status.LastError = ""
+		j.status.LastSuccessAt = &finishedAt
+	}
+	s.mu.Unlock()
+
+	if err != nil {
+		s.logger.WarnWithFields("Background job failed", map[string]interface{}{
+			"job":      j.def.Name,
+			"trigger":  trigger,
+			"duration": duration.String(),
+			"error":    err.Error(),
+		})
+		return
+	}
+
+	s.logger.DebugWithFields("Background job finished", map[string]interface{}{
+		"job":      j.def.Name,
+		"trigger":  trigger,
+		"duration": duration.String(),
+	})
+}
+
+// run calls the job, turning a panic into a failed run so the job keeps its schedule
+func (s *Service) run(ctx context.Context, j *job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return j.def.Run(ctx)
+}
+
+func (s *Service) setState(j *job, state string, leading bool) {
+	s.mu.Lock()
+	j.status.State = state
+	j.leading = leading
+	s.mu.Unlock()
+}
+
+func (s *Service) setNextRun(j *job, next *time.Time) {
+	s.mu.Lock()
+	j.status.NextRunAt = next
+	s.mu.Unlock()
+}
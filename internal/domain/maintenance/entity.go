@@ -0,0 +1,41 @@
+package maintenance
+
+import (
+	"errors"
+	"time"
+)
+
+// DefaultRetryAfter is the Retry-After sent with rejected sends when maintenance sets none
+const DefaultRetryAfter = 60 * time.Second
+
+var ErrSessionNotFrozen = errors.New("session is not in maintenance")
+
+// ErrSendFrozen is returned by sends refused while the instance or the session is in maintenance
+var ErrSendFrozen = errors.New("sending is paused for maintenance")
+
+// Status is the maintenance state of the instance and of individually frozen sessions
+type Status struct {
+	Enabled    bool             `json:"enabled"`
+	Reason     string           `json:"reason,omitempty"`
+	Since      *time.Time       `json:"since,omitempty"`
+	RetryAfter time.Duration    `json:"retry_after"`
+	Sessions   []*SessionFreeze `json:"sessions"`
+}
+
+// SessionFreeze puts a single session into maintenance
+type SessionFreeze struct {
+	SessionID   string    `json:"session_id"`
+	SessionName string    `json:"session_name"`
+	Reason      string    `json:"reason,omitempty"`
+	Since       time.Time `json:"since"`
+}
+
+type EnableRequest struct {
+	Reason     string        `json:"reason,omitempty"`
+	RetryAfter time.Duration `json:"retry_after,omitempty"`
+}
+
+// Matches reports whether the identifier is the ID or the name of the frozen session
+func (f *SessionFreeze) Matches(identifier string) bool {
+	return identifier == f.SessionID || identifier == f.SessionName
+}
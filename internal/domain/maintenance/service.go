@@ -0,0 +1,276 @@
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"zpwoot/platform/logger"
+)
+
+// Repository defines the interface for maintenance state data operations
+type Repository interface {
+	// Get returns the persisted state; Enabled is false when the instance is not in maintenance
+	Get(ctx context.Context) (*Status, error)
+	// SaveInstance puts the instance into maintenance, keeping its start when it already was
+	SaveInstance(ctx context.Context, reason string, retryAfter time.Duration) error
+	DeleteInstance(ctx context.Context) error
+	// SaveSession freezes the session, keeping its start when it already was frozen
+	SaveSession(ctx context.Context, freeze *SessionFreeze) error
+	// DeleteSession unfreezes the session and reports whether it was frozen
+	DeleteSession(ctx context.Context, sessionID string) (bool, error)
+}
+
+// Service holds the maintenance state. While the instance or a session is in maintenance
+// reads keep working, sends are refused and webhook dispatch is paused.
+// The state is persisted, and every replica polls for changes so maintenance applies to all of them.
+type Service struct {
+	logger *logger.Logger
+	repo   Repository
+
+	mu         sync.RWMutex
+	enabled    bool
+	reason     string
+	since      time.Time
+	retryAfter time.Duration
+	sessions   map[string]*SessionFreeze
+	changed    chan struct{}
+}
+
+func NewService(logger *logger.Logger, repo Repository) *Service {
+	return &Service{
+		logger:     logger,
+		repo:       repo,
+		retryAfter: DefaultRetryAfter,
+		sessions:   make(map[string]*SessionFreeze),
+		changed:    make(chan struct{}),
+	}
+}
+
+// Load reads the persisted maintenance state and applies it
+func (s *Service) Load(ctx context.Context) error {
+	status, err := s.repo.Get(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load maintenance state: %w", err)
+	}
+
+	s.apply(status)
+	return nil
+}
+
+// Sync picks up maintenance changed by other replicas
+func (s *Service) Sync(ctx context.Context) error {
+	return s.Load(ctx)
+}
+
+// Enable puts the whole instance into maintenance
+func (s *Service) Enable(ctx context.Context, req *EnableRequest) (*Status, error) {
+	retryAfter := DefaultRetryAfter
+	if req.RetryAfter > 0 {
+		retryAfter = req.RetryAfter
+	}
+
+	if err := s.repo.SaveInstance(ctx, req.Reason, retryAfter); err != nil {
+		return nil, fmt.Errorf("failed to enable maintenance: %w", err)
+	}
+	if err := s.Load(ctx); err != nil {
+		return nil, err
+	}
+
+	s.logger.WarnWithFields("Maintenance mode enabled", map[string]interface{}{
+		"reason":      req.Reason,
+		"retry_after": retryAfter.String(),
+	})
+
+	return s.Status(), nil
+}
+
+// Disable takes the instance out of maintenance; frozen sessions stay frozen
+func (s *Service) Disable(ctx context.Context) (*Status, error) {
+	wasEnabled := s.SendFrozen("")
+
+	if err := s.repo.DeleteInstance(ctx); err != nil {
+		return nil, fmt.Errorf("failed to disable maintenance: %w", err)
+	}
+	if err := s.Load(ctx); err != nil {
+		return nil, err
+	}
+
+	if wasEnabled {
+		s.logger.Info("Maintenance mode disabled")
+	}
+
+	return s.Status(), nil
+}
+
+// FreezeSession puts a single session into maintenance
+func (s *Service) FreezeSession(ctx context.Context, sessionID, sessionName, reason string) (*SessionFreeze, error) {
+	err := s.repo.SaveSession(ctx, &SessionFreeze{
+		SessionID:   sessionID,
+		SessionName: sessionName,
+		Reason:      reason,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to freeze session: %w", err)
+	}
+	if err := s.Load(ctx); err != nil {
+		return nil, err
+	}
+
+	s.logger.WarnWithFields("Session put into maintenance", map[string]interface{}{
+		"session_id": sessionID,
+		"reason":     reason,
+	})
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	freeze, exists := s.sessions[sessionID]
+	if !exists {
+		// Unfrozen by another replica in the meantime
+		return nil, ErrSessionNotFrozen
+	}
+	result := *freeze
+	return &result, nil
+}
+
+func (s *Service) UnfreezeSession(ctx context.Context, sessionID string) error {
+	deleted, err := s.repo.DeleteSession(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to unfreeze session: %w", err)
+	}
+	if err := s.Load(ctx); err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrSessionNotFrozen
+	}
+
+	s.logger.InfoWithFields("Session left maintenance", map[string]interface{}{
+		"session_id": sessionID,
+	})
+
+	return nil
+}
+
+func (s *Service) Status() *Status {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	status := &Status{
+		Enabled:    s.enabled,
+		Reason:     s.reason,
+		RetryAfter: s.retryAfter,
+		Sessions:   make([]*SessionFreeze, 0, len(s.sessions)),
+	}
+	if s.enabled {
+		since := s.since
+		status.Since = &since
+	}
+	for _, freeze := range s.sessions {
+		f := *freeze
+		status.Sessions = append(status.Sessions, &f)
+	}
+	sort.Slice(status.Sessions, func(i, j int) bool {
+		return status.Sessions[i].Since.Before(status.Sessions[j].Since)
+	})
+
+	return status
+}
+
+// SendFrozen reports whether sends are refused for the session, given by ID or name.
+// An empty identifier only checks the instance.
+func (s *Service) SendFrozen(sessionIdentifier string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.enabled {
+		return true
+	}
+	if sessionIdentifier == "" {
+		return false
+	}
+	if _, exists := s.sessions[sessionIdentifier]; exists {
+		return true
+	}
+	for _, freeze := range s.sessions {
+		if freeze.Matches(sessionIdentifier) {
+			return true
+		}
+	}
+	return false
+}
+
+// DispatchPaused reports whether webhook dispatch is paused for the session
+func (s *Service) DispatchPaused(sessionID string) bool {
+	return s.SendFrozen(sessionID)
+}
+
+func (s *Service) RetryAfter() time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.retryAfter
+}
+
+// Changed returns a channel that is closed on the next maintenance state change
+func (s *Service) Changed() <-chan struct{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.changed
+}
+
+// apply replaces the state held in memory, waking up the waiters of Changed when it differs
+func (s *Service) apply(status *Status) {
+	sessions := make(map[string]*SessionFreeze, len(status.Sessions))
+	for _, freeze := range status.Sessions {
+		sessions[freeze.SessionID] = freeze
+	}
+	retryAfter := status.RetryAfter
+	if retryAfter <= 0 {
+		retryAfter = DefaultRetryAfter
+	}
+	var since time.Time
+	if status.Since != nil {
+		since = *status.Since
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if status.Enabled == s.enabled && status.Reason == s.reason && since.Equal(s.since) &&
+		retryAfter == s.retryAfter && sameFreezes(sessions, s.sessions) {
+		return
+	}
+
+	s.enabled = status.Enabled
+	s.reason = status.Reason
+	s.since = since
+	s.retryAfter = retryAfter
+	s.sessions = sessions
+	s.notifyLocked()
+
+	s.logger.InfoWithFields("Maintenance state applied", map[string]interface{}{
+		"enabled":         status.Enabled,
+		"reason":          status.Reason,
+		"frozen_sessions": len(sessions),
+	})
+}
+
+func sameFreezes(a, b map[string]*SessionFreeze) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for sessionID, freeze := range a {
+		other, exists := b[sessionID]
+		if !exists || other.SessionName != freeze.SessionName || other.Reason != freeze.Reason || !other.Since.Equal(freeze.Since) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Service) notifyLocked() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
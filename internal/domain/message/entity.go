@@ -52,6 +52,10 @@ type SendMessageRequest struct {
 	ContactName  string       `json:"contactName,omitempty" example:"John Doe"`
 	ContactPhone string       `json:"contactPhone,omitempty" example:"+5511999999999"`
 	ContextInfo  *ContextInfo `json:"contextInfo,omitempty"`
+
+	// Upload is media uploaded with the request and already saved to disk; it is sent instead of File and
+	// cleaned up by whoever saved it
+	Upload *ProcessedMedia `json:"-"`
 }
 
 type ContextInfo struct {
@@ -107,6 +111,13 @@ func (req *SendMessageRequest) GetMediaSource() MediaSource {
 	return MediaSourceFile
 }
 
+// Media errors
+var (
+	ErrInvalidMedia        = errors.New("invalid media")
+	ErrMediaTooLarge       = errors.New("media exceeds the maximum allowed size")
+	ErrMediaDownloadFailed = errors.New("failed to download media")
+)
+
 // Poll domain errors
 var (
 	ErrInvalidPollName        = errors.New("invalid poll name")
@@ -125,6 +136,35 @@ var (
 	ErrInvalidRecipient       = errors.New("invalid recipient")
 )
 
+// Group mention errors
+var (
+	ErrMentionAllNotGroup      = errors.New("mentionAll is only supported on group messages")
+	ErrMentionAllRequiresAdmin = errors.New("mentioning everyone requires the account to be a group admin")
+)
+
+// Pin durations WhatsApp offers when pinning a message; DefaultPinDuration matches the official apps
+var PinDurations = map[string]time.Duration{
+	"24h": 24 * time.Hour,
+	"7d":  7 * 24 * time.Hour,
+	"30d": 30 * 24 * time.Hour,
+}
+
+const DefaultPinDuration = "7d"
+
+var ErrInvalidPinDuration = errors.New("duration must be 24h, 7d or 30d")
+
+// ParsePinDuration returns the pin duration named by name, or the default one when name is empty
+func ParsePinDuration(name string) (time.Duration, error) {
+	if name == "" {
+		name = DefaultPinDuration
+	}
+	duration, ok := PinDurations[name]
+	if !ok {
+		return 0, ErrInvalidPinDuration
+	}
+	return duration, nil
+}
+
 // CreatePollRequest represents a request to create a poll
 type CreatePollRequest struct {
 	To                    string
@@ -5,15 +5,35 @@ import (
 	"encoding/base64"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"time"
 
 	"zpwoot/platform/logger"
+	"zpwoot/platform/phone"
 )
 
+const (
+	// DefaultMediaMaxSize and DefaultMediaDownloadTimeout apply when MediaLimits leaves them unset
+	DefaultMediaMaxSize         = 100 * 1024 * 1024
+	DefaultMediaDownloadTimeout = 60 * time.Second
+
+	genericMimeType = "application/octet-stream"
+	// sniffLength is how many bytes http.DetectContentType looks at
+	sniffLength = 512
+)
+
+// MediaLimits bound the media accepted from URLs and base64 data URIs
+type MediaLimits struct {
+	MaxSize         int64         // Maximum decoded or downloaded size in bytes
+	DownloadTimeout time.Duration // Maximum time to download a media URL
+}
+
 type MediaProcessor struct {
 	logger  *logger.Logger
 	tempDir string
@@ -21,13 +41,20 @@ type MediaProcessor struct {
 	timeout time.Duration
 }
 
-func NewMediaProcessor(logger *logger.Logger) *MediaProcessor {
-	return &MediaProcessor{
+func NewMediaProcessor(logger *logger.Logger, limits MediaLimits) *MediaProcessor {
+	mp := &MediaProcessor{
 		logger:  logger,
 		tempDir: os.TempDir(),
-		maxSize: 100 * 1024 * 1024, // 100MB default
-		timeout: 60 * time.Second,  // Increased timeout to 60 seconds
+		maxSize: limits.MaxSize,
+		timeout: limits.DownloadTimeout,
+	}
+	if mp.maxSize <= 0 {
+		mp.maxSize = DefaultMediaMaxSize
 	}
+	if mp.timeout <= 0 {
+		mp.timeout = DefaultMediaDownloadTimeout
+	}
+	return mp
 }
 
 // ProcessMediaForType processes media with type-specific validations
@@ -48,16 +75,76 @@ func (mp *MediaProcessor) ProcessMediaForType(ctx context.Context, file string,
 	return media, nil
 }
 
+// SaveUpload streams media uploaded with a request to a temporary file, up to the maximum media size,
+// and detects its mime type. The caller must call Cleanup once the media is sent.
+func (mp *MediaProcessor) SaveUpload(r io.Reader, declaredType, filename string) (*ProcessedMedia, error) {
+	tempFile, err := os.CreateTemp(mp.tempDir, "whatsmeow-upload-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+
+	written, err := io.CopyN(tempFile, r, mp.maxSize+1)
+	if err != nil && err != io.EOF {
+		mp.cleanupTempFile(tempFile)
+		return nil, fmt.Errorf("%w: failed to read upload: %v", ErrInvalidMedia, err)
+	}
+
+	if written > mp.maxSize {
+		mp.cleanupTempFile(tempFile)
+		return nil, mp.tooLarge()
+	}
+
+	if written == 0 {
+		mp.cleanupTempFile(tempFile)
+		return nil, fmt.Errorf("%w: uploaded file is empty", ErrInvalidMedia)
+	}
+
+	if err := tempFile.Close(); err != nil {
+		_ = os.Remove(tempFile.Name())
+		return nil, fmt.Errorf("failed to close temporary file: %w", err)
+	}
+
+	mimeType, err := sniffFile(tempFile.Name(), declaredType, filename)
+	if err != nil {
+		_ = os.Remove(tempFile.Name())
+		return nil, fmt.Errorf("failed to read temporary file: %w", err)
+	}
+
+	mp.logger.DebugWithFields("Media upload saved", map[string]interface{}{
+		"file_path": tempFile.Name(),
+		"mime_type": mimeType,
+		"file_size": written,
+	})
+
+	return &ProcessedMedia{
+		FilePath: tempFile.Name(),
+		MimeType: mimeType,
+		FileSize: written,
+		Cleanup: func() error {
+			return os.Remove(tempFile.Name())
+		},
+	}, nil
+}
+
+// ValidateUpload applies the type-specific validations to media saved with SaveUpload
+func (mp *MediaProcessor) ValidateUpload(media *ProcessedMedia, messageType MessageType) error {
+	return mp.validateMediaForType(media, messageType)
+}
+
 // validateMediaForType validates media based on message type
 func (mp *MediaProcessor) validateMediaForType(media *ProcessedMedia, messageType MessageType) error {
+	if !mediaMatchesType(media.MimeType, messageType) {
+		return fmt.Errorf("%w: %s messages cannot send %s content", ErrInvalidMedia, messageType, media.MimeType)
+	}
+
 	switch messageType {
 	case MessageTypeSticker:
 		// Stickers must be WebP and <= 100KB
 		if !strings.Contains(media.MimeType, "webp") {
-			return fmt.Errorf("stickers must be WebP format, got: %s", media.MimeType)
+			return fmt.Errorf("%w: stickers must be WebP format, got: %s", ErrInvalidMedia, media.MimeType)
 		}
 		if media.FileSize > 100*1024 { // 100KB
-			return fmt.Errorf("sticker size exceeds 100KB limit: %d bytes", media.FileSize)
+			return fmt.Errorf("%w: sticker size exceeds 100KB limit: %d bytes", ErrMediaTooLarge, media.FileSize)
 		}
 		mp.logger.InfoWithFields("Sticker validation passed", map[string]interface{}{
 			"mime_type": media.MimeType,
@@ -81,6 +168,24 @@ func (mp *MediaProcessor) validateMediaForType(media *ProcessedMedia, messageTyp
 	return nil
 }
 
+// mediaMatchesType reports whether content of mimeType can be sent as messageType. Content whose type
+// is unknown is let through, and documents accept anything.
+func mediaMatchesType(mimeType string, messageType MessageType) bool {
+	if mimeType == genericMimeType {
+		return true
+	}
+
+	switch messageType {
+	case MessageTypeImage, MessageTypeSticker:
+		return strings.HasPrefix(mimeType, "image/")
+	case MessageTypeVideo:
+		return strings.HasPrefix(mimeType, "video/")
+	case MessageTypeAudio:
+		return strings.HasPrefix(mimeType, "audio/") || mimeType == "application/ogg"
+	}
+	return true
+}
+
 type ProcessedMedia struct {
 	FilePath string
 	MimeType string
@@ -90,7 +195,7 @@ type ProcessedMedia struct {
 
 func (mp *MediaProcessor) ProcessMedia(ctx context.Context, file string) (*ProcessedMedia, error) {
 	if file == "" {
-		return nil, fmt.Errorf("file content is empty")
+		return nil, fmt.Errorf("%w: file content is empty", ErrInvalidMedia)
 	}
 
 	if strings.HasPrefix(file, "data:") {
@@ -101,32 +206,37 @@ func (mp *MediaProcessor) ProcessMedia(ctx context.Context, file string) (*Proce
 		return mp.processURL(ctx, file)
 	}
 
-	return nil, fmt.Errorf("unsupported file format: must be URL or base64")
+	return nil, fmt.Errorf("%w: file must be an http(s) URL or a base64 data URI", ErrInvalidMedia)
 }
 
 func (mp *MediaProcessor) processBase64(data string) (*ProcessedMedia, error) {
 	mp.logger.Debug("Processing base64 media")
 
-	parts := strings.SplitN(data, ",", 2)
-	if len(parts) != 2 {
-		return nil, fmt.Errorf("invalid base64 data format")
+	header, payload, found := strings.Cut(data, ",")
+	if !found || !strings.HasSuffix(header, ";base64") {
+		return nil, fmt.Errorf("%w: data URI must be formatted as data:<mime type>;base64,<data>", ErrInvalidMedia)
 	}
 
-	mimeType := "application/octet-stream"
-	if strings.Contains(parts[0], ":") && strings.Contains(parts[0], ";") {
-		mimePart := strings.Split(parts[0], ":")[1]
-		mimeType = strings.Split(mimePart, ";")[0]
+	// Reject oversized payloads before decoding them
+	payload = strings.Join(strings.Fields(payload), "")
+	if int64(base64.StdEncoding.DecodedLen(len(payload))) > mp.maxSize+2 {
+		return nil, mp.tooLarge()
 	}
 
-	decoded, err := base64.StdEncoding.DecodeString(parts[1])
+	decoded, err := base64.StdEncoding.DecodeString(payload)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode base64: %w", err)
+		if decoded, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(payload, "=")); err != nil {
+			return nil, fmt.Errorf("%w: failed to decode base64: %v", ErrInvalidMedia, err)
+		}
 	}
 
 	if int64(len(decoded)) > mp.maxSize {
-		return nil, fmt.Errorf("file size exceeds maximum allowed size of %d bytes", mp.maxSize)
+		return nil, mp.tooLarge()
 	}
 
+	declared := strings.TrimSuffix(strings.TrimPrefix(header, "data:"), ";base64")
+	mimeType := resolveMimeType(declared, decoded, "")
+
 	tempFile, err := os.CreateTemp(mp.tempDir, "whatsmeow-media-*")
 	if err != nil {
 		return nil, fmt.Errorf("failed to create temporary file: %w", err)
@@ -176,7 +286,16 @@ func (mp *MediaProcessor) processURL(ctx context.Context, url string) (*Processe
 	}
 
 	// Save to temporary file
-	return mp.saveToTempFile(resp, url, mimeType)
+	media, err := mp.saveToTempFile(resp, url, mimeType)
+	if err != nil {
+		return nil, err
+	}
+	if media.MimeType, err = sniffFile(media.FilePath, mimeType, urlFilename(url)); err != nil {
+		_ = media.Cleanup()
+		return nil, fmt.Errorf("failed to read downloaded file: %w", err)
+	}
+
+	return media, nil
 }
 
 // logURLProcessing logs URL processing start
@@ -199,7 +318,7 @@ func (mp *MediaProcessor) downloadFromURL(ctx context.Context, url string) (*htt
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to download file from URL: %w", err)
+		return nil, fmt.Errorf("%w: %v", ErrMediaDownloadFailed, err)
 	}
 
 	return resp, nil
@@ -217,19 +336,14 @@ func (mp *MediaProcessor) closeResponse(resp *http.Response) {
 // validateResponse validates HTTP response and returns mime type
 func (mp *MediaProcessor) validateResponse(resp *http.Response) (string, error) {
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("failed to download file: HTTP %d", resp.StatusCode)
+		return "", fmt.Errorf("%w: HTTP %d", ErrMediaDownloadFailed, resp.StatusCode)
 	}
 
 	if resp.ContentLength > mp.maxSize {
-		return "", fmt.Errorf("file size exceeds maximum allowed size of %d bytes", mp.maxSize)
+		return "", mp.tooLarge()
 	}
 
-	mimeType := resp.Header.Get("Content-Type")
-	if mimeType == "" {
-		mimeType = "application/octet-stream"
-	}
-
-	return mimeType, nil
+	return resp.Header.Get("Content-Type"), nil
 }
 
 // saveToTempFile saves response content to temporary file
@@ -242,12 +356,12 @@ func (mp *MediaProcessor) saveToTempFile(resp *http.Response, url, mimeType stri
 	written, err := io.CopyN(tempFile, resp.Body, mp.maxSize+1)
 	if err != nil && err != io.EOF {
 		mp.cleanupTempFile(tempFile)
-		return nil, fmt.Errorf("failed to copy data to temporary file: %w", err)
+		return nil, fmt.Errorf("%w: %v", ErrMediaDownloadFailed, err)
 	}
 
 	if written > mp.maxSize {
 		mp.cleanupTempFile(tempFile)
-		return nil, fmt.Errorf("file size exceeds maximum allowed size of %d bytes", mp.maxSize)
+		return nil, mp.tooLarge()
 	}
 
 	if err := tempFile.Close(); err != nil {
@@ -267,6 +381,69 @@ func (mp *MediaProcessor) saveToTempFile(resp *http.Response, url, mimeType stri
 	}, nil
 }
 
+func (mp *MediaProcessor) tooLarge() error {
+	return fmt.Errorf("%w: maximum allowed size is %d bytes", ErrMediaTooLarge, mp.maxSize)
+}
+
+// sniffFile resolves the mime type of a file from its first bytes
+func sniffFile(filePath, declared, filename string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = file.Close() }()
+
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+
+	return resolveMimeType(declared, head[:n], filename), nil
+}
+
+// resolveMimeType picks the mime type of media content: what the content looks like when it is
+// recognizable, then the declared type, then the file extension. Servers and clients often declare
+// generic or wrong types, while the first bytes of images, audio and video are reliable.
+func resolveMimeType(declared string, content []byte, filename string) string {
+	if parsed, _, err := mime.ParseMediaType(declared); err == nil {
+		declared = strings.ToLower(parsed)
+	} else {
+		declared = ""
+	}
+
+	sniffed := http.DetectContentType(content)
+	if parsed, _, err := mime.ParseMediaType(sniffed); err == nil {
+		sniffed = parsed
+	}
+	// text/plain and the generic types only say the content was not recognized
+	if sniffed != genericMimeType && sniffed != "text/plain" && sniffed != "application/zip" {
+		return sniffed
+	}
+
+	if declared != "" && declared != genericMimeType && declared != "binary/octet-stream" {
+		return declared
+	}
+	if filename != "" {
+		if byExtension := DetectMimeType(filename); byExtension != genericMimeType {
+			return byExtension
+		}
+	}
+	if sniffed == "text/plain" {
+		return sniffed
+	}
+	return genericMimeType
+}
+
+// urlFilename returns the last path segment of a media URL, used to guess the type of undeclared content
+func urlFilename(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return path.Base(parsed.Path)
+}
+
 // cleanupTempFile cleans up temporary file on error
 func (mp *MediaProcessor) cleanupTempFile(tempFile *os.File) {
 	_ = tempFile.Close()
@@ -319,6 +496,13 @@ func ValidateMessageRequest(req *SendMessageRequest) error {
 	if req.To == "" {
 		return fmt.Errorf("recipient (to) is required")
 	}
+	// Impossible numbers are flagged here rather than failing on WhatsApp's side; bare group IDs are
+	// too long to be numbers and are left to the JID parsing
+	if !strings.Contains(req.To, "@") && phone.LooksLikeNumber(req.To) {
+		if err := phone.Validate(req.To); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidRecipient, err)
+		}
+	}
 
 	if req.Type == "" {
 		return fmt.Errorf("message type is required")
@@ -330,7 +514,7 @@ func ValidateMessageRequest(req *SendMessageRequest) error {
 			return fmt.Errorf("body is required for text messages")
 		}
 	case MessageTypeImage, MessageTypeAudio, MessageTypeVideo, MessageTypeDocument, MessageTypeSticker:
-		if req.File == "" {
+		if req.File == "" && req.Upload == nil {
 			return fmt.Errorf("file is required for %s messages", req.Type)
 		}
 	case MessageTypeLocation:
@@ -341,6 +525,9 @@ func ValidateMessageRequest(req *SendMessageRequest) error {
 		if req.ContactName == "" || req.ContactPhone == "" {
 			return fmt.Errorf("contact name and phone are required for contact messages")
 		}
+		if err := phone.Validate(req.ContactPhone); err != nil {
+			return fmt.Errorf("invalid contact phone: %w", err)
+		}
 	default:
 		return fmt.Errorf("unsupported message type: %s", req.Type)
 	}
@@ -0,0 +1,20 @@
+package message
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateMessageRequestAcceptsBareGroupID(t *testing.T) {
+	req := &SendMessageRequest{To: "120363025246125486", Type: MessageTypeText, Body: "hi"}
+	if err := ValidateMessageRequest(req); err != nil {
+		t.Fatalf("bare group ID rejected: %v", err)
+	}
+}
+
+func TestValidateMessageRequestRejectsImpossibleNumber(t *testing.T) {
+	req := &SendMessageRequest{To: "+55 11 9", Type: MessageTypeText, Body: "hi"}
+	if err := ValidateMessageRequest(req); !errors.Is(err, ErrInvalidRecipient) {
+		t.Fatalf("got %v, want ErrInvalidRecipient", err)
+	}
+}
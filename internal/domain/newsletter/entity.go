@@ -138,6 +138,11 @@ func (n *NewsletterInfo) IsOwner() bool {
 	return n.Role == NewsletterRoleOwner
 }
 
+// IsFollowing checks if the current user follows the newsletter, either as subscriber or as admin
+func (n *NewsletterInfo) IsFollowing() bool {
+	return n.Role == NewsletterRoleSubscriber || n.IsAdmin()
+}
+
 // IsActive checks if the newsletter is active
 func (n *NewsletterInfo) IsActive() bool {
 	return n.State == NewsletterStateActive
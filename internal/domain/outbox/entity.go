@@ -0,0 +1,71 @@
+package outbox
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"zpwoot/internal/domain/history"
+	"zpwoot/internal/domain/message"
+	"zpwoot/platform/pagination"
+)
+
+// Message is a send accepted by the enqueue API. The outbox worker sends it once SendAfter has
+// passed, keeping a delay between messages to the same chat.
+type Message struct {
+	ID        uuid.UUID                   `json:"id"`
+	SessionID string                      `json:"session_id"`
+	ChatJID   string                      `json:"chat_jid"`
+	Workspace string                      `json:"workspace,omitempty"`
+	RequestID string                      `json:"request_id,omitempty"`
+	Actor     *history.Actor              `json:"actor,omitempty"`
+	Request   *message.SendMessageRequest `json:"request"`
+	Status    string                      `json:"status"`
+	Attempts  int                         `json:"attempts"`
+	MessageID string                      `json:"message_id,omitempty"`
+	LastError string                      `json:"last_error,omitempty"`
+	SendAfter time.Time                   `json:"send_after"`
+	SentAt    *time.Time                  `json:"sent_at,omitempty"`
+	CreatedAt time.Time                   `json:"created_at"`
+	UpdatedAt time.Time                   `json:"updated_at"`
+}
+
+// Outbox message statuses
+const (
+	StatusQueued    = "queued"
+	StatusSending   = "sending"
+	StatusSent      = "sent"
+	StatusFailed    = "failed"
+	StatusCancelled = "cancelled"
+)
+
+// Config controls the outbox; the enqueue API is rejected unless Enabled is set
+type Config struct {
+	Enabled bool
+	// MinDelay and MaxDelay bound the random delay between two messages to the same chat
+	MinDelay time.Duration
+	MaxDelay time.Duration
+	// SimulateTyping shows the session as typing, or recording for audio, for a time proportional
+	// to the message length before sending it
+	SimulateTyping bool
+}
+
+type ListRequest struct {
+	SessionID string `json:"session_id" validate:"required"`
+	Status    string `json:"status,omitempty" query:"status"`
+	Limit     int    `json:"limit,omitempty" query:"limit" validate:"omitempty,min=1,max=100"`
+	Offset    int    `json:"offset,omitempty" query:"offset" validate:"omitempty,min=0"`
+	// After continues the list past a cursor instead of skipping Offset rows
+	After *pagination.Cursor `json:"-"`
+}
+
+var (
+	ErrOutboxDisabled        = errors.New("outbox is disabled")
+	ErrOutboxMessageNotFound = errors.New("outbox message not found")
+)
+
+// IsPending reports whether the message still waits to be sent
+func (m *Message) IsPending() bool {
+	return m.Status == StatusQueued || m.Status == StatusSending
+}
@@ -0,0 +1,363 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"zpwoot/internal/domain/history"
+	"zpwoot/internal/domain/maintenance"
+	"zpwoot/internal/domain/message"
+	"zpwoot/internal/domain/queue"
+	"zpwoot/internal/domain/usage"
+	"zpwoot/platform/logger"
+)
+
+// dueBatchSize limits how many chats are served per dispatcher tick
+const dueBatchSize = 20
+
+// staleClaimTimeout is how long a message may stay in sending before it is considered abandoned by a
+// crashed dispatcher and queued again; it is far longer than any typing simulation plus send
+const staleClaimTimeout = 10 * time.Minute
+
+// disconnectedRetryDelay is how long a message waits before it is tried again when its session is
+// not connected
+const disconnectedRetryDelay = 30 * time.Second
+
+// Typing is simulated at the pace of a fast typist, within bounds so long texts don't stall the chat
+const (
+	typingCharsPerSecond = 15
+	minTypingDuration    = time.Second
+	maxTypingDuration    = 8 * time.Second
+)
+
+// Repository defines the interface for outbox data operations
+type Repository interface {
+	Create(ctx context.Context, msg *Message) error
+	// LastSlot returns when the chat's latest message was sent or is due to be sent, or the zero
+	// time when the chat has none
+	LastSlot(ctx context.Context, sessionID, chatJID string) (time.Time, error)
+	// ClaimDue marks the oldest due message of up to limit chats as sending and returns them; chats
+	// with a message already being sent are skipped so each chat gets one message at a time
+	ClaimDue(ctx context.Context, now time.Time, limit int) ([]*Message, error)
+	// ReleaseStale puts messages claimed before claimedBefore back in the queue and returns how many
+	ReleaseStale(ctx context.Context, claimedBefore time.Time) (int, error)
+	Update(ctx context.Context, msg *Message) error
+	// Postpone moves the chat's queued messages due before sendAfter to sendAfter
+	Postpone(ctx context.Context, sessionID, chatJID string, sendAfter time.Time) error
+	GetByID(ctx context.Context, sessionID string, id uuid.UUID) (*Message, error)
+	// CancelQueued marks the message cancelled unless the dispatcher already claimed it, and reports
+	// whether it did
+	CancelQueued(ctx context.Context, sessionID string, id uuid.UUID) (bool, error)
+	List(ctx context.Context, req *ListRequest) ([]*Message, int, error)
+}
+
+// Sender sends an outbox message through the regular send path
+type Sender interface {
+	SendQueued(ctx context.Context, sessionID string, req *message.SendMessageRequest) (*message.SendResult, error)
+}
+
+// PresenceSender shows the session typing or recording in a chat
+type PresenceSender interface {
+	SendPresence(sessionID, to, presence string) error
+}
+
+// SendGate reports whether sends are frozen for a session, e.g. during maintenance
+type SendGate interface {
+	SendFrozen(sessionID string) bool
+}
+
+// Service sends messages asynchronously, spacing out the messages to each chat by a random delay
+// and showing the session typing before each one, so bulk sends look like a person writing them
+type Service struct {
+	logger   *logger.Logger
+	repo     Repository
+	config   Config
+	presence PresenceSender
+	sender   Sender
+	sendGate SendGate
+}
+
+func NewService(logger *logger.Logger, repo Repository, config Config, presence PresenceSender) *Service {
+	return &Service{
+		logger:   logger,
+		repo:     repo,
+		config:   config,
+		presence: presence,
+	}
+}
+
+// SetSender sets what sends the outbox messages; it is set after creation because the sender is
+// built on top of the domain services
+func (s *Service) SetSender(sender Sender) {
+	s.sender = sender
+}
+
+// SetSendGate makes the dispatcher hold back messages of sessions whose sends are frozen
+func (s *Service) SetSendGate(gate SendGate) {
+	s.sendGate = gate
+}
+
+// Enabled reports whether the enqueue API accepts messages
+func (s *Service) Enabled() bool {
+	return s != nil && s.config.Enabled
+}
+
+// Enqueue stores a message for the worker, due one delay after the chat's previous message
+func (s *Service) Enqueue(ctx context.Context, sessionID string, req *message.SendMessageRequest) (*Message, error) {
+	if !s.Enabled() {
+		return nil, ErrOutboxDisabled
+	}
+	if s.sendGate != nil && s.sendGate.SendFrozen(sessionID) {
+		return nil, maintenance.ErrSendFrozen
+	}
+
+	last, err := s.repo.LastSlot(ctx, sessionID, req.To)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last outbox message of the chat: %w", err)
+	}
+
+	now := time.Now()
+	sendAfter := now
+	if next := last.Add(s.delay()); next.After(sendAfter) {
+		sendAfter = next
+	}
+
+	msg := &Message{
+		ID:        uuid.New(),
+		SessionID: sessionID,
+		ChatJID:   req.To,
+		Workspace: usage.WorkspaceFromContext(ctx),
+		RequestID: logger.RequestIDFromContext(ctx),
+		Actor:     history.ActorFromContext(ctx),
+		Request:   req,
+		Status:    StatusQueued,
+		SendAfter: sendAfter,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := s.repo.Create(ctx, msg); err != nil {
+		return nil, fmt.Errorf("failed to add message to the outbox: %w", err)
+	}
+
+	s.logger.InfoWithFields("Message added to the outbox", map[string]interface{}{
+		"session_id": sessionID,
+		"outbox_id":  msg.ID.String(),
+		"to":         req.To,
+		"send_after": msg.SendAfter,
+	})
+
+	return msg, nil
+}
+
+func (s *Service) Get(ctx context.Context, sessionID string, id uuid.UUID) (*Message, error) {
+	return s.repo.GetByID(ctx, sessionID, id)
+}
+
+// Cancel keeps a queued message from being sent; it reports false with the message's current state
+// when the dispatcher already picked it up
+func (s *Service) Cancel(ctx context.Context, sessionID string, id uuid.UUID) (*Message, bool, error) {
+	cancelled, err := s.repo.CancelQueued(ctx, sessionID, id)
+	if err != nil {
+		return nil, false, err
+	}
+
+	msg, err := s.repo.GetByID(ctx, sessionID, id)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if cancelled {
+		s.logger.InfoWithFields("Outbox message cancelled", map[string]interface{}{
+			"session_id": sessionID,
+			"outbox_id":  id.String(),
+		})
+	}
+
+	return msg, cancelled, nil
+}
+
+func (s *Service) List(ctx context.Context, req *ListRequest) ([]*Message, int, error) {
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+
+	return s.repo.List(ctx, req)
+}
+
+// DispatchDue sends the next due message of each chat; chats are served concurrently, so a tick
+// lasts about as long as the longest typing simulation
+func (s *Service) DispatchDue(ctx context.Context) error {
+	if s.sender == nil {
+		return nil
+	}
+	// Nothing can be sent while the whole instance is frozen
+	if s.sendGate != nil && s.sendGate.SendFrozen("") {
+		return nil
+	}
+
+	if released, err := s.repo.ReleaseStale(ctx, time.Now().Add(-staleClaimTimeout)); err != nil {
+		s.logger.ErrorWithFields("Failed to release stale outbox message claims", map[string]interface{}{
+			"error": err.Error(),
+		})
+	} else if released > 0 {
+		s.logger.WarnWithFields("Outbox message claims abandoned in sending were released", map[string]interface{}{
+			"count": released,
+		})
+	}
+
+	msgs, err := s.repo.ClaimDue(ctx, time.Now(), dueBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to claim due outbox messages: %w", err)
+	}
+
+	var wg sync.WaitGroup
+	for _, msg := range msgs {
+		if s.sendGate != nil && s.sendGate.SendFrozen(msg.SessionID) {
+			s.retryLater(ctx, msg)
+			continue
+		}
+
+		wg.Add(1)
+		go func(msg *Message) {
+			defer wg.Done()
+			s.deliver(ctx, msg)
+		}(msg)
+	}
+	wg.Wait()
+
+	return nil
+}
+
+// deliver shows the session typing, sends a claimed message and records the outcome. Once sent,
+// the chat's next messages are pushed back by a delay, which also spaces out messages that became
+// due together while the session was disconnected.
+func (s *Service) deliver(ctx context.Context, msg *Message) {
+	sendCtx := ctx
+	if msg.Workspace != "" {
+		sendCtx = context.WithValue(ctx, usage.WorkspaceContextKey, msg.Workspace)
+	}
+	// the events of the message are traced back to the request that enqueued it
+	sendCtx = logger.WithRequestID(sendCtx, msg.RequestID)
+	if msg.Actor != nil {
+		sendCtx = history.WithActor(sendCtx, msg.Actor)
+	}
+
+	s.simulateTyping(ctx, msg)
+
+	result, err := s.sender.SendQueued(sendCtx, msg.SessionID, msg.Request)
+	switch {
+	case errors.Is(err, queue.ErrSessionDisconnected), errors.Is(err, maintenance.ErrSendFrozen):
+		s.retryLater(ctx, msg)
+		return
+	case err != nil:
+		s.logger.WarnWithFields("Failed to send outbox message", map[string]interface{}{
+			"session_id": msg.SessionID,
+			"outbox_id":  msg.ID.String(),
+			"error":      err.Error(),
+		})
+		msg.Status = StatusFailed
+		msg.LastError = err.Error()
+		s.stopTyping(msg)
+	default:
+		sentAt := time.Now()
+		msg.Status = StatusSent
+		msg.MessageID = result.MessageID
+		msg.LastError = ""
+		msg.SentAt = &sentAt
+	}
+
+	s.update(ctx, msg)
+
+	if msg.Status == StatusSent {
+		if err := s.repo.Postpone(ctx, msg.SessionID, msg.ChatJID, msg.SentAt.Add(s.delay())); err != nil {
+			s.logger.ErrorWithFields("Failed to space out outbox messages of the chat", map[string]interface{}{
+				"session_id": msg.SessionID,
+				"chat_jid":   msg.ChatJID,
+				"error":      err.Error(),
+			})
+		}
+	}
+}
+
+// retryLater returns a claimed message to the queue for a later tick
+func (s *Service) retryLater(ctx context.Context, msg *Message) {
+	msg.Status = StatusQueued
+	msg.SendAfter = time.Now().Add(disconnectedRetryDelay)
+	s.update(ctx, msg)
+}
+
+func (s *Service) update(ctx context.Context, msg *Message) {
+	if err := s.repo.Update(ctx, msg); err != nil {
+		s.logger.ErrorWithFields("Failed to record outbox message outcome", map[string]interface{}{
+			"session_id": msg.SessionID,
+			"outbox_id":  msg.ID.String(),
+			"status":     msg.Status,
+			"error":      err.Error(),
+		})
+	}
+}
+
+// simulateTyping shows the session typing in the chat, or recording for audio, for a time
+// proportional to the message length. Presence is cosmetic, so failures are only logged.
+func (s *Service) simulateTyping(ctx context.Context, msg *Message) {
+	if !s.config.SimulateTyping || s.presence == nil {
+		return
+	}
+
+	presence := "typing"
+	if msg.Request.Type == message.MessageTypeAudio {
+		presence = "recording"
+	}
+
+	if err := s.presence.SendPresence(msg.SessionID, msg.ChatJID, presence); err != nil {
+		s.logger.DebugWithFields("Failed to send typing presence for outbox message", map[string]interface{}{
+			"session_id": msg.SessionID,
+			"outbox_id":  msg.ID.String(),
+			"error":      err.Error(),
+		})
+		return
+	}
+
+	timer := time.NewTimer(typingDuration(msg.Request))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
+
+// stopTyping clears the typing presence of a message that could not be sent
+func (s *Service) stopTyping(msg *Message) {
+	if !s.config.SimulateTyping || s.presence == nil {
+		return
+	}
+	_ = s.presence.SendPresence(msg.SessionID, msg.ChatJID, "paused")
+}
+
+// delay returns a random delay between two messages to the same chat
+func (s *Service) delay() time.Duration {
+	spread := s.config.MaxDelay - s.config.MinDelay
+	if spread <= 0 {
+		return s.config.MinDelay
+	}
+	return s.config.MinDelay + time.Duration(rand.Int63n(int64(spread)+1))
+}
+
+func typingDuration(req *message.SendMessageRequest) time.Duration {
+	chars := len([]rune(req.Body)) + len([]rune(req.Caption))
+	duration := time.Duration(chars) * time.Second / typingCharsPerSecond
+	if duration < minTypingDuration {
+		return minTypingDuration
+	}
+	if duration > maxTypingDuration {
+		return maxTypingDuration
+	}
+	return duration
+}
@@ -0,0 +1,35 @@
+package pairing
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// DefaultLinkTTL is how long a pairing link stays valid when the request sets none
+	DefaultLinkTTL = 10 * time.Minute
+	// MaxLinkTTL bounds how long a pairing link can stay valid
+	MaxLinkTTL = time.Hour
+)
+
+var (
+	ErrInvalidLink   = errors.New("invalid pairing link")
+	ErrLinkExpired   = errors.New("pairing link has expired")
+	ErrLinkUsed      = errors.New("pairing link has already been used")
+	ErrAlreadyPaired = errors.New("session is already paired")
+)
+
+// Link is a one-time pairing link for a session. It is revoked once the session
+// pairs through it, when it expires, or when a newer link is issued for the session.
+type Link struct {
+	ID          string
+	SessionID   string
+	SessionName string
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+	UsedAt      *time.Time
+}
+
+func (l *Link) IsExpired() bool {
+	return time.Now().After(l.ExpiresAt)
+}
@@ -0,0 +1,146 @@
+package pairing
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"zpwoot/platform/logger"
+)
+
+// Service issues and verifies signed pairing links. Links are kept in memory and signed
+// with a key generated at startup, so a restart invalidates every outstanding link.
+type Service struct {
+	logger *logger.Logger
+	secret []byte
+
+	mu    sync.Mutex
+	links map[string]*Link
+}
+
+func NewService(logger *logger.Logger) (*Service, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate pairing link key: %w", err)
+	}
+
+	return &Service{
+		logger: logger,
+		secret: secret,
+		links:  make(map[string]*Link),
+	}, nil
+}
+
+// Issue creates a link for the session and returns it with its token; earlier links of the session are revoked
+func (s *Service) Issue(sessionID, sessionName string, ttl time.Duration) (*Link, string, error) {
+	if ttl <= 0 {
+		ttl = DefaultLinkTTL
+	}
+	if ttl > MaxLinkTTL {
+		ttl = MaxLinkTTL
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, "", fmt.Errorf("failed to generate pairing link: %w", err)
+	}
+
+	now := time.Now()
+	link := &Link{
+		ID:          hex.EncodeToString(nonce),
+		SessionID:   sessionID,
+		SessionName: sessionName,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(ttl),
+	}
+
+	s.mu.Lock()
+	for id, existing := range s.links {
+		if existing.SessionID == sessionID || existing.IsExpired() {
+			delete(s.links, id)
+		}
+	}
+	s.links[link.ID] = link
+	s.mu.Unlock()
+
+	s.logger.InfoWithFields("Pairing link issued", map[string]interface{}{
+		"session_id": sessionID,
+		"expires_at": link.ExpiresAt,
+	})
+
+	return link, s.sign(link), nil
+}
+
+// Verify checks the token signature and returns its link while it is still usable
+func (s *Service) Verify(token string) (*Link, error) {
+	id, expiresAt, err := s.parse(token)
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().After(expiresAt) {
+		return nil, ErrLinkExpired
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	link, exists := s.links[id]
+	if !exists {
+		return nil, ErrInvalidLink
+	}
+	if link.UsedAt != nil {
+		return nil, ErrLinkUsed
+	}
+
+	copied := *link
+	return &copied, nil
+}
+
+// MarkUsed revokes a link after its session paired through it
+func (s *Service) MarkUsed(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if link, exists := s.links[id]; exists && link.UsedAt == nil {
+		now := time.Now()
+		link.UsedAt = &now
+	}
+}
+
+// sign builds the URL safe token "<id>.<expiry>.<mac>"
+func (s *Service) sign(link *Link) string {
+	payload := link.ID + "." + strconv.FormatInt(link.ExpiresAt.Unix(), 10)
+	return payload + "." + s.mac(payload)
+}
+
+func (s *Service) parse(token string) (string, time.Time, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", time.Time{}, ErrInvalidLink
+	}
+
+	payload := parts[0] + "." + parts[1]
+	if !hmac.Equal([]byte(parts[2]), []byte(s.mac(payload))) {
+		return "", time.Time{}, ErrInvalidLink
+	}
+
+	expiresUnix, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return "", time.Time{}, ErrInvalidLink
+	}
+
+	return parts[0], time.Unix(expiresUnix, 0), nil
+}
+
+func (s *Service) mac(payload string) string {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,203 @@
+package poll
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Poll is the definition of a poll sent or received by a session, kept so votes, which only carry
+// SHA-256 hashes of the chosen options, can be mapped back to the option text
+type Poll struct {
+	ID              uuid.UUID  `json:"id" db:"id"`
+	SessionID       string     `json:"session_id" db:"session_id"`
+	MessageID       string     `json:"message_id" db:"message_id"`
+	ChatJID         string     `json:"chat_jid" db:"chat_jid"`
+	Name            string     `json:"name" db:"name"`
+	Options         []Option   `json:"options" db:"options"`
+	SelectableCount int        `json:"selectable_count" db:"selectable_count"` // 0 allows any number of answers
+	ExpiresAt       *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+}
+
+// Option is one poll option with the hash WhatsApp uses to refer to it in votes
+type Option struct {
+	Name string `json:"name"`
+	Hash string `json:"hash"` // hex encoded SHA-256 of Name
+}
+
+// Vote is the current selection of one voter; a new vote replaces the previous one and an
+// empty selection means the voter retracted their vote
+type Vote struct {
+	PollID   uuid.UUID `json:"poll_id" db:"poll_id"`
+	VoterJID string    `json:"voter_jid" db:"voter_jid"`
+	Options  []string  `json:"options" db:"options"`
+	Late     bool      `json:"late" db:"late"` // Cast after the poll expired
+	VotedAt  time.Time `json:"voted_at" db:"voted_at"`
+}
+
+// OptionTally is how many current votes an option has
+type OptionTally struct {
+	Option string `json:"option"`
+	Votes  int    `json:"votes"`
+}
+
+// VoteResult is the outcome of recording a vote
+type VoteResult struct {
+	Poll          *Poll         `json:"poll"`
+	Vote          *Vote         `json:"vote"`
+	UnknownHashes []string      `json:"unknown_hashes,omitempty"` // Hashes that match no option of the stored poll
+	Tallies       []OptionTally `json:"tallies"`
+}
+
+// Results are the current votes of a poll: the tally of every option with the voters who chose it
+type Results struct {
+	Poll    *Poll               `json:"poll"`
+	Tallies []OptionTally       `json:"tallies"`
+	Voters  map[string][]string `json:"voters"` // Option name -> voter JIDs, in voting order
+	// TotalVoters counts the voters with a current selection; retracted and late votes are not counted
+	TotalVoters int `json:"total_voters"`
+	LateVotes   int `json:"late_votes"`
+}
+
+var (
+	ErrPollNotFound = errors.New("poll not found")
+	ErrInvalidPoll  = errors.New("poll message ID, chat and options are required")
+
+	ErrUnknownOption   = errors.New("option is not part of the poll")
+	ErrTooManyOptions  = errors.New("more options selected than the poll allows")
+	ErrDuplicateOption = errors.New("option selected more than once")
+)
+
+// NewPoll builds the definition of a sent poll, hashing its options the way WhatsApp does
+func NewPoll(sessionID, messageID, chatJID, name string, options []string, selectableCount int, expiresAt *time.Time) *Poll {
+	p := &Poll{
+		ID:              uuid.New(),
+		SessionID:       sessionID,
+		MessageID:       messageID,
+		ChatJID:         chatJID,
+		Name:            name,
+		Options:         make([]Option, len(options)),
+		SelectableCount: selectableCount,
+		ExpiresAt:       expiresAt,
+		CreatedAt:       time.Now(),
+	}
+	for i, option := range options {
+		p.Options[i] = Option{Name: option, Hash: HashOption(option)}
+	}
+	return p
+}
+
+// HashOption returns the hex encoded SHA-256 hash WhatsApp uses for a poll option
+func HashOption(name string) string {
+	sum := sha256.Sum256([]byte(name))
+	return hex.EncodeToString(sum[:])
+}
+
+// OptionByHash returns the option whose hash matches the raw hash of a vote
+func (p *Poll) OptionByHash(hash []byte) (Option, bool) {
+	encoded := hex.EncodeToString(hash)
+	for _, option := range p.Options {
+		if option.Hash == encoded {
+			return option, true
+		}
+	}
+	return Option{}, false
+}
+
+// ResolveOptions maps the options chosen for a vote to the exact option text WhatsApp hashes. An option
+// may be given by its text, ignoring case and surrounding spaces, or by its hex encoded hash.
+func (p *Poll) ResolveOptions(selected []string) ([]string, error) {
+	resolved := make([]string, 0, len(selected))
+	seen := make(map[string]bool, len(selected))
+
+	for _, choice := range selected {
+		option, ok := p.findOption(choice)
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownOption, choice)
+		}
+		if seen[option.Name] {
+			return nil, fmt.Errorf("%w: %q", ErrDuplicateOption, option.Name)
+		}
+		seen[option.Name] = true
+		resolved = append(resolved, option.Name)
+	}
+
+	if p.SelectableCount > 0 && len(resolved) > p.SelectableCount {
+		return nil, fmt.Errorf("%w: at most %d", ErrTooManyOptions, p.SelectableCount)
+	}
+	return resolved, nil
+}
+
+// findOption returns the option matching a choice by exact text, hash or case-insensitive text
+func (p *Poll) findOption(choice string) (Option, bool) {
+	for _, option := range p.Options {
+		if option.Name == choice || option.Hash == strings.ToLower(choice) {
+			return option, true
+		}
+	}
+	trimmed := strings.TrimSpace(choice)
+	for _, option := range p.Options {
+		if strings.EqualFold(option.Name, trimmed) {
+			return option, true
+		}
+	}
+	return Option{}, false
+}
+
+// AllowsMultipleAnswers reports whether voters may pick more than one option
+func (p *Poll) AllowsMultipleAnswers() bool {
+	return p.SelectableCount != 1
+}
+
+// IsExpired reports whether the poll stopped accepting votes at the given time
+func (p *Poll) IsExpired(at time.Time) bool {
+	return p.ExpiresAt != nil && !at.Before(*p.ExpiresAt)
+}
+
+// Results aggregates the current votes of the poll
+func (p *Poll) Results(votes []*Vote) *Results {
+	results := &Results{
+		Poll:    p,
+		Tallies: p.Tally(votes),
+		Voters:  make(map[string][]string, len(p.Options)),
+	}
+	for _, vote := range votes {
+		if vote.Late {
+			results.LateVotes++
+			continue
+		}
+		if len(vote.Options) == 0 {
+			continue
+		}
+		results.TotalVoters++
+		for _, option := range vote.Options {
+			results.Voters[option] = append(results.Voters[option], vote.VoterJID)
+		}
+	}
+	return results
+}
+
+// Tally counts the current votes of every option in poll order; late votes are not counted
+func (p *Poll) Tally(votes []*Vote) []OptionTally {
+	counts := make(map[string]int, len(p.Options))
+	for _, vote := range votes {
+		if vote.Late {
+			continue
+		}
+		for _, option := range vote.Options {
+			counts[option]++
+		}
+	}
+
+	tallies := make([]OptionTally, len(p.Options))
+	for i, option := range p.Options {
+		tallies[i] = OptionTally{Option: option.Name, Votes: counts[option.Name]}
+	}
+	return tallies
+}
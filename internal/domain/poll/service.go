@@ -0,0 +1,115 @@
+package poll
+
+import (
+	"context"
+	"encoding/hex"
+	"time"
+
+	"zpwoot/platform/logger"
+)
+
+// Repository defines the interface for poll definition data operations
+type Repository interface {
+	Create(ctx context.Context, poll *Poll) error
+	GetByMessageID(ctx context.Context, sessionID, messageID string) (*Poll, error)
+	// SaveVote stores the voter's selection unless a newer vote of the same voter is already stored
+	SaveVote(ctx context.Context, vote *Vote) error
+	ListVotes(ctx context.Context, pollID string) ([]*Vote, error)
+}
+
+type Service struct {
+	logger   *logger.Logger
+	pollRepo Repository
+}
+
+func NewService(logger *logger.Logger, pollRepo Repository) *Service {
+	return &Service{
+		logger:   logger,
+		pollRepo: pollRepo,
+	}
+}
+
+// RecordPoll stores the definition of a poll that was just sent
+func (s *Service) RecordPoll(ctx context.Context, p *Poll) error {
+	if p.MessageID == "" || p.ChatJID == "" || len(p.Options) == 0 {
+		return ErrInvalidPoll
+	}
+
+	if err := s.pollRepo.Create(ctx, p); err != nil {
+		return err
+	}
+
+	s.logger.DebugWithFields("Poll definition stored", map[string]interface{}{
+		"session_id": p.SessionID,
+		"message_id": p.MessageID,
+		"options":    len(p.Options),
+	})
+	return nil
+}
+
+// GetPoll returns the definition of a poll sent or received by the session
+func (s *Service) GetPoll(ctx context.Context, sessionID, messageID string) (*Poll, error) {
+	return s.pollRepo.GetByMessageID(ctx, sessionID, messageID)
+}
+
+// GetResults returns the aggregated current votes of a poll seen by the session
+func (s *Service) GetResults(ctx context.Context, sessionID, messageID string) (*Results, error) {
+	p, err := s.pollRepo.GetByMessageID(ctx, sessionID, messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	votes, err := s.pollRepo.ListVotes(ctx, p.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return p.Results(votes), nil
+}
+
+// RecordVote maps the option hashes of a vote on a stored poll back to option text, stores it as the
+// voter's current selection and returns the updated tallies
+func (s *Service) RecordVote(ctx context.Context, sessionID, pollMessageID, voterJID string, hashes [][]byte, votedAt time.Time) (*VoteResult, error) {
+	p, err := s.pollRepo.GetByMessageID(ctx, sessionID, pollMessageID)
+	if err != nil {
+		return nil, err
+	}
+
+	vote := &Vote{
+		PollID:   p.ID,
+		VoterJID: voterJID,
+		Options:  []string{},
+		Late:     p.IsExpired(votedAt),
+		VotedAt:  votedAt,
+	}
+	result := &VoteResult{Poll: p, Vote: vote}
+
+	for _, hash := range hashes {
+		option, ok := p.OptionByHash(hash)
+		if !ok {
+			result.UnknownHashes = append(result.UnknownHashes, hex.EncodeToString(hash))
+			continue
+		}
+		vote.Options = append(vote.Options, option.Name)
+	}
+
+	if err := s.pollRepo.SaveVote(ctx, vote); err != nil {
+		return nil, err
+	}
+
+	votes, err := s.pollRepo.ListVotes(ctx, p.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	result.Tallies = p.Tally(votes)
+
+	s.logger.DebugWithFields("Poll vote recorded", map[string]interface{}{
+		"session_id": sessionID,
+		"poll_id":    pollMessageID,
+		"voter":      voterJID,
+		"options":    len(vote.Options),
+		"late":       vote.Late,
+	})
+
+	return result, nil
+}
@@ -0,0 +1,54 @@
+package privacy
+
+import (
+	"time"
+)
+
+// Config is the privacy mode of a session. RedactLogs keeps message content out of the logs;
+// MetadataOnlyWebhooks delivers message events without their content, which receivers fetch from
+// the history API when they need it.
+type Config struct {
+	SessionID            string    `json:"session_id"`
+	RedactLogs           bool      `json:"redact_logs"`
+	MetadataOnlyWebhooks bool      `json:"metadata_only_webhooks"`
+	UpdatedAt            time.Time `json:"updated_at"`
+}
+
+// UpdateConfigRequest changes the fields that are set
+type UpdateConfigRequest struct {
+	RedactLogs           *bool `json:"redact_logs,omitempty"`
+	MetadataOnlyWebhooks *bool `json:"metadata_only_webhooks,omitempty"`
+}
+
+// ContentRedactedField is set on message events whose content was removed
+const ContentRedactedField = "content_redacted"
+
+// metadataFields are the fields of a message event kept in metadata-only webhooks. The whatsmeow
+// event itself is reduced to its Info, which holds IDs, chat, sender and timestamps but no content.
+var metadataFields = []string{
+	"message_id",
+	"from_me",
+	"chat",
+	"sender",
+	"timestamp",
+	"message_type",
+	"selected_id",
+	"Info",
+}
+
+// NewDefaultConfig returns the configuration sessions start with, with privacy mode off
+func NewDefaultConfig(sessionID string) *Config {
+	return &Config{SessionID: sessionID}
+}
+
+// MetadataOnly returns the metadata fields of message event data, marked as redacted
+func MetadataOnly(data map[string]interface{}) map[string]interface{} {
+	metadata := make(map[string]interface{}, len(metadataFields)+1)
+	for _, field := range metadataFields {
+		if value, exists := data[field]; exists {
+			metadata[field] = value
+		}
+	}
+	metadata[ContentRedactedField] = true
+	return metadata
+}
@@ -0,0 +1,135 @@
+package privacy
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"zpwoot/internal/domain/webhook"
+	"zpwoot/platform/logger"
+)
+
+// configCacheTTL bounds how long configuration changes made on another replica take to apply here
+const configCacheTTL = 30 * time.Second
+
+const messageEventType = "Message"
+
+type Repository interface {
+	// GetBySession returns nil when the session has no privacy configuration
+	GetBySession(ctx context.Context, sessionID string) (*Config, error)
+	Upsert(ctx context.Context, config *Config) error
+}
+
+// Service manages the per-session privacy mode and applies it to logs and webhook events
+type Service struct {
+	logger *logger.Logger
+	repo   Repository
+
+	mu    sync.RWMutex
+	cache map[string]*cachedConfig
+}
+
+type cachedConfig struct {
+	config   *Config
+	loadedAt time.Time
+}
+
+func NewService(logger *logger.Logger, repo Repository) *Service {
+	return &Service{
+		logger: logger,
+		repo:   repo,
+		cache:  make(map[string]*cachedConfig),
+	}
+}
+
+// GetConfig returns the session's configuration, or the default with privacy mode off when it has none
+func (s *Service) GetConfig(ctx context.Context, sessionID string) (*Config, error) {
+	config, err := s.repo.GetBySession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		return NewDefaultConfig(sessionID), nil
+	}
+	return config, nil
+}
+
+func (s *Service) UpdateConfig(ctx context.Context, sessionID string, req *UpdateConfigRequest) (*Config, error) {
+	config, err := s.GetConfig(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.RedactLogs != nil {
+		config.RedactLogs = *req.RedactLogs
+	}
+	if req.MetadataOnlyWebhooks != nil {
+		config.MetadataOnlyWebhooks = *req.MetadataOnlyWebhooks
+	}
+
+	config.UpdatedAt = time.Now()
+	if err := s.repo.Upsert(ctx, config); err != nil {
+		return nil, err
+	}
+
+	s.invalidate(sessionID)
+	return config, nil
+}
+
+// RedactsLogs reports whether message content of the session must be kept out of the logs. When
+// the configuration cannot be loaded it errs on the side of redacting.
+func (s *Service) RedactsLogs(sessionID string) bool {
+	config := s.activeConfig(context.Background(), sessionID)
+	return config == nil || config.RedactLogs
+}
+
+// ProcessWebhookEvent reduces message events of sessions with metadata-only webhooks to their
+// metadata; it lets the service be added as a webhook event processor. It runs after translation
+// so the translated text and the originals kept with it are removed as well. Like RedactsLogs it
+// redacts when the configuration cannot be loaded; the content stays available from the history API.
+func (s *Service) ProcessWebhookEvent(ctx context.Context, event *webhook.WebhookEvent) error {
+	if event.Type != messageEventType || event.SessionID == "" || event.Data == nil {
+		return nil
+	}
+
+	config := s.activeConfig(ctx, event.SessionID)
+	if config != nil && !config.MetadataOnlyWebhooks {
+		return nil
+	}
+
+	event.Data = MetadataOnly(event.Data)
+	event.Translation = nil
+	return nil
+}
+
+// activeConfig returns the session's configuration from the cache when fresh, or nil when it
+// cannot be loaded
+func (s *Service) activeConfig(ctx context.Context, sessionID string) *Config {
+	s.mu.RLock()
+	cached, exists := s.cache[sessionID]
+	s.mu.RUnlock()
+	if exists && time.Since(cached.loadedAt) < configCacheTTL {
+		return cached.config
+	}
+
+	config, err := s.GetConfig(ctx, sessionID)
+	if err != nil {
+		s.logger.WarnWithFields("Failed to load privacy configuration", map[string]interface{}{
+			"session_id": sessionID,
+			"error":      err.Error(),
+		})
+		return nil
+	}
+
+	s.mu.Lock()
+	s.cache[sessionID] = &cachedConfig{config: config, loadedAt: time.Now()}
+	s.mu.Unlock()
+
+	return config
+}
+
+func (s *Service) invalidate(sessionID string) {
+	s.mu.Lock()
+	delete(s.cache, sessionID)
+	s.mu.Unlock()
+}
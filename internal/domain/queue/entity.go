@@ -0,0 +1,60 @@
+package queue
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"zpwoot/internal/domain/history"
+	"zpwoot/internal/domain/message"
+)
+
+// QueuedMessage is a send accepted while its session was disconnected, kept until the session
+// reconnects or the message expires
+type QueuedMessage struct {
+	ID        uuid.UUID                   `json:"id"`
+	SessionID string                      `json:"session_id"`
+	Workspace string                      `json:"workspace,omitempty"`
+	RequestID string                      `json:"request_id,omitempty"`
+	Actor     *history.Actor              `json:"actor,omitempty"`
+	Request   *message.SendMessageRequest `json:"request"`
+	Status    string                      `json:"status"`
+	Attempts  int                         `json:"attempts"`
+	MessageID string                      `json:"message_id,omitempty"`
+	LastError string                      `json:"last_error,omitempty"`
+	ExpiresAt time.Time                   `json:"expires_at"`
+	SentAt    *time.Time                  `json:"sent_at,omitempty"`
+	CreatedAt time.Time                   `json:"created_at"`
+	UpdatedAt time.Time                   `json:"updated_at"`
+}
+
+// Queued message statuses
+const (
+	StatusQueued    = "queued"
+	StatusSending   = "sending"
+	StatusSent      = "sent"
+	StatusFailed    = "failed"
+	StatusExpired   = "expired"
+	StatusCancelled = "cancelled"
+)
+
+// Config controls the offline queue; it is disabled unless Enabled is set
+type Config struct {
+	Enabled bool
+	// TTL is how long a queued message waits for its session to reconnect
+	TTL time.Duration
+	// MaxPerSession caps the messages waiting per session; 0 means unlimited
+	MaxPerSession int
+}
+
+var (
+	ErrQueueDisabled         = errors.New("offline queue is disabled")
+	ErrQueueFull             = errors.New("offline queue of the session is full")
+	ErrQueuedMessageNotFound = errors.New("queued message not found")
+)
+
+// IsPending reports whether the message still waits to be sent
+func (m *QueuedMessage) IsPending() bool {
+	return m.Status == StatusQueued || m.Status == StatusSending
+}
@@ -0,0 +1,339 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"zpwoot/internal/domain/history"
+	"zpwoot/internal/domain/maintenance"
+	"zpwoot/internal/domain/message"
+	"zpwoot/internal/domain/usage"
+	"zpwoot/platform/logger"
+)
+
+// ErrSessionDisconnected is returned by the sender when the session dropped again; the message is
+// put back in the queue for the next reconnection
+var ErrSessionDisconnected = errors.New("session is not connected")
+
+// staleClaimTimeout is how long a message may stay in sending before a flush assumes the one that
+// claimed it is gone and queues it again
+const staleClaimTimeout = 10 * time.Minute
+
+// Repository defines the interface for offline queue data operations
+type Repository interface {
+	Create(ctx context.Context, msg *QueuedMessage) error
+	CountPending(ctx context.Context, sessionID string) (int, error)
+	// ClaimNext marks the session's oldest queued message that has not expired as sending and
+	// returns it, or nil when none is left
+	ClaimNext(ctx context.Context, sessionID string, now time.Time) (*QueuedMessage, error)
+	// ReleaseStale puts the session's messages claimed before claimedBefore back in the queue and
+	// returns how many
+	ReleaseStale(ctx context.Context, sessionID string, claimedBefore time.Time) (int, error)
+	Update(ctx context.Context, msg *QueuedMessage) error
+	// ExpireBefore marks queued messages whose TTL ended before the given time as expired
+	ExpireBefore(ctx context.Context, now time.Time) (int, error)
+	GetByID(ctx context.Context, sessionID string, id uuid.UUID) (*QueuedMessage, error)
+	// CancelQueued marks the message cancelled unless a flush already claimed it, and reports whether it did
+	CancelQueued(ctx context.Context, sessionID string, id uuid.UUID) (bool, error)
+}
+
+// SendGate reports whether sends are frozen for a session, e.g. during maintenance
+type SendGate interface {
+	SendFrozen(sessionID string) bool
+	// Changed returns a channel that is closed on the next change of the frozen state
+	Changed() <-chan struct{}
+}
+
+// Sender sends a queued message once its session is connected again
+type Sender interface {
+	SendQueued(ctx context.Context, sessionID string, req *message.SendMessageRequest) (*message.SendResult, error)
+}
+
+// Service accepts sends of temporarily disconnected sessions and sends them in order once the
+// session reconnects within the TTL
+type Service struct {
+	logger *logger.Logger
+	repo   Repository
+	config Config
+	sender Sender
+	gate   SendGate
+
+	mu       sync.Mutex
+	flushing map[string]bool     // session ID -> whether another flush was requested while running
+	held     map[string]struct{} // sessions whose flush waits for their sends to be unfrozen
+}
+
+func NewService(logger *logger.Logger, repo Repository, config Config) *Service {
+	return &Service{
+		logger:   logger,
+		repo:     repo,
+		config:   config,
+		flushing: make(map[string]bool),
+		held:     make(map[string]struct{}),
+	}
+}
+
+// SetSender sets what sends the queued messages; it is set after creation because the sender
+// itself enqueues messages
+func (s *Service) SetSender(sender Sender) {
+	s.sender = sender
+}
+
+// SetSendGate holds back the flush of sessions whose sends are frozen and flushes them once they are
+// unfrozen
+func (s *Service) SetSendGate(gate SendGate) {
+	s.gate = gate
+	go s.releaseHeld()
+}
+
+// releaseHeld flushes the held back sessions whenever the frozen state changes
+func (s *Service) releaseHeld() {
+	for {
+		<-s.gate.Changed()
+
+		s.mu.Lock()
+		var ready []string
+		for sessionID := range s.held {
+			if !s.gate.SendFrozen(sessionID) {
+				ready = append(ready, sessionID)
+				delete(s.held, sessionID)
+			}
+		}
+		s.mu.Unlock()
+
+		for _, sessionID := range ready {
+			s.SessionConnected(sessionID)
+		}
+	}
+}
+
+// hold keeps the session's queue until its sends are unfrozen
+func (s *Service) hold(sessionID string) {
+	s.mu.Lock()
+	s.held[sessionID] = struct{}{}
+	s.mu.Unlock()
+}
+
+// Enabled reports whether sends of disconnected sessions are queued
+func (s *Service) Enabled() bool {
+	return s != nil && s.config.Enabled
+}
+
+// Enqueue stores a send of a disconnected session until it reconnects
+func (s *Service) Enqueue(ctx context.Context, sessionID string, req *message.SendMessageRequest) (*QueuedMessage, error) {
+	if !s.Enabled() {
+		return nil, ErrQueueDisabled
+	}
+
+	if s.config.MaxPerSession > 0 {
+		pending, err := s.repo.CountPending(ctx, sessionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count queued messages: %w", err)
+		}
+		if pending >= s.config.MaxPerSession {
+			return nil, ErrQueueFull
+		}
+	}
+
+	now := time.Now()
+	msg := &QueuedMessage{
+		ID:        uuid.New(),
+		SessionID: sessionID,
+		Workspace: usage.WorkspaceFromContext(ctx),
+		RequestID: logger.RequestIDFromContext(ctx),
+		Actor:     history.ActorFromContext(ctx),
+		Request:   req,
+		Status:    StatusQueued,
+		ExpiresAt: now.Add(s.config.TTL),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := s.repo.Create(ctx, msg); err != nil {
+		return nil, fmt.Errorf("failed to queue message: %w", err)
+	}
+
+	s.logger.InfoWithFields("Message queued for disconnected session", map[string]interface{}{
+		"session_id": sessionID,
+		"queue_id":   msg.ID.String(),
+		"to":         req.To,
+		"expires_at": msg.ExpiresAt,
+	})
+
+	return msg, nil
+}
+
+// SessionConnected flushes the session's queue in the background; a flush requested while one is
+// running makes it run again once done
+func (s *Service) SessionConnected(sessionID string) {
+	if !s.Enabled() || s.sender == nil {
+		return
+	}
+
+	s.mu.Lock()
+	if _, running := s.flushing[sessionID]; running {
+		s.flushing[sessionID] = true
+		s.mu.Unlock()
+		return
+	}
+	s.flushing[sessionID] = false
+	s.mu.Unlock()
+
+	go func() {
+		for {
+			s.Flush(context.Background(), sessionID)
+
+			s.mu.Lock()
+			again := s.flushing[sessionID]
+			if !again {
+				delete(s.flushing, sessionID)
+				s.mu.Unlock()
+				return
+			}
+			s.flushing[sessionID] = false
+			s.mu.Unlock()
+		}
+	}()
+}
+
+// Flush sends the session's queued messages one at a time, oldest first, and returns how many were
+// sent. It stops when the session disconnects again or its sends are frozen, leaving the rest queued.
+func (s *Service) Flush(ctx context.Context, sessionID string) int {
+	if released, err := s.repo.ReleaseStale(ctx, sessionID, time.Now().Add(-staleClaimTimeout)); err != nil {
+		s.logger.ErrorWithFields("Failed to release stale queued message claims", map[string]interface{}{
+			"session_id": sessionID,
+			"error":      err.Error(),
+		})
+	} else if released > 0 {
+		s.logger.WarnWithFields("Queued message claims abandoned in sending were released", map[string]interface{}{
+			"session_id": sessionID,
+			"count":      released,
+		})
+	}
+
+	sent := 0
+	for {
+		if s.gate != nil && s.gate.SendFrozen(sessionID) {
+			s.hold(sessionID)
+			break
+		}
+
+		msg, err := s.repo.ClaimNext(ctx, sessionID, time.Now())
+		if err != nil {
+			s.logger.ErrorWithFields("Failed to claim queued message", map[string]interface{}{
+				"session_id": sessionID,
+				"error":      err.Error(),
+			})
+			break
+		}
+		if msg == nil {
+			break
+		}
+
+		if !s.deliver(ctx, msg) {
+			break
+		}
+		if msg.Status == StatusSent {
+			sent++
+		}
+	}
+
+	if sent > 0 {
+		s.logger.InfoWithFields("Offline queue flushed", map[string]interface{}{
+			"session_id": sessionID,
+			"sent":       sent,
+		})
+	}
+
+	return sent
+}
+
+// deliver sends a claimed message and records the outcome; it returns false when the session is
+// disconnected again or its sends are frozen and flushing must stop
+func (s *Service) deliver(ctx context.Context, msg *QueuedMessage) bool {
+	sendCtx := ctx
+	if msg.Workspace != "" {
+		sendCtx = context.WithValue(ctx, usage.WorkspaceContextKey, msg.Workspace)
+	}
+	// the events of the message are traced back to the request that queued it
+	sendCtx = logger.WithRequestID(sendCtx, msg.RequestID)
+	if msg.Actor != nil {
+		sendCtx = history.WithActor(sendCtx, msg.Actor)
+	}
+
+	result, err := s.sender.SendQueued(sendCtx, msg.SessionID, msg.Request)
+	switch {
+	case errors.Is(err, ErrSessionDisconnected):
+		msg.Status = StatusQueued
+	case errors.Is(err, maintenance.ErrSendFrozen):
+		msg.Status = StatusQueued
+		s.hold(msg.SessionID)
+	case err != nil:
+		s.logger.WarnWithFields("Failed to send queued message", map[string]interface{}{
+			"session_id": msg.SessionID,
+			"queue_id":   msg.ID.String(),
+			"error":      err.Error(),
+		})
+		msg.Status = StatusFailed
+		msg.LastError = err.Error()
+	default:
+		sentAt := time.Now()
+		msg.Status = StatusSent
+		msg.MessageID = result.MessageID
+		msg.LastError = ""
+		msg.SentAt = &sentAt
+	}
+
+	if updateErr := s.repo.Update(ctx, msg); updateErr != nil {
+		s.logger.ErrorWithFields("Failed to record queued message outcome", map[string]interface{}{
+			"session_id": msg.SessionID,
+			"queue_id":   msg.ID.String(),
+			"status":     msg.Status,
+			"error":      updateErr.Error(),
+		})
+	}
+
+	return msg.Status != StatusQueued
+}
+
+// Cancel stops a queued message from being sent; it reports false with the message's current state
+// when a flush already picked it up
+func (s *Service) Cancel(ctx context.Context, sessionID string, id uuid.UUID) (*QueuedMessage, bool, error) {
+	cancelled, err := s.repo.CancelQueued(ctx, sessionID, id)
+	if err != nil {
+		return nil, false, err
+	}
+
+	msg, err := s.repo.GetByID(ctx, sessionID, id)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if cancelled {
+		s.logger.InfoWithFields("Queued message cancelled", map[string]interface{}{
+			"session_id": sessionID,
+			"queue_id":   id.String(),
+		})
+	}
+
+	return msg, cancelled, nil
+}
+
+// ExpireOverdue expires messages whose session did not reconnect in time
+func (s *Service) ExpireOverdue(ctx context.Context) error {
+	expired, err := s.repo.ExpireBefore(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	if expired > 0 {
+		s.logger.WarnWithFields("Queued messages expired before their session reconnected", map[string]interface{}{
+			"count": expired,
+		})
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+package routing
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Rule tags, labels or routes a session's incoming messages. A rule matches when the message text
+// contains any of its keywords or matches any of its patterns, its sender is any of its senders, the
+// chat is in any of its conversation states and the message replies with any of its reply IDs; empty
+// lists match everything.
+type Rule struct {
+	ID        uuid.UUID `json:"id"`
+	SessionID string    `json:"session_id"`
+	Name      string    `json:"name"`
+	Priority  int       `json:"priority"`
+	Enabled   bool      `json:"enabled"`
+
+	// Conditions
+	Keywords []string `json:"keywords"`
+	Patterns []string `json:"patterns"`
+	Senders  []string `json:"senders"`
+	// States are conversation states the chat must be in
+	States []string `json:"states"`
+	// ReplyIDs are button or list row IDs the message must reply with
+	ReplyIDs []string `json:"reply_ids"`
+
+	// Actions
+	Tags       []string `json:"tags"`
+	WebhookIDs []string `json:"webhook_ids"`
+	// Labels are WhatsApp label IDs applied to the chat of matching messages
+	Labels []string `json:"labels"`
+	// Exclusive delivers matching messages only to the rule's webhooks
+	Exclusive bool `json:"exclusive"`
+	// StopProcessing skips the rules after this one when it matches
+	StopProcessing bool `json:"stop_processing"`
+	// NextState is the conversation state the chat moves to when the rule matches
+	NextState string `json:"next_state"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	compiled []*regexp.Regexp
+}
+
+// maxStateLength is the longest conversation state name a chat can be moved to
+const maxStateLength = 100
+
+var (
+	ErrRuleNotFound     = errors.New("routing rule not found")
+	ErrRuleNameRequired = errors.New("routing rule name is required")
+	ErrNoConditions     = errors.New("routing rule needs at least one keyword, pattern, sender, state or reply ID")
+	ErrNoActions        = errors.New("routing rule needs at least one tag, webhook, label or next state")
+	ErrInvalidNextState = errors.New("routing rule next state must be at most 100 characters")
+	ErrInvalidWebhookID = errors.New("routing rule webhook IDs must be UUIDs")
+	ErrInvalidPattern   = errors.New("routing rule pattern is not a valid regular expression")
+	ErrInvalidChatJID   = errors.New("invalid chat JID")
+	ErrLabelIDRequired  = errors.New("label ID is required")
+	ErrLabelingDisabled = errors.New("chat labeling is not available")
+)
+
+type CreateRuleRequest struct {
+	SessionID      string   `json:"session_id" validate:"required"`
+	Name           string   `json:"name" validate:"required"`
+	Priority       int      `json:"priority"`
+	Enabled        *bool    `json:"enabled,omitempty"`
+	Keywords       []string `json:"keywords"`
+	Patterns       []string `json:"patterns"`
+	Senders        []string `json:"senders"`
+	States         []string `json:"states"`
+	ReplyIDs       []string `json:"reply_ids"`
+	Tags           []string `json:"tags"`
+	WebhookIDs     []string `json:"webhook_ids"`
+	Labels         []string `json:"labels"`
+	Exclusive      bool     `json:"exclusive"`
+	StopProcessing bool     `json:"stop_processing"`
+	NextState      string   `json:"next_state"`
+}
+
+// UpdateRuleRequest changes the fields that are set
+type UpdateRuleRequest struct {
+	Name           *string   `json:"name,omitempty"`
+	Priority       *int      `json:"priority,omitempty"`
+	Enabled        *bool     `json:"enabled,omitempty"`
+	Keywords       *[]string `json:"keywords,omitempty"`
+	Patterns       *[]string `json:"patterns,omitempty"`
+	Senders        *[]string `json:"senders,omitempty"`
+	States         *[]string `json:"states,omitempty"`
+	ReplyIDs       *[]string `json:"reply_ids,omitempty"`
+	Tags           *[]string `json:"tags,omitempty"`
+	WebhookIDs     *[]string `json:"webhook_ids,omitempty"`
+	Labels         *[]string `json:"labels,omitempty"`
+	Exclusive      *bool     `json:"exclusive,omitempty"`
+	StopProcessing *bool     `json:"stop_processing,omitempty"`
+	NextState      *string   `json:"next_state,omitempty"`
+}
+
+// Message is what the rules are evaluated against. ReplyID is the button or list row ID a reply selected
+// and State the conversation state of the chat.
+type Message struct {
+	Text    string
+	Sender  string
+	Chat    string
+	ReplyID string
+	State   string
+}
+
+// Validate checks that the rule has a name, a condition and an action, and compiles its patterns
+func (r *Rule) Validate() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return ErrRuleNameRequired
+	}
+	if len(r.Keywords) == 0 && len(r.Patterns) == 0 && len(r.Senders) == 0 && len(r.States) == 0 && len(r.ReplyIDs) == 0 {
+		return ErrNoConditions
+	}
+	if len(r.Tags) == 0 && len(r.WebhookIDs) == 0 && len(r.Labels) == 0 && r.NextState == "" {
+		return ErrNoActions
+	}
+	if len(r.NextState) > maxStateLength {
+		return ErrInvalidNextState
+	}
+	for _, id := range r.WebhookIDs {
+		if _, err := uuid.Parse(id); err != nil {
+			return ErrInvalidWebhookID
+		}
+	}
+	return r.Compile()
+}
+
+// Compile prepares the rule's patterns for matching. It must be called before the rule is
+// shared between goroutines, since Matches only reads the compiled patterns.
+func (r *Rule) Compile() error {
+	compiled := make([]*regexp.Regexp, 0, len(r.Patterns))
+	for _, pattern := range r.Patterns {
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidPattern, pattern)
+		}
+		compiled = append(compiled, re)
+	}
+	r.compiled = compiled
+	return nil
+}
+
+// Matches reports whether the rule applies to the message
+func (r *Rule) Matches(msg *Message) bool {
+	if !r.Enabled {
+		return false
+	}
+	return r.matchesKeywords(msg.Text) && r.matchesSender(msg.Sender) &&
+		matchesAny(r.States, msg.State) && matchesAny(r.ReplyIDs, msg.ReplyID)
+}
+
+// UsesState reports whether the rule reads or moves the conversation state
+func (r *Rule) UsesState() bool {
+	return len(r.States) > 0 || r.NextState != ""
+}
+
+func (r *Rule) matchesKeywords(text string) bool {
+	if len(r.Keywords) == 0 && len(r.Patterns) == 0 {
+		return true
+	}
+
+	lowered := strings.ToLower(text)
+	for _, keyword := range r.Keywords {
+		if strings.Contains(lowered, keyword) {
+			return true
+		}
+	}
+	for _, re := range r.compiled {
+		if re.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesSender(sender string) bool {
+	if len(r.Senders) == 0 {
+		return true
+	}
+
+	phone := senderPhone(sender)
+	for _, candidate := range r.Senders {
+		if candidate == sender || (phone != "" && senderPhone(candidate) == phone) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesAny reports whether value is in values; an empty list matches everything
+func matchesAny(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+// normalize lower-cases keywords and drops blanks so matching stays cheap
+func (r *Rule) normalize() {
+	r.Name = strings.TrimSpace(r.Name)
+	r.Keywords = cleanList(r.Keywords, true)
+	r.Patterns = cleanList(r.Patterns, false)
+	r.Senders = cleanList(r.Senders, false)
+	r.States = cleanList(r.States, false)
+	r.ReplyIDs = cleanList(r.ReplyIDs, false)
+	r.NextState = strings.TrimSpace(r.NextState)
+	r.Tags = cleanList(r.Tags, false)
+	r.WebhookIDs = cleanList(r.WebhookIDs, true)
+	r.Labels = cleanList(r.Labels, false)
+}
+
+// senderPhone reduces a JID or phone number to its digits, without device or server parts
+func senderPhone(value string) string {
+	if at := strings.Index(value, "@"); at >= 0 {
+		value = value[:at]
+	}
+	if colon := strings.Index(value, ":"); colon >= 0 {
+		value = value[:colon]
+	}
+
+	var digits strings.Builder
+	for _, ch := range value {
+		if ch >= '0' && ch <= '9' {
+			digits.WriteRune(ch)
+		}
+	}
+	return digits.String()
+}
+
+func cleanList(values []string, lower bool) []string {
+	cleaned := make([]string, 0, len(values))
+	seen := make(map[string]bool, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if lower {
+			value = strings.ToLower(value)
+		}
+		if value == "" || seen[value] {
+			continue
+		}
+		seen[value] = true
+		cleaned = append(cleaned, value)
+	}
+	return cleaned
+}
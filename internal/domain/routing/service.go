@@ -0,0 +1,447 @@
+package routing
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"zpwoot/internal/domain/webhook"
+	"zpwoot/platform/logger"
+)
+
+// rulesCacheTTL bounds how long rule changes made on another replica take to apply here
+const rulesCacheTTL = 30 * time.Second
+
+// appliedLabelTTL bounds how long an applied label is remembered, so a label removed
+// on the phone is applied again by the next matching message after a while
+const appliedLabelTTL = time.Hour
+
+const messageEventType = "Message"
+
+type Repository interface {
+	Create(ctx context.Context, rule *Rule) error
+	GetByID(ctx context.Context, sessionID, id string) (*Rule, error)
+	ListBySession(ctx context.Context, sessionID string) ([]*Rule, error)
+	Update(ctx context.Context, rule *Rule) error
+	Delete(ctx context.Context, sessionID, id string) error
+}
+
+// ChatLabeler applies WhatsApp labels to chats
+type ChatLabeler interface {
+	LabelChat(sessionID, chatJID, labelID string, labeled bool) error
+}
+
+// ConversationStates reads and moves the conversation state of chats
+type ConversationStates interface {
+	CurrentState(ctx context.Context, sessionID, chatJID string) string
+	Transition(ctx context.Context, sessionID, chatJID, state string) error
+}
+
+// Service manages routing rules and evaluates them against incoming messages
+type Service struct {
+	logger  *logger.Logger
+	repo    Repository
+	labeler ChatLabeler
+	states  ConversationStates
+
+	mu    sync.RWMutex
+	cache map[string]*cachedRules
+
+	appliedMu sync.Mutex
+	applied   map[string]time.Time
+}
+
+type cachedRules struct {
+	rules    []*Rule
+	loadedAt time.Time
+}
+
+func NewService(logger *logger.Logger, repo Repository, labeler ChatLabeler) *Service {
+	return &Service{
+		logger:  logger,
+		repo:    repo,
+		labeler: labeler,
+		cache:   make(map[string]*cachedRules),
+		applied: make(map[string]time.Time),
+	}
+}
+
+// SetConversationStates lets rules match and move the conversation state of chats
+func (s *Service) SetConversationStates(states ConversationStates) {
+	s.states = states
+}
+
+func (s *Service) CreateRule(ctx context.Context, req *CreateRuleRequest) (*Rule, error) {
+	now := time.Now()
+	rule := &Rule{
+		ID:             uuid.New(),
+		SessionID:      req.SessionID,
+		Name:           req.Name,
+		Priority:       req.Priority,
+		Enabled:        req.Enabled == nil || *req.Enabled,
+		Keywords:       req.Keywords,
+		Patterns:       req.Patterns,
+		Senders:        req.Senders,
+		States:         req.States,
+		ReplyIDs:       req.ReplyIDs,
+		Tags:           req.Tags,
+		WebhookIDs:     req.WebhookIDs,
+		Labels:         req.Labels,
+		Exclusive:      req.Exclusive,
+		StopProcessing: req.StopProcessing,
+		NextState:      req.NextState,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	rule.normalize()
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	s.invalidate(rule.SessionID)
+	return rule, nil
+}
+
+func (s *Service) GetRule(ctx context.Context, sessionID, id string) (*Rule, error) {
+	return s.repo.GetByID(ctx, sessionID, id)
+}
+
+func (s *Service) ListRules(ctx context.Context, sessionID string) ([]*Rule, error) {
+	return s.repo.ListBySession(ctx, sessionID)
+}
+
+func (s *Service) UpdateRule(ctx context.Context, sessionID, id string, req *UpdateRuleRequest) (*Rule, error) {
+	rule, err := s.repo.GetByID(ctx, sessionID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		rule.Name = *req.Name
+	}
+	if req.Priority != nil {
+		rule.Priority = *req.Priority
+	}
+	if req.Enabled != nil {
+		rule.Enabled = *req.Enabled
+	}
+	if req.Keywords != nil {
+		rule.Keywords = *req.Keywords
+	}
+	if req.Patterns != nil {
+		rule.Patterns = *req.Patterns
+	}
+	if req.Senders != nil {
+		rule.Senders = *req.Senders
+	}
+	if req.States != nil {
+		rule.States = *req.States
+	}
+	if req.ReplyIDs != nil {
+		rule.ReplyIDs = *req.ReplyIDs
+	}
+	if req.Tags != nil {
+		rule.Tags = *req.Tags
+	}
+	if req.WebhookIDs != nil {
+		rule.WebhookIDs = *req.WebhookIDs
+	}
+	if req.Labels != nil {
+		rule.Labels = *req.Labels
+	}
+	if req.Exclusive != nil {
+		rule.Exclusive = *req.Exclusive
+	}
+	if req.StopProcessing != nil {
+		rule.StopProcessing = *req.StopProcessing
+	}
+	if req.NextState != nil {
+		rule.NextState = *req.NextState
+	}
+
+	rule.normalize()
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+
+	rule.UpdatedAt = time.Now()
+	if err := s.repo.Update(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	s.invalidate(sessionID)
+	return rule, nil
+}
+
+func (s *Service) DeleteRule(ctx context.Context, sessionID, id string) error {
+	if err := s.repo.Delete(ctx, sessionID, id); err != nil {
+		return err
+	}
+
+	s.invalidate(sessionID)
+	return nil
+}
+
+// Evaluate applies the session's rules to a message, in priority order, and returns
+// the resulting routing metadata, or nil when no rule matched. The conversation state of the
+// chat is looked up when a rule uses it and the message does not carry it.
+func (s *Service) Evaluate(ctx context.Context, sessionID string, msg *Message) *webhook.Routing {
+	rules, err := s.rulesFor(ctx, sessionID)
+	if err != nil {
+		s.logger.WarnWithFields("Failed to load routing rules", map[string]interface{}{
+			"session_id": sessionID,
+			"error":      err.Error(),
+		})
+		return nil
+	}
+
+	usesState := false
+	for _, rule := range rules {
+		if rule.UsesState() {
+			usesState = true
+			break
+		}
+	}
+	if usesState && msg.State == "" && s.states != nil && msg.Chat != "" {
+		msg.State = s.states.CurrentState(ctx, sessionID, msg.Chat)
+	}
+
+	var result *webhook.Routing
+	for _, rule := range rules {
+		if !rule.Matches(msg) {
+			continue
+		}
+
+		if result == nil {
+			result = &webhook.Routing{Rules: []string{}, Tags: []string{}}
+			if usesState {
+				result.State = msg.State
+			}
+		}
+		// The first matching rule that moves the conversation decides the next state
+		if result.NextState == "" {
+			result.NextState = rule.NextState
+		}
+		result.Rules = append(result.Rules, rule.Name)
+		result.Tags = appendUnique(result.Tags, rule.Tags...)
+		result.WebhookIDs = appendUnique(result.WebhookIDs, rule.WebhookIDs...)
+		result.Labels = appendUnique(result.Labels, rule.Labels...)
+		if rule.Exclusive && len(rule.WebhookIDs) > 0 {
+			result.Exclusive = true
+		}
+
+		if rule.StopProcessing {
+			break
+		}
+	}
+
+	return result
+}
+
+// Route evaluates the rules for a message event; it lets the service act as the webhook delivery router
+func (s *Service) Route(ctx context.Context, event *webhook.WebhookEvent) *webhook.Routing {
+	if event.Type != messageEventType || event.SessionID == "" {
+		return nil
+	}
+	// Only incoming messages are routed
+	if fromMe, _ := event.Data["from_me"].(bool); fromMe {
+		return nil
+	}
+
+	text, _ := event.Data["text"].(string)
+	if text == "" {
+		text, _ = event.Data["caption"].(string)
+	}
+	sender, _ := event.Data["sender"].(string)
+	chat, _ := event.Data["chat"].(string)
+	replyID, _ := event.Data["selected_id"].(string)
+
+	result := s.Evaluate(ctx, event.SessionID, &Message{Text: text, Sender: sender, Chat: chat, ReplyID: replyID})
+	if result != nil && result.NextState != "" && chat != "" {
+		s.moveConversation(ctx, event.SessionID, chat, result)
+	}
+	if result != nil && len(result.Labels) > 0 && chat != "" {
+		// Labeling is an app state round trip, keep it off the delivery path
+		go s.applyLabels(event.SessionID, chat, result.Labels)
+	}
+
+	return result
+}
+
+// moveConversation moves the chat to the next state chosen by the rules. It runs before delivery so
+// the next message of the chat is evaluated in the new state.
+func (s *Service) moveConversation(ctx context.Context, sessionID, chatJID string, result *webhook.Routing) {
+	if s.states == nil {
+		result.NextState = ""
+		return
+	}
+
+	if err := s.states.Transition(ctx, sessionID, chatJID, result.NextState); err != nil {
+		s.logger.WarnWithFields("Failed to move conversation state", map[string]interface{}{
+			"session_id": sessionID,
+			"chat":       chatJID,
+			"state":      result.NextState,
+			"error":      err.Error(),
+		})
+		result.NextState = ""
+		return
+	}
+
+	s.logger.DebugWithFields("Conversation state moved", map[string]interface{}{
+		"session_id": sessionID,
+		"chat":       chatJID,
+		"from":       result.State,
+		"to":         result.NextState,
+	})
+}
+
+// LabelChat adds or removes a WhatsApp label on a chat
+func (s *Service) LabelChat(sessionID, chatJID, labelID string, labeled bool) error {
+	if s.labeler == nil {
+		return ErrLabelingDisabled
+	}
+	if chatJID == "" {
+		return ErrInvalidChatJID
+	}
+	if labelID == "" {
+		return ErrLabelIDRequired
+	}
+
+	if err := s.labeler.LabelChat(sessionID, chatJID, labelID, labeled); err != nil {
+		return err
+	}
+
+	key := appliedLabelKey(sessionID, chatJID, labelID)
+	s.appliedMu.Lock()
+	if labeled {
+		s.applied[key] = time.Now()
+	} else {
+		delete(s.applied, key)
+	}
+	s.appliedMu.Unlock()
+
+	return nil
+}
+
+// applyLabels applies the labels chosen by the rules, skipping the ones applied recently
+func (s *Service) applyLabels(sessionID, chatJID string, labelIDs []string) {
+	if s.labeler == nil {
+		return
+	}
+
+	for _, labelID := range labelIDs {
+		key := appliedLabelKey(sessionID, chatJID, labelID)
+
+		s.appliedMu.Lock()
+		appliedAt, exists := s.applied[key]
+		if exists && time.Since(appliedAt) < appliedLabelTTL {
+			s.appliedMu.Unlock()
+			continue
+		}
+		s.applied[key] = time.Now()
+		s.pruneApplied()
+		s.appliedMu.Unlock()
+
+		if err := s.labeler.LabelChat(sessionID, chatJID, labelID, true); err != nil {
+			s.appliedMu.Lock()
+			delete(s.applied, key)
+			s.appliedMu.Unlock()
+
+			s.logger.WarnWithFields("Failed to auto-label chat", map[string]interface{}{
+				"session_id": sessionID,
+				"chat":       chatJID,
+				"label_id":   labelID,
+				"error":      err.Error(),
+			})
+			continue
+		}
+
+		s.logger.InfoWithFields("Chat auto-labeled", map[string]interface{}{
+			"session_id": sessionID,
+			"chat":       chatJID,
+			"label_id":   labelID,
+		})
+	}
+}
+
+// pruneApplied drops expired entries; the caller must hold appliedMu
+func (s *Service) pruneApplied() {
+	for key, appliedAt := range s.applied {
+		if time.Since(appliedAt) >= appliedLabelTTL {
+			delete(s.applied, key)
+		}
+	}
+}
+
+func appliedLabelKey(sessionID, chatJID, labelID string) string {
+	return sessionID + "|" + chatJID + "|" + labelID
+}
+
+// rulesFor returns the session's rules sorted by priority, highest first, from the cache when fresh
+func (s *Service) rulesFor(ctx context.Context, sessionID string) ([]*Rule, error) {
+	s.mu.RLock()
+	cached, exists := s.cache[sessionID]
+	s.mu.RUnlock()
+	if exists && time.Since(cached.loadedAt) < rulesCacheTTL {
+		return cached.rules, nil
+	}
+
+	rules, err := s.repo.ListBySession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	compiled := make([]*Rule, 0, len(rules))
+	for _, rule := range rules {
+		if err := rule.Compile(); err != nil {
+			s.logger.WarnWithFields("Skipping routing rule with invalid pattern", map[string]interface{}{
+				"session_id": sessionID,
+				"rule_id":    rule.ID.String(),
+				"error":      err.Error(),
+			})
+			continue
+		}
+		compiled = append(compiled, rule)
+	}
+	rules = compiled
+
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].Priority > rules[j].Priority
+	})
+
+	s.mu.Lock()
+	s.cache[sessionID] = &cachedRules{rules: rules, loadedAt: time.Now()}
+	s.mu.Unlock()
+
+	return rules, nil
+}
+
+func (s *Service) invalidate(sessionID string) {
+	s.mu.Lock()
+	delete(s.cache, sessionID)
+	s.mu.Unlock()
+}
+
+func appendUnique(values []string, additions ...string) []string {
+	for _, addition := range additions {
+		found := false
+		for _, value := range values {
+			if value == addition {
+				found = true
+				break
+			}
+		}
+		if !found {
+			values = append(values, addition)
+		}
+	}
+	return values
+}
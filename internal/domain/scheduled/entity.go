@@ -0,0 +1,59 @@
+package scheduled
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"zpwoot/internal/domain/message"
+	"zpwoot/platform/pagination"
+)
+
+// Message is a send given a scheduleAt. The scheduler sends it once ScheduledAt has passed.
+type Message struct {
+	ID          uuid.UUID                   `json:"id"`
+	SessionID   string                      `json:"session_id"`
+	ChatJID     string                      `json:"chat_jid"`
+	Workspace   string                      `json:"workspace,omitempty"`
+	RequestID   string                      `json:"request_id,omitempty"`
+	Request     *message.SendMessageRequest `json:"request"`
+	Status      string                      `json:"status"`
+	Attempts    int                         `json:"attempts"`
+	MessageID   string                      `json:"message_id,omitempty"`
+	LastError   string                      `json:"last_error,omitempty"`
+	ScheduledAt time.Time                   `json:"scheduled_at"`
+	SentAt      *time.Time                  `json:"sent_at,omitempty"`
+	CreatedAt   time.Time                   `json:"created_at"`
+	UpdatedAt   time.Time                   `json:"updated_at"`
+}
+
+// Scheduled message statuses
+const (
+	StatusScheduled = "scheduled"
+	StatusSending   = "sending"
+	StatusSent      = "sent"
+	StatusFailed    = "failed"
+	StatusCancelled = "cancelled"
+)
+
+type ListRequest struct {
+	SessionID string `json:"session_id" validate:"required"`
+	Status    string `json:"status,omitempty" query:"status"`
+	Limit     int    `json:"limit,omitempty" query:"limit" validate:"omitempty,min=1,max=100"`
+	Offset    int    `json:"offset,omitempty" query:"offset" validate:"omitempty,min=0"`
+	// After continues the list past a cursor instead of skipping Offset rows
+	After *pagination.Cursor `json:"-"`
+}
+
+var (
+	ErrScheduledMessageNotFound = errors.New("scheduled message not found")
+	ErrScheduleInPast           = errors.New("scheduleAt must be in the future")
+	ErrScheduleTooFar           = errors.New("scheduleAt must be within a year")
+	ErrNotScheduled             = errors.New("message is no longer scheduled")
+)
+
+// IsPending reports whether the message still waits to be sent
+func (m *Message) IsPending() bool {
+	return m.Status == StatusScheduled || m.Status == StatusSending
+}
@@ -0,0 +1,296 @@
+package scheduled
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"zpwoot/internal/domain/history"
+	"zpwoot/internal/domain/maintenance"
+	"zpwoot/internal/domain/message"
+	"zpwoot/internal/domain/queue"
+	"zpwoot/internal/domain/usage"
+	"zpwoot/platform/logger"
+)
+
+// dueBatchSize limits how many messages are sent per scheduler tick
+const dueBatchSize = 50
+
+// staleClaimTimeout is how long a claimed message may go without an outcome before a later tick
+// assumes its scheduler died and sends it again
+const staleClaimTimeout = 10 * time.Minute
+
+// maxSendDelay is how late a message may still be sent; past it, a message whose session stayed
+// disconnected fails instead of arriving out of context
+const maxSendDelay = 24 * time.Hour
+
+// maxScheduleAhead bounds how far in the future a message can be scheduled
+const maxScheduleAhead = 365 * 24 * time.Hour
+
+// Repository defines the interface for scheduled message data operations
+type Repository interface {
+	Create(ctx context.Context, msg *Message) error
+	// ClaimDue marks up to limit messages due at now as sending and returns them
+	ClaimDue(ctx context.Context, now time.Time, limit int) ([]*Message, error)
+	// ReleaseStale reschedules messages claimed before claimedBefore and returns how many
+	ReleaseStale(ctx context.Context, claimedBefore time.Time) (int, error)
+	Update(ctx context.Context, msg *Message) error
+	// Reschedule and Cancel only change messages still scheduled, and report whether they did
+	Reschedule(ctx context.Context, sessionID string, id uuid.UUID, scheduledAt time.Time) (bool, error)
+	Cancel(ctx context.Context, sessionID string, id uuid.UUID) (bool, error)
+	GetByID(ctx context.Context, sessionID string, id uuid.UUID) (*Message, error)
+	List(ctx context.Context, req *ListRequest) ([]*Message, int, error)
+}
+
+// Sender sends a scheduled message through the regular send path
+type Sender interface {
+	SendQueued(ctx context.Context, sessionID string, req *message.SendMessageRequest) (*message.SendResult, error)
+}
+
+// SendGate reports whether sends are frozen for a session, e.g. during maintenance
+type SendGate interface {
+	SendFrozen(sessionID string) bool
+}
+
+// Service keeps messages given a scheduleAt and sends them once due
+type Service struct {
+	logger   *logger.Logger
+	repo     Repository
+	sender   Sender
+	sendGate SendGate
+}
+
+func NewService(logger *logger.Logger, repo Repository) *Service {
+	return &Service{
+		logger: logger,
+		repo:   repo,
+	}
+}
+
+// SetSender sets what sends the scheduled messages; it is set after creation because the sender is
+// built on top of the domain services
+func (s *Service) SetSender(sender Sender) {
+	s.sender = sender
+}
+
+// SetSendGate makes the scheduler hold back messages of sessions whose sends are frozen
+func (s *Service) SetSendGate(gate SendGate) {
+	s.sendGate = gate
+}
+
+// Schedule stores a validated send to be sent at scheduledAt
+func (s *Service) Schedule(ctx context.Context, sessionID string, req *message.SendMessageRequest, scheduledAt time.Time) (*Message, error) {
+	now := time.Now()
+	if err := validateScheduledAt(scheduledAt, now); err != nil {
+		return nil, err
+	}
+
+	msg := &Message{
+		ID:          uuid.New(),
+		SessionID:   sessionID,
+		ChatJID:     req.To,
+		Workspace:   usage.WorkspaceFromContext(ctx),
+		RequestID:   logger.RequestIDFromContext(ctx),
+		Request:     req,
+		Status:      StatusScheduled,
+		ScheduledAt: scheduledAt,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := s.repo.Create(ctx, msg); err != nil {
+		return nil, fmt.Errorf("failed to schedule message: %w", err)
+	}
+
+	s.logger.InfoWithFields("Message scheduled", map[string]interface{}{
+		"session_id":   sessionID,
+		"scheduled_id": msg.ID.String(),
+		"to":           req.To,
+		"scheduled_at": scheduledAt,
+	})
+
+	return msg, nil
+}
+
+func (s *Service) Get(ctx context.Context, sessionID string, id uuid.UUID) (*Message, error) {
+	return s.repo.GetByID(ctx, sessionID, id)
+}
+
+func (s *Service) List(ctx context.Context, req *ListRequest) ([]*Message, int, error) {
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+
+	return s.repo.List(ctx, req)
+}
+
+// Reschedule moves a message that is still scheduled to scheduledAt
+func (s *Service) Reschedule(ctx context.Context, sessionID string, id uuid.UUID, scheduledAt time.Time) (*Message, error) {
+	if err := validateScheduledAt(scheduledAt, time.Now()); err != nil {
+		return nil, err
+	}
+
+	rescheduled, err := s.repo.Reschedule(ctx, sessionID, id, scheduledAt)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := s.repo.GetByID(ctx, sessionID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !rescheduled {
+		return msg, ErrNotScheduled
+	}
+
+	s.logger.InfoWithFields("Scheduled message rescheduled", map[string]interface{}{
+		"session_id":   sessionID,
+		"scheduled_id": id.String(),
+		"scheduled_at": scheduledAt,
+	})
+
+	return msg, nil
+}
+
+// Cancel keeps a scheduled message from being sent; it reports false with the message's current state
+// when it is not scheduled anymore
+func (s *Service) Cancel(ctx context.Context, sessionID string, id uuid.UUID) (*Message, bool, error) {
+	cancelled, err := s.repo.Cancel(ctx, sessionID, id)
+	if err != nil {
+		return nil, false, err
+	}
+
+	msg, err := s.repo.GetByID(ctx, sessionID, id)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if cancelled {
+		s.logger.InfoWithFields("Scheduled message cancelled", map[string]interface{}{
+			"session_id":   sessionID,
+			"scheduled_id": id.String(),
+		})
+	}
+
+	return msg, cancelled, nil
+}
+
+// DispatchDue sends every scheduled message whose time has come
+func (s *Service) DispatchDue(ctx context.Context) error {
+	if s.sender == nil {
+		return nil
+	}
+	// Nothing can be sent while the whole instance is frozen
+	if s.sendGate != nil && s.sendGate.SendFrozen("") {
+		return nil
+	}
+
+	if released, err := s.repo.ReleaseStale(ctx, time.Now().Add(-staleClaimTimeout)); err != nil {
+		s.logger.ErrorWithFields("Failed to release stale scheduled message claims", map[string]interface{}{
+			"error": err.Error(),
+		})
+	} else if released > 0 {
+		s.logger.WarnWithFields("Scheduled message claims abandoned in sending were released", map[string]interface{}{
+			"count": released,
+		})
+	}
+
+	msgs, err := s.repo.ClaimDue(ctx, time.Now(), dueBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to claim due scheduled messages: %w", err)
+	}
+
+	sent := 0
+	for _, msg := range msgs {
+		if s.sendGate != nil && s.sendGate.SendFrozen(msg.SessionID) {
+			s.retryLater(ctx, msg, maintenance.ErrSendFrozen)
+			continue
+		}
+		if s.deliver(ctx, msg) {
+			sent++
+		}
+	}
+
+	if sent > 0 {
+		s.logger.InfoWithFields("Scheduled messages sent", map[string]interface{}{
+			"count": sent,
+		})
+	}
+	return nil
+}
+
+// schedulerActor attributes a scheduled send to the scheduled message it came from
+func schedulerActor(msg *Message) *history.Actor {
+	return &history.Actor{Type: history.ActorScheduler, ID: "scheduled:" + msg.ID.String()}
+}
+
+// deliver sends a claimed message and records the outcome; it reports whether the message was sent
+func (s *Service) deliver(ctx context.Context, msg *Message) bool {
+	sendCtx := history.WithActor(ctx, schedulerActor(msg))
+	if msg.Workspace != "" {
+		sendCtx = context.WithValue(sendCtx, usage.WorkspaceContextKey, msg.Workspace)
+	}
+	// the events of the message are traced back to the request that scheduled it
+	sendCtx = logger.WithRequestID(sendCtx, msg.RequestID)
+
+	result, err := s.sender.SendQueued(sendCtx, msg.SessionID, msg.Request)
+	switch {
+	// a maintenance freeze is held for as long as it lasts, like the freeze check before the send
+	case errors.Is(err, maintenance.ErrSendFrozen):
+		s.retryLater(ctx, msg, err)
+		return false
+	case errors.Is(err, queue.ErrSessionDisconnected) && time.Since(msg.ScheduledAt) < maxSendDelay:
+		s.retryLater(ctx, msg, err)
+		return false
+	case err != nil:
+		s.logger.WarnWithFields("Failed to send scheduled message", map[string]interface{}{
+			"session_id":   msg.SessionID,
+			"scheduled_id": msg.ID.String(),
+			"error":        err.Error(),
+		})
+		msg.Status = StatusFailed
+		msg.LastError = err.Error()
+	default:
+		sentAt := time.Now()
+		msg.Status = StatusSent
+		msg.MessageID = result.MessageID
+		msg.LastError = ""
+		msg.SentAt = &sentAt
+	}
+
+	s.update(ctx, msg)
+	return msg.Status == StatusSent
+}
+
+// retryLater returns a claimed message to the scheduler so it is tried again on the next tick, recording
+// why it was held back; it keeps its scheduled time so a late message still fails once maxSendDelay has
+// passed
+func (s *Service) retryLater(ctx context.Context, msg *Message, reason error) {
+	msg.Status = StatusScheduled
+	msg.LastError = reason.Error()
+	s.update(ctx, msg)
+}
+
+func (s *Service) update(ctx context.Context, msg *Message) {
+	if err := s.repo.Update(ctx, msg); err != nil {
+		s.logger.ErrorWithFields("Failed to record scheduled message outcome", map[string]interface{}{
+			"session_id":   msg.SessionID,
+			"scheduled_id": msg.ID.String(),
+			"status":       msg.Status,
+			"error":        err.Error(),
+		})
+	}
+}
+
+func validateScheduledAt(scheduledAt, now time.Time) error {
+	if !scheduledAt.After(now) {
+		return ErrScheduleInPast
+	}
+	if scheduledAt.Sub(now) > maxScheduleAhead {
+		return ErrScheduleTooFar
+	}
+	return nil
+}
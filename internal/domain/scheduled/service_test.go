@@ -0,0 +1,159 @@
+package scheduled
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"zpwoot/internal/domain/maintenance"
+	"zpwoot/internal/domain/message"
+	"zpwoot/internal/domain/queue"
+	"zpwoot/platform/logger"
+)
+
+type memoryRepository struct {
+	due            []*Message
+	updated        []Message
+	releasedBefore time.Time
+}
+
+func (r *memoryRepository) Create(ctx context.Context, msg *Message) error { return nil }
+
+func (r *memoryRepository) ClaimDue(ctx context.Context, now time.Time, limit int) ([]*Message, error) {
+	due := r.due
+	r.due = nil
+	return due, nil
+}
+
+func (r *memoryRepository) ReleaseStale(ctx context.Context, claimedBefore time.Time) (int, error) {
+	r.releasedBefore = claimedBefore
+	return 0, nil
+}
+
+func (r *memoryRepository) Update(ctx context.Context, msg *Message) error {
+	r.updated = append(r.updated, *msg)
+	return nil
+}
+
+func (r *memoryRepository) Reschedule(ctx context.Context, sessionID string, id uuid.UUID, scheduledAt time.Time) (bool, error) {
+	return false, nil
+}
+
+func (r *memoryRepository) Cancel(ctx context.Context, sessionID string, id uuid.UUID) (bool, error) {
+	return false, nil
+}
+
+func (r *memoryRepository) GetByID(ctx context.Context, sessionID string, id uuid.UUID) (*Message, error) {
+	return nil, ErrScheduledMessageNotFound
+}
+
+func (r *memoryRepository) List(ctx context.Context, req *ListRequest) ([]*Message, int, error) {
+	return nil, 0, nil
+}
+
+type failingSender struct {
+	err error
+}
+
+func (s failingSender) SendQueued(ctx context.Context, sessionID string, req *message.SendMessageRequest) (*message.SendResult, error) {
+	return nil, s.err
+}
+
+type frozenSessions map[string]bool
+
+func (f frozenSessions) SendFrozen(sessionID string) bool {
+	return f[sessionID]
+}
+
+func dueMessage(sessionID string, scheduledAt time.Time) *Message {
+	return &Message{
+		ID:          uuid.New(),
+		SessionID:   sessionID,
+		Request:     &message.SendMessageRequest{To: "5511999990000"},
+		Status:      StatusSending,
+		ScheduledAt: scheduledAt,
+	}
+}
+
+func TestDispatchDueRecordsWhyMessagesAreHeld(t *testing.T) {
+	tests := []struct {
+		name      string
+		frozen    frozenSessions
+		sendErr   error
+		late      bool
+		wantState string
+		wantError string
+	}{
+		{
+			name:      "frozen session",
+			frozen:    frozenSessions{"s1": true},
+			wantState: StatusScheduled,
+			wantError: maintenance.ErrSendFrozen.Error(),
+		},
+		{
+			name:      "frozen while sending",
+			sendErr:   fmt.Errorf("send: %w", maintenance.ErrSendFrozen),
+			late:      true,
+			wantState: StatusScheduled,
+			wantError: "send: " + maintenance.ErrSendFrozen.Error(),
+		},
+		{
+			name:      "disconnected session",
+			sendErr:   queue.ErrSessionDisconnected,
+			wantState: StatusScheduled,
+			wantError: queue.ErrSessionDisconnected.Error(),
+		},
+		{
+			name:      "disconnected past the send delay",
+			sendErr:   queue.ErrSessionDisconnected,
+			late:      true,
+			wantState: StatusFailed,
+			wantError: queue.ErrSessionDisconnected.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheduledAt := time.Now().Add(-time.Minute)
+			if tt.late {
+				scheduledAt = time.Now().Add(-2 * maxSendDelay)
+			}
+			repo := &memoryRepository{due: []*Message{dueMessage("s1", scheduledAt)}}
+			service := NewService(logger.New(), repo)
+			service.SetSender(failingSender{err: tt.sendErr})
+			if tt.frozen != nil {
+				service.SetSendGate(tt.frozen)
+			}
+
+			if err := service.DispatchDue(context.Background()); err != nil {
+				t.Fatalf("DispatchDue: %v", err)
+			}
+			if len(repo.updated) != 1 {
+				t.Fatalf("expected one update, got %d", len(repo.updated))
+			}
+			got := repo.updated[0]
+			if got.Status != tt.wantState || got.LastError != tt.wantError {
+				t.Errorf("got status %q error %q, want %q %q", got.Status, got.LastError, tt.wantState, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestDispatchDueReleasesAbandonedClaims(t *testing.T) {
+	repo := &memoryRepository{}
+	service := NewService(logger.New(), repo)
+	service.SetSender(failingSender{})
+
+	before := time.Now()
+	if err := service.DispatchDue(context.Background()); err != nil {
+		t.Fatalf("DispatchDue: %v", err)
+	}
+
+	cutoff := repo.releasedBefore
+	if cutoff.Before(before.Add(-staleClaimTimeout)) || cutoff.After(time.Now().Add(-staleClaimTimeout)) {
+		t.Errorf("released claims before %v, want about %v ago", cutoff, staleClaimTimeout)
+	}
+}
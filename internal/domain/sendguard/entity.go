@@ -0,0 +1,164 @@
+package sendguard
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AllowAllGroups in an allowlist allows every group
+const AllowAllGroups = "*"
+
+// groupServer is the server part of group JIDs
+const groupServer = "@g.us"
+
+// userServer is the server part of the JIDs of phone numbers
+const userServer = "@s.whatsapp.net"
+
+// Violation reasons
+const (
+	ReasonGroupNotAllowed = "group_not_allowed"
+	// ReasonSandboxRejected is recorded when a sandboxed session sends to a recipient outside the sandbox
+	ReasonSandboxRejected = "sandbox_recipient_not_allowed"
+	// ReasonSandboxRedirected is recorded when such a send went to the sandbox redirect recipient instead
+	ReasonSandboxRedirected = "sandbox_redirected"
+)
+
+// Config is the guard applied to every session; sends to groups are only blocked when BlockGroups is set
+type Config struct {
+	BlockGroups bool
+	// AllowedGroups are the group JIDs every session may send to
+	AllowedGroups []string
+
+	// Sandbox restricts the sends of every session to SandboxRecipients, as on staging deployments
+	Sandbox bool
+	// SandboxRecipients are the phone numbers or JIDs every sandboxed session may send to
+	SandboxRecipients []string
+	// SandboxRedirectTo receives the sends to other recipients; when empty they are rejected
+	SandboxRedirectTo string
+}
+
+// Policy is the send policy of a session
+type Policy struct {
+	SessionID string `json:"session_id"`
+	// AllowedGroups are the group JIDs the session may send to on top of the global allowlist
+	AllowedGroups []string `json:"allowed_groups"`
+	// Sandbox restricts the sends of the session to the sandbox recipients, even when the global
+	// sandbox is off
+	Sandbox bool `json:"sandbox"`
+	// SandboxRecipients are the recipient JIDs the session may send to on top of the global ones
+	SandboxRecipients []string `json:"sandbox_recipients"`
+	// SandboxRedirectTo overrides the global redirect recipient of the session
+	SandboxRedirectTo string    `json:"sandbox_redirect_to,omitempty"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// Violation is a send the guard blocked, kept for auditing
+type Violation struct {
+	ID        uuid.UUID `json:"id"`
+	SessionID string    `json:"session_id"`
+	Recipient string    `json:"recipient"`
+	Reason    string    `json:"reason"`
+	// RedirectedTo is the recipient a sandboxed send went to instead
+	RedirectedTo string    `json:"redirected_to,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// UpdatePolicyRequest replaces the fields that are set
+type UpdatePolicyRequest struct {
+	AllowedGroups     *[]string `json:"allowed_groups,omitempty"`
+	Sandbox           *bool     `json:"sandbox,omitempty"`
+	SandboxRecipients *[]string `json:"sandbox_recipients,omitempty"`
+	SandboxRedirectTo *string   `json:"sandbox_redirect_to,omitempty"`
+}
+
+var (
+	ErrGroupSendBlocked = errors.New("sending to this group is not allowed")
+	ErrInvalidGroupJID  = errors.New("allowed groups must be group JIDs (ending in @g.us) or *")
+
+	ErrSandboxRecipientBlocked = errors.New("recipient is not allowed while the session is in sandbox mode")
+	ErrInvalidSandboxRecipient = errors.New("sandbox recipients must be phone numbers or JIDs")
+)
+
+// IsGroupJID reports whether the recipient is a group
+func IsGroupJID(jid string) bool {
+	return strings.HasSuffix(jid, groupServer)
+}
+
+// NormalizeGroups trims the group JIDs and drops duplicates; it fails on anything that is not a group JID or *
+func NormalizeGroups(groups []string) ([]string, error) {
+	normalized := make([]string, 0, len(groups))
+	seen := make(map[string]bool, len(groups))
+	for _, group := range groups {
+		group = strings.ToLower(strings.TrimSpace(group))
+		if group != AllowAllGroups && !IsGroupJID(group) {
+			return nil, ErrInvalidGroupJID
+		}
+		if !seen[group] {
+			seen[group] = true
+			normalized = append(normalized, group)
+		}
+	}
+	return normalized, nil
+}
+
+// NormalizeRecipient turns a phone number or JID into the JID sends to it are addressed to, e.g.
+// "+55 11 99999-9999" into "5511999999999@s.whatsapp.net"
+func NormalizeRecipient(recipient string) (string, error) {
+	recipient = strings.ToLower(strings.TrimSpace(recipient))
+	if strings.Contains(recipient, "@") {
+		user, server, _ := strings.Cut(recipient, "@")
+		if user == "" || server == "" {
+			return "", ErrInvalidSandboxRecipient
+		}
+		return recipient, nil
+	}
+
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, recipient)
+	if digits == "" || strings.TrimLeft(recipient, "+0123456789 ()-.") != "" {
+		return "", ErrInvalidSandboxRecipient
+	}
+	return digits + userServer, nil
+}
+
+// NormalizeRecipients normalizes every recipient and drops duplicates
+func NormalizeRecipients(recipients []string) ([]string, error) {
+	normalized := make([]string, 0, len(recipients))
+	seen := make(map[string]bool, len(recipients))
+	for _, recipient := range recipients {
+		jid, err := NormalizeRecipient(recipient)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[jid] {
+			seen[jid] = true
+			normalized = append(normalized, jid)
+		}
+	}
+	return normalized, nil
+}
+
+func containsRecipient(recipients []string, recipient string) bool {
+	for _, allowed := range recipients {
+		if strings.EqualFold(allowed, recipient) {
+			return true
+		}
+	}
+	return false
+}
+
+func allowsGroup(allowlist []string, group string) bool {
+	for _, allowed := range allowlist {
+		if allowed == AllowAllGroups || strings.EqualFold(allowed, group) {
+			return true
+		}
+	}
+	return false
+}
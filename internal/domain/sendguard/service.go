@@ -0,0 +1,236 @@
+package sendguard
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"zpwoot/platform/logger"
+)
+
+// policyCacheTTL bounds how long policy changes made on another replica take to apply here
+const policyCacheTTL = 30 * time.Second
+
+// violationTimeout bounds the recording of a violation, which must not hold the send error back
+const violationTimeout = 5 * time.Second
+
+type Repository interface {
+	// GetBySession returns nil when the session has no send policy
+	GetBySession(ctx context.Context, sessionID string) (*Policy, error)
+	Upsert(ctx context.Context, policy *Policy) error
+	CreateViolation(ctx context.Context, violation *Violation) error
+	// ListViolations returns the session's most recent violations first
+	ListViolations(ctx context.Context, sessionID string, limit int) ([]*Violation, error)
+}
+
+// Service guards the recipients sessions may send to. With BlockGroups set, sends to groups fail with
+// ErrGroupSendBlocked unless the group is allowed globally or by the session's policy, so a buggy
+// integration cannot spam groups. Sandboxed sessions only send to the sandbox recipients, anything
+// else goes to the redirect recipient or fails with ErrSandboxRecipientBlocked, so a staging deployment
+// cannot message real customers. Every blocked or redirected send is recorded as a violation.
+type Service struct {
+	logger *logger.Logger
+	repo   Repository
+	config Config
+
+	mu    sync.Mutex
+	cache map[string]*cachedPolicy
+}
+
+type cachedPolicy struct {
+	policy   *Policy
+	loadedAt time.Time
+}
+
+func NewService(logger *logger.Logger, repo Repository, config Config) *Service {
+	config.AllowedGroups, _ = NormalizeGroups(config.AllowedGroups)
+	config.SandboxRecipients, _ = NormalizeRecipients(config.SandboxRecipients)
+	if config.SandboxRedirectTo != "" {
+		config.SandboxRedirectTo, _ = NormalizeRecipient(config.SandboxRedirectTo)
+	}
+
+	return &Service{
+		logger: logger,
+		repo:   repo,
+		config: config,
+		cache:  make(map[string]*cachedPolicy),
+	}
+}
+
+// BlocksGroups reports whether sends to groups need to be allowed
+func (s *Service) BlocksGroups() bool {
+	return s.config.BlockGroups
+}
+
+// GlobalAllowedGroups returns the groups every session may send to
+func (s *Service) GlobalAllowedGroups() []string {
+	return s.config.AllowedGroups
+}
+
+// GlobalSandbox returns the sandbox applied to every session
+func (s *Service) GlobalSandbox() (enabled bool, recipients []string, redirectTo string) {
+	return s.config.Sandbox, s.config.SandboxRecipients, s.config.SandboxRedirectTo
+}
+
+// GetPolicy returns the session's send policy; sessions without one get an empty policy
+func (s *Service) GetPolicy(ctx context.Context, sessionID string) (*Policy, error) {
+	policy, err := s.repo.GetBySession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		policy = &Policy{SessionID: sessionID, AllowedGroups: []string{}, SandboxRecipients: []string{}}
+	}
+	return policy, nil
+}
+
+func (s *Service) UpdatePolicy(ctx context.Context, sessionID string, req *UpdatePolicyRequest) (*Policy, error) {
+	policy, err := s.GetPolicy(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.AllowedGroups != nil {
+		groups, err := NormalizeGroups(*req.AllowedGroups)
+		if err != nil {
+			return nil, err
+		}
+		policy.AllowedGroups = groups
+	}
+	if req.Sandbox != nil {
+		policy.Sandbox = *req.Sandbox
+	}
+	if req.SandboxRecipients != nil {
+		recipients, err := NormalizeRecipients(*req.SandboxRecipients)
+		if err != nil {
+			return nil, err
+		}
+		policy.SandboxRecipients = recipients
+	}
+	if req.SandboxRedirectTo != nil {
+		policy.SandboxRedirectTo = ""
+		if *req.SandboxRedirectTo != "" {
+			redirectTo, err := NormalizeRecipient(*req.SandboxRedirectTo)
+			if err != nil {
+				return nil, err
+			}
+			policy.SandboxRedirectTo = redirectTo
+		}
+	}
+
+	policy.UpdatedAt = time.Now()
+	if err := s.repo.Upsert(ctx, policy); err != nil {
+		return nil, err
+	}
+
+	s.invalidate(sessionID)
+	return policy, nil
+}
+
+// CheckSend returns the JID the session's send to the recipient JID goes to, which differs from the
+// recipient when a sandbox redirects it. Sends the session may not make fail with ErrGroupSendBlocked or
+// ErrSandboxRecipientBlocked; they are recorded as violations, as are redirects.
+func (s *Service) CheckSend(ctx context.Context, sessionID, recipient string) (string, error) {
+	policy := s.sessionPolicy(ctx, sessionID)
+
+	if s.config.BlockGroups && IsGroupJID(recipient) && !allowsGroup(s.config.AllowedGroups, recipient) {
+		if policy == nil || !allowsGroup(policy.AllowedGroups, recipient) {
+			s.recordViolation(sessionID, recipient, ReasonGroupNotAllowed, "")
+			return "", ErrGroupSendBlocked
+		}
+	}
+
+	return s.checkSandbox(sessionID, recipient, policy)
+}
+
+// checkSandbox applies the global sandbox and the session's sandbox to the send
+func (s *Service) checkSandbox(sessionID, recipient string, policy *Policy) (string, error) {
+	sandboxed := s.config.Sandbox || (policy != nil && policy.Sandbox)
+	if !sandboxed || containsRecipient(s.config.SandboxRecipients, recipient) {
+		return recipient, nil
+	}
+	if policy != nil && containsRecipient(policy.SandboxRecipients, recipient) {
+		return recipient, nil
+	}
+
+	redirectTo := s.config.SandboxRedirectTo
+	if policy != nil && policy.SandboxRedirectTo != "" {
+		redirectTo = policy.SandboxRedirectTo
+	}
+	if redirectTo == "" {
+		s.recordViolation(sessionID, recipient, ReasonSandboxRejected, "")
+		return "", ErrSandboxRecipientBlocked
+	}
+
+	s.recordViolation(sessionID, recipient, ReasonSandboxRedirected, redirectTo)
+	return redirectTo, nil
+}
+
+func (s *Service) ListViolations(ctx context.Context, sessionID string, limit int) ([]*Violation, error) {
+	return s.repo.ListViolations(ctx, sessionID, limit)
+}
+
+func (s *Service) recordViolation(sessionID, recipient, reason, redirectedTo string) {
+	s.logger.WarnWithFields("Send guard violation", map[string]interface{}{
+		"session_id":    sessionID,
+		"recipient":     recipient,
+		"reason":        reason,
+		"redirected_to": redirectedTo,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), violationTimeout)
+	defer cancel()
+
+	violation := &Violation{
+		ID:           uuid.New(),
+		SessionID:    sessionID,
+		Recipient:    recipient,
+		Reason:       reason,
+		RedirectedTo: redirectedTo,
+		CreatedAt:    time.Now(),
+	}
+	if err := s.repo.CreateViolation(ctx, violation); err != nil {
+		s.logger.ErrorWithFields("Failed to record send guard violation", map[string]interface{}{
+			"session_id": sessionID,
+			"recipient":  recipient,
+			"error":      err.Error(),
+		})
+	}
+}
+
+// sessionPolicy returns the session's policy from the cache when fresh, or nil when it has none. When
+// the policy cannot be loaded the last one seen is used, and without one only global allowances apply.
+func (s *Service) sessionPolicy(ctx context.Context, sessionID string) *Policy {
+	s.mu.Lock()
+	cached, exists := s.cache[sessionID]
+	s.mu.Unlock()
+	if exists && time.Since(cached.loadedAt) < policyCacheTTL {
+		return cached.policy
+	}
+
+	policy, err := s.repo.GetBySession(ctx, sessionID)
+	if err != nil {
+		s.logger.WarnWithFields("Failed to load send policy", map[string]interface{}{
+			"session_id": sessionID,
+			"error":      err.Error(),
+		})
+		if exists {
+			return cached.policy
+		}
+		return nil
+	}
+
+	s.mu.Lock()
+	s.cache[sessionID] = &cachedPolicy{policy: policy, loadedAt: time.Now()}
+	s.mu.Unlock()
+
+	return policy
+}
+
+func (s *Service) invalidate(sessionID string) {
+	s.mu.Lock()
+	delete(s.cache, sessionID)
+	s.mu.Unlock()
+}
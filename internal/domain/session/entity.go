@@ -2,9 +2,13 @@ package session
 
 import (
 	"errors"
+	"fmt"
+	"net"
 	"time"
 
 	"github.com/google/uuid"
+
+	"zpwoot/platform/pagination"
 )
 
 type Session struct {
@@ -20,6 +24,7 @@ type Session struct {
 	UpdatedAt       time.Time    `json:"updatedAt" db:"updated_at"`
 	ConnectedAt     *time.Time   `json:"connectedAt,omitempty" db:"connected_at"`
 	LastSeen        *time.Time   `json:"lastSeen,omitempty" db:"last_seen"`
+	Warning         *Warning     `json:"warning,omitempty" db:"warning"`
 }
 
 type SessionInfo struct {
@@ -27,6 +32,70 @@ type SessionInfo struct {
 	DeviceInfo *DeviceInfo `json:"deviceInfo,omitempty"`
 }
 
+// Phone sync states of a connected session
+const (
+	SyncStateSynced          = "synced"
+	SyncStateWaitingForPhone = "waiting_for_phone"
+)
+
+// Reasons a connected session waits for its phone
+const (
+	// SyncReasonAppStateKeys: the phone has not shared the app state keys yet, usually after pairing
+	SyncReasonAppStateKeys = "app_state_keys"
+	// SyncReasonMessageResend: messages could not be decrypted and only the phone can resend them
+	SyncReasonMessageResend = "message_resend"
+)
+
+// SyncState tells whether a connected session is waiting for its phone to come online, which holds
+// back decryption and delivery until it does
+type SyncState struct {
+	State           string     `json:"state"`
+	Reasons         []string   `json:"reasons,omitempty"`
+	PendingMessages int        `json:"pendingMessages,omitempty"`
+	WaitingSince    *time.Time `json:"waitingSince,omitempty"`
+}
+
+// States of the reconnect supervisor for a session
+const (
+	ReconnectStateIdle         = "idle"
+	ReconnectStateWaiting      = "waiting"
+	ReconnectStateReconnecting = "reconnecting"
+	ReconnectStateConnected    = "connected"
+	// ReconnectStateGaveUp: the attempts ran out; the session stays disconnected until connected again
+	ReconnectStateGaveUp = "gave_up"
+)
+
+// ReconnectState tells how the reconnect supervisor is bringing a dropped session back
+type ReconnectState struct {
+	State          string     `json:"state"`
+	Reason         string     `json:"reason,omitempty"`
+	Attempts       int        `json:"attempts"`
+	LastError      string     `json:"lastError,omitempty"`
+	DisconnectedAt *time.Time `json:"disconnectedAt,omitempty"`
+	NextAttemptAt  *time.Time `json:"nextAttemptAt,omitempty"`
+	ConnectedAt    *time.Time `json:"connectedAt,omitempty"`
+}
+
+// Kinds of account warnings WhatsApp reports for a session
+const (
+	// WarningTemporaryBan: the account is banned until ExpiresAt, usually for spam-like sending
+	WarningTemporaryBan = "temporary_ban"
+	// WarningBanned: WhatsApp logged the device out for a ban
+	WarningBanned = "banned"
+	// WarningRestricted: WhatsApp refused the connection with an account violation notice
+	WarningRestricted = "restricted"
+)
+
+// Warning is an account violation or ban warning WhatsApp sent for a session. It is kept on the
+// session until an operator clears it, as an early signal before a full ban.
+type Warning struct {
+	Kind       string     `json:"kind"`
+	Code       int        `json:"code"`
+	Reason     string     `json:"reason,omitempty"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	ReceivedAt time.Time  `json:"receivedAt"`
+}
+
 type DeviceInfo struct {
 	Platform    string `json:"platform"`
 	DeviceModel string `json:"device_model"`
@@ -48,6 +117,7 @@ var (
 	ErrSessionAlreadyExists = errors.New("session already exists")
 	ErrInvalidSessionStatus = errors.New("invalid session status")
 	ErrSessionNotConnected  = errors.New("session not connected")
+	ErrInvalidProxyConfig   = errors.New("invalid proxy configuration")
 )
 
 // @name ProxyConfig
@@ -57,6 +127,40 @@ type ProxyConfig struct {
 	Port     int    `json:"port" db:"proxy_port" example:"8080"`
 	Username string `json:"username,omitempty" db:"proxy_username" example:"user"`
 	Password string `json:"password,omitempty" db:"proxy_password" example:"password"`
+	// LocalAddress or Interface binds the session's WhatsApp and media connections to a local source
+	// address, connecting directly or through a socks5 proxy
+	LocalAddress string `json:"localAddress,omitempty" db:"proxy_local_address" example:"203.0.113.10"`
+	Interface    string `json:"interface,omitempty" db:"proxy_interface" example:"eth1"`
+}
+
+// Validate checks the proxy settings; an empty Type connects directly, which binds a session to a
+// local address or interface without a proxy
+func (p *ProxyConfig) Validate() error {
+	switch p.Type {
+	case "":
+	case "http", "https", "socks5":
+		if p.Host == "" || p.Port <= 0 || p.Port > 65535 {
+			return fmt.Errorf("%w: proxy host and port are required", ErrInvalidProxyConfig)
+		}
+	default:
+		return fmt.Errorf("%w: unsupported proxy type %q", ErrInvalidProxyConfig, p.Type)
+	}
+
+	if p.LocalAddress != "" && p.Interface != "" {
+		return fmt.Errorf("%w: set either localAddress or interface, not both", ErrInvalidProxyConfig)
+	}
+	if p.LocalAddress != "" && net.ParseIP(p.LocalAddress) == nil {
+		return fmt.Errorf("%w: localAddress must be an IP address", ErrInvalidProxyConfig)
+	}
+	if p.HasBinding() && p.Type != "" && p.Type != "socks5" {
+		return fmt.Errorf("%w: localAddress and interface only apply to direct or socks5 connections", ErrInvalidProxyConfig)
+	}
+	return nil
+}
+
+// HasBinding reports whether connections are bound to a local address or interface
+func (p *ProxyConfig) HasBinding() bool {
+	return p.LocalAddress != "" || p.Interface != ""
 }
 
 type CreateSessionRequest struct {
@@ -75,6 +179,8 @@ type ListSessionsRequest struct {
 	DeviceJid   *string `json:"deviceJid,omitempty" query:"deviceJid"`
 	Limit       int     `json:"limit,omitempty" query:"limit" validate:"omitempty,min=1,max=100"`
 	Offset      int     `json:"offset,omitempty" query:"offset" validate:"omitempty,min=0"`
+	// After continues the list past a cursor instead of skipping Offset rows
+	After *pagination.Cursor `json:"-"`
 }
 
 type PairPhoneRequest struct {
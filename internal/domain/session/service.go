@@ -2,10 +2,12 @@ package session
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"zpwoot/pkg/errors"
 	"zpwoot/pkg/uuid"
+	"zpwoot/platform/logger"
 )
 
 type Service struct {
@@ -13,6 +15,12 @@ type Service struct {
 	Wameow      WameowManager
 	generator   *uuid.Generator
 	qrGenerator QRGenerator
+	logger      *logger.Logger
+
+	stalePolicy  StalePolicy
+	dispatcher   EventDispatcher
+	staleMu      sync.Mutex
+	staleHandled map[string]bool
 }
 
 type QRGenerator interface {
@@ -33,6 +41,8 @@ type WameowManager interface {
 	ConnectSession(sessionID string) error
 	DisconnectSession(sessionID string) error
 	LogoutSession(sessionID string) error
+	RestartSession(sessionID string) error
+	RemoveSession(sessionID string) error
 	GetQRCode(sessionID string) (*QRCodeResponse, error)
 	PairPhone(sessionID, phoneNumber string) error
 	IsConnected(sessionID string) bool
@@ -41,17 +51,25 @@ type WameowManager interface {
 	GetProxy(sessionID string) (*ProxyConfig, error)
 }
 
-func NewService(repo Repository, Wameow WameowManager, qrGenerator QRGenerator) *Service {
+func NewService(repo Repository, Wameow WameowManager, qrGenerator QRGenerator, logger *logger.Logger) *Service {
 	return &Service{
-		repo:        repo,
-		Wameow:      Wameow,
-		generator:   uuid.New(),
-		qrGenerator: qrGenerator,
+		repo:         repo,
+		Wameow:       Wameow,
+		generator:    uuid.New(),
+		qrGenerator:  qrGenerator,
+		logger:       logger,
+		staleHandled: make(map[string]bool),
 	}
 }
 
 func (s *Service) CreateSession(ctx context.Context, req *CreateSessionRequest) (*Session, error) {
 
+	if req.ProxyConfig != nil {
+		if err := req.ProxyConfig.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
 	session := NewSession(req.Name)
 	session.ProxyConfig = req.ProxyConfig
 
@@ -120,16 +138,15 @@ func (s *Service) DeleteSession(ctx context.Context, id string) error {
 		return errors.ErrNotFound
 	}
 
-	if session.IsActive() {
-		if err := s.Wameow.DisconnectSession(id); err != nil {
-			_ = err // Explicitly ignore error
-		}
-	}
-
 	if err := s.repo.Delete(ctx, id); err != nil {
 		return errors.Wrap(err, "failed to delete session")
 	}
 
+	// Disconnects the client and frees its QR state, event handlers and statistics
+	if err := s.Wameow.RemoveSession(id); err != nil {
+		_ = err // Explicitly ignore error
+	}
+
 	return nil
 }
 
@@ -186,6 +203,24 @@ func (s *Service) LogoutSession(ctx context.Context, id string) error {
 	return nil
 }
 
+// RestartSession recreates the session's WhatsApp client from its stored credentials
+func (s *Service) RestartSession(ctx context.Context, id string) error {
+	session, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return errors.Wrap(err, "failed to get session")
+	}
+
+	if session == nil {
+		return errors.ErrNotFound
+	}
+
+	if err := s.Wameow.RestartSession(id); err != nil {
+		return errors.Wrap(err, "failed to restart Wameow client")
+	}
+
+	return nil
+}
+
 func (s *Service) GetQRCode(ctx context.Context, id string) (*QRCodeResponse, error) {
 	session, err := s.repo.GetByID(ctx, id)
 	if err != nil {
@@ -246,6 +281,10 @@ func (s *Service) PairPhone(ctx context.Context, id string, req *PairPhoneReques
 }
 
 func (s *Service) SetProxy(ctx context.Context, id string, config *ProxyConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
 	session, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return errors.Wrap(err, "failed to get session")
@@ -268,6 +307,30 @@ func (s *Service) SetProxy(ctx context.Context, id string, config *ProxyConfig)
 	return nil
 }
 
+// UpdateProxy changes the session's proxy and reconnects the session so the new settings take effect
+// right away. A paired session reconnects even when it is offline, as the old proxy may be what kept it
+// from connecting. It reports whether the session was reconnected.
+func (s *Service) UpdateProxy(ctx context.Context, id string, config *ProxyConfig) (bool, error) {
+	if err := s.SetProxy(ctx, id, config); err != nil {
+		return false, err
+	}
+
+	session, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get session")
+	}
+
+	if session == nil || (session.DeviceJid == "" && !s.Wameow.IsConnected(id)) {
+		return false, nil
+	}
+
+	if err := s.Wameow.ConnectSession(id); err != nil {
+		return false, errors.Wrap(err, "failed to reconnect with the new proxy")
+	}
+
+	return true, nil
+}
+
 func (s *Service) GetProxy(ctx context.Context, id string) (*ProxyConfig, error) {
 	session, err := s.repo.GetByID(ctx, id)
 	if err != nil {
@@ -0,0 +1,164 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"zpwoot/internal/domain/webhook"
+	"zpwoot/platform/pagination"
+)
+
+// StaleEventType is the webhook event sent once when a session becomes stale
+const StaleEventType = "session.stale"
+
+// staleSweepPageSize is how many sessions the stale sweep loads per query
+const staleSweepPageSize = 100
+
+// What the stale policy does with a session that has been inactive for too long
+const (
+	// StaleActionFlag only reports the session as stale in listings
+	StaleActionFlag = "flag"
+	// StaleActionNotify also sends a session.stale webhook event
+	StaleActionNotify = "notify"
+	// StaleActionDisconnect also sends the event and disconnects the session, freeing its linked device slot
+	StaleActionDisconnect = "disconnect"
+)
+
+var ErrInvalidStaleAction = errors.New("invalid stale session action")
+
+// StalePolicy flags sessions without activity for longer than After; a zero After disables it
+type StalePolicy struct {
+	After  time.Duration
+	Action string
+}
+
+// Validate checks the policy action
+func (p StalePolicy) Validate() error {
+	switch p.Action {
+	case StaleActionFlag, StaleActionNotify, StaleActionDisconnect:
+		return nil
+	default:
+		return ErrInvalidStaleAction
+	}
+}
+
+// Enabled reports whether sessions are ever flagged as stale
+func (p StalePolicy) Enabled() bool {
+	return p.After > 0
+}
+
+// IsStale reports whether the session has been inactive for longer than the policy allows at now
+func (p StalePolicy) IsStale(s *Session, now time.Time) bool {
+	return p.Enabled() && now.Sub(s.LastActivity()) > p.After
+}
+
+// LastActivity returns when the session last received a message or connected, or when it was created
+func (s *Session) LastActivity() time.Time {
+	last := s.CreatedAt
+	if s.ConnectedAt != nil && s.ConnectedAt.After(last) {
+		last = *s.ConnectedAt
+	}
+	if s.LastSeen != nil && s.LastSeen.After(last) {
+		last = *s.LastSeen
+	}
+	return last
+}
+
+// EventDispatcher sends session.stale events to the session's and the global webhooks
+type EventDispatcher interface {
+	DeliverEvent(ctx context.Context, event *webhook.WebhookEvent) error
+}
+
+// SetStalePolicy sets how sessions without activity are handled; dispatcher may be nil when no
+// events should be sent
+func (s *Service) SetStalePolicy(policy StalePolicy, dispatcher EventDispatcher) {
+	s.stalePolicy = policy
+	s.dispatcher = dispatcher
+}
+
+// IsStale reports whether the stale policy flags the session
+func (s *Service) IsStale(sess *Session) bool {
+	return s.stalePolicy.IsStale(sess, time.Now())
+}
+
+// SweepStaleSessions notifies about and disconnects the sessions that became stale since the last sweep.
+// Sessions are handled once per process; one that becomes active again is handled again next time it goes stale.
+func (s *Service) SweepStaleSessions(ctx context.Context) error {
+	now := time.Now()
+	req := &ListSessionsRequest{Limit: staleSweepPageSize}
+
+	for {
+		sessions, _, err := s.repo.List(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		for _, sess := range sessions {
+			id := sess.ID.String()
+			if !s.stalePolicy.IsStale(sess, now) {
+				s.staleMu.Lock()
+				delete(s.staleHandled, id)
+				s.staleMu.Unlock()
+				continue
+			}
+
+			s.staleMu.Lock()
+			handled := s.staleHandled[id]
+			s.staleHandled[id] = true
+			s.staleMu.Unlock()
+			if !handled {
+				s.handleStaleSession(ctx, sess, now)
+			}
+		}
+
+		if len(sessions) < staleSweepPageSize {
+			return nil
+		}
+		last := sessions[len(sessions)-1]
+		req.After = &pagination.Cursor{CreatedAt: last.CreatedAt, ID: last.ID.String()}
+	}
+}
+
+// handleStaleSession applies the policy action to a session that just became stale
+func (s *Service) handleStaleSession(ctx context.Context, sess *Session, now time.Time) {
+	id := sess.ID.String()
+	lastActivity := sess.LastActivity()
+
+	s.logger.WarnWithFields("Session is stale", map[string]interface{}{
+		"session_id":    id,
+		"last_activity": lastActivity,
+		"action":        s.stalePolicy.Action,
+	})
+
+	disconnected := false
+	if s.stalePolicy.Action == StaleActionDisconnect && sess.IsConnected {
+		if err := s.Wameow.DisconnectSession(id); err != nil {
+			s.logger.ErrorWithFields("Failed to disconnect stale session", map[string]interface{}{
+				"session_id": id,
+				"error":      err.Error(),
+			})
+		} else {
+			disconnected = true
+		}
+	}
+
+	if s.stalePolicy.Action == StaleActionFlag || s.dispatcher == nil {
+		return
+	}
+
+	event := webhook.NewWebhookEvent(id, StaleEventType, map[string]interface{}{
+		"sessionId":       id,
+		"name":            sess.Name,
+		"lastActivity":    lastActivity,
+		"inactiveSeconds": int64(now.Sub(lastActivity).Seconds()),
+		"action":          s.stalePolicy.Action,
+		"disconnected":    disconnected,
+	})
+	if err := s.dispatcher.DeliverEvent(ctx, event); err != nil {
+		s.logger.WarnWithFields("Failed to send stale session event", map[string]interface{}{
+			"session_id": id,
+			"error":      err.Error(),
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package settings
+
+import (
+	"errors"
+	"regexp"
+	"time"
+)
+
+// Setting keys; feature flags are stored as flag.<name>
+const (
+	KeyLogLevel         = "log_level"
+	KeyDebugBodyLogging = "debug_body_logging"
+	FlagKeyPrefix       = "flag."
+)
+
+var flagNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]{0,62}$`)
+
+var (
+	ErrInvalidLogLevel = errors.New("invalid log level")
+	ErrInvalidFlagName = errors.New("invalid feature flag name")
+	ErrNothingToUpdate = errors.New("no settings to update")
+)
+
+// Setting is one persisted runtime setting
+type Setting struct {
+	Key       string    `json:"key" db:"key"`
+	Value     string    `json:"value" db:"value"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Settings are the runtime settings in effect
+type Settings struct {
+	LogLevel         string          `json:"log_level"`
+	DebugBodyLogging bool            `json:"debug_body_logging"`
+	Flags            map[string]bool `json:"flags"`
+	UpdatedAt        *time.Time      `json:"updated_at,omitempty"`
+}
+
+// UpdateRequest changes the given settings; nil fields and omitted flags are left as they are
+type UpdateRequest struct {
+	LogLevel         *string         `json:"log_level,omitempty"`
+	DebugBodyLogging *bool           `json:"debug_body_logging,omitempty"`
+	Flags            map[string]bool `json:"flags,omitempty"`
+}
+
+// IsValidFlagName reports whether name can be used as a feature flag
+func IsValidFlagName(name string) bool {
+	return flagNamePattern.MatchString(name)
+}
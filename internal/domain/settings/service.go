@@ -0,0 +1,179 @@
+package settings
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"zpwoot/platform/logger"
+)
+
+// Repository defines the interface for runtime setting data operations
+type Repository interface {
+	List(ctx context.Context) ([]*Setting, error)
+	Upsert(ctx context.Context, settings []*Setting) error
+	LastUpdated(ctx context.Context) (*time.Time, error)
+}
+
+// Service applies runtime settings without a restart. Settings are persisted, and every replica
+// polls for changes so they all converge on the same values.
+type Service struct {
+	logger       *logger.Logger
+	settingsRepo Repository
+	defaultLevel string
+
+	mu       sync.RWMutex
+	current  Settings
+	lastSeen *time.Time
+}
+
+// NewService creates the settings service; defaultLevel is used while no log level is persisted
+func NewService(logger *logger.Logger, settingsRepo Repository, defaultLevel string) *Service {
+	return &Service{
+		logger:       logger,
+		settingsRepo: settingsRepo,
+		defaultLevel: defaultLevel,
+		current: Settings{
+			LogLevel: defaultLevel,
+			Flags:    make(map[string]bool),
+		},
+	}
+}
+
+// Load reads the persisted settings and applies them
+func (s *Service) Load(ctx context.Context) error {
+	stored, err := s.settingsRepo.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load settings: %w", err)
+	}
+
+	next := Settings{
+		LogLevel: s.defaultLevel,
+		Flags:    make(map[string]bool),
+	}
+	for _, setting := range stored {
+		switch {
+		case setting.Key == KeyLogLevel:
+			next.LogLevel = setting.Value
+		case setting.Key == KeyDebugBodyLogging:
+			next.DebugBodyLogging, _ = strconv.ParseBool(setting.Value)
+		case strings.HasPrefix(setting.Key, FlagKeyPrefix):
+			next.Flags[strings.TrimPrefix(setting.Key, FlagKeyPrefix)], _ = strconv.ParseBool(setting.Value)
+		}
+		if next.UpdatedAt == nil || setting.UpdatedAt.After(*next.UpdatedAt) {
+			updatedAt := setting.UpdatedAt
+			next.UpdatedAt = &updatedAt
+		}
+	}
+
+	s.apply(next)
+	return nil
+}
+
+// Update validates, persists and applies the given settings
+func (s *Service) Update(ctx context.Context, req *UpdateRequest) (*Settings, error) {
+	var changes []*Setting
+
+	if req.LogLevel != nil {
+		level := strings.ToLower(*req.LogLevel)
+		if !logger.IsValidLevel(level) {
+			return nil, ErrInvalidLogLevel
+		}
+		changes = append(changes, &Setting{Key: KeyLogLevel, Value: level})
+	}
+
+	if req.DebugBodyLogging != nil {
+		changes = append(changes, &Setting{Key: KeyDebugBodyLogging, Value: strconv.FormatBool(*req.DebugBodyLogging)})
+	}
+
+	for name, enabled := range req.Flags {
+		if !IsValidFlagName(name) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidFlagName, name)
+		}
+		changes = append(changes, &Setting{Key: FlagKeyPrefix + name, Value: strconv.FormatBool(enabled)})
+	}
+
+	if len(changes) == 0 {
+		return nil, ErrNothingToUpdate
+	}
+
+	if err := s.settingsRepo.Upsert(ctx, changes); err != nil {
+		return nil, fmt.Errorf("failed to save settings: %w", err)
+	}
+
+	if err := s.Load(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.Current(), nil
+}
+
+// Current returns a copy of the settings in effect
+func (s *Service) Current() *Settings {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	current := s.current
+	current.Flags = make(map[string]bool, len(s.current.Flags))
+	for name, enabled := range s.current.Flags {
+		current.Flags[name] = enabled
+	}
+	return &current
+}
+
+// DebugBodyLogging reports whether HTTP request and response bodies are logged
+func (s *Service) DebugBodyLogging() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.current.DebugBodyLogging
+}
+
+// FlagEnabled reports whether a feature flag is on; unknown flags are off
+func (s *Service) FlagEnabled(name string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.current.Flags[name]
+}
+
+// Sync reloads the settings when another replica changed them
+func (s *Service) Sync(ctx context.Context) error {
+	lastUpdated, err := s.settingsRepo.LastUpdated(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check settings for changes: %w", err)
+	}
+
+	s.mu.RLock()
+	changed := lastUpdated != nil && (s.lastSeen == nil || lastUpdated.After(*s.lastSeen))
+	s.mu.RUnlock()
+
+	if !changed {
+		return nil
+	}
+
+	if err := s.Load(ctx); err != nil {
+		return fmt.Errorf("failed to reload settings: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) apply(next Settings) {
+	s.mu.Lock()
+	previous := s.current
+	s.current = next
+	s.lastSeen = next.UpdatedAt
+	s.mu.Unlock()
+
+	if next.LogLevel != logger.Level() {
+		logger.SetLevel(next.LogLevel)
+	}
+
+	s.logger.InfoWithFields("Runtime settings applied", map[string]interface{}{
+		"log_level":          next.LogLevel,
+		"debug_body_logging": next.DebugBodyLogging,
+		"flags":              next.Flags,
+		"previous_log_level": previous.LogLevel,
+	})
+}
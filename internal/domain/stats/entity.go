@@ -0,0 +1,133 @@
+package stats
+
+import (
+	"errors"
+	"time"
+)
+
+// Message directions
+const (
+	DirectionInbound  = "inbound"
+	DirectionOutbound = "outbound"
+)
+
+const (
+	dayLayout = "2006-01-02"
+
+	// DefaultRangeDays is the range reported when the request gives no start day
+	DefaultRangeDays = 30
+	// MaxRangeDays bounds the range of a single report
+	MaxRangeDays = 366
+)
+
+var (
+	ErrInvalidDay   = errors.New("days must be formatted as YYYY-MM-DD")
+	ErrInvalidRange = errors.New("from must not be after to")
+	ErrRangeTooLong = errors.New("date range exceeds 366 days")
+)
+
+// DailyCount is the number of messages of one type sent or received by a session in one day
+type DailyCount struct {
+	SessionID string    `json:"session_id" db:"sessionId"`
+	Day       string    `json:"day" db:"day"`
+	Type      string    `json:"type" db:"type"`
+	Direction string    `json:"direction" db:"direction"`
+	Count     int64     `json:"count" db:"count"`
+	UpdatedAt time.Time `json:"updated_at" db:"updatedAt"`
+}
+
+// TypeTotal is the number of messages of one type in a report range
+type TypeTotal struct {
+	Type     string `json:"type"`
+	Inbound  int64  `json:"inbound"`
+	Outbound int64  `json:"outbound"`
+}
+
+// DayTotal is the number of messages of every type in one day of a report range
+type DayTotal struct {
+	Day      string       `json:"day"`
+	Inbound  int64        `json:"inbound"`
+	Outbound int64        `json:"outbound"`
+	ByType   []*TypeTotal `json:"by_type"`
+}
+
+// Report is the message breakdown of a session between two days, both included
+type Report struct {
+	SessionID string       `json:"session_id"`
+	From      string       `json:"from"`
+	To        string       `json:"to"`
+	Inbound   int64        `json:"inbound"`
+	Outbound  int64        `json:"outbound"`
+	ByType    []*TypeTotal `json:"by_type"`
+	Days      []*DayTotal  `json:"days"` // Only days with messages, oldest first
+}
+
+type ReportRequest struct {
+	SessionID string `json:"session_id"`
+	From      string `json:"from,omitempty"`
+	To        string `json:"to,omitempty"`
+}
+
+// Day returns the day a message at t is counted in
+func Day(t time.Time) string {
+	return t.UTC().Format(dayLayout)
+}
+
+// Direction returns the direction of a message; messages sent from the phone count as outbound
+func Direction(fromMe bool) string {
+	if fromMe {
+		return DirectionOutbound
+	}
+	return DirectionInbound
+}
+
+// resolveRange fills in the defaults of a report range and checks it
+func (r *ReportRequest) resolveRange(now time.Time) (string, string, error) {
+	to := now.UTC()
+	if r.To != "" {
+		parsed, err := time.Parse(dayLayout, r.To)
+		if err != nil {
+			return "", "", ErrInvalidDay
+		}
+		to = parsed
+	}
+
+	from := to.AddDate(0, 0, -(DefaultRangeDays - 1))
+	if r.From != "" {
+		parsed, err := time.Parse(dayLayout, r.From)
+		if err != nil {
+			return "", "", ErrInvalidDay
+		}
+		from = parsed
+	}
+
+	fromDay, toDay := from.Format(dayLayout), to.Format(dayLayout)
+	if fromDay > toDay {
+		return "", "", ErrInvalidRange
+	}
+	if to.Sub(from) >= MaxRangeDays*24*time.Hour {
+		return "", "", ErrRangeTooLong
+	}
+
+	return fromDay, toDay, nil
+}
+
+// add counts a daily count in the totals of a type
+func (t *TypeTotal) add(count *DailyCount) {
+	if count.Direction == DirectionOutbound {
+		t.Outbound += count.Count
+	} else {
+		t.Inbound += count.Count
+	}
+}
+
+// typeTotal returns the total of msgType in totals, appending it when missing
+func typeTotal(totals []*TypeTotal, msgType string) ([]*TypeTotal, *TypeTotal) {
+	for _, total := range totals {
+		if total.Type == msgType {
+			return totals, total
+		}
+	}
+	total := &TypeTotal{Type: msgType}
+	return append(totals, total), total
+}
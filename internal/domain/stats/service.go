@@ -0,0 +1,105 @@
+package stats
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"zpwoot/platform/logger"
+)
+
+// Repository defines the interface for daily message statistics data operations
+type Repository interface {
+	Add(ctx context.Context, sessionID, day, msgType, direction string, delta int64) error
+	List(ctx context.Context, sessionID, from, to string) ([]*DailyCount, error)
+}
+
+// Service keeps the daily message counters of every session by type and direction
+type Service struct {
+	logger    *logger.Logger
+	statsRepo Repository
+}
+
+func NewService(logger *logger.Logger, statsRepo Repository) *Service {
+	return &Service{
+		logger:    logger,
+		statsRepo: statsRepo,
+	}
+}
+
+// RecordMessage counts a message sent or received at the given time; failures are logged since
+// statistics never fail a send or an event
+func (s *Service) RecordMessage(ctx context.Context, sessionID, msgType string, fromMe bool, at time.Time) {
+	direction := Direction(fromMe)
+	day := Day(at)
+	if err := s.statsRepo.Add(ctx, sessionID, day, msgType, direction, 1); err != nil {
+		s.logger.WarnWithFields("Failed to record message statistics", map[string]interface{}{
+			"session_id": sessionID,
+			"type":       msgType,
+			"direction":  direction,
+			"day":        day,
+			"error":      err.Error(),
+		})
+	}
+}
+
+// GetReport aggregates the daily counters of a session; the range defaults to the last 30 days
+func (s *Service) GetReport(ctx context.Context, req *ReportRequest) (*Report, error) {
+	from, to, err := req.resolveRange(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.statsRepo.List(ctx, req.SessionID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		SessionID: req.SessionID,
+		From:      from,
+		To:        to,
+		ByType:    []*TypeTotal{},
+		Days:      []*DayTotal{},
+	}
+
+	days := make(map[string]*DayTotal)
+	for _, count := range counts {
+		if count.Direction == DirectionOutbound {
+			report.Outbound += count.Count
+		} else {
+			report.Inbound += count.Count
+		}
+
+		var total *TypeTotal
+		report.ByType, total = typeTotal(report.ByType, count.Type)
+		total.add(count)
+
+		day, exists := days[count.Day]
+		if !exists {
+			day = &DayTotal{Day: count.Day}
+			days[count.Day] = day
+			report.Days = append(report.Days, day)
+		}
+		if count.Direction == DirectionOutbound {
+			day.Outbound += count.Count
+		} else {
+			day.Inbound += count.Count
+		}
+		day.ByType, total = typeTotal(day.ByType, count.Type)
+		total.add(count)
+	}
+
+	sort.Slice(report.ByType, func(i, j int) bool {
+		a, b := report.ByType[i], report.ByType[j]
+		if a.Inbound+a.Outbound != b.Inbound+b.Outbound {
+			return a.Inbound+a.Outbound > b.Inbound+b.Outbound
+		}
+		return a.Type < b.Type
+	})
+	sort.Slice(report.Days, func(i, j int) bool {
+		return report.Days[i].Day < report.Days[j].Day
+	})
+
+	return report, nil
+}
@@ -0,0 +1,108 @@
+package translation
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// AutoLanguage lets the provider detect the language of incoming messages
+const AutoLanguage = "auto"
+
+// Config is the translation setup of a session. SourceLanguage is the language contacts write in
+// and TargetLanguage the one operators read: incoming messages are translated from source to
+// target before webhook delivery, outgoing ones from target to source before they are sent.
+type Config struct {
+	SessionID      string    `json:"session_id"`
+	Enabled        bool      `json:"enabled"`
+	Inbound        bool      `json:"inbound"`
+	Outbound       bool      `json:"outbound"`
+	SourceLanguage string    `json:"source_language"`
+	TargetLanguage string    `json:"target_language"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// UpdateConfigRequest changes the fields that are set
+type UpdateConfigRequest struct {
+	Enabled        *bool   `json:"enabled,omitempty"`
+	Inbound        *bool   `json:"inbound,omitempty"`
+	Outbound       *bool   `json:"outbound,omitempty"`
+	SourceLanguage *string `json:"source_language,omitempty"`
+	TargetLanguage *string `json:"target_language,omitempty"`
+}
+
+// Result is a translated text together with the text it replaced
+type Result struct {
+	Text             string `json:"text"`
+	OriginalText     string `json:"original_text"`
+	SourceLanguage   string `json:"source_language"`
+	TargetLanguage   string `json:"target_language"`
+	DetectedLanguage string `json:"detected_language,omitempty"`
+}
+
+var (
+	ErrInvalidLanguage        = errors.New("language must be a language code such as \"en\" or \"pt-BR\"")
+	ErrSameLanguage           = errors.New("source and target languages must differ")
+	ErrOutboundNeedsSource    = errors.New("outbound translation needs an explicit source language")
+	ErrDirectionRequired      = errors.New("enable inbound or outbound translation")
+	ErrTranslationUnavailable = errors.New("translation provider is not configured")
+)
+
+var languagePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z]{2,4})?$`)
+
+// NewDefaultConfig returns the disabled configuration sessions start with
+func NewDefaultConfig(sessionID string) *Config {
+	return &Config{
+		SessionID:      sessionID,
+		Inbound:        true,
+		SourceLanguage: AutoLanguage,
+	}
+}
+
+func (c *Config) normalize() {
+	c.SourceLanguage = strings.TrimSpace(c.SourceLanguage)
+	c.TargetLanguage = strings.TrimSpace(c.TargetLanguage)
+	if c.SourceLanguage == "" {
+		c.SourceLanguage = AutoLanguage
+	}
+}
+
+// Validate checks the languages; a disabled configuration only needs well formed ones
+func (c *Config) Validate() error {
+	if c.SourceLanguage != AutoLanguage && !languagePattern.MatchString(c.SourceLanguage) {
+		return ErrInvalidLanguage
+	}
+	if c.TargetLanguage != "" && !languagePattern.MatchString(c.TargetLanguage) {
+		return ErrInvalidLanguage
+	}
+
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.TargetLanguage == "" {
+		return ErrInvalidLanguage
+	}
+	if !c.Inbound && !c.Outbound {
+		return ErrDirectionRequired
+	}
+	if c.Outbound && c.SourceLanguage == AutoLanguage {
+		return ErrOutboundNeedsSource
+	}
+	if strings.EqualFold(c.SourceLanguage, c.TargetLanguage) {
+		return ErrSameLanguage
+	}
+
+	return nil
+}
+
+// TranslatesInbound reports whether incoming messages are translated
+func (c *Config) TranslatesInbound() bool {
+	return c.Enabled && c.Inbound
+}
+
+// TranslatesOutbound reports whether outgoing messages are translated
+func (c *Config) TranslatesOutbound() bool {
+	return c.Enabled && c.Outbound
+}
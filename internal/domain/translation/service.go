@@ -0,0 +1,243 @@
+package translation
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"zpwoot/internal/domain/webhook"
+	"zpwoot/platform/logger"
+)
+
+// configCacheTTL bounds how long configuration changes made on another replica take to apply here
+const configCacheTTL = 30 * time.Second
+
+const messageEventType = "Message"
+
+type Repository interface {
+	// GetBySession returns nil when the session has no translation configuration
+	GetBySession(ctx context.Context, sessionID string) (*Config, error)
+	Upsert(ctx context.Context, config *Config) error
+}
+
+// Translator translates text through an external provider; source may be AutoLanguage, in which
+// case the detected language is returned
+type Translator interface {
+	Translate(ctx context.Context, text, source, target string) (translated, detected string, err error)
+}
+
+// Service manages per-session translation settings and translates message text with them
+type Service struct {
+	logger     *logger.Logger
+	repo       Repository
+	translator Translator
+
+	mu    sync.RWMutex
+	cache map[string]*cachedConfig
+}
+
+type cachedConfig struct {
+	config   *Config
+	loadedAt time.Time
+}
+
+// NewService creates the translation service; a nil translator disables translation
+func NewService(logger *logger.Logger, repo Repository, translator Translator) *Service {
+	return &Service{
+		logger:     logger,
+		repo:       repo,
+		translator: translator,
+		cache:      make(map[string]*cachedConfig),
+	}
+}
+
+// Available reports whether a translation provider is configured
+func (s *Service) Available() bool {
+	return s.translator != nil
+}
+
+// GetConfig returns the session's configuration, or the disabled default when it has none
+func (s *Service) GetConfig(ctx context.Context, sessionID string) (*Config, error) {
+	config, err := s.repo.GetBySession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		return NewDefaultConfig(sessionID), nil
+	}
+	return config, nil
+}
+
+func (s *Service) UpdateConfig(ctx context.Context, sessionID string, req *UpdateConfigRequest) (*Config, error) {
+	config, err := s.GetConfig(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Enabled != nil {
+		config.Enabled = *req.Enabled
+	}
+	if req.Inbound != nil {
+		config.Inbound = *req.Inbound
+	}
+	if req.Outbound != nil {
+		config.Outbound = *req.Outbound
+	}
+	if req.SourceLanguage != nil {
+		config.SourceLanguage = *req.SourceLanguage
+	}
+	if req.TargetLanguage != nil {
+		config.TargetLanguage = *req.TargetLanguage
+	}
+
+	config.normalize()
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	if config.Enabled && s.translator == nil {
+		return nil, ErrTranslationUnavailable
+	}
+
+	config.UpdatedAt = time.Now()
+	if err := s.repo.Upsert(ctx, config); err != nil {
+		return nil, err
+	}
+
+	s.invalidate(sessionID)
+	return config, nil
+}
+
+// TranslateInbound translates text a contact sent into the operators' language; it returns nil
+// when translation is off for the session, no provider is configured or the text is empty
+func (s *Service) TranslateInbound(ctx context.Context, sessionID, text string) (*Result, error) {
+	if s.translator == nil {
+		return nil, nil
+	}
+	config := s.activeConfig(ctx, sessionID)
+	if config == nil || !config.TranslatesInbound() {
+		return nil, nil
+	}
+	return s.translate(ctx, text, config.SourceLanguage, config.TargetLanguage)
+}
+
+// TranslateOutbound translates text written by an operator into the contacts' language; it returns
+// nil when translation is off for the session, no provider is configured or the text is empty
+func (s *Service) TranslateOutbound(ctx context.Context, sessionID, text string) (*Result, error) {
+	if s.translator == nil {
+		return nil, nil
+	}
+	config := s.activeConfig(ctx, sessionID)
+	if config == nil || !config.TranslatesOutbound() {
+		return nil, nil
+	}
+	return s.translate(ctx, text, config.TargetLanguage, config.SourceLanguage)
+}
+
+// ProcessWebhookEvent translates the text and caption of incoming message events in place and
+// records the originals on the event; it lets the service be added as a webhook event processor.
+// Events are delivered untranslated when the provider fails.
+func (s *Service) ProcessWebhookEvent(ctx context.Context, event *webhook.WebhookEvent) error {
+	if s.translator == nil || event.Type != messageEventType || event.SessionID == "" || event.Data == nil {
+		return nil
+	}
+	if fromMe, _ := event.Data["from_me"].(bool); fromMe {
+		return nil
+	}
+
+	text, _ := event.Data["text"].(string)
+	caption, _ := event.Data["caption"].(string)
+	if strings.TrimSpace(text) == "" && strings.TrimSpace(caption) == "" {
+		return nil
+	}
+
+	// Translate both fields before touching the event so a failure leaves it as it was
+	results := make(map[string]*Result, 2)
+	for _, field := range []string{"text", "caption"} {
+		original, _ := event.Data[field].(string)
+		result, err := s.TranslateInbound(ctx, event.SessionID, original)
+		if err != nil {
+			s.logger.WarnWithFields("Failed to translate incoming message, delivering original text", map[string]interface{}{
+				"session_id": event.SessionID,
+				"event_id":   event.ID,
+				"error":      err.Error(),
+			})
+			return nil
+		}
+		if result != nil {
+			results[field] = result
+		}
+	}
+	if len(results) == 0 {
+		return nil
+	}
+
+	info := &webhook.Translation{}
+	for field, result := range results {
+		info.SourceLanguage = result.SourceLanguage
+		info.TargetLanguage = result.TargetLanguage
+		if result.DetectedLanguage != "" {
+			info.DetectedLanguage = result.DetectedLanguage
+		}
+		if field == "text" {
+			info.OriginalText = result.OriginalText
+		} else {
+			info.OriginalCaption = result.OriginalText
+		}
+		event.Data[field] = result.Text
+	}
+	event.Translation = info
+
+	return nil
+}
+
+func (s *Service) translate(ctx context.Context, text, source, target string) (*Result, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+
+	translated, detected, err := s.translator.Translate(ctx, text, source, target)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		Text:             translated,
+		OriginalText:     text,
+		SourceLanguage:   source,
+		TargetLanguage:   target,
+		DetectedLanguage: detected,
+	}, nil
+}
+
+// activeConfig returns the session's configuration from the cache when fresh, or nil when it
+// cannot be loaded
+func (s *Service) activeConfig(ctx context.Context, sessionID string) *Config {
+	s.mu.RLock()
+	cached, exists := s.cache[sessionID]
+	s.mu.RUnlock()
+	if exists && time.Since(cached.loadedAt) < configCacheTTL {
+		return cached.config
+	}
+
+	config, err := s.GetConfig(ctx, sessionID)
+	if err != nil {
+		s.logger.WarnWithFields("Failed to load translation configuration", map[string]interface{}{
+			"session_id": sessionID,
+			"error":      err.Error(),
+		})
+		return nil
+	}
+
+	s.mu.Lock()
+	s.cache[sessionID] = &cachedConfig{config: config, loadedAt: time.Now()}
+	s.mu.Unlock()
+
+	return config
+}
+
+func (s *Service) invalidate(sessionID string) {
+	s.mu.Lock()
+	delete(s.cache, sessionID)
+	s.mu.Unlock()
+}
@@ -0,0 +1,86 @@
+package usage
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// DefaultWorkspace is the workspace usage is accounted to when the request carries none
+const DefaultWorkspace = "default"
+
+// WorkspaceContextKey is the request value holding the workspace of the caller.
+// It is a plain string so values stored with fiber's Locals are visible through the request context.
+const WorkspaceContextKey = "workspace"
+
+// Usage metrics
+const (
+	MetricMessages   = "messages"
+	MetricMediaBytes = "media_bytes"
+)
+
+// Period layouts; message quotas are daily and media quotas monthly
+const (
+	dayLayout   = "2006-01-02"
+	monthLayout = "2006-01"
+)
+
+var (
+	ErrMessageQuotaExceeded = errors.New("daily message quota exceeded")
+	ErrMediaQuotaExceeded   = errors.New("monthly media quota exceeded")
+	ErrSessionQuotaExceeded = errors.New("session quota exceeded")
+)
+
+// Limits are the quotas applied to every workspace; zero means unlimited
+type Limits struct {
+	MessagesPerDay  int64 `json:"messages_per_day"`
+	MediaMBPerMonth int64 `json:"media_mb_per_month"`
+	MaxSessions     int   `json:"max_sessions"`
+}
+
+// Counter is the value of one usage metric of a workspace in one period
+type Counter struct {
+	Workspace string    `json:"workspace" db:"workspace"`
+	Metric    string    `json:"metric" db:"metric"`
+	Period    string    `json:"period" db:"period"`
+	Value     int64     `json:"value" db:"value"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Usage is the current consumption of a workspace against its limits
+type Usage struct {
+	Workspace       string `json:"workspace"`
+	Day             string `json:"day"`
+	Month           string `json:"month"`
+	MessagesToday   int64  `json:"messages_today"`
+	MediaBytesMonth int64  `json:"media_bytes_month"`
+	Sessions        int    `json:"sessions"`
+	Limits          Limits `json:"limits"`
+}
+
+type ListCountersRequest struct {
+	Workspace string `json:"workspace,omitempty" query:"workspace"`
+	Metric    string `json:"metric,omitempty" query:"metric"`
+	From      string `json:"from,omitempty" query:"from"`
+	To        string `json:"to,omitempty" query:"to"`
+}
+
+// DayPeriod returns the daily period the given time is accounted to
+func DayPeriod(t time.Time) string {
+	return t.UTC().Format(dayLayout)
+}
+
+// MonthPeriod returns the monthly period the given time is accounted to
+func MonthPeriod(t time.Time) string {
+	return t.UTC().Format(monthLayout)
+}
+
+// WorkspaceFromContext returns the workspace of the request, falling back to the default workspace
+func WorkspaceFromContext(ctx context.Context) string {
+	if ctx != nil {
+		if workspace, ok := ctx.Value(WorkspaceContextKey).(string); ok && workspace != "" {
+			return workspace
+		}
+	}
+	return DefaultWorkspace
+}
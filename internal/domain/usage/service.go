@@ -0,0 +1,153 @@
+package usage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"zpwoot/platform/logger"
+)
+
+const bytesPerMB = 1024 * 1024
+
+// Repository defines the interface for usage counter data operations
+type Repository interface {
+	Add(ctx context.Context, workspace, metric, period string, delta int64) error
+	Get(ctx context.Context, workspace, metric, period string) (int64, error)
+	List(ctx context.Context, req *ListCountersRequest) ([]*Counter, error)
+	CountSessions(ctx context.Context) (int, error)
+}
+
+// Service enforces workspace quotas and keeps the usage counters used for billing.
+// Quotas are checked before an operation and usage recorded once it succeeded, so concurrent
+// requests may overshoot a limit by the number of operations in flight.
+type Service struct {
+	logger    *logger.Logger
+	usageRepo Repository
+	limits    Limits
+}
+
+func NewService(logger *logger.Logger, usageRepo Repository, limits Limits) *Service {
+	return &Service{
+		logger:    logger,
+		usageRepo: usageRepo,
+		limits:    limits,
+	}
+}
+
+func (s *Service) Limits() Limits {
+	return s.limits
+}
+
+// CheckMessage fails when the workspace already sent its daily message quota
+func (s *Service) CheckMessage(ctx context.Context, workspace string) error {
+	if s.limits.MessagesPerDay <= 0 {
+		return nil
+	}
+
+	sent, err := s.usageRepo.Get(ctx, workspace, MetricMessages, DayPeriod(time.Now()))
+	if err != nil {
+		return fmt.Errorf("failed to check message quota: %w", err)
+	}
+
+	if sent >= s.limits.MessagesPerDay {
+		return ErrMessageQuotaExceeded
+	}
+
+	return nil
+}
+
+// CheckMedia fails when sending size more bytes of media would exceed the monthly media quota
+func (s *Service) CheckMedia(ctx context.Context, workspace string, size int64) error {
+	if s.limits.MediaMBPerMonth <= 0 {
+		return nil
+	}
+
+	used, err := s.usageRepo.Get(ctx, workspace, MetricMediaBytes, MonthPeriod(time.Now()))
+	if err != nil {
+		return fmt.Errorf("failed to check media quota: %w", err)
+	}
+
+	if used+size > s.limits.MediaMBPerMonth*bytesPerMB {
+		return ErrMediaQuotaExceeded
+	}
+
+	return nil
+}
+
+// CheckSessions fails when no more sessions may be created.
+// Sessions are not assigned to workspaces yet, so every session counts against the limit.
+func (s *Service) CheckSessions(ctx context.Context, workspace string) error {
+	if s.limits.MaxSessions <= 0 {
+		return nil
+	}
+
+	count, err := s.usageRepo.CountSessions(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check session quota: %w", err)
+	}
+
+	if count >= s.limits.MaxSessions {
+		s.logger.WarnWithFields("Session quota exceeded", map[string]interface{}{
+			"workspace":    workspace,
+			"sessions":     count,
+			"max_sessions": s.limits.MaxSessions,
+		})
+		return ErrSessionQuotaExceeded
+	}
+
+	return nil
+}
+
+// RecordMessage counts a sent message; failures are logged since the message already went out
+func (s *Service) RecordMessage(ctx context.Context, workspace string) {
+	s.record(ctx, workspace, MetricMessages, DayPeriod(time.Now()), 1)
+}
+
+// RecordMedia counts size bytes of sent media
+func (s *Service) RecordMedia(ctx context.Context, workspace string, size int64) {
+	if size <= 0 {
+		return
+	}
+	s.record(ctx, workspace, MetricMediaBytes, MonthPeriod(time.Now()), size)
+}
+
+func (s *Service) record(ctx context.Context, workspace, metric, period string, delta int64) {
+	if err := s.usageRepo.Add(ctx, workspace, metric, period, delta); err != nil {
+		s.logger.WarnWithFields("Failed to record usage", map[string]interface{}{
+			"workspace": workspace,
+			"metric":    metric,
+			"period":    period,
+			"error":     err.Error(),
+		})
+	}
+}
+
+// GetUsage returns the current day and month consumption of a workspace
+func (s *Service) GetUsage(ctx context.Context, workspace string) (*Usage, error) {
+	now := time.Now()
+	usage := &Usage{
+		Workspace: workspace,
+		Day:       DayPeriod(now),
+		Month:     MonthPeriod(now),
+		Limits:    s.limits,
+	}
+
+	var err error
+	if usage.MessagesToday, err = s.usageRepo.Get(ctx, workspace, MetricMessages, usage.Day); err != nil {
+		return nil, err
+	}
+	if usage.MediaBytesMonth, err = s.usageRepo.Get(ctx, workspace, MetricMediaBytes, usage.Month); err != nil {
+		return nil, err
+	}
+	if usage.Sessions, err = s.usageRepo.CountSessions(ctx); err != nil {
+		return nil, err
+	}
+
+	return usage, nil
+}
+
+// ListCounters returns the stored usage counters, optionally filtered by workspace, metric and period range
+func (s *Service) ListCounters(ctx context.Context, req *ListCountersRequest) ([]*Counter, error) {
+	return s.usageRepo.List(ctx, req)
+}
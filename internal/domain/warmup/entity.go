@@ -0,0 +1,156 @@
+package warmup
+
+import (
+	"errors"
+	"time"
+)
+
+// DefaultSchedule is the daily send cap of each warm-up day; the warm-up completes after the last day
+var DefaultSchedule = []int{20, 40, 80, 150, 250, 400, 600}
+
+// Default random delay between two sends of a warming up session
+const (
+	DefaultMinDelay = 3 * time.Second
+	DefaultMaxDelay = 10 * time.Second
+)
+
+// maxDelay bounds the configurable delay so a send request cannot hang for minutes
+const maxDelay = 2 * time.Minute
+
+// Profile is the warm-up of a newly paired number: sends per day are capped by Schedule, counted
+// from the day StartedAt falls on, and consecutive sends are spaced by a random delay
+type Profile struct {
+	SessionID string        `json:"session_id"`
+	Enabled   bool          `json:"enabled"`
+	StartedAt time.Time     `json:"started_at"`
+	Schedule  []int         `json:"schedule"`
+	MinDelay  time.Duration `json:"min_delay"`
+	MaxDelay  time.Duration `json:"max_delay"`
+	// SentDay and SentCount count the sends of the current day (YYYY-MM-DD, UTC)
+	SentDay   string    `json:"sent_day"`
+	SentCount int       `json:"sent_count"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Status is the warm-up state of a session on a given day
+type Status struct {
+	Enabled   bool          `json:"enabled"`
+	Completed bool          `json:"completed"`
+	StartedAt *time.Time    `json:"started_at,omitempty"`
+	Day       int           `json:"day"`
+	TotalDays int           `json:"total_days"`
+	DailyCap  int           `json:"daily_cap"`
+	SentToday int           `json:"sent_today"`
+	Remaining int           `json:"remaining"`
+	MinDelay  time.Duration `json:"min_delay"`
+	MaxDelay  time.Duration `json:"max_delay"`
+	Schedule  []int         `json:"schedule"`
+}
+
+// UpdateProfileRequest changes the fields that are set; Restart starts the schedule over from today
+type UpdateProfileRequest struct {
+	Enabled  *bool          `json:"enabled,omitempty"`
+	Restart  bool           `json:"restart,omitempty"`
+	Schedule *[]int         `json:"schedule,omitempty"`
+	MinDelay *time.Duration `json:"min_delay,omitempty"`
+	MaxDelay *time.Duration `json:"max_delay,omitempty"`
+}
+
+var (
+	ErrDailyCapReached = errors.New("warm-up daily send cap reached")
+	ErrInvalidSchedule = errors.New("warm-up schedule needs at least one day and positive caps")
+	ErrInvalidDelay    = errors.New("warm-up delays must be between 0 and 2m and min must not exceed max")
+)
+
+// NewProfile returns a warm-up starting now with the default schedule and delays
+func NewProfile(sessionID string, now time.Time) *Profile {
+	schedule := make([]int, len(DefaultSchedule))
+	copy(schedule, DefaultSchedule)
+
+	return &Profile{
+		SessionID: sessionID,
+		Enabled:   true,
+		StartedAt: now,
+		Schedule:  schedule,
+		MinDelay:  DefaultMinDelay,
+		MaxDelay:  DefaultMaxDelay,
+	}
+}
+
+func (p *Profile) Validate() error {
+	if len(p.Schedule) == 0 {
+		return ErrInvalidSchedule
+	}
+	for _, dailyCap := range p.Schedule {
+		if dailyCap <= 0 {
+			return ErrInvalidSchedule
+		}
+	}
+	if p.MinDelay < 0 || p.MaxDelay < 0 || p.MaxDelay > maxDelay || p.MinDelay > p.MaxDelay {
+		return ErrInvalidDelay
+	}
+	return nil
+}
+
+// Day returns the 1-based warm-up day at now
+func (p *Profile) Day(now time.Time) int {
+	start := dayStart(p.StartedAt)
+	return int(dayStart(now).Sub(start)/(24*time.Hour)) + 1
+}
+
+// DailyCap returns the send cap at now, or 0 when the warm-up is disabled or completed
+func (p *Profile) DailyCap(now time.Time) int {
+	if !p.Enabled {
+		return 0
+	}
+	day := p.Day(now)
+	if day < 1 || day > len(p.Schedule) {
+		return 0
+	}
+	return p.Schedule[day-1]
+}
+
+// Completed reports whether every day of the schedule has passed
+func (p *Profile) Completed(now time.Time) bool {
+	return p.Day(now) > len(p.Schedule)
+}
+
+// SentOn returns the number of sends counted on the given day
+func (p *Profile) SentOn(day string) int {
+	if p.SentDay != day {
+		return 0
+	}
+	return p.SentCount
+}
+
+// Status returns the warm-up state at now
+func (p *Profile) Status(now time.Time) *Status {
+	status := &Status{
+		Enabled:   p.Enabled,
+		Completed: p.Completed(now),
+		TotalDays: len(p.Schedule),
+		DailyCap:  p.DailyCap(now),
+		SentToday: p.SentOn(DayKey(now)),
+		MinDelay:  p.MinDelay,
+		MaxDelay:  p.MaxDelay,
+		Schedule:  p.Schedule,
+	}
+	if !p.StartedAt.IsZero() {
+		startedAt := p.StartedAt
+		status.StartedAt = &startedAt
+		status.Day = p.Day(now)
+	}
+	if status.DailyCap > 0 && status.SentToday < status.DailyCap {
+		status.Remaining = status.DailyCap - status.SentToday
+	}
+	return status
+}
+
+// DayKey identifies the UTC day sends are counted on
+func DayKey(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
+
+func dayStart(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
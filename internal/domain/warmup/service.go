@@ -0,0 +1,230 @@
+package warmup
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"zpwoot/platform/logger"
+)
+
+// profileCacheTTL bounds how long profile changes made on another replica take to apply here
+const profileCacheTTL = 30 * time.Second
+
+type Repository interface {
+	// GetBySession returns nil when the session has no warm-up profile
+	GetBySession(ctx context.Context, sessionID string) (*Profile, error)
+	Upsert(ctx context.Context, profile *Profile) error
+	// IncrementSent counts one send on day unless the day already reached dailyCap; it reports the
+	// day's count and whether the send was counted
+	IncrementSent(ctx context.Context, sessionID, day string, dailyCap int) (int, bool, error)
+}
+
+// Service enforces the warm-up of newly paired numbers: a daily send cap growing with the days since
+// pairing, and a random delay between consecutive sends of the session
+type Service struct {
+	logger     *logger.Logger
+	repo       Repository
+	autoEnable bool
+
+	mu         sync.Mutex
+	cache      map[string]*cachedProfile
+	nextSendAt map[string]time.Time
+}
+
+type cachedProfile struct {
+	profile  *Profile
+	loadedAt time.Time
+}
+
+// NewService creates the warm-up service; with autoEnable every newly paired session starts a
+// warm-up with the default profile
+func NewService(logger *logger.Logger, repo Repository, autoEnable bool) *Service {
+	return &Service{
+		logger:     logger,
+		repo:       repo,
+		autoEnable: autoEnable,
+		cache:      make(map[string]*cachedProfile),
+		nextSendAt: make(map[string]time.Time),
+	}
+}
+
+// GetStatus returns the session's warm-up state; sessions without a profile report it disabled
+func (s *Service) GetStatus(ctx context.Context, sessionID string) (*Status, error) {
+	profile, err := s.repo.GetBySession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		profile = NewProfile(sessionID, time.Time{})
+		profile.Enabled = false
+	}
+	return profile.Status(time.Now()), nil
+}
+
+func (s *Service) UpdateProfile(ctx context.Context, sessionID string, req *UpdateProfileRequest) (*Status, error) {
+	now := time.Now()
+
+	profile, err := s.repo.GetBySession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		profile = NewProfile(sessionID, now)
+		profile.Enabled = false
+	}
+
+	if req.Enabled != nil {
+		// Turning the warm-up on starts it over, a schedule left behind would skip the early days
+		if *req.Enabled && !profile.Enabled {
+			req.Restart = true
+		}
+		profile.Enabled = *req.Enabled
+	}
+	if req.Schedule != nil {
+		profile.Schedule = *req.Schedule
+	}
+	if req.MinDelay != nil {
+		profile.MinDelay = *req.MinDelay
+	}
+	if req.MaxDelay != nil {
+		profile.MaxDelay = *req.MaxDelay
+	}
+	if req.Restart {
+		profile.StartedAt = now
+		profile.SentDay = ""
+		profile.SentCount = 0
+	}
+
+	if err := profile.Validate(); err != nil {
+		return nil, err
+	}
+
+	profile.UpdatedAt = now
+	if err := s.repo.Upsert(ctx, profile); err != nil {
+		return nil, err
+	}
+
+	s.invalidate(sessionID)
+	return profile.Status(now), nil
+}
+
+// SessionPaired starts a warm-up for a session that paired a new number, when new sessions warm up
+// by default
+func (s *Service) SessionPaired(sessionID string) {
+	if !s.autoEnable {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	profile := NewProfile(sessionID, now)
+	profile.UpdatedAt = now
+	if err := s.repo.Upsert(ctx, profile); err != nil {
+		s.logger.ErrorWithFields("Failed to start warm-up for paired session", map[string]interface{}{
+			"session_id": sessionID,
+			"error":      err.Error(),
+		})
+		return
+	}
+
+	s.invalidate(sessionID)
+	s.logger.InfoWithFields("Warm-up started for paired session", map[string]interface{}{
+		"session_id": sessionID,
+		"days":       len(profile.Schedule),
+	})
+}
+
+// Acquire counts a send against the session's daily cap and returns how long the caller must wait
+// before sending. It returns ErrDailyCapReached when the cap is exhausted; sessions that are not
+// warming up send immediately.
+func (s *Service) Acquire(ctx context.Context, sessionID string) (time.Duration, error) {
+	profile := s.activeProfile(ctx, sessionID)
+	if profile == nil {
+		return 0, nil
+	}
+
+	now := time.Now()
+	dailyCap := profile.DailyCap(now)
+	if dailyCap <= 0 {
+		return 0, nil
+	}
+
+	count, counted, err := s.repo.IncrementSent(ctx, sessionID, DayKey(now), dailyCap)
+	if err != nil {
+		// The cap protects the number, not the API: a counter outage must not stop sending
+		s.logger.WarnWithFields("Failed to count warm-up send", map[string]interface{}{
+			"session_id": sessionID,
+			"error":      err.Error(),
+		})
+	} else if !counted {
+		s.logger.WarnWithFields("Warm-up daily send cap reached", map[string]interface{}{
+			"session_id": sessionID,
+			"day":        profile.Day(now),
+			"daily_cap":  dailyCap,
+			"sent_today": count,
+		})
+		return 0, ErrDailyCapReached
+	}
+
+	return s.reserveSlot(sessionID, profile, now), nil
+}
+
+// reserveSlot returns the wait until the session's next send slot and pushes the following slot a
+// random delay further, so concurrent sends are spaced as well
+func (s *Service) reserveSlot(sessionID string, profile *Profile, now time.Time) time.Duration {
+	delay := profile.MinDelay
+	if spread := profile.MaxDelay - profile.MinDelay; spread > 0 {
+		delay += time.Duration(rand.Int63n(int64(spread) + 1))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	slot := s.nextSendAt[sessionID]
+	if slot.Before(now) {
+		slot = now
+	}
+	s.nextSendAt[sessionID] = slot.Add(delay)
+
+	return slot.Sub(now)
+}
+
+// activeProfile returns the session's enabled profile from the cache when fresh, or nil when the
+// session is not warming up or the profile cannot be loaded
+func (s *Service) activeProfile(ctx context.Context, sessionID string) *Profile {
+	s.mu.Lock()
+	cached, exists := s.cache[sessionID]
+	s.mu.Unlock()
+	if exists && time.Since(cached.loadedAt) < profileCacheTTL {
+		return cached.profile
+	}
+
+	profile, err := s.repo.GetBySession(ctx, sessionID)
+	if err != nil {
+		s.logger.WarnWithFields("Failed to load warm-up profile", map[string]interface{}{
+			"session_id": sessionID,
+			"error":      err.Error(),
+		})
+		return nil
+	}
+	if profile != nil && !profile.Enabled {
+		profile = nil
+	}
+
+	s.mu.Lock()
+	s.cache[sessionID] = &cachedProfile{profile: profile, loadedAt: time.Now()}
+	s.mu.Unlock()
+
+	return profile
+}
+
+func (s *Service) invalidate(sessionID string) {
+	s.mu.Lock()
+	delete(s.cache, sessionID)
+	delete(s.nextSendAt, sessionID)
+	s.mu.Unlock()
+}
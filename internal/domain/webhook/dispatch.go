@@ -0,0 +1,15 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MaxDispatchWorkers bounds the webhook delivery concurrency set at runtime
+const MaxDispatchWorkers = 100
+
+var ErrInvalidDispatchWorkers = fmt.Errorf("dispatch workers must be between 1 and %d", MaxDispatchWorkers)
+
+// ErrSessionDispatchNotPaused is returned when draining a session whose dispatch still runs, which
+// would race the workers delivering its events
+var ErrSessionDispatchNotPaused = errors.New("webhook dispatch of the session is not paused")
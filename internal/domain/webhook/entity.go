@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+
+	"zpwoot/platform/pagination"
 )
 
 type WebhookConfig struct {
@@ -14,23 +16,42 @@ type WebhookConfig struct {
 	Secret    string    `json:"secret,omitempty" db:"secret"`
 	Events    []string  `json:"events" db:"events"`
 	Enabled   bool      `json:"enabled" db:"enabled"` // User-controlled enable/disable
+	// PayloadTemplate reshapes the delivered body; empty delivers the default payload
+	PayloadTemplate string `json:"payload_template,omitempty" db:"payload_template"`
+	// TimeoutSeconds overrides the delivery timeout for slow receivers; 0 uses the configured default
+	TimeoutSeconds int `json:"timeout_seconds,omitempty" db:"timeout_seconds"`
+	// Shadow webhooks receive a copy of the events next to the regular webhooks; their deliveries are
+	// never retried nor reported as failures, so new consumers can be tried against live traffic
+	Shadow bool `json:"shadow,omitempty" db:"shadow"`
+	// Chats limits the events about a chat to these chat JIDs or patterns (e.g. *@g.us); empty means all
+	Chats     []string  `json:"chats,omitempty" db:"chats"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
 
+// MaxWebhookTimeoutSeconds bounds the per-webhook delivery timeout so one receiver cannot hold a
+// worker for long
+const MaxWebhookTimeoutSeconds = 120
+
 var (
 	ErrWebhookNotFound       = errors.New("webhook not found")
 	ErrWebhookAlreadyExists  = errors.New("webhook already exists")
 	ErrInvalidWebhookURL     = errors.New("invalid webhook URL")
 	ErrWebhookDeliveryFailed = errors.New("webhook delivery failed")
+	ErrInvalidWebhookTimeout = errors.New("webhook timeout must be between 0 and 120 seconds")
 )
 
 type SetConfigRequest struct {
-	SessionID *string  `json:"session_id,omitempty" validate:"omitempty,uuid"`
-	URL       string   `json:"url" validate:"required,url"`
-	Secret    string   `json:"secret,omitempty"`
-	Events    []string `json:"events" validate:"required,min=1"`
-	Enabled   *bool    `json:"enabled,omitempty"`
+	SessionID       *string  `json:"session_id,omitempty" validate:"omitempty,uuid"`
+	URL             string   `json:"url" validate:"required,url"`
+	Secret          string   `json:"secret,omitempty"`
+	Events          []string `json:"events" validate:"required,min=1"`
+	Enabled         *bool    `json:"enabled,omitempty"`
+	PayloadTemplate string   `json:"payload_template,omitempty"`
+	TimeoutSeconds  int      `json:"timeout_seconds,omitempty"`
+	// Shadow sets the session's shadow webhook instead of its regular one
+	Shadow bool     `json:"shadow,omitempty"`
+	Chats  []string `json:"chats,omitempty"`
 }
 
 type UpdateWebhookRequest struct {
@@ -38,6 +59,13 @@ type UpdateWebhookRequest struct {
 	Secret  *string  `json:"secret,omitempty"`
 	Events  []string `json:"events,omitempty" validate:"omitempty,min=1"`
 	Enabled *bool    `json:"enabled,omitempty"`
+	// PayloadTemplate set to an empty string restores the default payload
+	PayloadTemplate *string `json:"payload_template,omitempty"`
+	// TimeoutSeconds set to 0 restores the configured default
+	TimeoutSeconds *int  `json:"timeout_seconds,omitempty"`
+	Shadow         *bool `json:"shadow,omitempty"`
+	// Chats set to an empty list removes the chat filters
+	Chats []string `json:"chats,omitempty"`
 }
 
 type ListWebhooksRequest struct {
@@ -45,6 +73,8 @@ type ListWebhooksRequest struct {
 	Enabled   *bool   `json:"enabled,omitempty" query:"enabled"`
 	Limit     int     `json:"limit,omitempty" query:"limit" validate:"omitempty,min=1,max=100"`
 	Offset    int     `json:"offset,omitempty" query:"offset" validate:"omitempty,min=0"`
+	// After continues the list past a cursor instead of skipping Offset rows
+	After *pagination.Cursor `json:"-"`
 }
 
 type WebhookEvent struct {
@@ -53,6 +83,39 @@ type WebhookEvent struct {
 	Type      string                 `json:"type"`
 	Timestamp time.Time              `json:"timestamp"`
 	Data      map[string]interface{} `json:"data"`
+	// Routing is set when routing rules matched the event
+	Routing *Routing `json:"routing,omitempty"`
+	// Translation is set when the message text was translated before delivery
+	Translation *Translation `json:"translation,omitempty"`
+	// RequestID is the X-Request-ID of the API call that triggered the event, when known
+	RequestID string `json:"request_id,omitempty"`
+	// Replay is set when a stored event is dispatched again
+	Replay bool `json:"replay,omitempty"`
+}
+
+// Routing is the routing metadata the matching routing rules attach to an event
+type Routing struct {
+	Rules      []string `json:"rules"`
+	Tags       []string `json:"tags"`
+	WebhookIDs []string `json:"webhookIds,omitempty"`
+	// Labels are the WhatsApp label IDs applied to the chat
+	Labels []string `json:"labels,omitempty"`
+	// Exclusive restricts delivery to WebhookIDs
+	Exclusive bool `json:"exclusive,omitempty"`
+	// State is the conversation state of the chat the rules were evaluated in, when a rule uses states
+	State string `json:"state,omitempty"`
+	// NextState is the conversation state the chat was moved to
+	NextState string `json:"nextState,omitempty"`
+}
+
+// Translation describes how the text of a message event was translated; the data carries the
+// translated text and the original is kept here
+type Translation struct {
+	SourceLanguage   string `json:"sourceLanguage"`
+	TargetLanguage   string `json:"targetLanguage"`
+	DetectedLanguage string `json:"detectedLanguage,omitempty"`
+	OriginalText     string `json:"originalText,omitempty"`
+	OriginalCaption  string `json:"originalCaption,omitempty"`
 }
 
 var SupportedEventTypes = []string{
@@ -110,6 +173,30 @@ var SupportedEventTypes = []string{
 	"NewsletterLeave",
 	"NewsletterMuteChange",
 	"NewsletterLiveUpdate",
+	// View and reaction changes of subscribed newsletters, one event per message
+	"newsletter.message",
+	// Decrypted votes on polls sent by the session
+	"poll.vote",
+	// A row was selected on a list message sent by the session
+	"list.response",
+	// A message was pinned or unpinned in a chat from another device or by another participant
+	"message.pin",
+	// The disappearing messages timer of a chat was changed or turned off
+	"chat.ephemeral",
+	// A recipient replied to a native flow message sent by the session
+	"flow.response",
+	// A contact exceeded the inbound message rate
+	"flood.detected",
+	// Pushname, business name, avatar or address book changes of a contact
+	"contact.updated",
+	// A connected session started or stopped waiting for its phone
+	"session.sync_state",
+	// A session had no activity for longer than the stale session policy allows
+	"session.stale",
+	// WhatsApp reported a temporary ban, ban or account violation for a session
+	"session.warning",
+	// A source IP or API key was locked out after repeated failed API key checks; global webhooks only
+	"auth.lockout",
 
 	"FBMessage",
 
@@ -132,7 +219,7 @@ func IsValidEventType(eventType string) bool {
 func ValidateEvents(events []string) []string {
 	var invalidEvents []string
 	for _, event := range events {
-		if !IsValidEventType(event) {
+		if !IsValidEventType(event) && !isValidEventPattern(event) {
 			invalidEvents = append(invalidEvents, event)
 		}
 	}
@@ -158,7 +245,7 @@ func (w *WebhookConfig) IsGlobal() bool {
 
 func (w *WebhookConfig) HasEvent(eventType string) bool {
 	for _, event := range w.Events {
-		if event == "All" || event == eventType {
+		if MatchesEventPattern(event, eventType) {
 			return true
 		}
 	}
@@ -178,6 +265,18 @@ func (w *WebhookConfig) Update(req *UpdateWebhookRequest) {
 	if req.Enabled != nil {
 		w.Enabled = *req.Enabled
 	}
+	if req.PayloadTemplate != nil {
+		w.PayloadTemplate = *req.PayloadTemplate
+	}
+	if req.TimeoutSeconds != nil {
+		w.TimeoutSeconds = *req.TimeoutSeconds
+	}
+	if req.Shadow != nil {
+		w.Shadow = *req.Shadow
+	}
+	if req.Chats != nil {
+		w.Chats = req.Chats
+	}
 	w.UpdatedAt = time.Now()
 }
 
@@ -0,0 +1,186 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"zpwoot/platform/logger"
+)
+
+// Event replay page sizes
+const (
+	defaultReplayLimit = 500
+	maxReplayLimit     = 1000
+)
+
+// DefaultEventRetention is how long stored events can be replayed when no retention is configured
+const DefaultEventRetention = 72 * time.Hour
+
+var (
+	ErrEventStoreDisabled = errors.New("event store is disabled")
+	ErrInvalidReplayRange = errors.New("replay range needs a from before its to")
+)
+
+// EventStoreConfig controls the event store; events are only stored when Enabled is set
+type EventStoreConfig struct {
+	Enabled bool
+	// Retention is how long events are kept for replays once stored
+	Retention time.Duration
+}
+
+// ReplayRequest selects the stored events of a session to replay: those timestamped from From
+// (inclusive) to To (exclusive), optionally of the given types only
+type ReplayRequest struct {
+	From       time.Time `json:"from"`
+	To         time.Time `json:"to"`
+	EventTypes []string  `json:"event_types,omitempty"`
+	Limit      int       `json:"limit,omitempty"`
+}
+
+// ReplayResult reports a replay; when HasMore is set, replaying again from NextFrom continues it
+type ReplayResult struct {
+	Replayed int        `json:"replayed"`
+	HasMore  bool       `json:"has_more"`
+	NextFrom *time.Time `json:"next_from,omitempty"`
+}
+
+// EventStoreRepository stores dispatched events for replays
+type EventStoreRepository interface {
+	Create(ctx context.Context, event *WebhookEvent) error
+	// ListRange returns up to limit events of the session timestamped from from to to, oldest first;
+	// no event types means all of them
+	ListRange(ctx context.Context, sessionID string, from, to time.Time, eventTypes []string, limit int) ([]*WebhookEvent, error)
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// EventDispatcher re-emits events through the webhook pipeline
+type EventDispatcher interface {
+	DeliverEvent(ctx context.Context, event *WebhookEvent) error
+}
+
+// EventStore keeps the events dispatched to webhooks so consumers recovering from an outage can have
+// them replayed
+type EventStore struct {
+	logger     *logger.Logger
+	repo       EventStoreRepository
+	config     EventStoreConfig
+	dispatcher EventDispatcher
+}
+
+// NewEventStore creates the event store
+func NewEventStore(logger *logger.Logger, repo EventStoreRepository, config EventStoreConfig) *EventStore {
+	if config.Retention <= 0 {
+		config.Retention = DefaultEventRetention
+	}
+
+	return &EventStore{
+		logger: logger,
+		repo:   repo,
+		config: config,
+	}
+}
+
+// SetDispatcher sets where replayed events are dispatched; it must be set before replays
+func (s *EventStore) SetDispatcher(dispatcher EventDispatcher) {
+	s.dispatcher = dispatcher
+}
+
+// Enabled reports whether dispatched events are stored
+func (s *EventStore) Enabled() bool {
+	return s.config.Enabled
+}
+
+// RecordEvent stores a dispatched event; replays and events without a session are not stored
+func (s *EventStore) RecordEvent(ctx context.Context, event *WebhookEvent) {
+	if !s.config.Enabled || event.Replay || event.SessionID == "" {
+		return
+	}
+
+	if err := s.repo.Create(ctx, event); err != nil {
+		s.logger.WarnWithFields("Failed to store webhook event", map[string]interface{}{
+			"event_id":   event.ID,
+			"event_type": event.Type,
+			"session_id": event.SessionID,
+			"error":      err.Error(),
+		})
+	}
+}
+
+// Replay re-emits the session's stored events selected by req, flagged as replays
+func (s *EventStore) Replay(ctx context.Context, sessionID string, req *ReplayRequest) (*ReplayResult, error) {
+	if !s.config.Enabled || s.dispatcher == nil {
+		return nil, ErrEventStoreDisabled
+	}
+	if req.From.IsZero() || req.To.IsZero() || !req.From.Before(req.To) {
+		return nil, ErrInvalidReplayRange
+	}
+	if invalidEvents := ValidateEvents(req.EventTypes); len(invalidEvents) > 0 {
+		return nil, fmt.Errorf("invalid events: %v", invalidEvents)
+	}
+
+	eventTypes, all := ExpandEventPatterns(req.EventTypes)
+	if all {
+		eventTypes = nil
+	} else if len(req.EventTypes) > 0 && len(eventTypes) == 0 {
+		// Patterns selecting no event type replay nothing rather than everything
+		return &ReplayResult{}, nil
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultReplayLimit
+	}
+	if limit > maxReplayLimit {
+		limit = maxReplayLimit
+	}
+
+	events, err := s.repo.ListRange(ctx, sessionID, req.From, req.To, eventTypes, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReplayResult{HasMore: len(events) > limit}
+	if result.HasMore {
+		events = events[:limit]
+	}
+
+	for _, event := range events {
+		event.Replay = true
+		if err := s.dispatcher.DeliverEvent(ctx, event); err != nil {
+			return nil, fmt.Errorf("failed to replay event %s: %w", event.ID, err)
+		}
+		result.Replayed++
+	}
+
+	if result.HasMore && len(events) > 0 {
+		// Stored timestamps have microsecond precision
+		nextFrom := events[len(events)-1].Timestamp.Add(time.Microsecond)
+		result.NextFrom = &nextFrom
+	}
+
+	s.logger.InfoWithFields("Webhook events replayed", map[string]interface{}{
+		"session_id": sessionID,
+		"from":       req.From,
+		"to":         req.To,
+		"replayed":   result.Replayed,
+		"has_more":   result.HasMore,
+	})
+
+	return result, nil
+}
+
+// PruneExpired drops events older than the retention period
+func (s *EventStore) PruneExpired(ctx context.Context) error {
+	deleted, err := s.repo.DeleteBefore(ctx, time.Now().Add(-s.config.Retention))
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		s.logger.InfoWithFields("Old webhook events pruned", map[string]interface{}{
+			"count": deleted,
+		})
+	}
+	return nil
+}
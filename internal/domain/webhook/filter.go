@@ -0,0 +1,151 @@
+package webhook
+
+import (
+	"errors"
+	"path"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var ErrInvalidChatFilter = errors.New("invalid chat filter")
+
+var eventPatternChars = regexp.MustCompile(`^[A-Za-z0-9_.*]+$`)
+
+// Event subscriptions are event types or wildcard patterns. Patterns match the dotted lowercase path of
+// the event type, where CamelCase types are split into words: GroupInfo is group.info, CallOffer is
+// call.offer and newsletter.message stays as is. A * matches any run of characters and a trailing .*
+// also matches the bare prefix, so message.* matches Message, group.* matches GroupInfo and
+// JoinedGroup does not.
+
+// MatchesEventPattern reports whether an event subscription selects the event type
+func MatchesEventPattern(pattern, eventType string) bool {
+	if pattern == "All" || pattern == "*" || pattern == eventType {
+		return true
+	}
+	if !strings.Contains(pattern, "*") {
+		return false
+	}
+
+	pattern = strings.ToLower(pattern)
+	eventPath := EventTypePath(eventType)
+	if strings.HasSuffix(pattern, ".*") && eventPath == strings.TrimSuffix(pattern, ".*") {
+		return true
+	}
+
+	matched, err := path.Match(pattern, eventPath)
+	return err == nil && matched
+}
+
+// EventTypePath returns the dotted lowercase path wildcard patterns are matched against
+func EventTypePath(eventType string) string {
+	if strings.Contains(eventType, ".") {
+		return strings.ToLower(eventType)
+	}
+
+	runes := []rune(eventType)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+				b.WriteByte('.')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// ExpandEventPatterns returns the supported event types selected by the subscriptions; all is set
+// instead when they select every event type
+func ExpandEventPatterns(patterns []string) (eventTypes []string, all bool) {
+	for _, pattern := range patterns {
+		if pattern == "All" || pattern == "*" {
+			return nil, true
+		}
+		if !strings.Contains(pattern, "*") {
+			eventTypes = appendEventType(eventTypes, pattern)
+			continue
+		}
+		for _, eventType := range SupportedEventTypes {
+			if eventType != "All" && MatchesEventPattern(pattern, eventType) {
+				eventTypes = appendEventType(eventTypes, eventType)
+			}
+		}
+	}
+	return eventTypes, false
+}
+
+func appendEventType(eventTypes []string, eventType string) []string {
+	for _, existing := range eventTypes {
+		if existing == eventType {
+			return eventTypes
+		}
+	}
+	return append(eventTypes, eventType)
+}
+
+func isValidEventPattern(pattern string) bool {
+	if !strings.Contains(pattern, "*") || !eventPatternChars.MatchString(pattern) {
+		return false
+	}
+	_, err := path.Match(strings.ToLower(pattern), "")
+	return err == nil
+}
+
+// ValidateChats returns an error for chat filters that are empty or bad patterns
+func ValidateChats(chats []string) error {
+	for _, chat := range chats {
+		if strings.TrimSpace(chat) == "" {
+			return ErrInvalidChatFilter
+		}
+		if _, err := path.Match(chat, ""); err != nil {
+			return ErrInvalidChatFilter
+		}
+	}
+	return nil
+}
+
+// HasChat reports whether the chat filters let an event about the chat through. Webhooks without
+// filters and events that are not about a chat always pass; filters are JIDs or patterns such as *@g.us.
+func (w *WebhookConfig) HasChat(chat string) bool {
+	if len(w.Chats) == 0 || chat == "" {
+		return true
+	}
+	for _, filter := range w.Chats {
+		if filter == chat {
+			return true
+		}
+		if matched, err := path.Match(filter, chat); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Accepts reports whether the webhook is enabled and subscribed to the event and its chat
+func (w *WebhookConfig) Accepts(event *WebhookEvent) bool {
+	return w.Enabled && w.HasEvent(event.Type) && w.HasChat(event.ChatJID())
+}
+
+// ChatJID returns the chat the event is about, or "" when it is not about a chat. Converted events
+// carry it as "chat"; raw whatsmeow events under data.Info.Chat or data.Chat.
+func (e *WebhookEvent) ChatJID() string {
+	if chat, ok := e.Data["chat"].(string); ok {
+		return chat
+	}
+
+	data, ok := e.Data["data"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	if info, ok := data["Info"].(map[string]interface{}); ok {
+		if chat, ok := info["Chat"].(string); ok {
+			return chat
+		}
+	}
+	chat, _ := data["Chat"].(string)
+	return chat
+}
@@ -37,6 +37,7 @@ func (s *Service) SetConfig(ctx context.Context, req *SetConfigRequest) (*Webhoo
 		"session_id": req.SessionID,
 		"events":     req.Events,
 		"enabled":    req.Enabled,
+		"shadow":     req.Shadow,
 	})
 
 	// Validate events
@@ -50,17 +51,22 @@ func (s *Service) SetConfig(ctx context.Context, req *SetConfigRequest) (*Webhoo
 		enabled = *req.Enabled
 	}
 
-	// Try to find existing webhook for this session
+	// Try to find existing webhook for this session; the shadow webhook is kept apart from the regular one
 	var webhook *WebhookConfig
 	if req.SessionID != nil {
 		existingWebhooks, err := s.webhookRepo.GetBySessionID(ctx, *req.SessionID)
-		if err == nil && len(existingWebhooks) > 0 {
+		if err == nil {
+			webhook = firstWebhook(existingWebhooks, req.Shadow)
+		}
+		if webhook != nil {
 			// Update existing webhook
-			webhook = existingWebhooks[0]
 			webhook.URL = req.URL
 			webhook.Secret = req.Secret
 			webhook.Events = req.Events
 			webhook.Enabled = enabled
+			webhook.PayloadTemplate = req.PayloadTemplate
+			webhook.TimeoutSeconds = req.TimeoutSeconds
+			webhook.Chats = req.Chats
 			webhook.UpdatedAt = time.Now()
 
 			// Validate webhook config
@@ -83,14 +89,18 @@ func (s *Service) SetConfig(ctx context.Context, req *SetConfigRequest) (*Webhoo
 
 	// Create new webhook
 	webhook = &WebhookConfig{
-		ID:        uuid.New(),
-		SessionID: req.SessionID,
-		URL:       req.URL,
-		Secret:    req.Secret,
-		Events:    req.Events,
-		Enabled:   enabled,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		ID:              uuid.New(),
+		SessionID:       req.SessionID,
+		URL:             req.URL,
+		Secret:          req.Secret,
+		Events:          req.Events,
+		Enabled:         enabled,
+		PayloadTemplate: req.PayloadTemplate,
+		TimeoutSeconds:  req.TimeoutSeconds,
+		Shadow:          req.Shadow,
+		Chats:           req.Chats,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
 	}
 
 	// Validate webhook config
@@ -166,19 +176,28 @@ func (s *Service) GetWebhookBySession(ctx context.Context, sessionID string) (*W
 		return nil, err
 	}
 
-	if len(webhooks) == 0 {
-		return nil, ErrWebhookNotFound
-	}
-
-	// Return the first enabled webhook for the session
+	// Return the first enabled regular webhook for the session
 	for _, webhook := range webhooks {
-		if webhook.Enabled {
+		if webhook.Enabled && !webhook.Shadow {
 			return webhook, nil
 		}
 	}
 
-	// If no enabled webhook, return the first one
-	return webhooks[0], nil
+	// If no enabled webhook, return the first regular one
+	if webhook := firstWebhook(webhooks, false); webhook != nil {
+		return webhook, nil
+	}
+	return nil, ErrWebhookNotFound
+}
+
+// firstWebhook returns the first shadow or regular webhook, or nil when there is none
+func firstWebhook(webhooks []*WebhookConfig, shadow bool) *WebhookConfig {
+	for _, webhook := range webhooks {
+		if webhook.Shadow == shadow {
+			return webhook
+		}
+	}
+	return nil
 }
 
 func (s *Service) ListWebhooks(ctx context.Context, req *ListWebhooksRequest) ([]*WebhookConfig, int, error) {
@@ -222,6 +241,21 @@ func (s *Service) ProcessEvent(ctx context.Context, event *WebhookEvent) error {
 	return nil
 }
 
+// PreviewPayloadTemplate renders a payload template against an event the way delivery would
+func (s *Service) PreviewPayloadTemplate(text string, event *WebhookEvent) ([]byte, error) {
+	tmpl, err := ParsePayloadTemplate(text)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := PayloadData(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build payload data: %w", err)
+	}
+
+	return tmpl.Render(data)
+}
+
 func (s *Service) ValidateWebhookConfig(config *WebhookConfig) error {
 	if config.URL == "" {
 		return ErrInvalidWebhookURL
@@ -231,5 +265,19 @@ func (s *Service) ValidateWebhookConfig(config *WebhookConfig) error {
 		return fmt.Errorf("webhook must listen to at least one event")
 	}
 
+	if config.TimeoutSeconds < 0 || config.TimeoutSeconds > MaxWebhookTimeoutSeconds {
+		return ErrInvalidWebhookTimeout
+	}
+
+	if err := ValidateChats(config.Chats); err != nil {
+		return err
+	}
+
+	if config.PayloadTemplate != "" {
+		if _, err := ParsePayloadTemplate(config.PayloadTemplate); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,174 @@
+package webhook
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// MaxStreamsPerSession bounds the event streams open on one session
+const MaxStreamsPerSession = 10
+
+// streamBufferSize is how many events a stream holds for a slow reader before dropping them
+const streamBufferSize = 256
+
+var (
+	ErrTooManyStreams = errors.New("too many event streams open on this session")
+	ErrStreamClosed   = errors.New("event stream is closed")
+)
+
+// StreamEvent is an event payload sent to streams, as it is delivered to webhooks without a payload template
+type StreamEvent struct {
+	Type    string
+	Payload []byte
+}
+
+// EventStream fans the events dispatched to webhooks out to the streams open on their session, for
+// consumers that cannot receive webhooks
+type EventStream struct {
+	mu      sync.RWMutex
+	streams map[string]map[*Subscription]struct{}
+	closed  bool
+}
+
+// Subscription is one open stream. Events arrive on Events until the stream is unsubscribed or the
+// EventStream closed; a reader falling more than streamBufferSize events behind loses the overflow.
+type Subscription struct {
+	sessionID string
+	events    chan *StreamEvent
+	dropped   atomic.Int64
+
+	filterMu sync.RWMutex
+	filter   []string
+}
+
+// NewEventStream creates an event stream without subscribers
+func NewEventStream() *EventStream {
+	return &EventStream{
+		streams: make(map[string]map[*Subscription]struct{}),
+	}
+}
+
+// Subscribe opens a stream of the events of a session matching filter, which takes the event types
+// and patterns of webhook configurations; an empty filter receives every event
+func (s *EventStream) Subscribe(sessionID string, filter []string) (*Subscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil, ErrStreamClosed
+	}
+	if len(s.streams[sessionID]) >= MaxStreamsPerSession {
+		return nil, ErrTooManyStreams
+	}
+
+	sub := &Subscription{
+		sessionID: sessionID,
+		events:    make(chan *StreamEvent, streamBufferSize),
+		filter:    filter,
+	}
+	if s.streams[sessionID] == nil {
+		s.streams[sessionID] = make(map[*Subscription]struct{})
+	}
+	s.streams[sessionID][sub] = struct{}{}
+
+	return sub, nil
+}
+
+// Unsubscribe closes a stream; it is safe to call more than once
+func (s *EventStream) Unsubscribe(sub *Subscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.streams[sub.sessionID][sub]; !ok {
+		return
+	}
+	delete(s.streams[sub.sessionID], sub)
+	if len(s.streams[sub.sessionID]) == 0 {
+		delete(s.streams, sub.sessionID)
+	}
+	close(sub.events)
+}
+
+// HasSubscribers reports whether any stream is open on the session, so payloads are only built when
+// someone listens
+func (s *EventStream) HasSubscribers(sessionID string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.streams[sessionID]) > 0
+}
+
+// Publish sends an event payload to the streams of the session whose filter matches it, without
+// waiting for slow readers
+func (s *EventStream) Publish(sessionID, eventType string, payload []byte) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	event := &StreamEvent{Type: eventType, Payload: payload}
+	for sub := range s.streams[sessionID] {
+		if !sub.Matches(eventType) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			sub.dropped.Add(1)
+		}
+	}
+}
+
+// Close ends every stream, e.g. on shutdown; later subscriptions are refused
+func (s *EventStream) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	for sessionID, subs := range s.streams {
+		for sub := range subs {
+			close(sub.events)
+		}
+		delete(s.streams, sessionID)
+	}
+}
+
+// Events returns the channel the events of the stream arrive on; it is closed when the stream ends
+func (sub *Subscription) Events() <-chan *StreamEvent {
+	return sub.events
+}
+
+// TakeDropped returns how many events were dropped since the last call
+func (sub *Subscription) TakeDropped() int64 {
+	return sub.dropped.Swap(0)
+}
+
+// SetFilter replaces the event types and patterns the stream receives
+func (sub *Subscription) SetFilter(filter []string) {
+	sub.filterMu.Lock()
+	sub.filter = filter
+	sub.filterMu.Unlock()
+}
+
+// Filter returns the event types and patterns the stream receives; empty means all of them
+func (sub *Subscription) Filter() []string {
+	sub.filterMu.RLock()
+	defer sub.filterMu.RUnlock()
+
+	return append([]string(nil), sub.filter...)
+}
+
+// Matches reports whether the stream receives events of eventType
+func (sub *Subscription) Matches(eventType string) bool {
+	sub.filterMu.RLock()
+	defer sub.filterMu.RUnlock()
+
+	if len(sub.filter) == 0 {
+		return true
+	}
+	for _, pattern := range sub.filter {
+		if MatchesEventPattern(pattern, eventType) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// maxPayloadTemplateSize bounds stored payload templates
+const maxPayloadTemplateSize = 64 * 1024
+
+var ErrInvalidPayloadTemplate = errors.New("invalid payload template")
+
+// PayloadTemplate reshapes the normalized payload of an event into the JSON body a webhook
+// receiver expects. Templates use Go text/template syntax over the fields receivers get by
+// default: .id, .event, .sessionId, .timestamp, .data, .routing and .translation.
+type PayloadTemplate struct {
+	tmpl *template.Template
+}
+
+var payloadTemplateFuncs = template.FuncMap{
+	// json encodes a value as JSON so strings are quoted and escaped; missing values become null
+	"json": func(v interface{}) (string, error) {
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(encoded), nil
+	},
+	"default": func(fallback, v interface{}) interface{} {
+		if v == nil {
+			return fallback
+		}
+		if s, ok := v.(string); ok && s == "" {
+			return fallback
+		}
+		return v
+	},
+	"lower":   strings.ToLower,
+	"upper":   strings.ToUpper,
+	"trim":    strings.TrimSpace,
+	"replace": strings.ReplaceAll,
+}
+
+// ParsePayloadTemplate compiles a payload template
+func ParsePayloadTemplate(text string) (*PayloadTemplate, error) {
+	if len(text) > maxPayloadTemplateSize {
+		return nil, fmt.Errorf("%w: larger than %d bytes", ErrInvalidPayloadTemplate, maxPayloadTemplateSize)
+	}
+
+	tmpl, err := template.New("payload").Funcs(payloadTemplateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayloadTemplate, err)
+	}
+
+	return &PayloadTemplate{tmpl: tmpl}, nil
+}
+
+// Render executes the template against the payload data of an event and checks that the result
+// is valid JSON
+func (t *PayloadTemplate) Render(data map[string]interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := t.tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayloadTemplate, err)
+	}
+
+	rendered := bytes.TrimSpace(buf.Bytes())
+	if !json.Valid(rendered) {
+		return nil, fmt.Errorf("%w: rendered payload is not valid JSON", ErrInvalidPayloadTemplate)
+	}
+
+	return rendered, nil
+}
+
+// PayloadData returns the normalized payload of an event as templates see it. Values go through
+// a JSON round trip so field names and types match the default payload.
+func PayloadData(event *WebhookEvent) (map[string]interface{}, error) {
+	payload := map[string]interface{}{
+		"id":        event.ID,
+		"event":     event.Type,
+		"sessionId": event.SessionID,
+		"timestamp": event.Timestamp.Unix(),
+		"data":      event.Data,
+	}
+	if event.Routing != nil {
+		payload["routing"] = event.Routing
+	}
+	if event.Translation != nil {
+		payload["translation"] = event.Translation
+	}
+	if event.RequestID != "" {
+		payload["requestId"] = event.RequestID
+	}
+	if event.Replay {
+		payload["replay"] = true
+	}
+
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(encoded, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
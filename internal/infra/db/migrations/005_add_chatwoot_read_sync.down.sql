@@ -0,0 +1,3 @@
+-- Remove read receipt synchronization flags from chatwoot config
+ALTER TABLE "zpChatwoot" DROP COLUMN IF EXISTS "sendReads";
+ALTER TABLE "zpChatwoot" DROP COLUMN IF EXISTS "syncReads";
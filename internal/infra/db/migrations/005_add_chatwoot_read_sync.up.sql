@@ -0,0 +1,6 @@
+-- Add read receipt synchronization flags to chatwoot config
+ALTER TABLE "zpChatwoot" ADD COLUMN IF NOT EXISTS "syncReads" BOOLEAN DEFAULT true;
+ALTER TABLE "zpChatwoot" ADD COLUMN IF NOT EXISTS "sendReads" BOOLEAN DEFAULT false;
+
+COMMENT ON COLUMN "zpChatwoot"."syncReads" IS 'Mark Chatwoot messages as read when the WhatsApp contact reads them';
+COMMENT ON COLUMN "zpChatwoot"."sendReads" IS 'Send WhatsApp read receipts when an agent reads the Chatwoot conversation';
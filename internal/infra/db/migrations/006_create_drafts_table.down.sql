@@ -0,0 +1,5 @@
+-- Drop drafts table
+DROP TRIGGER IF EXISTS update_zp_drafts_updated_at ON "zpDrafts";
+DROP INDEX IF EXISTS "idx_zp_drafts_due";
+DROP INDEX IF EXISTS "idx_zp_drafts_session_chat";
+DROP TABLE IF EXISTS "zpDrafts";
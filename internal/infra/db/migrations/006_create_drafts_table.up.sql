@@ -0,0 +1,30 @@
+-- Create drafts table (one draft per chat)
+CREATE TABLE IF NOT EXISTS "zpDrafts" (
+    "id" UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    "sessionId" UUID NOT NULL REFERENCES "zpSessions"("id") ON DELETE CASCADE,
+    "chatJid" VARCHAR(255) NOT NULL,
+    "text" TEXT NOT NULL,
+    "status" VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK ("status" IN ('draft', 'scheduled', 'sending', 'failed')),
+    "scheduledAt" TIMESTAMP WITH TIME ZONE,
+    "lastError" TEXT,
+    "createdAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    "updatedAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for better performance
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_zp_drafts_session_chat" ON "zpDrafts" ("sessionId", "chatJid");
+CREATE INDEX IF NOT EXISTS "idx_zp_drafts_due" ON "zpDrafts" ("status", "scheduledAt");
+
+-- Create trigger to automatically update updatedAt
+CREATE TRIGGER update_zp_drafts_updated_at
+    BEFORE UPDATE ON "zpDrafts"
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Add comments for documentation
+COMMENT ON TABLE "zpDrafts" IS 'Message drafts kept server-side per chat';
+COMMENT ON COLUMN "zpDrafts"."chatJid" IS 'WhatsApp chat JID the draft belongs to';
+COMMENT ON COLUMN "zpDrafts"."text" IS 'Draft message text';
+COMMENT ON COLUMN "zpDrafts"."status" IS 'draft, scheduled, sending or failed; drafts are removed once sent';
+COMMENT ON COLUMN "zpDrafts"."scheduledAt" IS 'When a scheduled draft should be sent';
+COMMENT ON COLUMN "zpDrafts"."lastError" IS 'Error of the last failed send attempt';
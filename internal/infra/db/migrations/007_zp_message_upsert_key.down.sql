@@ -0,0 +1,6 @@
+-- Restore the per-session message ID constraint
+DROP INDEX IF EXISTS "idx_zp_message_session_message";
+DROP INDEX IF EXISTS "idx_zp_message_unique_chat_message";
+
+CREATE INDEX IF NOT EXISTS "idx_zp_message_session_chat" ON "zpMessage" ("sessionId", "zpChat");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_zp_message_unique_zp" ON "zpMessage" ("sessionId", "zpMessageId");
@@ -0,0 +1,6 @@
+-- Key zpMessage rows by chat so history sync, live events and backfills upsert the same row
+DROP INDEX IF EXISTS "idx_zp_message_unique_zp";
+DROP INDEX IF EXISTS "idx_zp_message_session_chat";
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_zp_message_unique_chat_message" ON "zpMessage" ("sessionId", "zpChat", "zpMessageId");
+CREATE INDEX IF NOT EXISTS "idx_zp_message_session_message" ON "zpMessage" ("sessionId", "zpMessageId");
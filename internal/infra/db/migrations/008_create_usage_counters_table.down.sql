@@ -0,0 +1,3 @@
+-- Drop usage counters table
+DROP INDEX IF EXISTS "idx_zp_usage_counters_period";
+DROP TABLE IF EXISTS "zpUsageCounters";
@@ -0,0 +1,19 @@
+-- Create usage counters table (one row per workspace, metric and period)
+CREATE TABLE IF NOT EXISTS "zpUsageCounters" (
+    "workspace" VARCHAR(100) NOT NULL,
+    "metric" VARCHAR(50) NOT NULL,
+    "period" VARCHAR(10) NOT NULL,
+    "value" BIGINT NOT NULL DEFAULT 0,
+    "updatedAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY ("workspace", "metric", "period")
+);
+
+-- Create indexes for better performance
+CREATE INDEX IF NOT EXISTS "idx_zp_usage_counters_period" ON "zpUsageCounters" ("period");
+
+-- Add comments for documentation
+COMMENT ON TABLE "zpUsageCounters" IS 'Per-workspace usage counters used for quotas and billing';
+COMMENT ON COLUMN "zpUsageCounters"."workspace" IS 'Workspace the usage is accounted to';
+COMMENT ON COLUMN "zpUsageCounters"."metric" IS 'messages or media_bytes';
+COMMENT ON COLUMN "zpUsageCounters"."period" IS 'Day (YYYY-MM-DD) or month (YYYY-MM) the value belongs to';
+COMMENT ON COLUMN "zpUsageCounters"."value" IS 'Accumulated usage in the period';
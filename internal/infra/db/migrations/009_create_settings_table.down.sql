@@ -0,0 +1,2 @@
+-- Drop runtime settings table
+DROP TABLE IF EXISTS "zpSettings";
@@ -0,0 +1,12 @@
+-- Create runtime settings table (log level, debug body logging and feature flags)
+CREATE TABLE IF NOT EXISTS "zpSettings" (
+    "key" VARCHAR(100) PRIMARY KEY,
+    "value" TEXT NOT NULL,
+    "updatedAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Add comments for documentation
+COMMENT ON TABLE "zpSettings" IS 'Runtime settings shared by all replicas and applied without a restart';
+COMMENT ON COLUMN "zpSettings"."key" IS 'log_level, debug_body_logging or flag.<name>';
+COMMENT ON COLUMN "zpSettings"."value" IS 'Setting value as text';
+COMMENT ON COLUMN "zpSettings"."updatedAt" IS 'Last change; replicas reload when it moves forward';
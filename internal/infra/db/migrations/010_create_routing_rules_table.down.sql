@@ -0,0 +1,4 @@
+-- Drop routing rules table
+DROP TRIGGER IF EXISTS update_zp_routing_rules_updated_at ON "zpRoutingRules";
+DROP INDEX IF EXISTS "idx_zp_routing_rules_session_priority";
+DROP TABLE IF EXISTS "zpRoutingRules";
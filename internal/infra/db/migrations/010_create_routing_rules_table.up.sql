@@ -0,0 +1,35 @@
+-- Create routing rules table (tag or route incoming messages to webhooks)
+CREATE TABLE IF NOT EXISTS "zpRoutingRules" (
+    "id" UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    "sessionId" UUID NOT NULL REFERENCES "zpSessions"("id") ON DELETE CASCADE,
+    "name" VARCHAR(255) NOT NULL,
+    "priority" INTEGER NOT NULL DEFAULT 0,
+    "enabled" BOOLEAN NOT NULL DEFAULT true,
+    "keywords" JSONB NOT NULL DEFAULT '[]',
+    "senders" JSONB NOT NULL DEFAULT '[]',
+    "tags" JSONB NOT NULL DEFAULT '[]',
+    "webhookIds" JSONB NOT NULL DEFAULT '[]',
+    "exclusive" BOOLEAN NOT NULL DEFAULT false,
+    "stopProcessing" BOOLEAN NOT NULL DEFAULT false,
+    "createdAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    "updatedAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for better performance
+CREATE INDEX IF NOT EXISTS "idx_zp_routing_rules_session_priority" ON "zpRoutingRules" ("sessionId", "priority" DESC);
+
+-- Create trigger to automatically update updatedAt
+CREATE TRIGGER update_zp_routing_rules_updated_at
+    BEFORE UPDATE ON "zpRoutingRules"
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Add comments for documentation
+COMMENT ON TABLE "zpRoutingRules" IS 'Rules that tag incoming messages and route them to webhooks';
+COMMENT ON COLUMN "zpRoutingRules"."priority" IS 'Rules are evaluated from the highest priority down';
+COMMENT ON COLUMN "zpRoutingRules"."keywords" IS 'Lower-cased keywords; the rule matches when the text contains any';
+COMMENT ON COLUMN "zpRoutingRules"."senders" IS 'Phone numbers or JIDs; the rule matches when the sender is any';
+COMMENT ON COLUMN "zpRoutingRules"."tags" IS 'Tags added to the routing metadata of matching messages';
+COMMENT ON COLUMN "zpRoutingRules"."webhookIds" IS 'Webhooks that receive matching messages';
+COMMENT ON COLUMN "zpRoutingRules"."exclusive" IS 'Deliver matching messages only to the rule webhooks';
+COMMENT ON COLUMN "zpRoutingRules"."stopProcessing" IS 'Skip lower priority rules when this rule matches';
@@ -0,0 +1,3 @@
+-- Remove routing rule patterns and labels
+ALTER TABLE "zpRoutingRules" DROP COLUMN IF EXISTS "labels";
+ALTER TABLE "zpRoutingRules" DROP COLUMN IF EXISTS "patterns";
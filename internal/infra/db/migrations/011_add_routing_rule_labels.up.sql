@@ -0,0 +1,6 @@
+-- Add regex patterns and WhatsApp labels to routing rules (keyword-based auto-labeling)
+ALTER TABLE "zpRoutingRules" ADD COLUMN IF NOT EXISTS "patterns" JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE "zpRoutingRules" ADD COLUMN IF NOT EXISTS "labels" JSONB NOT NULL DEFAULT '[]';
+
+COMMENT ON COLUMN "zpRoutingRules"."patterns" IS 'Case-insensitive regular expressions; the rule matches when the text matches any';
+COMMENT ON COLUMN "zpRoutingRules"."labels" IS 'WhatsApp label IDs applied to the chat of matching messages';
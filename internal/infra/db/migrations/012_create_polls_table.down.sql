@@ -0,0 +1,3 @@
+-- Drop polls table
+DROP INDEX IF EXISTS "idx_zp_polls_session_message";
+DROP TABLE IF EXISTS "zpPolls";
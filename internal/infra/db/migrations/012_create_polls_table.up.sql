@@ -0,0 +1,23 @@
+-- Create polls table (definitions of polls sent by sessions)
+CREATE TABLE IF NOT EXISTS "zpPolls" (
+    "id" UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    "sessionId" UUID NOT NULL REFERENCES "zpSessions"("id") ON DELETE CASCADE,
+    "messageId" VARCHAR(255) NOT NULL,
+    "chatJid" VARCHAR(255) NOT NULL,
+    "name" TEXT NOT NULL,
+    "options" JSONB NOT NULL DEFAULT '[]',
+    "selectableCount" INTEGER NOT NULL DEFAULT 1,
+    "expiresAt" TIMESTAMP WITH TIME ZONE,
+    "createdAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for better performance
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_zp_polls_session_message" ON "zpPolls" ("sessionId", "messageId");
+
+-- Add comments for documentation
+COMMENT ON TABLE "zpPolls" IS 'Definitions of sent polls, used to map vote hashes back to option text';
+COMMENT ON COLUMN "zpPolls"."messageId" IS 'WhatsApp message ID of the poll creation message';
+COMMENT ON COLUMN "zpPolls"."chatJid" IS 'WhatsApp chat JID the poll was sent to';
+COMMENT ON COLUMN "zpPolls"."options" IS 'Options in order with the hex SHA-256 hash WhatsApp uses in votes';
+COMMENT ON COLUMN "zpPolls"."selectableCount" IS 'How many options a voter may pick; 0 allows any number';
+COMMENT ON COLUMN "zpPolls"."expiresAt" IS 'When the poll stops accepting votes; NULL never expires';
@@ -0,0 +1,2 @@
+-- Drop poll votes table
+DROP TABLE IF EXISTS "zpPollVotes";
@@ -0,0 +1,16 @@
+-- Create poll votes table (current selection of each voter)
+CREATE TABLE IF NOT EXISTS "zpPollVotes" (
+    "pollId" UUID NOT NULL REFERENCES "zpPolls"("id") ON DELETE CASCADE,
+    "voterJid" VARCHAR(255) NOT NULL,
+    "options" JSONB NOT NULL DEFAULT '[]',
+    "late" BOOLEAN NOT NULL DEFAULT false,
+    "votedAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY ("pollId", "voterJid")
+);
+
+-- Add comments for documentation
+COMMENT ON TABLE "zpPollVotes" IS 'Latest vote of each voter on sent polls, used for tallies';
+COMMENT ON COLUMN "zpPollVotes"."voterJid" IS 'WhatsApp JID of the voter';
+COMMENT ON COLUMN "zpPollVotes"."options" IS 'Selected option names; empty when the voter retracted the vote';
+COMMENT ON COLUMN "zpPollVotes"."late" IS 'Vote was cast after the poll expired and is not counted';
+COMMENT ON COLUMN "zpPollVotes"."votedAt" IS 'When the vote was cast';
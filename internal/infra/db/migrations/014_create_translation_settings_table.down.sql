@@ -0,0 +1,2 @@
+-- Drop translation settings table
+DROP TABLE IF EXISTS "zpTranslationSettings";
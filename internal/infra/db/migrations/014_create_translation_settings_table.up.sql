@@ -0,0 +1,17 @@
+-- Create translation settings table (per-session message translation)
+CREATE TABLE IF NOT EXISTS "zpTranslationSettings" (
+    "sessionId" UUID PRIMARY KEY REFERENCES "zpSessions"("id") ON DELETE CASCADE,
+    "enabled" BOOLEAN NOT NULL DEFAULT false,
+    "inbound" BOOLEAN NOT NULL DEFAULT true,
+    "outbound" BOOLEAN NOT NULL DEFAULT false,
+    "sourceLanguage" VARCHAR(16) NOT NULL DEFAULT 'auto',
+    "targetLanguage" VARCHAR(16) NOT NULL DEFAULT '',
+    "updatedAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Add comments for documentation
+COMMENT ON TABLE "zpTranslationSettings" IS 'Per-session translation of incoming and outgoing message text';
+COMMENT ON COLUMN "zpTranslationSettings"."inbound" IS 'Translate incoming messages before webhook delivery';
+COMMENT ON COLUMN "zpTranslationSettings"."outbound" IS 'Translate outgoing messages before they are sent';
+COMMENT ON COLUMN "zpTranslationSettings"."sourceLanguage" IS 'Language contacts write in; auto detects it for incoming messages';
+COMMENT ON COLUMN "zpTranslationSettings"."targetLanguage" IS 'Language operators read and write in';
@@ -0,0 +1,4 @@
+-- Drop contact changes table
+DROP INDEX IF EXISTS "idx_zp_contact_changes_changed";
+DROP INDEX IF EXISTS "idx_zp_contact_changes_session_changed";
+DROP TABLE IF EXISTS "zpContactChanges";
@@ -0,0 +1,24 @@
+-- Create contact changes table (feed of pushname, avatar and address book changes)
+CREATE TABLE IF NOT EXISTS "zpContactChanges" (
+    "id" BIGSERIAL PRIMARY KEY,
+    "sessionId" UUID NOT NULL REFERENCES "zpSessions"("id") ON DELETE CASCADE,
+    "contactJid" VARCHAR(255) NOT NULL,
+    "changeType" VARCHAR(32) NOT NULL,
+    "pushName" TEXT NOT NULL DEFAULT '',
+    "businessName" TEXT NOT NULL DEFAULT '',
+    "fullName" TEXT NOT NULL DEFAULT '',
+    "firstName" TEXT NOT NULL DEFAULT '',
+    "pictureId" VARCHAR(255) NOT NULL DEFAULT '',
+    "pictureRemoved" BOOLEAN NOT NULL DEFAULT false,
+    "changedAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for better performance
+CREATE INDEX IF NOT EXISTS "idx_zp_contact_changes_session_changed" ON "zpContactChanges" ("sessionId", "changedAt", "id");
+CREATE INDEX IF NOT EXISTS "idx_zp_contact_changes_changed" ON "zpContactChanges" ("changedAt");
+
+-- Add comments for documentation
+COMMENT ON TABLE "zpContactChanges" IS 'Contact change feed polled by CRMs through /contacts/changes';
+COMMENT ON COLUMN "zpContactChanges"."changeType" IS 'push_name, business_name, picture or contact (address book)';
+COMMENT ON COLUMN "zpContactChanges"."pictureRemoved" IS 'The contact removed their profile picture';
+COMMENT ON COLUMN "zpContactChanges"."changedAt" IS 'When the change was received; the feed is ordered by it';
@@ -0,0 +1,2 @@
+-- Drop warm-up profiles table
+DROP TABLE IF EXISTS "zpWarmupProfiles";
@@ -0,0 +1,19 @@
+-- Create warm-up profiles table (progressive send caps for newly paired numbers)
+CREATE TABLE IF NOT EXISTS "zpWarmupProfiles" (
+    "sessionId" UUID PRIMARY KEY REFERENCES "zpSessions"("id") ON DELETE CASCADE,
+    "enabled" BOOLEAN NOT NULL DEFAULT true,
+    "startedAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    "schedule" INTEGER[] NOT NULL,
+    "minDelayMs" INTEGER NOT NULL DEFAULT 3000,
+    "maxDelayMs" INTEGER NOT NULL DEFAULT 10000,
+    "sentDay" VARCHAR(10) NOT NULL DEFAULT '',
+    "sentCount" INTEGER NOT NULL DEFAULT 0,
+    "updatedAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Add comments for documentation
+COMMENT ON TABLE "zpWarmupProfiles" IS 'Per-session warm-up with progressively increasing daily send caps';
+COMMENT ON COLUMN "zpWarmupProfiles"."schedule" IS 'Daily send cap of each warm-up day, counted from startedAt';
+COMMENT ON COLUMN "zpWarmupProfiles"."minDelayMs" IS 'Minimum random delay between two sends in milliseconds';
+COMMENT ON COLUMN "zpWarmupProfiles"."maxDelayMs" IS 'Maximum random delay between two sends in milliseconds';
+COMMENT ON COLUMN "zpWarmupProfiles"."sentDay" IS 'UTC day (YYYY-MM-DD) sentCount refers to';
@@ -0,0 +1,4 @@
+-- Add user-defined payload templates to webhooks
+ALTER TABLE "zpWebhooks" ADD COLUMN IF NOT EXISTS "payloadTemplate" TEXT;
+
+COMMENT ON COLUMN "zpWebhooks"."payloadTemplate" IS 'Go text/template reshaping the delivered JSON body; NULL delivers the default payload';
@@ -0,0 +1,2 @@
+-- Remove per-webhook delivery timeout overrides
+ALTER TABLE "zpWebhooks" DROP COLUMN IF EXISTS "timeoutSeconds";
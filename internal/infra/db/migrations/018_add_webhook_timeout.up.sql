@@ -0,0 +1,4 @@
+-- Add per-webhook delivery timeout overrides
+ALTER TABLE "zpWebhooks" ADD COLUMN IF NOT EXISTS "timeoutSeconds" INTEGER NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN "zpWebhooks"."timeoutSeconds" IS 'Delivery timeout override in seconds; 0 uses WEBHOOK_TIMEOUT';
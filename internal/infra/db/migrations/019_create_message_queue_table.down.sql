@@ -0,0 +1,5 @@
+-- Drop offline message queue table
+DROP TRIGGER IF EXISTS update_zp_message_queue_updated_at ON "zpMessageQueue";
+DROP INDEX IF EXISTS "idx_zp_message_queue_expiry";
+DROP INDEX IF EXISTS "idx_zp_message_queue_pending";
+DROP TABLE IF EXISTS "zpMessageQueue";
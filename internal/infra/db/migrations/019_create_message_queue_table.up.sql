@@ -0,0 +1,34 @@
+-- Create offline message queue table (sends accepted while the session was disconnected)
+CREATE TABLE IF NOT EXISTS "zpMessageQueue" (
+    "id" UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    "sessionId" UUID NOT NULL REFERENCES "zpSessions"("id") ON DELETE CASCADE,
+    "workspace" VARCHAR(255) NOT NULL DEFAULT 'default',
+    "request" JSONB NOT NULL,
+    "status" VARCHAR(20) NOT NULL DEFAULT 'queued' CHECK ("status" IN ('queued', 'sending', 'sent', 'failed', 'expired')),
+    "attempts" INTEGER NOT NULL DEFAULT 0,
+    "messageId" VARCHAR(255),
+    "lastError" TEXT,
+    "expiresAt" TIMESTAMP WITH TIME ZONE NOT NULL,
+    "sentAt" TIMESTAMP WITH TIME ZONE,
+    "createdAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    "updatedAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for better performance
+CREATE INDEX IF NOT EXISTS "idx_zp_message_queue_pending" ON "zpMessageQueue" ("sessionId", "status", "createdAt");
+CREATE INDEX IF NOT EXISTS "idx_zp_message_queue_expiry" ON "zpMessageQueue" ("status", "expiresAt");
+
+-- Create trigger to automatically update updatedAt
+CREATE TRIGGER update_zp_message_queue_updated_at
+    BEFORE UPDATE ON "zpMessageQueue"
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Add comments for documentation
+COMMENT ON TABLE "zpMessageQueue" IS 'Messages accepted while their session was disconnected, sent in order once it reconnects';
+COMMENT ON COLUMN "zpMessageQueue"."workspace" IS 'Workspace the send is accounted to';
+COMMENT ON COLUMN "zpMessageQueue"."request" IS 'Send request as received by the API';
+COMMENT ON COLUMN "zpMessageQueue"."status" IS 'queued, sending, sent, failed or expired';
+COMMENT ON COLUMN "zpMessageQueue"."messageId" IS 'WhatsApp message ID once sent';
+COMMENT ON COLUMN "zpMessageQueue"."lastError" IS 'Error of the last failed send attempt';
+COMMENT ON COLUMN "zpMessageQueue"."expiresAt" IS 'When the message expires if the session has not reconnected';
@@ -0,0 +1,7 @@
+-- Remove the cancelled status of queued messages
+DELETE FROM "zpMessageQueue" WHERE "status" = 'cancelled';
+ALTER TABLE "zpMessageQueue" DROP CONSTRAINT IF EXISTS "zpMessageQueue_status_check";
+ALTER TABLE "zpMessageQueue" ADD CONSTRAINT "zpMessageQueue_status_check"
+    CHECK ("status" IN ('queued', 'sending', 'sent', 'failed', 'expired'));
+
+COMMENT ON COLUMN "zpMessageQueue"."status" IS 'queued, sending, sent, failed or expired';
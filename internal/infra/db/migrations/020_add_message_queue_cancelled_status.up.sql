@@ -0,0 +1,6 @@
+-- Allow queued messages to be cancelled before they are sent
+ALTER TABLE "zpMessageQueue" DROP CONSTRAINT IF EXISTS "zpMessageQueue_status_check";
+ALTER TABLE "zpMessageQueue" ADD CONSTRAINT "zpMessageQueue_status_check"
+    CHECK ("status" IN ('queued', 'sending', 'sent', 'failed', 'expired', 'cancelled'));
+
+COMMENT ON COLUMN "zpMessageQueue"."status" IS 'queued, sending, sent, failed, expired or cancelled';
@@ -0,0 +1,2 @@
+-- Remove the request ID of queued messages
+ALTER TABLE "zpMessageQueue" DROP COLUMN IF EXISTS "requestId";
@@ -0,0 +1,4 @@
+-- Keep the ID of the API request that queued a message, so its delivery can be traced back to it
+ALTER TABLE "zpMessageQueue" ADD COLUMN IF NOT EXISTS "requestId" VARCHAR(255);
+
+COMMENT ON COLUMN "zpMessageQueue"."requestId" IS 'ID of the API request that queued the message';
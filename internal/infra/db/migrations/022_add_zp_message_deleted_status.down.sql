@@ -0,0 +1,7 @@
+-- Remove the deleted status of message mappings
+UPDATE "zpMessage" SET "syncStatus" = 'synced' WHERE "syncStatus" = 'deleted';
+ALTER TABLE "zpMessage" DROP CONSTRAINT IF EXISTS "zpMessage_syncStatus_check";
+ALTER TABLE "zpMessage" ADD CONSTRAINT "zpMessage_syncStatus_check"
+    CHECK ("syncStatus" IN ('pending', 'synced', 'failed'));
+
+COMMENT ON COLUMN "zpMessage"."syncStatus" IS 'Synchronization status with Chatwoot';
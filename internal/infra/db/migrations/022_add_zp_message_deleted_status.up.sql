@@ -0,0 +1,6 @@
+-- Mark message mappings whose message was deleted on either side, so the deletion is not synced back
+ALTER TABLE "zpMessage" DROP CONSTRAINT IF EXISTS "zpMessage_syncStatus_check";
+ALTER TABLE "zpMessage" ADD CONSTRAINT "zpMessage_syncStatus_check"
+    CHECK ("syncStatus" IN ('pending', 'synced', 'failed', 'deleted'));
+
+COMMENT ON COLUMN "zpMessage"."syncStatus" IS 'Synchronization status with Chatwoot: pending, synced, failed or deleted';
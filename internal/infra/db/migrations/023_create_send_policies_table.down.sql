@@ -0,0 +1,4 @@
+-- Drop send guard tables
+DROP INDEX IF EXISTS "idx_zp_send_violations_session_created";
+DROP TABLE IF EXISTS "zpSendViolations";
+DROP TABLE IF EXISTS "zpSendPolicies";
@@ -0,0 +1,24 @@
+-- Create send policies table (recipients a session may send to while the send guard is on)
+CREATE TABLE IF NOT EXISTS "zpSendPolicies" (
+    "sessionId" UUID PRIMARY KEY REFERENCES "zpSessions"("id") ON DELETE CASCADE,
+    "allowedGroups" TEXT[] NOT NULL DEFAULT '{}',
+    "updatedAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create send guard violations table (audit of sends the guard blocked)
+CREATE TABLE IF NOT EXISTS "zpSendViolations" (
+    "id" UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    "sessionId" UUID NOT NULL REFERENCES "zpSessions"("id") ON DELETE CASCADE,
+    "recipient" VARCHAR(255) NOT NULL,
+    "reason" VARCHAR(50) NOT NULL,
+    "createdAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS "idx_zp_send_violations_session_created" ON "zpSendViolations" ("sessionId", "createdAt" DESC);
+
+-- Add comments for documentation
+COMMENT ON TABLE "zpSendPolicies" IS 'Per-session allowances of the send guard';
+COMMENT ON COLUMN "zpSendPolicies"."allowedGroups" IS 'Group JIDs the session may send to on top of the global allowlist, * allows all groups';
+COMMENT ON TABLE "zpSendViolations" IS 'Sends blocked by the send guard';
+COMMENT ON COLUMN "zpSendViolations"."recipient" IS 'JID the blocked send was addressed to';
+COMMENT ON COLUMN "zpSendViolations"."reason" IS 'Why the send was blocked, e.g. group_not_allowed';
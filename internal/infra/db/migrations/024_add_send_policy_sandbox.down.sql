@@ -0,0 +1,7 @@
+-- Remove sandbox mode from send policies
+ALTER TABLE "zpSendViolations" DROP COLUMN IF EXISTS "redirectedTo";
+
+ALTER TABLE "zpSendPolicies"
+    DROP COLUMN IF EXISTS "sandboxRedirectTo",
+    DROP COLUMN IF EXISTS "sandboxRecipients",
+    DROP COLUMN IF EXISTS "sandbox";
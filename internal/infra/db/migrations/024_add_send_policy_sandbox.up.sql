@@ -0,0 +1,14 @@
+-- Add sandbox mode to send policies (sends restricted to test recipients)
+ALTER TABLE "zpSendPolicies"
+    ADD COLUMN IF NOT EXISTS "sandbox" BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS "sandboxRecipients" TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS "sandboxRedirectTo" VARCHAR(255);
+
+ALTER TABLE "zpSendViolations"
+    ADD COLUMN IF NOT EXISTS "redirectedTo" VARCHAR(255);
+
+-- Add comments for documentation
+COMMENT ON COLUMN "zpSendPolicies"."sandbox" IS 'Restricts the sends of the session to the sandbox recipients';
+COMMENT ON COLUMN "zpSendPolicies"."sandboxRecipients" IS 'Recipient JIDs the sandboxed session may send to on top of the global ones';
+COMMENT ON COLUMN "zpSendPolicies"."sandboxRedirectTo" IS 'Recipient JID sends to other recipients go to instead of being rejected';
+COMMENT ON COLUMN "zpSendViolations"."redirectedTo" IS 'Recipient JID a sandboxed send went to instead';
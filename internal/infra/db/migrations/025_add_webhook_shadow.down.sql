@@ -0,0 +1,2 @@
+-- Remove shadow webhooks
+ALTER TABLE "zpWebhooks" DROP COLUMN IF EXISTS "shadow";
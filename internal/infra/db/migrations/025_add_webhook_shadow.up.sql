@@ -0,0 +1,4 @@
+-- Add shadow webhooks (copies of events for testing new consumers)
+ALTER TABLE "zpWebhooks" ADD COLUMN IF NOT EXISTS "shadow" BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN "zpWebhooks"."shadow" IS 'Receives a copy of the events; its deliveries are never retried nor reported as failures';
@@ -0,0 +1,4 @@
+-- Drop webhook events table
+DROP INDEX IF EXISTS "idx_zp_webhook_events_created";
+DROP INDEX IF EXISTS "idx_zp_webhook_events_session_timestamp";
+DROP TABLE IF EXISTS "zpWebhookEvents";
@@ -0,0 +1,23 @@
+-- Create webhook events table (dispatched events kept for replays)
+CREATE TABLE IF NOT EXISTS "zpWebhookEvents" (
+    "id" BIGSERIAL PRIMARY KEY,
+    "eventId" VARCHAR(255) NOT NULL,
+    "sessionId" UUID NOT NULL REFERENCES "zpSessions"("id") ON DELETE CASCADE,
+    "eventType" VARCHAR(64) NOT NULL,
+    "timestamp" TIMESTAMP WITH TIME ZONE NOT NULL,
+    "data" JSONB NOT NULL DEFAULT '{}',
+    "routing" JSONB,
+    "translation" JSONB,
+    "requestId" VARCHAR(255),
+    "createdAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for better performance
+CREATE INDEX IF NOT EXISTS "idx_zp_webhook_events_session_timestamp" ON "zpWebhookEvents" ("sessionId", "timestamp", "id");
+CREATE INDEX IF NOT EXISTS "idx_zp_webhook_events_created" ON "zpWebhookEvents" ("createdAt");
+
+-- Add comments for documentation
+COMMENT ON TABLE "zpWebhookEvents" IS 'Events dispatched to webhooks, replayed through /sessions/{sessionId}/events/replay';
+COMMENT ON COLUMN "zpWebhookEvents"."eventId" IS 'ID of the event, kept on replays so receivers can drop duplicates';
+COMMENT ON COLUMN "zpWebhookEvents"."data" IS 'Event data as delivered, after translation';
+COMMENT ON COLUMN "zpWebhookEvents"."createdAt" IS 'When the event was stored; events expire EVENT_STORE_RETENTION after it';
@@ -0,0 +1,4 @@
+-- Drop cursor pagination indexes
+DROP INDEX IF EXISTS "idx_zp_drafts_session_created_at_id";
+DROP INDEX IF EXISTS "idx_zp_webhooks_created_at_id";
+DROP INDEX IF EXISTS "idx_zp_sessions_created_at_id";
@@ -0,0 +1,4 @@
+-- Indexes matching the ("createdAt", id) order of cursor paginated lists
+CREATE INDEX IF NOT EXISTS "idx_zp_sessions_created_at_id" ON "zpSessions" ("createdAt" DESC, "id" DESC);
+CREATE INDEX IF NOT EXISTS "idx_zp_webhooks_created_at_id" ON "zpWebhooks" ("createdAt" DESC, "id" DESC);
+CREATE INDEX IF NOT EXISTS "idx_zp_drafts_session_created_at_id" ON "zpDrafts" ("sessionId", "createdAt" DESC, "id" DESC);
@@ -0,0 +1,2 @@
+-- Remove per-chat event filters
+ALTER TABLE "zpWebhooks" DROP COLUMN IF EXISTS "chats";
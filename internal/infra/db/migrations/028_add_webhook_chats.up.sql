@@ -0,0 +1,5 @@
+-- Add per-chat event filters to webhooks
+ALTER TABLE "zpWebhooks" ADD COLUMN IF NOT EXISTS "chats" TEXT[] NOT NULL DEFAULT '{}';
+
+COMMENT ON COLUMN "zpWebhooks"."chats" IS 'Chat JIDs or patterns (e.g. *@g.us) the events about a chat are limited to; empty means all chats';
+COMMENT ON COLUMN "zpWebhooks"."events" IS 'Subscribed event types or wildcard patterns (e.g. message.*, group.*)';
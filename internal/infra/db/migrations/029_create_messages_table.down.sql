@@ -0,0 +1,6 @@
+-- Drop messages table
+DROP TRIGGER IF EXISTS update_zp_messages_updated_at ON "zpMessages";
+DROP INDEX IF EXISTS "idx_zp_messages_text_search";
+DROP INDEX IF EXISTS "idx_zp_messages_chat_timestamp";
+DROP INDEX IF EXISTS "idx_zp_messages_session_chat_message";
+DROP TABLE IF EXISTS "zpMessages";
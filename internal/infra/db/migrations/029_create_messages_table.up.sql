@@ -0,0 +1,41 @@
+-- Create messages table (history of the messages sent and received by sessions)
+CREATE TABLE IF NOT EXISTS "zpMessages" (
+    "id" UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    "sessionId" UUID NOT NULL REFERENCES "zpSessions"("id") ON DELETE CASCADE,
+    "chatJid" VARCHAR(255) NOT NULL,
+    "messageId" VARCHAR(255) NOT NULL,
+    "senderJid" VARCHAR(255) NOT NULL DEFAULT '',
+    "fromMe" BOOLEAN NOT NULL DEFAULT FALSE,
+    "type" VARCHAR(50) NOT NULL,
+    "text" TEXT NOT NULL DEFAULT '',
+    "media" JSONB,
+    "context" JSONB,
+    "timestamp" TIMESTAMP WITH TIME ZONE NOT NULL,
+    "editedAt" TIMESTAMP WITH TIME ZONE,
+    "revokedAt" TIMESTAMP WITH TIME ZONE,
+    "createdAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    "updatedAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for better performance
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_zp_messages_session_chat_message" ON "zpMessages" ("sessionId", "chatJid", "messageId");
+CREATE INDEX IF NOT EXISTS "idx_zp_messages_chat_timestamp" ON "zpMessages" ("sessionId", "chatJid", "timestamp" DESC, "id" DESC);
+CREATE INDEX IF NOT EXISTS "idx_zp_messages_text_search" ON "zpMessages" USING GIN (to_tsvector('simple', "text"));
+
+-- Create trigger to automatically update updatedAt
+CREATE TRIGGER update_zp_messages_updated_at
+    BEFORE UPDATE ON "zpMessages"
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Add comments for documentation
+COMMENT ON TABLE "zpMessages" IS 'History of the messages sent and received by sessions';
+COMMENT ON COLUMN "zpMessages"."chatJid" IS 'WhatsApp chat JID the message belongs to';
+COMMENT ON COLUMN "zpMessages"."messageId" IS 'WhatsApp message ID';
+COMMENT ON COLUMN "zpMessages"."type" IS 'text, image, audio, video, document, sticker, location, contact, contacts or poll';
+COMMENT ON COLUMN "zpMessages"."text" IS 'Text or caption of the message; replaced by the latest edit';
+COMMENT ON COLUMN "zpMessages"."media" IS 'Attachment metadata (mime type, size, dimensions, direct path); the media itself is not stored';
+COMMENT ON COLUMN "zpMessages"."context" IS 'Quoted message, mentions and forwarded flag';
+COMMENT ON COLUMN "zpMessages"."timestamp" IS 'When the message was sent';
+COMMENT ON COLUMN "zpMessages"."editedAt" IS 'When the text was last edited';
+COMMENT ON COLUMN "zpMessages"."revokedAt" IS 'When the message was revoked for everyone';
@@ -0,0 +1,2 @@
+-- Remove account warnings
+ALTER TABLE "zpSessions" DROP COLUMN IF EXISTS "warning";
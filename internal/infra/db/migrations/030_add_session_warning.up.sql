@@ -0,0 +1,4 @@
+-- Keep the last account warning (temporary ban, ban, restriction) reported for a session
+ALTER TABLE "zpSessions" ADD COLUMN IF NOT EXISTS "warning" JSONB;
+
+COMMENT ON COLUMN "zpSessions"."warning" IS 'Last account violation or ban warning reported by WhatsApp, kept until cleared through the API';
@@ -0,0 +1,2 @@
+-- Drop message statistics table
+DROP TABLE IF EXISTS "zpMessageStats";
@@ -0,0 +1,17 @@
+-- Create message statistics table (one row per session, day, message type and direction)
+CREATE TABLE IF NOT EXISTS "zpMessageStats" (
+    "sessionId" UUID NOT NULL REFERENCES "zpSessions"("id") ON DELETE CASCADE,
+    "day" VARCHAR(10) NOT NULL,
+    "type" VARCHAR(50) NOT NULL,
+    "direction" VARCHAR(10) NOT NULL,
+    "count" BIGINT NOT NULL DEFAULT 0,
+    "updatedAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY ("sessionId", "day", "type", "direction")
+);
+
+-- Add comments for documentation
+COMMENT ON TABLE "zpMessageStats" IS 'Daily message counters of sessions by message type and direction';
+COMMENT ON COLUMN "zpMessageStats"."day" IS 'UTC day (YYYY-MM-DD) the messages were sent or received';
+COMMENT ON COLUMN "zpMessageStats"."type" IS 'text, image, audio, video, document, sticker, location, contact, contacts, poll, reaction, list, template, ...';
+COMMENT ON COLUMN "zpMessageStats"."direction" IS 'inbound or outbound; messages sent from the phone count as outbound';
+COMMENT ON COLUMN "zpMessageStats"."count" IS 'Number of messages in the day';
@@ -0,0 +1,5 @@
+-- Remove outgoing message actors
+DROP INDEX IF EXISTS "idx_zp_messages_session_message";
+ALTER TABLE "zpMessages" DROP COLUMN IF EXISTS "actorId";
+ALTER TABLE "zpMessages" DROP COLUMN IF EXISTS "actorType";
+ALTER TABLE "zpMessageQueue" DROP COLUMN IF EXISTS "actor";
@@ -0,0 +1,13 @@
+-- Record who made the session send each outgoing message
+ALTER TABLE "zpMessages" ADD COLUMN IF NOT EXISTS "actorType" VARCHAR(32);
+ALTER TABLE "zpMessages" ADD COLUMN IF NOT EXISTS "actorId" VARCHAR(255);
+
+-- Queued sends keep their actor until they are sent
+ALTER TABLE "zpMessageQueue" ADD COLUMN IF NOT EXISTS "actor" JSONB;
+
+-- Sent messages are attributed by session and message ID, without their chat
+CREATE INDEX IF NOT EXISTS "idx_zp_messages_session_message" ON "zpMessages" ("sessionId", "messageId");
+
+COMMENT ON COLUMN "zpMessages"."actorType" IS 'What triggered an outgoing message: api, chatwoot, autoresponder, campaign or scheduler';
+COMMENT ON COLUMN "zpMessages"."actorId" IS 'Masked API key of api sends, or the item of the integration that caused the send';
+COMMENT ON COLUMN "zpMessageQueue"."actor" IS 'Actor of the request that queued the message, given to the message once sent';
@@ -0,0 +1,2 @@
+-- Remove the inbound webhook secret from chatwoot config
+ALTER TABLE "zpChatwoot" DROP COLUMN IF EXISTS "webhookSecret";
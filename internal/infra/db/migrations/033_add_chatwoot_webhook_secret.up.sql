@@ -0,0 +1,4 @@
+-- Shared secret inbound Chatwoot webhooks must present
+ALTER TABLE "zpChatwoot" ADD COLUMN IF NOT EXISTS "webhookSecret" VARCHAR(255);
+
+COMMENT ON COLUMN "zpChatwoot"."webhookSecret" IS 'Optional shared secret; inbound webhooks must send it as the token query parameter or sign the body with it (X-Chatwoot-Signature)';
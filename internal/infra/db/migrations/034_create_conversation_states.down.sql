@@ -0,0 +1,7 @@
+-- Remove conversation state routing and drop conversation states table
+ALTER TABLE "zpRoutingRules" DROP COLUMN IF EXISTS "nextState";
+ALTER TABLE "zpRoutingRules" DROP COLUMN IF EXISTS "replyIds";
+ALTER TABLE "zpRoutingRules" DROP COLUMN IF EXISTS "states";
+DROP TRIGGER IF EXISTS update_zp_conversation_states_updated_at ON "zpConversationStates";
+DROP INDEX IF EXISTS "idx_zp_conversation_states_expires";
+DROP TABLE IF EXISTS "zpConversationStates";
@@ -0,0 +1,34 @@
+-- Create conversation states table (per chat state of multi-step conversations such as menu bots)
+CREATE TABLE IF NOT EXISTS "zpConversationStates" (
+    "sessionId" UUID NOT NULL REFERENCES "zpSessions"("id") ON DELETE CASCADE,
+    "chatJid" VARCHAR(255) NOT NULL,
+    "state" VARCHAR(100) NOT NULL,
+    "data" JSONB NOT NULL DEFAULT '{}',
+    "expiresAt" TIMESTAMP WITH TIME ZONE,
+    "createdAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    "updatedAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY ("sessionId", "chatJid")
+);
+
+-- Create indexes for better performance
+CREATE INDEX IF NOT EXISTS "idx_zp_conversation_states_expires" ON "zpConversationStates" ("expiresAt") WHERE "expiresAt" IS NOT NULL;
+
+-- Create trigger to automatically update updatedAt
+CREATE TRIGGER update_zp_conversation_states_updated_at
+    BEFORE UPDATE ON "zpConversationStates"
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Let routing rules match and move the conversation state, and match button and list replies
+ALTER TABLE "zpRoutingRules" ADD COLUMN IF NOT EXISTS "states" JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE "zpRoutingRules" ADD COLUMN IF NOT EXISTS "replyIds" JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE "zpRoutingRules" ADD COLUMN IF NOT EXISTS "nextState" VARCHAR(100) NOT NULL DEFAULT '';
+
+-- Add comments for documentation
+COMMENT ON TABLE "zpConversationStates" IS 'Per chat state of multi-step conversations, e.g. the menu level of a bot';
+COMMENT ON COLUMN "zpConversationStates"."state" IS 'Current state name; chats without a row are in the start state';
+COMMENT ON COLUMN "zpConversationStates"."data" IS 'Values collected along the conversation';
+COMMENT ON COLUMN "zpConversationStates"."expiresAt" IS 'When the chat returns to the start state; NULL keeps the state until changed';
+COMMENT ON COLUMN "zpRoutingRules"."states" IS 'Conversation states; the rule matches when the chat is in any';
+COMMENT ON COLUMN "zpRoutingRules"."replyIds" IS 'Button or list row IDs; the rule matches when the message replies with any';
+COMMENT ON COLUMN "zpRoutingRules"."nextState" IS 'Conversation state the chat moves to when the rule matches';
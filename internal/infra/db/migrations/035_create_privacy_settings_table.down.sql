@@ -0,0 +1,2 @@
+-- Drop privacy settings table
+DROP TABLE IF EXISTS "zpPrivacySettings";
@@ -0,0 +1,12 @@
+-- Create privacy settings table (per-session redaction of message content)
+CREATE TABLE IF NOT EXISTS "zpPrivacySettings" (
+    "sessionId" UUID PRIMARY KEY REFERENCES "zpSessions"("id") ON DELETE CASCADE,
+    "redactLogs" BOOLEAN NOT NULL DEFAULT false,
+    "metadataOnlyWebhooks" BOOLEAN NOT NULL DEFAULT false,
+    "updatedAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Add comments for documentation
+COMMENT ON TABLE "zpPrivacySettings" IS 'Per-session privacy mode for regulated customers';
+COMMENT ON COLUMN "zpPrivacySettings"."redactLogs" IS 'Keep message text, captions and contact cards out of the logs';
+COMMENT ON COLUMN "zpPrivacySettings"."metadataOnlyWebhooks" IS 'Deliver message events without their content; receivers fetch it from the history API';
@@ -0,0 +1,6 @@
+-- Drop outbox table
+DROP TRIGGER IF EXISTS update_zp_outbox_updated_at ON "zpOutbox";
+DROP INDEX IF EXISTS "idx_zp_outbox_session_list";
+DROP INDEX IF EXISTS "idx_zp_outbox_chat";
+DROP INDEX IF EXISTS "idx_zp_outbox_due";
+DROP TABLE IF EXISTS "zpOutbox";
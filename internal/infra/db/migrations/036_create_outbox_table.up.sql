@@ -0,0 +1,38 @@
+-- Create outbox table (sends accepted by the enqueue API, sent by a worker with per-chat delays)
+CREATE TABLE IF NOT EXISTS "zpOutbox" (
+    "id" UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    "sessionId" UUID NOT NULL REFERENCES "zpSessions"("id") ON DELETE CASCADE,
+    "chatJid" VARCHAR(255) NOT NULL,
+    "workspace" VARCHAR(255) NOT NULL DEFAULT 'default',
+    "requestId" VARCHAR(255),
+    "actor" JSONB,
+    "request" JSONB NOT NULL,
+    "status" VARCHAR(20) NOT NULL DEFAULT 'queued' CHECK ("status" IN ('queued', 'sending', 'sent', 'failed')),
+    "attempts" INTEGER NOT NULL DEFAULT 0,
+    "messageId" VARCHAR(255),
+    "lastError" TEXT,
+    "sendAfter" TIMESTAMP WITH TIME ZONE NOT NULL,
+    "sentAt" TIMESTAMP WITH TIME ZONE,
+    "createdAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    "updatedAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for better performance
+CREATE INDEX IF NOT EXISTS "idx_zp_outbox_due" ON "zpOutbox" ("status", "sendAfter");
+CREATE INDEX IF NOT EXISTS "idx_zp_outbox_chat" ON "zpOutbox" ("sessionId", "chatJid", "status");
+CREATE INDEX IF NOT EXISTS "idx_zp_outbox_session_list" ON "zpOutbox" ("sessionId", "createdAt" DESC, "id" DESC);
+
+-- Create trigger to automatically update updatedAt
+CREATE TRIGGER update_zp_outbox_updated_at
+    BEFORE UPDATE ON "zpOutbox"
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Add comments for documentation
+COMMENT ON TABLE "zpOutbox" IS 'Messages sent asynchronously, spaced out per chat to look like a person typing';
+COMMENT ON COLUMN "zpOutbox"."chatJid" IS 'Recipient of the message, sends to the same chat are spaced out';
+COMMENT ON COLUMN "zpOutbox"."request" IS 'Send request as received by the API';
+COMMENT ON COLUMN "zpOutbox"."status" IS 'queued, sending, sent or failed';
+COMMENT ON COLUMN "zpOutbox"."sendAfter" IS 'Earliest time the worker sends the message';
+COMMENT ON COLUMN "zpOutbox"."messageId" IS 'WhatsApp message ID once sent';
+COMMENT ON COLUMN "zpOutbox"."lastError" IS 'Error of the last failed send attempt';
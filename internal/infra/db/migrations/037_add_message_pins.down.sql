@@ -0,0 +1,5 @@
+-- Remove the pinned state of stored messages
+DROP INDEX IF EXISTS "idx_zp_messages_pinned";
+ALTER TABLE "zpMessages" DROP COLUMN IF EXISTS "pinnedBy";
+ALTER TABLE "zpMessages" DROP COLUMN IF EXISTS "pinExpiresAt";
+ALTER TABLE "zpMessages" DROP COLUMN IF EXISTS "pinnedAt";
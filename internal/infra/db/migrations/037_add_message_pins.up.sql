@@ -0,0 +1,11 @@
+-- Keep the pinned state of stored messages
+ALTER TABLE "zpMessages" ADD COLUMN IF NOT EXISTS "pinnedAt" TIMESTAMP WITH TIME ZONE;
+ALTER TABLE "zpMessages" ADD COLUMN IF NOT EXISTS "pinExpiresAt" TIMESTAMP WITH TIME ZONE;
+ALTER TABLE "zpMessages" ADD COLUMN IF NOT EXISTS "pinnedBy" VARCHAR(255);
+
+-- Pinned messages of a chat are listed on their own
+CREATE INDEX IF NOT EXISTS "idx_zp_messages_pinned" ON "zpMessages" ("sessionId", "chatJid") WHERE "pinnedAt" IS NOT NULL;
+
+COMMENT ON COLUMN "zpMessages"."pinnedAt" IS 'When the message was pinned in its chat; cleared when it is unpinned';
+COMMENT ON COLUMN "zpMessages"."pinExpiresAt" IS 'When the pin lapses (24 hours, 7 days or 30 days after pinning)';
+COMMENT ON COLUMN "zpMessages"."pinnedBy" IS 'JID of the participant who pinned the message';
@@ -0,0 +1,5 @@
+-- Drop scheduled messages table
+DROP TRIGGER IF EXISTS update_zp_scheduled_messages_updated_at ON "zpScheduledMessages";
+DROP INDEX IF EXISTS "idx_zp_scheduled_messages_session_list";
+DROP INDEX IF EXISTS "idx_zp_scheduled_messages_due";
+DROP TABLE IF EXISTS "zpScheduledMessages";
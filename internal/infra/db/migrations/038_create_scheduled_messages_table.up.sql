@@ -0,0 +1,35 @@
+-- Create scheduled messages table (sends given a scheduleAt, sent by the scheduler once due)
+CREATE TABLE IF NOT EXISTS "zpScheduledMessages" (
+    "id" UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    "sessionId" UUID NOT NULL REFERENCES "zpSessions"("id") ON DELETE CASCADE,
+    "chatJid" VARCHAR(255) NOT NULL,
+    "workspace" VARCHAR(255) NOT NULL DEFAULT 'default',
+    "requestId" VARCHAR(255),
+    "request" JSONB NOT NULL,
+    "status" VARCHAR(20) NOT NULL DEFAULT 'scheduled' CHECK ("status" IN ('scheduled', 'sending', 'sent', 'failed', 'cancelled')),
+    "attempts" INTEGER NOT NULL DEFAULT 0,
+    "messageId" VARCHAR(255),
+    "lastError" TEXT,
+    "scheduledAt" TIMESTAMP WITH TIME ZONE NOT NULL,
+    "sentAt" TIMESTAMP WITH TIME ZONE,
+    "createdAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    "updatedAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for better performance
+CREATE INDEX IF NOT EXISTS "idx_zp_scheduled_messages_due" ON "zpScheduledMessages" ("status", "scheduledAt");
+CREATE INDEX IF NOT EXISTS "idx_zp_scheduled_messages_session_list" ON "zpScheduledMessages" ("sessionId", "createdAt" DESC, "id" DESC);
+
+-- Create trigger to automatically update updatedAt
+CREATE TRIGGER update_zp_scheduled_messages_updated_at
+    BEFORE UPDATE ON "zpScheduledMessages"
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Add comments for documentation
+COMMENT ON TABLE "zpScheduledMessages" IS 'Messages sent at a later time given with scheduleAt';
+COMMENT ON COLUMN "zpScheduledMessages"."request" IS 'Send request as received by the API, without scheduleAt';
+COMMENT ON COLUMN "zpScheduledMessages"."status" IS 'scheduled, sending, sent, failed or cancelled';
+COMMENT ON COLUMN "zpScheduledMessages"."scheduledAt" IS 'When the message is due; sends of a disconnected session are retried for up to a day';
+COMMENT ON COLUMN "zpScheduledMessages"."messageId" IS 'WhatsApp message ID once sent';
+COMMENT ON COLUMN "zpScheduledMessages"."lastError" IS 'Error of the last failed send attempt';
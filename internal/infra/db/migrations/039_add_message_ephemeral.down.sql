@@ -0,0 +1,4 @@
+-- Remove the disappearing messages timer of stored messages
+DROP INDEX IF EXISTS "idx_zp_messages_ephemeral_expires";
+ALTER TABLE "zpMessages" DROP COLUMN IF EXISTS "ephemeralExpiresAt";
+ALTER TABLE "zpMessages" DROP COLUMN IF EXISTS "ephemeralDuration";
@@ -0,0 +1,9 @@
+-- Keep the disappearing messages timer of stored messages
+ALTER TABLE "zpMessages" ADD COLUMN IF NOT EXISTS "ephemeralDuration" INTEGER;
+ALTER TABLE "zpMessages" ADD COLUMN IF NOT EXISTS "ephemeralExpiresAt" TIMESTAMP WITH TIME ZONE;
+
+-- Expired ephemeral messages are left out of the history
+CREATE INDEX IF NOT EXISTS "idx_zp_messages_ephemeral_expires" ON "zpMessages" ("ephemeralExpiresAt") WHERE "ephemeralExpiresAt" IS NOT NULL;
+
+COMMENT ON COLUMN "zpMessages"."ephemeralDuration" IS 'Disappearing messages timer of the chat when the message was sent, in seconds';
+COMMENT ON COLUMN "zpMessages"."ephemeralExpiresAt" IS 'When the message disappears from the chat; it is no longer listed afterwards';
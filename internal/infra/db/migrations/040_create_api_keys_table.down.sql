@@ -0,0 +1,2 @@
+-- Drop API keys table
+DROP TABLE IF EXISTS "zpAPIKeys";
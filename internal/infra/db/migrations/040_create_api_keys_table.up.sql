@@ -0,0 +1,24 @@
+-- Create API keys table (keys besides ZP_API_KEY, scoped to permissions and sessions)
+CREATE TABLE IF NOT EXISTS "zpAPIKeys" (
+    "id" UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    "name" VARCHAR(255) NOT NULL,
+    "prefix" VARCHAR(32) NOT NULL,
+    "keyHash" VARCHAR(64) NOT NULL UNIQUE,
+    "scopes" TEXT[] NOT NULL DEFAULT '{}',
+    "sessionIds" TEXT[] NOT NULL DEFAULT '{}',
+    "workspace" VARCHAR(255),
+    "expiresAt" TIMESTAMP WITH TIME ZONE,
+    "lastUsedAt" TIMESTAMP WITH TIME ZONE,
+    "revokedAt" TIMESTAMP WITH TIME ZONE,
+    "createdAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    "updatedAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Add comments for documentation
+COMMENT ON TABLE "zpAPIKeys" IS 'API keys managed through /admin/apikeys';
+COMMENT ON COLUMN "zpAPIKeys"."prefix" IS 'Start of the key, kept in clear to tell keys apart';
+COMMENT ON COLUMN "zpAPIKeys"."keyHash" IS 'SHA-256 of the key; the key itself is only shown when it is created';
+COMMENT ON COLUMN "zpAPIKeys"."scopes" IS 'send, manage-sessions and/or read-only';
+COMMENT ON COLUMN "zpAPIKeys"."sessionIds" IS 'Sessions the key is restricted to; empty allows every session';
+COMMENT ON COLUMN "zpAPIKeys"."workspace" IS 'Workspace the usage of the key is accounted to';
+COMMENT ON COLUMN "zpAPIKeys"."updatedAt" IS 'Last change of the key; uses only update lastUsedAt';
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS "idx_zp_sessions_name_lower";
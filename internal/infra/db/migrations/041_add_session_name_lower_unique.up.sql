@@ -0,0 +1,19 @@
+-- Session names are unique regardless of case. Existing names that only differ in case must be renamed
+-- before this migration can run; it fails listing them rather than leaving names ambiguous.
+DO $$
+DECLARE
+    collisions TEXT;
+BEGIN
+    SELECT string_agg(names, '; ') INTO collisions FROM (
+        SELECT string_agg("name", ', ' ORDER BY "name") AS names
+        FROM "zpSessions" GROUP BY LOWER("name") HAVING COUNT(*) > 1
+    ) AS duplicated;
+
+    IF collisions IS NOT NULL THEN
+        RAISE EXCEPTION 'zpSessions has session names that only differ in case: %', collisions
+            USING HINT = 'Rename or delete all but one session of each group, then run the migrations again.';
+    END IF;
+END
+$$;
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_zp_sessions_name_lower" ON "zpSessions" (LOWER("name"));
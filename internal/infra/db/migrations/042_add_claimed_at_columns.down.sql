@@ -0,0 +1,4 @@
+ALTER TABLE "zpDrafts" DROP COLUMN IF EXISTS "claimedAt";
+ALTER TABLE "zpMessageQueue" DROP COLUMN IF EXISTS "claimedAt";
+ALTER TABLE "zpScheduledMessages" DROP COLUMN IF EXISTS "claimedAt";
+ALTER TABLE "zpOutbox" DROP COLUMN IF EXISTS "claimedAt";
@@ -0,0 +1,12 @@
+-- Records when a worker moved a row to sending, so rows left behind by a crashed worker can be
+-- handed back to the dispatchers once the claim is old enough
+ALTER TABLE "zpOutbox" ADD COLUMN IF NOT EXISTS "claimedAt" TIMESTAMPTZ;
+ALTER TABLE "zpScheduledMessages" ADD COLUMN IF NOT EXISTS "claimedAt" TIMESTAMPTZ;
+ALTER TABLE "zpMessageQueue" ADD COLUMN IF NOT EXISTS "claimedAt" TIMESTAMPTZ;
+ALTER TABLE "zpDrafts" ADD COLUMN IF NOT EXISTS "claimedAt" TIMESTAMPTZ;
+
+-- Rows already sending start their claim now rather than being released straight away
+UPDATE "zpOutbox" SET "claimedAt" = NOW() WHERE status = 'sending';
+UPDATE "zpScheduledMessages" SET "claimedAt" = NOW() WHERE status = 'sending';
+UPDATE "zpMessageQueue" SET "claimedAt" = NOW() WHERE status = 'sending';
+UPDATE "zpDrafts" SET "claimedAt" = NOW() WHERE status = 'sending';
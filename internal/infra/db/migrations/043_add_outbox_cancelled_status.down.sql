@@ -0,0 +1,7 @@
+-- Remove the cancelled status of outbox messages
+DELETE FROM "zpOutbox" WHERE "status" = 'cancelled';
+ALTER TABLE "zpOutbox" DROP CONSTRAINT IF EXISTS "zpOutbox_status_check";
+ALTER TABLE "zpOutbox" ADD CONSTRAINT "zpOutbox_status_check"
+    CHECK ("status" IN ('queued', 'sending', 'sent', 'failed'));
+
+COMMENT ON COLUMN "zpOutbox"."status" IS 'queued, sending, sent or failed';
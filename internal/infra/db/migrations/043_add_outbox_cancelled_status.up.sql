@@ -0,0 +1,6 @@
+-- Allow outbox messages to be cancelled before they are sent
+ALTER TABLE "zpOutbox" DROP CONSTRAINT IF EXISTS "zpOutbox_status_check";
+ALTER TABLE "zpOutbox" ADD CONSTRAINT "zpOutbox_status_check"
+    CHECK ("status" IN ('queued', 'sending', 'sent', 'failed', 'cancelled'));
+
+COMMENT ON COLUMN "zpOutbox"."status" IS 'queued, sending, sent, failed or cancelled';
@@ -0,0 +1 @@
+ALTER TABLE "zpDrafts" DROP COLUMN IF EXISTS "workspace";
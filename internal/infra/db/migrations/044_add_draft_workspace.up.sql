@@ -0,0 +1,4 @@
+-- Scheduled drafts are accounted to the workspace that scheduled them, like scheduled messages
+ALTER TABLE "zpDrafts" ADD COLUMN IF NOT EXISTS "workspace" VARCHAR(255) NOT NULL DEFAULT 'default';
+
+COMMENT ON COLUMN "zpDrafts"."workspace" IS 'Workspace the draft is accounted to once the scheduler sends it';
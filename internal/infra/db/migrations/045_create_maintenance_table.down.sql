@@ -0,0 +1,2 @@
+-- Drop maintenance table
+DROP TABLE IF EXISTS "zpMaintenance";
@@ -0,0 +1,15 @@
+-- Create maintenance table (instance maintenance and frozen sessions, shared by all replicas)
+CREATE TABLE IF NOT EXISTS "zpMaintenance" (
+    "sessionId" VARCHAR(255) PRIMARY KEY,
+    "sessionName" VARCHAR(255) NOT NULL DEFAULT '',
+    "reason" TEXT NOT NULL DEFAULT '',
+    "retryAfterSeconds" INTEGER NOT NULL DEFAULT 0,
+    "since" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    "updatedAt" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Add comments for documentation
+COMMENT ON TABLE "zpMaintenance" IS 'Maintenance state; replicas poll it and refuse sends while a row applies to them';
+COMMENT ON COLUMN "zpMaintenance"."sessionId" IS 'Frozen session, or an empty string for the whole instance';
+COMMENT ON COLUMN "zpMaintenance"."retryAfterSeconds" IS 'Retry-After sent with refused sends during instance maintenance';
+COMMENT ON COLUMN "zpMaintenance"."since" IS 'When the instance or the session entered maintenance';
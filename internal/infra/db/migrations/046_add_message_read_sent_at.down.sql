@@ -0,0 +1 @@
+ALTER TABLE "zpMessage" DROP COLUMN IF EXISTS "readSentAt";
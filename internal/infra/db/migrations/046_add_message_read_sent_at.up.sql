@@ -0,0 +1,4 @@
+-- Track which WhatsApp messages were already acknowledged with a read receipt after an agent read them
+ALTER TABLE "zpMessage" ADD COLUMN IF NOT EXISTS "readSentAt" TIMESTAMP WITH TIME ZONE;
+
+COMMENT ON COLUMN "zpMessage"."readSentAt" IS 'When a WhatsApp read receipt was sent because an agent read the Chatwoot conversation';
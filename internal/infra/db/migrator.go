@@ -1,6 +1,7 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"embed"
 	"fmt"
@@ -25,19 +26,86 @@ type Migration struct {
 	AppliedAt *time.Time
 }
 
+const (
+	// migrationLockKey is the Postgres advisory lock serializing migrations across replicas
+	migrationLockKey = 7206183310
+	// DefaultMigrationLockTimeout is how long a migrator waits for another replica's migrations
+	DefaultMigrationLockTimeout = 5 * time.Minute
+	migrationLockPollInterval   = time.Second
+)
+
 type Migrator struct {
-	db     *sql.DB
-	logger *logger.Logger
+	db          *sql.DB
+	logger      *logger.Logger
+	lockTimeout time.Duration
 }
 
 func NewMigrator(db *sql.DB, logger *logger.Logger) *Migrator {
 	return &Migrator{
-		db:     db,
-		logger: logger,
+		db:          db,
+		logger:      logger,
+		lockTimeout: DefaultMigrationLockTimeout,
 	}
 }
 
+// SetLockTimeout sets how long RunMigrations and Rollback wait for the migration lock
+func (m *Migrator) SetLockTimeout(timeout time.Duration) {
+	if timeout > 0 {
+		m.lockTimeout = timeout
+	}
+}
+
+// RunMigrations applies the pending migrations while holding the migration advisory lock, so replicas
+// starting together apply them once; the others wait and then find the database up to date
 func (m *Migrator) RunMigrations() error {
+	return m.withLock(m.runMigrations)
+}
+
+// withLock runs fn holding the migration advisory lock, waiting up to the lock timeout for it
+func (m *Migrator) withLock(fn func() error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), m.lockTimeout)
+	defer cancel()
+
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get connection for migration lock: %w", err)
+	}
+	defer conn.Close()
+
+	waiting := false
+	for {
+		var acquired bool
+		if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, migrationLockKey).Scan(&acquired); err != nil {
+			return fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+		if acquired {
+			break
+		}
+
+		if !waiting {
+			m.logger.InfoWithFields("Waiting for migrations running elsewhere", map[string]interface{}{
+				"timeout": m.lockTimeout.String(),
+			})
+			waiting = true
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out after %s waiting for migration lock", m.lockTimeout)
+		case <-time.After(migrationLockPollInterval):
+		}
+	}
+
+	defer func() {
+		if _, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, migrationLockKey); err != nil {
+			m.logger.Error("Failed to release migration lock: " + err.Error())
+		}
+	}()
+
+	return fn()
+}
+
+func (m *Migrator) runMigrations() error {
 	m.logger.Info("Starting database migrations...")
 
 	if err := m.createMigrationsTable(); err != nil {
@@ -304,6 +372,10 @@ func (m *Migrator) executeMigration(migration *Migration) error {
 }
 
 func (m *Migrator) Rollback() error {
+	return m.withLock(m.rollback)
+}
+
+func (m *Migrator) rollback() error {
 	m.logger.Info("Rolling back last migration...")
 
 	// Get last migration info
@@ -0,0 +1,207 @@
+(function () {
+  'use strict';
+
+  var KEY_STORAGE = 'zpwoot.apiKey';
+  var QR_REFRESH_MS = 5000;
+
+  var state = { sessions: [], selected: null, qrTimer: null };
+
+  function $(id) { return document.getElementById(id); }
+
+  function apiKey() { return localStorage.getItem(KEY_STORAGE) || ''; }
+
+  function api(method, path, body) {
+    var opts = { method: method, headers: { 'Authorization': apiKey() } };
+    if (body !== undefined) {
+      opts.headers['Content-Type'] = 'application/json';
+      opts.body = JSON.stringify(body);
+    }
+    return fetch(path, opts).then(function (res) {
+      return res.json().catch(function () { return {}; }).then(function (payload) {
+        if (res.status === 401) {
+          showLogin('Invalid API key');
+          throw new Error('unauthorized');
+        }
+        if (!res.ok || payload.success === false) {
+          throw new Error(payload.error || ('HTTP ' + res.status));
+        }
+        return payload.data;
+      });
+    });
+  }
+
+  function el(tag, attrs, children) {
+    var node = document.createElement(tag);
+    Object.keys(attrs || {}).forEach(function (k) {
+      if (k === 'text') { node.textContent = attrs[k]; } else if (k === 'onclick') { node.onclick = attrs[k]; } else { node.setAttribute(k, attrs[k]); }
+    });
+    (children || []).forEach(function (c) { node.appendChild(c); });
+    return node;
+  }
+
+  function formatTime(value) {
+    return value ? new Date(value).toLocaleString() : '';
+  }
+
+  function setStatus(text) { $('status').textContent = text; }
+
+  function showLogin(message) {
+    $('app').hidden = true;
+    $('logout').hidden = true;
+    $('login').hidden = false;
+    setStatus(message || '');
+  }
+
+  function showApp() {
+    $('login').hidden = true;
+    $('app').hidden = false;
+    $('logout').hidden = false;
+    setStatus('');
+    loadSessions();
+    loadFailures();
+  }
+
+  function loadSessions() {
+    return api('GET', '/sessions/list?limit=100').then(function (data) {
+      state.sessions = (data && data.sessions) || [];
+      renderSessions();
+      if (!state.selected && state.sessions.length) {
+        selectSession(state.sessions[0].session);
+      }
+    }).catch(function (err) { setStatus('Sessions: ' + err.message); });
+  }
+
+  function renderSessions() {
+    var tbody = $('sessions');
+    var select = $('send-session');
+    tbody.innerHTML = '';
+    select.innerHTML = '';
+
+    state.sessions.forEach(function (item) {
+      var s = item.session;
+      var badge = el('span', { 'class': 'badge ' + (s.isConnected ? 'on' : 'off'), text: s.isConnected ? 'connected' : 'disconnected' });
+      var actions = el('td', {}, [
+        el('button', { text: 'Messages', onclick: function () { selectSession(s); } }),
+        el('button', { text: s.isConnected ? 'Reconnect' : 'Connect', onclick: function () { connect(s); } }),
+        el('button', { text: 'QR', onclick: function () { showQR(s); } })
+      ]);
+      tbody.appendChild(el('tr', {}, [
+        el('td', { text: s.name, title: s.id }),
+        el('td', {}, [badge]),
+        el('td', { text: s.deviceJid || '' }),
+        actions
+      ]));
+      select.appendChild(el('option', { value: s.id, text: s.name }));
+    });
+
+    if (state.selected) { select.value = state.selected.id; }
+  }
+
+  function connect(s) {
+    api('POST', '/sessions/' + encodeURIComponent(s.id) + '/connect').then(function () {
+      setStatus('Connecting ' + s.name + '...');
+      if (!s.isConnected) { showQR(s); }
+    }).catch(function (err) { setStatus('Connect: ' + err.message); });
+  }
+
+  function showQR(s) {
+    stopQR();
+    $('qr-panel').hidden = false;
+    $('qr-session').textContent = s.name;
+    var refresh = function () {
+      api('GET', '/sessions/' + encodeURIComponent(s.id) + '/qr').then(function (data) {
+        if (data && data.qrCodeImage) { $('qr-image').src = data.qrCodeImage; }
+      }).catch(function (err) {
+        setStatus('QR: ' + err.message);
+        loadSessions();
+      });
+    };
+    refresh();
+    state.qrTimer = setInterval(refresh, QR_REFRESH_MS);
+  }
+
+  function stopQR() {
+    if (state.qrTimer) { clearInterval(state.qrTimer); }
+    state.qrTimer = null;
+    $('qr-panel').hidden = true;
+    $('qr-image').removeAttribute('src');
+  }
+
+  function selectSession(s) {
+    state.selected = s;
+    $('messages-session').textContent = s.name;
+    $('send-session').value = s.id;
+    loadMessages();
+  }
+
+  function loadMessages() {
+    if (!state.selected) { return; }
+    api('GET', '/admin/dashboard/messages?limit=50&sessionId=' + encodeURIComponent(state.selected.id)).then(function (data) {
+      var list = $('messages');
+      list.innerHTML = '';
+      var messages = (data && data.messages) || [];
+      if (!messages.length) { list.appendChild(el('li', { 'class': 'muted', text: 'No messages since the server started.' })); }
+      messages.forEach(function (m) {
+        list.appendChild(el('li', {}, [
+          el('div', { 'class': m.fromMe ? 'from-me' : '', text: (m.fromMe ? 'me → ' + m.chat : m.sender) + ' · ' + m.type }),
+          el('div', { text: m.text || '' }),
+          el('div', { 'class': 'muted', text: formatTime(m.timestamp) })
+        ]));
+      });
+    }).catch(function (err) { setStatus('Messages: ' + err.message); });
+  }
+
+  function loadFailures() {
+    api('GET', '/admin/dashboard/webhook-failures?limit=50').then(function (data) {
+      var tbody = $('failures');
+      tbody.innerHTML = '';
+      var failures = (data && data.failures) || [];
+      if (!failures.length) { tbody.appendChild(el('tr', {}, [el('td', { colspan: '5', 'class': 'muted', text: 'No failed deliveries since the server started.' })])); }
+      failures.forEach(function (f) {
+        tbody.appendChild(el('tr', {}, [
+          el('td', { text: formatTime(f.failedAt) }),
+          el('td', { text: f.eventType }),
+          el('td', { text: f.url }),
+          el('td', { text: f.statusCode ? String(f.statusCode) : '-' }),
+          el('td', { 'class': 'error', text: f.error || '' })
+        ]));
+      });
+    }).catch(function (err) { setStatus('Webhook failures: ' + err.message); });
+  }
+
+  function sendText(event) {
+    event.preventDefault();
+    var sessionID = $('send-session').value;
+    $('send-result').textContent = 'Sending...';
+    api('POST', '/sessions/' + encodeURIComponent(sessionID) + '/messages/send/text', {
+      remoteJid: $('send-to').value.trim(),
+      body: $('send-body').value
+    }).then(function (data) {
+      $('send-result').textContent = 'Sent ' + ((data && data.id) || '');
+      $('send-body').value = '';
+    }).catch(function (err) { $('send-result').textContent = 'Failed: ' + err.message; });
+  }
+
+  $('login-form').addEventListener('submit', function (event) {
+    event.preventDefault();
+    localStorage.setItem(KEY_STORAGE, $('api-key').value.trim());
+    $('api-key').value = '';
+    showApp();
+  });
+  $('logout').addEventListener('click', function () {
+    localStorage.removeItem(KEY_STORAGE);
+    stopQR();
+    showLogin();
+  });
+  $('refresh-sessions').addEventListener('click', loadSessions);
+  $('refresh-messages').addEventListener('click', loadMessages);
+  $('refresh-failures').addEventListener('click', loadFailures);
+  $('qr-close').addEventListener('click', stopQR);
+  $('send-session').addEventListener('change', function () {
+    var id = $('send-session').value;
+    state.sessions.forEach(function (item) { if (item.session.id === id) { selectSession(item.session); } });
+  });
+  $('send-form').addEventListener('submit', sendText);
+
+  if (apiKey()) { showApp(); } else { showLogin(); }
+})();
@@ -0,0 +1,21 @@
+// Package dashboard embeds the static assets of the web dashboard served at /dashboard.
+package dashboard
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+//go:embed assets/*
+var assets embed.FS
+
+// FileSystem returns the dashboard assets rooted at the assets directory
+func FileSystem() http.FileSystem {
+	sub, err := fs.Sub(assets, "assets")
+	if err != nil {
+		// The assets directory is embedded at build time, so this cannot fail at runtime
+		panic(err)
+	}
+	return http.FS(sub)
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"zpwoot/internal/app/apikey"
+	"zpwoot/internal/app/common"
+	domainAPIKey "zpwoot/internal/domain/apikey"
+	"zpwoot/platform/logger"
+)
+
+type APIKeyHandler struct {
+	logger   *logger.Logger
+	apiKeyUC apikey.UseCase
+}
+
+func NewAPIKeyHandler(appLogger *logger.Logger, apiKeyUC apikey.UseCase) *APIKeyHandler {
+	return &APIKeyHandler{
+		logger:   appLogger,
+		apiKeyUC: apiKeyUC,
+	}
+}
+
+// @Summary Create API key
+// @Description Create an API key limited to scopes (send, manage-sessions, read-only) and optionally to some sessions.
+// @Description The key is only returned in this response; only its hash is stored. Requires the global API key.
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param request body apikey.CreateAPIKeyRequest true "API key"
+// @Success 201 {object} common.SuccessResponse{data=apikey.CreateAPIKeyResponse} "API key created successfully"
+// @Failure 400 {object} object "Invalid name, scopes, sessions or expiresAt"
+// @Failure 403 {object} object "Global API key required"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /admin/apikeys [post]
+func (h *APIKeyHandler) CreateKey(c *fiber.Ctx) error {
+	var req apikey.CreateAPIKeyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid request body"))
+	}
+
+	response, err := h.apiKeyUC.CreateKey(c.Context(), &req)
+	if err != nil {
+		return h.handleError(c, "create API key", err)
+	}
+
+	return c.Status(201).JSON(common.NewSuccessResponse(response, "API key created successfully"))
+}
+
+// @Summary List API keys
+// @Description List the stored API keys, newest first, revoked and expired ones included
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} common.SuccessResponse{data=apikey.ListAPIKeysResponse} "API keys retrieved successfully"
+// @Failure 403 {object} object "Global API key required"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /admin/apikeys [get]
+func (h *APIKeyHandler) ListKeys(c *fiber.Ctx) error {
+	response, err := h.apiKeyUC.ListKeys(c.Context())
+	if err != nil {
+		return h.handleError(c, "list API keys", err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "API keys retrieved successfully"))
+}
+
+// @Summary Get API key
+// @Description Get a stored API key with its scopes, sessions, status and last use
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Param keyId path string true "API key ID" example("4f1c2a8e-5b7d-4e3f-9a6c-1d2e3f4a5b6c")
+// @Success 200 {object} common.SuccessResponse{data=apikey.APIKeyResponse} "API key retrieved successfully"
+// @Failure 400 {object} object "Invalid API key ID"
+// @Failure 404 {object} object "API key not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /admin/apikeys/{keyId} [get]
+func (h *APIKeyHandler) GetKey(c *fiber.Ctx) error {
+	keyID, err := uuid.Parse(c.Params("keyId"))
+	if err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid API key ID"))
+	}
+
+	response, err := h.apiKeyUC.GetKey(c.Context(), keyID)
+	if err != nil {
+		return h.handleError(c, "get API key", err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "API key retrieved successfully"))
+}
+
+// @Summary Update API key
+// @Description Change the name, scopes, sessions or workspace of an API key. Changes reach every replica within 30 seconds.
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param keyId path string true "API key ID" example("4f1c2a8e-5b7d-4e3f-9a6c-1d2e3f4a5b6c")
+// @Param request body apikey.UpdateAPIKeyRequest true "Fields to change"
+// @Success 200 {object} common.SuccessResponse{data=apikey.APIKeyResponse} "API key updated successfully"
+// @Failure 400 {object} object "Invalid name, scopes or sessions"
+// @Failure 404 {object} object "API key not found"
+// @Failure 409 {object} object "API key has been revoked"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /admin/apikeys/{keyId} [patch]
+func (h *APIKeyHandler) UpdateKey(c *fiber.Ctx) error {
+	keyID, err := uuid.Parse(c.Params("keyId"))
+	if err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid API key ID"))
+	}
+
+	var req apikey.UpdateAPIKeyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid request body"))
+	}
+
+	response, err := h.apiKeyUC.UpdateKey(c.Context(), keyID, &req)
+	if err != nil {
+		return h.handleError(c, "update API key", err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "API key updated successfully"))
+}
+
+// @Summary Revoke API key
+// @Description Revoke an API key for good. It stops working on every replica within 30 seconds.
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Param keyId path string true "API key ID" example("4f1c2a8e-5b7d-4e3f-9a6c-1d2e3f4a5b6c")
+// @Success 200 {object} common.SuccessResponse{data=apikey.APIKeyResponse} "API key revoked successfully"
+// @Failure 400 {object} object "Invalid API key ID"
+// @Failure 404 {object} object "API key not found"
+// @Failure 409 {object} object "API key is already revoked"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /admin/apikeys/{keyId} [delete]
+func (h *APIKeyHandler) RevokeKey(c *fiber.Ctx) error {
+	keyID, err := uuid.Parse(c.Params("keyId"))
+	if err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid API key ID"))
+	}
+
+	response, err := h.apiKeyUC.RevokeKey(c.Context(), keyID)
+	if err != nil {
+		return h.handleError(c, "revoke API key", err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "API key revoked successfully"))
+}
+
+// handleError maps API key domain errors to HTTP responses
+func (h *APIKeyHandler) handleError(c *fiber.Ctx, action string, err error) error {
+	switch {
+	case errors.Is(err, domainAPIKey.ErrAPIKeyNotFound):
+		return c.Status(404).JSON(common.NewErrorResponse(err.Error()))
+	case errors.Is(err, domainAPIKey.ErrNameRequired),
+		errors.Is(err, domainAPIKey.ErrScopesRequired),
+		errors.Is(err, domainAPIKey.ErrInvalidScope),
+		errors.Is(err, domainAPIKey.ErrUnknownSession),
+		errors.Is(err, domainAPIKey.ErrExpiresInPast):
+		return c.Status(400).JSON(common.NewErrorResponse(err.Error()))
+	case errors.Is(err, domainAPIKey.ErrAPIKeyRevoked), errors.Is(err, domainAPIKey.ErrAlreadyRevoked):
+		return c.Status(409).JSON(common.NewErrorResponseWithCode(err.Error(), "API_KEY_REVOKED"))
+	}
+
+	h.logger.ErrorWithFields("Failed to "+action, map[string]interface{}{
+		"error": err.Error(),
+	})
+	return c.Status(500).JSON(common.NewErrorResponse("Failed to " + action))
+}
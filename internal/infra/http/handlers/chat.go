@@ -0,0 +1,361 @@
+package handlers
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/gofiber/fiber/v2"
+
+	"zpwoot/internal/app/chat"
+	"zpwoot/internal/app/common"
+	domainChat "zpwoot/internal/domain/chat"
+	"zpwoot/internal/domain/session"
+	"zpwoot/internal/infra/http/helpers"
+	"zpwoot/platform/logger"
+)
+
+type ChatHandler struct {
+	logger          *logger.Logger
+	chatUC          chat.UseCase
+	sessionResolver *helpers.SessionResolver
+}
+
+func NewChatHandler(appLogger *logger.Logger, chatUC chat.UseCase, sessionRepo helpers.SessionRepository) *ChatHandler {
+	return &ChatHandler{
+		logger:          appLogger,
+		chatUC:          chatUC,
+		sessionResolver: helpers.NewSessionResolver(appLogger, sessionRepo),
+	}
+}
+
+// @Summary Archive chat
+// @Description Archive a chat on the phone and every linked device. Archiving also unpins the chat
+// @Tags Chats
+// @Security ApiKeyAuth
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param jid path string true "Chat JID" example("5511999999999@s.whatsapp.net")
+// @Success 200 {object} common.SuccessResponse{data=chat.ChatActionResponse} "Chat archived successfully"
+// @Failure 400 {object} object "Bad Request"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/chats/{jid}/archive [post]
+func (h *ChatHandler) ArchiveChat(c *fiber.Ctx) error {
+	return h.setArchived(c, true, "Chat archived successfully")
+}
+
+// @Summary Unarchive chat
+// @Description Move a chat out of the archive on the phone and every linked device
+// @Tags Chats
+// @Security ApiKeyAuth
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param jid path string true "Chat JID" example("5511999999999@s.whatsapp.net")
+// @Success 200 {object} common.SuccessResponse{data=chat.ChatActionResponse} "Chat unarchived successfully"
+// @Failure 400 {object} object "Bad Request"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/chats/{jid}/unarchive [post]
+func (h *ChatHandler) UnarchiveChat(c *fiber.Ctx) error {
+	return h.setArchived(c, false, "Chat unarchived successfully")
+}
+
+func (h *ChatHandler) setArchived(c *fiber.Ctx, archive bool, successMessage string) error {
+	sess, chatJID, fiberErr := h.resolveChat(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	response, err := h.chatUC.Archive(c.Context(), sess.ID.String(), chatJID, archive)
+	if err != nil {
+		return h.handleError(c, "update chat archive", err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, successMessage))
+}
+
+// @Summary Pin chat
+// @Description Pin a chat to the top of the chat list on the phone and every linked device
+// @Tags Chats
+// @Security ApiKeyAuth
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param jid path string true "Chat JID" example("5511999999999@s.whatsapp.net")
+// @Success 200 {object} common.SuccessResponse{data=chat.ChatActionResponse} "Chat pinned successfully"
+// @Failure 400 {object} object "Bad Request"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/chats/{jid}/pin [post]
+func (h *ChatHandler) PinChat(c *fiber.Ctx) error {
+	return h.setPinned(c, true, "Chat pinned successfully")
+}
+
+// @Summary Unpin chat
+// @Description Unpin a chat on the phone and every linked device
+// @Tags Chats
+// @Security ApiKeyAuth
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param jid path string true "Chat JID" example("5511999999999@s.whatsapp.net")
+// @Success 200 {object} common.SuccessResponse{data=chat.ChatActionResponse} "Chat unpinned successfully"
+// @Failure 400 {object} object "Bad Request"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/chats/{jid}/unpin [post]
+func (h *ChatHandler) UnpinChat(c *fiber.Ctx) error {
+	return h.setPinned(c, false, "Chat unpinned successfully")
+}
+
+func (h *ChatHandler) setPinned(c *fiber.Ctx, pin bool, successMessage string) error {
+	sess, chatJID, fiberErr := h.resolveChat(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	response, err := h.chatUC.Pin(c.Context(), sess.ID.String(), chatJID, pin)
+	if err != nil {
+		return h.handleError(c, "update chat pin", err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, successMessage))
+}
+
+// @Summary Mute chat
+// @Description Mute the notifications of a chat for durationSeconds, or until unmuted when it is 0 or omitted
+// @Tags Chats
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param jid path string true "Chat JID" example("5511999999999@s.whatsapp.net")
+// @Param request body chat.MuteChatRequest false "Mute duration"
+// @Success 200 {object} common.SuccessResponse{data=chat.ChatActionResponse} "Chat muted successfully"
+// @Failure 400 {object} object "Bad Request"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/chats/{jid}/mute [post]
+func (h *ChatHandler) MuteChat(c *fiber.Ctx) error {
+	sess, chatJID, fiberErr := h.resolveChat(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	var req chat.MuteChatRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(common.NewErrorResponse("Invalid request body"))
+		}
+	}
+
+	response, err := h.chatUC.Mute(c.Context(), sess.ID.String(), chatJID, &req)
+	if err != nil {
+		return h.handleError(c, "mute chat", err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "Chat muted successfully"))
+}
+
+// @Summary Unmute chat
+// @Description Unmute the notifications of a chat
+// @Tags Chats
+// @Security ApiKeyAuth
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param jid path string true "Chat JID" example("5511999999999@s.whatsapp.net")
+// @Success 200 {object} common.SuccessResponse{data=chat.ChatActionResponse} "Chat unmuted successfully"
+// @Failure 400 {object} object "Bad Request"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/chats/{jid}/unmute [post]
+func (h *ChatHandler) UnmuteChat(c *fiber.Ctx) error {
+	sess, chatJID, fiberErr := h.resolveChat(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	response, err := h.chatUC.Unmute(c.Context(), sess.ID.String(), chatJID)
+	if err != nil {
+		return h.handleError(c, "unmute chat", err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "Chat unmuted successfully"))
+}
+
+// @Summary Mark chat as read
+// @Description Mark a whole chat as read on the phone and every linked device, clearing its unread badge. Unlike mark-read, no read receipts are sent to the contact
+// @Tags Chats
+// @Security ApiKeyAuth
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param jid path string true "Chat JID" example("5511999999999@s.whatsapp.net")
+// @Success 200 {object} common.SuccessResponse{data=chat.ChatActionResponse} "Chat marked as read successfully"
+// @Failure 400 {object} object "Bad Request"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/chats/{jid}/read [post]
+func (h *ChatHandler) ReadChat(c *fiber.Ctx) error {
+	return h.setRead(c, true, "Chat marked as read successfully")
+}
+
+// @Summary Mark chat as unread
+// @Description Mark a chat as unread on the phone and every linked device
+// @Tags Chats
+// @Security ApiKeyAuth
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param jid path string true "Chat JID" example("5511999999999@s.whatsapp.net")
+// @Success 200 {object} common.SuccessResponse{data=chat.ChatActionResponse} "Chat marked as unread successfully"
+// @Failure 400 {object} object "Bad Request"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/chats/{jid}/unread [post]
+func (h *ChatHandler) UnreadChat(c *fiber.Ctx) error {
+	return h.setRead(c, false, "Chat marked as unread successfully")
+}
+
+func (h *ChatHandler) setRead(c *fiber.Ctx, read bool, successMessage string) error {
+	sess, chatJID, fiberErr := h.resolveChat(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	response, err := h.chatUC.MarkRead(c.Context(), sess.ID.String(), chatJID, read)
+	if err != nil {
+		return h.handleError(c, "update chat read state", err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, successMessage))
+}
+
+// @Summary Clear chat
+// @Description Delete the messages of a chat on the phone and every linked device, keeping the chat itself
+// @Tags Chats
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param jid path string true "Chat JID" example("5511999999999@s.whatsapp.net")
+// @Param request body chat.ClearChatRequest false "Clear options"
+// @Success 200 {object} common.SuccessResponse{data=chat.ChatActionResponse} "Chat cleared successfully"
+// @Failure 400 {object} object "Bad Request"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/chats/{jid}/clear [post]
+func (h *ChatHandler) ClearChat(c *fiber.Ctx) error {
+	sess, chatJID, fiberErr := h.resolveChat(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	var req chat.ClearChatRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(common.NewErrorResponse("Invalid request body"))
+		}
+	}
+
+	response, err := h.chatUC.Clear(c.Context(), sess.ID.String(), chatJID, &req)
+	if err != nil {
+		return h.handleError(c, "clear chat", err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "Chat cleared successfully"))
+}
+
+// @Summary Delete chat
+// @Description Delete a chat with its messages on the phone and every linked device
+// @Tags Chats
+// @Security ApiKeyAuth
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param jid path string true "Chat JID" example("5511999999999@s.whatsapp.net")
+// @Param deleteMedia query bool false "Also delete the media of the messages from the devices"
+// @Success 200 {object} common.SuccessResponse{data=chat.ChatActionResponse} "Chat deleted successfully"
+// @Failure 400 {object} object "Bad Request"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/chats/{jid} [delete]
+func (h *ChatHandler) DeleteChat(c *fiber.Ctx) error {
+	sess, chatJID, fiberErr := h.resolveChat(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	response, err := h.chatUC.Delete(c.Context(), sess.ID.String(), chatJID, c.QueryBool("deleteMedia"))
+	if err != nil {
+		return h.handleError(c, "delete chat", err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "Chat deleted successfully"))
+}
+
+// @Summary Show typing in chat
+// @Description Show the session typing in a chat, e.g. while an agent writes a reply, or stop showing it. A started indicator is kept visible until stopped or until timeoutSeconds (25 when omitted, at most 300) pass; starting again extends it. Sending a message also clears it on the recipient's side.
+// @Tags Chats
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param jid path string true "Chat JID" example("5511999999999@s.whatsapp.net")
+// @Param request body chat.TypingRequest true "Start or stop typing"
+// @Success 200 {object} common.SuccessResponse{data=chat.ChatActionResponse} "Typing updated successfully"
+// @Failure 400 {object} object "Invalid action or timeout"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/chats/{jid}/typing [post]
+func (h *ChatHandler) Typing(c *fiber.Ctx) error {
+	sess, chatJID, fiberErr := h.resolveChat(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	var req chat.TypingRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid request body"))
+	}
+
+	response, err := h.chatUC.Typing(c.Context(), sess.ID.String(), chatJID, &req)
+	if err != nil {
+		return h.handleError(c, "update typing", err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "Typing updated successfully"))
+}
+
+// resolveChat resolves the session and the URL-decoded chat JID path parameter
+func (h *ChatHandler) resolveChat(c *fiber.Ctx) (*session.Session, string, *fiber.Error) {
+	chatJID, err := url.PathUnescape(c.Params("jid"))
+	if err != nil || chatJID == "" {
+		return nil, "", fiber.NewError(400, "Chat JID is required")
+	}
+
+	sessionIdentifier := c.Params("sessionId")
+	if sessionIdentifier == "" {
+		return nil, "", fiber.NewError(400, "Session identifier is required")
+	}
+
+	sess, err := h.sessionResolver.ResolveSession(c.Context(), sessionIdentifier)
+	if err != nil {
+		return nil, "", fiber.NewError(404, "Session not found")
+	}
+
+	return sess, chatJID, nil
+}
+
+// handleError maps chat domain errors to HTTP responses
+func (h *ChatHandler) handleError(c *fiber.Ctx, action string, err error) error {
+	switch {
+	case errors.Is(err, domainChat.ErrInvalidChatJID), errors.Is(err, domainChat.ErrInvalidMuteDuration),
+		errors.Is(err, domainChat.ErrInvalidTypingAction), errors.Is(err, domainChat.ErrInvalidTypingTimeout):
+		return c.Status(400).JSON(common.NewErrorResponse(err.Error()))
+	}
+
+	if handled, respErr := writeQuotaError(c, err); handled {
+		return respErr
+	}
+
+	h.logger.ErrorWithFields("Failed to "+action, map[string]interface{}{
+		"error": err.Error(),
+	})
+	return c.Status(500).JSON(common.NewErrorResponse("Failed to " + action))
+}
@@ -2,16 +2,22 @@ package handlers
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
+	"net/url"
 	"os"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 
 	"zpwoot/internal/app/chatwoot"
+	"zpwoot/internal/app/common"
 	domainChatwoot "zpwoot/internal/domain/chatwoot"
+	"zpwoot/internal/infra/http/helpers"
 	"zpwoot/pkg/errors"
 	"zpwoot/platform/logger"
+	"zpwoot/platform/precondition"
 )
 
 type ChatwootHandler struct {
@@ -44,31 +50,29 @@ func NewChatwootHandler(chatwootUC chatwoot.UseCase, logger *logger.Logger) *Cha
 // @Produce json
 // @Param sessionId path string true "Session ID"
 // @Param request body chatwoot.CreateChatwootConfigRequest true "Chatwoot configuration request"
+// @Param If-Match header string false "ETag from GET /sessions/{sessionId}/chatwoot/find; the update fails with 412 when the configuration changed since"
 // @Success 200 {object} chatwoot.CreateChatwootConfigResponse "Chatwoot configuration set successfully"
 // @Failure 400 {object} object "Bad Request"
 // @Failure 404 {object} object "Session not found"
+// @Failure 412 {object} object "Chatwoot configuration changed since the ETag in If-Match"
 // @Failure 500 {object} object "Internal Server Error"
 // @Router /sessions/{sessionId}/chatwoot/set [post]
 func (h *ChatwootHandler) CreateConfig(c *fiber.Ctx) error {
 	sessionID := c.Params("sessionId")
 	if sessionID == "" {
-		return c.Status(400).JSON(fiber.Map{
-			"error": "Session ID is required",
-		})
+		return c.Status(400).JSON(common.NewErrorResponse("Session ID is required"))
 	}
 
 	var req chatwoot.CreateChatwootConfigRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(400).JSON(fiber.Map{
-			"error": "Invalid request body",
-		})
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid request body"))
 	}
 
 	// Check if auto-create is requested
 	if req.AutoCreate != nil && *req.AutoCreate {
 		// Generate webhook URL dynamically
 		baseURL := h.getBaseURL(c)
-		webhookURL := fmt.Sprintf("%s/chatwoot/webhook/%s", baseURL, sessionID)
+		webhookURL := chatwootWebhookURL(baseURL, sessionID, req.WebhookSecret)
 
 		inboxName := "WhatsApp zpwoot"
 		if req.InboxName != nil && *req.InboxName != "" {
@@ -89,140 +93,87 @@ func (h *ChatwootHandler) CreateConfig(c *fiber.Ctx) error {
 	config, err := h.chatwootUC.CreateConfig(c.Context(), sessionID, &req)
 	if err != nil {
 		if appErr := errors.GetAppError(err); appErr != nil {
-			return c.Status(appErr.Code).JSON(fiber.Map{
-				"error":   appErr.Message,
-				"details": appErr.Details,
-			})
+			return c.Status(appErr.Code).JSON(common.NewErrorResponse(appErr.Message, appErr.Details))
 		}
-		return c.Status(500).JSON(fiber.Map{
-			"error": "Internal server error",
-		})
+		return c.Status(500).JSON(common.NewErrorResponse("Internal server error"))
 	}
 
-	return c.Status(201).JSON(fiber.Map{
-		"success": true,
-		"message": "Chatwoot configuration created successfully",
-		"data":    config,
-	})
+	return c.Status(201).JSON(common.NewSuccessResponse(config, "Chatwoot configuration created successfully"))
 }
 
 func (h *ChatwootHandler) GetConfig(c *fiber.Ctx) error {
 	config, err := h.chatwootUC.GetConfig(c.Context())
 	if err != nil {
 		if appErr := errors.GetAppError(err); appErr != nil {
-			return c.Status(appErr.Code).JSON(fiber.Map{
-				"error": appErr.Message,
-			})
+			return c.Status(appErr.Code).JSON(common.NewErrorResponse(appErr.Message))
 		}
-		return c.Status(500).JSON(fiber.Map{
-			"error": "Internal server error",
-		})
+		return c.Status(500).JSON(common.NewErrorResponse("Internal server error"))
 	}
 
-	return c.JSON(fiber.Map{
-		"success": true,
-		"data":    config,
-	})
+	return c.JSON(common.NewSuccessResponse(config))
 }
 
 func (h *ChatwootHandler) UpdateConfig(c *fiber.Ctx) error {
 	var req chatwoot.UpdateChatwootConfigRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(400).JSON(fiber.Map{
-			"error": "Invalid request body",
-		})
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid request body"))
 	}
 
 	config, err := h.chatwootUC.UpdateConfig(c.Context(), &req)
 	if err != nil {
 		if appErr := errors.GetAppError(err); appErr != nil {
-			return c.Status(appErr.Code).JSON(fiber.Map{
-				"error":   appErr.Message,
-				"details": appErr.Details,
-			})
+			return c.Status(appErr.Code).JSON(common.NewErrorResponse(appErr.Message, appErr.Details))
 		}
-		return c.Status(500).JSON(fiber.Map{
-			"error": "Internal server error",
-		})
+		return c.Status(500).JSON(common.NewErrorResponse("Internal server error"))
 	}
 
-	return c.JSON(fiber.Map{
-		"success": true,
-		"data":    config,
-	})
+	return c.JSON(common.NewSuccessResponse(config))
 }
 
 func (h *ChatwootHandler) DeleteConfig(c *fiber.Ctx) error {
 	err := h.chatwootUC.DeleteConfig(c.Context())
 	if err != nil {
 		if appErr := errors.GetAppError(err); appErr != nil {
-			return c.Status(appErr.Code).JSON(fiber.Map{
-				"error": appErr.Message,
-			})
+			return c.Status(appErr.Code).JSON(common.NewErrorResponse(appErr.Message))
 		}
-		return c.Status(500).JSON(fiber.Map{
-			"error": "Internal server error",
-		})
+		return c.Status(500).JSON(common.NewErrorResponse("Internal server error"))
 	}
 
-	return c.JSON(fiber.Map{
-		"success": true,
-		"message": "Chatwoot configuration deleted successfully",
-	})
+	return c.JSON(common.NewSuccessResponse(nil, "Chatwoot configuration deleted successfully"))
 }
 
 func (h *ChatwootHandler) SyncContacts(c *fiber.Ctx) error {
 	var req chatwoot.SyncContactRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(400).JSON(fiber.Map{
-			"error": "Invalid request body",
-		})
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid request body"))
 	}
 
 	contact, err := h.chatwootUC.SyncContact(c.Context(), &req)
 	if err != nil {
 		if appErr := errors.GetAppError(err); appErr != nil {
-			return c.Status(appErr.Code).JSON(fiber.Map{
-				"error":   appErr.Message,
-				"details": appErr.Details,
-			})
+			return c.Status(appErr.Code).JSON(common.NewErrorResponse(appErr.Message, appErr.Details))
 		}
-		return c.Status(500).JSON(fiber.Map{
-			"error": "Internal server error",
-		})
+		return c.Status(500).JSON(common.NewErrorResponse("Internal server error"))
 	}
 
-	return c.JSON(fiber.Map{
-		"success": true,
-		"data":    contact,
-	})
+	return c.JSON(common.NewSuccessResponse(contact))
 }
 
 func (h *ChatwootHandler) SyncConversations(c *fiber.Ctx) error {
 	var req chatwoot.SyncConversationRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(400).JSON(fiber.Map{
-			"error": "Invalid request body",
-		})
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid request body"))
 	}
 
 	conversation, err := h.chatwootUC.SyncConversation(c.Context(), &req)
 	if err != nil {
 		if appErr := errors.GetAppError(err); appErr != nil {
-			return c.Status(appErr.Code).JSON(fiber.Map{
-				"error":   appErr.Message,
-				"details": appErr.Details,
-			})
+			return c.Status(appErr.Code).JSON(common.NewErrorResponse(appErr.Message, appErr.Details))
 		}
-		return c.Status(500).JSON(fiber.Map{
-			"error": "Internal server error",
-		})
+		return c.Status(500).JSON(common.NewErrorResponse("Internal server error"))
 	}
 
-	return c.JSON(fiber.Map{
-		"success": true,
-		"data":    conversation,
-	})
+	return c.JSON(common.NewSuccessResponse(conversation))
 }
 
 func (h *ChatwootHandler) ReceiveWebhook(c *fiber.Ctx) error {
@@ -233,18 +184,18 @@ func (h *ChatwootHandler) ReceiveWebhook(c *fiber.Ctx) error {
 
 	// Validate session ID
 	if err := h.validateSessionID(sessionID); err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		return c.Status(400).JSON(common.NewErrorResponse(err.Error()))
 	}
 
 	// Parse webhook payload
 	payload, err := h.parseWebhookPayload(c, sessionID)
 	if err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		return c.Status(400).JSON(common.NewErrorResponse(err.Error()))
 	}
 
 	// Validate event type
 	if err := h.validateEventType(sessionID, payload.Event, c.Body()); err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": err.Error(), "event": payload.Event})
+		return c.Status(400).JSON(common.NewErrorResponse(err.Error(), fiber.Map{"event": payload.Event}))
 	}
 
 	// Process webhook
@@ -324,9 +275,25 @@ func (h *ChatwootHandler) validateEventType(sessionID, event string, rawBody []b
 	return nil
 }
 
-// processWebhook processes the webhook using the use case
+// processWebhook processes the webhook using the use case, along with what the request presents to
+// authenticate itself
 func (h *ChatwootHandler) processWebhook(c *fiber.Ctx, sessionID string, payload *chatwoot.ChatwootWebhookPayload) error {
-	return h.chatwootUC.ProcessWebhook(c.Context(), sessionID, payload)
+	auth := &chatwoot.WebhookAuth{
+		Token:     c.Query("token"),
+		Signature: c.Get("X-Chatwoot-Signature"),
+		Timestamp: c.Get("X-Chatwoot-Timestamp"),
+		Body:      c.Body(),
+	}
+	return h.chatwootUC.ProcessWebhook(c.Context(), sessionID, payload, auth)
+}
+
+// chatwootWebhookURL builds the URL Chatwoot posts webhooks to; a webhook secret travels as its token query parameter
+func chatwootWebhookURL(baseURL, sessionID string, secret *string) string {
+	webhookURL := fmt.Sprintf("%s/chatwoot/webhook/%s", baseURL, sessionID)
+	if secret != nil && *secret != "" {
+		webhookURL += "?token=" + url.QueryEscape(*secret)
+	}
+	return webhookURL
 }
 
 // handleWebhookError handles webhook processing errors
@@ -338,28 +305,19 @@ func (h *ChatwootHandler) handleWebhookError(c *fiber.Ctx, sessionID, event stri
 	})
 
 	if appErr := errors.GetAppError(err); appErr != nil {
-		return c.Status(appErr.Code).JSON(fiber.Map{
-			"error":   appErr.Message,
-			"details": appErr.Details,
-		})
+		return c.Status(appErr.Code).JSON(common.NewErrorResponse(appErr.Message, appErr.Details))
 	}
-	return c.Status(500).JSON(fiber.Map{
-		"error": "Internal server error",
-	})
+	return c.Status(500).JSON(common.NewErrorResponse("Internal server error"))
 }
 
 // returnWebhookSuccess returns a successful webhook response
 func (h *ChatwootHandler) returnWebhookSuccess(c *fiber.Ctx, sessionID, event string) error {
-	h.logger.InfoWithFields("Webhook processed successfully", map[string]interface{}{
+	h.logger.InfoWithFields("Webhook accepted", map[string]interface{}{
 		"session_id": sessionID,
 		"event":      event,
 	})
 
-	return c.JSON(fiber.Map{
-		"success": true,
-		"message": "Webhook processed successfully",
-		"event":   event,
-	})
+	return c.JSON(common.NewSuccessResponse(fiber.Map{"event": event}, "Webhook accepted for processing"))
 }
 
 func (h *ChatwootHandler) TestConnection(c *fiber.Ctx) error {
@@ -367,20 +325,10 @@ func (h *ChatwootHandler) TestConnection(c *fiber.Ctx) error {
 
 	result, err := h.chatwootUC.TestConnection(ctx)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{
-			"success": false,
-			"message": "Chatwoot connection test failed",
-			"error":   err.Error(),
-			"status":  "failed",
-		})
+		return c.Status(500).JSON(common.NewErrorResponse("Chatwoot connection test failed", err.Error()))
 	}
 
-	return c.JSON(fiber.Map{
-		"success": true,
-		"message": "Chatwoot connection test completed",
-		"data":    result,
-		"status":  "connected",
-	})
+	return c.JSON(common.NewSuccessResponse(result, "Chatwoot connection test completed"))
 }
 
 func (h *ChatwootHandler) GetStats(c *fiber.Ctx) error {
@@ -388,17 +336,10 @@ func (h *ChatwootHandler) GetStats(c *fiber.Ctx) error {
 
 	stats, err := h.chatwootUC.GetStats(ctx)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{
-			"success": false,
-			"message": "Failed to get Chatwoot statistics",
-			"error":   err.Error(),
-		})
+		return c.Status(500).JSON(common.NewErrorResponse("Failed to get Chatwoot statistics", err.Error()))
 	}
 
-	return c.JSON(fiber.Map{
-		"success": true,
-		"data":    stats,
-	})
+	return c.JSON(common.NewSuccessResponse(stats))
 }
 
 // @Summary Set Chatwoot configuration
@@ -417,26 +358,30 @@ func (h *ChatwootHandler) GetStats(c *fiber.Ctx) error {
 func (h *ChatwootHandler) SetConfig(c *fiber.Ctx) error {
 	var req chatwoot.CreateChatwootConfigRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(400).JSON(fiber.Map{
-			"success": false,
-			"message": "Invalid request body",
-			"error":   err.Error(),
-		})
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid request body", err.Error()))
 	}
 
 	ctx := c.Context()
 
-	_, err := h.chatwootUC.GetConfig(ctx)
+	// Only the config update is conditional; the inbox auto-creation that follows updates it again
+	updateCtx, ok, err := helpers.CheckIfMatch(c, func() (interface{}, time.Time, error) {
+		config, err := h.chatwootUC.GetConfig(ctx)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		return config, config.UpdatedAt, nil
+	})
+	if !ok {
+		return err
+	}
+
+	_, err = h.chatwootUC.GetConfig(ctx)
 
 	if err != nil {
 		sessionID := c.Params("sessionId")
 		result, createErr := h.chatwootUC.CreateConfig(ctx, sessionID, &req)
 		if createErr != nil {
-			return c.Status(500).JSON(fiber.Map{
-				"success": false,
-				"message": "Failed to create Chatwoot configuration",
-				"error":   createErr.Error(),
-			})
+			return c.Status(500).JSON(common.NewErrorResponse("Failed to create Chatwoot configuration", createErr.Error()))
 		}
 
 		// Auto-create inbox if requested
@@ -457,7 +402,7 @@ func (h *ChatwootHandler) SetConfig(c *fiber.Ctx) error {
 			if serverHost == "" {
 				serverHost = "http://localhost:8080" // fallback
 			}
-			webhookURL := fmt.Sprintf("%s/chatwoot/webhook/%s", serverHost, sessionID)
+			webhookURL := chatwootWebhookURL(serverHost, sessionID, req.WebhookSecret)
 
 			// Call auto-creation logic (this would need to be implemented in the use case)
 			autoCreateErr := h.chatwootUC.AutoCreateInbox(ctx, sessionID, inboxName, webhookURL)
@@ -470,27 +415,23 @@ func (h *ChatwootHandler) SetConfig(c *fiber.Ctx) error {
 			}
 		}
 
-		return c.Status(201).JSON(fiber.Map{
-			"success": true,
-			"message": "Chatwoot configuration created successfully",
-			"data":    result,
-		})
+		return c.Status(201).JSON(common.NewSuccessResponse(result, "Chatwoot configuration created successfully"))
 	}
 
 	updateReq := chatwoot.UpdateChatwootConfigRequest{
-		URL:       &req.URL,
-		Token:     &req.Token,
-		AccountID: &req.AccountID,
-		InboxID:   req.InboxID,
+		URL:           &req.URL,
+		Token:         &req.Token,
+		AccountID:     &req.AccountID,
+		InboxID:       req.InboxID,
+		WebhookSecret: req.WebhookSecret,
 	}
 
-	result, updateErr := h.chatwootUC.UpdateConfig(ctx, &updateReq)
+	result, updateErr := h.chatwootUC.UpdateConfig(updateCtx, &updateReq)
 	if updateErr != nil {
-		return c.Status(500).JSON(fiber.Map{
-			"success": false,
-			"message": "Failed to update Chatwoot configuration",
-			"error":   updateErr.Error(),
-		})
+		if stderrors.Is(updateErr, precondition.ErrFailed) {
+			return helpers.RespondPreconditionFailed(c)
+		}
+		return c.Status(500).JSON(common.NewErrorResponse("Failed to update Chatwoot configuration", updateErr.Error()))
 	}
 
 	// Auto-create inbox if requested (also for updates)
@@ -512,7 +453,7 @@ func (h *ChatwootHandler) SetConfig(c *fiber.Ctx) error {
 		if serverHost == "" {
 			serverHost = "http://localhost:8080" // fallback
 		}
-		webhookURL := fmt.Sprintf("%s/chatwoot/webhook/%s", serverHost, sessionID)
+		webhookURL := chatwootWebhookURL(serverHost, sessionID, req.WebhookSecret)
 
 		// Call auto-creation logic
 		autoCreateErr := h.chatwootUC.AutoCreateInbox(ctx, sessionID, inboxName, webhookURL)
@@ -525,11 +466,7 @@ func (h *ChatwootHandler) SetConfig(c *fiber.Ctx) error {
 		}
 	}
 
-	return c.JSON(fiber.Map{
-		"success": true,
-		"message": "Chatwoot configuration updated successfully",
-		"data":    result,
-	})
+	return c.JSON(common.NewSuccessResponse(result, "Chatwoot configuration updated successfully"))
 }
 
 // @Summary Get Chatwoot configuration
@@ -538,7 +475,9 @@ func (h *ChatwootHandler) SetConfig(c *fiber.Ctx) error {
 // @Security ApiKeyAuth
 // @Produce json
 // @Param sessionId path string true "Session ID"
+// @Param If-None-Match header string false "ETag of a previous response; answered with 304 while the configuration is unchanged"
 // @Success 200 {object} chatwoot.ChatwootConfigResponse "Chatwoot configuration retrieved successfully"
+// @Success 304 "Chatwoot configuration unchanged since the ETag in If-None-Match"
 // @Failure 404 {object} object "Session not found"
 // @Failure 500 {object} object "Internal Server Error"
 // @Router /sessions/{sessionId}/chatwoot/find [get]
@@ -549,18 +488,33 @@ func (h *ChatwootHandler) FindConfig(c *fiber.Ctx) error {
 	config, err := h.chatwootUC.GetConfig(ctx)
 
 	if err != nil {
-		return c.Status(404).JSON(fiber.Map{
-			"success":    false,
-			"message":    "Chatwoot configuration not found for this session",
-			"session_id": sessionID,
-		})
+		return c.Status(404).JSON(common.NewErrorResponse("Chatwoot configuration not found for this session", fiber.Map{"sessionId": sessionID}))
 	}
 
-	return c.JSON(fiber.Map{
-		"success": true,
-		"message": "Chatwoot configuration found",
-		"data":    config,
-	})
+	return helpers.RespondWithETag(c, config, "Chatwoot configuration found")
+}
+
+// @Summary Get Chatwoot webhook queue status
+// @Description Get background processing statistics and recent failures of Chatwoot webhooks for a WhatsApp session
+// @Tags Chatwoot
+// @Security ApiKeyAuth
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Success 200 {object} chatwoot.WebhookQueueStatsResponse "Webhook queue status retrieved successfully"
+// @Failure 404 {object} object "Webhook queue not configured"
+// @Router /sessions/{sessionId}/chatwoot/queue [get]
+func (h *ChatwootHandler) GetWebhookQueue(c *fiber.Ctx) error {
+	sessionID := c.Params("sessionId")
+
+	stats, err := h.chatwootUC.GetWebhookQueueStats(c.Context(), sessionID)
+	if err != nil {
+		if appErr := errors.GetAppError(err); appErr != nil {
+			return c.Status(appErr.Code).JSON(common.NewErrorResponse(appErr.Message))
+		}
+		return c.Status(500).JSON(common.NewErrorResponse("Internal server error"))
+	}
+
+	return c.JSON(common.NewSuccessResponse(stats))
 }
 
 // getBaseURL gets the base URL from server configuration
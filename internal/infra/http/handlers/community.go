@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"fmt"
+	"zpwoot/internal/app/common"
 	"zpwoot/internal/app/community"
 	domainSession "zpwoot/internal/domain/session"
 	"zpwoot/internal/infra/http/helpers"
@@ -133,10 +134,7 @@ func (h *CommunityHandler) handleCommunityQueryAction(
 		paramName:    paramValue,
 	})
 
-	return c.JSON(fiber.Map{
-		"success": true,
-		"data":    response,
-	})
+	return c.JSON(common.NewSuccessResponse(response))
 }
 
 // handleGroupLinkAction handles common group link/unlink logic
@@ -196,10 +194,7 @@ func (h *CommunityHandler) handleGroupLinkAction(
 		"group_jid":     groupJID,
 	})
 
-	return c.Status(200).JSON(fiber.Map{
-		"success": true,
-		"data":    response,
-	})
+	return c.Status(200).JSON(common.NewSuccessResponse(response))
 }
 
 // LinkGroup links a group to a community
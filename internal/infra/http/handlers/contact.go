@@ -3,6 +3,8 @@ package handlers
 import (
 	"context"
 	"fmt"
+	"time"
+
 	"zpwoot/internal/app/common"
 	"zpwoot/internal/app/contact"
 	"zpwoot/internal/domain/session"
@@ -62,7 +64,7 @@ func (h *ContactHandler) handleContactAction(
 }
 
 // @Summary Check if phone numbers are on WhatsApp
-// @Description Check if one or more phone numbers are registered on WhatsApp
+// @Description Check if one or more phone numbers are registered on WhatsApp. Numbers are validated against the numbering plan of their country first; impossible ones are reported with invalidReason and not checked, possible ones with their E.164 form
 // @Tags Contacts
 // @Security ApiKeyAuth
 // @Accept json
@@ -317,6 +319,113 @@ func (h *ContactHandler) GetBusinessProfile(c *fiber.Ctx) error {
 	return c.JSON(response)
 }
 
+// @Summary List contact changes
+// @Description Poll the contact change feed: new pushnames, business names, avatars and address book entries, oldest first. Pass the returned nextSince as since to get the following changes. Every change is also delivered as a contact.updated webhook event
+// @Tags Contacts
+// @Security ApiKeyAuth
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param since query string false "Only changes recorded after this RFC 3339 timestamp" example("2024-01-01T12:00:00Z")
+// @Param limit query int false "Maximum number of changes" default(100)
+// @Success 200 {object} common.SuccessResponse{data=contact.ListContactChangesResponse} "Contact changes retrieved successfully"
+// @Failure 400 {object} object "Bad Request"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/contacts/changes [get]
+func (h *ContactHandler) ListContactChanges(c *fiber.Ctx) error {
+	sess, fiberErr := h.resolveSession(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	var since time.Time
+	if raw := c.Query("since"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			return c.Status(400).JSON(common.NewErrorResponse("'since' must be an RFC 3339 timestamp"))
+		}
+		since = parsed
+	}
+
+	limit := c.QueryInt("limit", 100)
+	if limit <= 0 || limit > 500 {
+		return c.Status(400).JSON(common.NewErrorResponse("'limit' must be between 1 and 500"))
+	}
+
+	req := &contact.ListContactChangesRequest{
+		SessionID: sess.ID.String(),
+		Since:     since,
+		Limit:     limit,
+	}
+
+	result, err := h.contactUC.ListContactChanges(c.Context(), req)
+	if err != nil {
+		h.logger.Error("Failed to list contact changes: " + err.Error())
+		return c.Status(500).JSON(common.NewErrorResponse("Failed to list contact changes"))
+	}
+
+	return c.JSON(common.NewSuccessResponse(result, "Contact changes retrieved successfully"))
+}
+
+// @Summary Subscribe to contact presence
+// @Description Follow the online status and last seen of contacts. Updates are delivered as Presence webhook events, and the subscriptions are renewed whenever the session reconnects
+// @Tags Contacts
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param request body contact.SubscribePresenceRequest true "Contact JIDs to follow"
+// @Success 200 {object} common.SuccessResponse{data=contact.SubscribePresenceResponse} "Presence subscribed successfully"
+// @Failure 400 {object} object "Bad Request"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/contacts/presence/subscribe [post]
+func (h *ContactHandler) SubscribePresence(c *fiber.Ctx) error {
+	return h.handleContactAction(
+		c,
+		"Subscribing to presence",
+		"Presence subscribed successfully",
+		func(c *fiber.Ctx, sess *session.Session) (interface{}, error) {
+			var req contact.SubscribePresenceRequest
+			if err := c.BodyParser(&req); err != nil {
+				return nil, err
+			}
+			if len(req.JIDs) == 0 || len(req.JIDs) > 100 {
+				return nil, fmt.Errorf("between 1 and 100 JIDs are required")
+			}
+			req.SessionID = sess.ID.String()
+			return &req, nil
+		},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return h.contactUC.SubscribePresence(ctx, req.(*contact.SubscribePresenceRequest))
+		},
+	)
+}
+
+// @Summary List presence subscriptions
+// @Description List the contacts whose presence the session follows
+// @Tags Contacts
+// @Security ApiKeyAuth
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Success 200 {object} common.SuccessResponse{data=contact.ListPresenceSubscriptionsResponse} "Presence subscriptions retrieved successfully"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/contacts/presence [get]
+func (h *ContactHandler) ListPresenceSubscriptions(c *fiber.Ctx) error {
+	return h.handleContactAction(
+		c,
+		"Listing presence subscriptions",
+		"Presence subscriptions retrieved successfully",
+		func(c *fiber.Ctx, sess *session.Session) (interface{}, error) {
+			return &contact.ListPresenceSubscriptionsRequest{SessionID: sess.ID.String()}, nil
+		},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return h.contactUC.ListPresenceSubscriptions(ctx, req.(*contact.ListPresenceSubscriptionsRequest))
+		},
+	)
+}
+
 func (h *ContactHandler) resolveSession(c *fiber.Ctx) (*domainSession.Session, *fiber.Error) {
 	idOrName := c.Params("sessionId")
 
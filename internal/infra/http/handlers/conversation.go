@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/gofiber/fiber/v2"
+
+	"zpwoot/internal/app/common"
+	"zpwoot/internal/app/conversation"
+	domainConversation "zpwoot/internal/domain/conversation"
+	"zpwoot/internal/domain/session"
+	"zpwoot/internal/infra/http/helpers"
+	"zpwoot/platform/logger"
+)
+
+type ConversationHandler struct {
+	logger          *logger.Logger
+	conversationUC  conversation.UseCase
+	sessionResolver *helpers.SessionResolver
+}
+
+func NewConversationHandler(appLogger *logger.Logger, conversationUC conversation.UseCase, sessionRepo helpers.SessionRepository) *ConversationHandler {
+	return &ConversationHandler{
+		logger:          appLogger,
+		conversationUC:  conversationUC,
+		sessionResolver: helpers.NewSessionResolver(appLogger, sessionRepo),
+	}
+}
+
+// @Summary List conversation states
+// @Description List the chats of a session that have a conversation state, optionally filtered by state
+// @Tags Conversations
+// @Security ApiKeyAuth
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param state query string false "Filter by state"
+// @Param limit query int false "Number of states to return" default(20)
+// @Param offset query int false "Number of states to skip" default(0)
+// @Success 200 {object} common.SuccessResponse{data=conversation.ListConversationStatesResponse} "Conversation states retrieved successfully"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/conversations [get]
+func (h *ConversationHandler) ListStates(c *fiber.Ctx) error {
+	sess, fiberErr := h.resolveSession(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	var req conversation.ListConversationStatesRequest
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid query parameters"))
+	}
+
+	response, err := h.conversationUC.ListStates(c.Context(), sess.ID.String(), &req)
+	if err != nil {
+		return h.handleError(c, "list conversation states", err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "Conversation states retrieved successfully"))
+}
+
+// @Summary Get conversation state
+// @Description Get the conversation state of a chat. Chats without a stored state are in the "start" state
+// @Tags Conversations
+// @Security ApiKeyAuth
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param jid path string true "Chat JID" example("5511999999999@s.whatsapp.net")
+// @Success 200 {object} common.SuccessResponse{data=conversation.ConversationStateResponse} "Conversation state retrieved successfully"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/chats/{jid}/state [get]
+func (h *ConversationHandler) GetState(c *fiber.Ctx) error {
+	sess, chatJID, fiberErr := h.resolveChat(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	response, err := h.conversationUC.GetState(c.Context(), sess.ID.String(), chatJID)
+	if err != nil {
+		return h.handleError(c, "get conversation state", err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "Conversation state retrieved successfully"))
+}
+
+// @Summary Set conversation state
+// @Description Move a chat to a conversation state, e.g. after answering a button or list reply. Routing rules match the state and can move it further
+// @Tags Conversations
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param jid path string true "Chat JID" example("5511999999999@s.whatsapp.net")
+// @Param request body conversation.SetConversationStateRequest true "State and data"
+// @Success 200 {object} common.SuccessResponse{data=conversation.ConversationStateResponse} "Conversation state set successfully"
+// @Failure 400 {object} object "Bad Request"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/chats/{jid}/state [put]
+func (h *ConversationHandler) SetState(c *fiber.Ctx) error {
+	sess, chatJID, fiberErr := h.resolveChat(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	var req conversation.SetConversationStateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid request body"))
+	}
+
+	response, err := h.conversationUC.SetState(c.Context(), sess.ID.String(), chatJID, &req)
+	if err != nil {
+		return h.handleError(c, "set conversation state", err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "Conversation state set successfully"))
+}
+
+// @Summary Reset conversation state
+// @Description Return a chat to the "start" state, dropping its stored state and data
+// @Tags Conversations
+// @Security ApiKeyAuth
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param jid path string true "Chat JID" example("5511999999999@s.whatsapp.net")
+// @Success 200 {object} common.SuccessResponse "Conversation state reset successfully"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/chats/{jid}/state [delete]
+func (h *ConversationHandler) ResetState(c *fiber.Ctx) error {
+	sess, chatJID, fiberErr := h.resolveChat(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	if err := h.conversationUC.ResetState(c.Context(), sess.ID.String(), chatJID); err != nil {
+		return h.handleError(c, "reset conversation state", err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(nil, "Conversation state reset successfully"))
+}
+
+// resolveSession resolves the session from the sessionId path parameter
+func (h *ConversationHandler) resolveSession(c *fiber.Ctx) (*session.Session, *fiber.Error) {
+	sessionIdentifier := c.Params("sessionId")
+	if sessionIdentifier == "" {
+		return nil, fiber.NewError(400, "Session identifier is required")
+	}
+
+	sess, err := h.sessionResolver.ResolveSession(c.Context(), sessionIdentifier)
+	if err != nil {
+		return nil, fiber.NewError(404, "Session not found")
+	}
+
+	return sess, nil
+}
+
+// resolveChat resolves the session and the URL-decoded chat JID path parameter
+func (h *ConversationHandler) resolveChat(c *fiber.Ctx) (*session.Session, string, *fiber.Error) {
+	chatJID, err := url.PathUnescape(c.Params("jid"))
+	if err != nil || chatJID == "" {
+		return nil, "", fiber.NewError(400, "Chat JID is required")
+	}
+
+	sess, fiberErr := h.resolveSession(c)
+	if fiberErr != nil {
+		return nil, "", fiberErr
+	}
+
+	return sess, chatJID, nil
+}
+
+// handleError maps conversation domain errors to HTTP responses
+func (h *ConversationHandler) handleError(c *fiber.Ctx, action string, err error) error {
+	switch {
+	case errors.Is(err, domainConversation.ErrInvalidChatJID),
+		errors.Is(err, domainConversation.ErrInvalidState),
+		errors.Is(err, domainConversation.ErrInvalidTTL),
+		errors.Is(err, domainConversation.ErrTooMuchData):
+		return c.Status(400).JSON(common.NewErrorResponse(err.Error()))
+	}
+
+	h.logger.ErrorWithFields("Failed to "+action, map[string]interface{}{
+		"error": err.Error(),
+	})
+	return c.Status(500).JSON(common.NewErrorResponse("Failed to " + action))
+}
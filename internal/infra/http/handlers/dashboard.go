@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"zpwoot/internal/app/common"
+	"zpwoot/internal/app/dashboard"
+	"zpwoot/internal/domain/session"
+	"zpwoot/platform/logger"
+)
+
+type DashboardHandler struct {
+	logger      *logger.Logger
+	dashboardUC dashboard.UseCase
+}
+
+func NewDashboardHandler(appLogger *logger.Logger, dashboardUC dashboard.UseCase) *DashboardHandler {
+	return &DashboardHandler{
+		logger:      appLogger,
+		dashboardUC: dashboardUC,
+	}
+}
+
+// @Summary List recent messages
+// @Description List the latest messages seen on a session since the server started, newest first
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Param sessionId query string true "Session ID or name"
+// @Param limit query int false "Maximum number of messages" default(20)
+// @Success 200 {object} common.SuccessResponse{data=dashboard.RecentMessagesResponse} "Recent messages retrieved successfully"
+// @Failure 400 {object} object "Invalid query parameters"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /admin/dashboard/messages [get]
+func (h *DashboardHandler) RecentMessages(c *fiber.Ctx) error {
+	var req dashboard.RecentMessagesRequest
+	if err := c.QueryParser(&req); err != nil || req.SessionID == "" {
+		return c.Status(400).JSON(common.NewErrorResponse("sessionId query parameter is required"))
+	}
+
+	response, err := h.dashboardUC.RecentMessages(c.Context(), &req)
+	if err != nil {
+		return h.handleError(c, err, req.SessionID, "Failed to list recent messages")
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "Recent messages retrieved successfully"))
+}
+
+// @Summary List webhook failures
+// @Description List webhook deliveries that failed after all retries since the server started, newest first
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Param sessionId query string false "Session ID or name"
+// @Param limit query int false "Maximum number of failures" default(50)
+// @Success 200 {object} common.SuccessResponse{data=dashboard.WebhookFailuresResponse} "Webhook failures retrieved successfully"
+// @Failure 400 {object} object "Invalid query parameters"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /admin/dashboard/webhook-failures [get]
+func (h *DashboardHandler) WebhookFailures(c *fiber.Ctx) error {
+	var req dashboard.WebhookFailuresRequest
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid query parameters"))
+	}
+
+	response, err := h.dashboardUC.WebhookFailures(c.Context(), &req)
+	if err != nil {
+		return h.handleError(c, err, req.SessionID, "Failed to list webhook failures")
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "Webhook failures retrieved successfully"))
+}
+
+func (h *DashboardHandler) handleError(c *fiber.Ctx, err error, sessionID, message string) error {
+	if errors.Is(err, session.ErrSessionNotFound) {
+		return c.Status(404).JSON(common.NewErrorResponse("Session not found"))
+	}
+
+	h.logger.ErrorWithFields(message, map[string]interface{}{
+		"session_id": sessionID,
+		"error":      err.Error(),
+	})
+	return c.Status(500).JSON(common.NewErrorResponse(message))
+}
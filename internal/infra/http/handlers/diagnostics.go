@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"bytes"
+	"runtime"
+	"runtime/pprof"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"zpwoot/internal/app/common"
+	"zpwoot/internal/infra/http/helpers"
+	webhookIntegration "zpwoot/internal/infra/integrations/webhook"
+	"zpwoot/internal/infra/wameow"
+	"zpwoot/internal/ports"
+	"zpwoot/platform/logger"
+)
+
+// DiagnosticsHandler serves runtime internals for debugging leaks and stuck sessions
+type DiagnosticsHandler struct {
+	logger          *logger.Logger
+	wameowManager   *wameow.Manager
+	webhookManager  *webhookIntegration.WebhookManager
+	chatwootQueue   ports.ChatwootWebhookQueue
+	sessionResolver *helpers.SessionResolver
+	startedAt       time.Time
+}
+
+func NewDiagnosticsHandler(
+	appLogger *logger.Logger,
+	wameowManager *wameow.Manager,
+	webhookManager *webhookIntegration.WebhookManager,
+	chatwootQueue ports.ChatwootWebhookQueue,
+	sessionRepo helpers.SessionRepository,
+) *DiagnosticsHandler {
+	return &DiagnosticsHandler{
+		logger:          appLogger,
+		wameowManager:   wameowManager,
+		webhookManager:  webhookManager,
+		chatwootQueue:   chatwootQueue,
+		sessionResolver: helpers.NewSessionResolver(appLogger, sessionRepo),
+		startedAt:       time.Now(),
+	}
+}
+
+// @Summary Runtime diagnostics
+// @Description Go runtime state (goroutines, memory, GC), loaded sessions and background queue sizes. Requires the admin key in the X-Admin-Key header.
+// @Tags Debug
+// @Security AdminKeyAuth
+// @Produce json
+// @Success 200 {object} common.SuccessResponse{data=object} "Runtime diagnostics retrieved successfully"
+// @Failure 401 {object} object "Admin key is required"
+// @Failure 403 {object} object "Invalid admin key"
+// @Router /debug/runtime [get]
+func (h *DiagnosticsHandler) GetRuntime(c *fiber.Ctx) error {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	sessions := h.wameowManager.ListDiagnostics()
+	connected, loggedIn := 0, 0
+	for _, s := range sessions {
+		if s.Connected {
+			connected++
+		}
+		if s.LoggedIn {
+			loggedIn++
+		}
+	}
+
+	queues := fiber.Map{}
+	if h.webhookManager != nil {
+		queues["webhooks"] = h.webhookManager.GetStats()
+	}
+	if h.chatwootQueue != nil {
+		queues["chatwoot"] = h.chatwootQueue.GetStats("")
+	}
+
+	data := fiber.Map{
+		"goVersion":     runtime.Version(),
+		"uptimeSeconds": int64(time.Since(h.startedAt).Seconds()),
+		"goroutines":    runtime.NumGoroutine(),
+		"gomaxprocs":    runtime.GOMAXPROCS(0),
+		"cpus":          runtime.NumCPU(),
+		"memory": fiber.Map{
+			"heapAlloc":   mem.HeapAlloc,
+			"heapInuse":   mem.HeapInuse,
+			"heapObjects": mem.HeapObjects,
+			"stackInuse":  mem.StackInuse,
+			"sys":         mem.Sys,
+			"numGC":       mem.NumGC,
+			"gcPauseMs":   float64(mem.PauseTotalNs) / float64(time.Millisecond),
+		},
+		"sessions": fiber.Map{
+			"total":     len(sessions),
+			"connected": connected,
+			"loggedIn":  loggedIn,
+		},
+		"queues": queues,
+	}
+
+	return c.JSON(common.NewSuccessResponse(data, "Runtime diagnostics retrieved successfully"))
+}
+
+// @Summary Goroutine dump
+// @Description Stack traces of all goroutines as plain text, the same as /debug/pprof/goroutine?debug=2. Requires the admin key in the X-Admin-Key header.
+// @Tags Debug
+// @Security AdminKeyAuth
+// @Produce plain
+// @Success 200 {string} string "Goroutine dump"
+// @Failure 401 {object} object "Admin key is required"
+// @Failure 403 {object} object "Invalid admin key"
+// @Router /debug/goroutines [get]
+func (h *DiagnosticsHandler) GetGoroutines(c *fiber.Ctx) error {
+	var buf bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 2); err != nil {
+		h.logger.ErrorWithFields("Failed to dump goroutines", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return c.Status(500).JSON(common.NewErrorResponse("Failed to dump goroutines"))
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMETextPlainCharsetUTF8)
+	return c.Send(buf.Bytes())
+}
+
+// @Summary List session diagnostics
+// @Description Client state, pending receipts, event handlers and recent errors of every loaded session. Requires the admin key in the X-Admin-Key header.
+// @Tags Debug
+// @Security AdminKeyAuth
+// @Produce json
+// @Success 200 {object} common.SuccessResponse{data=[]wameow.SessionDiagnostics} "Session diagnostics retrieved successfully"
+// @Failure 401 {object} object "Admin key is required"
+// @Failure 403 {object} object "Invalid admin key"
+// @Router /debug/sessions [get]
+func (h *DiagnosticsHandler) ListSessions(c *fiber.Ctx) error {
+	return c.JSON(common.NewSuccessResponse(h.wameowManager.ListDiagnostics(), "Session diagnostics retrieved successfully"))
+}
+
+// @Summary Get session diagnostics
+// @Description Client state, pending receipts, event handlers, recent errors and the Chatwoot queue of one session. Requires the admin key in the X-Admin-Key header.
+// @Tags Debug
+// @Security AdminKeyAuth
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Success 200 {object} common.SuccessResponse{data=object} "Session diagnostics retrieved successfully"
+// @Failure 401 {object} object "Admin key is required"
+// @Failure 403 {object} object "Invalid admin key"
+// @Failure 404 {object} object "Session not found or not loaded"
+// @Router /debug/sessions/{sessionId} [get]
+func (h *DiagnosticsHandler) GetSession(c *fiber.Ctx) error {
+	sess, err := h.sessionResolver.ResolveSession(c.Context(), c.Params("sessionId"))
+	if err != nil {
+		return c.Status(404).JSON(common.NewErrorResponse("Session not found"))
+	}
+
+	sessionID := sess.ID.String()
+	snapshot, err := h.wameowManager.GetDiagnostics(sessionID)
+	if err != nil {
+		return c.Status(404).JSON(common.NewErrorResponse("Session is not loaded"))
+	}
+
+	data := fiber.Map{
+		"client": snapshot,
+	}
+	if h.chatwootQueue != nil {
+		data["chatwootQueue"] = h.chatwootQueue.GetStats(sessionID)
+	}
+
+	return c.JSON(common.NewSuccessResponse(data, "Session diagnostics retrieved successfully"))
+}
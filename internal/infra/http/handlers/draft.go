@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/gofiber/fiber/v2"
+
+	"zpwoot/internal/app/common"
+	"zpwoot/internal/app/draft"
+	domainDraft "zpwoot/internal/domain/draft"
+	"zpwoot/internal/domain/queue"
+	"zpwoot/internal/domain/session"
+	"zpwoot/internal/infra/http/helpers"
+	"zpwoot/platform/logger"
+	"zpwoot/platform/pagination"
+)
+
+type DraftHandler struct {
+	logger          *logger.Logger
+	draftUC         draft.UseCase
+	sessionResolver *helpers.SessionResolver
+}
+
+func NewDraftHandler(appLogger *logger.Logger, draftUC draft.UseCase, sessionRepo helpers.SessionRepository) *DraftHandler {
+	return &DraftHandler{
+		logger:          appLogger,
+		draftUC:         draftUC,
+		sessionResolver: helpers.NewSessionResolver(appLogger, sessionRepo),
+	}
+}
+
+// @Summary List drafts
+// @Description List the message drafts kept for a session, optionally filtered by status
+// @Tags Drafts
+// @Security ApiKeyAuth
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param status query string false "Filter by status (draft, scheduled, sending, failed)"
+// @Param limit query int false "Number of drafts to return" default(20)
+// @Param offset query int false "Number of drafts to skip" default(0)
+// @Param cursor query string false "nextCursor of the previous page; replaces offset"
+// @Success 200 {object} common.SuccessResponse{data=draft.ListDraftsResponse} "Drafts retrieved successfully"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/drafts [get]
+func (h *DraftHandler) ListDrafts(c *fiber.Ctx) error {
+	sess, fiberErr := h.resolveSession(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	var req draft.ListDraftsRequest
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid query parameters"))
+	}
+
+	response, err := h.draftUC.ListDrafts(c.Context(), sess.ID.String(), &req)
+	if err != nil {
+		return h.handleError(c, "list drafts", err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "Drafts retrieved successfully"))
+}
+
+// @Summary Get chat draft
+// @Description Get the message draft of a chat
+// @Tags Drafts
+// @Security ApiKeyAuth
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param jid path string true "Chat JID" example("5511999999999@s.whatsapp.net")
+// @Success 200 {object} common.SuccessResponse{data=draft.DraftResponse} "Draft retrieved successfully"
+// @Failure 404 {object} object "Session or draft not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/chats/{jid}/draft [get]
+func (h *DraftHandler) GetDraft(c *fiber.Ctx) error {
+	sess, chatJID, fiberErr := h.resolveChat(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	response, err := h.draftUC.GetDraft(c.Context(), sess.ID.String(), chatJID)
+	if err != nil {
+		return h.handleError(c, "get draft", err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "Draft retrieved successfully"))
+}
+
+// @Summary Save chat draft
+// @Description Create or replace the message draft of a chat. Saving a scheduled draft cancels its schedule
+// @Tags Drafts
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param jid path string true "Chat JID" example("5511999999999@s.whatsapp.net")
+// @Param request body draft.SaveDraftRequest true "Draft content"
+// @Success 200 {object} common.SuccessResponse{data=draft.DraftResponse} "Draft saved successfully"
+// @Failure 400 {object} object "Bad Request"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/chats/{jid}/draft [put]
+func (h *DraftHandler) SaveDraft(c *fiber.Ctx) error {
+	sess, chatJID, fiberErr := h.resolveChat(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	var req draft.SaveDraftRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid request body"))
+	}
+
+	response, err := h.draftUC.SaveDraft(c.Context(), sess.ID.String(), chatJID, &req)
+	if err != nil {
+		return h.handleError(c, "save draft", err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "Draft saved successfully"))
+}
+
+// @Summary Delete chat draft
+// @Description Delete the message draft of a chat, cancelling it if scheduled
+// @Tags Drafts
+// @Security ApiKeyAuth
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param jid path string true "Chat JID" example("5511999999999@s.whatsapp.net")
+// @Success 200 {object} common.SuccessResponse "Draft deleted successfully"
+// @Failure 404 {object} object "Session or draft not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/chats/{jid}/draft [delete]
+func (h *DraftHandler) DeleteDraft(c *fiber.Ctx) error {
+	sess, chatJID, fiberErr := h.resolveChat(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	if err := h.draftUC.DeleteDraft(c.Context(), sess.ID.String(), chatJID); err != nil {
+		return h.handleError(c, "delete draft", err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(nil, "Draft deleted successfully"))
+}
+
+// @Summary Send chat draft
+// @Description Send the draft of a chat now, or schedule it with sendAt. The draft is removed once sent
+// @Tags Drafts
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param jid path string true "Chat JID" example("5511999999999@s.whatsapp.net")
+// @Param request body draft.SendDraftRequest false "Send options"
+// @Success 200 {object} common.SuccessResponse{data=draft.SendDraftResponse} "Draft sent or scheduled successfully"
+// @Failure 400 {object} object "Bad Request"
+// @Failure 404 {object} object "Session or draft not found"
+// @Failure 409 {object} object "Draft is already being sent"
+// @Failure 429 {object} object "Daily message quota exceeded"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/chats/{jid}/draft/send [post]
+func (h *DraftHandler) SendDraft(c *fiber.Ctx) error {
+	sess, chatJID, fiberErr := h.resolveChat(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	var req draft.SendDraftRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(common.NewErrorResponse("Invalid request body"))
+		}
+	}
+
+	response, err := h.draftUC.SendDraft(c.Context(), sess.ID.String(), chatJID, &req)
+	if err != nil {
+		return h.handleError(c, "send draft", err)
+	}
+
+	message := "Draft sent successfully"
+	if response.Status == domainDraft.StatusScheduled {
+		message = "Draft scheduled successfully"
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, message))
+}
+
+// resolveSession resolves the session from the sessionId path parameter
+func (h *DraftHandler) resolveSession(c *fiber.Ctx) (*session.Session, *fiber.Error) {
+	sessionIdentifier := c.Params("sessionId")
+	if sessionIdentifier == "" {
+		return nil, fiber.NewError(400, "Session identifier is required")
+	}
+
+	sess, err := h.sessionResolver.ResolveSession(c.Context(), sessionIdentifier)
+	if err != nil {
+		return nil, fiber.NewError(404, "Session not found")
+	}
+
+	return sess, nil
+}
+
+// resolveChat resolves the session and the URL-decoded chat JID path parameter
+func (h *DraftHandler) resolveChat(c *fiber.Ctx) (*session.Session, string, *fiber.Error) {
+	chatJID, err := url.PathUnescape(c.Params("jid"))
+	if err != nil || chatJID == "" {
+		return nil, "", fiber.NewError(400, "Chat JID is required")
+	}
+
+	sess, fiberErr := h.resolveSession(c)
+	if fiberErr != nil {
+		return nil, "", fiberErr
+	}
+
+	return sess, chatJID, nil
+}
+
+// handleError maps draft domain errors to HTTP responses
+func (h *DraftHandler) handleError(c *fiber.Ctx, action string, err error) error {
+	switch {
+	case errors.Is(err, domainDraft.ErrDraftNotFound):
+		return c.Status(404).JSON(common.NewErrorResponse("Draft not found"))
+	case errors.Is(err, domainDraft.ErrEmptyDraft), errors.Is(err, domainDraft.ErrInvalidChatJID):
+		return c.Status(400).JSON(common.NewErrorResponse(err.Error()))
+	case errors.Is(err, domainDraft.ErrDraftSending):
+		return c.Status(409).JSON(common.NewErrorResponse(err.Error()))
+	case errors.Is(err, queue.ErrSessionDisconnected):
+		return c.Status(400).JSON(common.NewErrorResponse("Session is not connected"))
+	case errors.Is(err, pagination.ErrInvalidCursor):
+		return c.Status(400).JSON(common.NewErrorResponseWithCode(err.Error(), "INVALID_CURSOR"))
+	}
+
+	if handled, respErr := writeQuotaError(c, err); handled {
+		return respErr
+	}
+
+	h.logger.ErrorWithFields("Failed to "+action, map[string]interface{}{
+		"error": err.Error(),
+	})
+	return c.Status(500).JSON(common.NewErrorResponse("Failed to " + action))
+}
@@ -0,0 +1,261 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+
+	"zpwoot/internal/app/common"
+	"zpwoot/internal/app/webhook"
+	"zpwoot/internal/domain/session"
+	domainWebhook "zpwoot/internal/domain/webhook"
+	"zpwoot/internal/infra/http/helpers"
+	"zpwoot/platform/logger"
+)
+
+// Event stream timings: pings keep idle connections alive through proxies, a client missing the pongs
+// for streamPongWait is dropped, and a write stuck for streamWriteWait ends the stream
+const (
+	streamPingInterval = 30 * time.Second
+	streamPongWait     = 75 * time.Second
+	streamWriteWait    = 10 * time.Second
+)
+
+// streamMaxMessageSize bounds the filter messages read from clients
+const streamMaxMessageSize = 64 * 1024
+
+// Locals handing the validated request to the upgraded connection
+const (
+	streamSessionLocal = "stream_session_id"
+	streamEventsLocal  = "stream_events"
+)
+
+// streamNotice is a message of the stream itself, next to the event payloads; its event names start
+// with "stream." so they never clash with webhook events
+type streamNotice struct {
+	Event     string   `json:"event"`
+	SessionID string   `json:"sessionId,omitempty"`
+	Events    []string `json:"events,omitempty"`
+	Dropped   int64    `json:"dropped,omitempty"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// streamFilterRequest is sent by clients to replace the event types they receive
+type streamFilterRequest struct {
+	Events []string `json:"events"`
+}
+
+// streamConn serializes the writes to a connection, which allows a single writer at a time; the
+// reader goroutine answers filter changes while the event loop writes events
+type streamConn struct {
+	*websocket.Conn
+	writeMu sync.Mutex
+}
+
+type EventStreamHandler struct {
+	logger          *logger.Logger
+	webhookUC       webhook.UseCase
+	sessionResolver *helpers.SessionResolver
+	upgrade         fiber.Handler
+}
+
+func NewEventStreamHandler(appLogger *logger.Logger, webhookUC webhook.UseCase, sessionRepo helpers.SessionRepository) *EventStreamHandler {
+	h := &EventStreamHandler{
+		logger:          appLogger,
+		webhookUC:       webhookUC,
+		sessionResolver: helpers.NewSessionResolver(appLogger, sessionRepo),
+	}
+	h.upgrade = websocket.New(func(conn *websocket.Conn) {
+		sessionID, _ := conn.Locals(streamSessionLocal).(string)
+		events, _ := conn.Locals(streamEventsLocal).([]string)
+		h.serveStream(&streamConn{Conn: conn}, sessionID, events)
+	})
+	return h
+}
+
+// @Summary Stream session events over WebSocket
+// @Description Upgrade to a WebSocket streaming the events of a session as text messages with the same JSON payload webhooks without a payload template receive, for consumers that cannot expose a webhook URL. Authenticate the upgrade request with the API key headers as any other call.
+// @Description The events query parameter takes event types or patterns (message.*) as webhook configurations do; send {"events": [...]} on the socket to replace them, an empty list receiving every event.
+// @Description Besides events the stream sends stream.ready on connect, stream.filter after a filter change, stream.error for a rejected filter and stream.lagged with the number of events dropped while the client read too slowly.
+// @Description At most 10 streams can be open per session; further connections are closed with code 1013.
+// @Tags Webhooks
+// @Security ApiKeyAuth
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param events query string false "Comma separated event types or patterns; all events when empty" example("Message,Receipt")
+// @Success 101 "Switching to the WebSocket protocol"
+// @Failure 400 {object} object "Invalid event types"
+// @Failure 404 {object} object "Session not found"
+// @Failure 426 {object} object "Not a WebSocket upgrade request"
+// @Router /sessions/{sessionId}/events/ws [get]
+func (h *EventStreamHandler) StreamEvents(c *fiber.Ctx) error {
+	sess, fiberErr := h.resolveSession(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	events := splitEventFilter(c.Query("events"))
+	if invalidEvents := domainWebhook.ValidateEvents(events); len(invalidEvents) > 0 {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid event types: " + fmt.Sprintf("%v", invalidEvents)))
+	}
+
+	if !websocket.IsWebSocketUpgrade(c) {
+		c.Set(fiber.HeaderUpgrade, "websocket")
+		return c.Status(fiber.StatusUpgradeRequired).JSON(common.NewErrorResponse("This endpoint only accepts WebSocket upgrade requests"))
+	}
+
+	c.Locals(streamSessionLocal, sess.ID.String())
+	c.Locals(streamEventsLocal, events)
+	return h.upgrade(c)
+}
+
+// serveStream subscribes once the connection is upgraded, so a handshake that never completes leaves
+// no subscription behind
+func (h *EventStreamHandler) serveStream(conn *streamConn, sessionID string, events []string) {
+
+	sub, err := h.webhookUC.OpenEventStream(sessionID, events)
+	if err != nil {
+		code := websocket.CloseTryAgainLater
+		if !errors.Is(err, domainWebhook.ErrTooManyStreams) && !errors.Is(err, domainWebhook.ErrStreamClosed) {
+			code = websocket.CloseInternalServerErr
+		}
+		conn.close(code, err.Error())
+		return
+	}
+	defer h.webhookUC.CloseEventStream(sub)
+
+	h.logger.InfoWithFields("Event stream opened", map[string]interface{}{
+		"session_id": sessionID,
+		"events":     events,
+	})
+
+	if err := writeStreamJSON(conn, &streamNotice{Event: "stream.ready", SessionID: sessionID, Events: events}); err != nil {
+		return
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		h.readStream(conn, sub)
+	}()
+
+	ticker := time.NewTicker(streamPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			h.logger.InfoWithFields("Event stream closed", map[string]interface{}{
+				"session_id": sessionID,
+			})
+			return
+		case event, ok := <-sub.Events():
+			if !ok {
+				conn.close(websocket.CloseGoingAway, "server shutting down")
+				return
+			}
+			if dropped := sub.TakeDropped(); dropped > 0 {
+				if err := writeStreamJSON(conn, &streamNotice{Event: "stream.lagged", Dropped: dropped}); err != nil {
+					return
+				}
+			}
+			if err := conn.writeText(event.Payload); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(streamWriteWait)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// readStream applies the filters sent by the client until the connection ends
+func (h *EventStreamHandler) readStream(conn *streamConn, sub *domainWebhook.Subscription) {
+	conn.SetReadLimit(streamMaxMessageSize)
+	// Any message, pongs included, proves the client is still there
+	_ = conn.SetReadDeadline(time.Now().Add(streamPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(streamPongWait))
+	})
+
+	for {
+		messageType, message, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		_ = conn.SetReadDeadline(time.Now().Add(streamPongWait))
+		if messageType != websocket.TextMessage {
+			continue
+		}
+
+		var req streamFilterRequest
+		if err := json.Unmarshal(message, &req); err != nil {
+			_ = writeStreamJSON(conn, &streamNotice{Event: "stream.error", Error: "Invalid message, expected {\"events\": [...]}"})
+			continue
+		}
+		if invalidEvents := domainWebhook.ValidateEvents(req.Events); len(invalidEvents) > 0 {
+			_ = writeStreamJSON(conn, &streamNotice{Event: "stream.error", Error: fmt.Sprintf("Invalid event types: %v", invalidEvents)})
+			continue
+		}
+
+		sub.SetFilter(req.Events)
+		_ = writeStreamJSON(conn, &streamNotice{Event: "stream.filter", Events: sub.Filter()})
+	}
+}
+
+func (h *EventStreamHandler) resolveSession(c *fiber.Ctx) (*session.Session, *fiber.Error) {
+	sessionIdentifier := c.Params("sessionId")
+	if sessionIdentifier == "" {
+		return nil, fiber.NewError(400, "Session identifier is required")
+	}
+
+	sess, err := h.sessionResolver.ResolveSession(c.Context(), sessionIdentifier)
+	if err != nil {
+		return nil, fiber.NewError(404, "Session not found")
+	}
+
+	return sess, nil
+}
+
+func writeStreamJSON(conn *streamConn, notice *streamNotice) error {
+	payload, err := json.Marshal(notice)
+	if err != nil {
+		return err
+	}
+	return conn.writeText(payload)
+}
+
+// writeText sends a text message, giving up after streamWriteWait
+func (conn *streamConn) writeText(payload []byte) error {
+	conn.writeMu.Lock()
+	defer conn.writeMu.Unlock()
+
+	_ = conn.SetWriteDeadline(time.Now().Add(streamWriteWait))
+	return conn.WriteMessage(websocket.TextMessage, payload)
+}
+
+// close sends a close message with a code and reason
+func (conn *streamConn) close(code int, reason string) {
+	// Control frames carry at most 125 bytes, two of them for the code
+	if len(reason) > 123 {
+		reason = reason[:123]
+	}
+	_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(streamWriteWait))
+}
+
+// splitEventFilter parses a comma separated list of event types
+func splitEventFilter(value string) []string {
+	var events []string
+	for _, event := range strings.Split(value, ",") {
+		if event = strings.TrimSpace(event); event != "" {
+			events = append(events, event)
+		}
+	}
+	return events
+}
@@ -0,0 +1,462 @@
+package handlers
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+
+	"zpwoot/internal/app/message"
+	"zpwoot/internal/app/session"
+	domainSession "zpwoot/internal/domain/session"
+	"zpwoot/internal/infra/http/helpers"
+	"zpwoot/internal/infra/wameow"
+	"zpwoot/platform/logger"
+)
+
+// EvolutionHandler exposes Evolution-API-style routes and payloads on top of the zpwoot use cases,
+// so integrations written for Evolution API can be pointed at zpwoot unchanged.
+// The :instance path parameter is the zpwoot session name or ID.
+type EvolutionHandler struct {
+	messageUC       message.UseCase
+	sessionUC       session.UseCase
+	wameowManager   *wameow.Manager
+	sessionResolver *helpers.SessionResolver
+	logger          *logger.Logger
+}
+
+func NewEvolutionHandler(
+	messageUC message.UseCase,
+	sessionUC session.UseCase,
+	wameowManager *wameow.Manager,
+	sessionRepo helpers.SessionRepository,
+	logger *logger.Logger,
+) *EvolutionHandler {
+	return &EvolutionHandler{
+		messageUC:       messageUC,
+		sessionUC:       sessionUC,
+		wameowManager:   wameowManager,
+		sessionResolver: helpers.NewSessionResolver(logger, sessionRepo),
+		logger:          logger,
+	}
+}
+
+// evolutionKey identifies a message the way Evolution API does
+type evolutionKey struct {
+	RemoteJID string `json:"remoteJid"`
+	FromMe    bool   `json:"fromMe"`
+	ID        string `json:"id"`
+}
+
+type evolutionQuoted struct {
+	Key evolutionKey `json:"key"`
+}
+
+type evolutionSendTextRequest struct {
+	Number string           `json:"number"`
+	Text   string           `json:"text"`
+	Quoted *evolutionQuoted `json:"quoted,omitempty"`
+}
+
+type evolutionSendMediaRequest struct {
+	Number    string           `json:"number"`
+	MediaType string           `json:"mediatype"`
+	MimeType  string           `json:"mimetype"`
+	Caption   string           `json:"caption"`
+	Media     string           `json:"media"`
+	FileName  string           `json:"fileName"`
+	Quoted    *evolutionQuoted `json:"quoted,omitempty"`
+}
+
+type evolutionSendAudioRequest struct {
+	Number string           `json:"number"`
+	Audio  string           `json:"audio"`
+	Quoted *evolutionQuoted `json:"quoted,omitempty"`
+}
+
+type evolutionSendLocationRequest struct {
+	Number    string  `json:"number"`
+	Name      string  `json:"name"`
+	Address   string  `json:"address"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+type evolutionContact struct {
+	FullName    string `json:"fullName"`
+	PhoneNumber string `json:"phoneNumber"`
+}
+
+type evolutionSendContactRequest struct {
+	Number  string             `json:"number"`
+	Contact []evolutionContact `json:"contact"`
+}
+
+type evolutionSendReactionRequest struct {
+	Key      evolutionKey `json:"key"`
+	Reaction string       `json:"reaction"`
+}
+
+type evolutionReadMessagesRequest struct {
+	ReadMessages []evolutionKey `json:"readMessages"`
+}
+
+// SendText handles POST /message/sendText/:instance
+func (h *EvolutionHandler) SendText(c *fiber.Ctx) error {
+	var req evolutionSendTextRequest
+	if err := c.BodyParser(&req); err != nil || req.Number == "" || req.Text == "" {
+		return h.badRequest(c, "number and text are required")
+	}
+
+	return h.send(c, &message.SendMessageRequest{
+		RemoteJID:   evolutionJID(req.Number),
+		Type:        "text",
+		Body:        req.Text,
+		ContextInfo: evolutionContextInfo(req.Quoted),
+	}, map[string]interface{}{"conversation": req.Text})
+}
+
+// SendMedia handles POST /message/sendMedia/:instance
+func (h *EvolutionHandler) SendMedia(c *fiber.Ctx) error {
+	var req evolutionSendMediaRequest
+	if err := c.BodyParser(&req); err != nil || req.Number == "" || req.Media == "" {
+		return h.badRequest(c, "number and media are required")
+	}
+
+	switch req.MediaType {
+	case "image", "video", "document", "audio":
+	default:
+		return h.badRequest(c, "mediatype must be one of image, video, document, audio")
+	}
+
+	return h.send(c, &message.SendMessageRequest{
+		RemoteJID:   evolutionJID(req.Number),
+		Type:        req.MediaType,
+		Caption:     req.Caption,
+		File:        evolutionMedia(req.Media, req.MimeType),
+		Filename:    req.FileName,
+		MimeType:    req.MimeType,
+		ContextInfo: evolutionContextInfo(req.Quoted),
+	}, map[string]interface{}{req.MediaType + "Message": map[string]interface{}{"caption": req.Caption}})
+}
+
+// SendWhatsAppAudio handles POST /message/sendWhatsAppAudio/:instance
+func (h *EvolutionHandler) SendWhatsAppAudio(c *fiber.Ctx) error {
+	var req evolutionSendAudioRequest
+	if err := c.BodyParser(&req); err != nil || req.Number == "" || req.Audio == "" {
+		return h.badRequest(c, "number and audio are required")
+	}
+
+	return h.send(c, &message.SendMessageRequest{
+		RemoteJID:   evolutionJID(req.Number),
+		Type:        "audio",
+		File:        evolutionMedia(req.Audio, "audio/ogg"),
+		ContextInfo: evolutionContextInfo(req.Quoted),
+	}, map[string]interface{}{"audioMessage": map[string]interface{}{}})
+}
+
+// SendLocation handles POST /message/sendLocation/:instance
+func (h *EvolutionHandler) SendLocation(c *fiber.Ctx) error {
+	var req evolutionSendLocationRequest
+	if err := c.BodyParser(&req); err != nil || req.Number == "" {
+		return h.badRequest(c, "number, latitude and longitude are required")
+	}
+
+	return h.send(c, &message.SendMessageRequest{
+		RemoteJID: evolutionJID(req.Number),
+		Type:      "location",
+		Body:      req.Name,
+		Latitude:  req.Latitude,
+		Longitude: req.Longitude,
+		Address:   req.Address,
+	}, map[string]interface{}{"locationMessage": map[string]interface{}{
+		"degreesLatitude":  req.Latitude,
+		"degreesLongitude": req.Longitude,
+		"name":             req.Name,
+		"address":          req.Address,
+	}})
+}
+
+// SendContact handles POST /message/sendContact/:instance; one message is sent per contact
+func (h *EvolutionHandler) SendContact(c *fiber.Ctx) error {
+	var req evolutionSendContactRequest
+	if err := c.BodyParser(&req); err != nil || req.Number == "" || len(req.Contact) == 0 {
+		return h.badRequest(c, "number and contact are required")
+	}
+
+	sessionID, ok := h.resolveInstance(c)
+	if !ok {
+		return nil
+	}
+
+	var last *message.SendMessageResponse
+	for _, contact := range req.Contact {
+		response, err := h.messageUC.SendMessage(c.Context(), sessionID, &message.SendMessageRequest{
+			RemoteJID:    evolutionJID(req.Number),
+			Type:         "contact",
+			ContactName:  contact.FullName,
+			ContactPhone: contact.PhoneNumber,
+		})
+		if err != nil {
+			return h.sendError(c, err)
+		}
+		last = response
+	}
+
+	return c.Status(201).JSON(evolutionMessage(evolutionJID(req.Number), last, map[string]interface{}{"contactMessage": req.Contact}))
+}
+
+// SendReaction handles POST /message/sendReaction/:instance
+func (h *EvolutionHandler) SendReaction(c *fiber.Ctx) error {
+	var req evolutionSendReactionRequest
+	if err := c.BodyParser(&req); err != nil || req.Key.RemoteJID == "" || req.Key.ID == "" {
+		return h.badRequest(c, "key.remoteJid and key.id are required")
+	}
+
+	sessionID, ok := h.resolveInstance(c)
+	if !ok {
+		return nil
+	}
+
+	if err := h.wameowManager.SendReaction(sessionID, req.Key.RemoteJID, req.Key.ID, req.Reaction, "", req.Key.FromMe); err != nil {
+		return h.sendError(c, err)
+	}
+
+	return c.Status(201).JSON(evolutionMessage(req.Key.RemoteJID, &message.SendMessageResponse{
+		Status:    "sent",
+		Timestamp: time.Now(),
+	}, map[string]interface{}{"reactionMessage": map[string]interface{}{"key": req.Key, "text": req.Reaction}}))
+}
+
+// MarkMessageAsRead handles POST /chat/markMessageAsRead/:instance
+func (h *EvolutionHandler) MarkMessageAsRead(c *fiber.Ctx) error {
+	var req evolutionReadMessagesRequest
+	if err := c.BodyParser(&req); err != nil || len(req.ReadMessages) == 0 {
+		return h.badRequest(c, "readMessages is required")
+	}
+
+	sessionID, ok := h.resolveInstance(c)
+	if !ok {
+		return nil
+	}
+
+	// Receipts are sent per chat; group senders are looked up in the message store
+	chats := make(map[string][]string)
+	order := make([]string, 0, 1)
+	for _, key := range req.ReadMessages {
+		if key.FromMe || key.ID == "" {
+			continue
+		}
+		if _, exists := chats[key.RemoteJID]; !exists {
+			order = append(order, key.RemoteJID)
+		}
+		chats[key.RemoteJID] = append(chats[key.RemoteJID], key.ID)
+	}
+
+	for _, chat := range order {
+		if _, err := h.messageUC.MarkAsRead(c.Context(), &message.MarkAsReadRequest{
+			SessionID:  sessionID,
+			RemoteJID:  chat,
+			MessageIDs: chats[chat],
+		}); err != nil {
+			return h.sendError(c, err)
+		}
+	}
+
+	return c.Status(201).JSON(fiber.Map{
+		"message": "Read messages",
+		"read":    "success",
+	})
+}
+
+// ConnectionState handles GET /instance/connectionState/:instance
+func (h *EvolutionHandler) ConnectionState(c *fiber.Ctx) error {
+	sess, ok := h.resolveInstanceSession(c)
+	if !ok {
+		return nil
+	}
+
+	connected := sess.IsConnected
+	if h.wameowManager != nil {
+		connected = h.wameowManager.IsConnected(sess.ID.String())
+	}
+
+	state := "close"
+	if connected {
+		state = "open"
+	}
+
+	return c.JSON(fiber.Map{
+		"instance": fiber.Map{
+			"instanceName": sess.Name,
+			"state":        state,
+		},
+	})
+}
+
+// Connect handles GET /instance/connect/:instance, starting the connection and returning the QR code when pairing is needed
+func (h *EvolutionHandler) Connect(c *fiber.Ctx) error {
+	sessionID, ok := h.resolveInstance(c)
+	if !ok {
+		return nil
+	}
+
+	ctx := c.Context()
+	if _, err := h.sessionUC.ConnectSession(ctx, sessionID); err != nil && !strings.Contains(err.Error(), "already connected") {
+		return h.sendError(c, err)
+	}
+
+	qr, err := h.waitForQRCode(ctx, sessionID)
+	if err != nil {
+		// Already paired sessions have no QR code to show
+		return c.JSON(fiber.Map{"count": 0})
+	}
+
+	return c.JSON(fiber.Map{
+		"pairingCode": nil,
+		"code":        qr.QRCode,
+		"base64":      qr.QRCodeImage,
+		"count":       1,
+	})
+}
+
+// Logout handles DELETE /instance/logout/:instance
+func (h *EvolutionHandler) Logout(c *fiber.Ctx) error {
+	sessionID, ok := h.resolveInstance(c)
+	if !ok {
+		return nil
+	}
+
+	if err := h.sessionUC.LogoutSession(c.Context(), sessionID); err != nil {
+		return h.sendError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "SUCCESS",
+		"error":  false,
+		"response": fiber.Map{
+			"message": "Instance logged out",
+		},
+	})
+}
+
+// send resolves the instance, sends the message and answers with an Evolution-style message object
+func (h *EvolutionHandler) send(c *fiber.Ctx, req *message.SendMessageRequest, content map[string]interface{}) error {
+	sessionID, ok := h.resolveInstance(c)
+	if !ok {
+		return nil
+	}
+
+	response, err := h.messageUC.SendMessage(c.Context(), sessionID, req)
+	if err != nil {
+		return h.sendError(c, err)
+	}
+
+	return c.Status(201).JSON(evolutionMessage(req.RemoteJID, response, content))
+}
+
+// waitForQRCode gives a freshly started connection a few seconds to produce its QR code
+func (h *EvolutionHandler) waitForQRCode(ctx context.Context, sessionID string) (*session.QRCodeResponse, error) {
+	var lastErr error
+	for attempt := 0; attempt < 10; attempt++ {
+		qr, err := h.sessionUC.GetQRCode(ctx, sessionID)
+		if err == nil && qr.QRCode != "" {
+			return qr, nil
+		}
+		lastErr = err
+		time.Sleep(500 * time.Millisecond)
+	}
+	return nil, lastErr
+}
+
+func (h *EvolutionHandler) resolveInstance(c *fiber.Ctx) (string, bool) {
+	sess, ok := h.resolveInstanceSession(c)
+	if !ok {
+		return "", false
+	}
+	return sess.ID.String(), true
+}
+
+// resolveInstanceSession resolves the :instance parameter, writing a 404 response when it is unknown
+func (h *EvolutionHandler) resolveInstanceSession(c *fiber.Ctx) (*domainSession.Session, bool) {
+	instance := c.Params("instance")
+	sess, err := h.sessionResolver.ResolveSession(c.Context(), instance)
+	if err != nil {
+		_ = c.Status(404).JSON(evolutionError(404, "Not Found", "The \""+instance+"\" instance does not exist"))
+		return nil, false
+	}
+	return sess, true
+}
+
+func (h *EvolutionHandler) badRequest(c *fiber.Ctx, msg string) error {
+	return c.Status(400).JSON(evolutionError(400, "Bad Request", msg))
+}
+
+func (h *EvolutionHandler) sendError(c *fiber.Ctx, err error) error {
+	h.logger.ErrorWithFields("Evolution compatibility request failed", map[string]interface{}{
+		"path":  c.Path(),
+		"error": err.Error(),
+	})
+
+	if status, _, ok := quotaErrorStatus(err); ok {
+		return c.Status(status).JSON(evolutionError(status, utils.StatusMessage(status), err.Error()))
+	}
+	if strings.Contains(err.Error(), "not connected") || strings.Contains(err.Error(), "not logged in") {
+		return c.Status(400).JSON(evolutionError(400, "Bad Request", "Instance is not connected"))
+	}
+	return c.Status(500).JSON(evolutionError(500, "Internal Server Error", err.Error()))
+}
+
+// evolutionError builds the Evolution API error body
+func evolutionError(status int, statusText, msg string) fiber.Map {
+	return fiber.Map{
+		"status": status,
+		"error":  statusText,
+		"response": fiber.Map{
+			"message": []string{msg},
+		},
+	}
+}
+
+// evolutionMessage builds the Evolution API representation of a sent message
+func evolutionMessage(remoteJID string, response *message.SendMessageResponse, content map[string]interface{}) fiber.Map {
+	return fiber.Map{
+		"key": evolutionKey{
+			RemoteJID: remoteJID,
+			FromMe:    true,
+			ID:        response.ID,
+		},
+		"message":          content,
+		"messageTimestamp": strconv.FormatInt(response.Timestamp.Unix(), 10),
+		"status":           "PENDING",
+	}
+}
+
+// evolutionJID turns an Evolution number into a JID; JIDs are passed through
+func evolutionJID(number string) string {
+	if strings.Contains(number, "@") {
+		return number
+	}
+	return strings.TrimPrefix(strings.TrimSpace(number), "+") + "@s.whatsapp.net"
+}
+
+// evolutionMedia turns raw base64 media into a data URL; URLs and data URLs are passed through
+func evolutionMedia(media, mimeType string) string {
+	if strings.HasPrefix(media, "http://") || strings.HasPrefix(media, "https://") || strings.HasPrefix(media, "data:") {
+		return media
+	}
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+	return "data:" + mimeType + ";base64," + media
+}
+
+func evolutionContextInfo(quoted *evolutionQuoted) *message.ContextInfo {
+	if quoted == nil || quoted.Key.ID == "" {
+		return nil
+	}
+	return &message.ContextInfo{StanzaID: quoted.Key.ID}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"zpwoot/internal/app/common"
 	"zpwoot/internal/app/group"
 	domainSession "zpwoot/internal/domain/session"
 	"zpwoot/internal/infra/http/helpers"
@@ -89,7 +90,7 @@ func (h *GroupHandler) handleGroupActionWithJID(
 		return fiber.NewError(500, err.Error())
 	}
 
-	return c.JSON(response)
+	return c.JSON(common.NewSuccessResponse(response))
 }
 
 // CreateGroup creates a new WhatsApp group
@@ -123,7 +124,7 @@ func (h *GroupHandler) CreateGroup(c *fiber.Ctx) error {
 		return fiber.NewError(500, err.Error())
 	}
 
-	return c.JSON(response)
+	return c.JSON(common.NewSuccessResponse(response))
 }
 
 // GetGroupInfo retrieves information about a specific group
@@ -157,7 +158,7 @@ func (h *GroupHandler) GetGroupInfo(c *fiber.Ctx) error {
 		return fiber.NewError(500, err.Error())
 	}
 
-	return c.JSON(response)
+	return c.JSON(common.NewSuccessResponse(response))
 }
 
 // ListGroups lists all groups the user is a member of
@@ -180,7 +181,7 @@ func (h *GroupHandler) ListGroups(c *fiber.Ctx) error {
 		return fiber.NewError(500, err.Error())
 	}
 
-	return c.JSON(response)
+	return c.JSON(common.NewSuccessResponse(response))
 }
 
 // UpdateGroupParticipants adds, removes, promotes, or demotes group participants
@@ -222,7 +223,7 @@ func (h *GroupHandler) UpdateGroupParticipants(c *fiber.Ctx) error {
 		return fiber.NewError(500, err.Error())
 	}
 
-	return c.JSON(response)
+	return c.JSON(common.NewSuccessResponse(response))
 }
 
 // SetGroupName updates the group name
@@ -323,7 +324,7 @@ func (h *GroupHandler) GetGroupInviteLink(c *fiber.Ctx) error {
 		return fiber.NewError(500, err.Error())
 	}
 
-	return c.JSON(response)
+	return c.JSON(common.NewSuccessResponse(response))
 }
 
 // JoinGroup joins a group using an invite link
@@ -355,7 +356,7 @@ func (h *GroupHandler) JoinGroup(c *fiber.Ctx) error {
 		return fiber.NewError(500, err.Error())
 	}
 
-	return c.JSON(response)
+	return c.JSON(common.NewSuccessResponse(response))
 }
 
 // LeaveGroup leaves a group
@@ -418,7 +419,7 @@ func (h *GroupHandler) UpdateGroupSettings(c *fiber.Ctx) error {
 		return fiber.NewError(500, err.Error())
 	}
 
-	return c.JSON(response)
+	return c.JSON(common.NewSuccessResponse(response))
 }
 
 // GetGroupRequestParticipants gets the list of participants that have requested to join the group
@@ -438,7 +439,7 @@ func (h *GroupHandler) GetGroupRequestParticipants(c *fiber.Ctx) error {
 		"group_jid":  groupJid,
 	})
 
-	participants, err := h.groupUC.GetGroupRequestParticipants(c.Context(), sess.ID.String(), groupJid)
+	response, err := h.groupUC.GetGroupRequestParticipants(c.Context(), sess.ID.String(), groupJid)
 	if err != nil {
 		h.logger.ErrorWithFields("Failed to get group request participants", map[string]interface{}{
 			"session_id": sess.ID.String(),
@@ -448,28 +449,18 @@ func (h *GroupHandler) GetGroupRequestParticipants(c *fiber.Ctx) error {
 		return fiber.NewError(500, err.Error())
 	}
 
-	response := map[string]interface{}{
-		"groupJid":     groupJid,
-		"participants": participants,
-		"total":        len(participants),
-	}
-
-	return c.JSON(response)
+	return c.JSON(common.NewSuccessResponse(response))
 }
 
-// UpdateGroupRequestParticipants approves or rejects requests to join the group
+// UpdateGroupRequestParticipants approves or rejects requests to join the group, either the listed
+// participants or, with all set, every pending request
 func (h *GroupHandler) UpdateGroupRequestParticipants(c *fiber.Ctx) error {
 	sess, fiberErr := h.resolveSession(c)
 	if fiberErr != nil {
 		return fiberErr
 	}
 
-	var req struct {
-		GroupJID     string   `json:"groupJid"`     // Group JID
-		Action       string   `json:"action"`       // "approve" or "reject"
-		Participants []string `json:"participants"` // List of participant JIDs
-	}
-
+	var req group.UpdateGroupJoinRequestsRequest
 	if err := c.BodyParser(&req); err != nil {
 		return fiber.NewError(400, "Invalid request body")
 	}
@@ -478,43 +469,38 @@ func (h *GroupHandler) UpdateGroupRequestParticipants(c *fiber.Ctx) error {
 		return fiber.NewError(400, "Group JID is required in request body")
 	}
 
-	if req.Action == "" {
-		return fiber.NewError(400, "Action is required")
+	if req.Action != "approve" && req.Action != "reject" {
+		return fiber.NewError(400, "Action must be 'approve' or 'reject'")
 	}
 
-	if len(req.Participants) == 0 {
-		return fiber.NewError(400, "At least one participant is required")
+	if req.All && len(req.Participants) > 0 {
+		return fiber.NewError(400, "Use either participants or all, not both")
 	}
 
-	groupJid := req.GroupJID
+	if !req.All && len(req.Participants) == 0 {
+		return fiber.NewError(400, "At least one participant is required, or set all to true")
+	}
 
 	h.logger.InfoWithFields("Updating group request participants", map[string]interface{}{
 		"session_id":   sess.ID.String(),
-		"group_jid":    groupJid,
+		"group_jid":    req.GroupJID,
 		"action":       req.Action,
 		"participants": len(req.Participants),
+		"all":          req.All,
 	})
 
-	success, failed, err := h.groupUC.UpdateGroupRequestParticipants(c.Context(), sess.ID.String(), groupJid, req.Participants, req.Action)
+	response, err := h.groupUC.UpdateGroupRequestParticipants(c.Context(), sess.ID.String(), &req)
 	if err != nil {
 		h.logger.ErrorWithFields("Failed to update group request participants", map[string]interface{}{
 			"session_id": sess.ID.String(),
-			"group_jid":  groupJid,
+			"group_jid":  req.GroupJID,
 			"action":     req.Action,
 			"error":      err.Error(),
 		})
 		return fiber.NewError(500, err.Error())
 	}
 
-	response := map[string]interface{}{
-		"groupJid":     groupJid,
-		"action":       req.Action,
-		"participants": req.Participants,
-		"success":      success,
-		"failed":       failed,
-	}
-
-	return c.JSON(response)
+	return c.JSON(common.NewSuccessResponse(response))
 }
 
 // SetGroupJoinApprovalMode sets the group join approval mode
@@ -561,7 +547,7 @@ func (h *GroupHandler) SetGroupJoinApprovalMode(c *fiber.Ctx) error {
 		"status":          "updated",
 	}
 
-	return c.JSON(response)
+	return c.JSON(common.NewSuccessResponse(response))
 }
 
 // SetGroupMemberAddMode sets the group member add mode
@@ -612,7 +598,7 @@ func (h *GroupHandler) SetGroupMemberAddMode(c *fiber.Ctx) error {
 		"status":   "updated",
 	}
 
-	return c.JSON(response)
+	return c.JSON(common.NewSuccessResponse(response))
 }
 
 // ============================================================================
@@ -662,10 +648,7 @@ func (h *GroupHandler) GetGroupInfoFromLink(c *fiber.Ctx) error {
 		"group_name": response.Name,
 	})
 
-	return c.JSON(fiber.Map{
-		"success": true,
-		"data":    response,
-	})
+	return c.JSON(common.NewSuccessResponse(response))
 }
 
 // GetGroupInfoFromInvite gets group information from an invite
@@ -713,10 +696,7 @@ func (h *GroupHandler) GetGroupInfoFromInvite(c *fiber.Ctx) error {
 		"group_name": response.Name,
 	})
 
-	return c.JSON(fiber.Map{
-		"success": true,
-		"data":    response,
-	})
+	return c.JSON(common.NewSuccessResponse(response))
 }
 
 // JoinGroupWithInvite joins a group using a specific invite
@@ -764,8 +744,5 @@ func (h *GroupHandler) JoinGroupWithInvite(c *fiber.Ctx) error {
 		"success":    response.Success,
 	})
 
-	return c.Status(200).JSON(fiber.Map{
-		"success": true,
-		"data":    response,
-	})
+	return c.Status(200).JSON(common.NewSuccessResponse(response))
 }
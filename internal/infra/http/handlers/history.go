@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"zpwoot/internal/app/common"
+	"zpwoot/internal/app/history"
+	domainHistory "zpwoot/internal/domain/history"
+	"zpwoot/internal/domain/session"
+	"zpwoot/internal/infra/http/helpers"
+	"zpwoot/platform/logger"
+	"zpwoot/platform/pagination"
+)
+
+type HistoryHandler struct {
+	logger          *logger.Logger
+	historyUC       history.UseCase
+	sessionResolver *helpers.SessionResolver
+}
+
+func NewHistoryHandler(appLogger *logger.Logger, historyUC history.UseCase, sessionRepo helpers.SessionRepository) *HistoryHandler {
+	return &HistoryHandler{
+		logger:          appLogger,
+		historyUC:       historyUC,
+		sessionResolver: helpers.NewSessionResolver(appLogger, sessionRepo),
+	}
+}
+
+// @Summary List chat messages
+// @Description List the stored messages of a chat, newest first: text, media metadata, replies, mentions, edits, revokes, pins and disappearing messages timers of every message the session sent or received while MESSAGE_HISTORY_ENABLED was on. Ephemeral messages are left out once they have disappeared from the chat. Messages sent through the API, Chatwoot or the draft scheduler carry the actor that sent them. Filter by time range and actor and full-text search the message text; pass the returned nextCursor as cursor to get older messages
+// @Tags Messages
+// @Security ApiKeyAuth
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param jid path string true "Chat JID" example("5511999999999@s.whatsapp.net")
+// @Param from query string false "Only messages sent at or after this RFC 3339 timestamp" example("2024-01-01T00:00:00Z")
+// @Param to query string false "Only messages sent at or before this RFC 3339 timestamp" example("2024-01-31T23:59:59Z")
+// @Param q query string false "Full-text search on the message text" example("invoice")
+// @Param actor query string false "Only outgoing messages sent by this kind of actor" Enums(api, chatwoot, autoresponder, campaign, scheduler)
+// @Param pinned query bool false "Only messages currently pinned in the chat"
+// @Param limit query int false "Number of messages to return (max 200)" default(50)
+// @Param cursor query string false "nextCursor of the previous page"
+// @Success 200 {object} common.SuccessResponse{data=history.ListChatMessagesResponse} "Messages retrieved successfully"
+// @Failure 400 {object} object "Bad Request"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/chats/{jid}/messages [get]
+func (h *HistoryHandler) ListChatMessages(c *fiber.Ctx) error {
+	sess, chatJID, fiberErr := h.resolveChat(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	var req history.ListChatMessagesRequest
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid query parameters"))
+	}
+
+	for _, param := range []struct {
+		name   string
+		target **time.Time
+	}{{"from", &req.From}, {"to", &req.To}} {
+		raw := c.Query(param.name)
+		if raw == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			return c.Status(400).JSON(common.NewErrorResponse("'" + param.name + "' must be an RFC 3339 timestamp"))
+		}
+		*param.target = &parsed
+	}
+
+	response, err := h.historyUC.ListChatMessages(c.Context(), sess.ID.String(), chatJID, &req)
+	if err != nil {
+		return h.handleError(c, "list chat messages", err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "Messages retrieved successfully"))
+}
+
+// resolveSession resolves the session from the sessionId path parameter
+func (h *HistoryHandler) resolveSession(c *fiber.Ctx) (*session.Session, *fiber.Error) {
+	sessionIdentifier := c.Params("sessionId")
+	if sessionIdentifier == "" {
+		return nil, fiber.NewError(400, "Session identifier is required")
+	}
+
+	sess, err := h.sessionResolver.ResolveSession(c.Context(), sessionIdentifier)
+	if err != nil {
+		return nil, fiber.NewError(404, "Session not found")
+	}
+
+	return sess, nil
+}
+
+// resolveChat resolves the session and the URL-decoded chat JID path parameter
+func (h *HistoryHandler) resolveChat(c *fiber.Ctx) (*session.Session, string, *fiber.Error) {
+	chatJID, err := url.PathUnescape(c.Params("jid"))
+	if err != nil || chatJID == "" {
+		return nil, "", fiber.NewError(400, "Chat JID is required")
+	}
+
+	sess, fiberErr := h.resolveSession(c)
+	if fiberErr != nil {
+		return nil, "", fiberErr
+	}
+
+	return sess, chatJID, nil
+}
+
+// handleError maps message history domain errors to HTTP responses
+func (h *HistoryHandler) handleError(c *fiber.Ctx, action string, err error) error {
+	switch {
+	case errors.Is(err, domainHistory.ErrInvalidChatJID), errors.Is(err, domainHistory.ErrInvalidTimeRange),
+		errors.Is(err, domainHistory.ErrInvalidActorType):
+		return c.Status(400).JSON(common.NewErrorResponse(err.Error()))
+	case errors.Is(err, pagination.ErrInvalidCursor):
+		return c.Status(400).JSON(common.NewErrorResponseWithCode(err.Error(), "INVALID_CURSOR"))
+	}
+
+	h.logger.ErrorWithFields("Failed to "+action, map[string]interface{}{
+		"error": err.Error(),
+	})
+	return c.Status(500).JSON(common.NewErrorResponse("Failed to " + action))
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"zpwoot/internal/app/common"
+	"zpwoot/internal/app/job"
+	domainJob "zpwoot/internal/domain/job"
+	"zpwoot/platform/logger"
+)
+
+type JobHandler struct {
+	logger *logger.Logger
+	jobUC  job.UseCase
+}
+
+func NewJobHandler(appLogger *logger.Logger, jobUC job.UseCase) *JobHandler {
+	return &JobHandler{
+		logger: appLogger,
+		jobUC:  jobUC,
+	}
+}
+
+// @Summary List background jobs
+// @Description List the background jobs of this replica with their state, last run and next run.
+// @Description Exclusive jobs are in standby on every replica but the one running them.
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} common.SuccessResponse{data=job.ListJobsResponse} "Jobs retrieved successfully"
+// @Router /admin/jobs [get]
+func (h *JobHandler) ListJobs(c *fiber.Ctx) error {
+	return c.JSON(common.NewSuccessResponse(h.jobUC.ListJobs(c.Context()), "Jobs retrieved successfully"))
+}
+
+// @Summary Get background job
+// @Description Get the state, last run and next run of a background job
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Param name path string true "Job name" example("draft_scheduler")
+// @Success 200 {object} common.SuccessResponse{data=job.JobResponse} "Job retrieved successfully"
+// @Failure 404 {object} object "Job not found"
+// @Router /admin/jobs/{name} [get]
+func (h *JobHandler) GetJob(c *fiber.Ctx) error {
+	response, err := h.jobUC.GetJob(c.Context(), c.Params("name"))
+	if err != nil {
+		return h.handleError(c, "get job", err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "Job retrieved successfully"))
+}
+
+// @Summary Run background job now
+// @Description Queue a run of a background job outside its schedule; a run in progress finishes first.
+// @Description Exclusive jobs can only be triggered on the replica running them.
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Param name path string true "Job name" example("draft_scheduler")
+// @Success 202 {object} common.SuccessResponse{data=job.JobResponse} "Job run queued"
+// @Failure 404 {object} object "Job not found"
+// @Failure 409 {object} object "Job already queued or running on another replica"
+// @Failure 503 {object} object "Job scheduler not running"
+// @Router /admin/jobs/{name}/run [post]
+func (h *JobHandler) TriggerJob(c *fiber.Ctx) error {
+	response, err := h.jobUC.TriggerJob(c.Context(), c.Params("name"))
+	if err != nil {
+		return h.handleError(c, "trigger job", err)
+	}
+
+	return c.Status(202).JSON(common.NewSuccessResponse(response, "Job run queued"))
+}
+
+func (h *JobHandler) handleError(c *fiber.Ctx, action string, err error) error {
+	switch {
+	case errors.Is(err, domainJob.ErrJobNotFound):
+		return c.Status(404).JSON(common.NewErrorResponse(err.Error()))
+	case errors.Is(err, domainJob.ErrJobNotLeader),
+		errors.Is(err, domainJob.ErrJobAlreadyQueued):
+		return c.Status(409).JSON(common.NewErrorResponse(err.Error()))
+	case errors.Is(err, domainJob.ErrJobNotStarted):
+		return c.Status(503).JSON(common.NewErrorResponse(err.Error()))
+	}
+
+	h.logger.ErrorWithFields("Failed to "+action, map[string]interface{}{
+		"job":   c.Params("name"),
+		"error": err.Error(),
+	})
+	return c.Status(500).JSON(common.NewErrorResponse("Failed to " + action))
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"zpwoot/internal/app/common"
+	"zpwoot/internal/app/maintenance"
+	domainMaintenance "zpwoot/internal/domain/maintenance"
+	"zpwoot/internal/domain/session"
+	"zpwoot/platform/logger"
+)
+
+type MaintenanceHandler struct {
+	logger        *logger.Logger
+	maintenanceUC maintenance.UseCase
+}
+
+func NewMaintenanceHandler(appLogger *logger.Logger, maintenanceUC maintenance.UseCase) *MaintenanceHandler {
+	return &MaintenanceHandler{
+		logger:        appLogger,
+		maintenanceUC: maintenanceUC,
+	}
+}
+
+// @Summary Get maintenance status
+// @Description Get whether the instance is in maintenance and which sessions are frozen
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} common.SuccessResponse{data=maintenance.MaintenanceStatusResponse} "Maintenance status retrieved successfully"
+// @Router /admin/maintenance [get]
+func (h *MaintenanceHandler) GetStatus(c *fiber.Ctx) error {
+	return c.JSON(common.NewSuccessResponse(h.maintenanceUC.GetStatus(c.Context()), "Maintenance status retrieved successfully"))
+}
+
+// @Summary Toggle maintenance mode
+// @Description Put the whole instance into maintenance or take it out. During maintenance reads keep working,
+// @Description sends return 503 with Retry-After and webhook dispatch is paused until maintenance ends.
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param request body maintenance.SetMaintenanceRequest true "Maintenance settings"
+// @Success 200 {object} common.SuccessResponse{data=maintenance.MaintenanceStatusResponse} "Maintenance mode updated successfully"
+// @Failure 400 {object} object "Invalid request body"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /admin/maintenance [put]
+func (h *MaintenanceHandler) SetMaintenance(c *fiber.Ctx) error {
+	var req maintenance.SetMaintenanceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid request body"))
+	}
+	if req.RetryAfter < 0 {
+		return c.Status(400).JSON(common.NewErrorResponse("retryAfter must be positive"))
+	}
+
+	response, err := h.maintenanceUC.SetMaintenance(c.Context(), &req)
+	if err != nil {
+		return h.handleError(c, "update maintenance mode", err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "Maintenance mode updated successfully"))
+}
+
+// @Summary Freeze session sends
+// @Description Put a single session into maintenance: its sends return 503 and its webhooks are held back
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param request body maintenance.FreezeSessionRequest false "Freeze reason"
+// @Success 200 {object} common.SuccessResponse{data=maintenance.FrozenSessionResponse} "Session frozen successfully"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /admin/maintenance/sessions/{sessionId} [put]
+func (h *MaintenanceHandler) FreezeSession(c *fiber.Ctx) error {
+	var req maintenance.FreezeSessionRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(common.NewErrorResponse("Invalid request body"))
+		}
+	}
+
+	response, err := h.maintenanceUC.FreezeSession(c.Context(), c.Params("sessionId"), &req)
+	if err != nil {
+		return h.handleError(c, "freeze session", err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "Session frozen successfully"))
+}
+
+// @Summary Unfreeze session sends
+// @Description Take a single session out of maintenance and release its held back webhooks
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Success 200 {object} common.SuccessResponse "Session unfrozen successfully"
+// @Failure 404 {object} object "Session not found or not frozen"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /admin/maintenance/sessions/{sessionId} [delete]
+func (h *MaintenanceHandler) UnfreezeSession(c *fiber.Ctx) error {
+	if err := h.maintenanceUC.UnfreezeSession(c.Context(), c.Params("sessionId")); err != nil {
+		return h.handleError(c, "unfreeze session", err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(nil, "Session unfrozen successfully"))
+}
+
+func (h *MaintenanceHandler) handleError(c *fiber.Ctx, action string, err error) error {
+	switch {
+	case errors.Is(err, session.ErrSessionNotFound):
+		return c.Status(404).JSON(common.NewErrorResponse("Session not found"))
+	case errors.Is(err, domainMaintenance.ErrSessionNotFrozen):
+		return c.Status(404).JSON(common.NewErrorResponse(err.Error()))
+	}
+
+	h.logger.ErrorWithFields("Failed to "+action, map[string]interface{}{
+		"session_id": c.Params("sessionId"),
+		"error":      err.Error(),
+	})
+	return c.Status(500).JSON(common.NewErrorResponse("Failed to " + action))
+}
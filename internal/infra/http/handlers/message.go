@@ -1,14 +1,21 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/url"
 	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 
 	"zpwoot/internal/app/common"
 	"zpwoot/internal/app/message"
+	domainMessage "zpwoot/internal/domain/message"
+	"zpwoot/internal/domain/poll"
+	"zpwoot/internal/domain/queue"
 	"zpwoot/internal/infra/http/helpers"
 	"zpwoot/internal/infra/wameow"
 	"zpwoot/platform/logger"
@@ -53,6 +60,12 @@ func (h *MessageHandler) handleMediaMessage(
 		return fiberErr
 	}
 
+	scheduleAt, fiberErr := scheduleAtFromBody(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+	req.ScheduleAt = scheduleAt
+
 	sess, err := h.sessionResolver.ResolveSession(c.Context(), sessionIdentifier)
 	if err != nil {
 		return c.Status(404).JSON(common.NewErrorResponse("Session not found"))
@@ -68,14 +81,50 @@ func (h *MessageHandler) handleMediaMessage(
 			"error":      err.Error(),
 		})
 
+		if handled, respErr := writeQuotaError(c, err); handled {
+			return respErr
+		}
+
 		if strings.Contains(err.Error(), "not connected") {
 			return c.Status(400).JSON(common.NewErrorResponse("Session is not connected"))
 		}
+		if strings.HasPrefix(err.Error(), "invalid request") {
+			return c.Status(400).JSON(common.NewErrorResponse(err.Error()))
+		}
 
 		return c.Status(500).JSON(common.NewErrorResponse(fmt.Sprintf("Failed to send %s message", messageType)))
 	}
 
-	return c.JSON(common.NewSuccessResponse(response, fmt.Sprintf("%s message sent successfully", strings.Title(messageType))))
+	return writeSendResponse(c, response, fmt.Sprintf("%s message sent successfully", strings.Title(messageType)))
+}
+
+// scheduleAtFromBody reads the optional scheduleAt of a JSON send body; the media parsers only read
+// their own fields
+func scheduleAtFromBody(c *fiber.Ctx) (*time.Time, *fiber.Error) {
+	if !c.Is("json") {
+		return nil, nil
+	}
+
+	var body struct {
+		ScheduleAt *time.Time `json:"scheduleAt"`
+	}
+	if err := json.Unmarshal(c.Body(), &body); err != nil {
+		return nil, fiber.NewError(400, "Invalid 'scheduleAt', expected an RFC3339 time")
+	}
+
+	return body.ScheduleAt, nil
+}
+
+// writeSendResponse answers a send; a message scheduled with scheduleAt or queued while the session
+// is disconnected is answered with 202 Accepted
+func writeSendResponse(c *fiber.Ctx, response *message.SendMessageResponse, sentMessage string) error {
+	if response.ScheduledID != "" {
+		return c.Status(fiber.StatusAccepted).JSON(common.NewSuccessResponse(response, "Message scheduled"))
+	}
+	if response.QueueID != "" {
+		return c.Status(fiber.StatusAccepted).JSON(common.NewSuccessResponse(response, "Session is not connected, message queued until it reconnects"))
+	}
+	return c.JSON(common.NewSuccessResponse(response, sentMessage))
 }
 
 // parseMediaRequest parses common media request fields
@@ -124,6 +173,7 @@ func parseMediaRequest(c *fiber.Ctx, messageType string, parseBody func(*fiber.C
 // @Success 200 {object} message.MessageResponse "Media message sent successfully"
 // @Failure 400 {object} object "Bad Request"
 // @Failure 404 {object} object "Session not found"
+// @Failure 429 {object} object "Daily message quota exceeded"
 // @Failure 500 {object} object "Internal Server Error"
 // @Router /sessions/{sessionId}/messages/send/media [post]
 func (h *MessageHandler) SendMedia(c *fiber.Ctx) error {
@@ -179,6 +229,10 @@ func (h *MessageHandler) SendMedia(c *fiber.Ctx) error {
 			"error":      err.Error(),
 		})
 
+		if handled, respErr := writeQuotaError(c, err); handled {
+			return respErr
+		}
+
 		if strings.Contains(err.Error(), "not connected") {
 			return c.Status(400).JSON(common.NewErrorResponse("Session is not connected"))
 		}
@@ -198,14 +252,14 @@ func (h *MessageHandler) SendMedia(c *fiber.Ctx) error {
 		"message_id": response.ID,
 	})
 
-	return c.JSON(common.NewSuccessResponse(response, "Media message sent successfully"))
+	return writeSendResponse(c, response, "Media message sent successfully")
 }
 
 // @Summary Send image message
-// @Description Send an image message through WhatsApp with optional reply context
+// @Description Send an image message through WhatsApp with optional reply context. The file can also be uploaded as multipart/form-data in a "file" part, with the other fields as form fields (contextInfo.stanzaId and contextInfo.participant for replies)
 // @Tags Messages
 // @Security ApiKeyAuth
-// @Accept json
+// @Accept json,mpfd
 // @Produce json
 // @Security ApiKeyAuth
 // @Param sessionId path string true "Session ID or Name" example("mySession")
@@ -213,9 +267,14 @@ func (h *MessageHandler) SendMedia(c *fiber.Ctx) error {
 // @Success 200 {object} common.SuccessResponse{data=message.SendMessageResponse} "Message sent successfully"
 // @Failure 400 {object} object "Invalid request"
 // @Failure 404 {object} object "Session not found"
+// @Failure 429 {object} object "Daily message quota exceeded"
 // @Failure 500 {object} object "Internal server error"
 // @Router /sessions/{sessionId}/messages/send/image [post]
 func (h *MessageHandler) SendImage(c *fiber.Ctx) error {
+	if isMultipartUpload(c) {
+		return h.sendUploadedMedia(c, "image")
+	}
+
 	return h.handleMediaMessage(c, "image", func(c *fiber.Ctx) (*message.SendMessageRequest, *fiber.Error) {
 		return parseMediaRequest(c, "image", func(c *fiber.Ctx) (string, string, string, string, string, *message.ContextInfo, error) {
 			var imageReq message.ImageMessageRequest
@@ -228,10 +287,10 @@ func (h *MessageHandler) SendImage(c *fiber.Ctx) error {
 }
 
 // @Summary Send audio message
-// @Description Send an audio message through WhatsApp with optional reply context
+// @Description Send an audio message through WhatsApp with optional reply context. The file can also be uploaded as multipart/form-data in a "file" part, with the other fields as form fields (contextInfo.stanzaId and contextInfo.participant for replies)
 // @Tags Messages
 // @Security ApiKeyAuth
-// @Accept json
+// @Accept json,mpfd
 // @Produce json
 // @Security ApiKeyAuth
 // @Param sessionId path string true "Session ID or Name" example("mySession")
@@ -239,9 +298,14 @@ func (h *MessageHandler) SendImage(c *fiber.Ctx) error {
 // @Success 200 {object} common.SuccessResponse{data=message.SendMessageResponse} "Message sent successfully"
 // @Failure 400 {object} object "Invalid request"
 // @Failure 404 {object} object "Session not found"
+// @Failure 429 {object} object "Daily message quota exceeded"
 // @Failure 500 {object} object "Internal server error"
 // @Router /sessions/{sessionId}/messages/send/audio [post]
 func (h *MessageHandler) SendAudio(c *fiber.Ctx) error {
+	if isMultipartUpload(c) {
+		return h.sendUploadedMedia(c, "audio")
+	}
+
 	sessionIdentifier := c.Params("sessionId")
 	if sessionIdentifier == "" {
 		return c.Status(400).JSON(common.NewErrorResponse("Session identifier is required"))
@@ -291,6 +355,10 @@ func (h *MessageHandler) SendAudio(c *fiber.Ctx) error {
 			"error":      err.Error(),
 		})
 
+		if handled, respErr := writeQuotaError(c, err); handled {
+			return respErr
+		}
+
 		if strings.Contains(err.Error(), "not connected") {
 			return c.Status(400).JSON(common.NewErrorResponse("Session is not connected"))
 		}
@@ -298,14 +366,14 @@ func (h *MessageHandler) SendAudio(c *fiber.Ctx) error {
 		return c.Status(500).JSON(common.NewErrorResponse("Failed to send audio message"))
 	}
 
-	return c.JSON(common.NewSuccessResponse(response, "Audio message sent successfully"))
+	return writeSendResponse(c, response, "Audio message sent successfully")
 }
 
 // @Summary Send video message
-// @Description Send a video message through WhatsApp with optional reply context
+// @Description Send a video message through WhatsApp with optional reply context. The file can also be uploaded as multipart/form-data in a "file" part, with the other fields as form fields (contextInfo.stanzaId and contextInfo.participant for replies)
 // @Tags Messages
 // @Security ApiKeyAuth
-// @Accept json
+// @Accept json,mpfd
 // @Produce json
 // @Security ApiKeyAuth
 // @Param sessionId path string true "Session ID or Name" example("mySession")
@@ -313,9 +381,14 @@ func (h *MessageHandler) SendAudio(c *fiber.Ctx) error {
 // @Success 200 {object} common.SuccessResponse{data=message.SendMessageResponse} "Message sent successfully"
 // @Failure 400 {object} object "Invalid request"
 // @Failure 404 {object} object "Session not found"
+// @Failure 429 {object} object "Daily message quota exceeded"
 // @Failure 500 {object} object "Internal server error"
 // @Router /sessions/{sessionId}/messages/send/video [post]
 func (h *MessageHandler) SendVideo(c *fiber.Ctx) error {
+	if isMultipartUpload(c) {
+		return h.sendUploadedMedia(c, "video")
+	}
+
 	return h.handleMediaMessage(c, "video", func(c *fiber.Ctx) (*message.SendMessageRequest, *fiber.Error) {
 		return parseMediaRequest(c, "video", func(c *fiber.Ctx) (string, string, string, string, string, *message.ContextInfo, error) {
 			var videoReq message.VideoMessageRequest
@@ -328,10 +401,10 @@ func (h *MessageHandler) SendVideo(c *fiber.Ctx) error {
 }
 
 // @Summary Send document message
-// @Description Send a document message through WhatsApp with optional reply context
+// @Description Send a document message through WhatsApp with optional reply context. The file can also be uploaded as multipart/form-data in a "file" part, with the other fields as form fields (contextInfo.stanzaId and contextInfo.participant for replies)
 // @Tags Messages
 // @Security ApiKeyAuth
-// @Accept json
+// @Accept json,mpfd
 // @Produce json
 // @Security ApiKeyAuth
 // @Param sessionId path string true "Session ID or Name" example("mySession")
@@ -339,9 +412,14 @@ func (h *MessageHandler) SendVideo(c *fiber.Ctx) error {
 // @Success 200 {object} common.SuccessResponse{data=message.SendMessageResponse} "Message sent successfully"
 // @Failure 400 {object} object "Invalid request"
 // @Failure 404 {object} object "Session not found"
+// @Failure 429 {object} object "Daily message quota exceeded"
 // @Failure 500 {object} object "Internal server error"
 // @Router /sessions/{sessionId}/messages/send/document [post]
 func (h *MessageHandler) SendDocument(c *fiber.Ctx) error {
+	if isMultipartUpload(c) {
+		return h.sendUploadedMedia(c, "document")
+	}
+
 	sessionIdentifier := c.Params("sessionId")
 	if sessionIdentifier == "" {
 		return c.Status(400).JSON(common.NewErrorResponse("Session identifier is required"))
@@ -397,6 +475,10 @@ func (h *MessageHandler) SendDocument(c *fiber.Ctx) error {
 			"error":      err.Error(),
 		})
 
+		if handled, respErr := writeQuotaError(c, err); handled {
+			return respErr
+		}
+
 		if strings.Contains(err.Error(), "not connected") {
 			return c.Status(400).JSON(common.NewErrorResponse("Session is not connected"))
 		}
@@ -404,14 +486,14 @@ func (h *MessageHandler) SendDocument(c *fiber.Ctx) error {
 		return c.Status(500).JSON(common.NewErrorResponse("Failed to send document message"))
 	}
 
-	return c.JSON(common.NewSuccessResponse(response, "Document message sent successfully"))
+	return writeSendResponse(c, response, "Document message sent successfully")
 }
 
 // @Summary Send sticker message
-// @Description Send a sticker message through WhatsApp
+// @Description Send a sticker message through WhatsApp. The file can also be uploaded as multipart/form-data in a "file" part, with the other fields as form fields (contextInfo.stanzaId and contextInfo.participant for replies)
 // @Tags Messages
 // @Security ApiKeyAuth
-// @Accept json
+// @Accept json,mpfd
 // @Produce json
 // @Security ApiKeyAuth
 // @Param sessionId path string true "Session ID or Name" example("mySession")
@@ -419,9 +501,14 @@ func (h *MessageHandler) SendDocument(c *fiber.Ctx) error {
 // @Success 200 {object} common.SuccessResponse{data=message.SendMessageResponse} "Message sent successfully"
 // @Failure 400 {object} object "Invalid request"
 // @Failure 404 {object} object "Session not found"
+// @Failure 429 {object} object "Daily message quota exceeded"
 // @Failure 500 {object} object "Internal server error"
 // @Router /sessions/{sessionId}/messages/send/sticker [post]
 func (h *MessageHandler) SendSticker(c *fiber.Ctx) error {
+	if isMultipartUpload(c) {
+		return h.sendUploadedMedia(c, "sticker")
+	}
+
 	return h.sendSpecificMessageType(c, "sticker")
 }
 
@@ -437,6 +524,7 @@ func (h *MessageHandler) SendSticker(c *fiber.Ctx) error {
 // @Success 200 {object} common.SuccessResponse{data=message.SendMessageResponse} "Message sent successfully"
 // @Failure 400 {object} object "Invalid request"
 // @Failure 404 {object} object "Session not found"
+// @Failure 429 {object} object "Daily message quota exceeded"
 // @Failure 500 {object} object "Internal server error"
 // @Router /sessions/{sessionId}/messages/send/location [post]
 func (h *MessageHandler) SendLocation(c *fiber.Ctx) error {
@@ -456,6 +544,7 @@ func (h *MessageHandler) SendLocation(c *fiber.Ctx) error {
 // @Success 200 {object} common.SuccessResponse{data=message.ContactListMessageResponse} "Contact list sent successfully"
 // @Failure 400 {object} object "Invalid request"
 // @Failure 404 {object} object "Session not found"
+// @Failure 429 {object} object "Daily message quota exceeded"
 // @Failure 500 {object} object "Internal server error"
 // @Router /sessions/{sessionId}/messages/send/contact [post]
 func (h *MessageHandler) SendContact(c *fiber.Ctx) error {
@@ -499,21 +588,25 @@ func (h *MessageHandler) handleSingleContact(c *fiber.Ctx, sessionIdentifier str
 		return c.Status(404).JSON(common.NewErrorResponse("Session not found"))
 	}
 
-	result, err := h.wameowManager.SendMessage(
-		sess.ID.String(),
-		contactReq.RemoteJID,
-		"contact",
-		"",
-		"",
-		"",
-		"",
-		0,
-		0,
-		contactReq.ContactName,
-		contactReq.ContactPhone,
-		nil,
-	)
-
+	var result *domainMessage.SendResult
+	err = h.messageUC.AccountSend(c.Context(), func() (sendErr error) {
+		result, sendErr = h.wameowManager.SendMessage(
+			sess.ID.String(),
+			contactReq.RemoteJID,
+			"contact",
+			"",
+			"",
+			"",
+			"",
+			"",
+			0,
+			0,
+			contactReq.ContactName,
+			contactReq.ContactPhone,
+			nil,
+		)
+		return sendErr
+	})
 	if err != nil {
 		h.logger.ErrorWithFields("Failed to send contact message", map[string]interface{}{
 			"session_id":   sess.ID.String(),
@@ -526,9 +619,15 @@ func (h *MessageHandler) handleSingleContact(c *fiber.Ctx, sessionIdentifier str
 			return c.Status(400).JSON(common.NewErrorResponse("Session is not connected"))
 		}
 
+		if handled, respErr := writeQuotaError(c, err); handled {
+			return respErr
+		}
+
 		return c.Status(500).JSON(common.NewErrorResponse("Failed to send contact message"))
 	}
 
+	h.wameowManager.TraceRequest(c.Context(), sess.ID.String(), result.MessageID)
+
 	response := &message.SendMessageResponse{
 		ID:        result.MessageID,
 		Status:    result.Status,
@@ -560,11 +659,19 @@ func (h *MessageHandler) handleContactList(c *fiber.Ctx, sessionIdentifier strin
 
 	// Convert and send contacts
 	contacts := h.convertToWameowContacts(contactListReq.Contacts)
-	result, err := h.sendContactsViaWameow(sess.ID.String(), contactListReq.RemoteJID, contacts, contactListReq.Contacts)
+	var result *wameow.ContactListResult
+	err = h.messageUC.AccountSend(c.Context(), func() (sendErr error) {
+		result, sendErr = h.sendContactsViaWameow(sess.ID.String(), contactListReq.RemoteJID, contacts, contactListReq.Contacts)
+		return sendErr
+	})
 	if err != nil {
 		return h.handleContactSendError(c, err)
 	}
 
+	for _, contactResult := range result.Results {
+		h.wameowManager.TraceRequest(c.Context(), sess.ID.String(), contactResult.MessageID)
+	}
+
 	// Build and return response
 	return h.buildContactListResponse(c, result, sess.ID.String(), contactListReq.RemoteJID, len(contactListReq.Contacts))
 }
@@ -650,6 +757,9 @@ func (h *MessageHandler) handleContactSendError(c *fiber.Ctx, err error) error {
 	if strings.Contains(err.Error(), "not connected") {
 		return c.Status(400).JSON(common.NewErrorResponse("Session is not connected"))
 	}
+	if handled, respErr := writeQuotaError(c, err); handled {
+		return respErr
+	}
 	return c.Status(500).JSON(common.NewErrorResponse("Failed to send contact list"))
 }
 
@@ -745,7 +855,11 @@ func (h *MessageHandler) SendBusinessProfile(c *fiber.Ctx) error {
 		Address:      businessReq.Address,
 	}
 
-	result, err := h.wameowManager.SendSingleContactBusinessFormat(sess.ID.String(), businessReq.RemoteJID, contact)
+	var result *wameow.ContactListResult
+	err = h.messageUC.AccountSend(c.Context(), func() (sendErr error) {
+		result, sendErr = h.wameowManager.SendSingleContactBusinessFormat(sess.ID.String(), businessReq.RemoteJID, contact)
+		return sendErr
+	})
 	if err != nil {
 		h.logger.ErrorWithFields("Failed to send business profile", map[string]interface{}{
 			"session_id":    sess.ID.String(),
@@ -758,9 +872,17 @@ func (h *MessageHandler) SendBusinessProfile(c *fiber.Ctx) error {
 			return c.Status(400).JSON(common.NewErrorResponse("Session is not connected"))
 		}
 
+		if handled, respErr := writeQuotaError(c, err); handled {
+			return respErr
+		}
+
 		return c.Status(500).JSON(common.NewErrorResponse("Failed to send business profile"))
 	}
 
+	for _, contactResult := range result.Results {
+		h.wameowManager.TraceRequest(c.Context(), sess.ID.String(), contactResult.MessageID)
+	}
+
 	h.logger.InfoWithFields("Business profile sent successfully", map[string]interface{}{
 		"session_id":    sess.ID.String(),
 		"to":            businessReq.RemoteJID,
@@ -778,7 +900,7 @@ func (h *MessageHandler) SendBusinessProfile(c *fiber.Ctx) error {
 }
 
 // @Summary Send text message
-// @Description Send a text message with optional context info for replies
+// @Description Send a text message with optional context info for replies. On groups where the account is admin, mentionAll mentions every participant. With scheduleAt the message is stored and sent at that time instead.
 // @Tags Messages
 // @Security ApiKeyAuth
 // @Accept json
@@ -787,8 +909,11 @@ func (h *MessageHandler) SendBusinessProfile(c *fiber.Ctx) error {
 // @Param sessionId path string true "Session ID"
 // @Param request body message.TextMessageRequest true "Text message request"
 // @Success 200 {object} message.MessageResponse "Text message sent successfully"
+// @Success 202 {object} common.SuccessResponse{data=message.SendMessageResponse} "Message scheduled, or session disconnected and message queued (OFFLINE_QUEUE_ENABLED)"
 // @Failure 400 {object} object "Bad Request"
+// @Failure 403 {object} object "mentionAll requires the account to be a group admin"
 // @Failure 404 {object} object "Session not found"
+// @Failure 429 {object} object "Daily message quota exceeded"
 // @Failure 500 {object} object "Internal Server Error"
 // @Router /sessions/{sessionId}/messages/send/text [post]
 func (h *MessageHandler) SendText(c *fiber.Ctx) error {
@@ -816,27 +941,89 @@ func (h *MessageHandler) SendText(c *fiber.Ctx) error {
 		}
 	}
 
+	if textReq.MentionAll && !strings.HasSuffix(textReq.RemoteJID, "@g.us") {
+		return c.Status(400).JSON(common.NewErrorResponseWithCode(domainMessage.ErrMentionAllNotGroup.Error(), "MENTION_ALL_NOT_GROUP"))
+	}
+
+	// Scheduled messages go through the generic send, which has no group mentions
+	if textReq.ScheduleAt != nil && textReq.MentionAll {
+		return c.Status(400).JSON(common.NewErrorResponse("'mentionAll' cannot be combined with 'scheduleAt'"))
+	}
+
 	sess, err := h.sessionResolver.ResolveSession(c.Context(), sessionIdentifier)
 	if err != nil {
 		return c.Status(404).JSON(common.NewErrorResponse("Session not found"))
 	}
 
-	result, err := h.wameowManager.SendTextMessage(sess.ID.String(), textReq.RemoteJID, textReq.Body, textReq.ContextInfo)
+	if textReq.ScheduleAt != nil {
+		// The original text is scheduled, it is translated when it is sent
+		scheduled, scheduleErr := h.messageUC.SendMessage(c.Context(), sess.ID.String(), &message.SendMessageRequest{
+			RemoteJID:   textReq.RemoteJID,
+			Type:        "text",
+			Body:        textReq.Body,
+			ContextInfo: textReq.ContextInfo,
+			ScheduleAt:  textReq.ScheduleAt,
+		})
+		if scheduleErr != nil {
+			if handled, respErr := writeQuotaError(c, scheduleErr); handled {
+				return respErr
+			}
+			if strings.HasPrefix(scheduleErr.Error(), "invalid request") {
+				return c.Status(400).JSON(common.NewErrorResponse(scheduleErr.Error()))
+			}
+			h.logger.ErrorWithFields("Failed to schedule text message", map[string]interface{}{
+				"session_id": sess.ID.String(),
+				"to":         textReq.RemoteJID,
+				"error":      scheduleErr.Error(),
+			})
+			return c.Status(500).JSON(common.NewErrorResponse("Failed to schedule text message"))
+		}
+		return writeSendResponse(c, scheduled, "Text message sent successfully")
+	}
+
+	body, translated := h.messageUC.TranslateText(c.Context(), sess.ID.String(), textReq.Body)
+
+	var result *wameow.TextMessageResult
+	err = h.messageUC.AccountSend(c.Context(), func() (sendErr error) {
+		result, sendErr = h.wameowManager.SendTextMessage(sess.ID.String(), textReq.RemoteJID, body, textReq.ContextInfo, textReq.MentionAll)
+		return sendErr
+	})
 	if err != nil {
 		h.logger.ErrorWithFields("Failed to send text message", map[string]interface{}{
-			"session_id": sess.ID.String(),
-			"to":         textReq.RemoteJID,
-			"has_reply":  textReq.ContextInfo != nil,
-			"error":      err.Error(),
+			"session_id":  sess.ID.String(),
+			"to":          textReq.RemoteJID,
+			"has_reply":   textReq.ContextInfo != nil,
+			"mention_all": textReq.MentionAll,
+			"error":       err.Error(),
 		})
 
-		if strings.Contains(err.Error(), "not connected") {
+		// Queued messages go through the generic send, which has no group mentions
+		if strings.Contains(err.Error(), "not connected") && !textReq.MentionAll {
+			// The original text is queued, it is translated when it is sent
+			queued, queueErr := h.messageUC.QueueMessage(c.Context(), sess.ID.String(), &message.SendMessageRequest{
+				RemoteJID:   textReq.RemoteJID,
+				Type:        "text",
+				Body:        textReq.Body,
+				ContextInfo: textReq.ContextInfo,
+			})
+			if queueErr == nil {
+				return writeSendResponse(c, queued, "Text message sent successfully")
+			}
+			if handled, respErr := writeQuotaError(c, queueErr); handled {
+				return respErr
+			}
 			return c.Status(400).JSON(common.NewErrorResponse("Session is not connected"))
 		}
 
+		if handled, respErr := writeQuotaError(c, err); handled {
+			return respErr
+		}
+
 		return c.Status(500).JSON(common.NewErrorResponse("Failed to send text message"))
 	}
 
+	h.wameowManager.TraceRequest(c.Context(), sess.ID.String(), result.MessageID)
+
 	h.logger.InfoWithFields("Text message sent successfully", map[string]interface{}{
 		"session_id": sess.ID.String(),
 		"to":         textReq.RemoteJID,
@@ -845,9 +1032,10 @@ func (h *MessageHandler) SendText(c *fiber.Ctx) error {
 	})
 
 	response := message.SendMessageResponse{
-		ID:        result.MessageID,
-		Status:    result.Status,
-		Timestamp: result.Timestamp,
+		ID:          result.MessageID,
+		Status:      result.Status,
+		Timestamp:   result.Timestamp,
+		Translation: translated,
 	}
 
 	return c.Status(200).JSON(common.NewSuccessResponse(response, "Text message sent successfully"))
@@ -865,6 +1053,7 @@ func (h *MessageHandler) SendText(c *fiber.Ctx) error {
 // @Success 200 {object} common.SuccessResponse{data=message.MessageResponse} "Button message sent successfully"
 // @Failure 400 {object} object "Invalid request"
 // @Failure 404 {object} object "Session not found"
+// @Failure 429 {object} object "Daily message quota exceeded"
 // @Failure 500 {object} object "Internal server error"
 // @Router /sessions/{sessionId}/messages/send/button [post]
 func (h *MessageHandler) SendButtonMessage(c *fiber.Ctx) error {
@@ -919,7 +1108,11 @@ func (h *MessageHandler) SendButtonMessage(c *fiber.Ctx) error {
 		})
 	}
 
-	result, err := h.wameowManager.SendButtonMessage(sess.ID.String(), buttonReq.RemoteJID, buttonReq.Title, buttons)
+	var result *domainMessage.SendResult
+	err = h.messageUC.AccountSend(c.Context(), func() (sendErr error) {
+		result, sendErr = h.wameowManager.SendButtonMessage(sess.ID.String(), buttonReq.RemoteJID, buttonReq.Title, buttons)
+		return sendErr
+	})
 	if err != nil {
 		h.logger.ErrorWithFields("Failed to send button message", map[string]interface{}{
 			"session_id": sess.ID.String(),
@@ -927,20 +1120,26 @@ func (h *MessageHandler) SendButtonMessage(c *fiber.Ctx) error {
 			"error":      err.Error(),
 		})
 
+		if handled, respErr := writeQuotaError(c, err); handled {
+			return respErr
+		}
+
 		return c.Status(500).JSON(common.NewErrorResponse(fmt.Sprintf("error sending message: %v", err)))
 	}
 
-	response := map[string]interface{}{
-		"Details":   "Sent",
-		"Timestamp": result.Timestamp.Unix(),
-		"Id":        result.MessageID,
+	h.wameowManager.TraceRequest(c.Context(), sess.ID.String(), result.MessageID)
+
+	response := &message.MessageResponse{
+		ID:        result.MessageID,
+		Status:    "sent",
+		Timestamp: result.Timestamp,
 	}
 
-	return c.JSON(response)
+	return c.JSON(common.NewSuccessResponse(response, "Button message sent successfully"))
 }
 
 // @Summary Send list message
-// @Description Send a message with interactive list through WhatsApp
+// @Description Send a native interactive list message through WhatsApp. Row IDs are echoed back in list.response webhook events when a row is selected. Set fallbackToText to send the list as a numbered text message instead
 // @Tags Messages
 // @Security ApiKeyAuth
 // @Accept json
@@ -951,6 +1150,7 @@ func (h *MessageHandler) SendButtonMessage(c *fiber.Ctx) error {
 // @Success 200 {object} common.SuccessResponse{data=message.MessageResponse} "List message sent successfully"
 // @Failure 400 {object} object "Invalid request"
 // @Failure 404 {object} object "Session not found"
+// @Failure 429 {object} object "Daily message quota exceeded"
 // @Failure 500 {object} object "Internal server error"
 // @Router /sessions/{sessionId}/messages/send/list [post]
 func (h *MessageHandler) SendListMessage(c *fiber.Ctx) error {
@@ -973,158 +1173,192 @@ func (h *MessageHandler) SendListMessage(c *fiber.Ctx) error {
 
 	// Convert to internal format and send
 	sections := h.convertListRequestToSections(listReq)
-	result, err := h.wameowManager.SendListMessage(sess.ID.String(), listReq.RemoteJID, listReq.Desc, listReq.ButtonText, sections)
+	var result *domainMessage.SendResult
+	err = h.messageUC.AccountSend(c.Context(), func() (sendErr error) {
+		result, sendErr = h.wameowManager.SendListMessage(sess.ID.String(), listReq.RemoteJID, listReq.TopText, listReq.Desc, listReq.FooterText, listReq.ButtonText, sections, listReq.FallbackToText)
+		return sendErr
+	})
 	if err != nil {
 		h.logger.ErrorWithFields("Failed to send list message", map[string]interface{}{
 			"session_id": sess.ID.String(),
 			"to":         listReq.RemoteJID,
 			"error":      err.Error(),
 		})
-		return c.Status(500).JSON(common.NewErrorResponse(fmt.Sprintf("error sending message: %v", err)))
-	}
+		if handled, respErr := writeQuotaError(c, err); handled {
+			return respErr
+		}
 
-	// Return response
-	response := map[string]interface{}{
-		"Details":   "Sent",
-		"Timestamp": result.Timestamp.Unix(),
-		"Id":        result.MessageID,
+		return c.Status(500).JSON(common.NewErrorResponse(fmt.Sprintf("error sending message: %v", err)))
 	}
 
-	return c.JSON(response)
-}
+	h.wameowManager.TraceRequest(c.Context(), sess.ID.String(), result.MessageID)
 
-// listItem represents a single item in a list
-type listItem struct {
-	Title string `json:"title"`
-	Desc  string `json:"desc"`
-	RowId string `json:"RowId"`
-}
+	response := &message.MessageResponse{
+		ID:        result.MessageID,
+		Status:    "sent",
+		Timestamp: result.Timestamp,
+	}
 
-// section represents a section containing multiple list items
-type section struct {
-	Title string     `json:"title"`
-	Rows  []listItem `json:"rows"`
+	return c.JSON(common.NewSuccessResponse(response, "List message sent successfully"))
 }
 
-// listRequest represents the complete list message request
-type listRequest struct {
-	RemoteJID  string     `json:"remoteJid"`
-	ButtonText string     `json:"ButtonText"`
-	Desc       string     `json:"Desc"`
-	TopText    string     `json:"TopText"`
-	Sections   []section  `json:"Sections"`
-	List       []listItem `json:"List"` // compatibility
-	FooterText string     `json:"FooterText"`
-	Id         string     `json:"Id,omitempty"`
-}
+// @Summary Send flow message
+// @Description Send an interactive message with native flow buttons (quick_reply, cta_url, cta_call, cta_copy, single_select, ...), each with its parameters. Replies arrive as flow.response webhook events. Only sessions of WhatsApp Business accounts can send them
+// @Tags Messages
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param request body message.FlowMessageRequest true "Flow message request"
+// @Success 200 {object} common.SuccessResponse{data=message.MessageResponse} "Flow message sent successfully"
+// @Failure 400 {object} object "Invalid request"
+// @Failure 404 {object} object "Session not found"
+// @Failure 422 {object} object "The session's account cannot send native flow messages"
+// @Failure 429 {object} object "Daily message quota exceeded"
+// @Failure 500 {object} object "Internal server error"
+// @Router /sessions/{sessionId}/messages/send/flow [post]
+func (h *MessageHandler) SendFlowMessage(c *fiber.Ctx) error {
+	sessionIdentifier := c.Params("sessionId")
+	if sessionIdentifier == "" {
+		return c.Status(400).JSON(common.NewErrorResponse("Session identifier is required"))
+	}
 
-// parseListMessageRequest parses and validates the list message request
-func (h *MessageHandler) parseListMessageRequest(c *fiber.Ctx) (*listRequest, error) {
-	var listReq listRequest
-	if err := c.BodyParser(&listReq); err != nil {
-		return nil, c.Status(400).JSON(common.NewErrorResponse("could not decode Payload"))
+	var flowReq message.FlowMessageRequest
+	if err := c.BodyParser(&flowReq); err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid request body"))
 	}
 
-	// Required fields validation - FooterText is optional
-	if listReq.RemoteJID == "" || listReq.ButtonText == "" || listReq.Desc == "" || listReq.TopText == "" {
-		return nil, c.Status(400).JSON(common.NewErrorResponse("missing required fields: Phone, ButtonText, Desc, TopText"))
+	if flowReq.RemoteJID == "" {
+		return c.Status(400).JSON(common.NewErrorResponse("'Phone' field is required"))
 	}
 
-	// Check if we have sections or list
-	if len(listReq.Sections) == 0 && len(listReq.List) == 0 {
-		return nil, c.Status(400).JSON(common.NewErrorResponse("no section or list provided"))
+	flow, err := toFlowMessage(&flowReq)
+	if err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse(err.Error()))
+	}
+	if err := flow.Validate(); err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse(err.Error()))
 	}
 
-	return &listReq, nil
-}
+	sess, err := h.sessionResolver.ResolveSession(c.Context(), sessionIdentifier)
+	if err != nil {
+		return c.Status(404).JSON(common.NewErrorResponse("Session not found"))
+	}
 
-// convertListRequestToSections converts the request format to internal sections format
-func (h *MessageHandler) convertListRequestToSections(listReq *listRequest) []map[string]interface{} {
-	var sections []map[string]interface{}
+	var result *domainMessage.SendResult
+	err = h.messageUC.AccountSend(c.Context(), func() (sendErr error) {
+		result, sendErr = h.wameowManager.SendFlowMessage(sess.ID.String(), flowReq.RemoteJID, flow)
+		return sendErr
+	})
+	if err != nil {
+		h.logger.ErrorWithFields("Failed to send flow message", map[string]interface{}{
+			"session_id": sess.ID.String(),
+			"to":         flowReq.RemoteJID,
+			"error":      err.Error(),
+		})
+		if errors.Is(err, wameow.ErrNativeFlowUnsupported) {
+			return c.Status(422).JSON(common.NewErrorResponse(err.Error()))
+		}
+		if handled, respErr := writeQuotaError(c, err); handled {
+			return respErr
+		}
 
-	if len(listReq.Sections) > 0 {
-		sections = h.convertSectionsFormat(listReq.Sections)
-	} else if len(listReq.List) > 0 {
-		sections = h.convertListFormat(listReq.List, listReq.TopText)
+		return c.Status(500).JSON(common.NewErrorResponse(fmt.Sprintf("error sending message: %v", err)))
 	}
 
-	return sections
-}
-
-// convertSectionsFormat converts sections to internal format
-func (h *MessageHandler) convertSectionsFormat(reqSections []section) []map[string]interface{} {
-	var sections []map[string]interface{}
+	h.wameowManager.TraceRequest(c.Context(), sess.ID.String(), result.MessageID)
 
-	for _, sec := range reqSections {
-		var rows []interface{}
-		for _, item := range sec.Rows {
-			rows = append(rows, map[string]interface{}{
-				"id":          item.RowId,
-				"title":       item.Title,
-				"description": item.Desc,
-			})
-		}
-		sections = append(sections, map[string]interface{}{
-			"title": sec.Title,
-			"rows":  rows,
-		})
+	response := &message.MessageResponse{
+		ID:        result.MessageID,
+		Status:    "sent",
+		Timestamp: result.Timestamp,
 	}
 
-	return sections
+	return c.JSON(common.NewSuccessResponse(response, "Flow message sent successfully"))
 }
 
-// convertListFormat converts list format to internal sections format
-func (h *MessageHandler) convertListFormat(list []listItem, topText string) []map[string]interface{} {
-	var rows []interface{}
-	for _, item := range list {
-		rows = append(rows, map[string]interface{}{
-			"id":          item.RowId,
-			"title":       item.Title,
-			"description": item.Desc,
-		})
+// toFlowMessage converts the request to a flow message, encoding the parameters as JSON
+func toFlowMessage(req *message.FlowMessageRequest) (*wameow.FlowMessage, error) {
+	buttons, err := toFlowButtons(req.Buttons)
+	if err != nil {
+		return nil, err
 	}
 
-	sectionTitle := topText
-	if sectionTitle == "" {
-		sectionTitle = "Menu"
+	flow := &wameow.FlowMessage{
+		Header:  req.Header,
+		Body:    req.Body,
+		Footer:  req.Footer,
+		Buttons: buttons,
 	}
 
-	return []map[string]interface{}{
-		{
-			"title": sectionTitle,
-			"rows":  rows,
-		},
+	if req.MessageParams != nil {
+		params, err := json.Marshal(req.MessageParams)
+		if err != nil {
+			return nil, fmt.Errorf("invalid messageParams: %v", err)
+		}
+		flow.MessageParamsJSON = string(params)
+	}
+
+	return flow, nil
+}
+
+// toFlowButtons converts request buttons to native flow buttons, encoding their parameters as JSON
+func toFlowButtons(requestButtons []message.FlowButton) ([]wameow.FlowButton, error) {
+	buttons := make([]wameow.FlowButton, 0, len(requestButtons))
+	for i, button := range requestButtons {
+		params := []byte("{}")
+		if button.Params != nil {
+			var err error
+			if params, err = json.Marshal(button.Params); err != nil {
+				return nil, fmt.Errorf("button %d: invalid params: %v", i+1, err)
+			}
+		}
+		buttons = append(buttons, wameow.FlowButton{Name: button.Name, ParamsJSON: string(params)})
 	}
+	return buttons, nil
 }
 
-// @Summary Send reaction
-// @Description Send a reaction (emoji) to a specific message
+// @Summary Send carousel message
+// @Description Send cards the recipient swipes through, each with an image or video, a title, a body and up to 2 native flow buttons (quick_reply, cta_url, cta_call, ...). WhatsApp only renders carousels sent from business accounts: other sessions, or requests with fallbackToText, send the cards as a text message and report sentAsText
 // @Tags Messages
 // @Security ApiKeyAuth
 // @Accept json
 // @Produce json
-// @Security ApiKeyAuth
 // @Param sessionId path string true "Session ID or Name" example("mySession")
-// @Param request body message.ReactionMessageRequest true "Reaction request"
-// @Success 200 {object} common.SuccessResponse{data=message.ReactionResponse} "Reaction sent successfully"
+// @Param request body message.CarouselMessageRequest true "Carousel message request"
+// @Success 200 {object} common.SuccessResponse{data=message.CarouselMessageResponse} "Carousel message sent successfully"
 // @Failure 400 {object} object "Invalid request"
 // @Failure 404 {object} object "Session not found"
+// @Failure 413 {object} object "Card media too large"
+// @Failure 429 {object} object "Daily message quota exceeded"
 // @Failure 500 {object} object "Internal server error"
-// @Router /sessions/{sessionId}/messages/send/reaction [post]
-func (h *MessageHandler) SendReaction(c *fiber.Ctx) error {
+// @Router /sessions/{sessionId}/messages/send/carousel [post]
+func (h *MessageHandler) SendCarouselMessage(c *fiber.Ctx) error {
 	sessionIdentifier := c.Params("sessionId")
 	if sessionIdentifier == "" {
 		return c.Status(400).JSON(common.NewErrorResponse("Session identifier is required"))
 	}
 
-	var reactionReq message.ReactionMessageRequest
-
-	if err := c.BodyParser(&reactionReq); err != nil {
+	var carouselReq message.CarouselMessageRequest
+	if err := c.BodyParser(&carouselReq); err != nil {
 		return c.Status(400).JSON(common.NewErrorResponse("Invalid request body"))
 	}
 
-	if reactionReq.RemoteJID == "" || reactionReq.MessageID == "" || reactionReq.Reaction == "" {
-		return c.Status(400).JSON(common.NewErrorResponse("'Phone', 'messageId', and 'reaction' are required"))
+	if carouselReq.RemoteJID == "" {
+		return c.Status(400).JSON(common.NewErrorResponse("'Phone' field is required"))
+	}
+
+	carousel, err := toCarouselMessage(&carouselReq)
+	if err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse(err.Error()))
+	}
+	if err := carousel.Validate(); err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse(err.Error()))
+	}
+	for i, card := range carouselReq.Cards {
+		if card.Media.File == "" {
+			return c.Status(400).JSON(common.NewErrorResponse(fmt.Sprintf("card %d: 'media.file' field is required", i+1)))
+		}
 	}
 
 	sess, err := h.sessionResolver.ResolveSession(c.Context(), sessionIdentifier)
@@ -1132,42 +1366,382 @@ func (h *MessageHandler) SendReaction(c *fiber.Ctx) error {
 		return c.Status(404).JSON(common.NewErrorResponse("Session not found"))
 	}
 
-	err = h.wameowManager.SendReaction(sess.ID.String(), reactionReq.RemoteJID, reactionReq.MessageID, reactionReq.Reaction)
+	for i, card := range carouselReq.Cards {
+		media, err := h.messageUC.ProcessMedia(c.Context(), card.Media.File, domainMessage.MessageType(card.Media.Type))
+		if err != nil {
+			if status, _, ok := quotaErrorStatus(err); ok {
+				return c.Status(status).JSON(common.NewErrorResponse(fmt.Sprintf("card %d: %v", i+1, err)))
+			}
+			return c.Status(500).JSON(common.NewErrorResponse(fmt.Sprintf("card %d: failed to process media: %v", i+1, err)))
+		}
+		defer h.removeUpload(media)
+
+		cardMedia := carousel.Cards[i].Media
+		cardMedia.FilePath = media.FilePath
+		if cardMedia.MimeType == "" {
+			cardMedia.MimeType = media.MimeType
+		}
+	}
+
+	var result *domainMessage.SendResult
+	var sentAsText bool
+	err = h.messageUC.AccountSend(c.Context(), func() (sendErr error) {
+		result, sentAsText, sendErr = h.wameowManager.SendCarouselMessage(sess.ID.String(), carouselReq.RemoteJID, carousel, carouselReq.FallbackToText)
+		return sendErr
+	})
 	if err != nil {
-		h.logger.ErrorWithFields("Failed to send reaction", map[string]interface{}{
+		h.logger.ErrorWithFields("Failed to send carousel message", map[string]interface{}{
 			"session_id": sess.ID.String(),
-			"to":         reactionReq.RemoteJID,
-			"message_id": reactionReq.MessageID,
+			"to":         carouselReq.RemoteJID,
 			"error":      err.Error(),
 		})
-
-		if strings.Contains(err.Error(), "not connected") {
-			return c.Status(400).JSON(common.NewErrorResponse("Session is not connected"))
+		if handled, respErr := writeQuotaError(c, err); handled {
+			return respErr
 		}
 
-		return c.Status(500).JSON(common.NewErrorResponse("Failed to send reaction"))
+		return c.Status(500).JSON(common.NewErrorResponse(fmt.Sprintf("error sending message: %v", err)))
 	}
 
-	response := map[string]interface{}{
-		"id":        reactionReq.MessageID,
-		"reaction":  reactionReq.Reaction,
-		"status":    "sent",
-		"timestamp": time.Now(),
+	h.wameowManager.TraceRequest(c.Context(), sess.ID.String(), result.MessageID)
+
+	response := &message.CarouselMessageResponse{
+		ID:         result.MessageID,
+		Status:     "sent",
+		SentAsText: sentAsText,
+		Timestamp:  result.Timestamp,
 	}
 
-	return c.JSON(common.NewSuccessResponse(response, "Reaction sent successfully"))
+	return c.JSON(common.NewSuccessResponse(response, "Carousel message sent successfully"))
 }
 
-// @Summary Send presence
-// @Description Send presence information (typing, online, etc.)
-// @Tags Messages
-// @Security ApiKeyAuth
-// @Accept json
-// @Produce json
-// @Security ApiKeyAuth
-// @Param sessionId path string true "Session ID or Name" example("mySession")
-// @Param request body message.PresenceMessageRequest true "Presence request"
-// @Success 200 {object} common.SuccessResponse{data=message.PresenceResponse} "Presence sent successfully"
+// toCarouselMessage converts the request to a carousel; the card media files are set once processed
+func toCarouselMessage(req *message.CarouselMessageRequest) (*wameow.CarouselMessage, error) {
+	carousel := &wameow.CarouselMessage{
+		Body:   req.Body,
+		Footer: req.Footer,
+	}
+
+	for i, card := range req.Cards {
+		buttons, err := toFlowButtons(card.Buttons)
+		if err != nil {
+			return nil, fmt.Errorf("card %d: %v", i+1, err)
+		}
+		carousel.Cards = append(carousel.Cards, wameow.CarouselCard{
+			Title:  card.Title,
+			Body:   card.Body,
+			Footer: card.Footer,
+			Media: &wameow.TemplateMedia{
+				Type:     card.Media.Type,
+				MimeType: card.Media.MimeType,
+			},
+			Buttons: buttons,
+		})
+	}
+
+	return carousel, nil
+}
+
+// @Summary Send template message
+// @Description Send a hydrated template with a header title or media (image, video or document), a body, a footer and up to 3 buttons: quick_reply, url or call. Quick reply presses arrive as messages of type template_reply
+// @Tags Messages
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param request body message.TemplateMessageRequest true "Template message request"
+// @Success 200 {object} common.SuccessResponse{data=message.MessageResponse} "Template message sent successfully"
+// @Failure 400 {object} object "Invalid request"
+// @Failure 404 {object} object "Session not found"
+// @Failure 413 {object} object "Header media too large"
+// @Failure 429 {object} object "Daily message quota exceeded"
+// @Failure 500 {object} object "Internal server error"
+// @Router /sessions/{sessionId}/messages/send/template-hydrated [post]
+func (h *MessageHandler) SendTemplateMessage(c *fiber.Ctx) error {
+	sessionIdentifier := c.Params("sessionId")
+	if sessionIdentifier == "" {
+		return c.Status(400).JSON(common.NewErrorResponse("Session identifier is required"))
+	}
+
+	var templateReq message.TemplateMessageRequest
+	if err := c.BodyParser(&templateReq); err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid request body"))
+	}
+
+	if templateReq.RemoteJID == "" {
+		return c.Status(400).JSON(common.NewErrorResponse("'Phone' field is required"))
+	}
+
+	template := toTemplateMessage(&templateReq)
+	if err := template.Validate(); err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse(err.Error()))
+	}
+	if template.HeaderMedia != nil && templateReq.HeaderMedia.File == "" {
+		return c.Status(400).JSON(common.NewErrorResponse("'headerMedia.file' field is required"))
+	}
+
+	sess, err := h.sessionResolver.ResolveSession(c.Context(), sessionIdentifier)
+	if err != nil {
+		return c.Status(404).JSON(common.NewErrorResponse("Session not found"))
+	}
+
+	if template.HeaderMedia != nil {
+		media, err := h.messageUC.ProcessMedia(c.Context(), templateReq.HeaderMedia.File, domainMessage.MessageType(template.HeaderMedia.Type))
+		if err != nil {
+			if status, _, ok := quotaErrorStatus(err); ok {
+				return c.Status(status).JSON(common.NewErrorResponse(err.Error()))
+			}
+			return c.Status(500).JSON(common.NewErrorResponse(fmt.Sprintf("failed to process header media: %v", err)))
+		}
+		defer h.removeUpload(media)
+
+		template.HeaderMedia.FilePath = media.FilePath
+		if template.HeaderMedia.MimeType == "" {
+			template.HeaderMedia.MimeType = media.MimeType
+		}
+	}
+
+	var result *domainMessage.SendResult
+	err = h.messageUC.AccountSend(c.Context(), func() (sendErr error) {
+		result, sendErr = h.wameowManager.SendTemplateMessage(sess.ID.String(), templateReq.RemoteJID, template)
+		return sendErr
+	})
+	if err != nil {
+		h.logger.ErrorWithFields("Failed to send template message", map[string]interface{}{
+			"session_id": sess.ID.String(),
+			"to":         templateReq.RemoteJID,
+			"error":      err.Error(),
+		})
+		if handled, respErr := writeQuotaError(c, err); handled {
+			return respErr
+		}
+
+		return c.Status(500).JSON(common.NewErrorResponse(fmt.Sprintf("error sending message: %v", err)))
+	}
+
+	h.wameowManager.TraceRequest(c.Context(), sess.ID.String(), result.MessageID)
+
+	response := &message.MessageResponse{
+		ID:        result.MessageID,
+		Status:    "sent",
+		Timestamp: result.Timestamp,
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "Template message sent successfully"))
+}
+
+// toTemplateMessage converts the request to a hydrated template; the header media file is set once processed
+func toTemplateMessage(req *message.TemplateMessageRequest) *wameow.TemplateMessage {
+	template := &wameow.TemplateMessage{
+		Title:  req.Title,
+		Body:   req.Body,
+		Footer: req.Footer,
+	}
+
+	if req.HeaderMedia != nil {
+		template.HeaderMedia = &wameow.TemplateMedia{
+			Type:     req.HeaderMedia.Type,
+			MimeType: req.HeaderMedia.MimeType,
+			Filename: req.HeaderMedia.Filename,
+		}
+	}
+
+	for _, button := range req.Buttons {
+		template.Buttons = append(template.Buttons, wameow.TemplateButton{
+			Type:        button.Type,
+			Text:        button.Text,
+			ID:          button.ID,
+			URL:         button.URL,
+			PhoneNumber: button.PhoneNumber,
+		})
+	}
+
+	return template
+}
+
+// listItem represents a single item in a list
+type listItem struct {
+	Title string `json:"title"`
+	Desc  string `json:"desc"`
+	RowId string `json:"RowId"`
+}
+
+// section represents a section containing multiple list items
+type section struct {
+	Title string     `json:"title"`
+	Rows  []listItem `json:"rows"`
+}
+
+// listRequest represents the complete list message request
+type listRequest struct {
+	RemoteJID      string     `json:"remoteJid"`
+	ButtonText     string     `json:"ButtonText"`
+	Desc           string     `json:"Desc"`
+	TopText        string     `json:"TopText"`
+	Sections       []section  `json:"Sections"`
+	List           []listItem `json:"List"` // compatibility
+	FooterText     string     `json:"FooterText"`
+	Id             string     `json:"Id,omitempty"`
+	FallbackToText bool       `json:"fallbackToText"`
+}
+
+// parseListMessageRequest parses and validates the list message request
+func (h *MessageHandler) parseListMessageRequest(c *fiber.Ctx) (*listRequest, error) {
+	var listReq listRequest
+	if err := c.BodyParser(&listReq); err != nil {
+		return nil, c.Status(400).JSON(common.NewErrorResponse("could not decode Payload"))
+	}
+
+	// Required fields validation - FooterText is optional
+	if listReq.RemoteJID == "" || listReq.ButtonText == "" || listReq.Desc == "" || listReq.TopText == "" {
+		return nil, c.Status(400).JSON(common.NewErrorResponse("missing required fields: Phone, ButtonText, Desc, TopText"))
+	}
+
+	// Check if we have sections or list
+	if len(listReq.Sections) == 0 && len(listReq.List) == 0 {
+		return nil, c.Status(400).JSON(common.NewErrorResponse("no section or list provided"))
+	}
+
+	return &listReq, nil
+}
+
+// convertListRequestToSections converts the request format to internal sections format
+func (h *MessageHandler) convertListRequestToSections(listReq *listRequest) []map[string]interface{} {
+	var sections []map[string]interface{}
+
+	if len(listReq.Sections) > 0 {
+		sections = h.convertSectionsFormat(listReq.Sections)
+	} else if len(listReq.List) > 0 {
+		sections = h.convertListFormat(listReq.List, listReq.TopText)
+	}
+
+	return sections
+}
+
+// convertSectionsFormat converts sections to internal format
+func (h *MessageHandler) convertSectionsFormat(reqSections []section) []map[string]interface{} {
+	var sections []map[string]interface{}
+
+	for _, sec := range reqSections {
+		var rows []interface{}
+		for _, item := range sec.Rows {
+			rows = append(rows, map[string]interface{}{
+				"id":          item.RowId,
+				"title":       item.Title,
+				"description": item.Desc,
+			})
+		}
+		sections = append(sections, map[string]interface{}{
+			"title": sec.Title,
+			"rows":  rows,
+		})
+	}
+
+	return sections
+}
+
+// convertListFormat converts list format to internal sections format
+func (h *MessageHandler) convertListFormat(list []listItem, topText string) []map[string]interface{} {
+	var rows []interface{}
+	for _, item := range list {
+		rows = append(rows, map[string]interface{}{
+			"id":          item.RowId,
+			"title":       item.Title,
+			"description": item.Desc,
+		})
+	}
+
+	sectionTitle := topText
+	if sectionTitle == "" {
+		sectionTitle = "Menu"
+	}
+
+	return []map[string]interface{}{
+		{
+			"title": sectionTitle,
+			"rows":  rows,
+		},
+	}
+}
+
+// @Summary Send reaction
+// @Description Send a reaction (emoji) to a specific message. For messages from other group members set participant to the original sender; set fromMe for own messages
+// @Tags Messages
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param request body message.ReactionMessageRequest true "Reaction request"
+// @Success 200 {object} common.SuccessResponse{data=message.ReactionResponse} "Reaction sent successfully"
+// @Failure 400 {object} object "Invalid request"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal server error"
+// @Router /sessions/{sessionId}/messages/send/reaction [post]
+func (h *MessageHandler) SendReaction(c *fiber.Ctx) error {
+	sessionIdentifier := c.Params("sessionId")
+	if sessionIdentifier == "" {
+		return c.Status(400).JSON(common.NewErrorResponse("Session identifier is required"))
+	}
+
+	var reactionReq message.ReactionMessageRequest
+
+	if err := c.BodyParser(&reactionReq); err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid request body"))
+	}
+
+	if reactionReq.RemoteJID == "" || reactionReq.MessageID == "" || reactionReq.Reaction == "" {
+		return c.Status(400).JSON(common.NewErrorResponse("'Phone', 'messageId', and 'reaction' are required"))
+	}
+
+	sess, err := h.sessionResolver.ResolveSession(c.Context(), sessionIdentifier)
+	if err != nil {
+		return c.Status(404).JSON(common.NewErrorResponse("Session not found"))
+	}
+
+	err = h.wameowManager.SendReaction(sess.ID.String(), reactionReq.RemoteJID, reactionReq.MessageID, reactionReq.Reaction, reactionReq.Participant, reactionReq.FromMe)
+	if err != nil {
+		h.logger.ErrorWithFields("Failed to send reaction", map[string]interface{}{
+			"session_id": sess.ID.String(),
+			"to":         reactionReq.RemoteJID,
+			"message_id": reactionReq.MessageID,
+			"error":      err.Error(),
+		})
+
+		if strings.Contains(err.Error(), "not connected") {
+			return c.Status(400).JSON(common.NewErrorResponse("Session is not connected"))
+		}
+
+		if strings.Contains(err.Error(), "participant") {
+			return c.Status(400).JSON(common.NewErrorResponse(err.Error()))
+		}
+
+		if handled, respErr := writeQuotaError(c, err); handled {
+			return respErr
+		}
+
+		return c.Status(500).JSON(common.NewErrorResponse("Failed to send reaction"))
+	}
+
+	response := map[string]interface{}{
+		"id":        reactionReq.MessageID,
+		"reaction":  reactionReq.Reaction,
+		"status":    "sent",
+		"timestamp": time.Now(),
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "Reaction sent successfully"))
+}
+
+// @Summary Send presence
+// @Description Send presence information (typing, online, etc.)
+// @Tags Messages
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param request body message.PresenceMessageRequest true "Presence request"
+// @Success 200 {object} common.SuccessResponse{data=message.PresenceResponse} "Presence sent successfully"
 // @Failure 400 {object} object "Invalid request"
 // @Failure 404 {object} object "Session not found"
 // @Failure 500 {object} object "Internal server error"
@@ -1219,6 +1793,10 @@ func (h *MessageHandler) SendPresence(c *fiber.Ctx) error {
 			return c.Status(400).JSON(common.NewErrorResponse("Session is not connected"))
 		}
 
+		if handled, respErr := writeQuotaError(c, err); handled {
+			return respErr
+		}
+
 		return c.Status(500).JSON(common.NewErrorResponse("Failed to send presence"))
 	}
 
@@ -1283,6 +1861,10 @@ func (h *MessageHandler) EditMessage(c *fiber.Ctx) error {
 			return c.Status(400).JSON(common.NewErrorResponse("Session is not connected"))
 		}
 
+		if handled, respErr := writeQuotaError(c, err); handled {
+			return respErr
+		}
+
 		return c.Status(500).JSON(common.NewErrorResponse("Failed to edit message"))
 	}
 
@@ -1290,7 +1872,7 @@ func (h *MessageHandler) EditMessage(c *fiber.Ctx) error {
 }
 
 // @Summary Mark message as read
-// @Description Mark a specific message as read
+// @Description Mark one or more messages in a chat as read. For group messages pass sender, or it is looked up in the message history
 // @Tags Messages
 // @Security ApiKeyAuth
 // @Accept json
@@ -1298,7 +1880,7 @@ func (h *MessageHandler) EditMessage(c *fiber.Ctx) error {
 // @Security ApiKeyAuth
 // @Param sessionId path string true "Session ID or Name" example("mySession")
 // @Param request body message.MarkReadRequest true "Mark as read request"
-// @Success 200 {object} common.SuccessResponse{data=message.MarkReadResponse} "Message marked as read successfully"
+// @Success 200 {object} common.SuccessResponse{data=message.MarkAsReadResponse} "Message marked as read successfully"
 // @Failure 400 {object} object "Invalid request"
 // @Failure 404 {object} object "Session not found"
 // @Failure 500 {object} object "Internal server error"
@@ -1309,17 +1891,15 @@ func (h *MessageHandler) MarkAsRead(c *fiber.Ctx) error {
 		return c.Status(400).JSON(common.NewErrorResponse("Session identifier is required"))
 	}
 
-	var markReadReq struct {
-		RemoteJID string `json:"remoteJid" validate:"required"`
-		MessageID string `json:"messageId" validate:"required"`
-	}
+	var markReadReq message.MarkReadRequest
 
 	if err := c.BodyParser(&markReadReq); err != nil {
 		return c.Status(400).JSON(common.NewErrorResponse("Invalid request body"))
 	}
 
-	if markReadReq.RemoteJID == "" || markReadReq.MessageID == "" {
-		return c.Status(400).JSON(common.NewErrorResponse("'Phone' and 'messageId' are required"))
+	messageIDs := markReadReq.GetMessageIDs()
+	if markReadReq.RemoteJID == "" || len(messageIDs) == 0 {
+		return c.Status(400).JSON(common.NewErrorResponse("'Phone' and 'messageId' or 'messageIds' are required"))
 	}
 
 	sess, err := h.sessionResolver.ResolveSession(c.Context(), sessionIdentifier)
@@ -1327,29 +1907,167 @@ func (h *MessageHandler) MarkAsRead(c *fiber.Ctx) error {
 		return c.Status(404).JSON(common.NewErrorResponse("Session not found"))
 	}
 
-	err = h.wameowManager.MarkRead(sess.ID.String(), markReadReq.RemoteJID, markReadReq.MessageID)
+	response, err := h.messageUC.MarkAsRead(c.Context(), &message.MarkAsReadRequest{
+		SessionID:  sess.ID.String(),
+		RemoteJID:  markReadReq.RemoteJID,
+		MessageIDs: messageIDs,
+		Sender:     markReadReq.Sender,
+	})
 	if err != nil {
 		h.logger.ErrorWithFields("Failed to mark message as read", map[string]interface{}{
-			"session_id": sess.ID.String(),
-			"to":         markReadReq.RemoteJID,
-			"message_id": markReadReq.MessageID,
-			"error":      err.Error(),
+			"session_id":  sess.ID.String(),
+			"to":          markReadReq.RemoteJID,
+			"message_ids": messageIDs,
+			"error":       err.Error(),
 		})
 
 		if strings.Contains(err.Error(), "not connected") {
 			return c.Status(400).JSON(common.NewErrorResponse("Session is not connected"))
 		}
+		if strings.Contains(err.Error(), "sender") {
+			return c.Status(400).JSON(common.NewErrorResponse(err.Error()))
+		}
+
+		if handled, respErr := writeQuotaError(c, err); handled {
+			return respErr
+		}
 
 		return c.Status(500).JSON(common.NewErrorResponse("Failed to mark message as read"))
 	}
 
-	response := map[string]interface{}{
-		"messageId": markReadReq.MessageID,
-		"status":    "read",
-		"timestamp": time.Now(),
+	return c.JSON(common.NewSuccessResponse(response, "Message marked as read successfully"))
+}
+
+// @Summary Mark chat as read
+// @Description Mark every received message in a chat up to a message ID or unix timestamp as read, batching receipts per sender. Messages are taken from the message history; without a bound all of them are marked
+// @Tags Messages
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param jid path string true "Chat JID" example("5511999999999@s.whatsapp.net")
+// @Param request body message.MarkChatAsReadRequest false "Mark chat as read request"
+// @Success 200 {object} common.SuccessResponse{data=message.MarkChatAsReadResponse} "Chat marked as read successfully"
+// @Failure 400 {object} object "Invalid request"
+// @Failure 404 {object} object "Session or message not found"
+// @Failure 500 {object} object "Internal server error"
+// @Router /sessions/{sessionId}/chats/{jid}/mark-read [post]
+func (h *MessageHandler) MarkChatAsRead(c *fiber.Ctx) error {
+	sessionIdentifier := c.Params("sessionId")
+	if sessionIdentifier == "" {
+		return c.Status(400).JSON(common.NewErrorResponse("Session identifier is required"))
 	}
 
-	return c.JSON(common.NewSuccessResponse(response, "Message marked as read successfully"))
+	chatJID, err := url.PathUnescape(c.Params("jid"))
+	if err != nil || chatJID == "" {
+		return c.Status(400).JSON(common.NewErrorResponse("Chat JID is required"))
+	}
+
+	var req message.MarkChatAsReadRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(common.NewErrorResponse("Invalid request body"))
+		}
+	}
+
+	sess, err := h.sessionResolver.ResolveSession(c.Context(), sessionIdentifier)
+	if err != nil {
+		return c.Status(404).JSON(common.NewErrorResponse("Session not found"))
+	}
+
+	req.SessionID = sess.ID.String()
+	req.ChatJID = chatJID
+
+	response, err := h.messageUC.MarkChatAsRead(c.Context(), &req)
+	if err != nil {
+		h.logger.ErrorWithFields("Failed to mark chat as read", map[string]interface{}{
+			"session_id": sess.ID.String(),
+			"chat_jid":   chatJID,
+			"error":      err.Error(),
+		})
+
+		if strings.Contains(err.Error(), "not connected") || strings.Contains(err.Error(), "not logged in") {
+			return c.Status(400).JSON(common.NewErrorResponse("Session is not connected"))
+		}
+		if strings.Contains(err.Error(), "not found") {
+			return c.Status(404).JSON(common.NewErrorResponse(err.Error()))
+		}
+
+		if handled, respErr := writeQuotaError(c, err); handled {
+			return respErr
+		}
+
+		return c.Status(500).JSON(common.NewErrorResponse("Failed to mark chat as read"))
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "Chat marked as read successfully"))
+}
+
+// @Summary Backfill chat history
+// @Description Request messages older than the oldest message of a chat in the message history from the phone and store them there, so they are listed by GET /sessions/{sessionId}/chats/{jid}/messages. Without stored messages the anchor must be given with beforeMessageId and beforeTimestamp
+// @Tags Messages
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param jid path string true "Chat JID" example("5511999999999@s.whatsapp.net")
+// @Param request body message.BackfillChatRequest false "Backfill request"
+// @Success 200 {object} common.SuccessResponse{data=message.BackfillChatResponse} "Chat history backfilled successfully"
+// @Failure 400 {object} object "Invalid request"
+// @Failure 404 {object} object "Session not found"
+// @Failure 409 {object} object "A backfill is already running for the session"
+// @Failure 504 {object} object "The phone did not answer in time"
+// @Failure 500 {object} object "Internal server error"
+// @Router /sessions/{sessionId}/chats/{jid}/backfill [post]
+func (h *MessageHandler) BackfillChat(c *fiber.Ctx) error {
+	sessionIdentifier := c.Params("sessionId")
+	if sessionIdentifier == "" {
+		return c.Status(400).JSON(common.NewErrorResponse("Session identifier is required"))
+	}
+
+	chatJID, err := url.PathUnescape(c.Params("jid"))
+	if err != nil || chatJID == "" {
+		return c.Status(400).JSON(common.NewErrorResponse("Chat JID is required"))
+	}
+
+	var req message.BackfillChatRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(common.NewErrorResponse("Invalid request body"))
+		}
+	}
+
+	sess, err := h.sessionResolver.ResolveSession(c.Context(), sessionIdentifier)
+	if err != nil {
+		return c.Status(404).JSON(common.NewErrorResponse("Session not found"))
+	}
+
+	req.SessionID = sess.ID.String()
+	req.ChatJID = chatJID
+
+	response, err := h.messageUC.BackfillChat(c.Context(), &req)
+	if err != nil {
+		h.logger.ErrorWithFields("Failed to backfill chat", map[string]interface{}{
+			"session_id": sess.ID.String(),
+			"chat_jid":   chatJID,
+			"error":      err.Error(),
+		})
+
+		switch {
+		case strings.Contains(err.Error(), "not connected") || strings.Contains(err.Error(), "not logged in"):
+			return c.Status(400).JSON(common.NewErrorResponse("Session is not connected"))
+		case strings.Contains(err.Error(), "required") || strings.Contains(err.Error(), "invalid chat JID"):
+			return c.Status(400).JSON(common.NewErrorResponse(err.Error()))
+		case strings.Contains(err.Error(), "already running"):
+			return c.Status(409).JSON(common.NewErrorResponse(err.Error()))
+		case strings.Contains(err.Error(), "timed out"):
+			return c.Status(504).JSON(common.NewErrorResponse("Timed out waiting for history from the phone"))
+		}
+
+		return c.Status(500).JSON(common.NewErrorResponse("Failed to backfill chat"))
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "Chat history backfilled successfully"))
 }
 
 func (h *MessageHandler) sendSpecificMessageType(c *fiber.Ctx, messageType string) error {
@@ -1414,6 +2132,10 @@ func (h *MessageHandler) sendSpecificMessageType(c *fiber.Ctx, messageType strin
 			"error":      err.Error(),
 		})
 
+		if handled, respErr := writeQuotaError(c, err); handled {
+			return respErr
+		}
+
 		if strings.Contains(err.Error(), "not connected") {
 			return c.Status(400).JSON(common.NewErrorResponse("Session is not connected"))
 		}
@@ -1427,6 +2149,10 @@ func (h *MessageHandler) sendSpecificMessageType(c *fiber.Ctx, messageType strin
 			return c.Status(400).JSON(common.NewErrorResponse("Failed to process media: " + err.Error()))
 		}
 
+		if handled, respErr := writeQuotaError(c, err); handled {
+			return respErr
+		}
+
 		return c.Status(500).JSON(common.NewErrorResponse("Failed to send " + messageType + " message"))
 	}
 
@@ -1437,7 +2163,7 @@ func (h *MessageHandler) sendSpecificMessageType(c *fiber.Ctx, messageType strin
 		"message_id": response.ID,
 	})
 
-	return c.JSON(common.NewSuccessResponse(response, capitalizeFirst(messageType)+" message sent successfully"))
+	return writeSendResponse(c, response, capitalizeFirst(messageType)+" message sent successfully")
 }
 
 // detectMediaType detects the media type from MIME type or file extension
@@ -1494,6 +2220,7 @@ func (h *MessageHandler) detectMediaType(mimeType, fileURL string) string {
 // @Success 200 {object} common.SuccessResponse{data=message.CreatePollResponse} "Poll sent successfully"
 // @Failure 400 {object} object "Invalid request"
 // @Failure 404 {object} object "Session not found"
+// @Failure 429 {object} object "Daily message quota exceeded"
 // @Failure 500 {object} object "Internal server error"
 // @Router /sessions/{sessionId}/messages/send/poll [post]
 func (h *MessageHandler) SendPoll(c *fiber.Ctx) error {
@@ -1530,10 +2257,12 @@ func (h *MessageHandler) parsePollRequest(c *fiber.Ctx) (*message.CreatePollRequ
 		return nil, err
 	}
 
-	// Set default selectable count if not provided
-	if pollReq.SelectableOptionCount < 1 {
-		pollReq.SelectableOptionCount = 1
+	// Resolve the selectable count, where 0 lets voters pick any number of options
+	selectableCount, err := pollReq.ResolveSelectableCount()
+	if err != nil {
+		return nil, c.Status(400).JSON(common.NewErrorResponse(err.Error()))
 	}
+	pollReq.SelectableOptionCount = selectableCount
 
 	return &pollReq, nil
 }
@@ -1556,8 +2285,17 @@ func (h *MessageHandler) validatePollRequest(c *fiber.Ctx, pollReq *message.Crea
 		return c.Status(400).JSON(common.NewErrorResponse("maximum 12 options allowed"))
 	}
 
-	if pollReq.SelectableOptionCount > len(pollReq.Options) {
-		return c.Status(400).JSON(common.NewErrorResponse("selectable count cannot exceed number of options"))
+	// Votes refer to options by the hash of their text, so options must be distinct
+	seen := make(map[string]bool, len(pollReq.Options))
+	for _, option := range pollReq.Options {
+		if seen[option] {
+			return c.Status(400).JSON(common.NewErrorResponse("duplicate option names not allowed"))
+		}
+		seen[option] = true
+	}
+
+	if pollReq.ExpiresAt != nil && !pollReq.ExpiresAt.After(time.Now()) {
+		return c.Status(400).JSON(common.NewErrorResponse("expiresAt must be in the future"))
 	}
 
 	return nil
@@ -1574,11 +2312,17 @@ func (h *MessageHandler) sendPollAndRespond(c *fiber.Ctx, sessionID string, poll
 	})
 
 	// Send poll using wameow manager
-	result, err := h.wameowManager.SendPoll(sessionID, pollReq.RemoteJID, pollReq.Name, pollReq.Options, pollReq.SelectableOptionCount)
+	var result *wameow.MessageResult
+	err := h.messageUC.AccountSend(c.Context(), func() (sendErr error) {
+		result, sendErr = h.wameowManager.SendPoll(sessionID, pollReq.RemoteJID, pollReq.Name, pollReq.Options, pollReq.SelectableOptionCount, pollReq.ExpiresAt)
+		return sendErr
+	})
 	if err != nil {
 		return h.handlePollSendError(c, sessionID, pollReq, err)
 	}
 
+	h.wameowManager.TraceRequest(c.Context(), sessionID, result.MessageID)
+
 	// Log success and return response
 	return h.returnPollSuccess(c, sessionID, pollReq, result)
 }
@@ -1600,6 +2344,10 @@ func (h *MessageHandler) handlePollSendError(c *fiber.Ctx, sessionID string, pol
 		return c.Status(400).JSON(common.NewErrorResponse("Session is not logged in"))
 	}
 
+	if handled, respErr := writeQuotaError(c, err); handled {
+		return respErr
+	}
+
 	return c.Status(500).JSON(common.NewErrorResponse("Failed to send poll"))
 }
 
@@ -1613,19 +2361,22 @@ func (h *MessageHandler) returnPollSuccess(c *fiber.Ctx, sessionID string, pollR
 	})
 
 	response := &message.CreatePollResponse{
-		MessageID: result.MessageID,
-		PollName:  pollReq.Name,
-		Options:   pollReq.Options,
-		RemoteJID: pollReq.RemoteJID,
-		Status:    result.Status,
-		Timestamp: result.Timestamp,
+		MessageID:             result.MessageID,
+		PollName:              pollReq.Name,
+		Options:               pollReq.Options,
+		SelectableOptionCount: pollReq.SelectableOptionCount,
+		AllowMultipleAnswers:  pollReq.SelectableOptionCount != 1,
+		ExpiresAt:             pollReq.ExpiresAt,
+		RemoteJID:             pollReq.RemoteJID,
+		Status:                result.Status,
+		Timestamp:             result.Timestamp,
 	}
 
 	return c.JSON(common.NewSuccessResponse(response, "Poll sent successfully"))
 }
 
 // @Summary Revoke message
-// @Description Revoke (delete for everyone) a previously sent message
+// @Description Revoke (delete for everyone) a message, or delete it only for this account with forAll=false. Set participant to revoke another member's message as group admin
 // @Tags Messages
 // @Security ApiKeyAuth
 // @Accept json
@@ -1693,6 +2444,13 @@ func (h *MessageHandler) RevokeMessage(c *fiber.Ctx) error {
 		if strings.Contains(err.Error(), "too old") {
 			return c.Status(400).JSON(common.NewErrorResponse("Message is too old to be revoked"))
 		}
+		if strings.Contains(err.Error(), "participant") {
+			return c.Status(400).JSON(common.NewErrorResponse(err.Error()))
+		}
+
+		if handled, respErr := writeQuotaError(c, err); handled {
+			return respErr
+		}
 
 		return c.Status(500).JSON(common.NewErrorResponse("Failed to revoke message"))
 	}
@@ -1700,14 +2458,154 @@ func (h *MessageHandler) RevokeMessage(c *fiber.Ctx) error {
 	return c.JSON(common.NewSuccessResponse(response, "Message revoked successfully"))
 }
 
+// @Summary Pin message
+// @Description Pin a message in a chat or group for 24h, 7d (default) or 30d. Set participant for messages from other group members, or fromMe for messages sent by this session. In groups where only admins can pin messages the session must be an admin
+// @Tags Messages
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param request body message.PinMessageRequest true "Pin message request"
+// @Success 200 {object} common.SuccessResponse{data=message.PinMessageResponse} "Message pinned successfully"
+// @Failure 400 {object} object "Bad Request"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/messages/pin [post]
+func (h *MessageHandler) PinMessage(c *fiber.Ctx) error {
+	return h.setMessagePin(c, true)
+}
+
+// @Summary Unpin message
+// @Description Unpin a message pinned in a chat or group
+// @Tags Messages
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param request body message.PinMessageRequest true "Unpin message request (duration is ignored)"
+// @Success 200 {object} common.SuccessResponse{data=message.PinMessageResponse} "Message unpinned successfully"
+// @Failure 400 {object} object "Bad Request"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/messages/unpin [post]
+func (h *MessageHandler) UnpinMessage(c *fiber.Ctx) error {
+	return h.setMessagePin(c, false)
+}
+
+// setMessagePin pins or unpins the message given in the request body
+func (h *MessageHandler) setMessagePin(c *fiber.Ctx, pin bool) error {
+	sessionIdentifier := c.Params("sessionId")
+	if sessionIdentifier == "" {
+		return c.Status(400).JSON(common.NewErrorResponse("Session identifier is required"))
+	}
+
+	var req message.PinMessageRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid request body"))
+	}
+
+	if req.RemoteJID == "" || req.MessageID == "" {
+		return c.Status(400).JSON(common.NewErrorResponse("'remoteJid' and 'messageId' are required"))
+	}
+
+	sess, err := h.sessionResolver.ResolveSession(c.Context(), sessionIdentifier)
+	if err != nil {
+		return c.Status(404).JSON(common.NewErrorResponse("Session not found"))
+	}
+
+	req.SessionID = sess.ID.String()
+
+	action := "unpin"
+	if pin {
+		action = "pin"
+	}
+
+	response, err := h.messageUC.PinMessage(c.Context(), &req, pin)
+	if err != nil {
+		if errors.Is(err, domainMessage.ErrInvalidPinDuration) {
+			return c.Status(400).JSON(common.NewErrorResponseWithCode(err.Error(), "INVALID_PIN_DURATION"))
+		}
+
+		h.logger.ErrorWithFields("Failed to "+action+" message", map[string]interface{}{
+			"session_id": sess.ID.String(),
+			"message_id": req.MessageID,
+			"to":         req.RemoteJID,
+			"error":      err.Error(),
+		})
+
+		if strings.Contains(err.Error(), "not logged in") {
+			return c.Status(400).JSON(common.NewErrorResponse("Session is not connected"))
+		}
+		if strings.Contains(err.Error(), "participant") || strings.Contains(err.Error(), "invalid JID") {
+			return c.Status(400).JSON(common.NewErrorResponse(err.Error()))
+		}
+
+		if handled, respErr := writeQuotaError(c, err); handled {
+			return respErr
+		}
+
+		return c.Status(500).JSON(common.NewErrorResponse("Failed to " + action + " message"))
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "Message "+response.Status+" successfully"))
+}
+
+// @Summary Cancel a queued or scheduled message
+// @Description Cancel a message still waiting in the offline queue (sent while the session was disconnected) or the outbox, a message sent with scheduleAt, or a scheduled draft. The response tells whether it was cancelled before being dispatched; a cancelled scheduled draft stays as a plain draft of its chat.
+// @Tags Messages
+// @Security ApiKeyAuth
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param queueId path string true "Queue ID of a queued message, ID of an outbox message or ID of a scheduled draft" example("7c9e6679-7425-40de-944b-e07fc1f90ae7")
+// @Success 200 {object} common.SuccessResponse{data=message.CancelQueuedMessageResponse} "Cancellation result"
+// @Failure 400 {object} object "Invalid queue ID"
+// @Failure 404 {object} object "Session or queued message not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/messages/queue/{queueId} [delete]
+func (h *MessageHandler) CancelQueuedMessage(c *fiber.Ctx) error {
+	sessionIdentifier := c.Params("sessionId")
+	if sessionIdentifier == "" {
+		return c.Status(400).JSON(common.NewErrorResponse("Session identifier is required"))
+	}
+
+	queueID, err := uuid.Parse(c.Params("queueId"))
+	if err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid queue ID"))
+	}
+
+	sess, err := h.sessionResolver.ResolveSession(c.Context(), sessionIdentifier)
+	if err != nil {
+		return c.Status(404).JSON(common.NewErrorResponse("Session not found"))
+	}
+
+	response, err := h.messageUC.CancelQueuedMessage(c.Context(), sess.ID.String(), queueID)
+	if err != nil {
+		if errors.Is(err, queue.ErrQueuedMessageNotFound) {
+			return c.Status(404).JSON(common.NewErrorResponse("Queued message not found"))
+		}
+
+		h.logger.ErrorWithFields("Failed to cancel queued message", map[string]interface{}{
+			"session_id": sess.ID.String(),
+			"queue_id":   queueID.String(),
+			"error":      err.Error(),
+		})
+		return c.Status(500).JSON(common.NewErrorResponse("Failed to cancel queued message"))
+	}
+
+	if !response.Cancelled {
+		return c.JSON(common.NewSuccessResponse(response, "Message is no longer waiting to be sent"))
+	}
+	return c.JSON(common.NewSuccessResponse(response, "Message cancelled successfully"))
+}
+
 // @Summary Get poll results
-// @Description Get voting results for a poll message
+// @Description Get the current votes of a poll sent or received by the session, counted per option with the voters who chose it. Votes are decrypted and stored as they arrive, so votes cast before the poll was seen are missing; votes after expiresAt are only counted in lateVotes
 // @Tags Messages
 // @Security ApiKeyAuth
 // @Produce json
 // @Param sessionId path string true "Session ID or Name" example("mySession")
 // @Param messageId path string true "Poll Message ID" example("3EB0C431C26A1916E07E")
-// @Param chatJid query string true "Chat JID where the poll was sent" example("5511999999999@s.whatsapp.net"
+// @Param remoteJid query string false "Chat JID where the poll was sent, checked when given" example("5511999999999@s.whatsapp.net")
 // @Success 200 {object} common.SuccessResponse{data=message.GetPollResultsResponse} "Poll results retrieved successfully"
 // @Failure 400 {object} object "Bad Request"
 // @Failure 404 {object} object "Session or poll not found"
@@ -1725,9 +2623,6 @@ func (h *MessageHandler) GetPollResults(c *fiber.Ctx) error {
 	}
 
 	remoteJID := c.Query("remoteJid")
-	if remoteJID == "" {
-		return c.Status(400).JSON(common.NewErrorResponse("Remote JID is required"))
-	}
 
 	h.logger.InfoWithFields("Getting poll results", map[string]interface{}{
 		"session":    sessionIdentifier,
@@ -1747,6 +2642,7 @@ func (h *MessageHandler) GetPollResults(c *fiber.Ctx) error {
 
 	// Create request
 	req := &message.GetPollResultsRequest{
+		SessionID:     sess.ID.String(),
 		RemoteJID:     remoteJID,
 		PollMessageID: messageID,
 	}
@@ -1761,7 +2657,7 @@ func (h *MessageHandler) GetPollResults(c *fiber.Ctx) error {
 			"error":      err.Error(),
 		})
 
-		if strings.Contains(err.Error(), "not found") {
+		if errors.Is(err, poll.ErrPollNotFound) {
 			return c.Status(404).JSON(common.NewErrorResponse("Poll not found"))
 		}
 
@@ -1771,6 +2667,84 @@ func (h *MessageHandler) GetPollResults(c *fiber.Ctx) error {
 	return c.JSON(common.NewSuccessResponse(response, "Poll results retrieved successfully"))
 }
 
+// @Summary Vote on poll
+// @Description Vote on a poll received or sent by the session. Options are given by their text; for polls sent by the session they are matched ignoring case and may also be given by hash, and the poll's selectable count is enforced. Set participant to the poll creator for polls from other group members and fromMe for own polls
+// @Tags Messages
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param messageId path string true "Poll Message ID" example("3EB0C431C26A1916E07E")
+// @Param request body message.VotePollRequest true "Vote request"
+// @Success 200 {object} common.SuccessResponse{data=message.SendMessageResponse} "Vote sent successfully"
+// @Failure 400 {object} object "Invalid request or option not part of the poll"
+// @Failure 404 {object} object "Session or poll not found"
+// @Failure 429 {object} object "Daily message quota exceeded"
+// @Failure 500 {object} object "Internal server error"
+// @Router /sessions/{sessionId}/messages/poll/{messageId}/vote [post]
+func (h *MessageHandler) VotePoll(c *fiber.Ctx) error {
+	sessionIdentifier := c.Params("sessionId")
+	if sessionIdentifier == "" {
+		return c.Status(400).JSON(common.NewErrorResponse("Session identifier is required"))
+	}
+
+	var voteReq message.VotePollRequest
+	if err := c.BodyParser(&voteReq); err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid request body"))
+	}
+	voteReq.PollMessageID = c.Params("messageId")
+
+	if voteReq.RemoteJID == "" || voteReq.PollMessageID == "" || len(voteReq.SelectedOptions) == 0 {
+		return c.Status(400).JSON(common.NewErrorResponse("'remoteJid', 'messageId' and 'selectedOptions' are required"))
+	}
+
+	sess, err := h.sessionResolver.ResolveSession(c.Context(), sessionIdentifier)
+	if err != nil {
+		return c.Status(404).JSON(common.NewErrorResponse("Session not found"))
+	}
+
+	var result *wameow.MessageResult
+	err = h.messageUC.AccountSend(c.Context(), func() (sendErr error) {
+		result, sendErr = h.wameowManager.VotePoll(sess.ID.String(), voteReq.RemoteJID, voteReq.PollMessageID, voteReq.Participant, voteReq.FromMe, voteReq.SelectedOptions)
+		return sendErr
+	})
+	if err != nil {
+		h.logger.ErrorWithFields("Failed to vote on poll", map[string]interface{}{
+			"session_id": sess.ID.String(),
+			"to":         voteReq.RemoteJID,
+			"poll_id":    voteReq.PollMessageID,
+			"error":      err.Error(),
+		})
+
+		switch {
+		case errors.Is(err, poll.ErrPollNotFound):
+			return c.Status(404).JSON(common.NewErrorResponse(err.Error()))
+		case errors.Is(err, poll.ErrUnknownOption), errors.Is(err, poll.ErrTooManyOptions), errors.Is(err, poll.ErrDuplicateOption):
+			return c.Status(400).JSON(common.NewErrorResponse(err.Error()))
+		case strings.Contains(err.Error(), "not logged in"):
+			return c.Status(400).JSON(common.NewErrorResponse("Session is not logged in"))
+		case strings.Contains(err.Error(), "participant"):
+			return c.Status(400).JSON(common.NewErrorResponse(err.Error()))
+		}
+
+		if handled, respErr := writeQuotaError(c, err); handled {
+			return respErr
+		}
+
+		return c.Status(500).JSON(common.NewErrorResponse("Failed to vote on poll"))
+	}
+
+	h.wameowManager.TraceRequest(c.Context(), sess.ID.String(), result.MessageID)
+
+	response := &message.SendMessageResponse{
+		ID:        result.MessageID,
+		Status:    result.Status,
+		Timestamp: result.Timestamp,
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "Vote sent successfully"))
+}
+
 // capitalizeFirst capitalizes the first letter of a string
 func capitalizeFirst(s string) string {
 	if len(s) == 0 {
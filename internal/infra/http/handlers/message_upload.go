@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+
+	"github.com/gofiber/fiber/v2"
+
+	"zpwoot/internal/app/message"
+	domainMessage "zpwoot/internal/domain/message"
+)
+
+// maxUploadFieldBytes bounds the text fields sent along with a multipart upload
+const maxUploadFieldBytes = 64 * 1024
+
+// isMultipartUpload reports whether the request sends its media as multipart/form-data
+func isMultipartUpload(c *fiber.Ctx) bool {
+	return len(c.Request().Header.MultipartFormBoundary()) > 0
+}
+
+// sendUploadedMedia sends a media message whose file was uploaded with multipart/form-data. The upload
+// is streamed to a temporary file, removed once the message is sent.
+func (h *MessageHandler) sendUploadedMedia(c *fiber.Ctx, messageType string) error {
+	var upload *domainMessage.ProcessedMedia
+	defer func() {
+		if upload != nil {
+			h.removeUpload(upload)
+		}
+	}()
+
+	return h.handleMediaMessage(c, messageType, func(c *fiber.Ctx) (*message.SendMessageRequest, *fiber.Error) {
+		req, fiberErr := h.parseMediaUpload(c, messageType)
+		if req != nil {
+			upload = req.Upload
+		}
+		return req, fiberErr
+	})
+}
+
+// parseMediaUpload reads the multipart form part by part so the file goes straight to disk instead of
+// being buffered in memory. The file is expected in the "file" part and the other fields use the JSON
+// names, with contextInfo.stanzaId and contextInfo.participant for replies.
+func (h *MessageHandler) parseMediaUpload(c *fiber.Ctx, messageType string) (*message.SendMessageRequest, *fiber.Error) {
+	body := c.Context().RequestBodyStream()
+	if body == nil {
+		body = bytes.NewReader(c.Body())
+	}
+	reader := multipart.NewReader(body, string(c.Request().Header.MultipartFormBoundary()))
+
+	req := &message.SendMessageRequest{Type: messageType}
+	var uploadName, uploadType string
+	var contextInfo message.ContextInfo
+
+	fail := func(fiberErr *fiber.Error) (*message.SendMessageRequest, *fiber.Error) {
+		if req.Upload != nil {
+			h.removeUpload(req.Upload)
+		}
+		// The rest of the body may be unread, the connection cannot serve another request
+		c.Context().SetConnectionClose()
+		return nil, fiberErr
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fail(fiber.NewError(400, fmt.Sprintf("Invalid %s upload: %v", messageType, err)))
+		}
+
+		if part.FormName() == "file" {
+			if req.Upload != nil {
+				return fail(fiber.NewError(400, "Only one 'file' can be uploaded"))
+			}
+			uploadName = part.FileName()
+			uploadType = part.Header.Get("Content-Type")
+			upload, err := h.messageUC.SaveUpload(part, uploadName, uploadType)
+			if err != nil {
+				return fail(uploadError(err))
+			}
+			req.Upload = upload
+			continue
+		}
+
+		value, err := io.ReadAll(io.LimitReader(part, maxUploadFieldBytes+1))
+		if err != nil {
+			return fail(fiber.NewError(400, fmt.Sprintf("Invalid %s upload: %v", messageType, err)))
+		}
+		if len(value) > maxUploadFieldBytes {
+			return fail(fiber.NewError(400, fmt.Sprintf("'%s' field is too long", part.FormName())))
+		}
+
+		switch part.FormName() {
+		case "remoteJid":
+			req.RemoteJID = string(value)
+		case "caption":
+			req.Caption = string(value)
+		case "mimeType":
+			req.MimeType = string(value)
+		case "filename":
+			req.Filename = string(value)
+		case "contextInfo.stanzaId":
+			contextInfo.StanzaID = string(value)
+		case "contextInfo.participant":
+			contextInfo.Participant = string(value)
+		}
+	}
+
+	if req.RemoteJID == "" {
+		return fail(fiber.NewError(400, "'Phone' field is required"))
+	}
+
+	if req.Upload == nil {
+		return fail(fiber.NewError(400, "'file' field is required"))
+	}
+
+	if contextInfo.StanzaID != "" || contextInfo.Participant != "" {
+		if contextInfo.StanzaID == "" {
+			return fail(fiber.NewError(400, "'contextInfo.stanzaId' is required when replying"))
+		}
+		req.ContextInfo = &contextInfo
+	}
+
+	// The uploaded file name stands in for a missing filename, documents need one
+	if req.Filename == "" && messageType != "audio" {
+		req.Filename = uploadName
+	}
+	if messageType == "document" && req.Filename == "" {
+		return fail(fiber.NewError(400, "'filename' field is required"))
+	}
+
+	return req, nil
+}
+
+// uploadError converts a failed upload to the status of the media error it wraps
+func uploadError(err error) *fiber.Error {
+	if status, _, ok := quotaErrorStatus(err); ok {
+		return fiber.NewError(status, err.Error())
+	}
+	return fiber.NewError(500, "Failed to save uploaded file")
+}
+
+// removeUpload deletes the temporary file of an upload
+func (h *MessageHandler) removeUpload(upload *domainMessage.ProcessedMedia) {
+	if err := upload.Cleanup(); err != nil {
+		h.logger.WarnWithFields("Failed to cleanup uploaded file", map[string]interface{}{
+			"file_path": upload.FilePath,
+			"error":     err.Error(),
+		})
+	}
+}
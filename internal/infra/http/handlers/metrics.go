@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"zpwoot/platform/logger"
+)
+
+type MetricsHandler struct {
+	logger  *logger.Logger
+	handler fiber.Handler
+}
+
+func NewMetricsHandler(logger *logger.Logger) *MetricsHandler {
+	return &MetricsHandler{
+		logger:  logger,
+		handler: adaptor.HTTPHandler(promhttp.Handler()),
+	}
+}
+
+// @Summary Prometheus metrics
+// @Description Metrics in the Prometheus text format: messages sent and received and connection state per session, whatsmeow internals per session (websocket reconnects, QR events, receipts, sent messages awaiting a receipt, media bytes transferred), webhook delivery latency and failures, HTTP request durations, and the Go runtime and process metrics of the Prometheus client
+// @Tags Health
+// @Security ApiKeyAuth
+// @Produce plain
+// @Success 200 {string} string "Prometheus metrics"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /metrics [get]
+func (h *MetricsHandler) GetMetrics(c *fiber.Ctx) error {
+	return h.handler(c)
+}
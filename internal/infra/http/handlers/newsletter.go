@@ -3,7 +3,9 @@ package handlers
 import (
 	"context"
 	"fmt"
+	"strings"
 
+	"zpwoot/internal/app/common"
 	"zpwoot/internal/app/newsletter"
 	domainSession "zpwoot/internal/domain/session"
 	"zpwoot/internal/infra/http/helpers"
@@ -101,7 +103,7 @@ func (h *NewsletterHandler) handleNewsletterAction(
 		return fiber.NewError(500, err.Error())
 	}
 
-	return c.JSON(response)
+	return c.JSON(common.NewSuccessResponse(response))
 }
 
 // handleNewsletterActionWithFiberMap handles newsletter actions that return fiber.Map responses
@@ -122,10 +124,7 @@ func (h *NewsletterHandler) handleNewsletterActionWithFiberMap(
 			"session_id": sess.ID.String(),
 			"error":      err.Error(),
 		})
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"error":   "Invalid request body",
-		})
+		return c.Status(fiber.StatusBadRequest).JSON(common.NewErrorResponse("Invalid request body"))
 	}
 
 	h.logger.InfoWithFields(actionName, map[string]interface{}{
@@ -138,20 +137,14 @@ func (h *NewsletterHandler) handleNewsletterActionWithFiberMap(
 			"session_id": sess.ID.String(),
 			"error":      err.Error(),
 		})
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"error":   fmt.Sprintf("Failed to %s", actionName),
-		})
+		return c.Status(fiber.StatusInternalServerError).JSON(common.NewErrorResponse(fmt.Sprintf("Failed to %s", actionName)))
 	}
 
 	h.logger.InfoWithFields(fmt.Sprintf("%s successfully", actionName), map[string]interface{}{
 		"session_id": sess.ID.String(),
 	})
 
-	return c.JSON(fiber.Map{
-		"success": true,
-		"data":    response,
-	})
+	return c.JSON(common.NewSuccessResponse(response))
 }
 
 // CreateNewsletter creates a new WhatsApp newsletter/channel
@@ -197,10 +190,7 @@ func (h *NewsletterHandler) CreateNewsletter(c *fiber.Ctx) error {
 		"name":          response.Name,
 	})
 
-	return c.Status(201).JSON(fiber.Map{
-		"success": true,
-		"data":    response,
-	})
+	return c.Status(201).JSON(common.NewSuccessResponse(response))
 }
 
 // GetNewsletterInfo gets information about a newsletter by JID
@@ -250,10 +240,7 @@ func (h *NewsletterHandler) GetNewsletterInfo(c *fiber.Ctx) error {
 		"name":          response.Name,
 	})
 
-	return c.JSON(fiber.Map{
-		"success": true,
-		"data":    response,
-	})
+	return c.JSON(common.NewSuccessResponse(response))
 }
 
 // GetNewsletterInfoWithInvite gets newsletter information using an invite key
@@ -303,10 +290,7 @@ func (h *NewsletterHandler) GetNewsletterInfoWithInvite(c *fiber.Ctx) error {
 		"name":          response.Name,
 	})
 
-	return c.JSON(fiber.Map{
-		"success": true,
-		"data":    response,
-	})
+	return c.JSON(common.NewSuccessResponse(response))
 }
 
 // FollowNewsletter follows a newsletter
@@ -328,6 +312,25 @@ func (h *NewsletterHandler) FollowNewsletter(c *fiber.Ctx) error {
 	)
 }
 
+// FollowNewsletterWithInvite follows a newsletter from an invite link or key in one call
+// POST /sessions/:sessionId/newsletters/follow-invite
+func (h *NewsletterHandler) FollowNewsletterWithInvite(c *fiber.Ctx) error {
+	return h.handleNewsletterAction(
+		c,
+		"Following newsletter via invite",
+		func(c *fiber.Ctx) (interface{}, error) {
+			var req newsletter.FollowNewsletterWithInviteRequest
+			if err := c.BodyParser(&req); err != nil {
+				return nil, err
+			}
+			return &req, nil
+		},
+		func(ctx context.Context, sessionID string, req interface{}) (interface{}, error) {
+			return h.newsletterUC.FollowNewsletterWithInvite(ctx, sessionID, req.(*newsletter.FollowNewsletterWithInviteRequest))
+		},
+	)
+}
+
 // UnfollowNewsletter unfollows a newsletter
 // POST /sessions/:sessionId/newsletters/unfollow
 func (h *NewsletterHandler) UnfollowNewsletter(c *fiber.Ctx) error {
@@ -347,19 +350,37 @@ func (h *NewsletterHandler) UnfollowNewsletter(c *fiber.Ctx) error {
 	)
 }
 
-// GetSubscribedNewsletters gets all newsletters the user is subscribed to
-// GET /sessions/:sessionId/newsletters
+// GetSubscribedNewsletters gets the newsletters the user is subscribed to
+// GET /sessions/:sessionId/newsletters?muted=true&role=owner&limit=50&offset=0&refresh=false
+// The list is cached per session for a few minutes; refresh=true fetches it from WhatsApp again.
 func (h *NewsletterHandler) GetSubscribedNewsletters(c *fiber.Ctx) error {
 	sess, fiberErr := h.resolveSession(c)
 	if fiberErr != nil {
 		return fiberErr
 	}
 
+	req := &newsletter.GetSubscribedNewslettersRequest{
+		Role:    strings.ToLower(c.Query("role")),
+		Limit:   c.QueryInt("limit", 50),
+		Offset:  c.QueryInt("offset", 0),
+		Refresh: c.QueryBool("refresh", false),
+	}
+	if c.Query("muted") != "" {
+		muted := c.QueryBool("muted", false)
+		req.Muted = &muted
+	}
+
+	if err := req.Validate(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(common.NewErrorResponse(err.Error()))
+	}
+
 	h.logger.InfoWithFields("Getting subscribed newsletters", map[string]interface{}{
 		"session_id": sess.ID.String(),
+		"role":       req.Role,
+		"refresh":    req.Refresh,
 	})
 
-	response, err := h.newsletterUC.GetSubscribedNewsletters(c.Context(), sess.ID.String())
+	response, err := h.newsletterUC.GetSubscribedNewsletters(c.Context(), sess.ID.String(), req)
 	if err != nil {
 		h.logger.ErrorWithFields("Failed to get subscribed newsletters", map[string]interface{}{
 			"session_id": sess.ID.String(),
@@ -378,10 +399,7 @@ func (h *NewsletterHandler) GetSubscribedNewsletters(c *fiber.Ctx) error {
 		"count":      response.Total,
 	})
 
-	return c.JSON(fiber.Map{
-		"success": true,
-		"data":    response,
-	})
+	return c.JSON(common.NewSuccessResponse(response))
 }
 
 // GetNewsletterMessages gets messages from a newsletter
@@ -397,10 +415,7 @@ func (h *NewsletterHandler) GetNewsletterMessages(c *fiber.Ctx) error {
 		h.logger.ErrorWithFields("Missing Newsletter JID parameter", map[string]interface{}{
 			"session_id": sess.ID.String(),
 		})
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"error":   "Missing required parameter: newsletterJid",
-		})
+		return c.Status(fiber.StatusBadRequest).JSON(common.NewErrorResponse("Missing required parameter: newsletterJid"))
 	}
 
 	// Parse optional parameters
@@ -427,10 +442,7 @@ func (h *NewsletterHandler) GetNewsletterMessages(c *fiber.Ctx) error {
 			"newsletter_jid": req.NewsletterJID,
 			"error":          err.Error(),
 		})
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"error":   "Failed to get newsletter messages",
-		})
+		return c.Status(fiber.StatusInternalServerError).JSON(common.NewErrorResponse("Failed to get newsletter messages"))
 	}
 
 	h.logger.InfoWithFields("Newsletter messages retrieved successfully", map[string]interface{}{
@@ -439,10 +451,7 @@ func (h *NewsletterHandler) GetNewsletterMessages(c *fiber.Ctx) error {
 		"count":          len(response.Messages),
 	})
 
-	return c.JSON(fiber.Map{
-		"success": true,
-		"data":    response,
-	})
+	return c.JSON(common.NewSuccessResponse(response))
 }
 
 // GetNewsletterMessageUpdates gets message updates from a newsletter
@@ -458,10 +467,7 @@ func (h *NewsletterHandler) GetNewsletterMessageUpdates(c *fiber.Ctx) error {
 		h.logger.ErrorWithFields("Missing Newsletter JID parameter", map[string]interface{}{
 			"session_id": sess.ID.String(),
 		})
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"error":   "Missing required parameter: newsletterJid",
-		})
+		return c.Status(fiber.StatusBadRequest).JSON(common.NewErrorResponse("Missing required parameter: newsletterJid"))
 	}
 
 	// Parse optional parameters
@@ -491,10 +497,7 @@ func (h *NewsletterHandler) GetNewsletterMessageUpdates(c *fiber.Ctx) error {
 			"newsletter_jid": req.NewsletterJID,
 			"error":          err.Error(),
 		})
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"error":   "Failed to get newsletter message updates",
-		})
+		return c.Status(fiber.StatusInternalServerError).JSON(common.NewErrorResponse("Failed to get newsletter message updates"))
 	}
 
 	h.logger.InfoWithFields("Newsletter message updates retrieved successfully", map[string]interface{}{
@@ -503,10 +506,7 @@ func (h *NewsletterHandler) GetNewsletterMessageUpdates(c *fiber.Ctx) error {
 		"count":          len(response.Updates),
 	})
 
-	return c.JSON(fiber.Map{
-		"success": true,
-		"data":    response,
-	})
+	return c.JSON(common.NewSuccessResponse(response))
 }
 
 // NewsletterMarkViewed marks newsletter messages as viewed
@@ -522,10 +522,7 @@ func (h *NewsletterHandler) NewsletterMarkViewed(c *fiber.Ctx) error {
 			"session_id": sess.ID.String(),
 			"error":      err.Error(),
 		})
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"error":   "Invalid request body",
-		})
+		return c.Status(fiber.StatusBadRequest).JSON(common.NewErrorResponse("Invalid request body"))
 	}
 
 	h.logger.InfoWithFields("Marking newsletter messages as viewed", map[string]interface{}{
@@ -541,10 +538,7 @@ func (h *NewsletterHandler) NewsletterMarkViewed(c *fiber.Ctx) error {
 			"newsletter_jid": req.NewsletterJID,
 			"error":          err.Error(),
 		})
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"error":   "Failed to mark newsletter messages as viewed",
-		})
+		return c.Status(fiber.StatusInternalServerError).JSON(common.NewErrorResponse("Failed to mark newsletter messages as viewed"))
 	}
 
 	h.logger.InfoWithFields("Newsletter messages marked as viewed successfully", map[string]interface{}{
@@ -553,10 +547,7 @@ func (h *NewsletterHandler) NewsletterMarkViewed(c *fiber.Ctx) error {
 		"count":          len(req.ServerIDs),
 	})
 
-	return c.JSON(fiber.Map{
-		"success": true,
-		"data":    response,
-	})
+	return c.JSON(common.NewSuccessResponse(response))
 }
 
 // NewsletterSendReaction sends a reaction to a newsletter message
@@ -572,10 +563,7 @@ func (h *NewsletterHandler) NewsletterSendReaction(c *fiber.Ctx) error {
 			"session_id": sess.ID.String(),
 			"error":      err.Error(),
 		})
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"error":   "Invalid request body",
-		})
+		return c.Status(fiber.StatusBadRequest).JSON(common.NewErrorResponse("Invalid request body"))
 	}
 
 	h.logger.InfoWithFields("Sending newsletter reaction", map[string]interface{}{
@@ -593,10 +581,11 @@ func (h *NewsletterHandler) NewsletterSendReaction(c *fiber.Ctx) error {
 			"server_id":      req.ServerID,
 			"error":          err.Error(),
 		})
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"error":   "Failed to send newsletter reaction",
-		})
+		if handled, respErr := writeQuotaError(c, err); handled {
+			return respErr
+		}
+
+		return c.Status(fiber.StatusInternalServerError).JSON(common.NewErrorResponse("Failed to send newsletter reaction"))
 	}
 
 	h.logger.InfoWithFields("Newsletter reaction sent successfully", map[string]interface{}{
@@ -606,10 +595,81 @@ func (h *NewsletterHandler) NewsletterSendReaction(c *fiber.Ctx) error {
 		"reaction":       req.Reaction,
 	})
 
-	return c.JSON(fiber.Map{
-		"success": true,
-		"data":    response,
-	})
+	return c.JSON(common.NewSuccessResponse(response))
+}
+
+// BulkNewsletterMarkViewed marks many newsletter messages as viewed, reporting the outcome per server ID
+// POST /sessions/:sessionId/newsletters/mark-viewed/bulk
+func (h *NewsletterHandler) BulkNewsletterMarkViewed(c *fiber.Ctx) error {
+	sess, fiberErr := h.resolveSession(c)
+	if fiberErr != nil {
+		return fiberErr
+	}
+
+	var req newsletter.BulkNewsletterMarkViewedRequest
+	if err := c.BodyParser(&req); err != nil {
+		h.logger.WarnWithFields("Failed to parse bulk newsletter mark viewed request", map[string]interface{}{
+			"session_id": sess.ID.String(),
+			"error":      err.Error(),
+		})
+		return c.Status(fiber.StatusBadRequest).JSON(common.NewErrorResponse("Invalid request body"))
+	}
+
+	if err := req.Validate(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(common.NewErrorResponse(err.Error()))
+	}
+
+	response, err := h.newsletterUC.BulkNewsletterMarkViewed(c.Context(), sess.ID.String(), &req)
+	if err != nil {
+		h.logger.ErrorWithFields("Failed to bulk mark newsletter messages as viewed", map[string]interface{}{
+			"session_id":     sess.ID.String(),
+			"newsletter_jid": req.NewsletterJID,
+			"error":          err.Error(),
+		})
+		if err.Error() == "session is not connected" {
+			return c.Status(fiber.StatusBadRequest).JSON(common.NewErrorResponse("Session is not connected"))
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(common.NewErrorResponse("Failed to mark newsletter messages as viewed"))
+	}
+
+	return c.JSON(common.NewSuccessResponse(response))
+}
+
+// BulkNewsletterSendReaction reacts to many newsletter messages, reporting the outcome per message
+// POST /sessions/:sessionId/newsletters/send-reaction/bulk
+func (h *NewsletterHandler) BulkNewsletterSendReaction(c *fiber.Ctx) error {
+	sess, fiberErr := h.resolveSession(c)
+	if fiberErr != nil {
+		return fiberErr
+	}
+
+	var req newsletter.BulkNewsletterSendReactionRequest
+	if err := c.BodyParser(&req); err != nil {
+		h.logger.WarnWithFields("Failed to parse bulk newsletter send reaction request", map[string]interface{}{
+			"session_id": sess.ID.String(),
+			"error":      err.Error(),
+		})
+		return c.Status(fiber.StatusBadRequest).JSON(common.NewErrorResponse("Invalid request body"))
+	}
+
+	if err := req.Validate(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(common.NewErrorResponse(err.Error()))
+	}
+
+	response, err := h.newsletterUC.BulkNewsletterSendReaction(c.Context(), sess.ID.String(), &req)
+	if err != nil {
+		h.logger.ErrorWithFields("Failed to bulk send newsletter reactions", map[string]interface{}{
+			"session_id":     sess.ID.String(),
+			"newsletter_jid": req.NewsletterJID,
+			"error":          err.Error(),
+		})
+		if err.Error() == "session is not connected" {
+			return c.Status(fiber.StatusBadRequest).JSON(common.NewErrorResponse("Session is not connected"))
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(common.NewErrorResponse("Failed to send newsletter reactions"))
+	}
+
+	return c.JSON(common.NewSuccessResponse(response))
 }
 
 // NewsletterSubscribeLiveUpdates subscribes to live updates from a newsletter
@@ -625,10 +685,7 @@ func (h *NewsletterHandler) NewsletterSubscribeLiveUpdates(c *fiber.Ctx) error {
 			"session_id": sess.ID.String(),
 			"error":      err.Error(),
 		})
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"error":   "Invalid request body",
-		})
+		return c.Status(fiber.StatusBadRequest).JSON(common.NewErrorResponse("Invalid request body"))
 	}
 
 	h.logger.InfoWithFields("Subscribing to newsletter live updates", map[string]interface{}{
@@ -643,10 +700,7 @@ func (h *NewsletterHandler) NewsletterSubscribeLiveUpdates(c *fiber.Ctx) error {
 			"newsletter_jid": req.NewsletterJID,
 			"error":          err.Error(),
 		})
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"error":   "Failed to subscribe to newsletter live updates",
-		})
+		return c.Status(fiber.StatusInternalServerError).JSON(common.NewErrorResponse("Failed to subscribe to newsletter live updates"))
 	}
 
 	h.logger.InfoWithFields("Subscribed to newsletter live updates successfully", map[string]interface{}{
@@ -655,10 +709,7 @@ func (h *NewsletterHandler) NewsletterSubscribeLiveUpdates(c *fiber.Ctx) error {
 		"duration":       response.Duration,
 	})
 
-	return c.JSON(fiber.Map{
-		"success": true,
-		"data":    response,
-	})
+	return c.JSON(common.NewSuccessResponse(response))
 }
 
 // NewsletterToggleMute toggles mute status of a newsletter
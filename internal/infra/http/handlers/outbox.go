@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"zpwoot/internal/app/common"
+	"zpwoot/internal/app/message"
+	"zpwoot/internal/app/outbox"
+	domainOutbox "zpwoot/internal/domain/outbox"
+	"zpwoot/internal/domain/session"
+	"zpwoot/internal/infra/http/helpers"
+	"zpwoot/platform/logger"
+	"zpwoot/platform/pagination"
+)
+
+type OutboxHandler struct {
+	logger          *logger.Logger
+	outboxUC        outbox.UseCase
+	sessionResolver *helpers.SessionResolver
+}
+
+func NewOutboxHandler(appLogger *logger.Logger, outboxUC outbox.UseCase, sessionRepo helpers.SessionRepository) *OutboxHandler {
+	return &OutboxHandler{
+		logger:          appLogger,
+		outboxUC:        outboxUC,
+		sessionResolver: helpers.NewSessionResolver(appLogger, sessionRepo),
+	}
+}
+
+// @Summary Enqueue message
+// @Description Add a message to the outbox instead of sending it right away. A worker sends it after a random delay since the previous message to the same chat (OUTBOX_MIN_DELAY to OUTBOX_MAX_DELAY), showing the session typing first when OUTBOX_SIMULATE_TYPING is set. Track it with the outbox endpoints; its status goes from queued to sending, then sent or failed
+// @Tags Messages
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param request body message.SendMessageRequest true "Message to send"
+// @Success 202 {object} common.SuccessResponse{data=outbox.OutboxMessageResponse} "Message added to the outbox"
+// @Failure 400 {object} object "Invalid request"
+// @Failure 404 {object} object "Session not found"
+// @Failure 429 {object} object "Daily message quota exceeded"
+// @Failure 503 {object} object "Outbox is disabled (OUTBOX_ENABLED)"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/messages/enqueue [post]
+func (h *OutboxHandler) Enqueue(c *fiber.Ctx) error {
+	sess, fiberErr := h.resolveSession(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	var req message.SendMessageRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid request body"))
+	}
+
+	if req.RemoteJID == "" {
+		return c.Status(400).JSON(common.NewErrorResponse("'remoteJid' field is required"))
+	}
+
+	response, err := h.outboxUC.Enqueue(c.Context(), sess.ID.String(), &req)
+	if err != nil {
+		return h.handleError(c, "enqueue message", err)
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(common.NewSuccessResponse(response, "Message added to the outbox"))
+}
+
+// @Summary List outbox messages
+// @Description List the messages added to the outbox of a session, newest first, optionally filtered by status
+// @Tags Messages
+// @Security ApiKeyAuth
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param status query string false "Filter by status (queued, sending, sent, failed)"
+// @Param limit query int false "Number of messages to return" default(20)
+// @Param offset query int false "Number of messages to skip" default(0)
+// @Param cursor query string false "nextCursor of the previous page; replaces offset"
+// @Success 200 {object} common.SuccessResponse{data=outbox.ListOutboxResponse} "Outbox messages retrieved successfully"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/messages/outbox [get]
+func (h *OutboxHandler) ListMessages(c *fiber.Ctx) error {
+	sess, fiberErr := h.resolveSession(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	var req outbox.ListOutboxRequest
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid query parameters"))
+	}
+
+	response, err := h.outboxUC.ListMessages(c.Context(), sess.ID.String(), &req)
+	if err != nil {
+		return h.handleError(c, "list outbox messages", err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "Outbox messages retrieved successfully"))
+}
+
+// @Summary Get outbox message
+// @Description Get the status of a message added to the outbox
+// @Tags Messages
+// @Security ApiKeyAuth
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param outboxId path string true "Outbox message ID" example("7c9e6679-7425-40de-944b-e07fc1f90ae7")
+// @Success 200 {object} common.SuccessResponse{data=outbox.OutboxMessageResponse} "Outbox message retrieved successfully"
+// @Failure 400 {object} object "Invalid outbox message ID"
+// @Failure 404 {object} object "Session or outbox message not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/messages/outbox/{outboxId} [get]
+func (h *OutboxHandler) GetMessage(c *fiber.Ctx) error {
+	outboxID, err := uuid.Parse(c.Params("outboxId"))
+	if err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid outbox message ID"))
+	}
+
+	sess, fiberErr := h.resolveSession(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	response, err := h.outboxUC.GetMessage(c.Context(), sess.ID.String(), outboxID)
+	if err != nil {
+		return h.handleError(c, "get outbox message", err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "Outbox message retrieved successfully"))
+}
+
+func (h *OutboxHandler) resolveSession(c *fiber.Ctx) (*session.Session, *fiber.Error) {
+	sessionIdentifier := c.Params("sessionId")
+	if sessionIdentifier == "" {
+		return nil, fiber.NewError(400, "Session identifier is required")
+	}
+
+	sess, err := h.sessionResolver.ResolveSession(c.Context(), sessionIdentifier)
+	if err != nil {
+		return nil, fiber.NewError(404, "Session not found")
+	}
+
+	return sess, nil
+}
+
+// handleError maps outbox domain errors to HTTP responses
+func (h *OutboxHandler) handleError(c *fiber.Ctx, action string, err error) error {
+	if handled, respErr := writeQuotaError(c, err); handled {
+		return respErr
+	}
+
+	switch {
+	case errors.Is(err, domainOutbox.ErrOutboxMessageNotFound):
+		return c.Status(404).JSON(common.NewErrorResponse("Outbox message not found"))
+	case errors.Is(err, domainOutbox.ErrOutboxDisabled):
+		return c.Status(503).JSON(common.NewErrorResponseWithCode(err.Error(), "OUTBOX_DISABLED"))
+	case errors.Is(err, pagination.ErrInvalidCursor):
+		return c.Status(400).JSON(common.NewErrorResponseWithCode(err.Error(), "INVALID_CURSOR"))
+	case strings.HasPrefix(err.Error(), "invalid request"):
+		return c.Status(400).JSON(common.NewErrorResponse(err.Error()))
+	}
+
+	h.logger.ErrorWithFields("Failed to "+action, map[string]interface{}{
+		"error": err.Error(),
+	})
+	return c.Status(500).JSON(common.NewErrorResponse("Failed to " + action))
+}
@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"zpwoot/internal/app/common"
+	"zpwoot/internal/app/pairing"
+	domainPairing "zpwoot/internal/domain/pairing"
+	"zpwoot/internal/domain/session"
+	"zpwoot/internal/infra/http/pages"
+	"zpwoot/platform/logger"
+)
+
+// pairingPollInterval is how often the pairing event stream checks for a new QR code or a completed pairing
+const pairingPollInterval = 2 * time.Second
+
+type PairingHandler struct {
+	logger    *logger.Logger
+	pairingUC pairing.UseCase
+}
+
+func NewPairingHandler(appLogger *logger.Logger, pairingUC pairing.UseCase) *PairingHandler {
+	return &PairingHandler{
+		logger:    appLogger,
+		pairingUC: pairingUC,
+	}
+}
+
+// @Summary Create pairing link
+// @Description Create a one-time signed URL to a public page where the end customer scans the QR code
+// @Description to pair their own number, without access to the API key. The page refreshes the QR code by itself.
+// @Description A new link revokes earlier ones of the session; the link stops working once the session is paired.
+// @Tags Sessions
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param request body pairing.CreateLinkRequest false "Link options"
+// @Success 201 {object} common.SuccessResponse{data=pairing.PairingLinkResponse} "Pairing link created successfully"
+// @Failure 400 {object} object "Invalid request body"
+// @Failure 404 {object} object "Session not found"
+// @Failure 409 {object} object "Session is already paired"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/pairing-link [post]
+func (h *PairingHandler) CreateLink(c *fiber.Ctx) error {
+	sessionID := c.Params("sessionId")
+
+	var req pairing.CreateLinkRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(common.NewErrorResponse("Invalid request body"))
+		}
+	}
+	if req.TTL < 0 || time.Duration(req.TTL)*time.Second > domainPairing.MaxLinkTTL {
+		return c.Status(400).JSON(common.NewErrorResponse(fmt.Sprintf("ttlSeconds must be between 1 and %d", int(domainPairing.MaxLinkTTL.Seconds()))))
+	}
+
+	response, err := h.pairingUC.CreateLink(c.Context(), sessionID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, session.ErrSessionNotFound):
+			return c.Status(404).JSON(common.NewErrorResponse("Session not found"))
+		case errors.Is(err, domainPairing.ErrAlreadyPaired):
+			return c.Status(409).JSON(common.NewErrorResponseWithCode("Session is already paired", "SESSION_ALREADY_PAIRED"))
+		}
+
+		h.logger.ErrorWithFields("Failed to create pairing link", map[string]interface{}{
+			"session_id": sessionID,
+			"error":      err.Error(),
+		})
+		return c.Status(500).JSON(common.NewErrorResponse("Failed to create pairing link"))
+	}
+
+	return c.Status(201).JSON(common.NewSuccessResponse(response, "Pairing link created successfully"))
+}
+
+// PairingPage renders the public pairing page of a pairing link
+func (h *PairingHandler) PairingPage(c *fiber.Ctx) error {
+	token := c.Params("token")
+	data := pages.PairingData{EventsURL: "/pair/" + token + "/events"}
+
+	status := fiber.StatusOK
+	page, err := h.pairingUC.OpenLink(c.Context(), token)
+	if err != nil {
+		status, data.Error = pairingLinkError(err)
+	} else {
+		data.SessionName = page.SessionName
+		data.ExpiresAt = page.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	c.Set("Referrer-Policy", "no-referrer")
+	c.Type("html", "utf-8")
+	return pages.Pairing.Execute(c.Status(status).Response().BodyWriter(), data)
+}
+
+// PairingEvents streams server-sent events to the pairing page: "qr" with each new QR code,
+// "waiting" while the next code is generated, then "connected" or "closed" to end the stream
+func (h *PairingHandler) PairingEvents(c *fiber.Ctx) error {
+	token := c.Params("token")
+
+	page, err := h.pairingUC.OpenLink(c.Context(), token)
+	if err != nil {
+		status, message := pairingLinkError(err)
+		return c.Status(status).JSON(common.NewErrorResponse(message))
+	}
+
+	if err := h.pairingUC.StartPairing(c.Context(), token); err != nil {
+		h.logger.WarnWithFields("Failed to start pairing", map[string]interface{}{
+			"session_name": page.SessionName,
+			"error":        err.Error(),
+		})
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	// The stream outlives the handler, so it runs on its own context bounded by the link expiry
+	ctx, cancel := context.WithDeadline(context.Background(), page.ExpiresAt)
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer cancel()
+		h.streamPairing(ctx, w, token)
+	})
+
+	return nil
+}
+
+func (h *PairingHandler) streamPairing(ctx context.Context, w *bufio.Writer, token string) {
+	ticker := time.NewTicker(pairingPollInterval)
+	defer ticker.Stop()
+
+	lastQR, waiting := "", false
+	for {
+		state, err := h.pairingUC.PairingState(ctx, token)
+		switch {
+		case ctx.Err() != nil:
+			writeSSE(w, "closed", map[string]string{"reason": "This pairing link has expired."})
+			return
+		case err != nil:
+			_, reason := pairingLinkError(err)
+			writeSSE(w, "closed", map[string]string{"reason": reason})
+			return
+		case state.Connected:
+			writeSSE(w, "connected", state)
+			return
+		case state.QRCodeImage == "" && !waiting:
+			lastQR, waiting = "", true
+			if !writeSSE(w, "waiting", state) {
+				return
+			}
+		case state.QRCodeImage != "" && state.QRCodeImage != lastQR:
+			waiting = false
+			lastQR = state.QRCodeImage
+			if !writeSSE(w, "qr", state) {
+				return
+			}
+		default:
+			// Heartbeat comment; a failed flush means the page was closed
+			if _, err := w.WriteString(": ping\n\n"); err != nil || w.Flush() != nil {
+				return
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			writeSSE(w, "closed", map[string]string{"reason": "This pairing link has expired."})
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// writeSSE writes one server-sent event and reports whether the client is still listening
+func writeSSE(w *bufio.Writer, event string, data interface{}) bool {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return false
+	}
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return false
+	}
+	return w.Flush() == nil
+}
+
+// pairingLinkError maps a pairing link error to its HTTP status and a message for the end customer
+func pairingLinkError(err error) (int, string) {
+	switch {
+	case errors.Is(err, domainPairing.ErrLinkExpired):
+		return fiber.StatusGone, "This pairing link has expired."
+	case errors.Is(err, domainPairing.ErrLinkUsed):
+		return fiber.StatusGone, "This pairing link has already been used."
+	case errors.Is(err, domainPairing.ErrInvalidLink), errors.Is(err, session.ErrSessionNotFound):
+		return fiber.StatusNotFound, "This pairing link is not valid."
+	default:
+		return fiber.StatusInternalServerError, "Pairing is temporarily unavailable."
+	}
+}
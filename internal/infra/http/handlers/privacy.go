@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"zpwoot/internal/app/common"
+	"zpwoot/internal/app/privacy"
+	"zpwoot/internal/domain/session"
+	"zpwoot/internal/infra/http/helpers"
+	"zpwoot/platform/logger"
+)
+
+type PrivacyHandler struct {
+	logger          *logger.Logger
+	privacyUC       privacy.UseCase
+	sessionResolver *helpers.SessionResolver
+}
+
+func NewPrivacyHandler(appLogger *logger.Logger, privacyUC privacy.UseCase, sessionRepo helpers.SessionRepository) *PrivacyHandler {
+	return &PrivacyHandler{
+		logger:          appLogger,
+		privacyUC:       privacyUC,
+		sessionResolver: helpers.NewSessionResolver(appLogger, sessionRepo),
+	}
+}
+
+// @Summary Get privacy settings
+// @Description Get the privacy mode of a session. Sessions without settings report privacy mode as off
+// @Tags Privacy
+// @Security ApiKeyAuth
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Success 200 {object} common.SuccessResponse{data=privacy.PrivacyConfigResponse} "Privacy settings retrieved successfully"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/privacy [get]
+func (h *PrivacyHandler) GetConfig(c *fiber.Ctx) error {
+	sess, fiberErr := h.resolveSession(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	response, err := h.privacyUC.GetConfig(c.Context(), sess.ID.String())
+	if err != nil {
+		return h.handleError(c, "get privacy settings", err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "Privacy settings retrieved successfully"))
+}
+
+// @Summary Update privacy settings
+// @Description Update the fields that are set on the privacy mode of a session. redactLogs keeps the text, captions and contact cards of its messages out of the logs, including debug body logging. metadataOnlyWebhooks delivers Message events with only their IDs, chat, sender, timestamp and type, marked with "content_redacted": true; receivers fetch the content on demand from GET /sessions/{sessionId}/chats/{jid}/messages. Stored events replayed later stay redacted
+// @Tags Privacy
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param request body privacy.UpdatePrivacyConfigRequest true "Fields to update"
+// @Success 200 {object} common.SuccessResponse{data=privacy.PrivacyConfigResponse} "Privacy settings updated successfully"
+// @Failure 400 {object} object "Bad Request"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/privacy [put]
+func (h *PrivacyHandler) UpdateConfig(c *fiber.Ctx) error {
+	sess, fiberErr := h.resolveSession(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	var req privacy.UpdatePrivacyConfigRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid request body"))
+	}
+
+	response, err := h.privacyUC.UpdateConfig(c.Context(), sess.ID.String(), &req)
+	if err != nil {
+		return h.handleError(c, "update privacy settings", err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "Privacy settings updated successfully"))
+}
+
+// resolveSession resolves the session from the sessionId path parameter
+func (h *PrivacyHandler) resolveSession(c *fiber.Ctx) (*session.Session, *fiber.Error) {
+	sessionIdentifier := c.Params("sessionId")
+	if sessionIdentifier == "" {
+		return nil, fiber.NewError(400, "Session identifier is required")
+	}
+
+	sess, err := h.sessionResolver.ResolveSession(c.Context(), sessionIdentifier)
+	if err != nil {
+		return nil, fiber.NewError(404, "Session not found")
+	}
+
+	return sess, nil
+}
+
+func (h *PrivacyHandler) handleError(c *fiber.Ctx, action string, err error) error {
+	h.logger.ErrorWithFields("Failed to "+action, map[string]interface{}{
+		"error": err.Error(),
+	})
+	return c.Status(500).JSON(common.NewErrorResponse("Failed to " + action))
+}
@@ -0,0 +1,264 @@
+package handlers
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/gofiber/fiber/v2"
+
+	"zpwoot/internal/app/common"
+	"zpwoot/internal/app/routing"
+	domainRouting "zpwoot/internal/domain/routing"
+	"zpwoot/internal/domain/session"
+	"zpwoot/internal/infra/http/helpers"
+	"zpwoot/platform/logger"
+)
+
+type RoutingHandler struct {
+	logger          *logger.Logger
+	routingUC       routing.UseCase
+	sessionResolver *helpers.SessionResolver
+}
+
+func NewRoutingHandler(appLogger *logger.Logger, routingUC routing.UseCase, sessionRepo helpers.SessionRepository) *RoutingHandler {
+	return &RoutingHandler{
+		logger:          appLogger,
+		routingUC:       routingUC,
+		sessionResolver: helpers.NewSessionResolver(appLogger, sessionRepo),
+	}
+}
+
+// @Summary Create routing rule
+// @Description Create a rule that tags, labels or routes incoming messages by keyword, regex or sender, e.g. messages containing "suporte" to the support webhook. Labels are applied to the chat automatically and matching rules are reported in the "routing" field of webhook payloads
+// @Tags Routing
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param request body routing.CreateRoutingRuleRequest true "Routing rule"
+// @Success 201 {object} common.SuccessResponse{data=routing.RoutingRuleResponse} "Routing rule created successfully"
+// @Failure 400 {object} object "Bad Request"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/routing/rules [post]
+func (h *RoutingHandler) CreateRule(c *fiber.Ctx) error {
+	sess, fiberErr := h.resolveSession(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	var req routing.CreateRoutingRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid request body"))
+	}
+
+	response, err := h.routingUC.CreateRule(c.Context(), sess.ID.String(), &req)
+	if err != nil {
+		return h.handleError(c, "create routing rule", err)
+	}
+
+	return c.Status(201).JSON(common.NewSuccessResponse(response, "Routing rule created successfully"))
+}
+
+// @Summary List routing rules
+// @Description List the routing rules of a session in evaluation order
+// @Tags Routing
+// @Security ApiKeyAuth
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Success 200 {object} common.SuccessResponse{data=routing.ListRoutingRulesResponse} "Routing rules retrieved successfully"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/routing/rules [get]
+func (h *RoutingHandler) ListRules(c *fiber.Ctx) error {
+	sess, fiberErr := h.resolveSession(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	response, err := h.routingUC.ListRules(c.Context(), sess.ID.String())
+	if err != nil {
+		return h.handleError(c, "list routing rules", err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "Routing rules retrieved successfully"))
+}
+
+// @Summary Get routing rule
+// @Description Get a routing rule of a session
+// @Tags Routing
+// @Security ApiKeyAuth
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param ruleId path string true "Routing rule ID"
+// @Success 200 {object} common.SuccessResponse{data=routing.RoutingRuleResponse} "Routing rule retrieved successfully"
+// @Failure 404 {object} object "Session or routing rule not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/routing/rules/{ruleId} [get]
+func (h *RoutingHandler) GetRule(c *fiber.Ctx) error {
+	sess, fiberErr := h.resolveSession(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	response, err := h.routingUC.GetRule(c.Context(), sess.ID.String(), c.Params("ruleId"))
+	if err != nil {
+		return h.handleError(c, "get routing rule", err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "Routing rule retrieved successfully"))
+}
+
+// @Summary Update routing rule
+// @Description Update the fields that are set on a routing rule
+// @Tags Routing
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param ruleId path string true "Routing rule ID"
+// @Param request body routing.UpdateRoutingRuleRequest true "Fields to update"
+// @Success 200 {object} common.SuccessResponse{data=routing.RoutingRuleResponse} "Routing rule updated successfully"
+// @Failure 400 {object} object "Bad Request"
+// @Failure 404 {object} object "Session or routing rule not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/routing/rules/{ruleId} [put]
+func (h *RoutingHandler) UpdateRule(c *fiber.Ctx) error {
+	sess, fiberErr := h.resolveSession(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	var req routing.UpdateRoutingRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid request body"))
+	}
+
+	response, err := h.routingUC.UpdateRule(c.Context(), sess.ID.String(), c.Params("ruleId"), &req)
+	if err != nil {
+		return h.handleError(c, "update routing rule", err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "Routing rule updated successfully"))
+}
+
+// @Summary Delete routing rule
+// @Description Delete a routing rule of a session
+// @Tags Routing
+// @Security ApiKeyAuth
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param ruleId path string true "Routing rule ID"
+// @Success 200 {object} common.SuccessResponse "Routing rule deleted successfully"
+// @Failure 404 {object} object "Session or routing rule not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/routing/rules/{ruleId} [delete]
+func (h *RoutingHandler) DeleteRule(c *fiber.Ctx) error {
+	sess, fiberErr := h.resolveSession(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	if err := h.routingUC.DeleteRule(c.Context(), sess.ID.String(), c.Params("ruleId")); err != nil {
+		return h.handleError(c, "delete routing rule", err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(nil, "Routing rule deleted successfully"))
+}
+
+// @Summary Label chat
+// @Description Apply a WhatsApp Business label to a chat. Routing rules with labels apply them automatically to chats of matching messages
+// @Tags Routing
+// @Security ApiKeyAuth
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param jid path string true "Chat JID" example("5511999999999@s.whatsapp.net")
+// @Param labelId path string true "WhatsApp label ID" example("1")
+// @Success 200 {object} common.SuccessResponse{data=routing.ChatLabelResponse} "Chat labeled successfully"
+// @Failure 400 {object} object "Bad Request"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/chats/{jid}/labels/{labelId} [put]
+func (h *RoutingHandler) LabelChat(c *fiber.Ctx) error {
+	return h.setChatLabel(c, true, "Chat labeled successfully")
+}
+
+// @Summary Unlabel chat
+// @Description Remove a WhatsApp Business label from a chat
+// @Tags Routing
+// @Security ApiKeyAuth
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param jid path string true "Chat JID" example("5511999999999@s.whatsapp.net")
+// @Param labelId path string true "WhatsApp label ID" example("1")
+// @Success 200 {object} common.SuccessResponse{data=routing.ChatLabelResponse} "Chat label removed successfully"
+// @Failure 400 {object} object "Bad Request"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/chats/{jid}/labels/{labelId} [delete]
+func (h *RoutingHandler) UnlabelChat(c *fiber.Ctx) error {
+	return h.setChatLabel(c, false, "Chat label removed successfully")
+}
+
+func (h *RoutingHandler) setChatLabel(c *fiber.Ctx, labeled bool, message string) error {
+	sess, fiberErr := h.resolveSession(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	chatJID, err := url.PathUnescape(c.Params("jid"))
+	if err != nil || chatJID == "" {
+		return c.Status(400).JSON(common.NewErrorResponse("Chat JID is required"))
+	}
+
+	action := "label chat"
+	if !labeled {
+		action = "unlabel chat"
+	}
+
+	response, err := h.routingUC.LabelChat(c.Context(), sess.ID.String(), chatJID, c.Params("labelId"), labeled)
+	if err != nil {
+		return h.handleError(c, action, err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, message))
+}
+
+// resolveSession resolves the session from the sessionId path parameter
+func (h *RoutingHandler) resolveSession(c *fiber.Ctx) (*session.Session, *fiber.Error) {
+	sessionIdentifier := c.Params("sessionId")
+	if sessionIdentifier == "" {
+		return nil, fiber.NewError(400, "Session identifier is required")
+	}
+
+	sess, err := h.sessionResolver.ResolveSession(c.Context(), sessionIdentifier)
+	if err != nil {
+		return nil, fiber.NewError(404, "Session not found")
+	}
+
+	return sess, nil
+}
+
+// handleError maps routing domain errors to HTTP responses
+func (h *RoutingHandler) handleError(c *fiber.Ctx, action string, err error) error {
+	switch {
+	case errors.Is(err, domainRouting.ErrRuleNotFound):
+		return c.Status(404).JSON(common.NewErrorResponse("Routing rule not found"))
+	case errors.Is(err, domainRouting.ErrRuleNameRequired),
+		errors.Is(err, domainRouting.ErrNoConditions),
+		errors.Is(err, domainRouting.ErrNoActions),
+		errors.Is(err, domainRouting.ErrInvalidWebhookID),
+		errors.Is(err, domainRouting.ErrInvalidPattern),
+		errors.Is(err, domainRouting.ErrInvalidNextState),
+		errors.Is(err, domainRouting.ErrInvalidChatJID),
+		errors.Is(err, domainRouting.ErrLabelIDRequired):
+		return c.Status(400).JSON(common.NewErrorResponse(err.Error()))
+	case errors.Is(err, domainRouting.ErrLabelingDisabled):
+		return c.Status(503).JSON(common.NewErrorResponse(err.Error()))
+	}
+
+	h.logger.ErrorWithFields("Failed to "+action, map[string]interface{}{
+		"error": err.Error(),
+	})
+	return c.Status(500).JSON(common.NewErrorResponse("Failed to " + action))
+}
@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"zpwoot/internal/app/common"
+	"zpwoot/internal/app/scheduled"
+	domainScheduled "zpwoot/internal/domain/scheduled"
+	"zpwoot/internal/domain/session"
+	"zpwoot/internal/infra/http/helpers"
+	"zpwoot/platform/logger"
+	"zpwoot/platform/pagination"
+)
+
+type ScheduledHandler struct {
+	logger          *logger.Logger
+	scheduledUC     scheduled.UseCase
+	sessionResolver *helpers.SessionResolver
+}
+
+func NewScheduledHandler(appLogger *logger.Logger, scheduledUC scheduled.UseCase, sessionRepo helpers.SessionRepository) *ScheduledHandler {
+	return &ScheduledHandler{
+		logger:          appLogger,
+		scheduledUC:     scheduledUC,
+		sessionResolver: helpers.NewSessionResolver(appLogger, sessionRepo),
+	}
+}
+
+// @Summary List scheduled messages
+// @Description List the messages of a session sent with a scheduleAt, newest first, optionally filtered by status
+// @Tags Messages
+// @Security ApiKeyAuth
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param status query string false "Filter by status (scheduled, sending, sent, failed, cancelled)"
+// @Param limit query int false "Number of messages to return" default(20)
+// @Param offset query int false "Number of messages to skip" default(0)
+// @Param cursor query string false "nextCursor of the previous page; replaces offset"
+// @Success 200 {object} common.SuccessResponse{data=scheduled.ListScheduledResponse} "Scheduled messages retrieved successfully"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/messages/scheduled [get]
+func (h *ScheduledHandler) ListMessages(c *fiber.Ctx) error {
+	sess, fiberErr := h.resolveSession(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	var req scheduled.ListScheduledRequest
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid query parameters"))
+	}
+
+	response, err := h.scheduledUC.ListMessages(c.Context(), sess.ID.String(), &req)
+	if err != nil {
+		return h.handleError(c, "list scheduled messages", err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "Scheduled messages retrieved successfully"))
+}
+
+// @Summary Get scheduled message
+// @Description Get the status of a message sent with a scheduleAt
+// @Tags Messages
+// @Security ApiKeyAuth
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param scheduledId path string true "Scheduled message ID" example("1b9d6bcd-bbfd-4b2d-9b5d-ab8dfbbd4bed")
+// @Success 200 {object} common.SuccessResponse{data=scheduled.ScheduledMessageResponse} "Scheduled message retrieved successfully"
+// @Failure 400 {object} object "Invalid scheduled message ID"
+// @Failure 404 {object} object "Session or scheduled message not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/messages/scheduled/{scheduledId} [get]
+func (h *ScheduledHandler) GetMessage(c *fiber.Ctx) error {
+	scheduledID, err := uuid.Parse(c.Params("scheduledId"))
+	if err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid scheduled message ID"))
+	}
+
+	sess, fiberErr := h.resolveSession(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	response, err := h.scheduledUC.GetMessage(c.Context(), sess.ID.String(), scheduledID)
+	if err != nil {
+		return h.handleError(c, "get scheduled message", err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "Scheduled message retrieved successfully"))
+}
+
+// @Summary Reschedule message
+// @Description Move a message that is still scheduled to a new scheduleAt, which must be in the future and within a year
+// @Tags Messages
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param scheduledId path string true "Scheduled message ID" example("1b9d6bcd-bbfd-4b2d-9b5d-ab8dfbbd4bed")
+// @Param request body scheduled.RescheduleRequest true "New schedule"
+// @Success 200 {object} common.SuccessResponse{data=scheduled.ScheduledMessageResponse} "Message rescheduled successfully"
+// @Failure 400 {object} object "Invalid scheduleAt"
+// @Failure 404 {object} object "Session or scheduled message not found"
+// @Failure 409 {object} object "Message is no longer scheduled"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/messages/scheduled/{scheduledId} [patch]
+func (h *ScheduledHandler) Reschedule(c *fiber.Ctx) error {
+	scheduledID, err := uuid.Parse(c.Params("scheduledId"))
+	if err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid scheduled message ID"))
+	}
+
+	sess, fiberErr := h.resolveSession(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	var req scheduled.RescheduleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid request body"))
+	}
+
+	response, err := h.scheduledUC.Reschedule(c.Context(), sess.ID.String(), scheduledID, &req)
+	if err != nil {
+		return h.handleError(c, "reschedule message", err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "Message rescheduled successfully"))
+}
+
+// @Summary Cancel scheduled message
+// @Description Keep a scheduled message from being sent. Messages already picked up for sending cannot be cancelled; the response then reports cancelled false with their current status
+// @Tags Messages
+// @Security ApiKeyAuth
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param scheduledId path string true "Scheduled message ID" example("1b9d6bcd-bbfd-4b2d-9b5d-ab8dfbbd4bed")
+// @Success 200 {object} common.SuccessResponse{data=scheduled.CancelScheduledResponse} "Cancellation processed"
+// @Failure 400 {object} object "Invalid scheduled message ID"
+// @Failure 404 {object} object "Session or scheduled message not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/messages/scheduled/{scheduledId} [delete]
+func (h *ScheduledHandler) Cancel(c *fiber.Ctx) error {
+	scheduledID, err := uuid.Parse(c.Params("scheduledId"))
+	if err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid scheduled message ID"))
+	}
+
+	sess, fiberErr := h.resolveSession(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	response, err := h.scheduledUC.Cancel(c.Context(), sess.ID.String(), scheduledID)
+	if err != nil {
+		return h.handleError(c, "cancel scheduled message", err)
+	}
+
+	message := "Scheduled message cancelled"
+	if !response.Cancelled {
+		message = "Message is no longer scheduled"
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, message))
+}
+
+func (h *ScheduledHandler) resolveSession(c *fiber.Ctx) (*session.Session, *fiber.Error) {
+	sessionIdentifier := c.Params("sessionId")
+	if sessionIdentifier == "" {
+		return nil, fiber.NewError(400, "Session identifier is required")
+	}
+
+	sess, err := h.sessionResolver.ResolveSession(c.Context(), sessionIdentifier)
+	if err != nil {
+		return nil, fiber.NewError(404, "Session not found")
+	}
+
+	return sess, nil
+}
+
+// handleError maps scheduled message domain errors to HTTP responses
+func (h *ScheduledHandler) handleError(c *fiber.Ctx, action string, err error) error {
+	switch {
+	case errors.Is(err, domainScheduled.ErrScheduledMessageNotFound):
+		return c.Status(404).JSON(common.NewErrorResponse("Scheduled message not found"))
+	case errors.Is(err, domainScheduled.ErrScheduleInPast), errors.Is(err, domainScheduled.ErrScheduleTooFar):
+		return c.Status(400).JSON(common.NewErrorResponseWithCode(err.Error(), "INVALID_SCHEDULE"))
+	case errors.Is(err, domainScheduled.ErrNotScheduled):
+		return c.Status(409).JSON(common.NewErrorResponseWithCode(err.Error(), "NOT_SCHEDULED"))
+	case errors.Is(err, pagination.ErrInvalidCursor):
+		return c.Status(400).JSON(common.NewErrorResponseWithCode(err.Error(), "INVALID_CURSOR"))
+	case strings.HasPrefix(err.Error(), "invalid request"):
+		return c.Status(400).JSON(common.NewErrorResponse(err.Error()))
+	}
+
+	h.logger.ErrorWithFields("Failed to "+action, map[string]interface{}{
+		"error": err.Error(),
+	})
+	return c.Status(500).JSON(common.NewErrorResponse("Failed to " + action))
+}
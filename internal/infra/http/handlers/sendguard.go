@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"zpwoot/internal/app/common"
+	"zpwoot/internal/app/sendguard"
+	domainSendGuard "zpwoot/internal/domain/sendguard"
+	"zpwoot/internal/domain/session"
+	"zpwoot/internal/infra/http/helpers"
+	"zpwoot/platform/logger"
+)
+
+type SendGuardHandler struct {
+	logger          *logger.Logger
+	sendGuardUC     sendguard.UseCase
+	sessionResolver *helpers.SessionResolver
+}
+
+func NewSendGuardHandler(appLogger *logger.Logger, sendGuardUC sendguard.UseCase, sessionRepo helpers.SessionRepository) *SendGuardHandler {
+	return &SendGuardHandler{
+		logger:          appLogger,
+		sendGuardUC:     sendGuardUC,
+		sessionResolver: helpers.NewSessionResolver(appLogger, sessionRepo),
+	}
+}
+
+// @Summary Get send policy
+// @Description Get the send policy of a session: whether sends to groups are blocked, the groups every session may send to and the groups this session may send to, and whether the session is in sandbox mode with the recipients it may send to
+// @Tags Send Guard
+// @Security ApiKeyAuth
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Success 200 {object} common.SuccessResponse{data=sendguard.SendPolicyResponse} "Send policy retrieved successfully"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/send-policy [get]
+func (h *SendGuardHandler) GetPolicy(c *fiber.Ctx) error {
+	sess, fiberErr := h.resolveSession(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	response, err := h.sendGuardUC.GetPolicy(c.Context(), sess.ID.String())
+	if err != nil {
+		return h.handleError(c, "get send policy", err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "Send policy retrieved successfully"))
+}
+
+// @Summary Update send policy
+// @Description Update the fields that are set on the send policy of a session. While SEND_GUARD_BLOCK_GROUPS is enabled, sends to groups that are neither in SEND_GUARD_ALLOWED_GROUPS nor in the session's allowedGroups fail with 403 GROUP_SEND_BLOCKED and are recorded as violations. While the session is in sandbox mode (sandbox set or SANDBOX_MODE enabled), sends to recipients outside sandboxRecipients and SANDBOX_RECIPIENTS go to sandboxRedirectTo, or SANDBOX_REDIRECT_TO, and fail with 403 SANDBOX_RECIPIENT_BLOCKED when neither is set
+// @Tags Send Guard
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param request body sendguard.UpdateSendPolicyRequest true "Fields to update"
+// @Success 200 {object} common.SuccessResponse{data=sendguard.SendPolicyResponse} "Send policy updated successfully"
+// @Failure 400 {object} object "Bad Request"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/send-policy [put]
+func (h *SendGuardHandler) UpdatePolicy(c *fiber.Ctx) error {
+	sess, fiberErr := h.resolveSession(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	var req sendguard.UpdateSendPolicyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid request body"))
+	}
+
+	response, err := h.sendGuardUC.UpdatePolicy(c.Context(), sess.ID.String(), &req)
+	if err != nil {
+		return h.handleError(c, "update send policy", err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "Send policy updated successfully"))
+}
+
+// @Summary List send violations
+// @Description List the sends of a session the send guard blocked or redirected to the sandbox, most recent first
+// @Tags Send Guard
+// @Security ApiKeyAuth
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param limit query int false "Maximum number of violations (default 50, max 500)"
+// @Success 200 {object} common.SuccessResponse{data=sendguard.ListSendViolationsResponse} "Send violations retrieved successfully"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/send-policy/violations [get]
+func (h *SendGuardHandler) ListViolations(c *fiber.Ctx) error {
+	sess, fiberErr := h.resolveSession(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	response, err := h.sendGuardUC.ListViolations(c.Context(), sess.ID.String(), c.QueryInt("limit"))
+	if err != nil {
+		return h.handleError(c, "list send violations", err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "Send violations retrieved successfully"))
+}
+
+// resolveSession resolves the session from the sessionId path parameter
+func (h *SendGuardHandler) resolveSession(c *fiber.Ctx) (*session.Session, *fiber.Error) {
+	sessionIdentifier := c.Params("sessionId")
+	if sessionIdentifier == "" {
+		return nil, fiber.NewError(400, "Session identifier is required")
+	}
+
+	sess, err := h.sessionResolver.ResolveSession(c.Context(), sessionIdentifier)
+	if err != nil {
+		return nil, fiber.NewError(404, "Session not found")
+	}
+
+	return sess, nil
+}
+
+// handleError maps send guard domain errors to HTTP responses
+func (h *SendGuardHandler) handleError(c *fiber.Ctx, action string, err error) error {
+	if errors.Is(err, domainSendGuard.ErrInvalidGroupJID) || errors.Is(err, domainSendGuard.ErrInvalidSandboxRecipient) {
+		return c.Status(400).JSON(common.NewErrorResponse(err.Error()))
+	}
+
+	h.logger.ErrorWithFields("Failed to "+action, map[string]interface{}{
+		"error": err.Error(),
+	})
+	return c.Status(500).JSON(common.NewErrorResponse("Failed to " + action))
+}
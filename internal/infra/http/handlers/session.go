@@ -2,14 +2,19 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"zpwoot/internal/app/common"
 	"zpwoot/internal/app/session"
 	domainSession "zpwoot/internal/domain/session"
+	"zpwoot/internal/domain/stats"
 	"zpwoot/internal/infra/http/helpers"
 	"zpwoot/platform/logger"
+	"zpwoot/platform/pagination"
+	"zpwoot/platform/precondition"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -121,6 +126,7 @@ func (h *SessionHandler) handleSessionActionNoReturn(
 // @Param request body session.CreateSessionRequest true "Session creation request with optional qrCode flag"
 // @Success 201 {object} session.CreateSessionResponse "Session created successfully. If qrCode was true, includes QR code data."
 // @Failure 400 {object} object "Bad Request"
+// @Failure 402 {object} object "Session quota exceeded"
 // @Failure 409 {object} object "Session already exists"
 // @Failure 500 {object} object "Internal Server Error"
 // @Router /sessions/create [post]
@@ -129,10 +135,7 @@ func (h *SessionHandler) CreateSession(c *fiber.Ctx) error {
 
 	if h.sessionUC == nil {
 		h.logger.Error("Session use case not initialized")
-		return c.Status(500).JSON(fiber.Map{
-			"success": false,
-			"message": "Session service not available",
-		})
+		return c.Status(500).JSON(common.NewErrorResponse("Session service not available"))
 	}
 
 	var req session.CreateSessionRequest
@@ -148,8 +151,7 @@ func (h *SessionHandler) CreateSession(c *fiber.Ctx) error {
 		})
 
 		suggested := h.sessionResolver.SuggestValidName(req.Name)
-		return c.Status(400).JSON(fiber.Map{
-			"error":         "Invalid session name",
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid session name", fiber.Map{
 			"message":       errorMsg,
 			"suggestedName": suggested,
 			"namingRules": []string{
@@ -158,19 +160,23 @@ func (h *SessionHandler) CreateSession(c *fiber.Ctx) error {
 				"Can contain letters, numbers, hyphens, and underscores",
 				"Cannot use reserved names (create, list, info, etc.)",
 			},
-		})
+		}))
 	}
 
 	result, err := h.sessionUC.CreateSession(c.Context(), &req)
 	if err != nil {
 		h.logger.Error("Failed to create session: " + err.Error())
 
+		if handled, respErr := writeQuotaError(c, err); handled {
+			return respErr
+		}
+
+		if errors.Is(err, domainSession.ErrInvalidProxyConfig) {
+			return c.Status(400).JSON(common.NewErrorResponse(err.Error()))
+		}
+
 		if strings.Contains(err.Error(), "Session already exists") {
-			return c.Status(409).JSON(fiber.Map{
-				"success": false,
-				"error":   "Session already exists",
-				"message": fmt.Sprintf("A session with the name '%s' already exists. Please choose a different name.", req.Name),
-			})
+			return c.Status(409).JSON(common.NewErrorResponse(fmt.Sprintf("A session with the name '%s' already exists. Please choose a different name.", req.Name), "Session already exists"))
 		}
 
 		return c.Status(500).JSON(common.NewErrorResponse("Failed to create session"))
@@ -191,6 +197,9 @@ func (h *SessionHandler) CreateSession(c *fiber.Ctx) error {
 // @Param deviceJid query string false "Filter by device JID"
 // @Param limit query int false "Number of sessions to return (default: 20)"
 // @Param offset query int false "Number of sessions to skip (default: 0)"
+// @Param cursor query string false "nextCursor of the previous page; replaces offset"
+// @Param fields query string false "Comma-separated dotted paths of the data to return" example("sessions.session.name,total")
+// @Param expand query string false "Comma-separated sub-resources to include for every session: stats, webhooks"
 // @Success 200 {object} session.ListSessionsResponse "Sessions retrieved successfully"
 // @Failure 400 {object} object "Bad Request"
 // @Failure 500 {object} object "Internal Server Error"
@@ -228,8 +237,12 @@ func (h *SessionHandler) ListSessions(c *fiber.Ctx) error {
 	if offset := c.QueryInt("offset", 0); offset >= 0 {
 		req.Offset = offset
 	}
+	req.Cursor = c.Query("cursor")
 
 	result, err := h.sessionUC.ListSessions(c.Context(), &req)
+	if errors.Is(err, pagination.ErrInvalidCursor) {
+		return c.Status(400).JSON(common.NewErrorResponseWithCode(err.Error(), "INVALID_CURSOR"))
+	}
 	if err != nil {
 		h.logger.Error("Failed to list sessions: " + err.Error())
 		return c.Status(500).JSON(common.NewErrorResponse("Failed to list sessions"))
@@ -240,19 +253,36 @@ func (h *SessionHandler) ListSessions(c *fiber.Ctx) error {
 }
 
 // @Summary Get session information
-// @Description Get detailed information about a specific WhatsApp session
+// @Description Get detailed information about a specific WhatsApp session. The health block reports recent webhook delivery success, Chatwoot relay errors and queue depth so degraded integrations show up in one call. The message counters and webhooks of the session are only included when asked for with expand; fields trims the response to the listed dotted paths
 // @Tags Sessions
 // @Security ApiKeyAuth
 // @Produce json
 // @Param sessionId path string true "Session ID"
+// @Param fields query string false "Comma-separated dotted paths of the data to return" example("session.name,session.isConnected,stats")
+// @Param expand query string false "Comma-separated sub-resources to include: stats, webhooks" example("stats,webhooks")
+// @Param If-None-Match header string false "ETag of a previous response; answered with 304 while the session is unchanged"
 // @Success 200 {object} session.SessionInfoResponse "Session information retrieved successfully"
+// @Success 304 "Session unchanged since the ETag in If-None-Match"
 // @Failure 404 {object} object "Session not found"
 // @Failure 500 {object} object "Internal Server Error"
 // @Router /sessions/{sessionId}/info [get]
 func (h *SessionHandler) GetSessionInfo(c *fiber.Ctx) error {
-	return h.handleSessionAction(c, "get session info", func(ctx context.Context, sessionID string) (interface{}, error) {
-		return h.sessionUC.GetSessionInfo(ctx, sessionID)
-	})
+	if h.sessionUC == nil {
+		return c.Status(500).JSON(common.NewErrorResponse("Session use case not initialized"))
+	}
+
+	sess, fiberErr := h.resolveSession(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	result, err := h.sessionUC.GetSessionInfo(c.Context(), sess.ID.String())
+	if err != nil {
+		h.logger.Error("Failed to get session info: " + err.Error())
+		return c.Status(500).JSON(common.NewErrorResponse("Failed to get session info"))
+	}
+
+	return helpers.RespondWithETag(c, result, "Get Session Info retrieved successfully")
 }
 
 // @Summary Delete session
@@ -299,6 +329,20 @@ func (h *SessionHandler) LogoutSession(c *fiber.Ctx) error {
 	return h.handleSessionActionNoReturn(c, "logout session", h.sessionUC.LogoutSession, "Session logged out successfully")
 }
 
+// @Summary Restart session
+// @Description Tear down the session's WhatsApp client (contexts, QR loop, event handlers) and recreate it from the stored credentials, reconnecting paired sessions. Recovers a wedged client without restarting the service; registered event handlers are kept.
+// @Tags Sessions
+// @Security ApiKeyAuth
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Success 200 {object} common.SuccessResponse "Session restarted"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/restart [post]
+func (h *SessionHandler) RestartSession(c *fiber.Ctx) error {
+	return h.handleSessionActionNoReturn(c, "restart session", h.sessionUC.RestartSession, "Session restarted successfully")
+}
+
 // @Summary Get QR code
 // @Description Get QR code for WhatsApp session pairing. Returns both raw QR code string and base64 image.
 // @Tags Sessions
@@ -356,16 +400,18 @@ func (h *SessionHandler) PairPhone(c *fiber.Ctx) error {
 }
 
 // @Summary Set proxy configuration
-// @Description Set or update proxy configuration for a WhatsApp session
+// @Description Set or update proxy configuration for a WhatsApp session. localAddress or interface binds the session's WhatsApp and media connections to a local IP of a multi-IP server, directly (leave type empty) or through a socks5 proxy. Changes apply from the next connection of the session; use PUT /sessions/{sessionId}/proxy to reconnect with them right away.
 // @Tags Sessions
 // @Security ApiKeyAuth
 // @Accept json
 // @Produce json
 // @Param sessionId path string true "Session ID"
 // @Param request body session.SetProxyRequest true "Proxy configuration request"
+// @Param If-Match header string false "ETag from GET /sessions/{sessionId}/proxy/find; the update fails with 412 when the proxy configuration changed since"
 // @Success 200 {object} session.ProxyResponse "Proxy configuration set successfully"
 // @Failure 400 {object} object "Bad Request"
 // @Failure 404 {object} object "Session not found"
+// @Failure 412 {object} object "Proxy configuration changed since the ETag in If-Match"
 // @Failure 500 {object} object "Internal Server Error"
 // @Router /sessions/{sessionId}/proxy/set [post]
 func (h *SessionHandler) SetProxy(c *fiber.Ctx) error {
@@ -389,12 +435,26 @@ func (h *SessionHandler) SetProxy(c *fiber.Ctx) error {
 		return c.Status(400).JSON(common.NewErrorResponse("Invalid request body"))
 	}
 
-	err := h.sessionUC.SetProxy(c.Context(), sess.ID.String(), &req)
+	ctx, ok, err := helpers.CheckIfMatch(c, func() (interface{}, time.Time, error) {
+		proxy, err := h.sessionUC.GetProxy(c.Context(), sess.ID.String())
+		return proxy, sess.UpdatedAt, err
+	})
+	if !ok {
+		return err
+	}
+
+	err = h.sessionUC.SetProxy(ctx, sess.ID.String(), &req)
 	if err != nil {
 		h.logger.Error("Failed to set proxy: " + err.Error())
+		if errors.Is(err, precondition.ErrFailed) {
+			return helpers.RespondPreconditionFailed(c)
+		}
 		if err.Error() == "session not found" {
 			return c.Status(404).JSON(common.NewErrorResponse("Session not found"))
 		}
+		if errors.Is(err, domainSession.ErrInvalidProxyConfig) {
+			return c.Status(400).JSON(common.NewErrorResponse(err.Error()))
+		}
 		return c.Status(500).JSON(common.NewErrorResponse("Failed to set proxy"))
 	}
 
@@ -402,13 +462,81 @@ func (h *SessionHandler) SetProxy(c *fiber.Ctx) error {
 	return c.JSON(response)
 }
 
+// @Summary Update proxy configuration
+// @Description Replace the proxy configuration of a WhatsApp session and apply it right away: a connected or paired session reconnects through the new proxy. Supports http, https and socks5 proxies with username/password authentication; leave type empty to connect directly. localAddress or interface binds the connections to a local IP of a multi-IP server, for direct and socks5 connections. The applied configuration is echoed back without the proxy password.
+// @Tags Sessions
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID"
+// @Param request body session.SetProxyRequest true "Proxy configuration request"
+// @Param If-Match header string false "ETag from GET /sessions/{sessionId}/proxy/find; the update fails with 412 when the proxy configuration changed since"
+// @Success 200 {object} session.UpdateProxyResponse "Proxy configuration applied"
+// @Failure 400 {object} object "Bad Request"
+// @Failure 404 {object} object "Session not found"
+// @Failure 412 {object} object "Proxy configuration changed since the ETag in If-Match"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/proxy [put]
+func (h *SessionHandler) UpdateProxy(c *fiber.Ctx) error {
+	if h.sessionUC == nil {
+		return c.Status(500).JSON(common.NewErrorResponse("Session use case not initialized"))
+	}
+
+	sess, fiberErr := h.resolveSession(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	h.logger.InfoWithFields("Updating proxy", map[string]interface{}{
+		"session_id":   sess.ID.String(),
+		"session_name": sess.Name,
+	})
+
+	var req session.SetProxyRequest
+	if err := c.BodyParser(&req); err != nil {
+		h.logger.Error("Failed to parse request body: " + err.Error())
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid request body"))
+	}
+
+	ctx, ok, err := helpers.CheckIfMatch(c, func() (interface{}, time.Time, error) {
+		proxy, err := h.sessionUC.GetProxy(c.Context(), sess.ID.String())
+		return proxy, sess.UpdatedAt, err
+	})
+	if !ok {
+		return err
+	}
+
+	result, err := h.sessionUC.UpdateProxy(ctx, sess.ID.String(), &req)
+	if err != nil {
+		h.logger.Error("Failed to update proxy: " + err.Error())
+		if errors.Is(err, precondition.ErrFailed) {
+			return helpers.RespondPreconditionFailed(c)
+		}
+		if err.Error() == "session not found" {
+			return c.Status(404).JSON(common.NewErrorResponse("Session not found"))
+		}
+		if errors.Is(err, domainSession.ErrInvalidProxyConfig) {
+			return c.Status(400).JSON(common.NewErrorResponse(err.Error()))
+		}
+		return c.Status(500).JSON(common.NewErrorResponse("Failed to update proxy"))
+	}
+
+	message := "Proxy configuration updated, applies from the next connection"
+	if result.Reconnected {
+		message = "Proxy configuration updated, session reconnecting"
+	}
+	return c.JSON(common.NewSuccessResponse(result, message))
+}
+
 // @Summary Get proxy configuration
 // @Description Get current proxy configuration for a WhatsApp session
 // @Tags Sessions
 // @Security ApiKeyAuth
 // @Produce json
 // @Param sessionId path string true "Session ID"
+// @Param If-None-Match header string false "ETag of a previous response; answered with 304 while the proxy configuration is unchanged"
 // @Success 200 {object} session.ProxyResponse "Proxy configuration retrieved successfully"
+// @Success 304 "Proxy configuration unchanged since the ETag in If-None-Match"
 // @Failure 404 {object} object "Session not found"
 // @Failure 500 {object} object "Internal Server Error"
 // @Router /sessions/{sessionId}/proxy/find [get]
@@ -436,6 +564,75 @@ func (h *SessionHandler) GetProxy(c *fiber.Ctx) error {
 		return c.Status(500).JSON(common.NewErrorResponse("Failed to get proxy"))
 	}
 
-	response := common.NewSuccessResponse(result, "Proxy configuration retrieved successfully")
-	return c.JSON(response)
+	return helpers.RespondWithETag(c, result, "Proxy configuration retrieved successfully")
+}
+
+// @Summary List event handlers
+// @Description List the event handlers registered for a session. Handlers that are not attached were registered before the session had a client and do not receive events.
+// @Tags Sessions
+// @Security ApiKeyAuth
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Success 200 {object} common.SuccessResponse{data=session.ListEventHandlersResponse} "Event handlers retrieved successfully"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/event-handlers [get]
+func (h *SessionHandler) ListEventHandlers(c *fiber.Ctx) error {
+	return h.handleSessionAction(c, "list event handlers", func(ctx context.Context, sessionID string) (interface{}, error) {
+		return h.sessionUC.ListEventHandlers(ctx, sessionID)
+	})
+}
+
+// @Summary Clear session warning
+// @Description Clear the account violation or ban warning kept on the session after a session.warning event
+// @Tags Sessions
+// @Security ApiKeyAuth
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Success 200 {object} common.SuccessResponse "Session warning cleared"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/warning [delete]
+func (h *SessionHandler) ClearWarning(c *fiber.Ctx) error {
+	return h.handleSessionActionNoReturn(c, "clear session warning", h.sessionUC.ClearWarning, "Session warning cleared successfully")
+}
+
+// @Summary Get session message statistics
+// @Description Get the messages sent and received by a session per message type (text, image, audio, template, ...) and direction, in total and per UTC day. Counters are stored daily; the range defaults to the last 30 days and may span up to 366 days.
+// @Tags Sessions
+// @Security ApiKeyAuth
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param from query string false "First day of the range (YYYY-MM-DD)" example("2024-01-01")
+// @Param to query string false "Last day of the range (YYYY-MM-DD), defaults to today" example("2024-01-31")
+// @Success 200 {object} common.SuccessResponse{data=session.SessionStatsReportResponse} "Session statistics retrieved successfully"
+// @Failure 400 {object} object "Invalid date range"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/stats [get]
+func (h *SessionHandler) GetSessionStats(c *fiber.Ctx) error {
+	if h.sessionUC == nil {
+		return c.Status(500).JSON(common.NewErrorResponse("Session use case not initialized"))
+	}
+
+	sess, fiberErr := h.resolveSession(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	req := &session.SessionStatsRequest{
+		From: c.Query("from"),
+		To:   c.Query("to"),
+	}
+
+	result, err := h.sessionUC.GetSessionStats(c.Context(), sess.ID.String(), req)
+	if err != nil {
+		if errors.Is(err, stats.ErrInvalidDay) || errors.Is(err, stats.ErrInvalidRange) || errors.Is(err, stats.ErrRangeTooLong) {
+			return c.Status(400).JSON(common.NewErrorResponse(err.Error()))
+		}
+		h.logger.Error("Failed to get session stats: " + err.Error())
+		return c.Status(500).JSON(common.NewErrorResponse("Failed to get session stats"))
+	}
+
+	return c.JSON(common.NewSuccessResponse(result, "Session statistics retrieved successfully"))
 }
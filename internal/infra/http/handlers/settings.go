@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"zpwoot/internal/app/common"
+	"zpwoot/internal/app/settings"
+	domainSettings "zpwoot/internal/domain/settings"
+	"zpwoot/internal/infra/http/helpers"
+	"zpwoot/platform/logger"
+	"zpwoot/platform/precondition"
+)
+
+type SettingsHandler struct {
+	logger     *logger.Logger
+	settingsUC settings.UseCase
+}
+
+func NewSettingsHandler(appLogger *logger.Logger, settingsUC settings.UseCase) *SettingsHandler {
+	return &SettingsHandler{
+		logger:     appLogger,
+		settingsUC: settingsUC,
+	}
+}
+
+// @Summary Get runtime settings
+// @Description Get the log level, debug body logging and feature flags in effect
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Param If-None-Match header string false "ETag of a previous response; answered with 304 while the settings are unchanged"
+// @Success 200 {object} common.SuccessResponse{data=settings.SettingsResponse} "Settings retrieved successfully"
+// @Success 304 "Settings unchanged since the ETag in If-None-Match"
+// @Router /admin/settings [get]
+func (h *SettingsHandler) GetSettings(c *fiber.Ctx) error {
+	return helpers.RespondWithETag(c, h.settingsUC.GetSettings(c.Context()), "Settings retrieved successfully")
+}
+
+// @Summary Update runtime settings
+// @Description Change the log level, toggle debug body logging (logged at debug level) or flip feature flags without a restart.
+// @Description Changes are persisted and picked up by every replica within a few seconds.
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param request body settings.UpdateSettingsRequest true "Settings to change"
+// @Param If-Match header string false "ETag from GET /admin/settings; the update fails with 412 when the settings changed since"
+// @Success 200 {object} common.SuccessResponse{data=settings.SettingsResponse} "Settings updated successfully"
+// @Failure 400 {object} object "Invalid settings"
+// @Failure 412 {object} object "Settings changed since the ETag in If-Match"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /admin/settings [patch]
+func (h *SettingsHandler) UpdateSettings(c *fiber.Ctx) error {
+	var req settings.UpdateSettingsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid request body"))
+	}
+
+	ctx, ok, err := helpers.CheckIfMatch(c, func() (interface{}, time.Time, error) {
+		current := h.settingsUC.GetSettings(c.Context())
+		var version time.Time
+		if current.UpdatedAt != nil {
+			version = *current.UpdatedAt
+		}
+		return current, version, nil
+	})
+	if !ok {
+		return err
+	}
+
+	response, err := h.settingsUC.UpdateSettings(ctx, &req)
+	if err != nil {
+		if errors.Is(err, precondition.ErrFailed) {
+			return helpers.RespondPreconditionFailed(c)
+		}
+		if errors.Is(err, domainSettings.ErrInvalidLogLevel) ||
+			errors.Is(err, domainSettings.ErrInvalidFlagName) ||
+			errors.Is(err, domainSettings.ErrNothingToUpdate) {
+			return c.Status(400).JSON(common.NewErrorResponse(err.Error()))
+		}
+
+		h.logger.ErrorWithFields("Failed to update settings", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return c.Status(500).JSON(common.NewErrorResponse("Failed to update settings"))
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "Settings updated successfully"))
+}
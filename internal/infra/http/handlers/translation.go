@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"zpwoot/internal/app/common"
+	"zpwoot/internal/app/translation"
+	"zpwoot/internal/domain/session"
+	domainTranslation "zpwoot/internal/domain/translation"
+	"zpwoot/internal/infra/http/helpers"
+	"zpwoot/platform/logger"
+)
+
+type TranslationHandler struct {
+	logger          *logger.Logger
+	translationUC   translation.UseCase
+	sessionResolver *helpers.SessionResolver
+}
+
+func NewTranslationHandler(appLogger *logger.Logger, translationUC translation.UseCase, sessionRepo helpers.SessionRepository) *TranslationHandler {
+	return &TranslationHandler{
+		logger:          appLogger,
+		translationUC:   translationUC,
+		sessionResolver: helpers.NewSessionResolver(appLogger, sessionRepo),
+	}
+}
+
+// @Summary Get translation settings
+// @Description Get the message translation settings of a session. Sessions without settings report translation as disabled
+// @Tags Translation
+// @Security ApiKeyAuth
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Success 200 {object} common.SuccessResponse{data=translation.TranslationConfigResponse} "Translation settings retrieved successfully"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/translation [get]
+func (h *TranslationHandler) GetConfig(c *fiber.Ctx) error {
+	sess, fiberErr := h.resolveSession(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	response, err := h.translationUC.GetConfig(c.Context(), sess.ID.String())
+	if err != nil {
+		return h.handleError(c, "get translation settings", err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "Translation settings retrieved successfully"))
+}
+
+// @Summary Update translation settings
+// @Description Update the fields that are set on the translation settings of a session. Inbound translation replaces the text and caption of incoming messages in webhook payloads, from sourceLanguage to targetLanguage, and keeps the originals in the "translation" field. Outbound translation translates the text and caption of sent messages from targetLanguage to sourceLanguage and reports the original text in the send response
+// @Tags Translation
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param request body translation.UpdateTranslationConfigRequest true "Fields to update"
+// @Success 200 {object} common.SuccessResponse{data=translation.TranslationConfigResponse} "Translation settings updated successfully"
+// @Failure 400 {object} object "Bad Request"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Failure 503 {object} object "Translation provider is not configured"
+// @Router /sessions/{sessionId}/translation [put]
+func (h *TranslationHandler) UpdateConfig(c *fiber.Ctx) error {
+	sess, fiberErr := h.resolveSession(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	var req translation.UpdateTranslationConfigRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid request body"))
+	}
+
+	response, err := h.translationUC.UpdateConfig(c.Context(), sess.ID.String(), &req)
+	if err != nil {
+		return h.handleError(c, "update translation settings", err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "Translation settings updated successfully"))
+}
+
+// resolveSession resolves the session from the sessionId path parameter
+func (h *TranslationHandler) resolveSession(c *fiber.Ctx) (*session.Session, *fiber.Error) {
+	sessionIdentifier := c.Params("sessionId")
+	if sessionIdentifier == "" {
+		return nil, fiber.NewError(400, "Session identifier is required")
+	}
+
+	sess, err := h.sessionResolver.ResolveSession(c.Context(), sessionIdentifier)
+	if err != nil {
+		return nil, fiber.NewError(404, "Session not found")
+	}
+
+	return sess, nil
+}
+
+// handleError maps translation domain errors to HTTP responses
+func (h *TranslationHandler) handleError(c *fiber.Ctx, action string, err error) error {
+	switch {
+	case errors.Is(err, domainTranslation.ErrInvalidLanguage),
+		errors.Is(err, domainTranslation.ErrSameLanguage),
+		errors.Is(err, domainTranslation.ErrOutboundNeedsSource),
+		errors.Is(err, domainTranslation.ErrDirectionRequired):
+		return c.Status(400).JSON(common.NewErrorResponse(err.Error()))
+	case errors.Is(err, domainTranslation.ErrTranslationUnavailable):
+		return c.Status(503).JSON(common.NewErrorResponse(err.Error()))
+	}
+
+	h.logger.ErrorWithFields("Failed to "+action, map[string]interface{}{
+		"error": err.Error(),
+	})
+	return c.Status(500).JSON(common.NewErrorResponse("Failed to " + action))
+}
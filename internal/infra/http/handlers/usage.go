@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"zpwoot/internal/app/common"
+	"zpwoot/internal/app/usage"
+	domainMaintenance "zpwoot/internal/domain/maintenance"
+	domainMessage "zpwoot/internal/domain/message"
+	"zpwoot/internal/domain/queue"
+	domainScheduled "zpwoot/internal/domain/scheduled"
+	"zpwoot/internal/domain/sendguard"
+	domainUsage "zpwoot/internal/domain/usage"
+	"zpwoot/internal/domain/warmup"
+	"zpwoot/platform/logger"
+)
+
+type UsageHandler struct {
+	logger  *logger.Logger
+	usageUC usage.UseCase
+}
+
+func NewUsageHandler(appLogger *logger.Logger, usageUC usage.UseCase) *UsageHandler {
+	return &UsageHandler{
+		logger:  appLogger,
+		usageUC: usageUC,
+	}
+}
+
+// @Summary Get workspace usage
+// @Description Get the current day and month usage of a workspace against its quotas
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Param workspace query string false "Workspace" default(default)
+// @Success 200 {object} common.SuccessResponse{data=usage.UsageResponse} "Usage retrieved successfully"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /admin/usage [get]
+func (h *UsageHandler) GetUsage(c *fiber.Ctx) error {
+	response, err := h.usageUC.GetUsage(c.Context(), c.Query("workspace"))
+	if err != nil {
+		h.logger.ErrorWithFields("Failed to get usage", map[string]interface{}{
+			"workspace": c.Query("workspace"),
+			"error":     err.Error(),
+		})
+		return c.Status(500).JSON(common.NewErrorResponse("Failed to get usage"))
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "Usage retrieved successfully"))
+}
+
+// @Summary List usage counters
+// @Description List the stored usage counters for billing, filtered by workspace, metric and period range.
+// @Description Message counters are daily (YYYY-MM-DD) and media counters monthly (YYYY-MM).
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Param workspace query string false "Workspace"
+// @Param metric query string false "Metric (messages, media_bytes)"
+// @Param from query string false "First period" example("2024-01-01")
+// @Param to query string false "Last period" example("2024-01-31")
+// @Success 200 {object} common.SuccessResponse{data=usage.ListUsageResponse} "Usage counters retrieved successfully"
+// @Failure 400 {object} object "Invalid query parameters"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /admin/usage/counters [get]
+func (h *UsageHandler) ListCounters(c *fiber.Ctx) error {
+	var req usage.ListUsageRequest
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid query parameters"))
+	}
+
+	response, err := h.usageUC.ListUsage(c.Context(), &req)
+	if err != nil {
+		h.logger.ErrorWithFields("Failed to list usage counters", map[string]interface{}{
+			"workspace": req.Workspace,
+			"error":     err.Error(),
+		})
+		return c.Status(500).JSON(common.NewErrorResponse("Failed to list usage counters"))
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "Usage counters retrieved successfully"))
+}
+
+// quotaErrorStatus maps a workspace quota error to its HTTP status and error code.
+// Daily message quotas and warm-up caps reset on their own (429); media and session quotas need a
+// plan change (402). Sends the send guard blocks are forbidden (403). Media that is too large (413),
+// invalid or cannot be downloaded (400) is rejected with it, as those sends fail before anything is sent.
+// Sends refused during maintenance are unavailable (503).
+func quotaErrorStatus(err error) (int, string, bool) {
+	switch {
+	case errors.Is(err, domainUsage.ErrMessageQuotaExceeded):
+		return fiber.StatusTooManyRequests, "MESSAGE_QUOTA_EXCEEDED", true
+	case errors.Is(err, domainUsage.ErrMediaQuotaExceeded):
+		return fiber.StatusPaymentRequired, "MEDIA_QUOTA_EXCEEDED", true
+	case errors.Is(err, domainUsage.ErrSessionQuotaExceeded):
+		return fiber.StatusPaymentRequired, "SESSION_QUOTA_EXCEEDED", true
+	case errors.Is(err, warmup.ErrDailyCapReached):
+		return fiber.StatusTooManyRequests, "WARMUP_CAP_REACHED", true
+	case errors.Is(err, queue.ErrQueueFull):
+		return fiber.StatusTooManyRequests, "OFFLINE_QUEUE_FULL", true
+	case errors.Is(err, sendguard.ErrGroupSendBlocked):
+		return fiber.StatusForbidden, "GROUP_SEND_BLOCKED", true
+	case errors.Is(err, sendguard.ErrSandboxRecipientBlocked):
+		return fiber.StatusForbidden, "SANDBOX_RECIPIENT_BLOCKED", true
+	case errors.Is(err, domainMessage.ErrMediaTooLarge):
+		return fiber.StatusRequestEntityTooLarge, "MEDIA_TOO_LARGE", true
+	case errors.Is(err, domainMessage.ErrInvalidMedia):
+		return fiber.StatusBadRequest, "INVALID_MEDIA", true
+	case errors.Is(err, domainMessage.ErrMediaDownloadFailed):
+		return fiber.StatusBadRequest, "MEDIA_DOWNLOAD_FAILED", true
+	case errors.Is(err, domainMessage.ErrMentionAllNotGroup):
+		return fiber.StatusBadRequest, "MENTION_ALL_NOT_GROUP", true
+	case errors.Is(err, domainMessage.ErrMentionAllRequiresAdmin):
+		return fiber.StatusForbidden, "MENTION_ALL_REQUIRES_ADMIN", true
+	case errors.Is(err, domainMaintenance.ErrSendFrozen):
+		return fiber.StatusServiceUnavailable, "MAINTENANCE", true
+	case errors.Is(err, domainScheduled.ErrScheduleInPast), errors.Is(err, domainScheduled.ErrScheduleTooFar):
+		return fiber.StatusBadRequest, "INVALID_SCHEDULE", true
+	}
+	return 0, "", false
+}
+
+// writeQuotaError writes the response of a workspace quota error and reports whether err was one
+func writeQuotaError(c *fiber.Ctx, err error) (bool, error) {
+	status, code, ok := quotaErrorStatus(err)
+	if !ok {
+		return false, nil
+	}
+	return true, c.Status(status).JSON(common.NewErrorResponseWithCode(err.Error(), code))
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"zpwoot/internal/app/common"
+	"zpwoot/internal/app/warmup"
+	"zpwoot/internal/domain/session"
+	domainWarmup "zpwoot/internal/domain/warmup"
+	"zpwoot/internal/infra/http/helpers"
+	"zpwoot/platform/logger"
+)
+
+type WarmupHandler struct {
+	logger          *logger.Logger
+	warmupUC        warmup.UseCase
+	sessionResolver *helpers.SessionResolver
+}
+
+func NewWarmupHandler(appLogger *logger.Logger, warmupUC warmup.UseCase, sessionRepo helpers.SessionRepository) *WarmupHandler {
+	return &WarmupHandler{
+		logger:          appLogger,
+		warmupUC:        warmupUC,
+		sessionResolver: helpers.NewSessionResolver(appLogger, sessionRepo),
+	}
+}
+
+// @Summary Get warm-up status
+// @Description Get the warm-up of a session: the current day, today's send cap and how many sends are left. Sessions without a warm-up report it as disabled
+// @Tags Warm-up
+// @Security ApiKeyAuth
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Success 200 {object} common.SuccessResponse{data=warmup.WarmupStatusResponse} "Warm-up status retrieved successfully"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/warmup [get]
+func (h *WarmupHandler) GetStatus(c *fiber.Ctx) error {
+	sess, fiberErr := h.resolveSession(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	response, err := h.warmupUC.GetStatus(c.Context(), sess.ID.String())
+	if err != nil {
+		return h.handleError(c, "get warm-up status", err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "Warm-up status retrieved successfully"))
+}
+
+// @Summary Update warm-up
+// @Description Update the fields that are set on the warm-up of a session. While warming up, the session sends at most the cap of the current schedule day, further sends fail with 429 WARMUP_CAP_REACHED, and consecutive sends are spaced by a random delay between minDelaySeconds and maxDelaySeconds
+// @Tags Warm-up
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID or Name" example("mySession")
+// @Param request body warmup.UpdateWarmupRequest true "Fields to update"
+// @Success 200 {object} common.SuccessResponse{data=warmup.WarmupStatusResponse} "Warm-up updated successfully"
+// @Failure 400 {object} object "Bad Request"
+// @Failure 404 {object} object "Session not found"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/warmup [put]
+func (h *WarmupHandler) UpdateWarmup(c *fiber.Ctx) error {
+	sess, fiberErr := h.resolveSession(c)
+	if fiberErr != nil {
+		return c.Status(fiberErr.Code).JSON(common.NewErrorResponse(fiberErr.Message))
+	}
+
+	var req warmup.UpdateWarmupRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid request body"))
+	}
+
+	response, err := h.warmupUC.UpdateWarmup(c.Context(), sess.ID.String(), &req)
+	if err != nil {
+		return h.handleError(c, "update warm-up", err)
+	}
+
+	return c.JSON(common.NewSuccessResponse(response, "Warm-up updated successfully"))
+}
+
+// resolveSession resolves the session from the sessionId path parameter
+func (h *WarmupHandler) resolveSession(c *fiber.Ctx) (*session.Session, *fiber.Error) {
+	sessionIdentifier := c.Params("sessionId")
+	if sessionIdentifier == "" {
+		return nil, fiber.NewError(400, "Session identifier is required")
+	}
+
+	sess, err := h.sessionResolver.ResolveSession(c.Context(), sessionIdentifier)
+	if err != nil {
+		return nil, fiber.NewError(404, "Session not found")
+	}
+
+	return sess, nil
+}
+
+// handleError maps warm-up domain errors to HTTP responses
+func (h *WarmupHandler) handleError(c *fiber.Ctx, action string, err error) error {
+	if errors.Is(err, domainWarmup.ErrInvalidSchedule) || errors.Is(err, domainWarmup.ErrInvalidDelay) {
+		return c.Status(400).JSON(common.NewErrorResponse(err.Error()))
+	}
+
+	h.logger.ErrorWithFields("Failed to "+action, map[string]interface{}{
+		"error": err.Error(),
+	})
+	return c.Status(500).JSON(common.NewErrorResponse("Failed to " + action))
+}
@@ -1,12 +1,17 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"zpwoot/internal/app/common"
 	"zpwoot/internal/app/webhook"
 	domainWebhook "zpwoot/internal/domain/webhook"
+	"zpwoot/internal/infra/http/helpers"
 	"zpwoot/platform/logger"
+	"zpwoot/platform/precondition"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -25,16 +30,18 @@ func NewWebhookHandler(webhookUC webhook.UseCase, appLogger *logger.Logger) *Web
 }
 
 // @Summary Set webhook configuration
-// @Description Create or update webhook configuration for a WhatsApp session. Set enabled=true to activate, enabled=false to disable without deleting. If enabled is not provided, defaults to true.
+// @Description Create or update webhook configuration for a WhatsApp session. Set enabled=true to activate, enabled=false to disable without deleting. If enabled is not provided, defaults to true. An optional payloadTemplate reshapes the delivered JSON body; check it with POST /sessions/{sessionId}/webhook/template/preview. With shadow=true the session's shadow webhook is set instead: it receives a copy of the events next to the regular webhook, and its failed deliveries are neither retried nor reported, so a new consumer can be tried against live traffic. Events may be wildcard patterns matched against the dotted event name (message.* matches Message, group.* matches GroupInfo, call.* every Call event), and chats limits the events about a chat to the listed chat JIDs or patterns such as *@g.us.
 // @Tags Webhooks
 // @Security ApiKeyAuth
 // @Accept json
 // @Produce json
 // @Param sessionId path string true "Session ID" format(uuid)
 // @Param request body webhook.SetConfigRequest true "Webhook configuration request"
+// @Param If-Match header string false "ETag from GET /sessions/{sessionId}/webhook/find; the update fails with 412 when the webhook changed since. Ignored with shadow=true"
 // @Success 201 {object} webhook.SetConfigResponse "Webhook configuration created/updated successfully"
 // @Failure 400 {object} object "Bad Request - Invalid session ID, URL, or event types"
 // @Failure 404 {object} object "Session not found"
+// @Failure 412 {object} object "Webhook changed since the ETag in If-Match"
 // @Failure 500 {object} object "Internal Server Error"
 // @Router /sessions/{sessionId}/webhook/set [post]
 func (h *WebhookHandler) SetConfig(c *fiber.Ctx) error {
@@ -65,9 +72,33 @@ func (h *WebhookHandler) SetConfig(c *fiber.Ctx) error {
 
 	req.SessionID = &sessionID
 
-	ctx := c.Context()
+	var ctx context.Context = c.Context()
+
+	// The shadow webhook has no GET of its own, so If-Match only guards the regular webhook
+	if !req.Shadow {
+		var ok bool
+		var err error
+		ctx, ok, err = helpers.CheckIfMatch(c, func() (interface{}, time.Time, error) {
+			config, err := h.webhookUC.FindConfig(ctx, sessionID)
+			if err != nil {
+				return nil, time.Time{}, err
+			}
+			return config, config.UpdatedAt, nil
+		})
+		if !ok {
+			return err
+		}
+	}
+
 	result, err := h.webhookUC.SetConfig(ctx, &req)
 	if err != nil {
+		if errors.Is(err, precondition.ErrFailed) {
+			return helpers.RespondPreconditionFailed(c)
+		}
+		if errors.Is(err, domainWebhook.ErrInvalidPayloadTemplate) || errors.Is(err, domainWebhook.ErrInvalidWebhookTimeout) ||
+			errors.Is(err, domainWebhook.ErrInvalidChatFilter) {
+			return c.Status(400).JSON(common.NewErrorResponse(err.Error()))
+		}
 		h.logger.Error("Failed to create webhook: " + err.Error())
 		return c.Status(500).JSON(common.NewErrorResponse("Failed to create webhook"))
 	}
@@ -82,7 +113,9 @@ func (h *WebhookHandler) SetConfig(c *fiber.Ctx) error {
 // @Security ApiKeyAuth
 // @Produce json
 // @Param sessionId path string true "Session ID" format(uuid)
+// @Param If-None-Match header string false "ETag of a previous response; answered with 304 while the webhook is unchanged"
 // @Success 200 {object} webhook.WebhookResponse "Webhook configuration retrieved successfully"
+// @Success 304 "Webhook unchanged since the ETag in If-None-Match"
 // @Failure 400 {object} object "Bad Request - Invalid session ID format"
 // @Failure 404 {object} object "Webhook not found for this session"
 // @Failure 500 {object} object "Internal Server Error"
@@ -105,8 +138,7 @@ func (h *WebhookHandler) FindConfig(c *fiber.Ctx) error {
 		return c.Status(500).JSON(common.NewErrorResponse("Failed to get webhook configuration"))
 	}
 
-	response := common.NewSuccessResponse(webhook, "Webhook configuration retrieved successfully")
-	return c.JSON(response)
+	return helpers.RespondWithETag(c, webhook, "Webhook configuration retrieved successfully")
 }
 
 // @Summary Test webhook
@@ -168,6 +200,55 @@ func (h *WebhookHandler) TestWebhook(c *fiber.Ctx) error {
 	return c.JSON(response)
 }
 
+// @Summary Preview webhook payload template
+// @Description Render a payload template against a sample event the way delivery would, without sending anything. Templates use Go text/template syntax over the default payload fields (.id, .event, .sessionId, .timestamp, .data, .routing, .translation) with the helpers json, default, lower, upper, trim and replace; use json to quote values, e.g. {"type": {{json .event}}, "text": {{json .data.text}}}. Without a template, the template of the session's webhook is rendered
+// @Tags Webhooks
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID" format(uuid)
+// @Param request body webhook.PreviewPayloadTemplateRequest true "Template and sample event"
+// @Success 200 {object} common.SuccessResponse{data=webhook.PreviewPayloadTemplateResponse} "Payload template rendered successfully"
+// @Failure 400 {object} object "Bad Request - Invalid template, event type, or rendered JSON"
+// @Failure 404 {object} object "Webhook not found for this session"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/webhook/template/preview [post]
+func (h *WebhookHandler) PreviewPayloadTemplate(c *fiber.Ctx) error {
+	sessionID := c.Params("sessionId")
+
+	// Validate session ID format
+	if _, err := uuid.Parse(sessionID); err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid session ID format"))
+	}
+
+	var req webhook.PreviewPayloadTemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid request body"))
+	}
+
+	if req.EventType == "" {
+		return c.Status(400).JSON(common.NewErrorResponse("Event type is required"))
+	}
+
+	if !domainWebhook.IsValidEventType(req.EventType) {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid event type: " + req.EventType))
+	}
+
+	result, err := h.webhookUC.PreviewPayloadTemplate(c.Context(), sessionID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, domainWebhook.ErrInvalidPayloadTemplate):
+			return c.Status(400).JSON(common.NewErrorResponse(err.Error()))
+		case errors.Is(err, domainWebhook.ErrWebhookNotFound):
+			return c.Status(404).JSON(common.NewErrorResponse("Webhook not found for this session"))
+		}
+		h.logger.Error("Failed to preview payload template: " + err.Error())
+		return c.Status(500).JSON(common.NewErrorResponse("Failed to preview payload template"))
+	}
+
+	return c.JSON(common.NewSuccessResponse(result, "Payload template rendered successfully"))
+}
+
 // @Summary Get supported webhook events
 // @Description Get list of all supported webhook event types that can be subscribed to
 // @Tags Webhooks
@@ -188,3 +269,147 @@ func (h *WebhookHandler) GetSupportedEvents(c *fiber.Ctx) error {
 	response := common.NewSuccessResponse(result, "Supported events retrieved successfully")
 	return c.JSON(response)
 }
+
+// @Summary Replay webhook events
+// @Description Dispatch the stored events of a session timestamped from "from" up to "to" through the webhook pipeline again, oldest first, optionally of the given event types only. Replayed events keep their id, carry "replay": true and the X-Webhook-Replay header, and skip routing and processors such as Chatwoot. Events are only stored while EVENT_STORE_ENABLED is set and expire after EVENT_STORE_RETENTION. When hasMore is returned, call again from nextFrom
+// @Tags Webhooks
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID" format(uuid)
+// @Param request body webhook.ReplayEventsRequest true "Time range and event types to replay"
+// @Success 200 {object} common.SuccessResponse{data=webhook.ReplayEventsResponse} "Events replayed successfully"
+// @Failure 400 {object} object "Bad Request - Invalid time range or event types"
+// @Failure 409 {object} object "Event store is disabled"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /sessions/{sessionId}/events/replay [post]
+func (h *WebhookHandler) ReplayEvents(c *fiber.Ctx) error {
+	sessionID := c.Params("sessionId")
+
+	// Validate session ID format
+	if _, err := uuid.Parse(sessionID); err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid session ID format"))
+	}
+
+	var req webhook.ReplayEventsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid request body"))
+	}
+
+	if invalidEvents := domainWebhook.ValidateEvents(req.EventTypes); len(invalidEvents) > 0 {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid event types: " + fmt.Sprintf("%v", invalidEvents)))
+	}
+
+	result, err := h.webhookUC.ReplayEvents(c.Context(), sessionID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, domainWebhook.ErrInvalidReplayRange):
+			return c.Status(400).JSON(common.NewErrorResponse(err.Error()))
+		case errors.Is(err, domainWebhook.ErrEventStoreDisabled):
+			return c.Status(409).JSON(common.NewErrorResponse("Event store is disabled, set EVENT_STORE_ENABLED to keep events for replays"))
+		}
+		h.logger.Error("Failed to replay webhook events: " + err.Error())
+		return c.Status(500).JSON(common.NewErrorResponse("Failed to replay webhook events"))
+	}
+
+	return c.JSON(common.NewSuccessResponse(result, "Events replayed successfully"))
+}
+
+// @Summary Get webhook dispatch status
+// @Description Get the webhook delivery concurrency, the queue and which sessions have their dispatch paused
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} common.SuccessResponse{data=webhook.DispatchStatusResponse} "Dispatch status retrieved successfully"
+// @Router /admin/webhooks/dispatch [get]
+func (h *WebhookHandler) GetDispatchStatus(c *fiber.Ctx) error {
+	return c.JSON(common.NewSuccessResponse(h.webhookUC.GetDispatchStatus(c.Context()), "Dispatch status retrieved successfully"))
+}
+
+// @Summary Update webhook dispatch
+// @Description Change the webhook delivery concurrency and pause or resume dispatch for every session without a restart.
+// @Description Paused deliveries are held in memory and sent once dispatch resumes; they are lost on restart.
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param request body webhook.UpdateDispatchRequest true "Dispatch settings"
+// @Success 200 {object} common.SuccessResponse{data=webhook.DispatchStatusResponse} "Dispatch updated successfully"
+// @Failure 400 {object} object "Invalid request body or worker count"
+// @Router /admin/webhooks/dispatch [put]
+func (h *WebhookHandler) UpdateDispatch(c *fiber.Ctx) error {
+	var req webhook.UpdateDispatchRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid request body"))
+	}
+
+	result, err := h.webhookUC.UpdateDispatch(c.Context(), &req)
+	if err != nil {
+		if errors.Is(err, domainWebhook.ErrInvalidDispatchWorkers) {
+			return c.Status(400).JSON(common.NewErrorResponse(err.Error()))
+		}
+		h.logger.Error("Failed to update webhook dispatch: " + err.Error())
+		return c.Status(500).JSON(common.NewErrorResponse("Failed to update webhook dispatch"))
+	}
+
+	return c.JSON(common.NewSuccessResponse(result, "Dispatch updated successfully"))
+}
+
+// @Summary Pause or resume session dispatch
+// @Description Hold back the webhook deliveries of a session, e.g. while its consumer misbehaves, or send them again
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param sessionId path string true "Session ID" format(uuid)
+// @Param request body webhook.SetSessionDispatchRequest true "Whether dispatch is paused"
+// @Success 200 {object} common.SuccessResponse{data=webhook.DispatchStatusResponse} "Session dispatch updated successfully"
+// @Failure 400 {object} object "Invalid session ID or request body"
+// @Router /admin/webhooks/dispatch/sessions/{sessionId} [put]
+func (h *WebhookHandler) SetSessionDispatch(c *fiber.Ctx) error {
+	sessionID := c.Params("sessionId")
+	if _, err := uuid.Parse(sessionID); err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid session ID format"))
+	}
+
+	var req webhook.SetSessionDispatchRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid request body"))
+	}
+
+	return c.JSON(common.NewSuccessResponse(h.webhookUC.SetSessionDispatch(c.Context(), sessionID, &req), "Session dispatch updated successfully"))
+}
+
+// @Summary Drain session dispatch
+// @Description Drop the queued and held back webhook deliveries of a session whose dispatch is paused, so resuming
+// @Description does not flood its consumer. The events stay in the event store: replay replayFrom to replayTo through
+// @Description POST /sessions/{sessionId}/events/replay once the consumer is fixed. Requires EVENT_STORE_ENABLED.
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Param sessionId path string true "Session ID" format(uuid)
+// @Success 200 {object} common.SuccessResponse{data=webhook.DrainDispatchResponse} "Session dispatch drained successfully"
+// @Failure 400 {object} object "Invalid session ID"
+// @Failure 409 {object} object "Dispatch of the session is not paused or the event store is disabled"
+// @Failure 500 {object} object "Internal Server Error"
+// @Router /admin/webhooks/dispatch/sessions/{sessionId}/drain [post]
+func (h *WebhookHandler) DrainSessionDispatch(c *fiber.Ctx) error {
+	sessionID := c.Params("sessionId")
+	if _, err := uuid.Parse(sessionID); err != nil {
+		return c.Status(400).JSON(common.NewErrorResponse("Invalid session ID format"))
+	}
+
+	result, err := h.webhookUC.DrainSessionDispatch(c.Context(), sessionID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domainWebhook.ErrSessionDispatchNotPaused):
+			return c.Status(409).JSON(common.NewErrorResponse("Pause the session's dispatch before draining it"))
+		case errors.Is(err, domainWebhook.ErrEventStoreDisabled):
+			return c.Status(409).JSON(common.NewErrorResponse("Event store is disabled, set EVENT_STORE_ENABLED to keep drained events for replays"))
+		}
+		h.logger.Error("Failed to drain webhook dispatch: " + err.Error())
+		return c.Status(500).JSON(common.NewErrorResponse("Failed to drain webhook dispatch"))
+	}
+
+	return c.JSON(common.NewSuccessResponse(result, "Session dispatch drained successfully"))
+}
@@ -0,0 +1,86 @@
+package helpers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"zpwoot/internal/app/common"
+	"zpwoot/platform/precondition"
+)
+
+// ETag returns the entity tag of a resource: a hash of its JSON representation, so it changes whenever
+// any field of the response data does
+func ETag(data interface{}) (string, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(encoded)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`, nil
+}
+
+// RespondWithETag answers a GET with data and its ETag, or with 304 Not Modified when If-None-Match
+// already names that ETag
+func RespondWithETag(c *fiber.Ctx, data interface{}, message string) error {
+	etag, err := ETag(data)
+	if err != nil {
+		return c.JSON(common.NewSuccessResponse(data, message))
+	}
+
+	c.Set(fiber.HeaderETag, etag)
+	if matchesETag(c.Get(fiber.HeaderIfNoneMatch), etag) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+	return c.JSON(common.NewSuccessResponse(data, message))
+}
+
+// CheckIfMatch enforces the If-Match header of an update so concurrent clients don't overwrite each
+// other's changes. load returns the resource as the matching GET returns it along with its updatedAt,
+// and is only called when the header is set; a load error counts as the resource not existing, which
+// only If-Match: * rejects. It returns false after answering 412 Precondition Failed when the resource
+// changed since it was read. Otherwise it returns the context to update with: under If-Match it carries
+// the updatedAt that matched, so the write itself fails with precondition.ErrFailed when another change
+// lands between this check and the update.
+func CheckIfMatch(c *fiber.Ctx, load func() (interface{}, time.Time, error)) (context.Context, bool, error) {
+	ifMatch := c.Get(fiber.HeaderIfMatch)
+	if ifMatch == "" {
+		return c.Context(), true, nil
+	}
+
+	current, version, err := load()
+	if err == nil && current != nil {
+		if etag, err := ETag(current); err == nil && matchesETag(ifMatch, etag) {
+			return precondition.WithVersion(c.Context(), version), true, nil
+		}
+	}
+
+	return nil, false, RespondPreconditionFailed(c)
+}
+
+// RespondPreconditionFailed answers 412 Precondition Failed, for an If-Match check or a conditional
+// update that found the resource changed
+func RespondPreconditionFailed(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusPreconditionFailed).JSON(common.NewErrorResponseWithCode(
+		"Resource was modified since it was read; fetch it again and retry", "PRECONDITION_FAILED"))
+}
+
+// matchesETag reports whether an If-Match or If-None-Match header names the ETag; weak tags compare
+// by their value
+func matchesETag(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
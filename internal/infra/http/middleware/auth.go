@@ -1,50 +1,164 @@
 package middleware
 
 import (
+	"container/list"
+	"crypto/subtle"
+	"errors"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"zpwoot/internal/app"
+	"zpwoot/internal/app/common"
+	domainAPIKey "zpwoot/internal/domain/apikey"
+	"zpwoot/internal/domain/history"
+	"zpwoot/internal/domain/usage"
 	"zpwoot/platform/config"
 	"zpwoot/platform/logger"
 )
 
-func APIKeyAuth(cfg *config.Config, logger *logger.Logger) fiber.Handler {
+// APIKeyAuth checks the API key of every request. Sources failing the check cfg.AuthMaxFailures times
+// within cfg.AuthFailureWindow, by IP or by key, are locked out for cfg.AuthLockoutDuration; the
+// lockout is logged and sent to dispatcher as an auth.lockout event.
+//
+// The global key and the compatibility keys (ZP_COMPAT_API_KEYS) have full access. Keys managed under
+// /admin/apikeys are accepted within their scopes and sessions; /admin itself always requires a key
+// with full access.
+func APIKeyAuth(cfg *config.Config, container *app.Container, logger *logger.Logger, dispatcher AuthEventDispatcher) fiber.Handler {
+	var lockout *authLockout
+	if cfg.AuthMaxFailures > 0 {
+		lockout = &authLockout{
+			maxFailures: cfg.AuthMaxFailures,
+			window:      cfg.AuthFailureWindow,
+			duration:    cfg.AuthLockoutDuration,
+			logger:      logger,
+			dispatcher:  dispatcher,
+
+			trustedProxies: parseTrustedProxies(cfg.TrustedProxies),
+			proxyHeader:    cfg.ProxyHeader,
+
+			sources: make(map[string]*list.Element),
+			recent:  list.New(),
+		}
+	}
+
 	return func(c *fiber.Ctx) error {
 		path := c.Path()
 		if strings.HasPrefix(path, "/health") || strings.HasPrefix(path, "/swagger") || strings.Contains(path, "/chatwoot/webhook") {
 			return c.Next()
 		}
 
+		// The dashboard page itself is static; its data calls carry the API key
+		if path == "/dashboard" || strings.HasPrefix(path, "/dashboard/") {
+			return c.Next()
+		}
+
+		// Pairing pages are authorized by their signed link token
+		if strings.HasPrefix(path, "/pair/") {
+			return c.Next()
+		}
+
+		// Debug routes are authorized by the admin key
+		if strings.HasPrefix(path, "/debug/") {
+			return c.Next()
+		}
+
 		apiKey := c.Get("Authorization")
 		if apiKey == "" {
 			apiKey = c.Get("X-API-Key")
 		}
+		if apiKey == "" {
+			// Header used by Evolution API clients
+			apiKey = c.Get("apikey")
+		}
+
+		clientIP := c.IP()
+		if lockout != nil {
+			clientIP = lockout.clientIP(c)
+		}
+		ipSource := authIPSource(clientIP)
+		sources := []string{ipSource}
+		if apiKey != "" {
+			sources = append(sources, authKeySource(apiKey))
+		}
+
+		if lockout != nil {
+			if until := lockout.lockedUntil(sources...); !until.IsZero() {
+				c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(time.Until(until).Seconds())+1))
+				return c.Status(429).JSON(common.NewErrorResponseWithCode("Too many failed authentication attempts, try again later", "AUTH_LOCKED_OUT"))
+			}
+		}
 
 		if apiKey == "" {
+			if lockout != nil {
+				lockout.recordFailure(clientIP, ipSource)
+			}
 			logger.WarnWithFields("Missing API key", map[string]interface{}{
 				"path":   path,
 				"method": c.Method(),
 				"ip":     c.IP(),
 			})
-			return c.Status(401).JSON(fiber.Map{
-				"error":   "Unauthorized",
-				"message": "API key is required. Provide it via Authorization header or X-API-Key header",
-				"code":    "MISSING_API_KEY",
-			})
+			return c.Status(401).JSON(common.NewErrorResponseWithCode("API key is required. Provide it via Authorization header or X-API-Key header", "MISSING_API_KEY"))
 		}
 
-		if apiKey != cfg.GlobalAPIKey {
-			logger.WarnWithFields("Invalid API key", map[string]interface{}{
-				"path":    path,
-				"method":  c.Method(),
-				"ip":      c.IP(),
-				"api_key": maskAPIKey(apiKey),
-			})
-			return c.Status(401).JSON(fiber.Map{
-				"error":   "Unauthorized",
-				"message": "Invalid API key",
-				"code":    "INVALID_API_KEY",
-			})
+		var storedKey *domainAPIKey.APIKey
+		if subtle.ConstantTimeCompare([]byte(apiKey), []byte(cfg.GlobalAPIKey)) != 1 && !cfg.IsCompatAPIKey(apiKey) {
+			key, err := container.GetAPIKeyUseCase().Authenticate(c.Context(), apiKey)
+			if err != nil {
+				if lockout != nil {
+					lockout.recordFailure(clientIP, sources...)
+				}
+				logger.WarnWithFields("Invalid API key", map[string]interface{}{
+					"path":    path,
+					"method":  c.Method(),
+					"ip":      c.IP(),
+					"api_key": maskAPIKey(apiKey),
+					"reason":  err.Error(),
+				})
+				switch {
+				case errors.Is(err, domainAPIKey.ErrAPIKeyRevoked):
+					return c.Status(401).JSON(common.NewErrorResponseWithCode(err.Error(), "API_KEY_REVOKED"))
+				case errors.Is(err, domainAPIKey.ErrAPIKeyExpired):
+					return c.Status(401).JSON(common.NewErrorResponseWithCode(err.Error(), "API_KEY_EXPIRED"))
+				}
+				return c.Status(401).JSON(common.NewErrorResponseWithCode("Invalid API key", "INVALID_API_KEY"))
+			}
+			storedKey = key
+		}
+
+		if lockout != nil {
+			lockout.recordSuccess(ipSource)
+		}
+
+		if storedKey != nil {
+			if err := authorizeStoredKey(c, container, storedKey); err != nil {
+				logger.WarnWithFields("API key denied", map[string]interface{}{
+					"path":       path,
+					"method":     c.Method(),
+					"ip":         c.IP(),
+					"api_key_id": storedKey.ID.String(),
+					"reason":     err.Error(),
+				})
+				switch {
+				case errors.Is(err, domainAPIKey.ErrGlobalKeyNeeded):
+					return c.Status(403).JSON(common.NewErrorResponseWithCode(err.Error(), "GLOBAL_KEY_REQUIRED"))
+				case errors.Is(err, domainAPIKey.ErrScopeDenied):
+					return c.Status(403).JSON(common.NewErrorResponseWithCode(err.Error(), "SCOPE_DENIED"))
+				case errors.Is(err, domainAPIKey.ErrSessionDenied):
+					return c.Status(403).JSON(common.NewErrorResponseWithCode(err.Error(), "SESSION_DENIED"))
+				}
+				logger.ErrorWithFields("Failed to authorize API key", map[string]interface{}{
+					"api_key_id": storedKey.ID.String(),
+					"error":      err.Error(),
+				})
+				return c.Status(500).JSON(common.NewErrorResponse("Failed to authorize API key"))
+			}
+
+			c.Locals("api_key_id", storedKey.ID.String())
+			if storedKey.Workspace != "" {
+				c.Locals(usage.WorkspaceContextKey, storedKey.Workspace)
+			}
 		}
 
 		logger.DebugWithFields("API key authenticated", map[string]interface{}{
@@ -56,11 +170,94 @@ func APIKeyAuth(cfg *config.Config, logger *logger.Logger) fiber.Handler {
 
 		c.Locals("api_key", apiKey)
 		c.Locals("authenticated", true)
+		// Messages sent by the request are attributed to the key, masked as in the logs
+		c.Locals(history.ActorContextKey, &history.Actor{Type: history.ActorAPI, ID: maskAPIKey(apiKey)})
 
 		return c.Next()
 	}
 }
 
+// AdminKeyAuth guards operator-only routes with the admin API key, sent in the X-Admin-Key header.
+// The regular API key is not enough; without an admin key configured the routes answer 404.
+func AdminKeyAuth(cfg *config.Config, logger *logger.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !cfg.HasAdminAPIKey() {
+			return fiber.ErrNotFound
+		}
+
+		adminKey := c.Get("X-Admin-Key")
+		if adminKey == "" {
+			return c.Status(401).JSON(common.NewErrorResponseWithCode("Admin key is required. Provide it via X-Admin-Key header", "MISSING_ADMIN_KEY"))
+		}
+
+		if subtle.ConstantTimeCompare([]byte(adminKey), []byte(cfg.AdminAPIKey)) != 1 {
+			logger.WarnWithFields("Invalid admin key", map[string]interface{}{
+				"path":      c.Path(),
+				"method":    c.Method(),
+				"ip":        c.IP(),
+				"admin_key": maskAPIKey(adminKey),
+			})
+			return c.Status(403).JSON(common.NewErrorResponseWithCode("Invalid admin key", "INVALID_ADMIN_KEY"))
+		}
+
+		logger.InfoWithFields("Admin debug access", map[string]interface{}{
+			"path":   c.Path(),
+			"method": c.Method(),
+			"ip":     c.IP(),
+		})
+
+		return c.Next()
+	}
+}
+
+// authorizeStoredKey checks that a key managed under /admin/apikeys may make the request
+func authorizeStoredKey(c *fiber.Ctx, container *app.Container, key *domainAPIKey.APIKey) error {
+	path := c.Path()
+	if path == "/admin" || strings.HasPrefix(path, "/admin/") {
+		return domainAPIKey.ErrGlobalKeyNeeded
+	}
+
+	return container.GetAPIKeyUseCase().Authorize(c.Context(), key, requiredScope(c.Method(), path), sessionFromPath(path))
+}
+
+// sessionFromPath returns the session ID or name a request path addresses, which a key restricted to
+// sessions must be allowed for: the :sessionId of /sessions/:sessionId/... or the :instance of the
+// Evolution-style /{message,chat,instance}/:action/:instance routes. It returns "" for paths outside a
+// single session, like listing or creating sessions, and for inbound Chatwoot webhooks, which
+// authenticate with their own token.
+func sessionFromPath(path string) string {
+	if strings.Contains(path, "/chatwoot/webhook") {
+		return ""
+	}
+
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	switch {
+	case len(parts) >= 3 && parts[0] == "sessions":
+		return parts[1]
+	case len(parts) == 3 && (parts[0] == "message" || parts[0] == "chat" || parts[0] == "instance"):
+		return parts[2]
+	}
+	return ""
+}
+
+// requiredScope returns the scope a request needs: reads need read-only, changes to the messages and
+// chats of a session need send, and every other change needs manage-sessions
+func requiredScope(method, path string) string {
+	switch method {
+	case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+		return domainAPIKey.ScopeReadOnly
+	}
+
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	switch {
+	case len(parts) >= 3 && parts[0] == "sessions" && (parts[2] == "messages" || parts[2] == "chats"):
+		return domainAPIKey.ScopeSend
+	case len(parts) == 3 && (parts[0] == "message" || parts[0] == "chat"):
+		return domainAPIKey.ScopeSend
+	}
+	return domainAPIKey.ScopeManageSessions
+}
+
 func maskAPIKey(apiKey string) string {
 	if len(apiKey) <= 12 {
 		return strings.Repeat("*", len(apiKey))
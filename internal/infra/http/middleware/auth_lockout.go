@@ -0,0 +1,225 @@
+package middleware
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"zpwoot/internal/domain/webhook"
+	"zpwoot/platform/logger"
+)
+
+// maxTrackedAuthSources bounds the failure records kept; past it the least recently failing source
+// is forgotten, so a spray of distinct sources cannot grow the records without bound
+const maxTrackedAuthSources = 10000
+
+// AuthEventDispatcher sends the auth.lockout alerts to the global webhooks
+type AuthEventDispatcher interface {
+	DeliverEvent(ctx context.Context, event *webhook.WebhookEvent) error
+}
+
+// authLockout counts failed API key checks per source IP and per presented key and locks a source out
+// once it fails too often within the window
+type authLockout struct {
+	maxFailures int
+	window      time.Duration
+	duration    time.Duration
+	logger      *logger.Logger
+	dispatcher  AuthEventDispatcher
+
+	// trustedProxies may send the client IP in proxyHeader
+	trustedProxies []*net.IPNet
+	proxyHeader    string
+
+	mu      sync.Mutex
+	sources map[string]*list.Element
+	// recent orders the sources from the most to the least recently failing
+	recent *list.List
+}
+
+type authFailures struct {
+	source      string
+	count       int
+	windowStart time.Time
+	lockedUntil time.Time
+}
+
+// lockedUntil returns when the lockout of the first locked out source ends, or the zero time when
+// none is locked out
+func (l *authLockout) lockedUntil(sources ...string) time.Time {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, source := range sources {
+		if element, exists := l.sources[source]; exists {
+			if failures := element.Value.(*authFailures); now.Before(failures.lockedUntil) {
+				return failures.lockedUntil
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// recordFailure counts a failed check against each source and alerts on the ones it locks out
+func (l *authLockout) recordFailure(ip string, sources ...string) {
+	now := time.Now()
+	var locked []string
+	var lockedUntil time.Time
+	var failureCount int
+
+	l.mu.Lock()
+	for _, source := range sources {
+		failures := l.touchLocked(source, now)
+		if now.Sub(failures.windowStart) > l.window {
+			failures.count = 0
+			failures.windowStart = now
+		}
+		failures.count++
+		if failures.count >= l.maxFailures && !now.Before(failures.lockedUntil) {
+			failures.lockedUntil = now.Add(l.duration)
+			failures.count = 0
+			failures.windowStart = now
+			locked = append(locked, source)
+			lockedUntil = failures.lockedUntil
+			failureCount = l.maxFailures
+		}
+	}
+	l.mu.Unlock()
+
+	for _, source := range locked {
+		l.alert(source, ip, failureCount, lockedUntil)
+	}
+}
+
+// recordSuccess clears the failures of the source IP
+func (l *authLockout) recordSuccess(source string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if element, exists := l.sources[source]; exists && !time.Now().Before(element.Value.(*authFailures).lockedUntil) {
+		l.recent.Remove(element)
+		delete(l.sources, source)
+	}
+}
+
+// touchLocked returns the record of a source, created if needed, and marks it the most recently
+// failing one; at the cap the least recently failing record is evicted. The caller holds the lock.
+func (l *authLockout) touchLocked(source string, now time.Time) *authFailures {
+	if element, exists := l.sources[source]; exists {
+		l.recent.MoveToFront(element)
+		return element.Value.(*authFailures)
+	}
+
+	if len(l.sources) >= maxTrackedAuthSources {
+		if oldest := l.recent.Back(); oldest != nil {
+			l.recent.Remove(oldest)
+			delete(l.sources, oldest.Value.(*authFailures).source)
+		}
+	}
+
+	failures := &authFailures{source: source, windowStart: now}
+	l.sources[source] = l.recent.PushFront(failures)
+	return failures
+}
+
+// clientIP returns the IP failures are counted against. Behind a trusted proxy it is the rightmost
+// address of the proxy header that is not a trusted proxy; the addresses left of it come from the
+// client and can be forged.
+func (l *authLockout) clientIP(c *fiber.Ctx) string {
+	if len(l.trustedProxies) == 0 || !c.IsProxyTrusted() {
+		return c.IP()
+	}
+
+	hops := strings.Split(c.Get(l.proxyHeader), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		ip := net.ParseIP(strings.TrimSpace(hops[i]))
+		if ip == nil {
+			break
+		}
+		if !l.isTrustedProxy(ip) {
+			return ip.String()
+		}
+	}
+	return c.Context().RemoteIP().String()
+}
+
+func (l *authLockout) isTrustedProxy(ip net.IP) bool {
+	for _, network := range l.trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTrustedProxies parses proxy IPs and CIDRs; invalid entries are skipped
+func parseTrustedProxies(proxies []string) []*net.IPNet {
+	var networks []*net.IPNet
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			if ip := net.ParseIP(proxy); ip != nil {
+				bits := 8 * len(ip.To16())
+				if ip.To4() != nil {
+					ip, bits = ip.To4(), 32
+				}
+				networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			}
+			continue
+		}
+		if _, network, err := net.ParseCIDR(proxy); err == nil {
+			networks = append(networks, network)
+		}
+	}
+	return networks
+}
+
+// alert logs the lockout and sends it to the global webhooks subscribed to auth.lockout
+func (l *authLockout) alert(source, ip string, failures int, lockedUntil time.Time) {
+	l.logger.ErrorWithFields("Authentication locked out after repeated failures", map[string]interface{}{
+		"source":       source,
+		"ip":           ip,
+		"failures":     failures,
+		"window":       l.window.String(),
+		"locked_until": lockedUntil,
+	})
+
+	if l.dispatcher == nil {
+		return
+	}
+
+	event := webhook.NewWebhookEvent("", "auth.lockout", map[string]interface{}{
+		"source":      source,
+		"ip":          ip,
+		"failures":    failures,
+		"window":      l.window.String(),
+		"lockedUntil": lockedUntil,
+	})
+	go func() {
+		if err := l.dispatcher.DeliverEvent(context.Background(), event); err != nil {
+			l.logger.WarnWithFields("Failed to send auth lockout alert", map[string]interface{}{
+				"source": source,
+				"error":  err.Error(),
+			})
+		}
+	}()
+}
+
+// authIPSource and authKeySource name the sources failures are counted against
+func authIPSource(ip string) string {
+	return "ip:" + ip
+}
+
+// authKeySource identifies a key by a hash of the whole key. Stored keys share their prefix, so
+// counting failures per prefix would let bogus keys lock out every valid one.
+func authKeySource(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return "key:" + hex.EncodeToString(sum[:8])
+}
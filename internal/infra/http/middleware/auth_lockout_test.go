@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"container/list"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"zpwoot/platform/logger"
+)
+
+func newTestLockout(trustedProxies ...string) *authLockout {
+	return &authLockout{
+		maxFailures:    3,
+		window:         time.Minute,
+		duration:       time.Minute,
+		logger:         logger.New(),
+		trustedProxies: parseTrustedProxies(trustedProxies),
+		proxyHeader:    fiber.HeaderXForwardedFor,
+		sources:        make(map[string]*list.Element),
+		recent:         list.New(),
+	}
+}
+
+func TestAuthLockoutClientIPSkipsTrustedProxies(t *testing.T) {
+	// fiber's test requests come from 0.0.0.0
+	lockout := newTestLockout("0.0.0.0", "10.0.0.0/8")
+
+	fiberApp := fiber.New(fiber.Config{
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          []string{"0.0.0.0", "10.0.0.0/8"},
+		ProxyHeader:             fiber.HeaderXForwardedFor,
+	})
+	var got string
+	fiberApp.Get("/", func(c *fiber.Ctx) error {
+		got = lockout.clientIP(c)
+		return nil
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	// The client forged the first address; the proxies appended the real one and their own
+	req.Header.Set(fiber.HeaderXForwardedFor, "1.2.3.4, 203.0.113.7, 10.0.0.2")
+	if _, err := fiberApp.Test(req); err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if got != "203.0.113.7" {
+		t.Fatalf("client IP is %q, want 203.0.113.7", got)
+	}
+}
+
+func TestAuthLockoutEvictsLeastRecentSource(t *testing.T) {
+	lockout := newTestLockout()
+
+	lockout.recordFailure("", "victim")
+	lockout.recordFailure("", "victim")
+	lockout.recordFailure("", "victim")
+	if lockout.lockedUntil("victim").IsZero() {
+		t.Fatal("victim is not locked out after 3 failures")
+	}
+
+	for i := 0; i < maxTrackedAuthSources; i++ {
+		lockout.recordFailure("", fmt.Sprintf("spray-%d", i))
+	}
+	if len(lockout.sources) > maxTrackedAuthSources {
+		t.Fatalf("%d sources tracked, want at most %d", len(lockout.sources), maxTrackedAuthSources)
+	}
+	if _, exists := lockout.sources["spray-0"]; !exists {
+		t.Fatal("a recently failing source was evicted")
+	}
+}
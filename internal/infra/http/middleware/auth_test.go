@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"zpwoot/internal/app"
+	"zpwoot/internal/app/apikey"
+	"zpwoot/internal/app/common"
+	domainAPIKey "zpwoot/internal/domain/apikey"
+	"zpwoot/platform/config"
+	"zpwoot/platform/logger"
+)
+
+// unknownKeys is an API key store without any key
+type unknownKeys struct {
+	apikey.UseCase
+}
+
+func (unknownKeys) Authenticate(context.Context, string) (*domainAPIKey.APIKey, error) {
+	return nil, domainAPIKey.ErrAPIKeyNotFound
+}
+
+func newAuthTestApp(cfg *config.Config) *fiber.App {
+	appLogger := logger.New()
+	container := &app.Container{APIKeyUseCase: unknownKeys{}}
+
+	fiberApp := fiber.New()
+	fiberApp.Use(APIKeyAuth(cfg, container, appLogger, nil))
+	fiberApp.Use(ResponseEnvelope(cfg, appLogger))
+	fiberApp.Get("/sessions/list", func(c *fiber.Ctx) error {
+		return c.JSON(common.NewSuccessResponse([]string{}))
+	})
+	return fiberApp
+}
+
+func TestAPIKeyAuthAcceptsCompatOnlyKey(t *testing.T) {
+	cfg := &config.Config{
+		GlobalAPIKey:  "global-key-0123456789abcdef",
+		CompatAPIKeys: []string{"compat-key-0123456789abcdef"},
+	}
+	fiberApp := newAuthTestApp(cfg)
+
+	req := httptest.NewRequest("GET", "/sessions/list", nil)
+	req.Header.Set("X-API-Key", "compat-key-0123456789abcdef")
+	resp, err := fiberApp.Test(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if resp.StatusCode != 200 {
+		t.Fatalf("compat key got status %d, want 200", resp.StatusCode)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	var envelope struct {
+		Code    int  `json:"code"`
+		Success bool `json:"success"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		t.Fatalf("invalid body %s: %v", body, err)
+	}
+	if envelope.Code != 200 || !envelope.Success {
+		t.Fatalf("compat key got body %s, want the compatibility envelope", body)
+	}
+}
+
+func TestAPIKeyAuthRejectsUnknownKey(t *testing.T) {
+	cfg := &config.Config{
+		GlobalAPIKey:  "global-key-0123456789abcdef",
+		CompatAPIKeys: []string{"compat-key-0123456789abcdef"},
+	}
+	fiberApp := newAuthTestApp(cfg)
+
+	req := httptest.NewRequest("GET", "/sessions/list", nil)
+	req.Header.Set("X-API-Key", "compat-key-0123456789abcdeX")
+	resp, err := fiberApp.Test(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if resp.StatusCode != 401 {
+		t.Fatalf("unknown key got status %d, want 401", resp.StatusCode)
+	}
+}
+
+func TestSessionFromPath(t *testing.T) {
+	cases := map[string]string{
+		"/sessions/list":                         "",
+		"/sessions/create":                       "",
+		"/sessions/mySession/messages/send/text": "mySession",
+		"/message/sendText/mySession":            "mySession",
+		"/instance/connect/mySession":            "mySession",
+		"/sessions/mySession/chatwoot/webhook":   "",
+	}
+	for path, want := range cases {
+		if got := sessionFromPath(path); got != want {
+			t.Errorf("sessionFromPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"io"
+
+	"github.com/gofiber/fiber/v2"
+	"zpwoot/internal/app/common"
+	"zpwoot/platform/logger"
+)
+
+// BodyLimit caps request bodies at limit bytes. The server streams request bodies so media uploads are
+// not held in memory; multipart/form-data bodies are left to the handlers, which stream them to disk
+// within the media size limit, and every other body is read here up to the limit.
+func BodyLimit(limit int, logger *logger.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if len(c.Request().Header.MultipartFormBoundary()) > 0 {
+			return c.Next()
+		}
+
+		// Bodies within the limit were read entirely by the server already
+		contentLength := c.Request().Header.ContentLength()
+		if contentLength >= 0 && contentLength <= limit {
+			return c.Next()
+		}
+
+		if contentLength > limit {
+			return bodyTooLarge(c, limit, logger)
+		}
+
+		// Chunked bodies have no length up front
+		stream := c.Context().RequestBodyStream()
+		if stream == nil {
+			return c.Next()
+		}
+		body, err := io.ReadAll(io.LimitReader(stream, int64(limit)+1))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(common.NewErrorResponse("Failed to read request body"))
+		}
+		if len(body) > limit {
+			return bodyTooLarge(c, limit, logger)
+		}
+		c.Request().SetBody(body)
+
+		return c.Next()
+	}
+}
+
+func bodyTooLarge(c *fiber.Ctx, limit int, logger *logger.Logger) error {
+	logger.WarnWithFields("Request body too large", map[string]interface{}{
+		"component": "http",
+		"method":    c.Method(),
+		"path":      c.Path(),
+		"limit":     limit,
+	})
+	// The body is left unread, the connection cannot serve another request
+	c.Context().SetConnectionClose()
+	return c.Status(fiber.StatusRequestEntityTooLarge).JSON(common.NewErrorResponseWithCode("Request body too large", "BODY_TOO_LARGE"))
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"zpwoot/internal/app"
+	"zpwoot/platform/logger"
+)
+
+// maxLoggedBodyBytes truncates logged bodies; media payloads are often megabytes of base64
+const maxLoggedBodyBytes = 2048
+
+// redactedBody replaces the bodies of sessions in privacy mode
+const redactedBody = "(redacted)"
+
+// BodyLogger logs request and response bodies at debug level while debug body logging is switched on
+// in the runtime settings. Headers are never logged since they carry API keys, nor the bodies of
+// requests to sessions whose privacy mode redacts logs.
+func BodyLogger(container *app.Container, logger *logger.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !container.GetSettingsUseCase().DebugBodyLogging() {
+			return c.Next()
+		}
+
+		// Multipart uploads are streamed to disk by the handlers, reading them here would buffer them
+		requestBody := "(multipart)"
+		if len(c.Request().Header.MultipartFormBoundary()) == 0 {
+			requestBody = truncateBody(c.Body())
+		}
+		err := c.Next()
+
+		responseBody := "(stream)"
+		if !c.Response().IsBodyStream() {
+			responseBody = truncateBody(c.Response().Body())
+		}
+
+		if sessionID := c.Params("sessionId"); sessionID != "" && container.GetPrivacyUseCase().RedactsLogs(c.Context(), sessionID) {
+			requestBody, responseBody = redactedBody, redactedBody
+		}
+
+		logger.DebugWithFields("HTTP body "+c.Method()+" "+c.Path(), map[string]interface{}{
+			"component":     "http",
+			"method":        c.Method(),
+			"path":          c.Path(),
+			"status_code":   c.Response().StatusCode(),
+			"request_body":  requestBody,
+			"response_body": responseBody,
+		})
+
+		return err
+	}
+}
+
+func truncateBody(body []byte) string {
+	if len(body) <= maxLoggedBodyBytes {
+		return string(body)
+	}
+	return strings.ToValidUTF8(string(body[:maxLoggedBodyBytes]), "") + "...(truncated)"
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"zpwoot/platform/config"
+	"zpwoot/platform/logger"
+)
+
+// compatEnvelope is the wuzapi-style response envelope used for compatibility API keys
+type compatEnvelope struct {
+	Code    int             `json:"code"`
+	Success bool            `json:"success"`
+	Data    json.RawMessage `json:"data,omitempty"`
+	Error   string          `json:"error,omitempty"`
+}
+
+// ResponseEnvelope rewrites the standard response envelope into the compatibility envelope
+// for requests authenticated with one of the configured compatibility API keys
+func ResponseEnvelope(cfg *config.Config, logger *logger.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		apiKey, _ := c.Locals("api_key").(string)
+		if apiKey == "" || !cfg.IsCompatAPIKey(apiKey) {
+			return c.Next()
+		}
+
+		// Render handler errors now so the error body is rewritten as well
+		if err := c.Next(); err != nil {
+			if handlerErr := c.App().ErrorHandler(c, err); handlerErr != nil {
+				return handlerErr
+			}
+		}
+
+		if !strings.HasPrefix(string(c.Response().Header.ContentType()), fiber.MIMEApplicationJSON) {
+			return nil
+		}
+
+		var standard struct {
+			Success *bool           `json:"success"`
+			Data    json.RawMessage `json:"data"`
+			Error   string          `json:"error"`
+		}
+		if err := json.Unmarshal(c.Response().Body(), &standard); err != nil || standard.Success == nil {
+			return nil
+		}
+
+		body, err := json.Marshal(compatEnvelope{
+			Code:    c.Response().StatusCode(),
+			Success: *standard.Success,
+			Data:    standard.Data,
+			Error:   standard.Error,
+		})
+		if err != nil {
+			logger.WarnWithFields("Failed to build compatibility response", map[string]interface{}{
+				"path":  c.Path(),
+				"error": err.Error(),
+			})
+			return nil
+		}
+
+		c.Response().SetBodyRaw(body)
+		return nil
+	}
+}
@@ -120,7 +120,7 @@ func logHTTPRequest(logger *logger.Logger, c *fiber.Ctx, data *fiberLogger.Data)
 		"latency_ms":     data.Stop.Sub(data.Start).Milliseconds(),
 		"ip":             c.IP(),
 		"user_agent":     c.Get("User-Agent"),
-		"content_length": len(c.Response().Body()),
+		"content_length": responseLength(c),
 	}
 
 	if c.Request().URI().QueryString() != nil {
@@ -155,6 +155,15 @@ func logHTTPRequest(logger *logger.Logger, c *fiber.Ctx, data *fiberLogger.Data)
 	}
 }
 
+// responseLength returns the response body size; streamed bodies such as server-sent events
+// are reported as -1 since reading them would drain the stream
+func responseLength(c *fiber.Ctx) int {
+	if c.Response().IsBodyStream() {
+		return -1
+	}
+	return len(c.Response().Body())
+}
+
 func HTTPLogger(logger *logger.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		start := time.Now()
@@ -174,7 +183,7 @@ func HTTPLogger(logger *logger.Logger) fiber.Handler {
 			"latency_human":  latency.String(),
 			"ip":             c.IP(),
 			"user_agent":     c.Get("User-Agent"),
-			"content_length": len(c.Response().Body()),
+			"content_length": responseLength(c),
 			"protocol":       c.Protocol(),
 		}
 
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"zpwoot/internal/app"
+	"zpwoot/internal/app/common"
+	"zpwoot/platform/logger"
+)
+
+// Maintenance rejects message sends with 503 and a Retry-After while the instance or the target session
+// is in maintenance, before the request body is read. The freeze itself is enforced where the sessions
+// send, so every other outbound action fails with 503 MAINTENANCE as well; reads keep working.
+func Maintenance(container *app.Container, logger *logger.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.Method() != fiber.MethodPost {
+			return c.Next()
+		}
+
+		sessionID, isSend := sendSessionFromPath(c.Path())
+		if !isSend {
+			return c.Next()
+		}
+
+		frozen, retryAfter := container.GetMaintenanceUseCase().SendFrozen(sessionID)
+		if !frozen {
+			return c.Next()
+		}
+
+		logger.DebugWithFields("Send refused during maintenance", map[string]interface{}{
+			"session_id": sessionID,
+			"path":       c.Path(),
+		})
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(retryAfter.Seconds())))
+		return c.Status(503).JSON(common.NewErrorResponseWithCode("Sending is paused for maintenance", "MAINTENANCE"))
+	}
+}
+
+// sendSessionFromPath returns the session identifier of a message send path:
+// /sessions/:sessionId/messages/send/..., /sessions/:sessionId/chats/:jid/draft/send
+// or an Evolution-style /message/:action/:instance
+func sendSessionFromPath(path string) (string, bool) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	switch {
+	case len(parts) >= 4 && parts[0] == "sessions" && parts[2] == "messages" && parts[3] == "send":
+		return parts[1], true
+	case len(parts) == 6 && parts[0] == "sessions" && parts[2] == "chats" && parts[4] == "draft" && parts[5] == "send":
+		return parts[1], true
+	case len(parts) == 3 && parts[0] == "message":
+		return parts[2], true
+	}
+	return "", false
+}
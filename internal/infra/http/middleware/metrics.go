@@ -1,18 +1,38 @@
 package middleware
 
 import (
+	"errors"
+	"strconv"
+	"time"
+
 	"zpwoot/internal/app"
 	"zpwoot/platform/logger"
+	"zpwoot/platform/metrics"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
+var requestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name: "zpwoot_http_request_duration_seconds",
+	Help: "Duration of HTTP requests by method, route pattern and status",
+}, []string{"method", "route", "status"})
+
 func Metrics(container *app.Container, logger *logger.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		container.GetCommonUseCase().IncrementRequestCount()
+		startedAt := time.Now()
+
+		// Label slow queries issued while serving this request with its route and session
+		c.Locals(metrics.RequestLabelsKey, metrics.RequestLabels(func() (string, string) {
+			return c.Route().Path, c.Params("sessionId")
+		}))
 
 		err := c.Next()
 
+		requestDuration.WithLabelValues(c.Method(), c.Route().Path, strconv.Itoa(responseStatus(c, err))).Observe(time.Since(startedAt).Seconds())
+
 		if err != nil {
 			container.GetCommonUseCase().IncrementErrorCount()
 
@@ -28,3 +48,17 @@ func Metrics(container *app.Container, logger *logger.Logger) fiber.Handler {
 		return err
 	}
 }
+
+// responseStatus is the status the request is answered with; the error handler sets it for errors,
+// which runs only after the middleware chain returns
+func responseStatus(c *fiber.Ctx, err error) int {
+	if err == nil {
+		return c.Response().StatusCode()
+	}
+
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return fiberErr.Code
+	}
+	return fiber.StatusInternalServerError
+}
@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"zpwoot/internal/app"
+	"zpwoot/internal/app/common"
+	"zpwoot/internal/infra/http/helpers"
+	"zpwoot/platform/config"
+	"zpwoot/platform/logger"
+)
+
+// whatsappAreas are the parts of a session's API whose changes send messages or WhatsApp RPCs
+var whatsappAreas = map[string]bool{
+	"messages":    true,
+	"chats":       true,
+	"groups":      true,
+	"contacts":    true,
+	"newsletters": true,
+	"communities": true,
+	"privacy":     true,
+}
+
+// sessionLimiter hands out a fixed number of in-flight slots per session. The slots of a session
+// are dropped once no call holds or waits for one.
+type sessionLimiter struct {
+	limit int
+	mu    sync.Mutex
+	slots map[string]*sessionSlots
+}
+
+type sessionSlots struct {
+	slots chan struct{}
+	// users counts the calls holding or waiting for a slot
+	users int
+}
+
+// acquire registers a call on the slots of the session; release must follow
+func (l *sessionLimiter) acquire(sessionID string) *sessionSlots {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	slots, exists := l.slots[sessionID]
+	if !exists {
+		slots = &sessionSlots{slots: make(chan struct{}, l.limit)}
+		l.slots[sessionID] = slots
+	}
+	slots.users++
+	return slots
+}
+
+// release unregisters a call, dropping the slots of the session when it was the last one
+func (l *sessionLimiter) release(sessionID string, slots *sessionSlots) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	slots.users--
+	if slots.users == 0 {
+		delete(l.slots, sessionID)
+	}
+}
+
+// SessionConcurrency limits how many sends and WhatsApp RPCs can be in flight for one session at a
+// time; reads are not limited. Calls over the limit wait for a free slot up to the queue timeout and
+// are then rejected with 429. Sessions are counted by ID however the path names them.
+func SessionConcurrency(cfg *config.Config, container *app.Container, logger *logger.Logger) fiber.Handler {
+	if cfg.SessionMaxInFlight <= 0 {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+
+	limiter := &sessionLimiter{
+		limit: cfg.SessionMaxInFlight,
+		slots: make(map[string]*sessionSlots),
+	}
+	resolver := helpers.NewSessionResolver(logger, container.GetSessionRepository())
+
+	return func(c *fiber.Ctx) error {
+		identifier := limitedSessionFromPath(c.Method(), c.Path())
+		if identifier == "" {
+			return c.Next()
+		}
+
+		// Unknown sessions are left to the handlers, which answer 404
+		sess, err := resolver.ResolveSession(c.Context(), identifier)
+		if err != nil {
+			return c.Next()
+		}
+		sessionID := sess.ID.String()
+
+		entry := limiter.acquire(sessionID)
+		defer limiter.release(sessionID, entry)
+		slots := entry.slots
+
+		select {
+		case slots <- struct{}{}:
+		default:
+			timer := time.NewTimer(cfg.SessionQueueTimeout)
+			defer timer.Stop()
+
+			select {
+			case slots <- struct{}{}:
+			case <-timer.C:
+				logger.WarnWithFields("Session concurrency limit reached", map[string]interface{}{
+					"session_id": sessionID,
+					"path":       c.Path(),
+					"method":     c.Method(),
+					"limit":      cfg.SessionMaxInFlight,
+				})
+				c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(cfg.SessionQueueTimeout.Seconds())+1))
+				return c.Status(429).JSON(common.NewErrorResponseWithCode("Too many concurrent requests for this session", "SESSION_CONCURRENCY_LIMIT"))
+			}
+		}
+		defer func() { <-slots }()
+
+		return c.Next()
+	}
+}
+
+// limitedSessionFromPath returns the session identifier of a call the limiter applies to: a change
+// under one of the whatsappAreas of a session, or an Evolution-style message send
+func limitedSessionFromPath(method, path string) string {
+	switch method {
+	case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+		return ""
+	}
+
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	switch {
+	case len(parts) >= 3 && parts[0] == "sessions" && whatsappAreas[parts[2]]:
+		return parts[1]
+	case len(parts) == 3 && parts[0] == "message":
+		return parts[2]
+	}
+	return ""
+}
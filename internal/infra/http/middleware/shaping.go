@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"zpwoot/platform/logger"
+	"zpwoot/platform/shaping"
+)
+
+// ResponseShaping handles the fields and expand query parameters of GET requests. The requested
+// expansions are handed to the use cases through the request context so heavy sub-resources are only
+// computed when asked for; fields trims the data of successful responses to the listed dotted paths.
+func ResponseShaping(logger *logger.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.Method() != fiber.MethodGet {
+			return c.Next()
+		}
+
+		shape := shaping.Parse(c.Query("fields"), c.Query("expand"))
+		if shape == nil {
+			return c.Next()
+		}
+		c.Locals(shaping.ContextKey, shape)
+
+		if err := c.Next(); err != nil || len(shape.Fields) == 0 {
+			return err
+		}
+
+		status := c.Response().StatusCode()
+		if status < 200 || status >= 300 || !strings.HasPrefix(string(c.Response().Header.ContentType()), fiber.MIMEApplicationJSON) {
+			return nil
+		}
+
+		var body map[string]json.RawMessage
+		if err := json.Unmarshal(c.Response().Body(), &body); err != nil {
+			return nil
+		}
+		data, ok := body["data"]
+		if !ok {
+			return nil
+		}
+
+		selected, err := shaping.Select(data, shape.Fields)
+		if err != nil {
+			logger.WarnWithFields("Failed to select response fields", map[string]interface{}{
+				"path":  c.Path(),
+				"error": err.Error(),
+			})
+			return nil
+		}
+		body["data"] = selected
+
+		shaped, err := json.Marshal(body)
+		if err != nil {
+			return nil
+		}
+		c.Response().SetBodyRaw(shaped)
+		return nil
+	}
+}
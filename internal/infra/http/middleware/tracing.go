@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+
+	"zpwoot/platform/tracing"
+)
+
+// Tracing starts a server span per request, continuing the caller's trace from its W3C trace context
+// headers. The span is stored in the request locals, so use cases reached with c.Context() create
+// their spans as its children.
+func Tracing() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		propagator := otel.GetTextMapPropagator()
+		carrier := propagation.MapCarrier{}
+		for _, field := range propagator.Fields() {
+			if value := c.Get(field); value != "" {
+				carrier.Set(field, value)
+			}
+		}
+
+		ctx := propagator.Extract(c.UserContext(), carrier)
+		ctx, span := tracing.Start(ctx, "HTTP "+c.Method(), tracing.KindServer)
+		defer span.End()
+
+		c.SetUserContext(ctx)
+		c.Locals(tracing.SpanKey, span)
+		span.SetAttributes(
+			attribute.String("http.request.method", c.Method()),
+			attribute.String("url.path", c.Path()),
+		)
+		if requestID, ok := c.Locals("request_id").(string); ok {
+			span.SetAttributes(attribute.String("zpwoot.request_id", requestID))
+		}
+
+		err := c.Next()
+
+		route := c.Route().Path
+		status := responseStatus(c, err)
+		span.SetName(c.Method() + " " + route)
+		span.SetAttributes(
+			attribute.String("http.route", route),
+			attribute.Int("http.response.status_code", status),
+		)
+		if sessionID := c.Params("sessionId"); sessionID != "" {
+			span.SetAttributes(attribute.String("zpwoot.session", sessionID))
+		}
+		if err != nil {
+			tracing.RecordError(span, err)
+		} else if status >= fiber.StatusInternalServerError {
+			tracing.SetErrorf(span, "HTTP %d", status)
+		}
+
+		return err
+	}
+}
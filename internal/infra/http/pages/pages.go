@@ -0,0 +1,21 @@
+// Package pages embeds the server-rendered public pages, such as the pairing page.
+package pages
+
+import (
+	"embed"
+	"html/template"
+)
+
+//go:embed *.html
+var files embed.FS
+
+// Pairing renders the public pairing page; see PairingData for its fields
+var Pairing = template.Must(template.ParseFS(files, "pairing.html"))
+
+// PairingData is the data the pairing page is rendered with
+type PairingData struct {
+	SessionName string
+	EventsURL   string
+	ExpiresAt   string
+	Error       string
+}
@@ -2,11 +2,18 @@ package routers
 
 import (
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/filesystem"
+	"github.com/gofiber/fiber/v2/middleware/pprof"
 	fiberSwagger "github.com/swaggo/fiber-swagger"
 
 	"zpwoot/internal/app"
+	"zpwoot/internal/infra/http/dashboard"
 	"zpwoot/internal/infra/http/handlers"
+	"zpwoot/internal/infra/http/middleware"
+	"zpwoot/internal/infra/integrations/webhook"
 	"zpwoot/internal/infra/wameow"
+	"zpwoot/internal/ports"
+	"zpwoot/platform/config"
 	"zpwoot/platform/db"
 	"zpwoot/platform/logger"
 )
@@ -24,6 +31,8 @@ func SetupRoutes(app *fiber.App, database *db.DB, logger *logger.Logger, WameowM
 	setupSessionSpecificRoutes(app, database, logger, WameowManager, container)
 
 	setupGlobalRoutes(app, database, logger, WameowManager, container)
+
+	setupAdminRoutes(app, container, logger)
 }
 
 func setupSessionRoutes(app *fiber.App, appLogger *logger.Logger, WameowManager *wameow.Manager, container *app.Container) {
@@ -34,12 +43,23 @@ func setupSessionRoutes(app *fiber.App, appLogger *logger.Logger, WameowManager
 	// Setup all route groups
 	setupSessionManagementRoutes(sessions, container, appLogger)
 	setupMessageRoutes(sessions, container, WameowManager, appLogger)
+	setupOutboxRoutes(sessions, container, appLogger)
+	setupScheduledRoutes(sessions, container, appLogger)
 	setupGroupRoutes(sessions, container, appLogger)
 	setupNewsletterRoutes(sessions, container, appLogger)
 	setupCommunityRoutes(sessions, container, appLogger)
 	setupContactRoutes(sessions, container, appLogger)
+	setupChatRoutes(sessions, container, appLogger)
 	setupWebhookRoutes(sessions, container, appLogger)
 	setupChatwootRoutes(sessions, container, appLogger)
+	setupDraftRoutes(sessions, container, appLogger)
+	setupRoutingRoutes(sessions, container, appLogger)
+	setupConversationRoutes(sessions, container, appLogger)
+	setupTranslationRoutes(sessions, container, appLogger)
+	setupPrivacyRoutes(sessions, container, appLogger)
+	setupWarmupRoutes(sessions, container, appLogger)
+	setupSendGuardRoutes(sessions, container, appLogger)
+	setupHistoryRoutes(sessions, container, appLogger)
 }
 
 // logWameowAvailability logs Wameow manager availability
@@ -61,10 +81,18 @@ func setupSessionManagementRoutes(sessions fiber.Router, container *app.Containe
 	sessions.Delete("/:sessionId/delete", sessionHandler.DeleteSession)
 	sessions.Post("/:sessionId/connect", sessionHandler.ConnectSession)
 	sessions.Post("/:sessionId/logout", sessionHandler.LogoutSession)
+	sessions.Post("/:sessionId/restart", sessionHandler.RestartSession)
 	sessions.Get("/:sessionId/qr", sessionHandler.GetQRCode)
 	sessions.Post("/:sessionId/pair", sessionHandler.PairPhone)
 	sessions.Post("/:sessionId/proxy/set", sessionHandler.SetProxy)
 	sessions.Get("/:sessionId/proxy/find", sessionHandler.GetProxy)
+	sessions.Put("/:sessionId/proxy", sessionHandler.UpdateProxy)
+	sessions.Get("/:sessionId/event-handlers", sessionHandler.ListEventHandlers)
+	sessions.Delete("/:sessionId/warning", sessionHandler.ClearWarning)
+	sessions.Get("/:sessionId/stats", sessionHandler.GetSessionStats)
+
+	pairingHandler := handlers.NewPairingHandler(appLogger, container.GetPairingUseCase())
+	sessions.Post("/:sessionId/pairing-link", pairingHandler.CreateLink)
 }
 
 // setupMessageRoutes sets up message-related routes
@@ -82,6 +110,9 @@ func setupMessageRoutes(sessions fiber.Router, container *app.Container, WameowM
 	sessions.Post("/:sessionId/messages/send/button", messageHandler.SendButtonMessage)
 	sessions.Post("/:sessionId/messages/send/contact", messageHandler.SendContact)
 	sessions.Post("/:sessionId/messages/send/list", messageHandler.SendListMessage)
+	sessions.Post("/:sessionId/messages/send/flow", messageHandler.SendFlowMessage)
+	sessions.Post("/:sessionId/messages/send/carousel", messageHandler.SendCarouselMessage)
+	sessions.Post("/:sessionId/messages/send/template-hydrated", messageHandler.SendTemplateMessage)
 	sessions.Post("/:sessionId/messages/send/location", messageHandler.SendLocation)
 	sessions.Post("/:sessionId/messages/send/poll", messageHandler.SendPoll)
 	sessions.Post("/:sessionId/messages/send/reaction", messageHandler.SendReaction)
@@ -91,7 +122,32 @@ func setupMessageRoutes(sessions fiber.Router, container *app.Container, WameowM
 	sessions.Post("/:sessionId/messages/edit", messageHandler.EditMessage)
 	sessions.Post("/:sessionId/messages/mark-read", messageHandler.MarkAsRead)
 	sessions.Post("/:sessionId/messages/revoke", messageHandler.RevokeMessage)
+	sessions.Post("/:sessionId/messages/pin", messageHandler.PinMessage)
+	sessions.Post("/:sessionId/messages/unpin", messageHandler.UnpinMessage)
+	sessions.Post("/:sessionId/chats/:jid/mark-read", messageHandler.MarkChatAsRead)
+	sessions.Post("/:sessionId/chats/:jid/backfill", messageHandler.BackfillChat)
 	sessions.Get("/:sessionId/messages/poll/:messageId/results", messageHandler.GetPollResults)
+	sessions.Post("/:sessionId/messages/poll/:messageId/vote", messageHandler.VotePoll)
+	sessions.Delete("/:sessionId/messages/queue/:queueId", messageHandler.CancelQueuedMessage)
+}
+
+// setupOutboxRoutes sets up asynchronous send routes
+func setupOutboxRoutes(sessions fiber.Router, container *app.Container, appLogger *logger.Logger) {
+	outboxHandler := handlers.NewOutboxHandler(appLogger, container.GetOutboxUseCase(), container.GetSessionRepository())
+
+	sessions.Post("/:sessionId/messages/enqueue", outboxHandler.Enqueue)
+	sessions.Get("/:sessionId/messages/outbox", outboxHandler.ListMessages)
+	sessions.Get("/:sessionId/messages/outbox/:outboxId", outboxHandler.GetMessage)
+}
+
+// setupScheduledRoutes sets up scheduled message routes
+func setupScheduledRoutes(sessions fiber.Router, container *app.Container, appLogger *logger.Logger) {
+	scheduledHandler := handlers.NewScheduledHandler(appLogger, container.GetScheduledUseCase(), container.GetSessionRepository())
+
+	sessions.Get("/:sessionId/messages/scheduled", scheduledHandler.ListMessages)
+	sessions.Get("/:sessionId/messages/scheduled/:scheduledId", scheduledHandler.GetMessage)
+	sessions.Patch("/:sessionId/messages/scheduled/:scheduledId", scheduledHandler.Reschedule)
+	sessions.Delete("/:sessionId/messages/scheduled/:scheduledId", scheduledHandler.Cancel)
 }
 
 // setupGroupRoutes sets up group management routes
@@ -131,11 +187,14 @@ func setupNewsletterRoutes(sessions fiber.Router, container *app.Container, appL
 	sessions.Get("/:sessionId/newsletters/info", newsletterHandler.GetNewsletterInfo)
 	sessions.Post("/:sessionId/newsletters/info-from-invite", newsletterHandler.GetNewsletterInfoWithInvite)
 	sessions.Post("/:sessionId/newsletters/follow", newsletterHandler.FollowNewsletter)
+	sessions.Post("/:sessionId/newsletters/follow-invite", newsletterHandler.FollowNewsletterWithInvite)
 	sessions.Post("/:sessionId/newsletters/unfollow", newsletterHandler.UnfollowNewsletter)
 	sessions.Get("/:sessionId/newsletters/messages", newsletterHandler.GetNewsletterMessages)
 	sessions.Get("/:sessionId/newsletters/updates", newsletterHandler.GetNewsletterMessageUpdates)
 	sessions.Post("/:sessionId/newsletters/mark-viewed", newsletterHandler.NewsletterMarkViewed)
+	sessions.Post("/:sessionId/newsletters/mark-viewed/bulk", newsletterHandler.BulkNewsletterMarkViewed)
 	sessions.Post("/:sessionId/newsletters/send-reaction", newsletterHandler.NewsletterSendReaction)
+	sessions.Post("/:sessionId/newsletters/send-reaction/bulk", newsletterHandler.BulkNewsletterSendReaction)
 	sessions.Post("/:sessionId/newsletters/subscribe-live", newsletterHandler.NewsletterSubscribeLiveUpdates)
 	sessions.Post("/:sessionId/newsletters/toggle-mute", newsletterHandler.NewsletterToggleMute)
 	sessions.Post("/:sessionId/newsletters/accept-tos", newsletterHandler.AcceptTOSNotice)
@@ -164,6 +223,26 @@ func setupContactRoutes(sessions fiber.Router, container *app.Container, appLogg
 	sessions.Get("/:sessionId/contacts", contactHandler.ListContacts)
 	sessions.Post("/:sessionId/contacts/sync", contactHandler.SyncContacts)
 	sessions.Get("/:sessionId/contacts/business", contactHandler.GetBusinessProfile)
+	sessions.Get("/:sessionId/contacts/changes", contactHandler.ListContactChanges)
+	sessions.Post("/:sessionId/contacts/presence/subscribe", contactHandler.SubscribePresence)
+	sessions.Get("/:sessionId/contacts/presence", contactHandler.ListPresenceSubscriptions)
+}
+
+// setupChatRoutes sets up chat management routes
+func setupChatRoutes(sessions fiber.Router, container *app.Container, appLogger *logger.Logger) {
+	chatHandler := handlers.NewChatHandler(appLogger, container.GetChatUseCase(), container.GetSessionRepository())
+
+	sessions.Post("/:sessionId/chats/:jid/archive", chatHandler.ArchiveChat)
+	sessions.Post("/:sessionId/chats/:jid/unarchive", chatHandler.UnarchiveChat)
+	sessions.Post("/:sessionId/chats/:jid/pin", chatHandler.PinChat)
+	sessions.Post("/:sessionId/chats/:jid/unpin", chatHandler.UnpinChat)
+	sessions.Post("/:sessionId/chats/:jid/mute", chatHandler.MuteChat)
+	sessions.Post("/:sessionId/chats/:jid/unmute", chatHandler.UnmuteChat)
+	sessions.Post("/:sessionId/chats/:jid/read", chatHandler.ReadChat)
+	sessions.Post("/:sessionId/chats/:jid/unread", chatHandler.UnreadChat)
+	sessions.Post("/:sessionId/chats/:jid/clear", chatHandler.ClearChat)
+	sessions.Post("/:sessionId/chats/:jid/typing", chatHandler.Typing)
+	sessions.Delete("/:sessionId/chats/:jid", chatHandler.DeleteChat)
 }
 
 // setupWebhookRoutes sets up webhook management routes
@@ -173,6 +252,11 @@ func setupWebhookRoutes(sessions fiber.Router, container *app.Container, appLogg
 	sessions.Post("/:sessionId/webhook/set", webhookHandler.SetConfig)
 	sessions.Get("/:sessionId/webhook/find", webhookHandler.FindConfig)
 	sessions.Post("/:sessionId/webhook/test", webhookHandler.TestWebhook)
+	sessions.Post("/:sessionId/webhook/template/preview", webhookHandler.PreviewPayloadTemplate)
+	sessions.Post("/:sessionId/events/replay", webhookHandler.ReplayEvents)
+
+	eventStreamHandler := handlers.NewEventStreamHandler(appLogger, container.GetWebhookUseCase(), container.GetSessionRepository())
+	sessions.Get("/:sessionId/events/ws", eventStreamHandler.StreamEvents)
 }
 
 // setupChatwootRoutes sets up Chatwoot integration routes
@@ -183,6 +267,81 @@ func setupChatwootRoutes(sessions fiber.Router, container *app.Container, appLog
 	sessions.Get("/:sessionId/chatwoot/find", chatwootHandler.FindConfig)
 	sessions.Post("/:sessionId/chatwoot/contacts/sync", chatwootHandler.SyncContacts)
 	sessions.Post("/:sessionId/chatwoot/conversations/sync", chatwootHandler.SyncConversations)
+	sessions.Get("/:sessionId/chatwoot/queue", chatwootHandler.GetWebhookQueue)
+}
+
+// setupDraftRoutes sets up message draft routes
+func setupDraftRoutes(sessions fiber.Router, container *app.Container, appLogger *logger.Logger) {
+	draftHandler := handlers.NewDraftHandler(appLogger, container.GetDraftUseCase(), container.GetSessionRepository())
+
+	sessions.Get("/:sessionId/drafts", draftHandler.ListDrafts)
+	sessions.Get("/:sessionId/chats/:jid/draft", draftHandler.GetDraft)
+	sessions.Put("/:sessionId/chats/:jid/draft", draftHandler.SaveDraft)
+	sessions.Delete("/:sessionId/chats/:jid/draft", draftHandler.DeleteDraft)
+	sessions.Post("/:sessionId/chats/:jid/draft/send", draftHandler.SendDraft)
+}
+
+// setupConversationRoutes sets up conversation state routes
+func setupConversationRoutes(sessions fiber.Router, container *app.Container, appLogger *logger.Logger) {
+	conversationHandler := handlers.NewConversationHandler(appLogger, container.GetConversationUseCase(), container.GetSessionRepository())
+
+	sessions.Get("/:sessionId/conversations", conversationHandler.ListStates)
+	sessions.Get("/:sessionId/chats/:jid/state", conversationHandler.GetState)
+	sessions.Put("/:sessionId/chats/:jid/state", conversationHandler.SetState)
+	sessions.Delete("/:sessionId/chats/:jid/state", conversationHandler.ResetState)
+}
+
+// setupRoutingRoutes sets up message routing rule and chat label routes
+func setupRoutingRoutes(sessions fiber.Router, container *app.Container, appLogger *logger.Logger) {
+	routingHandler := handlers.NewRoutingHandler(appLogger, container.GetRoutingUseCase(), container.GetSessionRepository())
+
+	sessions.Post("/:sessionId/routing/rules", routingHandler.CreateRule)
+	sessions.Get("/:sessionId/routing/rules", routingHandler.ListRules)
+	sessions.Get("/:sessionId/routing/rules/:ruleId", routingHandler.GetRule)
+	sessions.Put("/:sessionId/routing/rules/:ruleId", routingHandler.UpdateRule)
+	sessions.Delete("/:sessionId/routing/rules/:ruleId", routingHandler.DeleteRule)
+	sessions.Put("/:sessionId/chats/:jid/labels/:labelId", routingHandler.LabelChat)
+	sessions.Delete("/:sessionId/chats/:jid/labels/:labelId", routingHandler.UnlabelChat)
+}
+
+// setupTranslationRoutes sets up message translation settings routes
+func setupTranslationRoutes(sessions fiber.Router, container *app.Container, appLogger *logger.Logger) {
+	translationHandler := handlers.NewTranslationHandler(appLogger, container.GetTranslationUseCase(), container.GetSessionRepository())
+
+	sessions.Get("/:sessionId/translation", translationHandler.GetConfig)
+	sessions.Put("/:sessionId/translation", translationHandler.UpdateConfig)
+}
+
+// setupPrivacyRoutes sets up privacy mode routes
+func setupPrivacyRoutes(sessions fiber.Router, container *app.Container, appLogger *logger.Logger) {
+	privacyHandler := handlers.NewPrivacyHandler(appLogger, container.GetPrivacyUseCase(), container.GetSessionRepository())
+
+	sessions.Get("/:sessionId/privacy", privacyHandler.GetConfig)
+	sessions.Put("/:sessionId/privacy", privacyHandler.UpdateConfig)
+}
+
+// setupWarmupRoutes sets up number warm-up routes
+func setupWarmupRoutes(sessions fiber.Router, container *app.Container, appLogger *logger.Logger) {
+	warmupHandler := handlers.NewWarmupHandler(appLogger, container.GetWarmupUseCase(), container.GetSessionRepository())
+
+	sessions.Get("/:sessionId/warmup", warmupHandler.GetStatus)
+	sessions.Put("/:sessionId/warmup", warmupHandler.UpdateWarmup)
+}
+
+// setupSendGuardRoutes sets up send policy routes
+func setupSendGuardRoutes(sessions fiber.Router, container *app.Container, appLogger *logger.Logger) {
+	sendGuardHandler := handlers.NewSendGuardHandler(appLogger, container.GetSendGuardUseCase(), container.GetSessionRepository())
+
+	sessions.Get("/:sessionId/send-policy", sendGuardHandler.GetPolicy)
+	sessions.Put("/:sessionId/send-policy", sendGuardHandler.UpdatePolicy)
+	sessions.Get("/:sessionId/send-policy/violations", sendGuardHandler.ListViolations)
+}
+
+// setupHistoryRoutes sets up message history routes
+func setupHistoryRoutes(sessions fiber.Router, container *app.Container, appLogger *logger.Logger) {
+	historyHandler := handlers.NewHistoryHandler(appLogger, container.GetHistoryUseCase(), container.GetSessionRepository())
+
+	sessions.Get("/:sessionId/chats/:jid/messages", historyHandler.ListChatMessages)
 }
 
 func setupSessionSpecificRoutes(app *fiber.App, database *db.DB, appLogger *logger.Logger, WameowManager *wameow.Manager, container *app.Container) {
@@ -200,4 +359,103 @@ func setupGlobalRoutes(app *fiber.App, database *db.DB, appLogger *logger.Logger
 	chatwootHandler := handlers.NewChatwootHandler(container.GetChatwootUseCase(), appLogger)
 	app.Post("/sessions/:sessionId/chatwoot/webhook", chatwootHandler.ReceiveWebhook) // POST /sessions/:sessionId/chatwoot/webhook
 	app.Post("/chatwoot/webhook/:sessionId", chatwootHandler.ReceiveWebhook)          // POST /chatwoot/webhook/:sessionId (alternative route)
+
+	// Public pairing page (authenticated by the signed link token). It lives outside /sessions
+	// so the long-lived event stream does not hold a per-session concurrency slot.
+	pairingHandler := handlers.NewPairingHandler(appLogger, container.GetPairingUseCase())
+	app.Get("/pair/:token", pairingHandler.PairingPage)
+	app.Get("/pair/:token/events", pairingHandler.PairingEvents)
+
+	// Prometheus scrape endpoint; scrapers send the API key in the X-API-Key header
+	metricsHandler := handlers.NewMetricsHandler(appLogger)
+	app.Get("/metrics", metricsHandler.GetMetrics)
+}
+
+// setupAdminRoutes sets up operator routes: usage reporting for billing, maintenance mode, runtime settings,
+// background jobs and webhook dispatch
+func setupAdminRoutes(app *fiber.App, container *app.Container, appLogger *logger.Logger) {
+	usageHandler := handlers.NewUsageHandler(appLogger, container.GetUsageUseCase())
+
+	admin := app.Group("/admin")
+	admin.Get("/usage", usageHandler.GetUsage)
+	admin.Get("/usage/counters", usageHandler.ListCounters)
+
+	maintenanceHandler := handlers.NewMaintenanceHandler(appLogger, container.GetMaintenanceUseCase())
+	admin.Get("/maintenance", maintenanceHandler.GetStatus)
+	admin.Put("/maintenance", maintenanceHandler.SetMaintenance)
+	admin.Put("/maintenance/sessions/:sessionId", maintenanceHandler.FreezeSession)
+	admin.Delete("/maintenance/sessions/:sessionId", maintenanceHandler.UnfreezeSession)
+
+	settingsHandler := handlers.NewSettingsHandler(appLogger, container.GetSettingsUseCase())
+	admin.Get("/settings", settingsHandler.GetSettings)
+	admin.Patch("/settings", settingsHandler.UpdateSettings)
+
+	jobHandler := handlers.NewJobHandler(appLogger, container.GetJobUseCase())
+	admin.Get("/jobs", jobHandler.ListJobs)
+	admin.Get("/jobs/:name", jobHandler.GetJob)
+	admin.Post("/jobs/:name/run", jobHandler.TriggerJob)
+
+	webhookHandler := handlers.NewWebhookHandler(container.GetWebhookUseCase(), appLogger)
+	admin.Get("/webhooks/dispatch", webhookHandler.GetDispatchStatus)
+	admin.Put("/webhooks/dispatch", webhookHandler.UpdateDispatch)
+	admin.Put("/webhooks/dispatch/sessions/:sessionId", webhookHandler.SetSessionDispatch)
+	admin.Post("/webhooks/dispatch/sessions/:sessionId/drain", webhookHandler.DrainSessionDispatch)
+
+	apiKeyHandler := handlers.NewAPIKeyHandler(appLogger, container.GetAPIKeyUseCase())
+	admin.Get("/apikeys", apiKeyHandler.ListKeys)
+	admin.Post("/apikeys", apiKeyHandler.CreateKey)
+	admin.Get("/apikeys/:keyId", apiKeyHandler.GetKey)
+	admin.Patch("/apikeys/:keyId", apiKeyHandler.UpdateKey)
+	admin.Delete("/apikeys/:keyId", apiKeyHandler.RevokeKey)
+}
+
+// SetupDashboardRoutes serves the embedded web dashboard and the admin data it reads besides the regular API
+func SetupDashboardRoutes(app *fiber.App, container *app.Container, appLogger *logger.Logger) {
+	dashboardHandler := handlers.NewDashboardHandler(appLogger, container.GetDashboardUseCase())
+	app.Get("/admin/dashboard/messages", dashboardHandler.RecentMessages)
+	app.Get("/admin/dashboard/webhook-failures", dashboardHandler.WebhookFailures)
+
+	app.Use("/dashboard", filesystem.New(filesystem.Config{
+		Root:   dashboard.FileSystem(),
+		Index:  "index.html",
+		Browse: false,
+	}))
+
+	appLogger.Info("Web dashboard enabled at /dashboard")
+}
+
+// SetupEvolutionRoutes exposes the Evolution-API-style compatibility routes; :instance is the session name or ID
+func SetupEvolutionRoutes(app *fiber.App, appLogger *logger.Logger, WameowManager *wameow.Manager, container *app.Container) {
+	evolutionHandler := handlers.NewEvolutionHandler(container.GetMessageUseCase(), container.GetSessionUseCase(), WameowManager, container.GetSessionRepository(), appLogger)
+
+	messageRoutes := app.Group("/message")
+	messageRoutes.Post("/sendText/:instance", evolutionHandler.SendText)
+	messageRoutes.Post("/sendMedia/:instance", evolutionHandler.SendMedia)
+	messageRoutes.Post("/sendWhatsAppAudio/:instance", evolutionHandler.SendWhatsAppAudio)
+	messageRoutes.Post("/sendLocation/:instance", evolutionHandler.SendLocation)
+	messageRoutes.Post("/sendContact/:instance", evolutionHandler.SendContact)
+	messageRoutes.Post("/sendReaction/:instance", evolutionHandler.SendReaction)
+
+	app.Post("/chat/markMessageAsRead/:instance", evolutionHandler.MarkMessageAsRead)
+
+	instanceRoutes := app.Group("/instance")
+	instanceRoutes.Get("/connectionState/:instance", evolutionHandler.ConnectionState)
+	instanceRoutes.Get("/connect/:instance", evolutionHandler.Connect)
+	instanceRoutes.Delete("/logout/:instance", evolutionHandler.Logout)
+
+	appLogger.Info("Evolution API compatibility routes enabled")
+}
+
+// SetupDebugRoutes serves pprof, goroutine dumps and session diagnostics behind the admin key
+func SetupDebugRoutes(app *fiber.App, cfg *config.Config, appLogger *logger.Logger, WameowManager *wameow.Manager, webhookManager *webhook.WebhookManager, chatwootQueue ports.ChatwootWebhookQueue, container *app.Container) {
+	diagnosticsHandler := handlers.NewDiagnosticsHandler(appLogger, WameowManager, webhookManager, chatwootQueue, container.GetSessionRepository())
+
+	debug := app.Group("/debug", middleware.AdminKeyAuth(cfg, appLogger))
+	debug.Use(pprof.New()) // /debug/pprof/*
+	debug.Get("/runtime", diagnosticsHandler.GetRuntime)
+	debug.Get("/goroutines", diagnosticsHandler.GetGoroutines)
+	debug.Get("/sessions", diagnosticsHandler.ListSessions)
+	debug.Get("/sessions/:sessionId", diagnosticsHandler.GetSession)
+
+	appLogger.Info("Debug routes enabled at /debug (admin key required)")
 }
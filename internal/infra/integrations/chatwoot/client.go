@@ -7,34 +7,48 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
 	"zpwoot/internal/ports"
 	"zpwoot/platform/logger"
 )
 
-// Client implements the ChatwootClient interface
+// Client implements the ChatwootClient interface. Calls share a pooled transport, are retried on
+// rate limits and server errors, and are rejected without calling Chatwoot while its circuit is open.
 type Client struct {
 	baseURL    string
 	token      string
 	accountID  string
+	host       string
 	httpClient *http.Client
+	breaker    *circuitBreaker
 	logger     *logger.Logger
+	requestID  string // sent as X-Request-ID to trace the call back to the API request behind it
 }
 
 // NewClient creates a new Chatwoot API client
 func NewClient(baseURL, token, accountID string, logger *logger.Logger) *Client {
+	host := hostLabel(baseURL)
 	return &Client{
-		baseURL:   baseURL,
-		token:     token,
-		accountID: accountID,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-		logger: logger,
+		baseURL:    baseURL,
+		token:      token,
+		accountID:  accountID,
+		host:       host,
+		httpClient: newHTTPClient(),
+		breaker:    newCircuitBreaker(host),
+		logger:     logger,
 	}
 }
 
+// WithRequestID returns a copy of the client that sends the given request ID with its calls; it shares
+// the HTTP client and circuit breaker of the original
+func (c *Client) WithRequestID(requestID string) *Client {
+	traced := *c
+	traced.requestID = requestID
+	return &traced
+}
+
 // ============================================================================
 // INBOX OPERATIONS
 // ============================================================================
@@ -301,6 +315,49 @@ func (c *Client) SendMessageWithType(conversationID int, content string, message
 	return &message, nil
 }
 
+// SendMessageWithReply sends a message to a conversation as a reply to an existing Chatwoot message
+func (c *Client) SendMessageWithReply(conversationID int, content string, messageType string, inReplyTo int) (*ports.ChatwootMessage, error) {
+	payload := map[string]interface{}{
+		"content":      content,
+		"message_type": messageType,
+		"content_attributes": map[string]interface{}{
+			"in_reply_to": inReplyTo,
+		},
+	}
+
+	var message ports.ChatwootMessage
+	err := c.makeRequest("POST", fmt.Sprintf("/conversations/%d/messages", conversationID), payload, &message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send reply message: %w", err)
+	}
+
+	return &message, nil
+}
+
+// UpdateMessageStatus updates the delivery status (sent, delivered, read, failed) of a message in an API inbox
+func (c *Client) UpdateMessageStatus(conversationID, messageID int, status string) error {
+	payload := map[string]interface{}{
+		"status": status,
+	}
+
+	err := c.makeRequest("PATCH", fmt.Sprintf("/conversations/%d/messages/%d", conversationID, messageID), payload, nil)
+	if err != nil {
+		return fmt.Errorf("failed to update message status: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteMessage deletes a message from a conversation
+func (c *Client) DeleteMessage(conversationID, messageID int) error {
+	err := c.makeRequest("DELETE", fmt.Sprintf("/conversations/%d/messages/%d", conversationID, messageID), nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete message: %w", err)
+	}
+
+	return nil
+}
+
 // SendMediaMessage sends a media message to a conversation
 func (c *Client) SendMediaMessage(conversationID int, content string, attachment io.Reader, filename string) (*ports.ChatwootMessage, error) {
 	// TODO: Implement multipart form data upload for media
@@ -360,27 +417,94 @@ func (c *Client) UpdateAccount(updates map[string]interface{}) error {
 func (c *Client) makeRequest(method, endpoint string, payload interface{}, result interface{}) error {
 	url := fmt.Sprintf("%s/api/v1/accounts/%s%s", c.baseURL, c.accountID, endpoint)
 
-	var body io.Reader
+	var jsonData []byte
 	if payload != nil {
-		jsonData, err := json.Marshal(payload)
+		var err error
+		jsonData, err = json.Marshal(payload)
 		if err != nil {
 			return fmt.Errorf("failed to marshal payload: %w", err)
 		}
-		body = bytes.NewBuffer(jsonData)
+	}
+
+	if !c.breaker.allow() {
+		requestsTotal.WithLabelValues(c.host, method, "circuit_open").Inc()
+		return ErrCircuitOpen
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, err := c.doRequest(method, url, jsonData)
+
+		statusCode := 0
+		if err == nil {
+			statusCode = resp.StatusCode
+		}
+
+		if attempt < maxAttempts && (err != nil || statusCode < 200 || statusCode >= 300) && isRetryable(method, statusCode) {
+			delay := retryDelay(resp, attempt)
+			reason := "error"
+			if err == nil {
+				reason = strconv.Itoa(statusCode)
+				_, _ = io.Copy(io.Discard, resp.Body)
+				_ = resp.Body.Close()
+			}
+			retriesTotal.WithLabelValues(c.host, reason).Inc()
+
+			c.logger.WarnWithFields("Retrying Chatwoot API request", map[string]interface{}{
+				"method":   method,
+				"endpoint": endpoint,
+				"status":   statusCode,
+				"attempt":  attempt,
+				"delay":    delay.String(),
+			})
+			time.Sleep(delay)
+			continue
+		}
+
+		if err != nil {
+			c.breaker.record(false)
+			return fmt.Errorf("failed to make request: %w", err)
+		}
+
+		// Client errors mean Chatwoot is up, only rate limits and server errors count against the circuit
+		c.breaker.record(statusCode != http.StatusTooManyRequests && statusCode < 500)
+
+		return c.readResponse(resp, result)
+	}
+}
+
+// doRequest performs one attempt of a Chatwoot API call and records its metrics
+func (c *Client) doRequest(method, url string, jsonData []byte) (*http.Response, error) {
+	var body io.Reader
+	if jsonData != nil {
+		body = bytes.NewReader(jsonData)
 	}
 
 	req, err := http.NewRequest(method, url, body)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("api_access_token", c.token)
+	if c.requestID != "" {
+		req.Header.Set("X-Request-ID", c.requestID)
+	}
 
+	started := time.Now()
 	resp, err := c.httpClient.Do(req)
+	requestSecondsTotal.WithLabelValues(c.host).Add(time.Since(started).Seconds())
+
 	if err != nil {
-		return fmt.Errorf("failed to make request: %w", err)
+		requestsTotal.WithLabelValues(c.host, method, "error").Inc()
+		return nil, err
 	}
+	requestsTotal.WithLabelValues(c.host, method, strconv.Itoa(resp.StatusCode)).Inc()
+
+	return resp, nil
+}
+
+// readResponse decodes a successful response into result and turns other statuses into errors
+func (c *Client) readResponse(resp *http.Response, result interface{}) error {
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
@@ -411,9 +535,7 @@ func (c *Client) MergeContacts(baseContactID, mergeContactID int) error {
 	}
 
 	// Make the merge request to Chatwoot API (same endpoint as Evolution API)
-	url := fmt.Sprintf("/api/v1/accounts/%s/actions/contact_merge", c.accountID)
-
-	err := c.makeRequest("POST", url, requestBody, nil)
+	err := c.makeRequest("POST", "/actions/contact_merge", requestBody, nil)
 	if err != nil {
 		c.logger.ErrorWithFields("Failed to merge contacts", map[string]interface{}{
 			"base_contact_id":  baseContactID,
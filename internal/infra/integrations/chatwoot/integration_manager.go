@@ -8,10 +8,17 @@ import (
 	"strings"
 	"time"
 
+	"zpwoot/internal/domain/activity"
 	"zpwoot/internal/ports"
 	"zpwoot/platform/logger"
+	platformPhone "zpwoot/platform/phone"
 )
 
+// ErrorRecorder keeps Chatwoot relay errors, e.g. for integration health
+type ErrorRecorder interface {
+	RecordChatwootError(chatwootErr *activity.ChatwootError)
+}
+
 // IntegrationManager handles the integration between WhatsApp and Chatwoot
 type IntegrationManager struct {
 	logger          *logger.Logger
@@ -20,6 +27,7 @@ type IntegrationManager struct {
 	contactSync     *ContactSync
 	conversationMgr *ConversationManager
 	formatter       *MessageFormatter
+	errors          ErrorRecorder
 }
 
 // NewIntegrationManager creates a new integration manager
@@ -41,41 +49,136 @@ func NewIntegrationManager(
 	}
 }
 
+// SetErrorRecorder sets where messages that could not be relayed to Chatwoot are reported
+func (im *IntegrationManager) SetErrorRecorder(recorder ErrorRecorder) {
+	im.errors = recorder
+}
+
 // IsEnabled checks if Chatwoot integration is enabled for a session
 func (im *IntegrationManager) IsEnabled(sessionID string) bool {
 	return im.chatwootManager.IsEnabled(sessionID)
 }
 
 // ProcessWhatsAppMessage processes a WhatsApp message for Chatwoot integration
-func (im *IntegrationManager) ProcessWhatsAppMessage(sessionID, messageID, from, content, messageType string, timestamp time.Time, fromMe bool) error {
-	ctx := context.Background()
+// quotedMessageID is the WhatsApp ID of the message being replied to, or empty when the message is not a reply
+func (im *IntegrationManager) ProcessWhatsAppMessage(ctx context.Context, sessionID, messageID, from, content, messageType string, timestamp time.Time, fromMe bool, quotedMessageID string) error {
+	err := im.relayWhatsAppMessage(ctx, sessionID, messageID, from, content, messageType, timestamp, fromMe, quotedMessageID)
+	if err != nil && im.errors != nil {
+		im.errors.RecordChatwootError(&activity.ChatwootError{
+			SessionID: sessionID,
+			Direction: activity.RelayToChatwoot,
+			Event:     messageType,
+			Error:     err.Error(),
+		})
+	}
+	return err
+}
 
+// relayWhatsAppMessage maps a WhatsApp message and forwards it to Chatwoot
+func (im *IntegrationManager) relayWhatsAppMessage(ctx context.Context, sessionID, messageID, from, content, messageType string, timestamp time.Time, fromMe bool, quotedMessageID string) error {
 	// Skip if message is already mapped (originated from Chatwoot)
 	if im.messageMapper.IsMessageMapped(ctx, sessionID, messageID) {
 		return nil
 	}
 
-	// Create message mapping
-	if err := im.createMessageMapping(ctx, sessionID, messageID, from, messageType, content, timestamp, fromMe); err != nil {
+	// Create message mapping; a message stored concurrently by another delivery is not forwarded again
+	created, err := im.createMessageMapping(ctx, sessionID, messageID, from, messageType, content, timestamp, fromMe)
+	if err != nil {
 		return err
 	}
+	if !created {
+		return nil
+	}
 
 	// Process message through Chatwoot
-	return im.processMessageToChatwoot(ctx, sessionID, messageID, from, content, messageType, fromMe)
+	return im.processMessageToChatwoot(ctx, sessionID, messageID, from, content, messageType, fromMe, quotedMessageID)
 }
 
-// createMessageMapping creates initial message mapping
-func (im *IntegrationManager) createMessageMapping(ctx context.Context, sessionID, messageID, from, messageType, content string, timestamp time.Time, fromMe bool) error {
-	chatJID := im.extractChatJID(from)
+// ProcessReadReceipt marks the Chatwoot copies of WhatsApp messages as read when the contact reads them
+func (im *IntegrationManager) ProcessReadReceipt(ctx context.Context, sessionID string, messageIDs []string) error {
+	config, err := im.chatwootManager.GetConfig(sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to get Chatwoot config: %w", err)
+	}
+
+	if !config.SyncReads {
+		return nil
+	}
 
-	_, err := im.messageMapper.CreateMapping(ctx, sessionID, messageID, from, chatJID, messageType, content, timestamp, fromMe)
+	client, err := im.chatwootManager.GetClient(sessionID)
 	if err != nil {
-		return fmt.Errorf("failed to create message mapping: %w", err)
+		return fmt.Errorf("failed to get Chatwoot client: %w", err)
+	}
+	client = tracedClient(ctx, client)
+
+	for _, messageID := range messageIDs {
+		mapping, err := im.messageMapper.GetMappingByZpID(ctx, sessionID, messageID)
+		if err != nil || mapping.CwMessageID == nil || mapping.CwConversationID == nil {
+			continue
+		}
+
+		if err := client.UpdateMessageStatus(*mapping.CwConversationID, *mapping.CwMessageID, "read"); err != nil {
+			im.logger.WarnWithFields("Failed to mark Chatwoot message as read", map[string]interface{}{
+				"session_id":    sessionID,
+				"message_id":    messageID,
+				"cw_message_id": *mapping.CwMessageID,
+				"error":         err.Error(),
+			})
+		}
 	}
 
 	return nil
 }
 
+// ProcessMessageRevoke deletes the Chatwoot copy of a WhatsApp message that was revoked
+func (im *IntegrationManager) ProcessMessageRevoke(ctx context.Context, sessionID, messageID string) error {
+	mapping, err := im.messageMapper.GetMappingByZpID(ctx, sessionID, messageID)
+	if err != nil || mapping.CwMessageID == nil || mapping.CwConversationID == nil {
+		return nil
+	}
+
+	// Already deleted, either in Chatwoot by an agent or by an earlier revoke
+	if mapping.SyncStatus == "deleted" {
+		return nil
+	}
+
+	client, err := im.chatwootManager.GetClient(sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to get Chatwoot client: %w", err)
+	}
+	client = tracedClient(ctx, client)
+
+	// Marked first so the message_updated webhook Chatwoot sends for the deletion is not synced back
+	if err := im.messageMapper.MarkAsDeleted(ctx, sessionID, messageID); err != nil {
+		return err
+	}
+
+	if err := client.DeleteMessage(*mapping.CwConversationID, *mapping.CwMessageID); err != nil {
+		_ = im.messageMapper.UpdateMapping(ctx, sessionID, messageID, *mapping.CwMessageID, *mapping.CwConversationID)
+		return fmt.Errorf("failed to delete Chatwoot message: %w", err)
+	}
+
+	im.logger.InfoWithFields("Revoked WhatsApp message deleted in Chatwoot", map[string]interface{}{
+		"session_id":    sessionID,
+		"message_id":    messageID,
+		"cw_message_id": *mapping.CwMessageID,
+	})
+
+	return nil
+}
+
+// createMessageMapping creates initial message mapping and reports whether the message was new
+func (im *IntegrationManager) createMessageMapping(ctx context.Context, sessionID, messageID, from, messageType, content string, timestamp time.Time, fromMe bool) (bool, error) {
+	chatJID := im.extractChatJID(from)
+
+	_, created, err := im.messageMapper.CreateMapping(ctx, sessionID, messageID, from, chatJID, messageType, content, timestamp, fromMe)
+	if err != nil {
+		return false, fmt.Errorf("failed to create message mapping: %w", err)
+	}
+
+	return created, nil
+}
+
 // extractChatJID extracts chat JID from sender
 func (im *IntegrationManager) extractChatJID(from string) string {
 	// For both groups and individual messages, use the from field as chatJID
@@ -83,7 +186,7 @@ func (im *IntegrationManager) extractChatJID(from string) string {
 }
 
 // processMessageToChatwoot handles the Chatwoot integration flow
-func (im *IntegrationManager) processMessageToChatwoot(ctx context.Context, sessionID, messageID, from, content, messageType string, fromMe bool) error {
+func (im *IntegrationManager) processMessageToChatwoot(ctx context.Context, sessionID, messageID, from, content, messageType string, fromMe bool, quotedMessageID string) error {
 	// Setup Chatwoot client and extract phone number
 	client, phoneNumber, err := im.setupChatwootClient(ctx, sessionID, messageID, from)
 	if err != nil {
@@ -103,7 +206,7 @@ func (im *IntegrationManager) processMessageToChatwoot(ctx context.Context, sess
 	}
 
 	// Send message to Chatwoot
-	chatwootMessage, err := im.sendMessageToChatwoot(client, conversation.ID, content, messageType, fromMe, ctx, sessionID, messageID)
+	chatwootMessage, err := im.sendMessageToChatwoot(client, conversation.ID, content, messageType, fromMe, ctx, sessionID, messageID, quotedMessageID)
 	if err != nil {
 		return err
 	}
@@ -112,6 +215,18 @@ func (im *IntegrationManager) processMessageToChatwoot(ctx context.Context, sess
 	return im.finalizeMessageProcessing(ctx, sessionID, messageID, chatwootMessage.ID, conversation.ID)
 }
 
+// tracedClient makes the client send the ID of the API request that triggered the relay, if any
+func tracedClient(ctx context.Context, client ports.ChatwootClient) ports.ChatwootClient {
+	requestID := logger.RequestIDFromContext(ctx)
+	if requestID == "" {
+		return client
+	}
+	if c, ok := client.(*Client); ok {
+		return c.WithRequestID(requestID)
+	}
+	return client
+}
+
 // setupChatwootClient sets up the Chatwoot client and extracts phone number
 func (im *IntegrationManager) setupChatwootClient(ctx context.Context, sessionID, messageID, from string) (ports.ChatwootClient, string, error) {
 	// Get Chatwoot client
@@ -120,6 +235,7 @@ func (im *IntegrationManager) setupChatwootClient(ctx context.Context, sessionID
 		_ = im.messageMapper.MarkAsFailed(ctx, sessionID, messageID)
 		return nil, "", fmt.Errorf("failed to get Chatwoot client: %w", err)
 	}
+	client = tracedClient(ctx, client)
 
 	// Extract phone number from JID
 	phoneNumber := im.extractPhoneFromJID(from)
@@ -171,7 +287,7 @@ func (im *IntegrationManager) setupContactAndConversation(client ports.ChatwootC
 }
 
 // sendMessageToChatwoot sends the formatted message to Chatwoot
-func (im *IntegrationManager) sendMessageToChatwoot(client ports.ChatwootClient, conversationID int, content, messageType string, fromMe bool, ctx context.Context, sessionID, messageID string) (*ports.ChatwootMessage, error) {
+func (im *IntegrationManager) sendMessageToChatwoot(client ports.ChatwootClient, conversationID int, content, messageType string, fromMe bool, ctx context.Context, sessionID, messageID, quotedMessageID string) (*ports.ChatwootMessage, error) {
 	// Format content for Chatwoot
 	formattedContent := im.formatContentForChatwoot(content, messageType)
 
@@ -181,8 +297,14 @@ func (im *IntegrationManager) sendMessageToChatwoot(client ports.ChatwootClient,
 		chatwootMessageType = "outgoing" // messages sent by agent/phone
 	}
 
-	// Send message to Chatwoot with correct type
-	chatwootMessage, err := client.SendMessageWithType(conversationID, formattedContent, chatwootMessageType)
+	// Send message to Chatwoot with correct type, as a reply when the quoted message is known
+	var chatwootMessage *ports.ChatwootMessage
+	var err error
+	if replyToID := im.resolveQuotedChatwootMessageID(ctx, sessionID, quotedMessageID); replyToID > 0 {
+		chatwootMessage, err = client.SendMessageWithReply(conversationID, formattedContent, chatwootMessageType, replyToID)
+	} else {
+		chatwootMessage, err = client.SendMessageWithType(conversationID, formattedContent, chatwootMessageType)
+	}
 	if err != nil {
 		_ = im.messageMapper.MarkAsFailed(ctx, sessionID, messageID)
 		return nil, fmt.Errorf("failed to send message to Chatwoot: %w", err)
@@ -191,6 +313,26 @@ func (im *IntegrationManager) sendMessageToChatwoot(client ports.ChatwootClient,
 	return chatwootMessage, nil
 }
 
+// resolveQuotedChatwootMessageID maps a quoted WhatsApp message ID to its Chatwoot message ID
+// Returns 0 when there is no quote or the quoted message was never synced to Chatwoot
+func (im *IntegrationManager) resolveQuotedChatwootMessageID(ctx context.Context, sessionID, quotedMessageID string) int {
+	if quotedMessageID == "" {
+		return 0
+	}
+
+	cwMessageID, err := im.messageMapper.GetChatwootMessageID(ctx, sessionID, quotedMessageID)
+	if err != nil {
+		im.logger.DebugWithFields("Quoted message not mapped to Chatwoot, sending without reply", map[string]interface{}{
+			"session_id":        sessionID,
+			"quoted_message_id": quotedMessageID,
+			"error":             err.Error(),
+		})
+		return 0
+	}
+
+	return cwMessageID
+}
+
 // finalizeMessageProcessing updates mapping and logs success
 func (im *IntegrationManager) finalizeMessageProcessing(ctx context.Context, sessionID, messageID string, chatwootMessageID, conversationID int) error {
 	// Update mapping with Chatwoot IDs
@@ -242,9 +384,18 @@ func (im *IntegrationManager) extractPhoneFromJID(jid string) string {
 	// Step 5: Format Brazilian numbers (like Evolution API formatBRNumber)
 	phone = im.formatBrazilianPhone(phone)
 
-	// Step 6: Add + prefix for E164 format (required by Chatwoot)
-	if !strings.HasPrefix(phone, "+") {
-		phone = "+" + phone
+	// Step 6: Format to E164 (required by Chatwoot); numbers the country's plan rejects are still
+	// synced with a + prefix so the conversation is not lost, and flagged in the logs
+	if e164, err := platformPhone.FormatE164(phone); err == nil {
+		phone = e164
+	} else {
+		im.logger.WarnWithFields("Contact phone is not a possible number", map[string]interface{}{
+			"original_jid": jid,
+			"error":        err.Error(),
+		})
+		if !strings.HasPrefix(phone, "+") {
+			phone = "+" + phone
+		}
 	}
 
 	im.logger.DebugWithFields("Extracted phone from JID", map[string]interface{}{
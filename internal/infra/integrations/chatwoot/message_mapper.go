@@ -25,8 +25,9 @@ func NewMessageMapper(logger *logger.Logger, repository ports.ChatwootMessageRep
 	}
 }
 
-// CreateMapping creates a new mapping between WhatsApp and Chatwoot message IDs
-func (mm *MessageMapper) CreateMapping(ctx context.Context, sessionID, zpMessageID, zpSender, zpChat, zpType, content string, zpTimestamp time.Time, zpFromMe bool) (*ports.ZpMessage, error) {
+// CreateMapping stores the mapping of a WhatsApp message, reusing the existing row when the message was already stored.
+// created reports whether the message was new
+func (mm *MessageMapper) CreateMapping(ctx context.Context, sessionID, zpMessageID, zpSender, zpChat, zpType, content string, zpTimestamp time.Time, zpFromMe bool) (*ports.ZpMessage, bool, error) {
 
 	mapping := &ports.ZpMessage{
 		ID:          uuid.New().String(),
@@ -43,12 +44,12 @@ func (mm *MessageMapper) CreateMapping(ctx context.Context, sessionID, zpMessage
 		UpdatedAt:   time.Now(),
 	}
 
-	err := mm.repository.CreateMessage(ctx, mapping)
+	created, err := mm.repository.UpsertMessage(ctx, mapping)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create message mapping: %w", err)
+		return nil, false, fmt.Errorf("failed to create message mapping: %w", err)
 	}
 
-	return mapping, nil
+	return mapping, created, nil
 }
 
 // UpdateMapping updates an existing mapping with Chatwoot IDs
@@ -85,13 +86,14 @@ func (mm *MessageMapper) GetMappingByZpID(ctx context.Context, sessionID, zpMess
 	return mapping, nil
 }
 
-// GetMappingByCwID gets mapping by Chatwoot message ID
-func (mm *MessageMapper) GetMappingByCwID(ctx context.Context, cwMessageID int) (*ports.ZpMessage, error) {
+// GetMappingByCwID gets the session's mapping by Chatwoot message ID
+func (mm *MessageMapper) GetMappingByCwID(ctx context.Context, sessionID string, cwMessageID int) (*ports.ZpMessage, error) {
 	mm.logger.DebugWithFields("Getting mapping by CW ID", map[string]interface{}{
+		"session_id":    sessionID,
 		"cw_message_id": cwMessageID,
 	})
 
-	mapping, err := mm.repository.GetMessageByCwID(ctx, cwMessageID)
+	mapping, err := mm.repository.GetMessageByCwID(ctx, sessionID, cwMessageID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get mapping by CW ID: %w", err)
 	}
@@ -99,6 +101,16 @@ func (mm *MessageMapper) GetMappingByCwID(ctx context.Context, cwMessageID int)
 	return mapping, nil
 }
 
+// GetMappingsByCwConversation gets the most recent mappings of a Chatwoot conversation
+func (mm *MessageMapper) GetMappingsByCwConversation(ctx context.Context, sessionID string, cwConversationID, limit int) ([]*ports.ZpMessage, error) {
+	mappings, err := mm.repository.GetMessagesByCwConversation(ctx, sessionID, cwConversationID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mappings by CW conversation: %w", err)
+	}
+
+	return mappings, nil
+}
+
 // MarkAsFailed marks a mapping as failed
 func (mm *MessageMapper) MarkAsFailed(ctx context.Context, sessionID, zpMessageID string) error {
 	// Get existing mapping
@@ -116,6 +128,30 @@ func (mm *MessageMapper) MarkAsFailed(ctx context.Context, sessionID, zpMessageI
 	return nil
 }
 
+// MarkAsDeleted marks a mapping as deleted
+func (mm *MessageMapper) MarkAsDeleted(ctx context.Context, sessionID, zpMessageID string) error {
+	mapping, err := mm.repository.GetMessageByZpID(ctx, sessionID, zpMessageID)
+	if err != nil {
+		return fmt.Errorf("failed to get existing mapping: %w", err)
+	}
+
+	err = mm.repository.UpdateSyncStatus(ctx, mapping.ID, "deleted", nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to mark mapping as deleted: %w", err)
+	}
+
+	return nil
+}
+
+// MarkAsRead records that a read receipt was sent for the messages
+func (mm *MessageMapper) MarkAsRead(ctx context.Context, sessionID string, zpMessageIDs []string) error {
+	if err := mm.repository.MarkReadSent(ctx, sessionID, zpMessageIDs); err != nil {
+		return fmt.Errorf("failed to mark mappings as read: %w", err)
+	}
+
+	return nil
+}
+
 // GetPendingMappings gets all pending mappings for a session
 func (mm *MessageMapper) GetPendingMappings(ctx context.Context, sessionID string, limit int) ([]*ports.ZpMessage, error) {
 	mappings, err := mm.repository.GetPendingSyncMessages(ctx, sessionID, limit)
@@ -169,6 +205,8 @@ func (mm *MessageMapper) GetMappingStats(ctx context.Context, sessionID string)
 			stats.Synced++
 		case "failed":
 			stats.Failed++
+		case "deleted":
+			stats.Deleted++
 		}
 	}
 
@@ -182,6 +220,7 @@ type MappingStats struct {
 	Pending   int    `json:"pending"`
 	Synced    int    `json:"synced"`
 	Failed    int    `json:"failed"`
+	Deleted   int    `json:"deleted"`
 }
 
 // IsMessageMapped checks if a WhatsApp message is already mapped
@@ -209,8 +248,8 @@ func (mm *MessageMapper) GetChatwootMessageID(ctx context.Context, sessionID, zp
 }
 
 // GetWhatsAppMessageID gets the WhatsApp message ID for a Chatwoot message
-func (mm *MessageMapper) GetWhatsAppMessageID(ctx context.Context, cwMessageID int) (string, error) {
-	mapping, err := mm.GetMappingByCwID(ctx, cwMessageID)
+func (mm *MessageMapper) GetWhatsAppMessageID(ctx context.Context, sessionID string, cwMessageID int) (string, error) {
+	mapping, err := mm.GetMappingByCwID(ctx, sessionID, cwMessageID)
 	if err != nil {
 		return "", fmt.Errorf("mapping not found: %w", err)
 	}
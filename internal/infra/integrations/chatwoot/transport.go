@@ -0,0 +1,195 @@
+package chatwoot
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	// requestTimeout bounds one attempt of a Chatwoot API call
+	requestTimeout = 30 * time.Second
+	// maxAttempts is how often a call is tried before its error is returned
+	maxAttempts = 3
+	// retryBaseDelay is the first backoff when the response has no Retry-After header; it doubles per attempt
+	retryBaseDelay = 500 * time.Millisecond
+	// maxRetryDelay caps the wait before a retry, including the one asked for by Retry-After
+	maxRetryDelay = 30 * time.Second
+
+	// circuitFailureThreshold consecutive failed calls open the circuit of a client
+	circuitFailureThreshold = 5
+	// circuitOpenDuration is how long an open circuit rejects calls before letting one through
+	circuitOpenDuration = 30 * time.Second
+)
+
+// ErrCircuitOpen is returned without calling Chatwoot while it is considered down
+var ErrCircuitOpen = errors.New("chatwoot API unavailable, circuit open")
+
+var (
+	requestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "zpwoot_chatwoot_requests_total",
+		Help: "Chatwoot API calls by HTTP status; error counts transport failures and circuit_open rejected calls",
+	}, []string{"host", "method", "status"})
+	retriesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "zpwoot_chatwoot_retries_total",
+		Help: "Chatwoot API attempts retried after a rate limit, server error or transport failure",
+	}, []string{"host", "reason"})
+	requestSecondsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "zpwoot_chatwoot_request_seconds_total",
+		Help: "Time spent in Chatwoot API attempts",
+	}, []string{"host"})
+	circuitOpen = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "zpwoot_chatwoot_circuit_open",
+		Help: "Chatwoot clients whose circuit is open",
+	}, []string{"host"})
+)
+
+// sharedTransport pools connections across the clients of all sessions, which mostly talk to the
+// same Chatwoot instance
+var sharedTransport = &http.Transport{
+	Proxy: http.ProxyFromEnvironment,
+	DialContext: (&net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}).DialContext,
+	ForceAttemptHTTP2:     true,
+	MaxIdleConns:          100,
+	MaxIdleConnsPerHost:   20,
+	IdleConnTimeout:       90 * time.Second,
+	TLSHandshakeTimeout:   10 * time.Second,
+	ExpectContinueTimeout: time.Second,
+}
+
+func newHTTPClient() *http.Client {
+	return &http.Client{
+		Timeout:   requestTimeout,
+		Transport: sharedTransport,
+	}
+}
+
+// circuitBreaker stops calling Chatwoot after repeated failures, and lets a single call through
+// once circuitOpenDuration has passed to find out whether it recovered
+type circuitBreaker struct {
+	host string
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+func newCircuitBreaker(host string) *circuitBreaker {
+	return &circuitBreaker{host: host}
+}
+
+// allow reports whether a call may go out
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < circuitFailureThreshold {
+		return true
+	}
+	if time.Now().Before(b.openUntil) || b.probing {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+// record closes the circuit after a success and opens it once the failure threshold is reached
+func (b *circuitBreaker) record(success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	wasOpen := b.failures >= circuitFailureThreshold
+	b.probing = false
+
+	if success {
+		b.failures = 0
+		if wasOpen {
+			circuitOpen.WithLabelValues(b.host).Add(-1)
+		}
+		return
+	}
+
+	b.failures++
+	if b.failures >= circuitFailureThreshold {
+		b.openUntil = time.Now().Add(circuitOpenDuration)
+		if !wasOpen {
+			circuitOpen.WithLabelValues(b.host).Add(1)
+		}
+	}
+}
+
+// isRetryable reports whether a failed attempt may be repeated. Rate limited and unavailable
+// responses were not processed and are always retried; other server errors and transport failures
+// only for idempotent methods, so a message is never posted twice.
+func isRetryable(method string, statusCode int) bool {
+	if statusCode == http.StatusTooManyRequests || statusCode == http.StatusServiceUnavailable {
+		return true
+	}
+
+	idempotent := method == http.MethodGet || method == http.MethodHead || method == http.MethodPut || method == http.MethodDelete
+	if !idempotent {
+		return false
+	}
+
+	// statusCode 0 is a transport failure
+	return statusCode == 0 || statusCode >= 500
+}
+
+// retryDelay returns the wait before the given retry, preferring the server's Retry-After
+func retryDelay(resp *http.Response, retry int) time.Duration {
+	if resp != nil {
+		if delay, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			if delay > maxRetryDelay {
+				return maxRetryDelay
+			}
+			return delay
+		}
+	}
+
+	delay := retryBaseDelay << (retry - 1)
+	if delay > maxRetryDelay {
+		return maxRetryDelay
+	}
+	return delay
+}
+
+// parseRetryAfter parses a Retry-After header in seconds or as an HTTP date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		delay := time.Until(at)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
+
+// hostLabel returns the host of a Chatwoot base URL for metric labels
+func hostLabel(baseURL string) string {
+	parsed, err := url.Parse(baseURL)
+	if err != nil || parsed.Host == "" {
+		return "unknown"
+	}
+	return parsed.Host
+}
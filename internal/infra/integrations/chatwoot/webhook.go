@@ -3,9 +3,9 @@ package chatwoot
 import (
 	"context"
 	"fmt"
-	"time"
 
 	chatwootdomain "zpwoot/internal/domain/chatwoot"
+	"zpwoot/internal/domain/history"
 	"zpwoot/internal/ports"
 	"zpwoot/platform/logger"
 )
@@ -32,9 +32,6 @@ func NewWebhookHandler(logger *logger.Logger, manager ports.ChatwootManager, wam
 
 // ProcessWebhook processes incoming webhooks from Chatwoot
 func (h *WebhookHandler) ProcessWebhook(ctx context.Context, webhook *chatwootdomain.ChatwootWebhookPayload, sessionID string) error {
-	// Delay 500ms to avoid race conditions (based on Evolution API)
-	time.Sleep(500 * time.Millisecond)
-
 	// Filter private messages
 	if h.isPrivateMessage(webhook) {
 		return nil
@@ -141,11 +138,14 @@ func (h *WebhookHandler) sendToWhatsApp(ctx context.Context, webhook *chatwootdo
 	content := h.formatContentForWhatsApp(webhook.Message.Content)
 
 	// Send message to WhatsApp using wameowManager
-	_, err := h.wameowManager.SendMessage(sessionID, phoneNumber, "text", content, "", "", "", 0, 0, "", "", nil)
+	result, err := h.wameowManager.SendMessage(sessionID, phoneNumber, "text", content, "", "", "", "", 0, 0, "", "", nil)
 	if err != nil {
 		return fmt.Errorf("failed to send message to WhatsApp: %w", err)
 	}
 
+	actor := &history.Actor{Type: history.ActorChatwoot, ID: fmt.Sprintf("conversation:%d", webhook.Conversation.ID)}
+	h.wameowManager.TraceRequest(history.WithActor(ctx, actor), sessionID, result.MessageID)
+
 	return nil
 }
 
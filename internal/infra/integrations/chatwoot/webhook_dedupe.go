@@ -0,0 +1,35 @@
+package chatwoot
+
+import (
+	"time"
+
+	"zpwoot/platform/ttlset"
+)
+
+// DefaultWebhookDedupeTTL is how long a processed Chatwoot message is remembered
+const DefaultWebhookDedupeTTL = 10 * time.Minute
+
+// WebhookDeduplicator is an in-memory store of recently processed Chatwoot webhook keys.
+// Chatwoot retries webhook deliveries on timeouts, so each message must only be acted on once.
+type WebhookDeduplicator struct {
+	entries *ttlset.Set
+}
+
+// NewWebhookDeduplicator creates a new deduplicator with the given TTL
+func NewWebhookDeduplicator(ttl time.Duration) *WebhookDeduplicator {
+	if ttl <= 0 {
+		ttl = DefaultWebhookDedupeTTL
+	}
+
+	return &WebhookDeduplicator{entries: ttlset.New(ttl)}
+}
+
+// Claim records the key and returns true if it was not seen within the TTL
+func (d *WebhookDeduplicator) Claim(key string) bool {
+	return d.entries.Add(key)
+}
+
+// Release forgets the key so a later delivery can be processed again
+func (d *WebhookDeduplicator) Release(key string) {
+	d.entries.Remove(key)
+}
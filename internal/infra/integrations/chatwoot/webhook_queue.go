@@ -0,0 +1,327 @@
+package chatwoot
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"zpwoot/internal/domain/activity"
+	"zpwoot/internal/ports"
+	"zpwoot/platform/logger"
+)
+
+const (
+	defaultWebhookQueueSize  = 1000
+	defaultWebhookMaxRetries = 3
+	defaultWebhookRetryDelay = 2 * time.Second
+	maxRecentWebhookFailures = 100
+	webhookJobProcessTimeout = 60 * time.Second
+	webhookDrainPollInterval = 50 * time.Millisecond
+)
+
+// WebhookQueue processes Chatwoot webhooks in background workers so the HTTP request can be acknowledged immediately
+type WebhookQueue struct {
+	logger     *logger.Logger
+	jobs       chan *webhookJob
+	workers    int
+	maxRetries int
+	retryDelay time.Duration
+
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	mu       sync.RWMutex
+	started  bool
+	draining bool
+
+	// pending counts the accepted jobs not finished yet, including those waiting for a retry
+	pending atomic.Int64
+
+	processed int64
+	failed    int64
+	retried   int64
+
+	failuresMu sync.Mutex
+	failures   []ports.ChatwootWebhookFailure
+	errors     ErrorRecorder
+}
+
+// webhookJob represents a queued Chatwoot webhook
+type webhookJob struct {
+	sessionID string
+	event     string
+	process   func(ctx context.Context) error
+	attempt   int
+}
+
+// NewWebhookQueue creates a new Chatwoot webhook queue
+func NewWebhookQueue(logger *logger.Logger, workers int) *WebhookQueue {
+	if workers <= 0 {
+		workers = 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &WebhookQueue{
+		logger:     logger,
+		jobs:       make(chan *webhookJob, defaultWebhookQueueSize),
+		workers:    workers,
+		maxRetries: defaultWebhookMaxRetries,
+		retryDelay: defaultWebhookRetryDelay,
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// Start starts the background workers
+func (q *WebhookQueue) Start() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.started {
+		return
+	}
+
+	for i := 0; i < q.workers; i++ {
+		q.wg.Add(1)
+		go q.worker(i)
+	}
+
+	q.started = true
+	q.logger.InfoWithFields("Chatwoot webhook queue started", map[string]interface{}{
+		"workers": q.workers,
+	})
+}
+
+// Stop stops the workers and waits for in-flight jobs to finish; jobs still queued or waiting for a
+// retry are dropped, so Shutdown drains the queue first
+func (q *WebhookQueue) Stop() {
+	q.mu.Lock()
+	if !q.started {
+		q.mu.Unlock()
+		return
+	}
+	q.started = false
+	q.mu.Unlock()
+
+	q.cancel()
+	q.wg.Wait()
+
+	if dropped := q.pending.Load(); dropped > 0 {
+		q.logger.WarnWithFields("Chatwoot webhook queue stopped with unprocessed webhooks", map[string]interface{}{
+			"dropped": dropped,
+		})
+		return
+	}
+	q.logger.Info("Chatwoot webhook queue stopped")
+}
+
+// Drain stops accepting webhooks and waits until every accepted one, including pending retries, is
+// processed or ctx is done
+func (q *WebhookQueue) Drain(ctx context.Context) error {
+	q.mu.Lock()
+	q.draining = true
+	q.mu.Unlock()
+
+	ticker := time.NewTicker(webhookDrainPollInterval)
+	defer ticker.Stop()
+
+	for {
+		remaining := q.pending.Load()
+		if remaining <= 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			q.logger.WarnWithFields("Chatwoot webhook queue not drained in time", map[string]interface{}{
+				"remaining": remaining,
+			})
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Shutdown drains the queue within ctx, then stops the workers
+func (q *WebhookQueue) Shutdown(ctx context.Context) error {
+	drainErr := q.Drain(ctx)
+
+	stopped := make(chan struct{})
+	go func() {
+		q.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return drainErr
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Enqueue queues a webhook for processing; returns an error if the queue is not running or full
+func (q *WebhookQueue) Enqueue(sessionID, event string, process func(ctx context.Context) error) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if !q.started {
+		return ErrWebhookQueueNotStarted
+	}
+	if q.draining {
+		return ErrWebhookQueueDraining
+	}
+
+	job := &webhookJob{
+		sessionID: sessionID,
+		event:     event,
+		process:   process,
+		attempt:   1,
+	}
+
+	select {
+	case q.jobs <- job:
+		q.pending.Add(1)
+		return nil
+	default:
+		return ErrWebhookQueueFull
+	}
+}
+
+// GetStats returns queue statistics; recent failures are filtered by session when sessionID is set
+func (q *WebhookQueue) GetStats(sessionID string) *ports.ChatwootWebhookQueueStats {
+	q.mu.RLock()
+	started := q.started
+	q.mu.RUnlock()
+
+	q.failuresMu.Lock()
+	failures := make([]ports.ChatwootWebhookFailure, 0, len(q.failures))
+	for _, failure := range q.failures {
+		if sessionID == "" || failure.SessionID == sessionID {
+			failures = append(failures, failure)
+		}
+	}
+	q.failuresMu.Unlock()
+
+	return &ports.ChatwootWebhookQueueStats{
+		Started:        started,
+		Workers:        q.workers,
+		QueueSize:      len(q.jobs),
+		QueueCapacity:  cap(q.jobs),
+		Processed:      atomic.LoadInt64(&q.processed),
+		Failed:         atomic.LoadInt64(&q.failed),
+		Retried:        atomic.LoadInt64(&q.retried),
+		RecentFailures: failures,
+	}
+}
+
+// worker processes jobs from the queue
+func (q *WebhookQueue) worker(workerID int) {
+	defer q.wg.Done()
+
+	for {
+		select {
+		case <-q.ctx.Done():
+			return
+		case job := <-q.jobs:
+			q.processJob(workerID, job)
+		}
+	}
+}
+
+// processJob runs a job and schedules a retry on failure
+func (q *WebhookQueue) processJob(workerID int, job *webhookJob) {
+	ctx, cancel := context.WithTimeout(q.ctx, webhookJobProcessTimeout)
+	defer cancel()
+
+	err := job.process(ctx)
+	if err == nil {
+		atomic.AddInt64(&q.processed, 1)
+		q.pending.Add(-1)
+		return
+	}
+
+	if job.attempt < q.maxRetries && q.ctx.Err() == nil {
+		q.logger.WarnWithFields("Chatwoot webhook processing failed, will retry", map[string]interface{}{
+			"worker_id":  workerID,
+			"session_id": job.sessionID,
+			"event":      job.event,
+			"attempt":    job.attempt,
+			"error":      err.Error(),
+		})
+
+		atomic.AddInt64(&q.retried, 1)
+		job.attempt++
+		time.AfterFunc(q.retryDelay*time.Duration(job.attempt-1), func() {
+			// The workers are gone once the queue stopped, so the retry would never run
+			if q.ctx.Err() != nil {
+				q.recordFailure(job, fmt.Errorf("retry dropped, queue stopped: %w", err))
+				return
+			}
+			select {
+			case q.jobs <- job:
+			default:
+				q.recordFailure(job, fmt.Errorf("retry dropped, queue full: %w", err))
+			}
+		})
+		return
+	}
+
+	q.recordFailure(job, err)
+}
+
+// recordFailure keeps a bounded history of failed webhooks for visibility
+func (q *WebhookQueue) recordFailure(job *webhookJob, err error) {
+	atomic.AddInt64(&q.failed, 1)
+	q.pending.Add(-1)
+
+	q.logger.ErrorWithFields("Chatwoot webhook processing failed permanently", map[string]interface{}{
+		"session_id": job.sessionID,
+		"event":      job.event,
+		"attempts":   job.attempt,
+		"error":      err.Error(),
+	})
+
+	if q.errors != nil {
+		q.errors.RecordChatwootError(&activity.ChatwootError{
+			SessionID: job.sessionID,
+			Direction: activity.RelayFromChatwoot,
+			Event:     job.event,
+			Error:     err.Error(),
+		})
+	}
+
+	q.failuresMu.Lock()
+	defer q.failuresMu.Unlock()
+
+	q.failures = append(q.failures, ports.ChatwootWebhookFailure{
+		SessionID: job.sessionID,
+		Event:     job.event,
+		Error:     err.Error(),
+		Attempts:  job.attempt,
+		FailedAt:  time.Now(),
+	})
+	if len(q.failures) > maxRecentWebhookFailures {
+		q.failures = q.failures[len(q.failures)-maxRecentWebhookFailures:]
+	}
+}
+
+// SetErrorRecorder sets where webhooks that failed permanently are reported; it must be set before Start
+func (q *WebhookQueue) SetErrorRecorder(recorder ErrorRecorder) {
+	q.errors = recorder
+}
+
+// QueueDepth returns how many webhooks are waiting and the queue capacity. The queue is shared by all
+// sessions, so the depth is the same for every session.
+func (q *WebhookQueue) QueueDepth(sessionID string) (depth, capacity int) {
+	return len(q.jobs), cap(q.jobs)
+}
+
+// Errors
+var (
+	ErrWebhookQueueNotStarted = fmt.Errorf("chatwoot webhook queue is not started")
+	ErrWebhookQueueFull       = fmt.Errorf("chatwoot webhook queue is full")
+	ErrWebhookQueueDraining   = fmt.Errorf("chatwoot webhook queue is shutting down")
+)
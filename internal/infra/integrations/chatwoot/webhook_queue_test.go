@@ -0,0 +1,67 @@
+package chatwoot
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"zpwoot/platform/logger"
+)
+
+func TestWebhookQueueShutdownWaitsForRetries(t *testing.T) {
+	q := NewWebhookQueue(logger.New(), 1)
+	q.retryDelay = 10 * time.Millisecond
+	q.Start()
+
+	var attempts atomic.Int32
+	err := q.Enqueue("session", "message_created", func(ctx context.Context) error {
+		if attempts.Add(1) == 1 {
+			return errors.New("chatwoot unavailable")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := q.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+
+	if got := attempts.Load(); got != 2 {
+		t.Errorf("job ran %d times, want 2", got)
+	}
+	if err := q.Enqueue("session", "message_created", func(context.Context) error { return nil }); err == nil {
+		t.Error("Enqueue accepted a webhook after shutdown")
+	}
+}
+
+func TestWebhookQueueDropsRetriesAfterStop(t *testing.T) {
+	q := NewWebhookQueue(logger.New(), 1)
+	q.retryDelay = 20 * time.Millisecond
+	q.Start()
+
+	var attempts atomic.Int32
+	_ = q.Enqueue("session", "message_created", func(ctx context.Context) error {
+		attempts.Add(1)
+		return errors.New("chatwoot unavailable")
+	})
+
+	// Wait for the first attempt, then stop before its retry fires
+	for attempts.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	q.Stop()
+	time.Sleep(50 * time.Millisecond)
+
+	if got := attempts.Load(); got != 1 {
+		t.Errorf("job ran %d times after stop, want 1", got)
+	}
+	if depth, _ := q.QueueDepth(""); depth != 0 {
+		t.Errorf("retry was re-enqueued after stop, depth %d", depth)
+	}
+}
@@ -0,0 +1,94 @@
+package translation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Client calls a LibreTranslate-compatible /translate endpoint
+type Client struct {
+	url        string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewClient creates a translation provider client for the given endpoint URL
+func NewClient(url, apiKey string, timeout time.Duration) *Client {
+	return &Client{
+		url:    url,
+		apiKey: apiKey,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+type translateRequest struct {
+	Q      string `json:"q"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Format string `json:"format"`
+	APIKey string `json:"api_key,omitempty"`
+}
+
+type translateResponse struct {
+	TranslatedText   string `json:"translatedText"`
+	DetectedLanguage *struct {
+		Language string `json:"language"`
+	} `json:"detectedLanguage,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// Translate translates text from source to target; source "auto" asks the provider to detect it
+func (c *Client) Translate(ctx context.Context, text, source, target string) (string, string, error) {
+	payload, err := json.Marshal(translateRequest{
+		Q:      text,
+		Source: source,
+		Target: target,
+		Format: "text",
+		APIKey: c.apiKey,
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to marshal translation request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(payload))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create translation request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to call translation provider: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", "", fmt.Errorf("translation provider returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result translateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", "", fmt.Errorf("failed to decode translation response: %w", err)
+	}
+	if result.Error != "" {
+		return "", "", fmt.Errorf("translation provider error: %s", result.Error)
+	}
+
+	detected := ""
+	if result.DetectedLanguage != nil {
+		detected = result.DetectedLanguage.Language
+	}
+
+	return result.TranslatedText, detected, nil
+}
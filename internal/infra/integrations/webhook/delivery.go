@@ -5,16 +5,28 @@ import (
 	"context"
 	"crypto/hmac"
 	"crypto/sha256"
+	"crypto/tls"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+
+	"zpwoot/internal/domain/activity"
 	"zpwoot/internal/domain/webhook"
 	"zpwoot/internal/ports"
 	"zpwoot/platform/logger"
+	"zpwoot/platform/tracing"
 )
 
 // WebhookEventProcessor defines the interface for processing webhook events
@@ -22,16 +34,151 @@ type WebhookEventProcessor interface {
 	ProcessWebhookEvent(ctx context.Context, event *webhook.WebhookEvent) error
 }
 
+// DeliveryGate pauses webhook dispatch, e.g. while the instance or a session is in maintenance
+type DeliveryGate interface {
+	DispatchPaused(sessionID string) bool
+	// Changed returns a channel that is closed on the next change of the paused state
+	Changed() <-chan struct{}
+}
+
+// FailureRecorder keeps deliveries that failed after all retries, e.g. for the dashboard
+type FailureRecorder interface {
+	RecordWebhookFailure(failure *activity.WebhookFailure)
+}
+
+// DeliveryRecorder counts the final outcome of deliveries, e.g. for integration health
+type DeliveryRecorder interface {
+	RecordWebhookDelivery(sessionID string, success bool)
+}
+
+// EventRecorder stores dispatched events so they can be replayed
+type EventRecorder interface {
+	RecordEvent(ctx context.Context, event *webhook.WebhookEvent)
+}
+
+// EventStreamer sends dispatched events to the event streams open on their session
+type EventStreamer interface {
+	HasSubscribers(sessionID string) bool
+	Publish(sessionID, eventType string, payload []byte)
+}
+
+// Router evaluates routing rules for an event before it is dispatched
+type Router interface {
+	Route(ctx context.Context, event *webhook.WebhookEvent) *webhook.Routing
+}
+
+// maxParkedTasks bounds how many deliveries are held back while dispatch is paused
+const maxParkedTasks = 1000
+
+// drainPollInterval is how often Drain checks whether the queued deliveries are done
+const drainPollInterval = 100 * time.Millisecond
+
+// maxCachedTemplates bounds the compiled payload templates kept in memory
+const maxCachedTemplates = 256
+
+var (
+	deliveryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "zpwoot_webhook_delivery_duration_seconds",
+		Help: "Duration of webhook delivery attempts by result (success or failure)",
+	}, []string{"result"})
+	deliveriesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "zpwoot_webhook_deliveries_total",
+		Help: "Webhook deliveries by final result (delivered or failed), shadow webhooks excluded",
+	}, []string{"session", "result"})
+	deliveryRetriesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "zpwoot_webhook_delivery_retries_total",
+		Help: "Webhook delivery attempts that failed and were retried",
+	}, []string{"session"})
+)
+
+// maxResponseBodySize bounds how much of a receiver's response is read, so a receiver streaming a
+// large body cannot hold a worker
+const maxResponseBodySize = 64 * 1024
+
+// HTTPClientConfig configures the HTTP client webhooks are delivered with. Timeout bounds a whole
+// delivery attempt, including reading the response; webhooks may override it.
+type HTTPClientConfig struct {
+	ConnectTimeout      time.Duration
+	Timeout             time.Duration
+	TLSHandshakeTimeout time.Duration
+	MaxIdleConnsPerHost int
+	EnableHTTP2         bool
+}
+
+// DefaultHTTPClientConfig returns the settings used when none are configured
+func DefaultHTTPClientConfig() HTTPClientConfig {
+	return HTTPClientConfig{
+		ConnectTimeout:      5 * time.Second,
+		Timeout:             30 * time.Second,
+		TLSHandshakeTimeout: 10 * time.Second,
+		MaxIdleConnsPerHost: 10,
+		EnableHTTP2:         true,
+	}
+}
+
+// newHTTPClient creates the delivery HTTP client; deadlines are set per request so webhooks can
+// override the timeout
+func newHTTPClient(config HTTPClientConfig) *http.Client {
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   config.ConnectTimeout,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     config.EnableHTTP2,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   config.MaxIdleConnsPerHost,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   config.TLSHandshakeTimeout,
+		ExpectContinueTimeout: time.Second,
+	}
+	if !config.EnableHTTP2 {
+		// A non-nil empty map keeps the transport from negotiating HTTP/2
+		transport.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+	}
+
+	return &http.Client{Transport: transport}
+}
+
 // WebhookDeliveryService handles the delivery of webhook events to external endpoints
 type WebhookDeliveryService struct {
 	logger        *logger.Logger
 	webhookRepo   ports.WebhookRepository
 	httpClient    *http.Client
+	timeout       time.Duration
 	maxRetries    int
 	retryDelay    time.Duration
 	deliveryQueue chan *DeliveryTask
-	workers       int
 	processors    []WebhookEventProcessor // Additional processors for webhook events
+
+	// workers is the configured concurrency; workerStops stops each running worker, see SetWorkers
+	workersMu    sync.Mutex
+	workers      int
+	workerCtx    context.Context
+	workerStops  []chan struct{}
+	nextWorkerID int
+
+	// Dispatch paused through the admin API, next to the gate; resumed wakes releaseParked
+	pauseMu        sync.RWMutex
+	pausedAll      bool
+	pausedSessions map[string]time.Time
+	resumed        chan struct{}
+
+	gate       DeliveryGate
+	recorder   FailureRecorder
+	deliveries DeliveryRecorder
+	router     Router
+	events     EventRecorder
+	streamer   EventStreamer
+	parkedMu   sync.Mutex
+	parked     []*DeliveryTask
+	// pending counts the tasks queued, being delivered or waiting for a retry, for Drain; it is
+	// raised before a task is queued so a task never goes uncounted
+	pending atomic.Int64
+
+	// templates caches compiled payload templates by their text
+	templatesMu sync.Mutex
+	templates   map[string]*webhook.PayloadTemplate
 }
 
 // DeliveryTask represents a webhook delivery task
@@ -48,6 +195,13 @@ type WebhookPayload struct {
 	SessionID string                 `json:"sessionId"`
 	Timestamp int64                  `json:"timestamp"`
 	Data      map[string]interface{} `json:"data"`
+	Routing   *webhook.Routing       `json:"routing,omitempty"`
+	// Translation keeps the original text when the message was translated
+	Translation *webhook.Translation `json:"translation,omitempty"`
+	// RequestID is the X-Request-ID of the API call that triggered the event
+	RequestID string `json:"requestId,omitempty"`
+	// Replay is set when a stored event is delivered again
+	Replay bool `json:"replay,omitempty"`
 }
 
 // DeliveryResult represents the result of a webhook delivery attempt
@@ -70,16 +224,20 @@ func NewWebhookDeliveryService(
 		workers = 5 // Default number of workers
 	}
 
+	httpConfig := DefaultHTTPClientConfig()
+
 	return &WebhookDeliveryService{
-		logger:      logger,
-		webhookRepo: webhookRepo,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-		maxRetries:    3,
-		retryDelay:    2 * time.Second,
-		deliveryQueue: make(chan *DeliveryTask, 1000), // Buffer for 1000 tasks
-		workers:       workers,
+		logger:         logger,
+		webhookRepo:    webhookRepo,
+		httpClient:     newHTTPClient(httpConfig),
+		timeout:        httpConfig.Timeout,
+		maxRetries:     3,
+		retryDelay:     2 * time.Second,
+		deliveryQueue:  make(chan *DeliveryTask, 1000), // Buffer for 1000 tasks
+		workers:        workers,
+		pausedSessions: make(map[string]time.Time),
+		resumed:        make(chan struct{}, 1),
+		templates:      make(map[string]*webhook.PayloadTemplate),
 	}
 }
 
@@ -88,20 +246,177 @@ func (s *WebhookDeliveryService) AddProcessor(processor WebhookEventProcessor) {
 	s.processors = append(s.processors, processor)
 }
 
+// SetGate sets the gate that pauses dispatch; it must be set before Start
+func (s *WebhookDeliveryService) SetGate(gate DeliveryGate) {
+	s.gate = gate
+}
+
+// SetFailureRecorder sets where permanent delivery failures are reported; it must be set before Start
+func (s *WebhookDeliveryService) SetFailureRecorder(recorder FailureRecorder) {
+	s.recorder = recorder
+}
+
+// SetDeliveryRecorder sets where final delivery outcomes are counted; it must be set before Start
+func (s *WebhookDeliveryService) SetDeliveryRecorder(recorder DeliveryRecorder) {
+	s.deliveries = recorder
+}
+
+// SetHTTPClientConfig replaces the delivery HTTP client settings; it must be set before Start
+func (s *WebhookDeliveryService) SetHTTPClientConfig(config HTTPClientConfig) {
+	s.httpClient = newHTTPClient(config)
+	s.timeout = config.Timeout
+}
+
+// SetEventRecorder sets where dispatched events are stored for replays; it must be set before Start
+func (s *WebhookDeliveryService) SetEventRecorder(recorder EventRecorder) {
+	s.events = recorder
+}
+
+// SetRouter sets the routing rules evaluator; it must be set before Start
+func (s *WebhookDeliveryService) SetRouter(router Router) {
+	s.router = router
+}
+
+// SetEventStreamer sets where dispatched events are streamed; it must be set before Start
+func (s *WebhookDeliveryService) SetEventStreamer(streamer EventStreamer) {
+	s.streamer = streamer
+}
+
 // Start initializes the webhook delivery workers
 func (s *WebhookDeliveryService) Start(ctx context.Context) {
+	s.workersMu.Lock()
 	s.logger.InfoWithFields("Starting webhook delivery service", map[string]interface{}{
 		"workers": s.workers,
 	})
 
 	// Start worker goroutines
+	s.workerCtx = ctx
 	for i := 0; i < s.workers; i++ {
-		go s.worker(ctx, i)
+		s.startWorkerLocked()
+	}
+	s.workersMu.Unlock()
+
+	go s.releaseParked(ctx)
+}
+
+// startWorkerLocked starts one more worker; the caller holds workersMu
+func (s *WebhookDeliveryService) startWorkerLocked() {
+	stop := make(chan struct{})
+	s.workerStops = append(s.workerStops, stop)
+	go s.worker(s.workerCtx, s.nextWorkerID, stop)
+	s.nextWorkerID++
+}
+
+// Drain waits until every queued delivery, including pending retries, is finished or ctx is done.
+// Deliveries parked while dispatch is paused are not waited for. Callers stop producing events first.
+func (s *WebhookDeliveryService) Drain(ctx context.Context) error {
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+
+	for {
+		remaining := s.pending.Load()
+		if remaining <= 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			s.logger.WarnWithFields("Webhook queue not drained in time, dropping deliveries", map[string]interface{}{
+				"remaining": remaining,
+			})
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// park holds a delivery back while dispatch is paused for its session and reports whether it did.
+// The paused state is checked under the parked lock so a resume cannot miss the task.
+func (s *WebhookDeliveryService) park(task *DeliveryTask) bool {
+	s.parkedMu.Lock()
+	defer s.parkedMu.Unlock()
+
+	if !s.dispatchPaused(task.Event.SessionID) {
+		return false
+	}
+
+	if len(s.parked) >= maxParkedTasks {
+		s.logger.WarnWithFields("Too many paused webhook deliveries, dropping task", map[string]interface{}{
+			"webhook_id": task.WebhookConfig.ID.String(),
+			"event_id":   task.Event.ID,
+		})
+		return true
 	}
+
+	s.parked = append(s.parked, task)
+	return true
 }
 
-// worker processes webhook delivery tasks
-func (s *WebhookDeliveryService) worker(ctx context.Context, workerID int) {
+// parkedCount returns how many deliveries are held back while dispatch is paused
+func (s *WebhookDeliveryService) parkedCount() int {
+	s.parkedMu.Lock()
+	defer s.parkedMu.Unlock()
+	return len(s.parked)
+}
+
+// QueueDepth returns how many deliveries are waiting, queued or parked, and the queue capacity. The
+// queue is shared by all sessions, so the depth is the same for every session.
+func (s *WebhookDeliveryService) QueueDepth(sessionID string) (depth, capacity int) {
+	return len(s.deliveryQueue) + s.parkedCount(), cap(s.deliveryQueue)
+}
+
+// releaseParked requeues held back deliveries whenever dispatch resumes for their session
+func (s *WebhookDeliveryService) releaseParked(ctx context.Context) {
+	var changed <-chan struct{}
+	if s.gate != nil {
+		changed = s.gate.Changed()
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-changed:
+		case <-s.resumed:
+		}
+
+		// Watch for the next change before scanning so none is missed
+		if s.gate != nil {
+			changed = s.gate.Changed()
+		}
+
+		s.parkedMu.Lock()
+		var ready []*DeliveryTask
+		remaining := s.parked[:0]
+		for _, task := range s.parked {
+			if s.dispatchPaused(task.Event.SessionID) {
+				remaining = append(remaining, task)
+			} else {
+				ready = append(ready, task)
+			}
+		}
+		s.parked = remaining
+		s.parkedMu.Unlock()
+
+		if len(ready) > 0 {
+			s.logger.InfoWithFields("Resuming paused webhook deliveries", map[string]interface{}{
+				"count": len(ready),
+			})
+		}
+
+		for _, task := range ready {
+			s.pending.Add(1)
+			select {
+			case s.deliveryQueue <- task:
+			case <-ctx.Done():
+				s.pending.Add(-1)
+				return
+			}
+		}
+	}
+}
+
+// worker processes webhook delivery tasks until ctx is done or stop is closed
+func (s *WebhookDeliveryService) worker(ctx context.Context, workerID int, stop <-chan struct{}) {
 	s.logger.InfoWithFields("Starting webhook worker", map[string]interface{}{
 		"worker_id": workerID,
 	})
@@ -113,8 +428,16 @@ func (s *WebhookDeliveryService) worker(ctx context.Context, workerID int) {
 				"worker_id": workerID,
 			})
 			return
+		case <-stop:
+			s.logger.InfoWithFields("Stopping webhook worker", map[string]interface{}{
+				"worker_id": workerID,
+			})
+			return
 		case task := <-s.deliveryQueue:
-			s.processDeliveryTask(ctx, task, workerID)
+			if !s.park(task) {
+				s.processDeliveryTask(ctx, task, workerID)
+			}
+			s.pending.Add(-1)
 		}
 	}
 }
@@ -141,17 +464,10 @@ func (s *WebhookDeliveryService) DeliverEvent(ctx context.Context, event *webhoo
 		})
 	}
 
-	// Process event with additional processors (like Chatwoot)
-	for _, processor := range s.processors {
-		if err := processor.ProcessWebhookEvent(ctx, event); err != nil {
-			s.logger.ErrorWithFields("Processor failed to handle event", map[string]interface{}{
-				"event_id":   event.ID,
-				"event_type": event.Type,
-				"session_id": event.SessionID,
-				"error":      err.Error(),
-			})
-			// Continue with other processors even if one fails
-		}
+	// Replays were routed and processed when first dispatched and are not stored or streamed again
+	if !event.Replay {
+		s.prepareEvent(ctx, event)
+		s.streamEvent(event)
 	}
 
 	// Get webhooks that should receive this event
@@ -176,6 +492,22 @@ func (s *WebhookDeliveryService) DeliverEvent(ctx context.Context, event *webhoo
 			Attempt:       1,
 			MaxAttempts:   s.maxRetries,
 		}
+		if webhookConfig.Shadow {
+			// Shadow deliveries get a single attempt
+			task.MaxAttempts = 1
+		}
+
+		s.pending.Add(1)
+		if event.Replay {
+			// Replays wait for room in the queue rather than being dropped
+			select {
+			case s.deliveryQueue <- task:
+			case <-ctx.Done():
+				s.pending.Add(-1)
+				return ctx.Err()
+			}
+			continue
+		}
 
 		select {
 		case s.deliveryQueue <- task:
@@ -184,6 +516,7 @@ func (s *WebhookDeliveryService) DeliverEvent(ctx context.Context, event *webhoo
 				"event_id":   event.ID,
 			})
 		default:
+			s.pending.Add(-1)
 			s.logger.WarnWithFields("Webhook delivery queue is full, dropping task", map[string]interface{}{
 				"webhook_id": webhookConfig.ID.String(),
 				"event_id":   event.ID,
@@ -194,9 +527,55 @@ func (s *WebhookDeliveryService) DeliverEvent(ctx context.Context, event *webhoo
 	return nil
 }
 
-// getWebhooksForEvent retrieves webhooks that should receive the given event
+// prepareEvent routes the event, runs it through the processors and stores it for replays
+func (s *WebhookDeliveryService) prepareEvent(ctx context.Context, event *webhook.WebhookEvent) {
+	// Attach routing metadata first so processors and receivers see the same tags
+	if s.router != nil && event.Routing == nil {
+		event.Routing = s.router.Route(ctx, event)
+	}
+
+	// Process event with additional processors (like Chatwoot)
+	for _, processor := range s.processors {
+		if err := processor.ProcessWebhookEvent(ctx, event); err != nil {
+			s.logger.ErrorWithFields("Processor failed to handle event", map[string]interface{}{
+				"event_id":   event.ID,
+				"event_type": event.Type,
+				"session_id": event.SessionID,
+				"error":      err.Error(),
+			})
+			// Continue with other processors even if one fails
+		}
+	}
+
+	// Store the event as receivers get it, after translation
+	if s.events != nil {
+		s.events.RecordEvent(ctx, event)
+	}
+}
+
+// streamEvent sends the event to the streams open on its session with the default webhook payload
+func (s *WebhookDeliveryService) streamEvent(event *webhook.WebhookEvent) {
+	if s.streamer == nil || !s.streamer.HasSubscribers(event.SessionID) {
+		return
+	}
+
+	payload, err := json.Marshal(defaultPayload(event))
+	if err != nil {
+		s.logger.ErrorWithFields("Failed to marshal streamed event", map[string]interface{}{
+			"event_id":   event.ID,
+			"event_type": event.Type,
+			"error":      err.Error(),
+		})
+		return
+	}
+
+	s.streamer.Publish(event.SessionID, event.Type, payload)
+}
+
+// getWebhooksForEvent retrieves webhooks that should receive the given event. Shadow webhooks receive
+// it whichever regular webhooks do.
 func (s *WebhookDeliveryService) getWebhooksForEvent(ctx context.Context, event *webhook.WebhookEvent) ([]*webhook.WebhookConfig, error) {
-	var webhooks []*webhook.WebhookConfig
+	var webhooks, shadows []*webhook.WebhookConfig
 
 	// Get session-specific webhooks (only if not empty sessionID)
 	if event.SessionID != "" {
@@ -207,31 +586,97 @@ func (s *WebhookDeliveryService) getWebhooksForEvent(ctx context.Context, event
 				"error":      err.Error(),
 			})
 		} else {
-			for _, wh := range sessionWebhooks {
-				if wh.Enabled && wh.HasEvent(event.Type) {
-					webhooks = append(webhooks, wh)
-				}
-			}
+			webhooks, shadows = splitShadowWebhooks(sessionWebhooks, event, shadows)
 		}
 	}
 
+	globalWebhooks, err := s.webhookRepo.GetGlobalWebhooks(ctx)
+	if err != nil {
+		s.logger.ErrorWithFields("Failed to get global webhooks", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+	var globalRegular []*webhook.WebhookConfig
+	globalRegular, shadows = splitShadowWebhooks(globalWebhooks, event, shadows)
+
+	// Exclusive routing sends the event only to the webhooks chosen by the rules
+	routed := s.getRoutedWebhooks(ctx, event)
+	if event.Routing != nil && event.Routing.Exclusive && len(routed) > 0 {
+		return appendWebhooks(routed, shadows), nil
+	}
+
 	// Get global webhooks only if we have session webhooks or no session-specific ones
 	if len(webhooks) == 0 {
-		globalWebhooks, err := s.webhookRepo.GetGlobalWebhooks(ctx)
+		webhooks = globalRegular
+	}
+
+	webhooks = appendWebhooks(webhooks, routed)
+	return appendWebhooks(webhooks, shadows), nil
+}
+
+// splitShadowWebhooks returns the enabled webhooks subscribed to the event and its chat, with the shadow
+// ones appended to shadows instead
+func splitShadowWebhooks(candidates []*webhook.WebhookConfig, event *webhook.WebhookEvent, shadows []*webhook.WebhookConfig) ([]*webhook.WebhookConfig, []*webhook.WebhookConfig) {
+	var regular []*webhook.WebhookConfig
+	for _, wh := range candidates {
+		if !wh.Accepts(event) {
+			continue
+		}
+		if wh.Shadow {
+			shadows = append(shadows, wh)
+		} else {
+			regular = append(regular, wh)
+		}
+	}
+	return regular, shadows
+}
+
+// appendWebhooks appends the webhooks that are not in webhooks yet
+func appendWebhooks(webhooks, more []*webhook.WebhookConfig) []*webhook.WebhookConfig {
+	for _, wh := range more {
+		if !containsWebhook(webhooks, wh) {
+			webhooks = append(webhooks, wh)
+		}
+	}
+	return webhooks
+}
+
+// getRoutedWebhooks resolves the webhooks selected by routing rules for the event
+func (s *WebhookDeliveryService) getRoutedWebhooks(ctx context.Context, event *webhook.WebhookEvent) []*webhook.WebhookConfig {
+	if event.Routing == nil {
+		return nil
+	}
+
+	var webhooks []*webhook.WebhookConfig
+	for _, id := range event.Routing.WebhookIDs {
+		wh, err := s.webhookRepo.GetByID(ctx, id)
 		if err != nil {
-			s.logger.ErrorWithFields("Failed to get global webhooks", map[string]interface{}{
-				"error": err.Error(),
+			s.logger.WarnWithFields("Failed to get routed webhook", map[string]interface{}{
+				"webhook_id": id,
+				"session_id": event.SessionID,
+				"error":      err.Error(),
 			})
-		} else {
-			for _, wh := range globalWebhooks {
-				if wh.Enabled && wh.HasEvent(event.Type) {
-					webhooks = append(webhooks, wh)
-				}
-			}
+			continue
+		}
+		// Routed webhooks must belong to the session or be global
+		if wh.SessionID != nil && *wh.SessionID != event.SessionID {
+			continue
+		}
+		if wh.Enabled && !containsWebhook(webhooks, wh) {
+			webhooks = append(webhooks, wh)
 		}
 	}
 
-	return webhooks, nil
+	return webhooks
+}
+
+func containsWebhook(webhooks []*webhook.WebhookConfig, target *webhook.WebhookConfig) bool {
+	for _, wh := range webhooks {
+		if wh.ID == target.ID {
+			return true
+		}
+	}
+	return false
 }
 
 // processDeliveryTask processes a single webhook delivery task
@@ -245,9 +690,16 @@ func (s *WebhookDeliveryService) processDeliveryTask(ctx context.Context, task *
 
 	result := s.deliverWebhook(ctx, task.WebhookConfig, task.Event)
 
+	attemptResult := "success"
+	if !result.Success {
+		attemptResult = "failure"
+	}
+	deliveryDuration.WithLabelValues(attemptResult).Observe(result.Latency.Seconds())
+
 	if !result.Success && task.Attempt < task.MaxAttempts {
 		// Retry the delivery
 		task.Attempt++
+		deliveryRetriesTotal.WithLabelValues(task.Event.SessionID).Inc()
 
 		// Add exponential backoff
 		delay := time.Duration(task.Attempt) * s.retryDelay
@@ -259,10 +711,12 @@ func (s *WebhookDeliveryService) processDeliveryTask(ctx context.Context, task *
 			"delay":      delay.String(),
 		})
 
+		s.pending.Add(1)
 		time.AfterFunc(delay, func() {
 			select {
 			case s.deliveryQueue <- task:
 			default:
+				s.pending.Add(-1)
 				s.logger.WarnWithFields("Failed to requeue webhook delivery task", map[string]interface{}{
 					"webhook_id": task.WebhookConfig.ID.String(),
 					"event_id":   task.Event.ID,
@@ -279,6 +733,15 @@ func (s *WebhookDeliveryService) processDeliveryTask(ctx context.Context, task *
 				"latency":     result.Latency.String(),
 				"attempt":     task.Attempt,
 			})
+			s.recordOutcome(task, true)
+		} else if task.WebhookConfig.Shadow {
+			// Shadow failures are only logged, they must not alert anyone
+			s.logger.WarnWithFields("Shadow webhook delivery failed", map[string]interface{}{
+				"webhook_id":  task.WebhookConfig.ID.String(),
+				"event_id":    task.Event.ID,
+				"error":       result.Error,
+				"status_code": result.StatusCode,
+			})
 		} else {
 			s.logger.ErrorWithFields("Webhook delivery failed permanently", map[string]interface{}{
 				"webhook_id":  task.WebhookConfig.ID.String(),
@@ -287,32 +750,97 @@ func (s *WebhookDeliveryService) processDeliveryTask(ctx context.Context, task *
 				"status_code": result.StatusCode,
 				"attempts":    task.Attempt,
 			})
+			s.recordOutcome(task, false)
+			s.recordFailure(task, result)
 		}
 	}
 }
 
+// recordOutcome counts the final outcome of a regular delivery in /metrics and reports it to the
+// delivery recorder, if any
+func (s *WebhookDeliveryService) recordOutcome(task *DeliveryTask, success bool) {
+	if task.WebhookConfig.Shadow {
+		return
+	}
+
+	result := "delivered"
+	if !success {
+		result = "failed"
+	}
+	deliveriesTotal.WithLabelValues(task.Event.SessionID, result).Inc()
+
+	if s.deliveries == nil {
+		return
+	}
+	s.deliveries.RecordWebhookDelivery(task.Event.SessionID, success)
+}
+
+// recordFailure reports a permanently failed delivery to the failure recorder, if any
+func (s *WebhookDeliveryService) recordFailure(task *DeliveryTask, result *DeliveryResult) {
+	if s.recorder == nil {
+		return
+	}
+
+	s.recorder.RecordWebhookFailure(&activity.WebhookFailure{
+		WebhookID:  task.WebhookConfig.ID.String(),
+		URL:        task.WebhookConfig.URL,
+		SessionID:  task.Event.SessionID,
+		EventID:    task.Event.ID,
+		EventType:  task.Event.Type,
+		StatusCode: result.StatusCode,
+		Error:      result.Error,
+		Attempts:   task.Attempt,
+		FailedAt:   time.Now(),
+	})
+}
+
 // deliverWebhook performs the actual HTTP request to deliver the webhook
-func (s *WebhookDeliveryService) deliverWebhook(ctx context.Context, webhookConfig *webhook.WebhookConfig, event *webhook.WebhookEvent) *DeliveryResult {
+func (s *WebhookDeliveryService) deliverWebhook(ctx context.Context, webhookConfig *webhook.WebhookConfig, event *webhook.WebhookEvent) (result *DeliveryResult) {
 	startTime := time.Now()
 
-	// Create payload
-	payload := &WebhookPayload{
-		Event:     event.Type,
-		SessionID: event.SessionID,
-		Timestamp: event.Timestamp.Unix(),
-		Data:      event.Data,
+	ctx, span := tracing.Start(ctx, "webhook.deliver", tracing.KindClient)
+	span.SetAttributes(
+		attribute.String("zpwoot.session", event.SessionID),
+		attribute.String("zpwoot.webhook.id", webhookConfig.ID.String()),
+		attribute.String("zpwoot.webhook.event", event.Type),
+	)
+	if event.RequestID != "" {
+		span.SetAttributes(attribute.String("zpwoot.request_id", event.RequestID))
 	}
+	defer func() {
+		if result.StatusCode != 0 {
+			span.SetAttributes(attribute.Int("http.response.status_code", result.StatusCode))
+		}
+		if !result.Success {
+			if result.Error != "" {
+				tracing.SetErrorf(span, "%s", result.Error)
+			} else {
+				tracing.SetErrorf(span, "HTTP %d", result.StatusCode)
+			}
+		}
+		span.End()
+	}()
 
-	// Marshal payload to JSON
-	payloadBytes, err := json.Marshal(payload)
+	payloadBytes, err := s.buildPayload(webhookConfig, event)
 	if err != nil {
 		return &DeliveryResult{
 			Success: false,
-			Error:   fmt.Sprintf("failed to marshal payload: %v", err),
+			Error:   err.Error(),
 			Latency: time.Since(startTime),
 		}
 	}
 
+	// A slow receiver only holds its worker until the webhook's timeout
+	timeout := s.timeout
+	if webhookConfig.TimeoutSeconds > 0 {
+		timeout = time.Duration(webhookConfig.TimeoutSeconds) * time.Second
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
 	// Create HTTP request
 	req, err := http.NewRequestWithContext(ctx, "POST", webhookConfig.URL, bytes.NewBuffer(payloadBytes))
 	if err != nil {
@@ -326,9 +854,16 @@ func (s *WebhookDeliveryService) deliverWebhook(ctx context.Context, webhookConf
 	// Set headers
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("User-Agent", "zpwoot-webhook/1.0")
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
 	req.Header.Set("X-Webhook-Event", event.Type)
 	req.Header.Set("X-Webhook-Session", event.SessionID)
 	req.Header.Set("X-Webhook-Timestamp", fmt.Sprintf("%d", event.Timestamp.Unix()))
+	if event.RequestID != "" {
+		req.Header.Set("X-Request-ID", event.RequestID)
+	}
+	if event.Replay {
+		req.Header.Set("X-Webhook-Replay", "true")
+	}
 
 	// Add HMAC signature if secret is configured
 	if webhookConfig.Secret != "" {
@@ -348,7 +883,7 @@ func (s *WebhookDeliveryService) deliverWebhook(ctx context.Context, webhookConf
 	defer func() { _ = resp.Body.Close() }()
 
 	// Read response body
-	responseBody, err := io.ReadAll(resp.Body)
+	responseBody, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBodySize))
 	if err != nil {
 		responseBody = []byte("failed to read response body")
 	}
@@ -365,6 +900,66 @@ func (s *WebhookDeliveryService) deliverWebhook(ctx context.Context, webhookConf
 	}
 }
 
+// buildPayload returns the JSON body delivered to the webhook: the default payload, or the
+// webhook's payload template rendered against it
+func (s *WebhookDeliveryService) buildPayload(webhookConfig *webhook.WebhookConfig, event *webhook.WebhookEvent) ([]byte, error) {
+	if webhookConfig.PayloadTemplate == "" {
+		payloadBytes, err := json.Marshal(defaultPayload(event))
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal payload: %w", err)
+		}
+		return payloadBytes, nil
+	}
+
+	tmpl, err := s.payloadTemplate(webhookConfig.PayloadTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := webhook.PayloadData(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build payload data: %w", err)
+	}
+
+	return tmpl.Render(data)
+}
+
+// defaultPayload returns the payload delivered to webhooks without a payload template
+func defaultPayload(event *webhook.WebhookEvent) *WebhookPayload {
+	return &WebhookPayload{
+		Event:       event.Type,
+		SessionID:   event.SessionID,
+		Timestamp:   event.Timestamp.Unix(),
+		Data:        event.Data,
+		Routing:     event.Routing,
+		Translation: event.Translation,
+		RequestID:   event.RequestID,
+		Replay:      event.Replay,
+	}
+}
+
+// payloadTemplate returns the compiled template, compiling it once per distinct text
+func (s *WebhookDeliveryService) payloadTemplate(text string) (*webhook.PayloadTemplate, error) {
+	s.templatesMu.Lock()
+	defer s.templatesMu.Unlock()
+
+	if tmpl, exists := s.templates[text]; exists {
+		return tmpl, nil
+	}
+
+	tmpl, err := webhook.ParsePayloadTemplate(text)
+	if err != nil {
+		return nil, err
+	}
+
+	// Edited templates leave their old text behind; start over rather than grow without bound
+	if len(s.templates) >= maxCachedTemplates {
+		s.templates = make(map[string]*webhook.PayloadTemplate)
+	}
+	s.templates[text] = tmpl
+	return tmpl, nil
+}
+
 // generateSignature generates HMAC-SHA256 signature for webhook payload
 func (s *WebhookDeliveryService) generateSignature(payload []byte, secret string) string {
 	h := hmac.New(sha256.New, []byte(secret))
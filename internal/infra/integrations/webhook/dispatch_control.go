@@ -0,0 +1,176 @@
+package webhook
+
+import (
+	"time"
+
+	"zpwoot/internal/domain/webhook"
+	"zpwoot/internal/ports"
+)
+
+// DispatchStatus returns the delivery concurrency, queue and the pauses set through the admin API
+func (s *WebhookDeliveryService) DispatchStatus() *ports.WebhookDispatchStatus {
+	s.pauseMu.RLock()
+	pausedAll := s.pausedAll
+	pausedSessions := make(map[string]time.Time, len(s.pausedSessions))
+	for sessionID, since := range s.pausedSessions {
+		pausedSessions[sessionID] = since
+	}
+	s.pauseMu.RUnlock()
+
+	return &ports.WebhookDispatchStatus{
+		Workers:        s.workerCount(),
+		QueueSize:      len(s.deliveryQueue),
+		QueueCapacity:  cap(s.deliveryQueue),
+		Parked:         s.parkedCount(),
+		PausedAll:      pausedAll,
+		PausedSessions: pausedSessions,
+	}
+}
+
+// workerCount returns the configured delivery concurrency
+func (s *WebhookDeliveryService) workerCount() int {
+	s.workersMu.Lock()
+	defer s.workersMu.Unlock()
+	return s.workers
+}
+
+// SetWorkers changes the delivery concurrency. Before Start it only sets how many workers start;
+// stopped workers finish the delivery in progress first.
+func (s *WebhookDeliveryService) SetWorkers(workers int) error {
+	if workers < 1 || workers > webhook.MaxDispatchWorkers {
+		return webhook.ErrInvalidDispatchWorkers
+	}
+
+	s.workersMu.Lock()
+	defer s.workersMu.Unlock()
+
+	previous := s.workers
+	s.workers = workers
+	if s.workerCtx != nil {
+		for len(s.workerStops) < workers {
+			s.startWorkerLocked()
+		}
+		for len(s.workerStops) > workers {
+			last := len(s.workerStops) - 1
+			close(s.workerStops[last])
+			s.workerStops = s.workerStops[:last]
+		}
+	}
+
+	s.logger.InfoWithFields("Webhook delivery workers changed", map[string]interface{}{
+		"previous": previous,
+		"workers":  workers,
+	})
+	return nil
+}
+
+// PauseDispatch holds back the deliveries of a session, or of every session when sessionID is empty,
+// until ResumeDispatch; held back deliveries are kept in memory like those paused by maintenance
+func (s *WebhookDeliveryService) PauseDispatch(sessionID string) {
+	s.pauseMu.Lock()
+	if sessionID == "" {
+		s.pausedAll = true
+	} else if _, paused := s.pausedSessions[sessionID]; !paused {
+		s.pausedSessions[sessionID] = time.Now()
+	}
+	s.pauseMu.Unlock()
+
+	s.logger.WarnWithFields("Webhook dispatch paused", map[string]interface{}{
+		"session_id": sessionID,
+	})
+}
+
+// ResumeDispatch lifts a pause set by PauseDispatch and requeues the deliveries it held back
+func (s *WebhookDeliveryService) ResumeDispatch(sessionID string) {
+	s.pauseMu.Lock()
+	if sessionID == "" {
+		s.pausedAll = false
+	} else {
+		delete(s.pausedSessions, sessionID)
+	}
+	s.pauseMu.Unlock()
+
+	select {
+	case s.resumed <- struct{}{}:
+	default:
+	}
+
+	s.logger.InfoWithFields("Webhook dispatch resumed", map[string]interface{}{
+		"session_id": sessionID,
+	})
+}
+
+// dispatchPaused reports whether the deliveries of a session are held back, through the admin API
+// or the gate
+func (s *WebhookDeliveryService) dispatchPaused(sessionID string) bool {
+	s.pauseMu.RLock()
+	_, paused := s.pausedSessions[sessionID]
+	paused = paused || s.pausedAll
+	s.pauseMu.RUnlock()
+
+	return paused || (s.gate != nil && s.gate.DispatchPaused(sessionID))
+}
+
+// DrainSession removes the held back and queued deliveries of a paused session and returns their
+// events, each once. Deliveries waiting for a retry are held back when they requeue, so draining
+// again picks them up.
+func (s *WebhookDeliveryService) DrainSession(sessionID string) ([]*webhook.WebhookEvent, error) {
+	if !s.dispatchPaused(sessionID) {
+		return nil, webhook.ErrSessionDispatchNotPaused
+	}
+
+	var drained []*DeliveryTask
+
+	s.parkedMu.Lock()
+	remaining := s.parked[:0]
+	for _, task := range s.parked {
+		if task.Event.SessionID == sessionID {
+			drained = append(drained, task)
+		} else {
+			remaining = append(remaining, task)
+		}
+	}
+	s.parked = remaining
+	s.parkedMu.Unlock()
+
+	// Take every queued task once, putting back those of other sessions
+	var requeue []*DeliveryTask
+	for queued := len(s.deliveryQueue); queued > 0; queued-- {
+		var task *DeliveryTask
+		select {
+		case task = <-s.deliveryQueue:
+		default:
+		}
+		if task == nil {
+			break
+		}
+
+		if task.Event.SessionID == sessionID {
+			drained = append(drained, task)
+			s.pending.Add(-1)
+		} else {
+			requeue = append(requeue, task)
+		}
+	}
+	for _, task := range requeue {
+		s.deliveryQueue <- task
+	}
+
+	seen := make(map[string]bool, len(drained))
+	events := make([]*webhook.WebhookEvent, 0, len(drained))
+	for _, task := range drained {
+		if seen[task.Event.ID] {
+			continue
+		}
+		seen[task.Event.ID] = true
+		events = append(events, task.Event)
+	}
+
+	s.logger.WarnWithFields("Drained webhook deliveries of session", map[string]interface{}{
+		"session_id": sessionID,
+		"deliveries": len(drained),
+		"events":     len(events),
+	})
+
+	return events, nil
+}
@@ -145,6 +145,19 @@ func (d *EventDispatcher) enrichEventData(evt interface{}, eventMap map[string]i
 			eventMap["message_type"] = "location"
 		} else if v.Message.ContactMessage != nil {
 			eventMap["message_type"] = "contact"
+		} else if v.Message.ButtonsResponseMessage != nil {
+			// Replies carry the ID of the selected button or row, so routing rules can match them
+			eventMap["message_type"] = "button_reply"
+			eventMap["text"] = v.Message.ButtonsResponseMessage.GetSelectedDisplayText()
+			eventMap["selected_id"] = v.Message.ButtonsResponseMessage.GetSelectedButtonID()
+		} else if v.Message.TemplateButtonReplyMessage != nil {
+			eventMap["message_type"] = "template_reply"
+			eventMap["text"] = v.Message.TemplateButtonReplyMessage.GetSelectedDisplayText()
+			eventMap["selected_id"] = v.Message.TemplateButtonReplyMessage.GetSelectedID()
+		} else if v.Message.ListResponseMessage != nil {
+			eventMap["message_type"] = "list_reply"
+			eventMap["text"] = v.Message.ListResponseMessage.GetTitle()
+			eventMap["selected_id"] = v.Message.ListResponseMessage.GetSingleSelectReply().GetSelectedRowID()
 		} else {
 			eventMap["message_type"] = "unknown"
 		}
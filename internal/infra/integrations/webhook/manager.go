@@ -90,6 +90,25 @@ func (m *WebhookManager) Stop() error {
 	return nil
 }
 
+// Shutdown stops dispatching new events, waits for the queued deliveries to finish until ctx is done
+// and then stops the workers
+func (m *WebhookManager) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	if !m.started {
+		m.mu.Unlock()
+		return nil
+	}
+	m.started = false
+	m.mu.Unlock()
+
+	m.logger.Info("Draining webhook delivery queue")
+	err := m.deliveryService.Drain(ctx)
+
+	m.cancel()
+	m.logger.Info("Webhook manager stopped")
+	return err
+}
+
 // IsStarted returns whether the webhook manager is currently running
 func (m *WebhookManager) IsStarted() bool {
 	m.mu.RLock()
@@ -127,9 +146,10 @@ func (m *WebhookManager) GetStats() *WebhookStats {
 
 	return &WebhookStats{
 		Started:       m.started,
-		Workers:       m.deliveryService.workers,
+		Workers:       m.deliveryService.workerCount(),
 		QueueSize:     len(m.deliveryService.deliveryQueue),
 		QueueCapacity: cap(m.deliveryService.deliveryQueue),
+		ParkedTasks:   m.deliveryService.parkedCount(),
 		MaxRetries:    m.deliveryService.maxRetries,
 		RetryDelay:    m.deliveryService.retryDelay.String(),
 	}
@@ -141,6 +161,7 @@ type WebhookStats struct {
 	Workers       int    `json:"workers"`
 	QueueSize     int    `json:"queue_size"`
 	QueueCapacity int    `json:"queue_capacity"`
+	ParkedTasks   int    `json:"parked_tasks"`
 	MaxRetries    int    `json:"max_retries"`
 	RetryDelay    string `json:"retry_delay"`
 }
@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"zpwoot/internal/domain/apikey"
+	"zpwoot/internal/ports"
+	"zpwoot/platform/logger"
+)
+
+type apiKeyRepository struct {
+	db     *sqlx.DB
+	logger *logger.Logger
+}
+
+func NewAPIKeyRepository(db *sqlx.DB, logger *logger.Logger) ports.APIKeyRepository {
+	return &apiKeyRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+type apiKeyModel struct {
+	ID         string         `db:"id"`
+	Name       string         `db:"name"`
+	Prefix     string         `db:"prefix"`
+	KeyHash    string         `db:"keyHash"`
+	Scopes     pq.StringArray `db:"scopes"`
+	SessionIDs pq.StringArray `db:"sessionIds"`
+	Workspace  sql.NullString `db:"workspace"`
+	ExpiresAt  sql.NullTime   `db:"expiresAt"`
+	LastUsedAt sql.NullTime   `db:"lastUsedAt"`
+	RevokedAt  sql.NullTime   `db:"revokedAt"`
+	CreatedAt  time.Time      `db:"createdAt"`
+	UpdatedAt  time.Time      `db:"updatedAt"`
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, key *apikey.APIKey) error {
+	query := `
+		INSERT INTO "zpAPIKeys" (id, name, prefix, "keyHash", scopes, "sessionIds", workspace, "expiresAt", "createdAt", "updatedAt")
+		VALUES (:id, :name, :prefix, :keyHash, :scopes, :sessionIds, :workspace, :expiresAt, :createdAt, :updatedAt)
+	`
+
+	if _, err := r.db.NamedExecContext(ctx, query, r.toModel(key)); err != nil {
+		r.logger.ErrorWithFields("Failed to create API key", map[string]interface{}{
+			"name":  key.Name,
+			"error": err.Error(),
+		})
+		return fmt.Errorf("failed to create API key: %w", err)
+	}
+
+	return nil
+}
+
+func (r *apiKeyRepository) GetByID(ctx context.Context, id uuid.UUID) (*apikey.APIKey, error) {
+	return r.get(ctx, `SELECT * FROM "zpAPIKeys" WHERE id = $1`, id.String())
+}
+
+func (r *apiKeyRepository) GetByHash(ctx context.Context, keyHash string) (*apikey.APIKey, error) {
+	return r.get(ctx, `SELECT * FROM "zpAPIKeys" WHERE "keyHash" = $1`, keyHash)
+}
+
+func (r *apiKeyRepository) get(ctx context.Context, query string, arg interface{}) (*apikey.APIKey, error) {
+	var model apiKeyModel
+	if err := r.db.GetContext(ctx, &model, query, arg); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apikey.ErrAPIKeyNotFound
+		}
+		r.logger.ErrorWithFields("Failed to get API key", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+
+	return r.fromModel(&model)
+}
+
+func (r *apiKeyRepository) List(ctx context.Context) ([]*apikey.APIKey, error) {
+	var models []apiKeyModel
+	query := `SELECT * FROM "zpAPIKeys" ORDER BY "createdAt" DESC`
+
+	if err := r.db.SelectContext(ctx, &models, query); err != nil {
+		r.logger.ErrorWithFields("Failed to list API keys", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+
+	keys := make([]*apikey.APIKey, 0, len(models))
+	for i := range models {
+		key, err := r.fromModel(&models[i])
+		if err != nil {
+			r.logger.WarnWithFields("Failed to decode API key", map[string]interface{}{
+				"id":    models[i].ID,
+				"error": err.Error(),
+			})
+			continue
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func (r *apiKeyRepository) Update(ctx context.Context, key *apikey.APIKey) error {
+	query := `
+		UPDATE "zpAPIKeys" SET name = :name, scopes = :scopes, "sessionIds" = :sessionIds, workspace = :workspace,
+			"expiresAt" = :expiresAt, "revokedAt" = :revokedAt, "updatedAt" = :updatedAt
+		WHERE id = :id
+	`
+
+	result, err := r.db.NamedExecContext(ctx, query, r.toModel(key))
+	if err != nil {
+		r.logger.ErrorWithFields("Failed to update API key", map[string]interface{}{
+			"api_key_id": key.ID.String(),
+			"error":      err.Error(),
+		})
+		return fmt.Errorf("failed to update API key: %w", err)
+	}
+
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return apikey.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+func (r *apiKeyRepository) TouchLastUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	query := `UPDATE "zpAPIKeys" SET "lastUsedAt" = $2 WHERE id = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, id.String(), usedAt); err != nil {
+		return fmt.Errorf("failed to record API key use: %w", err)
+	}
+
+	return nil
+}
+
+func (r *apiKeyRepository) toModel(key *apikey.APIKey) *apiKeyModel {
+	model := &apiKeyModel{
+		ID:         key.ID.String(),
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		KeyHash:    key.KeyHash,
+		Scopes:     pq.StringArray(key.Scopes),
+		SessionIDs: pq.StringArray(key.SessionIDs),
+		CreatedAt:  key.CreatedAt,
+		UpdatedAt:  key.UpdatedAt,
+	}
+
+	if key.Workspace != "" {
+		model.Workspace = sql.NullString{String: key.Workspace, Valid: true}
+	}
+	if key.ExpiresAt != nil {
+		model.ExpiresAt = sql.NullTime{Time: *key.ExpiresAt, Valid: true}
+	}
+	if key.LastUsedAt != nil {
+		model.LastUsedAt = sql.NullTime{Time: *key.LastUsedAt, Valid: true}
+	}
+	if key.RevokedAt != nil {
+		model.RevokedAt = sql.NullTime{Time: *key.RevokedAt, Valid: true}
+	}
+
+	return model
+}
+
+func (r *apiKeyRepository) fromModel(model *apiKeyModel) (*apikey.APIKey, error) {
+	id, err := uuid.Parse(model.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid API key ID: %w", err)
+	}
+
+	key := &apikey.APIKey{
+		ID:         id,
+		Name:       model.Name,
+		Prefix:     model.Prefix,
+		KeyHash:    model.KeyHash,
+		Scopes:     []string(model.Scopes),
+		SessionIDs: []string(model.SessionIDs),
+		Workspace:  model.Workspace.String,
+		CreatedAt:  model.CreatedAt,
+		UpdatedAt:  model.UpdatedAt,
+	}
+
+	if model.ExpiresAt.Valid {
+		key.ExpiresAt = &model.ExpiresAt.Time
+	}
+	if model.LastUsedAt.Valid {
+		key.LastUsedAt = &model.LastUsedAt.Time
+	}
+	if model.RevokedAt.Valid {
+		key.RevokedAt = &model.RevokedAt.Time
+	}
+
+	return key, nil
+}
@@ -12,6 +12,7 @@ import (
 
 	"zpwoot/internal/ports"
 	"zpwoot/platform/logger"
+	"zpwoot/platform/precondition"
 )
 
 type chatwootRepository struct {
@@ -44,10 +45,13 @@ type chatwootConfigModel struct {
 	ImportMessages bool           `db:"importMessages"`
 	ImportDays     int            `db:"importDays"`
 	MergeBrazil    bool           `db:"mergeBrazil"`
+	SyncReads      bool           `db:"syncReads"`
+	SendReads      bool           `db:"sendReads"`
 	Organization   sql.NullString `db:"organization"`
 	Logo           sql.NullString `db:"logo"`
 	Number         sql.NullString `db:"number"`
 	IgnoreJids     pq.StringArray `db:"ignoreJids"`
+	WebhookSecret  sql.NullString `db:"webhookSecret"`
 	CreatedAt      time.Time      `db:"createdAt"`
 	UpdatedAt      time.Time      `db:"updatedAt"`
 }
@@ -65,14 +69,14 @@ func (r *chatwootRepository) CreateConfig(ctx context.Context, config *ports.Cha
 			id, "sessionId", url, token, "accountId", "inboxId", enabled,
 			"inboxName", "autoCreate", "signMsg", "signDelimiter", "reopenConv",
 			"convPending", "importContacts", "importMessages", "importDays",
-			"mergeBrazil", organization, logo, number, "ignoreJids",
-			"createdAt", "updatedAt"
+			"mergeBrazil", "syncReads", "sendReads", organization, logo, number, "ignoreJids",
+			"webhookSecret", "createdAt", "updatedAt"
 		) VALUES (
 			:id, :sessionId, :url, :token, :accountId, :inboxId, :enabled,
 			:inboxName, :autoCreate, :signMsg, :signDelimiter, :reopenConv,
 			:convPending, :importContacts, :importMessages, :importDays,
-			:mergeBrazil, :organization, :logo, :number, :ignoreJids,
-			:createdAt, :updatedAt
+			:mergeBrazil, :syncReads, :sendReads, :organization, :logo, :number, :ignoreJids,
+			:webhookSecret, :createdAt, :updatedAt
 		)
 	`
 
@@ -152,11 +156,12 @@ func (r *chatwootRepository) UpdateConfig(ctx context.Context, config *ports.Cha
 	query := `
 		UPDATE "zpChatwoot"
 		SET url = :url, token = :token, "accountId" = :accountId,
-		    "inboxId" = :inboxId, enabled = :enabled, "updatedAt" = :updatedAt
+		    "inboxId" = :inboxId, enabled = :enabled, "syncReads" = :syncReads,
+		    "sendReads" = :sendReads, "webhookSecret" = :webhookSecret, "updatedAt" = :updatedAt
 		WHERE id = :id
 	`
 
-	result, err := r.db.NamedExecContext(ctx, query, model)
+	result, conditional, err := namedUpdate(ctx, r.db, query, model)
 	if err != nil {
 		r.logger.ErrorWithFields("Failed to update chatwoot config", map[string]interface{}{
 			"config_id": config.ID.String(),
@@ -171,6 +176,9 @@ func (r *chatwootRepository) UpdateConfig(ctx context.Context, config *ports.Cha
 	}
 
 	if rowsAffected == 0 {
+		if conditional {
+			return precondition.ErrFailed
+		}
 		return ports.ErrConfigNotFound
 	}
 
@@ -375,6 +383,8 @@ func (r *chatwootRepository) configToModel(config *ports.ChatwootConfig) *chatwo
 		ImportMessages: config.ImportMessages,
 		ImportDays:     config.ImportDays,
 		MergeBrazil:    config.MergeBrazil,
+		SyncReads:      config.SyncReads,
+		SendReads:      config.SendReads,
 		IgnoreJids:     pq.StringArray(config.IgnoreJids),
 		CreatedAt:      config.CreatedAt,
 		UpdatedAt:      config.UpdatedAt,
@@ -400,6 +410,10 @@ func (r *chatwootRepository) configToModel(config *ports.ChatwootConfig) *chatwo
 		model.Number = sql.NullString{String: *config.Number, Valid: true}
 	}
 
+	if config.WebhookSecret != nil {
+		model.WebhookSecret = sql.NullString{String: *config.WebhookSecret, Valid: true}
+	}
+
 	return model
 }
 
@@ -430,6 +444,8 @@ func (r *chatwootRepository) configFromModel(model *chatwootConfigModel) (*ports
 		ImportMessages: model.ImportMessages,
 		ImportDays:     model.ImportDays,
 		MergeBrazil:    model.MergeBrazil,
+		SyncReads:      model.SyncReads,
+		SendReads:      model.SendReads,
 		IgnoreJids:     []string(model.IgnoreJids),
 		CreatedAt:      model.CreatedAt,
 		UpdatedAt:      model.UpdatedAt,
@@ -455,5 +471,9 @@ func (r *chatwootRepository) configFromModel(model *chatwootConfigModel) (*ports
 		config.Number = &model.Number.String
 	}
 
+	if model.WebhookSecret.Valid {
+		config.WebhookSecret = &model.WebhookSecret.String
+	}
+
 	return config, nil
 }
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"zpwoot/internal/domain/contact"
+	"zpwoot/internal/ports"
+	"zpwoot/platform/logger"
+)
+
+type contactChangeRepository struct {
+	db     *sqlx.DB
+	logger *logger.Logger
+}
+
+func NewContactChangeRepository(db *sqlx.DB, logger *logger.Logger) ports.ContactChangeRepository {
+	return &contactChangeRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+type contactChangeModel struct {
+	ID             int64     `db:"id"`
+	SessionID      string    `db:"sessionId"`
+	ContactJID     string    `db:"contactJid"`
+	ChangeType     string    `db:"changeType"`
+	PushName       string    `db:"pushName"`
+	BusinessName   string    `db:"businessName"`
+	FullName       string    `db:"fullName"`
+	FirstName      string    `db:"firstName"`
+	PictureID      string    `db:"pictureId"`
+	PictureRemoved bool      `db:"pictureRemoved"`
+	ChangedAt      time.Time `db:"changedAt"`
+}
+
+func (r *contactChangeRepository) Create(ctx context.Context, change *contact.Change) error {
+	query := `
+		INSERT INTO "zpContactChanges" ("sessionId", "contactJid", "changeType", "pushName", "businessName", "fullName", "firstName", "pictureId", "pictureRemoved", "changedAt")
+		VALUES (:sessionId, :contactJid, :changeType, :pushName, :businessName, :fullName, :firstName, :pictureId, :pictureRemoved, :changedAt)
+		RETURNING id
+	`
+
+	rows, err := r.db.NamedQueryContext(ctx, query, r.toModel(change))
+	if err != nil {
+		r.logger.ErrorWithFields("Failed to record contact change", map[string]interface{}{
+			"session_id":  change.SessionID,
+			"contact_jid": change.ContactJID,
+			"error":       err.Error(),
+		})
+		return fmt.Errorf("failed to record contact change: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	if rows.Next() {
+		if err := rows.Scan(&change.ID); err != nil {
+			return fmt.Errorf("failed to read contact change id: %w", err)
+		}
+	}
+
+	return rows.Err()
+}
+
+func (r *contactChangeRepository) ListSince(ctx context.Context, sessionID string, since time.Time, limit int) ([]*contact.Change, error) {
+	query := `
+		SELECT * FROM "zpContactChanges"
+		WHERE "sessionId" = $1 AND "changedAt" > $2
+		ORDER BY "changedAt" ASC, id ASC
+		LIMIT $3
+	`
+
+	var models []contactChangeModel
+	if err := r.db.SelectContext(ctx, &models, query, sessionID, since, limit); err != nil {
+		r.logger.ErrorWithFields("Failed to list contact changes", map[string]interface{}{
+			"session_id": sessionID,
+			"error":      err.Error(),
+		})
+		return nil, fmt.Errorf("failed to list contact changes: %w", err)
+	}
+
+	changes := make([]*contact.Change, 0, len(models))
+	for i := range models {
+		changes = append(changes, r.fromModel(&models[i]))
+	}
+
+	return changes, nil
+}
+
+func (r *contactChangeRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM "zpContactChanges" WHERE "changedAt" < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete contact changes: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+func (r *contactChangeRepository) toModel(change *contact.Change) *contactChangeModel {
+	return &contactChangeModel{
+		ID:             change.ID,
+		SessionID:      change.SessionID,
+		ContactJID:     change.ContactJID,
+		ChangeType:     string(change.Type),
+		PushName:       change.PushName,
+		BusinessName:   change.BusinessName,
+		FullName:       change.FullName,
+		FirstName:      change.FirstName,
+		PictureID:      change.PictureID,
+		PictureRemoved: change.PictureRemoved,
+		ChangedAt:      change.ChangedAt,
+	}
+}
+
+func (r *contactChangeRepository) fromModel(model *contactChangeModel) *contact.Change {
+	return &contact.Change{
+		ID:             model.ID,
+		SessionID:      model.SessionID,
+		ContactJID:     model.ContactJID,
+		Type:           contact.ChangeType(model.ChangeType),
+		PushName:       model.PushName,
+		BusinessName:   model.BusinessName,
+		FullName:       model.FullName,
+		FirstName:      model.FirstName,
+		PictureID:      model.PictureID,
+		PictureRemoved: model.PictureRemoved,
+		ChangedAt:      model.ChangedAt,
+	}
+}
@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"zpwoot/internal/domain/conversation"
+	"zpwoot/internal/ports"
+	"zpwoot/platform/logger"
+)
+
+type conversationStateRepository struct {
+	db     *sqlx.DB
+	logger *logger.Logger
+}
+
+func NewConversationStateRepository(db *sqlx.DB, logger *logger.Logger) ports.ConversationStateRepository {
+	return &conversationStateRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+type conversationStateModel struct {
+	SessionID string       `db:"sessionId"`
+	ChatJID   string       `db:"chatJid"`
+	State     string       `db:"state"`
+	Data      string       `db:"data"` // JSONB field
+	ExpiresAt sql.NullTime `db:"expiresAt"`
+	CreatedAt time.Time    `db:"createdAt"`
+	UpdatedAt time.Time    `db:"updatedAt"`
+}
+
+// Upsert stores the chat state, replacing the state, data and expiry it had
+func (r *conversationStateRepository) Upsert(ctx context.Context, state *conversation.State) error {
+	model, err := r.toModel(state)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO "zpConversationStates" ("sessionId", "chatJid", state, data, "expiresAt", "createdAt", "updatedAt")
+		VALUES (:sessionId, :chatJid, :state, :data, :expiresAt, :createdAt, :updatedAt)
+		ON CONFLICT ("sessionId", "chatJid") DO UPDATE SET
+			state = EXCLUDED.state,
+			data = EXCLUDED.data,
+			"expiresAt" = EXCLUDED."expiresAt"
+	`
+
+	if _, err := r.db.NamedExecContext(ctx, query, model); err != nil {
+		r.logger.ErrorWithFields("Failed to save conversation state", map[string]interface{}{
+			"session_id": state.SessionID,
+			"chat_jid":   state.ChatJID,
+			"error":      err.Error(),
+		})
+		return fmt.Errorf("failed to save conversation state: %w", err)
+	}
+
+	return nil
+}
+
+func (r *conversationStateRepository) Get(ctx context.Context, sessionID, chatJID string) (*conversation.State, error) {
+	var model conversationStateModel
+	query := `SELECT * FROM "zpConversationStates" WHERE "sessionId" = $1 AND "chatJid" = $2`
+
+	if err := r.db.GetContext(ctx, &model, query, sessionID, chatJID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, conversation.ErrStateNotFound
+		}
+		r.logger.ErrorWithFields("Failed to get conversation state", map[string]interface{}{
+			"session_id": sessionID,
+			"chat_jid":   chatJID,
+			"error":      err.Error(),
+		})
+		return nil, fmt.Errorf("failed to get conversation state: %w", err)
+	}
+
+	return r.fromModel(&model)
+}
+
+// List returns the unexpired states of a session, most recently updated first
+func (r *conversationStateRepository) List(ctx context.Context, req *conversation.ListStatesRequest) ([]*conversation.State, int, error) {
+	whereClause := `WHERE "sessionId" = $1 AND ("expiresAt" IS NULL OR "expiresAt" > NOW())`
+	args := []interface{}{req.SessionID}
+	argIndex := 2
+
+	if req.State != "" {
+		whereClause += fmt.Sprintf(" AND state = $%d", argIndex)
+		args = append(args, req.State)
+		argIndex++
+	}
+
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM "zpConversationStates" %s`, whereClause)
+	var total int
+	if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
+		r.logger.ErrorWithFields("Failed to count conversation states", map[string]interface{}{
+			"session_id": req.SessionID,
+			"error":      err.Error(),
+		})
+		return nil, 0, fmt.Errorf("failed to count conversation states: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT * FROM "zpConversationStates" %s
+		ORDER BY "updatedAt" DESC, "chatJid" ASC
+		LIMIT $%d OFFSET $%d
+	`, whereClause, argIndex, argIndex+1)
+
+	args = append(args, req.Limit, req.Offset)
+
+	var models []conversationStateModel
+	if err := r.db.SelectContext(ctx, &models, query, args...); err != nil {
+		r.logger.ErrorWithFields("Failed to list conversation states", map[string]interface{}{
+			"session_id": req.SessionID,
+			"error":      err.Error(),
+		})
+		return nil, 0, fmt.Errorf("failed to list conversation states: %w", err)
+	}
+
+	states := make([]*conversation.State, 0, len(models))
+	for _, model := range models {
+		state, err := r.fromModel(&model)
+		if err != nil {
+			r.logger.WarnWithFields("Failed to convert conversation state model", map[string]interface{}{
+				"chat_jid": model.ChatJID,
+				"error":    err.Error(),
+			})
+			continue
+		}
+		states = append(states, state)
+	}
+
+	return states, total, nil
+}
+
+func (r *conversationStateRepository) Delete(ctx context.Context, sessionID, chatJID string) error {
+	query := `DELETE FROM "zpConversationStates" WHERE "sessionId" = $1 AND "chatJid" = $2`
+
+	if _, err := r.db.ExecContext(ctx, query, sessionID, chatJID); err != nil {
+		r.logger.ErrorWithFields("Failed to delete conversation state", map[string]interface{}{
+			"session_id": sessionID,
+			"chat_jid":   chatJID,
+			"error":      err.Error(),
+		})
+		return fmt.Errorf("failed to delete conversation state: %w", err)
+	}
+
+	return nil
+}
+
+func (r *conversationStateRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM "zpConversationStates" WHERE "expiresAt" <= $1`, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired conversation states: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+func (r *conversationStateRepository) toModel(state *conversation.State) (*conversationStateModel, error) {
+	data := state.Data
+	if data == nil {
+		data = map[string]string{}
+	}
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal conversation state data: %w", err)
+	}
+
+	model := &conversationStateModel{
+		SessionID: state.SessionID,
+		ChatJID:   state.ChatJID,
+		State:     state.State,
+		Data:      string(encoded),
+		CreatedAt: state.CreatedAt,
+		UpdatedAt: state.UpdatedAt,
+	}
+	if state.ExpiresAt != nil {
+		model.ExpiresAt = sql.NullTime{Time: *state.ExpiresAt, Valid: true}
+	}
+
+	return model, nil
+}
+
+func (r *conversationStateRepository) fromModel(model *conversationStateModel) (*conversation.State, error) {
+	state := &conversation.State{
+		SessionID: model.SessionID,
+		ChatJID:   model.ChatJID,
+		State:     model.State,
+		Data:      map[string]string{},
+		CreatedAt: model.CreatedAt,
+		UpdatedAt: model.UpdatedAt,
+	}
+	if model.Data != "" {
+		if err := json.Unmarshal([]byte(model.Data), &state.Data); err != nil {
+			return nil, fmt.Errorf("invalid conversation state data: %w", err)
+		}
+	}
+	if model.ExpiresAt.Valid {
+		expiresAt := model.ExpiresAt.Time
+		state.ExpiresAt = &expiresAt
+	}
+
+	return state, nil
+}
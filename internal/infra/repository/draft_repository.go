@@ -0,0 +1,319 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"zpwoot/internal/domain/draft"
+	"zpwoot/internal/ports"
+	"zpwoot/platform/logger"
+)
+
+type draftRepository struct {
+	db     *sqlx.DB
+	logger *logger.Logger
+}
+
+func NewDraftRepository(db *sqlx.DB, logger *logger.Logger) ports.DraftRepository {
+	return &draftRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+type draftModel struct {
+	ID          string         `db:"id"`
+	SessionID   string         `db:"sessionId"`
+	ChatJID     string         `db:"chatJid"`
+	Text        string         `db:"text"`
+	Status      string         `db:"status"`
+	ScheduledAt sql.NullTime   `db:"scheduledAt"`
+	LastError   sql.NullString `db:"lastError"`
+	Workspace   string         `db:"workspace"`
+	ClaimedAt   sql.NullTime   `db:"claimedAt"`
+	CreatedAt   time.Time      `db:"createdAt"`
+	UpdatedAt   time.Time      `db:"updatedAt"`
+}
+
+// Upsert creates the chat draft or replaces its text, clearing any schedule or failure
+func (r *draftRepository) Upsert(ctx context.Context, d *draft.Draft) error {
+	model := r.toModel(d)
+
+	query := `
+		INSERT INTO "zpDrafts" (id, "sessionId", "chatJid", text, status, "scheduledAt", "lastError", "createdAt", "updatedAt")
+		VALUES (:id, :sessionId, :chatJid, :text, :status, :scheduledAt, :lastError, :createdAt, :updatedAt)
+		ON CONFLICT ("sessionId", "chatJid") DO UPDATE SET
+			text = EXCLUDED.text,
+			status = EXCLUDED.status,
+			"scheduledAt" = EXCLUDED."scheduledAt",
+			"lastError" = EXCLUDED."lastError"
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, model)
+	if err != nil {
+		r.logger.ErrorWithFields("Failed to save draft", map[string]interface{}{
+			"session_id": d.SessionID,
+			"chat_jid":   d.ChatJID,
+			"error":      err.Error(),
+		})
+		return fmt.Errorf("failed to save draft: %w", err)
+	}
+
+	return nil
+}
+
+func (r *draftRepository) GetByChat(ctx context.Context, sessionID, chatJID string) (*draft.Draft, error) {
+	var model draftModel
+	query := `SELECT * FROM "zpDrafts" WHERE "sessionId" = $1 AND "chatJid" = $2`
+
+	err := r.db.GetContext(ctx, &model, query, sessionID, chatJID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, draft.ErrDraftNotFound
+		}
+		r.logger.ErrorWithFields("Failed to get draft", map[string]interface{}{
+			"session_id": sessionID,
+			"chat_jid":   chatJID,
+			"error":      err.Error(),
+		})
+		return nil, fmt.Errorf("failed to get draft: %w", err)
+	}
+
+	return r.fromModel(&model)
+}
+
+func (r *draftRepository) List(ctx context.Context, req *draft.ListDraftsRequest) ([]*draft.Draft, int, error) {
+	whereClause := `WHERE "sessionId" = $1`
+	args := []interface{}{req.SessionID}
+	argIndex := 2
+
+	if req.Status != "" {
+		whereClause += fmt.Sprintf(" AND status = $%d", argIndex)
+		args = append(args, req.Status)
+		argIndex++
+	}
+
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM "zpDrafts" %s`, whereClause)
+	var total int
+	if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
+		r.logger.ErrorWithFields("Failed to count drafts", map[string]interface{}{
+			"session_id": req.SessionID,
+			"error":      err.Error(),
+		})
+		return nil, 0, fmt.Errorf("failed to count drafts: %w", err)
+	}
+
+	// The cursor narrows the page, not the total
+	if req.After != nil {
+		whereClause += fmt.Sprintf(` AND ("createdAt", id) < ($%d, $%d)`, argIndex, argIndex+1)
+		args = append(args, req.After.CreatedAt, req.After.ID)
+		argIndex += 2
+	}
+
+	query := fmt.Sprintf(`
+		SELECT * FROM "zpDrafts" %s
+		ORDER BY "createdAt" DESC, id DESC
+		LIMIT $%d OFFSET $%d
+	`, whereClause, argIndex, argIndex+1)
+
+	args = append(args, req.Limit, req.Offset)
+
+	var models []draftModel
+	if err := r.db.SelectContext(ctx, &models, query, args...); err != nil {
+		r.logger.ErrorWithFields("Failed to list drafts", map[string]interface{}{
+			"session_id": req.SessionID,
+			"error":      err.Error(),
+		})
+		return nil, 0, fmt.Errorf("failed to list drafts: %w", err)
+	}
+
+	return r.fromModels(models), total, nil
+}
+
+func (r *draftRepository) Update(ctx context.Context, d *draft.Draft) error {
+	model := r.toModel(d)
+
+	query := `
+		UPDATE "zpDrafts"
+		SET text = :text, status = :status, "scheduledAt" = :scheduledAt, "lastError" = :lastError, workspace = :workspace
+		WHERE id = :id
+	`
+
+	result, err := r.db.NamedExecContext(ctx, query, model)
+	if err != nil {
+		r.logger.ErrorWithFields("Failed to update draft", map[string]interface{}{
+			"draft_id": d.ID.String(),
+			"error":    err.Error(),
+		})
+		return fmt.Errorf("failed to update draft: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return draft.ErrDraftNotFound
+	}
+
+	return nil
+}
+
+func (r *draftRepository) Delete(ctx context.Context, sessionID, chatJID string) error {
+	query := `DELETE FROM "zpDrafts" WHERE "sessionId" = $1 AND "chatJid" = $2`
+
+	if _, err := r.db.ExecContext(ctx, query, sessionID, chatJID); err != nil {
+		r.logger.ErrorWithFields("Failed to delete draft", map[string]interface{}{
+			"session_id": sessionID,
+			"chat_jid":   chatJID,
+			"error":      err.Error(),
+		})
+		return fmt.Errorf("failed to delete draft: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimDue atomically moves due scheduled drafts to sending so concurrent schedulers never send one twice
+func (r *draftRepository) ClaimDue(ctx context.Context, until time.Time, limit int) ([]*draft.Draft, error) {
+	query := `
+		UPDATE "zpDrafts" SET status = 'sending', "claimedAt" = $1
+		WHERE id IN (
+			SELECT id FROM "zpDrafts"
+			WHERE status = 'scheduled' AND "scheduledAt" <= $1
+			ORDER BY "scheduledAt" ASC
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *
+	`
+
+	var models []draftModel
+	if err := r.db.SelectContext(ctx, &models, query, until, limit); err != nil {
+		return nil, fmt.Errorf("failed to claim due drafts: %w", err)
+	}
+
+	return r.fromModels(models), nil
+}
+
+// ReleaseStale reschedules drafts the scheduler claimed before claimedBefore but never finished
+// sending, e.g. because the replica crashed mid-send
+func (r *draftRepository) ReleaseStale(ctx context.Context, claimedBefore time.Time) (int, error) {
+	query := `UPDATE "zpDrafts" SET status = 'scheduled' WHERE status = 'sending' AND "claimedAt" < $1`
+
+	result, err := r.db.ExecContext(ctx, query, claimedBefore)
+	if err != nil {
+		return 0, fmt.Errorf("failed to release stale drafts: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+func (r *draftRepository) GetByID(ctx context.Context, sessionID string, id uuid.UUID) (*draft.Draft, error) {
+	var model draftModel
+	query := `SELECT * FROM "zpDrafts" WHERE id = $1 AND "sessionId" = $2`
+
+	if err := r.db.GetContext(ctx, &model, query, id.String(), sessionID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, draft.ErrDraftNotFound
+		}
+		return nil, fmt.Errorf("failed to get draft: %w", err)
+	}
+
+	return r.fromModel(&model)
+}
+
+// CancelScheduled only cancels drafts still scheduled, so one the scheduler claimed is never reported cancelled
+func (r *draftRepository) CancelScheduled(ctx context.Context, sessionID string, id uuid.UUID) (bool, error) {
+	query := `
+		UPDATE "zpDrafts" SET status = 'draft', "scheduledAt" = NULL
+		WHERE id = $1 AND "sessionId" = $2 AND status = 'scheduled'
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id.String(), sessionID)
+	if err != nil {
+		return false, fmt.Errorf("failed to cancel scheduled draft: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (r *draftRepository) toModel(d *draft.Draft) *draftModel {
+	model := &draftModel{
+		ID:        d.ID.String(),
+		SessionID: d.SessionID,
+		ChatJID:   d.ChatJID,
+		Text:      d.Text,
+		Status:    d.Status,
+		Workspace: d.Workspace,
+		CreatedAt: d.CreatedAt,
+		UpdatedAt: d.UpdatedAt,
+	}
+
+	if d.ScheduledAt != nil {
+		model.ScheduledAt = sql.NullTime{Time: *d.ScheduledAt, Valid: true}
+	}
+	if d.LastError != "" {
+		model.LastError = sql.NullString{String: d.LastError, Valid: true}
+	}
+
+	return model
+}
+
+func (r *draftRepository) fromModel(model *draftModel) (*draft.Draft, error) {
+	id, err := uuid.Parse(model.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid draft ID: %w", err)
+	}
+
+	d := &draft.Draft{
+		ID:        id,
+		SessionID: model.SessionID,
+		ChatJID:   model.ChatJID,
+		Text:      model.Text,
+		Status:    model.Status,
+		LastError: model.LastError.String,
+		Workspace: model.Workspace,
+		CreatedAt: model.CreatedAt,
+		UpdatedAt: model.UpdatedAt,
+	}
+
+	if model.ScheduledAt.Valid {
+		scheduledAt := model.ScheduledAt.Time
+		d.ScheduledAt = &scheduledAt
+	}
+
+	return d, nil
+}
+
+func (r *draftRepository) fromModels(models []draftModel) []*draft.Draft {
+	drafts := make([]*draft.Draft, 0, len(models))
+	for _, model := range models {
+		d, err := r.fromModel(&model)
+		if err != nil {
+			r.logger.WarnWithFields("Failed to convert draft model", map[string]interface{}{
+				"draft_id": model.ID,
+				"error":    err.Error(),
+			})
+			continue
+		}
+		drafts = append(drafts, d)
+	}
+
+	return drafts
+}
@@ -0,0 +1,435 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"zpwoot/internal/domain/history"
+	"zpwoot/internal/ports"
+	"zpwoot/platform/logger"
+)
+
+type historyRepository struct {
+	db     *sqlx.DB
+	logger *logger.Logger
+}
+
+func NewHistoryRepository(db *sqlx.DB, logger *logger.Logger) ports.HistoryRepository {
+	return &historyRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+type historyMessageModel struct {
+	ID                 string         `db:"id"`
+	SessionID          string         `db:"sessionId"`
+	ChatJID            string         `db:"chatJid"`
+	MessageID          string         `db:"messageId"`
+	SenderJID          string         `db:"senderJid"`
+	FromMe             bool           `db:"fromMe"`
+	Type               string         `db:"type"`
+	Text               string         `db:"text"`
+	Media              sql.NullString `db:"media"`
+	Context            sql.NullString `db:"context"`
+	Timestamp          time.Time      `db:"timestamp"`
+	EditedAt           sql.NullTime   `db:"editedAt"`
+	RevokedAt          sql.NullTime   `db:"revokedAt"`
+	PinnedAt           sql.NullTime   `db:"pinnedAt"`
+	PinExpiresAt       sql.NullTime   `db:"pinExpiresAt"`
+	PinnedBy           sql.NullString `db:"pinnedBy"`
+	EphemeralDuration  sql.NullInt64  `db:"ephemeralDuration"`
+	EphemeralExpiresAt sql.NullTime   `db:"ephemeralExpiresAt"`
+	ActorType          sql.NullString `db:"actorType"`
+	ActorID            sql.NullString `db:"actorId"`
+	CreatedAt          time.Time      `db:"createdAt"`
+	UpdatedAt          time.Time      `db:"updatedAt"`
+}
+
+// Upsert stores a message; a replay of a stored message refreshes its content, except the text of an
+// edited message, and keeps its edit, revoke and pin state
+func (r *historyRepository) Upsert(ctx context.Context, m *history.Message) error {
+	model, err := r.toModel(m)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO "zpMessages" (id, "sessionId", "chatJid", "messageId", "senderJid", "fromMe", type, text, media, context, timestamp, "ephemeralDuration", "ephemeralExpiresAt", "createdAt", "updatedAt")
+		VALUES (:id, :sessionId, :chatJid, :messageId, :senderJid, :fromMe, :type, :text, :media, :context, :timestamp, :ephemeralDuration, :ephemeralExpiresAt, :createdAt, :updatedAt)
+		ON CONFLICT ("sessionId", "chatJid", "messageId") DO UPDATE SET
+			"senderJid" = EXCLUDED."senderJid",
+			"fromMe" = EXCLUDED."fromMe",
+			type = EXCLUDED.type,
+			text = CASE WHEN "zpMessages"."editedAt" IS NULL THEN EXCLUDED.text ELSE "zpMessages".text END,
+			media = EXCLUDED.media,
+			context = EXCLUDED.context,
+			timestamp = EXCLUDED.timestamp,
+			"ephemeralDuration" = EXCLUDED."ephemeralDuration",
+			"ephemeralExpiresAt" = EXCLUDED."ephemeralExpiresAt"
+	`
+
+	if _, err := r.db.NamedExecContext(ctx, query, model); err != nil {
+		r.logger.ErrorWithFields("Failed to store message", map[string]interface{}{
+			"session_id": m.SessionID,
+			"chat_jid":   m.ChatJID,
+			"message_id": m.MessageID,
+			"error":      err.Error(),
+		})
+		return fmt.Errorf("failed to store message: %w", err)
+	}
+
+	return nil
+}
+
+func (r *historyRepository) MarkEdited(ctx context.Context, sessionID, chatJID, messageID, text string, editedAt time.Time) error {
+	query := `
+		UPDATE "zpMessages" SET text = $4, "editedAt" = $5
+		WHERE "sessionId" = $1 AND "chatJid" = $2 AND "messageId" = $3
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, sessionID, chatJID, messageID, text, editedAt); err != nil {
+		r.logger.ErrorWithFields("Failed to store message edit", map[string]interface{}{
+			"session_id": sessionID,
+			"chat_jid":   chatJID,
+			"message_id": messageID,
+			"error":      err.Error(),
+		})
+		return fmt.Errorf("failed to store message edit: %w", err)
+	}
+
+	return nil
+}
+
+func (r *historyRepository) MarkRevoked(ctx context.Context, sessionID, chatJID, messageID string, revokedAt time.Time) error {
+	query := `
+		UPDATE "zpMessages" SET "revokedAt" = $4
+		WHERE "sessionId" = $1 AND "chatJid" = $2 AND "messageId" = $3
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, sessionID, chatJID, messageID, revokedAt); err != nil {
+		r.logger.ErrorWithFields("Failed to store message revoke", map[string]interface{}{
+			"session_id": sessionID,
+			"chat_jid":   chatJID,
+			"message_id": messageID,
+			"error":      err.Error(),
+		})
+		return fmt.Errorf("failed to store message revoke: %w", err)
+	}
+
+	return nil
+}
+
+func (r *historyRepository) SetPin(ctx context.Context, sessionID, chatJID, messageID string, pin *history.Pin) error {
+	var pinnedAt, expiresAt sql.NullTime
+	var pinnedBy sql.NullString
+	if pin != nil {
+		pinnedAt = sql.NullTime{Time: pin.PinnedAt, Valid: true}
+		expiresAt = sql.NullTime{Time: pin.ExpiresAt, Valid: true}
+		pinnedBy = sql.NullString{String: pin.PinnedBy, Valid: pin.PinnedBy != ""}
+	}
+
+	query := `
+		UPDATE "zpMessages" SET "pinnedAt" = $4, "pinExpiresAt" = $5, "pinnedBy" = $6
+		WHERE "sessionId" = $1 AND "chatJid" = $2 AND "messageId" = $3
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, sessionID, chatJID, messageID, pinnedAt, expiresAt, pinnedBy); err != nil {
+		r.logger.ErrorWithFields("Failed to store message pin", map[string]interface{}{
+			"session_id": sessionID,
+			"chat_jid":   chatJID,
+			"message_id": messageID,
+			"error":      err.Error(),
+		})
+		return fmt.Errorf("failed to store message pin: %w", err)
+	}
+
+	return nil
+}
+
+func (r *historyRepository) SetActor(ctx context.Context, sessionID, messageID string, actor *history.Actor) error {
+	query := `
+		UPDATE "zpMessages" SET "actorType" = $3, "actorId" = $4
+		WHERE "sessionId" = $1 AND "messageId" = $2 AND "fromMe" = TRUE
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, sessionID, messageID, actor.Type, actor.ID); err != nil {
+		r.logger.ErrorWithFields("Failed to store message actor", map[string]interface{}{
+			"session_id": sessionID,
+			"message_id": messageID,
+			"actor_type": actor.Type,
+			"error":      err.Error(),
+		})
+		return fmt.Errorf("failed to store message actor: %w", err)
+	}
+
+	return nil
+}
+
+func (r *historyRepository) GetByMessageID(ctx context.Context, sessionID, messageID string) (*history.Message, error) {
+	query := `
+		SELECT * FROM "zpMessages"
+		WHERE "sessionId" = $1 AND "messageId" = $2
+		ORDER BY timestamp DESC
+		LIMIT 1
+	`
+
+	return r.getOne(ctx, query, sessionID, messageID)
+}
+
+func (r *historyRepository) GetOldestInChat(ctx context.Context, sessionID, chatJID string) (*history.Message, error) {
+	query := `
+		SELECT * FROM "zpMessages"
+		WHERE "sessionId" = $1 AND "chatJid" = $2
+		ORDER BY timestamp ASC, id ASC
+		LIMIT 1
+	`
+
+	return r.getOne(ctx, query, sessionID, chatJID)
+}
+
+// getOne runs a query selecting at most one message; no row is history.ErrMessageNotFound
+func (r *historyRepository) getOne(ctx context.Context, query string, sessionID, key string) (*history.Message, error) {
+	var model historyMessageModel
+	if err := r.db.GetContext(ctx, &model, query, sessionID, key); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, history.ErrMessageNotFound
+		}
+		r.logger.ErrorWithFields("Failed to get message", map[string]interface{}{
+			"session_id": sessionID,
+			"key":        key,
+			"error":      err.Error(),
+		})
+		return nil, fmt.Errorf("failed to get message: %w", err)
+	}
+
+	return r.fromModel(&model)
+}
+
+func (r *historyRepository) ListReceivedUntil(ctx context.Context, sessionID, chatJID string, until time.Time, limit int) ([]*history.Message, error) {
+	query := `
+		SELECT * FROM "zpMessages"
+		WHERE "sessionId" = $1 AND "chatJid" = $2 AND "fromMe" = FALSE AND timestamp <= $3
+		ORDER BY timestamp DESC
+		LIMIT $4
+	`
+
+	var models []historyMessageModel
+	if err := r.db.SelectContext(ctx, &models, query, sessionID, chatJID, until, limit); err != nil {
+		r.logger.ErrorWithFields("Failed to list received messages", map[string]interface{}{
+			"session_id": sessionID,
+			"chat_jid":   chatJID,
+			"error":      err.Error(),
+		})
+		return nil, fmt.Errorf("failed to list received messages: %w", err)
+	}
+
+	messages := make([]*history.Message, 0, len(models))
+	for i := range models {
+		m, err := r.fromModel(&models[i])
+		if err != nil {
+			r.logger.WarnWithFields("Failed to decode message", map[string]interface{}{
+				"id":    models[i].ID,
+				"error": err.Error(),
+			})
+			continue
+		}
+		messages = append(messages, m)
+	}
+
+	return messages, nil
+}
+
+func (r *historyRepository) ListByChat(ctx context.Context, req *history.ListRequest) ([]*history.Message, int, error) {
+	// Ephemeral messages are gone from the chat once expired, so they are gone from its history too
+	whereClause := `WHERE "sessionId" = $1 AND "chatJid" = $2 AND ("ephemeralExpiresAt" IS NULL OR "ephemeralExpiresAt" > NOW())`
+	args := []interface{}{req.SessionID, req.ChatJID}
+	argIndex := 3
+
+	if req.From != nil {
+		whereClause += fmt.Sprintf(" AND timestamp >= $%d", argIndex)
+		args = append(args, *req.From)
+		argIndex++
+	}
+	if req.To != nil {
+		whereClause += fmt.Sprintf(" AND timestamp <= $%d", argIndex)
+		args = append(args, *req.To)
+		argIndex++
+	}
+	if req.Query != "" {
+		whereClause += fmt.Sprintf(" AND to_tsvector('simple', text) @@ plainto_tsquery('simple', $%d)", argIndex)
+		args = append(args, req.Query)
+		argIndex++
+	}
+	if req.ActorType != "" {
+		whereClause += fmt.Sprintf(` AND "actorType" = $%d`, argIndex)
+		args = append(args, req.ActorType)
+		argIndex++
+	}
+	if req.Pinned {
+		whereClause += ` AND "pinnedAt" IS NOT NULL AND "pinExpiresAt" > NOW()`
+	}
+
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM "zpMessages" %s`, whereClause)
+	var total int
+	if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
+		r.logger.ErrorWithFields("Failed to count messages", map[string]interface{}{
+			"session_id": req.SessionID,
+			"chat_jid":   req.ChatJID,
+			"error":      err.Error(),
+		})
+		return nil, 0, fmt.Errorf("failed to count messages: %w", err)
+	}
+
+	// The cursor narrows the page, not the total
+	if req.After != nil {
+		whereClause += fmt.Sprintf(` AND (timestamp, id) < ($%d, $%d)`, argIndex, argIndex+1)
+		args = append(args, req.After.CreatedAt, req.After.ID)
+		argIndex += 2
+	}
+
+	query := fmt.Sprintf(`
+		SELECT * FROM "zpMessages" %s
+		ORDER BY timestamp DESC, id DESC
+		LIMIT $%d OFFSET $%d
+	`, whereClause, argIndex, argIndex+1)
+
+	args = append(args, req.Limit, req.Offset)
+
+	var models []historyMessageModel
+	if err := r.db.SelectContext(ctx, &models, query, args...); err != nil {
+		r.logger.ErrorWithFields("Failed to list messages", map[string]interface{}{
+			"session_id": req.SessionID,
+			"chat_jid":   req.ChatJID,
+			"error":      err.Error(),
+		})
+		return nil, 0, fmt.Errorf("failed to list messages: %w", err)
+	}
+
+	messages := make([]*history.Message, 0, len(models))
+	for i := range models {
+		m, err := r.fromModel(&models[i])
+		if err != nil {
+			r.logger.WarnWithFields("Failed to decode message", map[string]interface{}{
+				"id":    models[i].ID,
+				"error": err.Error(),
+			})
+			continue
+		}
+		messages = append(messages, m)
+	}
+
+	return messages, total, nil
+}
+
+func (r *historyRepository) toModel(m *history.Message) (*historyMessageModel, error) {
+	model := &historyMessageModel{
+		ID:        m.ID.String(),
+		SessionID: m.SessionID,
+		ChatJID:   m.ChatJID,
+		MessageID: m.MessageID,
+		SenderJID: m.SenderJID,
+		FromMe:    m.FromMe,
+		Type:      m.Type,
+		Text:      m.Text,
+		Timestamp: m.Timestamp,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+
+	if m.Media != nil {
+		encoded, err := json.Marshal(m.Media)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal message media: %w", err)
+		}
+		model.Media = sql.NullString{String: string(encoded), Valid: true}
+	}
+	if m.Context != nil {
+		encoded, err := json.Marshal(m.Context)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal message context: %w", err)
+		}
+		model.Context = sql.NullString{String: string(encoded), Valid: true}
+	}
+	if m.EditedAt != nil {
+		model.EditedAt = sql.NullTime{Time: *m.EditedAt, Valid: true}
+	}
+	if m.RevokedAt != nil {
+		model.RevokedAt = sql.NullTime{Time: *m.RevokedAt, Valid: true}
+	}
+	if m.Pin != nil {
+		model.PinnedAt = sql.NullTime{Time: m.Pin.PinnedAt, Valid: true}
+		model.PinExpiresAt = sql.NullTime{Time: m.Pin.ExpiresAt, Valid: true}
+		model.PinnedBy = sql.NullString{String: m.Pin.PinnedBy, Valid: m.Pin.PinnedBy != ""}
+	}
+	if m.Ephemeral != nil {
+		model.EphemeralDuration = sql.NullInt64{Int64: int64(m.Ephemeral.Duration), Valid: true}
+		model.EphemeralExpiresAt = sql.NullTime{Time: m.Ephemeral.ExpiresAt, Valid: true}
+	}
+
+	return model, nil
+}
+
+func (r *historyRepository) fromModel(model *historyMessageModel) (*history.Message, error) {
+	id, err := uuid.Parse(model.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid message ID: %w", err)
+	}
+
+	m := &history.Message{
+		ID:        id,
+		SessionID: model.SessionID,
+		ChatJID:   model.ChatJID,
+		MessageID: model.MessageID,
+		SenderJID: model.SenderJID,
+		FromMe:    model.FromMe,
+		Type:      model.Type,
+		Text:      model.Text,
+		Timestamp: model.Timestamp,
+		CreatedAt: model.CreatedAt,
+		UpdatedAt: model.UpdatedAt,
+	}
+
+	if model.ActorType.Valid {
+		m.Actor = &history.Actor{Type: model.ActorType.String, ID: model.ActorID.String}
+	}
+	if model.Media.Valid {
+		m.Media = &history.Media{}
+		if err := json.Unmarshal([]byte(model.Media.String), m.Media); err != nil {
+			return nil, fmt.Errorf("failed to decode message media: %w", err)
+		}
+	}
+	if model.Context.Valid {
+		m.Context = &history.ContextInfo{}
+		if err := json.Unmarshal([]byte(model.Context.String), m.Context); err != nil {
+			return nil, fmt.Errorf("failed to decode message context: %w", err)
+		}
+	}
+	if model.EditedAt.Valid {
+		m.EditedAt = &model.EditedAt.Time
+	}
+	if model.RevokedAt.Valid {
+		m.RevokedAt = &model.RevokedAt.Time
+	}
+	if model.PinnedAt.Valid {
+		m.Pin = &history.Pin{
+			PinnedAt:  model.PinnedAt.Time,
+			ExpiresAt: model.PinExpiresAt.Time,
+			PinnedBy:  model.PinnedBy.String,
+		}
+	}
+	if model.EphemeralExpiresAt.Valid {
+		m.Ephemeral = &history.Ephemeral{
+			Duration:  uint32(model.EphemeralDuration.Int64),
+			ExpiresAt: model.EphemeralExpiresAt.Time,
+		}
+	}
+
+	return m, nil
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"zpwoot/internal/domain/maintenance"
+	"zpwoot/internal/ports"
+	"zpwoot/platform/logger"
+)
+
+// instanceScope is the sessionId of the row holding the maintenance of the whole instance
+const instanceScope = ""
+
+type maintenanceRepository struct {
+	db     *sqlx.DB
+	logger *logger.Logger
+}
+
+func NewMaintenanceRepository(db *sqlx.DB, logger *logger.Logger) ports.MaintenanceRepository {
+	return &maintenanceRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+type maintenanceModel struct {
+	SessionID         string    `db:"sessionId"`
+	SessionName       string    `db:"sessionName"`
+	Reason            string    `db:"reason"`
+	RetryAfterSeconds int       `db:"retryAfterSeconds"`
+	Since             time.Time `db:"since"`
+	UpdatedAt         time.Time `db:"updatedAt"`
+}
+
+func (r *maintenanceRepository) Get(ctx context.Context) (*maintenance.Status, error) {
+	var models []maintenanceModel
+	if err := r.db.SelectContext(ctx, &models, `SELECT * FROM "zpMaintenance" ORDER BY since`); err != nil {
+		return nil, fmt.Errorf("failed to get maintenance state: %w", err)
+	}
+
+	status := &maintenance.Status{
+		Sessions: make([]*maintenance.SessionFreeze, 0, len(models)),
+	}
+	for _, model := range models {
+		if model.SessionID == instanceScope {
+			since := model.Since
+			status.Enabled = true
+			status.Reason = model.Reason
+			status.Since = &since
+			status.RetryAfter = time.Duration(model.RetryAfterSeconds) * time.Second
+			continue
+		}
+		status.Sessions = append(status.Sessions, &maintenance.SessionFreeze{
+			SessionID:   model.SessionID,
+			SessionName: model.SessionName,
+			Reason:      model.Reason,
+			Since:       model.Since,
+		})
+	}
+
+	return status, nil
+}
+
+func (r *maintenanceRepository) SaveInstance(ctx context.Context, reason string, retryAfter time.Duration) error {
+	query := `
+		INSERT INTO "zpMaintenance" ("sessionId", reason, "retryAfterSeconds")
+		VALUES ($1, $2, $3)
+		ON CONFLICT ("sessionId") DO UPDATE SET
+			reason = EXCLUDED.reason,
+			"retryAfterSeconds" = EXCLUDED."retryAfterSeconds",
+			"updatedAt" = NOW()
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, instanceScope, reason, int(retryAfter/time.Second)); err != nil {
+		r.logger.ErrorWithFields("Failed to save instance maintenance", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return fmt.Errorf("failed to save instance maintenance: %w", err)
+	}
+
+	return nil
+}
+
+func (r *maintenanceRepository) DeleteInstance(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM "zpMaintenance" WHERE "sessionId" = $1`, instanceScope); err != nil {
+		return fmt.Errorf("failed to delete instance maintenance: %w", err)
+	}
+
+	return nil
+}
+
+func (r *maintenanceRepository) SaveSession(ctx context.Context, freeze *maintenance.SessionFreeze) error {
+	query := `
+		INSERT INTO "zpMaintenance" ("sessionId", "sessionName", reason)
+		VALUES ($1, $2, $3)
+		ON CONFLICT ("sessionId") DO UPDATE SET
+			"sessionName" = EXCLUDED."sessionName",
+			reason = EXCLUDED.reason,
+			"updatedAt" = NOW()
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, freeze.SessionID, freeze.SessionName, freeze.Reason); err != nil {
+		r.logger.ErrorWithFields("Failed to save session freeze", map[string]interface{}{
+			"session_id": freeze.SessionID,
+			"error":      err.Error(),
+		})
+		return fmt.Errorf("failed to save session freeze: %w", err)
+	}
+
+	return nil
+}
+
+func (r *maintenanceRepository) DeleteSession(ctx context.Context, sessionID string) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM "zpMaintenance" WHERE "sessionId" = $1`, sessionID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete session freeze: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 
 	"zpwoot/internal/ports"
 	"zpwoot/platform/logger"
@@ -49,13 +50,17 @@ type zpMessageModel struct {
 	CreatedAt  time.Time    `db:"createdAt"`
 	UpdatedAt  time.Time    `db:"updatedAt"`
 	SyncedAt   sql.NullTime `db:"syncedAt"`
+	ReadSentAt sql.NullTime `db:"readSentAt"`
 }
 
-// CreateMessage creates a new message mapping
-func (r *MessageRepository) CreateMessage(ctx context.Context, message *ports.ZpMessage) error {
-	r.logger.InfoWithFields("Creating zpMessage mapping", map[string]interface{}{
+// UpsertMessage stores a message keyed by session, chat and WhatsApp message ID.
+// Replays of a stored message refresh its WhatsApp data but keep the Chatwoot mapping and sync state;
+// the message is updated in place with the stored row and inserted reports whether it was new
+func (r *MessageRepository) UpsertMessage(ctx context.Context, message *ports.ZpMessage) (bool, error) {
+	r.logger.DebugWithFields("Upserting zpMessage", map[string]interface{}{
 		"session_id":    message.SessionID,
 		"zp_message_id": message.ZpMessageID,
+		"zp_chat":       message.ZpChat,
 		"sync_status":   message.SyncStatus,
 	})
 
@@ -71,19 +76,49 @@ func (r *MessageRepository) CreateMessage(ctx context.Context, message *ports.Zp
 			:zpFromMe, :zpType, :content, :cwMessageId, :cwConversationId,
 			:syncStatus, :createdAt, :updatedAt, :syncedAt
 		)
+		ON CONFLICT ("sessionId", "zpChat", "zpMessageId") DO UPDATE SET
+			"zpSender" = EXCLUDED."zpSender",
+			"zpTimestamp" = EXCLUDED."zpTimestamp",
+			"zpFromMe" = EXCLUDED."zpFromMe",
+			"zpType" = EXCLUDED."zpType",
+			"content" = COALESCE(NULLIF(EXCLUDED."content", ''), "zpMessage"."content"),
+			"cwMessageId" = COALESCE("zpMessage"."cwMessageId", EXCLUDED."cwMessageId"),
+			"cwConversationId" = COALESCE("zpMessage"."cwConversationId", EXCLUDED."cwConversationId")
+		RETURNING *, (xmax = 0) AS inserted
 	`
 
-	_, err := r.db.NamedExecContext(ctx, query, model)
+	rows, err := r.db.NamedQueryContext(ctx, query, model)
 	if err != nil {
-		r.logger.ErrorWithFields("Failed to create zpMessage", map[string]interface{}{
+		r.logger.ErrorWithFields("Failed to upsert zpMessage", map[string]interface{}{
 			"session_id":    message.SessionID,
 			"zp_message_id": message.ZpMessageID,
 			"error":         err.Error(),
 		})
-		return fmt.Errorf("failed to create zpMessage: %w", err)
+		return false, fmt.Errorf("failed to upsert zpMessage: %w", err)
 	}
+	defer rows.Close()
 
-	return nil
+	var stored struct {
+		zpMessageModel
+		Inserted bool `db:"inserted"`
+	}
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return false, fmt.Errorf("failed to upsert zpMessage: %w", err)
+		}
+		return false, fmt.Errorf("failed to upsert zpMessage: no row returned")
+	}
+	if err := rows.StructScan(&stored); err != nil {
+		return false, fmt.Errorf("failed to scan upserted zpMessage: %w", err)
+	}
+
+	result, err := r.messageFromModel(&stored.zpMessageModel)
+	if err != nil {
+		return false, fmt.Errorf("failed to convert model to domain: %w", err)
+	}
+	*message = *result
+
+	return stored.Inserted, nil
 }
 
 // GetMessageByZpID gets a message by WhatsApp message ID
@@ -117,21 +152,23 @@ func (r *MessageRepository) GetMessageByZpID(ctx context.Context, sessionID, zpM
 	return message, nil
 }
 
-// GetMessageByCwID gets a message by Chatwoot message ID
-func (r *MessageRepository) GetMessageByCwID(ctx context.Context, cwMessageID int) (*ports.ZpMessage, error) {
+// GetMessageByCwID gets a message of the session by Chatwoot message ID
+func (r *MessageRepository) GetMessageByCwID(ctx context.Context, sessionID string, cwMessageID int) (*ports.ZpMessage, error) {
 	r.logger.DebugWithFields("Getting zpMessage by CW ID", map[string]interface{}{
+		"session_id":    sessionID,
 		"cw_message_id": cwMessageID,
 	})
 
 	var model zpMessageModel
-	query := `SELECT * FROM "zpMessage" WHERE "cwMessageId" = $1`
+	query := `SELECT * FROM "zpMessage" WHERE "sessionId" = $1 AND "cwMessageId" = $2`
 
-	err := r.db.GetContext(ctx, &model, query, cwMessageID)
+	err := r.db.GetContext(ctx, &model, query, sessionID, cwMessageID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("zpMessage not found")
 		}
 		r.logger.ErrorWithFields("Failed to get zpMessage by CW ID", map[string]interface{}{
+			"session_id":    sessionID,
 			"cw_message_id": cwMessageID,
 			"error":         err.Error(),
 		})
@@ -202,6 +239,26 @@ func (r *MessageRepository) UpdateSyncStatus(ctx context.Context, id string, sta
 	return nil
 }
 
+// MarkReadSent records that a read receipt was sent for the given WhatsApp messages
+func (r *MessageRepository) MarkReadSent(ctx context.Context, sessionID string, zpMessageIDs []string) error {
+	query := `
+		UPDATE "zpMessage"
+		SET "readSentAt" = NOW(), "updatedAt" = NOW()
+		WHERE "sessionId" = $1 AND "zpMessageId" = ANY($2)
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, sessionID, pq.Array(zpMessageIDs)); err != nil {
+		r.logger.ErrorWithFields("Failed to mark zpMessages as read", map[string]interface{}{
+			"session_id":  sessionID,
+			"message_ids": zpMessageIDs,
+			"error":       err.Error(),
+		})
+		return fmt.Errorf("failed to mark zpMessages as read: %w", err)
+	}
+
+	return nil
+}
+
 // GetMessagesBySession gets messages by session ID
 func (r *MessageRepository) GetMessagesBySession(ctx context.Context, sessionID string, limit, offset int) ([]*ports.ZpMessage, error) {
 	r.logger.DebugWithFields("Getting zpMessages by session", map[string]interface{}{
@@ -286,6 +343,48 @@ func (r *MessageRepository) GetMessagesByChat(ctx context.Context, sessionID, ch
 	return messages, nil
 }
 
+// GetMessagesByCwConversation gets the most recent messages of a Chatwoot conversation
+func (r *MessageRepository) GetMessagesByCwConversation(ctx context.Context, sessionID string, cwConversationID, limit int) ([]*ports.ZpMessage, error) {
+	r.logger.DebugWithFields("Getting zpMessages by CW conversation", map[string]interface{}{
+		"session_id":         sessionID,
+		"cw_conversation_id": cwConversationID,
+		"limit":              limit,
+	})
+
+	var models []zpMessageModel
+	query := `
+		SELECT * FROM "zpMessage"
+		WHERE "sessionId" = $1 AND "cwConversationId" = $2
+		ORDER BY "zpTimestamp" DESC
+		LIMIT $3
+	`
+
+	err := r.db.SelectContext(ctx, &models, query, sessionID, cwConversationID, limit)
+	if err != nil {
+		r.logger.ErrorWithFields("Failed to get zpMessages by CW conversation", map[string]interface{}{
+			"session_id":         sessionID,
+			"cw_conversation_id": cwConversationID,
+			"error":              err.Error(),
+		})
+		return nil, fmt.Errorf("failed to get zpMessages: %w", err)
+	}
+
+	messages := make([]*ports.ZpMessage, 0, len(models))
+	for _, model := range models {
+		message, err := r.messageFromModel(&model)
+		if err != nil {
+			r.logger.WarnWithFields("Failed to convert model to domain", map[string]interface{}{
+				"id":    model.ID,
+				"error": err.Error(),
+			})
+			continue
+		}
+		messages = append(messages, message)
+	}
+
+	return messages, nil
+}
+
 // GetPendingSyncMessages gets messages with pending sync status
 func (r *MessageRepository) GetPendingSyncMessages(ctx context.Context, sessionID string, limit int) ([]*ports.ZpMessage, error) {
 	r.logger.DebugWithFields("Getting pending sync zpMessages", map[string]interface{}{
@@ -384,6 +483,10 @@ func (r *MessageRepository) messageToModel(message *ports.ZpMessage) *zpMessageM
 		model.SyncedAt = sql.NullTime{Time: *message.SyncedAt, Valid: true}
 	}
 
+	if message.ReadSentAt != nil {
+		model.ReadSentAt = sql.NullTime{Time: *message.ReadSentAt, Valid: true}
+	}
+
 	return model
 }
 
@@ -418,5 +521,9 @@ func (r *MessageRepository) messageFromModel(model *zpMessageModel) (*ports.ZpMe
 		message.SyncedAt = &model.SyncedAt.Time
 	}
 
+	if model.ReadSentAt.Valid {
+		message.ReadSentAt = &model.ReadSentAt.Time
+	}
+
 	return message, nil
 }
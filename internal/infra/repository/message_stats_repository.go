@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"zpwoot/internal/domain/stats"
+	"zpwoot/internal/ports"
+	"zpwoot/platform/logger"
+)
+
+type messageStatsRepository struct {
+	db     *sqlx.DB
+	logger *logger.Logger
+}
+
+func NewMessageStatsRepository(db *sqlx.DB, logger *logger.Logger) ports.MessageStatsRepository {
+	return &messageStatsRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+type messageStatsModel struct {
+	SessionID string    `db:"sessionId"`
+	Day       string    `db:"day"`
+	Type      string    `db:"type"`
+	Direction string    `db:"direction"`
+	Count     int64     `db:"count"`
+	UpdatedAt time.Time `db:"updatedAt"`
+}
+
+func (r *messageStatsRepository) Add(ctx context.Context, sessionID, day, msgType, direction string, delta int64) error {
+	query := `
+		INSERT INTO "zpMessageStats" ("sessionId", day, type, direction, count, "updatedAt")
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		ON CONFLICT ("sessionId", day, type, direction) DO UPDATE SET
+			count = "zpMessageStats".count + EXCLUDED.count,
+			"updatedAt" = NOW()
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, sessionID, day, msgType, direction, delta); err != nil {
+		r.logger.ErrorWithFields("Failed to add message statistics", map[string]interface{}{
+			"session_id": sessionID,
+			"day":        day,
+			"type":       msgType,
+			"direction":  direction,
+			"error":      err.Error(),
+		})
+		return fmt.Errorf("failed to add message statistics: %w", err)
+	}
+
+	return nil
+}
+
+func (r *messageStatsRepository) List(ctx context.Context, sessionID, from, to string) ([]*stats.DailyCount, error) {
+	query := `
+		SELECT * FROM "zpMessageStats"
+		WHERE "sessionId" = $1 AND day >= $2 AND day <= $3
+		ORDER BY day, type, direction
+	`
+
+	var models []messageStatsModel
+	if err := r.db.SelectContext(ctx, &models, query, sessionID, from, to); err != nil {
+		r.logger.ErrorWithFields("Failed to list message statistics", map[string]interface{}{
+			"session_id": sessionID,
+			"from":       from,
+			"to":         to,
+			"error":      err.Error(),
+		})
+		return nil, fmt.Errorf("failed to list message statistics: %w", err)
+	}
+
+	counts := make([]*stats.DailyCount, len(models))
+	for i, model := range models {
+		counts[i] = &stats.DailyCount{
+			SessionID: model.SessionID,
+			Day:       model.Day,
+			Type:      model.Type,
+			Direction: model.Direction,
+			Count:     model.Count,
+			UpdatedAt: model.UpdatedAt,
+		}
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,357 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"zpwoot/internal/domain/history"
+	"zpwoot/internal/domain/message"
+	"zpwoot/internal/domain/outbox"
+	"zpwoot/internal/ports"
+	"zpwoot/platform/logger"
+)
+
+type outboxRepository struct {
+	db     *sqlx.DB
+	logger *logger.Logger
+}
+
+func NewOutboxRepository(db *sqlx.DB, logger *logger.Logger) ports.OutboxRepository {
+	return &outboxRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+type outboxMessageModel struct {
+	ID        string         `db:"id"`
+	SessionID string         `db:"sessionId"`
+	ChatJID   string         `db:"chatJid"`
+	Workspace string         `db:"workspace"`
+	RequestID sql.NullString `db:"requestId"`
+	Actor     sql.NullString `db:"actor"`   // JSONB field
+	Request   string         `db:"request"` // JSONB field
+	Status    string         `db:"status"`
+	Attempts  int            `db:"attempts"`
+	MessageID sql.NullString `db:"messageId"`
+	LastError sql.NullString `db:"lastError"`
+	SendAfter time.Time      `db:"sendAfter"`
+	SentAt    sql.NullTime   `db:"sentAt"`
+	ClaimedAt sql.NullTime   `db:"claimedAt"`
+	CreatedAt time.Time      `db:"createdAt"`
+	UpdatedAt time.Time      `db:"updatedAt"`
+}
+
+func (r *outboxRepository) Create(ctx context.Context, msg *outbox.Message) error {
+	model, err := r.toModel(msg)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO "zpOutbox" (id, "sessionId", "chatJid", workspace, "requestId", actor, request, status, attempts, "sendAfter", "createdAt", "updatedAt")
+		VALUES (:id, :sessionId, :chatJid, :workspace, :requestId, :actor, :request, :status, :attempts, :sendAfter, :createdAt, :updatedAt)
+	`
+
+	if _, err := r.db.NamedExecContext(ctx, query, model); err != nil {
+		r.logger.ErrorWithFields("Failed to add message to the outbox", map[string]interface{}{
+			"session_id": msg.SessionID,
+			"error":      err.Error(),
+		})
+		return fmt.Errorf("failed to add message to the outbox: %w", err)
+	}
+
+	return nil
+}
+
+// LastSlot takes the send time of sent messages and the due time of the others, so a new message
+// is spaced out from whichever comes last
+func (r *outboxRepository) LastSlot(ctx context.Context, sessionID, chatJID string) (time.Time, error) {
+	query := `
+		SELECT MAX(COALESCE("sentAt", "sendAfter")) FROM "zpOutbox"
+		WHERE "sessionId" = $1 AND "chatJid" = $2 AND status IN ('queued', 'sending', 'sent')
+	`
+
+	var last sql.NullTime
+	if err := r.db.GetContext(ctx, &last, query, sessionID, chatJID); err != nil {
+		return time.Time{}, fmt.Errorf("failed to get last outbox slot: %w", err)
+	}
+
+	return last.Time, nil
+}
+
+// ClaimDue only claims the oldest queued message of a chat and skips chats with a message being
+// sent, so messages to a chat go out one at a time and in order even when several are due
+func (r *outboxRepository) ClaimDue(ctx context.Context, now time.Time, limit int) ([]*outbox.Message, error) {
+	query := `
+		UPDATE "zpOutbox" SET status = 'sending', attempts = attempts + 1, "claimedAt" = $1
+		WHERE id IN (
+			SELECT o.id FROM "zpOutbox" o
+			WHERE o.status = 'queued' AND o."sendAfter" <= $1
+			AND NOT EXISTS (
+				SELECT 1 FROM "zpOutbox" e
+				WHERE e."sessionId" = o."sessionId" AND e."chatJid" = o."chatJid"
+				AND (e.status = 'sending' OR (e.status = 'queued' AND (e."createdAt", e.id) < (o."createdAt", o.id)))
+			)
+			ORDER BY o."sendAfter" ASC
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *
+	`
+
+	var models []outboxMessageModel
+	if err := r.db.SelectContext(ctx, &models, query, now, limit); err != nil {
+		return nil, fmt.Errorf("failed to claim due outbox messages: %w", err)
+	}
+
+	return r.fromModels(models)
+}
+
+// ReleaseStale hands outbox messages claimed before claimedBefore back to the dispatcher; they were left in
+// sending by a worker that stopped before recording the outcome
+func (r *outboxRepository) ReleaseStale(ctx context.Context, claimedBefore time.Time) (int, error) {
+	query := `UPDATE "zpOutbox" SET status = 'queued' WHERE status = 'sending' AND "claimedAt" < $1`
+
+	result, err := r.db.ExecContext(ctx, query, claimedBefore)
+	if err != nil {
+		return 0, fmt.Errorf("failed to release stale outbox messages: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+func (r *outboxRepository) Update(ctx context.Context, msg *outbox.Message) error {
+	model, err := r.toModel(msg)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE "zpOutbox"
+		SET status = :status, "messageId" = :messageId, "lastError" = :lastError, "sendAfter" = :sendAfter, "sentAt" = :sentAt
+		WHERE id = :id
+	`
+
+	result, err := r.db.NamedExecContext(ctx, query, model)
+	if err != nil {
+		r.logger.ErrorWithFields("Failed to update outbox message", map[string]interface{}{
+			"outbox_id": msg.ID.String(),
+			"error":     err.Error(),
+		})
+		return fmt.Errorf("failed to update outbox message: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return outbox.ErrOutboxMessageNotFound
+	}
+
+	return nil
+}
+
+func (r *outboxRepository) Postpone(ctx context.Context, sessionID, chatJID string, sendAfter time.Time) error {
+	query := `
+		UPDATE "zpOutbox" SET "sendAfter" = $3
+		WHERE "sessionId" = $1 AND "chatJid" = $2 AND status = 'queued' AND "sendAfter" < $3
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, sessionID, chatJID, sendAfter); err != nil {
+		return fmt.Errorf("failed to postpone outbox messages: %w", err)
+	}
+
+	return nil
+}
+
+// CancelQueued only cancels messages still queued, so one the dispatcher claimed is never reported cancelled
+func (r *outboxRepository) CancelQueued(ctx context.Context, sessionID string, id uuid.UUID) (bool, error) {
+	query := `UPDATE "zpOutbox" SET status = 'cancelled' WHERE id = $1 AND "sessionId" = $2 AND status = 'queued'`
+
+	result, err := r.db.ExecContext(ctx, query, id.String(), sessionID)
+	if err != nil {
+		return false, fmt.Errorf("failed to cancel outbox message: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (r *outboxRepository) GetByID(ctx context.Context, sessionID string, id uuid.UUID) (*outbox.Message, error) {
+	var model outboxMessageModel
+	query := `SELECT * FROM "zpOutbox" WHERE id = $1 AND "sessionId" = $2`
+
+	if err := r.db.GetContext(ctx, &model, query, id.String(), sessionID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, outbox.ErrOutboxMessageNotFound
+		}
+		return nil, fmt.Errorf("failed to get outbox message: %w", err)
+	}
+
+	return r.fromModel(&model)
+}
+
+func (r *outboxRepository) List(ctx context.Context, req *outbox.ListRequest) ([]*outbox.Message, int, error) {
+	whereClause := `WHERE "sessionId" = $1`
+	args := []interface{}{req.SessionID}
+	argIndex := 2
+
+	if req.Status != "" {
+		whereClause += fmt.Sprintf(" AND status = $%d", argIndex)
+		args = append(args, req.Status)
+		argIndex++
+	}
+
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM "zpOutbox" %s`, whereClause)
+	var total int
+	if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
+		r.logger.ErrorWithFields("Failed to count outbox messages", map[string]interface{}{
+			"session_id": req.SessionID,
+			"error":      err.Error(),
+		})
+		return nil, 0, fmt.Errorf("failed to count outbox messages: %w", err)
+	}
+
+	// The cursor narrows the page, not the total
+	if req.After != nil {
+		whereClause += fmt.Sprintf(` AND ("createdAt", id) < ($%d, $%d)`, argIndex, argIndex+1)
+		args = append(args, req.After.CreatedAt, req.After.ID)
+		argIndex += 2
+	}
+
+	query := fmt.Sprintf(`
+		SELECT * FROM "zpOutbox" %s
+		ORDER BY "createdAt" DESC, id DESC
+		LIMIT $%d OFFSET $%d
+	`, whereClause, argIndex, argIndex+1)
+
+	args = append(args, req.Limit, req.Offset)
+
+	var models []outboxMessageModel
+	if err := r.db.SelectContext(ctx, &models, query, args...); err != nil {
+		r.logger.ErrorWithFields("Failed to list outbox messages", map[string]interface{}{
+			"session_id": req.SessionID,
+			"error":      err.Error(),
+		})
+		return nil, 0, fmt.Errorf("failed to list outbox messages: %w", err)
+	}
+
+	msgs, err := r.fromModels(models)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return msgs, total, nil
+}
+
+func (r *outboxRepository) toModel(msg *outbox.Message) (*outboxMessageModel, error) {
+	request, err := json.Marshal(msg.Request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal outbox message request: %w", err)
+	}
+
+	model := &outboxMessageModel{
+		ID:        msg.ID.String(),
+		SessionID: msg.SessionID,
+		ChatJID:   msg.ChatJID,
+		Workspace: msg.Workspace,
+		Request:   string(request),
+		Status:    msg.Status,
+		Attempts:  msg.Attempts,
+		SendAfter: msg.SendAfter,
+		CreatedAt: msg.CreatedAt,
+		UpdatedAt: msg.UpdatedAt,
+	}
+
+	if msg.RequestID != "" {
+		model.RequestID = sql.NullString{String: msg.RequestID, Valid: true}
+	}
+	if msg.Actor != nil {
+		actor, err := json.Marshal(msg.Actor)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal outbox message actor: %w", err)
+		}
+		model.Actor = sql.NullString{String: string(actor), Valid: true}
+	}
+	if msg.MessageID != "" {
+		model.MessageID = sql.NullString{String: msg.MessageID, Valid: true}
+	}
+	if msg.LastError != "" {
+		model.LastError = sql.NullString{String: msg.LastError, Valid: true}
+	}
+	if msg.SentAt != nil {
+		model.SentAt = sql.NullTime{Time: *msg.SentAt, Valid: true}
+	}
+
+	return model, nil
+}
+
+func (r *outboxRepository) fromModels(models []outboxMessageModel) ([]*outbox.Message, error) {
+	msgs := make([]*outbox.Message, 0, len(models))
+	for i := range models {
+		msg, err := r.fromModel(&models[i])
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+func (r *outboxRepository) fromModel(model *outboxMessageModel) (*outbox.Message, error) {
+	id, err := uuid.Parse(model.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid outbox message ID: %w", err)
+	}
+
+	var request message.SendMessageRequest
+	if err := json.Unmarshal([]byte(model.Request), &request); err != nil {
+		return nil, fmt.Errorf("invalid outbox message request: %w", err)
+	}
+
+	msg := &outbox.Message{
+		ID:        id,
+		SessionID: model.SessionID,
+		ChatJID:   model.ChatJID,
+		Workspace: model.Workspace,
+		RequestID: model.RequestID.String,
+		Request:   &request,
+		Status:    model.Status,
+		Attempts:  model.Attempts,
+		MessageID: model.MessageID.String,
+		LastError: model.LastError.String,
+		SendAfter: model.SendAfter,
+		CreatedAt: model.CreatedAt,
+		UpdatedAt: model.UpdatedAt,
+	}
+
+	if model.Actor.Valid {
+		msg.Actor = &history.Actor{}
+		if err := json.Unmarshal([]byte(model.Actor.String), msg.Actor); err != nil {
+			return nil, fmt.Errorf("invalid outbox message actor: %w", err)
+		}
+	}
+	if model.SentAt.Valid {
+		sentAt := model.SentAt.Time
+		msg.SentAt = &sentAt
+	}
+
+	return msg, nil
+}
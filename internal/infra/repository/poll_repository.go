@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"zpwoot/internal/domain/poll"
+	"zpwoot/internal/ports"
+	"zpwoot/platform/logger"
+)
+
+type pollRepository struct {
+	db     *sqlx.DB
+	logger *logger.Logger
+}
+
+func NewPollRepository(db *sqlx.DB, logger *logger.Logger) ports.PollRepository {
+	return &pollRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+type pollModel struct {
+	ID              string       `db:"id"`
+	SessionID       string       `db:"sessionId"`
+	MessageID       string       `db:"messageId"`
+	ChatJID         string       `db:"chatJid"`
+	Name            string       `db:"name"`
+	Options         string       `db:"options"`
+	SelectableCount int          `db:"selectableCount"`
+	ExpiresAt       sql.NullTime `db:"expiresAt"`
+	CreatedAt       time.Time    `db:"createdAt"`
+}
+
+// Create stores a poll definition; storing the same poll message twice keeps the first definition
+func (r *pollRepository) Create(ctx context.Context, p *poll.Poll) error {
+	model, err := r.toModel(p)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO "zpPolls" (id, "sessionId", "messageId", "chatJid", name, options, "selectableCount", "expiresAt", "createdAt")
+		VALUES (:id, :sessionId, :messageId, :chatJid, :name, :options, :selectableCount, :expiresAt, :createdAt)
+		ON CONFLICT ("sessionId", "messageId") DO NOTHING
+	`
+
+	if _, err := r.db.NamedExecContext(ctx, query, model); err != nil {
+		r.logger.ErrorWithFields("Failed to create poll", map[string]interface{}{
+			"session_id": p.SessionID,
+			"message_id": p.MessageID,
+			"error":      err.Error(),
+		})
+		return fmt.Errorf("failed to create poll: %w", err)
+	}
+
+	return nil
+}
+
+func (r *pollRepository) GetByMessageID(ctx context.Context, sessionID, messageID string) (*poll.Poll, error) {
+	var model pollModel
+	query := `SELECT * FROM "zpPolls" WHERE "sessionId" = $1 AND "messageId" = $2`
+
+	err := r.db.GetContext(ctx, &model, query, sessionID, messageID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, poll.ErrPollNotFound
+		}
+		r.logger.ErrorWithFields("Failed to get poll", map[string]interface{}{
+			"session_id": sessionID,
+			"message_id": messageID,
+			"error":      err.Error(),
+		})
+		return nil, fmt.Errorf("failed to get poll: %w", err)
+	}
+
+	return r.fromModel(&model)
+}
+
+type pollVoteModel struct {
+	PollID   string    `db:"pollId"`
+	VoterJID string    `db:"voterJid"`
+	Options  string    `db:"options"`
+	Late     bool      `db:"late"`
+	VotedAt  time.Time `db:"votedAt"`
+}
+
+// SaveVote stores the voter's selection unless a newer vote of the same voter is already stored
+func (r *pollRepository) SaveVote(ctx context.Context, vote *poll.Vote) error {
+	options := vote.Options
+	if options == nil {
+		options = []string{}
+	}
+	encoded, err := json.Marshal(options)
+	if err != nil {
+		return fmt.Errorf("failed to marshal poll vote: %w", err)
+	}
+
+	model := &pollVoteModel{
+		PollID:   vote.PollID.String(),
+		VoterJID: vote.VoterJID,
+		Options:  string(encoded),
+		Late:     vote.Late,
+		VotedAt:  vote.VotedAt,
+	}
+
+	query := `
+		INSERT INTO "zpPollVotes" ("pollId", "voterJid", options, late, "votedAt")
+		VALUES (:pollId, :voterJid, :options, :late, :votedAt)
+		ON CONFLICT ("pollId", "voterJid") DO UPDATE SET
+			options = EXCLUDED.options,
+			late = EXCLUDED.late,
+			"votedAt" = EXCLUDED."votedAt"
+		WHERE "zpPollVotes"."votedAt" <= EXCLUDED."votedAt"
+	`
+
+	if _, err := r.db.NamedExecContext(ctx, query, model); err != nil {
+		r.logger.ErrorWithFields("Failed to save poll vote", map[string]interface{}{
+			"poll_id": model.PollID,
+			"voter":   vote.VoterJID,
+			"error":   err.Error(),
+		})
+		return fmt.Errorf("failed to save poll vote: %w", err)
+	}
+
+	return nil
+}
+
+func (r *pollRepository) ListVotes(ctx context.Context, pollID string) ([]*poll.Vote, error) {
+	var models []pollVoteModel
+	query := `SELECT * FROM "zpPollVotes" WHERE "pollId" = $1 ORDER BY "votedAt" ASC`
+
+	if err := r.db.SelectContext(ctx, &models, query, pollID); err != nil {
+		r.logger.ErrorWithFields("Failed to list poll votes", map[string]interface{}{
+			"poll_id": pollID,
+			"error":   err.Error(),
+		})
+		return nil, fmt.Errorf("failed to list poll votes: %w", err)
+	}
+
+	votes := make([]*poll.Vote, 0, len(models))
+	for _, model := range models {
+		id, err := uuid.Parse(model.PollID)
+		if err != nil {
+			continue
+		}
+		vote := &poll.Vote{
+			PollID:   id,
+			VoterJID: model.VoterJID,
+			Options:  []string{},
+			Late:     model.Late,
+			VotedAt:  model.VotedAt,
+		}
+		if model.Options != "" {
+			if err := json.Unmarshal([]byte(model.Options), &vote.Options); err != nil {
+				r.logger.WarnWithFields("Failed to decode poll vote", map[string]interface{}{
+					"poll_id": pollID,
+					"voter":   model.VoterJID,
+					"error":   err.Error(),
+				})
+				continue
+			}
+		}
+		votes = append(votes, vote)
+	}
+
+	return votes, nil
+}
+
+func (r *pollRepository) toModel(p *poll.Poll) (*pollModel, error) {
+	options := p.Options
+	if options == nil {
+		options = []poll.Option{}
+	}
+	encoded, err := json.Marshal(options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal poll options: %w", err)
+	}
+
+	model := &pollModel{
+		ID:              p.ID.String(),
+		SessionID:       p.SessionID,
+		MessageID:       p.MessageID,
+		ChatJID:         p.ChatJID,
+		Name:            p.Name,
+		Options:         string(encoded),
+		SelectableCount: p.SelectableCount,
+		CreatedAt:       p.CreatedAt,
+	}
+
+	if p.ExpiresAt != nil {
+		model.ExpiresAt = sql.NullTime{Time: *p.ExpiresAt, Valid: true}
+	}
+
+	return model, nil
+}
+
+func (r *pollRepository) fromModel(model *pollModel) (*poll.Poll, error) {
+	id, err := uuid.Parse(model.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid poll ID: %w", err)
+	}
+
+	p := &poll.Poll{
+		ID:              id,
+		SessionID:       model.SessionID,
+		MessageID:       model.MessageID,
+		ChatJID:         model.ChatJID,
+		Name:            model.Name,
+		Options:         []poll.Option{},
+		SelectableCount: model.SelectableCount,
+		CreatedAt:       model.CreatedAt,
+	}
+
+	if model.Options != "" {
+		if err := json.Unmarshal([]byte(model.Options), &p.Options); err != nil {
+			return nil, fmt.Errorf("invalid poll options: %w", err)
+		}
+	}
+
+	if model.ExpiresAt.Valid {
+		expiresAt := model.ExpiresAt.Time
+		p.ExpiresAt = &expiresAt
+	}
+
+	return p, nil
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+
+	"zpwoot/platform/precondition"
+)
+
+// namedUpdate runs a named UPDATE whose WHERE clause comes last. When ctx carries a precondition version the
+// row is only updated while its "updatedAt" still equals it, so a concurrent change makes the update
+// miss instead of being overwritten; the returned flag tells callers to report a miss as
+// precondition.ErrFailed rather than as a missing row.
+func namedUpdate(ctx context.Context, db *sqlx.DB, query string, arg interface{}) (sql.Result, bool, error) {
+	version, conditional := precondition.Version(ctx)
+	if !conditional {
+		result, err := db.NamedExecContext(ctx, query, arg)
+		return result, false, err
+	}
+
+	bound, args, err := sqlx.Named(query, arg)
+	if err != nil {
+		return nil, true, err
+	}
+	bound += ` AND "updatedAt" = ?`
+	args = append(args, version)
+	result, err := db.ExecContext(ctx, db.Rebind(bound), args...)
+	return result, true, err
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"zpwoot/internal/domain/privacy"
+	"zpwoot/internal/ports"
+	"zpwoot/platform/logger"
+)
+
+type privacyRepository struct {
+	db     *sqlx.DB
+	logger *logger.Logger
+}
+
+func NewPrivacyRepository(db *sqlx.DB, logger *logger.Logger) ports.PrivacyRepository {
+	return &privacyRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+type privacyModel struct {
+	SessionID            string    `db:"sessionId"`
+	RedactLogs           bool      `db:"redactLogs"`
+	MetadataOnlyWebhooks bool      `db:"metadataOnlyWebhooks"`
+	UpdatedAt            time.Time `db:"updatedAt"`
+}
+
+func (r *privacyRepository) GetBySession(ctx context.Context, sessionID string) (*privacy.Config, error) {
+	var model privacyModel
+	query := `SELECT * FROM "zpPrivacySettings" WHERE "sessionId" = $1`
+
+	if err := r.db.GetContext(ctx, &model, query, sessionID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.ErrorWithFields("Failed to get privacy settings", map[string]interface{}{
+			"session_id": sessionID,
+			"error":      err.Error(),
+		})
+		return nil, fmt.Errorf("failed to get privacy settings: %w", err)
+	}
+
+	return &privacy.Config{
+		SessionID:            model.SessionID,
+		RedactLogs:           model.RedactLogs,
+		MetadataOnlyWebhooks: model.MetadataOnlyWebhooks,
+		UpdatedAt:            model.UpdatedAt,
+	}, nil
+}
+
+func (r *privacyRepository) Upsert(ctx context.Context, config *privacy.Config) error {
+	query := `
+		INSERT INTO "zpPrivacySettings" ("sessionId", "redactLogs", "metadataOnlyWebhooks", "updatedAt")
+		VALUES (:sessionId, :redactLogs, :metadataOnlyWebhooks, :updatedAt)
+		ON CONFLICT ("sessionId") DO UPDATE SET
+			"redactLogs" = EXCLUDED."redactLogs",
+			"metadataOnlyWebhooks" = EXCLUDED."metadataOnlyWebhooks",
+			"updatedAt" = EXCLUDED."updatedAt"
+	`
+
+	model := &privacyModel{
+		SessionID:            config.SessionID,
+		RedactLogs:           config.RedactLogs,
+		MetadataOnlyWebhooks: config.MetadataOnlyWebhooks,
+		UpdatedAt:            config.UpdatedAt,
+	}
+	if _, err := r.db.NamedExecContext(ctx, query, model); err != nil {
+		r.logger.ErrorWithFields("Failed to save privacy settings", map[string]interface{}{
+			"session_id": config.SessionID,
+			"error":      err.Error(),
+		})
+		return fmt.Errorf("failed to save privacy settings: %w", err)
+	}
+
+	return nil
+}
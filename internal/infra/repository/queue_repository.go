@@ -0,0 +1,285 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"zpwoot/internal/domain/history"
+	"zpwoot/internal/domain/message"
+	"zpwoot/internal/domain/queue"
+	"zpwoot/internal/ports"
+	"zpwoot/platform/logger"
+)
+
+type queueRepository struct {
+	db     *sqlx.DB
+	logger *logger.Logger
+}
+
+func NewQueueRepository(db *sqlx.DB, logger *logger.Logger) ports.QueueRepository {
+	return &queueRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+type queuedMessageModel struct {
+	ID        string         `db:"id"`
+	SessionID string         `db:"sessionId"`
+	Workspace string         `db:"workspace"`
+	RequestID sql.NullString `db:"requestId"`
+	Actor     sql.NullString `db:"actor"`   // JSONB field
+	Request   string         `db:"request"` // JSONB field
+	Status    string         `db:"status"`
+	Attempts  int            `db:"attempts"`
+	MessageID sql.NullString `db:"messageId"`
+	LastError sql.NullString `db:"lastError"`
+	ExpiresAt time.Time      `db:"expiresAt"`
+	SentAt    sql.NullTime   `db:"sentAt"`
+	ClaimedAt sql.NullTime   `db:"claimedAt"`
+	CreatedAt time.Time      `db:"createdAt"`
+	UpdatedAt time.Time      `db:"updatedAt"`
+}
+
+func (r *queueRepository) Create(ctx context.Context, msg *queue.QueuedMessage) error {
+	model, err := r.toModel(msg)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO "zpMessageQueue" (id, "sessionId", workspace, "requestId", actor, request, status, attempts, "expiresAt", "createdAt", "updatedAt")
+		VALUES (:id, :sessionId, :workspace, :requestId, :actor, :request, :status, :attempts, :expiresAt, :createdAt, :updatedAt)
+	`
+
+	if _, err := r.db.NamedExecContext(ctx, query, model); err != nil {
+		r.logger.ErrorWithFields("Failed to queue message", map[string]interface{}{
+			"session_id": msg.SessionID,
+			"error":      err.Error(),
+		})
+		return fmt.Errorf("failed to queue message: %w", err)
+	}
+
+	return nil
+}
+
+func (r *queueRepository) CountPending(ctx context.Context, sessionID string) (int, error) {
+	query := `SELECT COUNT(*) FROM "zpMessageQueue" WHERE "sessionId" = $1 AND status IN ('queued', 'sending')`
+
+	var count int
+	if err := r.db.GetContext(ctx, &count, query, sessionID); err != nil {
+		return 0, fmt.Errorf("failed to count queued messages: %w", err)
+	}
+
+	return count, nil
+}
+
+// ClaimNext atomically moves the session's oldest queued message to sending so concurrent flushes
+// never send one twice
+func (r *queueRepository) ClaimNext(ctx context.Context, sessionID string, now time.Time) (*queue.QueuedMessage, error) {
+	query := `
+		UPDATE "zpMessageQueue" SET status = 'sending', attempts = attempts + 1, "claimedAt" = $2
+		WHERE id = (
+			SELECT id FROM "zpMessageQueue"
+			WHERE "sessionId" = $1 AND status = 'queued' AND "expiresAt" > $2
+			ORDER BY "createdAt" ASC
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *
+	`
+
+	var model queuedMessageModel
+	if err := r.db.GetContext(ctx, &model, query, sessionID, now); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to claim queued message: %w", err)
+	}
+
+	return r.fromModel(&model)
+}
+
+func (r *queueRepository) Update(ctx context.Context, msg *queue.QueuedMessage) error {
+	model, err := r.toModel(msg)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE "zpMessageQueue"
+		SET status = :status, "messageId" = :messageId, "lastError" = :lastError, "sentAt" = :sentAt
+		WHERE id = :id
+	`
+
+	result, err := r.db.NamedExecContext(ctx, query, model)
+	if err != nil {
+		r.logger.ErrorWithFields("Failed to update queued message", map[string]interface{}{
+			"queue_id": msg.ID.String(),
+			"error":    err.Error(),
+		})
+		return fmt.Errorf("failed to update queued message: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return queue.ErrQueuedMessageNotFound
+	}
+
+	return nil
+}
+
+func (r *queueRepository) ExpireBefore(ctx context.Context, now time.Time) (int, error) {
+	query := `UPDATE "zpMessageQueue" SET status = 'expired' WHERE status = 'queued' AND "expiresAt" <= $1`
+
+	result, err := r.db.ExecContext(ctx, query, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire queued messages: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+// ReleaseStale puts the session's messages claimed before claimedBefore back in the queue; a flush
+// that stopped before recording their outcome left them in sending
+func (r *queueRepository) ReleaseStale(ctx context.Context, sessionID string, claimedBefore time.Time) (int, error) {
+	query := `UPDATE "zpMessageQueue" SET status = 'queued' WHERE "sessionId" = $1 AND status = 'sending' AND "claimedAt" < $2`
+
+	result, err := r.db.ExecContext(ctx, query, sessionID, claimedBefore)
+	if err != nil {
+		return 0, fmt.Errorf("failed to release stale queued messages: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+func (r *queueRepository) GetByID(ctx context.Context, sessionID string, id uuid.UUID) (*queue.QueuedMessage, error) {
+	var model queuedMessageModel
+	query := `SELECT * FROM "zpMessageQueue" WHERE id = $1 AND "sessionId" = $2`
+
+	if err := r.db.GetContext(ctx, &model, query, id.String(), sessionID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, queue.ErrQueuedMessageNotFound
+		}
+		return nil, fmt.Errorf("failed to get queued message: %w", err)
+	}
+
+	return r.fromModel(&model)
+}
+
+// CancelQueued only cancels messages still queued, so one a flush claimed is never reported cancelled
+func (r *queueRepository) CancelQueued(ctx context.Context, sessionID string, id uuid.UUID) (bool, error) {
+	query := `UPDATE "zpMessageQueue" SET status = 'cancelled' WHERE id = $1 AND "sessionId" = $2 AND status = 'queued'`
+
+	result, err := r.db.ExecContext(ctx, query, id.String(), sessionID)
+	if err != nil {
+		return false, fmt.Errorf("failed to cancel queued message: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (r *queueRepository) toModel(msg *queue.QueuedMessage) (*queuedMessageModel, error) {
+	request, err := json.Marshal(msg.Request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal queued message request: %w", err)
+	}
+
+	model := &queuedMessageModel{
+		ID:        msg.ID.String(),
+		SessionID: msg.SessionID,
+		Workspace: msg.Workspace,
+		Request:   string(request),
+		Status:    msg.Status,
+		Attempts:  msg.Attempts,
+		ExpiresAt: msg.ExpiresAt,
+		CreatedAt: msg.CreatedAt,
+		UpdatedAt: msg.UpdatedAt,
+	}
+
+	if msg.RequestID != "" {
+		model.RequestID = sql.NullString{String: msg.RequestID, Valid: true}
+	}
+	if msg.Actor != nil {
+		actor, err := json.Marshal(msg.Actor)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal queued message actor: %w", err)
+		}
+		model.Actor = sql.NullString{String: string(actor), Valid: true}
+	}
+	if msg.MessageID != "" {
+		model.MessageID = sql.NullString{String: msg.MessageID, Valid: true}
+	}
+	if msg.LastError != "" {
+		model.LastError = sql.NullString{String: msg.LastError, Valid: true}
+	}
+	if msg.SentAt != nil {
+		model.SentAt = sql.NullTime{Time: *msg.SentAt, Valid: true}
+	}
+
+	return model, nil
+}
+
+func (r *queueRepository) fromModel(model *queuedMessageModel) (*queue.QueuedMessage, error) {
+	id, err := uuid.Parse(model.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid queued message ID: %w", err)
+	}
+
+	var request message.SendMessageRequest
+	if err := json.Unmarshal([]byte(model.Request), &request); err != nil {
+		return nil, fmt.Errorf("invalid queued message request: %w", err)
+	}
+
+	msg := &queue.QueuedMessage{
+		ID:        id,
+		SessionID: model.SessionID,
+		Workspace: model.Workspace,
+		RequestID: model.RequestID.String,
+		Request:   &request,
+		Status:    model.Status,
+		Attempts:  model.Attempts,
+		MessageID: model.MessageID.String,
+		LastError: model.LastError.String,
+		ExpiresAt: model.ExpiresAt,
+		CreatedAt: model.CreatedAt,
+		UpdatedAt: model.UpdatedAt,
+	}
+
+	if model.Actor.Valid {
+		msg.Actor = &history.Actor{}
+		if err := json.Unmarshal([]byte(model.Actor.String), msg.Actor); err != nil {
+			return nil, fmt.Errorf("invalid queued message actor: %w", err)
+		}
+	}
+	if model.SentAt.Valid {
+		sentAt := model.SentAt.Time
+		msg.SentAt = &sentAt
+	}
+
+	return msg, nil
+}
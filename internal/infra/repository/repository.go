@@ -12,14 +12,58 @@ type Repositories struct {
 	Webhook         ports.WebhookRepository
 	Chatwoot        ports.ChatwootRepository
 	ChatwootMessage ports.ChatwootMessageRepository
+	Draft           ports.DraftRepository
+	Usage           ports.UsageRepository
+	Settings        ports.SettingsRepository
+	RoutingRule     ports.RoutingRuleRepository
+	Poll            ports.PollRepository
+	Translation     ports.TranslationRepository
+	Privacy         ports.PrivacyRepository
+	ContactChange   ports.ContactChangeRepository
+	Warmup          ports.WarmupRepository
+	Queue           ports.QueueRepository
+	Outbox          ports.OutboxRepository
+	Scheduled       ports.ScheduledMessageRepository
+	SendGuard       ports.SendGuardRepository
+	WebhookEvent    ports.WebhookEventRepository
+	History         ports.HistoryRepository
+	MessageStats    ports.MessageStatsRepository
+	Conversation    ports.ConversationStateRepository
+	APIKey          ports.APIKeyRepository
+	Maintenance     ports.MaintenanceRepository
 }
 
-func NewRepositories(db *sqlx.DB, logger *logger.Logger) *Repositories {
+// Options configures the repositories
+type Options struct {
+	// CaseSensitiveSessionNames makes session name lookups match exactly
+	CaseSensitiveSessionNames bool
+}
+
+func NewRepositories(db *sqlx.DB, logger *logger.Logger, options Options) *Repositories {
 	return &Repositories{
-		Session:         NewSessionRepository(db, logger),
+		Session:         NewSessionRepository(db, logger, options.CaseSensitiveSessionNames),
 		Webhook:         NewWebhookRepository(db, logger),
 		Chatwoot:        NewChatwootRepository(db, logger),
 		ChatwootMessage: NewMessageRepository(db, logger),
+		Draft:           NewDraftRepository(db, logger),
+		Usage:           NewUsageRepository(db, logger),
+		Settings:        NewSettingsRepository(db, logger),
+		RoutingRule:     NewRoutingRuleRepository(db, logger),
+		Poll:            NewPollRepository(db, logger),
+		Translation:     NewTranslationRepository(db, logger),
+		Privacy:         NewPrivacyRepository(db, logger),
+		ContactChange:   NewContactChangeRepository(db, logger),
+		Warmup:          NewWarmupRepository(db, logger),
+		Queue:           NewQueueRepository(db, logger),
+		Outbox:          NewOutboxRepository(db, logger),
+		Scheduled:       NewScheduledMessageRepository(db, logger),
+		SendGuard:       NewSendGuardRepository(db, logger),
+		WebhookEvent:    NewWebhookEventRepository(db, logger),
+		History:         NewHistoryRepository(db, logger),
+		MessageStats:    NewMessageStatsRepository(db, logger),
+		Conversation:    NewConversationStateRepository(db, logger),
+		APIKey:          NewAPIKeyRepository(db, logger),
+		Maintenance:     NewMaintenanceRepository(db, logger),
 	}
 }
 
@@ -38,3 +82,79 @@ func (r *Repositories) GetChatwootRepository() ports.ChatwootRepository {
 func (r *Repositories) GetChatwootMessageRepository() ports.ChatwootMessageRepository {
 	return r.ChatwootMessage
 }
+
+func (r *Repositories) GetDraftRepository() ports.DraftRepository {
+	return r.Draft
+}
+
+func (r *Repositories) GetUsageRepository() ports.UsageRepository {
+	return r.Usage
+}
+
+func (r *Repositories) GetSettingsRepository() ports.SettingsRepository {
+	return r.Settings
+}
+
+func (r *Repositories) GetRoutingRuleRepository() ports.RoutingRuleRepository {
+	return r.RoutingRule
+}
+
+func (r *Repositories) GetPollRepository() ports.PollRepository {
+	return r.Poll
+}
+
+func (r *Repositories) GetTranslationRepository() ports.TranslationRepository {
+	return r.Translation
+}
+
+func (r *Repositories) GetPrivacyRepository() ports.PrivacyRepository {
+	return r.Privacy
+}
+
+func (r *Repositories) GetContactChangeRepository() ports.ContactChangeRepository {
+	return r.ContactChange
+}
+
+func (r *Repositories) GetWarmupRepository() ports.WarmupRepository {
+	return r.Warmup
+}
+
+func (r *Repositories) GetQueueRepository() ports.QueueRepository {
+	return r.Queue
+}
+
+func (r *Repositories) GetOutboxRepository() ports.OutboxRepository {
+	return r.Outbox
+}
+
+func (r *Repositories) GetScheduledMessageRepository() ports.ScheduledMessageRepository {
+	return r.Scheduled
+}
+
+func (r *Repositories) GetSendGuardRepository() ports.SendGuardRepository {
+	return r.SendGuard
+}
+
+func (r *Repositories) GetWebhookEventRepository() ports.WebhookEventRepository {
+	return r.WebhookEvent
+}
+
+func (r *Repositories) GetHistoryRepository() ports.HistoryRepository {
+	return r.History
+}
+
+func (r *Repositories) GetMessageStatsRepository() ports.MessageStatsRepository {
+	return r.MessageStats
+}
+
+func (r *Repositories) GetConversationStateRepository() ports.ConversationStateRepository {
+	return r.Conversation
+}
+
+func (r *Repositories) GetAPIKeyRepository() ports.APIKeyRepository {
+	return r.APIKey
+}
+
+func (r *Repositories) GetMaintenanceRepository() ports.MaintenanceRepository {
+	return r.Maintenance
+}
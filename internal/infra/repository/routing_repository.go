@@ -0,0 +1,271 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"zpwoot/internal/domain/routing"
+	"zpwoot/internal/ports"
+	"zpwoot/platform/logger"
+)
+
+type routingRuleRepository struct {
+	db     *sqlx.DB
+	logger *logger.Logger
+}
+
+func NewRoutingRuleRepository(db *sqlx.DB, logger *logger.Logger) ports.RoutingRuleRepository {
+	return &routingRuleRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+type routingRuleModel struct {
+	ID             string    `db:"id"`
+	SessionID      string    `db:"sessionId"`
+	Name           string    `db:"name"`
+	Priority       int       `db:"priority"`
+	Enabled        bool      `db:"enabled"`
+	Keywords       string    `db:"keywords"`   // JSONB field
+	Patterns       string    `db:"patterns"`   // JSONB field
+	Senders        string    `db:"senders"`    // JSONB field
+	States         string    `db:"states"`     // JSONB field
+	ReplyIDs       string    `db:"replyIds"`   // JSONB field
+	Tags           string    `db:"tags"`       // JSONB field
+	WebhookIDs     string    `db:"webhookIds"` // JSONB field
+	Labels         string    `db:"labels"`     // JSONB field
+	Exclusive      bool      `db:"exclusive"`
+	StopProcessing bool      `db:"stopProcessing"`
+	NextState      string    `db:"nextState"`
+	CreatedAt      time.Time `db:"createdAt"`
+	UpdatedAt      time.Time `db:"updatedAt"`
+}
+
+func (r *routingRuleRepository) Create(ctx context.Context, rule *routing.Rule) error {
+	model, err := r.toModel(rule)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO "zpRoutingRules" (id, "sessionId", name, priority, enabled, keywords, patterns, senders, states, "replyIds", tags, "webhookIds", labels, exclusive, "stopProcessing", "nextState", "createdAt", "updatedAt")
+		VALUES (:id, :sessionId, :name, :priority, :enabled, :keywords, :patterns, :senders, :states, :replyIds, :tags, :webhookIds, :labels, :exclusive, :stopProcessing, :nextState, :createdAt, :updatedAt)
+	`
+
+	if _, err := r.db.NamedExecContext(ctx, query, model); err != nil {
+		r.logger.ErrorWithFields("Failed to create routing rule", map[string]interface{}{
+			"session_id": rule.SessionID,
+			"error":      err.Error(),
+		})
+		return fmt.Errorf("failed to create routing rule: %w", err)
+	}
+
+	return nil
+}
+
+func (r *routingRuleRepository) GetByID(ctx context.Context, sessionID, id string) (*routing.Rule, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, routing.ErrRuleNotFound
+	}
+
+	var model routingRuleModel
+	query := `SELECT * FROM "zpRoutingRules" WHERE "sessionId" = $1 AND id = $2`
+
+	if err := r.db.GetContext(ctx, &model, query, sessionID, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, routing.ErrRuleNotFound
+		}
+		r.logger.ErrorWithFields("Failed to get routing rule", map[string]interface{}{
+			"session_id": sessionID,
+			"rule_id":    id,
+			"error":      err.Error(),
+		})
+		return nil, fmt.Errorf("failed to get routing rule: %w", err)
+	}
+
+	return r.fromModel(&model)
+}
+
+func (r *routingRuleRepository) ListBySession(ctx context.Context, sessionID string) ([]*routing.Rule, error) {
+	query := `SELECT * FROM "zpRoutingRules" WHERE "sessionId" = $1 ORDER BY priority DESC, "createdAt" ASC`
+
+	var models []routingRuleModel
+	if err := r.db.SelectContext(ctx, &models, query, sessionID); err != nil {
+		r.logger.ErrorWithFields("Failed to list routing rules", map[string]interface{}{
+			"session_id": sessionID,
+			"error":      err.Error(),
+		})
+		return nil, fmt.Errorf("failed to list routing rules: %w", err)
+	}
+
+	rules := make([]*routing.Rule, 0, len(models))
+	for _, model := range models {
+		rule, err := r.fromModel(&model)
+		if err != nil {
+			r.logger.WarnWithFields("Failed to convert routing rule model", map[string]interface{}{
+				"rule_id": model.ID,
+				"error":   err.Error(),
+			})
+			continue
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+func (r *routingRuleRepository) Update(ctx context.Context, rule *routing.Rule) error {
+	model, err := r.toModel(rule)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE "zpRoutingRules"
+		SET name = :name, priority = :priority, enabled = :enabled, keywords = :keywords, patterns = :patterns, senders = :senders,
+		    states = :states, "replyIds" = :replyIds, tags = :tags, "webhookIds" = :webhookIds, labels = :labels,
+		    exclusive = :exclusive, "stopProcessing" = :stopProcessing, "nextState" = :nextState
+		WHERE id = :id AND "sessionId" = :sessionId
+	`
+
+	result, err := r.db.NamedExecContext(ctx, query, model)
+	if err != nil {
+		r.logger.ErrorWithFields("Failed to update routing rule", map[string]interface{}{
+			"rule_id": rule.ID.String(),
+			"error":   err.Error(),
+		})
+		return fmt.Errorf("failed to update routing rule: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return routing.ErrRuleNotFound
+	}
+
+	return nil
+}
+
+func (r *routingRuleRepository) Delete(ctx context.Context, sessionID, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return routing.ErrRuleNotFound
+	}
+
+	query := `DELETE FROM "zpRoutingRules" WHERE "sessionId" = $1 AND id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, sessionID, id)
+	if err != nil {
+		r.logger.ErrorWithFields("Failed to delete routing rule", map[string]interface{}{
+			"session_id": sessionID,
+			"rule_id":    id,
+			"error":      err.Error(),
+		})
+		return fmt.Errorf("failed to delete routing rule: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return routing.ErrRuleNotFound
+	}
+
+	return nil
+}
+
+func (r *routingRuleRepository) toModel(rule *routing.Rule) (*routingRuleModel, error) {
+	model := &routingRuleModel{
+		ID:             rule.ID.String(),
+		SessionID:      rule.SessionID,
+		Name:           rule.Name,
+		Priority:       rule.Priority,
+		Enabled:        rule.Enabled,
+		Exclusive:      rule.Exclusive,
+		StopProcessing: rule.StopProcessing,
+		NextState:      rule.NextState,
+		CreatedAt:      rule.CreatedAt,
+		UpdatedAt:      rule.UpdatedAt,
+	}
+
+	lists := []struct {
+		target *string
+		values []string
+	}{
+		{&model.Keywords, rule.Keywords},
+		{&model.Patterns, rule.Patterns},
+		{&model.Senders, rule.Senders},
+		{&model.States, rule.States},
+		{&model.ReplyIDs, rule.ReplyIDs},
+		{&model.Tags, rule.Tags},
+		{&model.WebhookIDs, rule.WebhookIDs},
+		{&model.Labels, rule.Labels},
+	}
+	for _, list := range lists {
+		values := list.values
+		if values == nil {
+			values = []string{}
+		}
+		encoded, err := json.Marshal(values)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal routing rule: %w", err)
+		}
+		*list.target = string(encoded)
+	}
+
+	return model, nil
+}
+
+func (r *routingRuleRepository) fromModel(model *routingRuleModel) (*routing.Rule, error) {
+	id, err := uuid.Parse(model.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid routing rule ID: %w", err)
+	}
+
+	rule := &routing.Rule{
+		ID:             id,
+		SessionID:      model.SessionID,
+		Name:           model.Name,
+		Priority:       model.Priority,
+		Enabled:        model.Enabled,
+		Exclusive:      model.Exclusive,
+		StopProcessing: model.StopProcessing,
+		NextState:      model.NextState,
+		CreatedAt:      model.CreatedAt,
+		UpdatedAt:      model.UpdatedAt,
+	}
+
+	lists := []struct {
+		target *[]string
+		value  string
+	}{
+		{&rule.Keywords, model.Keywords},
+		{&rule.Patterns, model.Patterns},
+		{&rule.Senders, model.Senders},
+		{&rule.States, model.States},
+		{&rule.ReplyIDs, model.ReplyIDs},
+		{&rule.Tags, model.Tags},
+		{&rule.WebhookIDs, model.WebhookIDs},
+		{&rule.Labels, model.Labels},
+	}
+	for _, list := range lists {
+		*list.target = []string{}
+		if list.value == "" {
+			continue
+		}
+		if err := json.Unmarshal([]byte(list.value), list.target); err != nil {
+			return nil, fmt.Errorf("invalid routing rule list: %w", err)
+		}
+	}
+
+	return rule, nil
+}
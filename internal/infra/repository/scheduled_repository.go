@@ -0,0 +1,323 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"zpwoot/internal/domain/message"
+	"zpwoot/internal/domain/scheduled"
+	"zpwoot/internal/ports"
+	"zpwoot/platform/logger"
+)
+
+type scheduledMessageRepository struct {
+	db     *sqlx.DB
+	logger *logger.Logger
+}
+
+func NewScheduledMessageRepository(db *sqlx.DB, logger *logger.Logger) ports.ScheduledMessageRepository {
+	return &scheduledMessageRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+type scheduledMessageModel struct {
+	ID          string         `db:"id"`
+	SessionID   string         `db:"sessionId"`
+	ChatJID     string         `db:"chatJid"`
+	Workspace   string         `db:"workspace"`
+	RequestID   sql.NullString `db:"requestId"`
+	Request     string         `db:"request"` // JSONB field
+	Status      string         `db:"status"`
+	Attempts    int            `db:"attempts"`
+	MessageID   sql.NullString `db:"messageId"`
+	LastError   sql.NullString `db:"lastError"`
+	ScheduledAt time.Time      `db:"scheduledAt"`
+	SentAt      sql.NullTime   `db:"sentAt"`
+	ClaimedAt   sql.NullTime   `db:"claimedAt"`
+	CreatedAt   time.Time      `db:"createdAt"`
+	UpdatedAt   time.Time      `db:"updatedAt"`
+}
+
+func (r *scheduledMessageRepository) Create(ctx context.Context, msg *scheduled.Message) error {
+	model, err := r.toModel(msg)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO "zpScheduledMessages" (id, "sessionId", "chatJid", workspace, "requestId", request, status, attempts, "scheduledAt", "createdAt", "updatedAt")
+		VALUES (:id, :sessionId, :chatJid, :workspace, :requestId, :request, :status, :attempts, :scheduledAt, :createdAt, :updatedAt)
+	`
+
+	if _, err := r.db.NamedExecContext(ctx, query, model); err != nil {
+		r.logger.ErrorWithFields("Failed to schedule message", map[string]interface{}{
+			"session_id": msg.SessionID,
+			"error":      err.Error(),
+		})
+		return fmt.Errorf("failed to schedule message: %w", err)
+	}
+
+	return nil
+}
+
+func (r *scheduledMessageRepository) ClaimDue(ctx context.Context, now time.Time, limit int) ([]*scheduled.Message, error) {
+	query := `
+		UPDATE "zpScheduledMessages" SET status = 'sending', attempts = attempts + 1, "claimedAt" = $1
+		WHERE id IN (
+			SELECT id FROM "zpScheduledMessages"
+			WHERE status = 'scheduled' AND "scheduledAt" <= $1
+			ORDER BY "scheduledAt" ASC
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *
+	`
+
+	var models []scheduledMessageModel
+	if err := r.db.SelectContext(ctx, &models, query, now, limit); err != nil {
+		return nil, fmt.Errorf("failed to claim due scheduled messages: %w", err)
+	}
+
+	return r.fromModels(models)
+}
+
+// ReleaseStale returns messages claimed before claimedBefore to scheduled so the next tick sends
+// them; a scheduler that stops mid-send would otherwise leave them in sending for good
+func (r *scheduledMessageRepository) ReleaseStale(ctx context.Context, claimedBefore time.Time) (int, error) {
+	query := `UPDATE "zpScheduledMessages" SET status = 'scheduled' WHERE status = 'sending' AND "claimedAt" < $1`
+
+	result, err := r.db.ExecContext(ctx, query, claimedBefore)
+	if err != nil {
+		return 0, fmt.Errorf("failed to release stale scheduled messages: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+func (r *scheduledMessageRepository) Update(ctx context.Context, msg *scheduled.Message) error {
+	model, err := r.toModel(msg)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE "zpScheduledMessages"
+		SET status = :status, "messageId" = :messageId, "lastError" = :lastError, "sentAt" = :sentAt
+		WHERE id = :id
+	`
+
+	result, err := r.db.NamedExecContext(ctx, query, model)
+	if err != nil {
+		r.logger.ErrorWithFields("Failed to update scheduled message", map[string]interface{}{
+			"scheduled_id": msg.ID.String(),
+			"error":        err.Error(),
+		})
+		return fmt.Errorf("failed to update scheduled message: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return scheduled.ErrScheduledMessageNotFound
+	}
+
+	return nil
+}
+
+// Reschedule only moves messages still scheduled, so one the scheduler claimed keeps its time
+func (r *scheduledMessageRepository) Reschedule(ctx context.Context, sessionID string, id uuid.UUID, scheduledAt time.Time) (bool, error) {
+	query := `UPDATE "zpScheduledMessages" SET "scheduledAt" = $3 WHERE id = $1 AND "sessionId" = $2 AND status = 'scheduled'`
+
+	result, err := r.db.ExecContext(ctx, query, id.String(), sessionID, scheduledAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to reschedule message: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// Cancel only cancels messages still scheduled, so one the scheduler claimed is never reported cancelled
+func (r *scheduledMessageRepository) Cancel(ctx context.Context, sessionID string, id uuid.UUID) (bool, error) {
+	query := `UPDATE "zpScheduledMessages" SET status = 'cancelled' WHERE id = $1 AND "sessionId" = $2 AND status = 'scheduled'`
+
+	result, err := r.db.ExecContext(ctx, query, id.String(), sessionID)
+	if err != nil {
+		return false, fmt.Errorf("failed to cancel scheduled message: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (r *scheduledMessageRepository) GetByID(ctx context.Context, sessionID string, id uuid.UUID) (*scheduled.Message, error) {
+	var model scheduledMessageModel
+	query := `SELECT * FROM "zpScheduledMessages" WHERE id = $1 AND "sessionId" = $2`
+
+	if err := r.db.GetContext(ctx, &model, query, id.String(), sessionID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, scheduled.ErrScheduledMessageNotFound
+		}
+		return nil, fmt.Errorf("failed to get scheduled message: %w", err)
+	}
+
+	return r.fromModel(&model)
+}
+
+func (r *scheduledMessageRepository) List(ctx context.Context, req *scheduled.ListRequest) ([]*scheduled.Message, int, error) {
+	whereClause := `WHERE "sessionId" = $1`
+	args := []interface{}{req.SessionID}
+	argIndex := 2
+
+	if req.Status != "" {
+		whereClause += fmt.Sprintf(" AND status = $%d", argIndex)
+		args = append(args, req.Status)
+		argIndex++
+	}
+
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM "zpScheduledMessages" %s`, whereClause)
+	var total int
+	if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
+		r.logger.ErrorWithFields("Failed to count scheduled messages", map[string]interface{}{
+			"session_id": req.SessionID,
+			"error":      err.Error(),
+		})
+		return nil, 0, fmt.Errorf("failed to count scheduled messages: %w", err)
+	}
+
+	// The cursor narrows the page, not the total
+	if req.After != nil {
+		whereClause += fmt.Sprintf(` AND ("createdAt", id) < ($%d, $%d)`, argIndex, argIndex+1)
+		args = append(args, req.After.CreatedAt, req.After.ID)
+		argIndex += 2
+	}
+
+	query := fmt.Sprintf(`
+		SELECT * FROM "zpScheduledMessages" %s
+		ORDER BY "createdAt" DESC, id DESC
+		LIMIT $%d OFFSET $%d
+	`, whereClause, argIndex, argIndex+1)
+
+	args = append(args, req.Limit, req.Offset)
+
+	var models []scheduledMessageModel
+	if err := r.db.SelectContext(ctx, &models, query, args...); err != nil {
+		r.logger.ErrorWithFields("Failed to list scheduled messages", map[string]interface{}{
+			"session_id": req.SessionID,
+			"error":      err.Error(),
+		})
+		return nil, 0, fmt.Errorf("failed to list scheduled messages: %w", err)
+	}
+
+	msgs, err := r.fromModels(models)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return msgs, total, nil
+}
+
+func (r *scheduledMessageRepository) toModel(msg *scheduled.Message) (*scheduledMessageModel, error) {
+	request, err := json.Marshal(msg.Request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal scheduled message request: %w", err)
+	}
+
+	model := &scheduledMessageModel{
+		ID:          msg.ID.String(),
+		SessionID:   msg.SessionID,
+		ChatJID:     msg.ChatJID,
+		Workspace:   msg.Workspace,
+		Request:     string(request),
+		Status:      msg.Status,
+		Attempts:    msg.Attempts,
+		ScheduledAt: msg.ScheduledAt,
+		CreatedAt:   msg.CreatedAt,
+		UpdatedAt:   msg.UpdatedAt,
+	}
+
+	if msg.RequestID != "" {
+		model.RequestID = sql.NullString{String: msg.RequestID, Valid: true}
+	}
+	if msg.MessageID != "" {
+		model.MessageID = sql.NullString{String: msg.MessageID, Valid: true}
+	}
+	if msg.LastError != "" {
+		model.LastError = sql.NullString{String: msg.LastError, Valid: true}
+	}
+	if msg.SentAt != nil {
+		model.SentAt = sql.NullTime{Time: *msg.SentAt, Valid: true}
+	}
+
+	return model, nil
+}
+
+func (r *scheduledMessageRepository) fromModels(models []scheduledMessageModel) ([]*scheduled.Message, error) {
+	msgs := make([]*scheduled.Message, 0, len(models))
+	for i := range models {
+		msg, err := r.fromModel(&models[i])
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+func (r *scheduledMessageRepository) fromModel(model *scheduledMessageModel) (*scheduled.Message, error) {
+	id, err := uuid.Parse(model.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scheduled message ID: %w", err)
+	}
+
+	var request message.SendMessageRequest
+	if err := json.Unmarshal([]byte(model.Request), &request); err != nil {
+		return nil, fmt.Errorf("invalid scheduled message request: %w", err)
+	}
+
+	msg := &scheduled.Message{
+		ID:          id,
+		SessionID:   model.SessionID,
+		ChatJID:     model.ChatJID,
+		Workspace:   model.Workspace,
+		RequestID:   model.RequestID.String,
+		Request:     &request,
+		Status:      model.Status,
+		Attempts:    model.Attempts,
+		MessageID:   model.MessageID.String,
+		LastError:   model.LastError.String,
+		ScheduledAt: model.ScheduledAt,
+		CreatedAt:   model.CreatedAt,
+		UpdatedAt:   model.UpdatedAt,
+	}
+
+	if model.SentAt.Valid {
+		sentAt := model.SentAt.Time
+		msg.SentAt = &sentAt
+	}
+
+	return msg, nil
+}
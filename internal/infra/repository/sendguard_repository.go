@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"zpwoot/internal/domain/sendguard"
+	"zpwoot/internal/ports"
+	"zpwoot/platform/logger"
+)
+
+type sendGuardRepository struct {
+	db     *sqlx.DB
+	logger *logger.Logger
+}
+
+func NewSendGuardRepository(db *sqlx.DB, logger *logger.Logger) ports.SendGuardRepository {
+	return &sendGuardRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+type sendPolicyModel struct {
+	SessionID         string         `db:"sessionId"`
+	AllowedGroups     pq.StringArray `db:"allowedGroups"`
+	Sandbox           bool           `db:"sandbox"`
+	SandboxRecipients pq.StringArray `db:"sandboxRecipients"`
+	SandboxRedirectTo sql.NullString `db:"sandboxRedirectTo"`
+	UpdatedAt         time.Time      `db:"updatedAt"`
+}
+
+type sendViolationModel struct {
+	ID           string         `db:"id"`
+	SessionID    string         `db:"sessionId"`
+	Recipient    string         `db:"recipient"`
+	Reason       string         `db:"reason"`
+	RedirectedTo sql.NullString `db:"redirectedTo"`
+	CreatedAt    time.Time      `db:"createdAt"`
+}
+
+func (r *sendGuardRepository) GetBySession(ctx context.Context, sessionID string) (*sendguard.Policy, error) {
+	var model sendPolicyModel
+	query := `SELECT * FROM "zpSendPolicies" WHERE "sessionId" = $1`
+
+	if err := r.db.GetContext(ctx, &model, query, sessionID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.ErrorWithFields("Failed to get send policy", map[string]interface{}{
+			"session_id": sessionID,
+			"error":      err.Error(),
+		})
+		return nil, fmt.Errorf("failed to get send policy: %w", err)
+	}
+
+	return &sendguard.Policy{
+		SessionID:         model.SessionID,
+		AllowedGroups:     []string(model.AllowedGroups),
+		Sandbox:           model.Sandbox,
+		SandboxRecipients: []string(model.SandboxRecipients),
+		SandboxRedirectTo: model.SandboxRedirectTo.String,
+		UpdatedAt:         model.UpdatedAt,
+	}, nil
+}
+
+func (r *sendGuardRepository) Upsert(ctx context.Context, policy *sendguard.Policy) error {
+	query := `
+		INSERT INTO "zpSendPolicies" ("sessionId", "allowedGroups", sandbox, "sandboxRecipients", "sandboxRedirectTo", "updatedAt")
+		VALUES (:sessionId, :allowedGroups, :sandbox, :sandboxRecipients, :sandboxRedirectTo, :updatedAt)
+		ON CONFLICT ("sessionId") DO UPDATE SET
+			"allowedGroups" = EXCLUDED."allowedGroups",
+			sandbox = EXCLUDED.sandbox,
+			"sandboxRecipients" = EXCLUDED."sandboxRecipients",
+			"sandboxRedirectTo" = EXCLUDED."sandboxRedirectTo",
+			"updatedAt" = EXCLUDED."updatedAt"
+	`
+
+	model := &sendPolicyModel{
+		SessionID:         policy.SessionID,
+		AllowedGroups:     pq.StringArray(policy.AllowedGroups),
+		Sandbox:           policy.Sandbox,
+		SandboxRecipients: pq.StringArray(policy.SandboxRecipients),
+		UpdatedAt:         policy.UpdatedAt,
+	}
+	if policy.SandboxRedirectTo != "" {
+		model.SandboxRedirectTo = sql.NullString{String: policy.SandboxRedirectTo, Valid: true}
+	}
+
+	if _, err := r.db.NamedExecContext(ctx, query, model); err != nil {
+		r.logger.ErrorWithFields("Failed to save send policy", map[string]interface{}{
+			"session_id": policy.SessionID,
+			"error":      err.Error(),
+		})
+		return fmt.Errorf("failed to save send policy: %w", err)
+	}
+
+	return nil
+}
+
+func (r *sendGuardRepository) CreateViolation(ctx context.Context, violation *sendguard.Violation) error {
+	query := `
+		INSERT INTO "zpSendViolations" (id, "sessionId", recipient, reason, "redirectedTo", "createdAt")
+		VALUES (:id, :sessionId, :recipient, :reason, :redirectedTo, :createdAt)
+	`
+
+	model := &sendViolationModel{
+		ID:        violation.ID.String(),
+		SessionID: violation.SessionID,
+		Recipient: violation.Recipient,
+		Reason:    violation.Reason,
+		CreatedAt: violation.CreatedAt,
+	}
+	if violation.RedirectedTo != "" {
+		model.RedirectedTo = sql.NullString{String: violation.RedirectedTo, Valid: true}
+	}
+
+	if _, err := r.db.NamedExecContext(ctx, query, model); err != nil {
+		return fmt.Errorf("failed to record send violation: %w", err)
+	}
+
+	return nil
+}
+
+func (r *sendGuardRepository) ListViolations(ctx context.Context, sessionID string, limit int) ([]*sendguard.Violation, error) {
+	var models []sendViolationModel
+	query := `SELECT * FROM "zpSendViolations" WHERE "sessionId" = $1 ORDER BY "createdAt" DESC LIMIT $2`
+
+	if err := r.db.SelectContext(ctx, &models, query, sessionID, limit); err != nil {
+		return nil, fmt.Errorf("failed to list send violations: %w", err)
+	}
+
+	violations := make([]*sendguard.Violation, 0, len(models))
+	for _, model := range models {
+		id, err := uuid.Parse(model.ID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid send violation ID: %w", err)
+		}
+		violations = append(violations, &sendguard.Violation{
+			ID:           id,
+			SessionID:    model.SessionID,
+			Recipient:    model.Recipient,
+			Reason:       model.Reason,
+			RedirectedTo: model.RedirectedTo.String,
+			CreatedAt:    model.CreatedAt,
+		})
+	}
+
+	return violations, nil
+}
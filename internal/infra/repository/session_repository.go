@@ -15,17 +15,23 @@ import (
 	"zpwoot/internal/ports"
 	"zpwoot/pkg/errors"
 	"zpwoot/platform/logger"
+	"zpwoot/platform/precondition"
 )
 
 type sessionRepository struct {
 	db     *sqlx.DB
 	logger *logger.Logger
+	// caseSensitiveNames keeps the legacy exact matching of session names in lookups
+	caseSensitiveNames bool
 }
 
-func NewSessionRepository(db *sqlx.DB, logger *logger.Logger) ports.SessionRepository {
+// NewSessionRepository creates the session repository. Session names are unique regardless of case;
+// caseSensitiveNames only makes name lookups match exactly, so "mysession" no longer finds "MySession".
+func NewSessionRepository(db *sqlx.DB, logger *logger.Logger, caseSensitiveNames bool) ports.SessionRepository {
 	return &sessionRepository{
-		db:     db,
-		logger: logger,
+		db:                 db,
+		logger:             logger,
+		caseSensitiveNames: caseSensitiveNames,
 	}
 }
 
@@ -42,6 +48,7 @@ type sessionModel struct {
 	UpdatedAt       time.Time      `db:"updatedAt"`
 	ConnectedAt     sql.NullTime   `db:"connectedAt"`
 	LastSeen        sql.NullTime   `db:"lastSeen"`
+	Warning         sql.NullString `db:"warning"` // JSON
 }
 
 func (r *sessionRepository) Create(ctx context.Context, sess *session.Session) error {
@@ -50,6 +57,12 @@ func (r *sessionRepository) Create(ctx context.Context, sess *session.Session) e
 		"name":       sess.Name,
 	})
 
+	if existing, err := r.findNameCollision(ctx, sess.Name, sess.ID.String()); err != nil {
+		return err
+	} else if existing != "" {
+		return errors.NewWithDetails(409, "Session already exists", fmt.Sprintf("A session with the name '%s' already exists", existing))
+	}
+
 	model := r.toModel(sess)
 
 	query := `
@@ -64,8 +77,7 @@ func (r *sessionRepository) Create(ctx context.Context, sess *session.Session) e
 			"error":      err.Error(),
 		})
 
-		if strings.Contains(err.Error(), "duplicate key value violates unique constraint") &&
-			strings.Contains(err.Error(), "zpSessions_name_key") {
+		if isSessionNameConflict(err) {
 			return errors.NewWithDetails(409, "Session already exists", fmt.Sprintf("A session with the name '%s' already exists", sess.Name))
 		}
 
@@ -111,6 +123,9 @@ func (r *sessionRepository) GetByName(ctx context.Context, name string) (*sessio
 
 	var model sessionModel
 	query := `SELECT * FROM "zpSessions" WHERE name = $1`
+	if !r.caseSensitiveNames {
+		query = `SELECT * FROM "zpSessions" WHERE LOWER(name) = LOWER($1)`
+	}
 
 	err := r.db.GetContext(ctx, &model, query, name)
 	if err != nil {
@@ -188,9 +203,16 @@ func (r *sessionRepository) List(ctx context.Context, req *session.ListSessionsR
 		return nil, 0, fmt.Errorf("failed to count sessions: %w", err)
 	}
 
+	// The cursor narrows the page, not the total
+	if req.After != nil {
+		whereClause += fmt.Sprintf(` AND ("createdAt", id) < ($%d, $%d)`, argIndex, argIndex+1)
+		args = append(args, req.After.CreatedAt, req.After.ID)
+		argIndex += 2
+	}
+
 	query := fmt.Sprintf(`
 		SELECT * FROM "zpSessions" %s
-		ORDER BY "createdAt" DESC
+		ORDER BY "createdAt" DESC, id DESC
 		LIMIT $%d OFFSET $%d
 	`, whereClause, argIndex, argIndex+1)
 
@@ -222,6 +244,11 @@ func (r *sessionRepository) List(ctx context.Context, req *session.ListSessionsR
 }
 
 func (r *sessionRepository) Update(ctx context.Context, sess *session.Session) error {
+	if existing, err := r.findNameCollision(ctx, sess.Name, sess.ID.String()); err != nil {
+		return err
+	} else if existing != "" {
+		return errors.NewWithDetails(409, "Session already exists", fmt.Sprintf("A session with the name '%s' already exists", existing))
+	}
 
 	model := r.toModel(sess)
 	model.UpdatedAt = time.Now()
@@ -235,12 +262,16 @@ func (r *sessionRepository) Update(ctx context.Context, sess *session.Session) e
 		WHERE id = :id
 	`
 
-	result, err := r.db.NamedExecContext(ctx, query, model)
+	result, conditional, err := namedUpdate(ctx, r.db, query, model)
 	if err != nil {
 		r.logger.ErrorWithFields("Failed to update session", map[string]interface{}{
 			"session_id": sess.ID.String(),
 			"error":      err.Error(),
 		})
+
+		if isSessionNameConflict(err) {
+			return errors.NewWithDetails(409, "Session already exists", fmt.Sprintf("A session with the name '%s' already exists", sess.Name))
+		}
 		return fmt.Errorf("failed to update session: %w", err)
 	}
 
@@ -250,12 +281,38 @@ func (r *sessionRepository) Update(ctx context.Context, sess *session.Session) e
 	}
 
 	if rowsAffected == 0 {
+		if conditional {
+			return precondition.ErrFailed
+		}
 		return session.ErrSessionNotFound
 	}
 
 	return nil
 }
 
+// findNameCollision returns the name of another session than id that name matches regardless of case,
+// or "" when there is none. The unique index refuses these too; checking first names the existing session
+// in the error.
+func (r *sessionRepository) findNameCollision(ctx context.Context, name, id string) (string, error) {
+	var existing string
+	query := `SELECT name FROM "zpSessions" WHERE LOWER(name) = LOWER($1) AND id <> $2 LIMIT 1`
+	if err := r.db.GetContext(ctx, &existing, query, name, id); err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to check session name: %w", err)
+	}
+
+	return existing, nil
+}
+
+// isSessionNameConflict reports whether err is a violation of the exact or case-insensitive uniqueness
+// of session names
+func isSessionNameConflict(err error) bool {
+	return strings.Contains(err.Error(), "duplicate key value violates unique constraint") &&
+		(strings.Contains(err.Error(), "zpSessions_name_key") || strings.Contains(err.Error(), "idx_zp_sessions_name_lower"))
+}
+
 func (r *sessionRepository) Delete(ctx context.Context, id string) error {
 	r.logger.InfoWithFields("Deleting session", map[string]interface{}{
 		"session_id": id,
@@ -334,6 +391,35 @@ func (r *sessionRepository) UpdateLastSeen(ctx context.Context, id string) error
 	return nil
 }
 
+func (r *sessionRepository) SetWarning(ctx context.Context, id string, warning *session.Warning) error {
+	var warningJSON sql.NullString
+	if warning != nil {
+		data, err := json.Marshal(warning)
+		if err != nil {
+			return fmt.Errorf("failed to marshal session warning: %w", err)
+		}
+		warningJSON = sql.NullString{String: string(data), Valid: true}
+	}
+
+	query := `UPDATE "zpSessions" SET "warning" = $1, "updatedAt" = $2 WHERE id = $3`
+
+	result, err := r.db.ExecContext(ctx, query, warningJSON, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update session warning: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return session.ErrSessionNotFound
+	}
+
+	return nil
+}
+
 func (r *sessionRepository) GetActiveSessions(ctx context.Context) ([]*session.Session, error) {
 	r.logger.Info("Getting active sessions")
 
@@ -464,5 +550,12 @@ func (r *sessionRepository) fromModel(model *sessionModel) (*session.Session, er
 		sess.ConnectedAt = &model.ConnectedAt.Time
 	}
 
+	if model.Warning.Valid {
+		var warning session.Warning
+		if err := json.Unmarshal([]byte(model.Warning.String), &warning); err == nil {
+			sess.Warning = &warning
+		}
+	}
+
 	return sess, nil
 }
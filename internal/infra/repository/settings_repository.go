@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"zpwoot/internal/domain/settings"
+	"zpwoot/internal/ports"
+	"zpwoot/platform/logger"
+	"zpwoot/platform/precondition"
+)
+
+type settingsRepository struct {
+	db     *sqlx.DB
+	logger *logger.Logger
+}
+
+func NewSettingsRepository(db *sqlx.DB, logger *logger.Logger) ports.SettingsRepository {
+	return &settingsRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+type settingModel struct {
+	Key       string    `db:"key"`
+	Value     string    `db:"value"`
+	UpdatedAt time.Time `db:"updatedAt"`
+}
+
+func (r *settingsRepository) List(ctx context.Context) ([]*settings.Setting, error) {
+	var models []settingModel
+	if err := r.db.SelectContext(ctx, &models, `SELECT * FROM "zpSettings" ORDER BY key`); err != nil {
+		r.logger.ErrorWithFields("Failed to list settings", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil, fmt.Errorf("failed to list settings: %w", err)
+	}
+
+	result := make([]*settings.Setting, len(models))
+	for i, model := range models {
+		result[i] = &settings.Setting{
+			Key:       model.Key,
+			Value:     model.Value,
+			UpdatedAt: model.UpdatedAt,
+		}
+	}
+
+	return result, nil
+}
+
+// Upsert saves all settings in one transaction so replicas never load half of an update. With a
+// precondition version in ctx it only saves while the settings were last updated at that version, the
+// zero time meaning none were ever saved.
+func (r *settingsRepository) Upsert(ctx context.Context, changes []*settings.Setting) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin settings transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if version, conditional := precondition.Version(ctx); conditional {
+		if _, err := tx.ExecContext(ctx, `LOCK TABLE "zpSettings" IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+			return fmt.Errorf("failed to lock settings: %w", err)
+		}
+
+		var lastUpdated sql.NullTime
+		if err := tx.GetContext(ctx, &lastUpdated, `SELECT MAX("updatedAt") FROM "zpSettings"`); err != nil {
+			return fmt.Errorf("failed to get settings update time: %w", err)
+		}
+		if !lastUpdated.Time.Equal(version) {
+			return precondition.ErrFailed
+		}
+	}
+
+	query := `
+		INSERT INTO "zpSettings" (key, value, "updatedAt")
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (key) DO UPDATE SET
+			value = EXCLUDED.value,
+			"updatedAt" = NOW()
+	`
+
+	for _, setting := range changes {
+		if _, err := tx.ExecContext(ctx, query, setting.Key, setting.Value); err != nil {
+			r.logger.ErrorWithFields("Failed to save setting", map[string]interface{}{
+				"key":   setting.Key,
+				"error": err.Error(),
+			})
+			return fmt.Errorf("failed to save setting %s: %w", setting.Key, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit settings: %w", err)
+	}
+
+	return nil
+}
+
+func (r *settingsRepository) LastUpdated(ctx context.Context) (*time.Time, error) {
+	var lastUpdated sql.NullTime
+	if err := r.db.GetContext(ctx, &lastUpdated, `SELECT MAX("updatedAt") FROM "zpSettings"`); err != nil {
+		return nil, fmt.Errorf("failed to get settings update time: %w", err)
+	}
+
+	if !lastUpdated.Valid {
+		return nil, nil
+	}
+	return &lastUpdated.Time, nil
+}
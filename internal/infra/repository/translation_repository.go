@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"zpwoot/internal/domain/translation"
+	"zpwoot/internal/ports"
+	"zpwoot/platform/logger"
+)
+
+type translationRepository struct {
+	db     *sqlx.DB
+	logger *logger.Logger
+}
+
+func NewTranslationRepository(db *sqlx.DB, logger *logger.Logger) ports.TranslationRepository {
+	return &translationRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+type translationModel struct {
+	SessionID      string    `db:"sessionId"`
+	Enabled        bool      `db:"enabled"`
+	Inbound        bool      `db:"inbound"`
+	Outbound       bool      `db:"outbound"`
+	SourceLanguage string    `db:"sourceLanguage"`
+	TargetLanguage string    `db:"targetLanguage"`
+	UpdatedAt      time.Time `db:"updatedAt"`
+}
+
+func (r *translationRepository) GetBySession(ctx context.Context, sessionID string) (*translation.Config, error) {
+	var model translationModel
+	query := `SELECT * FROM "zpTranslationSettings" WHERE "sessionId" = $1`
+
+	if err := r.db.GetContext(ctx, &model, query, sessionID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.ErrorWithFields("Failed to get translation settings", map[string]interface{}{
+			"session_id": sessionID,
+			"error":      err.Error(),
+		})
+		return nil, fmt.Errorf("failed to get translation settings: %w", err)
+	}
+
+	return r.fromModel(&model), nil
+}
+
+func (r *translationRepository) Upsert(ctx context.Context, config *translation.Config) error {
+	query := `
+		INSERT INTO "zpTranslationSettings" ("sessionId", enabled, inbound, outbound, "sourceLanguage", "targetLanguage", "updatedAt")
+		VALUES (:sessionId, :enabled, :inbound, :outbound, :sourceLanguage, :targetLanguage, :updatedAt)
+		ON CONFLICT ("sessionId") DO UPDATE SET
+			enabled = EXCLUDED.enabled,
+			inbound = EXCLUDED.inbound,
+			outbound = EXCLUDED.outbound,
+			"sourceLanguage" = EXCLUDED."sourceLanguage",
+			"targetLanguage" = EXCLUDED."targetLanguage",
+			"updatedAt" = EXCLUDED."updatedAt"
+	`
+
+	if _, err := r.db.NamedExecContext(ctx, query, r.toModel(config)); err != nil {
+		r.logger.ErrorWithFields("Failed to save translation settings", map[string]interface{}{
+			"session_id": config.SessionID,
+			"error":      err.Error(),
+		})
+		return fmt.Errorf("failed to save translation settings: %w", err)
+	}
+
+	return nil
+}
+
+func (r *translationRepository) toModel(config *translation.Config) *translationModel {
+	return &translationModel{
+		SessionID:      config.SessionID,
+		Enabled:        config.Enabled,
+		Inbound:        config.Inbound,
+		Outbound:       config.Outbound,
+		SourceLanguage: config.SourceLanguage,
+		TargetLanguage: config.TargetLanguage,
+		UpdatedAt:      config.UpdatedAt,
+	}
+}
+
+func (r *translationRepository) fromModel(model *translationModel) *translation.Config {
+	return &translation.Config{
+		SessionID:      model.SessionID,
+		Enabled:        model.Enabled,
+		Inbound:        model.Inbound,
+		Outbound:       model.Outbound,
+		SourceLanguage: model.SourceLanguage,
+		TargetLanguage: model.TargetLanguage,
+		UpdatedAt:      model.UpdatedAt,
+	}
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"zpwoot/internal/domain/usage"
+	"zpwoot/internal/ports"
+	"zpwoot/platform/logger"
+)
+
+type usageRepository struct {
+	db     *sqlx.DB
+	logger *logger.Logger
+}
+
+func NewUsageRepository(db *sqlx.DB, logger *logger.Logger) ports.UsageRepository {
+	return &usageRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+type usageCounterModel struct {
+	Workspace string    `db:"workspace"`
+	Metric    string    `db:"metric"`
+	Period    string    `db:"period"`
+	Value     int64     `db:"value"`
+	UpdatedAt time.Time `db:"updatedAt"`
+}
+
+func (r *usageRepository) Add(ctx context.Context, workspace, metric, period string, delta int64) error {
+	query := `
+		INSERT INTO "zpUsageCounters" (workspace, metric, period, value, "updatedAt")
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (workspace, metric, period) DO UPDATE SET
+			value = "zpUsageCounters".value + EXCLUDED.value,
+			"updatedAt" = NOW()
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, workspace, metric, period, delta); err != nil {
+		r.logger.ErrorWithFields("Failed to add usage", map[string]interface{}{
+			"workspace": workspace,
+			"metric":    metric,
+			"period":    period,
+			"error":     err.Error(),
+		})
+		return fmt.Errorf("failed to add usage: %w", err)
+	}
+
+	return nil
+}
+
+func (r *usageRepository) Get(ctx context.Context, workspace, metric, period string) (int64, error) {
+	var value int64
+	query := `SELECT COALESCE(SUM(value), 0) FROM "zpUsageCounters" WHERE workspace = $1 AND metric = $2 AND period = $3`
+
+	if err := r.db.GetContext(ctx, &value, query, workspace, metric, period); err != nil {
+		r.logger.ErrorWithFields("Failed to get usage", map[string]interface{}{
+			"workspace": workspace,
+			"metric":    metric,
+			"period":    period,
+			"error":     err.Error(),
+		})
+		return 0, fmt.Errorf("failed to get usage: %w", err)
+	}
+
+	return value, nil
+}
+
+func (r *usageRepository) List(ctx context.Context, req *usage.ListCountersRequest) ([]*usage.Counter, error) {
+	whereClause := `WHERE 1 = 1`
+	args := []interface{}{}
+	argIndex := 1
+
+	if req.Workspace != "" {
+		whereClause += fmt.Sprintf(" AND workspace = $%d", argIndex)
+		args = append(args, req.Workspace)
+		argIndex++
+	}
+	if req.Metric != "" {
+		whereClause += fmt.Sprintf(" AND metric = $%d", argIndex)
+		args = append(args, req.Metric)
+		argIndex++
+	}
+	// Periods sort lexically, and a month prefix sorts before the days in it
+	if req.From != "" {
+		whereClause += fmt.Sprintf(" AND period >= $%d", argIndex)
+		args = append(args, req.From)
+		argIndex++
+	}
+	if req.To != "" {
+		whereClause += fmt.Sprintf(" AND period <= $%d", argIndex)
+		args = append(args, req.To)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT * FROM "zpUsageCounters" %s
+		ORDER BY workspace, period DESC, metric
+	`, whereClause)
+
+	var models []usageCounterModel
+	if err := r.db.SelectContext(ctx, &models, query, args...); err != nil {
+		r.logger.ErrorWithFields("Failed to list usage", map[string]interface{}{
+			"workspace": req.Workspace,
+			"error":     err.Error(),
+		})
+		return nil, fmt.Errorf("failed to list usage: %w", err)
+	}
+
+	counters := make([]*usage.Counter, len(models))
+	for i, model := range models {
+		counters[i] = &usage.Counter{
+			Workspace: model.Workspace,
+			Metric:    model.Metric,
+			Period:    model.Period,
+			Value:     model.Value,
+			UpdatedAt: model.UpdatedAt,
+		}
+	}
+
+	return counters, nil
+}
+
+func (r *usageRepository) CountSessions(ctx context.Context) (int, error) {
+	var count int
+	if err := r.db.GetContext(ctx, &count, `SELECT COUNT(*) FROM "zpSessions"`); err != nil {
+		return 0, fmt.Errorf("failed to count sessions: %w", err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"zpwoot/internal/domain/warmup"
+	"zpwoot/internal/ports"
+	"zpwoot/platform/logger"
+)
+
+type warmupRepository struct {
+	db     *sqlx.DB
+	logger *logger.Logger
+}
+
+func NewWarmupRepository(db *sqlx.DB, logger *logger.Logger) ports.WarmupRepository {
+	return &warmupRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+type warmupModel struct {
+	SessionID  string        `db:"sessionId"`
+	Enabled    bool          `db:"enabled"`
+	StartedAt  time.Time     `db:"startedAt"`
+	Schedule   pq.Int64Array `db:"schedule"`
+	MinDelayMs int64         `db:"minDelayMs"`
+	MaxDelayMs int64         `db:"maxDelayMs"`
+	SentDay    string        `db:"sentDay"`
+	SentCount  int           `db:"sentCount"`
+	UpdatedAt  time.Time     `db:"updatedAt"`
+}
+
+func (r *warmupRepository) GetBySession(ctx context.Context, sessionID string) (*warmup.Profile, error) {
+	var model warmupModel
+	query := `SELECT * FROM "zpWarmupProfiles" WHERE "sessionId" = $1`
+
+	if err := r.db.GetContext(ctx, &model, query, sessionID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.ErrorWithFields("Failed to get warm-up profile", map[string]interface{}{
+			"session_id": sessionID,
+			"error":      err.Error(),
+		})
+		return nil, fmt.Errorf("failed to get warm-up profile: %w", err)
+	}
+
+	return r.fromModel(&model), nil
+}
+
+func (r *warmupRepository) Upsert(ctx context.Context, profile *warmup.Profile) error {
+	query := `
+		INSERT INTO "zpWarmupProfiles" ("sessionId", enabled, "startedAt", schedule, "minDelayMs", "maxDelayMs", "sentDay", "sentCount", "updatedAt")
+		VALUES (:sessionId, :enabled, :startedAt, :schedule, :minDelayMs, :maxDelayMs, :sentDay, :sentCount, :updatedAt)
+		ON CONFLICT ("sessionId") DO UPDATE SET
+			enabled = EXCLUDED.enabled,
+			"startedAt" = EXCLUDED."startedAt",
+			schedule = EXCLUDED.schedule,
+			"minDelayMs" = EXCLUDED."minDelayMs",
+			"maxDelayMs" = EXCLUDED."maxDelayMs",
+			"sentDay" = EXCLUDED."sentDay",
+			"sentCount" = EXCLUDED."sentCount",
+			"updatedAt" = EXCLUDED."updatedAt"
+	`
+
+	if _, err := r.db.NamedExecContext(ctx, query, r.toModel(profile)); err != nil {
+		r.logger.ErrorWithFields("Failed to save warm-up profile", map[string]interface{}{
+			"session_id": profile.SessionID,
+			"error":      err.Error(),
+		})
+		return fmt.Errorf("failed to save warm-up profile: %w", err)
+	}
+
+	return nil
+}
+
+// IncrementSent resets the counter on a new day and increments it in one statement, so concurrent
+// sends from several replicas cannot exceed the cap
+func (r *warmupRepository) IncrementSent(ctx context.Context, sessionID, day string, dailyCap int) (int, bool, error) {
+	query := `
+		UPDATE "zpWarmupProfiles" SET
+			"sentCount" = CASE WHEN "sentDay" = $2 THEN "sentCount" + 1 ELSE 1 END,
+			"sentDay" = $2
+		WHERE "sessionId" = $1 AND ("sentDay" <> $2 OR "sentCount" < $3)
+		RETURNING "sentCount"
+	`
+
+	var count int
+	err := r.db.GetContext(ctx, &count, query, sessionID, day, dailyCap)
+	if err == nil {
+		return count, true, nil
+	}
+	if err != sql.ErrNoRows {
+		return 0, false, fmt.Errorf("failed to count warm-up send: %w", err)
+	}
+
+	// Nothing was updated: either the cap is reached or the profile is gone
+	if err := r.db.GetContext(ctx, &count, `SELECT "sentCount" FROM "zpWarmupProfiles" WHERE "sessionId" = $1`, sessionID); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, true, nil
+		}
+		return 0, false, fmt.Errorf("failed to count warm-up send: %w", err)
+	}
+
+	return count, false, nil
+}
+
+func (r *warmupRepository) toModel(profile *warmup.Profile) *warmupModel {
+	schedule := make(pq.Int64Array, len(profile.Schedule))
+	for i, dailyCap := range profile.Schedule {
+		schedule[i] = int64(dailyCap)
+	}
+
+	return &warmupModel{
+		SessionID:  profile.SessionID,
+		Enabled:    profile.Enabled,
+		StartedAt:  profile.StartedAt,
+		Schedule:   schedule,
+		MinDelayMs: profile.MinDelay.Milliseconds(),
+		MaxDelayMs: profile.MaxDelay.Milliseconds(),
+		SentDay:    profile.SentDay,
+		SentCount:  profile.SentCount,
+		UpdatedAt:  profile.UpdatedAt,
+	}
+}
+
+func (r *warmupRepository) fromModel(model *warmupModel) *warmup.Profile {
+	schedule := make([]int, len(model.Schedule))
+	for i, dailyCap := range model.Schedule {
+		schedule[i] = int(dailyCap)
+	}
+
+	return &warmup.Profile{
+		SessionID: model.SessionID,
+		Enabled:   model.Enabled,
+		StartedAt: model.StartedAt,
+		Schedule:  schedule,
+		MinDelay:  time.Duration(model.MinDelayMs) * time.Millisecond,
+		MaxDelay:  time.Duration(model.MaxDelayMs) * time.Millisecond,
+		SentDay:   model.SentDay,
+		SentCount: model.SentCount,
+		UpdatedAt: model.UpdatedAt,
+	}
+}
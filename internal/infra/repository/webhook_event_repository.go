@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"zpwoot/internal/domain/webhook"
+	"zpwoot/internal/ports"
+	"zpwoot/platform/logger"
+)
+
+type webhookEventRepository struct {
+	db     *sqlx.DB
+	logger *logger.Logger
+}
+
+func NewWebhookEventRepository(db *sqlx.DB, logger *logger.Logger) ports.WebhookEventRepository {
+	return &webhookEventRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+type webhookEventModel struct {
+	ID          int64          `db:"id"`
+	EventID     string         `db:"eventId"`
+	SessionID   string         `db:"sessionId"`
+	EventType   string         `db:"eventType"`
+	Timestamp   time.Time      `db:"timestamp"`
+	Data        string         `db:"data"`        // JSONB field
+	Routing     sql.NullString `db:"routing"`     // JSONB field
+	Translation sql.NullString `db:"translation"` // JSONB field
+	RequestID   sql.NullString `db:"requestId"`
+	CreatedAt   time.Time      `db:"createdAt"`
+}
+
+func (r *webhookEventRepository) Create(ctx context.Context, event *webhook.WebhookEvent) error {
+	model, err := r.toModel(event)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO "zpWebhookEvents" ("eventId", "sessionId", "eventType", timestamp, data, routing, translation, "requestId", "createdAt")
+		VALUES (:eventId, :sessionId, :eventType, :timestamp, :data, :routing, :translation, :requestId, :createdAt)
+	`
+
+	if _, err := r.db.NamedExecContext(ctx, query, model); err != nil {
+		return fmt.Errorf("failed to store webhook event: %w", err)
+	}
+
+	return nil
+}
+
+func (r *webhookEventRepository) ListRange(ctx context.Context, sessionID string, from, to time.Time, eventTypes []string, limit int) ([]*webhook.WebhookEvent, error) {
+	query := `
+		SELECT * FROM "zpWebhookEvents"
+		WHERE "sessionId" = $1 AND timestamp >= $2 AND timestamp < $3
+		AND (cardinality($4::text[]) = 0 OR "eventType" = ANY($4))
+		ORDER BY timestamp ASC, id ASC
+		LIMIT $5
+	`
+
+	var models []webhookEventModel
+	if err := r.db.SelectContext(ctx, &models, query, sessionID, from, to, pq.StringArray(eventTypes), limit); err != nil {
+		r.logger.ErrorWithFields("Failed to list webhook events", map[string]interface{}{
+			"session_id": sessionID,
+			"error":      err.Error(),
+		})
+		return nil, fmt.Errorf("failed to list webhook events: %w", err)
+	}
+
+	events := make([]*webhook.WebhookEvent, 0, len(models))
+	for i := range models {
+		event, err := r.fromModel(&models[i])
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+func (r *webhookEventRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM "zpWebhookEvents" WHERE "createdAt" < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete webhook events: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+func (r *webhookEventRepository) toModel(event *webhook.WebhookEvent) (*webhookEventModel, error) {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal webhook event data: %w", err)
+	}
+
+	model := &webhookEventModel{
+		EventID:   event.ID,
+		SessionID: event.SessionID,
+		EventType: event.Type,
+		Timestamp: event.Timestamp,
+		Data:      string(data),
+		CreatedAt: time.Now(),
+	}
+
+	if event.Routing != nil {
+		routing, err := json.Marshal(event.Routing)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal webhook event routing: %w", err)
+		}
+		model.Routing = sql.NullString{String: string(routing), Valid: true}
+	}
+	if event.Translation != nil {
+		translation, err := json.Marshal(event.Translation)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal webhook event translation: %w", err)
+		}
+		model.Translation = sql.NullString{String: string(translation), Valid: true}
+	}
+	if event.RequestID != "" {
+		model.RequestID = sql.NullString{String: event.RequestID, Valid: true}
+	}
+
+	return model, nil
+}
+
+func (r *webhookEventRepository) fromModel(model *webhookEventModel) (*webhook.WebhookEvent, error) {
+	event := &webhook.WebhookEvent{
+		ID:        model.EventID,
+		SessionID: model.SessionID,
+		Type:      model.EventType,
+		Timestamp: model.Timestamp,
+		RequestID: model.RequestID.String,
+	}
+
+	if err := json.Unmarshal([]byte(model.Data), &event.Data); err != nil {
+		return nil, fmt.Errorf("invalid webhook event data: %w", err)
+	}
+	if model.Routing.Valid {
+		if err := json.Unmarshal([]byte(model.Routing.String), &event.Routing); err != nil {
+			return nil, fmt.Errorf("invalid webhook event routing: %w", err)
+		}
+	}
+	if model.Translation.Valid {
+		if err := json.Unmarshal([]byte(model.Translation.String), &event.Translation); err != nil {
+			return nil, fmt.Errorf("invalid webhook event translation: %w", err)
+		}
+	}
+
+	return event, nil
+}
@@ -9,10 +9,12 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 
 	"zpwoot/internal/domain/webhook"
 	"zpwoot/internal/ports"
 	"zpwoot/platform/logger"
+	"zpwoot/platform/precondition"
 )
 
 type webhookRepository struct {
@@ -28,14 +30,18 @@ func NewWebhookRepository(db *sqlx.DB, logger *logger.Logger) ports.WebhookRepos
 }
 
 type webhookModel struct {
-	ID        string         `db:"id"`
-	SessionID sql.NullString `db:"sessionId"`
-	URL       string         `db:"url"`
-	Secret    sql.NullString `db:"secret"`
-	Events    string         `db:"events"` // JSONB field
-	Enabled   bool           `db:"enabled"`
-	CreatedAt time.Time      `db:"createdAt"`
-	UpdatedAt time.Time      `db:"updatedAt"`
+	ID              string         `db:"id"`
+	SessionID       sql.NullString `db:"sessionId"`
+	URL             string         `db:"url"`
+	Secret          sql.NullString `db:"secret"`
+	Events          string         `db:"events"` // JSONB field
+	Enabled         bool           `db:"enabled"`
+	PayloadTemplate sql.NullString `db:"payloadTemplate"`
+	TimeoutSeconds  int            `db:"timeoutSeconds"`
+	Shadow          bool           `db:"shadow"`
+	Chats           pq.StringArray `db:"chats"`
+	CreatedAt       time.Time      `db:"createdAt"`
+	UpdatedAt       time.Time      `db:"updatedAt"`
 }
 
 func (r *webhookRepository) Create(ctx context.Context, wh *webhook.WebhookConfig) error {
@@ -48,8 +54,8 @@ func (r *webhookRepository) Create(ctx context.Context, wh *webhook.WebhookConfi
 	model := r.toModel(wh)
 
 	query := `
-		INSERT INTO "zpWebhooks" (id, "sessionId", url, secret, events, enabled, "createdAt", "updatedAt")
-		VALUES (:id, :sessionId, :url, :secret, :events, :enabled, :createdAt, :updatedAt)
+		INSERT INTO "zpWebhooks" (id, "sessionId", url, secret, events, enabled, "payloadTemplate", "timeoutSeconds", shadow, chats, "createdAt", "updatedAt")
+		VALUES (:id, :sessionId, :url, :secret, :events, :enabled, :payloadTemplate, :timeoutSeconds, :shadow, :chats, :createdAt, :updatedAt)
 	`
 
 	_, err := r.db.NamedExecContext(ctx, query, model)
@@ -193,9 +199,16 @@ func (r *webhookRepository) List(ctx context.Context, req *webhook.ListWebhooksR
 		return nil, 0, fmt.Errorf("failed to count webhooks: %w", err)
 	}
 
+	// The cursor narrows the page, not the total
+	if req.After != nil {
+		whereClause += fmt.Sprintf(` AND ("createdAt", id) < ($%d, $%d)`, argIndex, argIndex+1)
+		args = append(args, req.After.CreatedAt, req.After.ID)
+		argIndex += 2
+	}
+
 	query := fmt.Sprintf(`
 		SELECT * FROM "zpWebhooks" %s
-		ORDER BY "createdAt" DESC
+		ORDER BY "createdAt" DESC, id DESC
 		LIMIT $%d OFFSET $%d
 	`, whereClause, argIndex, argIndex+1)
 
@@ -237,11 +250,12 @@ func (r *webhookRepository) Update(ctx context.Context, wh *webhook.WebhookConfi
 	query := `
 		UPDATE "zpWebhooks"
 		SET "sessionId" = :sessionId, url = :url, secret = :secret,
-		    events = :events, enabled = :enabled, "updatedAt" = :updatedAt
+		    events = :events, enabled = :enabled, "payloadTemplate" = :payloadTemplate,
+		    "timeoutSeconds" = :timeoutSeconds, shadow = :shadow, chats = :chats, "updatedAt" = :updatedAt
 		WHERE id = :id
 	`
 
-	result, err := r.db.NamedExecContext(ctx, query, model)
+	result, conditional, err := namedUpdate(ctx, r.db, query, model)
 	if err != nil {
 		r.logger.ErrorWithFields("Failed to update webhook", map[string]interface{}{
 			"webhook_id": wh.ID.String(),
@@ -256,6 +270,9 @@ func (r *webhookRepository) Update(ctx context.Context, wh *webhook.WebhookConfi
 	}
 
 	if rowsAffected == 0 {
+		if conditional {
+			return precondition.ErrFailed
+		}
 		return webhook.ErrWebhookNotFound
 	}
 
@@ -410,11 +427,14 @@ func (r *webhookRepository) UpdateWebhookStats(ctx context.Context, webhookID st
 
 func (r *webhookRepository) toModel(wh *webhook.WebhookConfig) *webhookModel {
 	model := &webhookModel{
-		ID:        wh.ID.String(),
-		URL:       wh.URL,
-		Enabled:   wh.Enabled,
-		CreatedAt: wh.CreatedAt,
-		UpdatedAt: wh.UpdatedAt,
+		ID:             wh.ID.String(),
+		URL:            wh.URL,
+		Enabled:        wh.Enabled,
+		TimeoutSeconds: wh.TimeoutSeconds,
+		Shadow:         wh.Shadow,
+		Chats:          pq.StringArray(append([]string{}, wh.Chats...)), // never NULL
+		CreatedAt:      wh.CreatedAt,
+		UpdatedAt:      wh.UpdatedAt,
 	}
 
 	if wh.SessionID != nil {
@@ -425,6 +445,10 @@ func (r *webhookRepository) toModel(wh *webhook.WebhookConfig) *webhookModel {
 		model.Secret = sql.NullString{String: wh.Secret, Valid: true}
 	}
 
+	if wh.PayloadTemplate != "" {
+		model.PayloadTemplate = sql.NullString{String: wh.PayloadTemplate, Valid: true}
+	}
+
 	if len(wh.Events) > 0 {
 		eventsJSON, err := json.Marshal(wh.Events)
 		if err == nil {
@@ -444,11 +468,14 @@ func (r *webhookRepository) fromModel(model *webhookModel) (*webhook.WebhookConf
 	}
 
 	wh := &webhook.WebhookConfig{
-		ID:        id,
-		URL:       model.URL,
-		Enabled:   model.Enabled,
-		CreatedAt: model.CreatedAt,
-		UpdatedAt: model.UpdatedAt,
+		ID:             id,
+		URL:            model.URL,
+		Enabled:        model.Enabled,
+		TimeoutSeconds: model.TimeoutSeconds,
+		Shadow:         model.Shadow,
+		Chats:          model.Chats,
+		CreatedAt:      model.CreatedAt,
+		UpdatedAt:      model.UpdatedAt,
 	}
 
 	if model.SessionID.Valid {
@@ -459,6 +486,10 @@ func (r *webhookRepository) fromModel(model *webhookModel) (*webhook.WebhookConf
 		wh.Secret = model.Secret.String
 	}
 
+	if model.PayloadTemplate.Valid {
+		wh.PayloadTemplate = model.PayloadTemplate.String
+	}
+
 	if model.Events != "" {
 		var events []string
 		if err := json.Unmarshal([]byte(model.Events), &events); err == nil {
@@ -0,0 +1,124 @@
+package wameow
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mau.fi/whatsmeow/types/events"
+
+	"zpwoot/internal/domain/session"
+)
+
+// SessionWarningEventType is the webhook event emitted when WhatsApp reports an account violation or ban
+const SessionWarningEventType = "session.warning"
+
+// connectFailureForbidden is the connect failure WhatsApp answers with, together with a logout notice,
+// when the account is restricted for violating its terms
+const connectFailureForbidden events.ConnectFailureReason = 403
+
+// accountWarningTimeout bounds storing a warning on the session
+const accountWarningTimeout = 5 * time.Second
+
+// SessionWarning reports an account violation or ban warning of a session
+type SessionWarning struct {
+	Kind      string     `json:"kind"`
+	Code      int        `json:"code"`
+	Reason    string     `json:"reason,omitempty"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	Timestamp time.Time  `json:"timestamp"`
+}
+
+// WebhookEventType implements webhookEventNamer
+func (w *SessionWarning) WebhookEventType() string {
+	return SessionWarningEventType
+}
+
+// parseAccountWarning returns the account warning carried by a whatsmeow event, or nil for other events
+func parseAccountWarning(evt interface{}) *session.Warning {
+	now := time.Now()
+
+	switch v := evt.(type) {
+	case *events.TemporaryBan:
+		warning := &session.Warning{
+			Kind:       session.WarningTemporaryBan,
+			Code:       int(v.Code),
+			Reason:     v.Code.String(),
+			ReceivedAt: now,
+		}
+		if v.Expire > 0 {
+			expiresAt := now.Add(v.Expire)
+			warning.ExpiresAt = &expiresAt
+		}
+		return warning
+	case *events.LoggedOut:
+		if v.Reason != events.ConnectFailureUnknownLogout {
+			return nil
+		}
+		return &session.Warning{
+			Kind:       session.WarningBanned,
+			Code:       int(v.Reason),
+			Reason:     v.Reason.String(),
+			ReceivedAt: now,
+		}
+	case *events.ConnectFailure:
+		if v.Reason != connectFailureForbidden {
+			return nil
+		}
+		return &session.Warning{
+			Kind:       session.WarningRestricted,
+			Code:       int(v.Reason),
+			Reason:     connectFailureNotice(v),
+			ReceivedAt: now,
+		}
+	}
+	return nil
+}
+
+// connectFailureNotice returns the logout notice shown to the user for a connect failure
+func connectFailureNotice(evt *events.ConnectFailure) string {
+	var parts []string
+	if evt.Raw != nil {
+		for _, attr := range []string{"logout_message_header", "logout_message_subtext"} {
+			if text, ok := evt.Raw.Attrs[attr].(string); ok && text != "" {
+				parts = append(parts, text)
+			}
+		}
+	}
+	if len(parts) == 0 {
+		if evt.Message != "" {
+			return evt.Message
+		}
+		return fmt.Sprintf("connect failure %d", int(evt.Reason))
+	}
+	return strings.Join(parts, ": ")
+}
+
+// handleAccountWarning flags the session with the warning and delivers it as a session.warning event
+func (h *EventHandler) handleAccountWarning(warning *session.Warning, sessionID string) {
+	h.logger.WarnWithFields("WhatsApp reported an account warning", map[string]interface{}{
+		"session_id": sessionID,
+		"kind":       warning.Kind,
+		"code":       warning.Code,
+		"reason":     warning.Reason,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), accountWarningTimeout)
+	defer cancel()
+
+	if err := h.sessionMgr.GetSessionRepo().SetWarning(ctx, sessionID, warning); err != nil {
+		h.logger.ErrorWithFields("Failed to store account warning", map[string]interface{}{
+			"session_id": sessionID,
+			"error":      err.Error(),
+		})
+	}
+
+	h.deliverToWebhook(&SessionWarning{
+		Kind:      warning.Kind,
+		Code:      warning.Code,
+		Reason:    warning.Reason,
+		ExpiresAt: warning.ExpiresAt,
+		Timestamp: warning.ReceivedAt,
+	}, sessionID)
+}
@@ -0,0 +1,329 @@
+package wameow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+
+	"zpwoot/internal/domain/message"
+)
+
+// Limits of the cards WhatsApp renders in a carousel
+const (
+	maxCarouselCards       = 10
+	maxCarouselCardButtons = 2
+)
+
+// CarouselCard is a card of a carousel: a header image or video, a title, a body, a footer and native
+// flow buttons. Every card of a carousel must use the same media type.
+type CarouselCard struct {
+	Title   string
+	Body    string
+	Footer  string
+	Media   *TemplateMedia
+	Buttons []FlowButton
+}
+
+// CarouselMessage is an interactive message showing cards the recipient swipes through
+type CarouselMessage struct {
+	Body   string
+	Footer string
+	Cards  []CarouselCard
+}
+
+// Validate checks the carousel has cards with media of a single type, a body and buttons
+func (m *CarouselMessage) Validate() error {
+	if m.Body == "" {
+		return fmt.Errorf("carousel body is required")
+	}
+	if len(m.Cards) == 0 || len(m.Cards) > maxCarouselCards {
+		return fmt.Errorf("carousel needs between 1 and %d cards", maxCarouselCards)
+	}
+
+	mediaType := ""
+	for i, card := range m.Cards {
+		if card.Body == "" {
+			return fmt.Errorf("card %d: body is required", i+1)
+		}
+		if card.Media == nil {
+			return fmt.Errorf("card %d: media is required", i+1)
+		}
+		if card.Media.Type != "image" && card.Media.Type != "video" {
+			return fmt.Errorf("card %d: invalid media type %q. Valid types: image, video", i+1, card.Media.Type)
+		}
+		if mediaType == "" {
+			mediaType = card.Media.Type
+		} else if card.Media.Type != mediaType {
+			return fmt.Errorf("card %d: all cards must use the same media type", i+1)
+		}
+		if len(card.Buttons) == 0 || len(card.Buttons) > maxCarouselCardButtons {
+			return fmt.Errorf("card %d: needs between 1 and %d buttons", i+1, maxCarouselCardButtons)
+		}
+		for j, button := range card.Buttons {
+			if button.Name == "" {
+				return fmt.Errorf("card %d, button %d: name is required", i+1, j+1)
+			}
+			if button.ParamsJSON != "" && !isJSONObject(button.ParamsJSON) {
+				return fmt.Errorf("card %d, button %d: params must be a JSON object", i+1, j+1)
+			}
+		}
+	}
+	return nil
+}
+
+// SendCarouselMessage sends a carousel. WhatsApp only renders carousels sent from business accounts, so
+// other sessions, and requests asking for it, get the cards as text instead; the returned flag reports
+// whether the text fallback was sent.
+func (m *Manager) SendCarouselMessage(sessionID, to string, carousel *CarouselMessage, fallbackToText bool) (*message.SendResult, bool, error) {
+	client := m.getClient(sessionID)
+	if client == nil {
+		return nil, false, fmt.Errorf("session %s not found", sessionID)
+	}
+	if !client.IsLoggedIn() {
+		return nil, false, fmt.Errorf("session %s is not logged in", sessionID)
+	}
+
+	if err := carousel.Validate(); err != nil {
+		return nil, false, err
+	}
+
+	asText := fallbackToText || !client.SupportsNativeFlow()
+	resp, err := client.SendCarouselMessage(context.Background(), to, carousel, asText)
+	if err != nil {
+		return &message.SendResult{
+			Status:    "failed",
+			Error:     err.Error(),
+			Timestamp: time.Now(),
+		}, asText, err
+	}
+
+	return &message.SendResult{
+		MessageID: resp.ID,
+		Status:    "sent",
+		Timestamp: resp.Timestamp,
+	}, asText, nil
+}
+
+// SendCarouselMessage uploads the card media and sends the carousel, or its text version when asText is set
+func (c *WameowClient) SendCarouselMessage(ctx context.Context, to string, carousel *CarouselMessage, asText bool) (*whatsmeow.SendResponse, error) {
+	if !c.client.IsLoggedIn() {
+		return nil, fmt.Errorf("client is not logged in")
+	}
+
+	jid, err := c.parseJID(to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JID: %w", err)
+	}
+
+	if asText {
+		return c.sendCarouselAsText(ctx, jid, to, carousel)
+	}
+
+	cards := make([]*waE2E.InteractiveMessage, 0, len(carousel.Cards))
+	for i, card := range carousel.Cards {
+		header, err := c.carouselCardHeader(ctx, card)
+		if err != nil {
+			return nil, fmt.Errorf("card %d: %w", i+1, err)
+		}
+
+		buttons := make([]*waE2E.InteractiveMessage_NativeFlowMessage_NativeFlowButton, 0, len(card.Buttons))
+		for _, button := range card.Buttons {
+			params := button.ParamsJSON
+			if params == "" {
+				params = "{}"
+			}
+			buttons = append(buttons, &waE2E.InteractiveMessage_NativeFlowMessage_NativeFlowButton{
+				Name:             proto.String(button.Name),
+				ButtonParamsJSON: proto.String(params),
+			})
+		}
+
+		interactiveCard := &waE2E.InteractiveMessage{
+			Header: header,
+			Body:   &waE2E.InteractiveMessage_Body{Text: proto.String(card.Body)},
+			InteractiveMessage: &waE2E.InteractiveMessage_NativeFlowMessage_{
+				NativeFlowMessage: &waE2E.InteractiveMessage_NativeFlowMessage{
+					Buttons:        buttons,
+					MessageVersion: proto.Int32(1),
+				},
+			},
+		}
+		if card.Footer != "" {
+			interactiveCard.Footer = &waE2E.InteractiveMessage_Footer{Text: proto.String(card.Footer)}
+		}
+		cards = append(cards, interactiveCard)
+	}
+
+	interactive := &waE2E.InteractiveMessage{
+		Body: &waE2E.InteractiveMessage_Body{Text: proto.String(carousel.Body)},
+		InteractiveMessage: &waE2E.InteractiveMessage_CarouselMessage_{
+			CarouselMessage: &waE2E.InteractiveMessage_CarouselMessage{
+				Cards:          cards,
+				MessageVersion: proto.Int32(1),
+			},
+		},
+	}
+	if carousel.Footer != "" {
+		interactive.Footer = &waE2E.InteractiveMessage_Footer{Text: proto.String(carousel.Footer)}
+	}
+
+	msg := &waE2E.Message{
+		ViewOnceMessage: &waE2E.FutureProofMessage{
+			Message: &waE2E.Message{
+				InteractiveMessage: interactive,
+			},
+		},
+	}
+
+	c.logger.InfoWithFields("Sending carousel message", map[string]interface{}{
+		"session_id": c.sessionID,
+		"to":         to,
+		"card_count": len(cards),
+	})
+
+	resp, err := c.sendMessage(ctx, jid, msg)
+	if err != nil {
+		c.logger.ErrorWithFields("Failed to send carousel message", map[string]interface{}{
+			"session_id": c.sessionID,
+			"to":         to,
+			"error":      err.Error(),
+		})
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// carouselCardHeader uploads the card media and builds the card header
+func (c *WameowClient) carouselCardHeader(ctx context.Context, card CarouselCard) (*waE2E.InteractiveMessage_Header, error) {
+	data, err := os.ReadFile(card.Media.FilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", card.Media.Type, err)
+	}
+
+	header := &waE2E.InteractiveMessage_Header{
+		Title:              proto.String(card.Title),
+		HasMediaAttachment: proto.Bool(true),
+	}
+
+	switch card.Media.Type {
+	case "image":
+		uploaded, err := c.upload(ctx, data, whatsmeow.MediaImage)
+		if err != nil {
+			return nil, fmt.Errorf("failed to upload image: %w", err)
+		}
+		header.Media = &waE2E.InteractiveMessage_Header_ImageMessage{
+			ImageMessage: &waE2E.ImageMessage{
+				URL:           proto.String(uploaded.URL),
+				DirectPath:    proto.String(uploaded.DirectPath),
+				MediaKey:      uploaded.MediaKey,
+				Mimetype:      proto.String(templateMimeType(card.Media.MimeType, "image/jpeg")),
+				FileEncSHA256: uploaded.FileEncSHA256,
+				FileSHA256:    uploaded.FileSHA256,
+				FileLength:    proto.Uint64(uploaded.FileLength),
+			},
+		}
+	case "video":
+		uploaded, err := c.upload(ctx, data, whatsmeow.MediaVideo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to upload video: %w", err)
+		}
+		header.Media = &waE2E.InteractiveMessage_Header_VideoMessage{
+			VideoMessage: &waE2E.VideoMessage{
+				URL:           proto.String(uploaded.URL),
+				DirectPath:    proto.String(uploaded.DirectPath),
+				MediaKey:      uploaded.MediaKey,
+				Mimetype:      proto.String(templateMimeType(card.Media.MimeType, "video/mp4")),
+				FileEncSHA256: uploaded.FileEncSHA256,
+				FileSHA256:    uploaded.FileSHA256,
+				FileLength:    proto.Uint64(uploaded.FileLength),
+			},
+		}
+	}
+
+	return header, nil
+}
+
+// sendCarouselAsText sends the cards of a carousel as a numbered text message
+func (c *WameowClient) sendCarouselAsText(ctx context.Context, jid types.JID, to string, carousel *CarouselMessage) (*whatsmeow.SendResponse, error) {
+	text := carouselMessageText(carousel)
+	msg := &waE2E.Message{
+		ExtendedTextMessage: &waE2E.ExtendedTextMessage{
+			Text: &text,
+		},
+	}
+
+	c.logger.InfoWithFields("Sending carousel message as text", map[string]interface{}{
+		"session_id": c.sessionID,
+		"to":         to,
+		"card_count": len(carousel.Cards),
+	})
+
+	resp, err := c.sendMessage(ctx, jid, msg)
+	if err != nil {
+		c.logger.ErrorWithFields("Failed to send carousel message as text", map[string]interface{}{
+			"session_id": c.sessionID,
+			"to":         to,
+			"error":      err.Error(),
+		})
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// carouselMessageText renders a carousel as text, listing each card with the labels and links of its buttons
+func carouselMessageText(carousel *CarouselMessage) string {
+	var b strings.Builder
+	b.WriteString(carousel.Body)
+
+	for i, card := range carousel.Cards {
+		b.WriteString("\n\n")
+		if card.Title != "" {
+			fmt.Fprintf(&b, "*%d. %s*\n%s", i+1, card.Title, card.Body)
+		} else {
+			fmt.Fprintf(&b, "*%d.* %s", i+1, card.Body)
+		}
+		for _, button := range card.Buttons {
+			if label := flowButtonText(button); label != "" {
+				fmt.Fprintf(&b, "\n• %s", label)
+			}
+		}
+		if card.Footer != "" {
+			fmt.Fprintf(&b, "\n_%s_", card.Footer)
+		}
+	}
+
+	if carousel.Footer != "" {
+		fmt.Fprintf(&b, "\n\n_%s_", carousel.Footer)
+	}
+
+	return b.String()
+}
+
+// flowButtonText describes a native flow button from its display text and the link, number or code it carries
+func flowButtonText(button FlowButton) string {
+	var params map[string]interface{}
+	if button.ParamsJSON != "" {
+		_ = json.Unmarshal([]byte(button.ParamsJSON), &params)
+	}
+
+	label, _ := params["display_text"].(string)
+	for _, key := range []string{"url", "phone_number", "copy_code"} {
+		if value, ok := params[key].(string); ok && value != "" {
+			if label == "" {
+				return value
+			}
+			return label + ": " + value
+		}
+	}
+	return label
+}
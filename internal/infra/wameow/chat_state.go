@@ -0,0 +1,156 @@
+package wameow
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mau.fi/whatsmeow/appstate"
+	"go.mau.fi/whatsmeow/proto/waCommon"
+	"go.mau.fi/whatsmeow/proto/waSyncAction"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+
+	"zpwoot/internal/domain/chat"
+)
+
+// ArchiveChat archives or unarchives a chat
+func (m *Manager) ArchiveChat(sessionID, chatJID string, archive bool, last *chat.LastMessage) error {
+	return m.sendChatPatch(sessionID, chatJID, func(jid types.JID) appstate.PatchInfo {
+		timestamp, key := lastMessageKey(jid, last)
+		return appstate.BuildArchive(jid, archive, timestamp, key)
+	})
+}
+
+// PinChat pins or unpins a chat
+func (m *Manager) PinChat(sessionID, chatJID string, pin bool) error {
+	return m.sendChatPatch(sessionID, chatJID, func(jid types.JID) appstate.PatchInfo {
+		return appstate.BuildPin(jid, pin)
+	})
+}
+
+// MuteChat mutes a chat for a duration, forever when it is zero, or unmutes it
+func (m *Manager) MuteChat(sessionID, chatJID string, mute bool, duration time.Duration) error {
+	return m.sendChatPatch(sessionID, chatJID, func(jid types.JID) appstate.PatchInfo {
+		return appstate.BuildMute(jid, mute, duration)
+	})
+}
+
+// MarkChatRead marks a whole chat as read or unread
+func (m *Manager) MarkChatRead(sessionID, chatJID string, read bool, last *chat.LastMessage) error {
+	return m.sendChatPatch(sessionID, chatJID, func(jid types.JID) appstate.PatchInfo {
+		return appstate.PatchInfo{
+			Type: appstate.WAPatchRegularLow,
+			Mutations: []appstate.MutationInfo{{
+				Index:   []string{appstate.IndexMarkChatAsRead, jid.String()},
+				Version: 3,
+				Value: &waSyncAction.SyncActionValue{
+					MarkChatAsReadAction: &waSyncAction.MarkChatAsReadAction{
+						Read:         proto.Bool(read),
+						MessageRange: chatMessageRange(jid, last),
+					},
+				},
+			}},
+		}
+	})
+}
+
+// ClearChat deletes the messages of a chat on every device but keeps the chat
+func (m *Manager) ClearChat(sessionID, chatJID string, options chat.ClearOptions, last *chat.LastMessage) error {
+	return m.sendChatPatch(sessionID, chatJID, func(jid types.JID) appstate.PatchInfo {
+		return appstate.PatchInfo{
+			Type: appstate.WAPatchRegularHigh,
+			Mutations: []appstate.MutationInfo{{
+				Index:   []string{appstate.IndexClearChat, jid.String(), patchFlag(!options.KeepStarred), patchFlag(options.DeleteMedia)},
+				Version: 6,
+				Value: &waSyncAction.SyncActionValue{
+					ClearChatAction: &waSyncAction.ClearChatAction{
+						MessageRange: chatMessageRange(jid, last),
+					},
+				},
+			}},
+		}
+	})
+}
+
+// DeleteChat deletes a chat with its messages on every device
+func (m *Manager) DeleteChat(sessionID, chatJID string, options chat.ClearOptions, last *chat.LastMessage) error {
+	return m.sendChatPatch(sessionID, chatJID, func(jid types.JID) appstate.PatchInfo {
+		return appstate.PatchInfo{
+			Type: appstate.WAPatchRegularHigh,
+			Mutations: []appstate.MutationInfo{{
+				Index:   []string{appstate.IndexDeleteChat, jid.String(), patchFlag(options.DeleteMedia)},
+				Version: 6,
+				Value: &waSyncAction.SyncActionValue{
+					DeleteChatAction: &waSyncAction.DeleteChatAction{
+						MessageRange: chatMessageRange(jid, last),
+					},
+				},
+			}},
+		}
+	})
+}
+
+// sendChatPatch sends the app state patch built for a chat of a logged in session
+func (m *Manager) sendChatPatch(sessionID, chatJID string, build func(jid types.JID) appstate.PatchInfo) error {
+	client := m.getClient(sessionID)
+	if client == nil {
+		return fmt.Errorf("session %s not found", sessionID)
+	}
+	if !client.IsLoggedIn() {
+		return fmt.Errorf("session %s is not logged in", sessionID)
+	}
+	if err := client.checkSendGate(); err != nil {
+		return err
+	}
+
+	jid, err := client.parseJID(chatJID)
+	if err != nil {
+		return fmt.Errorf("invalid JID: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	return client.GetClient().SendAppState(ctx, build(jid.ToNonAD()))
+}
+
+// chatMessageRange is the range of messages a chat patch covers, ending at the last message when known
+func chatMessageRange(jid types.JID, last *chat.LastMessage) *waSyncAction.SyncActionMessageRange {
+	timestamp, key := lastMessageKey(jid, last)
+
+	messageRange := &waSyncAction.SyncActionMessageRange{
+		LastMessageTimestamp: proto.Int64(timestamp.Unix()),
+	}
+	if key != nil {
+		messageRange.Messages = []*waSyncAction.SyncActionMessage{{
+			Key:       key,
+			Timestamp: proto.Int64(timestamp.Unix()),
+		}}
+	}
+	return messageRange
+}
+
+// lastMessageKey returns the timestamp and key of the last message of a chat, or now and no key
+func lastMessageKey(jid types.JID, last *chat.LastMessage) (time.Time, *waCommon.MessageKey) {
+	if last == nil || last.ID == "" {
+		return time.Now(), nil
+	}
+
+	key := &waCommon.MessageKey{
+		RemoteJID: proto.String(jid.String()),
+		FromMe:    proto.Bool(last.FromMe),
+		ID:        proto.String(last.ID),
+	}
+	if jid.Server == types.GroupServer && !last.FromMe && last.SenderJID != "" {
+		key.Participant = proto.String(last.SenderJID)
+	}
+	return last.Timestamp, key
+}
+
+func patchFlag(value bool) string {
+	if value {
+		return "1"
+	}
+	return "0"
+}
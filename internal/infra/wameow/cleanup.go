@@ -0,0 +1,128 @@
+package wameow
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"zpwoot/internal/domain/session"
+)
+
+// maxEventHandlersPerSession bounds handler registrations so a misbehaving caller cannot grow them forever
+const maxEventHandlersPerSession = 32
+
+// janitorLookupTimeout bounds each session lookup done by the janitor
+const janitorLookupTimeout = 5 * time.Second
+
+// RemoveSession disconnects the session's client and drops everything the manager keeps for it:
+// the client, its QR state, event handlers, statistics and metrics
+func (m *Manager) RemoveSession(sessionID string) error {
+	m.clientsMutex.Lock()
+	client := m.clients[sessionID]
+	delete(m.clients, sessionID)
+	m.clientsMutex.Unlock()
+
+	if client != nil {
+		client.release()
+	}
+	m.forgetSessionState(sessionID)
+
+	m.logger.InfoWithFields("Session state removed", map[string]interface{}{
+		"session_id": sessionID,
+	})
+	return nil
+}
+
+// forgetSessionState drops the per-session maps kept outside the client
+func (m *Manager) forgetSessionState(sessionID string) {
+	m.handlersMutex.Lock()
+	delete(m.eventHandlers, sessionID)
+	m.handlersMutex.Unlock()
+
+	m.statsMutex.Lock()
+	delete(m.sessionStats, sessionID)
+	m.statsMutex.Unlock()
+
+	m.historyBackfills.release(sessionID)
+	m.newsletterCounts.forgetSession(sessionID)
+	m.phoneSync.forget(sessionID)
+	m.presenceSubs.forget(sessionID)
+	m.reconnects.release(sessionID)
+}
+
+// ReleaseDeletedSessions removes the in-memory state of sessions deleted from the database
+func (m *Manager) ReleaseDeletedSessions(ctx context.Context) error {
+	if removed := m.sweepDeletedSessions(ctx); removed > 0 {
+		m.logger.InfoWithFields("Janitor removed state of deleted sessions", map[string]interface{}{
+			"count": removed,
+		})
+	}
+	return nil
+}
+
+// sweepDeletedSessions removes state for sessions that no longer exist and returns how many it removed
+func (m *Manager) sweepDeletedSessions(ctx context.Context) int {
+	removed := 0
+	for _, sessionID := range m.trackedSessionIDs() {
+		lookupCtx, cancel := context.WithTimeout(ctx, janitorLookupTimeout)
+		_, err := m.sessionMgr.GetSessionRepo().GetByID(lookupCtx, sessionID)
+		cancel()
+
+		if !errors.Is(err, session.ErrSessionNotFound) {
+			continue
+		}
+
+		if err := m.RemoveSession(sessionID); err == nil {
+			removed++
+		}
+	}
+	return removed
+}
+
+// trackedSessionIDs returns every session the manager keeps any state for
+func (m *Manager) trackedSessionIDs() []string {
+	seen := make(map[string]bool)
+
+	m.clientsMutex.RLock()
+	for sessionID := range m.clients {
+		seen[sessionID] = true
+	}
+	m.clientsMutex.RUnlock()
+
+	m.handlersMutex.RLock()
+	for sessionID := range m.eventHandlers {
+		seen[sessionID] = true
+	}
+	m.handlersMutex.RUnlock()
+
+	m.statsMutex.RLock()
+	for sessionID := range m.sessionStats {
+		seen[sessionID] = true
+	}
+	m.statsMutex.RUnlock()
+
+	sessionIDs := make([]string, 0, len(seen))
+	for sessionID := range seen {
+		sessionIDs = append(sessionIDs, sessionID)
+	}
+	return sessionIDs
+}
+
+// release stops the client's background work and frees its QR state, handlers and metrics
+func (c *WameowClient) release() {
+	c.stopQRLoop()
+
+	c.mu.Lock()
+	if c.cancel != nil {
+		c.cancel()
+	}
+	c.mu.Unlock()
+
+	if c.client.IsConnected() {
+		c.client.Disconnect()
+	}
+	c.client.RemoveEventHandlers()
+
+	c.clearQRCode()
+	c.metrics.forget()
+}
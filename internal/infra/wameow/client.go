@@ -4,6 +4,7 @@ package wameow
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
@@ -12,12 +13,15 @@ import (
 	"time"
 
 	appMessage "zpwoot/internal/app/message"
+	"zpwoot/internal/domain/poll"
 	"zpwoot/internal/domain/session"
 	"zpwoot/internal/ports"
 	"zpwoot/platform/logger"
 
 	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/appstate"
 	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/proto/waSyncAction"
 	"go.mau.fi/whatsmeow/store"
 	"go.mau.fi/whatsmeow/store/sqlstore"
 	"go.mau.fi/whatsmeow/types"
@@ -91,6 +95,13 @@ type WameowClient struct {
 	sessionMgr  SessionUpdater
 	qrGenerator QRGenerator
 	msgSender   MessageSender
+	metrics     *clientMetrics
+	sendGate    SendGate
+	// Send hooks copied from the Manager when the session is created
+	sendGuard      SendGuard
+	sendThrottle   SendThrottle
+	messageHistory MessageHistory
+	messageStats   MessageStats
 
 	// Event handling
 	eventHandler QREventHandler
@@ -167,8 +178,12 @@ func NewWameowClient(
 		cancel: cancel,
 	}
 
+	// Collect whatsmeow internals (reconnects, receipts, media bytes) for /metrics
+	wameowClient.metrics = newClientMetrics(sessionID, logger)
+	client.AddEventHandler(wameowClient.metrics.handleEvent)
+
 	// Initialize message sender
-	wameowClient.msgSender = NewMessageSender(client, logger)
+	wameowClient.msgSender = NewMessageSender(client, logger, wameowClient.metrics, wameowClient.prepareSend, wameowClient.recordSentMessage)
 
 	return wameowClient, nil
 }
@@ -209,6 +224,7 @@ func (c *WameowClient) Connect() error {
 	if c.client.IsConnected() {
 		c.client.Disconnect()
 	}
+	c.metrics.disconnected()
 
 	// Update context without holding the main mutex
 	c.mu.Lock()
@@ -430,6 +446,7 @@ func (c *WameowClient) handleQREvent(evt whatsmeow.QRChannelItem) {
 		c.qrState.mu.RUnlock()
 
 		if currentCode != evt.Code {
+			c.metrics.qrEvent(evt.Event)
 			c.updateQRCode(evt.Code)
 			c.setStatus("connecting")
 
@@ -449,6 +466,7 @@ func (c *WameowClient) handleQREvent(evt whatsmeow.QRChannelItem) {
 		}
 
 	case "success":
+		c.metrics.qrEvent(evt.Event)
 		c.logger.InfoWithFields("QR code scanned successfully", map[string]interface{}{
 			"session_id": c.sessionID,
 		})
@@ -456,6 +474,7 @@ func (c *WameowClient) handleQREvent(evt whatsmeow.QRChannelItem) {
 		c.setStatus("connected")
 
 	case "timeout":
+		c.metrics.qrEvent(evt.Event)
 		c.logger.WarnWithFields("QR code timeout", map[string]interface{}{
 			"session_id": c.sessionID,
 		})
@@ -475,7 +494,22 @@ func (c *WameowClient) updateQRCode(code string) {
 	defer c.qrState.mu.Unlock()
 
 	c.qrState.code = code
-	c.qrState.codeBase64 = c.qrGenerator.GenerateQRCodeImage(code)
+	// The image is rendered on first request, so sessions nobody is pairing hold no image
+	c.qrState.codeBase64 = ""
+}
+
+// GetQRCodeImage returns the current QR code as a base64 PNG, rendering it once per code
+func (c *WameowClient) GetQRCodeImage() string {
+	c.qrState.mu.Lock()
+	defer c.qrState.mu.Unlock()
+
+	if c.qrState.code == "" {
+		return ""
+	}
+	if c.qrState.codeBase64 == "" {
+		c.qrState.codeBase64 = c.qrGenerator.GenerateQRCodeImage(c.qrState.code)
+	}
+	return c.qrState.codeBase64
 }
 
 func (c *WameowClient) displayQRCode(code string) {
@@ -543,6 +577,7 @@ func (c *WameowClient) Logout() error {
 	}
 
 	c.setStatus("disconnected")
+	c.metrics.forget()
 	c.logger.InfoWithFields("Successfully logged out session", map[string]interface{}{
 		"session_id": c.sessionID,
 	})
@@ -553,34 +588,36 @@ func (c *WameowClient) SendTextMessage(ctx context.Context, to, body string) (*w
 	return c.msgSender.SendText(ctx, to, body, nil)
 }
 
-func (c *WameowClient) SendImageMessage(ctx context.Context, to, filePath, caption string, contextInfo *appMessage.ContextInfo) (*whatsmeow.SendResponse, error) {
+// SendImageMessage, SendAudioMessage, SendVideoMessage and SendDocumentMessage send a local file;
+// an empty mimeType falls back to the usual type of the media
+func (c *WameowClient) SendImageMessage(ctx context.Context, to, filePath, mimeType, caption string, contextInfo *appMessage.ContextInfo) (*whatsmeow.SendResponse, error) {
 	options := MediaOptions{
 		Caption:  caption,
-		MimeType: "image/jpeg",
+		MimeType: mimeTypeOr(mimeType, "image/jpeg"),
 	}
 	return c.msgSender.SendMedia(ctx, to, filePath, MediaTypeImage, options)
 }
 
-func (c *WameowClient) SendAudioMessage(ctx context.Context, to, filePath string, contextInfo *appMessage.ContextInfo) (*whatsmeow.SendResponse, error) {
+func (c *WameowClient) SendAudioMessage(ctx context.Context, to, filePath, mimeType string, contextInfo *appMessage.ContextInfo) (*whatsmeow.SendResponse, error) {
 	options := MediaOptions{
-		MimeType: "audio/ogg; codecs=opus",
+		MimeType: audioMimeType(mimeType),
 	}
 	return c.msgSender.SendMedia(ctx, to, filePath, MediaTypeAudio, options)
 }
 
-func (c *WameowClient) SendVideoMessage(ctx context.Context, to, filePath, caption string, contextInfo *appMessage.ContextInfo) (*whatsmeow.SendResponse, error) {
+func (c *WameowClient) SendVideoMessage(ctx context.Context, to, filePath, mimeType, caption string, contextInfo *appMessage.ContextInfo) (*whatsmeow.SendResponse, error) {
 	options := MediaOptions{
 		Caption:  caption,
-		MimeType: "video/mp4",
+		MimeType: mimeTypeOr(mimeType, "video/mp4"),
 	}
 	return c.msgSender.SendMedia(ctx, to, filePath, MediaTypeVideo, options)
 }
 
-func (c *WameowClient) SendDocumentMessage(ctx context.Context, to, filePath, filename, caption string, contextInfo *appMessage.ContextInfo) (*whatsmeow.SendResponse, error) {
+func (c *WameowClient) SendDocumentMessage(ctx context.Context, to, filePath, mimeType, filename, caption string, contextInfo *appMessage.ContextInfo) (*whatsmeow.SendResponse, error) {
 	options := MediaOptions{
 		Filename: filename,
 		Caption:  caption,
-		MimeType: "application/octet-stream",
+		MimeType: mimeTypeOr(mimeType, "application/octet-stream"),
 	}
 	return c.msgSender.SendMedia(ctx, to, filePath, MediaTypeDocument, options)
 }
@@ -615,7 +652,7 @@ func (c *WameowClient) SendContactMessage(ctx context.Context, to, contactName,
 		"contact_phone": contactPhone,
 	})
 
-	resp, err := c.client.SendMessage(ctx, jid, message)
+	resp, err := c.sendMessage(ctx, jid, message)
 	if err != nil {
 		c.logger.ErrorWithFields("Failed to send contact message", map[string]interface{}{
 			"session_id": c.sessionID,
@@ -690,7 +727,7 @@ func (c *WameowClient) SendDetailedContactMessage(ctx context.Context, to string
 		"has_org":       contact.Organization != "",
 	})
 
-	resp, err := c.client.SendMessage(ctx, jid, message)
+	resp, err := c.sendMessage(ctx, jid, message)
 	if err != nil {
 		c.logger.ErrorWithFields("Failed to send detailed contact message", map[string]interface{}{
 			"session_id": c.sessionID,
@@ -836,7 +873,7 @@ func (c *WameowClient) sendContactArrayMessage(ctx context.Context, jid types.JI
 		"display_name":  displayName,
 	})
 
-	resp, err := c.client.SendMessage(ctx, jid, message)
+	resp, err := c.sendMessage(ctx, jid, message)
 	if err != nil {
 		c.logger.ErrorWithFields(fmt.Sprintf("Failed to send %s contacts array message", formatType), map[string]interface{}{
 			"session_id": c.sessionID,
@@ -918,7 +955,7 @@ func (c *WameowClient) SendSingleContactMessage(ctx context.Context, to string,
 		"vcard_content": vcard,
 	})
 
-	resp, err := c.client.SendMessage(ctx, jid, message)
+	resp, err := c.sendMessage(ctx, jid, message)
 	if err != nil {
 		c.logger.ErrorWithFields("Failed to send single contact message", map[string]interface{}{
 			"session_id": c.sessionID,
@@ -986,7 +1023,7 @@ func (c *WameowClient) SendSingleContactMessageBusiness(ctx context.Context, to
 		"vcard_content": vcard,
 	})
 
-	resp, err := c.client.SendMessage(ctx, jid, message)
+	resp, err := c.sendMessage(ctx, jid, message)
 	if err != nil {
 		c.logger.ErrorWithFields("Failed to send Business single contact message", map[string]interface{}{
 			"session_id": c.sessionID,
@@ -1006,9 +1043,36 @@ func (c *WameowClient) SendSingleContactMessageBusiness(ctx context.Context, to
 	return &resp, nil
 }
 
+// parseJID parses a recipient, mapping the own number or LID of the session to its self chat
 func (c *WameowClient) parseJID(jidStr string) (types.JID, error) {
 	validator := NewJIDValidator()
-	return validator.Parse(jidStr)
+	jid, err := validator.Parse(jidStr)
+	if err != nil {
+		return types.EmptyJID, err
+	}
+	return selfChatJID(c.client, jid), nil
+}
+
+// resolveMessageSender returns the sender JID used to build the key of an existing message.
+// Own messages use an empty JID; messages from other group members need their participant JID.
+func (c *WameowClient) resolveMessageSender(chat types.JID, participant string, fromMe bool) (types.JID, error) {
+	if fromMe {
+		return types.EmptyJID, nil
+	}
+
+	if participant != "" {
+		sender, err := c.parseJID(participant)
+		if err != nil {
+			return types.EmptyJID, fmt.Errorf("invalid participant JID: %w", err)
+		}
+		return sender, nil
+	}
+
+	if chat.Server == types.GroupServer {
+		return types.EmptyJID, fmt.Errorf("participant is required for messages from other group members")
+	}
+
+	return chat, nil
 }
 
 // Helper function to create WhatsApp ContextInfo from our ContextInfo
@@ -1053,7 +1117,7 @@ func (c *WameowClient) sendMediaMessageWithContext(
 		return nil, fmt.Errorf("failed to read %s file: %w", messageType, err)
 	}
 
-	uploaded, err := c.client.Upload(ctx, data, mediaType)
+	uploaded, err := c.upload(ctx, data, mediaType)
 	if err != nil {
 		return nil, fmt.Errorf("failed to upload %s: %w", messageType, err)
 	}
@@ -1064,11 +1128,11 @@ func (c *WameowClient) sendMediaMessageWithContext(
 		"session_id": c.sessionID,
 		"to":         to,
 		"file_size":  len(data),
-		"caption":    caption,
+		"captioned":  caption != "",
 		"has_reply":  contextInfo != nil,
 	})
 
-	resp, err := c.client.SendMessage(ctx, jid, message)
+	resp, err := c.sendMessage(ctx, jid, message)
 	if err != nil {
 		c.logger.ErrorWithFields(fmt.Sprintf("Failed to send %s message", messageType), map[string]interface{}{
 			"session_id": c.sessionID,
@@ -1128,7 +1192,7 @@ func (c *WameowClient) SendAudioMessageWithContext(ctx context.Context, to, file
 		return nil, fmt.Errorf("failed to read audio file: %w", err)
 	}
 
-	uploaded, err := c.client.Upload(ctx, data, whatsmeow.MediaAudio)
+	uploaded, err := c.upload(ctx, data, whatsmeow.MediaAudio)
 	if err != nil {
 		return nil, fmt.Errorf("failed to upload audio: %w", err)
 	}
@@ -1154,7 +1218,7 @@ func (c *WameowClient) SendAudioMessageWithContext(ctx context.Context, to, file
 		"has_reply":  contextInfo != nil,
 	})
 
-	resp, err := c.client.SendMessage(ctx, jid, message)
+	resp, err := c.sendMessage(ctx, jid, message)
 	if err != nil {
 		c.logger.ErrorWithFields("Failed to send audio message", map[string]interface{}{
 			"session_id": c.sessionID,
@@ -1214,7 +1278,7 @@ func (c *WameowClient) SendDocumentMessageWithContext(ctx context.Context, to, f
 		return nil, fmt.Errorf("failed to read document file: %w", err)
 	}
 
-	uploaded, err := c.client.Upload(ctx, data, whatsmeow.MediaDocument)
+	uploaded, err := c.upload(ctx, data, whatsmeow.MediaDocument)
 	if err != nil {
 		return nil, fmt.Errorf("failed to upload document: %w", err)
 	}
@@ -1243,7 +1307,7 @@ func (c *WameowClient) SendDocumentMessageWithContext(ctx context.Context, to, f
 		"has_reply":  contextInfo != nil,
 	})
 
-	resp, err := c.client.SendMessage(ctx, jid, message)
+	resp, err := c.sendMessage(ctx, jid, message)
 	if err != nil {
 		c.logger.ErrorWithFields("Failed to send document message", map[string]interface{}{
 			"session_id": c.sessionID,
@@ -1266,6 +1330,45 @@ func (c *WameowClient) AddEventHandler(handler whatsmeow.EventHandler) uint32 {
 	return c.client.AddEventHandler(handler)
 }
 
+// sendMessage sends through whatsmeow, timing the send and waiting for the message's delivery receipt
+func (c *WameowClient) sendMessage(ctx context.Context, to types.JID, message *waE2E.Message, extra ...whatsmeow.SendRequestExtra) (whatsmeow.SendResponse, error) {
+	to, err := c.prepareSend(ctx, to, message)
+	if err != nil {
+		return whatsmeow.SendResponse{}, err
+	}
+
+	started := time.Now()
+	resp, err := c.client.SendMessage(ctx, to, message, extra...)
+	c.metrics.sendFinished(to, resp, started, err)
+	if err == nil {
+		c.recordSentMessage(to, resp, message)
+		recordMessageStats(c.messageStats, c.sessionID, true, resp.Timestamp, message)
+	}
+	return resp, err
+}
+
+// upload uploads media through whatsmeow and counts the uploaded bytes
+func (c *WameowClient) upload(ctx context.Context, data []byte, mediaType whatsmeow.MediaType) (whatsmeow.UploadResponse, error) {
+	uploaded, err := c.client.Upload(ctx, data, mediaType)
+	if err != nil {
+		c.metrics.recordError("upload", err.Error())
+	} else {
+		c.metrics.mediaUploaded(mediaType, len(data))
+	}
+	return uploaded, err
+}
+
+// Download downloads the media of a message and counts the downloaded bytes
+func (c *WameowClient) Download(ctx context.Context, msg whatsmeow.DownloadableMessage) ([]byte, error) {
+	data, err := c.client.Download(ctx, msg)
+	if err != nil {
+		c.metrics.recordError("download", err.Error())
+	} else {
+		c.metrics.mediaDownloaded(whatsmeow.GetMediaType(msg), len(data))
+	}
+	return data, err
+}
+
 func (c *WameowClient) SendStickerMessage(ctx context.Context, to, filePath string) (*whatsmeow.SendResponse, error) {
 	if !c.client.IsLoggedIn() {
 		return nil, fmt.Errorf("client is not logged in")
@@ -1281,7 +1384,7 @@ func (c *WameowClient) SendStickerMessage(ctx context.Context, to, filePath stri
 		return nil, fmt.Errorf("failed to read sticker file: %w", err)
 	}
 
-	uploaded, err := c.client.Upload(ctx, data, whatsmeow.MediaImage) // Stickers use image media type
+	uploaded, err := c.upload(ctx, data, whatsmeow.MediaImage) // Stickers use image media type
 	if err != nil {
 		return nil, fmt.Errorf("failed to upload sticker: %w", err)
 	}
@@ -1305,7 +1408,7 @@ func (c *WameowClient) SendStickerMessage(ctx context.Context, to, filePath stri
 		"file_size":  len(data),
 	})
 
-	resp, err := c.client.SendMessage(ctx, jid, message)
+	resp, err := c.sendMessage(ctx, jid, message)
 	if err != nil {
 		c.logger.ErrorWithFields("Failed to send sticker message", map[string]interface{}{
 			"session_id": c.sessionID,
@@ -1369,7 +1472,7 @@ func (c *WameowClient) SendButtonMessage(ctx context.Context, to, body string, b
 		"body_length":  len(body),
 	})
 
-	resp, err := c.client.SendMessage(ctx, jid, message)
+	resp, err := c.sendMessage(ctx, jid, message)
 	if err != nil {
 		c.logger.ErrorWithFields("Failed to send button message", map[string]interface{}{
 			"session_id": c.sessionID,
@@ -1388,7 +1491,7 @@ func (c *WameowClient) SendButtonMessage(ctx context.Context, to, body string, b
 	return &resp, nil
 }
 
-func (c *WameowClient) SendListMessage(ctx context.Context, to, body, buttonText string, sections []map[string]interface{}) (*whatsmeow.SendResponse, error) {
+func (c *WameowClient) SendListMessage(ctx context.Context, to, title, body, footer, buttonText string, sections []map[string]interface{}, fallbackToText bool) (*whatsmeow.SendResponse, error) {
 	// Validate request
 	jid, err := c.validateListMessageRequest(to)
 	if err != nil {
@@ -1398,8 +1501,24 @@ func (c *WameowClient) SendListMessage(ctx context.Context, to, body, buttonText
 	// Build list sections
 	listSections := c.buildListSections(sections)
 
+	listMsg := &waE2E.ListMessage{
+		Title:       &title,
+		Description: &body,
+		ButtonText:  &buttonText,
+		ListType:    waE2E.ListMessage_SINGLE_SELECT.Enum(),
+		Sections:    listSections,
+	}
+	if footer != "" {
+		listMsg.FooterText = &footer
+	}
+
+	// Clients that do not render native lists get the same content as numbered text
+	if fallbackToText {
+		return c.sendListAsText(ctx, jid, to, listMsg)
+	}
+
 	// Create and send message
-	return c.sendListMessage(ctx, jid, to, body, buttonText, listSections, len(sections))
+	return c.sendListMessage(ctx, jid, to, listMsg)
 }
 
 // validateListMessageRequest validates the list message request
@@ -1463,16 +1582,8 @@ func (c *WameowClient) buildListRows(rows []interface{}) []*waE2E.ListMessage_Ro
 	return listRows
 }
 
-// sendListMessage creates and sends the list message
-func (c *WameowClient) sendListMessage(ctx context.Context, jid types.JID, to, body, buttonText string, listSections []*waE2E.ListMessage_Section, sectionCount int) (*whatsmeow.SendResponse, error) {
-	listMsg := &waE2E.ListMessage{
-		Title:       &body,
-		Description: &body,
-		ButtonText:  &buttonText,
-		ListType:    waE2E.ListMessage_SINGLE_SELECT.Enum(),
-		Sections:    listSections,
-	}
-
+// sendListMessage sends the native list message
+func (c *WameowClient) sendListMessage(ctx context.Context, jid types.JID, to string, listMsg *waE2E.ListMessage) (*whatsmeow.SendResponse, error) {
 	message := &waE2E.Message{
 		ViewOnceMessage: &waE2E.FutureProofMessage{
 			Message: &waE2E.Message{
@@ -1484,11 +1595,11 @@ func (c *WameowClient) sendListMessage(ctx context.Context, jid types.JID, to, b
 	c.logger.InfoWithFields("Sending list message", map[string]interface{}{
 		"session_id":    c.sessionID,
 		"to":            to,
-		"section_count": sectionCount,
-		"body_length":   len(body),
+		"section_count": len(listMsg.GetSections()),
+		"body_length":   len(listMsg.GetDescription()),
 	})
 
-	resp, err := c.client.SendMessage(ctx, jid, message)
+	resp, err := c.sendMessage(ctx, jid, message)
 	if err != nil {
 		c.logger.ErrorWithFields("Failed to send list message", map[string]interface{}{
 			"session_id": c.sessionID,
@@ -1507,10 +1618,42 @@ func (c *WameowClient) sendListMessage(ctx context.Context, jid types.JID, to, b
 	return &resp, nil
 }
 
-func (c *WameowClient) SendReaction(ctx context.Context, to, messageID, reaction string) error {
+// sendListAsText sends a list message as numbered plain text
+func (c *WameowClient) sendListAsText(ctx context.Context, jid types.JID, to string, listMsg *waE2E.ListMessage) (*whatsmeow.SendResponse, error) {
+	text := listMessageText(listMsg)
+	message := &waE2E.Message{
+		ExtendedTextMessage: &waE2E.ExtendedTextMessage{
+			Text: &text,
+		},
+	}
+
+	c.logger.InfoWithFields("Sending list message as text", map[string]interface{}{
+		"session_id":    c.sessionID,
+		"to":            to,
+		"section_count": len(listMsg.GetSections()),
+	})
+
+	resp, err := c.sendMessage(ctx, jid, message)
+	if err != nil {
+		c.logger.ErrorWithFields("Failed to send list message as text", map[string]interface{}{
+			"session_id": c.sessionID,
+			"to":         to,
+			"error":      err.Error(),
+		})
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// SendReaction reacts to a message; participant is the original sender in groups and fromMe marks our own messages
+func (c *WameowClient) SendReaction(ctx context.Context, to, messageID, reaction, participant string, fromMe bool) error {
 	if !c.client.IsLoggedIn() {
 		return fmt.Errorf("client is not logged in")
 	}
+	if err := c.checkSendGate(); err != nil {
+		return err
+	}
 
 	jid, err := c.parseJID(to)
 	if err != nil {
@@ -1521,14 +1664,21 @@ func (c *WameowClient) SendReaction(ctx context.Context, to, messageID, reaction
 		return fmt.Errorf("message ID is required")
 	}
 
+	sender, err := c.resolveMessageSender(jid, participant, fromMe)
+	if err != nil {
+		return err
+	}
+
 	c.logger.InfoWithFields("Sending reaction", map[string]interface{}{
-		"session_id": c.sessionID,
-		"to":         to,
-		"message_id": messageID,
-		"reaction":   reaction,
+		"session_id":  c.sessionID,
+		"to":          to,
+		"message_id":  messageID,
+		"reaction":    reaction,
+		"participant": participant,
+		"from_me":     fromMe,
 	})
 
-	message := c.client.BuildReaction(jid, jid, types.MessageID(messageID), reaction)
+	message := c.client.BuildReaction(jid, sender, types.MessageID(messageID), reaction)
 
 	_, err = c.client.SendMessage(ctx, jid, message)
 	if err != nil {
@@ -1555,6 +1705,9 @@ func (c *WameowClient) SendPresence(ctx context.Context, to, presence string) er
 	if !c.client.IsLoggedIn() {
 		return fmt.Errorf("client is not logged in")
 	}
+	if err := c.checkSendGate(); err != nil {
+		return err
+	}
 
 	jid, err := c.parseJID(to)
 	if err != nil {
@@ -1599,18 +1752,22 @@ func (c *WameowClient) SendPresence(ctx context.Context, to, presence string) er
 	return nil
 }
 
-func (c *WameowClient) EditMessage(ctx context.Context, to, messageID, newText string) error {
+// EditMessage replaces the text of one of our own messages and returns the edit timestamp
+func (c *WameowClient) EditMessage(ctx context.Context, to, messageID, newText string) (time.Time, error) {
 	if !c.client.IsLoggedIn() {
-		return fmt.Errorf("client is not logged in")
+		return time.Time{}, fmt.Errorf("client is not logged in")
+	}
+	if err := c.checkSendGate(); err != nil {
+		return time.Time{}, err
 	}
 
 	jid, err := c.parseJID(to)
 	if err != nil {
-		return fmt.Errorf("invalid JID: %w", err)
+		return time.Time{}, fmt.Errorf("invalid JID: %w", err)
 	}
 
 	if messageID == "" {
-		return fmt.Errorf("message ID is required")
+		return time.Time{}, fmt.Errorf("message ID is required")
 	}
 
 	c.logger.InfoWithFields("Editing message", map[string]interface{}{
@@ -1620,17 +1777,12 @@ func (c *WameowClient) EditMessage(ctx context.Context, to, messageID, newText s
 		"new_text":   newText,
 	})
 
-	// Create the new message content (following  implementation)
-	newMessage := &waE2E.Message{
-		ExtendedTextMessage: &waE2E.ExtendedTextMessage{
-			Text: &newText,
-		},
-	}
-
-	// Use whatsmeow's BuildEdit method (like  does)
-	editMessage := c.client.BuildEdit(jid, messageID, newMessage)
+	editMessage := c.client.BuildEdit(jid.ToNonAD(), messageID, &waE2E.Message{
+		Conversation: proto.String(newText),
+	})
+	editedAt := time.UnixMilli(editMessage.GetEditedMessage().GetMessage().GetProtocolMessage().GetTimestampMS())
 
-	_, err = c.client.SendMessage(ctx, jid, editMessage)
+	resp, err := c.client.SendMessage(ctx, jid, editMessage)
 	if err != nil {
 		c.logger.ErrorWithFields("Failed to edit message", map[string]interface{}{
 			"session_id": c.sessionID,
@@ -1638,23 +1790,30 @@ func (c *WameowClient) EditMessage(ctx context.Context, to, messageID, newText s
 			"message_id": messageID,
 			"error":      err.Error(),
 		})
-		return err
+		return time.Time{}, err
 	}
 
+	c.recordSentMessage(jid, resp, editMessage)
+
 	c.logger.InfoWithFields("Message edited successfully", map[string]interface{}{
 		"session_id": c.sessionID,
 		"to":         to,
 		"message_id": messageID,
+		"edited_at":  editedAt.Unix(),
 	})
 
-	return nil
+	return editedAt, nil
 }
 
-// RevokeMessage revokes a message using whatsmeow's BuildRevoke method
-func (c *WameowClient) RevokeMessage(ctx context.Context, to, messageID string) error {
+// RevokeMessage revokes a message for everyone using whatsmeow's BuildRevoke method.
+// Own messages are revoked when participant is empty; group admins can revoke other members' messages by passing their JID.
+func (c *WameowClient) RevokeMessage(ctx context.Context, to, messageID, participant string) error {
 	if !c.client.IsLoggedIn() {
 		return fmt.Errorf("client is not logged in")
 	}
+	if err := c.checkSendGate(); err != nil {
+		return err
+	}
 
 	jid, err := c.parseJID(to)
 	if err != nil {
@@ -1665,16 +1824,22 @@ func (c *WameowClient) RevokeMessage(ctx context.Context, to, messageID string)
 		return fmt.Errorf("message ID is required")
 	}
 
+	sender, err := c.resolveMessageSender(jid, participant, participant == "")
+	if err != nil {
+		return err
+	}
+
 	c.logger.InfoWithFields("Revoking message", map[string]interface{}{
-		"session_id": c.sessionID,
-		"to":         to,
-		"message_id": messageID,
+		"session_id":  c.sessionID,
+		"to":          to,
+		"message_id":  messageID,
+		"participant": participant,
 	})
 
 	// Use whatsmeow's BuildRevoke method to create a revoke message (following  implementation)
-	message := c.client.BuildRevoke(jid, types.EmptyJID, messageID)
+	message := c.client.BuildRevoke(jid, sender, messageID)
 
-	_, err = c.client.SendMessage(ctx, jid, message)
+	resp, err := c.client.SendMessage(ctx, jid, message)
 	if err != nil {
 		c.logger.ErrorWithFields("Failed to revoke message", map[string]interface{}{
 			"session_id": c.sessionID,
@@ -1685,6 +1850,8 @@ func (c *WameowClient) RevokeMessage(ctx context.Context, to, messageID string)
 		return err
 	}
 
+	c.recordSentMessage(jid, resp, message)
+
 	c.logger.InfoWithFields("Message revoked successfully", map[string]interface{}{
 		"session_id": c.sessionID,
 		"to":         to,
@@ -1694,6 +1861,123 @@ func (c *WameowClient) RevokeMessage(ctx context.Context, to, messageID string)
 	return nil
 }
 
+// DeleteMessageForMe removes a message only from this account's devices by sending a deleteMessageForMe app state patch
+func (c *WameowClient) DeleteMessageForMe(ctx context.Context, to, messageID, participant string, fromMe bool, timestamp time.Time, deleteMedia bool) error {
+	if !c.client.IsLoggedIn() {
+		return fmt.Errorf("client is not logged in")
+	}
+	if err := c.checkSendGate(); err != nil {
+		return err
+	}
+
+	jid, err := c.parseJID(to)
+	if err != nil {
+		return fmt.Errorf("invalid JID: %w", err)
+	}
+
+	if messageID == "" {
+		return fmt.Errorf("message ID is required")
+	}
+
+	// The sender index is "0" for own messages and 1:1 chats, otherwise the participant JID
+	senderIndex := "0"
+	if !fromMe && jid.Server == types.GroupServer {
+		if participant == "" {
+			return fmt.Errorf("participant is required for messages from other group members")
+		}
+		sender, err := c.parseJID(participant)
+		if err != nil {
+			return fmt.Errorf("invalid participant JID: %w", err)
+		}
+		senderIndex = sender.ToNonAD().String()
+	}
+
+	fromMeIndex := "0"
+	if fromMe {
+		fromMeIndex = "1"
+	}
+
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	c.logger.InfoWithFields("Deleting message for me", map[string]interface{}{
+		"session_id":   c.sessionID,
+		"to":           to,
+		"message_id":   messageID,
+		"participant":  participant,
+		"from_me":      fromMe,
+		"delete_media": deleteMedia,
+	})
+
+	patch := appstate.PatchInfo{
+		Type: appstate.WAPatchRegularHigh,
+		Mutations: []appstate.MutationInfo{{
+			Index:   []string{appstate.IndexDeleteMessageForMe, jid.ToNonAD().String(), messageID, fromMeIndex, senderIndex},
+			Version: 3,
+			Value: &waSyncAction.SyncActionValue{
+				DeleteMessageForMeAction: &waSyncAction.DeleteMessageForMeAction{
+					DeleteMedia:      proto.Bool(deleteMedia),
+					MessageTimestamp: proto.Int64(timestamp.Unix()),
+				},
+			},
+		}},
+	}
+
+	if err := c.client.SendAppState(ctx, patch); err != nil {
+		c.logger.ErrorWithFields("Failed to delete message for me", map[string]interface{}{
+			"session_id": c.sessionID,
+			"to":         to,
+			"message_id": messageID,
+			"error":      err.Error(),
+		})
+		return err
+	}
+
+	c.logger.InfoWithFields("Message deleted for me successfully", map[string]interface{}{
+		"session_id": c.sessionID,
+		"to":         to,
+		"message_id": messageID,
+	})
+
+	return nil
+}
+
+// LabelChat adds or removes a WhatsApp Business label on a chat by sending a label association app state patch
+func (c *WameowClient) LabelChat(ctx context.Context, chat, labelID string, labeled bool) error {
+	if !c.client.IsLoggedIn() {
+		return fmt.Errorf("client is not logged in")
+	}
+	if err := c.checkSendGate(); err != nil {
+		return err
+	}
+
+	jid, err := c.parseJID(chat)
+	if err != nil {
+		return fmt.Errorf("invalid JID: %w", err)
+	}
+
+	if err := c.client.SendAppState(ctx, appstate.BuildLabelChat(jid.ToNonAD(), labelID, labeled)); err != nil {
+		c.logger.ErrorWithFields("Failed to update chat label", map[string]interface{}{
+			"session_id": c.sessionID,
+			"chat":       chat,
+			"label_id":   labelID,
+			"labeled":    labeled,
+			"error":      err.Error(),
+		})
+		return err
+	}
+
+	c.logger.DebugWithFields("Chat label updated", map[string]interface{}{
+		"session_id": c.sessionID,
+		"chat":       chat,
+		"label_id":   labelID,
+		"labeled":    labeled,
+	})
+
+	return nil
+}
+
 // IsOnWhatsApp checks if phone numbers are registered on WhatsApp
 func (c *WameowClient) IsOnWhatsApp(ctx context.Context, phoneNumbers []string) (map[string]interface{}, error) {
 	if !c.client.IsLoggedIn() {
@@ -1938,45 +2222,63 @@ func (c *WameowClient) GetAllContacts(ctx context.Context) (map[string]interface
 	return result, nil
 }
 
-func (c *WameowClient) MarkRead(ctx context.Context, to, messageID string) error {
+// MarkRead sends a single read receipt for messages in a chat.
+// In groups all messages must come from the same sender, which is required.
+func (c *WameowClient) MarkRead(ctx context.Context, to string, messageIDs []string, sender string) error {
 	if !c.client.IsLoggedIn() {
 		return fmt.Errorf("client is not logged in")
 	}
+	if err := c.checkSendGate(); err != nil {
+		return err
+	}
 
 	jid, err := c.parseJID(to)
 	if err != nil {
 		return fmt.Errorf("invalid JID: %w", err)
 	}
 
-	if messageID == "" {
+	if len(messageIDs) == 0 {
 		return fmt.Errorf("message ID is required")
 	}
 
-	c.logger.InfoWithFields("Marking message as read", map[string]interface{}{
-		"session_id": c.sessionID,
-		"to":         to,
-		"message_id": messageID,
+	senderJID := types.EmptyJID
+	if sender != "" {
+		senderJID, err = c.parseJID(sender)
+		if err != nil {
+			return fmt.Errorf("invalid sender JID: %w", err)
+		}
+	} else if jid.Server == types.GroupServer {
+		return fmt.Errorf("sender is required for group messages")
+	}
+
+	c.logger.InfoWithFields("Marking messages as read", map[string]interface{}{
+		"session_id":  c.sessionID,
+		"to":          to,
+		"sender":      sender,
+		"message_ids": messageIDs,
 	})
 
-	// Convert messageID string to types.MessageID
-	msgID := types.MessageID(messageID)
+	ids := make([]types.MessageID, len(messageIDs))
+	for i, messageID := range messageIDs {
+		ids[i] = types.MessageID(messageID)
+	}
 
 	// MarkRead expects a slice of message IDs, timestamp, chat JID, sender JID, and optional receipt type
-	err = c.client.MarkRead([]types.MessageID{msgID}, time.Now(), jid, jid, "")
+	err = c.client.MarkRead(ids, time.Now(), jid, senderJID)
 	if err != nil {
-		c.logger.ErrorWithFields("Failed to mark message as read", map[string]interface{}{
-			"session_id": c.sessionID,
-			"to":         to,
-			"message_id": messageID,
-			"error":      err.Error(),
+		c.logger.ErrorWithFields("Failed to mark messages as read", map[string]interface{}{
+			"session_id":  c.sessionID,
+			"to":          to,
+			"message_ids": messageIDs,
+			"error":       err.Error(),
 		})
 		return err
 	}
 
-	c.logger.InfoWithFields("Message marked as read successfully", map[string]interface{}{
+	c.logger.InfoWithFields("Messages marked as read successfully", map[string]interface{}{
 		"session_id": c.sessionID,
 		"to":         to,
-		"message_id": messageID,
+		"count":      len(messageIDs),
 	})
 
 	return nil
@@ -2476,7 +2778,7 @@ func (c *WameowClient) CreatePoll(ctx context.Context, to, name string, options
 	pollMessage := c.client.BuildPollCreation(name, options, selectableCount)
 
 	// Send the poll
-	resp, err := c.client.SendMessage(ctx, toJID, pollMessage)
+	resp, err := c.sendMessage(ctx, toJID, pollMessage)
 	if err != nil {
 		c.logger.ErrorWithFields("Failed to send poll", map[string]interface{}{
 			"session_id": c.sessionID,
@@ -2501,7 +2803,9 @@ func (c *WameowClient) CreatePoll(ctx context.Context, to, name string, options
 }
 
 // VotePoll votes in a poll
-func (c *WameowClient) VotePoll(ctx context.Context, to, pollMessageID string, selectedOptions []string) (*types.MessageInfo, error) {
+// VotePoll votes on a poll in chat to; participant is the poll creator in groups and fromMe marks our own polls.
+// The vote is encrypted with the secret of the poll message, so the poll must have been received by this device.
+func (c *WameowClient) VotePoll(ctx context.Context, to, pollMessageID, participant string, fromMe bool, selectedOptions []string) (*whatsmeow.SendResponse, error) {
 	if !c.client.IsLoggedIn() {
 		return nil, fmt.Errorf("client is not logged in")
 	}
@@ -2514,18 +2818,56 @@ func (c *WameowClient) VotePoll(ctx context.Context, to, pollMessageID string, s
 		return nil, fmt.Errorf("at least one option must be selected")
 	}
 
-	c.logger.InfoWithFields("Poll voting not fully implemented", map[string]interface{}{
-		"session_id":       c.sessionID,
-		"to":               to,
-		"poll_message_id":  pollMessageID,
-		"selected_options": selectedOptions,
+	jid, err := c.parseJID(to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JID: %w", err)
+	}
+
+	sender, err := c.resolveMessageSender(jid, participant, fromMe)
+	if err != nil {
+		return nil, err
+	}
+	if fromMe {
+		sender = c.client.Store.ID.ToNonAD()
+	}
+
+	pollInfo := &types.MessageInfo{
+		MessageSource: types.MessageSource{
+			Chat:     jid,
+			Sender:   sender,
+			IsFromMe: fromMe,
+			IsGroup:  jid.Server == types.GroupServer,
+		},
+		ID: types.MessageID(pollMessageID),
+	}
+
+	message, err := c.client.BuildPollVote(ctx, pollInfo, selectedOptions)
+	if errors.Is(err, whatsmeow.ErrOriginalMessageSecretNotFound) {
+		return nil, fmt.Errorf("%w: poll message %s was not received by this device", poll.ErrPollNotFound, pollMessageID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to build poll vote: %w", err)
+	}
+
+	c.logger.InfoWithFields("Sending poll vote", map[string]interface{}{
+		"session_id":      c.sessionID,
+		"to":              to,
+		"poll_message_id": pollMessageID,
+		"options":         len(selectedOptions),
 	})
 
-	// Return a mock response for now since poll voting requires complex message handling
-	return &types.MessageInfo{
-		ID:        "mock-vote-" + pollMessageID,
-		Timestamp: time.Now(),
-	}, nil
+	resp, err := c.sendMessage(ctx, jid, message)
+	if err != nil {
+		c.logger.ErrorWithFields("Failed to send poll vote", map[string]interface{}{
+			"session_id":      c.sessionID,
+			"to":              to,
+			"poll_message_id": pollMessageID,
+			"error":           err.Error(),
+		})
+		return nil, err
+	}
+
+	return &resp, nil
 }
 
 // SetGroupPhoto sets a group's photo
@@ -3366,6 +3708,9 @@ func (c *WameowClient) NewsletterSendReaction(ctx context.Context, jid string, s
 	if !c.client.IsLoggedIn() {
 		return fmt.Errorf("client is not logged in")
 	}
+	if err := c.checkSendGate(); err != nil {
+		return err
+	}
 
 	if jid == "" {
 		return fmt.Errorf("newsletter JID cannot be empty")
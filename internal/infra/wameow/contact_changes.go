@@ -0,0 +1,76 @@
+package wameow
+
+import (
+	"context"
+	"time"
+
+	"go.mau.fi/whatsmeow/types"
+
+	"zpwoot/internal/domain/contact"
+)
+
+// ContactUpdatedEventType is the webhook event emitted for each entry of the contact change feed
+const ContactUpdatedEventType = "contact.updated"
+
+// contactChangeTimeout bounds storing one contact change
+const contactChangeTimeout = 5 * time.Second
+
+// ContactUpdated reports a new pushname, business name, avatar or address book entry of a contact
+type ContactUpdated struct {
+	ContactJID      string    `json:"contactJid"`
+	ChangeType      string    `json:"changeType"`
+	PushName        string    `json:"pushName,omitempty"`
+	OldPushName     string    `json:"oldPushName,omitempty"`
+	BusinessName    string    `json:"businessName,omitempty"`
+	OldBusinessName string    `json:"oldBusinessName,omitempty"`
+	FullName        string    `json:"fullName,omitempty"`
+	FirstName       string    `json:"firstName,omitempty"`
+	PictureID       string    `json:"pictureId,omitempty"`
+	PictureRemoved  bool      `json:"pictureRemoved,omitempty"`
+	FromFullSync    bool      `json:"fromFullSync,omitempty"`
+	Timestamp       time.Time `json:"timestamp"`
+}
+
+// WebhookEventType implements webhookEventNamer
+func (c *ContactUpdated) WebhookEventType() string {
+	return ContactUpdatedEventType
+}
+
+// publishContactChange records the change in the session's contact change feed and delivers it as a
+// contact.updated event
+func (h *EventHandler) publishContactChange(sessionID string, update *ContactUpdated) {
+	if update.Timestamp.IsZero() {
+		update.Timestamp = time.Now()
+	}
+
+	if h.manager != nil && h.manager.contactFeed != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), contactChangeTimeout)
+		err := h.manager.contactFeed.Record(ctx, &contact.Change{
+			SessionID:      sessionID,
+			ContactJID:     update.ContactJID,
+			Type:           contact.ChangeType(update.ChangeType),
+			PushName:       update.PushName,
+			BusinessName:   update.BusinessName,
+			FullName:       update.FullName,
+			FirstName:      update.FirstName,
+			PictureID:      update.PictureID,
+			PictureRemoved: update.PictureRemoved,
+		})
+		cancel()
+		if err != nil {
+			h.logger.WarnWithFields("Failed to record contact change", map[string]interface{}{
+				"session_id":  sessionID,
+				"contact_jid": update.ContactJID,
+				"change_type": update.ChangeType,
+				"error":       err.Error(),
+			})
+		}
+	}
+
+	h.deliverToWebhook(update, sessionID)
+}
+
+// isContactJID reports whether the JID is a user, so group and newsletter pictures stay out of the feed
+func isContactJID(jid types.JID) bool {
+	return jid.Server == types.DefaultUserServer || jid.Server == types.HiddenUserServer
+}
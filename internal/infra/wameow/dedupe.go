@@ -0,0 +1,34 @@
+package wameow
+
+import (
+	"time"
+
+	"zpwoot/platform/ttlset"
+)
+
+// DefaultInboundDedupeTTL is how long an inbound message ID is remembered per session
+const DefaultInboundDedupeTTL = 10 * time.Minute
+
+// InboundDeduplicator remembers recently seen inbound message IDs per session.
+// whatsmeow can replay events after a reconnection, and each message must only be fanned out once.
+type InboundDeduplicator struct {
+	seen *ttlset.Set
+}
+
+// NewInboundDeduplicator creates a new deduplicator with the given TTL
+func NewInboundDeduplicator(ttl time.Duration) *InboundDeduplicator {
+	if ttl <= 0 {
+		ttl = DefaultInboundDedupeTTL
+	}
+
+	return &InboundDeduplicator{seen: ttlset.New(ttl)}
+}
+
+// Seen records the message and reports whether it was already seen within the TTL
+func (d *InboundDeduplicator) Seen(sessionID, messageID string) bool {
+	if messageID == "" {
+		return false
+	}
+
+	return !d.seen.Add(sessionID + ":" + messageID)
+}
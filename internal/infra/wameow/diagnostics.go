@@ -0,0 +1,148 @@
+package wameow
+
+import (
+	"fmt"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"zpwoot/internal/domain/session"
+)
+
+// maxRecentErrors bounds the per-session error history kept for diagnostics
+const maxRecentErrors = 20
+
+// ClientError is an error seen by a session's client, kept for diagnostics
+type ClientError struct {
+	Operation  string    `json:"operation"`
+	Error      string    `json:"error"`
+	OccurredAt time.Time `json:"occurredAt"`
+}
+
+// SessionDiagnostics is a point-in-time snapshot of a session's client internals
+type SessionDiagnostics struct {
+	SessionID        string             `json:"sessionId"`
+	Status           string             `json:"status"`
+	Connected        bool               `json:"connected"`
+	LoggedIn         bool               `json:"loggedIn"`
+	JID              string             `json:"jid,omitempty"`
+	LastActivity     time.Time          `json:"lastActivity"`
+	QRLoopActive     bool               `json:"qrLoopActive"`
+	EventHandlers    int                `json:"eventHandlers"`
+	PendingReceipts  int                `json:"pendingReceipts"`
+	HistoryBackfill  bool               `json:"historyBackfill"`
+	SyncState        *session.SyncState `json:"syncState,omitempty"`
+	MessagesSent     int64              `json:"messagesSent"`
+	MessagesReceived int64              `json:"messagesReceived"`
+	RecentErrors     []ClientError      `json:"recentErrors"`
+}
+
+// GetDiagnostics returns a diagnostic snapshot of one session's client
+func (m *Manager) GetDiagnostics(sessionID string) (*SessionDiagnostics, error) {
+	client := m.getClient(sessionID)
+	if client == nil {
+		return nil, fmt.Errorf("session %s not found", sessionID)
+	}
+	return m.diagnosticsFor(client), nil
+}
+
+// ListDiagnostics returns diagnostic snapshots of every loaded session, ordered by session ID
+func (m *Manager) ListDiagnostics() []*SessionDiagnostics {
+	m.clientsMutex.RLock()
+	clients := make([]*WameowClient, 0, len(m.clients))
+	for _, client := range m.clients {
+		clients = append(clients, client)
+	}
+	m.clientsMutex.RUnlock()
+
+	snapshots := make([]*SessionDiagnostics, 0, len(clients))
+	for _, client := range clients {
+		snapshots = append(snapshots, m.diagnosticsFor(client))
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].SessionID < snapshots[j].SessionID
+	})
+	return snapshots
+}
+
+func (m *Manager) diagnosticsFor(client *WameowClient) *SessionDiagnostics {
+	snapshot := client.diagnostics()
+
+	m.handlersMutex.RLock()
+	snapshot.EventHandlers = len(m.eventHandlers[client.sessionID])
+	m.handlersMutex.RUnlock()
+
+	snapshot.HistoryBackfill = m.historyBackfills.active(client.sessionID)
+	if snapshot.Connected {
+		snapshot.SyncState = m.phoneSync.get(client.sessionID)
+	}
+
+	m.statsMutex.RLock()
+	stats, exists := m.sessionStats[client.sessionID]
+	m.statsMutex.RUnlock()
+	if exists {
+		snapshot.MessagesSent = atomic.LoadInt64(&stats.MessagesSent)
+		snapshot.MessagesReceived = atomic.LoadInt64(&stats.MessagesReceived)
+	}
+
+	return snapshot
+}
+
+func (c *WameowClient) diagnostics() *SessionDiagnostics {
+	c.mu.RLock()
+	status, lastActivity := c.status, c.lastActivity
+	c.mu.RUnlock()
+
+	c.qrState.mu.RLock()
+	qrLoopActive := c.qrState.loopActive
+	c.qrState.mu.RUnlock()
+
+	snapshot := &SessionDiagnostics{
+		SessionID:       c.sessionID,
+		Status:          status,
+		Connected:       c.client.IsConnected(),
+		LoggedIn:        c.client.IsLoggedIn(),
+		LastActivity:    lastActivity,
+		QRLoopActive:    qrLoopActive,
+		PendingReceipts: c.metrics.pendingCount(),
+		RecentErrors:    c.metrics.recentErrorsSnapshot(),
+	}
+	if jid := c.GetJID(); !jid.IsEmpty() {
+		snapshot.JID = jid.String()
+	}
+	return snapshot
+}
+
+// recordError keeps the error in the session's bounded error history
+func (m *clientMetrics) recordError(operation, message string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.recentErrors) >= maxRecentErrors {
+		m.recentErrors = append(m.recentErrors[:0], m.recentErrors[1:]...)
+	}
+	m.recentErrors = append(m.recentErrors, ClientError{
+		Operation:  operation,
+		Error:      message,
+		OccurredAt: time.Now(),
+	})
+}
+
+// recentErrorsSnapshot returns the error history, newest first
+func (m *clientMetrics) recentErrorsSnapshot() []ClientError {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	errs := make([]ClientError, 0, len(m.recentErrors))
+	for i := len(m.recentErrors) - 1; i >= 0; i-- {
+		errs = append(errs, m.recentErrors[i])
+	}
+	return errs
+}
+
+func (m *clientMetrics) pendingCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.pending)
+}
@@ -7,8 +7,12 @@ import (
 	"strings"
 	"time"
 
+	"zpwoot/internal/domain/contact"
 	"zpwoot/platform/logger"
 
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/proto/waHistorySync"
+	"go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
 )
 
@@ -26,7 +30,8 @@ const (
 
 // WebhookEventHandler defines interface for handling webhook events
 type WebhookEventHandler interface {
-	HandleWhatsmeowEvent(evt interface{}, sessionID string) error
+	// HandleWhatsmeowEvent receives a context carrying the ID of the API request that triggered the event, if any
+	HandleWhatsmeowEvent(ctx context.Context, evt interface{}, sessionID string) error
 }
 
 type EventHandler struct {
@@ -41,7 +46,9 @@ type EventHandler struct {
 // ChatwootManager interface for Chatwoot integration
 type ChatwootManager interface {
 	IsEnabled(sessionID string) bool
-	ProcessWhatsAppMessage(sessionID, messageID, from, content, messageType string, timestamp time.Time, fromMe bool) error
+	ProcessWhatsAppMessage(ctx context.Context, sessionID, messageID, from, content, messageType string, timestamp time.Time, fromMe bool, quotedMessageID string) error
+	ProcessReadReceipt(ctx context.Context, sessionID string, messageIDs []string) error
+	ProcessMessageRevoke(ctx context.Context, sessionID, messageID string) error
 }
 
 func NewEventHandler(manager *Manager, sessionMgr SessionUpdater, qrGen *QRCodeGenerator, logger *logger.Logger) *EventHandler {
@@ -59,9 +66,26 @@ func (h *EventHandler) SetChatwootManager(chatwootManager ChatwootManager) {
 }
 
 func (h *EventHandler) HandleEvent(evt interface{}, sessionID string) {
+	// Drop replayed messages before they reach webhooks, storage or Chatwoot
+	if h.isDuplicateMessage(evt, sessionID) {
+		return
+	}
+
+	// Messages of flooding contacts are still stored, but kept from webhooks and Chatwoot during their cooldown
+	if h.isFloodSuppressed(evt, sessionID) {
+		h.storeMessage(evt.(*events.Message), sessionID)
+		return
+	}
+
 	// First, deliver to webhook if configured
 	h.deliverToWebhook(evt, sessionID)
 
+	h.trackPhoneSync(evt, sessionID)
+
+	if warning := parseAccountWarning(evt); warning != nil {
+		h.handleAccountWarning(warning, sessionID)
+	}
+
 	// Then handle the event internally
 	switch v := evt.(type) {
 	case *events.Connected:
@@ -70,6 +94,8 @@ func (h *EventHandler) HandleEvent(evt interface{}, sessionID string) {
 		h.handleDisconnected(v, sessionID)
 	case *events.LoggedOut:
 		h.handleLoggedOut(v, sessionID)
+	case *events.StreamReplaced:
+		h.handleStreamReplaced(v, sessionID)
 	case *events.QR:
 		// QR events are handled by client QR channel to avoid duplication
 		// All QR code processing is done through client.handleQREvent()
@@ -81,6 +107,22 @@ func (h *EventHandler) HandleEvent(evt interface{}, sessionID string) {
 	case *events.PairError:
 		h.handlePairError(v, sessionID)
 	case *events.Message:
+		if v.Message.GetPollUpdateMessage() != nil {
+			h.handlePollVote(v, sessionID)
+		}
+		if v.Message.GetListResponseMessage() != nil {
+			h.handleListResponse(v, sessionID)
+		}
+		if v.Message.GetPinInChatMessage() != nil {
+			h.handleMessagePin(v, sessionID)
+		}
+		if v.Message.GetProtocolMessage().GetType() == waE2E.ProtocolMessage_EPHEMERAL_SETTING {
+			h.handleEphemeralSetting(v, sessionID)
+		}
+		if v.Message.GetInteractiveResponseMessage().GetNativeFlowResponseMessage() != nil {
+			h.handleFlowResponse(v, sessionID)
+		}
+		h.storeMessage(v, sessionID)
 		h.handleMessage(v, sessionID)
 	case *events.Receipt:
 		h.handleReceipt(v, sessionID)
@@ -126,6 +168,8 @@ func (h *EventHandler) HandleEvent(evt interface{}, sessionID string) {
 		h.handleOfflineSyncPreview(v, sessionID)
 	case *events.OfflineSyncCompleted:
 		h.handleOfflineSyncCompleted(v, sessionID)
+	case *events.NewsletterLiveUpdate:
+		h.handleNewsletterLiveUpdate(v, sessionID)
 	default:
 		h.logger.DebugWithFields("Unhandled event", map[string]interface{}{
 			"session_id": sessionID,
@@ -134,6 +178,32 @@ func (h *EventHandler) HandleEvent(evt interface{}, sessionID string) {
 	}
 }
 
+// storeMessage persists a received message, its poll definition and its stats
+func (h *EventHandler) storeMessage(evt *events.Message, sessionID string) {
+	h.recordReceivedPoll(evt, sessionID)
+	h.manager.recordReceivedMessage(sessionID, evt.Info, evt.Message)
+	recordMessageStats(h.manager.messageStats, sessionID, evt.Info.IsFromMe, evt.Info.Timestamp, evt.Message)
+}
+
+// isDuplicateMessage reports whether a message event was already handled for this session
+func (h *EventHandler) isDuplicateMessage(evt interface{}, sessionID string) bool {
+	msg, ok := evt.(*events.Message)
+	if !ok || h.manager == nil || h.manager.inboundDedupe == nil {
+		return false
+	}
+
+	if !h.manager.inboundDedupe.Seen(sessionID, msg.Info.ID) {
+		return false
+	}
+
+	h.logger.DebugWithFields("Duplicate message event dropped", map[string]interface{}{
+		"session_id": sessionID,
+		"message_id": msg.Info.ID,
+		"chat":       msg.Info.Chat.String(),
+	})
+	return true
+}
+
 func (h *EventHandler) handleConnected(evt *events.Connected, sessionID string) {
 	h.logger.InfoWithFields("Wameow connected", map[string]interface{}{
 		"session_id":   sessionID,
@@ -144,6 +214,16 @@ func (h *EventHandler) handleConnected(evt *events.Connected, sessionID string)
 	_ = evt
 
 	h.sessionMgr.UpdateConnectionStatus(sessionID, true)
+
+	if h.manager != nil && h.manager.offlineQueue != nil {
+		h.manager.offlineQueue.SessionConnected(sessionID)
+	}
+
+	h.renewPresenceSubscriptions(sessionID)
+
+	if h.manager != nil {
+		h.manager.reconnects.connected(sessionID)
+	}
 }
 
 func (h *EventHandler) handleDisconnected(evt *events.Disconnected, sessionID string) {
@@ -156,6 +236,26 @@ func (h *EventHandler) handleDisconnected(evt *events.Disconnected, sessionID st
 	_ = evt
 
 	h.sessionMgr.UpdateConnectionStatus(sessionID, false)
+
+	if h.manager != nil {
+		h.manager.superviseDrop(sessionID, ReconnectReasonDisconnected)
+	}
+}
+
+// handleStreamReplaced handles another client taking over the session's connection; whatsmeow does
+// not reconnect on its own nor report a Disconnected event for it
+func (h *EventHandler) handleStreamReplaced(evt *events.StreamReplaced, sessionID string) {
+	h.logger.WarnWithFields("Wameow stream replaced", map[string]interface{}{
+		"session_id": sessionID,
+	})
+
+	_ = evt
+
+	h.sessionMgr.UpdateConnectionStatus(sessionID, false)
+
+	if h.manager != nil {
+		h.manager.superviseDrop(sessionID, ReconnectReasonStreamReplaced)
+	}
 }
 
 func (h *EventHandler) handleLoggedOut(evt *events.LoggedOut, sessionID string) {
@@ -165,6 +265,11 @@ func (h *EventHandler) handleLoggedOut(evt *events.LoggedOut, sessionID string)
 	})
 
 	h.sessionMgr.UpdateConnectionStatus(sessionID, false)
+
+	// A logged out device cannot connect again without pairing
+	if h.manager != nil {
+		h.manager.reconnects.release(sessionID)
+	}
 }
 
 func (h *EventHandler) handleQR(evt *events.QR, sessionID string) {
@@ -201,6 +306,8 @@ func (h *EventHandler) handlePairSuccess(evt *events.PairSuccess, sessionID stri
 	h.updateSessionDeviceJID(sessionID, evt.ID.String())
 
 	h.clearSessionQRCode(sessionID)
+
+	h.manager.notifySessionPaired(sessionID)
 }
 
 func (h *EventHandler) handlePairError(evt *events.PairError, sessionID string) {
@@ -220,24 +327,27 @@ func (h *EventHandler) handleMessage(evt *events.Message, sessionID string) {
 		"timestamp":  evt.Info.Timestamp,
 	}
 
-	// Removed detailed message analysis for cleaner logs
+	// Sessions in privacy mode log the message without its content
+	redact := h.manager.redactsLogs(sessionID)
 
 	if evt.Message.ContactMessage != nil {
 		contactMsg := evt.Message.ContactMessage
 		messageInfo["message_type"] = MessageTypeContact
 
-		if contactMsg.DisplayName != nil {
+		if contactMsg.DisplayName != nil && !redact {
 			messageInfo["contact_display_name"] = *contactMsg.DisplayName
 		}
 
 		if contactMsg.Vcard != nil {
-			messageInfo["contact_vcard"] = *contactMsg.Vcard
+			if !redact {
+				messageInfo["contact_vcard"] = *contactMsg.Vcard
+			}
 			messageInfo["vcard_length"] = len(*contactMsg.Vcard)
 		}
 
 		h.logger.InfoWithFields("📞 CONTACT MESSAGE RECEIVED", messageInfo)
 
-		if contactMsg.Vcard != nil {
+		if contactMsg.Vcard != nil && !redact {
 			h.logger.InfoWithFields("📋 FULL VCARD CONTENT", map[string]interface{}{
 				"session_id": sessionID,
 				"from":       evt.Info.Sender.String(),
@@ -248,7 +358,7 @@ func (h *EventHandler) handleMessage(evt *events.Message, sessionID string) {
 		contactsMsg := evt.Message.ContactsArrayMessage
 		messageInfo["message_type"] = "contacts_array"
 
-		if contactsMsg.DisplayName != nil {
+		if contactsMsg.DisplayName != nil && !redact {
 			messageInfo["contacts_display_name"] = *contactsMsg.DisplayName
 		}
 
@@ -258,7 +368,7 @@ func (h *EventHandler) handleMessage(evt *events.Message, sessionID string) {
 
 		h.logger.InfoWithFields("📞📞📞 CONTACTS ARRAY MESSAGE RECEIVED", messageInfo)
 
-		if contactsMsg.Contacts != nil {
+		if contactsMsg.Contacts != nil && !redact {
 			for i, contact := range contactsMsg.Contacts {
 				contactInfo := map[string]interface{}{
 					"session_id":    sessionID,
@@ -296,7 +406,7 @@ func (h *EventHandler) handleMessage(evt *events.Message, sessionID string) {
 
 		messageInfo["message_type"] = messageType
 
-		if evt.Message.GetConversation() != "" {
+		if evt.Message.GetConversation() != "" && !redact {
 			messageInfo["text_content"] = evt.Message.GetConversation()
 		}
 
@@ -316,6 +426,12 @@ func (h *EventHandler) processChatwootIntegration(evt *events.Message, sessionID
 		return
 	}
 
+	// A revoke deletes the Chatwoot copy of the revoked message instead of being forwarded
+	if protocolMsg := evt.Message.GetProtocolMessage(); protocolMsg != nil && protocolMsg.GetType() == waE2E.ProtocolMessage_REVOKE {
+		h.processChatwootRevoke(evt, sessionID, protocolMsg.GetKey().GetID())
+		return
+	}
+
 	// Extract message information
 	messageID := evt.Info.ID
 	from := evt.Info.Sender.String()
@@ -350,70 +466,144 @@ func (h *EventHandler) processChatwootIntegration(evt *events.Message, sessionID
 	}
 
 	// Determine message type and content
+	messageType, content := describeMessage(evt.Message)
+
+	quotedMessageID := extractQuotedMessageID(evt.Message)
+
+	// Process the message with Chatwoot
+	// Use contactNumber which is the correct contact (sender for incoming, recipient for outgoing)
+	err := h.chatwootManager.ProcessWhatsAppMessage(h.eventContext(evt, sessionID), sessionID, messageID, contactNumber, content, messageType, timestamp, fromMe, quotedMessageID)
+	if err != nil {
+		h.logger.ErrorWithFields("Failed to process message for Chatwoot", map[string]interface{}{
+			"session_id": sessionID,
+			"message_id": messageID,
+			"error":      err.Error(),
+		})
+	} else {
+		h.logger.DebugWithFields("Message processed for Chatwoot", map[string]interface{}{
+			"session_id":   sessionID,
+			"message_id":   messageID,
+			"message_type": messageType,
+		})
+	}
+}
+
+// describeMessage returns the stored message type and a text summary of a WhatsApp message
+// processChatwootRevoke forwards a WhatsApp revoke to Chatwoot
+func (h *EventHandler) processChatwootRevoke(evt *events.Message, sessionID, revokedID string) {
+	if revokedID == "" {
+		return
+	}
+
+	if err := h.chatwootManager.ProcessMessageRevoke(h.eventContext(evt, sessionID), sessionID, revokedID); err != nil {
+		h.logger.WarnWithFields("Failed to sync revoked message to Chatwoot", map[string]interface{}{
+			"session_id": sessionID,
+			"message_id": revokedID,
+			"error":      err.Error(),
+		})
+	}
+}
+
+func describeMessage(msg *waE2E.Message) (string, string) {
 	messageType := MessageTypeText
 	content := ""
 
-	if evt.Message.ContactMessage != nil {
+	if msg.ContactMessage != nil {
 		messageType = MessageTypeContact
-		if evt.Message.ContactMessage.DisplayName != nil {
-			content = "Contact: " + *evt.Message.ContactMessage.DisplayName
+		if msg.ContactMessage.DisplayName != nil {
+			content = "Contact: " + *msg.ContactMessage.DisplayName
 		} else {
 			content = "Contact shared"
 		}
-	} else if evt.Message.ContactsArrayMessage != nil {
+	} else if msg.ContactsArrayMessage != nil {
 		messageType = "contacts"
-		content = fmt.Sprintf("Contacts shared (%d contacts)", len(evt.Message.ContactsArrayMessage.Contacts))
-	} else if evt.Message.ImageMessage != nil {
+		content = fmt.Sprintf("Contacts shared (%d contacts)", len(msg.ContactsArrayMessage.Contacts))
+	} else if msg.ImageMessage != nil {
 		messageType = "image"
-		if evt.Message.ImageMessage.Caption != nil {
-			content = *evt.Message.ImageMessage.Caption
+		if msg.ImageMessage.Caption != nil {
+			content = *msg.ImageMessage.Caption
 		} else {
 			content = "Image"
 		}
-	} else if evt.Message.AudioMessage != nil {
+	} else if msg.AudioMessage != nil {
 		messageType = "audio"
 		content = "Audio message"
-	} else if evt.Message.VideoMessage != nil {
+	} else if msg.VideoMessage != nil {
 		messageType = "video"
-		if evt.Message.VideoMessage.Caption != nil {
-			content = *evt.Message.VideoMessage.Caption
+		if msg.VideoMessage.Caption != nil {
+			content = *msg.VideoMessage.Caption
 		} else {
 			content = "Video"
 		}
-	} else if evt.Message.DocumentMessage != nil {
+	} else if msg.DocumentMessage != nil {
 		messageType = "document"
-		if evt.Message.DocumentMessage.Title != nil {
-			content = "Document: " + *evt.Message.DocumentMessage.Title
+		if msg.DocumentMessage.Title != nil {
+			content = "Document: " + *msg.DocumentMessage.Title
 		} else {
 			content = "Document"
 		}
-	} else if evt.Message.StickerMessage != nil {
+	} else if msg.StickerMessage != nil {
 		messageType = "sticker"
 		content = "Sticker"
-	} else if evt.Message.LocationMessage != nil {
+	} else if msg.LocationMessage != nil {
 		messageType = "location"
 		content = "Location shared"
-	} else if evt.Message.GetConversation() != "" {
+	} else if msg.ListMessage != nil {
+		messageType = "list"
+		content = msg.ListMessage.GetDescription()
+	} else if msg.ListResponseMessage != nil {
+		messageType = "list_response"
+		content = msg.ListResponseMessage.GetTitle()
+	} else if msg.InteractiveMessage != nil {
+		messageType = "interactive"
+		content = msg.InteractiveMessage.GetBody().GetText()
+	} else if msg.InteractiveResponseMessage != nil {
+		messageType = "interactive_response"
+		content = msg.InteractiveResponseMessage.GetBody().GetText()
+	} else if msg.TemplateMessage != nil {
+		messageType = "template"
+		content = msg.TemplateMessage.GetHydratedTemplate().GetHydratedContentText()
+	} else if msg.TemplateButtonReplyMessage != nil {
+		messageType = "template_reply"
+		content = msg.TemplateButtonReplyMessage.GetSelectedDisplayText()
+	} else if msg.GetConversation() != "" {
+		messageType = "text"
+		content = msg.GetConversation()
+	} else if msg.ExtendedTextMessage != nil {
 		messageType = "text"
-		content = evt.Message.GetConversation()
+		content = msg.ExtendedTextMessage.GetText()
 	}
 
-	// Process the message with Chatwoot
-	// Use contactNumber which is the correct contact (sender for incoming, recipient for outgoing)
-	err := h.chatwootManager.ProcessWhatsAppMessage(sessionID, messageID, contactNumber, content, messageType, timestamp, fromMe)
-	if err != nil {
-		h.logger.ErrorWithFields("Failed to process message for Chatwoot", map[string]interface{}{
-			"session_id": sessionID,
-			"message_id": messageID,
-			"error":      err.Error(),
-		})
-	} else {
-		h.logger.DebugWithFields("Message processed for Chatwoot", map[string]interface{}{
-			"session_id":   sessionID,
-			"message_id":   messageID,
-			"message_type": messageType,
-		})
+	return messageType, content
+}
+
+// extractQuotedMessageID returns the ID of the message being replied to, if any
+func extractQuotedMessageID(msg *waE2E.Message) string {
+	return messageContextInfo(msg).GetStanzaID()
+}
+
+// messageContextInfo returns the context info (reply, mentions, forwarding) of a message, if any
+func messageContextInfo(msg *waE2E.Message) *waE2E.ContextInfo {
+	if msg == nil {
+		return nil
 	}
+
+	switch {
+	case msg.ExtendedTextMessage != nil:
+		return msg.ExtendedTextMessage.GetContextInfo()
+	case msg.ImageMessage != nil:
+		return msg.ImageMessage.GetContextInfo()
+	case msg.VideoMessage != nil:
+		return msg.VideoMessage.GetContextInfo()
+	case msg.AudioMessage != nil:
+		return msg.AudioMessage.GetContextInfo()
+	case msg.DocumentMessage != nil:
+		return msg.DocumentMessage.GetContextInfo()
+	case msg.StickerMessage != nil:
+		return msg.StickerMessage.GetContextInfo()
+	}
+
+	return nil
 }
 
 func (h *EventHandler) handleReceipt(evt *events.Receipt, sessionID string) {
@@ -423,6 +613,32 @@ func (h *EventHandler) handleReceipt(evt *events.Receipt, sessionID string) {
 		"sender":     evt.Sender.String(),
 		"timestamp":  evt.Timestamp,
 	})
+
+	h.processChatwootReadReceipt(evt, sessionID)
+}
+
+// processChatwootReadReceipt forwards read receipts from the contact to Chatwoot
+func (h *EventHandler) processChatwootReadReceipt(evt *events.Receipt, sessionID string) {
+	if evt.Type != types.ReceiptTypeRead || evt.IsFromMe {
+		return
+	}
+
+	if h.chatwootManager == nil || !h.chatwootManager.IsEnabled(sessionID) {
+		return
+	}
+
+	messageIDs := make([]string, 0, len(evt.MessageIDs))
+	for _, id := range evt.MessageIDs {
+		messageIDs = append(messageIDs, string(id))
+	}
+
+	if err := h.chatwootManager.ProcessReadReceipt(h.eventContext(evt, sessionID), sessionID, messageIDs); err != nil {
+		h.logger.WarnWithFields("Failed to sync read receipt to Chatwoot", map[string]interface{}{
+			"session_id":  sessionID,
+			"message_ids": messageIDs,
+			"error":       err.Error(),
+		})
+	}
 }
 
 func (h *EventHandler) handlePresence(evt *events.Presence, sessionID string) {
@@ -445,8 +661,19 @@ func (h *EventHandler) handleChatPresence(evt *events.ChatPresence, sessionID st
 func (h *EventHandler) handleHistorySync(evt *events.HistorySync, sessionID string) {
 	h.logger.InfoWithFields("History sync", map[string]interface{}{
 		"session_id": sessionID,
+		"sync_type":  evt.Data.GetSyncType().String(),
 		"data_size":  len(evt.Data.String()), // Just log the data size for now
 	})
+
+	if h.manager == nil {
+		return
+	}
+
+	// An on-demand sync answers a backfill request, which stores the messages itself
+	if evt.Data.GetSyncType() == waHistorySync.HistorySync_ON_DEMAND && h.manager.historyBackfills.deliver(sessionID, evt.Data) {
+		return
+	}
+	h.manager.recordHistorySync(sessionID, evt.Data)
 }
 
 func (h *EventHandler) handleAppState(evt *events.AppState, sessionID string) {
@@ -481,6 +708,15 @@ func (h *EventHandler) handleContact(evt *events.Contact, sessionID string) {
 		"session_id": sessionID,
 		"jid":        evt.JID.String(),
 	})
+
+	h.publishContactChange(sessionID, &ContactUpdated{
+		ContactJID:   evt.JID.ToNonAD().String(),
+		ChangeType:   string(contact.ChangeTypeContact),
+		FullName:     evt.Action.GetFullName(),
+		FirstName:    evt.Action.GetFirstName(),
+		FromFullSync: evt.FromFullSync,
+		Timestamp:    evt.Timestamp,
+	})
 }
 
 func (h *EventHandler) handleGroupInfo(evt *events.GroupInfo, sessionID string) {
@@ -488,6 +724,10 @@ func (h *EventHandler) handleGroupInfo(evt *events.GroupInfo, sessionID string)
 		"session_id": sessionID,
 		"jid":        evt.JID.String(),
 	})
+
+	if evt.Ephemeral != nil {
+		h.handleGroupEphemeral(evt, sessionID)
+	}
 }
 
 func (h *EventHandler) handlePicture(evt *events.Picture, sessionID string) {
@@ -495,6 +735,17 @@ func (h *EventHandler) handlePicture(evt *events.Picture, sessionID string) {
 		"session_id": sessionID,
 		"jid":        evt.JID.String(),
 	})
+
+	if !isContactJID(evt.JID) {
+		return
+	}
+	h.publishContactChange(sessionID, &ContactUpdated{
+		ContactJID:     evt.JID.ToNonAD().String(),
+		ChangeType:     string(contact.ChangeTypePicture),
+		PictureID:      evt.PictureID,
+		PictureRemoved: evt.Remove,
+		Timestamp:      evt.Timestamp,
+	})
 }
 
 func (h *EventHandler) handleBusinessName(evt *events.BusinessName, sessionID string) {
@@ -502,6 +753,17 @@ func (h *EventHandler) handleBusinessName(evt *events.BusinessName, sessionID st
 		"session_id": sessionID,
 		"jid":        evt.JID.String(),
 	})
+
+	update := &ContactUpdated{
+		ContactJID:      evt.JID.ToNonAD().String(),
+		ChangeType:      string(contact.ChangeTypeBusinessName),
+		BusinessName:    evt.NewBusinessName,
+		OldBusinessName: evt.OldBusinessName,
+	}
+	if evt.Message != nil {
+		update.Timestamp = evt.Message.Timestamp
+	}
+	h.publishContactChange(sessionID, update)
 }
 
 func (h *EventHandler) handlePushName(evt *events.PushName, sessionID string) {
@@ -509,6 +771,17 @@ func (h *EventHandler) handlePushName(evt *events.PushName, sessionID string) {
 		"session_id": sessionID,
 		"jid":        evt.JID.String(),
 	})
+
+	update := &ContactUpdated{
+		ContactJID:  evt.JID.ToNonAD().String(),
+		ChangeType:  string(contact.ChangeTypePushName),
+		PushName:    evt.NewPushName,
+		OldPushName: evt.OldPushName,
+	}
+	if evt.Message != nil {
+		update.Timestamp = evt.Message.Timestamp
+	}
+	h.publishContactChange(sessionID, update)
 }
 
 func (h *EventHandler) handleArchive(evt *events.Archive, sessionID string) {
@@ -678,12 +951,33 @@ func (h *EventHandler) clearSessionQRCode(sessionID string) {
 	}
 }
 
+// handleNewsletterLiveUpdate delivers the view and reaction changes of a subscribed newsletter
+// as one newsletter.message webhook event per changed message
+func (h *EventHandler) handleNewsletterLiveUpdate(evt *events.NewsletterLiveUpdate, sessionID string) {
+	updates := h.manager.newsletterCounts.updates(sessionID, evt)
+
+	h.logger.DebugWithFields("Newsletter live update", map[string]interface{}{
+		"session_id":    sessionID,
+		"newsletter":    evt.JID.String(),
+		"messages":      len(evt.Messages),
+		"changed_count": len(updates),
+	})
+
+	for _, update := range updates {
+		h.deliverToWebhook(update, sessionID)
+	}
+}
+
 // getEventType extracts the event type name using reflection
 func getEventType(evt interface{}) string {
 	if evt == nil {
 		return "nil"
 	}
 
+	if namer, ok := evt.(webhookEventNamer); ok {
+		return namer.WebhookEventType()
+	}
+
 	eventType := reflect.TypeOf(evt)
 	if eventType.Kind() == reflect.Ptr {
 		eventType = eventType.Elem()
@@ -710,7 +1004,7 @@ func (h *EventHandler) deliverToWebhook(evt interface{}, sessionID string) {
 		return
 	}
 
-	if err := h.webhookHandler.HandleWhatsmeowEvent(evt, sessionID); err != nil {
+	if err := h.webhookHandler.HandleWhatsmeowEvent(h.eventContext(evt, sessionID), evt, sessionID); err != nil {
 		h.logger.ErrorWithFields("Failed to deliver event to webhook", map[string]interface{}{
 			"session_id": sessionID,
 			"event_type": getEventType(evt),
@@ -0,0 +1,168 @@
+package wameow
+
+import (
+	"sync"
+	"time"
+
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// FloodDetectedEventType is the webhook event emitted once when a contact starts flooding a session
+const FloodDetectedEventType = "flood.detected"
+
+// FloodConfig configures inbound flood protection: a contact sending more than MaxMessages within
+// Window is flooding, and stays so for Cooldown. MaxMessages 0 disables the protection.
+type FloodConfig struct {
+	MaxMessages int
+	Window      time.Duration
+	Cooldown    time.Duration
+	// Suppress keeps the contact's messages from webhooks and Chatwoot during the cooldown
+	Suppress bool
+}
+
+// FloodDetected reports a contact that exceeded the inbound message rate of a session
+type FloodDetected struct {
+	ContactJID    string    `json:"contactJid"`
+	ChatJID       string    `json:"chatJid"`
+	MessageCount  int       `json:"messageCount"`
+	WindowSeconds int       `json:"windowSeconds"`
+	Suppressed    bool      `json:"suppressed"`
+	CooldownUntil time.Time `json:"cooldownUntil"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+// WebhookEventType implements webhookEventNamer
+func (f *FloodDetected) WebhookEventType() string {
+	return FloodDetectedEventType
+}
+
+// FloodGuard tracks the recent inbound message times of each contact per session
+type FloodGuard struct {
+	config    FloodConfig
+	mu        sync.Mutex
+	contacts  map[string]*floodState
+	lastSweep time.Time
+}
+
+type floodState struct {
+	received      []time.Time
+	cooldownUntil time.Time
+}
+
+// NewFloodGuard creates a flood guard, or returns nil when the configuration disables it
+func NewFloodGuard(config FloodConfig) *FloodGuard {
+	if config.MaxMessages <= 0 || config.Window <= 0 {
+		return nil
+	}
+
+	return &FloodGuard{
+		config:    config,
+		contacts:  make(map[string]*floodState),
+		lastSweep: time.Now(),
+	}
+}
+
+// Record counts a message from the contact and reports whether the contact is flooding, and
+// whether this message is the one that started the flood
+func (g *FloodGuard) Record(sessionID, contactJID string, at time.Time) (flooding, started bool, count int) {
+	key := sessionID + ":" + contactJID
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.sweep(at)
+
+	state, exists := g.contacts[key]
+	if !exists {
+		state = &floodState{}
+		g.contacts[key] = state
+	}
+
+	if at.Before(state.cooldownUntil) {
+		return true, false, 0
+	}
+
+	cutoff := at.Add(-g.config.Window)
+	kept := state.received[:0]
+	for _, received := range state.received {
+		if received.After(cutoff) {
+			kept = append(kept, received)
+		}
+	}
+	state.received = append(kept, at)
+
+	count = len(state.received)
+	if count <= g.config.MaxMessages {
+		return false, false, count
+	}
+
+	state.received = nil
+	state.cooldownUntil = at.Add(g.config.Cooldown)
+	return true, true, count
+}
+
+// sweep drops idle contacts at most once per window; caller must hold the lock
+func (g *FloodGuard) sweep(now time.Time) {
+	if now.Sub(g.lastSweep) < g.config.Window {
+		return
+	}
+
+	cutoff := now.Add(-g.config.Window)
+	for key, state := range g.contacts {
+		if now.Before(state.cooldownUntil) {
+			continue
+		}
+		if len(state.received) == 0 || !state.received[len(state.received)-1].After(cutoff) {
+			delete(g.contacts, key)
+		}
+	}
+	g.lastSweep = now
+}
+
+// isFloodSuppressed counts an incoming message against its sender's rate, emits flood.detected when
+// the sender starts flooding, and reports whether the message must be kept from webhooks and Chatwoot
+func (h *EventHandler) isFloodSuppressed(evt interface{}, sessionID string) bool {
+	msg, ok := evt.(*events.Message)
+	if !ok || msg.Info.IsFromMe || h.manager == nil || h.manager.floodGuard == nil {
+		return false
+	}
+
+	guard := h.manager.floodGuard
+	contactJID := msg.Info.Sender.ToNonAD().String()
+	now := time.Now()
+
+	flooding, started, count := guard.Record(sessionID, contactJID, now)
+	if !flooding {
+		return false
+	}
+
+	if started {
+		h.logger.WarnWithFields("Inbound flood detected", map[string]interface{}{
+			"session_id":    sessionID,
+			"contact":       contactJID,
+			"message_count": count,
+			"suppressed":    guard.config.Suppress,
+		})
+
+		h.deliverToWebhook(&FloodDetected{
+			ContactJID:    contactJID,
+			ChatJID:       msg.Info.Chat.String(),
+			MessageCount:  count,
+			WindowSeconds: int(guard.config.Window / time.Second),
+			Suppressed:    guard.config.Suppress,
+			CooldownUntil: now.Add(guard.config.Cooldown),
+			Timestamp:     now,
+		}, sessionID)
+	}
+
+	if !guard.config.Suppress {
+		return false
+	}
+
+	h.logger.DebugWithFields("Message from flooding contact suppressed", map[string]interface{}{
+		"session_id": sessionID,
+		"contact":    contactJID,
+		"message_id": msg.Info.ID,
+	})
+	return true
+}
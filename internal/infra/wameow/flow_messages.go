@@ -0,0 +1,235 @@
+package wameow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+
+	"zpwoot/internal/domain/message"
+)
+
+// FlowResponseEventType is the webhook event carrying a reply to a native flow message
+const FlowResponseEventType = "flow.response"
+
+// maxFlowButtons is the most buttons WhatsApp renders on a native flow message
+const maxFlowButtons = 10
+
+// ErrNativeFlowUnsupported is returned when the session's account cannot send native flow messages
+var ErrNativeFlowUnsupported = errors.New("native flow messages can only be sent from WhatsApp Business accounts")
+
+// FlowButton is a native flow button. Name is the button type WhatsApp renders (quick_reply, cta_url,
+// cta_call, cta_copy, single_select, ...) and ParamsJSON its parameters as a JSON object.
+type FlowButton struct {
+	Name       string
+	ParamsJSON string
+}
+
+// FlowMessage is an interactive message rendered with native flow buttons
+type FlowMessage struct {
+	Header            string
+	Body              string
+	Footer            string
+	Buttons           []FlowButton
+	MessageParamsJSON string
+}
+
+// Validate checks the message has a body and buttons with JSON object parameters
+func (f *FlowMessage) Validate() error {
+	if f.Body == "" {
+		return fmt.Errorf("flow message body is required")
+	}
+	if len(f.Buttons) == 0 || len(f.Buttons) > maxFlowButtons {
+		return fmt.Errorf("flow message needs between 1 and %d buttons", maxFlowButtons)
+	}
+	for i, button := range f.Buttons {
+		if button.Name == "" {
+			return fmt.Errorf("button %d: name is required", i+1)
+		}
+		if button.ParamsJSON != "" && !isJSONObject(button.ParamsJSON) {
+			return fmt.Errorf("button %d: params must be a JSON object", i+1)
+		}
+	}
+	if f.MessageParamsJSON != "" && !isJSONObject(f.MessageParamsJSON) {
+		return fmt.Errorf("message params must be a JSON object")
+	}
+	return nil
+}
+
+// FlowResponse is a reply to a native flow message. Params holds the decoded response parameters, such
+// as the ID of the quick reply pressed or the fields submitted in a flow; ParamsJSON keeps them raw when
+// they are not a JSON object.
+type FlowResponse struct {
+	FlowMessageID string                 `json:"flowMessageId"`
+	ChatJID       string                 `json:"chatJid"`
+	SenderJID     string                 `json:"senderJid"`
+	MessageID     string                 `json:"messageId"`
+	Name          string                 `json:"name"`
+	Body          string                 `json:"body,omitempty"`
+	Params        map[string]interface{} `json:"params,omitempty"`
+	ParamsJSON    string                 `json:"paramsJson,omitempty"`
+	Version       int32                  `json:"version,omitempty"`
+	Timestamp     time.Time              `json:"timestamp"`
+}
+
+// WebhookEventType implements webhookEventNamer
+func (r *FlowResponse) WebhookEventType() string {
+	return FlowResponseEventType
+}
+
+// SendFlowMessage sends an interactive message with native flow buttons. WhatsApp only renders them
+// when sent from a business account, so other sessions get ErrNativeFlowUnsupported.
+func (m *Manager) SendFlowMessage(sessionID, to string, flow *FlowMessage) (*message.SendResult, error) {
+	client := m.getClient(sessionID)
+	if client == nil {
+		return nil, fmt.Errorf("session %s not found", sessionID)
+	}
+	if !client.IsLoggedIn() {
+		return nil, fmt.Errorf("session %s is not logged in", sessionID)
+	}
+
+	if err := flow.Validate(); err != nil {
+		return nil, err
+	}
+
+	resp, err := client.SendFlowMessage(context.Background(), to, flow)
+	if err != nil {
+		return &message.SendResult{
+			Status:    "failed",
+			Error:     err.Error(),
+			Timestamp: time.Now(),
+		}, err
+	}
+
+	return &message.SendResult{
+		MessageID: resp.ID,
+		Status:    "sent",
+		Timestamp: resp.Timestamp,
+	}, nil
+}
+
+// SupportsNativeFlow reports whether the session's account is a business account, the only kind whose
+// native flow messages WhatsApp renders
+func (c *WameowClient) SupportsNativeFlow() bool {
+	return c.client.Store != nil && c.client.Store.BusinessName != ""
+}
+
+// SendFlowMessage builds and sends a native flow interactive message
+func (c *WameowClient) SendFlowMessage(ctx context.Context, to string, flow *FlowMessage) (*whatsmeow.SendResponse, error) {
+	if !c.client.IsLoggedIn() {
+		return nil, fmt.Errorf("client is not logged in")
+	}
+	if !c.SupportsNativeFlow() {
+		return nil, ErrNativeFlowUnsupported
+	}
+
+	jid, err := c.parseJID(to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JID: %w", err)
+	}
+
+	buttons := make([]*waE2E.InteractiveMessage_NativeFlowMessage_NativeFlowButton, 0, len(flow.Buttons))
+	for _, button := range flow.Buttons {
+		params := button.ParamsJSON
+		if params == "" {
+			params = "{}"
+		}
+		buttons = append(buttons, &waE2E.InteractiveMessage_NativeFlowMessage_NativeFlowButton{
+			Name:             proto.String(button.Name),
+			ButtonParamsJSON: proto.String(params),
+		})
+	}
+
+	nativeFlow := &waE2E.InteractiveMessage_NativeFlowMessage{
+		Buttons:        buttons,
+		MessageVersion: proto.Int32(1),
+	}
+	if flow.MessageParamsJSON != "" {
+		nativeFlow.MessageParamsJSON = proto.String(flow.MessageParamsJSON)
+	}
+
+	interactive := &waE2E.InteractiveMessage{
+		Body: &waE2E.InteractiveMessage_Body{Text: proto.String(flow.Body)},
+		InteractiveMessage: &waE2E.InteractiveMessage_NativeFlowMessage_{
+			NativeFlowMessage: nativeFlow,
+		},
+	}
+	if flow.Header != "" {
+		interactive.Header = &waE2E.InteractiveMessage_Header{
+			Title:              proto.String(flow.Header),
+			HasMediaAttachment: proto.Bool(false),
+		}
+	}
+	if flow.Footer != "" {
+		interactive.Footer = &waE2E.InteractiveMessage_Footer{Text: proto.String(flow.Footer)}
+	}
+
+	msg := &waE2E.Message{
+		ViewOnceMessage: &waE2E.FutureProofMessage{
+			Message: &waE2E.Message{
+				InteractiveMessage: interactive,
+			},
+		},
+	}
+
+	c.logger.InfoWithFields("Sending flow message", map[string]interface{}{
+		"session_id":   c.sessionID,
+		"to":           to,
+		"button_count": len(buttons),
+		"body_length":  len(flow.Body),
+	})
+
+	resp, err := c.sendMessage(ctx, jid, msg)
+	if err != nil {
+		c.logger.ErrorWithFields("Failed to send flow message", map[string]interface{}{
+			"session_id": c.sessionID,
+			"to":         to,
+			"error":      err.Error(),
+		})
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// handleFlowResponse delivers a reply to a native flow message as a flow.response event
+func (h *EventHandler) handleFlowResponse(evt *events.Message, sessionID string) {
+	interactiveResponse := evt.Message.GetInteractiveResponseMessage()
+	flowResponse := interactiveResponse.GetNativeFlowResponseMessage()
+
+	response := &FlowResponse{
+		FlowMessageID: interactiveResponse.GetContextInfo().GetStanzaID(),
+		ChatJID:       evt.Info.Chat.String(),
+		SenderJID:     evt.Info.Sender.ToNonAD().String(),
+		MessageID:     evt.Info.ID,
+		Name:          flowResponse.GetName(),
+		Body:          interactiveResponse.GetBody().GetText(),
+		Version:       flowResponse.GetVersion(),
+		Timestamp:     evt.Info.Timestamp,
+	}
+	if paramsJSON := flowResponse.GetParamsJSON(); paramsJSON != "" {
+		if err := json.Unmarshal([]byte(paramsJSON), &response.Params); err != nil {
+			response.ParamsJSON = paramsJSON
+		}
+	}
+
+	h.logger.InfoWithFields("Flow response received", map[string]interface{}{
+		"session_id": sessionID,
+		"flow_id":    response.FlowMessageID,
+		"sender":     response.SenderJID,
+		"name":       response.Name,
+	})
+
+	h.deliverToWebhook(response, sessionID)
+}
+
+func isJSONObject(value string) bool {
+	var object map[string]interface{}
+	return json.Unmarshal([]byte(value), &object) == nil
+}
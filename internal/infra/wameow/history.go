@@ -0,0 +1,215 @@
+package wameow
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"zpwoot/internal/domain/history"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waHistorySync"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// DefaultHistoryBackfillCount is the number of older messages requested from the phone per backfill
+const DefaultHistoryBackfillCount = 50
+
+// historyBackfillTimeout bounds how long a backfill waits for the phone to answer
+const historyBackfillTimeout = 60 * time.Second
+
+// historyBackfills hands on-demand history syncs to the backfill request waiting for them.
+// The phone does not echo which request a sync answers, so only one backfill runs per session at a time.
+type historyBackfills struct {
+	mu      sync.Mutex
+	pending map[string]chan *waHistorySync.HistorySync
+}
+
+func newHistoryBackfills() *historyBackfills {
+	return &historyBackfills{
+		pending: make(map[string]chan *waHistorySync.HistorySync),
+	}
+}
+
+// register reserves the session for a backfill and returns the channel its history will arrive on
+func (b *historyBackfills) register(sessionID string) (chan *waHistorySync.HistorySync, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, exists := b.pending[sessionID]; exists {
+		return nil, fmt.Errorf("a history backfill is already running for session %s", sessionID)
+	}
+
+	ch := make(chan *waHistorySync.HistorySync, 1)
+	b.pending[sessionID] = ch
+	return ch, nil
+}
+
+func (b *historyBackfills) release(sessionID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.pending, sessionID)
+}
+
+// active reports whether a backfill is waiting for the session's history
+func (b *historyBackfills) active(sessionID string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	_, exists := b.pending[sessionID]
+	return exists
+}
+
+// deliver passes an on-demand history sync to the waiting backfill, if any, and reports whether it did
+func (b *historyBackfills) deliver(sessionID string, data *waHistorySync.HistorySync) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch, exists := b.pending[sessionID]
+	if !exists {
+		return false
+	}
+
+	select {
+	case ch <- data:
+		return true
+	default:
+		return false
+	}
+}
+
+// recordHistorySync stores the messages of every conversation of a history sync in the message history,
+// so the chats keep what was sent before the session paired; storing is idempotent, so syncs the phone
+// sends again do not duplicate messages
+func (m *Manager) recordHistorySync(sessionID string, data *waHistorySync.HistorySync) {
+	if m.messageHistory == nil || len(data.GetConversations()) == 0 {
+		return
+	}
+
+	client := m.getClient(sessionID)
+	if client == nil {
+		return
+	}
+	cli := client.GetClient()
+
+	stored := 0
+	for _, conv := range data.GetConversations() {
+		chat, err := types.ParseJID(conv.GetID())
+		if err != nil {
+			continue
+		}
+
+		for _, historyMsg := range conv.GetMessages() {
+			evt, err := cli.ParseWebMessage(chat, historyMsg.GetMessage())
+			if err != nil || evt.Message == nil {
+				continue
+			}
+
+			recordMessage(m.messageHistory, m.logger, sessionID, evt.Info.Chat, evt.Info.Sender, evt.Info.ID, evt.Info.IsFromMe, evt.Info.Timestamp, evt.Message)
+			stored++
+		}
+	}
+
+	m.logger.InfoWithFields("History sync stored", map[string]interface{}{
+		"session_id":    sessionID,
+		"sync_type":     data.GetSyncType().String(),
+		"conversations": len(data.GetConversations()),
+		"messages":      stored,
+	})
+}
+
+// RequestHistoryBackfill asks the phone for up to count messages sent in a chat before the given oldest known message
+// and waits for them to arrive; they are returned in their message history form
+func (m *Manager) RequestHistoryBackfill(sessionID, chatJID, oldestMessageID string, oldestFromMe bool, oldestTimestamp time.Time, count int) ([]*history.Message, error) {
+	client := m.getClient(sessionID)
+	if client == nil {
+		return nil, fmt.Errorf("session %s not found", sessionID)
+	}
+	if !client.IsLoggedIn() {
+		return nil, fmt.Errorf("session %s is not logged in", sessionID)
+	}
+
+	chat, err := client.parseJID(chatJID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid chat JID: %w", err)
+	}
+
+	cli := client.GetClient()
+	if cli.Store.ID == nil {
+		return nil, fmt.Errorf("session %s is not logged in", sessionID)
+	}
+
+	if count <= 0 {
+		count = DefaultHistoryBackfillCount
+	}
+
+	ch, err := m.historyBackfills.register(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer m.historyBackfills.release(sessionID)
+
+	request := cli.BuildHistorySyncRequest(&types.MessageInfo{
+		MessageSource: types.MessageSource{
+			Chat:     chat,
+			IsFromMe: oldestFromMe,
+		},
+		ID:        oldestMessageID,
+		Timestamp: oldestTimestamp,
+	}, count)
+
+	m.logger.InfoWithFields("Requesting history backfill", map[string]interface{}{
+		"session_id":        sessionID,
+		"chat_jid":          chat.String(),
+		"oldest_message_id": oldestMessageID,
+		"count":             count,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), historyBackfillTimeout)
+	defer cancel()
+
+	if _, err := cli.SendMessage(ctx, cli.Store.ID.ToNonAD(), request, whatsmeow.SendRequestExtra{Peer: true}); err != nil {
+		return nil, fmt.Errorf("failed to request history from phone: %w", err)
+	}
+
+	select {
+	case data := <-ch:
+		return parseHistoryMessages(cli, sessionID, chat, data), nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("timed out waiting for history from phone")
+	}
+}
+
+// parseHistoryMessages extracts the messages of one chat from a history sync; edits, revokes and pins of
+// older messages are left out
+func parseHistoryMessages(cli *whatsmeow.Client, sessionID string, chat types.JID, data *waHistorySync.HistorySync) []*history.Message {
+	messages := make([]*history.Message, 0)
+
+	for _, conv := range data.GetConversations() {
+		convJID, err := types.ParseJID(conv.GetID())
+		if err != nil || convJID.ToNonAD() != chat.ToNonAD() {
+			continue
+		}
+
+		for _, historyMsg := range conv.GetMessages() {
+			evt, err := cli.ParseWebMessage(chat, historyMsg.GetMessage())
+			if err != nil || evt.Message == nil {
+				continue
+			}
+
+			message := unwrapHistoryMessage(evt.Message)
+			if message.GetProtocolMessage() != nil || message.GetPinInChatMessage() != nil {
+				continue
+			}
+
+			entry := historyEntry(sessionID, evt.Info.Chat, evt.Info.Sender, evt.Info.ID, evt.Info.IsFromMe, evt.Info.Timestamp, message)
+			if entry != nil {
+				messages = append(messages, entry)
+			}
+		}
+	}
+
+	return messages
+}
@@ -0,0 +1,86 @@
+package wameow
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// ListResponseEventType is the webhook event carrying the row selected on a list message
+const ListResponseEventType = "list.response"
+
+// ListResponse is a selection made on a list message. RowID is the ID given to the row when the list was sent.
+type ListResponse struct {
+	ListMessageID string    `json:"listMessageId"`
+	ChatJID       string    `json:"chatJid"`
+	SenderJID     string    `json:"senderJid"`
+	MessageID     string    `json:"messageId"`
+	RowID         string    `json:"rowId"`
+	Title         string    `json:"title"`
+	Description   string    `json:"description,omitempty"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+// WebhookEventType implements webhookEventNamer
+func (r *ListResponse) WebhookEventType() string {
+	return ListResponseEventType
+}
+
+// handleListResponse delivers the row selected on a list message as a list.response event
+func (h *EventHandler) handleListResponse(evt *events.Message, sessionID string) {
+	listResponse := evt.Message.GetListResponseMessage()
+
+	response := &ListResponse{
+		ListMessageID: listResponse.GetContextInfo().GetStanzaID(),
+		ChatJID:       evt.Info.Chat.String(),
+		SenderJID:     evt.Info.Sender.ToNonAD().String(),
+		MessageID:     evt.Info.ID,
+		RowID:         listResponse.GetSingleSelectReply().GetSelectedRowID(),
+		Title:         listResponse.GetTitle(),
+		Description:   listResponse.GetDescription(),
+		Timestamp:     evt.Info.Timestamp,
+	}
+
+	h.logger.InfoWithFields("List response received", map[string]interface{}{
+		"session_id": sessionID,
+		"list_id":    response.ListMessageID,
+		"sender":     response.SenderJID,
+		"row_id":     response.RowID,
+	})
+
+	h.deliverToWebhook(response, sessionID)
+}
+
+// listMessageText renders a list message as numbered plain text, for clients that do not render native lists
+func listMessageText(listMsg *waE2E.ListMessage) string {
+	var b strings.Builder
+
+	if title := listMsg.GetTitle(); title != "" {
+		fmt.Fprintf(&b, "*%s*\n\n", title)
+	}
+	b.WriteString(listMsg.GetDescription())
+
+	index := 1
+	for _, section := range listMsg.GetSections() {
+		b.WriteString("\n")
+		if title := section.GetTitle(); title != "" {
+			fmt.Fprintf(&b, "\n*%s*", title)
+		}
+		for _, row := range section.GetRows() {
+			fmt.Fprintf(&b, "\n%d. %s", index, row.GetTitle())
+			if description := row.GetDescription(); description != "" {
+				fmt.Fprintf(&b, " - %s", description)
+			}
+			index++
+		}
+	}
+
+	if footer := listMsg.GetFooterText(); footer != "" {
+		fmt.Fprintf(&b, "\n\n_%s_", footer)
+	}
+
+	return b.String()
+}
@@ -3,12 +3,16 @@ package wameow
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	appMessage "zpwoot/internal/app/message"
+	"zpwoot/internal/domain/contact"
 	"zpwoot/internal/domain/message"
+	"zpwoot/internal/domain/poll"
 	"zpwoot/internal/domain/session"
 	"zpwoot/internal/ports"
 	"zpwoot/platform/logger"
@@ -29,8 +33,13 @@ type SessionStats struct {
 }
 
 type EventHandlerInfo struct {
-	ID      string
-	Handler ports.EventHandler
+	ID           string
+	Handler      ports.EventHandler
+	RegisteredAt time.Time
+
+	// whatsmeowID identifies the callback added to the client; it is only set when attached
+	whatsmeowID uint32
+	attached    bool
 }
 
 type Manager struct {
@@ -49,6 +58,26 @@ type Manager struct {
 	handlersMutex   sync.RWMutex
 	webhookHandler  WebhookEventHandler // Global webhook handler for all sessions
 	chatwootManager ChatwootManager     // Global Chatwoot manager for all sessions
+	inboundDedupe   *InboundDeduplicator
+	floodGuard      *FloodGuard
+
+	historyBackfills *historyBackfills
+	newsletterCounts *newsletterCounters
+	pollService      *poll.Service
+	contactFeed      *contact.ChangeFeed
+	phoneSync        *phoneSyncTracker
+	presenceSubs     *presenceSubscriptions
+	offlineQueue     OfflineQueue
+	privacy          PrivacyPolicy
+	requestTraces    *requestTracer
+	reconnects       *reconnectSupervisor
+	sendGate         SendGate
+	sendGuard        SendGuard
+	sendThrottle     SendThrottle
+	messageHistory   MessageHistory
+	messageStats     MessageStats
+	// slowSendThreshold is copied into the metrics of each session's client
+	slowSendThreshold time.Duration
 }
 
 func NewManager(
@@ -57,14 +86,20 @@ func NewManager(
 	logger *logger.Logger,
 ) *Manager {
 	return &Manager{
-		clients:       make(map[string]*WameowClient),
-		container:     container,
-		connectionMgr: NewConnectionManager(logger),
-		qrGenerator:   NewQRCodeGenerator(logger),
-		sessionMgr:    NewSessionManager(sessionRepo, logger),
-		logger:        logger,
-		sessionStats:  make(map[string]*SessionStats),
-		eventHandlers: make(map[string]map[string]*EventHandlerInfo),
+		clients:          make(map[string]*WameowClient),
+		container:        container,
+		connectionMgr:    NewConnectionManager(logger),
+		qrGenerator:      NewQRCodeGenerator(logger),
+		sessionMgr:       NewSessionManager(sessionRepo, logger),
+		logger:           logger,
+		sessionStats:     make(map[string]*SessionStats),
+		eventHandlers:    make(map[string]map[string]*EventHandlerInfo),
+		inboundDedupe:    NewInboundDeduplicator(DefaultInboundDedupeTTL),
+		historyBackfills: newHistoryBackfills(),
+		newsletterCounts: newNewsletterCounters(),
+		phoneSync:        newPhoneSyncTracker(),
+		presenceSubs:     newPresenceSubscriptions(),
+		requestTraces:    newRequestTracer(),
 	}
 }
 
@@ -98,7 +133,17 @@ func (m *Manager) CreateSession(sessionID string, config *session.ProxyConfig) e
 
 // createWameowClient creates a new WameowClient instance
 func (m *Manager) createWameowClient(sessionID string) (*WameowClient, error) {
-	return NewWameowClient(sessionID, m.container, m.sessionMgr.GetSessionRepo(), m.logger)
+	client, err := NewWameowClient(sessionID, m.container, m.sessionMgr.GetSessionRepo(), m.logger)
+	if err != nil {
+		return nil, err
+	}
+	client.sendGate = m.sendGate
+	client.sendGuard = m.sendGuard
+	client.sendThrottle = m.sendThrottle
+	client.messageHistory = m.messageHistory
+	client.messageStats = m.messageStats
+	client.metrics.slowSendThreshold = m.slowSendThreshold
+	return client, nil
 }
 
 // configureSession configures the session with event handlers and proxy
@@ -116,8 +161,17 @@ func (m *Manager) configureSession(client *WameowClient, sessionID string, confi
 	}
 	client.SetEventHandler(eventHandler)
 
+	// The supervisor paces reconnects across sessions, which whatsmeow's own loop would race
+	if m.reconnects != nil {
+		client.GetClient().EnableAutoReconnect = false
+	}
+
 	if config != nil {
 		if err := m.applyProxyConfig(client.GetClient(), config); err != nil {
+			// Connecting from the host's default address would defeat a source address binding
+			if config.HasBinding() {
+				return err
+			}
 			m.logger.WarnWithFields("Failed to apply proxy config", map[string]interface{}{
 				"session_id": sessionID,
 				"error":      err.Error(),
@@ -148,6 +202,8 @@ func (m *Manager) ConnectSession(sessionID string) error {
 		}
 	}
 
+	m.reconnects.want(sessionID)
+
 	err := client.Connect()
 	if err != nil {
 		m.sessionMgr.UpdateConnectionStatus(sessionID, false)
@@ -163,6 +219,8 @@ func (m *Manager) DisconnectSession(sessionID string) error {
 		return fmt.Errorf("session %s not found", sessionID)
 	}
 
+	m.reconnects.release(sessionID)
+
 	err := client.Disconnect()
 	if err != nil {
 		return fmt.Errorf("failed to disconnect session %s: %w", sessionID, err)
@@ -189,13 +247,73 @@ func (m *Manager) LogoutSession(sessionID string) error {
 
 	m.sessionMgr.UpdateConnectionStatus(sessionID, false)
 
+	// A new client is created on the next connect, so nothing kept for this one is needed anymore
+	return m.RemoveSession(sessionID)
+}
+
+// RestartSession tears the session's client down and builds a new one from the stored credentials,
+// recovering a wedged client without restarting the service. Registered event handlers move to the
+// new client, which connects when the session is paired or the old client was connecting.
+func (m *Manager) RestartSession(sessionID string) error {
+	sess, err := m.sessionMgr.GetSession(sessionID)
+	if err != nil {
+		return fmt.Errorf("session %s not found", sessionID)
+	}
+
 	m.clientsMutex.Lock()
+	old := m.clients[sessionID]
 	delete(m.clients, sessionID)
 	m.clientsMutex.Unlock()
 
+	wasConnected := false
+	if old != nil {
+		wasConnected = old.IsConnected()
+		old.release()
+	}
+	m.sessionMgr.UpdateConnectionStatus(sessionID, false)
+	m.phoneSync.forget(sessionID)
+
+	if err := m.CreateSession(sessionID, sess.ProxyConfig); err != nil {
+		return fmt.Errorf("failed to recreate Wameow client for session %s: %w", sessionID, err)
+	}
+	m.reattachEventHandlers(sessionID)
+
+	client := m.getClient(sessionID)
+	if client == nil {
+		return fmt.Errorf("failed to create Wameow client for session %s", sessionID)
+	}
+
+	paired := client.GetClient().Store.ID != nil
+	m.logger.InfoWithFields("Session client restarted", map[string]interface{}{
+		"session_id":    sessionID,
+		"paired":        paired,
+		"was_connected": wasConnected,
+	})
+
+	if !paired && !wasConnected {
+		return nil
+	}
+	if err := client.Connect(); err != nil {
+		return fmt.Errorf("failed to connect session %s: %w", sessionID, err)
+	}
 	return nil
 }
 
+// reattachEventHandlers attaches the handlers registered for the session to its current client
+func (m *Manager) reattachEventHandlers(sessionID string) {
+	m.handlersMutex.Lock()
+	handlers := make(map[string]ports.EventHandler, len(m.eventHandlers[sessionID]))
+	for handlerID, info := range m.eventHandlers[sessionID] {
+		info.attached = false
+		handlers[handlerID] = info.Handler
+	}
+	m.handlersMutex.Unlock()
+
+	for handlerID, handler := range handlers {
+		m.attachHandlerToClient(sessionID, handlerID, handler)
+	}
+}
+
 func (m *Manager) GetQRCode(sessionID string) (*session.QRCodeResponse, error) {
 	m.logger.InfoWithFields("Getting QR code for session", map[string]interface{}{
 		"session_id": sessionID,
@@ -215,8 +333,7 @@ func (m *Manager) GetQRCode(sessionID string) (*session.QRCodeResponse, error) {
 		return nil, fmt.Errorf("failed to get QR code for session %s: %w", sessionID, err)
 	}
 
-	// Generate QR code image
-	qrCodeImage := m.qrGenerator.GenerateQRCodeImage(qrCode)
+	qrCodeImage := client.GetQRCodeImage()
 
 	return &session.QRCodeResponse{
 		QRCode:      qrCode,
@@ -268,14 +385,24 @@ func (m *Manager) GetDeviceInfo(sessionID string) (*session.DeviceInfo, error) {
 
 func (m *Manager) SetProxy(sessionID string, config *session.ProxyConfig) error {
 	m.logger.InfoWithFields("Setting proxy for session", map[string]interface{}{
-		"session_id": sessionID,
-		"proxy_type": config.Type,
-		"proxy_host": config.Host,
+		"session_id":    sessionID,
+		"proxy_type":    config.Type,
+		"proxy_host":    config.Host,
+		"local_address": config.LocalAddress,
+		"interface":     config.Interface,
 	})
 
+	// A session without a client gets the stored configuration when its client is created
 	client := m.getClient(sessionID)
 	if client == nil {
-		return fmt.Errorf("session %s not found", sessionID)
+		if err := config.Validate(); err != nil {
+			return err
+		}
+		if config.HasBinding() {
+			_, err := newBoundDialer(config)
+			return err
+		}
+		return nil
 	}
 
 	return m.applyProxyConfig(client.GetClient(), config)
@@ -401,7 +528,7 @@ func (m *Manager) validateMediaMessageRequest(sessionID, to string) (*WameowClie
 
 // uploadMedia uploads media to WhatsApp servers
 func (m *Manager) uploadMedia(client *WameowClient, media []byte, mediaType, sessionID, to string) (whatsmeow.UploadResponse, error) {
-	uploaded, err := client.GetClient().Upload(context.Background(), media, whatsmeow.MediaType(mediaType))
+	uploaded, err := client.upload(context.Background(), media, whatsmeow.MediaType(mediaType))
 	if err != nil {
 		m.logger.ErrorWithFields("Failed to upload media", map[string]interface{}{
 			"session_id": sessionID,
@@ -471,7 +598,7 @@ func (m *Manager) createMediaMessage(mediaType string, uploaded whatsmeow.Upload
 
 // sendMediaMessageAndLog sends the message and logs the result
 func (m *Manager) sendMediaMessageAndLog(client *WameowClient, recipientJID types.JID, msg *waE2E.Message, sessionID, to, mediaType string) error {
-	_, err := client.GetClient().SendMessage(context.Background(), recipientJID, msg)
+	_, err := client.sendMessage(context.Background(), recipientJID, msg)
 	if err != nil {
 		m.logger.ErrorWithFields("Failed to send media message", map[string]interface{}{
 			"session_id": sessionID,
@@ -495,7 +622,10 @@ func (m *Manager) sendMediaMessageAndLog(client *WameowClient, recipientJID type
 
 func (m *Manager) RegisterEventHandler(sessionID string, handler ports.EventHandler) error {
 	// Register handler in registry
-	handlerID := m.registerHandlerInRegistry(sessionID, handler)
+	handlerID, err := m.registerHandlerInRegistry(sessionID, handler)
+	if err != nil {
+		return err
+	}
 
 	// Attach handler to client
 	m.attachHandlerToClient(sessionID, handlerID, handler)
@@ -510,7 +640,7 @@ func (m *Manager) RegisterEventHandler(sessionID string, handler ports.EventHand
 }
 
 // registerHandlerInRegistry registers the handler in the internal registry
-func (m *Manager) registerHandlerInRegistry(sessionID string, handler ports.EventHandler) string {
+func (m *Manager) registerHandlerInRegistry(sessionID string, handler ports.EventHandler) (string, error) {
 	m.handlersMutex.Lock()
 	defer m.handlersMutex.Unlock()
 
@@ -518,24 +648,48 @@ func (m *Manager) registerHandlerInRegistry(sessionID string, handler ports.Even
 		m.eventHandlers[sessionID] = make(map[string]*EventHandlerInfo)
 	}
 
+	if len(m.eventHandlers[sessionID]) >= maxEventHandlersPerSession {
+		return "", fmt.Errorf("session %s already has %d event handlers", sessionID, maxEventHandlersPerSession)
+	}
+
 	handlerID := fmt.Sprintf("handler_%d", time.Now().UnixNano())
 
 	m.eventHandlers[sessionID][handlerID] = &EventHandlerInfo{
-		ID:      handlerID,
-		Handler: handler,
+		ID:           handlerID,
+		Handler:      handler,
+		RegisteredAt: time.Now(),
 	}
 
-	return handlerID
+	return handlerID, nil
 }
 
-// attachHandlerToClient attaches the handler to the WhatsApp client
+// attachHandlerToClient attaches the handler to the WhatsApp client and remembers its whatsmeow ID
+// so UnregisterEventHandler can remove the callback again
 func (m *Manager) attachHandlerToClient(sessionID, handlerID string, handler ports.EventHandler) {
 	client := m.getClient(sessionID)
-	if client != nil {
-		client.GetClient().AddEventHandler(func(evt interface{}) {
-			m.processEventForHandler(evt, sessionID, handler)
+	if client == nil {
+		m.logger.WarnWithFields("Event handler registered without a client, it will not receive events", map[string]interface{}{
+			"session_id": sessionID,
+			"handler_id": handlerID,
 		})
+		return
+	}
+
+	whatsmeowID := client.GetClient().AddEventHandler(func(evt interface{}) {
+		m.processEventForHandler(evt, sessionID, handler)
+	})
+
+	m.handlersMutex.Lock()
+	defer m.handlersMutex.Unlock()
+
+	info, exists := m.eventHandlers[sessionID][handlerID]
+	if !exists {
+		// Unregistered while attaching
+		client.GetClient().RemoveEventHandler(whatsmeowID)
+		return
 	}
+	info.whatsmeowID = whatsmeowID
+	info.attached = true
 }
 
 // processEventForHandler processes an event for a specific handler
@@ -602,17 +756,19 @@ func (m *Manager) handlePairSuccessEvent(sessionID string, handler ports.EventHa
 	}
 }
 
+// UnregisterEventHandler removes the handler and its whatsmeow callback, so it stops receiving events.
+// It must not be called from inside an event handler, as whatsmeow holds its handler lock while dispatching.
 func (m *Manager) UnregisterEventHandler(sessionID string, handlerID string) error {
 	m.handlersMutex.Lock()
-	defer m.handlersMutex.Unlock()
-
 	sessionHandlers, exists := m.eventHandlers[sessionID]
 	if !exists {
+		m.handlersMutex.Unlock()
 		return fmt.Errorf("no event handlers found for session %s", sessionID)
 	}
 
-	_, exists = sessionHandlers[handlerID]
+	info, exists := sessionHandlers[handlerID]
 	if !exists {
+		m.handlersMutex.Unlock()
 		return fmt.Errorf("event handler %s not found for session %s", handlerID, sessionID)
 	}
 
@@ -621,6 +777,13 @@ func (m *Manager) UnregisterEventHandler(sessionID string, handlerID string) err
 	if len(sessionHandlers) == 0 {
 		delete(m.eventHandlers, sessionID)
 	}
+	m.handlersMutex.Unlock()
+
+	if info.attached {
+		if client := m.getClient(sessionID); client != nil {
+			client.GetClient().RemoveEventHandler(info.whatsmeowID)
+		}
+	}
 
 	m.logger.InfoWithFields("Event handler unregistered", map[string]interface{}{
 		"session_id": sessionID,
@@ -630,6 +793,25 @@ func (m *Manager) UnregisterEventHandler(sessionID string, handlerID string) err
 	return nil
 }
 
+// ListEventHandlers returns the event handlers registered for a session, oldest first
+func (m *Manager) ListEventHandlers(sessionID string) []*ports.EventHandlerRegistration {
+	m.handlersMutex.RLock()
+	registrations := make([]*ports.EventHandlerRegistration, 0, len(m.eventHandlers[sessionID]))
+	for _, info := range m.eventHandlers[sessionID] {
+		registrations = append(registrations, &ports.EventHandlerRegistration{
+			ID:           info.ID,
+			Attached:     info.attached,
+			RegisteredAt: info.RegisteredAt,
+		})
+	}
+	m.handlersMutex.RUnlock()
+
+	sort.Slice(registrations, func(i, j int) bool {
+		return registrations[i].RegisteredAt.Before(registrations[j].RegisteredAt)
+	})
+	return registrations
+}
+
 func (m *Manager) getClient(sessionID string) *WameowClient {
 	m.clientsMutex.RLock()
 	defer m.clientsMutex.RUnlock()
@@ -646,17 +828,27 @@ func (m *Manager) applyProxyConfig(client *whatsmeow.Client, config *session.Pro
 		return fmt.Errorf("proxy configuration is nil")
 	}
 
-	// Validate proxy configuration
-	switch config.Type {
-	case "http", "https", "socks5":
-		// Valid proxy types
-	default:
-		return fmt.Errorf("unsupported proxy type: %s", config.Type)
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	// A bound source address replaces the dialer of both the websocket and the media client, which
+	// then tunnels through the proxy itself
+	if config.HasBinding() {
+		dialer, err := newBoundDialer(config)
+		if err != nil {
+			return err
+		}
+		client.SetSOCKSProxy(dialer)
+		return nil
 	}
 
-	// Proxy configuration validated successfully
+	if config.Type == "" {
+		client.SetProxy(http.ProxyFromEnvironment)
+		return nil
+	}
 
-	return nil
+	return client.SetProxyAddress(proxyURL(config))
 }
 
 func (m *Manager) SendButtonMessage(sessionID, to, body string, buttons []map[string]string) (*message.SendResult, error) {
@@ -685,7 +877,7 @@ func (m *Manager) SendButtonMessage(sessionID, to, body string, buttons []map[st
 	}, nil
 }
 
-func (m *Manager) SendListMessage(sessionID, to, body, buttonText string, sections []map[string]interface{}) (*message.SendResult, error) {
+func (m *Manager) SendListMessage(sessionID, to, title, body, footer, buttonText string, sections []map[string]interface{}, fallbackToText bool) (*message.SendResult, error) {
 	client := m.getClient(sessionID)
 	if client == nil {
 		return nil, fmt.Errorf("session %s not found", sessionID)
@@ -695,7 +887,7 @@ func (m *Manager) SendListMessage(sessionID, to, body, buttonText string, sectio
 	}
 
 	ctx := context.Background()
-	resp, err := client.SendListMessage(ctx, to, body, buttonText, sections)
+	resp, err := client.SendListMessage(ctx, to, title, body, footer, buttonText, sections, fallbackToText)
 	if err != nil {
 		return &message.SendResult{
 			Status:    "failed",
@@ -711,7 +903,7 @@ func (m *Manager) SendListMessage(sessionID, to, body, buttonText string, sectio
 	}, nil
 }
 
-func (m *Manager) SendReaction(sessionID, to, messageID, reaction string) error {
+func (m *Manager) SendReaction(sessionID, to, messageID, reaction, participant string, fromMe bool) error {
 	client := m.getClient(sessionID)
 	if client == nil {
 		return fmt.Errorf("session %s not found", sessionID)
@@ -721,7 +913,7 @@ func (m *Manager) SendReaction(sessionID, to, messageID, reaction string) error
 	}
 
 	ctx := context.Background()
-	return client.SendReaction(ctx, to, messageID, reaction)
+	return client.SendReaction(ctx, to, messageID, reaction, participant, fromMe)
 }
 
 func (m *Manager) SendPresence(sessionID, to, presence string) error {
@@ -737,7 +929,8 @@ func (m *Manager) SendPresence(sessionID, to, presence string) error {
 	return client.SendPresence(ctx, to, presence)
 }
 
-func (m *Manager) EditMessage(sessionID, to, messageID, newText string) error {
+// SendChatPresence sends a presence like SendPresence, but stops waiting for it once ctx ends
+func (m *Manager) SendChatPresence(ctx context.Context, sessionID, to, presence string) error {
 	client := m.getClient(sessionID)
 	if client == nil {
 		return fmt.Errorf("session %s not found", sessionID)
@@ -746,11 +939,48 @@ func (m *Manager) EditMessage(sessionID, to, messageID, newText string) error {
 		return fmt.Errorf("session %s is not logged in", sessionID)
 	}
 
+	// whatsmeow sends presence without a context, so the send is only waited for until ctx ends
+	done := make(chan error, 1)
+	go func() {
+		done <- client.SendPresence(ctx, to, presence)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// EditMessage edits one of our own messages; the result timestamp is the edit time
+func (m *Manager) EditMessage(sessionID, to, messageID, newText string) (*message.SendResult, error) {
+	client := m.getClient(sessionID)
+	if client == nil {
+		return nil, fmt.Errorf("session %s not found", sessionID)
+	}
+	if !client.IsLoggedIn() {
+		return nil, fmt.Errorf("session %s is not logged in", sessionID)
+	}
+
 	ctx := context.Background()
-	return client.EditMessage(ctx, to, messageID, newText)
+	editedAt, err := client.EditMessage(ctx, to, messageID, newText)
+	if err != nil {
+		return &message.SendResult{
+			Status:    "failed",
+			Error:     err.Error(),
+			Timestamp: time.Now(),
+		}, err
+	}
+
+	return &message.SendResult{
+		MessageID: messageID,
+		Status:    "edited",
+		Timestamp: editedAt,
+	}, nil
 }
 
-func (m *Manager) MarkRead(sessionID, to, messageID string) error {
+// MarkRead marks messages from the same sender as read; sender is required for group chats
+func (m *Manager) MarkRead(sessionID, to string, messageIDs []string, sender string) error {
 	client := m.getClient(sessionID)
 	if client == nil {
 		return fmt.Errorf("session %s not found", sessionID)
@@ -760,11 +990,11 @@ func (m *Manager) MarkRead(sessionID, to, messageID string) error {
 	}
 
 	ctx := context.Background()
-	return client.MarkRead(ctx, to, messageID)
+	return client.MarkRead(ctx, to, messageIDs, sender)
 }
 
 // RevokeMessage revokes a message using whatsmeow's RevokeMessage method
-func (m *Manager) RevokeMessage(sessionID, to, messageID string) (*message.SendResult, error) {
+func (m *Manager) RevokeMessage(sessionID, to, messageID, participant string) (*message.SendResult, error) {
 	client := m.getClient(sessionID)
 	if client == nil {
 		return nil, fmt.Errorf("session %s not found", sessionID)
@@ -774,7 +1004,7 @@ func (m *Manager) RevokeMessage(sessionID, to, messageID string) (*message.SendR
 	}
 
 	ctx := context.Background()
-	err := client.RevokeMessage(ctx, to, messageID)
+	err := client.RevokeMessage(ctx, to, messageID, participant)
 	if err != nil {
 		return &message.SendResult{
 			Status:    "failed",
@@ -790,6 +1020,48 @@ func (m *Manager) RevokeMessage(sessionID, to, messageID string) (*message.SendR
 	}, nil
 }
 
+// DeleteMessageForMe deletes a message only for this account
+func (m *Manager) DeleteMessageForMe(sessionID, to, messageID, participant string, fromMe bool, timestamp time.Time, deleteMedia bool) (*message.SendResult, error) {
+	client := m.getClient(sessionID)
+	if client == nil {
+		return nil, fmt.Errorf("session %s not found", sessionID)
+	}
+	if !client.IsLoggedIn() {
+		return nil, fmt.Errorf("session %s is not logged in", sessionID)
+	}
+
+	ctx := context.Background()
+	err := client.DeleteMessageForMe(ctx, to, messageID, participant, fromMe, timestamp, deleteMedia)
+	if err != nil {
+		return &message.SendResult{
+			Status:    "failed",
+			Error:     err.Error(),
+			Timestamp: time.Now(),
+		}, err
+	}
+
+	return &message.SendResult{
+		MessageID: messageID,
+		Status:    "deleted_for_me",
+		Timestamp: time.Now(),
+	}, nil
+}
+
+// LabelChat adds or removes a WhatsApp Business label on a chat
+func (m *Manager) LabelChat(sessionID, chatJID, labelID string, labeled bool) error {
+	client := m.getClient(sessionID)
+	if client == nil {
+		return fmt.Errorf("session %s not found", sessionID)
+	}
+	if !client.IsLoggedIn() {
+		return fmt.Errorf("session %s is not logged in", sessionID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	return client.LabelChat(ctx, chatJID, labelID, labeled)
+}
+
 // Group management methods
 func (m *Manager) CreateGroup(sessionID, name string, participants []string, description string) (*ports.GroupInfo, error) {
 	client := m.getClient(sessionID)
@@ -1067,8 +1339,9 @@ func (m *Manager) CreatePoll(sessionID, to, name string, options []string, selec
 	}, nil
 }
 
-// SendPoll sends a poll message (compatible with message handlers)
-func (m *Manager) SendPoll(sessionID, to, name string, options []string, selectableCount int) (*MessageResult, error) {
+// SendPoll sends a poll message (compatible with message handlers). A selectable count of 0 lets voters
+// pick any number of options. The poll definition is stored so votes can be mapped back to option text.
+func (m *Manager) SendPoll(sessionID, to, name string, options []string, selectableCount int, expiresAt *time.Time) (*MessageResult, error) {
 	client := m.getClient(sessionID)
 	if client == nil {
 		return nil, fmt.Errorf("session %s not found", sessionID)
@@ -1096,7 +1369,7 @@ func (m *Manager) SendPoll(sessionID, to, name string, options []string, selecta
 		return nil, fmt.Errorf("maximum 12 options allowed")
 	}
 
-	if selectableCount < 1 {
+	if selectableCount < 0 {
 		selectableCount = 1 // Default to single selection
 	}
 
@@ -1111,11 +1384,13 @@ func (m *Manager) SendPoll(sessionID, to, name string, options []string, selecta
 	pollMessage := client.GetClient().BuildPollCreation(name, options, selectableCount)
 
 	// Send the poll
-	resp, err := client.GetClient().SendMessage(context.Background(), toJID, pollMessage, whatsmeow.SendRequestExtra{ID: msgID})
+	resp, err := client.sendMessage(context.Background(), toJID, pollMessage, whatsmeow.SendRequestExtra{ID: msgID})
 	if err != nil {
 		return nil, fmt.Errorf("failed to send poll: %w", err)
 	}
 
+	m.recordPoll(poll.NewPoll(sessionID, resp.ID, toJID.String(), name, options, selectableCount, expiresAt))
+
 	return &MessageResult{
 		MessageID: resp.ID,
 		Status:    "sent",
@@ -1141,7 +1416,8 @@ func (m *Manager) GetUserJID(sessionID string) (string, error) {
 	return userJID.String(), nil
 }
 
-func (m *Manager) VotePoll(sessionID, to, pollMessageID string, selectedOptions []string) (*ports.MessageInfo, error) {
+// VotePoll votes on a poll; options of polls sent by the session are matched against the stored definition
+func (m *Manager) VotePoll(sessionID, to, pollMessageID, participant string, fromMe bool, selectedOptions []string) (*MessageResult, error) {
 	client := m.getClient(sessionID)
 	if client == nil {
 		return nil, fmt.Errorf("session %s not found", sessionID)
@@ -1151,15 +1427,20 @@ func (m *Manager) VotePoll(sessionID, to, pollMessageID string, selectedOptions
 	}
 
 	ctx := context.Background()
-	messageInfo, err := client.VotePoll(ctx, to, pollMessageID, selectedOptions)
+	options, err := m.resolvePollOptions(ctx, sessionID, pollMessageID, selectedOptions)
 	if err != nil {
 		return nil, err
 	}
 
-	return &ports.MessageInfo{
-		ID:        messageInfo.ID,
-		Timestamp: messageInfo.Timestamp,
-		Chat:      to,
+	resp, err := client.VotePoll(ctx, to, pollMessageID, participant, fromMe, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MessageResult{
+		MessageID: resp.ID,
+		Status:    "sent",
+		Timestamp: resp.Timestamp,
 	}, nil
 }
 
@@ -1194,7 +1475,9 @@ type TextMessageResult struct {
 	Timestamp time.Time
 }
 
-func (m *Manager) SendTextMessage(sessionID, to, text string, contextInfo *appMessage.ContextInfo) (*TextMessageResult, error) {
+// SendTextMessage sends a text message. With mentionAll the message mentions every participant of
+// the group it is sent to, which requires the account to be one of the group's admins.
+func (m *Manager) SendTextMessage(sessionID, to, text string, contextInfo *appMessage.ContextInfo, mentionAll bool) (*TextMessageResult, error) {
 	// Validate session and parse JID
 	client, recipientJID, err := m.validateTextMessageRequest(sessionID, to)
 	if err != nil {
@@ -1204,6 +1487,13 @@ func (m *Manager) SendTextMessage(sessionID, to, text string, contextInfo *appMe
 	// Create message with optional context
 	messageID, msg := m.createTextMessage(client, text, contextInfo)
 
+	if mentionAll {
+		msg, err = m.mentionAllParticipants(client, recipientJID, msg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Send message with Brazilian number fallback
 	resp, finalJID, err := m.sendTextMessageWithFallback(client, recipientJID, msg, messageID, sessionID, to)
 	if err != nil {
@@ -1272,9 +1562,68 @@ func (m *Manager) createTextMessage(client *WameowClient, text string, contextIn
 	return messageID, msg
 }
 
+// mentionAllParticipants turns a text message into a mention of the whole group. Participants are
+// listed in MentionedJID so clients without group mentions still notify everyone, and
+// NonJIDMentions marks the mention of the group itself, rendered as @everyone.
+func (m *Manager) mentionAllParticipants(client *WameowClient, groupJID types.JID, msg *waE2E.Message) (*waE2E.Message, error) {
+	if groupJID.Server != types.GroupServer {
+		return nil, message.ErrMentionAllNotGroup
+	}
+
+	groupInfo, err := client.GetClient().GetGroupInfo(groupJID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group info: %w", err)
+	}
+
+	if !isGroupAdmin(client, groupInfo) {
+		return nil, message.ErrMentionAllRequiresAdmin
+	}
+
+	mentioned := make([]string, 0, len(groupInfo.Participants))
+	for _, participant := range groupInfo.Participants {
+		mentioned = append(mentioned, participant.JID.String())
+	}
+
+	if msg.ExtendedTextMessage == nil {
+		msg = &waE2E.Message{
+			ExtendedTextMessage: &waE2E.ExtendedTextMessage{
+				Text: msg.Conversation,
+			},
+		}
+	}
+	if msg.ExtendedTextMessage.ContextInfo == nil {
+		msg.ExtendedTextMessage.ContextInfo = &waE2E.ContextInfo{}
+	}
+	msg.ExtendedTextMessage.ContextInfo.MentionedJID = mentioned
+	msg.ExtendedTextMessage.ContextInfo.NonJIDMentions = proto.Uint32(1)
+
+	return msg, nil
+}
+
+// isGroupAdmin reports whether the account of client is an admin of the group, matching both its
+// phone number and its LID since groups may address participants by either
+func isGroupAdmin(client *WameowClient, groupInfo *types.GroupInfo) bool {
+	store := client.GetClient().Store
+	if store.ID == nil {
+		return false
+	}
+
+	for _, participant := range groupInfo.Participants {
+		if !participant.IsAdmin && !participant.IsSuperAdmin {
+			continue
+		}
+		user := participant.JID.User
+		if user == store.ID.User || (!store.LID.IsEmpty() && user == store.LID.User) ||
+			participant.PhoneNumber.User == store.ID.User {
+			return true
+		}
+	}
+	return false
+}
+
 // sendTextMessageWithFallback sends text message with Brazilian number fallback
 func (m *Manager) sendTextMessageWithFallback(client *WameowClient, recipientJID types.JID, msg *waE2E.Message, messageID, sessionID, to string) (whatsmeow.SendResponse, types.JID, error) {
-	resp, err := client.GetClient().SendMessage(context.Background(), recipientJID, msg, whatsmeow.SendRequestExtra{ID: messageID})
+	resp, err := client.sendMessage(context.Background(), recipientJID, msg, whatsmeow.SendRequestExtra{ID: messageID})
 	if err != nil {
 		// Try Brazilian alternative number format
 		if altResp, altJID, altErr := m.tryBrazilianAlternative(client, msg, messageID, sessionID, to); altErr == nil {
@@ -1306,7 +1655,7 @@ func (m *Manager) tryBrazilianAlternative(client *WameowClient, msg *waE2E.Messa
 	}
 
 	// Try sending with the alternative number
-	resp, err := client.GetClient().SendMessage(context.Background(), altRecipientJID, msg, whatsmeow.SendRequestExtra{ID: messageID})
+	resp, err := client.sendMessage(context.Background(), altRecipientJID, msg, whatsmeow.SendRequestExtra{ID: messageID})
 	if err != nil {
 		return whatsmeow.SendResponse{}, types.EmptyJID, err
 	}
@@ -1339,7 +1688,7 @@ func (m *Manager) logAndReturnTextResult(sessionID, to, messageID string, contex
 }
 
 // SendMessage sends a message with optional context info for replies
-func (m *Manager) SendMessage(sessionID, to, messageType, body, caption, file, filename string, latitude, longitude float64, contactName, contactPhone string, contextInfo *message.ContextInfo) (*message.SendResult, error) {
+func (m *Manager) SendMessage(sessionID, to, messageType, body, caption, file, filename, mimeType string, latitude, longitude float64, contactName, contactPhone string, contextInfo *message.ContextInfo) (*message.SendResult, error) {
 	client := m.getClient(sessionID)
 	if client == nil {
 		return nil, fmt.Errorf("session %s not found", sessionID)
@@ -1364,7 +1713,7 @@ func (m *Manager) SendMessage(sessionID, to, messageType, body, caption, file, f
 
 	switch messageType {
 	case "text":
-		textResult, err := m.SendTextMessage(sessionID, to, body, appContextInfo)
+		textResult, err := m.SendTextMessage(sessionID, to, body, appContextInfo, false)
 		if err != nil {
 			return nil, err
 		}
@@ -1374,13 +1723,13 @@ func (m *Manager) SendMessage(sessionID, to, messageType, body, caption, file, f
 			Timestamp: textResult.Timestamp,
 		}, nil
 	case "image":
-		resp, err = client.SendImageMessage(ctx, to, file, caption, appContextInfo)
+		resp, err = client.SendImageMessage(ctx, to, file, mimeType, caption, appContextInfo)
 	case "audio":
-		resp, err = client.SendAudioMessage(ctx, to, file, appContextInfo)
+		resp, err = client.SendAudioMessage(ctx, to, file, mimeType, appContextInfo)
 	case "video":
-		resp, err = client.SendVideoMessage(ctx, to, file, caption, appContextInfo)
+		resp, err = client.SendVideoMessage(ctx, to, file, mimeType, caption, appContextInfo)
 	case "document":
-		resp, err = client.SendDocumentMessage(ctx, to, file, filename, caption, appContextInfo)
+		resp, err = client.SendDocumentMessage(ctx, to, file, mimeType, filename, caption, appContextInfo)
 	case "location":
 		resp, err = client.SendLocationMessage(ctx, to, latitude, longitude, body)
 	case "contact":
@@ -1654,6 +2003,73 @@ func (m *Manager) SetWebhookHandler(handler WebhookEventHandler) {
 	m.logger.Info("Webhook handler configured for wameow manager")
 }
 
+// SetFloodProtection enables inbound flood detection for all sessions; MaxMessages 0 disables it
+func (m *Manager) SetFloodProtection(config FloodConfig) {
+	m.floodGuard = NewFloodGuard(config)
+	if m.floodGuard != nil {
+		m.logger.InfoWithFields("Inbound flood protection enabled", map[string]interface{}{
+			"max_messages": config.MaxMessages,
+			"window":       config.Window.String(),
+			"cooldown":     config.Cooldown.String(),
+			"suppress":     config.Suppress,
+		})
+	}
+}
+
+// OfflineQueue holds sends accepted while a session was disconnected
+type OfflineQueue interface {
+	// SessionConnected is called whenever a session connects so its queued messages are sent
+	SessionConnected(sessionID string)
+}
+
+// PrivacyPolicy tells which sessions keep message content out of the logs
+type PrivacyPolicy interface {
+	RedactsLogs(sessionID string) bool
+}
+
+// SetPrivacy makes the event handlers leave message content of sessions in privacy mode out of the logs
+func (m *Manager) SetPrivacy(policy PrivacyPolicy) {
+	m.privacy = policy
+}
+
+func (m *Manager) redactsLogs(sessionID string) bool {
+	return m.privacy != nil && m.privacy.RedactsLogs(sessionID)
+}
+
+// SetOfflineQueue makes the manager flush the offline queue of a session when it connects
+func (m *Manager) SetOfflineQueue(queue OfflineQueue) {
+	m.offlineQueue = queue
+}
+
+// SetContactChangeFeed makes the manager record contact changes for polling CRMs
+func (m *Manager) SetContactChangeFeed(feed *contact.ChangeFeed) {
+	m.contactFeed = feed
+}
+
+// SetPollService makes the manager store the definitions of the polls it sends and receives
+func (m *Manager) SetPollService(service *poll.Service) {
+	m.pollService = service
+	m.logger.Info("Poll service configured for wameow manager")
+}
+
+// recordPoll stores a poll definition; a failure is logged but does not fail the send or the event
+func (m *Manager) recordPoll(p *poll.Poll) {
+	if m.pollService == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := m.pollService.RecordPoll(ctx, p); err != nil {
+		m.logger.WarnWithFields("Failed to store poll definition", map[string]interface{}{
+			"session_id": p.SessionID,
+			"message_id": p.MessageID,
+			"error":      err.Error(),
+		})
+	}
+}
+
 // SetChatwootManager sets the global Chatwoot manager for all sessions
 func (m *Manager) SetChatwootManager(manager ChatwootManager) {
 	m.chatwootManager = manager
@@ -0,0 +1,115 @@
+package wameow
+
+import (
+	"time"
+
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types/events"
+
+	"zpwoot/internal/domain/history"
+)
+
+// ChatEphemeralEventType is the webhook event carrying a change of the disappearing messages timer of a chat
+const ChatEphemeralEventType = "chat.ephemeral"
+
+// ChatEphemeral is a change of the disappearing messages timer of a chat; Duration is 0 when they were
+// turned off
+type ChatEphemeral struct {
+	ChatJID  string `json:"chatJid"`
+	Enabled  bool   `json:"enabled"`
+	Duration uint32 `json:"duration"` // seconds
+	// ChangedBy is the participant who changed the timer, when known
+	ChangedBy string    `json:"changedBy,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// WebhookEventType implements webhookEventNamer
+func (e *ChatEphemeral) WebhookEventType() string {
+	return ChatEphemeralEventType
+}
+
+// MessageEphemeral is the disappearing messages metadata added to Message webhook payloads as "ephemeral"
+type MessageEphemeral struct {
+	Duration  uint32    `json:"duration"` // seconds
+	ExpiresAt time.Time `json:"expiresAt"`
+	// SettingTimestamp is when the timer of the chat was last changed, when the sender included it
+	SettingTimestamp *time.Time `json:"settingTimestamp,omitempty"`
+}
+
+// handleEphemeralSetting delivers a change of the disappearing messages timer of a chat as a chat.ephemeral
+// event; groups report theirs through GroupInfo instead
+func (h *EventHandler) handleEphemeralSetting(evt *events.Message, sessionID string) {
+	duration := evt.Message.GetProtocolMessage().GetEphemeralExpiration()
+	setting := &ChatEphemeral{
+		ChatJID:   evt.Info.Chat.ToNonAD().String(),
+		Enabled:   duration > 0,
+		Duration:  duration,
+		ChangedBy: evt.Info.Sender.ToNonAD().String(),
+		Timestamp: evt.Info.Timestamp,
+	}
+
+	h.deliverChatEphemeral(setting, sessionID)
+}
+
+// handleGroupEphemeral delivers a change of the disappearing messages timer of a group as a chat.ephemeral
+// event
+func (h *EventHandler) handleGroupEphemeral(evt *events.GroupInfo, sessionID string) {
+	setting := &ChatEphemeral{
+		ChatJID:   evt.JID.ToNonAD().String(),
+		Enabled:   evt.Ephemeral.IsEphemeral,
+		Timestamp: evt.Timestamp,
+	}
+	if setting.Enabled {
+		setting.Duration = evt.Ephemeral.DisappearingTimer
+	}
+	if evt.Sender != nil {
+		setting.ChangedBy = evt.Sender.ToNonAD().String()
+	}
+
+	h.deliverChatEphemeral(setting, sessionID)
+}
+
+func (h *EventHandler) deliverChatEphemeral(setting *ChatEphemeral, sessionID string) {
+	h.logger.InfoWithFields("Disappearing messages timer changed", map[string]interface{}{
+		"session_id": sessionID,
+		"chat":       setting.ChatJID,
+		"enabled":    setting.Enabled,
+		"duration":   setting.Duration,
+		"changed_by": setting.ChangedBy,
+	})
+
+	h.deliverToWebhook(setting, sessionID)
+}
+
+// webhookEphemeral returns the disappearing messages metadata of a received message, or nil when its chat
+// has no timer
+func webhookEphemeral(evt *events.Message) *MessageEphemeral {
+	ephemeral := historyEphemeral(evt.Message, evt.Info.Timestamp)
+	if ephemeral == nil {
+		return nil
+	}
+
+	metadata := &MessageEphemeral{
+		Duration:  ephemeral.Duration,
+		ExpiresAt: ephemeral.ExpiresAt,
+	}
+	if ts := messageContextInfo(evt.Message).GetEphemeralSettingTimestamp(); ts > 0 {
+		settingTime := time.Unix(ts, 0)
+		metadata.SettingTimestamp = &settingTime
+	}
+	return metadata
+}
+
+// historyEphemeral returns the disappearing messages timer a message was sent with, or nil when its chat has
+// none. WhatsApp sends the timer of the chat in the context info of every message.
+func historyEphemeral(msg *waE2E.Message, timestamp time.Time) *history.Ephemeral {
+	duration := messageContextInfo(msg).GetExpiration()
+	if duration == 0 {
+		return nil
+	}
+
+	return &history.Ephemeral{
+		Duration:  duration,
+		ExpiresAt: timestamp.Add(time.Duration(duration) * time.Second),
+	}
+}
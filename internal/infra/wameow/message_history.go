@@ -0,0 +1,251 @@
+package wameow
+
+import (
+	"context"
+	"time"
+
+	"zpwoot/internal/domain/history"
+	"zpwoot/platform/logger"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// historyRecordTimeout bounds how long storing a message may hold up the event handler or the send
+const historyRecordTimeout = 5 * time.Second
+
+// MessageHistory stores the messages sessions send and receive, with their edits, revokes and pins
+type MessageHistory interface {
+	RecordMessage(ctx context.Context, message *history.Message) error
+	RecordEdit(ctx context.Context, sessionID, chatJID, messageID, text string, editedAt time.Time) error
+	RecordRevoke(ctx context.Context, sessionID, chatJID, messageID string, revokedAt time.Time) error
+	RecordPin(ctx context.Context, sessionID, chatJID, messageID string, pin *history.Pin) error
+	RecordActor(ctx context.Context, sessionID, messageID string, actor *history.Actor) error
+}
+
+// SetMessageHistory stores every message sent or received through a session in store; nil keeps no
+// history. It applies to the sessions created afterwards, so it is set before the sessions are started
+func (m *Manager) SetMessageHistory(store MessageHistory) {
+	m.messageHistory = store
+}
+
+// recordReceivedMessage stores a message event, which includes messages sent from the phone or other devices
+func (m *Manager) recordReceivedMessage(sessionID string, info types.MessageInfo, message *waE2E.Message) {
+	recordMessage(m.messageHistory, m.logger, sessionID, info.Chat, info.Sender, info.ID, info.IsFromMe, info.Timestamp, message)
+}
+
+// recordSentMessage stores a message the session just sent to chat
+func (c *WameowClient) recordSentMessage(chat types.JID, resp whatsmeow.SendResponse, message *waE2E.Message) {
+	recordMessage(c.messageHistory, c.logger, c.sessionID, chat, resp.Sender, resp.ID, true, resp.Timestamp, message)
+}
+
+// recordMessage stores a message in store, or applies it to the stored message it edits, revokes or pins; a
+// failure is logged but never fails the send or the event
+func recordMessage(store MessageHistory, logger *logger.Logger, sessionID string, chat, sender types.JID, messageID string, fromMe bool, timestamp time.Time, message *waE2E.Message) {
+	if store == nil || message == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), historyRecordTimeout)
+	defer cancel()
+
+	message = unwrapHistoryMessage(message)
+	chatJID := chat.ToNonAD().String()
+
+	var err error
+	if pinMsg := message.GetPinInChatMessage(); pinMsg != nil {
+		messageID = pinMsg.GetKey().GetID()
+		err = store.RecordPin(ctx, sessionID, chatJID, messageID, historyPin(message, sender, timestamp))
+	} else if protocolMsg := message.GetProtocolMessage(); protocolMsg != nil {
+		targetID := protocolMsg.GetKey().GetID()
+		switch protocolMsg.GetType() {
+		case waE2E.ProtocolMessage_REVOKE:
+			err = store.RecordRevoke(ctx, sessionID, chatJID, targetID, timestamp)
+		case waE2E.ProtocolMessage_MESSAGE_EDIT:
+			editedAt := timestamp
+			if ms := protocolMsg.GetTimestampMS(); ms > 0 {
+				editedAt = time.UnixMilli(ms)
+			}
+			err = store.RecordEdit(ctx, sessionID, chatJID, targetID, messageText(protocolMsg.GetEditedMessage()), editedAt)
+		default:
+			return
+		}
+		messageID = targetID
+	} else {
+		entry := historyEntry(sessionID, chat, sender, messageID, fromMe, timestamp, message)
+		if entry == nil {
+			return
+		}
+		err = store.RecordMessage(ctx, entry)
+	}
+
+	if err != nil && logger != nil {
+		logger.WarnWithFields("Failed to store message history", map[string]interface{}{
+			"session_id": sessionID,
+			"chat_jid":   chatJID,
+			"message_id": messageID,
+			"error":      err.Error(),
+		})
+	}
+}
+
+// unwrapHistoryMessage returns the message an edit or view-once wrapper carries, or message itself
+func unwrapHistoryMessage(message *waE2E.Message) *waE2E.Message {
+	if edited := message.GetEditedMessage().GetMessage(); edited != nil {
+		message = edited
+	}
+	if viewOnce := message.GetViewOnceMessage().GetMessage(); viewOnce != nil {
+		message = viewOnce
+	}
+	return message
+}
+
+// historyEntry returns the stored form of an unwrapped message sent in chat, or nil for messages that are
+// not part of the chat history
+func historyEntry(sessionID string, chat, sender types.JID, messageID string, fromMe bool, timestamp time.Time, message *waE2E.Message) *history.Message {
+	entry := historyMessage(message)
+	if entry == nil {
+		return nil
+	}
+	entry.SessionID = sessionID
+	entry.ChatJID = chat.ToNonAD().String()
+	entry.MessageID = messageID
+	entry.FromMe = fromMe
+	entry.Timestamp = timestamp
+	entry.Ephemeral = historyEphemeral(message, timestamp)
+	if !sender.IsEmpty() {
+		entry.SenderJID = sender.ToNonAD().String()
+	}
+	return entry
+}
+
+// recordMessageActor attributes a stored message the session sent to the actor of ctx. The message is
+// stored while it is sent, so this runs after the send returns; a failure is only logged.
+func (m *Manager) recordMessageActor(ctx context.Context, sessionID, messageID string) {
+	actor := history.ActorFromContext(ctx)
+	if actor == nil || messageID == "" || m.messageHistory == nil {
+		return
+	}
+
+	recordCtx, cancel := context.WithTimeout(context.Background(), historyRecordTimeout)
+	defer cancel()
+
+	if err := m.messageHistory.RecordActor(recordCtx, sessionID, messageID, actor); err != nil {
+		m.logger.WarnWithFields("Failed to store message actor", map[string]interface{}{
+			"session_id": sessionID,
+			"message_id": messageID,
+			"actor_type": actor.Type,
+			"error":      err.Error(),
+		})
+	}
+}
+
+// historyMessage returns the stored form of a message, or nil for messages that are not part of the chat
+// history, such as reactions, poll votes and key distribution messages
+func historyMessage(msg *waE2E.Message) *history.Message {
+	if msg.GetReactionMessage() != nil || msg.GetPollUpdateMessage() != nil {
+		return nil
+	}
+
+	messageType, _ := describeMessage(msg)
+	entry := &history.Message{
+		Type:  messageType,
+		Text:  messageText(msg),
+		Media: messageMedia(msg),
+	}
+
+	if poll := pollCreation(msg); poll != nil {
+		entry.Type = "poll"
+		entry.Text = poll.GetName()
+	}
+	if entry.Type == MessageTypeText && entry.Text == "" {
+		return nil
+	}
+
+	if contextInfo := messageContextInfo(msg); contextInfo != nil {
+		entry.Context = &history.ContextInfo{
+			QuotedMessageID:   contextInfo.GetStanzaID(),
+			QuotedParticipant: contextInfo.GetParticipant(),
+			Mentions:          contextInfo.GetMentionedJID(),
+			Forwarded:         contextInfo.GetIsForwarded(),
+		}
+		if entry.Context.QuotedMessageID == "" && len(entry.Context.Mentions) == 0 && !entry.Context.Forwarded {
+			entry.Context = nil
+		}
+	}
+
+	return entry
+}
+
+// messageText returns the text or caption of a message, without the placeholders describeMessage uses
+func messageText(msg *waE2E.Message) string {
+	switch {
+	case msg.GetConversation() != "":
+		return msg.GetConversation()
+	case msg.GetExtendedTextMessage() != nil:
+		return msg.GetExtendedTextMessage().GetText()
+	case msg.GetImageMessage() != nil:
+		return msg.GetImageMessage().GetCaption()
+	case msg.GetVideoMessage() != nil:
+		return msg.GetVideoMessage().GetCaption()
+	case msg.GetDocumentMessage() != nil:
+		return msg.GetDocumentMessage().GetCaption()
+	case msg.GetLocationMessage() != nil:
+		return msg.GetLocationMessage().GetName()
+	case msg.GetContactMessage() != nil:
+		return msg.GetContactMessage().GetDisplayName()
+	case msg.GetListMessage() != nil:
+		return msg.GetListMessage().GetDescription()
+	case msg.GetListResponseMessage() != nil:
+		return msg.GetListResponseMessage().GetTitle()
+	case msg.GetInteractiveMessage() != nil:
+		return msg.GetInteractiveMessage().GetBody().GetText()
+	case msg.GetInteractiveResponseMessage() != nil:
+		return msg.GetInteractiveResponseMessage().GetBody().GetText()
+	case msg.GetTemplateMessage() != nil:
+		return msg.GetTemplateMessage().GetHydratedTemplate().GetHydratedContentText()
+	case msg.GetTemplateButtonReplyMessage() != nil:
+		return msg.GetTemplateButtonReplyMessage().GetSelectedDisplayText()
+	}
+	return ""
+}
+
+// messageMedia returns the attachment metadata of a media message, or nil for other messages
+func messageMedia(msg *waE2E.Message) *history.Media {
+	switch {
+	case msg.GetImageMessage() != nil:
+		m := msg.GetImageMessage()
+		return &history.Media{MimeType: m.GetMimetype(), FileLength: m.GetFileLength(), Width: m.GetWidth(),
+			Height: m.GetHeight(), DirectPath: m.GetDirectPath(), URL: m.GetURL()}
+	case msg.GetVideoMessage() != nil:
+		m := msg.GetVideoMessage()
+		return &history.Media{MimeType: m.GetMimetype(), FileLength: m.GetFileLength(), Width: m.GetWidth(),
+			Height: m.GetHeight(), Seconds: m.GetSeconds(), DirectPath: m.GetDirectPath(), URL: m.GetURL()}
+	case msg.GetAudioMessage() != nil:
+		m := msg.GetAudioMessage()
+		return &history.Media{MimeType: m.GetMimetype(), FileLength: m.GetFileLength(), Seconds: m.GetSeconds(),
+			DirectPath: m.GetDirectPath(), URL: m.GetURL()}
+	case msg.GetDocumentMessage() != nil:
+		m := msg.GetDocumentMessage()
+		return &history.Media{MimeType: m.GetMimetype(), FileName: m.GetFileName(), FileLength: m.GetFileLength(),
+			DirectPath: m.GetDirectPath(), URL: m.GetURL()}
+	case msg.GetStickerMessage() != nil:
+		m := msg.GetStickerMessage()
+		return &history.Media{MimeType: m.GetMimetype(), FileLength: m.GetFileLength(), Width: m.GetWidth(),
+			Height: m.GetHeight(), DirectPath: m.GetDirectPath(), URL: m.GetURL()}
+	}
+	return nil
+}
+
+func pollCreation(msg *waE2E.Message) *waE2E.PollCreationMessage {
+	switch {
+	case msg.GetPollCreationMessage() != nil:
+		return msg.GetPollCreationMessage()
+	case msg.GetPollCreationMessageV2() != nil:
+		return msg.GetPollCreationMessageV2()
+	case msg.GetPollCreationMessageV3() != nil:
+		return msg.GetPollCreationMessageV3()
+	}
+	return nil
+}
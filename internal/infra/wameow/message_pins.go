@@ -0,0 +1,203 @@
+package wameow
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mau.fi/whatsmeow/proto/waCommon"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+
+	"zpwoot/internal/domain/history"
+	"zpwoot/internal/domain/message"
+)
+
+// MessagePinEventType is the webhook event carrying a message pinned or unpinned in a chat from another
+// device or by another participant
+const MessagePinEventType = "message.pin"
+
+// MessagePin is a pin or unpin of a message. ExpiresAt is only set for pins.
+type MessagePin struct {
+	ChatJID   string `json:"chatJid"`
+	MessageID string `json:"messageId"`
+	// Participant is the sender of the pinned message in groups
+	Participant string     `json:"participant,omitempty"`
+	FromMe      bool       `json:"fromMe"`
+	Pinned      bool       `json:"pinned"`
+	PinnedBy    string     `json:"pinnedBy"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+	Timestamp   time.Time  `json:"timestamp"`
+}
+
+// WebhookEventType implements webhookEventNamer
+func (p *MessagePin) WebhookEventType() string {
+	return MessagePinEventType
+}
+
+// handleMessagePin delivers a pin or unpin made from the phone, another device or another participant as a
+// message.pin event
+func (h *EventHandler) handleMessagePin(evt *events.Message, sessionID string) {
+	pinMsg := evt.Message.GetPinInChatMessage()
+	key := pinMsg.GetKey()
+
+	pin := &MessagePin{
+		ChatJID:     evt.Info.Chat.ToNonAD().String(),
+		MessageID:   key.GetID(),
+		Participant: key.GetParticipant(),
+		FromMe:      key.GetFromMe(),
+		Pinned:      pinMsg.GetType() == waE2E.PinInChatMessage_PIN_FOR_ALL,
+		PinnedBy:    evt.Info.Sender.ToNonAD().String(),
+		Timestamp:   evt.Info.Timestamp,
+	}
+	if pin.Pinned {
+		expiresAt := evt.Info.Timestamp.Add(pinDuration(evt.Message))
+		pin.ExpiresAt = &expiresAt
+	}
+
+	h.logger.InfoWithFields("Message pin received", map[string]interface{}{
+		"session_id": sessionID,
+		"chat":       pin.ChatJID,
+		"message_id": pin.MessageID,
+		"pinned":     pin.Pinned,
+		"pinned_by":  pin.PinnedBy,
+	})
+
+	h.deliverToWebhook(pin, sessionID)
+}
+
+// PinMessage pins a message in a chat for duration, or unpins it when pin is false; participant is the
+// original sender in groups and fromMe marks our own messages
+func (c *WameowClient) PinMessage(ctx context.Context, to, messageID, participant string, fromMe, pin bool, duration time.Duration) (time.Time, error) {
+	if !c.client.IsLoggedIn() {
+		return time.Time{}, fmt.Errorf("client is not logged in")
+	}
+	if err := c.checkSendGate(); err != nil {
+		return time.Time{}, err
+	}
+
+	jid, err := c.parseJID(to)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid JID: %w", err)
+	}
+
+	if messageID == "" {
+		return time.Time{}, fmt.Errorf("message ID is required")
+	}
+
+	sender, err := c.resolveMessageSender(jid, participant, fromMe)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	c.logger.InfoWithFields("Pinning message", map[string]interface{}{
+		"session_id":  c.sessionID,
+		"to":          to,
+		"message_id":  messageID,
+		"participant": participant,
+		"pin":         pin,
+		"duration":    duration.String(),
+	})
+
+	pinnedAt := time.Now()
+	pinMessage := buildPinMessage(c.client.BuildMessageKey(jid, sender, messageID), pin, duration, pinnedAt)
+
+	resp, err := c.client.SendMessage(ctx, jid, pinMessage)
+	if err != nil {
+		c.logger.ErrorWithFields("Failed to pin message", map[string]interface{}{
+			"session_id": c.sessionID,
+			"to":         to,
+			"message_id": messageID,
+			"pin":        pin,
+			"error":      err.Error(),
+		})
+		return time.Time{}, err
+	}
+
+	c.recordSentMessage(jid, resp, pinMessage)
+
+	return pinnedAt, nil
+}
+
+// buildPinMessage builds the pin or unpin of the message with key. The duration travels in the message
+// context info, the way official clients send it.
+func buildPinMessage(key *waCommon.MessageKey, pin bool, duration time.Duration, pinnedAt time.Time) *waE2E.Message {
+	pinType := waE2E.PinInChatMessage_UNPIN_FOR_ALL
+	if pin {
+		pinType = waE2E.PinInChatMessage_PIN_FOR_ALL
+	}
+
+	msg := &waE2E.Message{
+		PinInChatMessage: &waE2E.PinInChatMessage{
+			Key:               key,
+			Type:              pinType.Enum(),
+			SenderTimestampMS: proto.Int64(pinnedAt.UnixMilli()),
+		},
+	}
+	if pin {
+		msg.MessageContextInfo = &waE2E.MessageContextInfo{
+			MessageAddOnDurationInSecs: proto.Uint32(uint32(duration.Seconds())),
+		}
+	}
+
+	return msg
+}
+
+// pinDuration returns how long a received pin lasts, falling back to the default duration for clients
+// that leave it out
+func pinDuration(msg *waE2E.Message) time.Duration {
+	if seconds := msg.GetMessageContextInfo().GetMessageAddOnDurationInSecs(); seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return message.PinDurations[message.DefaultPinDuration]
+}
+
+// historyPin returns the pinned state a pin message gives the message it targets, or nil for an unpin
+func historyPin(msg *waE2E.Message, sender types.JID, timestamp time.Time) *history.Pin {
+	if msg.GetPinInChatMessage().GetType() != waE2E.PinInChatMessage_PIN_FOR_ALL {
+		return nil
+	}
+
+	pin := &history.Pin{
+		PinnedAt:  timestamp,
+		ExpiresAt: timestamp.Add(pinDuration(msg)),
+	}
+	if !sender.IsEmpty() {
+		pin.PinnedBy = sender.ToNonAD().String()
+	}
+	return pin
+}
+
+// PinMessage pins a message in a chat for duration, or unpins it when pin is false
+func (m *Manager) PinMessage(sessionID, to, messageID, participant string, fromMe, pin bool, duration time.Duration) (*message.SendResult, error) {
+	client := m.getClient(sessionID)
+	if client == nil {
+		return nil, fmt.Errorf("session %s not found", sessionID)
+	}
+	if !client.IsLoggedIn() {
+		return nil, fmt.Errorf("session %s is not logged in", sessionID)
+	}
+
+	status := "unpinned"
+	if pin {
+		status = "pinned"
+	}
+
+	ctx := context.Background()
+	pinnedAt, err := client.PinMessage(ctx, to, messageID, participant, fromMe, pin, duration)
+	if err != nil {
+		return &message.SendResult{
+			Status:    "failed",
+			Error:     err.Error(),
+			Timestamp: time.Now(),
+		}, err
+	}
+
+	return &message.SendResult{
+		MessageID: messageID,
+		Status:    status,
+		Timestamp: pinnedAt,
+	}, nil
+}
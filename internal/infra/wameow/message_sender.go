@@ -5,12 +5,14 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"time"
 
 	appMessage "zpwoot/internal/app/message"
 	"zpwoot/platform/logger"
 
 	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -19,17 +21,47 @@ type messageSender struct {
 	client    *whatsmeow.Client
 	logger    *logger.Logger
 	validator *JIDValidator
+	metrics   *clientMetrics
+	// prepare checks a send against the session's gate, guard and throttle and returns where it goes
+	prepare func(ctx context.Context, to types.JID, message *waE2E.Message) (types.JID, error)
+	// sent records a message once it was sent
+	sent func(to types.JID, resp whatsmeow.SendResponse, message *waE2E.Message)
 }
 
 // NewMessageSender creates a new message sender
-func NewMessageSender(client *whatsmeow.Client, logger *logger.Logger) MessageSender {
+func NewMessageSender(
+	client *whatsmeow.Client,
+	logger *logger.Logger,
+	metrics *clientMetrics,
+	prepare func(ctx context.Context, to types.JID, message *waE2E.Message) (types.JID, error),
+	sent func(to types.JID, resp whatsmeow.SendResponse, message *waE2E.Message),
+) MessageSender {
 	return &messageSender{
 		client:    client,
 		logger:    logger,
 		validator: NewJIDValidator(),
+		metrics:   metrics,
+		prepare:   prepare,
+		sent:      sent,
 	}
 }
 
+// send sends through whatsmeow and reports the send to the session metrics
+func (ms *messageSender) send(ctx context.Context, jid types.JID, message *waE2E.Message) (whatsmeow.SendResponse, error) {
+	jid, err := ms.prepare(ctx, jid, message)
+	if err != nil {
+		return whatsmeow.SendResponse{}, err
+	}
+
+	started := time.Now()
+	resp, err := ms.client.SendMessage(ctx, jid, message)
+	ms.metrics.sendFinished(jid, resp, started, err)
+	if err == nil {
+		ms.sent(jid, resp, message)
+	}
+	return resp, err
+}
+
 // SendText sends a text message with optional context info
 func (ms *messageSender) SendText(ctx context.Context, to, body string, contextInfo *appMessage.ContextInfo) (*whatsmeow.SendResponse, error) {
 	if !ms.client.IsLoggedIn() {
@@ -59,7 +91,7 @@ func (ms *messageSender) SendText(ctx context.Context, to, body string, contextI
 		"has_reply": contextInfo != nil,
 	})
 
-	resp, err := ms.client.SendMessage(ctx, jid, message)
+	resp, err := ms.send(ctx, jid, message)
 	if err != nil {
 		ms.logger.ErrorWithFields("Failed to send text message", map[string]interface{}{
 			"to":    to,
@@ -97,6 +129,7 @@ func (ms *messageSender) SendMedia(ctx context.Context, to, filePath string, med
 	if err != nil {
 		return nil, fmt.Errorf("failed to upload media: %w", err)
 	}
+	ms.metrics.mediaUploaded(whatsmeowMediaType, len(data))
 
 	message := ms.createMediaMessage(mediaType, uploaded, options)
 
@@ -107,7 +140,7 @@ func (ms *messageSender) SendMedia(ctx context.Context, to, filePath string, med
 		"has_reply": options.ContextInfo != nil,
 	})
 
-	resp, err := ms.client.SendMessage(ctx, jid, message)
+	resp, err := ms.send(ctx, jid, message)
 	if err != nil {
 		ms.logger.ErrorWithFields("Failed to send media message", map[string]interface{}{
 			"to":    to,
@@ -151,7 +184,7 @@ func (ms *messageSender) SendContact(ctx context.Context, to string, contact Con
 		"contact_name": contact.Name,
 	})
 
-	resp, err := ms.client.SendMessage(ctx, jid, message)
+	resp, err := ms.send(ctx, jid, message)
 	if err != nil {
 		ms.logger.ErrorWithFields("Failed to send contact message", map[string]interface{}{
 			"to":    to,
@@ -194,7 +227,7 @@ func (ms *messageSender) SendLocation(ctx context.Context, to string, lat, lng f
 		"address":   address,
 	})
 
-	resp, err := ms.client.SendMessage(ctx, jid, message)
+	resp, err := ms.send(ctx, jid, message)
 	if err != nil {
 		ms.logger.ErrorWithFields("Failed to send location message", map[string]interface{}{
 			"to":    to,
@@ -384,3 +417,21 @@ func (ms *messageSender) createStickerMessage(uploaded whatsmeow.UploadResponse,
 		},
 	}
 }
+
+// mimeTypeOr returns mimeType, or fallback when the type of the media is unknown
+func mimeTypeOr(mimeType, fallback string) string {
+	if mimeType == "" || mimeType == "application/octet-stream" {
+		return fallback
+	}
+	return mimeType
+}
+
+// audioMimeType returns the type an audio is sent with; Ogg audio must declare the Opus codec to be
+// played as a voice message
+func audioMimeType(mimeType string) string {
+	switch mimeType {
+	case "audio/ogg", "application/ogg":
+		return "audio/ogg; codecs=opus"
+	}
+	return mimeTypeOr(mimeType, "audio/ogg; codecs=opus")
+}
@@ -0,0 +1,87 @@
+package wameow
+
+import (
+	"context"
+	"time"
+
+	"go.mau.fi/whatsmeow/proto/waE2E"
+)
+
+// MessageStats counts the messages sessions send and receive by type and direction
+type MessageStats interface {
+	RecordMessage(ctx context.Context, sessionID, msgType string, fromMe bool, at time.Time)
+}
+
+// SetMessageStats counts every message sent or received through a session in stats; nil disables it. It
+// applies to the sessions created afterwards, so it is set before the sessions are started
+func (m *Manager) SetMessageStats(stats MessageStats) {
+	m.messageStats = stats
+}
+
+// recordMessageStats counts a sent or received message in /metrics and in stats; messages that are not
+// shown in the chat, such as edits, revokes and key distribution messages, are not counted
+func recordMessageStats(stats MessageStats, sessionID string, fromMe bool, at time.Time, message *waE2E.Message) {
+	if message == nil {
+		return
+	}
+
+	msgType := statsMessageType(message)
+	if msgType == "" {
+		return
+	}
+
+	direction := "received"
+	if fromMe {
+		direction = "sent"
+	}
+	messagesTotal.WithLabelValues(sessionID, direction, msgType).Inc()
+
+	if stats == nil {
+		return
+	}
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), historyRecordTimeout)
+	defer cancel()
+
+	stats.RecordMessage(ctx, sessionID, msgType, fromMe, at)
+}
+
+// statsMessageType returns the type a message is counted as, or "" for messages that are not counted
+func statsMessageType(msg *waE2E.Message) string {
+	if viewOnce := msg.GetViewOnceMessage().GetMessage(); viewOnce != nil {
+		msg = viewOnce
+	}
+	if ephemeral := msg.GetEphemeralMessage().GetMessage(); ephemeral != nil {
+		msg = ephemeral
+	}
+
+	switch {
+	case msg.GetProtocolMessage() != nil:
+		return ""
+	case msg.GetReactionMessage() != nil:
+		return "reaction"
+	case msg.GetPollUpdateMessage() != nil:
+		return "poll_vote"
+	case pollCreation(msg) != nil:
+		return "poll"
+	case msg.GetTemplateMessage() != nil:
+		return "template"
+	case msg.GetButtonsMessage() != nil:
+		return "buttons"
+	case msg.GetButtonsResponseMessage() != nil, msg.GetTemplateButtonReplyMessage() != nil:
+		return "button_response"
+	case msg.GetInteractiveMessage() != nil:
+		return "interactive"
+	case msg.GetInteractiveResponseMessage() != nil:
+		return "interactive_response"
+	}
+
+	msgType, _ := describeMessage(msg)
+	if msgType == MessageTypeText && messageText(msg) == "" {
+		return ""
+	}
+	return msgType
+}
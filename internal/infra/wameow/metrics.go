@@ -0,0 +1,295 @@
+package wameow
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+
+	"zpwoot/platform/logger"
+)
+
+const (
+	// pendingReceiptTTL drops sent messages that never got a receipt, e.g. to numbers that are offline for days
+	pendingReceiptTTL = 24 * time.Hour
+	// maxPendingReceipts bounds the per-session tracking of messages awaiting a receipt
+	maxPendingReceipts = 10000
+)
+
+var (
+	connectsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "zpwoot_whatsmeow_connects_total",
+		Help: "WhatsApp websocket connections established",
+	}, []string{"session"})
+	reconnectsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "zpwoot_whatsmeow_reconnects_total",
+		Help: "WhatsApp websocket connections re-established after a disconnect",
+	}, []string{"session"})
+	disconnectsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "zpwoot_whatsmeow_disconnects_total",
+		Help: "WhatsApp websocket disconnections",
+	}, []string{"session"})
+	keepAliveTimeoutsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "zpwoot_whatsmeow_keepalive_timeouts_total",
+		Help: "WhatsApp websocket keepalive pings that timed out",
+	}, []string{"session"})
+	qrEventsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "zpwoot_whatsmeow_qr_events_total",
+		Help: "QR pairing events by type; code counts QR codes generated",
+	}, []string{"session", "event"})
+	receiptsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "zpwoot_whatsmeow_receipts_total",
+		Help: "Receipts received from contacts by type",
+	}, []string{"session", "type"})
+	pendingReceipts = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "zpwoot_whatsmeow_pending_receipts",
+		Help: "Sent messages still waiting for a delivery receipt",
+	}, []string{"session"})
+	mediaUploadBytesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "zpwoot_whatsmeow_media_upload_bytes_total",
+		Help: "Media bytes uploaded to WhatsApp servers",
+	}, []string{"session", "media_type"})
+	mediaDownloadBytesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "zpwoot_whatsmeow_media_download_bytes_total",
+		Help: "Media bytes downloaded from WhatsApp servers",
+	}, []string{"session", "media_type"})
+	slowSendsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "zpwoot_whatsmeow_slow_sends_total",
+		Help: "WhatsApp sends slower than the configured threshold",
+	}, []string{"session", "chat_type"})
+	messagesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "zpwoot_messages_total",
+		Help: "Messages sent and received by session, direction (sent or received) and message type",
+	}, []string{"session", "direction", "type"})
+	connectionState = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "zpwoot_whatsmeow_connected",
+		Help: "Whether the session's WhatsApp websocket is connected (1) or not (0)",
+	}, []string{"session"})
+)
+
+// SetSlowSendThreshold logs and counts WhatsApp sends slower than threshold; 0 disables it. It applies to
+// the sessions created afterwards, so it is set before the sessions are started
+func (m *Manager) SetSlowSendThreshold(threshold time.Duration) {
+	m.slowSendThreshold = threshold
+}
+
+// clientMetrics collects whatsmeow internals for one session
+type clientMetrics struct {
+	sessionID string
+	logger    *logger.Logger
+	// slowSendThreshold is the send duration from which sends are logged and counted; 0 disables it
+	slowSendThreshold time.Duration
+
+	mu            sync.Mutex
+	everConnected bool
+	pending       map[types.MessageID]time.Time
+	recentErrors  []ClientError
+}
+
+func newClientMetrics(sessionID string, logger *logger.Logger) *clientMetrics {
+	return &clientMetrics{
+		sessionID: sessionID,
+		logger:    logger,
+		pending:   make(map[types.MessageID]time.Time),
+	}
+}
+
+// handleEvent is registered as a whatsmeow event handler
+func (m *clientMetrics) handleEvent(evt interface{}) {
+	switch e := evt.(type) {
+	case *events.Connected:
+		connectsTotal.WithLabelValues(m.sessionID).Inc()
+		connectionState.WithLabelValues(m.sessionID).Set(1)
+		m.mu.Lock()
+		if m.everConnected {
+			reconnectsTotal.WithLabelValues(m.sessionID).Inc()
+		}
+		m.everConnected = true
+		m.mu.Unlock()
+	case *events.Disconnected:
+		disconnectsTotal.WithLabelValues(m.sessionID).Inc()
+		connectionState.WithLabelValues(m.sessionID).Set(0)
+	case *events.KeepAliveTimeout:
+		keepAliveTimeoutsTotal.WithLabelValues(m.sessionID).Inc()
+		m.recordError("keepalive", fmt.Sprintf("keepalive timed out %d times since %s", e.ErrorCount, e.LastSuccess.Format(time.RFC3339)))
+	case *events.ConnectFailure:
+		m.recordError("connect", fmt.Sprintf("connect failure %d: %s", e.Reason, e.Message))
+	case *events.StreamError:
+		m.recordError("stream", "stream error "+e.Code)
+	case *events.TemporaryBan:
+		m.recordError("connect", e.String())
+	case *events.LoggedOut:
+		connectionState.WithLabelValues(m.sessionID).Set(0)
+		m.recordError("connect", fmt.Sprintf("logged out by server (reason %d)", e.Reason))
+	case *events.StreamReplaced:
+		connectionState.WithLabelValues(m.sessionID).Set(0)
+		m.recordError("stream", "stream replaced by another client")
+	case *events.Receipt:
+		m.receiptReceived(e)
+	}
+}
+
+func (m *clientMetrics) receiptReceived(e *events.Receipt) {
+	if e.IsFromMe {
+		return
+	}
+
+	receiptType := string(e.Type)
+	if e.Type == types.ReceiptTypeDelivered {
+		receiptType = "delivered"
+	}
+	receiptsTotal.WithLabelValues(m.sessionID, receiptType).Inc()
+
+	switch e.Type {
+	case types.ReceiptTypeDelivered, types.ReceiptTypeRead, types.ReceiptTypePlayed:
+	default:
+		return
+	}
+
+	m.mu.Lock()
+	for _, id := range e.MessageIDs {
+		delete(m.pending, id)
+	}
+	count := len(m.pending)
+	m.mu.Unlock()
+
+	pendingReceipts.WithLabelValues(m.sessionID).Set(float64(count))
+}
+
+// sendFinished records a whatsmeow send: slow sends are logged and counted, and
+// successful ones start waiting for their delivery receipt
+func (m *clientMetrics) sendFinished(to types.JID, resp whatsmeow.SendResponse, started time.Time, err error) {
+	m.observeSendDuration(to, time.Since(started), err)
+	if err != nil {
+		m.recordError("send", err.Error())
+		return
+	}
+	m.messageSent(resp.ID)
+}
+
+func (m *clientMetrics) observeSendDuration(to types.JID, elapsed time.Duration, err error) {
+	threshold := m.slowSendThreshold
+	if threshold <= 0 || elapsed < threshold {
+		return
+	}
+
+	chatType := chatTypeLabel(to)
+	slowSendsTotal.WithLabelValues(m.sessionID, chatType).Inc()
+
+	fields := map[string]interface{}{
+		"session_id":   m.sessionID,
+		"chat_type":    chatType,
+		"to":           to.String(),
+		"duration_ms":  elapsed.Milliseconds(),
+		"threshold_ms": threshold.Milliseconds(),
+	}
+	if err != nil {
+		fields["error"] = err.Error()
+	}
+	m.logger.WarnWithFields("Slow WhatsApp send", fields)
+}
+
+// messageSent starts waiting for the receipt of a sent message
+func (m *clientMetrics) messageSent(id types.MessageID) {
+	if id == "" {
+		return
+	}
+
+	m.mu.Lock()
+	if len(m.pending) >= maxPendingReceipts {
+		m.prunePending()
+	}
+	if len(m.pending) < maxPendingReceipts {
+		m.pending[id] = time.Now()
+	}
+	count := len(m.pending)
+	m.mu.Unlock()
+
+	pendingReceipts.WithLabelValues(m.sessionID).Set(float64(count))
+}
+
+// prunePending drops expired entries; the caller must hold mu
+func (m *clientMetrics) prunePending() {
+	for id, sentAt := range m.pending {
+		if time.Since(sentAt) >= pendingReceiptTTL {
+			delete(m.pending, id)
+		}
+	}
+}
+
+// disconnected records a disconnect made on purpose, which whatsmeow reports with no event
+func (m *clientMetrics) disconnected() {
+	connectionState.WithLabelValues(m.sessionID).Set(0)
+}
+
+func (m *clientMetrics) qrEvent(event string) {
+	qrEventsTotal.WithLabelValues(m.sessionID, event).Inc()
+}
+
+func (m *clientMetrics) mediaUploaded(mediaType whatsmeow.MediaType, size int) {
+	mediaUploadBytesTotal.WithLabelValues(m.sessionID, mediaTypeLabel(mediaType)).Add(float64(size))
+}
+
+func (m *clientMetrics) mediaDownloaded(mediaType whatsmeow.MediaType, size int) {
+	mediaDownloadBytesTotal.WithLabelValues(m.sessionID, mediaTypeLabel(mediaType)).Add(float64(size))
+}
+
+// forget removes the session's series, e.g. after logout
+func (m *clientMetrics) forget() {
+	m.mu.Lock()
+	m.pending = make(map[types.MessageID]time.Time)
+	m.everConnected = false
+	m.mu.Unlock()
+
+	for _, counter := range []*prometheus.CounterVec{
+		connectsTotal, reconnectsTotal, disconnectsTotal, keepAliveTimeoutsTotal,
+		qrEventsTotal, receiptsTotal, mediaUploadBytesTotal, mediaDownloadBytesTotal, slowSendsTotal,
+		messagesTotal,
+	} {
+		counter.DeletePartialMatch(prometheus.Labels{"session": m.sessionID})
+	}
+	pendingReceipts.DeletePartialMatch(prometheus.Labels{"session": m.sessionID})
+	connectionState.DeletePartialMatch(prometheus.Labels{"session": m.sessionID})
+}
+
+// chatTypeLabel names the kind of destination, which decides how WhatsApp routes the send
+func chatTypeLabel(jid types.JID) string {
+	switch jid.Server {
+	case types.DefaultUserServer, types.HiddenUserServer:
+		return "user"
+	case types.GroupServer:
+		return "group"
+	case types.NewsletterServer:
+		return "newsletter"
+	case types.BroadcastServer:
+		return "broadcast"
+	default:
+		return "other"
+	}
+}
+
+func mediaTypeLabel(mediaType whatsmeow.MediaType) string {
+	switch mediaType {
+	case whatsmeow.MediaImage:
+		return "image"
+	case whatsmeow.MediaVideo:
+		return "video"
+	case whatsmeow.MediaAudio:
+		return "audio"
+	case whatsmeow.MediaDocument:
+		return "document"
+	case whatsmeow.MediaHistory:
+		return "history"
+	case whatsmeow.MediaAppState:
+		return "app_state"
+	case whatsmeow.MediaLinkThumbnail:
+		return "link_thumbnail"
+	default:
+		return "other"
+	}
+}
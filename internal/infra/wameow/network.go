@@ -0,0 +1,151 @@
+package wameow
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+
+	"golang.org/x/net/proxy"
+
+	"zpwoot/internal/domain/session"
+)
+
+// boundDialTimeout bounds opening a bound connection, including the proxy handshake
+const boundDialTimeout = 30 * time.Second
+
+// boundDialer opens a session's connections from a local address of the host, optionally tunnelling
+// them through the session's SOCKS5 proxy, so numbers can be spread across the addresses of a multi-IP
+// server. It implements the dialer whatsmeow accepts for SOCKS proxies, which covers both the
+// websocket and the media HTTP client.
+type boundDialer struct {
+	config *session.ProxyConfig
+}
+
+// newBoundDialer checks that the configured address or interface exists on this host
+func newBoundDialer(config *session.ProxyConfig) (*boundDialer, error) {
+	localIP, err := resolveLocalIP(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.LocalAddress != "" && !isHostAddress(localIP) {
+		return nil, fmt.Errorf("%w: local address %s is not assigned to any interface", session.ErrInvalidProxyConfig, config.LocalAddress)
+	}
+
+	return &boundDialer{config: config}, nil
+}
+
+func (d *boundDialer) Dial(network, addr string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, addr)
+}
+
+func (d *boundDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	// Interface addresses may change while the session runs, so they are resolved on every dial
+	localIP, err := resolveLocalIP(d.config)
+	if err != nil {
+		return nil, err
+	}
+
+	// The next hop must be reached over the family of the bound address
+	network = "tcp4"
+	if localIP.To4() == nil {
+		network = "tcp6"
+	}
+
+	forward := &net.Dialer{
+		Timeout:   boundDialTimeout,
+		KeepAlive: 30 * time.Second,
+		LocalAddr: &net.TCPAddr{IP: localIP},
+	}
+
+	if d.config.Type == "" {
+		return forward.DialContext(ctx, network, addr)
+	}
+
+	var auth *proxy.Auth
+	if d.config.Username != "" {
+		auth = &proxy.Auth{User: d.config.Username, Password: d.config.Password}
+	}
+
+	proxyAddr := net.JoinHostPort(d.config.Host, strconv.Itoa(d.config.Port))
+	dialer, err := proxy.SOCKS5(network, proxyAddr, auth, forward)
+	if err != nil {
+		return nil, fmt.Errorf("proxy %s: %w", proxyAddr, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, boundDialTimeout)
+	defer cancel()
+
+	conn, err := dialer.(proxy.ContextDialer).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("proxy %s from %s: %w", proxyAddr, localIP, err)
+	}
+	return conn, nil
+}
+
+// resolveLocalIP returns the configured local address, or the first address of the configured
+// interface, preferring IPv4
+func resolveLocalIP(config *session.ProxyConfig) (net.IP, error) {
+	if config.LocalAddress != "" {
+		ip := net.ParseIP(config.LocalAddress)
+		if ip == nil {
+			return nil, fmt.Errorf("%w: localAddress must be an IP address", session.ErrInvalidProxyConfig)
+		}
+		return ip, nil
+	}
+
+	iface, err := net.InterfaceByName(config.Interface)
+	if err != nil {
+		return nil, fmt.Errorf("%w: interface %s: %v", session.ErrInvalidProxyConfig, config.Interface, err)
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list addresses of interface %s: %w", config.Interface, err)
+	}
+
+	var fallback net.IP
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok || ipNet.IP.IsLinkLocalUnicast() {
+			continue
+		}
+		if ipNet.IP.To4() != nil {
+			return ipNet.IP, nil
+		}
+		if fallback == nil {
+			fallback = ipNet.IP
+		}
+	}
+	if fallback == nil {
+		return nil, fmt.Errorf("%w: interface %s has no usable address", session.ErrInvalidProxyConfig, config.Interface)
+	}
+	return fallback, nil
+}
+
+func isHostAddress(ip net.IP) bool {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return false
+	}
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// proxyURL returns the proxy address in the form whatsmeow's SetProxyAddress accepts
+func proxyURL(config *session.ProxyConfig) string {
+	u := &url.URL{
+		Scheme: config.Type,
+		Host:   net.JoinHostPort(config.Host, strconv.Itoa(config.Port)),
+	}
+	if config.Username != "" {
+		u.User = url.UserPassword(config.Username, config.Password)
+	}
+	return u.String()
+}
@@ -0,0 +1,159 @@
+package wameow
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// NewsletterMessageEventType is the webhook event carrying newsletter live updates
+const NewsletterMessageEventType = "newsletter.message"
+
+const (
+	// newsletterCountsTTL drops counts of newsletter messages that stopped receiving updates
+	newsletterCountsTTL = 24 * time.Hour
+	// maxNewsletterCounts bounds how many newsletter messages are tracked for deltas
+	maxNewsletterCounts = 10000
+)
+
+// webhookEventNamer is implemented by events zpwoot synthesizes, which are not named after a whatsmeow type
+type webhookEventNamer interface {
+	WebhookEventType() string
+}
+
+// NewsletterMessageUpdate is a live update of one newsletter message. Views and Reactions are the
+// current totals; the deltas are relative to the previous update this instance saw for the message.
+type NewsletterMessageUpdate struct {
+	NewsletterJID  string         `json:"newsletterJid"`
+	ServerID       int            `json:"serverId"`
+	MessageID      string         `json:"messageId,omitempty"`
+	Type           string         `json:"type,omitempty"`
+	Timestamp      time.Time      `json:"timestamp"`
+	UpdatedAt      time.Time      `json:"updatedAt"`
+	Views          int            `json:"views"`
+	ViewsDelta     int            `json:"viewsDelta"`
+	Reactions      map[string]int `json:"reactions"`
+	ReactionsDelta map[string]int `json:"reactionsDelta"`
+	FirstSeen      bool           `json:"firstSeen"`
+}
+
+// WebhookEventType implements webhookEventNamer
+func (u *NewsletterMessageUpdate) WebhookEventType() string {
+	return NewsletterMessageEventType
+}
+
+type newsletterCount struct {
+	views     int
+	reactions map[string]int
+	seenAt    time.Time
+}
+
+// newsletterCounters remembers the last counts of newsletter messages to turn live updates into deltas
+type newsletterCounters struct {
+	mu     sync.Mutex
+	counts map[string]*newsletterCount
+}
+
+func newNewsletterCounters() *newsletterCounters {
+	return &newsletterCounters{
+		counts: make(map[string]*newsletterCount),
+	}
+}
+
+// updates converts a live update into one update per message, skipping messages whose counts did not change
+func (n *newsletterCounters) updates(sessionID string, evt *events.NewsletterLiveUpdate) []*NewsletterMessageUpdate {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if len(n.counts) >= maxNewsletterCounts {
+		n.prune()
+	}
+
+	var updates []*NewsletterMessageUpdate
+	for _, msg := range evt.Messages {
+		if msg == nil {
+			continue
+		}
+
+		key := newsletterCountKey(sessionID, evt.JID, msg.MessageServerID)
+		previous, known := n.counts[key]
+
+		update := &NewsletterMessageUpdate{
+			NewsletterJID:  evt.JID.String(),
+			ServerID:       int(msg.MessageServerID),
+			MessageID:      string(msg.MessageID),
+			Type:           msg.Type,
+			Timestamp:      msg.Timestamp,
+			UpdatedAt:      evt.Time,
+			Views:          msg.ViewsCount,
+			Reactions:      make(map[string]int, len(msg.ReactionCounts)),
+			ReactionsDelta: make(map[string]int),
+			FirstSeen:      !known,
+		}
+
+		previousViews := 0
+		previousReactions := map[string]int{}
+		if known {
+			previousViews = previous.views
+			previousReactions = previous.reactions
+		}
+
+		update.ViewsDelta = msg.ViewsCount - previousViews
+		for reaction, count := range msg.ReactionCounts {
+			update.Reactions[reaction] = count
+			if delta := count - previousReactions[reaction]; delta != 0 {
+				update.ReactionsDelta[reaction] = delta
+			}
+		}
+		for reaction, count := range previousReactions {
+			if _, stillPresent := msg.ReactionCounts[reaction]; !stillPresent && count != 0 {
+				update.ReactionsDelta[reaction] = -count
+			}
+		}
+
+		if known || len(n.counts) < maxNewsletterCounts {
+			n.counts[key] = &newsletterCount{
+				views:     msg.ViewsCount,
+				reactions: update.Reactions,
+				seenAt:    time.Now(),
+			}
+		}
+
+		if known && update.ViewsDelta == 0 && len(update.ReactionsDelta) == 0 {
+			continue
+		}
+		updates = append(updates, update)
+	}
+
+	return updates
+}
+
+// forgetSession drops the counts tracked for a session
+func (n *newsletterCounters) forgetSession(sessionID string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	prefix := sessionID + "|"
+	for key := range n.counts {
+		if strings.HasPrefix(key, prefix) {
+			delete(n.counts, key)
+		}
+	}
+}
+
+// prune drops expired counts; the caller must hold mu
+func (n *newsletterCounters) prune() {
+	for key, count := range n.counts {
+		if time.Since(count.seenAt) >= newsletterCountsTTL {
+			delete(n.counts, key)
+		}
+	}
+}
+
+func newsletterCountKey(sessionID string, newsletter types.JID, serverID types.MessageServerID) string {
+	return sessionID + "|" + newsletter.String() + "|" + strconv.Itoa(int(serverID))
+}
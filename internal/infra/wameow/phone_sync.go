@@ -0,0 +1,280 @@
+package wameow
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.mau.fi/whatsmeow/types/events"
+
+	"zpwoot/internal/domain/session"
+)
+
+// SessionSyncStateEventType is the webhook event emitted when a session starts or stops waiting for
+// its phone
+const SessionSyncStateEventType = "session.sync_state"
+
+// phoneSyncGracePeriod keeps the short wait for the keys right after pairing from being reported
+const phoneSyncGracePeriod = 30 * time.Second
+
+// unavailableMessageTTL drops messages the phone never resent, so a lost message does not report the
+// session waiting forever
+const unavailableMessageTTL = time.Hour
+
+// SessionSyncStateChanged reports a connected session that waits for its phone, or no longer does
+type SessionSyncStateChanged struct {
+	State           string     `json:"state"`
+	Reasons         []string   `json:"reasons,omitempty"`
+	PendingMessages int        `json:"pendingMessages,omitempty"`
+	WaitingSince    *time.Time `json:"waitingSince,omitempty"`
+	Timestamp       time.Time  `json:"timestamp"`
+}
+
+// WebhookEventType implements webhookEventNamer
+func (s *SessionSyncStateChanged) WebhookEventType() string {
+	return SessionSyncStateEventType
+}
+
+// phoneSyncTracker follows what each connected session waits on its phone for: the app state keys
+// shared after pairing and the resend of messages that could not be decrypted
+type phoneSyncTracker struct {
+	mu       sync.Mutex
+	sessions map[string]*phoneSyncState
+}
+
+type phoneSyncState struct {
+	missingKeys  bool
+	unavailable  map[string]time.Time // message ID -> when it was reported unavailable
+	waitingSince time.Time
+	reported     bool
+	timer        *time.Timer
+	// checkKeys looks the keys up again before waiting for them is reported, as a key share that
+	// does not trigger a full app state sync emits no event
+	checkKeys func() (missing, known bool)
+}
+
+func newPhoneSyncTracker() *phoneSyncTracker {
+	return &phoneSyncTracker{
+		sessions: make(map[string]*phoneSyncState),
+	}
+}
+
+func (s *phoneSyncState) waiting() bool {
+	return s.missingKeys || len(s.unavailable) > 0
+}
+
+func (s *phoneSyncState) reasons() []string {
+	var reasons []string
+	if s.missingKeys {
+		reasons = append(reasons, session.SyncReasonAppStateKeys)
+	}
+	if len(s.unavailable) > 0 {
+		reasons = append(reasons, session.SyncReasonMessageResend)
+	}
+	return reasons
+}
+
+func (s *phoneSyncState) snapshot() *SessionSyncStateChanged {
+	change := &SessionSyncStateChanged{
+		State:     session.SyncStateSynced,
+		Timestamp: time.Now(),
+	}
+	if s.reported {
+		since := s.waitingSince
+		change.State = session.SyncStateWaitingForPhone
+		change.Reasons = s.reasons()
+		change.PendingMessages = len(s.unavailable)
+		change.WaitingSince = &since
+	}
+	return change
+}
+
+func (s *phoneSyncState) pruneUnavailable(now time.Time) {
+	for id, seenAt := range s.unavailable {
+		if now.Sub(seenAt) > unavailableMessageTTL {
+			delete(s.unavailable, id)
+		}
+	}
+}
+
+// update applies change to the session's state under the lock and reports the transitions: waiting
+// is reported through notify once it lasted the grace period, and its end right away
+func (t *phoneSyncTracker) update(sessionID string, notify func(*SessionSyncStateChanged), change func(*phoneSyncState)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	state, exists := t.sessions[sessionID]
+	if !exists {
+		state = &phoneSyncState{unavailable: make(map[string]time.Time)}
+		t.sessions[sessionID] = state
+	}
+
+	now := time.Now()
+	change(state)
+	state.pruneUnavailable(now)
+
+	switch {
+	case state.waiting() && state.waitingSince.IsZero():
+		state.waitingSince = now
+		state.timer = time.AfterFunc(phoneSyncGracePeriod, func() {
+			t.report(sessionID, state, notify)
+		})
+	case !state.waiting() && !state.waitingSince.IsZero():
+		if state.timer != nil {
+			state.timer.Stop()
+		}
+		wasReported := state.reported
+		state.waitingSince = time.Time{}
+		state.reported = false
+		state.timer = nil
+		if wasReported {
+			go notify(state.snapshot())
+		}
+	}
+
+	if !state.waiting() {
+		delete(t.sessions, sessionID)
+	}
+}
+
+func (t *phoneSyncTracker) report(sessionID string, state *phoneSyncState, notify func(*SessionSyncStateChanged)) {
+	t.mu.Lock()
+	checkKeys, missingKeys := state.checkKeys, state.missingKeys
+	t.mu.Unlock()
+
+	if missingKeys && checkKeys != nil {
+		if missing, known := checkKeys(); known && !missing {
+			t.update(sessionID, notify, func(state *phoneSyncState) {
+				state.missingKeys = false
+			})
+		}
+	}
+
+	t.mu.Lock()
+	if t.sessions[sessionID] != state || !state.waiting() || state.reported {
+		t.mu.Unlock()
+		return
+	}
+	state.reported = true
+	change := state.snapshot()
+	t.mu.Unlock()
+
+	notify(change)
+}
+
+// get returns the session's sync state; waiting is only reported after the grace period
+func (t *phoneSyncTracker) get(sessionID string) *session.SyncState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	syncState := &session.SyncState{State: session.SyncStateSynced}
+	state, exists := t.sessions[sessionID]
+	if !exists || !state.reported {
+		return syncState
+	}
+
+	change := state.snapshot()
+	syncState.State = change.State
+	syncState.Reasons = change.Reasons
+	syncState.PendingMessages = change.PendingMessages
+	syncState.WaitingSince = change.WaitingSince
+	return syncState
+}
+
+// forget drops the session's state when it disconnects or is removed
+func (t *phoneSyncTracker) forget(sessionID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if state, exists := t.sessions[sessionID]; exists && state.timer != nil {
+		state.timer.Stop()
+	}
+	delete(t.sessions, sessionID)
+}
+
+// trackPhoneSync updates the session's phone sync state from the events that reveal it
+func (h *EventHandler) trackPhoneSync(evt interface{}, sessionID string) {
+	if h.manager == nil || h.manager.phoneSync == nil {
+		return
+	}
+	tracker := h.manager.phoneSync
+
+	switch e := evt.(type) {
+	case *events.Connected, *events.AppStateSyncComplete:
+		missing, known := h.appStateKeysMissing(sessionID)
+		if !known {
+			return
+		}
+		tracker.update(sessionID, h.notifyPhoneSync(sessionID), func(state *phoneSyncState) {
+			state.missingKeys = missing
+			state.checkKeys = func() (bool, bool) {
+				return h.appStateKeysMissing(sessionID)
+			}
+		})
+	case *events.UndecryptableMessage:
+		// View-once messages are never resent to companion devices
+		if !e.IsUnavailable || e.UnavailableType != events.UnavailableTypeUnknown {
+			return
+		}
+		tracker.update(sessionID, h.notifyPhoneSync(sessionID), func(state *phoneSyncState) {
+			state.unavailable[e.Info.ID] = time.Now()
+		})
+	case *events.Message:
+		if e.UnavailableRequestID == "" {
+			return
+		}
+		tracker.update(sessionID, h.notifyPhoneSync(sessionID), func(state *phoneSyncState) {
+			delete(state.unavailable, e.Info.ID)
+		})
+	case *events.Disconnected, *events.LoggedOut:
+		tracker.forget(sessionID)
+	}
+}
+
+// appStateKeysMissing reports whether the phone has not shared any app state key with this device
+// yet; known is false when the store cannot tell
+func (h *EventHandler) appStateKeysMissing(sessionID string) (missing, known bool) {
+	client := h.manager.getClient(sessionID)
+	if client == nil || client.GetClient().Store.ID == nil {
+		return false, false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	keyID, err := client.GetClient().Store.AppStateKeys.GetLatestAppStateSyncKeyID(ctx)
+	if err != nil {
+		h.logger.WarnWithFields("Failed to check app state keys", map[string]interface{}{
+			"session_id": sessionID,
+			"error":      err.Error(),
+		})
+		return false, false
+	}
+	return keyID == nil, true
+}
+
+func (h *EventHandler) notifyPhoneSync(sessionID string) func(*SessionSyncStateChanged) {
+	return func(change *SessionSyncStateChanged) {
+		if change.State == session.SyncStateWaitingForPhone {
+			h.logger.WarnWithFields("Session is waiting for its phone", map[string]interface{}{
+				"session_id":       sessionID,
+				"reasons":          change.Reasons,
+				"pending_messages": change.PendingMessages,
+			})
+		} else {
+			h.logger.InfoWithFields("Session is no longer waiting for its phone", map[string]interface{}{
+				"session_id": sessionID,
+			})
+		}
+		h.deliverToWebhook(change, sessionID)
+	}
+}
+
+// GetSyncState returns whether the connected session waits for its phone, or nil when it is not connected
+func (m *Manager) GetSyncState(sessionID string) *session.SyncState {
+	client := m.getClient(sessionID)
+	if client == nil || !client.IsConnected() {
+		return nil
+	}
+	return m.phoneSync.get(sessionID)
+}
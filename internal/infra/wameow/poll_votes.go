@@ -0,0 +1,150 @@
+package wameow
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"go.mau.fi/whatsmeow/types/events"
+
+	"zpwoot/internal/domain/poll"
+)
+
+// PollVoteEventType is the webhook event carrying a decrypted vote on a poll sent by the session
+const PollVoteEventType = "poll.vote"
+
+// pollVoteTimeout bounds decrypting and storing one vote
+const pollVoteTimeout = 10 * time.Second
+
+// PollVote is a decrypted poll vote. SelectedOptions is empty when the voter retracted their vote,
+// and Tallies is only set when the poll definition is stored.
+type PollVote struct {
+	PollMessageID   string             `json:"pollMessageId"`
+	PollName        string             `json:"pollName,omitempty"`
+	ChatJID         string             `json:"chatJid"`
+	VoterJID        string             `json:"voterJid"`
+	MessageID       string             `json:"messageId"`
+	SelectedOptions []string           `json:"selectedOptions"`
+	SelectedHashes  []string           `json:"selectedHashes"`
+	UnknownHashes   []string           `json:"unknownHashes,omitempty"`
+	Late            bool               `json:"late"`
+	Tallies         []poll.OptionTally `json:"tallies,omitempty"`
+	Timestamp       time.Time          `json:"timestamp"`
+}
+
+// WebhookEventType implements webhookEventNamer
+func (v *PollVote) WebhookEventType() string {
+	return PollVoteEventType
+}
+
+// recordReceivedPoll stores the definition of a poll created by a contact or from the phone, so votes on
+// it are tallied like votes on polls sent through the API
+func (h *EventHandler) recordReceivedPoll(evt *events.Message, sessionID string) {
+	creation := pollCreation(evt.Message)
+	if creation == nil || h.manager == nil {
+		return
+	}
+
+	options := make([]string, 0, len(creation.GetOptions()))
+	for _, option := range creation.GetOptions() {
+		options = append(options, option.GetOptionName())
+	}
+
+	h.manager.recordPoll(poll.NewPoll(sessionID, evt.Info.ID, evt.Info.Chat.ToNonAD().String(), creation.GetName(),
+		options, int(creation.GetSelectableOptionsCount()), nil))
+}
+
+// resolvePollOptions maps the options chosen for a vote to the option text of the stored poll definition.
+// Polls without a stored definition, such as polls received before definitions were stored, are voted on
+// with the options as given.
+func (m *Manager) resolvePollOptions(ctx context.Context, sessionID, pollMessageID string, selected []string) ([]string, error) {
+	if m.pollService == nil {
+		return selected, nil
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, pollVoteTimeout)
+	defer cancel()
+
+	p, err := m.pollService.GetPoll(lookupCtx, sessionID, pollMessageID)
+	if errors.Is(err, poll.ErrPollNotFound) {
+		return selected, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if p.IsExpired(time.Now()) {
+		m.logger.InfoWithFields("Voting on an expired poll", map[string]interface{}{
+			"session_id": sessionID,
+			"poll_id":    pollMessageID,
+		})
+	}
+
+	return p.ResolveOptions(selected)
+}
+
+// handlePollVote decrypts a vote on a poll seen by the session, records it and delivers it as a poll.vote event
+func (h *EventHandler) handlePollVote(evt *events.Message, sessionID string) {
+	client := h.manager.getClient(sessionID)
+	if client == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pollVoteTimeout)
+	defer cancel()
+
+	decrypted, err := client.GetClient().DecryptPollVote(ctx, evt)
+	if err != nil {
+		// Votes on polls this device never received cannot be decrypted
+		h.logger.DebugWithFields("Failed to decrypt poll vote", map[string]interface{}{
+			"session_id": sessionID,
+			"message_id": evt.Info.ID,
+			"error":      err.Error(),
+		})
+		return
+	}
+
+	vote := &PollVote{
+		PollMessageID:   evt.Message.GetPollUpdateMessage().GetPollCreationMessageKey().GetID(),
+		ChatJID:         evt.Info.Chat.String(),
+		VoterJID:        evt.Info.Sender.ToNonAD().String(),
+		MessageID:       evt.Info.ID,
+		SelectedOptions: []string{},
+		SelectedHashes:  make([]string, 0, len(decrypted.GetSelectedOptions())),
+		Timestamp:       evt.Info.Timestamp,
+	}
+	for _, hash := range decrypted.GetSelectedOptions() {
+		vote.SelectedHashes = append(vote.SelectedHashes, hex.EncodeToString(hash))
+	}
+
+	if h.manager.pollService != nil {
+		result, err := h.manager.pollService.RecordVote(ctx, sessionID, vote.PollMessageID, vote.VoterJID, decrypted.GetSelectedOptions(), vote.Timestamp)
+		switch {
+		case err == nil:
+			vote.PollName = result.Poll.Name
+			vote.SelectedOptions = result.Vote.Options
+			vote.UnknownHashes = result.UnknownHashes
+			vote.Late = result.Vote.Late
+			vote.Tallies = result.Tallies
+		case errors.Is(err, poll.ErrPollNotFound):
+			// Polls sent before definitions were stored only carry the hashes
+			vote.UnknownHashes = vote.SelectedHashes
+		default:
+			h.logger.WarnWithFields("Failed to record poll vote", map[string]interface{}{
+				"session_id": sessionID,
+				"poll_id":    vote.PollMessageID,
+				"error":      err.Error(),
+			})
+		}
+	}
+
+	h.logger.InfoWithFields("Poll vote received", map[string]interface{}{
+		"session_id": sessionID,
+		"poll_id":    vote.PollMessageID,
+		"voter":      vote.VoterJID,
+		"options":    len(vote.SelectedHashes),
+		"late":       vote.Late,
+	})
+
+	h.deliverToWebhook(vote, sessionID)
+}
@@ -0,0 +1,188 @@
+package wameow
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"go.mau.fi/whatsmeow/types"
+)
+
+// maxPresenceSubscriptions bounds the contacts a session can follow the presence of
+const maxPresenceSubscriptions = 1000
+
+// presenceSubscriptions remembers the contacts each session follows the presence of. WhatsApp drops
+// presence subscriptions when the connection closes, so they are renewed on every connect.
+type presenceSubscriptions struct {
+	mu   sync.Mutex
+	jids map[string]map[types.JID]struct{}
+}
+
+func newPresenceSubscriptions() *presenceSubscriptions {
+	return &presenceSubscriptions{
+		jids: make(map[string]map[types.JID]struct{}),
+	}
+}
+
+// add records a subscription, failing once the session reached maxPresenceSubscriptions
+func (p *presenceSubscriptions) add(sessionID string, jid types.JID) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	jids := p.jids[sessionID]
+	if jids == nil {
+		jids = make(map[types.JID]struct{})
+		p.jids[sessionID] = jids
+	}
+	if _, exists := jids[jid]; !exists && len(jids) >= maxPresenceSubscriptions {
+		return false
+	}
+	jids[jid] = struct{}{}
+	return true
+}
+
+// list returns the contacts a session follows, sorted for stable output
+func (p *presenceSubscriptions) list(sessionID string) []types.JID {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	jids := make([]types.JID, 0, len(p.jids[sessionID]))
+	for jid := range p.jids[sessionID] {
+		jids = append(jids, jid)
+	}
+	sort.Slice(jids, func(i, j int) bool {
+		return jids[i].String() < jids[j].String()
+	})
+	return jids
+}
+
+// forget drops the subscriptions of a session
+func (p *presenceSubscriptions) forget(sessionID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.jids, sessionID)
+}
+
+// SubscribePresence subscribes the session to the presence of contacts, delivered as Presence webhook
+// events. It returns the JIDs subscribed and the reason each other one failed.
+func (m *Manager) SubscribePresence(ctx context.Context, sessionID string, jids []string) ([]string, map[string]string, error) {
+	client := m.getClient(sessionID)
+	if client == nil {
+		return nil, nil, fmt.Errorf("session %s not found", sessionID)
+	}
+
+	subscribed, failed, err := client.SubscribePresence(ctx, jids)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := make([]string, 0, len(subscribed))
+	for _, jid := range subscribed {
+		if !m.presenceSubs.add(sessionID, jid) {
+			failed[jid.String()] = fmt.Sprintf("session already follows %d contacts", maxPresenceSubscriptions)
+			continue
+		}
+		result = append(result, jid.String())
+	}
+
+	return result, failed, nil
+}
+
+// ListPresenceSubscriptions returns the contacts the session follows the presence of
+func (m *Manager) ListPresenceSubscriptions(sessionID string) ([]string, error) {
+	if m.getClient(sessionID) == nil {
+		return nil, fmt.Errorf("session %s not found", sessionID)
+	}
+
+	jids := m.presenceSubs.list(sessionID)
+	result := make([]string, 0, len(jids))
+	for _, jid := range jids {
+		result = append(result, jid.String())
+	}
+	return result, nil
+}
+
+// SubscribePresence announces the session as available, which WhatsApp requires before it sends
+// presence updates, and subscribes to each contact
+func (c *WameowClient) SubscribePresence(ctx context.Context, jids []string) ([]types.JID, map[string]string, error) {
+	if !c.client.IsLoggedIn() {
+		return nil, nil, fmt.Errorf("client is not logged in")
+	}
+
+	if err := c.client.SendPresence(types.PresenceAvailable); err != nil {
+		return nil, nil, fmt.Errorf("failed to send available presence: %w", err)
+	}
+
+	subscribed := make([]types.JID, 0, len(jids))
+	failed := make(map[string]string)
+	for _, jidStr := range jids {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+
+		jid, err := c.parseJID(jidStr)
+		if err != nil {
+			failed[jidStr] = fmt.Sprintf("invalid JID: %v", err)
+			continue
+		}
+		if jid.Server != types.DefaultUserServer && jid.Server != types.HiddenUserServer {
+			failed[jidStr] = "presence can only be subscribed for users"
+			continue
+		}
+
+		jid = jid.ToNonAD()
+		if err := c.client.SubscribePresence(jid); err != nil {
+			failed[jidStr] = err.Error()
+			continue
+		}
+		subscribed = append(subscribed, jid)
+	}
+
+	c.logger.InfoWithFields("Subscribed to presence", map[string]interface{}{
+		"session_id": c.sessionID,
+		"subscribed": len(subscribed),
+		"failed":     len(failed),
+	})
+
+	return subscribed, failed, nil
+}
+
+// renewPresenceSubscriptions subscribes again to the contacts the session followed before reconnecting
+func (h *EventHandler) renewPresenceSubscriptions(sessionID string) {
+	if h.manager == nil {
+		return
+	}
+	jids := h.manager.presenceSubs.list(sessionID)
+	if len(jids) == 0 {
+		return
+	}
+	client := h.manager.getClient(sessionID)
+	if client == nil {
+		return
+	}
+
+	go func() {
+		if err := client.GetClient().SendPresence(types.PresenceAvailable); err != nil {
+			h.logger.WarnWithFields("Failed to renew presence subscriptions", map[string]interface{}{
+				"session_id": sessionID,
+				"error":      err.Error(),
+			})
+			return
+		}
+
+		failed := 0
+		for _, jid := range jids {
+			if err := client.GetClient().SubscribePresence(jid); err != nil {
+				failed++
+			}
+		}
+
+		h.logger.InfoWithFields("Presence subscriptions renewed", map[string]interface{}{
+			"session_id": sessionID,
+			"renewed":    len(jids) - failed,
+			"failed":     failed,
+		})
+	}()
+}
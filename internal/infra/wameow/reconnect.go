@@ -0,0 +1,494 @@
+package wameow
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"zpwoot/internal/domain/session"
+	"zpwoot/platform/logger"
+)
+
+// ReconnectConfig configures the reconnect supervisor. A dropped session waits InitialDelay before its
+// first attempt, and the wait grows by Multiplier after each failure up to MaxDelay, randomized by
+// Jitter (0.2 = ±20%). MaxAttempts 0 retries until the session connects or is disconnected on purpose.
+type ReconnectConfig struct {
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+	Multiplier   float64
+	Jitter       float64
+	MaxAttempts  int
+	// MaxConcurrent caps the sessions reconnecting at the same time, so a network blip does not make
+	// every session hit WhatsApp at once
+	MaxConcurrent int
+	// AttemptTimeout is how long an attempt waits for the session to report connected
+	AttemptTimeout time.Duration
+}
+
+// Reasons a session is handed to the reconnect supervisor
+const (
+	ReconnectReasonStartup        = "startup"
+	ReconnectReasonDisconnected   = "disconnected"
+	ReconnectReasonStreamReplaced = "stream_replaced"
+	ReconnectReasonProbe          = "probe"
+)
+
+// errReconnectTimeout is recorded when an attempt does not connect within AttemptTimeout
+var errReconnectTimeout = errors.New("session did not connect in time")
+
+// reconnectSupervisor reconnects paired sessions that dropped their connection, one goroutine per
+// dropped session, instead of whatsmeow's built-in reconnect loop
+type reconnectSupervisor struct {
+	manager *Manager
+	config  ReconnectConfig
+	logger  *logger.Logger
+	slots   chan struct{}
+
+	mu       sync.Mutex
+	sessions map[string]*supervisedSession
+	// closed is set on shutdown, after which no session is supervised
+	closed bool
+}
+
+// supervisedSession is the reconnect state of one session
+type supervisedSession struct {
+	// wanted is set while the session should stay connected, i.e. it was connected and not
+	// disconnected, logged out or removed on purpose since
+	wanted  bool
+	running bool
+	stop    chan struct{}
+	// connected is closed by the Connected event of the attempt in progress
+	connected chan struct{}
+
+	state          string
+	reason         string
+	attempts       int
+	lastError      string
+	disconnectedAt *time.Time
+	nextAttemptAt  *time.Time
+	connectedAt    *time.Time
+}
+
+func newReconnectSupervisor(manager *Manager, config ReconnectConfig, logger *logger.Logger) *reconnectSupervisor {
+	if config.InitialDelay <= 0 {
+		config.InitialDelay = 2 * time.Second
+	}
+	if config.MaxDelay < config.InitialDelay {
+		config.MaxDelay = config.InitialDelay
+	}
+	if config.Multiplier < 1 {
+		config.Multiplier = 2
+	}
+	if config.Jitter < 0 || config.Jitter > 1 {
+		config.Jitter = 0.2
+	}
+	if config.MaxConcurrent <= 0 {
+		config.MaxConcurrent = 5
+	}
+	if config.AttemptTimeout <= 0 {
+		config.AttemptTimeout = 30 * time.Second
+	}
+
+	return &reconnectSupervisor{
+		manager:  manager,
+		config:   config,
+		logger:   logger,
+		slots:    make(chan struct{}, config.MaxConcurrent),
+		sessions: make(map[string]*supervisedSession),
+	}
+}
+
+// session returns the state of a session, creating it; callers hold s.mu
+func (s *reconnectSupervisor) session(sessionID string) *supervisedSession {
+	sup, ok := s.sessions[sessionID]
+	if !ok {
+		sup = &supervisedSession{state: session.ReconnectStateIdle}
+		s.sessions[sessionID] = sup
+	}
+	return sup
+}
+
+// want marks a session as one that should stay connected; a session that gave up is supervised again
+func (s *reconnectSupervisor) want(sessionID string) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+
+	sup := s.session(sessionID)
+	sup.wanted = true
+	if sup.state == session.ReconnectStateGaveUp {
+		sup.state = session.ReconnectStateIdle
+		sup.attempts = 0
+	}
+}
+
+// connected records that a session connected, ending the attempt in progress
+func (s *reconnectSupervisor) connected(sessionID string) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	sup := s.session(sessionID)
+	sup.wanted = true
+	sup.state = session.ReconnectStateConnected
+	sup.attempts = 0
+	sup.lastError = ""
+	sup.nextAttemptAt = nil
+	sup.connectedAt = &now
+	if sup.connected != nil {
+		close(sup.connected)
+		sup.connected = nil
+	}
+}
+
+// disconnected starts reconnecting a session that should stay connected, unless it already is
+func (s *reconnectSupervisor) disconnected(sessionID, reason string) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+
+	sup := s.session(sessionID)
+	if !sup.wanted {
+		return
+	}
+
+	now := time.Now()
+	sup.reason = reason
+	sup.disconnectedAt = &now
+	if sup.running {
+		// The running loop retries instead of settling on an attempt that already connected
+		if sup.state == session.ReconnectStateConnected {
+			sup.state = session.ReconnectStateWaiting
+		}
+		return
+	}
+
+	sup.running = true
+	sup.attempts = 0
+	sup.state = session.ReconnectStateWaiting
+	sup.stop = make(chan struct{})
+	go s.run(sessionID, sup, sup.stop)
+
+	s.logger.InfoWithFields("Session reconnect scheduled", map[string]interface{}{
+		"session_id": sessionID,
+		"reason":     reason,
+	})
+}
+
+// release stops supervising a session that was disconnected, logged out or removed on purpose
+func (s *reconnectSupervisor) release(sessionID string) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sup, ok := s.sessions[sessionID]
+	if !ok {
+		return
+	}
+	if sup.running {
+		close(sup.stop)
+	}
+	delete(s.sessions, sessionID)
+}
+
+// shutdown stops every reconnect loop and ignores drops from then on, as the process is going away
+func (s *reconnectSupervisor) shutdown() {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	for sessionID, sup := range s.sessions {
+		if sup.running {
+			close(sup.stop)
+		}
+		delete(s.sessions, sessionID)
+	}
+}
+
+// run retries a session with backoff until it connects, is released or runs out of attempts
+func (s *reconnectSupervisor) run(sessionID string, sup *supervisedSession, stop <-chan struct{}) {
+	for {
+		s.mu.Lock()
+		if s.settled(sessionID, sup) {
+			s.mu.Unlock()
+			return
+		}
+		sup.attempts++
+		attempt := sup.attempts
+		delay := s.backoff(attempt, sup.reason)
+		nextAttemptAt := time.Now().Add(delay)
+		sup.state = session.ReconnectStateWaiting
+		sup.nextAttemptAt = &nextAttemptAt
+		s.mu.Unlock()
+
+		if !sleepOrStop(delay, stop) {
+			return
+		}
+
+		// An attempt that timed out may still have connected while waiting
+		s.mu.Lock()
+		settled := s.settled(sessionID, sup)
+		s.mu.Unlock()
+		if settled {
+			return
+		}
+
+		select {
+		case s.slots <- struct{}{}:
+		case <-stop:
+			return
+		}
+
+		err := s.attempt(sessionID, sup, stop)
+		<-s.slots
+
+		select {
+		case <-stop:
+			return
+		default:
+		}
+
+		s.mu.Lock()
+		if s.settled(sessionID, sup) {
+			s.mu.Unlock()
+			return
+		}
+		if err == nil {
+			// A disconnect arrived right after the attempt connected
+			s.mu.Unlock()
+			continue
+		}
+
+		sup.lastError = err.Error()
+		if s.config.MaxAttempts > 0 && attempt >= s.config.MaxAttempts {
+			sup.state = session.ReconnectStateGaveUp
+			sup.nextAttemptAt = nil
+			sup.running = false
+			s.mu.Unlock()
+			s.logger.ErrorWithFields("Session reconnect gave up", map[string]interface{}{
+				"session_id": sessionID,
+				"attempts":   attempt,
+				"error":      err.Error(),
+			})
+			return
+		}
+		s.mu.Unlock()
+
+		s.logger.WarnWithFields("Session reconnect attempt failed", map[string]interface{}{
+			"session_id": sessionID,
+			"attempt":    attempt,
+			"error":      err.Error(),
+		})
+	}
+}
+
+// settled ends the loop of a session that connected; callers hold s.mu
+func (s *reconnectSupervisor) settled(sessionID string, sup *supervisedSession) bool {
+	if sup.state != session.ReconnectStateConnected {
+		return false
+	}
+
+	sup.running = false
+	s.logger.InfoWithFields("Session reconnected", map[string]interface{}{
+		"session_id": sessionID,
+	})
+	return true
+}
+
+// attempt connects a session and waits for its Connected event
+func (s *reconnectSupervisor) attempt(sessionID string, sup *supervisedSession, stop <-chan struct{}) error {
+	connected := make(chan struct{})
+
+	s.mu.Lock()
+	sup.state = session.ReconnectStateReconnecting
+	sup.nextAttemptAt = nil
+	sup.connected = connected
+	s.mu.Unlock()
+
+	if err := s.manager.ConnectSession(sessionID); err != nil {
+		return err
+	}
+
+	timer := time.NewTimer(s.config.AttemptTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-connected:
+		return nil
+	case <-stop:
+		return nil
+	case <-timer.C:
+		return errReconnectTimeout
+	}
+}
+
+// backoff returns the jittered wait before an attempt. A replaced stream waits the longest delay from
+// the start, as it usually means another client took over the session.
+func (s *reconnectSupervisor) backoff(attempt int, reason string) time.Duration {
+	delay := float64(s.config.InitialDelay)
+	switch reason {
+	case ReconnectReasonStartup:
+		if attempt == 1 {
+			return 0
+		}
+	case ReconnectReasonStreamReplaced:
+		delay = float64(s.config.MaxDelay)
+	}
+
+	for i := 1; i < attempt && delay < float64(s.config.MaxDelay); i++ {
+		delay *= s.config.Multiplier
+	}
+	if delay > float64(s.config.MaxDelay) {
+		delay = float64(s.config.MaxDelay)
+	}
+
+	if s.config.Jitter > 0 {
+		delay += delay * s.config.Jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(delay)
+}
+
+// probe hands paired sessions that should be connected but are not to the supervisor, catching
+// drops that did not deliver a Disconnected event
+func (s *reconnectSupervisor) probe() {
+	s.manager.clientsMutex.RLock()
+	clients := make(map[string]*WameowClient, len(s.manager.clients))
+	for sessionID, client := range s.manager.clients {
+		clients[sessionID] = client
+	}
+	s.manager.clientsMutex.RUnlock()
+
+	for sessionID, client := range clients {
+		s.mu.Lock()
+		sup, ok := s.sessions[sessionID]
+		idle := ok && sup.wanted && !sup.running && sup.state != session.ReconnectStateGaveUp
+		s.mu.Unlock()
+
+		if idle && IsDeviceRegistered(client.GetClient()) && !client.IsConnected() {
+			s.disconnected(sessionID, ReconnectReasonProbe)
+		}
+	}
+}
+
+// state returns the reconnect state of a session, nil when it is not supervised
+func (s *reconnectSupervisor) state(sessionID string) *session.ReconnectState {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sup, ok := s.sessions[sessionID]
+	if !ok {
+		return nil
+	}
+
+	return &session.ReconnectState{
+		State:          sup.state,
+		Reason:         sup.reason,
+		Attempts:       sup.attempts,
+		LastError:      sup.lastError,
+		DisconnectedAt: sup.disconnectedAt,
+		NextAttemptAt:  sup.nextAttemptAt,
+		ConnectedAt:    sup.connectedAt,
+	}
+}
+
+// sleepOrStop waits for d and reports false when stop closes first
+func sleepOrStop(d time.Duration, stop <-chan struct{}) bool {
+	if d <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-stop:
+		return false
+	}
+}
+
+// SetReconnectSupervisor replaces whatsmeow's built-in reconnect loop with the supervisor for
+// sessions created from now on
+func (m *Manager) SetReconnectSupervisor(config ReconnectConfig) {
+	m.reconnects = newReconnectSupervisor(m, config, m.logger)
+
+	m.logger.InfoWithFields("Reconnect supervisor enabled", map[string]interface{}{
+		"initial_delay":  m.reconnects.config.InitialDelay.String(),
+		"max_delay":      m.reconnects.config.MaxDelay.String(),
+		"max_attempts":   m.reconnects.config.MaxAttempts,
+		"max_concurrent": m.reconnects.config.MaxConcurrent,
+	})
+}
+
+// ProbeConnections checks the supervised sessions for connections dropped without a Disconnected event
+func (m *Manager) ProbeConnections(ctx context.Context) error {
+	if m.reconnects != nil {
+		m.reconnects.probe()
+	}
+	return nil
+}
+
+// ReconnectSupervised reports whether dropped sessions are reconnected by the supervisor
+func (m *Manager) ReconnectSupervised() bool {
+	return m.reconnects != nil
+}
+
+// ScheduleReconnect hands a paired session to the supervisor, which connects it as soon as a slot
+// frees up; used to connect the stored sessions on startup without flooding WhatsApp
+func (m *Manager) ScheduleReconnect(sessionID, reason string) {
+	if m.reconnects == nil {
+		return
+	}
+	m.reconnects.want(sessionID)
+	m.reconnects.disconnected(sessionID, reason)
+}
+
+// superviseDrop hands a session that lost its connection to the supervisor; sessions still waiting
+// for a QR scan are left alone, as reconnecting them would only produce new codes
+func (m *Manager) superviseDrop(sessionID, reason string) {
+	if m.reconnects == nil {
+		return
+	}
+	client := m.getClient(sessionID)
+	if client == nil || !IsDeviceRegistered(client.GetClient()) {
+		return
+	}
+	m.reconnects.disconnected(sessionID, reason)
+}
+
+// GetReconnectState returns the reconnect supervisor state of a session, nil when it is not supervised
+func (m *Manager) GetReconnectState(sessionID string) *session.ReconnectState {
+	return m.reconnects.state(sessionID)
+}
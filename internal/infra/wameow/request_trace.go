@@ -0,0 +1,151 @@
+package wameow
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.mau.fi/whatsmeow/types/events"
+
+	"zpwoot/platform/logger"
+)
+
+// messageTraceTTL covers the receipts of a sent message, read receipts may come hours later
+const messageTraceTTL = 24 * time.Hour
+
+// sessionTraceTTL covers the QR codes and connection events a connect or logout call triggers
+const sessionTraceTTL = 2 * time.Minute
+
+// maxMessageTraces bounds the memory of the message traces; sends beyond it are not traced
+const maxMessageTraces = 50000
+
+// requestTracer remembers which API request sent a message or acted on a session, so the webhook
+// events and Chatwoot relays that follow asynchronously carry the request ID
+type requestTracer struct {
+	mu       sync.Mutex
+	messages map[string]requestTrace // session ID + message ID -> trace
+	sessions map[string]requestTrace // session ID -> trace
+}
+
+type requestTrace struct {
+	requestID string
+	expiresAt time.Time
+}
+
+func newRequestTracer() *requestTracer {
+	return &requestTracer{
+		messages: make(map[string]requestTrace),
+		sessions: make(map[string]requestTrace),
+	}
+}
+
+func messageTraceKey(sessionID, messageID string) string {
+	return sessionID + "|" + messageID
+}
+
+func (t *requestTracer) traceMessage(sessionID, messageID, requestID string) {
+	now := time.Now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.messages) >= maxMessageTraces {
+		for key, trace := range t.messages {
+			if now.After(trace.expiresAt) {
+				delete(t.messages, key)
+			}
+		}
+		if len(t.messages) >= maxMessageTraces {
+			return
+		}
+	}
+
+	t.messages[messageTraceKey(sessionID, messageID)] = requestTrace{
+		requestID: requestID,
+		expiresAt: now.Add(messageTraceTTL),
+	}
+}
+
+func (t *requestTracer) traceSession(sessionID, requestID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.sessions[sessionID] = requestTrace{
+		requestID: requestID,
+		expiresAt: time.Now().Add(sessionTraceTTL),
+	}
+}
+
+func (t *requestTracer) messageRequest(sessionID, messageID string) string {
+	key := messageTraceKey(sessionID, messageID)
+	trace, exists := t.messages[key]
+	if !exists {
+		return ""
+	}
+	if time.Now().After(trace.expiresAt) {
+		delete(t.messages, key)
+		return ""
+	}
+	return trace.requestID
+}
+
+// requestIDFor returns the ID of the API request that triggered the event, or "" when it was not
+// triggered by one
+func (t *requestTracer) requestIDFor(sessionID string, evt interface{}) string {
+	if t == nil {
+		return ""
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	switch e := evt.(type) {
+	case *events.Receipt:
+		for _, id := range e.MessageIDs {
+			if requestID := t.messageRequest(sessionID, string(id)); requestID != "" {
+				return requestID
+			}
+		}
+	case *events.Message:
+		return t.messageRequest(sessionID, e.Info.ID)
+	case *events.QR, *events.PairSuccess, *events.PairError, *events.Connected, *events.Disconnected, *events.LoggedOut:
+		trace, exists := t.sessions[sessionID]
+		if !exists {
+			return ""
+		}
+		if time.Now().After(trace.expiresAt) {
+			delete(t.sessions, sessionID)
+			return ""
+		}
+		return trace.requestID
+	}
+	return ""
+}
+
+// TraceRequest links a message sent during an API request to the request's ID, so the events of the
+// message carry it, and attributes the stored message to the actor of ctx; contexts without a request
+// ID or actor are ignored
+func (m *Manager) TraceRequest(ctx context.Context, sessionID, messageID string) {
+	if requestID := logger.RequestIDFromContext(ctx); requestID != "" && messageID != "" {
+		m.requestTraces.traceMessage(sessionID, messageID, requestID)
+	}
+	m.recordMessageActor(ctx, sessionID, messageID)
+}
+
+// TraceSessionRequest links the connection events that follow an API call on a session, such as a
+// connect or logout, to the request's ID
+func (m *Manager) TraceSessionRequest(ctx context.Context, sessionID string) {
+	if requestID := logger.RequestIDFromContext(ctx); requestID != "" {
+		m.requestTraces.traceSession(sessionID, requestID)
+	}
+}
+
+// eventContext returns the context the asynchronous handling of the event runs with, carrying the ID
+// of the API request that triggered it
+func (h *EventHandler) eventContext(evt interface{}, sessionID string) context.Context {
+	ctx := context.Background()
+	if h.manager == nil {
+		return ctx
+	}
+	return logger.WithRequestID(ctx, h.manager.requestTraces.requestIDFor(sessionID, evt))
+}
@@ -0,0 +1,30 @@
+package wameow
+
+import (
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// selfChatJID returns the chat a message to "to" goes to. The own number or LID of the session, given
+// with a device part or, for Brazilian numbers, without or with the ninth digit, becomes the self chat
+// ("message yourself") in the addressing the caller used: whatsmeow then sends it to the other devices of
+// the account. Other recipients are returned unchanged.
+func selfChatJID(cli *whatsmeow.Client, to types.JID) types.JID {
+	if cli == nil || cli.Store == nil || cli.Store.ID == nil {
+		return to
+	}
+
+	switch to.Server {
+	case types.DefaultUserServer:
+		own := cli.Store.ID.ToNonAD()
+		if to.User == own.User || GetBrazilianAlternativeNumber("+"+to.User) == "+"+own.User {
+			return own
+		}
+	case types.HiddenUserServer:
+		ownLID := cli.Store.GetLID()
+		if !ownLID.IsEmpty() && to.User == ownLID.User {
+			return ownLID.ToNonAD()
+		}
+	}
+	return to
+}
@@ -0,0 +1,47 @@
+package wameow
+
+import (
+	"context"
+
+	"zpwoot/internal/domain/maintenance"
+
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// SendGate freezes everything a session sends: messages, edits, revokes, reactions, presence, read
+// receipts and chat actions, e.g. while the instance or the session is in maintenance
+type SendGate interface {
+	// SendFrozen reports whether the session must not send
+	SendFrozen(sessionID string) bool
+}
+
+// SetSendGate makes the sessions refuse to send while gate freezes them; it applies to the sessions
+// created afterwards, so it is set before the sessions are started
+func (m *Manager) SetSendGate(gate SendGate) {
+	m.sendGate = gate
+}
+
+// checkSendGate returns maintenance.ErrSendFrozen while the session's sends are frozen
+func (c *WameowClient) checkSendGate() error {
+	if c.sendGate != nil && c.sendGate.SendFrozen(c.sessionID) {
+		return maintenance.ErrSendFrozen
+	}
+	return nil
+}
+
+// prepareSend runs a message through the send gate, the send guard and the send throttle, in that order,
+// and returns the JID it is sent to
+func (c *WameowClient) prepareSend(ctx context.Context, to types.JID, message *waE2E.Message) (types.JID, error) {
+	if err := c.checkSendGate(); err != nil {
+		return types.EmptyJID, err
+	}
+	to, err := c.checkSendGuard(ctx, selfChatJID(c.client, to), message)
+	if err != nil {
+		return types.EmptyJID, err
+	}
+	if err := c.awaitSendSlot(ctx, message); err != nil {
+		return types.EmptyJID, err
+	}
+	return to, nil
+}
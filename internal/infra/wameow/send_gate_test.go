@@ -0,0 +1,70 @@
+package wameow
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"zpwoot/internal/domain/maintenance"
+
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+type frozenSessions map[string]bool
+
+func (f frozenSessions) SendFrozen(sessionID string) bool {
+	return f[sessionID]
+}
+
+func TestCheckSendGate(t *testing.T) {
+	gate := frozenSessions{"frozen": true}
+
+	frozen := &WameowClient{sessionID: "frozen", sendGate: gate}
+	if err := frozen.checkSendGate(); !errors.Is(err, maintenance.ErrSendFrozen) {
+		t.Errorf("frozen session: got %v, want ErrSendFrozen", err)
+	}
+
+	open := &WameowClient{sessionID: "open", sendGate: gate}
+	if err := open.checkSendGate(); err != nil {
+		t.Errorf("open session: got %v", err)
+	}
+
+	if err := (&WameowClient{sessionID: "frozen"}).checkSendGate(); err != nil {
+		t.Errorf("no gate: got %v", err)
+	}
+}
+
+type redirectGuard struct {
+	to      string
+	checked []string
+}
+
+func (g *redirectGuard) CheckSend(ctx context.Context, sessionID, recipient string) (string, error) {
+	g.checked = append(g.checked, sessionID+"/"+recipient)
+	return g.to, nil
+}
+
+func TestPrepareSendUsesTheClientGuard(t *testing.T) {
+	guard := &redirectGuard{to: "5511999990000@s.whatsapp.net"}
+	client := &WameowClient{sessionID: "sandboxed", sendGuard: guard}
+	message := &waE2E.Message{Conversation: proto.String("hi")}
+
+	to, err := client.prepareSend(context.Background(), types.NewJID("5511888880000", types.DefaultUserServer), message)
+	if err != nil {
+		t.Fatalf("prepareSend: %v", err)
+	}
+	if to.String() != guard.to {
+		t.Errorf("got %s, want the redirected %s", to, guard.to)
+	}
+	if len(guard.checked) != 1 || guard.checked[0] != "sandboxed/5511888880000@s.whatsapp.net" {
+		t.Errorf("guard checked %v", guard.checked)
+	}
+
+	other := &WameowClient{sessionID: "other"}
+	to, err = other.prepareSend(context.Background(), types.NewJID("5511888880000", types.DefaultUserServer), message)
+	if err != nil || to.User != "5511888880000" {
+		t.Errorf("client without a guard: got %s, %v", to, err)
+	}
+}
@@ -0,0 +1,46 @@
+package wameow
+
+import (
+	"context"
+	"fmt"
+
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// SendGuard decides which recipients a session may send to
+type SendGuard interface {
+	// CheckSend returns the JID the send to the recipient JID goes to, or an error when the session must
+	// not send to it
+	CheckSend(ctx context.Context, sessionID, recipient string) (string, error)
+}
+
+// SetSendGuard checks the recipient of every message sent through a session with guard; nil sends to
+// any recipient. It applies to the sessions created afterwards, so it is set before the sessions are started
+func (m *Manager) SetSendGuard(guard SendGuard) {
+	m.sendGuard = guard
+}
+
+// checkSendGuard returns the JID message goes to, which a sandbox may have rewritten, or an error when
+// the session may not send it to the recipient; edits, revokes and other protocol messages always pass,
+// so earlier sends can still be cleaned up
+func (c *WameowClient) checkSendGuard(ctx context.Context, to types.JID, message *waE2E.Message) (types.JID, error) {
+	if c.sendGuard == nil || message.GetProtocolMessage() != nil {
+		return to, nil
+	}
+
+	recipient := to.ToNonAD().String()
+	sendTo, err := c.sendGuard.CheckSend(ctx, c.sessionID, recipient)
+	if err != nil {
+		return types.EmptyJID, err
+	}
+	if sendTo == recipient {
+		return to, nil
+	}
+
+	redirected, err := types.ParseJID(sendTo)
+	if err != nil {
+		return types.EmptyJID, fmt.Errorf("invalid send guard recipient %q: %w", sendTo, err)
+	}
+	return redirected, nil
+}
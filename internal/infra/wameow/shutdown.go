@@ -0,0 +1,59 @@
+package wameow
+
+import (
+	"context"
+	"sync"
+)
+
+// Shutdown disconnects every client when the process stops, so the websockets are closed and the
+// QR loops end instead of being abandoned. Reconnects stop first, otherwise the supervisor would
+// dial the sessions again. It returns ctx's error when the clients did not all disconnect in time.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.reconnects.shutdown()
+
+	m.clientsMutex.RLock()
+	clients := make(map[string]*WameowClient, len(m.clients))
+	for sessionID, client := range m.clients {
+		clients[sessionID] = client
+	}
+	m.clientsMutex.RUnlock()
+
+	m.logger.InfoWithFields("Disconnecting WhatsApp clients", map[string]interface{}{
+		"clients": len(clients),
+	})
+
+	var wg sync.WaitGroup
+	for sessionID, client := range clients {
+		wg.Add(1)
+		go func(sessionID string, client *WameowClient) {
+			defer wg.Done()
+
+			wasConnected := client.IsConnected()
+			if err := client.Disconnect(); err != nil {
+				m.logger.WarnWithFields("Failed to disconnect client on shutdown", map[string]interface{}{
+					"session_id": sessionID,
+					"error":      err.Error(),
+				})
+				return
+			}
+			// A manual disconnect emits no Disconnected event, so the stored status is updated here
+			if wasConnected {
+				m.sessionMgr.UpdateConnectionStatus(sessionID, false)
+			}
+		}(sessionID, client)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		m.logger.Info("WhatsApp clients disconnected")
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,286 @@
+package wameow
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+
+	"zpwoot/internal/domain/message"
+)
+
+// maxTemplateButtons is the most buttons a hydrated template renders
+const maxTemplateButtons = 3
+
+// Template button types
+const (
+	TemplateButtonQuickReply = "quick_reply"
+	TemplateButtonURL        = "url"
+	TemplateButtonCall       = "call"
+)
+
+// TemplateButton is a button of a hydrated template. ID is sent back in the reply of a quick reply
+// button, URL is opened by a url button and PhoneNumber dialed by a call button.
+type TemplateButton struct {
+	Type        string
+	Text        string
+	ID          string
+	URL         string
+	PhoneNumber string
+}
+
+// TemplateMedia is the media shown in the header of a hydrated template, read from FilePath
+type TemplateMedia struct {
+	Type     string // image, video or document
+	FilePath string
+	MimeType string
+	Filename string
+}
+
+// TemplateMessage is a hydrated four row template: a header with a title or media, a body, a footer and
+// up to three buttons
+type TemplateMessage struct {
+	Title       string
+	HeaderMedia *TemplateMedia
+	Body        string
+	Footer      string
+	Buttons     []TemplateButton
+}
+
+// Validate checks the template has a body and well-formed buttons
+func (t *TemplateMessage) Validate() error {
+	if t.Body == "" {
+		return fmt.Errorf("template body is required")
+	}
+	if len(t.Buttons) == 0 || len(t.Buttons) > maxTemplateButtons {
+		return fmt.Errorf("template needs between 1 and %d buttons", maxTemplateButtons)
+	}
+	for i, button := range t.Buttons {
+		if button.Text == "" {
+			return fmt.Errorf("button %d: text is required", i+1)
+		}
+		switch button.Type {
+		case TemplateButtonQuickReply:
+		case TemplateButtonURL:
+			if button.URL == "" {
+				return fmt.Errorf("button %d: url is required", i+1)
+			}
+		case TemplateButtonCall:
+			if button.PhoneNumber == "" {
+				return fmt.Errorf("button %d: phoneNumber is required", i+1)
+			}
+		default:
+			return fmt.Errorf("button %d: invalid type %q. Valid types: quick_reply, url, call", i+1, button.Type)
+		}
+	}
+	if t.HeaderMedia != nil {
+		switch t.HeaderMedia.Type {
+		case "image", "video", "document":
+		default:
+			return fmt.Errorf("invalid header media type %q. Valid types: image, video, document", t.HeaderMedia.Type)
+		}
+	}
+	return nil
+}
+
+// SendTemplateMessage sends a hydrated template message
+func (m *Manager) SendTemplateMessage(sessionID, to string, template *TemplateMessage) (*message.SendResult, error) {
+	client := m.getClient(sessionID)
+	if client == nil {
+		return nil, fmt.Errorf("session %s not found", sessionID)
+	}
+	if !client.IsLoggedIn() {
+		return nil, fmt.Errorf("session %s is not logged in", sessionID)
+	}
+
+	if err := template.Validate(); err != nil {
+		return nil, err
+	}
+
+	resp, err := client.SendTemplateMessage(context.Background(), to, template)
+	if err != nil {
+		return &message.SendResult{
+			Status:    "failed",
+			Error:     err.Error(),
+			Timestamp: time.Now(),
+		}, err
+	}
+
+	return &message.SendResult{
+		MessageID: resp.ID,
+		Status:    "sent",
+		Timestamp: resp.Timestamp,
+	}, nil
+}
+
+// SendTemplateMessage builds and sends a hydrated four row template, uploading the header media first
+func (c *WameowClient) SendTemplateMessage(ctx context.Context, to string, template *TemplateMessage) (*whatsmeow.SendResponse, error) {
+	if !c.client.IsLoggedIn() {
+		return nil, fmt.Errorf("client is not logged in")
+	}
+
+	jid, err := c.parseJID(to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JID: %w", err)
+	}
+
+	hydrated := &waE2E.TemplateMessage_HydratedFourRowTemplate{
+		HydratedContentText: proto.String(template.Body),
+		TemplateID:          proto.String(fmt.Sprintf("%d", time.Now().UnixNano())),
+	}
+	if template.Footer != "" {
+		hydrated.HydratedFooterText = proto.String(template.Footer)
+	}
+
+	if template.HeaderMedia != nil {
+		if err := c.setTemplateHeaderMedia(ctx, hydrated, template.HeaderMedia); err != nil {
+			return nil, err
+		}
+	} else if template.Title != "" {
+		hydrated.Title = &waE2E.TemplateMessage_HydratedFourRowTemplate_HydratedTitleText{
+			HydratedTitleText: template.Title,
+		}
+	}
+
+	for i, button := range template.Buttons {
+		hydratedButton := &waE2E.HydratedTemplateButton{Index: proto.Uint32(uint32(i))}
+		switch button.Type {
+		case TemplateButtonQuickReply:
+			id := button.ID
+			if id == "" {
+				id = fmt.Sprintf("button_%d", i+1)
+			}
+			hydratedButton.HydratedButton = &waE2E.HydratedTemplateButton_QuickReplyButton{
+				QuickReplyButton: &waE2E.HydratedTemplateButton_HydratedQuickReplyButton{
+					DisplayText: proto.String(button.Text),
+					ID:          proto.String(id),
+				},
+			}
+		case TemplateButtonURL:
+			hydratedButton.HydratedButton = &waE2E.HydratedTemplateButton_UrlButton{
+				UrlButton: &waE2E.HydratedTemplateButton_HydratedURLButton{
+					DisplayText: proto.String(button.Text),
+					URL:         proto.String(button.URL),
+				},
+			}
+		case TemplateButtonCall:
+			hydratedButton.HydratedButton = &waE2E.HydratedTemplateButton_CallButton{
+				CallButton: &waE2E.HydratedTemplateButton_HydratedCallButton{
+					DisplayText: proto.String(button.Text),
+					PhoneNumber: proto.String(button.PhoneNumber),
+				},
+			}
+		}
+		hydrated.HydratedButtons = append(hydrated.HydratedButtons, hydratedButton)
+	}
+
+	msg := &waE2E.Message{
+		ViewOnceMessage: &waE2E.FutureProofMessage{
+			Message: &waE2E.Message{
+				TemplateMessage: &waE2E.TemplateMessage{
+					HydratedTemplate: hydrated,
+					Format: &waE2E.TemplateMessage_HydratedFourRowTemplate_{
+						HydratedFourRowTemplate: hydrated,
+					},
+				},
+			},
+		},
+	}
+
+	c.logger.InfoWithFields("Sending template message", map[string]interface{}{
+		"session_id":   c.sessionID,
+		"to":           to,
+		"button_count": len(template.Buttons),
+		"has_media":    template.HeaderMedia != nil,
+	})
+
+	resp, err := c.sendMessage(ctx, jid, msg)
+	if err != nil {
+		c.logger.ErrorWithFields("Failed to send template message", map[string]interface{}{
+			"session_id": c.sessionID,
+			"to":         to,
+			"error":      err.Error(),
+		})
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// setTemplateHeaderMedia uploads the header media and sets it as the template title
+func (c *WameowClient) setTemplateHeaderMedia(ctx context.Context, hydrated *waE2E.TemplateMessage_HydratedFourRowTemplate, media *TemplateMedia) error {
+	data, err := os.ReadFile(media.FilePath)
+	if err != nil {
+		return fmt.Errorf("failed to read header %s: %w", media.Type, err)
+	}
+
+	switch media.Type {
+	case "image":
+		uploaded, err := c.upload(ctx, data, whatsmeow.MediaImage)
+		if err != nil {
+			return fmt.Errorf("failed to upload header image: %w", err)
+		}
+		hydrated.Title = &waE2E.TemplateMessage_HydratedFourRowTemplate_ImageMessage{
+			ImageMessage: &waE2E.ImageMessage{
+				URL:           proto.String(uploaded.URL),
+				DirectPath:    proto.String(uploaded.DirectPath),
+				MediaKey:      uploaded.MediaKey,
+				Mimetype:      proto.String(templateMimeType(media.MimeType, "image/jpeg")),
+				FileEncSHA256: uploaded.FileEncSHA256,
+				FileSHA256:    uploaded.FileSHA256,
+				FileLength:    proto.Uint64(uploaded.FileLength),
+			},
+		}
+	case "video":
+		uploaded, err := c.upload(ctx, data, whatsmeow.MediaVideo)
+		if err != nil {
+			return fmt.Errorf("failed to upload header video: %w", err)
+		}
+		hydrated.Title = &waE2E.TemplateMessage_HydratedFourRowTemplate_VideoMessage{
+			VideoMessage: &waE2E.VideoMessage{
+				URL:           proto.String(uploaded.URL),
+				DirectPath:    proto.String(uploaded.DirectPath),
+				MediaKey:      uploaded.MediaKey,
+				Mimetype:      proto.String(templateMimeType(media.MimeType, "video/mp4")),
+				FileEncSHA256: uploaded.FileEncSHA256,
+				FileSHA256:    uploaded.FileSHA256,
+				FileLength:    proto.Uint64(uploaded.FileLength),
+			},
+		}
+	case "document":
+		uploaded, err := c.upload(ctx, data, whatsmeow.MediaDocument)
+		if err != nil {
+			return fmt.Errorf("failed to upload header document: %w", err)
+		}
+		filename := media.Filename
+		if filename == "" {
+			filename = "document"
+		}
+		hydrated.Title = &waE2E.TemplateMessage_HydratedFourRowTemplate_DocumentMessage{
+			DocumentMessage: &waE2E.DocumentMessage{
+				URL:           proto.String(uploaded.URL),
+				DirectPath:    proto.String(uploaded.DirectPath),
+				MediaKey:      uploaded.MediaKey,
+				Mimetype:      proto.String(templateMimeType(media.MimeType, "application/octet-stream")),
+				Title:         proto.String(filename),
+				FileName:      proto.String(filename),
+				FileEncSHA256: uploaded.FileEncSHA256,
+				FileSHA256:    uploaded.FileSHA256,
+				FileLength:    proto.Uint64(uploaded.FileLength),
+			},
+		}
+	}
+
+	return nil
+}
+
+func templateMimeType(mimeType, fallback string) string {
+	if mimeType == "" {
+		return fallback
+	}
+	return mimeType
+}
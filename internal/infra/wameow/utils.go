@@ -15,17 +15,22 @@ import (
 	"go.mau.fi/whatsmeow/store/sqlstore"
 	waTypes "go.mau.fi/whatsmeow/types"
 	"zpwoot/platform/logger"
+	"zpwoot/platform/phone"
 )
 
 // JIDValidator handles JID validation and normalization
 type JIDValidator struct {
 	phoneRegex *regexp.Regexp
+	// groupRegex matches bare group IDs: the current ones, longer than any phone number, and the
+	// legacy creator-timestamp ones
+	groupRegex *regexp.Regexp
 }
 
 // NewJIDValidator creates a new JID validator
 func NewJIDValidator() *JIDValidator {
 	return &JIDValidator{
 		phoneRegex: regexp.MustCompile(`^\d+$`),
+		groupRegex: regexp.MustCompile(`^(\d{16,}|\d{7,15}-\d{9,10})$`),
 	}
 }
 
@@ -39,8 +44,9 @@ func (v *JIDValidator) IsValid(jid string) bool {
 	// Normalize first, then validate
 	normalizedJID := v.Normalize(jid)
 
-	// Check for WhatsApp JID format (individual, group, or newsletter)
+	// Check for WhatsApp JID format (individual, LID, group, or newsletter)
 	if strings.Contains(normalizedJID, "@s.whatsapp.net") ||
+		strings.Contains(normalizedJID, "@lid") ||
 		strings.Contains(normalizedJID, "@g.us") ||
 		strings.Contains(normalizedJID, "@newsletter") {
 		return true
@@ -50,7 +56,7 @@ func (v *JIDValidator) IsValid(jid string) bool {
 }
 
 // Normalize converts a JID to standard WhatsApp format
-// Supports formats: +5511999999999, 5511999999999, 5511999999999@s.whatsapp.net, newsletters@newsletter
+// Supports formats: +5511999999999, 5511999999999, 5511999999999@s.whatsapp.net, 123456789@lid, newsletters@newsletter
 func (v *JIDValidator) Normalize(jid string) string {
 	jid = strings.TrimSpace(jid)
 
@@ -59,6 +65,14 @@ func (v *JIDValidator) Normalize(jid string) string {
 		return jid
 	}
 
+	if v.groupRegex.MatchString(jid) {
+		return jid + "@g.us"
+	}
+
+	if number, err := phone.Parse(jid); err == nil {
+		return number.Digits + "@s.whatsapp.net"
+	}
+
 	// Remove leading + if present
 	jid = strings.TrimPrefix(jid, "+")
 
@@ -82,6 +96,14 @@ func (v *JIDValidator) Parse(jid string) (waTypes.JID, error) {
 		return waTypes.EmptyJID, fmt.Errorf("JID cannot be empty")
 	}
 
+	// Phone numbers are checked against the numbering plan of their country before any send attempt;
+	// bare group IDs and other inputs are left to Normalize
+	if !strings.Contains(jid, "@") && phone.LooksLikeNumber(jid) {
+		if err := phone.Validate(jid); err != nil {
+			return waTypes.EmptyJID, fmt.Errorf("invalid phone number %s: %w", jid, err)
+		}
+	}
+
 	normalizedJID := v.Normalize(jid)
 
 	if !v.IsValid(normalizedJID) {
@@ -0,0 +1,22 @@
+package wameow
+
+import "testing"
+
+func TestJIDValidatorParseBareGroupIDs(t *testing.T) {
+	v := NewJIDValidator()
+	for _, raw := range []string{"120363025246125486", "5511999999999-1600000000"} {
+		jid, err := v.Parse(raw)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", raw, err)
+		}
+		if jid.Server != "g.us" || jid.User != raw {
+			t.Errorf("Parse(%q) = %s, want %s@g.us", raw, jid, raw)
+		}
+	}
+}
+
+func TestJIDValidatorParseRejectsImpossibleNumbers(t *testing.T) {
+	if _, err := NewJIDValidator().Parse("+55 11 9"); err == nil {
+		t.Error("Parse accepted a number shorter than any numbering plan")
+	}
+}
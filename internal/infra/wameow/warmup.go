@@ -0,0 +1,55 @@
+package wameow
+
+import (
+	"context"
+	"time"
+
+	"go.mau.fi/whatsmeow/proto/waE2E"
+)
+
+// SendThrottle paces the sends of a session, e.g. to warm up a newly paired number
+type SendThrottle interface {
+	// Acquire returns how long to wait before sending, or an error when the send must not happen
+	Acquire(ctx context.Context, sessionID string) (time.Duration, error)
+	// SessionPaired is called once a session pairs a new number
+	SessionPaired(sessionID string)
+}
+
+// SetSendThrottle paces every message sent through a session with throttle; nil disables pacing. It
+// applies to the sessions created afterwards, so it is set before the sessions are started
+func (m *Manager) SetSendThrottle(throttle SendThrottle) {
+	m.sendThrottle = throttle
+}
+
+// awaitSendSlot blocks until the session may send message; edits, revokes and other protocol
+// messages are never held back
+func (c *WameowClient) awaitSendSlot(ctx context.Context, message *waE2E.Message) error {
+	if c.sendThrottle == nil || message.GetProtocolMessage() != nil {
+		return nil
+	}
+
+	wait, err := c.sendThrottle.Acquire(ctx, c.sessionID)
+	if err != nil {
+		return err
+	}
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// notifySessionPaired lets the send throttle start pacing a newly paired session
+func (m *Manager) notifySessionPaired(sessionID string) {
+	if m.sendThrottle != nil {
+		m.sendThrottle.SessionPaired(sessionID)
+	}
+}
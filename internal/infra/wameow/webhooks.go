@@ -8,6 +8,8 @@ import (
 	"strings"
 	"time"
 
+	"go.mau.fi/whatsmeow/types/events"
+
 	webhookDomain "zpwoot/internal/domain/webhook"
 	"zpwoot/internal/infra/integrations/webhook"
 	"zpwoot/platform/logger"
@@ -79,6 +81,18 @@ var supportedEventTypes = []string{
 	"NewsletterLeave",
 	"NewsletterMuteChange",
 	"NewsletterLiveUpdate",
+	NewsletterMessageEventType,
+
+	// Decrypted votes on polls sent by the session
+	PollVoteEventType,
+	ListResponseEventType,
+	MessagePinEventType,
+	ChatEphemeralEventType,
+	FlowResponseEventType,
+	FloodDetectedEventType,
+	ContactUpdatedEventType,
+	SessionSyncStateEventType,
+	SessionWarningEventType,
 
 	// Facebook/Meta Bridge
 	"FBMessage",
@@ -119,7 +133,7 @@ func NewWhatsmeowWebhookHandler(logger *logger.Logger, webhookManager *webhook.W
 
 // HandleWhatsmeowEvent implements the WebhookEventHandler interface
 // It receives raw whatsmeow events and delivers them to webhook clients
-func (h *WhatsmeowWebhookHandler) HandleWhatsmeowEvent(evt interface{}, sessionID string) error {
+func (h *WhatsmeowWebhookHandler) HandleWhatsmeowEvent(ctx context.Context, evt interface{}, sessionID string) error {
 	if h.webhookManager == nil {
 		h.logger.Debug("Webhook manager not available, skipping event delivery")
 		return nil
@@ -159,12 +173,19 @@ func (h *WhatsmeowWebhookHandler) HandleWhatsmeowEvent(evt interface{}, sessionI
 		"timestamp": time.Now().Unix(),
 		"data":      eventData,
 	}
+	// Messages of chats with disappearing messages carry their timer and when they disappear
+	if msgEvt, ok := evt.(*events.Message); ok {
+		if ephemeral := webhookEphemeral(msgEvt); ephemeral != nil {
+			webhookPayload["ephemeral"] = ephemeral
+		}
+	}
 
 	// Create webhook event with the payload as data
 	webhookEvent := webhookDomain.NewWebhookEvent(sessionID, eventType, webhookPayload)
+	webhookEvent.RequestID = logger.RequestIDFromContext(ctx)
 
 	// Use the delivery service directly to deliver the event
-	return h.webhookManager.GetDeliveryService().DeliverEvent(context.Background(), webhookEvent)
+	return h.webhookManager.GetDeliveryService().DeliverEvent(ctx, webhookEvent)
 }
 
 // convertEventToRawData converts a whatsmeow event to raw data without normalization
@@ -190,6 +211,10 @@ func (h *WhatsmeowWebhookHandler) getEventType(evt interface{}) string {
 		return "nil"
 	}
 
+	if namer, ok := evt.(webhookEventNamer); ok {
+		return namer.WebhookEventType()
+	}
+
 	eventType := reflect.TypeOf(evt)
 	if eventType.Kind() == reflect.Ptr {
 		eventType = eventType.Elem()
@@ -0,0 +1,21 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"zpwoot/internal/domain/apikey"
+)
+
+// APIKeyRepository defines the interface for stored API keys
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *apikey.APIKey) error
+	GetByID(ctx context.Context, id uuid.UUID) (*apikey.APIKey, error)
+	// GetByHash returns apikey.ErrAPIKeyNotFound when no key has that hash
+	GetByHash(ctx context.Context, keyHash string) (*apikey.APIKey, error)
+	List(ctx context.Context) ([]*apikey.APIKey, error)
+	Update(ctx context.Context, key *apikey.APIKey) error
+	TouchLastUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) error
+}
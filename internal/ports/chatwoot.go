@@ -33,7 +33,10 @@ type ChatwootClient interface {
 	// Message operations
 	SendMessage(conversationID int, content string) (*ChatwootMessage, error)
 	SendMessageWithType(conversationID int, content string, messageType string) (*ChatwootMessage, error)
+	SendMessageWithReply(conversationID int, content string, messageType string, inReplyTo int) (*ChatwootMessage, error)
 	SendMediaMessage(conversationID int, content string, attachment io.Reader, filename string) (*ChatwootMessage, error)
+	UpdateMessageStatus(conversationID, messageID int, status string) error
+	DeleteMessage(conversationID, messageID int) error
 	GetMessages(conversationID int, before int) ([]ChatwootMessage, error)
 
 	// Account operations
@@ -254,32 +257,76 @@ type ZpMessage struct {
 	CwConversationID *int `json:"cw_conversation_id,omitempty"` // Chatwoot conversation ID
 
 	// Sync Status
-	SyncStatus string     `json:"sync_status"` // pending, synced, failed
+	SyncStatus string     `json:"sync_status"` // pending, synced, failed, deleted
 	CreatedAt  time.Time  `json:"created_at"`
 	UpdatedAt  time.Time  `json:"updated_at"`
 	SyncedAt   *time.Time `json:"synced_at,omitempty"`
+	// ReadSentAt is when a read receipt was sent for the message because an agent read it in Chatwoot
+	ReadSentAt *time.Time `json:"read_sent_at,omitempty"`
 }
 
 // ChatwootMessageRepository defines the interface for zpMessage operations
 type ChatwootMessageRepository interface {
-	CreateMessage(ctx context.Context, message *ZpMessage) error
+	UpsertMessage(ctx context.Context, message *ZpMessage) (bool, error)
 	GetMessageByZpID(ctx context.Context, sessionID, zpMessageID string) (*ZpMessage, error)
-	GetMessageByCwID(ctx context.Context, cwMessageID int) (*ZpMessage, error)
+	GetMessageByCwID(ctx context.Context, sessionID string, cwMessageID int) (*ZpMessage, error)
 	UpdateSyncStatus(ctx context.Context, id string, status string, cwMessageID, cwConversationID *int) error
 	GetMessagesBySession(ctx context.Context, sessionID string, limit, offset int) ([]*ZpMessage, error)
 	GetMessagesByChat(ctx context.Context, sessionID, chatJID string, limit, offset int) ([]*ZpMessage, error)
+	GetMessagesByCwConversation(ctx context.Context, sessionID string, cwConversationID, limit int) ([]*ZpMessage, error)
 	GetPendingSyncMessages(ctx context.Context, sessionID string, limit int) ([]*ZpMessage, error)
+	MarkReadSent(ctx context.Context, sessionID string, zpMessageIDs []string) error
 	DeleteMessage(ctx context.Context, id string) error
 }
 
 // ChatwootMessageMapper defines the interface for message mapping operations
 type ChatwootMessageMapper interface {
-	CreateMapping(ctx context.Context, sessionID, zpMessageID, zpSender, zpChat, zpType, content string, zpTimestamp time.Time, zpFromMe bool) (*ZpMessage, error)
+	CreateMapping(ctx context.Context, sessionID, zpMessageID, zpSender, zpChat, zpType, content string, zpTimestamp time.Time, zpFromMe bool) (*ZpMessage, bool, error)
 	UpdateMapping(ctx context.Context, sessionID, zpMessageID string, cwMessageID, cwConversationID int) error
 	GetMappingByZpID(ctx context.Context, sessionID, zpMessageID string) (*ZpMessage, error)
-	GetMappingByCwID(ctx context.Context, cwMessageID int) (*ZpMessage, error)
+	// GetMappingByCwID is scoped by session: sessions linked to different Chatwoot accounts or instances
+	// can see the same Chatwoot message IDs
+	GetMappingByCwID(ctx context.Context, sessionID string, cwMessageID int) (*ZpMessage, error)
+	GetMappingsByCwConversation(ctx context.Context, sessionID string, cwConversationID, limit int) ([]*ZpMessage, error)
 	IsMessageMapped(ctx context.Context, sessionID, zpMessageID string) bool
 	MarkAsFailed(ctx context.Context, sessionID, zpMessageID string) error
+	// MarkAsDeleted records that the message was deleted, so the deletion is not synced back
+	MarkAsDeleted(ctx context.Context, sessionID, zpMessageID string) error
+	// MarkAsRead records that a read receipt was sent for the messages, so they are not acknowledged again
+	MarkAsRead(ctx context.Context, sessionID string, zpMessageIDs []string) error
+}
+
+// ChatwootWebhookDeduplicator tracks Chatwoot webhook deliveries that were already processed
+type ChatwootWebhookDeduplicator interface {
+	Claim(key string) bool
+	Release(key string)
+}
+
+// ChatwootWebhookQueue processes Chatwoot webhooks asynchronously
+type ChatwootWebhookQueue interface {
+	Enqueue(sessionID, event string, process func(ctx context.Context) error) error
+	GetStats(sessionID string) *ChatwootWebhookQueueStats
+}
+
+// ChatwootWebhookQueueStats contains statistics about asynchronous webhook processing
+type ChatwootWebhookQueueStats struct {
+	Started        bool
+	Workers        int
+	QueueSize      int
+	QueueCapacity  int
+	Processed      int64
+	Failed         int64
+	Retried        int64
+	RecentFailures []ChatwootWebhookFailure
+}
+
+// ChatwootWebhookFailure describes a webhook that failed after all retries
+type ChatwootWebhookFailure struct {
+	SessionID string
+	Event     string
+	Error     string
+	Attempts  int
+	FailedAt  time.Time
 }
 
 // ChatwootWebhookPayload represents the payload structure for Chatwoot webhooks
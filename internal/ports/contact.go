@@ -37,6 +37,14 @@ type ContactRepository interface {
 	GetContactsByType(ctx context.Context, sessionID, contactType string) ([]*contact.Contact, error)
 }
 
+// ContactChangeRepository defines the interface for the contact change feed
+type ContactChangeRepository interface {
+	Create(ctx context.Context, change *contact.Change) error
+	// ListSince returns up to limit changes recorded after since, oldest first
+	ListSince(ctx context.Context, sessionID string, since time.Time, limit int) ([]*contact.Change, error)
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
 // ContactManager defines the interface for WhatsApp contact operations
 type ContactManager interface {
 	// IsOnWhatsApp checks if phone numbers are registered on WhatsApp
@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"zpwoot/internal/domain/conversation"
+)
+
+// ConversationStateRepository defines the interface for conversation state data operations
+type ConversationStateRepository interface {
+	// Upsert stores the state of a chat, replacing the one it had
+	Upsert(ctx context.Context, state *conversation.State) error
+	Get(ctx context.Context, sessionID, chatJID string) (*conversation.State, error)
+	List(ctx context.Context, req *conversation.ListStatesRequest) ([]*conversation.State, int, error)
+	Delete(ctx context.Context, sessionID, chatJID string) error
+	// DeleteExpired drops the states that expired by now and returns how many it dropped
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
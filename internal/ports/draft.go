@@ -0,0 +1,25 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"zpwoot/internal/domain/draft"
+)
+
+// DraftRepository defines the interface for message draft data operations
+type DraftRepository interface {
+	Upsert(ctx context.Context, draft *draft.Draft) error
+	GetByChat(ctx context.Context, sessionID, chatJID string) (*draft.Draft, error)
+	List(ctx context.Context, req *draft.ListDraftsRequest) ([]*draft.Draft, int, error)
+	Update(ctx context.Context, draft *draft.Draft) error
+	Delete(ctx context.Context, sessionID, chatJID string) error
+	// ClaimDue marks scheduled drafts due until the given time as sending and returns them
+	ClaimDue(ctx context.Context, until time.Time, limit int) ([]*draft.Draft, error)
+	// ReleaseStale reschedules drafts claimed before claimedBefore and returns how many
+	ReleaseStale(ctx context.Context, claimedBefore time.Time) (int, error)
+	GetByID(ctx context.Context, sessionID string, id uuid.UUID) (*draft.Draft, error)
+	CancelScheduled(ctx context.Context, sessionID string, id uuid.UUID) (bool, error)
+}
@@ -0,0 +1,28 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"zpwoot/internal/domain/history"
+)
+
+// HistoryRepository defines the interface for message history data operations
+type HistoryRepository interface {
+	// Upsert stores a message keyed by session, chat and message ID; storing it again refreshes its
+	// content but keeps its edit, revoke and pin state
+	Upsert(ctx context.Context, message *history.Message) error
+	MarkEdited(ctx context.Context, sessionID, chatJID, messageID, text string, editedAt time.Time) error
+	MarkRevoked(ctx context.Context, sessionID, chatJID, messageID string, revokedAt time.Time) error
+	// SetPin stores the pinned state of a message; a nil pin unpins it
+	SetPin(ctx context.Context, sessionID, chatJID, messageID string, pin *history.Pin) error
+	// SetActor attributes a stored message to the actor that sent it
+	SetActor(ctx context.Context, sessionID, messageID string, actor *history.Actor) error
+	// GetByMessageID gets a stored message of the session by its WhatsApp ID, whichever chat it is in
+	GetByMessageID(ctx context.Context, sessionID, messageID string) (*history.Message, error)
+	// GetOldestInChat gets the earliest stored message of a chat
+	GetOldestInChat(ctx context.Context, sessionID, chatJID string) (*history.Message, error)
+	// ListReceivedUntil gets the most recent messages received in a chat up to until, newest first
+	ListReceivedUntil(ctx context.Context, sessionID, chatJID string, until time.Time, limit int) ([]*history.Message, error)
+	ListByChat(ctx context.Context, req *history.ListRequest) ([]*history.Message, int, error)
+}
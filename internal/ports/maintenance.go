@@ -0,0 +1,21 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"zpwoot/internal/domain/maintenance"
+)
+
+// MaintenanceRepository defines the interface for maintenance state data operations
+type MaintenanceRepository interface {
+	// Get returns the persisted state; Enabled is false when the instance is not in maintenance
+	Get(ctx context.Context) (*maintenance.Status, error)
+	// SaveInstance puts the instance into maintenance, keeping its start when it already was
+	SaveInstance(ctx context.Context, reason string, retryAfter time.Duration) error
+	DeleteInstance(ctx context.Context) error
+	// SaveSession freezes the session, keeping its start when it already was frozen
+	SaveSession(ctx context.Context, freeze *maintenance.SessionFreeze) error
+	// DeleteSession unfreezes the session and reports whether it was frozen
+	DeleteSession(ctx context.Context, sessionID string) (bool, error)
+}
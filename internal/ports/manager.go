@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"go.mau.fi/whatsmeow/types"
+	"zpwoot/internal/domain/history"
 	"zpwoot/internal/domain/message"
 	"zpwoot/internal/domain/session"
 )
@@ -15,26 +16,34 @@ type WameowManager interface {
 	ConnectSession(sessionID string) error
 	DisconnectSession(sessionID string) error
 	LogoutSession(sessionID string) error
+	RestartSession(sessionID string) error
 
 	GetQRCode(sessionID string) (*session.QRCodeResponse, error)
 	PairPhone(sessionID, phoneNumber string) error
 	IsConnected(sessionID string) bool
 	GetDeviceInfo(sessionID string) (*session.DeviceInfo, error)
+	// GetSyncState returns whether the connected session waits for its phone, nil when not connected
+	GetSyncState(sessionID string) *session.SyncState
+	// GetReconnectState returns how the session is being reconnected, nil when it is not supervised
+	GetReconnectState(sessionID string) *session.ReconnectState
 
 	SetProxy(sessionID string, config *session.ProxyConfig) error
 	GetProxy(sessionID string) (*session.ProxyConfig, error)
 	GetUserJID(sessionID string) (string, error)
 
 	// Message operations
-	SendMessage(sessionID, to, messageType, body, caption, file, filename string, latitude, longitude float64, contactName, contactPhone string, contextInfo *message.ContextInfo) (*message.SendResult, error)
+	SendMessage(sessionID, to, messageType, body, caption, file, filename, mimeType string, latitude, longitude float64, contactName, contactPhone string, contextInfo *message.ContextInfo) (*message.SendResult, error)
 	SendMediaMessage(sessionID, to string, media []byte, mediaType, caption string) error
 	SendButtonMessage(sessionID, to, body string, buttons []map[string]string) (*message.SendResult, error)
-	SendListMessage(sessionID, to, body, buttonText string, sections []map[string]interface{}) (*message.SendResult, error)
-	SendReaction(sessionID, to, messageID, reaction string) error
+	SendListMessage(sessionID, to, title, body, footer, buttonText string, sections []map[string]interface{}, fallbackToText bool) (*message.SendResult, error)
+	SendReaction(sessionID, to, messageID, reaction, participant string, fromMe bool) error
 	SendPresence(sessionID, to, presence string) error
-	EditMessage(sessionID, to, messageID, newText string) error
-	MarkRead(sessionID, to, messageID string) error
-	RevokeMessage(sessionID, to, messageID string) (*message.SendResult, error)
+	EditMessage(sessionID, to, messageID, newText string) (*message.SendResult, error)
+	MarkRead(sessionID, to string, messageIDs []string, sender string) error
+	RevokeMessage(sessionID, to, messageID, participant string) (*message.SendResult, error)
+	DeleteMessageForMe(sessionID, to, messageID, participant string, fromMe bool, timestamp time.Time, deleteMedia bool) (*message.SendResult, error)
+	PinMessage(sessionID, to, messageID, participant string, fromMe, pin bool, duration time.Duration) (*message.SendResult, error)
+	RequestHistoryBackfill(sessionID, chatJID, oldestMessageID string, oldestFromMe bool, oldestTimestamp time.Time, count int) ([]*history.Message, error)
 
 	// Contact operations
 	IsOnWhatsApp(ctx context.Context, sessionID string, phoneNumbers []string) (map[string]interface{}, error)
@@ -69,6 +78,19 @@ type WameowManager interface {
 	GetSessionStats(sessionID string) (*SessionStats, error)
 	RegisterEventHandler(sessionID string, handler EventHandler) error
 	UnregisterEventHandler(sessionID string, handlerID string) error
+	ListEventHandlers(sessionID string) []*EventHandlerRegistration
+
+	// Request tracing: events triggered by an API call carry the request ID found in ctx, and sent
+	// messages are attributed to the actor found in ctx
+	TraceRequest(ctx context.Context, sessionID, messageID string)
+	TraceSessionRequest(ctx context.Context, sessionID string)
+}
+
+// EventHandlerRegistration describes an event handler registered for a session
+type EventHandlerRegistration struct {
+	ID           string
+	Attached     bool // false when the session had no client at registration, so the handler never fires
+	RegisteredAt time.Time
 }
 
 // GroupInfo represents information about a WhatsApp group
@@ -49,7 +49,7 @@ type MessageRepository interface {
 // MessageManager defines the interface for WhatsApp message operations
 type MessageManager interface {
 	// SendMessage sends a text message
-	SendMessage(sessionID, to, messageType, body, caption, file, filename string, latitude, longitude float64, contactName, contactPhone string, contextInfo *message.ContextInfo) (*message.SendResult, error)
+	SendMessage(sessionID, to, messageType, body, caption, file, filename, mimeType string, latitude, longitude float64, contactName, contactPhone string, contextInfo *message.ContextInfo) (*message.SendResult, error)
 
 	// SendMediaMessage sends a media message (image, video, audio, document)
 	SendMediaMessage(sessionID, to string, media []byte, mediaType, caption string) error
@@ -58,22 +58,28 @@ type MessageManager interface {
 	SendButtonMessage(sessionID, to, body string, buttons []map[string]string) (*message.SendResult, error)
 
 	// SendListMessage sends a message with interactive list
-	SendListMessage(sessionID, to, body, buttonText string, sections []map[string]interface{}) (*message.SendResult, error)
+	SendListMessage(sessionID, to, title, body, footer, buttonText string, sections []map[string]interface{}, fallbackToText bool) (*message.SendResult, error)
 
 	// SendReaction sends a reaction to a message
-	SendReaction(sessionID, to, messageID, reaction string) error
+	SendReaction(sessionID, to, messageID, reaction, participant string, fromMe bool) error
 
 	// SendPresence sends presence information (typing, recording, etc.)
 	SendPresence(sessionID, to, presence string) error
 
 	// EditMessage edits an existing message
-	EditMessage(sessionID, to, messageID, newText string) error
+	EditMessage(sessionID, to, messageID, newText string) (*message.SendResult, error)
 
-	// MarkRead marks a message as read
-	MarkRead(sessionID, to, messageID string) error
+	// MarkRead marks messages from the same sender as read
+	MarkRead(sessionID, to string, messageIDs []string, sender string) error
 
-	// RevokeMessage revokes/deletes a message
-	RevokeMessage(sessionID, to, messageID string) (*message.SendResult, error)
+	// RevokeMessage revokes/deletes a message for everyone
+	RevokeMessage(sessionID, to, messageID, participant string) (*message.SendResult, error)
+
+	// DeleteMessageForMe deletes a message only for this account
+	DeleteMessageForMe(sessionID, to, messageID, participant string, fromMe bool, timestamp time.Time, deleteMedia bool) (*message.SendResult, error)
+
+	// PinMessage pins a message in a chat for duration, or unpins it when pin is false
+	PinMessage(sessionID, to, messageID, participant string, fromMe, pin bool, duration time.Duration) (*message.SendResult, error)
 
 	// ForwardMessage forwards a message to another chat
 	ForwardMessage(sessionID, fromChat, toChat, messageID string) (*message.SendResult, error)
@@ -0,0 +1,27 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"zpwoot/internal/domain/outbox"
+)
+
+// OutboxRepository defines the interface for the outbound message queue
+type OutboxRepository interface {
+	Create(ctx context.Context, msg *outbox.Message) error
+	// LastSlot returns when the chat's latest message was sent or is due, or the zero time when it has none
+	LastSlot(ctx context.Context, sessionID, chatJID string) (time.Time, error)
+	// ClaimDue marks the oldest due message of up to limit chats as sending and returns them
+	ClaimDue(ctx context.Context, now time.Time, limit int) ([]*outbox.Message, error)
+	// ReleaseStale puts messages claimed before claimedBefore back in the queue and returns how many
+	ReleaseStale(ctx context.Context, claimedBefore time.Time) (int, error)
+	Update(ctx context.Context, msg *outbox.Message) error
+	Postpone(ctx context.Context, sessionID, chatJID string, sendAfter time.Time) error
+	GetByID(ctx context.Context, sessionID string, id uuid.UUID) (*outbox.Message, error)
+	// CancelQueued marks the message cancelled unless the dispatcher already claimed it, and reports whether it did
+	CancelQueued(ctx context.Context, sessionID string, id uuid.UUID) (bool, error)
+	List(ctx context.Context, req *outbox.ListRequest) ([]*outbox.Message, int, error)
+}
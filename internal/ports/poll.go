@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+
+	"zpwoot/internal/domain/poll"
+)
+
+// PollRepository defines the interface for poll definition data operations
+type PollRepository interface {
+	Create(ctx context.Context, poll *poll.Poll) error
+	GetByMessageID(ctx context.Context, sessionID, messageID string) (*poll.Poll, error)
+	// SaveVote stores the voter's selection unless a newer vote of the same voter is already stored
+	SaveVote(ctx context.Context, vote *poll.Vote) error
+	ListVotes(ctx context.Context, pollID string) ([]*poll.Vote, error)
+}
@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+
+	"zpwoot/internal/domain/privacy"
+)
+
+// PrivacyRepository defines the interface for per-session privacy settings
+type PrivacyRepository interface {
+	// GetBySession returns nil when the session has no privacy configuration
+	GetBySession(ctx context.Context, sessionID string) (*privacy.Config, error)
+	Upsert(ctx context.Context, config *privacy.Config) error
+}
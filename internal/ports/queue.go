@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"zpwoot/internal/domain/queue"
+)
+
+// QueueRepository defines the interface for the offline message queue
+type QueueRepository interface {
+	Create(ctx context.Context, msg *queue.QueuedMessage) error
+	CountPending(ctx context.Context, sessionID string) (int, error)
+	// ClaimNext marks the session's oldest queued message as sending and returns it, or nil when none is left
+	ClaimNext(ctx context.Context, sessionID string, now time.Time) (*queue.QueuedMessage, error)
+	// ReleaseStale puts the session's messages claimed before claimedBefore back in the queue and returns how many
+	ReleaseStale(ctx context.Context, sessionID string, claimedBefore time.Time) (int, error)
+	Update(ctx context.Context, msg *queue.QueuedMessage) error
+	ExpireBefore(ctx context.Context, now time.Time) (int, error)
+	GetByID(ctx context.Context, sessionID string, id uuid.UUID) (*queue.QueuedMessage, error)
+	CancelQueued(ctx context.Context, sessionID string, id uuid.UUID) (bool, error)
+}
@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+
+	"zpwoot/internal/domain/routing"
+)
+
+// RoutingRuleRepository defines the interface for routing rule data operations
+type RoutingRuleRepository interface {
+	Create(ctx context.Context, rule *routing.Rule) error
+	GetByID(ctx context.Context, sessionID, id string) (*routing.Rule, error)
+	// ListBySession returns the session's rules, highest priority first
+	ListBySession(ctx context.Context, sessionID string) ([]*routing.Rule, error)
+	Update(ctx context.Context, rule *routing.Rule) error
+	Delete(ctx context.Context, sessionID, id string) error
+}
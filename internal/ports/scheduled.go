@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"zpwoot/internal/domain/scheduled"
+)
+
+// ScheduledMessageRepository defines the interface for messages sent at a later time
+type ScheduledMessageRepository interface {
+	Create(ctx context.Context, msg *scheduled.Message) error
+	// ClaimDue marks up to limit messages due at now as sending and returns them
+	ClaimDue(ctx context.Context, now time.Time, limit int) ([]*scheduled.Message, error)
+	// ReleaseStale reschedules messages claimed before claimedBefore and returns how many
+	ReleaseStale(ctx context.Context, claimedBefore time.Time) (int, error)
+	Update(ctx context.Context, msg *scheduled.Message) error
+	Reschedule(ctx context.Context, sessionID string, id uuid.UUID, scheduledAt time.Time) (bool, error)
+	Cancel(ctx context.Context, sessionID string, id uuid.UUID) (bool, error)
+	GetByID(ctx context.Context, sessionID string, id uuid.UUID) (*scheduled.Message, error)
+	List(ctx context.Context, req *scheduled.ListRequest) ([]*scheduled.Message, int, error)
+}
@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+
+	"zpwoot/internal/domain/sendguard"
+)
+
+// SendGuardRepository defines the interface for per-session send policies and their violations
+type SendGuardRepository interface {
+	// GetBySession returns nil when the session has no send policy
+	GetBySession(ctx context.Context, sessionID string) (*sendguard.Policy, error)
+	Upsert(ctx context.Context, policy *sendguard.Policy) error
+	CreateViolation(ctx context.Context, violation *sendguard.Violation) error
+	ListViolations(ctx context.Context, sessionID string, limit int) ([]*sendguard.Violation, error)
+}
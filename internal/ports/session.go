@@ -17,6 +17,8 @@ type SessionRepository interface {
 	Delete(ctx context.Context, id string) error
 	UpdateConnectionStatus(ctx context.Context, id string, isConnected bool) error
 	UpdateLastSeen(ctx context.Context, id string) error
+	// SetWarning stores the last account warning of a session; nil clears it
+	SetWarning(ctx context.Context, id string, warning *session.Warning) error
 	GetActiveSessions(ctx context.Context) ([]*session.Session, error)
 	CountByConnectionStatus(ctx context.Context, isConnected bool) (int, error)
 }
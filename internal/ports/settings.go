@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"zpwoot/internal/domain/settings"
+)
+
+// SettingsRepository defines the interface for runtime setting data operations
+type SettingsRepository interface {
+	List(ctx context.Context) ([]*settings.Setting, error)
+	// Upsert saves the given settings, stamping them with the database time
+	Upsert(ctx context.Context, settings []*settings.Setting) error
+	// LastUpdated returns when any setting last changed, or nil when none is stored
+	LastUpdated(ctx context.Context) (*time.Time, error)
+}
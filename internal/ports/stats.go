@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"zpwoot/internal/domain/stats"
+)
+
+// MessageStatsRepository defines the interface for daily message statistics data operations
+type MessageStatsRepository interface {
+	// Add increments the counter of a session, day, type and direction, creating it when missing
+	Add(ctx context.Context, sessionID, day, msgType, direction string, delta int64) error
+	// List returns the counters of a session between two days, both included
+	List(ctx context.Context, sessionID, from, to string) ([]*stats.DailyCount, error)
+}
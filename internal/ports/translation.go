@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+
+	"zpwoot/internal/domain/translation"
+)
+
+// TranslationRepository defines the interface for per-session translation settings
+type TranslationRepository interface {
+	// GetBySession returns nil when the session has no translation configuration
+	GetBySession(ctx context.Context, sessionID string) (*translation.Config, error)
+	Upsert(ctx context.Context, config *translation.Config) error
+}
@@ -98,10 +98,13 @@ type ChatwootConfig struct {
 	ImportMessages bool     `json:"importMessages" db:"importMessages"`
 	ImportDays     int      `json:"importDays" db:"importDays"`
 	MergeBrazil    bool     `json:"mergeBrazil" db:"mergeBrazil"`
+	SyncReads      bool     `json:"syncReads" db:"syncReads"` // WhatsApp read receipts mark Chatwoot messages as read
+	SendReads      bool     `json:"sendReads" db:"sendReads"` // Agent reads in Chatwoot send WhatsApp read receipts
 	Organization   *string  `json:"organization,omitempty" db:"organization"`
 	Logo           *string  `json:"logo,omitempty" db:"logo"`
 	Number         *string  `json:"number,omitempty" db:"number"`
 	IgnoreJids     []string `json:"ignoreJids,omitempty" db:"ignoreJids"`
+	WebhookSecret  *string  `json:"-" db:"webhookSecret"` // Inbound webhooks must present it when set
 
 	CreatedAt time.Time `json:"createdAt" db:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt" db:"updatedAt"`
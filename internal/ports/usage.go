@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+
+	"zpwoot/internal/domain/usage"
+)
+
+// UsageRepository defines the interface for workspace usage counter data operations
+type UsageRepository interface {
+	// Add increments a counter, creating it when missing
+	Add(ctx context.Context, workspace, metric, period string, delta int64) error
+	Get(ctx context.Context, workspace, metric, period string) (int64, error)
+	List(ctx context.Context, req *usage.ListCountersRequest) ([]*usage.Counter, error)
+	CountSessions(ctx context.Context) (int, error)
+}
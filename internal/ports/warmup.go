@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"zpwoot/internal/domain/warmup"
+)
+
+// WarmupRepository defines the interface for per-session warm-up profiles and their send counters
+type WarmupRepository interface {
+	// GetBySession returns nil when the session has no warm-up profile
+	GetBySession(ctx context.Context, sessionID string) (*warmup.Profile, error)
+	Upsert(ctx context.Context, profile *warmup.Profile) error
+	IncrementSent(ctx context.Context, sessionID, day string, dailyCap int) (int, bool, error)
+}
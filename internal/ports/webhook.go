@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"zpwoot/internal/domain/webhook"
 )
@@ -31,6 +32,14 @@ type WebhookRepository interface {
 	UpdateWebhookStats(ctx context.Context, webhookID string, stats *WebhookStats) error
 }
 
+// WebhookEventRepository defines the interface for the store of dispatched webhook events
+type WebhookEventRepository interface {
+	Create(ctx context.Context, event *webhook.WebhookEvent) error
+	// ListRange returns up to limit events of the session timestamped from from to to, oldest first
+	ListRange(ctx context.Context, sessionID string, from, to time.Time, eventTypes []string, limit int) ([]*webhook.WebhookEvent, error)
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
 // WebhookDeliveryRepository defines the interface for webhook delivery operations
 type WebhookDeliveryRepository interface {
 	Create(ctx context.Context, delivery *WebhookDelivery) error
@@ -90,3 +99,26 @@ type DeliveryStats struct {
 	From            int64   `json:"from"`
 	To              int64   `json:"to"`
 }
+
+// WebhookDispatchControl adjusts webhook delivery at runtime, without a restart
+type WebhookDispatchControl interface {
+	DispatchStatus() *WebhookDispatchStatus
+	// SetWorkers changes the delivery concurrency; deliveries in progress finish on the workers stopped
+	SetWorkers(workers int) error
+	// PauseDispatch holds back the deliveries of a session, or of every session when sessionID is empty
+	PauseDispatch(sessionID string)
+	ResumeDispatch(sessionID string)
+	// DrainSession removes the queued and held back deliveries of a session whose dispatch is paused and
+	// returns their events
+	DrainSession(sessionID string) ([]*webhook.WebhookEvent, error)
+}
+
+// WebhookDispatchStatus describes webhook delivery and the pauses set through WebhookDispatchControl
+type WebhookDispatchStatus struct {
+	Workers        int
+	QueueSize      int
+	QueueCapacity  int
+	Parked         int
+	PausedAll      bool
+	PausedSessions map[string]time.Time
+}
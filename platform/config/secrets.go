@@ -0,0 +1,180 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	vault "github.com/hashicorp/vault/api"
+)
+
+const secretsRequestTimeout = 10 * time.Second
+
+// secrets resolves the secret settings (API keys, database URL, ...). A secret is taken from its env var,
+// then from the file named by its _FILE variant (Docker secrets), then from the secret manager chosen
+// with SECRETS_PROVIDER, whose secret holds the values keyed by env var name.
+type secrets struct {
+	managed map[string]string
+}
+
+// loadSecrets fetches the secret manager values once at startup
+func loadSecrets() (*secrets, error) {
+	var managed map[string]string
+	var err error
+
+	switch provider := strings.ToLower(strings.TrimSpace(os.Getenv("SECRETS_PROVIDER"))); provider {
+	case "":
+	case "vault":
+		managed, err = loadVaultSecrets()
+	case "aws":
+		managed, err = loadAWSSecrets()
+	default:
+		return nil, fmt.Errorf("unknown SECRETS_PROVIDER %q (expected vault or aws)", provider)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &secrets{managed: managed}, nil
+}
+
+func (s *secrets) get(key, defaultValue string) (string, error) {
+	if value := os.Getenv(key); value != "" {
+		return value, nil
+	}
+
+	value, err := readEnvFile(key)
+	if err != nil || value != "" {
+		return value, err
+	}
+
+	if value := s.managed[key]; value != "" {
+		return value, nil
+	}
+	return defaultValue, nil
+}
+
+// readEnvFile returns the trimmed content of the file named by key_FILE, or "" when it is not set
+func readEnvFile(key string) (string, error) {
+	path := os.Getenv(key + "_FILE")
+	if path == "" {
+		return "", nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s_FILE: %w", key, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// getEnvOrFile reads the credentials of the secret managers themselves
+func getEnvOrFile(key string) (string, error) {
+	if value := os.Getenv(key); value != "" {
+		return value, nil
+	}
+	return readEnvFile(key)
+}
+
+// loadVaultSecrets reads VAULT_SECRET_PATH (e.g. secret/data/zpwoot) from the KV v1 or v2 engine.
+// The client takes VAULT_ADDR, VAULT_NAMESPACE and the TLS settings (VAULT_CACERT, ...) from the environment.
+func loadVaultSecrets() (map[string]string, error) {
+	path := strings.Trim(os.Getenv("VAULT_SECRET_PATH"), "/")
+	token, err := getEnvOrFile("VAULT_TOKEN")
+	if err != nil {
+		return nil, err
+	}
+	if os.Getenv("VAULT_ADDR") == "" || path == "" || token == "" {
+		return nil, fmt.Errorf("vault secrets need VAULT_ADDR, VAULT_TOKEN and VAULT_SECRET_PATH")
+	}
+
+	vaultConfig := vault.DefaultConfig()
+	if vaultConfig.Error != nil {
+		return nil, fmt.Errorf("invalid vault configuration: %w", vaultConfig.Error)
+	}
+	vaultConfig.Timeout = secretsRequestTimeout
+
+	client, err := vault.NewClient(vaultConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create vault client: %w", err)
+	}
+	client.SetToken(token)
+
+	ctx, cancel := context.WithTimeout(context.Background(), secretsRequestTimeout)
+	defer cancel()
+
+	secret, err := client.Logical().ReadWithContext(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch vault secrets: %w", err)
+	}
+	if secret == nil {
+		return nil, fmt.Errorf("vault secret %s not found", path)
+	}
+
+	data := secret.Data
+	if inner, ok := data["data"].(map[string]interface{}); ok {
+		if _, isV2 := data["metadata"]; isV2 {
+			data = inner
+		}
+	}
+	return stringifySecrets(data), nil
+}
+
+// loadAWSSecrets reads the JSON secret AWS_SECRETS_MANAGER_SECRET_ID from AWS Secrets Manager.
+// Credentials come from the default AWS chain: env vars, shared profile, web identity (IRSA),
+// ECS task role or EC2 instance profile.
+func loadAWSSecrets() (map[string]string, error) {
+	secretID := os.Getenv("AWS_SECRETS_MANAGER_SECRET_ID")
+	if secretID == "" {
+		return nil, fmt.Errorf("aws secrets need AWS_SECRETS_MANAGER_SECRET_ID")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), secretsRequestTimeout)
+	defer cancel()
+
+	cfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load aws configuration: %w", err)
+	}
+	if cfg.Region == "" {
+		return nil, fmt.Errorf("aws secrets need AWS_REGION")
+	}
+
+	client := secretsmanager.NewFromConfig(cfg, func(o *secretsmanager.Options) {
+		if endpoint := os.Getenv("AWS_SECRETS_MANAGER_ENDPOINT"); endpoint != "" {
+			o.BaseEndpoint = aws.String(endpoint)
+		}
+	})
+
+	output, err := client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: aws.String(secretID)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch aws secrets: %w", err)
+	}
+	if output.SecretString == nil {
+		return nil, fmt.Errorf("aws secret %s has no SecretString", secretID)
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(*output.SecretString), &data); err != nil {
+		return nil, fmt.Errorf("aws secret %s is not a JSON object: %w", secretID, err)
+	}
+	return stringifySecrets(data), nil
+}
+
+func stringifySecrets(data map[string]interface{}) map[string]string {
+	values := make(map[string]string, len(data))
+	for key, value := range data {
+		if s, ok := value.(string); ok {
+			values[key] = s
+		} else if value != nil {
+			values[key] = fmt.Sprint(value)
+		}
+	}
+	return values
+}
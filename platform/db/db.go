@@ -3,9 +3,10 @@ package db
 import (
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 
 	"zpwoot/internal/infra/db"
 	"zpwoot/platform/logger"
@@ -13,28 +14,42 @@ import (
 
 type DB struct {
 	*sqlx.DB
+	queryTimer *queryTimer
 }
 
 func New(databaseURL string) (*DB, error) {
-	sqlxDB, err := sqlx.Connect("postgres", databaseURL)
+	connector, err := pq.NewConnector(databaseURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	timer := &queryTimer{}
+	sqlxDB := sqlx.NewDb(sql.OpenDB(&timedConnector{base: connector, timer: timer}), "postgres")
+
 	if err := sqlxDB.Ping(); err != nil {
+		_ = sqlxDB.Close()
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	return &DB{DB: sqlxDB}, nil
+	return &DB{DB: sqlxDB, queryTimer: timer}, nil
+}
+
+// SetSlowQueryLog logs and counts queries slower than threshold, labelled with the
+// route and session of the request that issued them; 0 disables it
+func (db *DB) SetSlowQueryLog(threshold time.Duration, logger *logger.Logger) {
+	db.queryTimer.set(threshold, logger)
 }
 
-func NewWithMigrations(databaseURL string, logger *logger.Logger) (*DB, error) {
+// NewWithMigrations connects and applies the pending migrations, waiting up to lockTimeout for
+// migrations another replica is running (0 uses db.DefaultMigrationLockTimeout)
+func NewWithMigrations(databaseURL string, lockTimeout time.Duration, logger *logger.Logger) (*DB, error) {
 	database, err := New(databaseURL)
 	if err != nil {
 		return nil, err
 	}
 
 	migrator := db.NewMigrator(database.DB.DB, logger)
+	migrator.SetLockTimeout(lockTimeout)
 	if err := migrator.RunMigrations(); err != nil {
 		if closeErr := database.Close(); closeErr != nil {
 			logger.Error("Failed to close database after migration error: " + closeErr.Error())
@@ -0,0 +1,149 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"hash/fnv"
+	"time"
+
+	"zpwoot/platform/logger"
+)
+
+// DefaultLeaderRetryInterval is how often a replica tries to take a job lock held elsewhere, and how
+// often the holder checks that its lock connection is still alive
+const DefaultLeaderRetryInterval = 15 * time.Second
+
+// leaderLockNamespace keeps job locks apart from other advisory locks, such as the migration lock
+const leaderLockNamespace = "zpwoot:job:"
+
+// Leader runs background jobs on exactly one replica. A job runs while its replica holds a Postgres
+// advisory lock named after it; the lock lives on a dedicated connection, so it is released when the
+// job stops or the replica dies, and another replica takes the job over on its next try.
+type Leader struct {
+	db            *sql.DB
+	logger        *logger.Logger
+	retryInterval time.Duration
+}
+
+func NewLeader(db *sql.DB, logger *logger.Logger) *Leader {
+	return &Leader{
+		db:            db,
+		logger:        logger,
+		retryInterval: DefaultLeaderRetryInterval,
+	}
+}
+
+// SetRetryInterval sets how often the lock of a job running elsewhere is tried
+func (l *Leader) SetRetryInterval(interval time.Duration) {
+	if interval > 0 {
+		l.retryInterval = interval
+	}
+}
+
+// Run runs job whenever this replica holds the lock of name, until ctx is done. The job's context is
+// cancelled when the lock is lost, and the job must return then; it may start again later.
+func (l *Leader) Run(ctx context.Context, name string, job func(ctx context.Context)) {
+	key := leaderLockKey(name)
+
+	for {
+		conn, acquired := l.tryLock(ctx, name, key)
+		if acquired {
+			l.lead(ctx, conn, name, key, job)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(l.retryInterval):
+		}
+	}
+}
+
+// tryLock takes the lock of a job on a new connection; the connection is returned only when acquired
+func (l *Leader) tryLock(ctx context.Context, name string, key int64) (*sql.Conn, bool) {
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			l.logger.WarnWithFields("Failed to get connection for job lock", map[string]interface{}{
+				"job":   name,
+				"error": err.Error(),
+			})
+		}
+		return nil, false
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, key).Scan(&acquired); err != nil || !acquired {
+		if err != nil && ctx.Err() == nil {
+			l.logger.WarnWithFields("Failed to acquire job lock", map[string]interface{}{
+				"job":   name,
+				"error": err.Error(),
+			})
+		}
+		_ = conn.Close()
+		return nil, false
+	}
+
+	return conn, true
+}
+
+// lead runs the job holding the lock, stopping it when ctx is done or the lock connection fails
+func (l *Leader) lead(ctx context.Context, conn *sql.Conn, name string, key int64, job func(ctx context.Context)) {
+	l.logger.InfoWithFields("Job lock acquired, running job on this replica", map[string]interface{}{
+		"job": name,
+	})
+
+	jobCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		job(jobCtx)
+	}()
+
+	ticker := time.NewTicker(l.retryInterval)
+	defer ticker.Stop()
+
+	stop := false
+	for !stop {
+		select {
+		case <-done:
+			stop = true
+		case <-ctx.Done():
+			stop = true
+		case <-ticker.C:
+			// The lock is gone with the connection, and another replica may already hold it
+			if _, err := conn.ExecContext(ctx, `SELECT 1`); err != nil && ctx.Err() == nil {
+				l.logger.WarnWithFields("Job lock connection lost, stopping job", map[string]interface{}{
+					"job":   name,
+					"error": err.Error(),
+				})
+				stop = true
+			}
+		}
+	}
+
+	cancel()
+	<-done
+
+	if _, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, key); err != nil {
+		l.logger.DebugWithFields("Failed to release job lock", map[string]interface{}{
+			"job":   name,
+			"error": err.Error(),
+		})
+		// Discard the connection rather than return it to the pool still holding the lock
+		_ = conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+	}
+	_ = conn.Close()
+
+	l.logger.InfoWithFields("Job lock released", map[string]interface{}{
+		"job": name,
+	})
+}
+
+// leaderLockKey derives the advisory lock key of a job from its name
+func leaderLockKey(name string) int64 {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(leaderLockNamespace + name))
+	return int64(hash.Sum64())
+}
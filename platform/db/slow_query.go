@@ -0,0 +1,164 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"zpwoot/platform/logger"
+	"zpwoot/platform/metrics"
+)
+
+// maxLoggedQueryLength keeps slow query log lines readable
+const maxLoggedQueryLength = 300
+
+var slowQueriesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "zpwoot_db_slow_queries_total",
+	Help: "Database queries slower than the configured threshold",
+}, []string{"route", "session"})
+
+// queryTimer times queries on connections opened through timedConnector
+type queryTimer struct {
+	mu        sync.RWMutex
+	threshold time.Duration
+	logger    *logger.Logger
+}
+
+func (t *queryTimer) set(threshold time.Duration, logger *logger.Logger) {
+	t.mu.Lock()
+	t.threshold = threshold
+	t.logger = logger
+	t.mu.Unlock()
+}
+
+// observe logs and counts the query when it took longer than the threshold
+func (t *queryTimer) observe(ctx context.Context, query string, started time.Time, err error) {
+	t.mu.RLock()
+	threshold, log := t.threshold, t.logger
+	t.mu.RUnlock()
+
+	if threshold <= 0 || log == nil {
+		return
+	}
+
+	elapsed := time.Since(started)
+	if elapsed < threshold {
+		return
+	}
+
+	route, session := metrics.LabelsFromContext(ctx)
+	slowQueriesTotal.WithLabelValues(route, session).Inc()
+
+	fields := map[string]interface{}{
+		"duration_ms":  elapsed.Milliseconds(),
+		"threshold_ms": threshold.Milliseconds(),
+		"query":        compactQuery(query),
+		"route":        route,
+		"session":      session,
+	}
+	if err != nil {
+		fields["error"] = err.Error()
+	}
+	log.WarnWithFields("Slow database query", fields)
+}
+
+// compactQuery collapses whitespace and truncates the query for logging
+func compactQuery(query string) string {
+	query = strings.Join(strings.Fields(query), " ")
+	if len(query) > maxLoggedQueryLength {
+		query = query[:maxLoggedQueryLength] + "..."
+	}
+	return query
+}
+
+// timedConnector wraps the PostgreSQL connector so every connection reports slow queries
+type timedConnector struct {
+	base  driver.Connector
+	timer *queryTimer
+}
+
+func (c *timedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.base.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &timedConn{Conn: conn, timer: c.timer}, nil
+}
+
+func (c *timedConnector) Driver() driver.Driver {
+	return c.base.Driver()
+}
+
+// timedConn forwards to the driver connection, timing context-aware queries and execs
+type timedConn struct {
+	driver.Conn
+	timer *queryTimer
+}
+
+func (c *timedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	started := time.Now()
+	rows, err := queryer.QueryContext(ctx, query, args)
+	if err != driver.ErrSkip {
+		c.timer.observe(ctx, query, started, err)
+	}
+	return rows, err
+}
+
+func (c *timedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	started := time.Now()
+	result, err := execer.ExecContext(ctx, query, args)
+	if err != driver.ErrSkip {
+		c.timer.observe(ctx, query, started, err)
+	}
+	return result, err
+}
+
+func (c *timedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return preparer.PrepareContext(ctx, query)
+	}
+	return c.Conn.Prepare(query)
+}
+
+func (c *timedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin()
+}
+
+func (c *timedConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *timedConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *timedConn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
@@ -251,3 +251,22 @@ func (l *Logger) WithField(key string, value interface{}) *Logger {
 func (l *Logger) GetZerologLogger() zerolog.Logger {
 	return l.logger
 }
+
+// IsValidLevel reports whether level names a log level that can be set at runtime
+func IsValidLevel(level string) bool {
+	switch strings.ToLower(level) {
+	case "trace", "debug", "info", "warn", "error":
+		return true
+	}
+	return false
+}
+
+// SetLevel changes the level of every logger at runtime
+func SetLevel(level string) {
+	zerolog.SetGlobalLevel(parseLogLevel(level))
+}
+
+// Level returns the current level of every logger
+func Level() string {
+	return zerolog.GlobalLevel().String()
+}
@@ -100,3 +100,23 @@ func GetSessionID(c *fiber.Ctx) string {
 	}
 	return ""
 }
+
+// RequestIDFromContext returns the ID of the API request the context belongs to, or "" outside of one.
+// The plain string key also finds the value the request ID middleware stores with fiber's Locals.
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if requestID, ok := ctx.Value(string(RequestIDKey)).(string); ok {
+		return requestID
+	}
+	return ""
+}
+
+// WithRequestID carries the ID of the API request that started work running outside of it
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	if requestID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, string(RequestIDKey), requestID)
+}
@@ -0,0 +1,25 @@
+package metrics
+
+import "context"
+
+// RequestLabels reports the route pattern and session of the request a context belongs to.
+// It is resolved lazily because the route is only known once the router matched it.
+type RequestLabels func() (route, session string)
+
+type requestLabelsKey struct{}
+
+// RequestLabelsKey is the context key the HTTP layer stores RequestLabels under;
+// fiber exposes request locals as values of the request context
+var RequestLabelsKey = requestLabelsKey{}
+
+// LabelsFromContext returns the route and session of the request behind ctx, if any
+func LabelsFromContext(ctx context.Context) (route, session string) {
+	if ctx == nil {
+		return "", ""
+	}
+	labels, ok := ctx.Value(RequestLabelsKey).(RequestLabels)
+	if !ok || labels == nil {
+		return "", ""
+	}
+	return labels()
+}
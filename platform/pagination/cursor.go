@@ -0,0 +1,58 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidCursor = errors.New("invalid pagination cursor")
+
+// Cursor points just past a row of a list ordered by creation time and id, newest first. Unlike
+// offsets, cursors keep pages stable while rows are inserted and don't slow down deep into large tables.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// Encode returns the opaque form of the cursor handed to clients
+func (c *Cursor) Encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID))
+}
+
+// Decode parses an opaque cursor; an empty one decodes to nil, the first page. Every list paged by cursor
+// is keyed by a uuid id, so a cursor whose id is not a uuid is rejected here rather than by the database.
+func Decode(cursor string) (*Cursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	createdAt, id, found := strings.Cut(string(raw), "|")
+	if !found || uuid.Validate(id) != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	parsed, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &Cursor{CreatedAt: parsed, ID: id}, nil
+}
+
+// Next returns the cursor of the page after one that returned count of limit rows, the last created at
+// createdAt with id, or "" when that page was the last
+func Next(count, limit int, createdAt time.Time, id string) string {
+	if count == 0 || count < limit {
+		return ""
+	}
+	return (&Cursor{CreatedAt: createdAt, ID: id}).Encode()
+}
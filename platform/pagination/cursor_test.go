@@ -0,0 +1,41 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDecodeRoundTrip(t *testing.T) {
+	cursor := &Cursor{
+		CreatedAt: time.Date(2024, 1, 1, 12, 0, 0, 123, time.UTC),
+		ID:        "1b2e424c-a2a0-41a4-b992-15b7ec06b9bc",
+	}
+
+	decoded, err := Decode(cursor.Encode())
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if !decoded.CreatedAt.Equal(cursor.CreatedAt) || decoded.ID != cursor.ID {
+		t.Errorf("got %+v, want %+v", decoded, cursor)
+	}
+}
+
+func TestDecodeRejectsInvalidCursors(t *testing.T) {
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+
+	for name, cursor := range map[string]string{
+		"not base64":   "!!!",
+		"no separator": encode("2024-01-01T12:00:00Z"),
+		"bad time":     encode("yesterday|1b2e424c-a2a0-41a4-b992-15b7ec06b9bc"),
+		"empty id":     encode("2024-01-01T12:00:00Z|"),
+		"id not uuid":  encode("2024-01-01T12:00:00Z|42"),
+	} {
+		if _, err := Decode(cursor); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("%s: got %v, want ErrInvalidCursor", name, err)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package phone
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nyaruka/phonenumbers"
+)
+
+// E.164 bounds on the digits of an international number, country code included
+const (
+	minDigits = 7
+	maxDigits = 15
+)
+
+var (
+	ErrInvalidNumber    = errors.New("phone number may only contain digits, spaces, dashes, dots, parentheses and a leading + or 00")
+	ErrImpossibleNumber = errors.New("impossible phone number")
+)
+
+// Number is a phone number in international form
+type Number struct {
+	// Digits are the country code followed by the national number, as in JIDs
+	Digits string
+	// Region is the ISO 3166 code of the number's country, e.g. "BR"; countries sharing a calling code,
+	// like the NANP ones under +1, are told apart by the number
+	Region string
+
+	parsed *phonenumbers.PhoneNumber
+}
+
+// Parse parses a number written in international form, e.g. "+55 (11) 99999-9999",
+// "0055 11 99999 9999" or "5511999999999", and checks its length is possible for its country. The
+// numbering plans come from libphonenumber; only the length is checked, so numbers in ranges assigned
+// after the metadata was released still pass.
+func Parse(raw string) (*Number, error) {
+	digits, err := clean(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(digits) < minDigits || len(digits) > maxDigits {
+		return nil, fmt.Errorf("%w: international numbers have %d to %d digits, got %d", ErrImpossibleNumber, minDigits, maxDigits, len(digits))
+	}
+
+	if digits[0] == '0' {
+		// A leading 0 is a national trunk prefix, the number lacks its country code
+		return nil, fmt.Errorf("%w: country codes do not start with 0, write the number with its country code", ErrImpossibleNumber)
+	}
+
+	parsed, err := phonenumbers.Parse("+"+digits, "")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrImpossibleNumber, err)
+	}
+
+	region := phonenumbers.GetRegionCodeForNumber(parsed)
+	switch phonenumbers.IsPossibleNumberWithReason(parsed) {
+	case phonenumbers.IS_POSSIBLE:
+	case phonenumbers.INVALID_COUNTRY_CODE:
+		return nil, fmt.Errorf("%w: unknown country code +%d", ErrImpossibleNumber, parsed.GetCountryCode())
+	case phonenumbers.TOO_SHORT, phonenumbers.IS_POSSIBLE_LOCAL_ONLY:
+		return nil, fmt.Errorf("%w: too short for a %s number", ErrImpossibleNumber, regionName(region, parsed))
+	case phonenumbers.TOO_LONG:
+		return nil, fmt.Errorf("%w: too long for a %s number", ErrImpossibleNumber, regionName(region, parsed))
+	default:
+		return nil, fmt.Errorf("%w: no %s number has this length", ErrImpossibleNumber, regionName(region, parsed))
+	}
+
+	return &Number{Digits: digits, Region: region, parsed: parsed}, nil
+}
+
+// regionName names the country of a number in errors, falling back to its calling code
+func regionName(region string, parsed *phonenumbers.PhoneNumber) string {
+	if region == "" || region == phonenumbers.UNKNOWN_REGION {
+		return fmt.Sprintf("+%d", parsed.GetCountryCode())
+	}
+	return region
+}
+
+// LooksLikeNumber reports whether raw is written as a phone number: a + followed by digits and
+// formatting, or at most 15 digits. Longer digit strings, like bare group IDs, are not numbers and
+// are left out of the country checks.
+func LooksLikeNumber(raw string) bool {
+	digits, err := clean(raw)
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(strings.TrimSpace(raw), "+") || len(digits) <= maxDigits
+}
+
+// Validate reports why raw is not a possible phone number, or nil
+func Validate(raw string) error {
+	_, err := Parse(raw)
+	return err
+}
+
+// FormatE164 returns raw in E.164 form, e.g. "+5511999999999"
+func FormatE164(raw string) (string, error) {
+	number, err := Parse(raw)
+	if err != nil {
+		return "", err
+	}
+	return number.E164(), nil
+}
+
+// E164 returns the number in E.164 form
+func (n *Number) E164() string {
+	return "+" + n.Digits
+}
+
+// National returns the national significant number, without the country code
+func (n *Number) National() string {
+	return phonenumbers.GetNationalSignificantNumber(n.parsed)
+}
+
+// International returns the number formatted for international dialing, e.g. "+55 11 99999-9999"
+func (n *Number) International() string {
+	return phonenumbers.Format(n.parsed, phonenumbers.INTERNATIONAL)
+}
+
+// clean strips the formatting of a number and its international prefix, keeping the digits
+func clean(raw string) (string, error) {
+	number := strings.TrimSpace(raw)
+	number = strings.TrimPrefix(number, "+")
+
+	var b strings.Builder
+	for _, r := range number {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')':
+		default:
+			return "", ErrInvalidNumber
+		}
+	}
+
+	digits := b.String()
+	if digits == "" {
+		return "", ErrInvalidNumber
+	}
+	// 00 is the international call prefix of most countries
+	return strings.TrimPrefix(digits, "00"), nil
+}
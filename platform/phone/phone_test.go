@@ -0,0 +1,45 @@
+package phone
+
+import "testing"
+
+func TestLooksLikeNumber(t *testing.T) {
+	cases := map[string]bool{
+		"5511999999999":            true,
+		"+55 (11) 99999-9999":      true,
+		"004915123456789":          true,
+		"120363025246125486":       false,
+		"5511999999999-1600000000": false,
+		"+1234567890123456":        true,
+		"group":                    false,
+	}
+	for raw, want := range cases {
+		if got := LooksLikeNumber(raw); got != want {
+			t.Errorf("LooksLikeNumber(%q) = %v, want %v", raw, got, want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	cases := map[string]string{
+		"+55 (11) 99999-9999": "+5511999999999",
+		"0055 11 9999 9999":   "+551199999999",
+		"+1 (415) 555-2671":   "+14155552671",
+		"447911123456":        "+447911123456",
+	}
+	for raw, want := range cases {
+		number, err := Parse(raw)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", raw, err)
+			continue
+		}
+		if got := number.E164(); got != want {
+			t.Errorf("Parse(%q).E164() = %q, want %q", raw, got, want)
+		}
+	}
+
+	for _, raw := range []string{"+55 11 9999", "+55 11 99999 99999", "+999 1234 5678", "0119999999", "+55 11 abc"} {
+		if _, err := Parse(raw); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", raw)
+		}
+	}
+}
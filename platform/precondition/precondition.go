@@ -0,0 +1,24 @@
+package precondition
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrFailed reports that a conditional write found the resource changed since the version it was based on
+var ErrFailed = errors.New("resource was modified since it was read")
+
+type versionKey struct{}
+
+// WithVersion returns a context whose writes only apply while the resource is still at version, the
+// updatedAt it had when the caller read it
+func WithVersion(ctx context.Context, version time.Time) context.Context {
+	return context.WithValue(ctx, versionKey{}, version)
+}
+
+// Version returns the version set by WithVersion and whether the write is conditional at all
+func Version(ctx context.Context) (time.Time, bool) {
+	version, ok := ctx.Value(versionKey{}).(time.Time)
+	return version, ok
+}
@@ -0,0 +1,19 @@
+package precondition
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestVersion(t *testing.T) {
+	if _, ok := Version(context.Background()); ok {
+		t.Fatal("expected a plain context to carry no version")
+	}
+
+	version := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	got, ok := Version(WithVersion(context.Background(), version))
+	if !ok || !got.Equal(version) {
+		t.Fatalf("expected version %v, got %v (ok=%v)", version, got, ok)
+	}
+}
@@ -0,0 +1,136 @@
+package shaping
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+)
+
+// ContextKey is the request value holding the Shape of the request, so use cases can skip the
+// sub-resources that were not asked for
+const ContextKey = "response_shape"
+
+// Shape is how the caller wants a GET response shaped: Fields lists the dotted paths of the response
+// data to keep (all of it when empty) and Expand names the heavy sub-resources to include, which are
+// left out by default
+type Shape struct {
+	Fields []string
+	Expand []string
+}
+
+// Parse reads the comma-separated fields and expand query parameters
+func Parse(fields, expand string) *Shape {
+	shape := &Shape{
+		Fields: splitList(fields),
+		Expand: splitList(strings.ToLower(expand)),
+	}
+	if len(shape.Fields) == 0 && len(shape.Expand) == 0 {
+		return nil
+	}
+	return shape
+}
+
+// Expands reports whether the sub-resource was asked for
+func (s *Shape) Expands(name string) bool {
+	if s == nil {
+		return false
+	}
+	for _, expand := range s.Expand {
+		if expand == name {
+			return true
+		}
+	}
+	return false
+}
+
+// FromContext returns the shape of the request, or nil when the response is not shaped
+func FromContext(ctx context.Context) *Shape {
+	if ctx == nil {
+		return nil
+	}
+	shape, _ := ctx.Value(ContextKey).(*Shape)
+	return shape
+}
+
+// Expands reports whether the request asked for the sub-resource
+func Expands(ctx context.Context, name string) bool {
+	return FromContext(ctx).Expands(name)
+}
+
+// Select keeps the fields of a JSON document named by dotted paths, e.g. session.name keeps the name
+// of the session object. Paths go through arrays, applying to every element; unknown paths are ignored.
+func Select(data json.RawMessage, fields []string) (json.RawMessage, error) {
+	if len(fields) == 0 {
+		return data, nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+
+	tree := fieldTree{}
+	for _, field := range fields {
+		tree.add(strings.Split(field, "."))
+	}
+
+	return json.Marshal(tree.apply(value))
+}
+
+// fieldTree holds the selected paths; a leaf keeps the whole value under it
+type fieldTree map[string]fieldTree
+
+func (t fieldTree) add(path []string) {
+	if len(path) == 0 || path[0] == "" {
+		return
+	}
+	sub, exists := t[path[0]]
+	if exists && len(sub) == 0 {
+		return
+	}
+	if len(path) == 1 {
+		t[path[0]] = fieldTree{}
+		return
+	}
+	if !exists {
+		sub = fieldTree{}
+		t[path[0]] = sub
+	}
+	sub.add(path[1:])
+}
+
+func (t fieldTree) apply(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		selected := make(map[string]interface{}, len(t))
+		for key, sub := range t {
+			field, ok := v[key]
+			if !ok {
+				continue
+			}
+			if len(sub) == 0 {
+				selected[key] = field
+			} else {
+				selected[key] = sub.apply(field)
+			}
+		}
+		return selected
+	case []interface{}:
+		selected := make([]interface{}, len(v))
+		for i, element := range v {
+			selected[i] = t.apply(element)
+		}
+		return selected
+	}
+	return value
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,133 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"zpwoot/platform/logger"
+)
+
+// Span kinds of the spans zpwoot starts
+const (
+	KindInternal = trace.SpanKindInternal
+	KindServer   = trace.SpanKindServer
+	KindClient   = trace.SpanKindClient
+)
+
+// instrumentationName names the tracer of zpwoot's spans
+const instrumentationName = "zpwoot"
+
+// Config configures tracing; spans are only recorded and exported when Endpoint is set
+type Config struct {
+	ServiceName string
+	// Endpoint is the OTLP/HTTP traces URL, e.g. http://otel-collector:4318/v1/traces
+	Endpoint string
+	Headers  map[string]string
+	// SampleRatio is the share of new traces recorded, from 0 to 1; traces started by a caller
+	// follow the caller's sampling decision
+	SampleRatio float64
+}
+
+// Tracer owns the OpenTelemetry tracer provider spans are exported through
+type Tracer struct {
+	provider *sdktrace.TracerProvider
+}
+
+// Init installs an OpenTelemetry tracer provider exporting over OTLP/HTTP and the W3C trace context
+// propagator, and returns it, or nil when no endpoint is configured. Spans are no-ops without it.
+func Init(config Config, logger *logger.Logger) *Tracer {
+	if config.Endpoint == "" {
+		return nil
+	}
+	if config.ServiceName == "" {
+		config.ServiceName = "zpwoot"
+	}
+
+	exporter, err := otlptracehttp.New(context.Background(),
+		otlptracehttp.WithEndpointURL(config.Endpoint),
+		otlptracehttp.WithHeaders(config.Headers),
+	)
+	if err != nil {
+		logger.ErrorWithFields("Tracing disabled, invalid OTLP exporter configuration", map[string]interface{}{
+			"endpoint": config.Endpoint,
+			"error":    err.Error(),
+		})
+		return nil
+	}
+
+	ratio := math.Max(0, math.Min(1, config.SampleRatio))
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(config.ServiceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	logger.InfoWithFields("Tracing enabled", map[string]interface{}{
+		"endpoint":     config.Endpoint,
+		"service_name": config.ServiceName,
+		"sample_ratio": ratio,
+	})
+	return &Tracer{provider: provider}
+}
+
+// Shutdown exports the spans still buffered until ctx is done; later spans are not recorded
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+	return t.provider.Shutdown(ctx)
+}
+
+type spanKey struct{}
+
+// SpanKey is the key the HTTP layer stores the request span under in the request locals, which fiber
+// exposes as values of the request context. OpenTelemetry keeps the current span under a private key,
+// so Start looks it up here too.
+var SpanKey = spanKey{}
+
+// SpanFromContext returns the current span of ctx, a no-op span when there is none
+func SpanFromContext(ctx context.Context) trace.Span {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if span := trace.SpanFromContext(ctx); span.SpanContext().IsValid() {
+		return span
+	}
+	if span, ok := ctx.Value(SpanKey).(trace.Span); ok {
+		return span
+	}
+	return trace.SpanFromContext(ctx)
+}
+
+// Start starts a span as a child of the current span of ctx, or as the root of a new trace, and
+// returns a context carrying it. The span must be ended.
+func Start(ctx context.Context, name string, kind trace.SpanKind) (context.Context, trace.Span) {
+	ctx = trace.ContextWithSpan(ctx, SpanFromContext(ctx))
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithSpanKind(kind))
+}
+
+// RecordError marks span as failed with err; a nil err is ignored
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// SetErrorf marks span as failed without an error value, e.g. after a 5xx response
+func SetErrorf(span trace.Span, format string, args ...interface{}) {
+	span.SetStatus(codes.Error, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,37 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestStartContinuesTheSpanStoredUnderSpanKey(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(previous)
+
+	_, request := Start(context.Background(), "HTTP POST", KindServer)
+	// fiber exposes the request locals as values of c.Context(), not through OpenTelemetry's own key
+	locals := context.WithValue(context.Background(), SpanKey, request)
+
+	_, child := Start(locals, "message.SendMessage", KindInternal)
+	child.End()
+	request.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("recorded %d spans, want 2", len(spans))
+	}
+	if got, want := spans[0].Parent().SpanID(), request.SpanContext().SpanID(); got != want {
+		t.Errorf("child parent = %s, want %s", got, want)
+	}
+	if spans[0].SpanContext().TraceID() != request.SpanContext().TraceID() {
+		t.Error("child started a new trace")
+	}
+}
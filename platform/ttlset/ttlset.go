@@ -0,0 +1,72 @@
+package ttlset
+
+import (
+	"sync"
+	"time"
+)
+
+// Set is an in-memory set of keys that are forgotten once their TTL has passed.
+// Expired keys are swept lazily, at most once per TTL, so no background goroutine is needed.
+type Set struct {
+	ttl       time.Duration
+	mu        sync.Mutex
+	entries   map[string]time.Time
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// New creates an empty set whose keys live for ttl
+func New(ttl time.Duration) *Set {
+	return &Set{
+		ttl:       ttl,
+		entries:   make(map[string]time.Time),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+// Add records the key and returns true if it was not already in the set within the TTL
+func (s *Set) Add(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	if expiresAt, exists := s.entries[key]; exists && now.Before(expiresAt) {
+		return false
+	}
+
+	s.entries[key] = now.Add(s.ttl)
+	return true
+}
+
+// Remove forgets the key so it can be added again
+func (s *Set) Remove(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+}
+
+// Len returns the number of keys held, including expired ones not swept yet
+func (s *Set) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.entries)
+}
+
+// sweep removes expired entries at most once per TTL; caller must hold the lock
+func (s *Set) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < s.ttl {
+		return
+	}
+
+	for key, expiresAt := range s.entries {
+		if !now.Before(expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+	s.lastSweep = now
+}
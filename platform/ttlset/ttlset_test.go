@@ -0,0 +1,41 @@
+package ttlset
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetExpiresAndSweepsKeys(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	set := New(time.Minute)
+	set.now = func() time.Time { return now }
+	set.lastSweep = now
+
+	if !set.Add("a") {
+		t.Fatal("first Add should report a new key")
+	}
+	if set.Add("a") {
+		t.Fatal("second Add within the TTL should report a duplicate")
+	}
+
+	now = now.Add(time.Minute)
+	if !set.Add("a") {
+		t.Fatal("Add after the TTL should report a new key")
+	}
+
+	set.Add("b")
+	now = now.Add(2 * time.Minute)
+	set.Add("c")
+	if got := set.Len(); got != 1 {
+		t.Fatalf("expected expired keys to be swept, got %d entries", got)
+	}
+}
+
+func TestSetRemove(t *testing.T) {
+	set := New(time.Minute)
+	set.Add("a")
+	set.Remove("a")
+	if !set.Add("a") {
+		t.Fatal("Add after Remove should report a new key")
+	}
+}